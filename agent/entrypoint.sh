@@ -5,15 +5,19 @@ set -e
 LIB_DIR="$(dirname "$0")/lib"
 source "${LIB_DIR}/log.sh"
 source "${LIB_DIR}/validate.sh"
+source "${LIB_DIR}/workspace_cache.sh"
 source "${LIB_DIR}/git.sh"
 source "${LIB_DIR}/github.sh"
+source "${LIB_DIR}/multi_repo.sh"
 source "${LIB_DIR}/prompt.sh"
 source "${LIB_DIR}/claude.sh"
 source "${LIB_DIR}/dryrun.sh"
+source "${LIB_DIR}/shadow.sh"
+source "${LIB_DIR}/proposal.sh"
 source "${LIB_DIR}/proxy.sh"
 
-# ── Start beta header proxy (if enabled) ───────────────────────────
-start_beta_proxy
+# ── Start Anthropic API proxy (if enabled) ─────────────────────────
+start_anthropic_proxy
 
 # ── Branch on work type ─────────────────────────────────────────────
 if [ "${WORK_TYPE}" = "epic" ]; then
@@ -45,7 +49,7 @@ fi
 # ── Failure trap ─────────────────────────────────────────────────────
 cleanup_on_failure() {
     local exit_code=$?
-    if [ "$exit_code" -ne 0 ] && [ -n "${BRANCH:-}" ]; then
+    if [ "$exit_code" -ne 0 ] && [ -n "${BRANCH:-}" ] && ! shadow_mode_enabled && ! plan_only_enabled; then
         log_agent "Agent exiting with error — pushing work-in-progress to branch"
         push_wip
     fi
@@ -57,7 +61,11 @@ log_header "Verve Agent Starting"
 [ -n "${TASK_NUMBER}" ] && echo "Task: #${TASK_NUMBER}"
 echo "Task ID: ${TASK_ID}"
 echo "Repository: ${GITHUB_REPO}"
+[ -n "${ADDITIONAL_REPOS}" ] && echo "Additional repositories: ${ADDITIONAL_REPOS}"
 [ -n "${TASK_TITLE}" ] && echo "Title: ${TASK_TITLE}"
+shadow_mode_enabled && echo "Mode: shadow (changes are recorded, not pushed)"
+plan_only_enabled && echo "Mode: plan only (a change plan is proposed for approval)"
+[ -n "${BASE_BRANCH}" ] && echo "Base branch: ${BASE_BRANCH}"
 echo "Description: ${TASK_DESCRIPTION}"
 if [ "${ATTEMPT:-1}" -gt 1 ]; then
     echo "Attempt: ${ATTEMPT} (retry)"
@@ -70,7 +78,14 @@ validate_env
 configure_git
 clone_repo
 detect_default_branch
+use_base_branch
 setup_branch
+if [ -n "${ADDITIONAL_REPOS}" ]; then
+    clone_additional_repos
+fi
+if shadow_mode_enabled || plan_only_enabled; then
+    disable_push
+fi
 
 # ── Initialize tome (session memory) ──────────────────────────────
 if command -v tome &>/dev/null; then
@@ -88,6 +103,16 @@ if [ "$DRY_RUN" = "true" ]; then
     exit 0
 fi
 
+# ── Plan-only mode: propose a change plan for approval ──────────────
+if plan_only_enabled; then
+    build_plan_prompt
+    run_claude "$PROMPT"
+    record_proposal
+    log_blank
+    log_header "Task Completed Successfully (Plan Only)"
+    exit 0
+fi
+
 # ── Run Claude Code ─────────────────────────────────────────────────
 if [ "${ATTEMPT:-1}" -gt 1 ]; then
     log_agent "Building retry-aware prompt..."
@@ -95,12 +120,27 @@ fi
 build_prompt
 run_claude "$PROMPT"
 
-# ── Commit, push, and create PR ────────────────────────────────────
+# ── Shadow mode: record the diff for review ─────────────────────────
+if shadow_mode_enabled; then
+    record_shadow_diff
+    log_blank
+    log_header "Task Completed Successfully (Shadow Mode)"
+    exit 0
+fi
+
+# ── Commit, push, and create PRs ───────────────────────────────────
+# Additional repositories are published first so the primary repository
+# having no changes is not reported as the task needing none.
+if [ -n "${ADDITIONAL_REPOS}" ]; then
+    publish_additional_repos
+fi
 commit_and_push
 
 if [ "$SKIP_PR" = "true" ]; then
     log_agent "Skip PR mode: branch pushed, skipping PR creation"
-    echo "VERVE_BRANCH_PUSHED:{\"branch\":\"${BRANCH}\"}"
+    emit_event branch_pushed "$(jq -cn --arg branch "$BRANCH" '{branch: $branch}')"
+elif [ "${PRIMARY_UNCHANGED:-false}" = "true" ]; then
+    log_agent "Skipping PR for ${GITHUB_REPO}: no changes"
 elif [ "${ATTEMPT:-1}" -le 1 ] || [ "${BRANCH_EXISTS_ON_REMOTE}" != "true" ]; then
     # For empty repos, ensure the default branch exists so PRs have a base
     ensure_base_branch
@@ -0,0 +1,183 @@
+// anthropic_proxy.js — Reverse proxy for the agent's Anthropic API traffic.
+// Runs inside the agent container when STRIP_ANTHROPIC_BETA_HEADERS=true or
+// COST_BUDGET_USD is set. Listens on a local port and forwards requests to
+// the upstream Anthropic API (or Bedrock proxy):
+//
+//   - With STRIP_ANTHROPIC_BETA_HEADERS=true the anthropic-beta header is
+//     removed from every request.
+//   - With COST_BUDGET_USD set, token usage in responses is metered and
+//     priced. Once the spend reaches the budget a budget_exceeded event is
+//     written to fd 3 for the worker, which stops the container, and any
+//     further requests are refused.
+//
+// Writes the listening port to the file specified by BETA_PROXY_PORT_FILE,
+// then keeps running until the process is killed.
+
+const http = require("http");
+const https = require("https");
+const url = require("url");
+const fs = require("fs");
+
+const upstream = process.env.BETA_PROXY_UPSTREAM || "https://api.anthropic.com";
+const portFile = process.env.BETA_PROXY_PORT_FILE;
+const stripBeta = process.env.STRIP_ANTHROPIC_BETA_HEADERS === "true";
+const budgetUSD = parseFloat(process.env.COST_BUDGET_USD || "") || 0;
+const parsed = new url.URL(upstream);
+const isHTTPS = parsed.protocol === "https:";
+const transport = isHTTPS ? https : http;
+
+// USD per million input and output tokens, matched in order against the
+// model name. Unknown models are priced like the most expensive model so the
+// budget errs on the side of stopping early.
+const pricing = [
+  { match: /opus-4-[5-9]/, input: 5, output: 25 },
+  { match: /opus/, input: 15, output: 75 },
+  { match: /haiku-4/, input: 1, output: 5 },
+  { match: /haiku/, input: 0.8, output: 4 },
+  { match: /sonnet/, input: 3, output: 15 },
+];
+const fallbackPricing = { input: 15, output: 75 };
+
+let spentUSD = 0;
+let budgetExceeded = false;
+
+function usageCost(model, usage) {
+  const price = pricing.find((p) => p.match.test(model || "")) || fallbackPricing;
+  const input =
+    (usage.input_tokens || 0) +
+    (usage.cache_creation_input_tokens || 0) * 1.25 +
+    (usage.cache_read_input_tokens || 0) * 0.1;
+  return (input * price.input + (usage.output_tokens || 0) * price.output) / 1e6;
+}
+
+function emitEvent(type, payload) {
+  const record = "\x1e" + JSON.stringify({ type, payload }) + "\n";
+  try {
+    fs.writeSync(3, record);
+  } catch (err) {
+    process.stdout.write(record);
+  }
+}
+
+function addCost(costUSD) {
+  spentUSD += costUSD;
+  if (!budgetExceeded && spentUSD >= budgetUSD) {
+    budgetExceeded = true;
+    process.stderr.write(
+      "cost budget reached: $" + spentUSD.toFixed(4) + " of $" + budgetUSD.toFixed(4) + "\n",
+    );
+    emitEvent("budget_exceeded", {
+      cost_usd: Number(spentUSD.toFixed(6)),
+      budget_usd: budgetUSD,
+    });
+  }
+}
+
+// meterResponse taps a response body and adds the cost of its usage once it
+// ends. Streaming responses report the model and input usage in
+// message_start and cumulative output usage in message_delta events;
+// non-streaming responses carry both in the JSON body.
+function meterResponse(proxyRes) {
+  const streaming = (proxyRes.headers["content-type"] || "").includes("text/event-stream");
+  let model = "";
+  let usage = {};
+  let buffered = "";
+
+  const consume = (data) => {
+    let body;
+    try {
+      body = JSON.parse(data);
+    } catch (err) {
+      return;
+    }
+    const message = body.type === "message_start" ? body.message : body;
+    if (message && message.model) {
+      model = message.model;
+    }
+    if (message && message.usage) {
+      usage = { ...usage, ...message.usage };
+    }
+  };
+
+  proxyRes.setEncoding("utf8");
+  proxyRes.on("data", (chunk) => {
+    buffered += chunk;
+    if (!streaming) {
+      return;
+    }
+    const lines = buffered.split("\n");
+    buffered = lines.pop();
+    for (const line of lines) {
+      if (line.startsWith("data:")) {
+        consume(line.slice(5).trim());
+      }
+    }
+  });
+  proxyRes.on("end", () => {
+    if (!streaming) {
+      consume(buffered);
+    }
+    addCost(usageCost(model, usage));
+  });
+}
+
+const server = http.createServer((clientReq, clientRes) => {
+  if (budgetExceeded) {
+    clientReq.resume();
+    clientRes.writeHead(400, { "content-type": "application/json" });
+    clientRes.end(
+      JSON.stringify({
+        type: "error",
+        error: {
+          type: "invalid_request_error",
+          message: "Verve task budget of $" + budgetUSD.toFixed(2) + " reached",
+        },
+      }),
+    );
+    return;
+  }
+
+  const headers = { ...clientReq.headers };
+  if (stripBeta) {
+    delete headers["anthropic-beta"];
+  }
+  if (budgetUSD > 0) {
+    // Metering reads the response body, so it must not be compressed.
+    delete headers["accept-encoding"];
+  }
+  headers.host = parsed.host;
+
+  const options = {
+    hostname: parsed.hostname,
+    port: parsed.port || (isHTTPS ? 443 : 80),
+    path: clientReq.url,
+    method: clientReq.method,
+    headers: headers,
+  };
+
+  const proxyReq = transport.request(options, (proxyRes) => {
+    clientRes.writeHead(proxyRes.statusCode, proxyRes.headers);
+    if (budgetUSD > 0 && proxyRes.statusCode < 300) {
+      meterResponse(proxyRes);
+    }
+    proxyRes.pipe(clientRes, { end: true });
+  });
+
+  proxyReq.on("error", (err) => {
+    process.stderr.write("proxy error: " + err.message + "\n");
+    if (!clientRes.headersSent) {
+      clientRes.writeHead(502);
+    }
+    clientRes.end("Bad Gateway");
+  });
+
+  clientReq.pipe(proxyReq, { end: true });
+});
+
+server.listen(0, "127.0.0.1", () => {
+  const port = server.address().port;
+  if (portFile) {
+    fs.writeFileSync(portFile, port.toString());
+  }
+  process.stderr.write("anthropic proxy listening on port " + port + "\n");
+});
@@ -16,6 +16,21 @@ run_claude() {
     log_blank
     log_agent "Using model: ${CLAUDE_MODEL}"
 
+    # Deliver messages the user sends while the task is running (see
+    # nudge_hook.sh) after each tool call and before Claude finishes.
+    local hook="${LIB_DIR}/nudge_hook.sh"
+    local settings
+    settings=$(jq -cn --arg cmd "$hook" '{hooks: {
+        PostToolUse: [{matcher: "*", hooks: [{type: "command", command: $cmd}]}],
+        Stop: [{hooks: [{type: "command", command: $cmd}]}]
+    }}')
+
+    # Give Claude access to the additional repositories of a multi-repo task.
+    local extra_args=()
+    if [ -n "${ADDITIONAL_REPOS}" ]; then
+        mapfile -t extra_args < <(additional_repo_claude_args)
+    fi
+
     # Use pipefail so we capture claude's exit code through the pipe.
     # Without this, a claude failure (e.g. auth error) is masked by _parse_stream
     # succeeding, and the script continues as if nothing went wrong.
@@ -24,7 +39,7 @@ run_claude() {
     set -o pipefail
     set +e
     claude --output-format stream-json --verbose --dangerously-skip-permissions \
-        --model "${CLAUDE_MODEL}" "${prompt}" 2>&1 | _parse_stream
+        --settings "${settings}" "${extra_args[@]}" --model "${CLAUDE_MODEL}" "${prompt}" 2>&1 | _parse_stream
     claude_exit=$?
     set -e
     set +o pipefail
@@ -80,6 +95,8 @@ _handle_assistant_event() {
             input=$(echo "$line" | jq -c '.message.content[0].input // empty' 2>/dev/null)
             detail=$(_tool_detail "$name" "$input")
             if [ -n "$name" ]; then
+                emit_event tool_call "$(jq -cn --arg name "$name" --arg detail "$detail" '{name: $name, detail: $detail}')"
+                [ "$name" = "TodoWrite" ] && _report_plan_progress "$input"
                 if [ -n "$detail" ]; then
                     log_tool "$name: $detail"
                 else
@@ -90,6 +107,39 @@ _handle_assistant_event() {
     esac
 }
 
+# Plan reported by the last TodoWrite call. _parse_stream runs in a single
+# subshell, so these persist across stream events.
+_PLAN_TITLES=""
+_PLAN_STATUSES=()
+
+# _report_plan_progress turns Claude's todo list into plan and step events:
+# a plan event whenever the step titles change, then a step event for each
+# step that started or completed since the last update.
+_report_plan_progress() {
+    local input="$1" titles
+    titles=$(echo "$input" | jq -c '[.todos[]?.content // empty]' 2>/dev/null)
+    [ -z "$titles" ] || [ "$titles" = "[]" ] && return
+
+    if [ "$titles" != "$_PLAN_TITLES" ]; then
+        emit_event plan "$(jq -cn --argjson steps "$titles" '{steps: $steps}')"
+        _PLAN_TITLES="$titles"
+        _PLAN_STATUSES=()
+    fi
+
+    local i=0 status
+    while IFS= read -r status; do
+        local prev="${_PLAN_STATUSES[$i]:-pending}"
+        if [ "$status" != "$prev" ]; then
+            case "$status" in
+                in_progress) emit_event step "$(jq -cn --argjson index "$i" '{index: $index, status: "started"}')" ;;
+                completed)   emit_event step "$(jq -cn --argjson index "$i" '{index: $index, status: "completed"}')" ;;
+            esac
+            _PLAN_STATUSES[$i]="$status"
+        fi
+        i=$((i + 1))
+    done < <(echo "$input" | jq -r '.todos[]? | select(.content != null) | .status // "pending"' 2>/dev/null)
+}
+
 _tool_detail() {
     local name="$1" input="$2"
     [ -z "$input" ] || [ "$input" = "null" ] || [ "$input" = '""' ] && return
@@ -172,6 +222,6 @@ _handle_result_event() {
     local cost
     cost=$(echo "$line" | jq -r '.total_cost_usd // empty' 2>/dev/null)
     if [ -n "$cost" ] && [ "$cost" != "null" ] && [ "$cost" != "0" ]; then
-        echo "VERVE_COST:${cost}"
+        emit_event cost "$(jq -cn --argjson cost "$cost" '{cost_usd: $cost}')"
     fi
 }
@@ -1,12 +1,19 @@
 #!/bin/bash
 # dryrun.sh — Dry run mode: skip Claude, make a dummy change, push, and optionally create a PR
 
-# Depends on: log.sh, github.sh (sourced by entrypoint.sh)
+# Depends on: log.sh, github.sh, shadow.sh, proposal.sh (sourced by entrypoint.sh)
 
 run_dry_run() {
     log_agent "DRY RUN mode - skipping Claude Code"
     log_blank
 
+    if plan_only_enabled; then
+        write_dry_run_proposal
+        log_blank
+        log_header "Task Completed Successfully (Dry Run, Plan Only)"
+        return 0
+    fi
+
     cat > "verve-dry-run.md" <<DRYEOF
 # Verve Dry Run
 
@@ -18,6 +25,13 @@ DRYEOF
 
     log_agent "Created dummy file: verve-dry-run.md"
 
+    if shadow_mode_enabled; then
+        record_shadow_diff
+        log_blank
+        log_header "Task Completed Successfully (Dry Run, Shadow Mode)"
+        return 0
+    fi
+
     # Commit and push
     log_agent "Committing changes..."
     git add -A
@@ -36,7 +50,7 @@ DRYEOF
     # PR handling
     if [ "$SKIP_PR" = "true" ]; then
         log_agent "Skip PR mode: branch pushed, skipping PR creation"
-        echo "VERVE_BRANCH_PUSHED:{\"branch\":\"${BRANCH}\"}"
+        emit_event branch_pushed "$(jq -cn --arg branch "$BRANCH" '{branch: $branch}')"
     elif [ "${ATTEMPT:-1}" -le 1 ]; then
         log_agent "Creating pull request..."
         local pr_title="[Dry Run] ${TASK_TITLE:-${TASK_DESCRIPTION}}"
@@ -37,8 +37,12 @@ run_epic_planning() {
     log_agent "Repository cloned for context analysis"
     log_blank
 
-    # Determine if this is a change request or initial planning
-    if [ -n "${EPIC_FEEDBACK}" ]; then
+    # Determine if this is a re-plan, a change request or initial planning
+    if [ "${EPIC_FEEDBACK_TYPE}" = "replan" ]; then
+        _epic_send_log "system: Re-planning remaining work against the current repository..."
+    elif [ -n "${EPIC_TRANSCRIPT}" ] && [ -z "${EPIC_FEEDBACK}" ]; then
+        _epic_send_log "system: Resuming planning from the session transcript..."
+    elif [ -n "${EPIC_FEEDBACK}" ]; then
         _epic_send_log "system: Re-planning based on user feedback..."
     else
         _epic_send_log "system: Planning started. Analyzing epic and generating task breakdown..."
@@ -162,7 +166,33 @@ ${REPO_EXPECTATIONS}"
         fi
     fi
 
-    if [ -n "$previous_plan" ] && [ -n "$feedback" ]; then
+    # Earlier sessions' transcript, so an interrupted conversation carries on
+    if [ -n "${EPIC_TRANSCRIPT}" ]; then
+        prompt="${prompt}
+
+## Planning Session So Far
+
+Earlier planning sessions for this epic logged the transcript below (oldest first; [user] lines are from the user, [system] lines from Verve and [agent] lines are agent output). A session may have ended before finishing. Continue from where it left off and take everything the user asked for into account rather than starting over.
+
+\`\`\`text
+${EPIC_TRANSCRIPT}
+\`\`\`"
+    fi
+
+    if [ "${EPIC_FEEDBACK_TYPE}" = "replan" ]; then
+        prompt="${prompt}
+
+## Epic Progress
+
+${feedback}
+
+**Previous Task Breakdown (remaining tasks):**
+\`\`\`json
+${previous_plan:-[]}
+\`\`\`
+
+Please produce the updated task breakdown for the work that remains, following the rules above for keeping, dropping and adding tasks."
+    elif [ -n "$previous_plan" ] && [ -n "$feedback" ]; then
         prompt="${prompt}
 
 **Previous Task Breakdown (for reference):**
@@ -218,6 +248,10 @@ After analyzing the codebase and the epic, output a JSON array of proposed tasks
 - \`description\`: Detailed description of what needs to be done, including relevant file paths and implementation details. Be thorough — include enough context and specifics that an AI agent can implement the task without further clarification.
 - \`depends_on_temp_ids\`: Array of temp_ids this task depends on (empty array if none)
 - \`acceptance_criteria\`: Array of specific, testable criteria for completion
+- \`estimate\`: Your estimate for the task, with:
+  - \`complexity\`: \"low\" (a contained change following existing patterns), \"medium\" (several files or a new pattern) or \"high\" (cross-cutting or exploratory work)
+  - \`model\`: The model you recommend for the agent doing the task: \"haiku\" for mechanical changes, \"sonnet\" for most tasks, \"opus\" for the hardest ones
+  - \`min_cost_usd\` and \`max_cost_usd\`: The range you expect the agent run to cost in USD. The upper end becomes the task's budget, so leave headroom for a retry of failing checks
 
 Output the tasks as a JSON array wrapped in a markdown code block with the language tag \`verve-tasks\`. Example:
 
@@ -228,14 +262,16 @@ Output the tasks as a JSON array wrapped in a markdown code block with the langu
     \"title\": \"Add database schema and repository for user profiles\",
     \"description\": \"Create the database migration, domain model, and repository implementation for user profiles. This includes the migration file with the profiles table, the Go struct, the repository interface methods, and the PostgreSQL/SQLite implementations...\",
     \"depends_on_temp_ids\": [],
-    \"acceptance_criteria\": [\"Migration creates profiles table with required columns\", \"Repository supports CRUD operations\", \"Both PostgreSQL and SQLite implementations work\"]
+    \"acceptance_criteria\": [\"Migration creates profiles table with required columns\", \"Repository supports CRUD operations\", \"Both PostgreSQL and SQLite implementations work\"],
+    \"estimate\": {\"complexity\": \"medium\", \"model\": \"sonnet\", \"min_cost_usd\": 1.5, \"max_cost_usd\": 4}
   },
   {
     \"temp_id\": \"task_2\",
     \"title\": \"Implement user profile API endpoints\",
     \"description\": \"Build the HTTP handler layer for user profiles including all REST endpoints, request/response types, input validation, and route registration...\",
     \"depends_on_temp_ids\": [\"task_1\"],
-    \"acceptance_criteria\": [\"GET/POST/PUT/DELETE endpoints work\", \"Input validation returns proper errors\", \"Routes registered under /api/v1/profiles\"]
+    \"acceptance_criteria\": [\"GET/POST/PUT/DELETE endpoints work\", \"Input validation returns proper errors\", \"Routes registered under /api/v1/profiles\"],
+    \"estimate\": {\"complexity\": \"low\", \"model\": \"sonnet\", \"min_cost_usd\": 1, \"max_cost_usd\": 2.5}
   }
 ]
 \`\`\`
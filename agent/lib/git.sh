@@ -1,7 +1,7 @@
 #!/bin/bash
 # git.sh — Git configuration, cloning, and branch management
 
-# Depends on: log.sh (sourced by entrypoint.sh)
+# Depends on: log.sh, workspace_cache.sh, shadow.sh (sourced by entrypoint.sh)
 
 configure_git() {
     log_agent "Configuring git..."
@@ -61,9 +61,13 @@ HOOK
 }
 
 clone_repo() {
-    log_agent "Cloning repository: ${GITHUB_REPO}..."
-    git clone "https://${GITHUB_TOKEN}@github.com/${GITHUB_REPO}.git" /workspace/repo
+    if ! workspace_cache_enabled || ! clone_repo_from_cache; then
+        rm -rf /workspace/repo
+        log_agent "Cloning repository: ${GITHUB_REPO}..."
+        git clone "https://${GITHUB_TOKEN}@github.com/${GITHUB_REPO}.git" /workspace/repo
+    fi
     cd /workspace/repo || exit 1
+    use_workspace_dependency_cache
 }
 
 detect_default_branch() {
@@ -78,6 +82,33 @@ detect_default_branch() {
     log_agent "Default branch: ${DEFAULT_BRANCH}"
 }
 
+# Tasks in an epic with a shared integration branch, and tasks stacked on
+# another task's open PR, work from a base branch (BASE_BRANCH) instead of the
+# default branch: the task branch is created from it and the PR targets it.
+# The first task of an epic to run creates the integration branch from the
+# default branch. Shadow-mode runs push nothing, so they keep the default
+# branch.
+use_base_branch() {
+    if [ -z "${BASE_BRANCH}" ] || shadow_mode_enabled; then
+        return 0
+    fi
+
+    if ! git fetch origin "${BASE_BRANCH}" 2>/dev/null; then
+        log_agent "Creating integration branch ${BASE_BRANCH} from ${DEFAULT_BRANCH}"
+        # Another task of the epic may create it at the same time; whichever
+        # push wins, the fetch below picks it up.
+        git push origin "refs/remotes/origin/${DEFAULT_BRANCH}:refs/heads/${BASE_BRANCH}" 2>&1 || true
+        if ! git fetch origin "${BASE_BRANCH}" 2>/dev/null; then
+            log_agent "Warning: could not create ${BASE_BRANCH}, working from ${DEFAULT_BRANCH} instead"
+            return 0
+        fi
+    fi
+
+    git checkout -q -B "${BASE_BRANCH}" "origin/${BASE_BRANCH}"
+    DEFAULT_BRANCH="${BASE_BRANCH}"
+    log_agent "Base branch: ${DEFAULT_BRANCH}"
+}
+
 setup_branch() {
     BRANCH="verve/task-${TASK_NUMBER:-${TASK_ID}}"
     # Track whether the branch already existed on the remote. Used later to
@@ -207,9 +238,14 @@ commit_and_push() {
         changes=$(git log HEAD --oneline 2>/dev/null || true)
     fi
     if [ -z "$changes" ]; then
+        if [ "${ADDITIONAL_REPOS_CHANGED:-false}" = "true" ]; then
+            log_agent "No changes in ${GITHUB_REPO} — only the additional repositories changed"
+            PRIMARY_UNCHANGED=true
+            return 0
+        fi
         log_agent "No changes were made — task appears to already meet the required criteria"
-        echo 'VERVE_NO_CHANGES:true'
-        echo 'VERVE_STATUS:{"files_modified":[],"tests_status":"skip","confidence":"high","blockers":[],"criteria_met":["already_satisfied"],"notes":"No changes needed — the codebase already meets the required criteria"}'
+        emit_event no_changes
+        emit_event status '{"files_modified":[],"tests_status":"skip","confidence":"high","blockers":[],"criteria_met":["already_satisfied"],"notes":"No changes needed — the codebase already meets the required criteria"}'
         exit 0
     fi
 
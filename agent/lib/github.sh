@@ -71,7 +71,7 @@ create_pr() {
         pr_number=$(echo "$response_body" | jq -r '.number // empty')
         if [ -n "$pr_url" ] && [ -n "$pr_number" ]; then
             log_agent "Pull request created: ${pr_url}"
-            echo "VERVE_PR_CREATED:{\"url\":\"${pr_url}\",\"number\":${pr_number}}"
+            emit_event pr_created "$(jq -cn --arg url "$pr_url" --argjson number "$pr_number" --arg repo "$GITHUB_REPO" '{url: $url, number: $number, repo: $repo}')"
         else
             log_agent "Pull request created but could not parse response"
         fi
@@ -112,7 +112,7 @@ update_pr() {
         local pr_url
         pr_url=$(echo "$response_body" | jq -r '.html_url // empty')
         log_agent "Pull request #${pr_number} updated: ${pr_url}"
-        echo "VERVE_PR_UPDATED:{\"url\":\"${pr_url}\",\"number\":${pr_number}}"
+        emit_event pr_updated "$(jq -cn --arg url "$pr_url" --argjson number "$pr_number" --arg repo "$GITHUB_REPO" '{url: $url, number: $number, repo: $repo}')"
         return 0
     else
         local error_msg
@@ -260,7 +260,10 @@ Task Description: ${TASK_DESCRIPTION}
 
 Files changed:
 ${diff_summary}
-
+${PR_CONVENTIONS:+
+The title and description must follow these repository conventions:
+${PR_CONVENTIONS}
+}
 Respond with ONLY valid JSON in this exact format (no markdown, no code blocks, no extra text):
 {\"title\": \"Short descriptive title (max 72 chars)\", \"description\": \"## Summary\\n\\nBrief description of changes.\\n\\n## Changes\\n\\n- Bullet points of what was done\"}"
 
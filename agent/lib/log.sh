@@ -15,3 +15,22 @@ log_header() {
 log_blank() {
     echo ""
 }
+
+# ── Structured events ──────────────────────────────────────────────
+# Structured events for the worker (PR created, cost, tool calls, ...) are
+# written as RFC 7464 JSON text sequence records — an ASCII record separator
+# followed by a JSON object — to a dedicated fd 3, which points at the
+# container's stdout. Writing to fd 3 keeps events out of command
+# substitutions that capture a function's stdout, and the separator keeps
+# them apart from plain log lines.
+if ! { true >&3; } 2>/dev/null; then
+    exec 3>&1
+fi
+
+# emit_event TYPE [PAYLOAD_JSON]
+emit_event() {
+    local type="$1" payload="${2:-"{}"}"
+    local record
+    record=$(jq -cn --arg type "$type" --argjson payload "$payload" '{type: $type, payload: $payload}' 2>/dev/null) || return 0
+    printf '\x1e%s\n' "$record" >&3
+}
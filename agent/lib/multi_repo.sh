@@ -0,0 +1,109 @@
+#!/bin/bash
+# multi_repo.sh — Additional repositories for tasks that span several repos
+
+# Depends on: log.sh, git.sh, github.sh (sourced by entrypoint.sh)
+
+# ADDITIONAL_REPOS is a space-separated list of owner/name repositories the
+# task changes besides GITHUB_REPO. Each is cloned next to the primary
+# repository on the task branch, and gets its own pull request when the agent
+# changes it.
+
+# Prints the directory an additional repository is cloned into.
+# Usage: additional_repo_dir <owner/name>
+additional_repo_dir() {
+    echo "/workspace/repos/$1"
+}
+
+# Prints --add-dir flags giving Claude access to the additional repositories.
+additional_repo_claude_args() {
+    local r
+    for r in ${ADDITIONAL_REPOS}; do
+        printf '%s\n' "--add-dir" "$(additional_repo_dir "$r")"
+    done
+}
+
+# Clones each additional repository and checks out the task branch in it.
+# Runs in subshells so the primary repository's BRANCH and DEFAULT_BRANCH are
+# left untouched.
+clone_additional_repos() {
+    local r dir
+    for r in ${ADDITIONAL_REPOS}; do
+        dir=$(additional_repo_dir "$r")
+        rm -rf "$dir"
+        mkdir -p "$(dirname "$dir")"
+        log_agent "Cloning additional repository: ${r}..."
+        git clone "https://${GITHUB_TOKEN}@github.com/${r}.git" "$dir"
+        (
+            cd "$dir" || exit 1
+            detect_default_branch
+            setup_branch
+        )
+    done
+}
+
+# Commits and pushes the agent's changes in each additional repository, then
+# creates or updates its pull request. pr_created and pr_updated events carry
+# the repository so the worker can tell the pull requests apart. Sets
+# ADDITIONAL_REPOS_CHANGED=true when any repository had changes.
+publish_additional_repos() {
+    ADDITIONAL_REPOS_CHANGED=false
+    local r
+    for r in ${ADDITIONAL_REPOS}; do
+        if (
+            GITHUB_REPO="$r"
+            cd "$(additional_repo_dir "$r")" || exit 1
+            detect_default_branch
+            BRANCH="verve/task-${TASK_NUMBER:-${TASK_ID}}"
+
+            _commit_additional_repo
+            case $? in
+                0) ;;
+                1) exit 2 ;; # nothing to publish
+                *) exit 1 ;;
+            esac
+
+            if [ "$SKIP_PR" = "true" ]; then
+                log_agent "Skip PR mode: branch pushed to ${r}, skipping PR creation"
+            elif pr_exists_for_branch "${BRANCH}"; then
+                generate_and_update_pr "${PR_NUMBER}" "${PR_URL}" "${BRANCH}" "${DEFAULT_BRANCH}"
+            else
+                ensure_base_branch
+                generate_and_create_pr "${BRANCH}" "${DEFAULT_BRANCH}"
+            fi
+        ); then
+            ADDITIONAL_REPOS_CHANGED=true
+        elif [ $? -ne 2 ]; then
+            log_agent "Failed to publish changes to ${r}"
+            return 1
+        fi
+    done
+}
+
+# Commits leftover changes in the current additional repository and pushes
+# the task branch. Returns 1 when the branch has no changes to publish and 2
+# when committing or pushing fails.
+_commit_additional_repo() {
+    git add -A
+    if ! git diff --cached --quiet; then
+        local commit_title="${TASK_TITLE:-${TASK_DESCRIPTION}}"
+        if ! echo "$commit_title" | grep -qE '^(feat|fix|refactor|docs|test|chore|ci|wip)(\(.+\))?!?: '; then
+            commit_title="feat: ${commit_title}"
+        fi
+        git commit -m "${commit_title}" || return 2
+    fi
+
+    git fetch origin "${DEFAULT_BRANCH}" 2>/dev/null || true
+    local changes
+    if git rev-parse "origin/${DEFAULT_BRANCH}" >/dev/null 2>&1; then
+        changes=$(git log "origin/${DEFAULT_BRANCH}..HEAD" --oneline 2>/dev/null || true)
+    else
+        changes=$(git log HEAD --oneline 2>/dev/null || true)
+    fi
+    if [ -z "$changes" ]; then
+        log_agent "No changes in ${GITHUB_REPO}"
+        return 1
+    fi
+
+    log_agent "Pushing branch to ${GITHUB_REPO}..."
+    git push --force-with-lease -u origin "${BRANCH}" || return 2
+}
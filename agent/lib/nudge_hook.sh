@@ -0,0 +1,36 @@
+#!/bin/bash
+# nudge_hook.sh — Claude Code hook that delivers user nudges to the agent
+#
+# The worker drops messages the user sends to a running task into
+# NUDGE_DIR, one file per message. This hook runs after every tool call
+# (PostToolUse) and when Claude is about to finish (Stop). It hands any new
+# messages to Claude and marks them as delivered so each is seen once.
+
+NUDGE_DIR="${NUDGE_DIR:-/tmp/verve-nudges}"
+
+input=$(cat)
+event=$(jq -r '.hook_event_name // empty' <<< "$input" 2>/dev/null)
+
+shopt -s nullglob
+files=("${NUDGE_DIR}"/*.txt)
+if [ ${#files[@]} -eq 0 ]; then
+    exit 0
+fi
+
+messages=""
+for f in "${files[@]}"; do
+    messages+="- $(cat "$f")"$'\n'
+    mv "$f" "${f%.txt}.delivered" 2>/dev/null || rm -f "$f"
+done
+
+context="The user sent the following message(s) while you were working. Take them into account and adjust your approach before continuing:
+${messages}"
+
+case "$event" in
+    Stop)
+        jq -cn --arg reason "$context" '{decision: "block", reason: $reason}'
+        ;;
+    *)
+        jq -cn --arg ctx "$context" '{hookSpecificOutput: {hookEventName: "PostToolUse", additionalContext: $ctx}}'
+        ;;
+esac
@@ -6,7 +6,9 @@ build_prompt() {
     local task_title_or_desc="${TASK_TITLE:-${TASK_DESCRIPTION}}"
     local prompt
 
-    if [ "${ATTEMPT:-1}" -gt 1 ]; then
+    # The run after a proposal is approved is a retry in name only: nothing
+    # has been implemented yet, so it gets the initial prompt.
+    if [ "${ATTEMPT:-1}" -gt 1 ] && [[ "${RETRY_REASON}" != proposal_approved* ]]; then
         prompt="You are an autonomous coding agent running non-interactively. You MUST fix the issues described below by making actual code changes. Do not just explore or plan — write and commit the code.
 
 IMPORTANT: Do NOT use EnterPlanMode or ExitPlanMode. There is no human to approve plans. Just implement the changes directly.
@@ -17,6 +19,9 @@ Reason for retry: ${RETRY_REASON}"
         if echo "$RETRY_REASON" | grep -qi "ci_failure"; then
             prompt+="
 Please examine the existing code changes on this branch, review the CI failure details below, and fix the issues. Do NOT create a new PR - just fix the code and commit to this branch."
+        elif echo "$RETRY_REASON" | grep -qi "security_alert"; then
+            prompt+="
+GitHub security scanning found alerts introduced by this branch, listed below. Fix every alert: remove any committed secret from the code and from the branch history (rewrite the offending commits; the branch is force-pushed when you finish) and load it from configuration instead, and change the flagged code so the code scanning rule no longer matches. Do NOT create a new PR."
         elif echo "$RETRY_REASON" | grep -qi "merge_conflict"; then
             prompt+="
 The branch had merge conflicts with ${DEFAULT_BRANCH}. A rebase was attempted. Please resolve any remaining conflicts, ensure the code works correctly with the latest ${DEFAULT_BRANCH} branch, and commit. Do NOT create a new PR."
@@ -26,11 +31,19 @@ The user has reviewed your previous changes and provided feedback. Please examin
         fi
 
         if [ -n "$RETRY_CONTEXT" ]; then
-            prompt+="
+            if echo "$RETRY_REASON" | grep -qi "security_alert"; then
+                prompt+="
+
+=== Security Alerts ===
+${RETRY_CONTEXT}
+=== End Security Alerts ==="
+            else
+                prompt+="
 
 === CI Failure Output ===
 ${RETRY_CONTEXT}
 === End CI Output ==="
+            fi
         fi
 
         if [ -n "$PREVIOUS_STATUS" ]; then
@@ -99,6 +112,76 @@ ${REPO_EXPECTATIONS}
 === End Repository Context ==="
     fi
 
+    # Add the change plan a human approved before implementation
+    if [ -n "${PROPOSAL}" ]; then
+        prompt+="
+
+=== Approved Plan ===
+A human reviewed and approved this plan for the task. Implement it; if part of it turns out to be wrong, deviate only as far as needed and explain why in your VERVE_STATUS notes:
+${PROPOSAL}
+=== End Approved Plan ==="
+    fi
+
+    # Add the commit and PR conventions the repository enforces
+    if [ -n "${PR_CONVENTIONS}" ]; then
+        prompt+="
+
+=== Commit and Pull Request Conventions ===
+The repository requires these conventions. Follow them in your commit messages; the pull request is checked against them when you finish:
+${PR_CONVENTIONS}
+=== End Commit and Pull Request Conventions ==="
+    fi
+
+    # Add epic planning context if this task was created from an epic
+    if [ -n "${EPIC_CONTEXT}" ]; then
+        prompt+="
+
+=== Epic Planning Context ===
+This task is part of a larger epic. These decisions and constraints were agreed during planning:
+${EPIC_CONTEXT}
+=== End Epic Planning Context ==="
+    fi
+
+    # List the other repositories of a multi-repo task
+    if [ -n "${ADDITIONAL_REPOS}" ]; then
+        prompt+="
+
+=== Additional Repositories ===
+This task spans several repositories. The current directory is ${GITHUB_REPO}. These repositories are also checked out on the task branch and may need changes:"
+        local r
+        for r in ${ADDITIONAL_REPOS}; do
+            prompt+="
+- ${r}: $(additional_repo_dir "$r")"
+        done
+        prompt+="
+Commit your changes in each repository you modify. A pull request is opened in every repository with changes.
+=== End Additional Repositories ==="
+    fi
+
+    if [ -n "${ATTACHMENTS_DIR}" ] && [ -d "${ATTACHMENTS_DIR}" ]; then
+        prompt+="
+
+=== Task Attachments ===
+The user attached these files to the task. Read them for context (screenshots, specs, error logs); they are not part of the repository and must not be committed:"
+        local f
+        for f in "${ATTACHMENTS_DIR}"/*; do
+            [ -f "$f" ] || continue
+            prompt+="
+- ${f}"
+        done
+        prompt+="
+=== End Task Attachments ==="
+    fi
+
+    if [ -n "${ARTIFACTS_DIR}" ]; then
+        mkdir -p "${ARTIFACTS_DIR}"
+        prompt+="
+
+=== Artifacts ===
+Save files that help reviewers check your work to ${ARTIFACTS_DIR}: test reports, coverage summaries, screenshots of UI changes. Subdirectories are kept. They are attached to the task for review and must not be committed. Each file may be up to 25 MB; at most 50 are kept.
+=== End Artifacts ==="
+    fi
+
     # Add tome session memory instructions if available
     if command -v tome &>/dev/null; then
         prompt+='
@@ -123,9 +206,14 @@ Allowed types: feat, fix, refactor, docs, test, chore, ci
 Examples: "feat: add user authentication", "fix(api): handle null response", "chore: update dependencies"
 A git hook will reject commits that do not follow this format.
 
+PLAN: Before you start making changes, break the work into a short list of concrete steps with the TodoWrite tool, and keep it up to date as you go (mark each step in_progress when you start it and completed when it is done). The list is shown to the user as a live checklist of your progress.
+
 As you work, periodically save your progress by running: git add -A && git commit -m "wip: <brief description>" && git push -u origin HEAD
 This ensures your work is pushed to the remote and can be recovered if the session is interrupted.
 
+FOLLOW-UP NOTES: If you discover follow-up work that is out of scope for this task (e.g. a module that needs refactoring, a flaky test, missing documentation), do not do it. Instead, output a line in this exact format on its own line for each item, with a short summary first, so it can be turned into a new task:
+VERVE_NOTE: <short summary of the follow-up work>. <optional details>
+
 IMPORTANT: Before you finish, output a status line in this exact format on its own line:
 VERVE_STATUS:{"files_modified":[],"tests_status":"pass|fail|skip","confidence":"high|medium|low","blockers":[],"criteria_met":[],"notes":"Any context for future retry attempts"}'
 
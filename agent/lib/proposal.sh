@@ -0,0 +1,100 @@
+#!/bin/bash
+# proposal.sh — Plan-only mode: propose a change plan instead of implementing
+
+# Depends on: log.sh, prompt.sh (sourced by entrypoint.sh)
+
+# In plan-only mode (PLAN_ONLY=true) the agent analyzes the repository and
+# writes a change plan to PROPOSAL_FILE as JSON without changing any code.
+# The worker copies the file out and reports it for a human to approve; the
+# approved plan is passed to the implementation run in PROPOSAL.
+PROPOSAL_FILE="/tmp/verve-proposal.json"
+
+plan_only_enabled() {
+    [ "${PLAN_ONLY}" = "true" ]
+}
+
+# Sets PROMPT to the plan-only prompt. On a retry the user's feedback on the
+# previous proposal (RETRY_REASON) and that proposal (PROPOSAL) are included
+# so the agent revises it rather than starting over.
+build_plan_prompt() {
+    local task_title_or_desc="${TASK_TITLE:-${TASK_DESCRIPTION}}"
+    local prompt="You are an autonomous coding agent running non-interactively in plan-only mode. Analyze the repository and propose how to implement the task below. Do NOT modify, create or commit any files in the repository — a human reviews your plan and another run implements it once approved.
+
+Task: ${task_title_or_desc}"
+    if [ -n "${TASK_TITLE}" ] && [ -n "${TASK_DESCRIPTION}" ]; then
+        prompt+="
+Details: ${TASK_DESCRIPTION}"
+    fi
+
+    if [ -n "${PROPOSAL}" ]; then
+        prompt+="
+
+=== Previous Proposal ===
+${PROPOSAL}
+=== End Previous Proposal ==="
+    fi
+    if [ "${ATTEMPT:-1}" -gt 1 ] && [ -n "${RETRY_REASON}" ]; then
+        prompt+="
+
+The user reviewed the previous proposal and asked for changes. Revise the plan to address this feedback:
+${RETRY_REASON}"
+    fi
+
+    if [ -n "${REPO_SUMMARY}" ] || [ -n "${REPO_TECH_STACK}" ]; then
+        prompt+="
+
+=== Repository Context ==="
+        [ -n "${REPO_SUMMARY}" ] && prompt+="
+Repository Summary: ${REPO_SUMMARY}"
+        [ -n "${REPO_TECH_STACK}" ] && prompt+="
+Tech Stack: ${REPO_TECH_STACK}"
+        prompt+="
+=== End Repository Context ==="
+    fi
+
+    if [ -n "${EPIC_CONTEXT}" ]; then
+        prompt+="
+
+=== Epic Planning Context ===
+${EPIC_CONTEXT}
+=== End Epic Planning Context ==="
+    fi
+
+    if [ -n "$ACCEPTANCE_CRITERIA" ]; then
+        prompt+="
+
+ACCEPTANCE CRITERIA (the plan must show how each is met):
+${ACCEPTANCE_CRITERIA}"
+    fi
+
+    prompt+="
+
+When you have finished your analysis, write the plan to ${PROPOSAL_FILE} as a single JSON object in exactly this format:
+{\"summary\":\"One or two sentences describing the change\",\"approach\":\"How you would implement it, step by step\",\"files\":[{\"path\":\"path/to/file\",\"change\":\"What changes in this file and why\"}],\"risks\":[\"Anything that could go wrong or needs a decision\"]}
+List every file you expect to add, change or remove. The file is the only output that is kept."
+
+    PROMPT="$prompt"
+}
+
+# Checks the proposal the agent wrote and logs its summary. Fails when the
+# agent wrote none or it is not a JSON object with a summary.
+record_proposal() {
+    if [ ! -s "$PROPOSAL_FILE" ]; then
+        log_error "Plan-only mode: the agent did not write a proposal to ${PROPOSAL_FILE}"
+        return 1
+    fi
+    if ! jq -e 'type == "object" and (.summary | type == "string" and length > 0)' "$PROPOSAL_FILE" >/dev/null 2>&1; then
+        log_error "Plan-only mode: ${PROPOSAL_FILE} is not a valid proposal"
+        return 1
+    fi
+    log_agent "Plan-only mode: recorded proposal ($(jq '.files // [] | length' "$PROPOSAL_FILE") files)"
+    log_agent "Summary: $(jq -r '.summary' "$PROPOSAL_FILE")"
+}
+
+# Writes a placeholder proposal for dry runs.
+write_dry_run_proposal() {
+    jq -n --arg summary "[Dry Run] ${TASK_TITLE:-${TASK_DESCRIPTION}}" \
+        '{summary: $summary, approach: "Dry-run mode: no analysis was performed.", files: [{path: "verve-dry-run.md", change: "Add a dummy file"}], risks: []}' \
+        > "$PROPOSAL_FILE"
+    log_agent "Created dummy proposal: ${PROPOSAL_FILE}"
+}
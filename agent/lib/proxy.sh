@@ -1,15 +1,16 @@
 #!/bin/bash
-# proxy.sh — Start the beta header stripping proxy inside the agent container.
+# proxy.sh — Start the Anthropic API proxy inside the agent container.
 #
-# When STRIP_ANTHROPIC_BETA_HEADERS=true, this starts a local Node.js reverse
-# proxy that strips anthropic-beta headers from outgoing API requests. The
-# ANTHROPIC_BASE_URL is rewritten to point at the local proxy so Claude Code
-# CLI routes all API traffic through it.
+# When STRIP_ANTHROPIC_BETA_HEADERS=true or COST_BUDGET_USD is set, this
+# starts a local Node.js reverse proxy that strips anthropic-beta headers from
+# outgoing API requests and/or meters their cost against the task's remaining
+# budget. The ANTHROPIC_BASE_URL is rewritten to point at the local proxy so
+# Claude Code CLI routes all API traffic through it.
 #
 # Depends on: log.sh (sourced by entrypoint.sh)
 
-start_beta_proxy() {
-    if [ "${STRIP_ANTHROPIC_BETA_HEADERS}" != "true" ]; then
+start_anthropic_proxy() {
+    if [ "${STRIP_ANTHROPIC_BETA_HEADERS}" != "true" ] && [ -z "${COST_BUDGET_USD}" ]; then
         return
     fi
 
@@ -17,10 +18,13 @@ start_beta_proxy() {
     local port_file
     port_file=$(mktemp)
 
-    log_agent "Starting beta header stripping proxy (upstream: ${upstream})"
+    log_agent "Starting Anthropic API proxy (upstream: ${upstream})"
+    if [ -n "${COST_BUDGET_USD}" ]; then
+        log_agent "Metering API cost against remaining budget: \$${COST_BUDGET_USD}"
+    fi
 
     BETA_PROXY_UPSTREAM="$upstream" BETA_PROXY_PORT_FILE="$port_file" \
-        node /lib/strip_beta_proxy.js &
+        node /lib/anthropic_proxy.js &
     BETA_PROXY_PID=$!
 
     # Wait for the proxy to write its port to the file
@@ -38,12 +42,14 @@ start_beta_proxy() {
     rm -f "$port_file"
 
     if [ -z "$port" ] || ! echo "$port" | grep -qE '^[0-9]+$'; then
-        log_error "Failed to start beta header proxy"
+        log_error "Failed to start Anthropic API proxy"
         kill $BETA_PROXY_PID 2>/dev/null || true
         exit 1
     fi
 
     export ANTHROPIC_BASE_URL="http://127.0.0.1:${port}"
-    export ANTHROPIC_BETA=""
-    log_agent "Beta header proxy running on port ${port}"
+    if [ "${STRIP_ANTHROPIC_BETA_HEADERS}" = "true" ]; then
+        export ANTHROPIC_BETA=""
+    fi
+    log_agent "Anthropic API proxy running on port ${port}"
 }
@@ -0,0 +1,64 @@
+#!/bin/bash
+# shadow.sh — Shadow mode: record the agent's diff instead of pushing
+
+# Depends on: log.sh, multi_repo.sh (sourced by entrypoint.sh)
+
+# In shadow mode (SHADOW_MODE=true) nothing leaves the container: the agent's
+# changes are written to SHADOW_DIFF_FILE as a unified diff, which the worker
+# copies out and reports for review in place of a pull request.
+SHADOW_DIFF_FILE="/tmp/verve-shadow.diff"
+
+# Git's well-known empty tree, used as the diff base for empty repositories.
+_SHADOW_EMPTY_TREE="4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+shadow_mode_enabled() {
+    [ "${SHADOW_MODE}" = "true" ]
+}
+
+# Points the push URL of every cloned repository at an invalid remote so a
+# stray push from the agent fails instead of publishing anything.
+disable_push() {
+    log_agent "Pushes disabled: nothing leaves the container"
+    git remote set-url --push origin no_push
+    local r
+    for r in ${ADDITIONAL_REPOS}; do
+        git -C "$(additional_repo_dir "$r")" remote set-url --push origin no_push
+    done
+}
+
+# Writes the diff of the primary and additional repositories against their
+# default branches to SHADOW_DIFF_FILE. Paths in additional repositories are
+# prefixed with the repository's full name. Emits no_changes when the agent
+# changed nothing.
+record_shadow_diff() {
+    : > "$SHADOW_DIFF_FILE"
+    _shadow_repo_diff /workspace/repo "" >> "$SHADOW_DIFF_FILE"
+    local r
+    for r in ${ADDITIONAL_REPOS}; do
+        _shadow_repo_diff "$(additional_repo_dir "$r")" "${r}/" >> "$SHADOW_DIFF_FILE"
+    done
+
+    if [ ! -s "$SHADOW_DIFF_FILE" ]; then
+        log_agent "Shadow mode: no changes to record"
+        emit_event no_changes
+        return 0
+    fi
+    log_agent "Shadow mode: recorded diff ($(grep -c '^diff --git ' "$SHADOW_DIFF_FILE") files changed)"
+}
+
+# Prints the diff of a repository's working tree, including commits the agent
+# made, against its default branch.
+# Usage: _shadow_repo_diff <dir> <path prefix>
+_shadow_repo_diff() {
+    local dir="$1" prefix="$2"
+    (
+        cd "$dir" || exit 1
+        detect_default_branch >/dev/null
+        git add -A
+        local base="$_SHADOW_EMPTY_TREE"
+        if git rev-parse --verify --quiet "origin/${DEFAULT_BRANCH}" >/dev/null; then
+            base="origin/${DEFAULT_BRANCH}"
+        fi
+        git diff --cached --src-prefix="a/${prefix}" --dst-prefix="b/${prefix}" "$base"
+    )
+}
@@ -0,0 +1,67 @@
+#!/bin/bash
+# workspace_cache.sh — Reuse the per-repo workspace cache across attempts
+
+# Depends on: log.sh (sourced by entrypoint.sh)
+
+# The worker mounts a per-repo Docker volume at WORKSPACE_CACHE_DIR for task
+# runs. It holds a bare mirror of the repository and, when the shared /cache
+# volume is not mounted, the package manager caches for the repo. Both survive
+# between attempts until the worker evicts the volume or a user invalidates it.
+
+workspace_cache_enabled() {
+    [ -n "${WORKSPACE_CACHE_DIR}" ] && [ -d "${WORKSPACE_CACHE_DIR}" ] && [ -w "${WORKSPACE_CACHE_DIR}" ]
+}
+
+# Clones the repository into /workspace/repo using the cached mirror. The
+# mirror is refreshed with a fetch, so retries only download what changed
+# since the last attempt. The working clone borrows objects from the mirror
+# and is dissociated so it stays intact if the cache is discarded.
+# Returns 1 if the cache could not be used; the caller falls back to a plain
+# clone.
+clone_repo_from_cache() {
+    local remote="https://${GITHUB_TOKEN}@github.com/${GITHUB_REPO}.git"
+    local mirror="${WORKSPACE_CACHE_DIR}/repo.git"
+
+    # Concurrent runs for the same repo share the volume, so mirror updates
+    # are serialized. The mirror's stored remote never contains the token.
+    if ! (
+        command -v flock >/dev/null 2>&1 && flock 9
+        if [ -d "${mirror}" ]; then
+            log_agent "Updating cached clone of ${GITHUB_REPO}..."
+            git -C "${mirror}" fetch --prune "${remote}" "+refs/heads/*:refs/heads/*" "+refs/tags/*:refs/tags/*" ||
+                { rm -rf "${mirror}"; exit 1; }
+        else
+            log_agent "Creating cached clone of ${GITHUB_REPO}..."
+            git clone --bare "${remote}" "${mirror}" ||
+                { rm -rf "${mirror}"; exit 1; }
+            git -C "${mirror}" remote set-url origin "https://github.com/${GITHUB_REPO}.git"
+        fi
+    ) 9>"${WORKSPACE_CACHE_DIR}/.lock"; then
+        log_agent "Warning: workspace cache unusable, discarded it"
+        return 1
+    fi
+
+    git clone --reference "${mirror}" --dissociate "${remote}" /workspace/repo
+}
+
+# Points package manager caches at the workspace cache volume when the shared
+# dependency cache is not mounted (WORKSPACE_CACHE_DEPS=true), so dependency
+# downloads are reused across attempts for this repo.
+use_workspace_dependency_cache() {
+    if [ "${WORKSPACE_CACHE_DEPS}" != "true" ] || ! workspace_cache_enabled; then
+        return 0
+    fi
+
+    local deps="${WORKSPACE_CACHE_DIR}/deps"
+    export GOMODCACHE="${deps}/go/mod"
+    export GOPATH="${deps}/go/path"
+    export NPM_CONFIG_CACHE="${deps}/npm"
+    export PNPM_HOME="${deps}/pnpm"
+    export YARN_CACHE_FOLDER="${deps}/yarn"
+    export PIP_CACHE_DIR="${deps}/pip"
+    export CARGO_HOME="${deps}/cargo"
+    export MAVEN_OPTS="-Dmaven.repo.local=${deps}/maven"
+    export GRADLE_USER_HOME="${deps}/gradle"
+    export NUGET_PACKAGES="${deps}/nuget"
+    log_agent "Using workspace cache for dependency caches"
+}
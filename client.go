@@ -0,0 +1,646 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/vervesh/verve/internal/keymanager"
+	"github.com/vervesh/verve/internal/mcp"
+	"github.com/vervesh/verve/pkg/verveclient"
+)
+
+const defaultServerURL = "http://localhost:7400"
+
+// clientFlags are the flags of every command that talks to a running API
+// server. The server URL and API key fall back to api_url and api_key in
+// ~/.config/verve/config.json.
+func clientFlags(extra ...cli.Flag) []cli.Flag {
+	return append([]cli.Flag{
+		&cli.StringFlag{
+			Name:    "server",
+			EnvVars: []string{"VERVE_SERVER"},
+			Usage:   "API server URL (default: api_url from the config file, then " + defaultServerURL + ")",
+		},
+		&cli.StringFlag{
+			Name:    "api-key",
+			EnvVars: []string{"VERVE_API_KEY"},
+			Usage:   "Bearer token sent to the API server (default: api_key from the config file)",
+		},
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "Print machine-readable JSON",
+		},
+	}, extra...)
+}
+
+// clientCommands are the commands for working with a running API server.
+func clientCommands(ctx context.Context) []*cli.Command {
+	return []*cli.Command{
+		{
+			Name:  "task",
+			Usage: "Create and manage tasks",
+			Subcommands: []*cli.Command{
+				{
+					Name:  "create",
+					Usage: "Create a task",
+					Flags: clientFlags(
+						&cli.StringFlag{Name: "repo", Usage: "Repo ID or owner/name", Required: true},
+						&cli.StringFlag{Name: "title", Required: true},
+						&cli.StringFlag{Name: "description"},
+						&cli.StringSliceFlag{Name: "acceptance-criteria", Usage: "Acceptance criterion (repeatable)"},
+						&cli.StringSliceFlag{Name: "depends-on", Usage: "ID of a task this task depends on (repeatable)"},
+						&cli.StringFlag{Name: "stack-on", Usage: "ID of a dependency to build on: start once its PR is open and branch off it"},
+						&cli.StringFlag{Name: "model", Usage: "Model to run the task with (default: the server's default model)"},
+						&cli.StringSliceFlag{Name: "fallback-model", Usage: "Model to fall back to when rate-limited, in order (repeatable, default: the repo's fallback models)"},
+						&cli.Float64Flag{Name: "max-cost", Usage: "Cost budget in USD (0 = unlimited)"},
+						&cli.BoolFlag{Name: "plan-only", Usage: "Propose a change plan for approval instead of implementing the task"},
+					),
+					Action: func(c *cli.Context) error {
+						client, err := newAPIClient(c)
+						if err != nil {
+							return err
+						}
+						repoID, err := resolveRepoID(ctx, client, c.String("repo"))
+						if err != nil {
+							return err
+						}
+						t, err := client.CreateTask(ctx, repoID, verveclient.CreateTaskRequest{
+							Title:              c.String("title"),
+							Description:        c.String("description"),
+							AcceptanceCriteria: c.StringSlice("acceptance-criteria"),
+							DependsOn:          c.StringSlice("depends-on"),
+							StackOn:            c.String("stack-on"),
+							Model:              c.String("model"),
+							FallbackModels:     c.StringSlice("fallback-model"),
+							MaxCostUSD:         c.Float64("max-cost"),
+							PlanOnly:           c.Bool("plan-only"),
+						})
+						if err != nil {
+							return err
+						}
+						return printTasks(c, []verveclient.Task{*t}, t)
+					},
+				},
+				{
+					Name:  "list",
+					Usage: "List a repo's tasks",
+					Flags: clientFlags(
+						&cli.StringFlag{Name: "repo", Usage: "Repo ID or owner/name", Required: true},
+						&cli.StringFlag{Name: "status", Usage: "Only list tasks with this status"},
+					),
+					Action: func(c *cli.Context) error {
+						client, err := newAPIClient(c)
+						if err != nil {
+							return err
+						}
+						repoID, err := resolveRepoID(ctx, client, c.String("repo"))
+						if err != nil {
+							return err
+						}
+						tasks, err := client.ListTasks(ctx, repoID)
+						if err != nil {
+							return err
+						}
+						if status := c.String("status"); status != "" {
+							filtered := tasks[:0]
+							for _, t := range tasks {
+								if t.Status == status {
+									filtered = append(filtered, t)
+								}
+							}
+							tasks = filtered
+						}
+						return printTasks(c, tasks, tasks)
+					},
+				},
+				{
+					Name:      "logs",
+					Usage:     "Print a task's logs",
+					ArgsUsage: "<task-id>",
+					Flags: clientFlags(
+						&cli.BoolFlag{Name: "follow", Aliases: []string{"f"}, Usage: "Keep streaming new logs"},
+						&cli.IntFlag{Name: "attempt", Usage: "Only print this attempt's logs"},
+					),
+					Action: func(c *cli.Context) error {
+						id, err := requireArg(c, "task-id")
+						if err != nil {
+							return err
+						}
+						client, err := newAPIClient(c)
+						if err != nil {
+							return err
+						}
+						stream, err := client.TaskLogs(ctx, id, c.Int("attempt"))
+						if err != nil {
+							return err
+						}
+						defer func() { _ = stream.Close() }()
+						return printLogs(c, stream)
+					},
+				},
+				{
+					Name:      "retry",
+					Usage:     "Retry a failed task",
+					ArgsUsage: "<task-id>",
+					Flags: clientFlags(
+						&cli.StringFlag{Name: "instructions", Usage: "Instructions for the agent's next attempt"},
+					),
+					Action: func(c *cli.Context) error {
+						id, err := requireArg(c, "task-id")
+						if err != nil {
+							return err
+						}
+						client, err := newAPIClient(c)
+						if err != nil {
+							return err
+						}
+						t, err := client.RetryTask(ctx, id, verveclient.RetryTaskRequest{Instructions: c.String("instructions")})
+						if err != nil {
+							return err
+						}
+						return printTasks(c, []verveclient.Task{*t}, t)
+					},
+				},
+				{
+					Name:      "close",
+					Usage:     "Close a task",
+					ArgsUsage: "<task-id>",
+					Flags: clientFlags(
+						&cli.StringFlag{Name: "reason", Usage: "Why the task is closed"},
+					),
+					Action: func(c *cli.Context) error {
+						id, err := requireArg(c, "task-id")
+						if err != nil {
+							return err
+						}
+						client, err := newAPIClient(c)
+						if err != nil {
+							return err
+						}
+						t, err := client.CloseTask(ctx, id, verveclient.CloseRequest{Reason: c.String("reason")})
+						if err != nil {
+							return err
+						}
+						return printTasks(c, []verveclient.Task{*t}, t)
+					},
+				},
+				{
+					Name:      "approve",
+					Usage:     "Approve the pull request of a task in review",
+					ArgsUsage: "<task-id>",
+					Flags: clientFlags(
+						&cli.StringFlag{Name: "body", Usage: "Comment submitted with the approval"},
+					),
+					Action: func(c *cli.Context) error {
+						id, err := requireArg(c, "task-id")
+						if err != nil {
+							return err
+						}
+						client, err := newAPIClient(c)
+						if err != nil {
+							return err
+						}
+						t, err := client.ApproveTask(ctx, id, verveclient.ApproveTaskRequest{Body: c.String("body")})
+						if err != nil {
+							return err
+						}
+						return printTasks(c, []verveclient.Task{*t}, t)
+					},
+				},
+				{
+					Name:      "merge",
+					Usage:     "Merge the pull request of a task in review",
+					ArgsUsage: "<task-id>",
+					Flags: clientFlags(
+						&cli.StringFlag{Name: "method", Usage: "Merge method: merge, squash or rebase (default: the server's merge method)"},
+					),
+					Action: func(c *cli.Context) error {
+						id, err := requireArg(c, "task-id")
+						if err != nil {
+							return err
+						}
+						client, err := newAPIClient(c)
+						if err != nil {
+							return err
+						}
+						t, err := client.MergeTask(ctx, id, verveclient.MergeTaskRequest{MergeMethod: c.String("method")})
+						if err != nil {
+							return err
+						}
+						return printTasks(c, []verveclient.Task{*t}, t)
+					},
+				},
+			},
+		},
+		{
+			Name:  "repo",
+			Usage: "Add and list repos",
+			Subcommands: []*cli.Command{
+				{
+					Name:      "add",
+					Usage:     "Add a GitHub repo",
+					ArgsUsage: "<owner/name>",
+					Flags:     clientFlags(),
+					Action: func(c *cli.Context) error {
+						fullName, err := requireArg(c, "owner/name")
+						if err != nil {
+							return err
+						}
+						client, err := newAPIClient(c)
+						if err != nil {
+							return err
+						}
+						r, err := client.AddRepo(ctx, verveclient.AddRepoRequest{FullName: fullName})
+						if err != nil {
+							return err
+						}
+						return printRepos(c, []verveclient.Repo{*r}, r)
+					},
+				},
+				{
+					Name:  "list",
+					Usage: "List repos",
+					Flags: clientFlags(),
+					Action: func(c *cli.Context) error {
+						client, err := newAPIClient(c)
+						if err != nil {
+							return err
+						}
+						repos, err := client.ListRepos(ctx)
+						if err != nil {
+							return err
+						}
+						return printRepos(c, repos, repos)
+					},
+				},
+			},
+		},
+		{
+			Name:  "epic",
+			Usage: "Plan, confirm and re-plan epics and export their planning transcripts",
+			Subcommands: []*cli.Command{
+				{
+					Name:  "plan",
+					Usage: "Create an epic and queue it for planning",
+					Flags: clientFlags(
+						&cli.StringFlag{Name: "repo", Usage: "Repo ID or owner/name", Required: true},
+						&cli.StringFlag{Name: "title", Required: true},
+						&cli.StringFlag{Name: "description"},
+						&cli.StringFlag{Name: "prompt", Usage: "Instructions for the planning agent"},
+						&cli.StringFlag{Name: "model", Usage: "Model to plan with (default: the server's default model)"},
+					),
+					Action: func(c *cli.Context) error {
+						client, err := newAPIClient(c)
+						if err != nil {
+							return err
+						}
+						repoID, err := resolveRepoID(ctx, client, c.String("repo"))
+						if err != nil {
+							return err
+						}
+						e, err := client.CreateEpic(ctx, repoID, verveclient.CreateEpicRequest{
+							Title:          c.String("title"),
+							Description:    c.String("description"),
+							PlanningPrompt: c.String("prompt"),
+							Model:          c.String("model"),
+						})
+						if err != nil {
+							return err
+						}
+						return printEpic(c, e)
+					},
+				},
+				{
+					Name:      "confirm",
+					Usage:     "Confirm an epic's proposed tasks, creating them",
+					ArgsUsage: "<epic-id>",
+					Flags: clientFlags(
+						&cli.BoolFlag{Name: "not-ready", Usage: "Create the tasks without marking them ready"},
+						&cli.BoolFlag{Name: "shared-branch", Usage: "Tasks branch from and merge into the epic's integration branch"},
+						&cli.StringSliceFlag{Name: "task", Usage: "Temp ID of a proposed task to confirm now; the rest stay proposed (repeatable, default all)"},
+					),
+					Action: func(c *cli.Context) error {
+						id, err := requireArg(c, "epic-id")
+						if err != nil {
+							return err
+						}
+						client, err := newAPIClient(c)
+						if err != nil {
+							return err
+						}
+						e, err := client.ConfirmEpic(ctx, id, verveclient.ConfirmEpicRequest{
+							NotReady:     c.Bool("not-ready"),
+							SharedBranch: c.Bool("shared-branch"),
+							TempIDs:      c.StringSlice("task"),
+						})
+						if err != nil {
+							return err
+						}
+						return printEpic(c, e)
+					},
+				},
+				{
+					Name:      "replan",
+					Usage:     "Re-plan an active epic's remaining work; confirm the new plan to amend it",
+					ArgsUsage: "<epic-id>",
+					Flags: clientFlags(
+						&cli.StringFlag{Name: "prompt", Usage: "What to change about the remaining work"},
+					),
+					Action: func(c *cli.Context) error {
+						id, err := requireArg(c, "epic-id")
+						if err != nil {
+							return err
+						}
+						client, err := newAPIClient(c)
+						if err != nil {
+							return err
+						}
+						e, err := client.ReplanEpic(ctx, id, verveclient.ReplanEpicRequest{Prompt: c.String("prompt")})
+						if err != nil {
+							return err
+						}
+						return printEpic(c, e)
+					},
+				},
+				{
+					Name:      "resume",
+					Usage:     "Resume a draft epic's planning session from its transcript",
+					ArgsUsage: "<epic-id>",
+					Flags: clientFlags(
+						&cli.StringFlag{Name: "message", Usage: "Your next message to the planning agent"},
+					),
+					Action: func(c *cli.Context) error {
+						id, err := requireArg(c, "epic-id")
+						if err != nil {
+							return err
+						}
+						client, err := newAPIClient(c)
+						if err != nil {
+							return err
+						}
+						e, err := client.ResumeEpicPlanning(ctx, id, verveclient.ResumePlanningRequest{Message: c.String("message")})
+						if err != nil {
+							return err
+						}
+						return printEpic(c, e)
+					},
+				},
+				{
+					Name:      "transcript",
+					Usage:     "Print an epic's planning transcript as markdown",
+					ArgsUsage: "<epic-id>",
+					Flags:     clientFlags(),
+					Action: func(c *cli.Context) error {
+						id, err := requireArg(c, "epic-id")
+						if err != nil {
+							return err
+						}
+						client, err := newAPIClient(c)
+						if err != nil {
+							return err
+						}
+						if c.Bool("json") {
+							entries, err := client.GetEpicTranscript(ctx, id)
+							if err != nil {
+								return err
+							}
+							return printJSON(c.App.Writer, entries)
+						}
+						md, err := client.ExportEpicTranscript(ctx, id)
+						if err != nil {
+							return err
+						}
+						_, err = io.WriteString(c.App.Writer, md)
+						return err
+					},
+				},
+			},
+		},
+		{
+			Name:  "events",
+			Usage: "Print task and repo events",
+			Flags: clientFlags(
+				&cli.StringFlag{Name: "repo", Usage: "Only print events for this repo (ID or owner/name)"},
+				&cli.BoolFlag{Name: "follow", Aliases: []string{"f"}, Usage: "Keep streaming events after the initial snapshot"},
+			),
+			Action: func(c *cli.Context) error {
+				client, err := newAPIClient(c)
+				if err != nil {
+					return err
+				}
+				var repoID string
+				if repo := c.String("repo"); repo != "" {
+					if repoID, err = resolveRepoID(ctx, client, repo); err != nil {
+						return err
+					}
+				}
+				stream, err := client.Events(ctx, verveclient.EventsOptions{RepoID: repoID})
+				if err != nil {
+					return err
+				}
+				defer func() { _ = stream.Close() }()
+				return printEvents(c, stream)
+			},
+		},
+		{
+			Name:  "mcp",
+			Usage: "Serve the server's MCP tools over stdio, for MCP clients such as Claude Desktop",
+			Description: "Forwards MCP messages read from stdin to the API server's MCP endpoint and writes\n" +
+				"the responses to stdout. Pass the server's MCP token as the API key.",
+			Flags: clientFlags(),
+			Action: func(c *cli.Context) error {
+				client, err := newAPIClient(c)
+				if err != nil {
+					return err
+				}
+				return mcp.ServeStdio(ctx, os.Stdin, os.Stdout, mcp.HandlerFunc(client.MCP))
+			},
+		},
+	}
+}
+
+// newAPIClient creates a client for the server selected by the client flags.
+func newAPIClient(c *cli.Context) (*verveclient.Client, error) {
+	server, apiKey := c.String("server"), c.String("api-key")
+	if server == "" || apiKey == "" {
+		cfg, err := keymanager.LoadClientConfig()
+		if err != nil {
+			return nil, err
+		}
+		server = firstNonEmpty(server, cfg.APIURL, defaultServerURL)
+		apiKey = firstNonEmpty(apiKey, cfg.APIKey)
+	}
+	var opts []verveclient.Option
+	if apiKey != "" {
+		opts = append(opts, verveclient.WithBearerToken(apiKey))
+	}
+	return verveclient.NewClient(server, opts...), nil
+}
+
+// firstNonEmpty returns the first non-empty value.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// resolveRepoID returns the ID of a repo given its ID or owner/name.
+func resolveRepoID(ctx context.Context, client *verveclient.Client, repo string) (string, error) {
+	if !strings.Contains(repo, "/") {
+		return repo, nil
+	}
+	repos, err := client.ListRepos(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, r := range repos {
+		if strings.EqualFold(r.FullName, repo) {
+			return r.ID, nil
+		}
+	}
+	return "", fmt.Errorf("repo %s has not been added (add it with: verve repo add %s)", repo, repo)
+}
+
+func requireArg(c *cli.Context, name string) (string, error) {
+	if c.NArg() != 1 {
+		return "", fmt.Errorf("expected exactly one argument: <%s>", name)
+	}
+	return c.Args().First(), nil
+}
+
+func printJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// printTasks prints tasks as a table, or v as JSON with --json.
+func printTasks(c *cli.Context, tasks []verveclient.Task, v any) error {
+	if c.Bool("json") {
+		return printJSON(c.App.Writer, v)
+	}
+	tw := tabwriter.NewWriter(c.App.Writer, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ID\tNUMBER\tSTATUS\tCOST\tTITLE")
+	for _, t := range tasks {
+		_, _ = fmt.Fprintf(tw, "%s\t#%d\t%s\t$%.2f\t%s\n", t.ID, t.Number, t.Status, t.CostUSD, t.Title)
+	}
+	return tw.Flush()
+}
+
+// printRepos prints repos as a table, or v as JSON with --json.
+func printRepos(c *cli.Context, repos []verveclient.Repo, v any) error {
+	if c.Bool("json") {
+		return printJSON(c.App.Writer, v)
+	}
+	tw := tabwriter.NewWriter(c.App.Writer, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ID\tREPO\tSETUP")
+	for _, r := range repos {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", r.ID, r.FullName, r.SetupStatus)
+	}
+	return tw.Flush()
+}
+
+func printEpic(c *cli.Context, e *verveclient.Epic) error {
+	if c.Bool("json") {
+		return printJSON(c.App.Writer, e)
+	}
+	_, err := fmt.Fprintf(c.App.Writer, "%s #%d %s (%s, %d proposed tasks, %d tasks)\n",
+		e.ID, e.Number, e.Title, e.Status, len(e.ProposedTasks), len(e.TaskIDs))
+	return err
+}
+
+// printLogs prints log lines from a task log stream, stopping once the
+// stored logs are printed unless --follow is set. With --json each event is
+// printed as a JSON line.
+func printLogs(c *cli.Context, stream *verveclient.EventStream) error {
+	for {
+		ev, err := stream.Next()
+		if err != nil {
+			return streamEnd(err)
+		}
+		if ev.Type == verveclient.EventLogsDone {
+			if !c.Bool("follow") {
+				return nil
+			}
+			continue
+		}
+		if ev.Type != verveclient.EventLogsAppended {
+			continue
+		}
+		var event verveclient.Event
+		if err := ev.Decode(&event); err != nil {
+			return err
+		}
+		if c.Bool("json") {
+			if err := json.NewEncoder(c.App.Writer).Encode(event); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, line := range event.Logs {
+			_, _ = fmt.Fprintln(c.App.Writer, line)
+		}
+	}
+}
+
+// printEvents prints events from the event stream, stopping after the
+// initial snapshot unless --follow is set. With --json each event's data is
+// printed as a JSON line.
+func printEvents(c *cli.Context, stream *verveclient.EventStream) error {
+	for {
+		ev, err := stream.Next()
+		if err != nil {
+			return streamEnd(err)
+		}
+		if c.Bool("json") {
+			_, _ = fmt.Fprintf(c.App.Writer, "{\"type\":%q,\"data\":%s}\n", ev.Type, ev.Data)
+		} else {
+			printEventSummary(c.App.Writer, ev)
+		}
+		if ev.Type == verveclient.EventInit && !c.Bool("follow") {
+			return nil
+		}
+	}
+}
+
+func printEventSummary(w io.Writer, ev verveclient.SSEEvent) {
+	if ev.Type == verveclient.EventInit {
+		var tasks []verveclient.Task
+		if ev.Decode(&tasks) == nil {
+			_, _ = fmt.Fprintf(w, "%s: %d tasks\n", ev.Type, len(tasks))
+		}
+		return
+	}
+	var event verveclient.Event
+	if ev.Decode(&event) != nil {
+		return
+	}
+	switch {
+	case event.Task != nil:
+		_, _ = fmt.Fprintf(w, "%s: %s #%d %s (%s)\n", ev.Type, event.Task.ID, event.Task.Number, event.Task.Title, event.Task.Status)
+	case event.TaskID != "":
+		_, _ = fmt.Fprintf(w, "%s: %s\n", ev.Type, event.TaskID)
+	default:
+		_, _ = fmt.Fprintf(w, "%s: repo %s\n", ev.Type, event.RepoID)
+	}
+}
+
+// streamEnd maps the error that ended a stream to the command's result: the
+// server closing the stream or the user interrupting it is not a failure.
+func streamEnd(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,290 @@
+package adminapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/joshjon/kit/server"
+	"github.com/labstack/echo/v4"
+
+	"github.com/vervesh/verve/internal/audit"
+	"github.com/vervesh/verve/internal/costguard"
+	"github.com/vervesh/verve/internal/epic"
+	"github.com/vervesh/verve/internal/logkey"
+	"github.com/vervesh/verve/internal/msgcat"
+	"github.com/vervesh/verve/internal/task"
+)
+
+// ErrGitHubNotConfigured is returned by JobRunner.Sync when no GitHub token
+// is configured.
+var ErrGitHubNotConfigured = errors.New("github token not configured")
+
+// ErrBackupNotSupported is returned by JobRunner.Backup and Snapshot when the
+// server doesn't use file-backed SQLite.
+var ErrBackupNotSupported = errors.New("backups require file-backed sqlite")
+
+// ErrBackupDirNotConfigured is returned by JobRunner.Backup when no backup
+// directory is configured.
+var ErrBackupDirNotConfigured = errors.New("backup directory not configured")
+
+// JobRunner runs background jobs on demand.
+type JobRunner interface {
+	// Sync runs the PR sync job once.
+	Sync(ctx context.Context) (SyncResult, error)
+	// Reap runs the stale task, epic and conversation reapers once.
+	Reap(ctx context.Context) (ReapResult, error)
+	// RewrapSecrets re-encrypts stored secrets encrypted under a previous
+	// key, completing a key rotation.
+	RewrapSecrets(ctx context.Context) (RewrapResult, error)
+	// Backup writes a snapshot of the database to the backup directory and
+	// removes the oldest snapshots beyond the number kept.
+	Backup(ctx context.Context) (BackupResult, error)
+	// Snapshot writes a consistent snapshot of the database to w.
+	Snapshot(ctx context.Context, w io.Writer) error
+}
+
+// HTTPHandler handles admin HTTP requests. All endpoints require the admin
+// token as a bearer token.
+type HTTPHandler struct {
+	jobs       JobRunner
+	taskStore  *task.Store
+	epicStore  *epic.Store
+	auditStore *audit.Store
+	killSwitch *costguard.Switch
+	token      string
+}
+
+// NewHTTPHandler creates a new HTTPHandler. The token must not be empty.
+func NewHTTPHandler(jobs JobRunner, taskStore *task.Store, epicStore *epic.Store, auditStore *audit.Store, killSwitch *costguard.Switch, token string) *HTTPHandler {
+	return &HTTPHandler{
+		jobs:       jobs,
+		taskStore:  taskStore,
+		epicStore:  epicStore,
+		auditStore: auditStore,
+		killSwitch: killSwitch,
+		token:      token,
+	}
+}
+
+// Register adds the admin endpoints to the provided Echo router group.
+func (h *HTTPHandler) Register(g *echo.Group) {
+	admin := g.Group("/admin", RequireToken(h.token))
+	admin.POST("/sync", h.Sync)
+	admin.POST("/reap", h.Reap)
+	admin.POST("/secrets/rewrap", h.RewrapSecrets)
+	admin.GET("/backup", h.DownloadBackup)
+	admin.POST("/backup", h.Backup)
+	admin.POST("/tasks/:id/force-status", h.ForceTaskStatus)
+	admin.POST("/epics/:id/force-status", h.ForceEpicStatus)
+	admin.GET("/audit", h.ListAudit)
+	admin.GET("/dispatch-pause", h.GetDispatchPause)
+	admin.POST("/dispatch-pause", h.PauseDispatch)
+	admin.POST("/dispatch-pause/acknowledge", h.AcknowledgeDispatchPause)
+}
+
+// Sync handles POST /admin/sync
+func (h *HTTPHandler) Sync(c echo.Context) error {
+	res, err := h.jobs.Sync(c.Request().Context())
+	if err != nil {
+		if errors.Is(err, ErrGitHubNotConfigured) {
+			return echo.NewHTTPError(http.StatusServiceUnavailable, msgcat.Text(msgcat.ErrGitHubTokenNotConfigured))
+		}
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, res)
+}
+
+// Reap handles POST /admin/reap
+func (h *HTTPHandler) Reap(c echo.Context) error {
+	res, err := h.jobs.Reap(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, res)
+}
+
+// RewrapSecrets handles POST /admin/secrets/rewrap
+// Secrets are otherwise rewrapped as they are loaded on startup, so after
+// rotating the encryption key this moves every secret under the new key
+// before the previous one is retired.
+func (h *HTTPHandler) RewrapSecrets(c echo.Context) error {
+	res, err := h.jobs.RewrapSecrets(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, res)
+}
+
+// DownloadBackup handles GET /admin/backup
+// It streams a consistent snapshot of the database, which `verve restore`
+// can restore.
+func (h *HTTPHandler) DownloadBackup(c echo.Context) error {
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "application/vnd.sqlite3")
+	res.Header().Set(echo.HeaderContentDisposition,
+		fmt.Sprintf("attachment; filename=%q", "verve-"+time.Now().UTC().Format(BackupTimeFormat)+".db"))
+	if err := h.jobs.Snapshot(c.Request().Context(), res); err != nil {
+		return backupError(err)
+	}
+	return nil
+}
+
+// Backup handles POST /admin/backup
+// It writes a snapshot to the backup directory now instead of waiting for
+// the next scheduled backup.
+func (h *HTTPHandler) Backup(c echo.Context) error {
+	res, err := h.jobs.Backup(c.Request().Context())
+	if err != nil {
+		return backupError(err)
+	}
+	return server.SetResponse(c, http.StatusOK, res)
+}
+
+func backupError(err error) error {
+	switch {
+	case errors.Is(err, ErrBackupNotSupported):
+		return echo.NewHTTPError(http.StatusNotImplemented, msgcat.Text(msgcat.ErrBackupNotSupported))
+	case errors.Is(err, ErrBackupDirNotConfigured):
+		return echo.NewHTTPError(http.StatusServiceUnavailable, msgcat.Text(msgcat.ErrBackupDirNotConfigured))
+	}
+	return err
+}
+
+// ForceTaskStatus handles POST /admin/tasks/:id/force-status
+// It sets a task's status outside the normal lifecycle and records the change
+// in the audit log. Transitions that would break task invariants, such as
+// moving a merged task back to running, are refused with 409.
+func (h *HTTPHandler) ForceTaskStatus(c echo.Context) error {
+	req, err := server.BindRequest[ForceTaskStatusRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	ctx := c.Request().Context()
+	status := task.Status(req.Status)
+	reason := strings.TrimSpace(req.Reason)
+
+	prev, err := h.taskStore.ForceStatus(ctx, id, status, reason)
+	if err != nil {
+		return err
+	}
+	entry := audit.NewStatusChangeEntry(audit.EntityTask, id.String(), string(prev), string(status), reason)
+	if err := h.auditStore.Record(ctx, entry); err != nil {
+		return err
+	}
+
+	t, err := h.taskStore.ReadTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	t.Logs = nil
+	return server.SetResponse(c, http.StatusOK, ForceTaskStatusResponse{Task: t, PreviousStatus: prev, Audit: entry})
+}
+
+// ForceEpicStatus handles POST /admin/epics/:id/force-status
+// It sets an epic's status outside the normal lifecycle and records the
+// change in the audit log. Transitions that would break epic invariants are
+// refused with 409.
+func (h *HTTPHandler) ForceEpicStatus(c echo.Context) error {
+	req, err := server.BindRequest[ForceEpicStatusRequest](c)
+	if err != nil {
+		return err
+	}
+	id := epic.MustParseEpicID(req.ID)
+	c.Set(logkey.EpicID, id.String())
+
+	ctx := c.Request().Context()
+	status := epic.Status(req.Status)
+	reason := strings.TrimSpace(req.Reason)
+
+	prev, err := h.epicStore.ForceStatus(ctx, id, status, reason)
+	if err != nil {
+		return err
+	}
+	entry := audit.NewStatusChangeEntry(audit.EntityEpic, id.String(), string(prev), string(status), reason)
+	if err := h.auditStore.Record(ctx, entry); err != nil {
+		return err
+	}
+
+	e, err := h.epicStore.ReadEpic(ctx, id)
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, ForceEpicStatusResponse{Epic: e, PreviousStatus: prev, Audit: entry})
+}
+
+// ListAudit handles GET /admin/audit
+// It returns the most recent audit entries, newest first, optionally filtered
+// with ?entity_id=xxx.
+func (h *HTTPHandler) ListAudit(c echo.Context) error {
+	req, err := server.BindRequest[ListAuditRequest](c)
+	if err != nil {
+		return err
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultAuditLimit
+	}
+	entries, err := h.auditStore.ListEntries(c.Request().Context(), req.EntityID, limit)
+	if err != nil {
+		return err
+	}
+	return server.SetResponseList(c, http.StatusOK, entries, "")
+}
+
+// GetDispatchPause handles GET /admin/dispatch-pause
+func (h *HTTPHandler) GetDispatchPause(c echo.Context) error {
+	p, err := h.killSwitch.Status(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, DispatchPauseResponse{Paused: p != nil, Pause: p})
+}
+
+// PauseDispatch handles POST /admin/dispatch-pause
+// It trips the dispatch kill switch by hand. When dispatch is already paused
+// the existing pause is kept.
+func (h *HTTPHandler) PauseDispatch(c echo.Context) error {
+	req, err := server.BindRequest[PauseDispatchRequest](c)
+	if err != nil {
+		return err
+	}
+	p, err := h.killSwitch.Trip(c.Request().Context(), req.Reason, nil)
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, DispatchPauseResponse{Paused: true, Pause: p})
+}
+
+// AcknowledgeDispatchPause handles POST /admin/dispatch-pause/acknowledge
+// It lifts the dispatch pause so agents are handed work again. The lifted
+// pause is returned as acknowledged.
+func (h *HTTPHandler) AcknowledgeDispatchPause(c echo.Context) error {
+	p, err := h.killSwitch.Acknowledge(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, DispatchPauseResponse{Acknowledged: p})
+}
+
+// RequireToken returns middleware that rejects requests without the admin
+// token as a bearer token.
+func RequireToken(adminToken string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if !ok || adminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+				return echo.NewHTTPError(http.StatusUnauthorized, msgcat.Text(msgcat.ErrInvalidAdminToken))
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package adminapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/joshjon/kit/log"
+	"github.com/joshjon/kit/server"
+	"github.com/joshjon/kit/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/adminapi"
+	"github.com/vervesh/verve/internal/audit"
+	"github.com/vervesh/verve/internal/costguard"
+	"github.com/vervesh/verve/internal/epic"
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/setting"
+	"github.com/vervesh/verve/internal/sqlite"
+	"github.com/vervesh/verve/internal/task"
+)
+
+const testToken = "s3cret"
+
+type fixture struct {
+	Server     *server.Server
+	TaskStore  *task.Store
+	EpicStore  *epic.Store
+	AuditStore *audit.Store
+	KillSwitch *costguard.Switch
+	Repo       *repo.Repo
+	t          *testing.T
+}
+
+func newFixture(t *testing.T, jobs adminapi.JobRunner) *fixture {
+	t.Helper()
+
+	db := sqlite.NewTestDB(t)
+	logger := log.NewLogger(log.WithNop())
+
+	taskStore := task.NewStore(sqlite.NewTaskRepository(db), task.NewBroker(nil))
+	repoStore := repo.NewStore(sqlite.NewRepoRepository(db))
+	epicStore := epic.NewStore(sqlite.NewEpicRepository(db), nil, logger)
+	auditStore := audit.NewStore(sqlite.NewAuditRepository(db), logger)
+	killSwitch := costguard.NewSwitch(setting.NewService(sqlite.NewSettingRepository(db)))
+
+	srv, err := server.NewServer(testutil.GetFreePort(t))
+	require.NoError(t, err)
+	srv.Register("/api/v1", adminapi.NewHTTPHandler(jobs, taskStore, epicStore, auditStore, killSwitch, testToken))
+
+	go srv.Start()
+	require.NoError(t, srv.WaitHealthy(10, 100*time.Millisecond))
+	t.Cleanup(func() { srv.Stop(context.Background()) })
+
+	// Pre-create a repo for use in tests.
+	r, _ := repo.NewRepo("owner/test-repo")
+	require.NoError(t, repoStore.CreateRepo(context.Background(), r))
+
+	return &fixture{
+		Server:     srv,
+		TaskStore:  taskStore,
+		EpicStore:  epicStore,
+		AuditStore: auditStore,
+		KillSwitch: killSwitch,
+		Repo:       r,
+		t:          t,
+	}
+}
+
+func newTestServer(t *testing.T, jobs adminapi.JobRunner) *server.Server {
+	t.Helper()
+	return newFixture(t, jobs).Server
+}
+
+// --- Data helpers ---
+
+func (f *fixture) createTask(title string) *task.Task {
+	f.t.Helper()
+	tsk := task.NewTask(f.Repo.ID.String(), title, "", nil, nil, 0, false, false, "", true)
+	require.NoError(f.t, f.TaskStore.CreateTask(context.Background(), tsk))
+	return tsk
+}
+
+func (f *fixture) createEpic(title string) *epic.Epic {
+	f.t.Helper()
+	e := epic.NewEpic(f.Repo.ID.String(), title, "")
+	require.NoError(f.t, f.EpicStore.CreateEpic(context.Background(), e))
+	return e
+}
+
+// --- HTTP helpers ---
+
+// doJSON sends an authenticated JSON request and decodes the response into
+// out when it is not nil.
+func (f *fixture) doJSON(method, path string, body, out any) *http.Response {
+	f.t.Helper()
+	var r io.Reader = http.NoBody
+	if body != nil {
+		b, err := json.Marshal(body)
+		require.NoError(f.t, err)
+		r = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, f.Server.Address()+path, r)
+	require.NoError(f.t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testToken)
+
+	httpRes, err := testutil.DefaultClient.Do(req)
+	require.NoError(f.t, err)
+	f.t.Cleanup(func() { httpRes.Body.Close() })
+	if out != nil && httpRes.StatusCode < 300 {
+		require.NoError(f.t, json.NewDecoder(httpRes.Body).Decode(out))
+	}
+	return httpRes
+}
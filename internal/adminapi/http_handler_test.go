@@ -0,0 +1,334 @@
+package adminapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/joshjon/kit/server"
+	"github.com/joshjon/kit/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/adminapi"
+	"github.com/vervesh/verve/internal/audit"
+	"github.com/vervesh/verve/internal/epic"
+	"github.com/vervesh/verve/internal/msgcat"
+	"github.com/vervesh/verve/internal/task"
+)
+
+type stubJobRunner struct {
+	syncCalls   int
+	reapCalls   int
+	rewrapCalls int
+	syncErr     error
+	backupErr   error
+}
+
+func (s *stubJobRunner) Sync(_ context.Context) (adminapi.SyncResult, error) {
+	s.syncCalls++
+	if s.syncErr != nil {
+		return adminapi.SyncResult{}, s.syncErr
+	}
+	return adminapi.SyncResult{ReposChecked: 2, TasksChecked: 5}, nil
+}
+
+func (s *stubJobRunner) Reap(_ context.Context) (adminapi.ReapResult, error) {
+	s.reapCalls++
+	return adminapi.ReapResult{TimedOutTasks: 1, TimedOutEpics: 2, TimedOutConversations: 3}, nil
+}
+
+func (s *stubJobRunner) RewrapSecrets(_ context.Context) (adminapi.RewrapResult, error) {
+	s.rewrapCalls++
+	return adminapi.RewrapResult{KeyID: "env:abc", Rewrapped: []string{"github_token"}}, nil
+}
+
+func (s *stubJobRunner) Backup(_ context.Context) (adminapi.BackupResult, error) {
+	if s.backupErr != nil {
+		return adminapi.BackupResult{}, s.backupErr
+	}
+	return adminapi.BackupResult{Path: "/backups/verve-20260101T000000Z.db", SizeBytes: 4096, Removed: []string{}}, nil
+}
+
+func (s *stubJobRunner) Snapshot(_ context.Context, w io.Writer) error {
+	if s.backupErr != nil {
+		return s.backupErr
+	}
+	_, err := io.WriteString(w, "SQLite format 3")
+	return err
+}
+
+func doPost(t *testing.T, url, token string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, nil)
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	httpRes, err := testutil.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { httpRes.Body.Close() })
+	return httpRes
+}
+
+func TestAdmin_RequiresToken(t *testing.T) {
+	jobs := &stubJobRunner{}
+	srv := newTestServer(t, jobs)
+
+	for _, path := range []string{"/api/v1/admin/sync", "/api/v1/admin/reap", "/api/v1/admin/secrets/rewrap"} {
+		httpRes := doPost(t, srv.Address()+path, "")
+		assert.Equal(t, http.StatusUnauthorized, httpRes.StatusCode, path)
+
+		httpRes = doPost(t, srv.Address()+path, "wrong")
+		assert.Equal(t, http.StatusUnauthorized, httpRes.StatusCode, path)
+	}
+	assert.Zero(t, jobs.syncCalls)
+	assert.Zero(t, jobs.reapCalls)
+	assert.Zero(t, jobs.rewrapCalls)
+}
+
+func TestAdmin_Sync(t *testing.T) {
+	jobs := &stubJobRunner{}
+	srv := newTestServer(t, jobs)
+
+	httpRes := doPost(t, srv.Address()+"/api/v1/admin/sync", testToken)
+	require.Equal(t, http.StatusOK, httpRes.StatusCode)
+
+	var res server.Response[adminapi.SyncResult]
+	require.NoError(t, json.NewDecoder(httpRes.Body).Decode(&res))
+	assert.Equal(t, adminapi.SyncResult{ReposChecked: 2, TasksChecked: 5}, res.Data)
+	assert.Equal(t, 1, jobs.syncCalls)
+}
+
+func TestAdmin_SyncGitHubNotConfigured(t *testing.T) {
+	jobs := &stubJobRunner{syncErr: adminapi.ErrGitHubNotConfigured}
+	srv := newTestServer(t, jobs)
+
+	httpRes := doPost(t, srv.Address()+"/api/v1/admin/sync", testToken)
+	assert.Equal(t, http.StatusServiceUnavailable, httpRes.StatusCode)
+}
+
+func TestAdmin_SyncFailure(t *testing.T) {
+	jobs := &stubJobRunner{syncErr: errors.New("boom")}
+	srv := newTestServer(t, jobs)
+
+	httpRes := doPost(t, srv.Address()+"/api/v1/admin/sync", testToken)
+	assert.Equal(t, http.StatusInternalServerError, httpRes.StatusCode)
+}
+
+func TestAdmin_Reap(t *testing.T) {
+	jobs := &stubJobRunner{}
+	srv := newTestServer(t, jobs)
+
+	httpRes := doPost(t, srv.Address()+"/api/v1/admin/reap", testToken)
+	require.Equal(t, http.StatusOK, httpRes.StatusCode)
+
+	var res server.Response[adminapi.ReapResult]
+	require.NoError(t, json.NewDecoder(httpRes.Body).Decode(&res))
+	assert.Equal(t, adminapi.ReapResult{TimedOutTasks: 1, TimedOutEpics: 2, TimedOutConversations: 3}, res.Data)
+	assert.Equal(t, 1, jobs.reapCalls)
+}
+
+func TestAdmin_RewrapSecrets(t *testing.T) {
+	jobs := &stubJobRunner{}
+	srv := newTestServer(t, jobs)
+
+	httpRes := doPost(t, srv.Address()+"/api/v1/admin/secrets/rewrap", testToken)
+	require.Equal(t, http.StatusOK, httpRes.StatusCode)
+
+	var res server.Response[adminapi.RewrapResult]
+	require.NoError(t, json.NewDecoder(httpRes.Body).Decode(&res))
+	assert.Equal(t, adminapi.RewrapResult{KeyID: "env:abc", Rewrapped: []string{"github_token"}}, res.Data)
+	assert.Equal(t, 1, jobs.rewrapCalls)
+}
+
+func TestAdmin_Backup(t *testing.T) {
+	srv := newTestServer(t, &stubJobRunner{})
+
+	httpRes := doPost(t, srv.Address()+"/api/v1/admin/backup", testToken)
+	require.Equal(t, http.StatusOK, httpRes.StatusCode)
+
+	var res server.Response[adminapi.BackupResult]
+	require.NoError(t, json.NewDecoder(httpRes.Body).Decode(&res))
+	assert.Equal(t, int64(4096), res.Data.SizeBytes)
+}
+
+func TestAdmin_BackupErrors(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{adminapi.ErrBackupNotSupported, http.StatusNotImplemented},
+		{adminapi.ErrBackupDirNotConfigured, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		srv := newTestServer(t, &stubJobRunner{backupErr: tt.err})
+		httpRes := doPost(t, srv.Address()+"/api/v1/admin/backup", testToken)
+		assert.Equal(t, tt.code, httpRes.StatusCode, tt.err)
+	}
+}
+
+func TestAdmin_DownloadBackup(t *testing.T) {
+	f := newFixture(t, &stubJobRunner{})
+
+	httpRes := f.doJSON(http.MethodGet, "/api/v1/admin/backup", nil, nil)
+	require.Equal(t, http.StatusOK, httpRes.StatusCode)
+	assert.Contains(t, httpRes.Header.Get("Content-Disposition"), "attachment")
+	body, err := io.ReadAll(httpRes.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "SQLite format 3", string(body))
+}
+
+func TestAdmin_ForceTaskStatus(t *testing.T) {
+	f := newFixture(t, &stubJobRunner{})
+	tsk := f.createTask("Stuck task")
+
+	var res server.Response[adminapi.ForceTaskStatusResponse]
+	httpRes := f.doJSON(http.MethodPost, "/api/v1/admin/tasks/"+tsk.ID.String()+"/force-status",
+		map[string]string{"status": "failed", "reason": " worker vanished "}, &res)
+	require.Equal(t, http.StatusOK, httpRes.StatusCode)
+
+	assert.Equal(t, task.StatusPending, res.Data.PreviousStatus)
+	assert.Equal(t, task.StatusFailed, res.Data.Task.Status)
+	assert.Equal(t, msgcat.Text(msgcat.TaskClosedByAdmin, "worker vanished"), res.Data.Task.CloseReason)
+	require.NotNil(t, res.Data.Audit)
+	assert.Equal(t, audit.ActionForceStatus, res.Data.Audit.Action)
+
+	entries, err := f.AuditStore.ListEntries(context.Background(), tsk.ID.String(), 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, audit.EntityTask, entries[0].EntityType)
+	assert.Equal(t, "pending", entries[0].FromStatus)
+	assert.Equal(t, "failed", entries[0].ToStatus)
+	assert.Equal(t, "worker vanished", entries[0].Reason)
+}
+
+func TestAdmin_ForceTaskStatus_RefusesMergedToRunning(t *testing.T) {
+	f := newFixture(t, &stubJobRunner{})
+	ctx := context.Background()
+	tsk := f.createTask("Merged task")
+	require.NoError(t, f.TaskStore.SetTaskPullRequest(ctx, tsk.ID, "https://github.com/owner/test-repo/pull/1", 1))
+	require.NoError(t, f.TaskStore.UpdateTaskStatus(ctx, tsk.ID, task.StatusMerged))
+
+	httpRes := f.doJSON(http.MethodPost, "/api/v1/admin/tasks/"+tsk.ID.String()+"/force-status",
+		map[string]string{"status": "running", "reason": "retry"}, nil)
+	assert.Equal(t, http.StatusConflict, httpRes.StatusCode)
+
+	read, err := f.TaskStore.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusMerged, read.Status)
+
+	entries, err := f.AuditStore.ListEntries(ctx, tsk.ID.String(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "expected refused change not to be audited")
+}
+
+func TestAdmin_ForceTaskStatus_Validation(t *testing.T) {
+	f := newFixture(t, &stubJobRunner{})
+	tsk := f.createTask("Task")
+	path := "/api/v1/admin/tasks/" + tsk.ID.String() + "/force-status"
+
+	tests := map[string]struct {
+		path string
+		body map[string]string
+		want int
+	}{
+		"missing reason": {path: path, body: map[string]string{"status": "failed"}, want: http.StatusBadRequest},
+		"blank reason":   {path: path, body: map[string]string{"status": "failed", "reason": "  "}, want: http.StatusBadRequest},
+		"unknown status": {path: path, body: map[string]string{"status": "paused", "reason": "x"}, want: http.StatusBadRequest},
+		"invalid id":     {path: "/api/v1/admin/tasks/nope/force-status", body: map[string]string{"status": "failed", "reason": "x"}, want: http.StatusBadRequest},
+		"not found": {
+			path: "/api/v1/admin/tasks/" + task.NewTaskID().String() + "/force-status",
+			body: map[string]string{"status": "failed", "reason": "x"},
+			want: http.StatusNotFound,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			httpRes := f.doJSON(http.MethodPost, tt.path, tt.body, nil)
+			assert.Equal(t, tt.want, httpRes.StatusCode)
+		})
+	}
+}
+
+func TestAdmin_ForceEpicStatus(t *testing.T) {
+	f := newFixture(t, &stubJobRunner{})
+	e := f.createEpic("Abandoned epic")
+	path := "/api/v1/admin/epics/" + e.ID.String() + "/force-status"
+
+	// New epics are still planning and have no tasks, so they can't be activated.
+	httpRes := f.doJSON(http.MethodPost, path, map[string]string{"status": "active", "reason": "x"}, nil)
+	assert.Equal(t, http.StatusConflict, httpRes.StatusCode)
+
+	var res server.Response[adminapi.ForceEpicStatusResponse]
+	httpRes = f.doJSON(http.MethodPost, path, map[string]string{"status": "closed", "reason": "abandoned"}, &res)
+	require.Equal(t, http.StatusOK, httpRes.StatusCode)
+	assert.Equal(t, epic.StatusPlanning, res.Data.PreviousStatus)
+	assert.Equal(t, epic.StatusClosed, res.Data.Epic.Status)
+	assert.Contains(t, res.Data.Epic.SessionLog, "system: Status forced from planning to closed by admin: abandoned")
+	assert.Equal(t, audit.EntityEpic, res.Data.Audit.EntityType)
+}
+
+func TestAdmin_ListAudit(t *testing.T) {
+	f := newFixture(t, &stubJobRunner{})
+	first := f.createTask("First")
+	second := f.createTask("Second")
+	for _, tsk := range []*task.Task{first, second} {
+		httpRes := f.doJSON(http.MethodPost, "/api/v1/admin/tasks/"+tsk.ID.String()+"/force-status",
+			map[string]string{"status": "closed", "reason": "cleanup"}, nil)
+		require.Equal(t, http.StatusOK, httpRes.StatusCode)
+	}
+
+	var res server.ResponseList[audit.Entry]
+	httpRes := f.doJSON(http.MethodGet, "/api/v1/admin/audit", nil, &res)
+	require.Equal(t, http.StatusOK, httpRes.StatusCode)
+	require.Len(t, res.Data, 2)
+	assert.Equal(t, second.ID.String(), res.Data[0].EntityID, "expected newest first")
+
+	res = server.ResponseList[audit.Entry]{}
+	httpRes = f.doJSON(http.MethodGet, "/api/v1/admin/audit?entity_id="+first.ID.String(), nil, &res)
+	require.Equal(t, http.StatusOK, httpRes.StatusCode)
+	require.Len(t, res.Data, 1)
+	assert.Equal(t, first.ID.String(), res.Data[0].EntityID)
+
+	httpRes = f.doJSON(http.MethodGet, "/api/v1/admin/audit?limit=100000", nil, nil)
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+}
+
+func TestAdmin_DispatchPause(t *testing.T) {
+	f := newFixture(t, &stubJobRunner{})
+
+	var res server.Response[adminapi.DispatchPauseResponse]
+	httpRes := f.doJSON(http.MethodGet, "/api/v1/admin/dispatch-pause", nil, &res)
+	require.Equal(t, http.StatusOK, httpRes.StatusCode)
+	assert.False(t, res.Data.Paused)
+	assert.Nil(t, res.Data.Pause)
+
+	httpRes = f.doJSON(http.MethodPost, "/api/v1/admin/dispatch-pause", map[string]string{"reason": " "}, nil)
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode, "a reason is required")
+
+	res = server.Response[adminapi.DispatchPauseResponse]{}
+	httpRes = f.doJSON(http.MethodPost, "/api/v1/admin/dispatch-pause", map[string]string{"reason": "investigating spend"}, &res)
+	require.Equal(t, http.StatusOK, httpRes.StatusCode)
+	assert.True(t, res.Data.Paused)
+	require.NotNil(t, res.Data.Pause)
+	assert.Equal(t, "investigating spend", res.Data.Pause.Reason)
+
+	paused, err := f.KillSwitch.DispatchPaused(context.Background())
+	require.NoError(t, err)
+	assert.True(t, paused)
+
+	res = server.Response[adminapi.DispatchPauseResponse]{}
+	httpRes = f.doJSON(http.MethodPost, "/api/v1/admin/dispatch-pause/acknowledge", nil, &res)
+	require.Equal(t, http.StatusOK, httpRes.StatusCode)
+	assert.False(t, res.Data.Paused)
+	require.NotNil(t, res.Data.Acknowledged)
+	assert.Equal(t, "investigating spend", res.Data.Acknowledged.Reason)
+
+	paused, err = f.KillSwitch.DispatchPaused(context.Background())
+	require.NoError(t, err)
+	assert.False(t, paused)
+}
@@ -0,0 +1,130 @@
+package adminapi
+
+import (
+	"strings"
+
+	"github.com/cohesivestack/valgo"
+
+	"github.com/vervesh/verve/internal/audit"
+	"github.com/vervesh/verve/internal/costguard"
+	"github.com/vervesh/verve/internal/epic"
+	"github.com/vervesh/verve/internal/task"
+)
+
+// SyncResult summarizes a PR sync run.
+type SyncResult struct {
+	ReposChecked int `json:"repos_checked"`
+	TasksChecked int `json:"tasks_checked"`
+}
+
+// ReapResult summarizes a reaper run.
+type ReapResult struct {
+	TimedOutTasks         int `json:"timed_out_tasks"`
+	RequeuedTasks         int `json:"requeued_tasks"`
+	TimedOutEpics         int `json:"timed_out_epics"`
+	TimedOutConversations int `json:"timed_out_conversations"`
+}
+
+// RewrapResult summarizes a rewrap of the stored secrets.
+type RewrapResult struct {
+	KeyID     string   `json:"key_id"`    // The key secrets are now encrypted under
+	Rewrapped []string `json:"rewrapped"` // Secrets that were under a previous key
+}
+
+// BackupTimeFormat is the UTC timestamp in backup file names, which sorts
+// oldest first.
+const BackupTimeFormat = "20060102T150405Z"
+
+// BackupResult summarizes a backup to the backup directory.
+type BackupResult struct {
+	Path      string   `json:"path"`
+	SizeBytes int64    `json:"size_bytes"`
+	Removed   []string `json:"removed"` // Older backups removed to stay within the number kept
+}
+
+const (
+	maxReasonLen      = 1000
+	defaultAuditLimit = 50
+	maxAuditLimit     = 500
+)
+
+// ForceTaskStatusRequest is the request body for forcing a task's status.
+// A reason is mandatory and recorded in the audit log.
+type ForceTaskStatusRequest struct {
+	ID     string `param:"id" json:"-"`
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+func (r ForceTaskStatusRequest) Validate() error {
+	return valgo.In("params", valgo.Is(task.TaskIDValidator(r.ID, "id"))).
+		Is(valgo.String(r.Status, "status").Passing(func(s string) bool {
+			return task.ValidStatus(task.Status(s))
+		}, "Must be a valid task status")).
+		Is(reasonValidator(r.Reason)).
+		ToError()
+}
+
+// ForceEpicStatusRequest is the request body for forcing an epic's status.
+// A reason is mandatory and recorded in the audit log.
+type ForceEpicStatusRequest struct {
+	ID     string `param:"id" json:"-"`
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+func (r ForceEpicStatusRequest) Validate() error {
+	return valgo.In("params", valgo.Is(epic.EpicIDValidator(r.ID, "id"))).
+		Is(valgo.String(r.Status, "status").Passing(func(s string) bool {
+			return epic.ValidStatus(epic.Status(s))
+		}, "Must be a valid epic status")).
+		Is(reasonValidator(r.Reason)).
+		ToError()
+}
+
+func reasonValidator(reason string) *valgo.ValidatorString[string] {
+	return valgo.String(strings.TrimSpace(reason), "reason").Not().Blank().MaxLength(maxReasonLen)
+}
+
+// ListAuditRequest captures the optional entity_id and limit query
+// parameters for the audit log.
+type ListAuditRequest struct {
+	EntityID string `query:"entity_id" json:"-"`
+	Limit    int    `query:"limit" json:"-"`
+}
+
+func (r ListAuditRequest) Validate() error {
+	v := valgo.Is(valgo.Int(r.Limit, "limit").Between(0, maxAuditLimit))
+	return v.ToError()
+}
+
+// ForceTaskStatusResponse is returned after a task's status is forced.
+type ForceTaskStatusResponse struct {
+	Task           *task.Task   `json:"task"`
+	PreviousStatus task.Status  `json:"previous_status"`
+	Audit          *audit.Entry `json:"audit"`
+}
+
+// ForceEpicStatusResponse is returned after an epic's status is forced.
+type ForceEpicStatusResponse struct {
+	Epic           *epic.Epic   `json:"epic"`
+	PreviousStatus epic.Status  `json:"previous_status"`
+	Audit          *audit.Entry `json:"audit"`
+}
+
+// PauseDispatchRequest is the request body for pausing dispatch by hand.
+type PauseDispatchRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (r PauseDispatchRequest) Validate() error {
+	return valgo.Is(reasonValidator(r.Reason)).ToError()
+}
+
+// DispatchPauseResponse reports the state of the dispatch kill switch.
+type DispatchPauseResponse struct {
+	Paused bool             `json:"paused"`
+	Pause  *costguard.Pause `json:"pause,omitempty"`
+	// Acknowledged is the pause an acknowledgement lifted.
+	Acknowledged *costguard.Pause `json:"acknowledged,omitempty"`
+}
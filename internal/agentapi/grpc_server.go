@@ -0,0 +1,334 @@
+package agentapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/cohesivestack/valgo"
+	"github.com/joshjon/kit/errtag"
+	"github.com/joshjon/kit/log"
+	"github.com/joshjon/kit/valgoutil"
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/vervesh/verve/internal/logkey"
+	"github.com/vervesh/verve/internal/redact"
+	"github.com/vervesh/verve/internal/task"
+	"github.com/vervesh/verve/pkg/agentpb"
+	"github.com/vervesh/verve/pkg/verveclient"
+)
+
+// GRPCServer serves the agent plane's AgentService: polls, log streams,
+// heartbeats and completions. It shares the HTTP handler's logic, so a
+// worker sees the same behavior over gRPC as over REST.
+type GRPCServer struct {
+	agentpb.UnimplementedAgentServiceServer
+	h      *HTTPHandler
+	logger log.Logger
+}
+
+// NewGRPCServer creates a GRPCServer backed by h.
+func NewGRPCServer(h *HTTPHandler, logger log.Logger) *GRPCServer {
+	return &GRPCServer{h: h, logger: logger.With("component", "agent_grpc")}
+}
+
+// Register adds the AgentService to srv.
+func (s *GRPCServer) Register(srv *grpc.Server) {
+	agentpb.RegisterAgentServiceServer(srv, s)
+}
+
+// ServerOptions returns the options a grpc.Server serving s needs, which
+// translate handler errors to gRPC statuses like the HTTP server does.
+func (s *GRPCServer) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			res, err := handler(ctx, req)
+			return res, s.status(info.FullMethod, err)
+		}),
+		grpc.ChainStreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			return s.status(info.FullMethod, handler(srv, ss))
+		}),
+	}
+}
+
+// Poll implements AgentService.Poll.
+func (s *GRPCServer) Poll(ctx context.Context, req *agentpb.PollRequest) (*agentpb.PollResponse, error) {
+	if req.Max < 0 {
+		return nil, status.Error(codes.InvalidArgument, "max must not be negative")
+	}
+	if req.WorkerId != "" && s.h.workerRegistry != nil {
+		defer s.h.recordPoll(req.WorkerId, int(req.MaxConcurrent), int(req.ActiveTasks))()
+	}
+	limit := min(max(int(req.Max), 1), maxPollBatch)
+	work, err := s.h.waitForWork(ctx, req.Labels, limit)
+	if err != nil {
+		return nil, err
+	}
+	res := &agentpb.PollResponse{Items: make([]*agentpb.WorkItem, len(work))}
+	for i, w := range work {
+		if res.Items[i], err = workItemProto(w); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// StreamLogs implements AgentService.StreamLogs. Each message is appended as
+// it arrives, once per idempotency key like the REST log callback, so the
+// messages a worker resends after the stream breaks aren't appended twice.
+func (s *GRPCServer) StreamLogs(stream grpc.ClientStreamingServer[agentpb.StreamLogsRequest, agentpb.StreamLogsResponse]) error {
+	const method = agentpb.AgentService_StreamLogs_FullMethodName
+	ctx := stream.Context()
+	var lines int64
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(&agentpb.StreamLogsResponse{Lines: lines})
+		}
+		if err != nil {
+			return s.status(method, err)
+		}
+		req := TaskLogsRequest{ID: msg.TaskId}
+		if err := req.Validate(); err != nil {
+			return s.status(method, err)
+		}
+		if len(msg.IdempotencyKey) > maxIdempotencyKeyLen {
+			return status.Errorf(codes.InvalidArgument, "idempotency_key must not be longer than %d characters", maxIdempotencyKeyLen)
+		}
+		attempt := int(msg.Attempt)
+		if attempt == 0 {
+			attempt = 1
+		}
+		applied, err := s.h.taskStore.AppendTaskLogsOnce(ctx, task.MustParseTaskID(msg.TaskId), attempt, msg.IdempotencyKey, redact.Lines(msg.Lines))
+		if err != nil {
+			return s.status(method, err)
+		}
+		if applied {
+			lines += int64(len(msg.Lines))
+		}
+	}
+}
+
+// Heartbeat implements AgentService.Heartbeat.
+func (s *GRPCServer) Heartbeat(ctx context.Context, req *agentpb.HeartbeatRequest) (*agentpb.HeartbeatResponse, error) {
+	if err := (TaskIDRequest{ID: req.TaskId}).Validate(); err != nil {
+		return nil, err
+	}
+	stopped, nudges, err := s.h.taskHeartbeat(ctx, task.MustParseTaskID(req.TaskId))
+	if err != nil {
+		return nil, err
+	}
+	res := &agentpb.HeartbeatResponse{Stopped: stopped}
+	for _, n := range nudges {
+		res.Nudges = append(res.Nudges, &agentpb.Nudge{
+			Id:        n.ID,
+			Attempt:   int32(n.Attempt),
+			Message:   n.Message,
+			CreatedAt: timestamppb.New(n.CreatedAt),
+		})
+	}
+	return res, nil
+}
+
+// Complete implements AgentService.Complete.
+func (s *GRPCServer) Complete(ctx context.Context, req *agentpb.CompleteRequest) (*agentpb.CompleteResponse, error) {
+	r := TaskCompleteRequest{ID: req.TaskId, TaskCompleteRequest: completeRequestFromProto(req)}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	if len(req.IdempotencyKey) > maxIdempotencyKeyLen {
+		return nil, status.Errorf(codes.InvalidArgument, "idempotency_key must not be longer than %d characters", maxIdempotencyKeyLen)
+	}
+	id := task.MustParseTaskID(req.TaskId)
+	err := s.h.completeTask(ctx, id, r.TaskCompleteRequest, req.IdempotencyKey, func(logKey string, err error) {
+		s.logger.Warn("task completion follow-up failed", logkey.TaskID, id.String(), logKey, err.Error())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &agentpb.CompleteResponse{}, nil
+}
+
+// status converts an error returned by a method to a gRPC status, logging
+// unexpected errors. Validation errors, errtag errors and echo HTTP errors
+// keep the meaning of their HTTP status.
+func (s *GRPCServer) status(method string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	var (
+		echoErr *echo.HTTPError
+		verr    *valgo.Error
+		tagErr  errtag.Tagger
+	)
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.As(err, &echoErr):
+		return status.Error(grpcCode(echoErr.Code), fmt.Sprint(echoErr.Message))
+	case errors.As(err, &verr):
+		return status.Error(codes.InvalidArgument, "validate request: "+strings.Join(valgoutil.GetDetails(verr), "; "))
+	case errors.As(err, &tagErr) && tagErr.Code() != http.StatusInternalServerError:
+		return status.Error(grpcCode(tagErr.Code()), tagErr.Msg())
+	}
+	s.logger.Error("agent gRPC call failed", "grpc.method", method, "error", err)
+	return status.Error(codes.Internal, http.StatusText(http.StatusInternalServerError))
+}
+
+// grpcCode returns the gRPC code closest to an HTTP status.
+func grpcCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	return codes.Internal
+}
+
+// workItemProto converts a claimed work item to its protobuf form.
+func workItemProto(w *PollResponse) (*agentpb.WorkItem, error) {
+	item := &agentpb.WorkItem{
+		Type:                     w.Type,
+		GithubToken:              w.GitHubToken,
+		RepoFullName:             w.RepoFullName,
+		RepoSummary:              w.RepoSummary,
+		RepoExpectations:         w.RepoExpectations,
+		RepoTechStack:            w.RepoTechStack,
+		PrConventions:            w.PRConventions,
+		EpicContext:              w.EpicContext,
+		EpicTranscript:           w.EpicTranscript,
+		WorkspaceCacheGeneration: int64(w.WorkspaceCacheGeneration),
+		AdditionalRepos:          w.AdditionalRepos,
+		ShadowMode:               w.ShadowMode,
+		Proposal:                 w.Proposal,
+		BaseBranch:               w.BaseBranch,
+		MaxRuntimeSeconds:        int64(w.MaxRuntimeSeconds),
+		PermitRequired:           w.PermitRequired,
+	}
+	if t := w.Task; t != nil {
+		item.Task = &agentpb.PollTask{
+			Id:                 t.ID.String(),
+			Number:             int64(t.Number),
+			RepoId:             t.RepoID,
+			Title:              t.Title,
+			Description:        t.Description,
+			Status:             string(t.Status),
+			Attempt:            int32(t.Attempt),
+			MaxAttempts:        int32(t.MaxAttempts),
+			RetryReason:        t.RetryReason,
+			AcceptanceCriteria: t.AcceptanceCriteria,
+			RetryContext:       t.RetryContext,
+			AgentStatus:        t.AgentStatus,
+			CostUsd:            t.CostUSD,
+			MaxCostUsd:         t.MaxCostUSD,
+			SkipPr:             t.SkipPR,
+			DraftPr:            t.DraftPR,
+			PlanOnly:           t.PlanOnly,
+			Model:              t.Model,
+		}
+	}
+	if e := w.Epic; e != nil {
+		proposed, err := json.Marshal(e.ProposedTasks)
+		if err != nil {
+			return nil, err
+		}
+		item.Epic = &agentpb.PollEpic{
+			Id:                e.ID.String(),
+			RepoId:            e.RepoID,
+			Title:             e.Title,
+			Description:       e.Description,
+			PlanningPrompt:    e.PlanningPrompt,
+			Model:             e.Model,
+			Feedback:          e.Feedback,
+			FeedbackType:      e.FeedbackType,
+			ProposedTasksJson: proposed,
+		}
+	}
+	if st := w.Setup; st != nil {
+		item.Setup = &agentpb.Setup{TaskId: st.TaskID, RepoId: st.RepoID, FullName: st.FullName}
+	}
+	if conv := w.Conversation; conv != nil {
+		messages, err := json.Marshal(conv.Messages)
+		if err != nil {
+			return nil, err
+		}
+		item.Conversation = &agentpb.PollConversation{
+			Id:           conv.ID.String(),
+			RepoId:       conv.RepoID,
+			Title:        conv.Title,
+			MessagesJson: messages,
+			Model:        conv.Model,
+		}
+		if conv.PendingMessage != nil {
+			item.Conversation.PendingMessage = *conv.PendingMessage
+		}
+	}
+	for _, a := range w.Attachments {
+		item.Attachments = append(item.Attachments, &agentpb.Attachment{
+			Id:          a.ID,
+			TaskId:      a.TaskID,
+			Filename:    a.Filename,
+			ContentType: a.ContentType,
+			SizeBytes:   a.SizeBytes,
+			UploadedBy:  a.UploadedBy,
+			CreatedAt:   timestamppb.New(a.CreatedAt),
+		})
+	}
+	return item, nil
+}
+
+// completeRequestFromProto converts a reported outcome from its protobuf
+// form.
+func completeRequestFromProto(req *agentpb.CompleteRequest) verveclient.TaskCompleteRequest {
+	out := verveclient.TaskCompleteRequest{
+		Attempt:            int(req.Attempt),
+		Success:            req.Success,
+		PullRequestURL:     req.PullRequestUrl,
+		PRNumber:           int(req.PrNumber),
+		BranchName:         req.BranchName,
+		Error:              req.Error,
+		AgentStatus:        req.AgentStatus,
+		CostUSD:            req.CostUsd,
+		NoChanges:          req.NoChanges,
+		Retryable:          req.Retryable,
+		ShadowDiff:         req.ShadowDiff,
+		MaxRuntimeExceeded: req.MaxRuntimeExceeded,
+		BudgetExceeded:     req.BudgetExceeded,
+	}
+	for _, pr := range req.PullRequests {
+		out.PullRequests = append(out.PullRequests, verveclient.CompletedPullRequest{Repo: pr.Repo, URL: pr.Url, Number: int(pr.Number)})
+	}
+	if p := req.Proposal; p != nil {
+		out.Proposal = &verveclient.Proposal{Summary: p.Summary, Approach: p.Approach, Risks: p.Risks}
+		for _, f := range p.Files {
+			out.Proposal.Files = append(out.Proposal.Files, verveclient.ProposalFile{Path: f.Path, Change: f.Change})
+		}
+	}
+	return out
+}
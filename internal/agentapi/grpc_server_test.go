@@ -0,0 +1,293 @@
+package agentapi_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joshjon/kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/vervesh/verve/internal/agentapi"
+	"github.com/vervesh/verve/internal/task"
+	"github.com/vervesh/verve/pkg/agentpb"
+	"github.com/vervesh/verve/pkg/verveclient"
+)
+
+// serveGRPC serves the fixture's agent handler over gRPC and returns the
+// server's address.
+func serveGRPC(t *testing.T, f *fixture, opts ...grpc.ServerOption) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	svc := agentapi.NewGRPCServer(f.Handler, log.NewLogger(log.WithNop()))
+	srv := grpc.NewServer(append(svc.ServerOptions(), opts...)...)
+	svc.Register(srv)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String()
+}
+
+func newGRPCClient(t *testing.T, f *fixture) *verveclient.AgentGRPCClient {
+	t.Helper()
+	client, err := verveclient.NewAgentGRPCClient(serveGRPC(t, f))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+// selfSignedCert writes a self-signed certificate for 127.0.0.1 to a PEM
+// file and returns it with the file's path.
+func selfSignedCert(t *testing.T) (tls.Certificate, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "verve-test"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "cert.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, path
+}
+
+func TestGRPC_TLS(t *testing.T) {
+	f := newFixture(t)
+	cert, caFile := selfSignedCert(t)
+	addr := serveGRPC(t, f, grpc.Creds(credentials.NewServerTLSFromCert(&cert)))
+
+	creds, err := verveclient.AgentGRPCTLS(caFile)
+	require.NoError(t, err)
+	client, err := verveclient.NewAgentGRPCClient(addr, creds)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	tsk := f.seedRunningTask()
+
+	res, err := client.TaskHeartbeat(context.Background(), tsk.ID.String())
+	require.NoError(t, err)
+	assert.False(t, res.Stopped)
+
+	// A plaintext client can't talk to the TLS server.
+	plain, err := verveclient.NewAgentGRPCClient(addr)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = plain.Close() })
+	_, err = plain.TaskHeartbeat(context.Background(), tsk.ID.String())
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestGRPC_Poll(t *testing.T) {
+	f := newFixture(t)
+	client := newGRPCClient(t, f)
+	ctx := context.Background()
+	require.NoError(t, f.RepoStore.UpdateRepoSetupStatus(ctx, f.Repo.ID, "ready"))
+	for range 3 {
+		tsk := task.NewTask(f.Repo.ID.String(), "title", "desc", nil, []string{"tests pass"}, 0, false, false, "", true)
+		require.NoError(t, f.TaskStore.CreateTask(ctx, tsk))
+	}
+	opts := verveclient.PollOptions{WorkerID: "worker-1", MaxConcurrent: 3}
+
+	work, err := client.PollBatch(ctx, opts, 2)
+	require.NoError(t, err)
+	require.Len(t, work, 2)
+	assert.NotEqual(t, work[0].Task.ID, work[1].Task.ID)
+
+	// Only what is left is handed out.
+	p, err := client.Poll(ctx, opts)
+	require.NoError(t, err)
+	require.NotNil(t, p)
+	assert.Equal(t, verveclient.WorkTypeTask, p.Type)
+	assert.Equal(t, "owner/test-repo", p.RepoFullName)
+	assert.Equal(t, "title", p.Task.Title)
+	assert.Equal(t, []string{"tests pass"}, p.Task.AcceptanceCriteria)
+	assert.Equal(t, string(task.StatusRunning), p.Task.Status)
+	assert.Equal(t, 1, p.Task.Attempt)
+
+	workers := f.WorkerRegistry.ListWorkers(time.Minute)
+	require.Len(t, workers, 1)
+	assert.Equal(t, "worker-1", workers[0].WorkerID)
+}
+
+func TestGRPC_PollInvalidMax(t *testing.T) {
+	f := newFixture(t)
+	conn, err := grpc.NewClient(serveGRPC(t, f), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	_, err = agentpb.NewAgentServiceClient(conn).Poll(context.Background(), &agentpb.PollRequest{Max: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPC_StreamLogs(t *testing.T) {
+	f := newFixture(t)
+	client := newGRPCClient(t, f)
+	tsk := f.seedRunningTask()
+
+	logs := client.OpenTaskLogs(context.Background(), tsk.ID.String(), 1)
+	require.NoError(t, logs.Write([]string{"line 1", "line 2"}))
+	require.NoError(t, logs.Write([]string{"line 3"}))
+	require.NoError(t, logs.Close())
+
+	stored, err := f.taskRepo.ReadTaskLogs(context.Background(), tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"line 1", "line 2", "line 3"}, stored)
+}
+
+func TestGRPC_StreamLogs_ResentMessagesAppendOnce(t *testing.T) {
+	f := newFixture(t)
+	conn, err := grpc.NewClient(serveGRPC(t, f), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	tsk := f.seedRunningTask()
+
+	stream, err := agentpb.NewAgentServiceClient(conn).StreamLogs(context.Background())
+	require.NoError(t, err)
+	msg := &agentpb.StreamLogsRequest{TaskId: tsk.ID.String(), Attempt: 1, Lines: []string{"line 1"}, IdempotencyKey: "batch-1"}
+	require.NoError(t, stream.Send(msg))
+	require.NoError(t, stream.Send(msg))
+	res, err := stream.CloseAndRecv()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), res.Lines)
+
+	stored, err := f.taskRepo.ReadTaskLogs(context.Background(), tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"line 1"}, stored)
+}
+
+func TestGRPC_StreamLogs_ResendsAfterServerFailure(t *testing.T) {
+	f := newFixture(t)
+	// The first stream fails after storing its first message, dropping
+	// anything sent after it.
+	var streams atomic.Int32
+	failFirst := grpc.ChainStreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if streams.Add(1) == 1 {
+			ss = &failingStream{ServerStream: ss, after: 1}
+		}
+		return handler(srv, ss)
+	})
+	client, err := verveclient.NewAgentGRPCClient(serveGRPC(t, f, failFirst))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	tsk := f.seedRunningTask()
+
+	logs := client.OpenTaskLogs(context.Background(), tsk.ID.String(), 1)
+	for _, line := range []string{"line 1", "line 2", "line 3"} {
+		require.NoError(t, logs.Write([]string{line}))
+	}
+	require.NoError(t, logs.Close())
+
+	stored, err := f.taskRepo.ReadTaskLogs(context.Background(), tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"line 1", "line 2", "line 3"}, stored)
+}
+
+// failingStream is a server stream that breaks once after messages have
+// been received.
+type failingStream struct {
+	grpc.ServerStream
+	after int
+}
+
+func (s *failingStream) RecvMsg(m any) error {
+	if s.after == 0 {
+		return status.Error(codes.Unavailable, "stream broke")
+	}
+	s.after--
+	return s.ServerStream.RecvMsg(m)
+}
+
+func TestGRPC_StreamLogs_InvalidTaskID(t *testing.T) {
+	f := newFixture(t)
+	client := newGRPCClient(t, f)
+
+	logs := client.OpenTaskLogs(context.Background(), "not-a-task", 1)
+	// The server's status arrives with whichever call notices the stream
+	// ended.
+	err := logs.Write([]string{"line 1"})
+	if err == nil {
+		err = logs.Close()
+	}
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPC_Heartbeat_DeliversNudges(t *testing.T) {
+	f := newFixture(t)
+	client := newGRPCClient(t, f)
+	tsk := f.seedRunningTask()
+	ctx := context.Background()
+
+	_, err := f.TaskStore.NudgeTask(ctx, tsk.ID, "Skip the migration for now")
+	require.NoError(t, err)
+
+	res, err := client.TaskHeartbeat(ctx, tsk.ID.String())
+	require.NoError(t, err)
+	assert.False(t, res.Stopped)
+	require.Len(t, res.Nudges, 1)
+	assert.Equal(t, "Skip the migration for now", res.Nudges[0].Message)
+	assert.False(t, res.Nudges[0].CreatedAt.IsZero())
+
+	// Each nudge is delivered once.
+	res, err = client.TaskHeartbeat(ctx, tsk.ID.String())
+	require.NoError(t, err)
+	assert.Empty(t, res.Nudges)
+
+	require.NoError(t, f.TaskStore.StopTask(ctx, tsk.ID, "stopped"))
+	res, err = client.TaskHeartbeat(ctx, tsk.ID.String())
+	require.NoError(t, err)
+	assert.True(t, res.Stopped)
+}
+
+func TestGRPC_Complete(t *testing.T) {
+	f := newFixture(t)
+	client := newGRPCClient(t, f)
+	tsk := f.seedRunningTask()
+	ctx := context.Background()
+
+	req := verveclient.TaskCompleteRequest{
+		Attempt:        1,
+		Success:        true,
+		PullRequestURL: "https://github.com/owner/repo/pull/42",
+		PRNumber:       42,
+		CostUSD:        1.5,
+	}
+	require.NoError(t, client.CompleteTask(ctx, tsk.ID.String(), req))
+	// A resent completion is acknowledged without being applied again.
+	require.NoError(t, client.CompleteTask(ctx, tsk.ID.String(), req))
+
+	stored, err := f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusReview, stored.Status)
+	assert.Equal(t, "https://github.com/owner/repo/pull/42", stored.PullRequestURL)
+	assert.Equal(t, 42, stored.PRNumber)
+	assert.InDelta(t, 1.5, stored.CostUSD, 0.001)
+
+	// An attempt the task has moved past.
+	err = client.CompleteTask(ctx, tsk.ID.String(), verveclient.TaskCompleteRequest{Attempt: 2, Success: true})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
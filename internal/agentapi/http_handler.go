@@ -1,6 +1,11 @@
 package agentapi
 
 import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
@@ -11,12 +16,20 @@ import (
 
 	"github.com/vervesh/verve/internal/conversation"
 	"github.com/vervesh/verve/internal/epic"
+	"github.com/vervesh/verve/internal/github"
 	"github.com/vervesh/verve/internal/githubtoken"
 	"github.com/vervesh/verve/internal/logkey"
+	"github.com/vervesh/verve/internal/msgcat"
+	"github.com/vervesh/verve/internal/pathguard"
+	"github.com/vervesh/verve/internal/permit"
+	"github.com/vervesh/verve/internal/prlint"
+	"github.com/vervesh/verve/internal/provenance"
 	"github.com/vervesh/verve/internal/redact"
 	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/selfreview"
 	"github.com/vervesh/verve/internal/task"
 	"github.com/vervesh/verve/internal/workertracker"
+	"github.com/vervesh/verve/pkg/verveclient"
 )
 
 // HTTPHandler handles agent-facing API requests.
@@ -27,11 +40,67 @@ type HTTPHandler struct {
 	conversationStore *conversation.Store
 	githubToken       *githubtoken.Service
 	workerRegistry    *workertracker.Registry
+	provenanceScanner *provenance.Scanner
+	selfReviewer      *selfreview.Reviewer
+	dispatchGates     []DispatchGate
+	permits           *permit.Limiter
+}
+
+// DispatchGate reports whether dispatching new work to agents is paused.
+type DispatchGate interface {
+	DispatchPaused(ctx context.Context) (bool, error)
+}
+
+// DispatchGateFunc adapts a func to a DispatchGate.
+type DispatchGateFunc func(ctx context.Context) (bool, error)
+
+// DispatchPaused calls f.
+func (f DispatchGateFunc) DispatchPaused(ctx context.Context) (bool, error) {
+	return f(ctx)
+}
+
+// Option configures an HTTPHandler.
+type Option func(h *HTTPHandler)
+
+// WithProvenanceScanner scans the diff of each PR an agent opens or updates
+// for license headers and large verbatim blocks. Flagged PRs get a failing
+// commit status until a human acknowledges the findings.
+func WithProvenanceScanner(scanner *provenance.Scanner) Option {
+	return func(h *HTTPHandler) {
+		h.provenanceScanner = scanner
+	}
+}
+
+// WithSelfReviewer reviews each PR an agent opens or updates against the
+// task's acceptance criteria and the reviewer's checks, posting the report as
+// a PR comment and recording its score on the task.
+func WithSelfReviewer(reviewer *selfreview.Reviewer) Option {
+	return func(h *HTTPHandler) {
+		h.selfReviewer = reviewer
+	}
+}
+
+// WithDispatchGate holds polls without handing out work while the gate
+// reports dispatch as paused. Work that is already running is unaffected.
+// When given more than once, dispatch is paused while any gate is.
+func WithDispatchGate(gate DispatchGate) Option {
+	return func(h *HTTPHandler) {
+		h.dispatchGates = append(h.dispatchGates, gate)
+	}
+}
+
+// WithPermitLimiter makes workers hold a permit from the limiter while a
+// task's agent runs, keeping agents across all workers within its global
+// and per-model limits.
+func WithPermitLimiter(limiter *permit.Limiter) Option {
+	return func(h *HTTPHandler) {
+		h.permits = limiter
+	}
 }
 
 // NewHTTPHandler creates a new HTTPHandler.
-func NewHTTPHandler(taskStore *task.Store, epicStore *epic.Store, repoStore *repo.Store, conversationStore *conversation.Store, githubToken *githubtoken.Service, workerRegistry *workertracker.Registry) *HTTPHandler {
-	return &HTTPHandler{
+func NewHTTPHandler(taskStore *task.Store, epicStore *epic.Store, repoStore *repo.Store, conversationStore *conversation.Store, githubToken *githubtoken.Service, workerRegistry *workertracker.Registry, opts ...Option) *HTTPHandler {
+	h := &HTTPHandler{
 		taskStore:         taskStore,
 		epicStore:         epicStore,
 		repoStore:         repoStore,
@@ -39,6 +108,10 @@ func NewHTTPHandler(taskStore *task.Store, epicStore *epic.Store, repoStore *rep
 		githubToken:       githubToken,
 		workerRegistry:    workerRegistry,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // Register adds the agent endpoints to the provided Echo router group.
@@ -48,16 +121,28 @@ func (h *HTTPHandler) Register(g *echo.Group) {
 
 	// Worker observability
 	g.GET("/workers", h.ListWorkers)
+	g.POST("/workers", h.RegisterWorker)
+	g.POST("/workers/:worker_id/heartbeat", h.WorkerHeartbeat)
+	g.POST("/drain", h.Drain)
+
+	// Agent permits
+	g.POST("/permits", h.AcquirePermit)
+	g.PUT("/permits/:permit_id", h.RenewPermit)
+	g.DELETE("/permits/:permit_id", h.ReleasePermit)
 
 	// Task agent endpoints
 	g.POST("/tasks/:id/logs", h.TaskAppendLogs)
+	g.POST("/tasks/:id/events", h.TaskAppendEvents)
 	g.POST("/tasks/:id/heartbeat", h.TaskHeartbeat)
 	g.POST("/tasks/:id/complete", h.TaskComplete)
+	g.GET("/tasks/:id/attachments/:attachment_id", h.TaskAttachment)
+	g.POST("/tasks/:id/artifacts", h.TaskUploadArtifact)
 
 	// Epic agent endpoints
 	g.POST("/epics/:id/complete", h.EpicComplete)
 	g.POST("/epics/:id/heartbeat", h.EpicHeartbeat)
 	g.POST("/epics/:id/logs", h.EpicAppendLogs)
+	g.POST("/epics/:id/cost", h.EpicCost)
 
 	// Conversation agent endpoints
 	g.POST("/conversations/:id/complete", h.ConversationComplete)
@@ -69,77 +154,97 @@ func (h *HTTPHandler) Register(g *echo.Group) {
 	g.POST("/repos/:repo_id/setup-heartbeat", h.RepoSetupHeartbeat)
 }
 
+// maxPollBatch caps how many work items one batch poll claims.
+const maxPollBatch = 32
+
+// pollTimeout is how long a poll waits for work before returning none.
+const pollTimeout = 30 * time.Second
+
 // Poll handles GET /poll — unified long-poll for available work.
-// When called with ?accept=stop, it long-polls for stop signals only.
+// When called with ?accept=stop, it long-polls for stop signals only. The
+// comma-separated labels parameter lists the worker's labels; tasks that
+// require labels the worker lacks are left for other workers. With ?max=N the
+// poll claims up to N work items at once and responds with a list of them,
+// so a worker with several free slots fills them in one round trip.
 func (h *HTTPHandler) Poll(c echo.Context) error {
 	if c.QueryParam("accept") == "stop" {
 		return h.pollForStops(c)
 	}
 
-	timeout := 30 * time.Second
-	deadline := time.Now().Add(timeout)
 	ctx := c.Request().Context()
 
 	workerID := c.QueryParam("worker_id")
 	if workerID != "" && h.workerRegistry != nil {
 		maxConcurrent, _ := strconv.Atoi(c.QueryParam("max_concurrent"))
 		activeTasks, _ := strconv.Atoi(c.QueryParam("active_tasks"))
-		if maxConcurrent <= 0 {
-			maxConcurrent = 1
+		defer h.recordPoll(workerID, maxConcurrent, activeTasks)()
+	}
+
+	var labels []string
+	if s := c.QueryParam("labels"); s != "" {
+		labels = strings.Split(s, ",")
+	}
+
+	batch := c.QueryParam("max") != ""
+	limit := 1
+	if batch {
+		n, err := strconv.Atoi(c.QueryParam("max"))
+		if err != nil || n < 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, "max must be a positive number")
 		}
-		h.workerRegistry.RecordPollStart(workerID, maxConcurrent, activeTasks)
-		defer h.workerRegistry.RecordPollEnd(workerID)
+		limit = min(n, maxPollBatch)
+	}
+
+	work, err := h.waitForWork(ctx, labels, limit)
+	if err != nil {
+		return err
+	}
+	if len(work) == 0 {
+		return c.NoContent(http.StatusNoContent)
+	}
+	if batch {
+		return server.SetResponseList(c, http.StatusOK, work, "")
+	}
+	return server.SetResponse(c, http.StatusOK, work[0])
+}
+
+// recordPoll tracks a polling worker's capacity for the duration of the poll.
+// The returned function records the end of the poll.
+func (h *HTTPHandler) recordPoll(workerID string, maxConcurrent, activeTasks int) (end func()) {
+	if maxConcurrent <= 0 {
+		maxConcurrent = 1
 	}
+	h.workerRegistry.RecordPollStart(workerID, maxConcurrent, activeTasks)
+	return func() { h.workerRegistry.RecordPollEnd(workerID) }
+}
 
+// waitForWork claims up to limit work items, waiting up to the poll timeout
+// for work to become available. It returns no work when the timeout passes,
+// ctx is done or dispatch is paused, in which case it holds the poll for the
+// full timeout so workers don't spin.
+func (h *HTTPHandler) waitForWork(ctx context.Context, labels []string, limit int) ([]*PollResponse, error) {
+	deadline := time.Now().Add(pollTimeout)
 	for {
-		e, err := h.epicStore.ClaimPendingEpic(ctx)
+		paused, err := h.dispatchPaused(ctx)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		if e != nil {
-			resp, err := h.buildEpicPollResponse(c, e)
-			if err != nil {
-				return err
-			}
-			return server.SetResponse(c, http.StatusOK, resp)
-		}
-
-		if h.conversationStore != nil {
-			conv, err := h.conversationStore.ClaimPendingConversation(ctx)
-			if err != nil {
-				return err
-			}
-			if conv != nil {
-				resp, err := h.buildConversationPollResponse(c, conv)
-				if err != nil {
-					return err
-				}
-				return server.SetResponse(c, http.StatusOK, resp)
+		if paused {
+			select {
+			case <-time.After(time.Until(deadline)):
+			case <-ctx.Done():
 			}
+			return nil, nil
 		}
 
-		t, err := h.taskStore.ClaimPendingTask(ctx, nil)
-		if err != nil {
-			return err
-		}
-		if t != nil {
-			if t.Type == task.TaskTypeSetup || t.Type == task.TaskTypeSetupReview {
-				resp, err := h.buildSetupPollResponse(c, t)
-				if err != nil {
-					return err
-				}
-				return server.SetResponse(c, http.StatusOK, resp)
-			}
-			resp, err := h.buildTaskPollResponse(c, t)
-			if err != nil {
-				return err
-			}
-			return server.SetResponse(c, http.StatusOK, resp)
+		work, err := h.claimWork(ctx, labels, limit)
+		if err != nil || len(work) > 0 {
+			return work, err
 		}
 
 		remaining := time.Until(deadline)
 		if remaining <= 0 {
-			return c.NoContent(http.StatusNoContent)
+			return nil, nil
 		}
 
 		var convPending <-chan struct{}
@@ -152,25 +257,126 @@ func (h *HTTPHandler) Poll(c echo.Context) error {
 		case <-convPending:
 		case <-h.taskStore.WaitForPending():
 		case <-time.After(remaining):
-			return c.NoContent(http.StatusNoContent)
+			return nil, nil
 		case <-ctx.Done():
-			return c.NoContent(http.StatusNoContent)
+			return nil, nil
+		}
+	}
+}
+
+// claimWork claims up to limit work items: epics first, then conversations,
+// then tasks, which are claimed together in one transaction. When the
+// response for a claimed item can't be built, every item the call claimed is
+// released, so none is left claimed without a worker to run it.
+func (h *HTTPHandler) claimWork(ctx context.Context, labels []string, limit int) (work []*PollResponse, err error) {
+	var releases []func(ctx context.Context) error
+	defer func() {
+		if err == nil {
+			return
 		}
+		// The poll's context may be what failed; release regardless.
+		ctx := context.WithoutCancel(ctx)
+		for _, release := range releases {
+			err = errors.Join(err, release(ctx))
+		}
+		work = nil
+	}()
+
+	for len(work) < limit {
+		e, err := h.epicStore.ClaimPendingEpic(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if e == nil {
+			break
+		}
+		releases = append(releases, func(ctx context.Context) error {
+			return h.epicStore.ReleaseEpicClaim(ctx, e.ID)
+		})
+		resp, err := h.buildEpicPollResponse(ctx, e)
+		if err != nil {
+			return nil, err
+		}
+		work = append(work, resp)
+	}
+
+	for h.conversationStore != nil && len(work) < limit {
+		conv, err := h.conversationStore.ClaimPendingConversation(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if conv == nil {
+			break
+		}
+		releases = append(releases, func(ctx context.Context) error {
+			return h.conversationStore.ReleaseConversationClaim(ctx, conv.ID)
+		})
+		resp, err := h.buildConversationPollResponse(ctx, conv)
+		if err != nil {
+			return nil, err
+		}
+		work = append(work, resp)
+	}
+
+	if len(work) == limit {
+		return work, nil
+	}
+	tasks, err := h.taskStore.ClaimPendingTasks(ctx, nil, labels, limit-len(work))
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tasks {
+		releases = append(releases, func(ctx context.Context) error {
+			_, err := h.taskStore.AbandonTask(ctx, t.ID)
+			return err
+		})
+	}
+	for _, t := range tasks {
+		var resp *PollResponse
+		if t.Type == task.TaskTypeSetup || t.Type == task.TaskTypeSetupReview {
+			resp, err = h.buildSetupPollResponse(ctx, t)
+		} else {
+			resp, err = h.buildTaskPollResponse(ctx, t)
+		}
+		if err != nil {
+			return nil, err
+		}
+		work = append(work, resp)
+	}
+	return work, nil
+}
+
+// dispatchPaused reports whether any dispatch gate is paused.
+func (h *HTTPHandler) dispatchPaused(ctx context.Context) (bool, error) {
+	for _, gate := range h.dispatchGates {
+		paused, err := gate.DispatchPaused(ctx)
+		if err != nil || paused {
+			return paused, err
+		}
+	}
+	return false, nil
+}
+
+// repoToken returns the GitHub token agents working on the repo receive.
+func (h *HTTPHandler) repoToken(ctx context.Context, repoFullName string) (string, error) {
+	if h.githubToken == nil {
+		return "", nil
 	}
+	return h.githubToken.TokenFor(ctx, repoFullName)
 }
 
-func (h *HTTPHandler) buildEpicPollResponse(c echo.Context, e *epic.Epic) (*PollResponse, error) {
+func (h *HTTPHandler) buildEpicPollResponse(ctx context.Context, e *epic.Epic) (*PollResponse, error) {
 	repoID, err := repo.ParseRepoID(e.RepoID)
 	if err != nil {
 		return nil, err
 	}
-	r, err := h.repoStore.ReadRepo(c.Request().Context(), repoID)
+	r, err := h.repoStore.ReadRepo(ctx, repoID)
 	if err != nil {
 		return nil, err
 	}
-	var token string
-	if h.githubToken != nil {
-		token = h.githubToken.GetToken()
+	token, err := h.repoToken(ctx, r.FullName)
+	if err != nil {
+		return nil, err
 	}
 	return &PollResponse{
 		Type:             "epic",
@@ -180,21 +386,22 @@ func (h *HTTPHandler) buildEpicPollResponse(c echo.Context, e *epic.Epic) (*Poll
 		RepoSummary:      r.Summary,
 		RepoExpectations: r.Expectations,
 		RepoTechStack:    strings.Join(r.TechStack, ", "),
+		EpicTranscript:   epic.AgentTranscript(e.Transcript),
 	}, nil
 }
 
-func (h *HTTPHandler) buildSetupPollResponse(c echo.Context, t *task.Task) (*PollResponse, error) {
+func (h *HTTPHandler) buildSetupPollResponse(ctx context.Context, t *task.Task) (*PollResponse, error) {
 	repoID, err := repo.ParseRepoID(t.RepoID)
 	if err != nil {
 		return nil, err
 	}
-	r, err := h.repoStore.ReadRepo(c.Request().Context(), repoID)
+	r, err := h.repoStore.ReadRepo(ctx, repoID)
 	if err != nil {
 		return nil, err
 	}
-	var token string
-	if h.githubToken != nil {
-		token = h.githubToken.GetToken()
+	token, err := h.repoToken(ctx, r.FullName)
+	if err != nil {
+		return nil, err
 	}
 
 	workType := "setup"
@@ -217,34 +424,122 @@ func (h *HTTPHandler) buildSetupPollResponse(c echo.Context, t *task.Task) (*Pol
 	}, nil
 }
 
-func (h *HTTPHandler) buildTaskPollResponse(c echo.Context, t *task.Task) (*PollResponse, error) {
+func (h *HTTPHandler) buildTaskPollResponse(ctx context.Context, t *task.Task) (*PollResponse, error) {
 	repoID, err := repo.ParseRepoID(t.RepoID)
 	if err != nil {
 		return nil, err
 	}
-	r, err := h.repoStore.ReadRepo(c.Request().Context(), repoID)
+	r, err := h.repoStore.ReadRepo(ctx, repoID)
+	if err != nil {
+		return nil, err
+	}
+	additional := make([]string, 0, len(t.AdditionalRepoIDs))
+	for _, id := range t.AdditionalRepoIDs {
+		ar, err := h.readRepo(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		additional = append(additional, ar.FullName)
+	}
+	token, err := h.repoToken(ctx, r.FullName)
+	if err != nil {
+		return nil, err
+	}
+	attachments, err := h.taskStore.ListAttachments(ctx, t.ID)
 	if err != nil {
 		return nil, err
 	}
-	var token string
-	if h.githubToken != nil {
-		token = h.githubToken.GetToken()
+	var conventions string
+	if r.CompletionValidations != nil {
+		conventions = r.CompletionValidations.Guidelines(t.ID.String())
 	}
+	var epicContext, baseBranch string
+	if e := h.taskEpic(ctx, t); e != nil {
+		epicContext = epic.SummaryExcerpt(e.PlanningSummary, t.Title, t.Description)
+		baseBranch = e.BranchName()
+	}
+	// A plan-only task revises its previous proposal; once approved, the
+	// proposal is the plan the implementation run follows.
+	var proposalText string
+	proposal, err := h.taskStore.ReadProposal(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	if proposal != nil && (t.PlanOnly || proposal.ApprovedAt != nil) {
+		proposalText = proposal.Format()
+	}
+	// A stacked task works from its parent's branch while the parent's PR is
+	// open. Once the parent is merged it works from where the parent merged.
+	if parent := h.stackParent(ctx, t); parent != nil && parent.Stackable() {
+		baseBranch = parent.BranchName
+	}
+	// A task that fell back after a rate limit runs with its fallback model.
+	pollTask := *t
+	pollTask.Model = t.RunModel()
 	return &PollResponse{
-		Type:             "task",
-		Task:             t,
-		GitHubToken:      token,
-		RepoFullName:     r.FullName,
-		RepoSummary:      r.Summary,
-		RepoExpectations: r.Expectations,
-		RepoTechStack:    strings.Join(r.TechStack, ", "),
+		Type:                     "task",
+		Task:                     &pollTask,
+		GitHubToken:              token,
+		RepoFullName:             r.FullName,
+		RepoSummary:              r.Summary,
+		RepoExpectations:         r.Expectations,
+		RepoTechStack:            strings.Join(r.TechStack, ", "),
+		PRConventions:            conventions,
+		EpicContext:              epicContext,
+		WorkspaceCacheGeneration: r.WorkspaceCacheGeneration,
+		AdditionalRepos:          additional,
+		ShadowMode:               r.ShadowMode,
+		Proposal:                 proposalText,
+		BaseBranch:               baseBranch,
+		MaxRuntimeSeconds:        cmp.Or(t.MaxRuntimeSeconds, r.MaxRuntimeSeconds),
+		Attachments:              attachments,
+		PermitRequired:           h.permits != nil,
 	}, nil
 }
 
+// readRepo reads the repo with the given ID string.
+func (h *HTTPHandler) readRepo(ctx context.Context, id string) (*repo.Repo, error) {
+	repoID, err := repo.ParseRepoID(id)
+	if err != nil {
+		return nil, err
+	}
+	return h.repoStore.ReadRepo(ctx, repoID)
+}
+
+// taskEpic returns the epic the task belongs to, or nil if the task is not
+// part of an epic or the epic cannot be read.
+func (h *HTTPHandler) taskEpic(ctx context.Context, t *task.Task) *epic.Epic {
+	if t.EpicID == "" {
+		return nil
+	}
+	epicID, err := epic.ParseEpicID(t.EpicID)
+	if err != nil {
+		return nil
+	}
+	e, err := h.epicStore.ReadEpic(ctx, epicID)
+	if err != nil {
+		return nil
+	}
+	return e
+}
+
+// stackParent returns the dependency the task is stacked on, or nil if the
+// task isn't stacked or the parent cannot be read.
+func (h *HTTPHandler) stackParent(ctx context.Context, t *task.Task) *task.Task {
+	parentID, err := task.ParseTaskID(t.StackParent())
+	if err != nil {
+		return nil
+	}
+	parent, err := h.taskStore.ReadTask(ctx, parentID)
+	if err != nil {
+		return nil
+	}
+	return parent
+}
+
 // pollForStops long-polls for stop signals from both task and epic stores.
 func (h *HTTPHandler) pollForStops(c echo.Context) error {
-	timeout := 30 * time.Second
-	deadline := time.Now().Add(timeout)
+	deadline := time.Now().Add(pollTimeout)
 	ctx := c.Request().Context()
 
 	for {
@@ -295,12 +590,132 @@ func (h *HTTPHandler) TaskAppendLogs(c echo.Context) error {
 	if attempt == 0 {
 		attempt = 1
 	}
-	if err := h.taskStore.AppendTaskLogs(c.Request().Context(), id, attempt, redact.Lines(req.Logs)); err != nil {
+	key, err := idempotencyKey(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.taskStore.AppendTaskLogsOnce(c.Request().Context(), id, attempt, key, redact.Lines(req.Logs)); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// TaskAppendEvents handles POST /tasks/:id/events. The worker reports the
+// structured events the agent emitted, in order.
+func (h *HTTPHandler) TaskAppendEvents(c echo.Context) error {
+	req, err := server.BindRequest[TaskEventsRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	attempt := req.Attempt
+	if attempt == 0 {
+		attempt = 1
+	}
+	if len(req.Events) == 0 {
+		return c.NoContent(http.StatusNoContent)
+	}
+	events := make([]*task.AgentEvent, len(req.Events))
+	for i, e := range req.Events {
+		events[i] = &task.AgentEvent{Type: task.AgentEventType(e.Type)}
+		if !isEmptyPayload(e.Payload) {
+			events[i].Payload = redact.JSON(e.Payload)
+		}
+	}
+	if err := h.taskStore.AppendAgentEvents(c.Request().Context(), id, attempt, events); err != nil {
 		return err
 	}
 	return c.NoContent(http.StatusNoContent)
 }
 
+// TaskAttachment handles GET /tasks/:id/attachments/:attachment_id
+// Workers download a claimed task's attachments into the agent's workspace.
+func (h *HTTPHandler) TaskAttachment(c echo.Context) error {
+	req, err := server.BindRequest[TaskAttachmentRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	attachmentID, _ := strconv.ParseInt(req.AttachmentID, 10, 64) // safe after validation
+
+	attachment, contents, err := h.taskStore.OpenAttachment(c.Request().Context(), id, attachmentID)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = contents.Close() }()
+	return c.Stream(http.StatusOK, attachment.ContentType, contents)
+}
+
+// multipartOverhead is the room left for multipart headers, boundaries and
+// form fields on top of the artifact size limit when capping upload request
+// bodies.
+const multipartOverhead = 64 << 10
+
+// TaskUploadArtifact handles POST /tasks/:id/artifacts
+// Workers upload the files an agent left in its artifacts directory as
+// multipart forms with the file, the attempt that produced it and an
+// optional name relative to the artifacts directory.
+func (h *HTTPHandler) TaskUploadArtifact(c echo.Context) error {
+	// The body is multipart, so only the path is validated up front.
+	req := TaskIDRequest{ID: c.Param("id")}
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	tooLarge := echo.NewHTTPError(http.StatusRequestEntityTooLarge, msgcat.Text(msgcat.ErrArtifactTooLarge, task.MaxArtifactSize))
+
+	r := c.Request()
+	r.Body = http.MaxBytesReader(c.Response(), r.Body, task.MaxArtifactSize+multipartOverhead)
+	fh, err := c.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return tooLarge
+		}
+		return echo.NewHTTPError(http.StatusBadRequest, "file is required")
+	}
+	if fh.Size > task.MaxArtifactSize {
+		return tooLarge
+	}
+	attempt, err := strconv.Atoi(c.FormValue("attempt"))
+	if err != nil || attempt <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "attempt must be a positive integer")
+	}
+	name, ok := task.CleanArtifactName(cmp.Or(c.FormValue("name"), fh.Filename))
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, msgcat.Text(msgcat.ErrArtifactName))
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	data, err := io.ReadAll(io.LimitReader(f, task.MaxArtifactSize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > task.MaxArtifactSize {
+		return tooLarge
+	}
+
+	artifact := &task.Artifact{Attempt: attempt, Name: name}
+	created, err := h.taskStore.CreateArtifact(r.Context(), id, artifact, data)
+	if errors.Is(err, task.ErrAttachmentsNotConfigured) {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, msgcat.Text(msgcat.ErrAttachmentsNotConfigured))
+	}
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusCreated, created)
+}
+
 // TaskHeartbeat handles POST /tasks/:id/heartbeat.
 // Returns immediately with the task's stop status from the database.
 // Stop signals are delivered primarily via the poll-based stop channel;
@@ -313,17 +728,39 @@ func (h *HTTPHandler) TaskHeartbeat(c echo.Context) error {
 	id := task.MustParseTaskID(req.ID)
 	c.Set(logkey.TaskID, id.String())
 
-	stillRunning, err := h.taskStore.Heartbeat(c.Request().Context(), id)
+	stopped, nudges, err := h.taskHeartbeat(c.Request().Context(), id)
 	if err != nil {
 		return err
 	}
-	return server.SetResponse(c, http.StatusOK, map[string]interface{}{
+	res := map[string]interface{}{
 		"status":  "ok",
-		"stopped": !stillRunning,
-	})
+		"stopped": stopped,
+	}
+	if len(nudges) > 0 {
+		res["nudges"] = nudges
+	}
+	return server.SetResponse(c, http.StatusOK, res)
 }
 
-// TaskComplete handles POST /tasks/:id/complete
+// taskHeartbeat records a heartbeat for a running task. While the task is
+// still running it hands over the pending user nudges for the worker to
+// deliver into the agent container.
+func (h *HTTPHandler) taskHeartbeat(ctx context.Context, id task.TaskID) (stopped bool, nudges []*task.Nudge, err error) {
+	stillRunning, err := h.taskStore.Heartbeat(ctx, id)
+	if err != nil || !stillRunning {
+		return !stillRunning, nil, err
+	}
+	nudges, err = h.taskStore.DeliverNudges(ctx, id)
+	if err != nil {
+		return false, nil, err
+	}
+	return false, nudges, nil
+}
+
+// TaskComplete handles POST /tasks/:id/complete. The outcome is recorded in
+// one transaction, and a completion resent with the same idempotency key is
+// acknowledged without being applied again. Calls to GitHub for a new PR
+// follow once the outcome is recorded.
 func (h *HTTPHandler) TaskComplete(c echo.Context) error {
 	req, err := server.BindRequest[TaskCompleteRequest](c)
 	if err != nil {
@@ -332,71 +769,440 @@ func (h *HTTPHandler) TaskComplete(c echo.Context) error {
 	id := task.MustParseTaskID(req.ID)
 	c.Set(logkey.TaskID, id.String())
 
-	ctx := c.Request().Context()
+	key, err := idempotencyKey(c)
+	if err != nil {
+		return err
+	}
+	err = h.completeTask(c.Request().Context(), id, req.TaskCompleteRequest, key, func(logKey string, err error) {
+		c.Set(logKey, err.Error())
+	})
+	if err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
 
-	if req.AgentStatus != "" {
-		if err := h.taskStore.SetAgentStatus(ctx, id, req.AgentStatus); err != nil {
+// completeTask records a task's reported outcome. When the outcome opens or
+// updates a pull request, the follow-up checks run once it is recorded;
+// their errors don't fail the completion and are passed to report with the
+// log key they are logged under.
+func (h *HTTPHandler) completeTask(ctx context.Context, id task.TaskID, req verveclient.TaskCompleteRequest, key string, report func(logKey string, err error)) error {
+	p := completionParams(req)
+	p.IdempotencyKey = key
+	// Matched to repos up front since the repo store can't see the
+	// transaction.
+	if len(req.PullRequests) > 0 && p.Success && !p.MaxRuntimeExceeded && !p.BudgetExceeded {
+		prs, err := h.additionalPullRequests(ctx, id, req.PullRequests)
+		if err != nil {
 			return err
 		}
+		p.PullRequests = prs
 	}
-	if req.CostUSD > 0 {
-		if err := h.taskStore.AddCost(ctx, id, req.CostUSD); err != nil {
-			return err
+
+	applied, err := h.taskStore.CompleteTask(ctx, id, p)
+	if err != nil || !applied || !p.OpenedPullRequest() {
+		return err
+	}
+
+	if req.PullRequestURL != "" {
+		// The PR is recorded either way; a failed dispatch only means its
+		// checks don't wait for the repo's CI workflow.
+		if err := h.dispatchCIWorkflow(ctx, id); err != nil {
+			report(logkey.CIDispatchError, err)
+		}
+		if err := h.scanProvenance(ctx, id); err != nil {
+			report(logkey.ProvenanceScanError, err)
+		}
+		if err := h.selfReview(ctx, id); err != nil {
+			report(logkey.SelfReviewError, err)
+		}
+	}
+	if err := h.checkProtectedPaths(ctx, id); err != nil {
+		report(logkey.ProtectedPathCheckError, err)
+	}
+	if err := h.validateCompletion(ctx, id); err != nil {
+		report(logkey.CompletionValidationError, err)
+	}
+	return nil
+}
+
+// completionParams converts a reported outcome to its task form, leaving the
+// additional repos' PRs to be matched to their repos.
+func completionParams(req verveclient.TaskCompleteRequest) task.CompletionParams {
+	p := task.CompletionParams{
+		Attempt:            req.Attempt,
+		Success:            req.Success,
+		Error:              req.Error,
+		Retryable:          req.Retryable,
+		AgentStatus:        req.AgentStatus,
+		CostUSD:            req.CostUSD,
+		NoChanges:          req.NoChanges,
+		PullRequestURL:     req.PullRequestURL,
+		PRNumber:           req.PRNumber,
+		ShadowDiff:         req.ShadowDiff,
+		BranchName:         req.BranchName,
+		MaxRuntimeExceeded: req.MaxRuntimeExceeded,
+		BudgetExceeded:     req.BudgetExceeded,
+	}
+	if req.Proposal != nil {
+		p.Proposal = &task.Proposal{
+			Summary:  req.Proposal.Summary,
+			Approach: req.Proposal.Approach,
+			Files:    proposalFiles(req.Proposal.Files),
+			Risks:    req.Proposal.Risks,
+		}
+	}
+	return p
+}
+
+// idempotencyKey returns the key the worker sent to have a callback applied
+// at most once, or "" if it sent none.
+func idempotencyKey(c echo.Context) (string, error) {
+	key := c.Request().Header.Get(verveclient.IdempotencyKeyHeader)
+	if len(key) > maxIdempotencyKeyLen {
+		return "", echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%s must not be longer than %d characters", verveclient.IdempotencyKeyHeader, maxIdempotencyKeyLen))
+	}
+	return key, nil
+}
+
+// proposalFiles converts the files of a reported proposal to their task form.
+func proposalFiles(files []verveclient.ProposalFile) []task.ProposalFile {
+	out := make([]task.ProposalFile, len(files))
+	for i, f := range files {
+		out[i] = task.ProposalFile{Path: f.Path, Change: f.Change}
+	}
+	return out
+}
+
+// additionalPullRequests matches the PRs the agent opened in a multi-repo
+// task's additional repos to the repos by their full names.
+func (h *HTTPHandler) additionalPullRequests(ctx context.Context, id task.TaskID, completed []verveclient.CompletedPullRequest) ([]task.PullRequest, error) {
+	t, err := h.taskStore.ReadTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]string, len(t.AdditionalRepoIDs))
+	for _, repoID := range t.AdditionalRepoIDs {
+		r, err := h.readRepo(ctx, repoID)
+		if err != nil {
+			return nil, err
 		}
+		byName[strings.ToLower(r.FullName)] = repoID
+	}
+	prs := make([]task.PullRequest, 0, len(completed))
+	for _, pr := range completed {
+		repoID, ok := byName[strings.ToLower(pr.Repo)]
+		if !ok {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, msgcat.Text(msgcat.ErrTaskPullRequestRepo, pr.Repo))
+		}
+		prs = append(prs, task.PullRequest{RepoID: repoID, URL: pr.URL, Number: pr.Number})
+	}
+	return prs, nil
+}
+
+// dispatchCIWorkflow triggers the repo's CI workflow on the task's PR branch
+// and records the dispatch so check-status logic waits for its run. It is a
+// no-op when the repo has no CI workflow or no GitHub token is configured.
+func (h *HTTPHandler) dispatchCIWorkflow(ctx context.Context, id task.TaskID) error {
+	if h.githubToken == nil {
+		return nil
+	}
+	gh := h.githubToken.GetClient()
+	if gh == nil {
+		return nil
+	}
+	t, err := h.taskStore.ReadTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	repoID, err := repo.ParseRepoID(t.RepoID)
+	if err != nil {
+		return err
+	}
+	r, err := h.repoStore.ReadRepo(ctx, repoID)
+	if err != nil {
+		return err
 	}
+	if r.CIWorkflow == nil || t.PRNumber <= 0 {
+		return nil
+	}
+
+	ref, err := gh.GetPRHeadRef(ctx, r.Owner, r.Name, t.PRNumber)
+	if err != nil {
+		return fmt.Errorf("get pr head ref: %w", err)
+	}
+	dispatch := &task.CIDispatch{
+		WorkflowFile: r.CIWorkflow.File,
+		Ref:          ref,
+		// Taken before dispatching so the run created by it is never older.
+		DispatchedAt: time.Now(),
+	}
+	if err := gh.DispatchWorkflow(ctx, r.Owner, r.Name, dispatch.WorkflowFile, ref, r.CIWorkflow.Inputs); err != nil {
+		return fmt.Errorf("dispatch workflow %s: %w", dispatch.WorkflowFile, err)
+	}
+	return h.taskStore.RecordCIDispatch(ctx, id, dispatch)
+}
 
+// scanProvenance scans the task's PR diff for license headers and large
+// verbatim blocks, records the findings on the task and sets the PR's
+// provenance commit status. Flagged PRs also have auto-merge disabled so they
+// wait for a human to acknowledge the findings. It is a no-op when scanning is
+// disabled or no GitHub token is configured.
+func (h *HTTPHandler) scanProvenance(ctx context.Context, id task.TaskID) error {
+	if h.provenanceScanner == nil || h.githubToken == nil {
+		return nil
+	}
+	gh := h.githubToken.GetClient()
+	if gh == nil {
+		return nil
+	}
+	t, err := h.taskStore.ReadTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	if t.PRNumber <= 0 {
+		return nil
+	}
+	repoID, err := repo.ParseRepoID(t.RepoID)
+	if err != nil {
+		return err
+	}
+	r, err := h.repoStore.ReadRepo(ctx, repoID)
+	if err != nil {
+		return err
+	}
+
+	sha, err := gh.GetPRHeadSHA(ctx, r.Owner, r.Name, t.PRNumber)
+	if err != nil {
+		return fmt.Errorf("get pr head sha: %w", err)
+	}
+	diff, err := gh.GetPRDiff(ctx, r.Owner, r.Name, t.PRNumber)
+	if err != nil {
+		return fmt.Errorf("get pr diff: %w", err)
+	}
+	// A failed external scanner still yields the built-in findings, which
+	// are recorded before the error is reported.
+	findings, scanErr := h.provenanceScanner.Scan(ctx, diff)
+	review := &task.ProvenanceReview{Findings: findings, HeadSHA: sha, ScannedAt: time.Now()}
+	if err := h.taskStore.RecordProvenanceReview(ctx, id, review); err != nil {
+		return err
+	}
+
+	status := github.CommitStatus{
+		State:       "success",
+		Context:     provenance.StatusContext,
+		Description: msgcat.Text(msgcat.StatusProvenanceClear),
+	}
 	switch {
-	case !req.Success:
-		if req.Retryable {
-			reason := "rate_limit: " + req.Error
-			if err := h.taskStore.ScheduleRetry(ctx, id, reason); err != nil {
-				return err
-			}
-			return c.NoContent(http.StatusNoContent)
+	case review.NeedsAcknowledgment():
+		status.State = "failure"
+		status.Description = msgcat.Text(msgcat.StatusProvenanceFlagged, len(review.Findings))
+	case review.AcknowledgedAt != nil:
+		status.Description = msgcat.Text(msgcat.StatusProvenanceAcknowledged)
+	}
+	if err := gh.SetCommitStatus(ctx, r.Owner, r.Name, sha, status); err != nil {
+		return fmt.Errorf("set commit status: %w", err)
+	}
+	if review.NeedsAcknowledgment() {
+		if _, err := gh.DisableAutoMerge(ctx, r.Owner, r.Name, t.PRNumber); err != nil {
+			return fmt.Errorf("disable auto-merge: %w", err)
+		}
+	}
+	return scanErr
+}
+
+// selfReview runs the self-review checks over the task's PR diff, posts the
+// report as a PR comment and records it on the task. The comment from an
+// earlier review of the same task is edited rather than posting another. It
+// is a no-op when self-review is disabled or no GitHub token is configured.
+func (h *HTTPHandler) selfReview(ctx context.Context, id task.TaskID) error {
+	if h.selfReviewer == nil || h.githubToken == nil {
+		return nil
+	}
+	gh := h.githubToken.GetClient()
+	if gh == nil {
+		return nil
+	}
+	t, err := h.taskStore.ReadTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	if t.PRNumber <= 0 {
+		return nil
+	}
+	r, err := h.readRepo(ctx, t.RepoID)
+	if err != nil {
+		return err
+	}
+	prev, err := h.taskStore.ReadSelfReview(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	sha, err := gh.GetPRHeadSHA(ctx, r.Owner, r.Name, t.PRNumber)
+	if err != nil {
+		return fmt.Errorf("get pr head sha: %w", err)
+	}
+	diff, err := gh.GetPRDiff(ctx, r.Owner, r.Name, t.PRNumber)
+	if err != nil {
+		return fmt.Errorf("get pr diff: %w", err)
+	}
+	report := h.selfReviewer.Review(diff, t.AcceptanceCriteria)
+
+	review := &task.SelfReview{Score: report.Score, Checks: report.Checks, HeadSHA: sha, ReviewedAt: time.Now()}
+	if prev != nil {
+		review.CommentID = prev.CommentID
+	}
+	// The score is recorded even when the comment can't be posted.
+	commentID, commentErr := gh.UpsertPRComment(ctx, r.Owner, r.Name, t.PRNumber, review.CommentID, report.Markdown())
+	if commentErr == nil {
+		review.CommentID = commentID
+	}
+	if err := h.taskStore.RecordSelfReview(ctx, id, review); err != nil {
+		return err
+	}
+	if commentErr != nil {
+		return fmt.Errorf("post pr comment: %w", commentErr)
+	}
+	return nil
+}
+
+// checkProtectedPaths fails the task when any of its open pull requests
+// changes a path protected in the PR's repo. Auto-merge is turned off for the
+// offending PRs so nothing lands before a human has looked at them.
+func (h *HTTPHandler) checkProtectedPaths(ctx context.Context, id task.TaskID) error {
+	if h.githubToken == nil {
+		return nil
+	}
+	gh := h.githubToken.GetClient()
+	if gh == nil {
+		return nil
+	}
+	t, err := h.taskStore.ReadTask(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	prs := make([]task.PullRequest, 0, len(t.PullRequests)+1)
+	if t.PRNumber > 0 {
+		prs = append(prs, task.PullRequest{RepoID: t.RepoID, Number: t.PRNumber})
+	}
+	for _, pr := range t.PullRequests {
+		if !pr.Merged {
+			prs = append(prs, pr)
 		}
-		t, readErr := h.taskStore.ReadTask(ctx, id)
-		if readErr != nil {
-			return readErr
+	}
+
+	var paths []string
+	for _, pr := range prs {
+		r, err := h.readRepo(ctx, pr.RepoID)
+		if err != nil {
+			return err
 		}
-		if t.PRNumber > 0 || t.BranchName != "" {
-			if err := h.taskStore.UpdateTaskStatus(ctx, id, task.StatusReview); err != nil {
-				return err
-			}
-		} else {
-			if err := h.taskStore.UpdateTaskStatus(ctx, id, task.StatusFailed); err != nil {
-				return err
+		if len(r.ProtectedPaths) == 0 {
+			continue
+		}
+		diff, err := gh.GetPRDiff(ctx, r.Owner, r.Name, pr.Number)
+		if err != nil {
+			return fmt.Errorf("get pr diff: %w", err)
+		}
+		violations := pathguard.Check(r.ProtectedPaths, diff)
+		if len(violations) == 0 {
+			continue
+		}
+		for _, v := range violations {
+			if pr.RepoID == t.RepoID {
+				paths = append(paths, v.Path)
+			} else {
+				paths = append(paths, r.FullName+":"+v.Path)
 			}
 		}
-	case req.PullRequestURL != "":
-		if err := h.taskStore.SetTaskPullRequest(ctx, id, req.PullRequestURL, req.PRNumber); err != nil {
-			return err
+		if _, err := gh.DisableAutoMerge(ctx, r.Owner, r.Name, pr.Number); err != nil {
+			return fmt.Errorf("disable auto-merge: %w", err)
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	return h.taskStore.FailProtectedPaths(ctx, id, paths)
+}
+
+// validateCompletion runs the completion validations configured for each of
+// the task's open pull requests' repos. A PR title that can be fixed
+// mechanically is amended first; remaining violations send the task back to
+// the agent as a feedback retry listing what to fix.
+func (h *HTTPHandler) validateCompletion(ctx context.Context, id task.TaskID) error {
+	if h.githubToken == nil {
+		return nil
+	}
+	gh := h.githubToken.GetClient()
+	if gh == nil {
+		return nil
+	}
+	t, err := h.taskStore.ReadTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	if t.Status != task.StatusReview {
+		return nil
+	}
+
+	prs := make([]task.PullRequest, 0, len(t.PullRequests)+1)
+	if t.PRNumber > 0 {
+		prs = append(prs, task.PullRequest{RepoID: t.RepoID, Number: t.PRNumber})
+	}
+	for _, pr := range t.PullRequests {
+		if !pr.Merged {
+			prs = append(prs, pr)
 		}
-	case req.BranchName != "":
-		if err := h.taskStore.SetTaskBranch(ctx, id, req.BranchName); err != nil {
+	}
+
+	var violations []prlint.Violation
+	var errs []error
+	for _, pr := range prs {
+		r, err := h.readRepo(ctx, pr.RepoID)
+		if err != nil {
 			return err
 		}
-	default:
-		t, readErr := h.taskStore.ReadTask(ctx, id)
-		if readErr != nil {
-			return readErr
+		if r.CompletionValidations == nil {
+			continue
 		}
-		if t.PRNumber > 0 || t.BranchName != "" {
-			if err := h.taskStore.UpdateTaskStatus(ctx, id, task.StatusReview); err != nil {
-				return err
-			}
-		} else {
-			if req.NoChanges {
-				if err := h.taskStore.SetCloseReason(ctx, id, "No changes needed — the codebase already meets the required criteria"); err != nil {
-					return err
-				}
+		details, err := gh.GetPRDetails(ctx, r.Owner, r.Name, pr.Number)
+		if err != nil {
+			return fmt.Errorf("get pr details: %w", err)
+		}
+		commits, err := gh.ListPRCommitMessages(ctx, r.Owner, r.Name, pr.Number)
+		if err != nil {
+			return fmt.Errorf("list pr commits: %w", err)
+		}
+		pull := &prlint.PullRequest{
+			TaskID:  t.ID.String(),
+			Title:   details.Title,
+			Body:    details.Body,
+			Branch:  details.HeadRef,
+			Commits: commits,
+		}
+		// Amend a title that only lacks the prefix or can be taken from a
+		// commit subject instead of spending an agent attempt on it.
+		if title, ok := r.CompletionValidations.FixTitle(pull); ok {
+			if err := gh.UpdatePR(ctx, r.Owner, r.Name, pr.Number, title, details.Body); err != nil {
+				errs = append(errs, fmt.Errorf("update pr title: %w", err))
+			} else {
+				pull.Title = title
 			}
-			if err := h.taskStore.UpdateTaskStatus(ctx, id, task.StatusClosed); err != nil {
-				return err
+		}
+		found := prlint.Check(r.CompletionValidations.Validators(), pull)
+		for _, v := range found {
+			if pr.RepoID != t.RepoID {
+				v.Message = r.FullName + ": " + v.Message
 			}
+			violations = append(violations, v)
 		}
 	}
-
-	return c.NoContent(http.StatusNoContent)
+	if len(violations) > 0 {
+		errs = append(errs, h.taskStore.CompletionValidationRetryTask(ctx, id, violations))
+	}
+	return errors.Join(errs...)
 }
 
 // --- Epic Agent Endpoints ---
@@ -468,6 +1274,27 @@ func (h *HTTPHandler) EpicAppendLogs(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// EpicCost handles POST /epics/:id/cost — worker reports planning session cost.
+// Returns JSON with a stopped flag that is true when the cost pushed the epic
+// over its planning budget and the session should end.
+func (h *HTTPHandler) EpicCost(c echo.Context) error {
+	req, err := server.BindRequest[EpicCostRequest](c)
+	if err != nil {
+		return err
+	}
+	id := epic.MustParseEpicID(req.ID)
+	c.Set(logkey.EpicID, id.String())
+
+	stopped, err := h.epicStore.AddPlanningCost(c.Request().Context(), id, req.CostUSD)
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, map[string]interface{}{
+		"status":  "ok",
+		"stopped": stopped,
+	})
+}
+
 // --- Repo Setup Agent Endpoint ---
 
 // RepoSetupComplete handles POST /repos/:repo_id/setup-complete
@@ -592,18 +1419,18 @@ func (h *HTTPHandler) ConversationAppendLogs(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
-func (h *HTTPHandler) buildConversationPollResponse(c echo.Context, conv *conversation.Conversation) (*PollResponse, error) {
+func (h *HTTPHandler) buildConversationPollResponse(ctx context.Context, conv *conversation.Conversation) (*PollResponse, error) {
 	repoID, err := repo.ParseRepoID(conv.RepoID)
 	if err != nil {
 		return nil, err
 	}
-	r, err := h.repoStore.ReadRepo(c.Request().Context(), repoID)
+	r, err := h.repoStore.ReadRepo(ctx, repoID)
 	if err != nil {
 		return nil, err
 	}
-	var token string
-	if h.githubToken != nil {
-		token = h.githubToken.GetToken()
+	token, err := h.repoToken(ctx, r.FullName)
+	if err != nil {
+		return nil, err
 	}
 	return &PollResponse{
 		Type:             "conversation",
@@ -629,3 +1456,128 @@ func (h *HTTPHandler) ListWorkers(c echo.Context) error {
 	}
 	return server.SetResponseList(c, http.StatusOK, workers, "")
 }
+
+// RegisterWorker handles POST /workers — a worker announces itself on
+// startup so it shows up in the fleet with its hostname, version and capacity.
+func (h *HTTPHandler) RegisterWorker(c echo.Context) error {
+	req, err := server.BindRequest[RegisterWorkerRequest](c)
+	if err != nil {
+		return err
+	}
+	if h.workerRegistry != nil {
+		h.workerRegistry.Register(workertracker.Registration{
+			WorkerID:           req.WorkerID,
+			Hostname:           req.Hostname,
+			Version:            req.Version,
+			MaxConcurrentTasks: req.MaxConcurrentTasks,
+			Labels:             req.Labels,
+		})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// WorkerHeartbeat handles POST /workers/:worker_id/heartbeat — a registered
+// worker reports the tasks it is running. Tasks of workers that stop sending
+// heartbeats are released for other workers. Responds 404 for an unknown
+// worker so it registers again.
+func (h *HTTPHandler) WorkerHeartbeat(c echo.Context) error {
+	req, err := server.BindRequest[WorkerHeartbeatRequest](c)
+	if err != nil {
+		return err
+	}
+	if h.workerRegistry == nil || !h.workerRegistry.Heartbeat(req.WorkerID, req.ActiveTaskIDs) {
+		return echo.NewHTTPError(http.StatusNotFound, msgcat.Text(msgcat.ErrWorkerNotRegistered))
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Drain handles POST /drain — a worker shutting down hands back the tasks it
+// abandoned. They are rescheduled right away instead of waiting for their
+// heartbeats to time out, and the worker is dropped from the registry.
+func (h *HTTPHandler) Drain(c echo.Context) error {
+	req, err := server.BindRequest[DrainRequest](c)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	rescheduled := []string{}
+	for _, rawID := range req.TaskIDs {
+		ok, err := h.taskStore.AbandonTask(ctx, task.MustParseTaskID(rawID))
+		if err != nil {
+			return err
+		}
+		if ok {
+			rescheduled = append(rescheduled, rawID)
+		}
+	}
+	if req.WorkerID != "" && h.workerRegistry != nil {
+		h.workerRegistry.Remove(req.WorkerID)
+	}
+	return server.SetResponse(c, http.StatusOK, DrainResponse{Rescheduled: rescheduled})
+}
+
+// --- Agent Permits ---
+
+// AcquirePermit handles POST /permits — a worker asks to run an agent. The
+// permit is granted when the limiter's global and per-model limits allow
+// it; otherwise the response says which limit was reached and when to ask
+// again. Without limits the request is granted without a permit to hold.
+func (h *HTTPHandler) AcquirePermit(c echo.Context) error {
+	req, err := server.BindRequest[PermitRequest](c)
+	if err != nil {
+		return err
+	}
+	if h.permits == nil {
+		return server.SetResponse(c, http.StatusOK, PermitResponse{Granted: true})
+	}
+	p, denial := h.permits.Acquire(permit.Request{WorkerID: req.WorkerID, TaskID: req.TaskID, Model: req.Model})
+	if denial != nil {
+		return server.SetResponse(c, http.StatusOK, PermitResponse{
+			Reason:       denial.Reason,
+			RetryAfterMs: denial.RetryAfter.Milliseconds(),
+		})
+	}
+	return server.SetResponse(c, http.StatusOK, PermitResponse{Granted: true, Permit: toPermit(p)})
+}
+
+// RenewPermit handles PUT /permits/:permit_id — a worker extends the permit
+// of an agent that is still running. Responds 404 once the permit expired.
+func (h *HTTPHandler) RenewPermit(c echo.Context) error {
+	req, err := server.BindRequest[PermitIDRequest](c)
+	if err != nil {
+		return err
+	}
+	if h.permits == nil {
+		return echo.NewHTTPError(http.StatusNotFound, msgcat.Text(msgcat.ErrPermitNotFound))
+	}
+	p, ok := h.permits.Renew(req.PermitID)
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, msgcat.Text(msgcat.ErrPermitNotFound))
+	}
+	return server.SetResponse(c, http.StatusOK, toPermit(p))
+}
+
+// ReleasePermit handles DELETE /permits/:permit_id — a worker returns the
+// permit of an agent that finished. Releasing an expired permit is a no-op.
+func (h *HTTPHandler) ReleasePermit(c echo.Context) error {
+	req, err := server.BindRequest[PermitIDRequest](c)
+	if err != nil {
+		return err
+	}
+	if h.permits != nil {
+		h.permits.Release(req.PermitID)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func toPermit(p *permit.Permit) *Permit {
+	return &Permit{
+		ID:        p.ID,
+		WorkerID:  p.WorkerID,
+		TaskID:    p.TaskID,
+		Model:     p.Model,
+		GrantedAt: p.GrantedAt,
+		ExpiresAt: p.ExpiresAt,
+	}
+}
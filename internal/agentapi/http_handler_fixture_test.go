@@ -22,10 +22,12 @@ import (
 	"github.com/vervesh/verve/internal/sqlite"
 	"github.com/vervesh/verve/internal/task"
 	"github.com/vervesh/verve/internal/workertracker"
+	"github.com/vervesh/verve/pkg/verveclient"
 )
 
 type fixture struct {
 	Server            *server.Server
+	Handler           *agentapi.HTTPHandler
 	TaskStore         *task.Store
 	EpicStore         *epic.Store
 	RepoStore         *repo.Store
@@ -39,7 +41,7 @@ type fixture struct {
 	convRepo conversation.Repository
 }
 
-func newFixture(t *testing.T) *fixture {
+func newFixture(t *testing.T, opts ...agentapi.Option) *fixture {
 	t.Helper()
 
 	db := sqlite.NewTestDB(t)
@@ -59,7 +61,7 @@ func newFixture(t *testing.T) *fixture {
 	convRepo := sqlite.NewConversationRepository(db)
 	convStore := conversation.NewStore(convRepo, logger)
 
-	handler := agentapi.NewHTTPHandler(taskStore, epicStore, repoStore, convStore, nil, registry)
+	handler := agentapi.NewHTTPHandler(taskStore, epicStore, repoStore, convStore, nil, registry, opts...)
 
 	srv, err := server.NewServer(testutil.GetFreePort(t))
 	require.NoError(t, err)
@@ -77,6 +79,7 @@ func newFixture(t *testing.T) *fixture {
 
 	return &fixture{
 		Server:            srv,
+		Handler:           handler,
 		TaskStore:         taskStore,
 		EpicStore:         epicStore,
 		RepoStore:         repoStore,
@@ -96,6 +99,10 @@ func (f *fixture) taskLogsURL(id task.TaskID) string {
 	return fmt.Sprintf("%s/api/v1/agent/tasks/%s/logs", f.Server.Address(), id)
 }
 
+func (f *fixture) taskEventsURL(id task.TaskID) string {
+	return fmt.Sprintf("%s/api/v1/agent/tasks/%s/events", f.Server.Address(), id)
+}
+
 func (f *fixture) taskHeartbeatURL(id task.TaskID) string {
 	return fmt.Sprintf("%s/api/v1/agent/tasks/%s/heartbeat", f.Server.Address(), id)
 }
@@ -104,6 +111,10 @@ func (f *fixture) taskCompleteURL(id task.TaskID) string {
 	return fmt.Sprintf("%s/api/v1/agent/tasks/%s/complete", f.Server.Address(), id)
 }
 
+func (f *fixture) taskArtifactsURL(id task.TaskID) string {
+	return fmt.Sprintf("%s/api/v1/agent/tasks/%s/artifacts", f.Server.Address(), id)
+}
+
 func (f *fixture) epicCompleteURL(id epic.EpicID) string {
 	return fmt.Sprintf("%s/api/v1/agent/epics/%s/complete", f.Server.Address(), id)
 }
@@ -120,6 +131,22 @@ func (f *fixture) workersURL() string {
 	return fmt.Sprintf("%s/api/v1/agent/workers", f.Server.Address())
 }
 
+func (f *fixture) workerHeartbeatURL(workerID string) string {
+	return fmt.Sprintf("%s/api/v1/agent/workers/%s/heartbeat", f.Server.Address(), workerID)
+}
+
+func (f *fixture) drainURL() string {
+	return fmt.Sprintf("%s/api/v1/agent/drain", f.Server.Address())
+}
+
+func (f *fixture) permitsURL() string {
+	return fmt.Sprintf("%s/api/v1/agent/permits", f.Server.Address())
+}
+
+func (f *fixture) permitURL(permitID string) string {
+	return fmt.Sprintf("%s/api/v1/agent/permits/%s", f.Server.Address(), permitID)
+}
+
 func (f *fixture) repoSetupCompleteURL(repoID repo.RepoID) string {
 	return fmt.Sprintf("%s/api/v1/agent/repos/%s/setup-complete", f.Server.Address(), repoID)
 }
@@ -198,6 +225,22 @@ func postNoContent(t *testing.T, url string, body any) {
 	}
 }
 
+// postKeyed is postNoContent for a callback sent with an idempotency key.
+func postKeyed(t *testing.T, url, key string, body any) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, mustJSONReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(verveclient.IdempotencyKeyHeader, key)
+	httpRes, err := testutil.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer httpRes.Body.Close()
+	if httpRes.StatusCode != http.StatusNoContent {
+		respBody, _ := io.ReadAll(httpRes.Body)
+		require.Failf(t, "http error", "POST %s\nStatus: %s\nBody: %s", url, httpRes.Status, string(respBody))
+	}
+}
+
 func doJSON(t *testing.T, method, url string, body any) *http.Response {
 	t.Helper()
 	req, err := http.NewRequest(method, url, mustJSONReader(body))
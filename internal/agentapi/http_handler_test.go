@@ -1,8 +1,14 @@
 package agentapi_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -12,9 +18,14 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/vervesh/verve/internal/agentapi"
+	"github.com/vervesh/verve/internal/attachment"
 	"github.com/vervesh/verve/internal/epic"
+	"github.com/vervesh/verve/internal/msgcat"
+	"github.com/vervesh/verve/internal/permit"
+	"github.com/vervesh/verve/internal/repo"
 	"github.com/vervesh/verve/internal/task"
 	"github.com/vervesh/verve/internal/workertracker"
+	"github.com/vervesh/verve/pkg/verveclient"
 )
 
 // --- Task Agent Endpoints ---
@@ -23,7 +34,7 @@ func TestTaskAppendLogs(t *testing.T) {
 	f := newFixture(t)
 	tsk := f.seedRunningTask()
 
-	req := agentapi.TaskLogsRequest{
+	req := verveclient.TaskLogsRequest{
 		Logs:    []string{"line 1", "line 2"},
 		Attempt: 1,
 	}
@@ -36,16 +47,88 @@ func TestTaskAppendLogs(t *testing.T) {
 	assert.Contains(t, stored, "line 2")
 }
 
+func TestTaskAppendLogs_IdempotencyKey(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedRunningTask()
+
+	req := verveclient.TaskLogsRequest{Logs: []string{"line 1"}, Attempt: 1}
+	postKeyed(t, f.taskLogsURL(tsk.ID), "batch-1", req)
+	postKeyed(t, f.taskLogsURL(tsk.ID), "batch-1", req)
+	postKeyed(t, f.taskLogsURL(tsk.ID), "batch-2", verveclient.TaskLogsRequest{Logs: []string{"line 2"}, Attempt: 1})
+
+	stored, err := f.taskRepo.ReadTaskLogs(context.Background(), tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"line 1", "line 2"}, stored)
+}
+
 func TestTaskAppendLogs_InvalidID(t *testing.T) {
 	f := newFixture(t)
 
 	url := f.Server.Address() + "/api/v1/agent/tasks/bad-id/logs"
-	req := agentapi.TaskLogsRequest{Logs: []string{"line"}}
+	req := verveclient.TaskLogsRequest{Logs: []string{"line"}}
 	httpRes := doJSON(t, http.MethodPost, url, req)
 	defer httpRes.Body.Close()
 	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
 }
 
+func TestTaskAppendEvents(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedRunningTask()
+
+	req := verveclient.TaskEventsRequest{
+		Attempt: 1,
+		Events: []verveclient.AgentEventInput{
+			{Type: string(task.AgentEventToolCall), Payload: json.RawMessage(`{"name":"Bash","detail":"export password=hunter2"}`)},
+			{Type: string(task.AgentEventPRCreated), Payload: json.RawMessage(`{"url":"https://github.com/owner/repo/pull/1","number":1}`)},
+			{Type: string(task.AgentEventNoChanges)},
+		},
+	}
+	postNoContent(t, f.taskEventsURL(tsk.ID), req)
+
+	stored, err := f.TaskStore.ListAgentEvents(context.Background(), tsk.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, stored, 3)
+	assert.Equal(t, task.AgentEventToolCall, stored[0].Type)
+	assert.NotContains(t, string(stored[0].Payload), "hunter2", "expected payload to be redacted")
+	assert.Equal(t, task.AgentEventPRCreated, stored[1].Type)
+	assert.JSONEq(t, `{}`, string(stored[2].Payload))
+	for _, e := range stored {
+		assert.Equal(t, 1, e.Attempt)
+	}
+}
+
+func TestTaskAppendEvents_NullPayload(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedRunningTask()
+
+	req := map[string]any{
+		"attempt": 1,
+		"events":  []map[string]any{{"type": string(task.AgentEventNoChanges), "payload": nil}},
+	}
+	postNoContent(t, f.taskEventsURL(tsk.ID), req)
+
+	stored, err := f.TaskStore.ListAgentEvents(context.Background(), tsk.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.JSONEq(t, `{}`, string(stored[0].Payload))
+}
+
+func TestTaskAppendEvents_Invalid(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedRunningTask()
+
+	tests := map[string]verveclient.AgentEventInput{
+		"unknown type":       {Type: "dance", Payload: json.RawMessage(`{}`)},
+		"non-object payload": {Type: string(task.AgentEventCost), Payload: json.RawMessage(`[1]`)},
+	}
+	for name, e := range tests {
+		req := verveclient.TaskEventsRequest{Attempt: 1, Events: []verveclient.AgentEventInput{e}}
+		httpRes := doJSON(t, http.MethodPost, f.taskEventsURL(tsk.ID), req)
+		httpRes.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode, name)
+	}
+}
+
 func TestTaskHeartbeat(t *testing.T) {
 	f := newFixture(t)
 	tsk := f.seedRunningTask()
@@ -55,6 +138,29 @@ func TestTaskHeartbeat(t *testing.T) {
 	assert.Equal(t, false, res.Data["stopped"])
 }
 
+func TestTaskHeartbeat_DeliversNudges(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedRunningTask()
+	ctx := context.Background()
+
+	_, err := f.TaskStore.NudgeTask(ctx, tsk.ID, "Skip the migration for now")
+	require.NoError(t, err)
+
+	res := testutil.Post[server.Response[verveclient.HeartbeatResponse]](t, f.taskHeartbeatURL(tsk.ID), nil)
+	assert.False(t, res.Data.Stopped)
+	require.Len(t, res.Data.Nudges, 1)
+	assert.Equal(t, "Skip the migration for now", res.Data.Nudges[0].Message)
+
+	// Each nudge is delivered once.
+	res = testutil.Post[server.Response[verveclient.HeartbeatResponse]](t, f.taskHeartbeatURL(tsk.ID), nil)
+	assert.Empty(t, res.Data.Nudges)
+
+	nudges, err := f.TaskStore.ListNudges(ctx, tsk.ID)
+	require.NoError(t, err)
+	require.Len(t, nudges, 1)
+	assert.NotNil(t, nudges[0].DeliveredAt)
+}
+
 func TestTaskHeartbeat_StoppedTask(t *testing.T) {
 	f := newFixture(t)
 	tsk := f.seedRunningTask()
@@ -76,7 +182,7 @@ func TestTaskComplete_Success(t *testing.T) {
 	f := newFixture(t)
 	tsk := f.seedRunningTask()
 
-	req := agentapi.TaskCompleteRequest{
+	req := verveclient.TaskCompleteRequest{
 		Success:        true,
 		PullRequestURL: "https://github.com/owner/repo/pull/42",
 		PRNumber:       42,
@@ -90,11 +196,71 @@ func TestTaskComplete_Success(t *testing.T) {
 	assert.Equal(t, "https://github.com/owner/repo/pull/42", stored.PullRequestURL)
 }
 
+func TestTaskComplete_IdempotencyKey(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	tsk := f.seedRunningTask()
+
+	req := verveclient.TaskCompleteRequest{
+		Attempt:     1,
+		Success:     true,
+		BranchName:  "verve/task",
+		AgentStatus: `{"files_modified":["a.go"]}`,
+		CostUSD:     1.5,
+	}
+	postKeyed(t, f.taskCompleteURL(tsk.ID), "complete", req)
+	// The worker resends the completion after losing the response.
+	postKeyed(t, f.taskCompleteURL(tsk.ID), "complete", req)
+
+	stored, err := f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, 1.5, stored.CostUSD, 0.001)
+	assert.Equal(t, "verve/task", stored.BranchName)
+
+	// A later attempt completes under the same key.
+	ok, err := f.taskRepo.RetryTask(ctx, tsk.ID, "feedback")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
+	req.Attempt = 2
+	postKeyed(t, f.taskCompleteURL(tsk.ID), "complete", req)
+
+	stored, err = f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, 3, stored.CostUSD, 0.001)
+}
+
+func TestTaskComplete_Conflict(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	tsk := f.seedRunningTask()
+
+	post := func(req verveclient.TaskCompleteRequest) int {
+		t.Helper()
+		res, err := testutil.DefaultClient.Post(f.taskCompleteURL(tsk.ID), "application/json", mustJSONReader(req))
+		require.NoError(t, err)
+		defer res.Body.Close()
+		return res.StatusCode
+	}
+
+	// An attempt the task has moved past.
+	assert.Equal(t, http.StatusConflict, post(verveclient.TaskCompleteRequest{Attempt: 2, Success: true, CostUSD: 1}))
+
+	// A task the user stopped meanwhile.
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusPending))
+	assert.Equal(t, http.StatusConflict, post(verveclient.TaskCompleteRequest{Attempt: 1, Success: true, CostUSD: 1}))
+
+	stored, err := f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusPending, stored.Status)
+	assert.Zero(t, stored.CostUSD)
+}
+
 func TestTaskComplete_Failure(t *testing.T) {
 	f := newFixture(t)
 	tsk := f.seedRunningTask()
 
-	req := agentapi.TaskCompleteRequest{
+	req := verveclient.TaskCompleteRequest{
 		Success: false,
 		Error:   "something went wrong",
 	}
@@ -105,6 +271,113 @@ func TestTaskComplete_Failure(t *testing.T) {
 	assert.Equal(t, task.StatusFailed, stored.Status)
 }
 
+func TestTaskComplete_MaxRuntimeExceeded(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedRunningTask()
+
+	req := verveclient.TaskCompleteRequest{
+		Error:              "max runtime of 1h0m0s exceeded",
+		MaxRuntimeExceeded: true,
+	}
+	postNoContent(t, f.taskCompleteURL(tsk.ID), req)
+
+	stored, err := f.taskRepo.ReadTask(context.Background(), tsk.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, task.StatusFailed, stored.Status)
+	assert.Equal(t, task.FailureMaxRuntime, stored.FailureCategory)
+	assert.Equal(t, msgcat.Text(msgcat.TaskFailedMaxRuntime), stored.CloseReason)
+}
+
+func TestTaskComplete_BudgetExceeded(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	tsk := task.NewTask(f.Repo.ID.String(), "Test Task", "description", nil, nil, 0, false, false, "sonnet", true)
+	tsk.MaxCostUSD = 1
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
+
+	req := verveclient.TaskCompleteRequest{
+		Error:          "max cost of $1.00 reached",
+		CostUSD:        1.02,
+		BudgetExceeded: true,
+	}
+	postNoContent(t, f.taskCompleteURL(tsk.ID), req)
+
+	stored, err := f.taskRepo.ReadTask(ctx, tsk.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, task.StatusFailed, stored.Status)
+	assert.Equal(t, task.FailureBudgetExceeded, stored.FailureCategory)
+	assert.InDelta(t, 1.02, stored.CostUSD, 0.001)
+}
+
+func TestTaskComplete_AdditionalRepoPullRequests(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	other, err := repo.NewRepo("owner/frontend")
+	require.NoError(t, err)
+	require.NoError(t, f.RepoStore.CreateRepo(ctx, other))
+
+	tsk := task.NewTask(f.Repo.ID.String(), "Test Task", "description", nil, nil, 0, false, false, "sonnet", true)
+	tsk.AdditionalRepoIDs = []string{other.ID.String()}
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
+
+	// Only the additional repo changed.
+	req := verveclient.TaskCompleteRequest{
+		Success: true,
+		PullRequests: []verveclient.CompletedPullRequest{
+			{Repo: "owner/frontend", URL: "https://github.com/owner/frontend/pull/7", Number: 7},
+		},
+	}
+	postNoContent(t, f.taskCompleteURL(tsk.ID), req)
+
+	stored, err := f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusReview, stored.Status)
+	assert.Zero(t, stored.PRNumber)
+	assert.Equal(t, []task.PullRequest{
+		{RepoID: other.ID.String(), URL: "https://github.com/owner/frontend/pull/7", Number: 7},
+	}, stored.PullRequests)
+}
+
+func TestTaskComplete_ShadowDiff(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedRunningTask()
+
+	diff := "diff --git a/main.go b/main.go\n+package main\n"
+	req := verveclient.TaskCompleteRequest{
+		Success:    true,
+		ShadowDiff: diff,
+	}
+	postNoContent(t, f.taskCompleteURL(tsk.ID), req)
+
+	stored, err := f.taskRepo.ReadTask(context.Background(), tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusReview, stored.Status)
+	assert.False(t, stored.HasPullRequest())
+
+	shadow, err := f.TaskStore.ReadShadowDiff(context.Background(), tsk.ID)
+	require.NoError(t, err)
+	require.NotNil(t, shadow)
+	assert.Equal(t, diff, shadow.Diff)
+}
+
+func TestTaskComplete_UnknownPullRequestRepo(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedRunningTask()
+
+	req := verveclient.TaskCompleteRequest{
+		Success: true,
+		PullRequests: []verveclient.CompletedPullRequest{
+			{Repo: "owner/other", URL: "https://github.com/owner/other/pull/1", Number: 1},
+		},
+	}
+	httpRes := doJSON(t, http.MethodPost, f.taskCompleteURL(tsk.ID), req)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+}
+
 func TestTaskComplete_MergesFilesModifiedAcrossRetries(t *testing.T) {
 	f := newFixture(t)
 	tsk := f.seedRunningTask()
@@ -115,7 +388,7 @@ func TestTaskComplete_MergesFilesModifiedAcrossRetries(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Complete task with new agent status (retry attempt only reports its own files)
-	req := agentapi.TaskCompleteRequest{
+	req := verveclient.TaskCompleteRequest{
 		Success:        true,
 		PullRequestURL: "https://github.com/owner/repo/pull/42",
 		PRNumber:       42,
@@ -195,7 +468,7 @@ func TestEpicAppendLogs(t *testing.T) {
 	f := newFixture(t)
 	e := f.seedPlanningEpic()
 
-	req := agentapi.SessionLogRequest{
+	req := verveclient.SessionLogRequest{
 		Lines: []string{"agent: analyzing repo", "agent: proposing tasks"},
 	}
 	postNoContent(t, f.epicLogsURL(e.ID), req)
@@ -306,13 +579,142 @@ func TestListWorkers_WithWorkers(t *testing.T) {
 	assert.Equal(t, 4, res.Data[0].MaxConcurrentTasks)
 }
 
+func TestRegisterWorker(t *testing.T) {
+	f := newFixture(t)
+
+	req := verveclient.RegisterWorkerRequest{WorkerID: "worker-1", Hostname: "host-a", Version: "v1.0.0", MaxConcurrentTasks: 4}
+	postNoContent(t, f.workersURL(), req)
+
+	workers := f.WorkerRegistry.ListWorkers(time.Minute)
+	require.Len(t, workers, 1)
+	assert.Equal(t, "host-a", workers[0].Hostname)
+	assert.Equal(t, "v1.0.0", workers[0].Version)
+	assert.Equal(t, 4, workers[0].MaxConcurrentTasks)
+
+	httpRes := doJSON(t, http.MethodPost, f.workersURL(), verveclient.RegisterWorkerRequest{})
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+}
+
+func TestWorkerHeartbeat(t *testing.T) {
+	f := newFixture(t)
+	running := f.seedRunningTask()
+	f.WorkerRegistry.Register(workertracker.Registration{WorkerID: "worker-1", MaxConcurrentTasks: 4})
+
+	postNoContent(t, f.workerHeartbeatURL("worker-1"), verveclient.WorkerHeartbeatRequest{ActiveTaskIDs: []string{running.ID.String()}})
+
+	workers := f.WorkerRegistry.ListWorkers(time.Minute)
+	require.Len(t, workers, 1)
+	assert.Equal(t, []string{running.ID.String()}, workers[0].ActiveTaskIDs)
+	assert.Equal(t, 1, workers[0].ActiveTasks)
+}
+
+func TestWorkerHeartbeat_Unregistered(t *testing.T) {
+	f := newFixture(t)
+
+	httpRes := doJSON(t, http.MethodPost, f.workerHeartbeatURL("worker-1"), verveclient.WorkerHeartbeatRequest{})
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusNotFound, httpRes.StatusCode)
+}
+
+func TestWorkerHeartbeat_InvalidTaskID(t *testing.T) {
+	f := newFixture(t)
+	f.WorkerRegistry.Register(workertracker.Registration{WorkerID: "worker-1"})
+
+	httpRes := doJSON(t, http.MethodPost, f.workerHeartbeatURL("worker-1"), verveclient.WorkerHeartbeatRequest{ActiveTaskIDs: []string{"bad"}})
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+}
+
+func TestDrain(t *testing.T) {
+	f := newFixture(t)
+	f.WorkerRegistry.RecordPollStart("worker-1", 4, 2)
+	running := f.seedRunningTask()
+	finished := f.seedRunningTask()
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(context.Background(), finished.ID, task.StatusReview))
+
+	req := verveclient.DrainRequest{
+		WorkerID: "worker-1",
+		TaskIDs:  []string{running.ID.String(), finished.ID.String()},
+	}
+	res := testutil.Post[server.Response[verveclient.DrainResponse]](t, f.drainURL(), req)
+	assert.Equal(t, []string{running.ID.String()}, res.Data.Rescheduled)
+
+	stored, err := f.taskRepo.ReadTask(context.Background(), running.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusPending, stored.Status)
+	assert.True(t, stored.Ready, "abandoned task should be picked up again")
+	stored, err = f.taskRepo.ReadTask(context.Background(), finished.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusReview, stored.Status)
+
+	assert.Empty(t, f.WorkerRegistry.ListWorkers(time.Minute))
+}
+
+func TestDrain_InvalidTaskID(t *testing.T) {
+	f := newFixture(t)
+
+	httpRes := doJSON(t, http.MethodPost, f.drainURL(), verveclient.DrainRequest{WorkerID: "worker-1", TaskIDs: []string{"bad"}})
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+}
+
+// --- Agent Permits ---
+
+func TestPermits(t *testing.T) {
+	limiter := permit.NewLimiter(permit.Limits{MaxConcurrent: 1}, time.Minute)
+	f := newFixture(t, agentapi.WithPermitLimiter(limiter))
+	req := verveclient.PermitRequest{WorkerID: "worker-1", Model: "sonnet"}
+
+	res := testutil.Post[server.Response[verveclient.PermitResponse]](t, f.permitsURL(), req)
+	require.True(t, res.Data.Granted)
+	require.NotNil(t, res.Data.Permit)
+	held := res.Data.Permit
+	assert.Equal(t, "worker-1", held.WorkerID)
+
+	res = testutil.Post[server.Response[verveclient.PermitResponse]](t, f.permitsURL(), verveclient.PermitRequest{WorkerID: "worker-2"})
+	assert.False(t, res.Data.Granted)
+	assert.Equal(t, "max concurrent agents reached", res.Data.Reason)
+	assert.Greater(t, res.Data.RetryAfterMs, int64(0))
+
+	renewed := testutil.Put[server.Response[verveclient.Permit]](t, f.permitURL(held.ID), nil)
+	assert.Equal(t, held.ID, renewed.Data.ID)
+
+	testutil.Delete(t, f.permitURL(held.ID))
+	assert.Equal(t, 0, limiter.Held())
+
+	httpRes := doJSON(t, http.MethodPut, f.permitURL(held.ID), nil)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusNotFound, httpRes.StatusCode)
+}
+
+func TestPermits_NotEnforced(t *testing.T) {
+	f := newFixture(t)
+
+	res := testutil.Post[server.Response[verveclient.PermitResponse]](t, f.permitsURL(), verveclient.PermitRequest{WorkerID: "worker-1"})
+	assert.True(t, res.Data.Granted)
+	assert.Nil(t, res.Data.Permit)
+}
+
+func TestPoll_PermitRequired(t *testing.T) {
+	f := newFixture(t, agentapi.WithPermitLimiter(permit.NewLimiter(permit.Limits{MaxConcurrent: 1}, 0)))
+	ctx := context.Background()
+	require.NoError(t, f.RepoStore.UpdateRepoSetupStatus(ctx, f.Repo.ID, "ready"))
+	tsk := task.NewTask(f.Repo.ID.String(), "title", "desc", nil, nil, 0, false, false, "", true)
+	require.NoError(t, f.TaskStore.CreateTask(ctx, tsk))
+
+	res := testutil.Get[server.Response[verveclient.PollResponse]](t, f.pollURL())
+	require.Equal(t, verveclient.WorkTypeTask, res.Data.Type)
+	assert.True(t, res.Data.PermitRequired)
+}
+
 // --- Conversation Agent Endpoints ---
 
 func TestConversationComplete_Success(t *testing.T) {
 	f := newFixture(t)
 	conv := f.seedClaimedConversation()
 
-	req := agentapi.ConversationCompleteRequest{
+	req := verveclient.ConversationCompleteRequest{
 		Success:  true,
 		Response: "Here is my analysis of the codebase.",
 	}
@@ -333,7 +735,7 @@ func TestConversationComplete_Failure(t *testing.T) {
 	f := newFixture(t)
 	conv := f.seedClaimedConversation()
 
-	req := agentapi.ConversationCompleteRequest{
+	req := verveclient.ConversationCompleteRequest{
 		Success: false,
 		Error:   "something went wrong",
 	}
@@ -351,7 +753,7 @@ func TestConversationComplete_InvalidID(t *testing.T) {
 	f := newFixture(t)
 
 	url := f.Server.Address() + "/api/v1/agent/conversations/bad-id/complete"
-	req := agentapi.ConversationCompleteRequest{Success: true, Response: "test"}
+	req := verveclient.ConversationCompleteRequest{Success: true, Response: "test"}
 	httpRes := doJSON(t, http.MethodPost, url, req)
 	defer httpRes.Body.Close()
 	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
@@ -373,7 +775,7 @@ func TestConversationAppendLogs(t *testing.T) {
 	f := newFixture(t)
 	conv := f.seedClaimedConversation()
 
-	req := agentapi.ConversationLogsRequest{
+	req := verveclient.ConversationLogsRequest{
 		Lines: []string{"agent: processing message", "agent: generating response"},
 	}
 	postNoContent(t, f.conversationLogsURL(conv.ID), req)
@@ -410,6 +812,270 @@ func TestPollForStops_Timeout(t *testing.T) {
 	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
 }
 
+type stubDispatchGate struct {
+	paused atomic.Bool
+}
+
+func (g *stubDispatchGate) DispatchPaused(_ context.Context) (bool, error) {
+	return g.paused.Load(), nil
+}
+
+func TestPoll_DispatchPaused(t *testing.T) {
+	gate := &stubDispatchGate{}
+	gate.paused.Store(true)
+	f := newFixture(t, agentapi.WithDispatchGate(gate))
+	require.NoError(t, f.RepoStore.UpdateRepoSetupStatus(context.Background(), f.Repo.ID, "ready"))
+
+	tsk := task.NewTask(f.Repo.ID.String(), "title", "desc", nil, nil, 0, false, false, "", true)
+	require.NoError(t, f.TaskStore.CreateTask(context.Background(), tsk))
+
+	// The poll is held without handing out the pending task.
+	client := &http.Client{Timeout: time.Second}
+	resp, err := client.Get(f.pollURL())
+	if err == nil {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	}
+	status, err := f.TaskStore.ReadTaskStatus(context.Background(), tsk.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, string(task.StatusPending), status)
+
+	gate.paused.Store(false)
+	res := testutil.Get[server.Response[agentapi.PollResponse]](t, f.pollURL())
+	assert.Equal(t, "task", res.Data.Type)
+	assert.Equal(t, tsk.ID, res.Data.Task.ID)
+}
+
+func TestPoll_AnyDispatchGatePaused(t *testing.T) {
+	var queuePaused atomic.Bool
+	queuePaused.Store(true)
+	f := newFixture(t,
+		agentapi.WithDispatchGate(&stubDispatchGate{}),
+		agentapi.WithDispatchGate(agentapi.DispatchGateFunc(func(context.Context) (bool, error) {
+			return queuePaused.Load(), nil
+		})),
+	)
+	require.NoError(t, f.RepoStore.UpdateRepoSetupStatus(context.Background(), f.Repo.ID, "ready"))
+
+	tsk := task.NewTask(f.Repo.ID.String(), "title", "desc", nil, nil, 0, false, false, "", true)
+	require.NoError(t, f.TaskStore.CreateTask(context.Background(), tsk))
+
+	client := &http.Client{Timeout: time.Second}
+	resp, err := client.Get(f.pollURL())
+	if err == nil {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	}
+	status, err := f.TaskStore.ReadTaskStatus(context.Background(), tsk.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, string(task.StatusPending), status)
+
+	queuePaused.Store(false)
+	res := testutil.Get[server.Response[agentapi.PollResponse]](t, f.pollURL())
+	assert.Equal(t, tsk.ID, res.Data.Task.ID)
+}
+
+func TestPoll_PausedRepo(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	require.NoError(t, f.RepoStore.UpdateRepoSetupStatus(ctx, f.Repo.ID, "ready"))
+	require.NoError(t, f.RepoStore.UpdateRepoPaused(ctx, f.Repo.ID, true))
+
+	tsk := task.NewTask(f.Repo.ID.String(), "title", "desc", nil, nil, 0, false, false, "", true)
+	require.NoError(t, f.TaskStore.CreateTask(ctx, tsk))
+
+	// The poll is held without handing out the paused repo's task.
+	client := &http.Client{Timeout: time.Second}
+	resp, err := client.Get(f.pollURL())
+	if err == nil {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	}
+	status, err := f.TaskStore.ReadTaskStatus(ctx, tsk.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, string(task.StatusPending), status)
+
+	require.NoError(t, f.RepoStore.UpdateRepoPaused(ctx, f.Repo.ID, false))
+	res := testutil.Get[server.Response[agentapi.PollResponse]](t, f.pollURL())
+	assert.Equal(t, tsk.ID, res.Data.Task.ID)
+}
+
+func TestPoll_RequiredLabels(t *testing.T) {
+	f := newFixture(t)
+	require.NoError(t, f.RepoStore.UpdateRepoSetupStatus(context.Background(), f.Repo.ID, "ready"))
+
+	tsk := task.NewTask(f.Repo.ID.String(), "title", "desc", nil, nil, 0, false, false, "", true)
+	tsk.RequiredLabels = []string{"gpu"}
+	require.NoError(t, f.TaskStore.CreateTask(context.Background(), tsk))
+
+	// A worker without the label is held without the task.
+	client := &http.Client{Timeout: time.Second}
+	resp, err := client.Get(f.pollURL() + "?labels=linux")
+	if err == nil {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	}
+	status, err := f.TaskStore.ReadTaskStatus(context.Background(), tsk.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, string(task.StatusPending), status)
+
+	res := testutil.Get[server.Response[agentapi.PollResponse]](t, f.pollURL()+"?labels=linux,gpu")
+	assert.Equal(t, "task", res.Data.Type)
+	assert.Equal(t, tsk.ID, res.Data.Task.ID)
+}
+
+func TestPoll_Batch(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	require.NoError(t, f.RepoStore.UpdateRepoSetupStatus(ctx, f.Repo.ID, "ready"))
+	for range 3 {
+		tsk := task.NewTask(f.Repo.ID.String(), "title", "desc", nil, nil, 0, false, false, "", true)
+		require.NoError(t, f.TaskStore.CreateTask(ctx, tsk))
+	}
+
+	res := testutil.Get[server.ResponseList[agentapi.PollResponse]](t, f.pollURL()+"?max=2")
+	require.Len(t, res.Data, 2)
+	assert.NotEqual(t, res.Data[0].Task.ID, res.Data[1].Task.ID)
+
+	// Only what is left is handed out.
+	res = testutil.Get[server.ResponseList[agentapi.PollResponse]](t, f.pollURL()+"?max=5")
+	require.Len(t, res.Data, 1)
+	assert.Equal(t, "task", res.Data[0].Type)
+}
+
+func TestPoll_BatchReleasesClaimsWhenResponseFails(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	require.NoError(t, f.RepoStore.UpdateRepoSetupStatus(ctx, f.Repo.ID, "ready"))
+	conv := f.seedPendingConversation()
+	ok := task.NewTask(f.Repo.ID.String(), "ok", "desc", nil, nil, 0, false, false, "", true)
+	require.NoError(t, f.TaskStore.CreateTask(ctx, ok))
+	// The additional repo doesn't exist, so the task's response can't be
+	// built after it is claimed.
+	missing, err := repo.NewRepo("owner/missing")
+	require.NoError(t, err)
+	broken := task.NewTask(f.Repo.ID.String(), "broken", "desc", nil, nil, 0, false, false, "", true)
+	broken.AdditionalRepoIDs = []string{missing.ID.String()}
+	require.NoError(t, f.TaskStore.CreateTask(ctx, broken))
+
+	resp, err := http.Get(f.pollURL() + "?max=3")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.GreaterOrEqual(t, resp.StatusCode, http.StatusBadRequest)
+
+	// Nothing is left claimed without a worker.
+	for _, id := range []task.TaskID{ok.ID, broken.ID} {
+		status, err := f.TaskStore.ReadTaskStatus(ctx, id.String())
+		require.NoError(t, err)
+		assert.Equal(t, string(task.StatusPending), status)
+	}
+	c, err := f.ConversationStore.ReadConversation(ctx, conv.ID)
+	require.NoError(t, err)
+	assert.Nil(t, c.ClaimedAt)
+	assert.NotNil(t, c.PendingMessage)
+}
+
+func TestPoll_BatchInvalidMax(t *testing.T) {
+	f := newFixture(t)
+
+	resp, err := http.Get(f.pollURL() + "?max=0")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestPoll_IncludesAttachments(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	require.NoError(t, f.RepoStore.UpdateRepoSetupStatus(ctx, f.Repo.ID, "ready"))
+	storage, err := attachment.NewDirStorage(t.TempDir())
+	require.NoError(t, err)
+	f.TaskStore.SetAttachmentStorage(storage, 0)
+
+	tsk := task.NewTask(f.Repo.ID.String(), "title", "desc", nil, nil, 0, false, false, "", true)
+	require.NoError(t, f.TaskStore.CreateTask(ctx, tsk))
+	att, err := f.TaskStore.CreateAttachment(ctx, tsk.ID, &task.Attachment{
+		Filename:    "error.log",
+		ContentType: "text/plain",
+		SizeBytes:   5,
+		UploadedBy:  "alice",
+	}, []byte("boom\n"))
+	require.NoError(t, err)
+
+	res := testutil.Get[server.Response[verveclient.PollResponse]](t, f.pollURL())
+	assert.Equal(t, "task", res.Data.Type)
+	require.Len(t, res.Data.Attachments, 1)
+	assert.Equal(t, "error.log", res.Data.Attachments[0].Filename)
+
+	resp, err := http.Get(fmt.Sprintf("%s/api/v1/agent/tasks/%s/attachments/%d", f.Server.Address(), tsk.ID, att.ID))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "boom\n", string(b))
+}
+
+func uploadArtifact(t *testing.T, url, name, attempt string, data []byte) *http.Response {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	require.NoError(t, w.WriteField("attempt", attempt))
+	require.NoError(t, w.WriteField("name", name))
+	part, err := w.CreateFormFile("file", "upload")
+	require.NoError(t, err)
+	_, err = part.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	res, err := http.Post(url, w.FormDataContentType(), &body)
+	require.NoError(t, err)
+	return res
+}
+
+func TestTaskUploadArtifact(t *testing.T) {
+	f := newFixture(t)
+	storage, err := attachment.NewDirStorage(t.TempDir())
+	require.NoError(t, err)
+	f.TaskStore.SetAttachmentStorage(storage, 0)
+	tsk := f.seedRunningTask()
+
+	res := uploadArtifact(t, f.taskArtifactsURL(tsk.ID), "coverage/summary.txt", "1", []byte("total: 87%\n"))
+	defer res.Body.Close()
+	require.Equal(t, http.StatusCreated, res.StatusCode)
+	var created server.Response[task.Artifact]
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&created))
+	assert.Equal(t, "coverage/summary.txt", created.Data.Name)
+	assert.Equal(t, 1, created.Data.Attempt)
+	assert.Equal(t, "text/plain", created.Data.ContentType)
+	assert.Equal(t, int64(11), created.Data.SizeBytes)
+
+	artifacts, err := f.TaskStore.ListArtifacts(context.Background(), tsk.ID, 1)
+	require.NoError(t, err)
+	require.Len(t, artifacts, 1)
+
+	res = uploadArtifact(t, f.taskArtifactsURL(tsk.ID), "coverage/summary.txt", "1", []byte("again"))
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusConflict, res.StatusCode, "expected a duplicate name to conflict")
+
+	res = uploadArtifact(t, f.taskArtifactsURL(tsk.ID), "report.xml", "2", []byte("<testsuites/>"))
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusNotFound, res.StatusCode, "expected an attempt the task has not reached to be rejected")
+
+	res = uploadArtifact(t, f.taskArtifactsURL(tsk.ID), "../escape.txt", "1", []byte("x"))
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func TestTaskUploadArtifact_NotConfigured(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedRunningTask()
+
+	res := uploadArtifact(t, f.taskArtifactsURL(tsk.ID), "report.xml", "1", []byte("<testsuites/>"))
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+}
+
 func TestPoll_ReturnsConversation(t *testing.T) {
 	f := newFixture(t)
 	// Need to mark the repo as ready for setup tasks
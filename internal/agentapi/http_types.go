@@ -1,19 +1,22 @@
 package agentapi
 
 import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/cohesivestack/valgo"
 
 	"github.com/vervesh/verve/internal/conversation"
 	"github.com/vervesh/verve/internal/epic"
 	"github.com/vervesh/verve/internal/repo"
 	"github.com/vervesh/verve/internal/task"
+	"github.com/vervesh/verve/pkg/verveclient"
 )
 
 // StopSignal identifies an entity that should be stopped.
-type StopSignal struct {
-	EntityType string `json:"entity_type"` // "task" or "epic"
-	EntityID   string `json:"entity_id"`
-}
+type StopSignal = verveclient.StopSignal
 
 // PollResponse is the discriminated union returned by the unified poll endpoint.
 type PollResponse struct {
@@ -42,14 +45,57 @@ type PollResponse struct {
 	RepoSummary      string `json:"repo_summary,omitempty"`
 	RepoExpectations string `json:"repo_expectations,omitempty"`
 	RepoTechStack    string `json:"repo_tech_stack,omitempty"`
+
+	// Commit and PR conventions the repo's completion validations enforce,
+	// one per line (present when Type == "task" and the repo has any)
+	PRConventions string `json:"pr_conventions,omitempty"`
+
+	// Epic planning context relevant to the task (present when the task
+	// belongs to an epic with a planning summary)
+	EpicContext string `json:"epic_context,omitempty"`
+
+	// Planning transcript so far, for the agent to continue from (present
+	// when Type == "epic" and earlier sessions logged anything)
+	EpicTranscript string `json:"epic_transcript,omitempty"`
+
+	// Generation of the repo's cached agent workspace (present when Type ==
+	// "task"). Workers discard cached workspaces from older generations.
+	WorkspaceCacheGeneration int `json:"workspace_cache_generation,omitempty"`
+
+	// Full names of the other repos a multi-repo task changes (present when
+	// Type == "task")
+	AdditionalRepos []string `json:"additional_repos,omitempty"`
+
+	// Whether the agent runs in shadow mode (present when Type == "task"):
+	// it records its diff instead of pushing or opening pull requests.
+	ShadowMode bool `json:"shadow_mode,omitempty"`
+
+	// Change plan for the agent (present when Type == "task" and the task
+	// has a proposal): for a plan-only task the previous proposal to revise,
+	// otherwise the approved proposal to implement.
+	Proposal string `json:"proposal,omitempty"`
+
+	// Branch the task branches from and opens its PR against instead of the
+	// repo's default branch (present when the task belongs to an epic with a
+	// shared integration branch)
+	BaseBranch string `json:"base_branch,omitempty"`
+
+	// Longest the agent may run, in seconds, before the worker kills it
+	// (present when Type == "task" and the task or its repo sets a max
+	// runtime)
+	MaxRuntimeSeconds int `json:"max_runtime_seconds,omitempty"`
+
+	// Files attached to the task (present when Type == "task" and the task
+	// has attachments)
+	Attachments []*task.Attachment `json:"attachments,omitempty"`
+
+	// Whether the worker must hold an agent permit while the agent runs
+	// (present when Type == "task" and agent permits are enabled)
+	PermitRequired bool `json:"permit_required,omitempty"`
 }
 
 // Setup holds the fields for a repository setup scan work item.
-type Setup struct {
-	TaskID   string `json:"task_id"`
-	RepoID   string `json:"repo_id"`
-	FullName string `json:"full_name"`
-}
+type Setup = verveclient.Setup
 
 // TaskIDRequest captures the :id path parameter for task agent endpoints.
 type TaskIDRequest struct {
@@ -60,33 +106,213 @@ func (r TaskIDRequest) Validate() error {
 	return valgo.In("params", valgo.Is(task.TaskIDValidator(r.ID, "id"))).ToError()
 }
 
+// TaskAttachmentRequest captures the :id and :attachment_id path parameters
+// for downloading a task attachment.
+type TaskAttachmentRequest struct {
+	ID           string `param:"id" json:"-"`
+	AttachmentID string `param:"attachment_id" json:"-"`
+}
+
+func (r TaskAttachmentRequest) Validate() error {
+	v := valgo.In("params", valgo.Is(task.TaskIDValidator(r.ID, "id")))
+	n, err := strconv.ParseInt(r.AttachmentID, 10, 64)
+	if err != nil || n <= 0 {
+		v = v.AddErrorMessage("attachment_id", "must be a positive integer")
+	}
+	return v.ToError()
+}
+
 // TaskLogsRequest is the request for appending task logs.
 type TaskLogsRequest struct {
-	ID      string   `param:"id" json:"-"`
-	Logs    []string `json:"logs"`
-	Attempt int      `json:"attempt"`
+	ID string `param:"id" json:"-"`
+	verveclient.TaskLogsRequest
 }
 
 func (r TaskLogsRequest) Validate() error {
 	return valgo.In("params", valgo.Is(task.TaskIDValidator(r.ID, "id"))).ToError()
 }
 
+// Limits on agent events accepted in a single request.
+const (
+	maxAgentEventsPerRequest = 500
+	maxAgentEventPayloadSize = 64 * 1024
+)
+
+// TaskEventsRequest is the request for appending structured agent events.
+type TaskEventsRequest struct {
+	ID string `param:"id" json:"-"`
+	verveclient.TaskEventsRequest
+}
+
+func (r TaskEventsRequest) Validate() error {
+	v := valgo.In("params", valgo.Is(task.TaskIDValidator(r.ID, "id")))
+	if len(r.Events) > maxAgentEventsPerRequest {
+		v = v.AddErrorMessage("events", fmt.Sprintf("must not contain more than %d events", maxAgentEventsPerRequest))
+	}
+	for _, e := range r.Events {
+		if !task.ValidAgentEventType(task.AgentEventType(e.Type)) {
+			v = v.AddErrorMessage("events", "type must be pr_created, pr_updated, branch_pushed, status, no_changes, cost, tool_call, plan or step")
+			break
+		}
+		if len(e.Payload) > maxAgentEventPayloadSize {
+			v = v.AddErrorMessage("events", fmt.Sprintf("payload must not be larger than %d bytes", maxAgentEventPayloadSize))
+			break
+		}
+		if !isEmptyPayload(e.Payload) && !isJSONObject(e.Payload) {
+			v = v.AddErrorMessage("events", "payload must be a JSON object")
+			break
+		}
+	}
+	return v.ToError()
+}
+
+// isEmptyPayload reports whether an event was sent without a payload, which
+// JSON encoders may write as null.
+func isEmptyPayload(raw json.RawMessage) bool {
+	return len(raw) == 0 || strings.TrimSpace(string(raw)) == "null"
+}
+
+func isJSONObject(raw json.RawMessage) bool {
+	var obj map[string]json.RawMessage
+	return json.Unmarshal(raw, &obj) == nil && obj != nil
+}
+
+// maxIdempotencyKeyLen caps the idempotency key of an agent callback.
+const maxIdempotencyKeyLen = 255
+
 // TaskCompleteRequest is the request for completing a task.
 type TaskCompleteRequest struct {
-	ID             string  `param:"id" json:"-"`
-	Success        bool    `json:"success"`
-	PullRequestURL string  `json:"pull_request_url"`
-	PRNumber       int     `json:"pr_number"`
-	BranchName     string  `json:"branch_name"`
-	Error       string  `json:"error"`
-	AgentStatus string  `json:"agent_status"`
-	CostUSD     float64 `json:"cost_usd"`
-	NoChanges   bool    `json:"no_changes"`
-	Retryable   bool    `json:"retryable"`
+	ID string `param:"id" json:"-"`
+	verveclient.TaskCompleteRequest
 }
 
+// Limits on what a completing run can report.
+const (
+	// maxShadowDiffSize caps the diff a shadow-mode run can report.
+	maxShadowDiffSize = 2 * 1024 * 1024
+	// maxProposalSize caps the text of a plan-only run's proposal.
+	maxProposalSize = 256 * 1024
+)
+
 func (r TaskCompleteRequest) Validate() error {
-	return valgo.In("params", valgo.Is(task.TaskIDValidator(r.ID, "id"))).ToError()
+	v := valgo.In("params", valgo.Is(task.TaskIDValidator(r.ID, "id")))
+	if r.Attempt < 0 {
+		v = v.AddErrorMessage("attempt", "must not be negative")
+	}
+	if len(r.ShadowDiff) > maxShadowDiffSize {
+		v = v.AddErrorMessage("shadow_diff", fmt.Sprintf("must not be larger than %d bytes", maxShadowDiffSize))
+	}
+	if p := r.Proposal; p != nil {
+		if strings.TrimSpace(p.Summary) == "" {
+			v = v.AddErrorMessage("proposal.summary", "must not be empty")
+		}
+		size := len(p.Summary) + len(p.Approach)
+		for _, f := range p.Files {
+			size += len(f.Path) + len(f.Change)
+		}
+		for _, risk := range p.Risks {
+			size += len(risk)
+		}
+		if size > maxProposalSize {
+			v = v.AddErrorMessage("proposal", fmt.Sprintf("must not be larger than %d bytes", maxProposalSize))
+		}
+	}
+	return v.ToError()
+}
+
+// maxDrainTasks caps the number of tasks a worker can hand back in one drain.
+const maxDrainTasks = 100
+
+// DrainRequest is the request for draining a worker on shutdown.
+type DrainRequest struct {
+	verveclient.DrainRequest
+}
+
+func (r DrainRequest) Validate() error {
+	if len(r.TaskIDs) > maxDrainTasks {
+		return valgo.AddErrorMessage("task_ids", fmt.Sprintf("at most %d tasks can be drained at once", maxDrainTasks)).ToError()
+	}
+	validators := make([]valgo.Validator, len(r.TaskIDs))
+	for i, id := range r.TaskIDs {
+		validators[i] = task.TaskIDValidator(id, fmt.Sprintf("task_ids[%d]", i))
+	}
+	return valgo.Is(validators...).ToError()
+}
+
+// DrainResponse is the response for the drain endpoint.
+type DrainResponse = verveclient.DrainResponse
+
+// Limits on what a worker reports about itself.
+const (
+	maxWorkerIDLen       = 100
+	maxWorkerMetadataLen = 255
+	maxWorkerActiveTasks = 100
+)
+
+// RegisterWorkerRequest is the request for registering a worker.
+type RegisterWorkerRequest struct {
+	verveclient.RegisterWorkerRequest
+}
+
+func (r RegisterWorkerRequest) Validate() error {
+	v := valgo.Is(
+		valgo.String(r.WorkerID, "worker_id").Not().Blank().MaxLength(maxWorkerIDLen),
+		valgo.String(r.Hostname, "hostname").MaxLength(maxWorkerMetadataLen),
+		valgo.String(r.Version, "version").MaxLength(maxWorkerMetadataLen),
+		valgo.Int(r.MaxConcurrentTasks, "max_concurrent_tasks").GreaterOrEqualTo(0),
+	)
+	if err := task.ValidateLabels(r.Labels); err != nil {
+		v = v.AddErrorMessage("labels", err.Error())
+	}
+	return v.ToError()
+}
+
+// WorkerHeartbeatRequest is the request for a worker heartbeat.
+type WorkerHeartbeatRequest struct {
+	WorkerID string `param:"worker_id" json:"-"`
+	verveclient.WorkerHeartbeatRequest
+}
+
+func (r WorkerHeartbeatRequest) Validate() error {
+	v := valgo.In("params", valgo.Is(valgo.String(r.WorkerID, "worker_id").Not().Blank().MaxLength(maxWorkerIDLen)))
+	if len(r.ActiveTaskIDs) > maxWorkerActiveTasks {
+		v = v.AddErrorMessage("active_task_ids", fmt.Sprintf("must not contain more than %d tasks", maxWorkerActiveTasks))
+	}
+	for i, id := range r.ActiveTaskIDs {
+		v = v.Is(task.TaskIDValidator(id, fmt.Sprintf("active_task_ids[%d]", i)))
+	}
+	return v.ToError()
+}
+
+// PermitRequest is the request for acquiring an agent permit.
+type PermitRequest struct {
+	verveclient.PermitRequest
+}
+
+func (r PermitRequest) Validate() error {
+	v := valgo.Is(
+		valgo.String(r.WorkerID, "worker_id").Not().Blank().MaxLength(maxWorkerIDLen),
+		valgo.String(r.Model, "model").MaxLength(maxWorkerMetadataLen),
+	)
+	if r.TaskID != "" {
+		v = v.Is(task.TaskIDValidator(r.TaskID, "task_id"))
+	}
+	return v.ToError()
+}
+
+// PermitResponse is the response for acquiring an agent permit.
+type PermitResponse = verveclient.PermitResponse
+
+// Permit is an agent permit held by a worker.
+type Permit = verveclient.Permit
+
+// PermitIDRequest captures the :permit_id path parameter.
+type PermitIDRequest struct {
+	PermitID string `param:"permit_id" json:"-"`
+}
+
+func (r PermitIDRequest) Validate() error {
+	return valgo.In("params", valgo.Is(valgo.String(r.PermitID, "permit_id").Not().Blank().MaxLength(maxWorkerIDLen))).ToError()
 }
 
 // EpicIDRequest captures the :id path parameter for epic agent endpoints.
@@ -110,10 +336,24 @@ func (r EpicCompleteRequest) Validate() error {
 	return valgo.In("params", valgo.Is(epic.EpicIDValidator(r.ID, "id"))).ToError()
 }
 
+// EpicCostRequest is the request for reporting epic planning cost.
+type EpicCostRequest struct {
+	ID string `param:"id" json:"-"`
+	verveclient.EpicCostRequest
+}
+
+func (r EpicCostRequest) Validate() error {
+	v := valgo.In("params", valgo.Is(epic.EpicIDValidator(r.ID, "id")))
+	if r.CostUSD < 0 {
+		v = v.AddErrorMessage("cost_usd", "must not be negative")
+	}
+	return v.ToError()
+}
+
 // SessionLogRequest is the request body for appending session log entries.
 type SessionLogRequest struct {
-	ID    string   `param:"id" json:"-"`
-	Lines []string `json:"lines"`
+	ID string `param:"id" json:"-"`
+	verveclient.SessionLogRequest
 }
 
 func (r SessionLogRequest) Validate() error {
@@ -157,10 +397,8 @@ func (r ConversationIDRequest) Validate() error {
 
 // ConversationCompleteRequest is the request for completing a conversation response.
 type ConversationCompleteRequest struct {
-	ID       string `param:"id" json:"-"`
-	Success  bool   `json:"success"`
-	Response string `json:"response"`
-	Error    string `json:"error"`
+	ID string `param:"id" json:"-"`
+	verveclient.ConversationCompleteRequest
 }
 
 func (r ConversationCompleteRequest) Validate() error {
@@ -169,8 +407,8 @@ func (r ConversationCompleteRequest) Validate() error {
 
 // ConversationLogsRequest is the request for appending conversation logs.
 type ConversationLogsRequest struct {
-	ID    string   `param:"id" json:"-"`
-	Lines []string `json:"lines"`
+	ID string `param:"id" json:"-"`
+	verveclient.ConversationLogsRequest
 }
 
 func (r ConversationLogsRequest) Validate() error {
@@ -0,0 +1,211 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/joshjon/kit/log"
+
+	"github.com/vervesh/verve/internal/adminapi"
+	"github.com/vervesh/verve/internal/sqlite"
+	litemigrations "github.com/vervesh/verve/internal/sqlite/migrations"
+)
+
+const (
+	defaultBackupInterval = 24 * time.Hour
+	defaultBackupKeep     = 7
+	// backupPrefix and backupExt frame the timestamp in backup file names.
+	// Only files named like this are ever pruned from the backup directory.
+	backupPrefix = "verve-"
+	backupExt    = ".db"
+)
+
+// BackupConfig holds the configuration for the backup command.
+type BackupConfig struct {
+	SQLiteDir string // Directory of the SQLite DB file to back up
+	Output    string // Path the snapshot is written to, which must not exist (default: verve-<timestamp>.db)
+}
+
+// RestoreConfig holds the configuration for the restore command.
+type RestoreConfig struct {
+	SQLiteDir string // Directory of the SQLite DB file to replace
+	Input     string // Snapshot to restore
+}
+
+// Backup writes a consistent snapshot of the file-backed SQLite database to
+// cfg.Output. It is safe to run while the server is running.
+func Backup(ctx context.Context, logger log.Logger, cfg BackupConfig) error {
+	if cfg.SQLiteDir == "" {
+		return errors.New("SQLITE_DIR is required: backups are only supported for file-backed SQLite")
+	}
+	if cfg.Output == "" {
+		cfg.Output = backupPrefix + time.Now().UTC().Format(adminapi.BackupTimeFormat) + backupExt
+	}
+	// OpenFile would create an empty database in its place.
+	if _, err := os.Stat(filepath.Join(cfg.SQLiteDir, "verve.db")); err != nil {
+		return fmt.Errorf("find database: %w", err)
+	}
+	db, err := sqlite.OpenFile(ctx, cfg.SQLiteDir, "verve")
+	if err != nil {
+		return fmt.Errorf("open sqlite: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	start := time.Now()
+	if err := db.Backup(ctx, cfg.Output); err != nil {
+		return err
+	}
+	info, err := os.Stat(cfg.Output)
+	if err != nil {
+		return err
+	}
+	logger.Info("backed up database", "backup.path", cfg.Output, "backup.size_bytes", info.Size(), "backup.duration", time.Since(start))
+	return nil
+}
+
+// Restore replaces the file-backed SQLite database with the snapshot at
+// cfg.Input. Every server using the database must be stopped first. A
+// snapshot from an older release is migrated on the next startup or by
+// `verve migrate`; one from a newer release is refused.
+func Restore(ctx context.Context, logger log.Logger, cfg RestoreConfig) error {
+	if cfg.SQLiteDir == "" {
+		return errors.New("SQLITE_DIR is required: restores are only supported for file-backed SQLite")
+	}
+	if cfg.Input == "" {
+		return errors.New("an input path is required")
+	}
+	status, err := sqlite.RestoreFile(ctx, cfg.SQLiteDir, "verve", cfg.Input, litemigrations.FS)
+	if err != nil {
+		return err
+	}
+	logger.Info("restored database", "backup.path", cfg.Input, "sqlite.dir", cfg.SQLiteDir, "migration.version", status.Current)
+	if len(status.Pending) > 0 {
+		logger.Info("restored snapshot has pending migrations; they are applied on startup, or run `verve migrate`",
+			"migration.target_version", status.Latest(),
+			"migration.pending", len(status.Pending),
+		)
+	}
+	return nil
+}
+
+// Backup writes a snapshot of the database to the backup directory and
+// removes the oldest backups beyond the number kept.
+func (j *jobs) Backup(ctx context.Context) (adminapi.BackupResult, error) {
+	res := adminapi.BackupResult{Removed: []string{}}
+	if j.s.fileDB == nil {
+		return res, adminapi.ErrBackupNotSupported
+	}
+	if j.backupDir == "" {
+		return res, adminapi.ErrBackupDirNotConfigured
+	}
+	j.backupMu.Lock()
+	defer j.backupMu.Unlock()
+
+	if err := os.MkdirAll(j.backupDir, 0o755); err != nil {
+		return res, fmt.Errorf("create backup dir: %w", err)
+	}
+	res.Path = filepath.Join(j.backupDir, backupPrefix+time.Now().UTC().Format(adminapi.BackupTimeFormat)+backupExt)
+	if err := j.s.fileDB.Backup(ctx, res.Path); err != nil {
+		return res, err
+	}
+	info, err := os.Stat(res.Path)
+	if err != nil {
+		return res, err
+	}
+	res.SizeBytes = info.Size()
+
+	removed, err := pruneBackups(j.backupDir, j.backupKeep)
+	if err != nil {
+		return res, fmt.Errorf("prune backups: %w", err)
+	}
+	res.Removed = append(res.Removed, removed...)
+	return res, nil
+}
+
+// Snapshot writes a consistent snapshot of the database to w. The snapshot
+// is made in a temporary file first, so w gets nothing if it fails.
+func (j *jobs) Snapshot(ctx context.Context, w io.Writer) error {
+	if j.s.fileDB == nil {
+		return adminapi.ErrBackupNotSupported
+	}
+	dir, err := os.MkdirTemp("", "verve-snapshot-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "verve"+backupExt)
+	if err := j.s.fileDB.Backup(ctx, path); err != nil {
+		return err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// pruneBackups removes the oldest backups in dir until keep remain and
+// returns their paths. keep < 0 keeps every backup.
+func pruneBackups(dir string, keep int) ([]string, error) {
+	if keep < 0 {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var backups []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.Type().IsRegular() && strings.HasPrefix(name, backupPrefix) && strings.HasSuffix(name, backupExt) {
+			backups = append(backups, name)
+		}
+	}
+	if len(backups) <= keep {
+		return nil, nil
+	}
+	// Timestamps in the names sort oldest first.
+	sort.Strings(backups)
+	var removed []string
+	for _, name := range backups[:len(backups)-keep] {
+		path := filepath.Join(dir, name)
+		if err := os.Remove(path); err != nil {
+			return removed, err
+		}
+		removed = append(removed, path)
+	}
+	return removed, nil
+}
+
+func backgroundBackup(ctx context.Context, logger log.Logger, s stores, j *jobs, interval time.Duration) {
+	logger = logger.With("component", "backup")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !s.leader.IsLeader() {
+				continue
+			}
+			res, err := j.Backup(ctx)
+			if err != nil {
+				logger.Error("failed to back up database", "error", err)
+				continue
+			}
+			logger.Info("backed up database", "backup.path", res.Path, "backup.size_bytes", res.SizeBytes, "backup.removed", len(res.Removed))
+		}
+	}
+}
@@ -1,26 +1,105 @@
 package app
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/vervesh/verve/internal/permit"
 	"github.com/vervesh/verve/internal/setting"
 )
 
 // Config holds the API server configuration.
 type Config struct {
+	Version                  string // Server version reported by the capabilities endpoint
 	Port                     int
+	AgentGRPCPort            int    // Port the agent plane's gRPC service listens on (0 = disabled; workers use REST)
+	AgentGRPCTLSCert         string // PEM certificate the agent gRPC service presents; with AgentGRPCTLSKey enables TLS (empty = plaintext)
+	AgentGRPCTLSKey          string // PEM private key of AgentGRPCTLSCert
 	UI                       bool
 	SQLiteDir                string         // Directory for SQLite DB file; if empty, uses in-memory
 	TursoDSN                 string         // Turso/libSQL DSN (e.g. "libsql://db-name.turso.io?authToken=...")
 	EncryptionKey            string         // Hex-encoded 32-byte key for encrypting secrets at rest
+	PreviousEncryptionKeys   []string       // Hex-encoded keys EncryptionKey was rotated from, to read secrets written under them
+	KeyProvider              string         // Provider wrapping the data keys secrets are encrypted with: env (default, uses EncryptionKey), awskms, gcpkms or vault
+	KeyProviderKeyID         string         // AWS KMS key ID or ARN, GCP KMS CryptoKey resource name, or Vault transit key name
+	AWSRegion                string         // Region of the AWS KMS key
+	AWSAccessKeyID           string         // Credentials for AWS KMS
+	AWSSecretAccessKey       string
+	AWSSessionToken          string
+	GCPAccessToken           string         // OAuth access token for GCP KMS; if empty, fetched from the metadata server
+	VaultAddress             string         // Vault server address for the vault key provider
+	VaultToken               string
+	VaultTransitMount        string         // Vault transit secrets engine mount (default: transit)
+	SecretsBackend           string         // Secrets manager secrets can be read from: vault, awssecretsmanager or gcpsecretmanager
+	VaultKVMount             string         // Vault KV v2 secrets engine mount for the vault secrets backend (default: secret)
+	EncryptionKeySecret      string         // Secret holding the hex encryption key, followed by any keys it was rotated from; replaces EncryptionKey
+	GitHubTokenSecret        string         // Secret holding the global GitHub token; replaces the token stored in the database
+	GitHubTokenSecretTTL     time.Duration  // How long the GitHub token read from the secrets manager is cached (default: 5m)
 	GitHubInsecureSkipVerify bool           // Disable TLS certificate verification for GitHub API calls
 	CorsOrigins              []string
 	TaskTimeout              time.Duration // How long before a running task with no heartbeat is considered stale (default: 5m)
 	LogRetention             time.Duration // How long to keep task logs before deleting them (0 = keep forever)
+	LogBatchInterval         time.Duration // How long log appends wait to be combined into one insert (default: 50ms, 0 = write each on its own)
+	TaskTrashRetention       time.Duration // How long deleted tasks can be restored from the trash before they are purged (0 = delete right away)
+	TaskArchiveAfter         time.Duration // How long tasks stay merged or closed before they are archived (0 = never archive)
 	ConversationRetention    time.Duration // How long before active conversations are auto-archived (default: 7 days, 0 = keep forever)
+	SyncInterval             time.Duration // How often PR status is synced from GitHub (default: 30s)
+	MergeMethod              string        // How PRs merged from Verve are merged on GitHub: merge, squash (default) or rebase
+	ReapInterval             time.Duration // How often stale tasks, epics and conversations are timed out (default: 1m)
+	AdminToken               string        // Bearer token for /api/v1/admin endpoints; admin endpoints are disabled when empty
+	RequireAuth              bool          // Require a user token with a sufficient role on the endpoints the UI uses; the admin token acts as an admin
+	SessionTTL               time.Duration // How long a single sign-on session lasts (default: 12h)
+	MCPToken                 string        // Bearer token for the /api/v1/mcp endpoints; the MCP server is disabled when empty
+	MCPRepos                 []string      // Repo full names (owner/name) MCP clients may access; empty allows every repo
+	APIV1Sunset              time.Time     // When the v1 task endpoints are removed, announced in their Sunset header (zero = not scheduled)
+	AutoMigrate              bool          // Apply pending migrations on startup; when false, startup fails if migrations are pending
+	MigrateLockTimeout       time.Duration // How long a migration waits for the database write lock (file-backed SQLite only, 0 = driver default)
+	CheckpointInterval       time.Duration // How often the SQLite WAL is checkpointed and truncated (file-backed SQLite only, 0 = disabled)
+	EventBus                 string        // How task events reach other replicas' SSE and WebSocket clients: memory (default, they don't) or database
+	BackupDir                string        // Directory the leader writes scheduled database backups to (file-backed SQLite only, empty = disabled)
+	BackupInterval           time.Duration // How often scheduled backups are taken (default: 24h)
+	BackupKeep               int           // Scheduled backups kept before the oldest are removed (0 = default of 7, negative = keep all)
+	AttachmentStorage        string        // Where task attachment contents are stored: dir (default) or s3
+	AttachmentDir            string        // Directory for dir attachment storage (default: <SQLiteDir>/attachments; attachments are disabled without either)
+	AttachmentS3Bucket       string        // Bucket for s3 attachment storage; credentials and region are the AWS_* settings
+	AttachmentS3Prefix       string        // Key prefix for attachments in the bucket
+	AttachmentS3Endpoint     string        // Endpoint of an S3-compatible service such as MinIO (empty = AWS S3)
+	AttachmentMaxSize        int64         // Largest attachment accepted, in bytes (0 = default of 10 MiB)
+	ArtifactRetention        time.Duration // How long to keep agent artifacts before deleting them (0 = until the task's logs are deleted)
+	ProvenanceScan           bool          // Scan agent PR diffs for license headers and large verbatim blocks; flagged PRs need acknowledgment before merge
+	ProvenanceScanner        string        // Optional external scanner command; receives the PR diff on stdin and prints JSON findings
+	ProvenanceBlockLines     int           // Contiguous added lines flagged as a large verbatim block (0 = default, negative = disabled)
+	SelfReview               bool          // Review agent PRs for acceptance criteria coverage, leftovers and diff size; the report is posted as a PR comment
+	SelfReviewMaxFiles       int           // Files a PR may change before self-review flags it (0 = default, negative = disabled)
+	SelfReviewMaxFileLines   int           // Changed lines in a single file before self-review flags it (0 = default, negative = disabled)
+	SelfReviewMaxLines       int           // Changed lines in a PR before self-review flags it (0 = default, negative = disabled)
+	CostAnomalyDetection     bool          // Watch for runaway task cost and hourly spend spikes, alerting notification sinks subscribed to cost_anomaly
+	CostAnomalyTaskFactor    float64       // Multiple of a repo's p95 task cost that flags an unfinished task (0 = default)
+	CostAnomalySpikeFactor   float64       // Multiple of the trailing 7-day average hourly spend that flags the last hour (0 = default)
+	CostAnomalyMinHourlyUSD  float64       // Hourly spend below which spikes are ignored (0 = default)
+	CostAnomalyAutoPause     bool          // Pause dispatching new work when an anomaly is detected until acknowledged via the admin API
+	AgentMaxConcurrent       int           // Task agents allowed to run at once across all workers (0 = unlimited)
+	AgentTokensPerMinute     int           // Tokens task agents may be granted per minute across all workers (0 = unlimited)
+	AgentRunTokens           int           // Tokens each task agent run is expected to use, drawn from the tokens-per-minute limits (0 = default)
+	AgentModelLimits         string        // Per-model limits as MODEL:MAX_CONCURRENT[:TOKENS_PER_MINUTE], comma-separated
 	Models                   []setting.ModelOption // Available Claude models; if empty, uses DefaultModels
 }
 
+// AgentPermitLimits returns the limits on task agents workers must hold a
+// permit within. No limits are enforced unless one is set.
+func (c Config) AgentPermitLimits() (permit.Limits, error) {
+	models, err := permit.ParseModelLimits(c.AgentModelLimits)
+	if err != nil {
+		return permit.Limits{}, fmt.Errorf("AGENT_MODEL_LIMITS: %w", err)
+	}
+	return permit.Limits{
+		MaxConcurrent:   c.AgentMaxConcurrent,
+		TokensPerMinute: c.AgentTokensPerMinute,
+		RunTokens:       c.AgentRunTokens,
+		Models:          models,
+	}, nil
+}
+
 // EffectiveModels returns the configured models or the default set.
 func (c Config) EffectiveModels() []setting.ModelOption {
 	if len(c.Models) > 0 {
@@ -0,0 +1,101 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/joshjon/kit/log"
+
+	"github.com/vervesh/verve/internal/sqlite"
+)
+
+// ExportConfig holds the configuration for the export command.
+type ExportConfig struct {
+	SQLiteDir string // Directory for SQLite DB file
+	TursoDSN  string // Turso/libSQL DSN
+	Output    string // Directory the export is written to
+}
+
+// ImportConfig holds the configuration for the import command.
+type ImportConfig struct {
+	SQLiteDir   string        // Directory for SQLite DB file
+	TursoDSN    string        // Turso/libSQL DSN
+	Input       string        // Directory holding the export
+	LockTimeout time.Duration // How long migrating the target waits for the write lock (0 = driver default)
+}
+
+// Export writes the data of the configured database to cfg.Output in a
+// backend-neutral format that Import loads into any backend. The database
+// must be fully migrated so the export matches the schema this release
+// imports into.
+func Export(ctx context.Context, logger log.Logger, cfg ExportConfig) error {
+	if cfg.SQLiteDir == "" && cfg.TursoDSN == "" {
+		return errors.New("SQLITE_DIR or TURSO_DSN is required")
+	}
+	if cfg.Output == "" {
+		return errors.New("an output directory is required")
+	}
+
+	d := resolveDatabase(logger, cfg.SQLiteDir, cfg.TursoDSN)
+	opened, err := d.open(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = opened.close() }()
+
+	status, err := checkMigrationStatus(ctx, opened.conn)
+	if err != nil {
+		return err
+	}
+	if len(status.Pending) > 0 {
+		return fmt.Errorf("%w: run `verve migrate` before exporting", sqlite.ErrPendingMigrations)
+	}
+
+	m, err := sqlite.Export(ctx, opened.db, cfg.Output, status.Current)
+	if err != nil {
+		return err
+	}
+	for _, t := range m.Tables {
+		logger.Info("exported table", "export.table", t.Table, "export.rows", t.Rows)
+	}
+	logger.Info("exported database", "export.dir", cfg.Output, "migration.version", m.SchemaVersion)
+	return nil
+}
+
+// Import loads an export written by Export into the configured database,
+// migrating it first. The target must not hold any data yet.
+func Import(ctx context.Context, logger log.Logger, cfg ImportConfig) error {
+	if cfg.SQLiteDir == "" && cfg.TursoDSN == "" {
+		return errors.New("SQLITE_DIR or TURSO_DSN is required")
+	}
+	if cfg.Input == "" {
+		return errors.New("an input directory is required")
+	}
+
+	d := resolveDatabase(logger, cfg.SQLiteDir, cfg.TursoDSN)
+	opened, err := d.open(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = opened.close() }()
+
+	if err := migrateOnStartup(ctx, logger, opened.conn, d, true, cfg.LockTimeout); err != nil {
+		return err
+	}
+	status, err := checkMigrationStatus(ctx, opened.conn)
+	if err != nil {
+		return err
+	}
+
+	m, err := sqlite.Import(ctx, opened.db, cfg.Input, status.Current)
+	if err != nil {
+		return err
+	}
+	for _, t := range m.Tables {
+		logger.Info("imported table", "export.table", t.Table, "export.rows", t.Rows)
+	}
+	logger.Info("imported database", "export.dir", cfg.Input, "migration.version", m.SchemaVersion)
+	return nil
+}
@@ -0,0 +1,643 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joshjon/kit/log"
+
+	"github.com/vervesh/verve/internal/adminapi"
+	"github.com/vervesh/verve/internal/github"
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/setting"
+	"github.com/vervesh/verve/internal/task"
+)
+
+const (
+	defaultSyncInterval        = 30 * time.Second
+	defaultReapInterval        = 1 * time.Minute
+	defaultTaskTimeout         = 5 * time.Minute
+	defaultEpicTimeout         = 15 * time.Minute
+	defaultConversationTimeout = 15 * time.Minute
+	settingRefreshInterval     = 10 * time.Second
+	// syncTick is how often the sync job checks which repos are due, which
+	// bounds how closely sync intervals are kept.
+	syncTick = 5 * time.Second
+)
+
+// jobs runs the singleton background jobs. Each job runs on a schedule on the
+// leader replica and can also be triggered on demand through the admin API.
+// Runs of the same job are serialized so a manual trigger never overlaps a
+// scheduled run.
+type jobs struct {
+	s            stores
+	logger       log.Logger
+	taskTimeout  time.Duration
+	syncInterval time.Duration // Used unless overridden by the sync_interval setting or the repo
+	backupDir    string        // Empty when scheduled backups are disabled
+	backupKeep   int
+
+	syncMu     sync.Mutex
+	lastSynced map[string]time.Time // By repo ID; guarded by syncMu
+	reapMu     sync.Mutex
+	backupMu   sync.Mutex
+
+	// Repos whose sync was requested through this replica's API, by ID.
+	requestedMu   sync.Mutex
+	requested     map[string]struct{}
+	syncRequested chan struct{}
+}
+
+var _ adminapi.JobRunner = (*jobs)(nil)
+
+func newJobs(s stores, logger log.Logger, taskTimeout, syncInterval time.Duration, backupDir string, backupKeep int) *jobs {
+	if taskTimeout <= 0 {
+		taskTimeout = defaultTaskTimeout
+	}
+	if syncInterval <= 0 {
+		syncInterval = defaultSyncInterval
+	}
+	if backupKeep == 0 {
+		backupKeep = defaultBackupKeep
+	}
+	return &jobs{
+		s:             s,
+		logger:        logger,
+		taskTimeout:   taskTimeout,
+		syncInterval:  syncInterval,
+		backupDir:     backupDir,
+		backupKeep:    backupKeep,
+		lastSynced:    make(map[string]time.Time),
+		requested:     make(map[string]struct{}),
+		syncRequested: make(chan struct{}, 1),
+	}
+}
+
+// Sync checks the pull requests of tasks in review in every repo that hasn't
+// disabled sync: it links manually created PRs to branch-only tasks, marks
+// merged PRs, and retries tasks whose PRs have merge conflicts,
+// change-request reviews or failed CI checks.
+func (j *jobs) Sync(ctx context.Context) (adminapi.SyncResult, error) {
+	return j.syncRepos(ctx, func(r *repo.Repo) bool { return !r.SyncDisabled })
+}
+
+// SyncDue syncs the repos whose sync interval has passed since they were last
+// synced.
+func (j *jobs) SyncDue(ctx context.Context) (adminapi.SyncResult, error) {
+	now := time.Now()
+	return j.syncRepos(ctx, func(r *repo.Repo) bool {
+		return !r.SyncDisabled && now.Sub(j.lastSynced[r.ID.String()]) >= j.repoSyncInterval(r)
+	})
+}
+
+// RequestRepoSync queues a sync of the repo, run by SyncRequested.
+func (j *jobs) RequestRepoSync(repoID string) {
+	j.requestedMu.Lock()
+	j.requested[repoID] = struct{}{}
+	j.requestedMu.Unlock()
+	select {
+	case j.syncRequested <- struct{}{}:
+	default: // A sync is already pending
+	}
+}
+
+// SyncRequested syncs the repos queued by RequestRepoSync.
+func (j *jobs) SyncRequested(ctx context.Context) (adminapi.SyncResult, error) {
+	j.requestedMu.Lock()
+	requested := j.requested
+	j.requested = make(map[string]struct{})
+	j.requestedMu.Unlock()
+	return j.syncRepos(ctx, func(r *repo.Repo) bool {
+		_, ok := requested[r.ID.String()]
+		return ok && !r.SyncDisabled
+	})
+}
+
+// repoSyncInterval returns how often the pull requests of r are synced.
+func (j *jobs) repoSyncInterval(r *repo.Repo) time.Duration {
+	if r.SyncIntervalSeconds > 0 {
+		return time.Duration(r.SyncIntervalSeconds) * time.Second
+	}
+	return j.s.setting.Duration(setting.KeySyncInterval, j.syncInterval)
+}
+
+// syncRepos syncs the pull requests of the repos include selects.
+func (j *jobs) syncRepos(ctx context.Context, include func(r *repo.Repo) bool) (adminapi.SyncResult, error) {
+	j.syncMu.Lock()
+	defer j.syncMu.Unlock()
+
+	var res adminapi.SyncResult
+	s := j.s
+	logger := j.logger.With("component", "pr_sync")
+
+	if s.githubToken == nil {
+		return res, adminapi.ErrGitHubNotConfigured
+	}
+	gh := s.githubToken.GetClient()
+	if gh == nil {
+		return res, adminapi.ErrGitHubNotConfigured
+	}
+	fineGrained := s.githubToken.IsFineGrained()
+
+	repos, err := s.repo.ListRepos(ctx)
+	if err != nil {
+		return res, fmt.Errorf("list repos: %w", err)
+	}
+	repos = slices.DeleteFunc(repos, func(r *repo.Repo) bool { return !include(r) })
+	if len(repos) == 0 {
+		return res, nil
+	}
+	now := time.Now()
+	included := make(map[string]bool, len(repos))
+	for _, r := range repos {
+		included[r.ID.String()] = true
+		j.lastSynced[r.ID.String()] = now
+	}
+
+	// Sync branch-only tasks: check if PRs were manually created.
+	branchTasks, err := s.task.ListTasksInReviewNoPR(ctx)
+	if err != nil {
+		logger.Error("failed to list branch-only tasks", "error", err)
+	} else {
+		for _, t := range branchTasks {
+			if t.BranchName == "" || !included[t.RepoID] {
+				continue
+			}
+			// Look up repo for this task.
+			repoID, parseErr := repo.ParseRepoID(t.RepoID)
+			if parseErr != nil {
+				continue
+			}
+			r, readErr := s.repo.ReadRepo(ctx, repoID)
+			if readErr != nil {
+				continue
+			}
+			prURL, prNumber, findErr := gh.FindPRForBranch(ctx, r.Owner, r.Name, t.BranchName)
+			if findErr != nil {
+				logger.Error("failed to find pr for branch", "task.id", t.ID, "task.branch", t.BranchName, "error", findErr)
+				continue
+			}
+			if prNumber > 0 {
+				if err := s.task.SetTaskPullRequest(ctx, t.ID, prURL, prNumber); err != nil {
+					logger.Error("failed to link pr to task", "task.id", t.ID, "error", err)
+				} else {
+					logger.Info("linked pr to branch-only task", "task.id", t.ID, "pr.number", prNumber)
+				}
+			}
+		}
+	}
+
+	// List every repo's tasks first so the open PRs of each repo, including
+	// multi-repo tasks' additional PRs, can be fetched in bulk.
+	tasksByRepo := make([][]*task.Task, len(repos))
+	prNumbers := map[string][]int{}
+	for i, r := range repos {
+		tasks, err := s.task.ListTasksInReviewByRepo(ctx, r.ID.String())
+		if err != nil {
+			logger.Error("failed to list review tasks", "repo.full_name", r.FullName, "error", err)
+			continue
+		}
+		res.ReposChecked++
+		tasksByRepo[i] = tasks
+		for _, t := range tasks {
+			if t.PRNumber > 0 {
+				prNumbers[r.ID.String()] = append(prNumbers[r.ID.String()], t.PRNumber)
+			}
+			for _, pr := range t.PullRequests {
+				if !pr.Merged {
+					prNumbers[pr.RepoID] = append(prNumbers[pr.RepoID], pr.Number)
+				}
+			}
+		}
+	}
+	statuses := fetchPRStatuses(ctx, gh, logger, repos, prNumbers)
+
+	for i, r := range repos {
+		for _, t := range tasksByRepo[i] {
+			if !t.HasPullRequest() {
+				continue
+			}
+			res.TasksChecked++
+			j.syncTaskPRs(ctx, gh, statuses, fineGrained, logger, r, t)
+		}
+	}
+	return res, nil
+}
+
+// prStatuses holds the PR statuses fetched in bulk for a sync, by repo ID and
+// PR number.
+type prStatuses map[string]map[int]*github.PRStatus
+
+// get returns the status of a PR, or nil when it wasn't fetched in bulk.
+func (ps prStatuses) get(repoID string, number int) *github.PRStatus {
+	return ps[repoID][number]
+}
+
+// fetchPRStatuses fetches the status of the given PRs with one GraphQL query
+// per repo. A repo whose PRs can't be fetched in bulk is left out, so its PRs
+// are checked one by one instead.
+func fetchPRStatuses(ctx context.Context, gh *github.Client, logger log.Logger, repos []*repo.Repo, prNumbers map[string][]int) prStatuses {
+	statuses := prStatuses{}
+	for _, r := range repos {
+		numbers := prNumbers[r.ID.String()]
+		if len(numbers) == 0 {
+			continue
+		}
+		repoStatuses, err := gh.GetPRStatuses(ctx, r.Owner, r.Name, numbers, r.CheckPolicy())
+		if err != nil {
+			logger.Warn("failed to fetch pr statuses in bulk", "repo.full_name", r.FullName, "error", err)
+			continue
+		}
+		statuses[r.ID.String()] = repoStatuses
+	}
+	return statuses
+}
+
+// taskPR is one of the pull requests of a task being synced.
+type taskPR struct {
+	repo         *repo.Repo
+	number       int
+	lastReviewID int64
+	// additional is set for a PR opened in one of a multi-repo task's
+	// additional repos rather than the task's own repo.
+	additional bool
+}
+
+// label identifies an additional repo's PR in retry reasons and feedback. It
+// is empty for the task's own PR.
+func (pr taskPR) label() string {
+	if !pr.additional {
+		return ""
+	}
+	return fmt.Sprintf(" (%s#%d)", pr.repo.FullName, pr.number)
+}
+
+// syncTaskPRs checks each unmerged pull request of a task in review. PRs are
+// tracked independently: the task is marked merged once all of them are
+// merged, and the first PR that needs the agent's attention sends the task
+// back to it.
+func (j *jobs) syncTaskPRs(ctx context.Context, gh *github.Client, statuses prStatuses, fineGrained bool, logger log.Logger, r *repo.Repo, t *task.Task) {
+	s := j.s
+	allMerged := true
+	if t.PRNumber > 0 && t.StackParent() != "" {
+		j.restackPR(ctx, gh, statuses.get(r.ID.String(), t.PRNumber), logger, r, t)
+	}
+	if t.PRNumber > 0 {
+		merged, done := j.syncPR(ctx, gh, statuses.get(r.ID.String(), t.PRNumber), fineGrained, logger, t, taskPR{repo: r, number: t.PRNumber, lastReviewID: t.LastReviewID})
+		if done {
+			return
+		}
+		allMerged = merged
+	}
+	for _, pr := range t.PullRequests {
+		if pr.Merged {
+			continue
+		}
+		repoID, err := repo.ParseRepoID(pr.RepoID)
+		if err != nil {
+			logger.Error("invalid pr repo id", "task.id", t.ID, "repo.id", pr.RepoID, "error", err)
+			return
+		}
+		prRepo, err := s.repo.ReadRepo(ctx, repoID)
+		if err != nil {
+			logger.Error("failed to read pr repo", "task.id", t.ID, "repo.id", pr.RepoID, "error", err)
+			return
+		}
+		merged, done := j.syncPR(ctx, gh, statuses.get(pr.RepoID, pr.Number), fineGrained, logger, t, taskPR{repo: prRepo, number: pr.Number, lastReviewID: pr.LastReviewID, additional: true})
+		if done {
+			return
+		}
+		if !merged {
+			allMerged = false
+			continue
+		}
+		if err := s.task.MarkPullRequestMerged(ctx, t.ID, pr.RepoID); err != nil {
+			logger.Error("failed to mark pr merged", "task.id", t.ID, "repo.full_name", prRepo.FullName, "error", err)
+			return
+		}
+		logger.Info("additional repo pr merged", "task.id", t.ID, "repo.full_name", prRepo.FullName, "pr.number", pr.Number)
+	}
+	if !allMerged {
+		return
+	}
+	if err := s.task.UpdateTaskStatus(ctx, t.ID, task.StatusMerged); err != nil {
+		logger.Error("failed to update task status", "task.id", t.ID, "error", err)
+	} else {
+		logger.Info("task pr merged", "task.id", t.ID)
+	}
+}
+
+// restackPR retargets the PR of a stacked task once its parent is merged.
+// Until then the PR targets the parent's branch; afterwards it targets the
+// branch the parent's PR was merged into, so it only shows its own changes.
+// status is the PR's status if it was fetched in bulk.
+func (j *jobs) restackPR(ctx context.Context, gh *github.Client, status *github.PRStatus, logger log.Logger, r *repo.Repo, t *task.Task) {
+	parentID, err := task.ParseTaskID(t.StackParent())
+	if err != nil {
+		logger.Error("invalid stack parent id", "task.id", t.ID, "error", err)
+		return
+	}
+	parent, err := j.s.task.ReadTask(ctx, parentID)
+	if err != nil {
+		logger.Error("failed to read stack parent", "task.id", t.ID, "error", err)
+		return
+	}
+	if parent.Status != task.StatusMerged || parent.PRNumber <= 0 || parent.BranchName == "" {
+		return
+	}
+
+	var base string
+	if status != nil {
+		base = status.BaseRef
+	}
+	if base == "" {
+		if base, err = gh.GetPRBaseRef(ctx, r.Owner, r.Name, t.PRNumber); err != nil {
+			logger.Error("failed to read pr base", "task.id", t.ID, "error", err)
+			return
+		}
+	}
+	if base != parent.BranchName {
+		// Already retargeted, by us or by GitHub when the parent's branch
+		// was deleted on merge.
+		return
+	}
+
+	newBase, err := gh.GetPRBaseRef(ctx, r.Owner, r.Name, parent.PRNumber)
+	if err != nil {
+		logger.Error("failed to read stack parent pr base", "task.id", t.ID, "error", err)
+		return
+	}
+	if err := gh.UpdatePRBase(ctx, r.Owner, r.Name, t.PRNumber, newBase); err != nil {
+		logger.Error("failed to retarget stacked pr", "task.id", t.ID, "error", err)
+		return
+	}
+	logger.Info("retargeted stacked pr", "task.id", t.ID, "pr.number", t.PRNumber, "pr.base", newBase)
+}
+
+// syncPR checks one of a task's pull requests. status is the PR's status if it
+// was fetched in bulk; whatever it lacks is fetched with REST calls. It
+// reports whether the PR is merged, and done when the task needs no further
+// checks this round because it was sent back to the agent or the PR could not
+// be checked.
+func (j *jobs) syncPR(ctx context.Context, gh *github.Client, status *github.PRStatus, fineGrained bool, logger log.Logger, t *task.Task, pr taskPR) (merged, done bool) {
+	s := j.s
+	r := pr.repo
+	if pr.additional {
+		logger = logger.With("repo.full_name", r.FullName, "pr.number", pr.number)
+	}
+
+	// 1. Check if merged (terminal positive).
+	if status == nil {
+		isMerged, err := gh.IsPRMerged(ctx, r.Owner, r.Name, pr.number)
+		if err != nil {
+			logger.Error("failed to check pr merged", "task.id", t.ID, "error", err)
+			return false, true
+		}
+		status = &github.PRStatus{Merged: isMerged}
+	}
+	if status.Merged {
+		return true, false
+	}
+
+	// 2. Check for merge conflicts.
+	mergeability := status.Mergeability
+	if mergeability == nil {
+		var err error
+		mergeability, err = gh.GetPRMergeability(ctx, r.Owner, r.Name, pr.number)
+		if err != nil {
+			logger.Error("failed to check mergeability", "task.id", t.ID, "error", err)
+			return false, true
+		}
+	}
+	switch mergeability.BranchAction() {
+	case github.BranchUpdate:
+		// Bring the branch up to date with GitHub, so an agent attempt
+		// is only spent when the base can't be merged in mechanically.
+		err := gh.UpdatePRBranch(ctx, r.Owner, r.Name, pr.number)
+		var rejected *github.RejectedError
+		switch {
+		case errors.As(err, &rejected):
+			// A branch that is only behind can't conflict; GitHub marks
+			// it dirty if it does, so wait for that.
+			logger.Warn("failed to update pr branch", "task.id", t.ID, "error", err)
+			return false, false
+		case err != nil:
+			logger.Error("failed to update pr branch", "task.id", t.ID, "error", err)
+		default:
+			logger.Info("updated pr branch with base", "task.id", t.ID)
+		}
+		return false, true
+	case github.BranchResolveConflicts:
+		// GitHub refuses to update a conflicting branch, so don't ask.
+		logger.Info("pr has merge conflicts, retrying", "task.id", t.ID, "task.attempt", t.Attempt)
+		reason := "merge_conflict: PR has conflicts with base branch" + pr.label()
+		if err := s.task.RetryTask(ctx, t.ID, "merge_conflict", reason); err != nil {
+			logger.Error("failed to retry task", "task.id", t.ID, "error", err)
+		}
+		return false, true
+	}
+
+	// 3. Check for new "changes requested" reviews and feed
+	// the review comments back to the agent.
+	reviews, err := gh.ListPRReviews(ctx, r.Owner, r.Name, pr.number)
+	if err != nil {
+		logger.Error("failed to list pr reviews", "task.id", t.ID, "error", err)
+		return false, true
+	}
+	if _, latestID := github.ChangeRequestFeedback(reviews, nil, pr.lastReviewID); latestID > 0 {
+		comments, err := gh.ListPRReviewComments(ctx, r.Owner, r.Name, pr.number)
+		if err != nil {
+			logger.Warn("failed to list pr review comments", "task.id", t.ID, "error", err)
+		}
+		feedback, latestID := github.ChangeRequestFeedback(reviews, comments, pr.lastReviewID)
+		logger.Info("pr changes requested, retrying with review feedback", "task.id", t.ID, "review.id", latestID)
+		if pr.additional {
+			feedback = fmt.Sprintf("Review of the pull request in %s:\n\n%s", r.FullName, feedback)
+			err = s.task.PullRequestReviewFeedbackRetryTask(ctx, t.ID, r.ID.String(), latestID, feedback)
+		} else {
+			err = s.task.ReviewFeedbackRetryTask(ctx, t.ID, latestID, feedback)
+		}
+		if err != nil {
+			logger.Error("failed to retry task with review feedback", "task.id", t.ID, "error", err)
+		}
+		return false, true
+	}
+
+	// 4. Check for security alerts introduced by the PR. Auto-merge
+	// is turned off and the agent is sent back to remediate them.
+	alerts, err := gh.ListPRSecurityAlerts(ctx, r.Owner, r.Name, pr.number)
+	if err != nil {
+		logger.Warn("failed to list pr security alerts", "task.id", t.ID, "error", err)
+	} else if len(alerts) > 0 {
+		if disabled, err := gh.DisableAutoMerge(ctx, r.Owner, r.Name, pr.number); err != nil {
+			logger.Warn("failed to disable pr auto-merge", "task.id", t.ID, "error", err)
+		} else if disabled {
+			logger.Info("disabled pr auto-merge due to security alerts", "task.id", t.ID)
+		}
+
+		keys := make([]string, len(alerts))
+		for i, a := range alerts {
+			keys[i] = fmt.Sprintf("%s#%d", a.Kind, a.Number)
+		}
+		sort.Strings(keys)
+		category := "security_alert:" + strings.Join(keys, ",")
+		reason := fmt.Sprintf("%s: PR introduces %d security alert(s)%s", category, len(alerts), pr.label())
+		logger.Info("pr introduces security alerts, retrying", "task.id", t.ID, "alert.count", len(alerts))
+		if err := s.task.SecurityAlertRetryTask(ctx, t.ID, category, reason, github.SecurityAlertFeedback(alerts)); err != nil {
+			logger.Error("failed to retry task for security alerts", "task.id", t.ID, "error", err)
+		}
+		return false, true
+	}
+
+	// 5. Check CI status (skipped for fine-grained tokens).
+	if fineGrained {
+		return false, false
+	}
+	checkResult := status.Checks
+	if checkResult == nil {
+		checkResult, err = gh.GetPRCheckStatus(ctx, r.Owner, r.Name, pr.number, r.CheckPolicy())
+		if err != nil {
+			logger.Error("failed to check ci status", "task.id", t.ID, "error", err)
+			return false, true
+		}
+	}
+	// Don't treat the PR as green until the run of the repo's
+	// dispatched CI workflow has passed. Only the task's own PR has
+	// a dispatched run.
+	if !pr.additional {
+		dispatch, err := s.task.ReadCIDispatch(ctx, t.ID)
+		if err != nil {
+			logger.Error("failed to read ci dispatch", "task.id", t.ID, "error", err)
+			return false, true
+		}
+		if dispatch != nil {
+			run, err := gh.FindDispatchedRun(ctx, r.Owner, r.Name, dispatch.WorkflowFile, dispatch.Ref, dispatch.DispatchedAt)
+			if err != nil {
+				logger.Error("failed to find dispatched ci run", "task.id", t.ID, "ci.workflow", dispatch.WorkflowFile, "error", err)
+				return false, true
+			}
+			github.ApplyDispatchedRun(checkResult, dispatch.WorkflowFile, run)
+		}
+	}
+	if checkResult.Status == github.CheckStatusFailure {
+		logger.Info("pr checks failed, retrying", "task.id", t.ID, "task.attempt", t.Attempt, "check.summary", checkResult.Summary)
+
+		// Fetch actual CI failure logs for targeted retry
+		failureLogs, logErr := gh.GetFailedCheckLogs(ctx, r.Owner, r.Name, pr.number, r.CheckPolicy())
+		if logErr != nil {
+			logger.Warn("failed to fetch ci logs", "task.id", t.ID, "error", logErr)
+		} else if failureLogs != "" {
+			if err := s.task.SetRetryContext(ctx, t.ID, failureLogs); err != nil {
+				logger.Warn("failed to set retry context", "task.id", t.ID, "error", err)
+			}
+		}
+
+		// Build category from failed check names so the circuit
+		// breaker only trips when the exact same checks keep failing.
+		category := "ci_failure"
+		if len(checkResult.FailedNames) > 0 {
+			names := make([]string, len(checkResult.FailedNames))
+			copy(names, checkResult.FailedNames)
+			sort.Strings(names)
+			category = "ci_failure:" + strings.Join(names, ",")
+		}
+		reason := fmt.Sprintf("%s: %s%s", category, checkResult.Summary, pr.label())
+		if err := s.task.RetryTask(ctx, t.ID, category, reason); err != nil {
+			logger.Error("failed to retry task", "task.id", t.ID, "error", err)
+		}
+		return false, true
+	}
+	// If pending, do nothing — wait for checks to complete.
+	return false, false
+}
+
+// Reap times out running tasks, epics and conversations that have stopped
+// sending heartbeats.
+func (j *jobs) Reap(ctx context.Context) (adminapi.ReapResult, error) {
+	j.reapMu.Lock()
+	defer j.reapMu.Unlock()
+
+	var res adminapi.ReapResult
+	logger := j.logger.With("component", "reaper")
+
+	count, requeued, err := j.s.task.TimeoutStaleTasks(ctx, j.taskTimeout)
+	if err != nil {
+		return res, fmt.Errorf("timeout stale tasks: %w", err)
+	}
+	res.TimedOutTasks = count
+	res.RequeuedTasks = requeued
+	if count > 0 {
+		logger.Info("timed out stale tasks", "count", count)
+	}
+	if requeued > 0 {
+		logger.Info("requeued stale tasks", "count", requeued)
+	}
+
+	count, err = j.s.epic.TimeoutStaleEpics(ctx, defaultEpicTimeout)
+	if err != nil {
+		return res, fmt.Errorf("timeout stale epics: %w", err)
+	}
+	res.TimedOutEpics = count
+	if count > 0 {
+		logger.Info("timed out stale epics", "count", count)
+	}
+
+	count, err = j.s.conversation.TimeoutStaleConversations(ctx, defaultConversationTimeout)
+	if err != nil {
+		return res, fmt.Errorf("timeout stale conversations: %w", err)
+	}
+	res.TimedOutConversations = count
+	if count > 0 {
+		logger.Info("timed out stale conversations", "count", count)
+	}
+
+	if _, err := j.s.task.DeleteExpiredCallbacks(ctx, task.CallbackRetention); err != nil {
+		return res, fmt.Errorf("delete expired callbacks: %w", err)
+	}
+
+	return res, nil
+}
+
+// secretRewrapper re-encrypts one stored secret under the current key.
+type secretRewrapper struct {
+	name   string
+	rewrap func(context.Context) (bool, error)
+}
+
+// RewrapSecrets re-encrypts the GitHub token, the GitHub credentials and the
+// OIDC client secret if they were encrypted under a previous key.
+func (j *jobs) RewrapSecrets(ctx context.Context) (adminapi.RewrapResult, error) {
+	res := adminapi.RewrapResult{Rewrapped: []string{}}
+	if j.s.secrets == nil {
+		return res, nil
+	}
+	res.KeyID = j.s.secrets.KeyID()
+
+	var rewrappers []secretRewrapper
+	if j.s.githubToken != nil {
+		rewrappers = append(rewrappers,
+			secretRewrapper{"github_token", j.s.githubToken.Rewrap},
+			secretRewrapper{"github_credentials", j.s.githubToken.RewrapCredentials},
+		)
+	}
+	if j.s.oidc != nil {
+		rewrappers = append(rewrappers, secretRewrapper{"oidc_client_secret", j.s.oidc.Rewrap})
+	}
+	for _, r := range rewrappers {
+		rewrapped, err := r.rewrap(ctx)
+		if err != nil {
+			return res, fmt.Errorf("rewrap %s: %w", r.name, err)
+		}
+		if rewrapped {
+			res.Rewrapped = append(res.Rewrapped, r.name)
+			j.logger.Info("rewrapped secret under the current key", "secret", r.name, "secrets.key_id", res.KeyID)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,175 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/joshjon/kit/log"
+	"github.com/joshjon/kit/sqlitedb"
+	_ "github.com/tursodatabase/libsql-client-go/libsql" // registers "libsql" database/sql driver
+
+	"github.com/vervesh/verve/internal/sqlite"
+	litemigrations "github.com/vervesh/verve/internal/sqlite/migrations"
+)
+
+// MigrateConfig holds the configuration for the migrate command.
+type MigrateConfig struct {
+	SQLiteDir   string        // Directory for SQLite DB file
+	TursoDSN    string        // Turso/libSQL DSN (e.g. "libsql://db-name.turso.io?authToken=...")
+	DryRun      bool          // Report pending migrations without applying them
+	LockTimeout time.Duration // How long to wait for the database write lock (0 = driver default)
+}
+
+// database describes how to open the configured database.
+type database struct {
+	openOpts     []sqlitedb.OpenOption
+	taskRepoOpts []sqlite.TaskRepoOption
+	// dir is set for file-backed SQLite, which is opened with
+	// sqlite.OpenFile instead of openOpts so writes are serialized.
+	dir string
+	// remote is true for Turso/libSQL, which doesn't support PRAGMA statements.
+	remote bool
+	// inMemory is true when the database starts empty on every run.
+	inMemory bool
+}
+
+func resolveDatabase(logger log.Logger, sqliteDir, tursoDSN string) database {
+	if tursoDSN != "" {
+		logger.Info("using turso/libsql")
+		return database{
+			openOpts:     []sqlitedb.OpenOption{sqlitedb.WithDSN("libsql", tursoDSN)},
+			taskRepoOpts: []sqlite.TaskRepoOption{sqlite.WithNoPragma()},
+			remote:       true,
+		}
+	}
+	if sqliteDir != "" {
+		logger.Info("using file-backed sqlite", "sqlite.dir", sqliteDir)
+		return database{dir: sqliteDir}
+	}
+	logger.Warn("using in-memory sqlite (data will not persist)")
+	return database{
+		openOpts: []sqlitedb.OpenOption{sqlitedb.WithInMemory()},
+		inMemory: true,
+	}
+}
+
+// openedDatabase is an open database. For file-backed SQLite, db routes writes
+// through a single writer connection and conn is that connection; otherwise
+// both are the same pool.
+type openedDatabase struct {
+	db   sqlite.DB
+	conn *sql.DB
+	// file is set for file-backed SQLite.
+	file  *sqlite.FileDB
+	close func() error
+}
+
+func (d database) open(ctx context.Context) (*openedDatabase, error) {
+	if d.dir != "" {
+		fdb, err := sqlite.OpenFile(ctx, d.dir, "verve")
+		if err != nil {
+			return nil, fmt.Errorf("open sqlite: %w", err)
+		}
+		return &openedDatabase{db: fdb, conn: fdb.Writer(), file: fdb, close: fdb.Close}, nil
+	}
+	db, err := sqlitedb.Open(ctx, d.openOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	return &openedDatabase{db: db, conn: db, close: db.Close}, nil
+}
+
+func (d database) migrateOpts(lockTimeout time.Duration) []sqlite.MigrateOption {
+	if lockTimeout <= 0 || d.remote {
+		return nil
+	}
+	return []sqlite.MigrateOption{sqlite.WithLockTimeout(lockTimeout)}
+}
+
+// Migrate applies pending database migrations, or only reports them when
+// cfg.DryRun is set. It is the explicit alternative to migrating on startup.
+func Migrate(ctx context.Context, logger log.Logger, cfg MigrateConfig) error {
+	if cfg.SQLiteDir == "" && cfg.TursoDSN == "" {
+		return errors.New("SQLITE_DIR or TURSO_DSN is required")
+	}
+
+	d := resolveDatabase(logger, cfg.SQLiteDir, cfg.TursoDSN)
+	opened, err := d.open(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = opened.close() }()
+	db := opened.conn
+
+	status, err := checkMigrationStatus(ctx, db)
+	if err != nil {
+		return err
+	}
+	if len(status.Pending) == 0 {
+		logger.Info("database schema is up to date", "migration.version", status.Current)
+		return nil
+	}
+
+	for _, m := range status.Pending {
+		logger.Info("pending migration", "migration.version", m.Version, "migration.name", m.Name)
+	}
+	if cfg.DryRun {
+		logger.Info("dry run: no migrations applied",
+			"migration.current_version", status.Current,
+			"migration.target_version", status.Latest(),
+			"migration.pending", len(status.Pending),
+		)
+		return nil
+	}
+
+	return applyMigrations(ctx, logger, db, d, status, cfg.LockTimeout)
+}
+
+// migrateOnStartup brings the schema up to date before the server starts. When
+// auto-migrate is disabled the server refuses to start against a database with
+// pending migrations rather than running against an outdated schema. In-memory
+// databases are always migrated since they start empty.
+func migrateOnStartup(ctx context.Context, logger log.Logger, db *sql.DB, d database, autoMigrate bool, lockTimeout time.Duration) error {
+	status, err := checkMigrationStatus(ctx, db)
+	if err != nil {
+		return err
+	}
+	if len(status.Pending) == 0 {
+		return nil
+	}
+	if !autoMigrate && !d.inMemory {
+		return fmt.Errorf("%w: schema is at version %d but this release requires version %d; run `verve migrate` (use --dry-run to preview) or set AUTO_MIGRATE=true",
+			sqlite.ErrPendingMigrations, status.Current, status.Latest())
+	}
+	return applyMigrations(ctx, logger, db, d, status, lockTimeout)
+}
+
+// checkMigrationStatus reads the schema status and refuses to continue when a
+// previous migration failed part way.
+func checkMigrationStatus(ctx context.Context, db *sql.DB) (sqlite.MigrationStatus, error) {
+	status, err := sqlite.ReadMigrationStatus(ctx, db, litemigrations.FS)
+	if err != nil {
+		return sqlite.MigrationStatus{}, fmt.Errorf("read migration status: %w", err)
+	}
+	if status.Dirty {
+		return sqlite.MigrationStatus{}, fmt.Errorf("database schema is dirty at version %d: a previous migration failed part way and must be repaired manually", status.Current)
+	}
+	return status, nil
+}
+
+func applyMigrations(ctx context.Context, logger log.Logger, db *sql.DB, d database, status sqlite.MigrationStatus, lockTimeout time.Duration) error {
+	logger.Info("applying database migrations",
+		"migration.current_version", status.Current,
+		"migration.target_version", status.Latest(),
+		"migration.pending", len(status.Pending),
+	)
+	start := time.Now()
+	if err := sqlite.Migrate(ctx, db, litemigrations.FS, d.migrateOpts(lockTimeout)...); err != nil {
+		return fmt.Errorf("migrate sqlite: %w", err)
+	}
+	logger.Info("applied database migrations", "migration.version", status.Latest(), "migration.duration", time.Since(start))
+	return nil
+}
@@ -1,42 +1,103 @@
 package app
 
 import (
+	"cmp"
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
-	"sort"
+	"net"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/joshjon/kit/log"
 	"github.com/joshjon/kit/server"
-	"github.com/joshjon/kit/sqlitedb"
 	"github.com/labstack/echo/v4"
-	_ "github.com/tursodatabase/libsql-client-go/libsql" // registers "libsql" database/sql driver
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
+	"github.com/vervesh/verve/internal/adminapi"
 	"github.com/vervesh/verve/internal/agentapi"
+	"github.com/vervesh/verve/internal/attachment"
+	"github.com/vervesh/verve/internal/audit"
+	"github.com/vervesh/verve/internal/authapi"
+	"github.com/vervesh/verve/internal/capabilityapi"
 	"github.com/vervesh/verve/internal/conversation"
 	"github.com/vervesh/verve/internal/conversationapi"
+	"github.com/vervesh/verve/internal/costguard"
+	"github.com/vervesh/verve/internal/digest"
+	"github.com/vervesh/verve/internal/digestapi"
 	"github.com/vervesh/verve/internal/epic"
 	"github.com/vervesh/verve/internal/epicapi"
+	"github.com/vervesh/verve/internal/epicbranch"
 	"github.com/vervesh/verve/internal/eventapi"
+	"github.com/vervesh/verve/internal/eventbus"
 	"github.com/vervesh/verve/internal/frontend"
 	"github.com/vervesh/verve/internal/github"
 	"github.com/vervesh/verve/internal/githubtoken"
+	"github.com/vervesh/verve/internal/keyprovider"
+	"github.com/vervesh/verve/internal/leader"
 	"github.com/vervesh/verve/internal/logkey"
+	"github.com/vervesh/verve/internal/mcp"
 	"github.com/vervesh/verve/internal/metric"
 	"github.com/vervesh/verve/internal/metricapi"
+	"github.com/vervesh/verve/internal/notification"
+	"github.com/vervesh/verve/internal/notificationapi"
+	"github.com/vervesh/verve/internal/oidc"
+	"github.com/vervesh/verve/internal/openapi"
+	"github.com/vervesh/verve/internal/permit"
+	"github.com/vervesh/verve/internal/provenance"
 	"github.com/vervesh/verve/internal/repo"
 	"github.com/vervesh/verve/internal/repoapi"
+	"github.com/vervesh/verve/internal/selfreview"
 	"github.com/vervesh/verve/internal/setting"
 	"github.com/vervesh/verve/internal/settingapi"
 	"github.com/vervesh/verve/internal/sqlite"
-	litemigrations "github.com/vervesh/verve/internal/sqlite/migrations"
 	"github.com/vervesh/verve/internal/task"
 	"github.com/vervesh/verve/internal/taskapi"
+	"github.com/vervesh/verve/internal/team"
+	"github.com/vervesh/verve/internal/teamapi"
+	"github.com/vervesh/verve/internal/throughput"
+	"github.com/vervesh/verve/internal/user"
+	"github.com/vervesh/verve/internal/userapi"
+	"github.com/vervesh/verve/internal/webhook"
+	"github.com/vervesh/verve/internal/webhookapi"
 	"github.com/vervesh/verve/internal/workertracker"
 )
 
+// backgroundJobsLease is the name of the lease that elects the replica that
+// runs singleton background jobs.
+const backgroundJobsLease = "background_jobs"
+
+// Event buses that share task events between replicas (EVENT_BUS).
+const (
+	eventBusMemory   = "memory"   // Events stay on the replica that published them
+	eventBusDatabase = "database" // Events pass through the shared database
+)
+
+// costAnalysisInterval is how often spend is checked for anomalies.
+const costAnalysisInterval = 5 * time.Minute
+
+// workerTimeout is how long a registered worker can go without polling or
+// sending a heartbeat before the tasks it reported running are released.
+const workerTimeout = 2 * time.Minute
+
+// StreamingPaths are the routes that hold their response open, such as
+// Server-Sent Events streams and long polls, and so are exempt from the
+// request timeout.
+var StreamingPaths = []string{
+	"/api/v1/events",
+	"/api/v1/tasks/:id/logs",
+	"/api/v1/tasks/:id/attempts/:attempt/logs",
+	"/api/v1/agent/poll",
+	"/api/v1/mcp/sse",
+	"/api/v2/tasks/:id/logs",
+	"/api/v2/tasks/:id/attempts/:attempt/logs",
+}
+
 type stores struct {
 	task         *task.Store
 	repo         *repo.Store
@@ -44,32 +105,90 @@ type stores struct {
 	conversation *conversation.Store
 	githubToken  *githubtoken.Service
 	setting      *setting.Service
+	notification *notification.Service
+	webhook      *webhook.Service
+	team         *team.Store
+	user         *user.Store
+	oidc         *oidc.Service         // nil when secrets encryption is not configured
+	secrets      *keyprovider.Envelope // nil when secrets encryption is not configured
+	audit        *audit.Store
+	costs        costguard.Repository
+	digests      digest.Repository
+	throughput   throughput.Repository
+	killSwitch   *costguard.Switch
+	leader       *leader.Elector
+	broker       *task.Broker
+	bus          *eventbus.Bus // nil when events stay on this replica
+	// fileDB is set when using file-backed SQLite.
+	fileDB *sqlite.FileDB
 }
 
 // Run starts the API server.
 func Run(ctx context.Context, logger log.Logger, cfg Config) error {
-	var encryptionKey []byte
-	if cfg.EncryptionKey != "" {
-		var err error
-		encryptionKey, err = hex.DecodeString(cfg.EncryptionKey)
-		if err != nil {
-			return fmt.Errorf("decode ENCRYPTION_KEY (expected hex): %w", err)
+	switch cfg.EventBus {
+	case "", eventBusMemory, eventBusDatabase:
+	default:
+		return fmt.Errorf("unknown EVENT_BUS %q: must be %s or %s", cfg.EventBus, eventBusMemory, eventBusDatabase)
+	}
+	if cfg.MergeMethod != "" && !slices.Contains(github.MergeMethods, cfg.MergeMethod) {
+		return fmt.Errorf("unknown MERGE_METHOD %q: must be one of %s", cfg.MergeMethod, strings.Join(github.MergeMethods, ", "))
+	}
+
+	secretReader, err := keyprovider.NewSecretReader(keyprovider.SecretsConfig{
+		Backend: cfg.SecretsBackend,
+		AWS:     awsConfig(cfg),
+		GCP:     keyprovider.GCPConfig{AccessToken: cfg.GCPAccessToken},
+		Vault:   keyprovider.VaultConfig{Address: cfg.VaultAddress, Token: cfg.VaultToken},
+		VaultKV: cfg.VaultKVMount,
+	})
+	if err != nil {
+		return err
+	}
+	secrets, err := newSecretsEnvelope(ctx, cfg, secretReader)
+	if err != nil {
+		return err
+	}
+	if secrets != nil {
+		logger.Info("secrets encryption configured", "secrets.key_id", secrets.KeyID())
+	}
+
+	gh := githubTokenOptions{insecureSkipVerify: cfg.GitHubInsecureSkipVerify}
+	if cfg.GitHubTokenSecret != "" {
+		if secretReader == nil {
+			return errors.New("GITHUB_TOKEN_SECRET requires SECRETS_BACKEND")
 		}
+		gh.secret, gh.secretRef, gh.secretTTL = secretReader, cfg.GitHubTokenSecret, cfg.GitHubTokenSecretTTL
 	}
 
-	s, cleanup, err := initStores(ctx, logger, cfg, encryptionKey)
+	s, cleanup, err := initStores(ctx, logger, cfg, secrets, gh)
 	if err != nil {
 		return err
 	}
 	defer cleanup()
+	s.task.SetLogBatchInterval(cfg.LogBatchInterval)
+	s.task.SetTrashRetention(cfg.TaskTrashRetention)
+	if err := configureAttachments(logger, cfg, s.task); err != nil {
+		return err
+	}
 
-	if s.githubToken != nil {
+	if s.githubToken != nil && s.githubToken.Managed() {
+		if s.githubToken.HasToken() {
+			logger.Info("github token read from secrets manager", "secrets.backend", cfg.SecretsBackend)
+		} else if err := s.githubToken.SecretError(); err != nil {
+			logger.Error("failed to read github token from secrets manager", "error", err)
+		}
+	} else if s.githubToken != nil {
 		if err := s.githubToken.Load(ctx); err != nil {
 			logger.Error("failed to load github token from database", "error", err)
 		} else if s.githubToken.HasToken() {
 			logger.Info("github token loaded from database")
 		}
 	}
+	if s.oidc != nil {
+		if _, err := s.oidc.Rewrap(ctx); err != nil {
+			logger.Error("failed to rewrap oidc client secret", "error", err)
+		}
+	}
 
 	if s.setting != nil {
 		if err := s.setting.Load(ctx); err != nil {
@@ -80,66 +199,215 @@ func Run(ctx context.Context, logger log.Logger, cfg Config) error {
 	return serve(ctx, logger, cfg, s)
 }
 
-func initStores(ctx context.Context, logger log.Logger, cfg Config, encryptionKey []byte) (stores, func(), error) {
-	if cfg.TursoDSN != "" {
-		logger.Info("using turso/libsql")
-		return initSQLite(ctx, encryptionKey, cfg.GitHubInsecureSkipVerify, logger,
-			[]sqlitedb.OpenOption{sqlitedb.WithDSN("libsql", cfg.TursoDSN)},
-			sqlite.WithNoPragma())
-	}
-	if cfg.SQLiteDir != "" {
-		logger.Info("using file-backed sqlite", "sqlite.dir", cfg.SQLiteDir)
-		return initSQLite(ctx, encryptionKey, cfg.GitHubInsecureSkipVerify, logger,
-			[]sqlitedb.OpenOption{sqlitedb.WithDir(cfg.SQLiteDir), sqlitedb.WithDBName("verve")})
-	}
-	logger.Warn("using in-memory sqlite (data will not persist)")
-	return initSQLite(ctx, encryptionKey, cfg.GitHubInsecureSkipVerify, logger,
-		[]sqlitedb.OpenOption{sqlitedb.WithInMemory()})
+// newSecretsEnvelope creates the envelope secrets are encrypted with at rest.
+// It returns nil when no encryption key or key provider is configured.
+func newSecretsEnvelope(ctx context.Context, cfg Config, secretReader keyprovider.SecretReader) (*keyprovider.Envelope, error) {
+	kpCfg := keyprovider.Config{
+		Provider: cfg.KeyProvider,
+		KeyID:    cfg.KeyProviderKeyID,
+		AWS:      awsConfig(cfg),
+		GCP:      keyprovider.GCPConfig{AccessToken: cfg.GCPAccessToken},
+		Vault: keyprovider.VaultConfig{
+			Address: cfg.VaultAddress,
+			Token:   cfg.VaultToken,
+			Mount:   cfg.VaultTransitMount,
+		},
+	}
+	encryptionKey, previousKeys := cfg.EncryptionKey, cfg.PreviousEncryptionKeys
+	if cfg.EncryptionKeySecret != "" {
+		if secretReader == nil {
+			return nil, errors.New("ENCRYPTION_KEY_SECRET requires SECRETS_BACKEND")
+		}
+		if encryptionKey != "" {
+			return nil, errors.New("set either ENCRYPTION_KEY or ENCRYPTION_KEY_SECRET, not both")
+		}
+		// The secret holds the current key followed by the keys it was
+		// rotated from, so a rotation only touches the secrets manager.
+		secret, err := secretReader.ReadSecret(ctx, cfg.EncryptionKeySecret)
+		if err != nil {
+			return nil, fmt.Errorf("read ENCRYPTION_KEY_SECRET: %w", err)
+		}
+		keys := strings.FieldsFunc(secret, func(r rune) bool { return r == ',' || unicode.IsSpace(r) })
+		if len(keys) == 0 {
+			return nil, errors.New("ENCRYPTION_KEY_SECRET is empty")
+		}
+		encryptionKey, previousKeys = keys[0], append(keys[1:], previousKeys...)
+	}
+	if encryptionKey != "" {
+		key, err := hex.DecodeString(encryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("decode ENCRYPTION_KEY (expected hex): %w", err)
+		}
+		kpCfg.EncryptionKey = key
+	}
+	for _, previous := range previousKeys {
+		key, err := hex.DecodeString(previous)
+		if err != nil {
+			return nil, fmt.Errorf("decode PREVIOUS_ENCRYPTION_KEYS (expected hex): %w", err)
+		}
+		kpCfg.PreviousEncryptionKeys = append(kpCfg.PreviousEncryptionKeys, key)
+	}
+	return keyprovider.New(kpCfg)
+}
+
+// configureAttachments enables task attachments when storage is configured.
+// The dir backend defaults to a directory next to the SQLite database.
+func configureAttachments(logger log.Logger, cfg Config, store *task.Store) error {
+	dir := cfg.AttachmentDir
+	if dir == "" && cfg.SQLiteDir != "" {
+		dir = filepath.Join(cfg.SQLiteDir, "attachments")
+	}
+	storage, err := attachment.New(attachment.Config{
+		Backend: cfg.AttachmentStorage,
+		Dir:     dir,
+		S3: attachment.S3Config{
+			Bucket:          cfg.AttachmentS3Bucket,
+			Prefix:          cfg.AttachmentS3Prefix,
+			Region:          cfg.AWSRegion,
+			Endpoint:        cfg.AttachmentS3Endpoint,
+			AccessKeyID:     cfg.AWSAccessKeyID,
+			SecretAccessKey: cfg.AWSSecretAccessKey,
+			SessionToken:    cfg.AWSSessionToken,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("attachment storage: %w", err)
+	}
+	if storage == nil {
+		logger.Info("task attachments disabled: set ATTACHMENT_DIR or SQLITE_DIR, or use ATTACHMENT_STORAGE=s3")
+		return nil
+	}
+	store.SetAttachmentStorage(storage, cfg.AttachmentMaxSize)
+	logger.Info("task attachments enabled", "attachment.storage", cmp.Or(cfg.AttachmentStorage, attachment.BackendDir))
+	return nil
+}
+
+func awsConfig(cfg Config) keyprovider.AWSConfig {
+	return keyprovider.AWSConfig{
+		Region:          cfg.AWSRegion,
+		AccessKeyID:     cfg.AWSAccessKeyID,
+		SecretAccessKey: cfg.AWSSecretAccessKey,
+		SessionToken:    cfg.AWSSessionToken,
+	}
 }
 
-func initSQLite(ctx context.Context, encryptionKey []byte, ghInsecureSkipVerify bool, logger log.Logger, dbOpts []sqlitedb.OpenOption, taskRepoOpts ...sqlite.TaskRepoOption) (stores, func(), error) {
-	db, err := sqlitedb.Open(ctx, dbOpts...)
+// githubTokenOptions configures where the global GitHub token comes from.
+type githubTokenOptions struct {
+	insecureSkipVerify bool
+	// secret is set to read the token from a secrets manager instead of
+	// the database.
+	secret    githubtoken.SecretReader
+	secretRef string
+	secretTTL time.Duration
+}
+
+func initStores(ctx context.Context, logger log.Logger, cfg Config, secrets *keyprovider.Envelope, gh githubTokenOptions) (stores, func(), error) {
+	d := resolveDatabase(logger, cfg.SQLiteDir, cfg.TursoDSN)
+	opened, err := d.open(ctx)
 	if err != nil {
-		return stores{}, nil, fmt.Errorf("open sqlite: %w", err)
+		return stores{}, nil, err
 	}
 
-	if err := sqlitedb.Migrate(db, litemigrations.FS); err != nil {
-		_ = db.Close()
-		return stores{}, nil, fmt.Errorf("migrate sqlite: %w", err)
+	if err := migrateOnStartup(ctx, logger, opened.conn, d, cfg.AutoMigrate, cfg.MigrateLockTimeout); err != nil {
+		_ = opened.close()
+		return stores{}, nil, err
 	}
 
-	broker := task.NewBroker(nil)
+	s := initSQLite(opened.db, secrets, gh, cfg.EventBus, logger, d.taskRepoOpts...)
+	s.fileDB = opened.file
+	return s, func() { _ = opened.close() }, nil
+}
+
+func initSQLite(db sqlite.DB, secrets *keyprovider.Envelope, gh githubTokenOptions, eventBus string, logger log.Logger, taskRepoOpts ...sqlite.TaskRepoOption) stores {
+	replicaID := leader.NewHolderID()
+	var bus *eventbus.Bus
+	var notifier task.Notifier
+	if eventBus == eventBusDatabase {
+		bus = eventbus.New(sqlite.NewEventBusRepository(db), replicaID, logger)
+		notifier = bus
+	}
+	broker := task.NewBroker(notifier)
 	taskRepo := sqlite.NewTaskRepository(db, taskRepoOpts...)
 	taskStore := task.NewStore(taskRepo, broker)
+	if bus != nil {
+		// Wake every replica's polls when tasks become claimable, not just
+		// the one that made them so.
+		taskStore.SetPendingNotifier(bus)
+	}
 
 	repoRepo := sqlite.NewRepoRepository(db)
 	repoStore := repo.NewStore(repoRepo)
 
 	var ghTokenService *githubtoken.Service
-	if encryptionKey != nil {
+	var ghTokenOpts []githubtoken.Option
+	if gh.secret != nil {
+		ghTokenOpts = append(ghTokenOpts, githubtoken.WithSecret(gh.secret, gh.secretRef, gh.secretTTL))
+	}
+	if secrets != nil {
+		// Per-repo and per-org credentials are encrypted like the token.
+		ghTokenOpts = append(ghTokenOpts, githubtoken.WithCredentials(sqlite.NewGitHubCredentialRepository(db)))
+	}
+	if gh.secret != nil || secrets != nil {
 		ghTokenRepo := sqlite.NewGitHubTokenRepository(db)
-		ghTokenService = githubtoken.NewService(ghTokenRepo, encryptionKey, ghInsecureSkipVerify)
+		ghTokenService = githubtoken.NewService(ghTokenRepo, secrets, gh.insecureSkipVerify, ghTokenOpts...)
 	}
 
 	settingRepo := sqlite.NewSettingRepository(db)
 	settingService := setting.NewService(settingRepo)
+	taskStore.SetRetryPolicyResolver(retryPolicyResolver(settingService, repoStore))
+
+	teamStore := team.NewStore(sqlite.NewTeamRepository(db))
+	repoStore.SetTeamDefaultsReader(teamDefaultsReader(teamStore))
+	userStore := user.NewStore(sqlite.NewUserRepository(db))
+	var oidcService *oidc.Service
+	if secrets != nil {
+		oidcService = oidc.NewService(settingService, secrets)
+	}
 
 	epicRepo := sqlite.NewEpicRepository(db)
 	taskCreator := epic.NewTaskCreatorFunc(taskStore.CreateTaskFromEpic)
 	epicStore := epic.NewStore(epicRepo, taskCreator, logger)
 	epicStore.SetTaskStatusReader(epic.NewTaskStatusReaderFunc(taskStore.ReadTaskStatus))
+	epicStore.SetTaskCloser(epic.NewTaskCloserFunc(taskStore.CloseTaskFromEpic))
 
 	convRepo := sqlite.NewConversationRepository(db)
 	convStore := conversation.NewStore(convRepo, logger)
+	if bus != nil {
+		// Polls wait for epics and conversations as well as tasks.
+		epicStore.SetPendingNotifier(bus)
+		convStore.SetPendingNotifier(bus)
+	}
+
+	notificationRepo := sqlite.NewNotificationSinkRepository(db)
+	notificationService := notification.NewService(notificationRepo, repoStore, notification.NewSender(nil), logger)
+
+	auditRepo := sqlite.NewAuditRepository(db)
+	auditStore := audit.NewStore(auditRepo, logger)
+	taskStore.SetStatusListener(task.StatusListeners{notificationService, auditStore})
+
+	webhookRepo := sqlite.NewWebhookRepository(db)
+	webhookService := webhook.NewService(webhookRepo, logger)
+
+	epicListeners := epic.EventListeners{notificationService, webhookService}
+	if ghTokenService != nil {
+		epicListeners = append(epicListeners, epicbranch.NewService(epicStore, repoStore, ghTokenService, logger))
+	}
+	epicStore.SetEventListener(epicListeners)
 
-	return stores{task: taskStore, repo: repoStore, epic: epicStore, conversation: convStore, githubToken: ghTokenService, setting: settingService}, func() { _ = db.Close() }, nil
+	leaseRepo := sqlite.NewLeaseRepository(db)
+	elector := leader.NewElector(leaseRepo, backgroundJobsLease, replicaID, leader.DefaultLeaseTTL, logger)
+
+	costRepo := sqlite.NewCostRepository(db)
+	killSwitch := costguard.NewSwitch(settingService)
+
+	return stores{task: taskStore, repo: repoStore, epic: epicStore, conversation: convStore, githubToken: ghTokenService, setting: settingService, notification: notificationService, webhook: webhookService, team: teamStore, user: userStore, oidc: oidcService, secrets: secrets, audit: auditStore, costs: costRepo, digests: sqlite.NewDigestRepository(db), throughput: sqlite.NewThroughputRepository(db), killSwitch: killSwitch, leader: elector, broker: broker, bus: bus}
 }
 
 func serve(ctx context.Context, logger log.Logger, cfg Config, s stores) error {
 	opts := []server.Option{
 		server.WithLogger(logger),
 		server.WithRequestLogKeys(logkey.HTTPKeys...),
-		server.WithRequestTimeout(server.DefaultRequestTimeout, "/api/v1/events", "/api/v1/tasks/:id/logs", "/api/v1/agent/poll"),
+		server.WithRequestTimeout(server.DefaultRequestTimeout, StreamingPaths...),
 	}
 	if len(cfg.CorsOrigins) > 0 {
 		opts = append(opts, server.WithCORS(cfg.CorsOrigins...))
@@ -160,35 +428,145 @@ func serve(ctx context.Context, logger log.Logger, cfg Config, s stores) error {
 	}
 
 	workerReg := workertracker.New()
+	j := newJobs(s, logger, cfg.TaskTimeout, cfg.SyncInterval, cfg.BackupDir, cfg.BackupKeep)
 	epicLister := planningEpicListerAdapter(s.epic)
 
-	srv.Register("/api/v1", repoapi.NewHTTPHandler(s.repo, s.task, s.githubToken))
-	srv.Register("/api/v1", metricapi.NewHTTPHandler(s.task, epicLister, workerReg))
-	srv.Register("/api/v1", settingapi.NewHTTPHandler(s.githubToken, s.setting, cfg.EffectiveModels()))
-	srv.Register("/api/v1", eventapi.NewHTTPHandler(s.task, s.repo))
-	srv.Register("/api/v1", taskapi.NewHTTPHandler(s.task, s.repo, s.epic, s.githubToken, s.setting))
-	srv.Register("/api/v1", epicapi.NewHTTPHandler(s.epic, s.repo, s.task, s.setting))
-	srv.Register("/api/v1", conversationapi.NewHTTPHandler(s.conversation, s.repo, s.epic, s.setting))
-	srv.Register("/api/v1/agent", agentapi.NewHTTPHandler(s.task, s.epic, s.repo, s.conversation, s.githubToken, workerReg))
+	// With auth required, every endpoint the UI uses checks the caller's
+	// role: viewers read, operators act on tasks, epics and conversations,
+	// and admins manage repos, settings, teams and users.
+	auth := userapi.NewAuthenticator(s.user, cfg.AdminToken)
+	access := func(read, write user.Role, middleware ...echo.MiddlewareFunc) []echo.MiddlewareFunc {
+		if !cfg.RequireAuth {
+			return middleware
+		}
+		return append([]echo.MiddlewareFunc{auth.RequireByMethod(read, write)}, middleware...)
+	}
+	if cfg.RequireAuth {
+		logger.Info("api authentication required")
+		if cfg.AdminToken == "" {
+			logger.Warn("api authentication required without ADMIN_TOKEN: only existing admin users can manage users")
+		}
+	}
+
+	srv.Register("/api/v1", repoapi.NewHTTPHandler(s.repo, s.task, s.githubToken, repoapi.WithRepoSyncer(j)), access(user.RoleViewer, user.RoleAdmin)...)
+	srv.Register("/api/v1", metricapi.NewHTTPHandler(s.task, epicLister, workerReg, s.throughput), access(user.RoleViewer, user.RoleViewer)...)
+	srv.Register("/api/v1", digestapi.NewHTTPHandler(s.digests), access(user.RoleViewer, user.RoleViewer)...)
+	var settingOpts []settingapi.Option
+	if s.oidc != nil {
+		settingOpts = append(settingOpts, settingapi.WithOIDC(s.oidc))
+	}
+	srv.Register("/api/v1", settingapi.NewHTTPHandler(s.githubToken, s.setting, cfg.EffectiveModels(), settingOpts...), access(user.RoleViewer, user.RoleAdmin)...)
+	srv.Register("/api/v1", eventapi.NewHTTPHandler(s.task, s.repo, eventapi.WithAllowedOrigins(cfg.CorsOrigins...)), access(user.RoleViewer, user.RoleViewer)...)
+	taskHandler := taskapi.NewHTTPHandler(s.task, s.repo, s.epic, s.githubToken, s.setting, taskapi.WithV1Sunset(cfg.APIV1Sunset), taskapi.WithMergeMethod(cfg.MergeMethod))
+	epicHandler := epicapi.NewHTTPHandler(s.epic, s.repo, s.task, s.setting, epicapi.WithAuditLog(s.audit))
+	spec := openapi.New("Verve API", cfg.Version)
+	spec.Add("/api/v1", taskHandler.Routes()...)
+	spec.Add("/api/v2", taskHandler.V2().Routes()...)
+	spec.Add("/api/v1", epicHandler.Routes()...)
+	srv.Register("/api/v1", taskHandler, access(user.RoleViewer, user.RoleOperator, spec.ValidateRequests)...)
+	srv.Register("/api/v2", taskHandler.V2(), access(user.RoleViewer, user.RoleOperator, spec.ValidateRequests)...)
+	srv.Register("/api/v1", epicHandler, access(user.RoleViewer, user.RoleOperator, spec.ValidateRequests)...)
+	srv.Register("/api/v1", openapi.NewHTTPHandler(spec))
+	srv.Register("/api/v1", conversationapi.NewHTTPHandler(s.conversation, s.repo, s.epic, s.setting), access(user.RoleViewer, user.RoleOperator)...)
+	srv.Register("/api/v1", notificationapi.NewHTTPHandler(s.notification, s.repo, s.team), access(user.RoleViewer, user.RoleAdmin)...)
+	srv.Register("/api/v1", teamapi.NewHTTPHandler(s.team, s.repo, s.task, s.costs), access(user.RoleViewer, user.RoleAdmin)...)
+	srv.Register("/api/v1", userapi.NewHTTPHandler(s.user, auth))
+	srv.Register("/api/v1", authapi.NewHTTPHandler(s.oidc, s.user, cfg.SessionTTL, logger))
+	srv.Register("/api/v1", capabilityapi.NewHTTPHandler(capabilities(cfg, s, j.taskTimeout)))
+	if cfg.AdminToken != "" {
+		logger.Info("admin endpoints enabled")
+		srv.Register("/api/v1", adminapi.NewHTTPHandler(j, s.task, s.epic, s.audit, s.killSwitch, cfg.AdminToken))
+		srv.Register("/api/v1", webhookapi.NewHTTPHandler(s.webhook, s.repo, cfg.AdminToken))
+	}
+	if cfg.MCPToken != "" {
+		logger.Info("mcp server enabled", "mcp.repos", cfg.MCPRepos)
+		tools := mcp.Tools(s.task, s.epic, s.repo, s.setting, mcp.Scope{Repos: cfg.MCPRepos})
+		srv.Register("/api/v1", mcp.NewHTTPHandler(mcp.NewServer(cfg.Version, tools, logger), cfg.MCPToken))
+	}
+	agentOpts := []agentapi.Option{
+		agentapi.WithDispatchGate(s.killSwitch),
+		agentapi.WithDispatchGate(agentapi.DispatchGateFunc(s.setting.QueuePaused)),
+	}
+	if cfg.ProvenanceScan {
+		logger.Info("provenance scanning enabled", "provenance.external_scanner", cfg.ProvenanceScanner != "")
+		agentOpts = append(agentOpts, agentapi.WithProvenanceScanner(provenance.NewScanner(provenance.Config{
+			LargeBlockLines: cfg.ProvenanceBlockLines,
+			Command:         cfg.ProvenanceScanner,
+		})))
+	}
+	if cfg.SelfReview {
+		logger.Info("pr self-review enabled")
+		agentOpts = append(agentOpts, agentapi.WithSelfReviewer(selfreview.NewReviewer(selfreview.Config{
+			MaxFiles:     cfg.SelfReviewMaxFiles,
+			MaxFileLines: cfg.SelfReviewMaxFileLines,
+			MaxLines:     cfg.SelfReviewMaxLines,
+		})))
+	}
+	permitLimits, err := cfg.AgentPermitLimits()
+	if err != nil {
+		return err
+	}
+	if permitLimits.Enabled() {
+		logger.Info("agent permits enabled", "permit.max_concurrent", permitLimits.MaxConcurrent, "permit.tokens_per_minute", permitLimits.TokensPerMinute, "permit.model_limits", cfg.AgentModelLimits)
+		agentOpts = append(agentOpts, agentapi.WithPermitLimiter(permit.NewLimiter(permitLimits, 0)))
+	}
+	agentHandler := agentapi.NewHTTPHandler(s.task, s.epic, s.repo, s.conversation, s.githubToken, workerReg, agentOpts...)
+	srv.Register("/api/v1/agent", agentHandler)
 
-	// Background PR sync.
-	go backgroundSync(ctx, logger, s, 30*time.Second)
+	// The agent plane's hot path over gRPC, for workers started with
+	// API_GRPC_ADDR. The REST endpoints stay for all other workers.
+	if cfg.AgentGRPCPort > 0 {
+		stopGRPC, err := serveAgentGRPC(logger, cfg, agentapi.NewGRPCServer(agentHandler, logger))
+		if err != nil {
+			return err
+		}
+		defer stopGRPC()
+	}
 
-	// Background stale task reaper.
-	taskTimeout := cfg.TaskTimeout
-	if taskTimeout == 0 {
-		taskTimeout = 5 * time.Minute
+	// Events published by other replicas, when they share an event bus.
+	if s.bus != nil {
+		logger.Info("sharing task events between replicas", "event_bus", eventBusDatabase)
+		stopBus := s.bus.Start(ctx, s.broker, eventbus.PendingWakers{s.task, s.epic, s.conversation})
+		defer stopBus()
 	}
-	go backgroundReaper(ctx, logger, s, 1*time.Minute, taskTimeout)
 
-	// Background stale epic reaper.
-	go backgroundEpicReaper(ctx, logger, s, 1*time.Minute, 15*time.Minute)
+	// Outbound webhook delivery. Every replica delivers the events published
+	// by its own broker.
+	go s.webhook.Run(ctx, s.broker)
+
+	// Singleton background jobs only run on the replica holding the leader
+	// lease, so multiple API replicas don't duplicate GitHub calls or race
+	// retries.
+	stopLeader := s.leader.Start(ctx)
+	defer stopLeader()
+
+	// Background reload of settings, so changes made through other replicas
+	// take effect here too. Not gated on the leader lease, since every
+	// replica caches settings.
+	go backgroundSettingRefresh(ctx, logger, s, settingRefreshInterval)
+
+	// Background PR sync. The interval can be changed at runtime through
+	// the sync_interval setting and overridden per repo.
+	go backgroundSync(ctx, logger, s, j)
+
+	// Background stale task, epic and conversation reaper. The interval can
+	// be changed at runtime through the reap_interval setting.
+	reapInterval := cfg.ReapInterval
+	if reapInterval == 0 {
+		reapInterval = defaultReapInterval
+	}
+	go backgroundReaper(ctx, logger, s, j, reapInterval)
+
+	// Background release of tasks held by workers that disappeared. Each
+	// replica tracks the workers that reach it, so this isn't gated on the
+	// leader lease.
+	go backgroundWorkerRelease(ctx, logger, s, workerReg, workerTimeout/4, workerTimeout)
 
 	// Background epic completion checker.
 	go backgroundEpicCompletion(ctx, logger, s, 30*time.Second)
 
-	// Background stale conversation reaper.
-	go backgroundConversationReaper(ctx, logger, s, 1*time.Minute, 15*time.Minute)
+	// Background review SLA checker.
+	go backgroundReviewSLA(ctx, logger, s, task.ReviewSLACheckInterval)
 
 	// Background conversation retention archival.
 	convRetention := cfg.ConversationRetention
@@ -197,15 +575,105 @@ func serve(ctx context.Context, logger log.Logger, cfg Config, s stores) error {
 	}
 	go backgroundConversationArchival(ctx, logger, s, 1*time.Hour, convRetention)
 
+	// Background WAL checkpointing. Every replica checkpoints its own
+	// database file, so this isn't gated on the leader lease.
+	if s.fileDB != nil && cfg.CheckpointInterval > 0 {
+		go backgroundCheckpoint(ctx, logger, s.fileDB, cfg.CheckpointInterval)
+	}
+
+	// Scheduled backups.
+	if cfg.BackupDir != "" {
+		if s.fileDB == nil {
+			logger.Warn("scheduled backups disabled: BACKUP_DIR requires file-backed sqlite")
+		} else {
+			backupInterval := cfg.BackupInterval
+			if backupInterval == 0 {
+				backupInterval = defaultBackupInterval
+			}
+			logger.Info("scheduled backups enabled", "backup.dir", cfg.BackupDir, "backup.interval", backupInterval.String())
+			go backgroundBackup(ctx, logger, s, j, backupInterval)
+		}
+	}
+
+	// Background cost anomaly detection.
+	if cfg.CostAnomalyDetection {
+		autoPause := cfg.CostAnomalyAutoPause
+		if autoPause && cfg.AdminToken == "" {
+			logger.Warn("cost anomaly auto-pause disabled: a pause can only be acknowledged with ADMIN_TOKEN set")
+			autoPause = false
+		}
+		logger.Info("cost anomaly detection enabled", "cost_anomaly.auto_pause", autoPause)
+		analyzer := costguard.NewAnalyzer(s.costs, s.killSwitch, s.notification, costguard.Config{
+			TaskFactor:        cfg.CostAnomalyTaskFactor,
+			SpikeFactor:       cfg.CostAnomalySpikeFactor,
+			MinHourlySpendUSD: cfg.CostAnomalyMinHourlyUSD,
+			AutoPause:         autoPause,
+		}, logger)
+		go backgroundCostAnalysis(ctx, logger, s, analyzer, costAnalysisInterval)
+	}
+
 	// Background log retention cleanup.
 	if cfg.LogRetention > 0 {
 		logger.Info("log retention enabled", "log.retention", cfg.LogRetention.String())
 		go backgroundLogRetention(ctx, logger, s, 1*time.Hour, cfg.LogRetention)
 	}
 
+	// Background artifact retention cleanup.
+	if cfg.ArtifactRetention > 0 {
+		logger.Info("artifact retention enabled", "artifact.retention", cfg.ArtifactRetention.String())
+		go backgroundArtifactRetention(ctx, logger, s, 1*time.Hour, cfg.ArtifactRetention)
+	}
+
+	// Background purge of tasks deleted longer than the trash retention ago.
+	if cfg.TaskTrashRetention > 0 {
+		logger.Info("task trash enabled", "task.trash_retention", cfg.TaskTrashRetention.String())
+		go backgroundTrashPurge(ctx, logger, s, 1*time.Hour)
+	}
+
+	// Background archival of tasks merged or closed longer than TaskArchiveAfter ago.
+	if cfg.TaskArchiveAfter > 0 {
+		logger.Info("task archival enabled", "task.archive_after", cfg.TaskArchiveAfter.String())
+		go backgroundTaskArchival(ctx, logger, s, 1*time.Hour, cfg.TaskArchiveAfter)
+	}
+
 	return Serve(ctx, logger, srv)
 }
 
+// serveAgentGRPC serves the agent gRPC service on cfg.AgentGRPCPort until the
+// returned stop function is called. The service hands out GitHub tokens, so
+// it is served over TLS when cfg has a certificate.
+func serveAgentGRPC(logger log.Logger, cfg Config, svc *agentapi.GRPCServer) (stop func(), err error) {
+	opts := svc.ServerOptions()
+	switch {
+	case cfg.AgentGRPCTLSCert != "" && cfg.AgentGRPCTLSKey != "":
+		creds, err := credentials.NewServerTLSFromFile(cfg.AgentGRPCTLSCert, cfg.AgentGRPCTLSKey)
+		if err != nil {
+			return nil, fmt.Errorf("load agent gRPC TLS certificate: %w", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	case cfg.AgentGRPCTLSCert != "" || cfg.AgentGRPCTLSKey != "":
+		return nil, errors.New("AGENT_GRPC_TLS_CERT and AGENT_GRPC_TLS_KEY must be set together")
+	default:
+		logger.Warn("agent grpc server is not using tls, github tokens will be sent in plaintext", "grpc.port", cfg.AgentGRPCPort)
+	}
+
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.AgentGRPCPort))
+	if err != nil {
+		return nil, fmt.Errorf("listen for agent gRPC: %w", err)
+	}
+	srv := grpc.NewServer(opts...)
+	svc.Register(srv)
+
+	logger.Info("starting agent gRPC server", "grpc.address", lis.Addr().String())
+	go func() {
+		if err := srv.Serve(lis); err != nil {
+			logger.Error("agent gRPC server failed", "error", err)
+		}
+	}()
+	// Polls are held open for up to 30 seconds, so don't wait for them.
+	return srv.Stop, nil
+}
+
 // Serve starts the server and blocks until the context is cancelled.
 func Serve(ctx context.Context, logger log.Logger, srv *server.Server) error {
 	errs := make(chan error)
@@ -233,8 +701,20 @@ func Serve(ctx context.Context, logger log.Logger, srv *server.Server) error {
 	}
 }
 
-func backgroundReaper(ctx context.Context, logger log.Logger, s stores, interval, timeout time.Duration) {
-	logger = logger.With("component", "task_reaper")
+func backgroundReaper(ctx context.Context, logger log.Logger, s stores, j *jobs, interval time.Duration) {
+	logger = logger.With("component", "reaper")
+	runEvery(ctx, logger, s.setting, setting.KeyReapInterval, interval, func() {
+		if !s.leader.IsLeader() {
+			return
+		}
+		if _, err := j.Reap(ctx); err != nil {
+			logger.Error("failed to reap stale work", "error", err)
+		}
+	})
+}
+
+func backgroundEpicCompletion(ctx context.Context, logger log.Logger, s stores, interval time.Duration) {
+	logger = logger.With("component", "epic_completion")
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
@@ -243,58 +723,78 @@ func backgroundReaper(ctx context.Context, logger log.Logger, s stores, interval
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			count, err := s.task.TimeoutStaleTasks(ctx, timeout)
+			if !s.leader.IsLeader() {
+				continue
+			}
+			count, err := s.epic.CheckActiveEpicsCompletion(ctx)
 			if err != nil {
-				logger.Error("failed to timeout stale tasks", "error", err)
+				logger.Error("failed to check epic completion", "error", err)
 			} else if count > 0 {
-				logger.Info("timed out stale tasks", "count", count)
+				logger.Info("epics completed", "count", count)
 			}
 		}
 	}
 }
 
-func backgroundEpicReaper(ctx context.Context, logger log.Logger, s stores, interval, timeout time.Duration) {
-	logger = logger.With("component", "epic_reaper")
-	ticker := time.NewTicker(interval)
+// backgroundSync syncs each repo's pull requests once its sync interval has
+// passed, and the repos whose sync was requested through the API as soon as
+// they are.
+func backgroundSync(ctx context.Context, logger log.Logger, s stores, j *jobs) {
+	logger = logger.With("component", "pr_sync")
+	ticker := time.NewTicker(syncTick)
 	defer ticker.Stop()
 
 	for {
+		var err error
 		select {
 		case <-ctx.Done():
 			return
+		case <-j.syncRequested:
+			// Requested syncs run on the replica that received the request,
+			// leader or not, and don't wait out the rate limit.
+			_, err = j.SyncRequested(ctx)
 		case <-ticker.C:
-			count, err := s.epic.TimeoutStaleEpics(ctx, timeout)
-			if err != nil {
-				logger.Error("failed to timeout stale epics", "error", err)
-			} else if count > 0 {
-				logger.Info("timed out stale epics", "count", count)
+			if !s.leader.IsLeader() {
+				continue
 			}
+			// Sync is background work: near the rate limit it waits for the
+			// reset rather than spend the requests left for agents.
+			_, err = j.SyncDue(github.Background(ctx))
+		}
+		if err != nil && !errors.Is(err, adminapi.ErrGitHubNotConfigured) {
+			logger.Error("failed to sync pull requests", "error", err)
 		}
 	}
 }
 
-func backgroundEpicCompletion(ctx context.Context, logger log.Logger, s stores, interval time.Duration) {
-	logger = logger.With("component", "epic_completion")
-	ticker := time.NewTicker(interval)
+// runEvery calls fn every interval until ctx is done. The duration setting
+// key overrides interval; when it changes the wait restarts with the new
+// interval.
+func runEvery(ctx context.Context, logger log.Logger, settings *setting.Service, key string, interval time.Duration, fn func()) {
+	changes, stop := settings.Subscribe(key)
+	defer stop()
+	current := settings.Duration(key, interval)
+	ticker := time.NewTicker(current)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
-			count, err := s.epic.CheckActiveEpicsCompletion(ctx)
-			if err != nil {
-				logger.Error("failed to check epic completion", "error", err)
-			} else if count > 0 {
-				logger.Info("epics completed", "count", count)
+		case <-changes:
+			if next := settings.Duration(key, interval); next != current {
+				logger.Info("interval changed", "interval", next.String(), "setting", key)
+				current = next
+				ticker.Reset(current)
 			}
+		case <-ticker.C:
+			fn()
 		}
 	}
 }
 
-func backgroundSync(ctx context.Context, logger log.Logger, s stores, interval time.Duration) {
-	logger = logger.With("component", "pr_sync")
+func backgroundSettingRefresh(ctx context.Context, logger log.Logger, s stores, interval time.Duration) {
+	logger = logger.With("component", "setting_refresh")
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
@@ -303,133 +803,8 @@ func backgroundSync(ctx context.Context, logger log.Logger, s stores, interval t
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			if s.githubToken == nil {
-				continue
-			}
-			gh := s.githubToken.GetClient()
-			if gh == nil {
-				continue
-			}
-			fineGrained := s.githubToken.IsFineGrained()
-
-			// Sync branch-only tasks: check if PRs were manually created.
-			branchTasks, err := s.task.ListTasksInReviewNoPR(ctx)
-			if err != nil {
-				logger.Error("failed to list branch-only tasks", "error", err)
-			} else {
-				for _, t := range branchTasks {
-					if t.BranchName == "" {
-						continue
-					}
-					// Look up repo for this task.
-					repoID, parseErr := repo.ParseRepoID(t.RepoID)
-					if parseErr != nil {
-						continue
-					}
-					r, readErr := s.repo.ReadRepo(ctx, repoID)
-					if readErr != nil {
-						continue
-					}
-					prURL, prNumber, findErr := gh.FindPRForBranch(ctx, r.Owner, r.Name, t.BranchName)
-					if findErr != nil {
-						logger.Error("failed to find pr for branch", "task.id", t.ID, "task.branch", t.BranchName, "error", findErr)
-						continue
-					}
-					if prNumber > 0 {
-						if err := s.task.SetTaskPullRequest(ctx, t.ID, prURL, prNumber); err != nil {
-							logger.Error("failed to link pr to task", "task.id", t.ID, "error", err)
-						} else {
-							logger.Info("linked pr to branch-only task", "task.id", t.ID, "pr.number", prNumber)
-						}
-					}
-				}
-			}
-
-			repos, err := s.repo.ListRepos(ctx)
-			if err != nil {
-				logger.Error("failed to list repos", "error", err)
-				continue
-			}
-			for _, r := range repos {
-				tasks, err := s.task.ListTasksInReviewByRepo(ctx, r.ID.String())
-				if err != nil {
-					logger.Error("failed to list review tasks", "repo.full_name", r.FullName, "error", err)
-					continue
-				}
-				for _, t := range tasks {
-					if t.PRNumber <= 0 {
-						continue
-					}
-
-					// 1. Check if merged (terminal positive).
-					merged, err := gh.IsPRMerged(ctx, r.Owner, r.Name, t.PRNumber)
-					if err != nil {
-						logger.Error("failed to check pr merged", "task.id", t.ID, "error", err)
-						continue
-					}
-					if merged {
-						if err := s.task.UpdateTaskStatus(ctx, t.ID, task.StatusMerged); err != nil {
-							logger.Error("failed to update task status", "task.id", t.ID, "error", err)
-						} else {
-							logger.Info("task pr merged", "task.id", t.ID)
-						}
-						continue
-					}
-
-					// 2. Check for merge conflicts.
-					mergeability, err := gh.GetPRMergeability(ctx, r.Owner, r.Name, t.PRNumber)
-					if err != nil {
-						logger.Error("failed to check mergeability", "task.id", t.ID, "error", err)
-						continue
-					}
-					if mergeability.HasConflicts {
-						logger.Info("pr has merge conflicts, retrying", "task.id", t.ID, "task.attempt", t.Attempt)
-						reason := "merge_conflict: PR has conflicts with base branch"
-						if err := s.task.RetryTask(ctx, t.ID, "merge_conflict", reason); err != nil {
-							logger.Error("failed to retry task", "task.id", t.ID, "error", err)
-						}
-						continue
-					}
-
-					// 3. Check CI status (skipped for fine-grained tokens).
-					if fineGrained {
-						continue
-					}
-					checkResult, err := gh.GetPRCheckStatus(ctx, r.Owner, r.Name, t.PRNumber)
-					if err != nil {
-						logger.Error("failed to check ci status", "task.id", t.ID, "error", err)
-						continue
-					}
-					if checkResult.Status == github.CheckStatusFailure {
-						logger.Info("pr checks failed, retrying", "task.id", t.ID, "task.attempt", t.Attempt, "check.summary", checkResult.Summary)
-
-						// Fetch actual CI failure logs for targeted retry
-						failureLogs, logErr := gh.GetFailedCheckLogs(ctx, r.Owner, r.Name, t.PRNumber)
-						if logErr != nil {
-							logger.Warn("failed to fetch ci logs", "task.id", t.ID, "error", logErr)
-						} else if failureLogs != "" {
-							if err := s.task.SetRetryContext(ctx, t.ID, failureLogs); err != nil {
-								logger.Warn("failed to set retry context", "task.id", t.ID, "error", err)
-							}
-						}
-
-						// Build category from failed check names so the circuit
-						// breaker only trips when the exact same checks keep failing.
-						category := "ci_failure"
-						if len(checkResult.FailedNames) > 0 {
-							names := make([]string, len(checkResult.FailedNames))
-							copy(names, checkResult.FailedNames)
-							sort.Strings(names)
-							category = "ci_failure:" + strings.Join(names, ",")
-						}
-						reason := fmt.Sprintf("%s: %s", category, checkResult.Summary)
-						if err := s.task.RetryTask(ctx, t.ID, category, reason); err != nil {
-							logger.Error("failed to retry task", "task.id", t.ID, "error", err)
-						}
-						continue
-					}
-					// If pending, do nothing — wait for checks to complete.
-				}
+			if err := s.setting.Load(ctx); err != nil {
+				logger.Error("failed to reload settings", "error", err)
 			}
 		}
 	}
@@ -451,15 +826,80 @@ func planningEpicListerAdapter(epicStore *epic.Store) *metric.PlanningEpicLister
 				Title:     ep.Title,
 				RepoID:    ep.RepoID,
 				Model:     ep.Model,
+				CostUSD:   ep.CostUSD,
 				ClaimedAt: ep.ClaimedAt,
 			}
 		}
 		return result, nil
-	})
+	}).WithPlanningCost(epicStore.TotalPlanningCost)
 }
 
-func backgroundConversationReaper(ctx context.Context, logger log.Logger, s stores, interval, timeout time.Duration) {
-	logger = logger.With("component", "conversation_reaper")
+// capabilities reports the features and limits the server's configuration
+// enables, for clients deciding what to render.
+func capabilities(cfg Config, s stores, taskTimeout time.Duration) capabilityapi.Capabilities {
+	return capabilityapi.Capabilities{
+		Version: cfg.Version,
+		Features: capabilityapi.Features{
+			Admin:                cfg.AdminToken != "",
+			Auth:                 cfg.RequireAuth,
+			MCP:                  cfg.MCPToken != "",
+			GitHubTokenStorage:   s.githubToken != nil,
+			ProvenanceScan:       cfg.ProvenanceScan,
+			SelfReview:           cfg.SelfReview,
+			CostAnomalyDetection: cfg.CostAnomalyDetection,
+			// Auto-pause needs the admin API to acknowledge a pause.
+			CostAnomalyAutoPause: cfg.CostAnomalyDetection && cfg.CostAnomalyAutoPause && cfg.AdminToken != "",
+		},
+		Limits: capabilityapi.Limits{
+			MaxLabels:             task.MaxLabels,
+			TaskTimeoutSeconds:    int64(taskTimeout.Seconds()),
+			LogRetentionSeconds:   int64(max(cfg.LogRetention, 0).Seconds()),
+			TrashRetentionSeconds: int64(max(cfg.TaskTrashRetention, 0).Seconds()),
+			ArchiveAfterSeconds:   int64(max(cfg.TaskArchiveAfter, 0).Seconds()),
+		},
+	}
+}
+
+// retryPolicyResolver resolves a repo's retry policy by merging the repo's
+// override over the instance-wide policy setting.
+func retryPolicyResolver(settingService *setting.Service, repoStore *repo.Store) task.RetryPolicyResolverFunc {
+	return func(ctx context.Context, repoID string) (task.RetryPolicy, error) {
+		policy, err := settingService.RetryPolicy()
+		if err != nil {
+			return task.RetryPolicy{}, err
+		}
+		id, err := repo.ParseRepoID(repoID)
+		if err != nil {
+			return task.RetryPolicy{}, err
+		}
+		r, err := repoStore.ReadRepo(ctx, id)
+		if err != nil {
+			return task.RetryPolicy{}, err
+		}
+		if r.RetryPolicy != nil {
+			policy = policy.Merge(*r.RetryPolicy)
+		}
+		return policy, nil
+	}
+}
+
+// teamDefaultsReader reads the settings repos inherit from their team.
+func teamDefaultsReader(teamStore *team.Store) repo.TeamDefaultsReaderFunc {
+	return func(ctx context.Context, teamID string) (repo.TeamDefaults, error) {
+		id, err := team.ParseTeamID(teamID)
+		if err != nil {
+			return repo.TeamDefaults{}, err
+		}
+		t, err := teamStore.ReadTeam(ctx, id)
+		if err != nil {
+			return repo.TeamDefaults{}, err
+		}
+		return repo.TeamDefaults{Model: t.DefaultModel, MaxCostUSD: t.DefaultMaxCostUSD}, nil
+	}
+}
+
+func backgroundCostAnalysis(ctx context.Context, logger log.Logger, s stores, analyzer *costguard.Analyzer, interval time.Duration) {
+	logger = logger.With("component", "cost_analysis")
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
@@ -468,11 +908,11 @@ func backgroundConversationReaper(ctx context.Context, logger log.Logger, s stor
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			count, err := s.conversation.TimeoutStaleConversations(ctx, timeout)
-			if err != nil {
-				logger.Error("failed to timeout stale conversations", "error", err)
-			} else if count > 0 {
-				logger.Info("timed out stale conversations", "count", count)
+			if !s.leader.IsLeader() {
+				continue
+			}
+			if _, err := analyzer.Analyze(ctx); err != nil {
+				logger.Error("failed to analyze spend", "error", err)
 			}
 		}
 	}
@@ -488,6 +928,9 @@ func backgroundConversationArchival(ctx context.Context, logger log.Logger, s st
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
+			if !s.leader.IsLeader() {
+				continue
+			}
 			count, err := s.conversation.ArchiveOldConversations(ctx, retention)
 			if err != nil {
 				logger.Error("failed to archive old conversations", "error", err)
@@ -498,10 +941,138 @@ func backgroundConversationArchival(ctx context.Context, logger log.Logger, s st
 	}
 }
 
+func backgroundTrashPurge(ctx context.Context, logger log.Logger, s stores, interval time.Duration) {
+	logger = logger.With("component", "trash_purge")
+
+	purge := func() {
+		if !s.leader.IsLeader() {
+			return
+		}
+		count, err := s.task.PurgeTrash(ctx)
+		if err != nil {
+			logger.Error("failed to purge trashed tasks", "error", err)
+		} else if count > 0 {
+			logger.Info("purged trashed tasks", "count", count, "task.trash_retention", s.task.TrashRetention().String())
+		}
+	}
+
+	// Run immediately on startup.
+	purge()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purge()
+		}
+	}
+}
+
+func backgroundTaskArchival(ctx context.Context, logger log.Logger, s stores, interval, after time.Duration) {
+	logger = logger.With("component", "task_archival")
+
+	archive := func() {
+		if !s.leader.IsLeader() {
+			return
+		}
+		count, err := s.task.ArchiveTasks(ctx, after)
+		if err != nil {
+			logger.Error("failed to archive tasks", "error", err, "count", count)
+		} else if count > 0 {
+			logger.Info("archived tasks", "count", count, "task.archive_after", after.String())
+		}
+	}
+
+	// Run immediately on startup.
+	archive()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			archive()
+		}
+	}
+}
+
+func backgroundReviewSLA(ctx context.Context, logger log.Logger, s stores, interval time.Duration) {
+	logger = logger.With("component", "review_sla")
+
+	check := func() {
+		if !s.leader.IsLeader() {
+			return
+		}
+		overdue, err := s.task.CheckReviewSLAs(ctx)
+		if err != nil {
+			logger.Error("failed to check review SLAs", "error", err)
+		}
+		for _, o := range overdue {
+			s.notification.ReviewOverdue(ctx, o)
+		}
+		if len(overdue) > 0 {
+			logger.Info("reported overdue reviews", "count", len(overdue))
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			check()
+		}
+	}
+}
+
+func backgroundArtifactRetention(ctx context.Context, logger log.Logger, s stores, interval, retention time.Duration) {
+	logger = logger.With("component", "artifact_retention")
+
+	cleanup := func() {
+		if !s.leader.IsLeader() {
+			return
+		}
+		count, err := s.task.DeleteExpiredArtifacts(ctx, retention)
+		if err != nil {
+			logger.Error("failed to delete expired artifacts", "error", err)
+		} else if count > 0 {
+			logger.Info("deleted expired artifacts", "count", count, "artifact.retention", retention.String())
+		}
+	}
+
+	// Run immediately on startup.
+	cleanup()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cleanup()
+		}
+	}
+}
+
 func backgroundLogRetention(ctx context.Context, logger log.Logger, s stores, interval, retention time.Duration) {
 	logger = logger.With("component", "log_retention")
 
 	cleanup := func() {
+		if !s.leader.IsLeader() {
+			return
+		}
 		count, err := s.task.DeleteExpiredLogs(ctx, retention)
 		if err != nil {
 			logger.Error("failed to delete expired logs", "error", err)
@@ -525,3 +1096,56 @@ func backgroundLogRetention(ctx context.Context, logger log.Logger, s stores, in
 		}
 	}
 }
+
+func backgroundWorkerRelease(ctx context.Context, logger log.Logger, s stores, reg *workertracker.Registry, interval, timeout time.Duration) {
+	logger = logger.With("component", "worker_release")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			before := time.Now().Add(-timeout)
+			for _, w := range reg.RemoveStale(timeout) {
+				released := 0
+				for _, rawID := range w.ActiveTaskIDs {
+					id, err := task.ParseTaskID(rawID)
+					if err != nil {
+						continue
+					}
+					ok, err := s.task.ReleaseOrphanedTask(ctx, id, before)
+					if err != nil {
+						logger.Error("failed to release orphaned task", "error", err, "task.id", rawID)
+						continue
+					}
+					if ok {
+						released++
+					}
+				}
+				logger.Warn("worker disappeared", "worker.id", w.WorkerID, "worker.hostname", w.Hostname, "worker.released_tasks", released)
+			}
+		}
+	}
+}
+
+func backgroundCheckpoint(ctx context.Context, logger log.Logger, db *sqlite.FileDB, interval time.Duration) {
+	logger = logger.With("component", "wal_checkpoint")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			res, err := db.Checkpoint(ctx)
+			if err != nil {
+				logger.Error("failed to checkpoint wal", "error", err)
+			} else if res.Busy {
+				logger.Warn("wal checkpoint incomplete: database busy", "wal.frames", res.LogFrames, "wal.checkpointed_frames", res.CheckpointedFrames)
+			}
+		}
+	}
+}
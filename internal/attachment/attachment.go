@@ -0,0 +1,93 @@
+// Package attachment stores the contents of files attached to tasks, either
+// in a local directory or in an S3 bucket. Attachment metadata lives in the
+// database; storage only holds the bytes, addressed by key.
+package attachment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Storage backends accepted by Config.Backend.
+const (
+	BackendDir = "dir"
+	BackendS3  = "s3"
+)
+
+// ErrNotFound is returned by Storage.Get when no object is stored under the
+// key.
+var ErrNotFound = errors.New("attachment not found in storage")
+
+// Storage holds attachment contents by key. Keys are slash-separated paths
+// made of letters, digits, '-', '_' and '.'.
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object stored under key. Deleting a key that holds
+	// nothing is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// Config selects and configures the storage backend.
+type Config struct {
+	Backend string // BackendDir (default) or BackendS3
+	Dir     string // Directory for BackendDir
+	S3      S3Config
+
+	HTTPClient *http.Client // Client for BackendS3; nil uses a default
+}
+
+// New creates the Storage for cfg. It returns nil when the dir backend is
+// selected without a directory, in which case attachments are disabled.
+func New(cfg Config) (Storage, error) {
+	switch cfg.Backend {
+	case "", BackendDir:
+		if cfg.Dir == "" {
+			return nil, nil
+		}
+		s, err := NewDirStorage(cfg.Dir)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	case BackendS3:
+		s, err := NewS3Storage(cfg.S3, cfg.HTTPClient)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	default:
+		return nil, fmt.Errorf("unknown attachment storage %q (expected %s or %s)", cfg.Backend, BackendDir, BackendS3)
+	}
+}
+
+// validKey reports whether key is safe to use as a relative file path and
+// an S3 object name.
+func validKey(key string) bool {
+	if key == "" || key[0] == '/' || key[len(key)-1] == '/' {
+		return false
+	}
+	prev := byte('/')
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_':
+		case c == '.':
+			// Segments may not start with a dot, which rules out "." and "..".
+			if prev == '/' {
+				return false
+			}
+		case c == '/':
+			if prev == '/' {
+				return false
+			}
+		default:
+			return false
+		}
+		prev = c
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package attachment
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStorageRoundTrip(t *testing.T, s Storage) {
+	t.Helper()
+	ctx := context.Background()
+
+	require.NoError(t, s.Put(ctx, "tsk_1/a.png", []byte("contents")))
+	rc, err := s.Get(ctx, "tsk_1/a.png")
+	require.NoError(t, err)
+	b, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	assert.Equal(t, "contents", string(b))
+
+	require.NoError(t, s.Delete(ctx, "tsk_1/a.png"))
+	_, err = s.Get(ctx, "tsk_1/a.png")
+	assert.ErrorIs(t, err, ErrNotFound)
+	require.NoError(t, s.Delete(ctx, "tsk_1/a.png"), "deleting a missing key should succeed")
+}
+
+func TestDirStorage(t *testing.T) {
+	s, err := NewDirStorage(t.TempDir())
+	require.NoError(t, err)
+	testStorageRoundTrip(t, s)
+
+	for _, key := range []string{"", "../escape", "tsk_1/../../escape", "/abs", "tsk_1/", "a//b", "tsk_1/.hidden", "a b"} {
+		assert.Error(t, s.Put(context.Background(), key, []byte("x")), key)
+	}
+}
+
+func TestS3Storage(t *testing.T) {
+	var mu sync.Mutex
+	objects := map[string][]byte{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "20260102T030405Z", r.Header.Get("X-Amz-Date"))
+		assert.NotEmpty(t, r.Header.Get("X-Amz-Content-Sha256"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"),
+			"AWS4-HMAC-SHA256 Credential=AKID/20260102/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature="))
+		assert.True(t, strings.HasPrefix(r.URL.Path, "/bucket/prefix/"), r.URL.Path)
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			b, _ := io.ReadAll(r.Body)
+			assert.Equal(t, sha256Hex(b), r.Header.Get("X-Amz-Content-Sha256"))
+			objects[r.URL.Path] = b
+		case http.MethodGet:
+			b, ok := objects[r.URL.Path]
+			if !ok {
+				http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(b)
+		case http.MethodDelete:
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	s, err := NewS3Storage(S3Config{
+		Bucket:          "bucket",
+		Prefix:          "prefix/",
+		Region:          "us-east-1",
+		Endpoint:        srv.URL,
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+	}, srv.Client())
+	require.NoError(t, err)
+	s.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+	testStorageRoundTrip(t, s)
+}
+
+func TestS3Storage_ErrorResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "<Error><Code>AccessDenied</Code></Error>", http.StatusForbidden)
+	}))
+	t.Cleanup(srv.Close)
+
+	s, err := NewS3Storage(S3Config{Bucket: "bucket", Region: "us-east-1", Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "secret"}, srv.Client())
+	require.NoError(t, err)
+	err = s.Put(context.Background(), "tsk_1/a.png", []byte("x"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "AccessDenied")
+}
+
+func TestNew(t *testing.T) {
+	s, err := New(Config{})
+	require.NoError(t, err)
+	assert.Nil(t, s, "dir storage without a directory disables attachments")
+
+	_, err = New(Config{Backend: BackendS3})
+	assert.Error(t, err)
+
+	_, err = New(Config{Backend: "gcs"})
+	assert.Error(t, err)
+}
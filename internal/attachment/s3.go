@@ -0,0 +1,187 @@
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultHTTPClient is used by S3Storage when no client is given. Uploads
+// and downloads are bounded by the attachment size limit, so the timeout
+// leaves room for slow links.
+var defaultHTTPClient = &http.Client{Timeout: 2 * time.Minute}
+
+// maxErrorBody caps how much of an error response is included in errors.
+const maxErrorBody = 512
+
+// S3Config configures an S3Storage.
+type S3Config struct {
+	Bucket string
+	Prefix string // Prepended to every key, e.g. "verve/attachments/"
+	Region string
+	// Endpoint is the base URL of an S3-compatible service such as MinIO.
+	// Objects are then addressed path-style as <endpoint>/<bucket>/<key>.
+	// Empty uses AWS S3 with virtual-hosted addressing.
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // Set for temporary credentials
+}
+
+// S3Storage stores attachments as objects in an S3 bucket. Requests are
+// signed with static credentials; instance and task roles are not resolved.
+type S3Storage struct {
+	cfg     S3Config
+	client  *http.Client
+	baseURL string
+	now     func() time.Time
+}
+
+// NewS3Storage creates an S3Storage. A nil client uses a default one.
+func NewS3Storage(cfg S3Config, client *http.Client) (*S3Storage, error) {
+	if cfg.Bucket == "" || cfg.Region == "" {
+		return nil, errors.New("s3 attachment storage requires a bucket and region")
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, errors.New("s3 attachment storage requires AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	baseURL := "https://" + cfg.Bucket + ".s3." + cfg.Region + ".amazonaws.com/"
+	if cfg.Endpoint != "" {
+		baseURL = strings.TrimSuffix(cfg.Endpoint, "/") + "/" + cfg.Bucket + "/"
+	}
+	return &S3Storage{cfg: cfg, client: client, baseURL: baseURL, now: time.Now}, nil
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, data []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	return closeResponse(resp)
+}
+
+func (s *S3Storage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		_ = resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, closeResponse(resp)
+	}
+	return resp.Body, nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		_ = resp.Body.Close()
+		return nil
+	}
+	return closeResponse(resp)
+}
+
+// do sends a signed request for the object stored under key. The caller
+// closes the response body.
+func (s *S3Storage) do(ctx context.Context, method, key string, body []byte) (*http.Response, error) {
+	if !validKey(key) {
+		return nil, fmt.Errorf("invalid attachment key %q", key)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.baseURL+s.cfg.Prefix+key, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+	signS3(req, body, s.cfg, s.now())
+	return s.client.Do(req)
+}
+
+// closeResponse closes resp's body, returning an error describing it unless
+// it succeeded.
+func closeResponse(resp *http.Response) error {
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	return fmt.Errorf("%s %s: %s: %s", resp.Request.Method, resp.Request.URL.Redacted(), resp.Status, bytes.TrimSpace(b))
+}
+
+// signS3 adds an AWS Signature Version 4 Authorization header for S3 to req.
+// S3 additionally requires the payload hash in x-amz-content-sha256.
+func signS3(req *http.Request, body []byte, cfg S3Config, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", cfg.SessionToken)
+	}
+
+	// Headers must be listed in sorted order.
+	headers := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if cfg.SessionToken != "" {
+		headers = append(headers, "x-amz-security-token")
+	}
+	var canonicalHeaders strings.Builder
+	for _, h := range headers {
+		v := req.Header.Get(h)
+		if h == "host" {
+			v = req.URL.Host
+		}
+		canonicalHeaders.WriteString(h + ":" + strings.TrimSpace(v) + "\n")
+	}
+	signedHeaders := strings.Join(headers, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + cfg.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+cfg.SecretAccessKey), date)
+	key = hmacSHA256(key, cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
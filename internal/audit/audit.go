@@ -0,0 +1,58 @@
+package audit
+
+import "time"
+
+// Action identifies an audited operation.
+const (
+	ActionForceStatus = "force_status"
+	// ActionStatusChange records a task moving to a new status through its
+	// normal lifecycle.
+	ActionStatusChange = "status_change"
+)
+
+// Entity types recorded in the audit log.
+const (
+	EntityTask = "task"
+	EntityEpic = "epic"
+)
+
+// Entry records an admin operation that bypassed the normal lifecycle of an
+// entity, or a lifecycle transition worth keeping a history of.
+type Entry struct {
+	ID         EntryID   `json:"id"`
+	Action     string    `json:"action"`
+	EntityType string    `json:"entity_type"`
+	EntityID   string    `json:"entity_id"`
+	FromStatus string    `json:"from_status,omitempty"`
+	ToStatus   string    `json:"to_status,omitempty"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// NewStatusChangeEntry creates an Entry for a forced status change.
+func NewStatusChangeEntry(entityType, entityID, from, to, reason string) *Entry {
+	return &Entry{
+		ID:         NewEntryID(),
+		Action:     ActionForceStatus,
+		EntityType: entityType,
+		EntityID:   entityID,
+		FromStatus: from,
+		ToStatus:   to,
+		Reason:     reason,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// NewLifecycleEntry creates an Entry for a status change made through the
+// entity's normal lifecycle.
+func NewLifecycleEntry(entityType, entityID, to, reason string) *Entry {
+	return &Entry{
+		ID:         NewEntryID(),
+		Action:     ActionStatusChange,
+		EntityType: entityType,
+		EntityID:   entityID,
+		ToStatus:   to,
+		Reason:     reason,
+		CreatedAt:  time.Now(),
+	}
+}
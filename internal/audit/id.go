@@ -0,0 +1,25 @@
+package audit
+
+import (
+	"github.com/joshjon/kit/id"
+	"go.jetify.com/typeid"
+)
+
+type entryPrefix struct{}
+
+func (entryPrefix) Prefix() string { return "aud" }
+
+// EntryID is the unique identifier for an audit log Entry.
+type EntryID struct {
+	typeid.TypeID[entryPrefix]
+}
+
+// NewEntryID generates a new unique EntryID.
+func NewEntryID() EntryID {
+	return id.New[EntryID]()
+}
+
+// MustParseEntryID parses a string into an EntryID, panicking on failure.
+func MustParseEntryID(s string) EntryID {
+	return id.MustParse[EntryID](s)
+}
@@ -0,0 +1,11 @@
+package audit
+
+import "context"
+
+// Repository is the data access interface for the audit log.
+type Repository interface {
+	CreateEntry(ctx context.Context, entry *Entry) error
+	// ListEntries returns the most recent entries, newest first. When
+	// entityID is non-empty only entries for that entity are returned.
+	ListEntries(ctx context.Context, entityID string, limit int) ([]*Entry, error)
+}
@@ -0,0 +1,81 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/joshjon/kit/log"
+
+	"github.com/vervesh/verve/internal/logkey"
+	"github.com/vervesh/verve/internal/task"
+)
+
+// transitionLookback bounds how many of an entity's entries LastTransition
+// searches.
+const transitionLookback = 50
+
+// Store records and lists audit log entries. Every admin entry is also
+// written to the server log so the record survives even if persisting it
+// fails.
+type Store struct {
+	repo   Repository
+	logger log.Logger
+}
+
+// NewStore creates a new Store backed by the given Repository.
+func NewStore(repo Repository, logger log.Logger) *Store {
+	return &Store{
+		repo:   repo,
+		logger: logger.With("component", "audit"),
+	}
+}
+
+// Record logs and persists an audit entry.
+func (s *Store) Record(ctx context.Context, entry *Entry) error {
+	s.logger.Warn("admin action",
+		"audit.id", entry.ID.String(),
+		"audit.action", entry.Action,
+		"audit.entity_type", entry.EntityType,
+		"audit.entity_id", entry.EntityID,
+		"audit.from_status", entry.FromStatus,
+		"audit.to_status", entry.ToStatus,
+		"audit.reason", entry.Reason,
+	)
+	return s.repo.CreateEntry(ctx, entry)
+}
+
+// ListEntries returns the most recent entries, newest first, optionally
+// filtered to a single entity.
+func (s *Store) ListEntries(ctx context.Context, entityID string, limit int) ([]*Entry, error) {
+	return s.repo.ListEntries(ctx, entityID, limit)
+}
+
+// TaskStatusChanged implements task.StatusListener. It records every status
+// a task moves to so completions can be charted over time and the time tasks
+// spend in each status measured.
+func (s *Store) TaskStatusChanged(ctx context.Context, t *task.Task) {
+	var reason string
+	switch t.Status {
+	case task.StatusMerged, task.StatusClosed, task.StatusFailed:
+		reason = t.CloseReason
+	}
+	entry := NewLifecycleEntry(EntityTask, t.ID.String(), string(t.Status), reason)
+	if err := s.repo.CreateEntry(ctx, entry); err != nil {
+		s.logger.Error("failed to record task status change", logkey.TaskID, t.ID.String(), "error", err)
+	}
+}
+
+// LastTransition returns when the entity last moved to status, or false when
+// the log has no record of it.
+func (s *Store) LastTransition(ctx context.Context, entityID, status string) (time.Time, bool, error) {
+	entries, err := s.repo.ListEntries(ctx, entityID, transitionLookback)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	for _, e := range entries {
+		if e.ToStatus == status {
+			return e.CreatedAt, true, nil
+		}
+	}
+	return time.Time{}, false, nil
+}
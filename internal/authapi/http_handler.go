@@ -0,0 +1,179 @@
+package authapi
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joshjon/kit/log"
+	"github.com/joshjon/kit/server"
+	"github.com/labstack/echo/v4"
+
+	"github.com/vervesh/verve/internal/msgcat"
+	"github.com/vervesh/verve/internal/oidc"
+	"github.com/vervesh/verve/internal/user"
+)
+
+const (
+	// loginCookie holds the encrypted state of a sign-in in progress.
+	loginCookie = "verve_oidc_login"
+	// uiCallbackPath is the UI page that receives the session token, in the
+	// URL fragment so it never reaches server logs.
+	uiCallbackPath = "/auth/callback"
+)
+
+// HTTPHandler handles single sign-on HTTP requests. Its endpoints are public:
+// they are how callers get a token in the first place.
+type HTTPHandler struct {
+	oidcService *oidc.Service
+	userStore   *user.Store
+	sessionTTL  time.Duration
+	logger      log.Logger
+}
+
+// NewHTTPHandler creates a new HTTPHandler issuing sessions lasting
+// sessionTTL. oidcService is nil when no encryption key is configured.
+func NewHTTPHandler(oidcService *oidc.Service, userStore *user.Store, sessionTTL time.Duration, logger log.Logger) *HTTPHandler {
+	return &HTTPHandler{oidcService: oidcService, userStore: userStore, sessionTTL: sessionTTL, logger: logger}
+}
+
+// Register adds the endpoints to the provided Echo router group.
+func (h *HTTPHandler) Register(g *echo.Group) {
+	g.GET("/auth/oidc", h.GetOIDCStatus)
+	g.GET("/auth/oidc/login", h.Login)
+	g.GET("/auth/oidc/callback", h.Callback)
+	g.POST("/auth/logout", h.Logout)
+}
+
+// GetOIDCStatus handles GET /auth/oidc
+// The UI offers single sign-on when it is enabled.
+func (h *HTTPHandler) GetOIDCStatus(c echo.Context) error {
+	enabled := false
+	if h.oidcService != nil {
+		_, err := h.oidcService.Config(c.Request().Context())
+		enabled = err == nil
+	}
+	return server.SetResponse(c, http.StatusOK, OIDCStatusResponse{Enabled: enabled})
+}
+
+// Login handles GET /auth/oidc/login?redirect=/path
+// It sends the user to the provider to sign in.
+func (h *HTTPHandler) Login(c echo.Context) error {
+	if h.oidcService == nil {
+		return echo.NewHTTPError(http.StatusNotFound, msgcat.Text(msgcat.ErrOIDCNotConfigured))
+	}
+	ctx := c.Request().Context()
+
+	provider, err := h.oidcService.Provider(ctx)
+	if errors.Is(err, oidc.ErrNotConfigured) {
+		return echo.NewHTTPError(http.StatusNotFound, msgcat.Text(msgcat.ErrOIDCNotConfigured))
+	}
+	if err != nil {
+		return err
+	}
+
+	login := oidc.NewLogin(c.QueryParam("redirect"))
+	sealed, err := h.oidcService.SealLogin(ctx, login)
+	if err != nil {
+		return err
+	}
+	c.SetCookie(h.cookie(c, sealed, time.Unix(login.ExpiresAt, 0)))
+	return c.Redirect(http.StatusFound, provider.AuthCodeURL(login.State, login.Nonce, login.Verifier))
+}
+
+// Callback handles GET /auth/oidc/callback?code=xxx&state=xxx
+// It finishes the sign-in and hands the UI a session token whose role comes
+// from the user's groups. Failures are shown by the UI too.
+func (h *HTTPHandler) Callback(c echo.Context) error {
+	if h.oidcService == nil {
+		return echo.NewHTTPError(http.StatusNotFound, msgcat.Text(msgcat.ErrOIDCNotConfigured))
+	}
+	ctx := c.Request().Context()
+
+	cookie, err := c.Cookie(loginCookie)
+	if err != nil {
+		return h.failLogin(c, msgcat.Text(msgcat.ErrOIDCLoginExpired))
+	}
+	c.SetCookie(h.cookie(c, "", time.Unix(0, 0)))
+
+	login, err := h.oidcService.OpenLogin(ctx, cookie.Value)
+	if err != nil || login.Expired() || login.State != c.QueryParam("state") {
+		return h.failLogin(c, msgcat.Text(msgcat.ErrOIDCLoginExpired))
+	}
+	if reason := c.QueryParam("error"); reason != "" {
+		if desc := c.QueryParam("error_description"); desc != "" {
+			reason = desc
+		}
+		return h.failLogin(c, msgcat.Text(msgcat.ErrOIDCLoginFailed, reason))
+	}
+
+	provider, err := h.oidcService.Provider(ctx)
+	if err != nil {
+		return h.failLogin(c, msgcat.Text(msgcat.ErrOIDCNotConfigured))
+	}
+	idToken, err := provider.Exchange(ctx, c.QueryParam("code"), login.Verifier)
+	if err != nil {
+		h.logger.Warn("oidc code exchange failed", "error", err)
+		return h.failLogin(c, msgcat.Text(msgcat.ErrOIDCLoginFailed, "the identity provider rejected the sign-in"))
+	}
+	claims, err := provider.VerifyIDToken(ctx, idToken, login.Nonce)
+	if err != nil {
+		h.logger.Warn("oidc id token rejected", "error", err)
+		return h.failLogin(c, msgcat.Text(msgcat.ErrOIDCLoginFailed, "the identity provider's token is invalid"))
+	}
+
+	cfg, err := h.oidcService.Config(ctx)
+	if err != nil {
+		return h.failLogin(c, msgcat.Text(msgcat.ErrOIDCNotConfigured))
+	}
+	role, ok := cfg.RoleFor(claims.Groups)
+	if !ok {
+		return h.failLogin(c, msgcat.Text(msgcat.ErrOIDCNoRole))
+	}
+
+	session, token, err := h.userStore.CreateSession(ctx, claims.Subject, claims.DisplayName(), role, h.sessionTTL)
+	if err != nil {
+		return err
+	}
+	h.logger.Info("oidc sign-in", "oidc.subject", claims.Subject, "user.role", role)
+
+	return c.Redirect(http.StatusFound, uiCallbackPath+"#"+url.Values{
+		"token":      {token},
+		"expires_at": {strconv.FormatInt(session.ExpiresAt.Unix(), 10)},
+		"redirect":   {login.Redirect},
+	}.Encode())
+}
+
+// Logout handles POST /auth/logout
+// It ends the caller's session. Users' API tokens are left alone; they are
+// revoked by deleting the user.
+func (h *HTTPHandler) Logout(c echo.Context) error {
+	token, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+	if ok && user.IsSessionToken(token) {
+		if err := h.userStore.EndSession(c.Request().Context(), token); err != nil {
+			return err
+		}
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// failLogin sends the user back to the UI with the reason the sign-in failed.
+func (h *HTTPHandler) failLogin(c echo.Context, reason string) error {
+	return c.Redirect(http.StatusFound, uiCallbackPath+"#"+url.Values{"error": {reason}}.Encode())
+}
+
+func (h *HTTPHandler) cookie(c echo.Context, value string, expires time.Time) *http.Cookie {
+	return &http.Cookie{
+		Name:     loginCookie,
+		Value:    value,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   c.Scheme() == "https",
+		// Lax lets the cookie come back on the provider's top-level redirect.
+		SameSite: http.SameSiteLaxMode,
+	}
+}
@@ -0,0 +1,115 @@
+package authapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joshjon/kit/log"
+	"github.com/joshjon/kit/server"
+	"github.com/joshjon/kit/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/authapi"
+	"github.com/vervesh/verve/internal/keyprovider"
+	"github.com/vervesh/verve/internal/oidc"
+	"github.com/vervesh/verve/internal/setting"
+	"github.com/vervesh/verve/internal/sqlite"
+	"github.com/vervesh/verve/internal/user"
+)
+
+type fixture struct {
+	Server      *server.Server
+	OIDCService *oidc.Service
+	UserStore   *user.Store
+	t           *testing.T
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	db := sqlite.NewTestDB(t)
+	settingService := setting.NewService(sqlite.NewSettingRepository(db))
+	envelope, err := keyprovider.New(keyprovider.Config{EncryptionKey: []byte("0123456789abcdef0123456789abcdef")})
+	require.NoError(t, err)
+	oidcService := oidc.NewService(settingService, envelope)
+	userStore := user.NewStore(sqlite.NewUserRepository(db))
+
+	srv, err := server.NewServer(testutil.GetFreePort(t))
+	require.NoError(t, err)
+	srv.Register("/api/v1", authapi.NewHTTPHandler(oidcService, userStore, time.Hour, log.NewLogger(log.WithNop())))
+
+	go srv.Start()
+	err = srv.WaitHealthy(10, 100*time.Millisecond)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { srv.Stop(context.Background()) })
+
+	return &fixture{
+		Server:      srv,
+		OIDCService: oidcService,
+		UserStore:   userStore,
+		t:           t,
+	}
+}
+
+// configureProvider points single sign-on at a provider that only serves
+// its discovery document; the sign-in itself is covered by the oidc tests.
+func (f *fixture) configureProvider() *httptest.Server {
+	f.t.Helper()
+	var idp *httptest.Server
+	idp = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.URL,
+			"authorization_endpoint": idp.URL + "/authorize",
+			"token_endpoint":         idp.URL + "/token",
+			"jwks_uri":               idp.URL + "/keys",
+		})
+	}))
+	f.t.Cleanup(idp.Close)
+
+	err := f.OIDCService.SaveConfig(context.Background(), oidc.Config{
+		IssuerURL:   idp.URL,
+		ClientID:    "verve",
+		RedirectURL: f.Server.Address() + "/api/v1/auth/oidc/callback",
+		DefaultRole: user.RoleViewer,
+	})
+	require.NoError(f.t, err)
+	return idp
+}
+
+// --- URL helpers ---
+
+func (f *fixture) oidcURL() string {
+	return fmt.Sprintf("%s/api/v1/auth/oidc", f.Server.Address())
+}
+
+func (f *fixture) logoutURL() string {
+	return fmt.Sprintf("%s/api/v1/auth/logout", f.Server.Address())
+}
+
+// --- HTTP helpers ---
+
+// noRedirectClient returns redirects instead of following them.
+var noRedirectClient = &http.Client{
+	Timeout: 10 * time.Second,
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+func get(t *testing.T, url string, cookies ...*http.Cookie) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	httpRes, err := noRedirectClient.Do(req)
+	require.NoError(t, err)
+	return httpRes
+}
@@ -0,0 +1,94 @@
+package authapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshjon/kit/server"
+	"github.com/joshjon/kit/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/authapi"
+	"github.com/vervesh/verve/internal/user"
+)
+
+func TestGetOIDCStatus(t *testing.T) {
+	f := newFixture(t)
+
+	res := testutil.Get[server.Response[authapi.OIDCStatusResponse]](t, f.oidcURL())
+	assert.False(t, res.Data.Enabled)
+
+	f.configureProvider()
+
+	res = testutil.Get[server.Response[authapi.OIDCStatusResponse]](t, f.oidcURL())
+	assert.True(t, res.Data.Enabled)
+}
+
+func TestLogin_NotConfigured(t *testing.T) {
+	f := newFixture(t)
+
+	httpRes := get(t, f.oidcURL()+"/login")
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusNotFound, httpRes.StatusCode)
+}
+
+func TestLogin_RedirectsToProvider(t *testing.T) {
+	f := newFixture(t)
+	idp := f.configureProvider()
+
+	httpRes := get(t, f.oidcURL()+"/login?redirect=/tasks")
+	defer httpRes.Body.Close()
+	require.Equal(t, http.StatusFound, httpRes.StatusCode)
+
+	location, err := url.Parse(httpRes.Header.Get("Location"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(location.String(), idp.URL+"/authorize?"))
+	assert.Equal(t, "verve", location.Query().Get("client_id"))
+	assert.NotEmpty(t, location.Query().Get("state"))
+
+	cookies := httpRes.Cookies()
+	require.Len(t, cookies, 1)
+	assert.True(t, cookies[0].HttpOnly)
+
+	// A callback whose state doesn't match the login is refused.
+	httpRes = get(t, f.oidcURL()+"/callback?code=abc&state=forged", cookies[0])
+	defer httpRes.Body.Close()
+	require.Equal(t, http.StatusFound, httpRes.StatusCode)
+	assert.True(t, strings.HasPrefix(httpRes.Header.Get("Location"), "/auth/callback#error="))
+}
+
+func TestCallback_NoLogin(t *testing.T) {
+	f := newFixture(t)
+	f.configureProvider()
+
+	httpRes := get(t, f.oidcURL()+"/callback?code=abc&state=xyz")
+	defer httpRes.Body.Close()
+	require.Equal(t, http.StatusFound, httpRes.StatusCode)
+	assert.True(t, strings.HasPrefix(httpRes.Header.Get("Location"), "/auth/callback#error="))
+}
+
+func TestLogout_EndsSession(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	_, token, err := f.UserStore.CreateSession(ctx, "u-123", "Ada", user.RoleViewer, time.Hour)
+	require.NoError(t, err)
+
+	_, err = f.UserStore.Authenticate(ctx, token)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, f.logoutURL(), nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	httpRes, err := testutil.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusNoContent, httpRes.StatusCode)
+
+	_, err = f.UserStore.Authenticate(ctx, token)
+	assert.Error(t, err)
+}
@@ -0,0 +1,6 @@
+package authapi
+
+// OIDCStatusResponse reports whether single sign-on is available.
+type OIDCStatusResponse struct {
+	Enabled bool `json:"enabled"`
+}
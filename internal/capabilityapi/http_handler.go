@@ -0,0 +1,29 @@
+package capabilityapi
+
+import (
+	"net/http"
+
+	"github.com/joshjon/kit/server"
+	"github.com/labstack/echo/v4"
+)
+
+// HTTPHandler handles capability discovery HTTP requests.
+type HTTPHandler struct {
+	caps Capabilities
+}
+
+// NewHTTPHandler creates a new HTTPHandler reporting caps. Capabilities are
+// fixed by the server's configuration, so they are built once at startup.
+func NewHTTPHandler(caps Capabilities) *HTTPHandler {
+	return &HTTPHandler{caps: caps}
+}
+
+// Register adds the endpoints to the provided Echo router group.
+func (h *HTTPHandler) Register(g *echo.Group) {
+	g.GET("/capabilities", h.GetCapabilities)
+}
+
+// GetCapabilities handles GET /capabilities
+func (h *HTTPHandler) GetCapabilities(c echo.Context) error {
+	return server.SetResponse(c, http.StatusOK, h.caps)
+}
@@ -0,0 +1,44 @@
+package capabilityapi_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/joshjon/kit/server"
+	"github.com/joshjon/kit/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/capabilityapi"
+)
+
+type fixture struct {
+	Server *server.Server
+	t      *testing.T
+}
+
+func newFixture(t *testing.T, caps capabilityapi.Capabilities) *fixture {
+	t.Helper()
+
+	handler := capabilityapi.NewHTTPHandler(caps)
+
+	srv, err := server.NewServer(testutil.GetFreePort(t))
+	require.NoError(t, err)
+	srv.Register("/api/v1", handler)
+
+	go srv.Start()
+	err = srv.WaitHealthy(10, 100*time.Millisecond)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { srv.Stop(context.Background()) })
+
+	return &fixture{
+		Server: srv,
+		t:      t,
+	}
+}
+
+func (f *fixture) capabilitiesURL() string {
+	return fmt.Sprintf("%s/api/v1/capabilities", f.Server.Address())
+}
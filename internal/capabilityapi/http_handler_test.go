@@ -0,0 +1,31 @@
+package capabilityapi_test
+
+import (
+	"testing"
+
+	"github.com/joshjon/kit/server"
+	"github.com/joshjon/kit/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vervesh/verve/internal/capabilityapi"
+)
+
+func TestGetCapabilities(t *testing.T) {
+	caps := capabilityapi.Capabilities{
+		Version: "1.2.3",
+		Features: capabilityapi.Features{
+			Admin:          true,
+			ProvenanceScan: true,
+		},
+		Limits: capabilityapi.Limits{
+			MaxLabels:             20,
+			TaskTimeoutSeconds:    300,
+			TrashRetentionSeconds: 604800,
+			ArchiveAfterSeconds:   2592000,
+		},
+	}
+	f := newFixture(t, caps)
+
+	res := testutil.Get[server.Response[capabilityapi.Capabilities]](t, f.capabilitiesURL())
+	assert.Equal(t, caps, res.Data)
+}
@@ -0,0 +1,30 @@
+package capabilityapi
+
+// Capabilities describes what this server deployment supports so clients,
+// such as the bundled UI, can decide what to render.
+type Capabilities struct {
+	Version  string   `json:"version"`
+	Features Features `json:"features"`
+	Limits   Limits   `json:"limits"`
+}
+
+// Features reports which optional features are enabled.
+type Features struct {
+	Admin                bool `json:"admin"`                   // Admin endpoints are enabled (an admin token is configured)
+	Auth                 bool `json:"auth"`                    // API requests need a user token whose role allows them
+	MCP                  bool `json:"mcp"`                     // The MCP server endpoints are enabled (an MCP token is configured)
+	GitHubTokenStorage   bool `json:"github_token_storage"`    // GitHub tokens can be saved (an encryption key is configured)
+	ProvenanceScan       bool `json:"provenance_scan"`         // Agent PRs are scanned for license headers and verbatim blocks
+	SelfReview           bool `json:"self_review"`             // Agent PRs get a self-review report
+	CostAnomalyDetection bool `json:"cost_anomaly_detection"`  // Runaway task cost and spend spikes raise alerts
+	CostAnomalyAutoPause bool `json:"cost_anomaly_auto_pause"` // Dispatching pauses when a cost anomaly is detected
+}
+
+// Limits reports limits clients should enforce or display.
+type Limits struct {
+	MaxLabels             int   `json:"max_labels"`              // Most required labels a task or repo may have
+	TaskTimeoutSeconds    int64 `json:"task_timeout_seconds"`    // Time without a heartbeat before a running task is stale
+	LogRetentionSeconds   int64 `json:"log_retention_seconds"`   // How long task logs are kept (0 = forever)
+	TrashRetentionSeconds int64 `json:"trash_retention_seconds"` // How long deleted tasks can be restored from the trash (0 = deleted right away)
+	ArchiveAfterSeconds   int64 `json:"archive_after_seconds"`   // How long tasks stay merged or closed before they are archived (0 = never)
+}
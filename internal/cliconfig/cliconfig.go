@@ -0,0 +1,313 @@
+// Package cliconfig loads command-line flag values from a YAML config file.
+//
+// A config file maps flag names to values:
+//
+//	port: 8080
+//	cors-origins: [https://verve.example.com, https://admin.example.com]
+//	claude-models:
+//	  haiku: Haiku
+//	  sonnet: Sonnet
+//	agent-model-limits:
+//	  opus: [2, 400000]
+//	  sonnet: 8
+//
+// Lists are joined with commas, and maps become comma-separated KEY:VALUE
+// entries with list values joined by colons, matching the format of the
+// flags' environment variables. Flags set on the command line or through
+// their environment variable take precedence over the file.
+package cliconfig
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Sources of a flag's effective value, as reported by Print.
+const (
+	SourceDefault = "default"
+	SourceFlag    = "flag"
+	SourceEnv     = "env"
+	SourceFile    = "file"
+)
+
+// File is a parsed config file.
+type File struct {
+	path     string
+	settings []setting
+	applied  map[string]bool
+}
+
+type setting struct {
+	name  string
+	value string
+	line  int
+}
+
+// Load reads and parses the config file at path, checking every setting
+// names one of known.
+func Load(path string, known []cli.Flag) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+	return Parse(path, data, known)
+}
+
+// Parse parses config file data, checking every setting names one of known.
+// path is only used in errors.
+func Parse(path string, data []byte, known []cli.Flag) (*File, error) {
+	f := &File{path: path, applied: make(map[string]bool)}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return f, nil // Empty file
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, f.errorf(root, "want a map of flag names to values")
+	}
+
+	seen := make(map[string]int)
+	var errs []error
+	for i := 0; i < len(root.Content); i += 2 {
+		key, val := root.Content[i], root.Content[i+1]
+		name := key.Value
+		if line, ok := seen[name]; ok {
+			errs = append(errs, f.errorf(key, "%q is already set on line %d", name, line))
+			continue
+		}
+		seen[name] = key.Line
+		if err := checkKnown(name, known); err != nil {
+			errs = append(errs, f.errorf(key, "%w", err))
+			continue
+		}
+		value, err := flagValue(val)
+		if err != nil {
+			errs = append(errs, f.errorf(val, "%s: %w", name, err))
+			continue
+		}
+		f.settings = append(f.settings, setting{name: name, value: value, line: key.Line})
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return f, nil
+}
+
+// Path returns the path the file was loaded from.
+func (f *File) Path() string {
+	return f.path
+}
+
+// Apply sets the flags of c's command named in the file, unless they were
+// set on the command line or through their environment variable. Settings
+// for flags the command doesn't have, such as worker settings when running
+// only the API server, are skipped.
+func (f *File) Apply(c *cli.Context) error {
+	var errs []error
+	for _, s := range f.settings {
+		if findFlag(c.Command.Flags, s.name) == nil || c.IsSet(s.name) {
+			continue
+		}
+		if err := c.Set(s.name, s.value); err != nil {
+			errs = append(errs, fmt.Errorf("%s:%d: %s: invalid value %q", f.path, s.line, s.name, s.value))
+			continue
+		}
+		f.applied[s.name] = true
+	}
+	return errors.Join(errs...)
+}
+
+func (f *File) errorf(n *yaml.Node, format string, args ...any) error {
+	return fmt.Errorf("%s:%d: %w", f.path, n.Line, fmt.Errorf(format, args...))
+}
+
+// Print writes the effective value of each flag of c's command, except skip,
+// as YAML commented with where it came from. The values of flags holding
+// secrets are redacted. f is the config file that was applied, or nil. A
+// flag given both on the command line and through its environment variable
+// is reported as coming from the variable.
+func Print(w io.Writer, c *cli.Context, f *File, skip ...string) error {
+	for _, fl := range c.Command.Flags {
+		name := fl.Names()[0]
+		if slices.Contains(skip, name) || name == "help" {
+			continue
+		}
+		value := formatValue(c, fl, name)
+		if value != "" && secret(name) {
+			value = "[REDACTED]"
+		}
+		node := &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+		if value == "" {
+			node.Style = yaml.DoubleQuotedStyle // Rather than null
+		}
+		b, err := yaml.Marshal(node)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s # %s\n", name, strings.TrimSuffix(string(b), "\n"), source(c, fl, f)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// source returns where fl's value came from.
+func source(c *cli.Context, fl cli.Flag, f *File) string {
+	name := fl.Names()[0]
+	switch {
+	case f != nil && f.applied[name]:
+		return SourceFile + " " + f.path
+	case fl.IsSet():
+		// Flags only record being set through an environment variable, so
+		// look up which one.
+		if ev, ok := fl.(interface{ GetEnvVars() []string }); ok {
+			for _, env := range ev.GetEnvVars() {
+				if _, set := os.LookupEnv(env); set {
+					return SourceEnv + " " + env
+				}
+			}
+		}
+		return SourceEnv
+	case c.IsSet(name):
+		return SourceFlag
+	default:
+		return SourceDefault
+	}
+}
+
+func formatValue(c *cli.Context, fl cli.Flag, name string) string {
+	if _, ok := fl.(*cli.TimestampFlag); ok {
+		if t := c.Timestamp(name); t != nil {
+			return t.Format(time.RFC3339)
+		}
+		return ""
+	}
+	return fmt.Sprint(c.Value(name))
+}
+
+// secret reports whether the flag holds a credential, judging by its name.
+func secret(name string) bool {
+	for _, suffix := range []string{"-key", "-keys", "-token", "-dsn"} {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// flagValue converts a YAML value to the string a flag is set to.
+func flagValue(n *yaml.Node) (string, error) {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		if n.Tag == "!!null" {
+			return "", errors.New("missing value")
+		}
+		return n.Value, nil
+	case yaml.SequenceNode:
+		return joinScalars(n.Content, ",")
+	case yaml.MappingNode:
+		entries := make([]string, 0, len(n.Content)/2)
+		for i := 0; i < len(n.Content); i += 2 {
+			key, val := n.Content[i], n.Content[i+1]
+			var v string
+			var err error
+			switch val.Kind {
+			case yaml.ScalarNode:
+				v, err = flagValue(val)
+			case yaml.SequenceNode:
+				v, err = joinScalars(val.Content, ":")
+			default:
+				err = errors.New("map values must be a value or a list of values")
+			}
+			if err != nil {
+				return "", fmt.Errorf("%s: %w", key.Value, err)
+			}
+			entries = append(entries, key.Value+":"+v)
+		}
+		return strings.Join(entries, ","), nil
+	default:
+		return "", errors.New("unsupported value")
+	}
+}
+
+func joinScalars(nodes []*yaml.Node, sep string) (string, error) {
+	values := make([]string, len(nodes))
+	for i, n := range nodes {
+		if n.Kind != yaml.ScalarNode || n.Tag == "!!null" {
+			return "", errors.New("list items must be plain values")
+		}
+		values[i] = n.Value
+	}
+	return strings.Join(values, sep), nil
+}
+
+// checkKnown returns an error suggesting the flag meant when name is not one
+// of known.
+func checkKnown(name string, known []cli.Flag) error {
+	if findFlag(known, name) != nil {
+		return nil
+	}
+	for _, fl := range known {
+		if ev, ok := fl.(interface{ GetEnvVars() []string }); ok && slices.Contains(ev.GetEnvVars(), name) {
+			return fmt.Errorf("unknown setting %q; settings are named after flags, use %q", name, fl.Names()[0])
+		}
+	}
+	if suggestion := closest(name, known); suggestion != "" {
+		return fmt.Errorf("unknown setting %q, did you mean %q?", name, suggestion)
+	}
+	return fmt.Errorf("unknown setting %q", name)
+}
+
+func findFlag(flags []cli.Flag, name string) cli.Flag {
+	for _, fl := range flags {
+		if slices.Contains(fl.Names(), name) {
+			return fl
+		}
+	}
+	return nil
+}
+
+// closest returns the name of the flag in known closest to name, or "" when
+// none is close enough to be a likely typo.
+func closest(name string, known []cli.Flag) string {
+	best, bestDist := "", max(2, len(name)/3)+1
+	for _, fl := range known {
+		candidate := fl.Names()[0]
+		if d := levenshtein(name, candidate); d < bestDist {
+			best, bestDist = candidate, d
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,196 @@
+package cliconfig_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+
+	"github.com/vervesh/verve/internal/cliconfig"
+)
+
+func testFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.IntFlag{Name: "port", EnvVars: []string{"TEST_PORT"}, Value: 7400},
+		&cli.StringFlag{Name: "cors-origins", EnvVars: []string{"TEST_CORS_ORIGINS"}},
+		&cli.StringFlag{Name: "claude-models", EnvVars: []string{"TEST_CLAUDE_MODELS"}},
+		&cli.StringFlag{Name: "agent-model-limits", EnvVars: []string{"TEST_AGENT_MODEL_LIMITS"}},
+		&cli.DurationFlag{Name: "task-timeout", EnvVars: []string{"TEST_TASK_TIMEOUT"}, Value: time.Hour},
+		&cli.StringFlag{Name: "encryption-key", EnvVars: []string{"TEST_ENCRYPTION_KEY"}},
+	}
+}
+
+// run parses args against testFlags, applies the config file and calls fn.
+func run(t *testing.T, config string, args []string, fn func(c *cli.Context, f *cliconfig.File)) {
+	t.Helper()
+	flags := testFlags()
+	f, err := cliconfig.Parse("verve.yaml", []byte(config), flags)
+	require.NoError(t, err)
+
+	app := &cli.App{
+		Name:  "verve",
+		Flags: flags,
+		Action: func(c *cli.Context) error {
+			if err := f.Apply(c); err != nil {
+				return err
+			}
+			fn(c, f)
+			return nil
+		},
+	}
+	require.NoError(t, app.Run(append([]string{"verve"}, args...)))
+}
+
+func TestParse_Values(t *testing.T) {
+	config := `
+port: 8080
+cors-origins: [https://a.example.com, https://b.example.com]
+claude-models:
+  haiku: Haiku
+  sonnet: Sonnet
+agent-model-limits:
+  opus: [2, 400000]
+  sonnet: 8
+task-timeout: 30m
+`
+	run(t, config, nil, func(c *cli.Context, _ *cliconfig.File) {
+		assert.Equal(t, 8080, c.Int("port"))
+		assert.Equal(t, "https://a.example.com,https://b.example.com", c.String("cors-origins"))
+		assert.Equal(t, "haiku:Haiku,sonnet:Sonnet", c.String("claude-models"))
+		assert.Equal(t, "opus:2:400000,sonnet:8", c.String("agent-model-limits"))
+		assert.Equal(t, 30*time.Minute, c.Duration("task-timeout"))
+	})
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   []string
+	}{
+		{
+			name:   "not a map",
+			config: "- port",
+			want:   []string{"verve.yaml:1: want a map of flag names to values"},
+		},
+		{
+			name:   "invalid yaml",
+			config: "port: [8080",
+			want:   []string{"verve.yaml: yaml:"},
+		},
+		{
+			name:   "duplicate",
+			config: "port: 8080\nport: 9090",
+			want:   []string{`verve.yaml:2: "port" is already set on line 1`},
+		},
+		{
+			name:   "env var name",
+			config: "TEST_PORT: 8080",
+			want:   []string{`verve.yaml:1: unknown setting "TEST_PORT"; settings are named after flags, use "port"`},
+		},
+		{
+			name:   "typo",
+			config: "prot: 8080",
+			want:   []string{`verve.yaml:1: unknown setting "prot", did you mean "port"?`},
+		},
+		{
+			name:   "unknown",
+			config: "listen-address: localhost",
+			want:   []string{`verve.yaml:1: unknown setting "listen-address"`},
+		},
+		{
+			name:   "missing value",
+			config: "port:",
+			want:   []string{"verve.yaml:1: port: missing value"},
+		},
+		{
+			name:   "nested list",
+			config: "cors-origins: [[a]]",
+			want:   []string{"verve.yaml:1: cors-origins: list items must be plain values"},
+		},
+		{
+			name:   "every error",
+			config: "prot: 8080\nport:",
+			want: []string{
+				`verve.yaml:1: unknown setting "prot", did you mean "port"?`,
+				"verve.yaml:2: port: missing value",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := cliconfig.Parse("verve.yaml", []byte(tt.config), testFlags())
+			require.Error(t, err)
+			for _, want := range tt.want {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestParse_Empty(t *testing.T) {
+	run(t, "", nil, func(c *cli.Context, _ *cliconfig.File) {
+		assert.Equal(t, 7400, c.Int("port"))
+	})
+}
+
+func TestApply_Precedence(t *testing.T) {
+	t.Setenv("TEST_TASK_TIMEOUT", "2h")
+	config := "port: 8080\ntask-timeout: 30m\ncors-origins: https://a.example.com"
+
+	run(t, config, []string{"--cors-origins", "https://b.example.com"}, func(c *cli.Context, _ *cliconfig.File) {
+		assert.Equal(t, 8080, c.Int("port"), "file over default")
+		assert.Equal(t, 2*time.Hour, c.Duration("task-timeout"), "env over file")
+		assert.Equal(t, "https://b.example.com", c.String("cors-origins"), "flag over file")
+	})
+}
+
+func TestApply_InvalidValue(t *testing.T) {
+	flags := testFlags()
+	f, err := cliconfig.Parse("verve.yaml", []byte("\nport: eighty"), flags)
+	require.NoError(t, err)
+
+	app := &cli.App{
+		Name:   "verve",
+		Flags:  flags,
+		Action: f.Apply,
+	}
+	err = app.Run([]string{"verve"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `verve.yaml:2: port: invalid value "eighty"`)
+}
+
+func TestPrint(t *testing.T) {
+	t.Setenv("TEST_TASK_TIMEOUT", "2h")
+	config := "port: 8080\nencryption-key: hunter2\nclaude-models: {haiku: Haiku}"
+
+	run(t, config, []string{"--cors-origins", "https://a.example.com"}, func(c *cli.Context, f *cliconfig.File) {
+		var buf bytes.Buffer
+		require.NoError(t, cliconfig.Print(&buf, c, f, "agent-model-limits"))
+		assert.Equal(t, `port: 8080 # file verve.yaml
+cors-origins: https://a.example.com # flag
+claude-models: haiku:Haiku # file verve.yaml
+task-timeout: 2h0m0s # env TEST_TASK_TIMEOUT
+encryption-key: '[REDACTED]' # file verve.yaml
+`, buf.String())
+	})
+}
+
+func TestPrint_Defaults(t *testing.T) {
+	flags := testFlags()
+	app := &cli.App{
+		Name:  "verve",
+		Flags: flags,
+		Action: func(c *cli.Context) error {
+			var buf bytes.Buffer
+			require.NoError(t, cliconfig.Print(&buf, c, nil))
+			assert.Contains(t, buf.String(), "port: 7400 # default\n")
+			assert.Contains(t, buf.String(), `encryption-key: "" # default`+"\n")
+			return nil
+		},
+	}
+	require.NoError(t, app.Run([]string{"verve"}))
+}
@@ -4,6 +4,8 @@ import (
 	"github.com/cohesivestack/valgo"
 	"github.com/joshjon/kit/id"
 	"go.jetify.com/typeid"
+
+	"github.com/vervesh/verve/internal/msgcat"
 )
 
 type conversationPrefix struct{}
@@ -38,5 +40,5 @@ func ConversationIDValidator(identifier string, nameAndTitle ...string) *valgo.V
 		Passing(func(_ string) bool {
 			_, err := ParseConversationID(identifier)
 			return err == nil
-		}, "Must be a valid conversation ID")
+		}, msgcat.Text(msgcat.ErrInvalidID, "conversation"))
 }
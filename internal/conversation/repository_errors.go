@@ -1,11 +1,15 @@
 package conversation
 
-import "github.com/joshjon/kit/errtag"
+import (
+	"github.com/joshjon/kit/errtag"
+
+	"github.com/vervesh/verve/internal/msgcat"
+)
 
 // ErrTagConversationNotFound indicates a conversation was not found.
 type ErrTagConversationNotFound struct{ errtag.NotFound }
 
-func (ErrTagConversationNotFound) Msg() string { return "Conversation not found" }
+func (ErrTagConversationNotFound) Msg() string { return msgcat.Text(msgcat.ErrConversationNotFound) }
 
 func (e ErrTagConversationNotFound) Unwrap() error {
 	return errtag.Tag[errtag.NotFound](e.Cause())
@@ -14,7 +18,7 @@ func (e ErrTagConversationNotFound) Unwrap() error {
 // ErrTagConversationConflict indicates a conversation conflict (e.g. duplicate ID).
 type ErrTagConversationConflict struct{ errtag.Conflict }
 
-func (ErrTagConversationConflict) Msg() string { return "Conversation conflict" }
+func (ErrTagConversationConflict) Msg() string { return msgcat.Text(msgcat.ErrConversationConflict) }
 
 func (e ErrTagConversationConflict) Unwrap() error {
 	return errtag.Tag[errtag.Conflict](e.Cause())
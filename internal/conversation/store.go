@@ -8,14 +8,22 @@ import (
 	"github.com/joshjon/kit/log"
 )
 
+// PendingNotifier tells the other server instances that conversations may
+// have become claimable, so their waiting polls look for them again. The
+// listen side calls Store.WakePending on every other instance.
+type PendingNotifier interface {
+	NotifyPending(ctx context.Context) error
+}
+
 // Store wraps a Repository and adds application-level concerns for conversations.
 type Store struct {
 	repo   Repository
 	logger log.Logger
 
 	// Pending conversation notification (same pattern as task.Store and epic.Store)
-	pendingMu sync.Mutex
-	pendingCh chan struct{}
+	pendingMu       sync.Mutex
+	pendingCh       chan struct{}
+	pendingNotifier PendingNotifier
 }
 
 // NewStore creates a new Store backed by the given Repository.
@@ -27,6 +35,12 @@ func NewStore(repo Repository, logger log.Logger) *Store {
 	}
 }
 
+// SetPendingNotifier sets the PendingNotifier that shares pending
+// conversation notifications with the other server instances.
+func (s *Store) SetPendingNotifier(notifier PendingNotifier) {
+	s.pendingNotifier = notifier
+}
+
 // WaitForPending returns a channel that signals when a pending conversation might be available.
 func (s *Store) WaitForPending() <-chan struct{} {
 	s.pendingMu.Lock()
@@ -35,6 +49,15 @@ func (s *Store) WaitForPending() <-chan struct{} {
 }
 
 func (s *Store) notifyPending() {
+	s.WakePending()
+	if s.pendingNotifier != nil {
+		_ = s.pendingNotifier.NotifyPending(context.Background())
+	}
+}
+
+// WakePending wakes this instance's waiting polls without notifying the
+// other instances. It is called by the listen side of a PendingNotifier.
+func (s *Store) WakePending() {
 	s.pendingMu.Lock()
 	defer s.pendingMu.Unlock()
 	select {
@@ -124,6 +147,16 @@ func (s *Store) FailResponse(ctx context.Context, id ConversationID) error {
 	return s.repo.ReleaseConversationClaim(ctx, id)
 }
 
+// ReleaseConversationClaim releases a worker's claim on a conversation,
+// leaving its pending message to be claimed again.
+func (s *Store) ReleaseConversationClaim(ctx context.Context, id ConversationID) error {
+	if err := s.repo.ReleaseConversationClaim(ctx, id); err != nil {
+		return err
+	}
+	s.notifyPending()
+	return nil
+}
+
 // ConversationHeartbeat updates the heartbeat timestamp for a claimed conversation.
 func (s *Store) ConversationHeartbeat(ctx context.Context, id ConversationID) error {
 	return s.repo.ConversationHeartbeat(ctx, id)
@@ -11,6 +11,7 @@ import (
 	"github.com/vervesh/verve/internal/conversation"
 	"github.com/vervesh/verve/internal/epic"
 	"github.com/vervesh/verve/internal/logkey"
+	"github.com/vervesh/verve/internal/msgcat"
 	"github.com/vervesh/verve/internal/repo"
 	"github.com/vervesh/verve/internal/setting"
 )
@@ -61,10 +62,18 @@ func (h *HTTPHandler) CreateConversation(c echo.Context) error {
 		return err
 	}
 	if r.SetupStatus != repo.SetupStatusReady {
-		return echo.NewHTTPError(http.StatusConflict, "repository setup is not complete — finish setup before starting conversations")
+		return echo.NewHTTPError(http.StatusConflict, msgcat.Text(msgcat.ErrRepoSetupIncompleteConvos))
 	}
 
 	model := req.Model
+	if model == "" {
+		// Inherit the model of the repo's team.
+		defaults, err := h.repoStore.TeamDefaults(c.Request().Context(), r)
+		if err != nil {
+			return err
+		}
+		model = defaults.Model
+	}
 	if model == "" && h.settingService != nil {
 		model = h.settingService.Get(setting.KeyDefaultModel)
 	}
@@ -223,12 +232,12 @@ func (h *HTTPHandler) GenerateTasks(c echo.Context) error {
 
 	// Must be active.
 	if conv.Status != conversation.StatusActive {
-		return echo.NewHTTPError(http.StatusConflict, "conversation must be active to generate tasks")
+		return echo.NewHTTPError(http.StatusConflict, msgcat.Text(msgcat.ErrConversationNotActive))
 	}
 
 	// Must not already have an epic linked.
 	if conv.EpicID != nil {
-		return echo.NewHTTPError(http.StatusConflict, "conversation already has a linked epic")
+		return echo.NewHTTPError(http.StatusConflict, msgcat.Text(msgcat.ErrConversationHasEpic))
 	}
 
 	// Build the planning prompt with conversation transcript.
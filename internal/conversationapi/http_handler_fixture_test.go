@@ -40,8 +40,8 @@ type stubTaskCreator struct {
 	taskStore *task.Store
 }
 
-func (s *stubTaskCreator) CreateTaskFromEpic(ctx context.Context, repoID, title, description string, dependsOn, acceptanceCriteria []string, epicID string, ready bool, model string) (string, error) {
-	tsk := task.NewTask(repoID, title, description, dependsOn, acceptanceCriteria, 0, false, false, model, ready)
+func (s *stubTaskCreator) CreateTaskFromEpic(ctx context.Context, repoID, title, description string, dependsOn, acceptanceCriteria []string, epicID string, ready bool, model string, maxCostUSD float64) (string, error) {
+	tsk := task.NewTask(repoID, title, description, dependsOn, acceptanceCriteria, maxCostUSD, false, false, model, ready)
 	tsk.EpicID = epicID
 	if err := s.taskStore.CreateTask(ctx, tsk); err != nil {
 		return "", err
@@ -0,0 +1,326 @@
+// Package costguard watches agent spend for runaway patterns: a task whose
+// cost has grown far beyond its repo's historical p95, or an instance-wide
+// hourly spend spike. Each anomaly is alerted once and can trip the dispatch
+// kill switch, which stops new work from being handed to agents until an
+// admin acknowledges the pause.
+package costguard
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/joshjon/kit/log"
+
+	"github.com/vervesh/verve/internal/logkey"
+)
+
+// Default anomaly thresholds.
+const (
+	DefaultTaskFactor        = 3.0
+	DefaultMinSamples        = 20
+	DefaultSpikeFactor       = 4.0
+	DefaultMinHourlySpendUSD = 5.0
+)
+
+const (
+	// historyLimit caps the finished tasks read to compute each repo's p95.
+	historyLimit = 5000
+	// repoHistorySize is the number of a repo's most recent finished tasks
+	// its p95 is computed over.
+	repoHistorySize = 200
+	// baselineWindow is the trailing window the average hourly spend is
+	// computed over.
+	baselineWindow = 7 * 24 * time.Hour
+)
+
+// Kind identifies the type of spend anomaly.
+type Kind string
+
+const (
+	KindTaskCost    Kind = "task_cost"    // Task cost far beyond its repo's p95
+	KindHourlySpend Kind = "hourly_spend" // Instance-wide spend spike in the last hour
+)
+
+// Config controls the analyzer's thresholds. Zero fields use the defaults.
+type Config struct {
+	// TaskFactor flags an unfinished task whose cost exceeds this multiple of
+	// its repo's p95 finished task cost.
+	TaskFactor float64
+	// MinSamples is the number of finished tasks with a cost a repo needs
+	// before its p95 is trusted.
+	MinSamples int
+	// SpikeFactor flags the last hour's spend when it exceeds this multiple
+	// of the trailing 7-day average hourly spend.
+	SpikeFactor float64
+	// MinHourlySpendUSD ignores hourly spikes below this spend.
+	MinHourlySpendUSD float64
+	// AutoPause trips the dispatch kill switch when an anomaly is detected.
+	AutoPause bool
+}
+
+func (c Config) withDefaults() Config {
+	if c.TaskFactor <= 0 {
+		c.TaskFactor = DefaultTaskFactor
+	}
+	if c.MinSamples <= 0 {
+		c.MinSamples = DefaultMinSamples
+	}
+	if c.SpikeFactor <= 0 {
+		c.SpikeFactor = DefaultSpikeFactor
+	}
+	if c.MinHourlySpendUSD <= 0 {
+		c.MinHourlySpendUSD = DefaultMinHourlySpendUSD
+	}
+	return c
+}
+
+// Anomaly is a detected runaway spend pattern.
+type Anomaly struct {
+	Kind Kind `json:"kind"`
+	// RepoID is the repo of the task for task cost anomalies.
+	RepoID string `json:"repo_id,omitempty"`
+	// RepoIDs are the repos that spent during the hour for hourly spend
+	// anomalies.
+	RepoIDs      []string  `json:"repo_ids,omitempty"`
+	TaskID       string    `json:"task_id,omitempty"`
+	TaskTitle    string    `json:"task_title,omitempty"`
+	CostUSD      float64   `json:"cost_usd"`
+	ThresholdUSD float64   `json:"threshold_usd"`
+	DetectedAt   time.Time `json:"detected_at"`
+}
+
+// String describes the anomaly for logs and pause reasons.
+func (a Anomaly) String() string {
+	if a.Kind == KindHourlySpend {
+		return fmt.Sprintf("$%.2f spent in the last hour (threshold $%.2f)", a.CostUSD, a.ThresholdUSD)
+	}
+	return fmt.Sprintf("task %s spent $%.2f (threshold $%.2f)", a.TaskID, a.CostUSD, a.ThresholdUSD)
+}
+
+// TaskCost is the accumulated cost of a single task.
+type TaskCost struct {
+	TaskID  string
+	RepoID  string
+	Title   string
+	CostUSD float64
+}
+
+// RepoSpend is a repo's spend over a time window.
+type RepoSpend struct {
+	RepoID  string
+	CostUSD float64
+}
+
+// Repository reads the spend history the analyzer works from.
+type Repository interface {
+	// ListFinishedTaskCosts returns the costs of up to limit finished tasks
+	// with a cost, newest first.
+	ListFinishedTaskCosts(ctx context.Context, limit int) ([]TaskCost, error)
+	// ListActiveTaskCosts returns the costs of pending and running tasks
+	// that have spent anything.
+	ListActiveTaskCosts(ctx context.Context) ([]TaskCost, error)
+	// ListRepoSpendSince returns each repo's task attempt spend since the
+	// given time.
+	ListRepoSpendSince(ctx context.Context, since time.Time) ([]RepoSpend, error)
+}
+
+// Alerter is told about each new anomaly. paused reports whether the
+// anomaly tripped the dispatch kill switch.
+type Alerter interface {
+	CostAnomaly(ctx context.Context, a Anomaly, paused bool)
+}
+
+// Analyzer detects spend anomalies. Each anomaly is reported once: a task is
+// not reported again while it stays active, and an hourly spike is not
+// reported again until spend falls back below the threshold.
+type Analyzer struct {
+	repo    Repository
+	kill    *Switch
+	alerter Alerter
+	cfg     Config
+	logger  log.Logger
+	now     func() time.Time
+
+	mu       sync.Mutex
+	reported map[string]bool // task IDs already reported
+	spiking  bool
+}
+
+// NewAnalyzer creates a new Analyzer. The alerter may be nil.
+func NewAnalyzer(repo Repository, kill *Switch, alerter Alerter, cfg Config, logger log.Logger) *Analyzer {
+	return &Analyzer{
+		repo:     repo,
+		kill:     kill,
+		alerter:  alerter,
+		cfg:      cfg.withDefaults(),
+		logger:   logger.With("component", "cost_guard"),
+		now:      time.Now,
+		reported: make(map[string]bool),
+	}
+}
+
+// Analyze runs one detection pass and returns the anomalies it found that
+// were not reported before. New anomalies are logged, alerted and, with
+// AutoPause, trip the dispatch kill switch.
+func (a *Analyzer) Analyze(ctx context.Context) ([]Anomaly, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	taskAnomalies, err := a.taskAnomalies(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	spike, err := a.hourlySpike(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	anomalies := taskAnomalies
+	if spike != nil {
+		anomalies = append(anomalies, *spike)
+	}
+	if len(anomalies) == 0 {
+		return nil, nil
+	}
+
+	paused := false
+	if a.cfg.AutoPause {
+		pause, err := a.kill.Trip(ctx, anomalies[0].String(), anomalies)
+		if err != nil {
+			return nil, fmt.Errorf("trip dispatch pause: %w", err)
+		}
+		paused = pause != nil
+	}
+
+	for _, an := range anomalies {
+		a.logger.Warn("cost anomaly detected",
+			"cost_anomaly.kind", an.Kind,
+			logkey.RepoID, an.RepoID,
+			logkey.TaskID, an.TaskID,
+			"cost_anomaly.cost_usd", an.CostUSD,
+			"cost_anomaly.threshold_usd", an.ThresholdUSD,
+			"cost_anomaly.dispatch_paused", paused,
+		)
+		if a.alerter != nil {
+			a.alerter.CostAnomaly(ctx, an, paused)
+		}
+	}
+	return anomalies, nil
+}
+
+func (a *Analyzer) taskAnomalies(ctx context.Context, now time.Time) ([]Anomaly, error) {
+	finished, err := a.repo.ListFinishedTaskCosts(ctx, historyLimit)
+	if err != nil {
+		return nil, fmt.Errorf("list finished task costs: %w", err)
+	}
+	history := make(map[string][]float64)
+	for _, tc := range finished {
+		if len(history[tc.RepoID]) < repoHistorySize {
+			history[tc.RepoID] = append(history[tc.RepoID], tc.CostUSD)
+		}
+	}
+
+	active, err := a.repo.ListActiveTaskCosts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list active task costs: %w", err)
+	}
+
+	var anomalies []Anomaly
+	stillActive := make(map[string]bool, len(active))
+	for _, tc := range active {
+		stillActive[tc.TaskID] = true
+		if a.reported[tc.TaskID] {
+			continue
+		}
+		costs := history[tc.RepoID]
+		if len(costs) < a.cfg.MinSamples {
+			continue
+		}
+		threshold := Percentile(costs, 95) * a.cfg.TaskFactor
+		if threshold <= 0 || tc.CostUSD <= threshold {
+			continue
+		}
+		a.reported[tc.TaskID] = true
+		anomalies = append(anomalies, Anomaly{
+			Kind:         KindTaskCost,
+			RepoID:       tc.RepoID,
+			TaskID:       tc.TaskID,
+			TaskTitle:    tc.Title,
+			CostUSD:      tc.CostUSD,
+			ThresholdUSD: threshold,
+			DetectedAt:   now,
+		})
+	}
+
+	// Forget finished tasks so the set doesn't grow forever.
+	for id := range a.reported {
+		if !stillActive[id] {
+			delete(a.reported, id)
+		}
+	}
+	return anomalies, nil
+}
+
+func (a *Analyzer) hourlySpike(ctx context.Context, now time.Time) (*Anomaly, error) {
+	lastHour, err := a.repo.ListRepoSpendSince(ctx, now.Add(-time.Hour))
+	if err != nil {
+		return nil, fmt.Errorf("list hourly spend: %w", err)
+	}
+	window, err := a.repo.ListRepoSpendSince(ctx, now.Add(-baselineWindow))
+	if err != nil {
+		return nil, fmt.Errorf("list baseline spend: %w", err)
+	}
+
+	var hourSpend, windowSpend float64
+	var repoIDs []string
+	for _, s := range lastHour {
+		hourSpend += s.CostUSD
+		repoIDs = append(repoIDs, s.RepoID)
+	}
+	for _, s := range window {
+		windowSpend += s.CostUSD
+	}
+
+	// The baseline excludes the hour being judged. Without any earlier spend
+	// there is nothing to compare against.
+	baseline := (windowSpend - hourSpend) / (baselineWindow.Hours() - 1)
+	if baseline <= 0 {
+		a.spiking = false
+		return nil, nil
+	}
+	threshold := max(baseline*a.cfg.SpikeFactor, a.cfg.MinHourlySpendUSD)
+	if hourSpend <= threshold {
+		a.spiking = false
+		return nil, nil
+	}
+	if a.spiking {
+		return nil, nil
+	}
+	a.spiking = true
+	sort.Strings(repoIDs)
+	return &Anomaly{
+		Kind:         KindHourlySpend,
+		RepoIDs:      repoIDs,
+		CostUSD:      hourSpend,
+		ThresholdUSD: threshold,
+		DetectedAt:   now,
+	}, nil
+}
+
+// Percentile returns the pth percentile of values using the nearest-rank
+// method, or zero when values is empty. values is not modified.
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	rank = min(max(rank, 0), len(sorted)-1)
+	return sorted[rank]
+}
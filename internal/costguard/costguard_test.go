@@ -0,0 +1,210 @@
+package costguard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/joshjon/kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	finished []TaskCost
+	active   []TaskCost
+	// spend maps a window length to the repo spend returned for it.
+	spend map[time.Duration][]RepoSpend
+	now   time.Time
+}
+
+func (f *fakeRepo) ListFinishedTaskCosts(_ context.Context, _ int) ([]TaskCost, error) {
+	return f.finished, nil
+}
+
+func (f *fakeRepo) ListActiveTaskCosts(_ context.Context) ([]TaskCost, error) {
+	return f.active, nil
+}
+
+func (f *fakeRepo) ListRepoSpendSince(_ context.Context, since time.Time) ([]RepoSpend, error) {
+	return f.spend[f.now.Sub(since)], nil
+}
+
+type memPauseStore struct {
+	pause *Pause
+}
+
+func (m *memPauseStore) ReadDispatchPause(_ context.Context) (*Pause, error) { return m.pause, nil }
+
+func (m *memPauseStore) SetDispatchPause(_ context.Context, p *Pause) error {
+	m.pause = p
+	return nil
+}
+
+func (m *memPauseStore) ClearDispatchPause(_ context.Context) error {
+	m.pause = nil
+	return nil
+}
+
+type recordingAlerter struct {
+	anomalies []Anomaly
+	paused    []bool
+}
+
+func (r *recordingAlerter) CostAnomaly(_ context.Context, a Anomaly, paused bool) {
+	r.anomalies = append(r.anomalies, a)
+	r.paused = append(r.paused, paused)
+}
+
+func newTestAnalyzer(repo *fakeRepo, cfg Config) (*Analyzer, *memPauseStore, *recordingAlerter) {
+	store := &memPauseStore{}
+	alerter := &recordingAlerter{}
+	a := NewAnalyzer(repo, NewSwitch(store), alerter, cfg, log.NewLogger(log.WithNop()))
+	a.now = func() time.Time { return repo.now }
+	return a, store, alerter
+}
+
+func finishedCosts(repoID string, costs ...float64) []TaskCost {
+	out := make([]TaskCost, len(costs))
+	for i, c := range costs {
+		out[i] = TaskCost{TaskID: "done", RepoID: repoID, CostUSD: c}
+	}
+	return out
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{5, 1, 4, 2, 3, 10, 6, 7, 9, 8}
+	assert.Equal(t, 10.0, Percentile(values, 95))
+	assert.Equal(t, 5.0, Percentile(values, 50))
+	assert.Equal(t, 1.0, Percentile(values, 0))
+	assert.Zero(t, Percentile(nil, 95))
+	assert.Equal(t, 5.0, values[0], "input must not be sorted in place")
+}
+
+func TestAnalyzer_TaskCost(t *testing.T) {
+	repo := &fakeRepo{
+		now:      time.Now(),
+		finished: finishedCosts("repo_a", 1, 1, 1, 1, 2),
+		active: []TaskCost{
+			{TaskID: "tsk_runaway", RepoID: "repo_a", Title: "Runaway", CostUSD: 7},
+			{TaskID: "tsk_normal", RepoID: "repo_a", CostUSD: 5},
+			{TaskID: "tsk_new_repo", RepoID: "repo_b", CostUSD: 100},
+		},
+	}
+	a, store, alerter := newTestAnalyzer(repo, Config{MinSamples: 5})
+
+	anomalies, err := a.Analyze(context.Background())
+	require.NoError(t, err)
+	require.Len(t, anomalies, 1, "only the task beyond 3x its repo's p95 is flagged; repo_b has no history")
+	assert.Equal(t, KindTaskCost, anomalies[0].Kind)
+	assert.Equal(t, "tsk_runaway", anomalies[0].TaskID)
+	assert.Equal(t, "repo_a", anomalies[0].RepoID)
+	assert.Equal(t, 6.0, anomalies[0].ThresholdUSD)
+	assert.Equal(t, anomalies, alerter.anomalies)
+	assert.Equal(t, []bool{false}, alerter.paused)
+	assert.Nil(t, store.pause, "dispatch is only paused with AutoPause")
+
+	anomalies, err = a.Analyze(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, anomalies, "a task is reported once while it stays active")
+	assert.Len(t, alerter.anomalies, 1)
+}
+
+func TestAnalyzer_HourlySpike(t *testing.T) {
+	repo := &fakeRepo{
+		now: time.Now(),
+		spend: map[time.Duration][]RepoSpend{
+			time.Hour:      {{RepoID: "repo_a", CostUSD: 20}, {RepoID: "repo_b", CostUSD: 10}},
+			baselineWindow: {{RepoID: "repo_a", CostUSD: 197}},
+		},
+	}
+	a, _, alerter := newTestAnalyzer(repo, Config{})
+
+	// Baseline: (197 - 30) / 167 = $1/hour, so the threshold is max(4, 5) = $5.
+	anomalies, err := a.Analyze(context.Background())
+	require.NoError(t, err)
+	require.Len(t, anomalies, 1)
+	assert.Equal(t, KindHourlySpend, anomalies[0].Kind)
+	assert.Equal(t, 30.0, anomalies[0].CostUSD)
+	assert.Equal(t, DefaultMinHourlySpendUSD, anomalies[0].ThresholdUSD)
+	assert.Equal(t, []string{"repo_a", "repo_b"}, anomalies[0].RepoIDs)
+
+	anomalies, err = a.Analyze(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, anomalies, "an ongoing spike is reported once")
+
+	repo.spend[time.Hour] = []RepoSpend{{RepoID: "repo_a", CostUSD: 2}}
+	anomalies, err = a.Analyze(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, anomalies)
+
+	repo.spend[time.Hour] = []RepoSpend{{RepoID: "repo_a", CostUSD: 40}}
+	anomalies, err = a.Analyze(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, anomalies, 1, "a new spike after spend recovered is reported again")
+	assert.Len(t, alerter.anomalies, 2)
+}
+
+func TestAnalyzer_HourlySpikeNoBaseline(t *testing.T) {
+	repo := &fakeRepo{
+		now: time.Now(),
+		spend: map[time.Duration][]RepoSpend{
+			time.Hour:      {{RepoID: "repo_a", CostUSD: 50}},
+			baselineWindow: {{RepoID: "repo_a", CostUSD: 50}},
+		},
+	}
+	a, _, _ := newTestAnalyzer(repo, Config{})
+
+	anomalies, err := a.Analyze(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, anomalies, "spend with no history before the last hour is not a spike")
+}
+
+func TestAnalyzer_AutoPause(t *testing.T) {
+	repo := &fakeRepo{
+		now:      time.Now(),
+		finished: finishedCosts("repo_a", 1, 1, 1),
+		active:   []TaskCost{{TaskID: "tsk_runaway", RepoID: "repo_a", CostUSD: 10}},
+	}
+	a, store, alerter := newTestAnalyzer(repo, Config{MinSamples: 3, AutoPause: true})
+
+	_, err := a.Analyze(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, store.pause)
+	assert.Equal(t, "task tsk_runaway spent $10.00 (threshold $3.00)", store.pause.Reason)
+	assert.Len(t, store.pause.Anomalies, 1)
+	assert.Equal(t, []bool{true}, alerter.paused)
+}
+
+func TestSwitch(t *testing.T) {
+	ctx := context.Background()
+	s := NewSwitch(&memPauseStore{})
+
+	paused, err := s.DispatchPaused(ctx)
+	require.NoError(t, err)
+	assert.False(t, paused)
+
+	acked, err := s.Acknowledge(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, acked, "acknowledging without a pause is a no-op")
+
+	first, err := s.Trip(ctx, " runaway spend ", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "runaway spend", first.Reason)
+
+	second, err := s.Trip(ctx, "another reason", nil)
+	require.NoError(t, err)
+	assert.Equal(t, first, second, "tripping while paused keeps the original pause")
+
+	paused, err = s.DispatchPaused(ctx)
+	require.NoError(t, err)
+	assert.True(t, paused)
+
+	acked, err = s.Acknowledge(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, first, acked)
+
+	paused, err = s.DispatchPaused(ctx)
+	require.NoError(t, err)
+	assert.False(t, paused)
+}
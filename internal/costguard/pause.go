@@ -0,0 +1,88 @@
+package costguard
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Pause is an instance-wide pause on dispatching new work to agents.
+type Pause struct {
+	Reason string `json:"reason"`
+	// Anomalies are the anomalies that tripped the pause. They are empty
+	// when an admin paused dispatch by hand.
+	Anomalies []Anomaly `json:"anomalies,omitempty"`
+	PausedAt  time.Time `json:"paused_at"`
+}
+
+// PauseStore persists the dispatch pause. Reads must not be served from a
+// per-replica cache so that every replica sees a pause as soon as it is
+// tripped or acknowledged.
+type PauseStore interface {
+	// ReadDispatchPause returns the current pause, or nil when dispatch is
+	// not paused.
+	ReadDispatchPause(ctx context.Context) (*Pause, error)
+	SetDispatchPause(ctx context.Context, p *Pause) error
+	ClearDispatchPause(ctx context.Context) error
+}
+
+// Switch is the instance-wide dispatch kill switch. Once tripped, agents are
+// handed no new tasks, epics or conversations until the pause is
+// acknowledged. Work that is already running is not interrupted.
+type Switch struct {
+	store PauseStore
+	now   func() time.Time
+}
+
+// NewSwitch creates a new Switch.
+func NewSwitch(store PauseStore) *Switch {
+	return &Switch{store: store, now: time.Now}
+}
+
+// Status returns the current pause, or nil when dispatch is not paused.
+func (s *Switch) Status(ctx context.Context) (*Pause, error) {
+	return s.store.ReadDispatchPause(ctx)
+}
+
+// DispatchPaused reports whether dispatching new work is paused.
+func (s *Switch) DispatchPaused(ctx context.Context) (bool, error) {
+	p, err := s.store.ReadDispatchPause(ctx)
+	if err != nil {
+		return false, err
+	}
+	return p != nil, nil
+}
+
+// Trip pauses dispatch. When dispatch is already paused, the existing pause
+// is kept and returned so the original reason survives until acknowledged.
+func (s *Switch) Trip(ctx context.Context, reason string, anomalies []Anomaly) (*Pause, error) {
+	existing, err := s.store.ReadDispatchPause(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+	p := &Pause{
+		Reason:    strings.TrimSpace(reason),
+		Anomalies: anomalies,
+		PausedAt:  s.now().UTC(),
+	}
+	if err := s.store.SetDispatchPause(ctx, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Acknowledge lifts the pause and returns it, or returns nil when dispatch
+// was not paused.
+func (s *Switch) Acknowledge(ctx context.Context) (*Pause, error) {
+	existing, err := s.store.ReadDispatchPause(ctx)
+	if err != nil || existing == nil {
+		return nil, err
+	}
+	if err := s.store.ClearDispatchPause(ctx); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
@@ -0,0 +1,197 @@
+// Package digest summarizes what changed in a repo since a point in time, so
+// callers catching up on a repo don't have to diff full task lists.
+package digest
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/vervesh/verve/internal/task"
+)
+
+const (
+	// maxSectionTasks caps the tasks listed in each digest section. Counts
+	// always cover every matching task.
+	maxSectionTasks = 50
+	// maxTransitions caps the status transitions listed in a digest, keeping
+	// the most recent.
+	maxTransitions = 200
+)
+
+// Attempt is a task attempt along with the task it belongs to.
+type Attempt struct {
+	TaskID     string
+	TaskNumber int
+	TaskTitle  string
+	task.Attempt
+}
+
+// Repository is the data source for building digests.
+type Repository interface {
+	// ListTasksUpdatedSince returns the repo's tasks updated at or after
+	// since, most recently updated first.
+	ListTasksUpdatedSince(ctx context.Context, repoID string, since time.Time) ([]*task.Task, error)
+	// ListAttemptsSince returns the repo's task attempts that started or
+	// ended at or after since, most recent activity first.
+	ListAttemptsSince(ctx context.Context, repoID string, since time.Time) ([]Attempt, error)
+}
+
+// TaskSummary is the compact form of a task listed in a digest.
+type TaskSummary struct {
+	ID              string      `json:"id"`
+	Number          int         `json:"number"`
+	Title           string      `json:"title"`
+	Status          task.Status `json:"status"`
+	FailureCategory string      `json:"failure_category,omitempty"`
+	PullRequestURL  string      `json:"pull_request_url,omitempty"`
+	UpdatedAt       time.Time   `json:"updated_at"`
+}
+
+// Section is a group of tasks in a digest. Tasks lists the most recently
+// updated tasks first and is capped; Count is the full number of tasks.
+type Section struct {
+	Count int           `json:"count"`
+	Tasks []TaskSummary `json:"tasks"`
+}
+
+// Transition is a task status change recorded by an agent attempt. Starting
+// an attempt moves a task from pending to running, and ending it moves the
+// task to the attempt's result.
+type Transition struct {
+	TaskID     string      `json:"task_id"`
+	TaskNumber int         `json:"task_number"`
+	TaskTitle  string      `json:"task_title"`
+	Attempt    int         `json:"attempt"`
+	From       task.Status `json:"from"`
+	To         task.Status `json:"to"`
+	// Result is set when the attempt ended as retried, stopped or
+	// abandoned, all of which return the task to pending.
+	Result string    `json:"result,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// Digest summarizes the changes to a repo between Since and Until. Until is
+// the time the digest was built and can be passed as the next since.
+type Digest struct {
+	RepoID  string    `json:"repo_id"`
+	Since   time.Time `json:"since"`
+	Until   time.Time `json:"until"`
+	Created Section   `json:"created"`
+	Merged  Section   `json:"merged"`
+	Failed  Section   `json:"failed"`
+	Closed  Section   `json:"closed"`
+	// Updated is the number of tasks changed in the window, and
+	// StatusCounts breaks them down by current status.
+	Updated      int                 `json:"updated"`
+	StatusCounts map[task.Status]int `json:"status_counts"`
+	Transitions  []Transition        `json:"transitions"`
+	// CostUSD is the agent spend on attempts active in the window.
+	CostUSD float64 `json:"cost_usd"`
+}
+
+// Build builds the digest for a repo since the given time.
+func Build(ctx context.Context, repo Repository, repoID string, since time.Time) (*Digest, error) {
+	// Take until before reading so that nothing changed during the reads is
+	// missed by a follow-up digest since until.
+	until := time.Now().UTC()
+
+	tasks, err := repo.ListTasksUpdatedSince(ctx, repoID, since)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := repo.ListAttemptsSince(ctx, repoID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Digest{
+		RepoID:       repoID,
+		Since:        since.UTC(),
+		Until:        until,
+		Created:      Section{Tasks: []TaskSummary{}},
+		Merged:       Section{Tasks: []TaskSummary{}},
+		Failed:       Section{Tasks: []TaskSummary{}},
+		Closed:       Section{Tasks: []TaskSummary{}},
+		Updated:      len(tasks),
+		StatusCounts: make(map[task.Status]int),
+		Transitions:  []Transition{},
+	}
+
+	for _, t := range tasks {
+		d.StatusCounts[t.Status]++
+		if !t.CreatedAt.Before(since) {
+			d.Created.add(t)
+		}
+		switch t.Status {
+		case task.StatusMerged:
+			d.Merged.add(t)
+		case task.StatusFailed:
+			d.Failed.add(t)
+		case task.StatusClosed:
+			d.Closed.add(t)
+		}
+	}
+
+	for _, a := range attempts {
+		if a.EndedAt == nil || !a.EndedAt.Before(since) {
+			d.CostUSD += a.CostUSD
+		}
+		if a.EndedAt != nil && !a.EndedAt.Before(since) {
+			d.addTransition(a, task.StatusRunning, endStatus(a.Result), *a.EndedAt)
+		}
+		if !a.StartedAt.Before(since) {
+			d.addTransition(a, task.StatusPending, task.StatusRunning, a.StartedAt)
+		}
+	}
+	slices.SortStableFunc(d.Transitions, func(a, b Transition) int {
+		return b.At.Compare(a.At)
+	})
+	if len(d.Transitions) > maxTransitions {
+		d.Transitions = d.Transitions[:maxTransitions]
+	}
+
+	return d, nil
+}
+
+func (s *Section) add(t *task.Task) {
+	s.Count++
+	if len(s.Tasks) >= maxSectionTasks {
+		return
+	}
+	s.Tasks = append(s.Tasks, TaskSummary{
+		ID:              t.ID.String(),
+		Number:          t.Number,
+		Title:           t.Title,
+		Status:          t.Status,
+		FailureCategory: t.FailureCategory,
+		PullRequestURL:  t.PullRequestURL,
+		UpdatedAt:       t.UpdatedAt,
+	})
+}
+
+func (d *Digest) addTransition(a Attempt, from, to task.Status, at time.Time) {
+	tr := Transition{
+		TaskID:     a.TaskID,
+		TaskNumber: a.TaskNumber,
+		TaskTitle:  a.TaskTitle,
+		Attempt:    a.Number,
+		From:       from,
+		To:         to,
+		At:         at,
+	}
+	if from == task.StatusRunning && to == task.StatusPending {
+		tr.Result = a.Result
+	}
+	d.Transitions = append(d.Transitions, tr)
+}
+
+// endStatus returns the status a task moved to when an attempt ended with
+// the given result.
+func endStatus(result string) task.Status {
+	switch result {
+	case task.AttemptResultRetried, task.AttemptResultStopped, task.AttemptResultAbandoned:
+		return task.StatusPending
+	}
+	return task.Status(result)
+}
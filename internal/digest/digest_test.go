@@ -0,0 +1,137 @@
+package digest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/task"
+)
+
+type fakeRepo struct {
+	tasks    []*task.Task
+	attempts []Attempt
+}
+
+func (f *fakeRepo) ListTasksUpdatedSince(_ context.Context, _ string, since time.Time) ([]*task.Task, error) {
+	var out []*task.Task
+	for _, t := range f.tasks {
+		if !t.UpdatedAt.Before(since) {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) ListAttemptsSince(_ context.Context, _ string, since time.Time) ([]Attempt, error) {
+	var out []Attempt
+	for _, a := range f.attempts {
+		if !a.StartedAt.Before(since) || (a.EndedAt != nil && !a.EndedAt.Before(since)) {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func seedTask(number int, status task.Status, created, updated time.Time) *task.Task {
+	t := task.NewTask("repo_123", "task", "", nil, nil, 0, false, false, "", true)
+	t.Number = number
+	t.Status = status
+	t.CreatedAt = created
+	t.UpdatedAt = updated
+	return t
+}
+
+func attempt(tsk *task.Task, number int, result string, cost float64, started time.Time, ended *time.Time) Attempt {
+	return Attempt{
+		TaskID:     tsk.ID.String(),
+		TaskNumber: tsk.Number,
+		TaskTitle:  tsk.Title,
+		Attempt: task.Attempt{
+			Number:    number,
+			Result:    result,
+			CostUSD:   cost,
+			StartedAt: started,
+			EndedAt:   ended,
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	since := time.Now().Add(-24 * time.Hour)
+	before := since.Add(-time.Hour)
+	after := func(d time.Duration) time.Time { return since.Add(d) }
+	endedAt := func(d time.Duration) *time.Time { ts := after(d); return &ts }
+
+	created := seedTask(1, task.StatusPending, after(time.Hour), after(time.Hour))
+	merged := seedTask(2, task.StatusMerged, before, after(3*time.Hour))
+	merged.PullRequestURL = "https://github.com/owner/repo/pull/2"
+	failed := seedTask(3, task.StatusFailed, after(time.Minute), after(2*time.Hour))
+	failed.FailureCategory = task.FailureMaxAttempts
+	unchanged := seedTask(4, task.StatusClosed, before, before)
+
+	repo := &fakeRepo{
+		tasks: []*task.Task{merged, failed, created, unchanged},
+		attempts: []Attempt{
+			// Started before since and merged within the window.
+			attempt(merged, 1, string(task.StatusMerged), 2, before, endedAt(3*time.Hour)),
+			attempt(failed, 1, task.AttemptResultRetried, 0.5, after(time.Minute), endedAt(time.Hour)),
+			attempt(failed, 2, string(task.StatusFailed), 0.25, after(90*time.Minute), endedAt(2*time.Hour)),
+			// Ended before since, so outside the window.
+			attempt(unchanged, 1, string(task.StatusClosed), 10, before, &before),
+		},
+	}
+
+	d, err := Build(context.Background(), repo, "repo_123", since)
+	require.NoError(t, err)
+
+	assert.Equal(t, "repo_123", d.RepoID)
+	assert.False(t, d.Until.Before(since))
+	assert.Equal(t, 3, d.Updated)
+	assert.Equal(t, map[task.Status]int{task.StatusPending: 1, task.StatusMerged: 1, task.StatusFailed: 1}, d.StatusCounts)
+
+	assert.Equal(t, 2, d.Created.Count)
+	assert.Equal(t, failed.ID.String(), d.Created.Tasks[0].ID)
+	assert.Equal(t, created.ID.String(), d.Created.Tasks[1].ID)
+
+	require.Equal(t, 1, d.Merged.Count)
+	assert.Equal(t, merged.PullRequestURL, d.Merged.Tasks[0].PullRequestURL)
+	require.Equal(t, 1, d.Failed.Count)
+	assert.Equal(t, task.FailureMaxAttempts, d.Failed.Tasks[0].FailureCategory)
+	assert.Zero(t, d.Closed.Count)
+	assert.Empty(t, d.Closed.Tasks)
+
+	assert.InDelta(t, 2.75, d.CostUSD, 0.001)
+
+	require.Len(t, d.Transitions, 5)
+	assert.Equal(t, Transition{
+		TaskID: merged.ID.String(), TaskNumber: 2, TaskTitle: "task", Attempt: 1,
+		From: task.StatusRunning, To: task.StatusMerged, At: after(3 * time.Hour),
+	}, d.Transitions[0])
+	assert.Equal(t, task.StatusFailed, d.Transitions[1].To)
+	assert.Equal(t, task.StatusRunning, d.Transitions[2].To, "the second attempt started")
+	assert.Equal(t, task.StatusPending, d.Transitions[3].To)
+	assert.Equal(t, task.AttemptResultRetried, d.Transitions[3].Result)
+	assert.Equal(t, task.StatusRunning, d.Transitions[4].To, "the first attempt started")
+}
+
+func TestBuild_Caps(t *testing.T) {
+	since := time.Now().Add(-time.Hour)
+	repo := &fakeRepo{}
+	for i := range maxSectionTasks + 5 {
+		tsk := seedTask(i+1, task.StatusMerged, since, since.Add(time.Minute))
+		repo.tasks = append(repo.tasks, tsk)
+		for n := range 4 {
+			repo.attempts = append(repo.attempts, attempt(tsk, n+1, task.AttemptResultRetried, 0, since, &since))
+		}
+	}
+
+	d, err := Build(context.Background(), repo, "repo_123", since)
+	require.NoError(t, err)
+	assert.Equal(t, maxSectionTasks+5, d.Merged.Count)
+	assert.Len(t, d.Merged.Tasks, maxSectionTasks)
+	assert.Len(t, d.Transitions, maxTransitions)
+}
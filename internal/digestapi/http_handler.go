@@ -0,0 +1,46 @@
+package digestapi
+
+import (
+	"net/http"
+
+	"github.com/joshjon/kit/server"
+	"github.com/labstack/echo/v4"
+
+	"github.com/vervesh/verve/internal/digest"
+	"github.com/vervesh/verve/internal/logkey"
+	"github.com/vervesh/verve/internal/repo"
+)
+
+// HTTPHandler handles repo digest HTTP requests.
+type HTTPHandler struct {
+	repo digest.Repository
+}
+
+// NewHTTPHandler creates a new HTTPHandler.
+func NewHTTPHandler(repo digest.Repository) *HTTPHandler {
+	return &HTTPHandler{repo: repo}
+}
+
+// Register adds the endpoints to the provided Echo router group.
+func (h *HTTPHandler) Register(g *echo.Group) {
+	g.GET("/repos/:repo_id/digest", h.GetDigest)
+}
+
+// GetDigest handles GET /repos/:repo_id/digest?since=<timestamp>
+// Returns a summary of the tasks created, merged, failed and closed, the
+// status transitions and the spend in a repo since the given time.
+func (h *HTTPHandler) GetDigest(c echo.Context) error {
+	req, err := server.BindRequest[DigestRequest](c)
+	if err != nil {
+		return err
+	}
+	id := repo.MustParseRepoID(req.RepoID)
+	c.Set(logkey.RepoID, id.String())
+
+	since, _ := parseSince(req.Since) // Validated by BindRequest
+	d, err := digest.Build(c.Request().Context(), h.repo, id.String(), since)
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, d)
+}
@@ -0,0 +1,73 @@
+package digestapi_test
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/joshjon/kit/server"
+	"github.com/joshjon/kit/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/digestapi"
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/sqlite"
+	"github.com/vervesh/verve/internal/task"
+)
+
+type fixture struct {
+	Server   *server.Server
+	TaskRepo task.Repository
+	Repo     *repo.Repo
+	t        *testing.T
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	db := sqlite.NewTestDB(t)
+	taskRepo := sqlite.NewTaskRepository(db)
+
+	repoRepo := sqlite.NewRepoRepository(db)
+	repoStore := repo.NewStore(repoRepo)
+
+	handler := digestapi.NewHTTPHandler(sqlite.NewDigestRepository(db))
+
+	srv, err := server.NewServer(testutil.GetFreePort(t))
+	require.NoError(t, err)
+	srv.Register("/api/v1", handler)
+
+	go srv.Start()
+	err = srv.WaitHealthy(10, 100*time.Millisecond)
+	require.NoError(t, err)
+
+	// Pre-create a repo for use in tests.
+	r, _ := repo.NewRepo("owner/test-repo")
+	require.NoError(t, repoStore.CreateRepo(context.Background(), r))
+
+	t.Cleanup(func() { srv.Stop(context.Background()) })
+
+	return &fixture{
+		Server:   srv,
+		TaskRepo: taskRepo,
+		Repo:     r,
+		t:        t,
+	}
+}
+
+func (f *fixture) digestURL(since string) string {
+	return fmt.Sprintf("%s/api/v1/repos/%s/digest?since=%s", f.Server.Address(), f.Repo.ID, url.QueryEscape(since))
+}
+
+func (f *fixture) seedTask(title string, status task.Status) *task.Task {
+	f.t.Helper()
+	ctx := context.Background()
+	tsk := task.NewTask(f.Repo.ID.String(), title, "desc", nil, nil, 0, false, false, "sonnet", true)
+	require.NoError(f.t, f.TaskRepo.CreateTask(ctx, tsk))
+	if status != task.StatusPending {
+		require.NoError(f.t, f.TaskRepo.UpdateTaskStatus(ctx, tsk.ID, status))
+	}
+	return tsk
+}
@@ -0,0 +1,58 @@
+package digestapi_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/joshjon/kit/server"
+	"github.com/joshjon/kit/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/digest"
+	"github.com/vervesh/verve/internal/task"
+)
+
+func TestGetDigest(t *testing.T) {
+	f := newFixture(t)
+
+	f.seedTask("Pending Task", task.StatusPending)
+	merged := f.seedTask("Merged Task", task.StatusMerged)
+	failed := f.seedTask("Failed Task", task.StatusFailed)
+
+	since := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	res := testutil.Get[server.Response[digest.Digest]](t, f.digestURL(since))
+	assert.Equal(t, f.Repo.ID.String(), res.Data.RepoID)
+	assert.Equal(t, 3, res.Data.Created.Count)
+	assert.Equal(t, 3, res.Data.Updated)
+	require.Equal(t, 1, res.Data.Merged.Count)
+	assert.Equal(t, merged.ID.String(), res.Data.Merged.Tasks[0].ID)
+	require.Equal(t, 1, res.Data.Failed.Count)
+	assert.Equal(t, failed.ID.String(), res.Data.Failed.Tasks[0].ID)
+	assert.Zero(t, res.Data.Closed.Count)
+	assert.Equal(t, 1, res.Data.StatusCounts[task.StatusPending])
+}
+
+func TestGetDigest_UnixSince(t *testing.T) {
+	f := newFixture(t)
+
+	f.seedTask("Pending Task", task.StatusPending)
+
+	since := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	res := testutil.Get[server.Response[digest.Digest]](t, f.digestURL(since))
+	assert.Zero(t, res.Data.Updated, "tasks changed before since are excluded")
+	assert.Empty(t, res.Data.Created.Tasks)
+}
+
+func TestGetDigest_InvalidSince(t *testing.T) {
+	f := newFixture(t)
+
+	for _, since := range []string{"", "yesterday"} {
+		httpRes, err := http.Get(f.digestURL(since))
+		require.NoError(t, err)
+		httpRes.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode, "expected validation error for since %q", since)
+	}
+}
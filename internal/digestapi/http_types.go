@@ -0,0 +1,34 @@
+package digestapi
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/cohesivestack/valgo"
+
+	"github.com/vervesh/verve/internal/repo"
+)
+
+// DigestRequest captures the :repo_id path parameter and the required
+// ?since= timestamp, given as RFC 3339 or unix seconds.
+type DigestRequest struct {
+	RepoID string `param:"repo_id" json:"-"`
+	Since  string `query:"since" json:"-"`
+}
+
+func (r DigestRequest) Validate() error {
+	v := valgo.In("params", valgo.Is(repo.RepoIDValidator(r.RepoID, "repo_id")))
+	if r.Since == "" {
+		v = v.AddErrorMessage("since", "is required")
+	} else if _, err := parseSince(r.Since); err != nil {
+		v = v.AddErrorMessage("since", "must be an RFC 3339 timestamp or unix seconds")
+	}
+	return v.ToError()
+}
+
+func parseSince(s string) (time.Time, error) {
+	if secs, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(secs, 0).UTC(), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
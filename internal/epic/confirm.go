@@ -0,0 +1,78 @@
+package epic
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/joshjon/kit/errtag"
+)
+
+// ErrTagInvalidSelection indicates a partial confirmation selected proposed
+// tasks that can't be confirmed on their own.
+type ErrTagInvalidSelection struct{ errtag.InvalidArgument }
+
+func (e ErrTagInvalidSelection) Unwrap() error {
+	return errtag.Tag[errtag.InvalidArgument](e.Cause())
+}
+
+// SelectProposedTasks splits proposed into the tasks named by tempIDs and the
+// rest, each in their original order. An empty selection selects every task.
+// Selected tasks may depend on each other or on tasks that already exist,
+// but not on a proposed task left out of the selection: it would be created
+// without that dependency and could run before it.
+func SelectProposedTasks(proposed []ProposedTask, tempIDs []string) (selected, rest []ProposedTask, err error) {
+	if len(tempIDs) == 0 {
+		return proposed, nil, nil
+	}
+
+	byTempID := make(map[string]bool, len(proposed))
+	for _, pt := range proposed {
+		byTempID[pt.TempID] = true
+	}
+	want := make(map[string]bool, len(tempIDs))
+	for _, tempID := range tempIDs {
+		if !byTempID[tempID] {
+			return nil, nil, invalidSelection(fmt.Sprintf("unknown proposed task %q", tempID))
+		}
+		want[tempID] = true
+	}
+
+	for _, pt := range proposed {
+		if !want[pt.TempID] {
+			rest = append(rest, pt)
+			continue
+		}
+		for _, dep := range pt.DependsOnTempIDs {
+			if byTempID[dep] && !want[dep] {
+				return nil, nil, invalidSelection(fmt.Sprintf("proposed task %q depends on %q, which is not being confirmed", pt.TempID, dep))
+			}
+		}
+		selected = append(selected, pt)
+	}
+	return selected, rest, nil
+}
+
+func invalidSelection(reason string) error {
+	return errtag.Tag[ErrTagInvalidSelection](errors.New(reason), errtag.WithMsg(reason))
+}
+
+// remapDependencies rewrites the dependencies of proposed tasks that are
+// still to be confirmed so those on newly created tasks name the task ID, as
+// they would for any other existing task.
+func remapDependencies(proposed []ProposedTask, tempToReal map[string]string) []ProposedTask {
+	out := make([]ProposedTask, 0, len(proposed))
+	for _, pt := range proposed {
+		if len(pt.DependsOnTempIDs) > 0 {
+			deps := make([]string, len(pt.DependsOnTempIDs))
+			for i, dep := range pt.DependsOnTempIDs {
+				if realID, ok := tempToReal[dep]; ok {
+					dep = realID
+				}
+				deps[i] = dep
+			}
+			pt.DependsOnTempIDs = deps
+		}
+		out = append(out, pt)
+	}
+	return out
+}
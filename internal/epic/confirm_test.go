@@ -0,0 +1,54 @@
+package epic
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectProposedTasks(t *testing.T) {
+	proposed := []ProposedTask{
+		{TempID: "t1", Title: "API"},
+		{TempID: "t2", Title: "UI", DependsOnTempIDs: []string{"t1"}},
+		{TempID: "t3", Title: "Docs", DependsOnTempIDs: []string{"tsk_existing"}},
+	}
+	tests := map[string]struct {
+		tempIDs      []string
+		wantSelected []string
+		wantErr      bool
+	}{
+		"all by default":              {wantSelected: []string{"t1", "t2", "t3"}},
+		"with dependency":             {tempIDs: []string{"t2", "t1"}, wantSelected: []string{"t1", "t2"}},
+		"depends on existing task":    {tempIDs: []string{"t3"}, wantSelected: []string{"t3"}},
+		"depends on unconfirmed task": {tempIDs: []string{"t2"}, wantErr: true},
+		"unknown temp ID":             {tempIDs: []string{"t9"}, wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			selected, rest, err := SelectProposedTasks(proposed, tt.tempIDs)
+			if tt.wantErr {
+				var tagErr ErrTagInvalidSelection
+				assert.True(t, errors.As(err, &tagErr), "expected ErrTagInvalidSelection, got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			var got []string
+			for _, pt := range selected {
+				got = append(got, pt.TempID)
+			}
+			assert.Equal(t, tt.wantSelected, got)
+			assert.Len(t, rest, len(proposed)-len(selected))
+		})
+	}
+}
+
+func TestRemapDependencies(t *testing.T) {
+	got := remapDependencies([]ProposedTask{
+		{TempID: "t2", DependsOnTempIDs: []string{"t1", "t3"}},
+		{TempID: "t3"},
+	}, map[string]string{"t1": "tsk_1"})
+	assert.Equal(t, []string{"tsk_1", "t3"}, got[0].DependsOnTempIDs)
+	assert.Nil(t, got[1].DependsOnTempIDs)
+}
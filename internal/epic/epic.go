@@ -21,16 +21,18 @@ const (
 	FeedbackMessage   FeedbackType = "message"   // User sent a feedback message
 	FeedbackConfirmed FeedbackType = "confirmed" // User confirmed the epic
 	FeedbackClosed    FeedbackType = "closed"    // User closed the epic
+	FeedbackReplan    FeedbackType = "replan"    // User asked to re-plan an active epic's remaining work
 )
 
 // ProposedTask represents a task proposed by the planning agent during
 // an epic planning session. These are not yet created in the task system.
 type ProposedTask struct {
-	TempID             string   `json:"temp_id"`
-	Title              string   `json:"title"`
-	Description        string   `json:"description"`
-	DependsOnTempIDs   []string `json:"depends_on_temp_ids,omitempty"`
-	AcceptanceCriteria []string `json:"acceptance_criteria,omitempty"`
+	TempID             string        `json:"temp_id"`
+	Title              string        `json:"title"`
+	Description        string        `json:"description"`
+	DependsOnTempIDs   []string      `json:"depends_on_temp_ids,omitempty"`
+	AcceptanceCriteria []string      `json:"acceptance_criteria,omitempty"`
+	Estimate           *TaskEstimate `json:"estimate,omitempty"`
 }
 
 // Epic represents a large deliverable that contains multiple related tasks.
@@ -44,6 +46,7 @@ type Epic struct {
 	ProposedTasks   []ProposedTask `json:"proposed_tasks"`
 	TaskIDs         []string       `json:"task_ids"`
 	PlanningPrompt  string         `json:"planning_prompt,omitempty"`
+	PlanningSummary string         `json:"planning_summary,omitempty"`
 	SessionLog      []string       `json:"session_log"`
 	NotReady        bool           `json:"not_ready"`
 	Model           string         `json:"model,omitempty"`
@@ -51,8 +54,17 @@ type Epic struct {
 	LastHeartbeatAt *time.Time     `json:"last_heartbeat_at,omitempty"`
 	Feedback        *string        `json:"feedback,omitempty"`
 	FeedbackType    *string        `json:"feedback_type,omitempty"`
+	CostUSD         float64        `json:"cost_usd"`
+	MaxCostUSD      float64        `json:"max_cost_usd,omitempty"`
+	SharedBranch    bool           `json:"shared_branch"`
+	PullRequestURL  string         `json:"pull_request_url,omitempty"`
+	PRNumber        int            `json:"pr_number,omitempty"`
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
+
+	// Transcript is the session log with roles and timestamps. It is
+	// served by its own endpoint rather than with the epic.
+	Transcript []TranscriptEntry `json:"-"`
 }
 
 // NewEpic creates a new Epic in planning status, queued for a worker to claim.
@@ -67,7 +79,23 @@ func NewEpic(repoID, title, description string) *Epic {
 		ProposedTasks: []ProposedTask{},
 		TaskIDs:       []string{},
 		SessionLog:    []string{},
+		Transcript:    []TranscriptEntry{},
 		CreatedAt:     now,
 		UpdatedAt:     now,
 	}
 }
+
+// BranchName returns the epic's shared integration branch, or an empty string
+// if its tasks branch from and merge into the repo's default branch.
+func (e *Epic) BranchName() string {
+	if !e.SharedBranch {
+		return ""
+	}
+	return "epic/" + e.ID.String()
+}
+
+// planningBudgetExceeded reports whether the epic has a planning budget and
+// its accumulated cost has reached it.
+func (e *Epic) planningBudgetExceeded() bool {
+	return e.MaxCostUSD > 0 && e.CostUSD >= e.MaxCostUSD
+}
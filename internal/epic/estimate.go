@@ -0,0 +1,71 @@
+package epic
+
+// Complexity is the planning agent's estimate of how hard a proposed task is.
+type Complexity string
+
+const (
+	ComplexityLow    Complexity = "low"    // Contained change following existing patterns
+	ComplexityMedium Complexity = "medium" // Several files or a new pattern
+	ComplexityHigh   Complexity = "high"   // Cross-cutting or exploratory work
+)
+
+// Valid reports whether c is a known complexity.
+func (c Complexity) Valid() bool {
+	switch c {
+	case ComplexityLow, ComplexityMedium, ComplexityHigh:
+		return true
+	}
+	return false
+}
+
+// TaskEstimate is the planning agent's estimate for a proposed task. The
+// upper end of the cost range becomes the task's budget when it is created.
+type TaskEstimate struct {
+	Model      string     `json:"model,omitempty"` // Recommended model
+	MinCostUSD float64    `json:"min_cost_usd,omitempty"`
+	MaxCostUSD float64    `json:"max_cost_usd,omitempty"`
+	Complexity Complexity `json:"complexity,omitempty"`
+}
+
+// sanitizeEstimates drops the parts of agent estimates that don't make
+// sense instead of rejecting the plan: unknown complexities and negative
+// costs. A reversed cost range is swapped.
+func sanitizeEstimates(tasks []ProposedTask) []ProposedTask {
+	for i, pt := range tasks {
+		if pt.Estimate == nil {
+			continue
+		}
+		est := *pt.Estimate
+		if !est.Complexity.Valid() {
+			est.Complexity = ""
+		}
+		est.MinCostUSD = max(est.MinCostUSD, 0)
+		est.MaxCostUSD = max(est.MaxCostUSD, 0)
+		if est.MaxCostUSD > 0 && est.MinCostUSD > est.MaxCostUSD {
+			est.MinCostUSD, est.MaxCostUSD = est.MaxCostUSD, est.MinCostUSD
+		}
+		if est == (TaskEstimate{}) {
+			tasks[i].Estimate = nil
+			continue
+		}
+		tasks[i].Estimate = &est
+	}
+	return tasks
+}
+
+// taskModel returns the model for the task created from pt: the epic's
+// model if one was chosen, otherwise the recommended one.
+func (pt ProposedTask) taskModel(epicModel string) string {
+	if epicModel == "" && pt.Estimate != nil {
+		return pt.Estimate.Model
+	}
+	return epicModel
+}
+
+// maxCostUSD returns the budget for the task created from pt, or 0 for none.
+func (pt ProposedTask) maxCostUSD() float64 {
+	if pt.Estimate == nil {
+		return 0
+	}
+	return pt.Estimate.MaxCostUSD
+}
@@ -0,0 +1,48 @@
+package epic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeEstimates(t *testing.T) {
+	tests := map[string]struct {
+		estimate *TaskEstimate
+		want     *TaskEstimate
+	}{
+		"none": {},
+		"valid": {
+			estimate: &TaskEstimate{Model: "opus", MinCostUSD: 2, MaxCostUSD: 5, Complexity: ComplexityHigh},
+			want:     &TaskEstimate{Model: "opus", MinCostUSD: 2, MaxCostUSD: 5, Complexity: ComplexityHigh},
+		},
+		"unknown complexity": {
+			estimate: &TaskEstimate{MaxCostUSD: 3, Complexity: "huge"},
+			want:     &TaskEstimate{MaxCostUSD: 3},
+		},
+		"negative costs": {
+			estimate: &TaskEstimate{Model: "haiku", MinCostUSD: -1, MaxCostUSD: -2},
+			want:     &TaskEstimate{Model: "haiku"},
+		},
+		"reversed range": {
+			estimate: &TaskEstimate{MinCostUSD: 4, MaxCostUSD: 1},
+			want:     &TaskEstimate{MinCostUSD: 1, MaxCostUSD: 4},
+		},
+		"nothing left": {
+			estimate: &TaskEstimate{Complexity: "unknown", MinCostUSD: -1},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tasks := sanitizeEstimates([]ProposedTask{{TempID: "t1", Estimate: tt.estimate}})
+			assert.Equal(t, tt.want, tasks[0].Estimate)
+		})
+	}
+}
+
+func TestProposedTask_TaskModel(t *testing.T) {
+	pt := ProposedTask{Estimate: &TaskEstimate{Model: "haiku"}}
+	assert.Equal(t, "haiku", pt.taskModel(""))
+	assert.Equal(t, "opus", pt.taskModel("opus"), "the epic's model wins")
+	assert.Empty(t, ProposedTask{}.taskModel(""))
+}
@@ -0,0 +1,55 @@
+package epic
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/joshjon/kit/errtag"
+)
+
+// ErrTagInvalidForcedStatus indicates a forced status change was rejected
+// because it would leave the epic in a state the rest of the system can't
+// handle.
+type ErrTagInvalidForcedStatus struct{ errtag.Conflict }
+
+func (e ErrTagInvalidForcedStatus) Unwrap() error {
+	return errtag.Tag[errtag.Conflict](e.Cause())
+}
+
+// ValidStatus reports whether s is a known epic status.
+func ValidStatus(s Status) bool {
+	switch s {
+	case StatusDraft, StatusPlanning, StatusReady, StatusActive, StatusCompleted, StatusClosed:
+		return true
+	}
+	return false
+}
+
+// ValidateForcedStatus checks whether e may be forced into status to. Forced
+// changes bypass the normal lifecycle, but must keep the epic consistent
+// with its tasks:
+//   - planning requires a worker session, so it is started with StartPlanning
+//   - ready, active and completed require confirmed tasks, and draft requires
+//     that no tasks have been created yet
+//   - completed epics can only be reopened as active or closed
+func ValidateForcedStatus(e *Epic, to Status) error {
+	hasTasks := len(e.TaskIDs) > 0
+	var reason string
+	switch {
+	case !ValidStatus(to):
+		reason = fmt.Sprintf("unknown epic status %q", to)
+	case e.Status == to:
+		reason = fmt.Sprintf("epic is already %s", to)
+	case to == StatusPlanning:
+		reason = "epics cannot be forced to planning; start a planning session instead"
+	case e.Status == StatusCompleted && to != StatusActive && to != StatusClosed:
+		reason = "completed epics can only be reopened as active or closed"
+	case (to == StatusReady || to == StatusActive || to == StatusCompleted) && !hasTasks:
+		reason = fmt.Sprintf("epic has no confirmed tasks to be %s", to)
+	case to == StatusDraft && hasTasks:
+		reason = "epic tasks have already been created"
+	default:
+		return nil
+	}
+	return errtag.Tag[ErrTagInvalidForcedStatus](errors.New(reason), errtag.WithMsg(reason))
+}
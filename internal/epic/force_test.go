@@ -0,0 +1,42 @@
+package epic
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateForcedStatus(t *testing.T) {
+	withTasks := []string{"tsk_1"}
+	tests := map[string]struct {
+		epic    Epic
+		to      Status
+		wantErr bool
+	}{
+		"draft to closed":              {epic: Epic{Status: StatusDraft}, to: StatusClosed},
+		"planning to closed":           {epic: Epic{Status: StatusPlanning}, to: StatusClosed},
+		"planning to draft":            {epic: Epic{Status: StatusPlanning}, to: StatusDraft},
+		"active to completed":          {epic: Epic{Status: StatusActive, TaskIDs: withTasks}, to: StatusCompleted},
+		"completed to active":          {epic: Epic{Status: StatusCompleted, TaskIDs: withTasks}, to: StatusActive},
+		"closed to ready":              {epic: Epic{Status: StatusClosed, TaskIDs: withTasks}, to: StatusReady},
+		"unknown status":               {epic: Epic{Status: StatusDraft}, to: "paused", wantErr: true},
+		"same status":                  {epic: Epic{Status: StatusDraft}, to: StatusDraft, wantErr: true},
+		"to planning":                  {epic: Epic{Status: StatusDraft}, to: StatusPlanning, wantErr: true},
+		"completed to ready":           {epic: Epic{Status: StatusCompleted, TaskIDs: withTasks}, to: StatusReady, wantErr: true},
+		"draft to active without task": {epic: Epic{Status: StatusDraft}, to: StatusActive, wantErr: true},
+		"new epic to active":           {epic: *NewEpic("repo_1", "Epic", ""), to: StatusActive, wantErr: true},
+		"active to draft with tasks":   {epic: Epic{Status: StatusActive, TaskIDs: withTasks}, to: StatusDraft, wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateForcedStatus(&tt.epic, tt.to)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			var tagErr ErrTagInvalidForcedStatus
+			assert.True(t, errors.As(err, &tagErr), "expected ErrTagInvalidForcedStatus, got %v", err)
+		})
+	}
+}
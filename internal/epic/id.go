@@ -4,6 +4,8 @@ import (
 	"github.com/cohesivestack/valgo"
 	"github.com/joshjon/kit/id"
 	"go.jetify.com/typeid"
+
+	"github.com/vervesh/verve/internal/msgcat"
 )
 
 type epicPrefix struct{}
@@ -38,5 +40,5 @@ func EpicIDValidator(identifier string, nameAndTitle ...string) *valgo.Validator
 		Passing(func(_ string) bool {
 			_, err := ParseEpicID(identifier)
 			return err == nil
-		}, "Must be a valid epic ID")
+		}, msgcat.Text(msgcat.ErrInvalidID, "epic"))
 }
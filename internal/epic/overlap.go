@@ -0,0 +1,151 @@
+package epic
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DependencySuggestion suggests that a proposed task should depend on another
+// proposed task because both likely touch the same files or components.
+type DependencySuggestion struct {
+	TempID          string   `json:"temp_id"`
+	Title           string   `json:"title"`
+	DependsOnTempID string   `json:"depends_on_temp_id"`
+	DependsOnTitle  string   `json:"depends_on_title"`
+	Shared          []string `json:"shared"`
+}
+
+var (
+	urlPattern = regexp.MustCompile(`https?://\S+`)
+	// pathPattern matches slash-separated paths (internal/epic/store.go,
+	// ui/src/lib/) and bare file names with a known source extension.
+	pathPattern = regexp.MustCompile(`[A-Za-z0-9_.\-]+(?:/[A-Za-z0-9_.\-]+)+/?|[A-Za-z0-9_\-]+\.(?:go|ts|tsx|js|jsx|svelte|py|rb|rs|java|kt|sql|sh|md|ya?ml|json|toml|css|scss|html|proto)\b`)
+	// backtickPattern matches inline code spans such as `ConfirmEpic`.
+	backtickPattern = regexp.MustCompile("`([^`\\s]{3,})`")
+	// camelCasePattern matches identifiers with at least two humps such as
+	// ProposedTask or taskStore.
+	camelCasePattern = regexp.MustCompile(`\b[A-Za-z][a-z0-9]+(?:[A-Z][a-z0-9]+)+\b`)
+)
+
+// commonCamelCaseWords are product names that look like identifiers but don't
+// indicate a shared component.
+var commonCamelCaseWords = map[string]bool{
+	"github": true, "gitlab": true, "javascript": true, "typescript": true,
+	"postgresql": true, "mysql": true, "youtube": true, "iphone": true,
+	"macos": true, "openapi": true, "graphql": true,
+}
+
+// SuggestDependencies analyzes proposed tasks for likely overlap — file paths
+// or code components mentioned by more than one task — and suggests a
+// dependency for each overlapping pair that isn't already sequenced. The later
+// task in proposal order is suggested to depend on the earlier one, nearest
+// first. Suggestions account for each other, so a chain A → B → C does not
+// also suggest C → A.
+func SuggestDependencies(tasks []ProposedTask) []DependencySuggestion {
+	hints := make([]map[string]bool, len(tasks))
+	deps := make(map[string]map[string]bool, len(tasks))
+	for i, pt := range tasks {
+		hints[i] = taskHints(pt)
+		deps[pt.TempID] = make(map[string]bool)
+		for _, d := range pt.DependsOnTempIDs {
+			deps[pt.TempID][d] = true
+		}
+	}
+
+	var suggestions []DependencySuggestion
+	for j := range tasks {
+		for i := j - 1; i >= 0; i-- {
+			shared := sharedHints(hints[i], hints[j])
+			if len(shared) == 0 {
+				continue
+			}
+			a, b := tasks[i].TempID, tasks[j].TempID
+			if dependsOn(deps, b, a) || dependsOn(deps, a, b) {
+				continue
+			}
+			deps[b][a] = true
+			suggestions = append(suggestions, DependencySuggestion{
+				TempID:          b,
+				Title:           tasks[j].Title,
+				DependsOnTempID: a,
+				DependsOnTitle:  tasks[i].Title,
+				Shared:          shared,
+			})
+		}
+	}
+	return suggestions
+}
+
+// ApplyDependencySuggestions returns a copy of tasks with the suggested
+// dependencies added.
+func ApplyDependencySuggestions(tasks []ProposedTask, suggestions []DependencySuggestion) []ProposedTask {
+	out := make([]ProposedTask, len(tasks))
+	copy(out, tasks)
+	for _, s := range suggestions {
+		for i := range out {
+			if out[i].TempID != s.TempID {
+				continue
+			}
+			deps := make([]string, len(out[i].DependsOnTempIDs), len(out[i].DependsOnTempIDs)+1)
+			copy(deps, out[i].DependsOnTempIDs)
+			out[i].DependsOnTempIDs = append(deps, s.DependsOnTempID)
+		}
+	}
+	return out
+}
+
+// taskHints extracts file path and component hints from a proposed task.
+func taskHints(pt ProposedTask) map[string]bool {
+	text := pt.Title + "\n" + pt.Description + "\n" + strings.Join(pt.AcceptanceCriteria, "\n")
+	text = urlPattern.ReplaceAllString(text, "")
+
+	hints := make(map[string]bool)
+	for _, p := range pathPattern.FindAllString(text, -1) {
+		p = strings.TrimRight(p, "./")
+		if p != "" {
+			hints[p] = true
+		}
+	}
+	for _, m := range backtickPattern.FindAllStringSubmatch(text, -1) {
+		hints[strings.Trim(m[1], "()")] = true
+	}
+	for _, w := range camelCasePattern.FindAllString(text, -1) {
+		if !commonCamelCaseWords[strings.ToLower(w)] {
+			hints[w] = true
+		}
+	}
+	return hints
+}
+
+func sharedHints(a, b map[string]bool) []string {
+	var shared []string
+	for h := range a {
+		if b[h] {
+			shared = append(shared, h)
+		}
+	}
+	sort.Strings(shared)
+	return shared
+}
+
+// dependsOn reports whether task from transitively depends on task to.
+func dependsOn(deps map[string]map[string]bool, from, to string) bool {
+	visited := make(map[string]bool)
+	stack := []string{from}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited[cur] {
+			continue
+		}
+		visited[cur] = true
+		for d := range deps[cur] {
+			if d == to {
+				return true
+			}
+			stack = append(stack, d)
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package epic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestDependencies_SharedPath(t *testing.T) {
+	tasks := []ProposedTask{
+		{TempID: "t1", Title: "Add cost column", Description: "Add a migration and update internal/sqlite/epic_repo.go."},
+		{TempID: "t2", Title: "Add docs", Description: "Document the feature in README.md."},
+		{TempID: "t3", Title: "Add budget", Description: "Store the budget in internal/sqlite/epic_repo.go."},
+	}
+
+	suggestions := SuggestDependencies(tasks)
+	require.Len(t, suggestions, 1)
+	assert.Equal(t, DependencySuggestion{
+		TempID:          "t3",
+		Title:           "Add budget",
+		DependsOnTempID: "t1",
+		DependsOnTitle:  "Add cost column",
+		Shared:          []string{"internal/sqlite/epic_repo.go"},
+	}, suggestions[0])
+}
+
+func TestSuggestDependencies_SharedComponent(t *testing.T) {
+	tasks := []ProposedTask{
+		{TempID: "t1", Title: "Validate input", Description: "Update `ConfirmEpic` to validate tasks."},
+		{TempID: "t2", Title: "Log warnings", AcceptanceCriteria: []string{"ConfirmEpic logs a warning"}},
+	}
+
+	suggestions := SuggestDependencies(tasks)
+	require.Len(t, suggestions, 1)
+	assert.Equal(t, "t2", suggestions[0].TempID)
+	assert.Equal(t, []string{"ConfirmEpic"}, suggestions[0].Shared)
+}
+
+func TestSuggestDependencies_IgnoresSequencedTasks(t *testing.T) {
+	tasks := []ProposedTask{
+		{TempID: "t1", Title: "A", Description: "Edit store.go"},
+		{TempID: "t2", Title: "B", Description: "Edit store.go", DependsOnTempIDs: []string{"t1"}},
+		{TempID: "t3", Title: "C", Description: "Edit store.go", DependsOnTempIDs: []string{"t2"}},
+	}
+
+	assert.Empty(t, SuggestDependencies(tasks), "transitively sequenced tasks should not be flagged")
+}
+
+func TestSuggestDependencies_ChainsSuggestions(t *testing.T) {
+	tasks := []ProposedTask{
+		{TempID: "t1", Title: "A", Description: "Edit store.go"},
+		{TempID: "t2", Title: "B", Description: "Edit store.go"},
+		{TempID: "t3", Title: "C", Description: "Edit store.go"},
+	}
+
+	suggestions := SuggestDependencies(tasks)
+	require.Len(t, suggestions, 2)
+	assert.Equal(t, "t2", suggestions[0].TempID)
+	assert.Equal(t, "t1", suggestions[0].DependsOnTempID)
+	assert.Equal(t, "t3", suggestions[1].TempID)
+	assert.Equal(t, "t2", suggestions[1].DependsOnTempID)
+}
+
+func TestSuggestDependencies_IgnoresURLsAndProductNames(t *testing.T) {
+	tasks := []ProposedTask{
+		{TempID: "t1", Title: "A", Description: "See https://github.com/org/repo for GitHub details"},
+		{TempID: "t2", Title: "B", Description: "See https://github.com/org/repo for GitHub details"},
+	}
+
+	assert.Empty(t, SuggestDependencies(tasks))
+}
+
+func TestApplyDependencySuggestions(t *testing.T) {
+	tasks := []ProposedTask{
+		{TempID: "t1", Title: "A"},
+		{TempID: "t2", Title: "B", DependsOnTempIDs: []string{"t0"}},
+	}
+
+	out := ApplyDependencySuggestions(tasks, []DependencySuggestion{{TempID: "t2", DependsOnTempID: "t1"}})
+	assert.Equal(t, []string{"t0", "t1"}, out[1].DependsOnTempIDs)
+	assert.Equal(t, []string{"t0"}, tasks[1].DependsOnTempIDs, "input should not be modified")
+}
@@ -0,0 +1,95 @@
+package epic
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TaskOutcome describes a task already created from an epic. Re-planning
+// shows the planning agent what became of each one.
+type TaskOutcome struct {
+	ID                 string
+	Title              string
+	Description        string
+	Status             string
+	PullRequestURL     string
+	DependsOn          []string
+	AcceptanceCriteria []string
+}
+
+// replaceable reports whether re-planning may drop or keep the task: it has
+// not started, or it failed and is waiting to be retried.
+func (o TaskOutcome) replaceable() bool {
+	return o.Status == "pending" || o.Status == "failed"
+}
+
+// remainingProposedTasks seeds a re-plan with the tasks that may still be
+// replaced. Each keeps its task ID as its temp ID so the confirmed plan can
+// be matched back to the existing task.
+func remainingProposedTasks(outcomes []TaskOutcome) []ProposedTask {
+	tasks := []ProposedTask{}
+	for _, o := range outcomes {
+		if !o.replaceable() {
+			continue
+		}
+		tasks = append(tasks, ProposedTask{
+			TempID:             o.ID,
+			Title:              o.Title,
+			Description:        o.Description,
+			DependsOnTempIDs:   o.DependsOn,
+			AcceptanceCriteria: o.AcceptanceCriteria,
+		})
+	}
+	return tasks
+}
+
+// BuildReplanFeedback describes an epic's progress for the agent re-planning
+// it: the original plan, the tasks that are done or in flight, and how to
+// keep or drop the remaining tasks it is given as the previous breakdown.
+func BuildReplanFeedback(e *Epic, outcomes []TaskOutcome, instructions string) string {
+	var b strings.Builder
+	b.WriteString("This epic is already in progress. Re-plan the work that remains against the current state of the repository.\n")
+
+	if e.PlanningSummary != "" {
+		b.WriteString("\n### Original Plan\n\n")
+		b.WriteString(e.PlanningSummary)
+		b.WriteString("\n")
+	}
+
+	var done, inFlight []string
+	for _, o := range outcomes {
+		line := fmt.Sprintf("- [%s] %s (%s)", o.Status, o.Title, o.ID)
+		if o.PullRequestURL != "" {
+			line += " " + o.PullRequestURL
+		}
+		switch {
+		case o.Status == "merged" || o.Status == "closed":
+			done = append(done, line)
+		case !o.replaceable():
+			inFlight = append(inFlight, line)
+		}
+	}
+	if len(done) > 0 {
+		b.WriteString("\n### Completed Tasks\n\nThese are finished; their changes are already in the repository or were abandoned. Do not propose them again.\n\n")
+		b.WriteString(strings.Join(done, "\n"))
+		b.WriteString("\n")
+	}
+	if len(inFlight) > 0 {
+		b.WriteString("\n### Tasks In Progress\n\nAn agent is working on these or their pull requests are in review. Do not propose them again.\n\n")
+		b.WriteString(strings.Join(inFlight, "\n"))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n### Remaining Tasks\n\n")
+	b.WriteString("The previous task breakdown lists the tasks that have not started or have failed; their temp_ids are task IDs. " +
+		"It also lists any proposed tasks that were never confirmed, with ordinary temp_ids. " +
+		"Return a task unchanged, with its temp_id, to keep it; leave it out to drop it. To change a task, drop it and propose a replacement with a new temp_id. " +
+		"depends_on_temp_ids may name any temp_id in your breakdown or the ID of a completed or in-progress task.\n")
+
+	if instructions = strings.TrimSpace(instructions); instructions != "" {
+		b.WriteString("\n### User Instructions\n\n")
+		b.WriteString(instructions)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
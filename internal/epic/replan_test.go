@@ -0,0 +1,47 @@
+package epic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var replanOutcomes = []TaskOutcome{
+	{ID: "tsk_1", Title: "Add schema", Status: "merged", PullRequestURL: "https://github.com/o/r/pull/1"},
+	{ID: "tsk_2", Title: "Add API", Status: "review", PullRequestURL: "https://github.com/o/r/pull/2"},
+	{ID: "tsk_3", Title: "Add UI", Description: "Build the page", Status: "pending", DependsOn: []string{"tsk_2"}, AcceptanceCriteria: []string{"Page renders"}},
+	{ID: "tsk_4", Title: "Add docs", Status: "failed"},
+}
+
+func TestBuildReplanFeedback(t *testing.T) {
+	e := NewEpic("repo_123", "Profiles", "Add user profiles")
+	e.PlanningSummary = "## Key Decisions\n- Broken into 4 tasks\n"
+
+	feedback := BuildReplanFeedback(e, replanOutcomes, "  Drop the docs task  ")
+
+	assert.Contains(t, feedback, "### Original Plan\n\n## Key Decisions\n- Broken into 4 tasks\n")
+	assert.Contains(t, feedback, "### Completed Tasks\n\nThese are finished; their changes are already in the repository or were abandoned. Do not propose them again.\n\n- [merged] Add schema (tsk_1) https://github.com/o/r/pull/1\n")
+	assert.Contains(t, feedback, "- [review] Add API (tsk_2) https://github.com/o/r/pull/2\n")
+	assert.NotContains(t, feedback, "Add UI", "remaining tasks are given as the previous plan")
+	assert.Contains(t, feedback, "### User Instructions\n\nDrop the docs task\n")
+}
+
+func TestBuildReplanFeedback_NoInstructions(t *testing.T) {
+	e := NewEpic("repo_123", "Profiles", "Add user profiles")
+
+	feedback := BuildReplanFeedback(e, nil, " ")
+
+	assert.NotContains(t, feedback, "### Original Plan")
+	assert.NotContains(t, feedback, "### Completed Tasks")
+	assert.NotContains(t, feedback, "### User Instructions")
+	assert.Contains(t, feedback, "### Remaining Tasks")
+}
+
+func TestRemainingProposedTasks(t *testing.T) {
+	tasks := remainingProposedTasks(replanOutcomes)
+
+	assert.Equal(t, []ProposedTask{
+		{TempID: "tsk_3", Title: "Add UI", Description: "Build the page", DependsOnTempIDs: []string{"tsk_2"}, AcceptanceCriteria: []string{"Page renders"}},
+		{TempID: "tsk_4", Title: "Add docs"},
+	}, tasks)
+}
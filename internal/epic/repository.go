@@ -16,7 +16,13 @@ type Repository interface {
 	UpdateEpicStatus(ctx context.Context, id EpicID, status Status) error
 	UpdateProposedTasks(ctx context.Context, id EpicID, tasks []ProposedTask) error
 	SetTaskIDs(ctx context.Context, id EpicID, taskIDs []string) error
+	SetPlanningSummary(ctx context.Context, id EpicID, summary string) error
+	SetSharedBranch(ctx context.Context, id EpicID, shared bool) error
+	SetPullRequest(ctx context.Context, id EpicID, prURL string, prNumber int) error
+	// AppendSessionLog appends lines to the session log and their parsed
+	// entries (see NewTranscriptEntry) to the transcript.
 	AppendSessionLog(ctx context.Context, id EpicID, lines []string) error
+	AddEpicCost(ctx context.Context, id EpicID, costUSD float64) error
 	DeleteEpic(ctx context.Context, id EpicID) error
 
 	// Worker support
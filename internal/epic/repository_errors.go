@@ -1,11 +1,15 @@
 package epic
 
-import "github.com/joshjon/kit/errtag"
+import (
+	"github.com/joshjon/kit/errtag"
+
+	"github.com/vervesh/verve/internal/msgcat"
+)
 
 // ErrTagEpicNotFound indicates an epic was not found.
 type ErrTagEpicNotFound struct{ errtag.NotFound }
 
-func (ErrTagEpicNotFound) Msg() string { return "Epic not found" }
+func (ErrTagEpicNotFound) Msg() string { return msgcat.Text(msgcat.ErrEpicNotFound) }
 
 func (e ErrTagEpicNotFound) Unwrap() error {
 	return errtag.Tag[errtag.NotFound](e.Cause())
@@ -14,7 +18,7 @@ func (e ErrTagEpicNotFound) Unwrap() error {
 // ErrTagEpicConflict indicates an epic conflict (e.g. duplicate ID).
 type ErrTagEpicConflict struct{ errtag.Conflict }
 
-func (ErrTagEpicConflict) Msg() string { return "Epic conflict" }
+func (ErrTagEpicConflict) Msg() string { return msgcat.Text(msgcat.ErrEpicConflict) }
 
 func (e ErrTagEpicConflict) Unwrap() error {
 	return errtag.Tag[errtag.Conflict](e.Cause())
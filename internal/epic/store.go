@@ -3,15 +3,18 @@ package epic
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/joshjon/kit/log"
+
+	"github.com/vervesh/verve/internal/msgcat"
 )
 
 // TaskCreator creates tasks in the task system when an epic is confirmed.
 type TaskCreator interface {
-	CreateTaskFromEpic(ctx context.Context, repoID, title, description string, dependsOn, acceptanceCriteria []string, epicID string, ready bool, model string) (string, error)
+	CreateTaskFromEpic(ctx context.Context, repoID, title, description string, dependsOn, acceptanceCriteria []string, epicID string, ready bool, model string, maxCostUSD float64) (string, error)
 }
 
 // TaskStatusReader reads task statuses for epic completion checking.
@@ -19,16 +22,52 @@ type TaskStatusReader interface {
 	ReadTaskStatus(ctx context.Context, taskID string) (string, error)
 }
 
+// TaskCloser closes tasks dropped when a re-planned epic is confirmed.
+type TaskCloser interface {
+	CloseTaskFromEpic(ctx context.Context, taskID, reason string) error
+}
+
+// EventListener is notified of epic lifecycle events.
+type EventListener interface {
+	EpicCompleted(ctx context.Context, e *Epic)
+	EpicBudgetExceeded(ctx context.Context, e *Epic)
+}
+
+// PendingNotifier tells the other server instances that planning epics may
+// have become claimable, so their waiting polls look for them again. The
+// listen side calls Store.WakePending on every other instance.
+type PendingNotifier interface {
+	NotifyPending(ctx context.Context) error
+}
+
+// EventListeners fans out epic lifecycle events to multiple listeners.
+type EventListeners []EventListener
+
+func (ls EventListeners) EpicCompleted(ctx context.Context, e *Epic) {
+	for _, l := range ls {
+		l.EpicCompleted(ctx, e)
+	}
+}
+
+func (ls EventListeners) EpicBudgetExceeded(ctx context.Context, e *Epic) {
+	for _, l := range ls {
+		l.EpicBudgetExceeded(ctx, e)
+	}
+}
+
 // Store wraps a Repository and adds application-level concerns for epics.
 type Store struct {
 	repo             Repository
 	taskCreator      TaskCreator
 	taskStatusReader TaskStatusReader
+	taskCloser       TaskCloser
+	eventListener    EventListener
 	logger           log.Logger
 
 	// Pending epic notification (same pattern as task.Store)
-	pendingMu sync.Mutex
-	pendingCh chan struct{}
+	pendingMu       sync.Mutex
+	pendingCh       chan struct{}
+	pendingNotifier PendingNotifier
 
 	// Stop queue: IDs of epics that have been stopped, delivered via poll.
 	stopMu        sync.Mutex
@@ -53,6 +92,24 @@ func (s *Store) SetTaskStatusReader(reader TaskStatusReader) {
 	s.taskStatusReader = reader
 }
 
+// SetTaskCloser sets the closer used to drop tasks when a re-planned epic
+// is confirmed.
+func (s *Store) SetTaskCloser(closer TaskCloser) {
+	s.taskCloser = closer
+}
+
+// SetEventListener sets the EventListener notified of epic lifecycle events.
+// This is set after construction to avoid circular dependencies.
+func (s *Store) SetEventListener(listener EventListener) {
+	s.eventListener = listener
+}
+
+// SetPendingNotifier sets the PendingNotifier that shares pending epic
+// notifications with the other server instances.
+func (s *Store) SetPendingNotifier(notifier PendingNotifier) {
+	s.pendingNotifier = notifier
+}
+
 // WaitForPending returns a channel that signals when a planning epic might be available.
 func (s *Store) WaitForPending() <-chan struct{} {
 	s.pendingMu.Lock()
@@ -61,6 +118,15 @@ func (s *Store) WaitForPending() <-chan struct{} {
 }
 
 func (s *Store) notifyPending() {
+	s.WakePending()
+	if s.pendingNotifier != nil {
+		_ = s.pendingNotifier.NotifyPending(context.Background())
+	}
+}
+
+// WakePending wakes this instance's waiting polls without notifying the
+// other instances. It is called by the listen side of a PendingNotifier.
+func (s *Store) WakePending() {
 	s.pendingMu.Lock()
 	defer s.pendingMu.Unlock()
 	select {
@@ -105,6 +171,9 @@ func (s *Store) ClaimPendingEpic(ctx context.Context) (*Epic, error) {
 		return nil, err
 	}
 	for _, e := range epics {
+		if e.planningBudgetExceeded() {
+			continue // Waiting for the user to raise the budget
+		}
 		ok, err := s.repo.ClaimEpic(ctx, e.ID)
 		if err != nil {
 			continue
@@ -134,6 +203,9 @@ func (s *Store) RequestChanges(ctx context.Context, id EpicID, feedback string)
 	if e.Status != StatusDraft && e.Status != StatusReady {
 		return fmt.Errorf("epic must be in draft or ready status to request changes")
 	}
+	if e.planningBudgetExceeded() {
+		return fmt.Errorf("epic planning budget of $%.2f has been exhausted", e.MaxCostUSD)
+	}
 	if err := s.repo.SetEpicFeedback(ctx, id, feedback, string(FeedbackMessage)); err != nil {
 		return err
 	}
@@ -146,14 +218,14 @@ func (s *Store) RequestChanges(ctx context.Context, id EpicID, feedback string)
 
 // UpdateProposedTasks updates the proposed tasks (used for manual edits by the user).
 func (s *Store) UpdateProposedTasks(ctx context.Context, id EpicID, tasks []ProposedTask) error {
-	return s.repo.UpdateProposedTasks(ctx, id, tasks)
+	return s.repo.UpdateProposedTasks(ctx, id, sanitizeEstimates(tasks))
 }
 
 // CompletePlanning is called by the agent when it finishes proposing tasks.
 // It updates the proposed tasks, transitions to draft status, releases the claim,
 // and clears any pending feedback (it has been consumed by this planning run).
 func (s *Store) CompletePlanning(ctx context.Context, id EpicID, tasks []ProposedTask) error {
-	if err := s.repo.UpdateProposedTasks(ctx, id, tasks); err != nil {
+	if err := s.repo.UpdateProposedTasks(ctx, id, sanitizeEstimates(tasks)); err != nil {
 		return err
 	}
 	if err := s.repo.ClearEpicFeedback(ctx, id); err != nil {
@@ -190,6 +262,57 @@ func (s *Store) AppendSessionLog(ctx context.Context, id EpicID, lines []string)
 	return s.repo.AppendSessionLog(ctx, id, lines)
 }
 
+// AddPlanningCost adds the cost of a planning session to the epic's accumulated
+// cost. If the epic has a budget and the accumulated cost reaches it while a
+// worker is still planning, the session is stopped gracefully: the claim is
+// released, the epic moves to draft so any existing proposals can be reviewed,
+// and a stop signal is queued for the worker. Returns true if the session was
+// stopped.
+func (s *Store) AddPlanningCost(ctx context.Context, id EpicID, costUSD float64) (bool, error) {
+	if err := s.repo.AddEpicCost(ctx, id, costUSD); err != nil {
+		return false, err
+	}
+	e, err := s.repo.ReadEpic(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if !e.planningBudgetExceeded() || e.Status != StatusPlanning || e.ClaimedAt == nil {
+		return false, nil
+	}
+
+	s.logger.Info("epic planning budget exceeded, stopping session",
+		"epic.id", id.String(), "epic.cost_usd", e.CostUSD, "epic.max_cost_usd", e.MaxCostUSD)
+	line := fmt.Sprintf("system: Planning budget exceeded ($%.2f of $%.2f). Session stopped.", e.CostUSD, e.MaxCostUSD)
+	if err := s.repo.AppendSessionLog(ctx, id, []string{line}); err != nil {
+		return false, err
+	}
+	if err := s.repo.ReleaseEpicClaim(ctx, id); err != nil {
+		return false, err
+	}
+	if err := s.repo.UpdateEpicStatus(ctx, id, StatusDraft); err != nil {
+		return false, err
+	}
+	s.queueStop(id)
+	if s.eventListener != nil {
+		e.Status = StatusDraft
+		s.eventListener.EpicBudgetExceeded(ctx, e)
+	}
+	return true, nil
+}
+
+// TotalPlanningCost returns the accumulated planning cost across all epics.
+func (s *Store) TotalPlanningCost(ctx context.Context) (float64, error) {
+	epics, err := s.repo.ListEpics(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var total float64
+	for _, e := range epics {
+		total += e.CostUSD
+	}
+	return total, nil
+}
+
 // StartPlanning transitions an epic back to planning status and notifies pending.
 func (s *Store) StartPlanning(ctx context.Context, id EpicID, prompt string) error {
 	e, err := s.repo.ReadEpic(ctx, id)
@@ -199,6 +322,9 @@ func (s *Store) StartPlanning(ctx context.Context, id EpicID, prompt string) err
 	if e.Status != StatusDraft && e.Status != StatusReady {
 		return fmt.Errorf("epic must be in draft or ready status to start planning")
 	}
+	if e.planningBudgetExceeded() {
+		return fmt.Errorf("epic planning budget of $%.2f has been exhausted", e.MaxCostUSD)
+	}
 	e.PlanningPrompt = prompt
 	e.Status = StatusPlanning
 	e.UpdatedAt = time.Now()
@@ -209,29 +335,161 @@ func (s *Store) StartPlanning(ctx context.Context, id EpicID, prompt string) err
 	return nil
 }
 
-// ConfirmEpic creates real tasks from proposed tasks and activates the epic.
-func (s *Store) ConfirmEpic(ctx context.Context, id EpicID, notReady bool) error {
+// ResumePlanning starts a new planning session for a draft epic whose last
+// session ended, for example because it was stopped or its agent was lost.
+// The next agent is given the epic's transcript so it continues the
+// conversation rather than starting over. A message, if given, is recorded
+// as the user's next turn and, unless feedback from the ended session is
+// still pending, passed to the agent as a change request.
+func (s *Store) ResumePlanning(ctx context.Context, id EpicID, message string) error {
 	e, err := s.repo.ReadEpic(ctx, id)
 	if err != nil {
 		return err
 	}
-	if e.Status != StatusDraft && e.Status != StatusReady {
-		return fmt.Errorf("epic must be in draft or ready status to confirm")
+	if e.Status != StatusDraft {
+		return fmt.Errorf("epic must be in draft status to resume planning")
+	}
+	if e.planningBudgetExceeded() {
+		return fmt.Errorf("epic planning budget of $%.2f has been exhausted", e.MaxCostUSD)
+	}
+	line := "system: Resuming planning session."
+	if message = strings.TrimSpace(message); message != "" {
+		line = "user: " + message
+		if e.Feedback == nil {
+			if err := s.repo.SetEpicFeedback(ctx, id, message, string(FeedbackMessage)); err != nil {
+				return err
+			}
+		}
+	}
+	if err := s.repo.AppendSessionLog(ctx, id, []string{line}); err != nil {
+		return err
+	}
+	if err := s.repo.UpdateEpicStatus(ctx, id, StatusPlanning); err != nil {
+		return err
+	}
+	s.notifyPending()
+	return nil
+}
+
+// Replan sends an active or ready epic back to planning so its remaining
+// work can be adjusted mid-flight. The agent is given the epic's progress
+// (see BuildReplanFeedback) and, as the previous plan, the tasks that have not
+// started or have failed along with any proposed tasks not yet confirmed.
+// Existing tasks carry on while the agent plans; confirming the new plan
+// amends the epic.
+func (s *Store) Replan(ctx context.Context, id EpicID, instructions string, outcomes []TaskOutcome) error {
+	e, err := s.repo.ReadEpic(ctx, id)
+	if err != nil {
+		return err
+	}
+	if e.Status != StatusActive && e.Status != StatusReady {
+		return fmt.Errorf("epic must be in active or ready status to re-plan")
+	}
+	if e.planningBudgetExceeded() {
+		return fmt.Errorf("epic planning budget of $%.2f has been exhausted", e.MaxCostUSD)
+	}
+	// Proposed tasks that were never confirmed are part of the remaining
+	// work too.
+	seed := append(remainingProposedTasks(outcomes), e.ProposedTasks...)
+	if err := s.repo.UpdateProposedTasks(ctx, id, seed); err != nil {
+		return err
+	}
+	if err := s.repo.SetEpicFeedback(ctx, id, BuildReplanFeedback(e, outcomes, instructions), string(FeedbackReplan)); err != nil {
+		return err
+	}
+	line := "system: Re-planning remaining work."
+	if instructions = strings.TrimSpace(instructions); instructions != "" {
+		line = "user: " + instructions
+	}
+	if err := s.repo.AppendSessionLog(ctx, id, []string{line}); err != nil {
+		return err
+	}
+	if err := s.repo.UpdateEpicStatus(ctx, id, StatusPlanning); err != nil {
+		return err
+	}
+	s.notifyPending()
+	return nil
+}
+
+// ConfirmEpic creates real tasks from all of the epic's proposed tasks and
+// activates the epic. See ConfirmEpicTasks.
+func (s *Store) ConfirmEpic(ctx context.Context, id EpicID, notReady, sharedBranch bool) error {
+	return s.ConfirmEpicTasks(ctx, id, nil, notReady, sharedBranch)
+}
+
+// ConfirmEpicTasks creates real tasks from the proposed tasks named by
+// tempIDs, or all of them when tempIDs is empty, and activates the epic. The
+// rest stay proposed and can be confirmed in later batches while the epic
+// runs (see SelectProposedTasks). With sharedBranch set, the tasks branch
+// from and merge into the epic's integration branch instead of the repo's
+// default branch; it is fixed by the first batch. A draft epic that already
+// has tasks has been re-planned and is amended instead (see amendEpic),
+// which applies the whole plan at once.
+func (s *Store) ConfirmEpicTasks(ctx context.Context, id EpicID, tempIDs []string, notReady, sharedBranch bool) error {
+	e, err := s.repo.ReadEpic(ctx, id)
+	if err != nil {
+		return err
+	}
+	if e.Status != StatusDraft && e.Status != StatusReady && e.Status != StatusActive {
+		return fmt.Errorf("epic must be in draft, ready or active status to confirm")
+	}
+	if e.Status == StatusDraft && len(e.TaskIDs) > 0 {
+		if len(tempIDs) > 0 {
+			return invalidSelection("a re-planned epic must be confirmed as a whole")
+		}
+		return s.amendEpic(ctx, e, notReady)
 	}
 	if len(e.ProposedTasks) == 0 {
 		return fmt.Errorf("epic has no proposed tasks to confirm")
 	}
+	selected, rest, err := SelectProposedTasks(e.ProposedTasks, tempIDs)
+	if err != nil {
+		return err
+	}
+	firstBatch := len(e.TaskIDs) == 0
+
+	// Warn about proposed tasks that likely touch the same code without being
+	// sequenced. Confirmation still proceeds; the warnings are recorded in the
+	// session log so the overlap is visible when tasks conflict later.
+	for _, sg := range SuggestDependencies(selected) {
+		s.logger.Warn("proposed tasks likely overlap", "epic.id", id.String(),
+			"task.temp_id", sg.TempID, "task.depends_on_temp_id", sg.DependsOnTempID, "overlap.shared", sg.Shared)
+		line := fmt.Sprintf("system: Warning: %q and %q both touch %s but have no dependency. Consider making %q depend on %q.",
+			sg.DependsOnTitle, sg.Title, strings.Join(sg.Shared, ", "), sg.Title, sg.DependsOnTitle)
+		if err := s.repo.AppendSessionLog(ctx, id, []string{line}); err != nil {
+			return err
+		}
+	}
+
+	if firstBatch {
+		// Capture the planning context before tasks are created so it
+		// outlives the planning session.
+		if err := s.repo.SetPlanningSummary(ctx, id, BuildPlanningSummary(e)); err != nil {
+			return err
+		}
+		if err := s.repo.SetSharedBranch(ctx, id, sharedBranch); err != nil {
+			return err
+		}
+	}
+
+	existing := make(map[string]bool, len(e.TaskIDs))
+	for _, taskID := range e.TaskIDs {
+		existing[taskID] = true
+	}
 
 	// Map temp IDs to real task IDs
 	tempToReal := make(map[string]string)
-	taskIDs := make([]string, 0, len(e.ProposedTasks))
+	taskIDs := append(make([]string, 0, len(e.TaskIDs)+len(selected)), e.TaskIDs...)
 
-	// Create tasks in dependency order
-	for _, pt := range e.ProposedTasks {
+	// Create tasks in dependency order. Dependencies on tasks confirmed in
+	// an earlier batch already name the task.
+	for _, pt := range selected {
 		var realDeps []string
 		for _, depTempID := range pt.DependsOnTempIDs {
 			if realID, ok := tempToReal[depTempID]; ok {
 				realDeps = append(realDeps, realID)
+			} else if existing[depTempID] {
+				realDeps = append(realDeps, depTempID)
 			}
 		}
 
@@ -244,7 +502,8 @@ func (s *Store) ConfirmEpic(ctx context.Context, id EpicID, notReady bool) error
 			pt.AcceptanceCriteria,
 			id.String(),
 			!notReady,
-			e.Model,
+			pt.taskModel(e.Model),
+			pt.maxCostUSD(),
 		)
 		if err != nil {
 			return fmt.Errorf("create task %q: %w", pt.Title, err)
@@ -254,11 +513,23 @@ func (s *Store) ConfirmEpic(ctx context.Context, id EpicID, notReady bool) error
 		taskIDs = append(taskIDs, taskID)
 	}
 
-	// Store task IDs and update status
+	// Store task IDs, keep the unconfirmed proposals and update status
 	if err := s.repo.SetTaskIDs(ctx, id, taskIDs); err != nil {
 		return err
 	}
+	if err := s.repo.UpdateProposedTasks(ctx, id, remapDependencies(rest, tempToReal)); err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		line := fmt.Sprintf("system: Confirmed %d of %d proposed tasks; %d remain in draft.", len(selected), len(e.ProposedTasks), len(rest))
+		if err := s.repo.AppendSessionLog(ctx, id, []string{line}); err != nil {
+			return err
+		}
+	}
 
+	if e.Status == StatusActive {
+		return nil
+	}
 	status := StatusActive
 	if notReady {
 		status = StatusReady
@@ -270,6 +541,152 @@ func (s *Store) ConfirmEpic(ctx context.Context, id EpicID, notReady bool) error
 	return nil
 }
 
+// amendEpic applies a confirmed re-plan to an epic's existing tasks.
+// Proposed tasks whose temp ID is an existing task ID keep that task as it
+// is; the rest are created, with dependencies on existing tasks preserved.
+// Existing tasks left out of the plan are closed if they still haven't
+// started or have failed; tasks that have moved on since are kept.
+func (s *Store) amendEpic(ctx context.Context, e *Epic, notReady bool) error {
+	existing := make(map[string]bool, len(e.TaskIDs))
+	for _, taskID := range e.TaskIDs {
+		existing[taskID] = true
+	}
+	kept := make(map[string]bool)
+	for _, pt := range e.ProposedTasks {
+		if existing[pt.TempID] {
+			kept[pt.TempID] = true
+		}
+	}
+
+	if err := s.repo.SetPlanningSummary(ctx, e.ID, BuildPlanningSummary(e)); err != nil {
+		return err
+	}
+
+	tempToReal := make(map[string]string)
+	taskIDs := append([]string{}, e.TaskIDs...)
+	var created int
+	for _, pt := range e.ProposedTasks {
+		if kept[pt.TempID] {
+			continue
+		}
+		var realDeps []string
+		for _, dep := range pt.DependsOnTempIDs {
+			if realID, ok := tempToReal[dep]; ok {
+				realDeps = append(realDeps, realID)
+			} else if existing[dep] {
+				realDeps = append(realDeps, dep)
+			}
+		}
+		taskID, err := s.taskCreator.CreateTaskFromEpic(ctx, e.RepoID, pt.Title, pt.Description, realDeps, pt.AcceptanceCriteria, e.ID.String(), !notReady, pt.taskModel(e.Model), pt.maxCostUSD())
+		if err != nil {
+			return fmt.Errorf("create task %q: %w", pt.Title, err)
+		}
+		tempToReal[pt.TempID] = taskID
+		taskIDs = append(taskIDs, taskID)
+		created++
+	}
+	if err := s.repo.SetTaskIDs(ctx, e.ID, taskIDs); err != nil {
+		return err
+	}
+	if err := s.repo.UpdateProposedTasks(ctx, e.ID, []ProposedTask{}); err != nil {
+		return err
+	}
+
+	var dropped int
+	if s.taskStatusReader != nil && s.taskCloser != nil {
+		for _, taskID := range e.TaskIDs {
+			if kept[taskID] {
+				continue
+			}
+			status, err := s.taskStatusReader.ReadTaskStatus(ctx, taskID)
+			if err != nil || (status != "pending" && status != "failed") {
+				continue
+			}
+			if err := s.taskCloser.CloseTaskFromEpic(ctx, taskID, msgcat.Text(msgcat.TaskClosedEpicReplanned)); err != nil {
+				return fmt.Errorf("close dropped task %s: %w", taskID, err)
+			}
+			dropped++
+		}
+	}
+
+	line := fmt.Sprintf("system: Re-plan confirmed: %d tasks kept, %d added, %d dropped.", len(kept), created, dropped)
+	if err := s.repo.AppendSessionLog(ctx, e.ID, []string{line}); err != nil {
+		return err
+	}
+
+	status := StatusActive
+	if notReady {
+		status = StatusReady
+	}
+	if err := s.repo.UpdateEpicStatus(ctx, e.ID, status); err != nil {
+		return err
+	}
+	return s.CheckAndCompleteEpic(ctx, e.ID)
+}
+
+// SetPullRequest records the PR that merges the epic's integration branch
+// into the repo's default branch.
+func (s *Store) SetPullRequest(ctx context.Context, id EpicID, prURL string, prNumber int) error {
+	return s.repo.SetPullRequest(ctx, id, prURL, prNumber)
+}
+
+// SuggestDependencies returns dependency suggestions for the epic's proposed
+// tasks that likely overlap (shared file paths or components) but have no
+// dependency between them.
+func (s *Store) SuggestDependencies(ctx context.Context, id EpicID) ([]DependencySuggestion, error) {
+	e, err := s.repo.ReadEpic(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return SuggestDependencies(e.ProposedTasks), nil
+}
+
+// ApplySuggestedDependencies adds the suggested dependencies to the epic's
+// proposed tasks so overlapping tasks run in sequence once confirmed.
+func (s *Store) ApplySuggestedDependencies(ctx context.Context, id EpicID) error {
+	e, err := s.repo.ReadEpic(ctx, id)
+	if err != nil {
+		return err
+	}
+	if e.Status != StatusDraft && e.Status != StatusReady {
+		return fmt.Errorf("epic must be in draft or ready status to apply suggested dependencies")
+	}
+	suggestions := SuggestDependencies(e.ProposedTasks)
+	if len(suggestions) == 0 {
+		return nil
+	}
+	return s.repo.UpdateProposedTasks(ctx, id, ApplyDependencySuggestions(e.ProposedTasks, suggestions))
+}
+
+// ForceStatus sets an epic's status outside the normal lifecycle to repair an
+// epic left in an inconsistent state. The transition must pass
+// ValidateForcedStatus. A claimed planning session is released and its
+// worker told to stop, and the change is noted in the session log. Returns
+// the epic's previous status.
+func (s *Store) ForceStatus(ctx context.Context, id EpicID, status Status, reason string) (Status, error) {
+	e, err := s.repo.ReadEpic(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if err := ValidateForcedStatus(e, status); err != nil {
+		return "", err
+	}
+	if e.ClaimedAt != nil {
+		if err := s.repo.ReleaseEpicClaim(ctx, id); err != nil {
+			return "", err
+		}
+		s.queueStop(id)
+	}
+	if err := s.repo.UpdateEpicStatus(ctx, id, status); err != nil {
+		return "", err
+	}
+	line := fmt.Sprintf("system: Status forced from %s to %s by admin: %s", e.Status, status, reason)
+	if err := s.repo.AppendSessionLog(ctx, id, []string{line}); err != nil {
+		return "", err
+	}
+	return e.Status, nil
+}
+
 // CloseEpic closes an epic.
 func (s *Store) CloseEpic(ctx context.Context, id EpicID) error {
 	return s.repo.UpdateEpicStatus(ctx, id, StatusClosed)
@@ -393,8 +810,9 @@ func (s *Store) CheckAndCompleteEpic(ctx context.Context, id EpicID) error {
 		return nil
 	}
 
-	// An epic with no tasks shouldn't auto-complete
-	if len(e.TaskIDs) == 0 {
+	// An epic with no tasks shouldn't auto-complete, nor one with proposed
+	// tasks still to be confirmed
+	if len(e.TaskIDs) == 0 || len(e.ProposedTasks) > 0 {
 		return nil
 	}
 
@@ -420,7 +838,14 @@ func (s *Store) CheckAndCompleteEpic(ctx context.Context, id EpicID) error {
 
 	// All tasks are in terminal success state — complete the epic
 	s.logger.Info("all tasks completed, marking epic as completed", "epic.id", id.String())
-	return s.repo.UpdateEpicStatus(ctx, id, StatusCompleted)
+	if err := s.repo.UpdateEpicStatus(ctx, id, StatusCompleted); err != nil {
+		return err
+	}
+	if s.eventListener != nil {
+		e.Status = StatusCompleted
+		s.eventListener.EpicCompleted(ctx, e)
+	}
+	return nil
 }
 
 // ListPlanningEpicsForMetrics returns epics that are actively being planned
@@ -448,6 +873,7 @@ func (s *Store) ListPlanningEpicsForMetrics(ctx context.Context) ([]PlanningEpic
 			Title:     e.Title,
 			RepoID:    e.RepoID,
 			Model:     e.Model,
+			CostUSD:   e.CostUSD,
 			ClaimedAt: e.ClaimedAt,
 		})
 	}
@@ -461,6 +887,7 @@ type PlanningEpicForMetrics struct {
 	Title     string
 	RepoID    string
 	Model     string
+	CostUSD   float64
 	ClaimedAt *time.Time
 }
 
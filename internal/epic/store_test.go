@@ -3,6 +3,7 @@ package epic_test
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
@@ -33,15 +34,16 @@ type createTaskCall struct {
 	epicID                     string
 	ready                      bool
 	model                      string
+	maxCostUSD                 float64
 }
 
-func (m *mockTaskCreator) CreateTaskFromEpic(_ context.Context, repoID, title, description string, dependsOn, acceptanceCriteria []string, epicID string, ready bool, model string) (string, error) {
+func (m *mockTaskCreator) CreateTaskFromEpic(_ context.Context, repoID, title, description string, dependsOn, acceptanceCriteria []string, epicID string, ready bool, model string, maxCostUSD float64) (string, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	if m.err != nil {
 		return "", m.err
 	}
-	call := createTaskCall{repoID, title, description, dependsOn, acceptanceCriteria, epicID, ready, model}
+	call := createTaskCall{repoID, title, description, dependsOn, acceptanceCriteria, epicID, ready, model, maxCostUSD}
 	m.calls = append(m.calls, call)
 	return m.idPrefix + "_" + title, nil
 }
@@ -60,6 +62,34 @@ func (m *mockTaskStatusReader) ReadTaskStatus(_ context.Context, taskID string)
 	return status, nil
 }
 
+// --- Mock TaskCloser ---
+
+type mockTaskCloser struct {
+	closed  []string
+	reasons []string
+}
+
+func (m *mockTaskCloser) CloseTaskFromEpic(_ context.Context, taskID, reason string) error {
+	m.closed = append(m.closed, taskID)
+	m.reasons = append(m.reasons, reason)
+	return nil
+}
+
+// --- Recording EventListener ---
+
+type recordingEventListener struct {
+	completed      []*epic.Epic
+	budgetExceeded []*epic.Epic
+}
+
+func (l *recordingEventListener) EpicCompleted(_ context.Context, e *epic.Epic) {
+	l.completed = append(l.completed, e)
+}
+
+func (l *recordingEventListener) EpicBudgetExceeded(_ context.Context, e *epic.Epic) {
+	l.budgetExceeded = append(l.budgetExceeded, e)
+}
+
 // --- Fixture ---
 
 type epicFixture struct {
@@ -317,7 +347,7 @@ func TestStore_ConfirmEpic(t *testing.T) {
 		}
 		require.NoError(t, f.epicRepo.UpdateEpic(ctx, epicObj))
 
-		err = f.store.ConfirmEpic(ctx, e.ID, false)
+		err = f.store.ConfirmEpic(ctx, e.ID, false, false)
 		require.NoError(t, err)
 
 		stored, err := f.epicRepo.ReadEpic(ctx, e.ID)
@@ -337,7 +367,7 @@ func TestStore_ConfirmEpic(t *testing.T) {
 		f := newEpicFixture(t)
 		e := f.seedEpic(t, "Epic", "desc", epic.StatusActive)
 
-		err := f.store.ConfirmEpic(context.Background(), e.ID, false)
+		err := f.store.ConfirmEpic(context.Background(), e.ID, false, false)
 		assert.Error(t, err)
 	})
 
@@ -345,7 +375,7 @@ func TestStore_ConfirmEpic(t *testing.T) {
 		f := newEpicFixture(t)
 		e := f.seedEpic(t, "Epic", "desc", epic.StatusDraft)
 
-		err := f.store.ConfirmEpic(context.Background(), e.ID, false)
+		err := f.store.ConfirmEpic(context.Background(), e.ID, false, false)
 		assert.Error(t, err)
 	})
 
@@ -361,13 +391,287 @@ func TestStore_ConfirmEpic(t *testing.T) {
 			{TempID: "t1", Title: "Task 1", Description: "desc 1"},
 		}))
 
-		err := f.store.ConfirmEpic(ctx, e.ID, true)
+		err := f.store.ConfirmEpic(ctx, e.ID, true, false)
 		require.NoError(t, err)
 
 		stored, err := f.epicRepo.ReadEpic(ctx, e.ID)
 		require.NoError(t, err)
 		assert.Equal(t, epic.StatusReady, stored.Status)
 	})
+
+	t.Run("shared branch", func(t *testing.T) {
+		tc := &mockTaskCreator{idPrefix: "tsk"}
+		f := newEpicFixtureWithTaskCreator(t, tc)
+		ctx := context.Background()
+
+		e := f.seedEpic(t, "Epic", "desc", epic.StatusDraft)
+		require.NoError(t, f.epicRepo.UpdateProposedTasks(ctx, e.ID, []epic.ProposedTask{
+			{TempID: "t1", Title: "Task 1", Description: "desc 1"},
+		}))
+
+		require.NoError(t, f.store.ConfirmEpic(ctx, e.ID, false, true))
+
+		stored, err := f.epicRepo.ReadEpic(ctx, e.ID)
+		require.NoError(t, err)
+		assert.True(t, stored.SharedBranch)
+		assert.Equal(t, "epic/"+e.ID.String(), stored.BranchName())
+	})
+
+	t.Run("stores planning summary", func(t *testing.T) {
+		tc := &mockTaskCreator{idPrefix: "tsk"}
+		f := newEpicFixtureWithTaskCreator(t, tc)
+		ctx := context.Background()
+
+		e := f.seedEpic(t, "Epic", "desc", epic.StatusDraft)
+		require.NoError(t, f.epicRepo.UpdateProposedTasks(ctx, e.ID, []epic.ProposedTask{
+			{TempID: "t1", Title: "Add login endpoint", Description: "desc 1"},
+		}))
+		require.NoError(t, f.epicRepo.AppendSessionLog(ctx, e.ID, []string{
+			"system: Planning started.",
+			"user: Use the existing session store",
+			"Passwords must be hashed with bcrypt.",
+			"SSO support is out of scope for this epic.",
+		}))
+
+		err := f.store.ConfirmEpic(ctx, e.ID, false, false)
+		require.NoError(t, err)
+
+		stored, err := f.epicRepo.ReadEpic(ctx, e.ID)
+		require.NoError(t, err)
+		assert.Contains(t, stored.PlanningSummary, "- Requested change: Use the existing session store")
+		assert.Contains(t, stored.PlanningSummary, "- Broken into 1 tasks: Add login endpoint")
+		assert.Contains(t, stored.PlanningSummary, "## Constraints\n- Passwords must be hashed with bcrypt.")
+		assert.Contains(t, stored.PlanningSummary, "## Out of Scope\n- SSO support is out of scope for this epic.")
+		assert.NotContains(t, stored.PlanningSummary, "Planning started")
+	})
+}
+
+func TestStore_ConfirmEpic_UsesEstimates(t *testing.T) {
+	tc := &mockTaskCreator{idPrefix: "tsk"}
+	f := newEpicFixtureWithTaskCreator(t, tc)
+	ctx := context.Background()
+	e := f.seedEpic(t, "Epic", "desc", epic.StatusPlanning)
+	require.NoError(t, f.store.CompletePlanning(ctx, e.ID, []epic.ProposedTask{
+		{TempID: "t1", Title: "Task 1", Estimate: &epic.TaskEstimate{Model: "haiku", MinCostUSD: 3, MaxCostUSD: 1, Complexity: "trivial"}},
+		{TempID: "t2", Title: "Task 2"},
+	}))
+
+	stored, err := f.epicRepo.ReadEpic(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &epic.TaskEstimate{Model: "haiku", MinCostUSD: 1, MaxCostUSD: 3}, stored.ProposedTasks[0].Estimate)
+
+	require.NoError(t, f.store.ConfirmEpic(ctx, e.ID, false, false))
+	require.Len(t, tc.calls, 2)
+	assert.Equal(t, "haiku", tc.calls[0].model)
+	assert.Equal(t, 3.0, tc.calls[0].maxCostUSD)
+	assert.Empty(t, tc.calls[1].model)
+	assert.Zero(t, tc.calls[1].maxCostUSD)
+}
+
+func TestStore_Replan(t *testing.T) {
+	t.Run("seeds remaining tasks and returns to planning", func(t *testing.T) {
+		f := newEpicFixture(t)
+		ctx := context.Background()
+		e := f.seedEpic(t, "Epic", "desc", epic.StatusActive)
+		require.NoError(t, f.epicRepo.SetTaskIDs(ctx, e.ID, []string{"tsk_1", "tsk_2"}))
+
+		err := f.store.Replan(ctx, e.ID, "Split the UI task", []epic.TaskOutcome{
+			{ID: "tsk_1", Title: "Add API", Status: "merged"},
+			{ID: "tsk_2", Title: "Add UI", Status: "pending"},
+		})
+		require.NoError(t, err)
+
+		stored, err := f.epicRepo.ReadEpic(ctx, e.ID)
+		require.NoError(t, err)
+		assert.Equal(t, epic.StatusPlanning, stored.Status)
+		assert.Equal(t, []epic.ProposedTask{{TempID: "tsk_2", Title: "Add UI"}}, stored.ProposedTasks)
+		require.NotNil(t, stored.FeedbackType)
+		assert.Equal(t, string(epic.FeedbackReplan), *stored.FeedbackType)
+		require.NotNil(t, stored.Feedback)
+		assert.Contains(t, *stored.Feedback, "Split the UI task")
+		assert.Contains(t, stored.SessionLog, "user: Split the UI task")
+		assert.Equal(t, []string{"tsk_1", "tsk_2"}, stored.TaskIDs, "existing tasks stay on the epic")
+	})
+
+	t.Run("wrong status", func(t *testing.T) {
+		f := newEpicFixture(t)
+		e := f.seedEpic(t, "Epic", "desc", epic.StatusDraft)
+
+		err := f.store.Replan(context.Background(), e.ID, "", nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestStore_ConfirmEpic_AmendsReplannedEpic(t *testing.T) {
+	tc := &mockTaskCreator{idPrefix: "tsk"}
+	f := newEpicFixtureWithTaskCreator(t, tc)
+	closer := &mockTaskCloser{}
+	f.store.SetTaskCloser(closer)
+	f.store.SetTaskStatusReader(&mockTaskStatusReader{statuses: map[string]string{
+		"tsk_done":    "merged",
+		"tsk_keep":    "pending",
+		"tsk_drop":    "failed",
+		"tsk_started": "running", // Dropped from the plan but picked up while re-planning
+		"tsk_Task 3":  "pending",
+	}})
+	ctx := context.Background()
+
+	e := f.seedEpic(t, "Epic", "desc", epic.StatusDraft)
+	require.NoError(t, f.epicRepo.SetTaskIDs(ctx, e.ID, []string{"tsk_done", "tsk_keep", "tsk_drop", "tsk_started"}))
+	require.NoError(t, f.epicRepo.UpdateProposedTasks(ctx, e.ID, []epic.ProposedTask{
+		{TempID: "tsk_keep", Title: "Keep"},
+		{TempID: "t3", Title: "Task 3", DependsOnTempIDs: []string{"tsk_done", "tsk_keep"}},
+	}))
+
+	require.NoError(t, f.store.ConfirmEpic(ctx, e.ID, false, false))
+
+	stored, err := f.epicRepo.ReadEpic(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, epic.StatusActive, stored.Status)
+	assert.Equal(t, []string{"tsk_done", "tsk_keep", "tsk_drop", "tsk_started", "tsk_Task 3"}, stored.TaskIDs)
+
+	require.Len(t, tc.calls, 1, "kept tasks are not recreated")
+	assert.Equal(t, []string{"tsk_done", "tsk_keep"}, tc.calls[0].dependsOn)
+
+	assert.Equal(t, []string{"tsk_drop"}, closer.closed)
+	assert.Contains(t, stored.SessionLog, "system: Re-plan confirmed: 1 tasks kept, 1 added, 1 dropped.")
+}
+
+func TestStore_ConfirmEpicTasks(t *testing.T) {
+	t.Run("confirms in batches", func(t *testing.T) {
+		tc := &mockTaskCreator{idPrefix: "tsk"}
+		f := newEpicFixtureWithTaskCreator(t, tc)
+		f.store.SetTaskStatusReader(&mockTaskStatusReader{statuses: map[string]string{"tsk_Task 1": "merged"}})
+		ctx := context.Background()
+
+		e := f.seedEpic(t, "Epic", "desc", epic.StatusDraft)
+		require.NoError(t, f.epicRepo.UpdateProposedTasks(ctx, e.ID, []epic.ProposedTask{
+			{TempID: "t1", Title: "Task 1"},
+			{TempID: "t2", Title: "Task 2", DependsOnTempIDs: []string{"t1"}},
+			{TempID: "t3", Title: "Task 3", DependsOnTempIDs: []string{"t2"}},
+		}))
+
+		require.NoError(t, f.store.ConfirmEpicTasks(ctx, e.ID, []string{"t1"}, false, true))
+
+		stored, err := f.epicRepo.ReadEpic(ctx, e.ID)
+		require.NoError(t, err)
+		assert.Equal(t, epic.StatusActive, stored.Status)
+		assert.Equal(t, []string{"tsk_Task 1"}, stored.TaskIDs)
+		require.Len(t, stored.ProposedTasks, 2)
+		assert.Equal(t, []string{"tsk_Task 1"}, stored.ProposedTasks[0].DependsOnTempIDs, "dependencies on confirmed tasks name the task")
+		assert.Contains(t, stored.SessionLog, "system: Confirmed 1 of 3 proposed tasks; 2 remain in draft.")
+
+		// Task 1 is done, but the epic has more to confirm.
+		require.NoError(t, f.store.CheckAndCompleteEpic(ctx, e.ID))
+		stored, err = f.epicRepo.ReadEpic(ctx, e.ID)
+		require.NoError(t, err)
+		assert.Equal(t, epic.StatusActive, stored.Status)
+
+		require.NoError(t, f.store.ConfirmEpicTasks(ctx, e.ID, nil, false, false))
+
+		stored, err = f.epicRepo.ReadEpic(ctx, e.ID)
+		require.NoError(t, err)
+		assert.Equal(t, epic.StatusActive, stored.Status)
+		assert.Equal(t, []string{"tsk_Task 1", "tsk_Task 2", "tsk_Task 3"}, stored.TaskIDs)
+		assert.Empty(t, stored.ProposedTasks)
+		assert.True(t, stored.SharedBranch, "the first batch picks the branch")
+
+		require.Len(t, tc.calls, 3)
+		assert.Equal(t, []string{"tsk_Task 1"}, tc.calls[1].dependsOn)
+		assert.Equal(t, []string{"tsk_Task 2"}, tc.calls[2].dependsOn)
+	})
+
+	t.Run("rejects selection depending on unconfirmed task", func(t *testing.T) {
+		tc := &mockTaskCreator{idPrefix: "tsk"}
+		f := newEpicFixtureWithTaskCreator(t, tc)
+		ctx := context.Background()
+
+		e := f.seedEpic(t, "Epic", "desc", epic.StatusDraft)
+		require.NoError(t, f.epicRepo.UpdateProposedTasks(ctx, e.ID, []epic.ProposedTask{
+			{TempID: "t1", Title: "Task 1"},
+			{TempID: "t2", Title: "Task 2", DependsOnTempIDs: []string{"t1"}},
+		}))
+
+		err := f.store.ConfirmEpicTasks(ctx, e.ID, []string{"t2"}, false, false)
+		require.Error(t, err)
+		var tagErr epic.ErrTagInvalidSelection
+		assert.True(t, errors.As(err, &tagErr), "expected ErrTagInvalidSelection, got %v", err)
+		assert.Empty(t, tc.calls)
+
+		stored, err := f.epicRepo.ReadEpic(ctx, e.ID)
+		require.NoError(t, err)
+		assert.Equal(t, epic.StatusDraft, stored.Status)
+	})
+
+	t.Run("rejects partial amend", func(t *testing.T) {
+		f := newEpicFixture(t)
+		ctx := context.Background()
+
+		e := f.seedEpic(t, "Epic", "desc", epic.StatusDraft)
+		require.NoError(t, f.epicRepo.SetTaskIDs(ctx, e.ID, []string{"tsk_1"}))
+		require.NoError(t, f.epicRepo.UpdateProposedTasks(ctx, e.ID, []epic.ProposedTask{
+			{TempID: "tsk_1", Title: "Keep"},
+			{TempID: "t2", Title: "New"},
+		}))
+
+		err := f.store.ConfirmEpicTasks(ctx, e.ID, []string{"t2"}, false, false)
+		var tagErr epic.ErrTagInvalidSelection
+		assert.True(t, errors.As(err, &tagErr), "expected ErrTagInvalidSelection, got %v", err)
+	})
+}
+
+func TestStore_ConfirmEpic_WarnsAboutOverlap(t *testing.T) {
+	tc := &mockTaskCreator{idPrefix: "tsk"}
+	f := newEpicFixtureWithTaskCreator(t, tc)
+	ctx := context.Background()
+
+	e := f.seedEpic(t, "Epic", "desc", epic.StatusDraft)
+	require.NoError(t, f.epicRepo.UpdateProposedTasks(ctx, e.ID, []epic.ProposedTask{
+		{TempID: "t1", Title: "Task 1", Description: "Update store.go"},
+		{TempID: "t2", Title: "Task 2", Description: "Refactor store.go"},
+	}))
+
+	err := f.store.ConfirmEpic(ctx, e.ID, false, false)
+	require.NoError(t, err)
+
+	stored, err := f.epicRepo.ReadEpic(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, epic.StatusActive, stored.Status, "overlap warnings should not block confirmation")
+	assert.Contains(t, stored.SessionLog,
+		`system: Warning: "Task 1" and "Task 2" both touch store.go but have no dependency. Consider making "Task 2" depend on "Task 1".`)
+}
+
+func TestStore_ApplySuggestedDependencies(t *testing.T) {
+	f := newEpicFixture(t)
+	ctx := context.Background()
+
+	e := f.seedEpic(t, "Epic", "desc", epic.StatusDraft)
+	require.NoError(t, f.epicRepo.UpdateProposedTasks(ctx, e.ID, []epic.ProposedTask{
+		{TempID: "t1", Title: "Task 1", Description: "Update store.go"},
+		{TempID: "t2", Title: "Task 2", Description: "Refactor store.go"},
+	}))
+
+	suggestions, err := f.store.SuggestDependencies(ctx, e.ID)
+	require.NoError(t, err)
+	require.Len(t, suggestions, 1)
+
+	require.NoError(t, f.store.ApplySuggestedDependencies(ctx, e.ID))
+
+	stored, err := f.epicRepo.ReadEpic(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t1"}, stored.ProposedTasks[1].DependsOnTempIDs)
+
+	suggestions, err = f.store.SuggestDependencies(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Empty(t, suggestions)
+}
+
+func TestStore_ApplySuggestedDependencies_WrongStatus(t *testing.T) {
+	f := newEpicFixture(t)
+	e := f.seedEpic(t, "Epic", "desc", epic.StatusActive)
+
+	err := f.store.ApplySuggestedDependencies(context.Background(), e.ID)
+	assert.Error(t, err)
 }
 
 func TestStore_CloseEpic(t *testing.T) {
@@ -415,6 +719,27 @@ func TestStore_CheckAndCompleteEpic(t *testing.T) {
 		assert.Equal(t, epic.StatusCompleted, stored.Status)
 	})
 
+	t.Run("completion notifies event listener", func(t *testing.T) {
+		f := newEpicFixture(t)
+		ctx := context.Background()
+
+		listener := &recordingEventListener{}
+		f.store.SetEventListener(listener)
+		f.store.SetTaskStatusReader(&mockTaskStatusReader{statuses: map[string]string{"task-1": "merged"}})
+
+		e := f.seedEpic(t, "Epic", "desc", epic.StatusActive)
+		require.NoError(t, f.epicRepo.SetTaskIDs(ctx, e.ID, []string{"task-1"}))
+
+		require.NoError(t, f.store.CheckAndCompleteEpic(ctx, e.ID))
+		require.Len(t, listener.completed, 1)
+		assert.Equal(t, e.ID, listener.completed[0].ID)
+		assert.Equal(t, epic.StatusCompleted, listener.completed[0].Status)
+
+		// Already completed epics are not re-notified.
+		require.NoError(t, f.store.CheckAndCompleteEpic(ctx, e.ID))
+		assert.Len(t, listener.completed, 1)
+	})
+
 	t.Run("failed task blocks completion", func(t *testing.T) {
 		f := newEpicFixture(t)
 		ctx := context.Background()
@@ -539,6 +864,54 @@ func TestStore_StopEpic_Success(t *testing.T) {
 	assert.Contains(t, stored.SessionLog, "system: Stopped by user.")
 }
 
+func TestStore_ResumePlanning(t *testing.T) {
+	t.Run("records the message and returns to planning", func(t *testing.T) {
+		f := newEpicFixture(t)
+		ctx := context.Background()
+		e := f.seedEpic(t, "Epic", "desc", epic.StatusPlanning)
+		require.NoError(t, f.store.AppendSessionLog(ctx, e.ID, []string{"system: Planning started.", "Exploring the repo"}))
+		require.NoError(t, f.epicRepo.UpdateEpicStatus(ctx, e.ID, epic.StatusDraft))
+
+		require.NoError(t, f.store.ResumePlanning(ctx, e.ID, "Keep going"))
+
+		stored, err := f.epicRepo.ReadEpic(ctx, e.ID)
+		require.NoError(t, err)
+		assert.Equal(t, epic.StatusPlanning, stored.Status)
+		require.NotNil(t, stored.Feedback)
+		assert.Equal(t, "Keep going", *stored.Feedback)
+
+		require.Len(t, stored.Transcript, 3)
+		assert.Equal(t, epic.RoleSystem, stored.Transcript[0].Role)
+		assert.Equal(t, "Planning started.", stored.Transcript[0].Content)
+		assert.Equal(t, epic.RoleAgent, stored.Transcript[1].Role)
+		assert.Equal(t, epic.TranscriptEntry{Role: epic.RoleUser, Content: "Keep going", Timestamp: stored.Transcript[2].Timestamp}, stored.Transcript[2])
+		assert.NotZero(t, stored.Transcript[2].Timestamp)
+	})
+
+	t.Run("keeps pending feedback", func(t *testing.T) {
+		f := newEpicFixture(t)
+		ctx := context.Background()
+		e := f.seedEpic(t, "Epic", "desc", epic.StatusDraft)
+		require.NoError(t, f.epicRepo.SetEpicFeedback(ctx, e.ID, "Split the UI task", string(epic.FeedbackMessage)))
+
+		require.NoError(t, f.store.ResumePlanning(ctx, e.ID, "Also add docs"))
+
+		stored, err := f.epicRepo.ReadEpic(ctx, e.ID)
+		require.NoError(t, err)
+		require.NotNil(t, stored.Feedback)
+		assert.Equal(t, "Split the UI task", *stored.Feedback)
+		assert.Contains(t, stored.SessionLog, "user: Also add docs")
+	})
+
+	t.Run("wrong status", func(t *testing.T) {
+		f := newEpicFixture(t)
+		e := f.seedEpic(t, "Epic", "desc", epic.StatusPlanning)
+
+		err := f.store.ResumePlanning(context.Background(), e.ID, "")
+		assert.Error(t, err)
+	})
+}
+
 func TestStore_StopEpic_NoProposals(t *testing.T) {
 	f := newEpicFixture(t)
 	ctx := context.Background()
@@ -625,3 +998,124 @@ func TestStore_ClaimPendingEpic(t *testing.T) {
 		assert.Nil(t, claimed)
 	})
 }
+
+func TestStore_AddPlanningCost_Accumulates(t *testing.T) {
+	f := newEpicFixture(t)
+	ctx := context.Background()
+
+	e := f.seedEpic(t, "Epic", "desc", epic.StatusPlanning)
+
+	stopped, err := f.store.AddPlanningCost(ctx, e.ID, 0.5)
+	require.NoError(t, err)
+	assert.False(t, stopped)
+	stopped, err = f.store.AddPlanningCost(ctx, e.ID, 0.25)
+	require.NoError(t, err)
+	assert.False(t, stopped, "epics without a budget are never stopped")
+
+	stored, err := f.epicRepo.ReadEpic(ctx, e.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.75, stored.CostUSD, 0.0001)
+	assert.Equal(t, epic.StatusPlanning, stored.Status)
+}
+
+func TestStore_AddPlanningCost_BudgetExceededStopsSession(t *testing.T) {
+	f := newEpicFixture(t)
+	ctx := context.Background()
+
+	e := epic.NewEpic(f.repoID, "Epic", "desc")
+	e.MaxCostUSD = 1.0
+	require.NoError(t, f.store.CreateEpic(ctx, e))
+
+	claimed, err := f.epicRepo.ClaimEpic(ctx, e.ID)
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	stopped, err := f.store.AddPlanningCost(ctx, e.ID, 0.6)
+	require.NoError(t, err)
+	assert.False(t, stopped)
+
+	stopped, err = f.store.AddPlanningCost(ctx, e.ID, 0.6)
+	require.NoError(t, err)
+	assert.True(t, stopped)
+
+	stored, err := f.epicRepo.ReadEpic(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, epic.StatusDraft, stored.Status)
+	assert.Nil(t, stored.ClaimedAt, "claim should be released")
+	assert.Equal(t, 1.0, stored.MaxCostUSD)
+	assert.Contains(t, stored.SessionLog, "system: Planning budget exceeded ($1.20 of $1.00). Session stopped.")
+	assert.Equal(t, []epic.EpicID{e.ID}, f.store.DrainStops())
+}
+
+func TestStore_AddPlanningCost_BudgetExceededNotifiesListener(t *testing.T) {
+	f := newEpicFixture(t)
+	ctx := context.Background()
+
+	listener := &recordingEventListener{}
+	f.store.SetEventListener(listener)
+
+	e := epic.NewEpic(f.repoID, "Epic", "desc")
+	e.MaxCostUSD = 1.0
+	require.NoError(t, f.store.CreateEpic(ctx, e))
+	claimed, err := f.epicRepo.ClaimEpic(ctx, e.ID)
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	_, err = f.store.AddPlanningCost(ctx, e.ID, 0.5)
+	require.NoError(t, err)
+	assert.Empty(t, listener.budgetExceeded)
+
+	_, err = f.store.AddPlanningCost(ctx, e.ID, 0.5)
+	require.NoError(t, err)
+	require.Len(t, listener.budgetExceeded, 1)
+	assert.Equal(t, e.ID, listener.budgetExceeded[0].ID)
+	assert.InDelta(t, 1.0, listener.budgetExceeded[0].CostUSD, 0.0001)
+}
+
+func TestStore_StartPlanning_BudgetExhausted(t *testing.T) {
+	f := newEpicFixture(t)
+	ctx := context.Background()
+
+	e := epic.NewEpic(f.repoID, "Epic", "desc")
+	e.MaxCostUSD = 1.0
+	require.NoError(t, f.epicRepo.CreateEpic(ctx, e))
+	require.NoError(t, f.epicRepo.AddEpicCost(ctx, e.ID, 1.5))
+	require.NoError(t, f.epicRepo.UpdateEpicStatus(ctx, e.ID, epic.StatusDraft))
+
+	err := f.store.StartPlanning(ctx, e.ID, "try again")
+	assert.Error(t, err)
+	err = f.store.RequestChanges(ctx, e.ID, "more tasks")
+	assert.Error(t, err)
+
+	stored, err := f.epicRepo.ReadEpic(ctx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, epic.StatusDraft, stored.Status)
+}
+
+func TestStore_ClaimPendingEpic_SkipsBudgetExhausted(t *testing.T) {
+	f := newEpicFixture(t)
+	ctx := context.Background()
+
+	e := epic.NewEpic(f.repoID, "Epic", "desc")
+	e.MaxCostUSD = 1.0
+	require.NoError(t, f.epicRepo.CreateEpic(ctx, e))
+	require.NoError(t, f.epicRepo.AddEpicCost(ctx, e.ID, 1.0))
+
+	claimed, err := f.store.ClaimPendingEpic(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, claimed)
+}
+
+func TestStore_TotalPlanningCost(t *testing.T) {
+	f := newEpicFixture(t)
+	ctx := context.Background()
+
+	e1 := f.seedEpic(t, "Epic 1", "desc", epic.StatusPlanning)
+	e2 := f.seedEpic(t, "Epic 2", "desc", epic.StatusDraft)
+	require.NoError(t, f.epicRepo.AddEpicCost(ctx, e1.ID, 0.5))
+	require.NoError(t, f.epicRepo.AddEpicCost(ctx, e2.ID, 1.25))
+
+	total, err := f.store.TotalPlanningCost(ctx)
+	require.NoError(t, err)
+	assert.InDelta(t, 1.75, total, 0.0001)
+}
@@ -0,0 +1,190 @@
+package epic
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	summaryDecisionsHeading   = "## Key Decisions"
+	summaryConstraintsHeading = "## Constraints"
+	summaryOutOfScopeHeading  = "## Out of Scope"
+
+	// maxSummaryItems caps the number of entries in each summary section.
+	maxSummaryItems = 10
+	// maxSummaryItemLen caps the length of a single summary entry.
+	maxSummaryItemLen = 300
+)
+
+// outOfScopeMarkers identify session log lines that exclude work from the epic.
+var outOfScopeMarkers = []string{
+	"out of scope", "out-of-scope", "not in scope", "non-goal",
+	"future work", "follow-up", "follow up", "defer",
+}
+
+// constraintMarkers identify session log lines that constrain how work is done.
+var constraintMarkers = []string{
+	"must", "should not", "shouldn't", "do not", "don't", "cannot", "can't",
+	"never", "require", "constraint", "avoid", "backwards compatible",
+	"backward compatible",
+}
+
+// BuildPlanningSummary generates a concise summary of an epic's planning
+// session: the key decisions (planning guidance, user change requests and the
+// final task breakdown), constraints and out-of-scope items mentioned in the
+// session log. The summary is stored on the epic when it is confirmed so the
+// context survives long after the planning session ends.
+func BuildPlanningSummary(e *Epic) string {
+	var decisions []string
+	if prompt := summaryItem(e.PlanningPrompt); prompt != "" {
+		decisions = append(decisions, "Planning guidance: "+prompt)
+	}
+
+	var constraints, outOfScope []string
+	seen := make(map[string]bool)
+	for _, line := range e.SessionLog {
+		if strings.HasPrefix(line, "system:") {
+			continue
+		}
+		if msg, ok := strings.CutPrefix(line, "user:"); ok {
+			if item := summaryItem(msg); item != "" {
+				decisions = append(decisions, "Requested change: "+item)
+			}
+			continue
+		}
+
+		item := summaryItem(line)
+		key := strings.ToLower(item)
+		if item == "" || seen[key] {
+			continue
+		}
+		switch {
+		case containsAny(key, outOfScopeMarkers):
+			outOfScope = append(outOfScope, item)
+		case containsAny(key, constraintMarkers):
+			constraints = append(constraints, item)
+		default:
+			continue
+		}
+		seen[key] = true
+	}
+
+	if len(e.ProposedTasks) > 0 {
+		titles := make([]string, len(e.ProposedTasks))
+		for i, pt := range e.ProposedTasks {
+			titles[i] = pt.Title
+		}
+		decisions = append(decisions, fmt.Sprintf("Broken into %d tasks: %s", len(titles), strings.Join(titles, "; ")))
+	}
+
+	var b strings.Builder
+	writeSummarySection(&b, summaryDecisionsHeading, decisions)
+	writeSummarySection(&b, summaryConstraintsHeading, constraints)
+	writeSummarySection(&b, summaryOutOfScopeHeading, outOfScope)
+	return b.String()
+}
+
+// SummaryExcerpt returns the parts of a planning summary relevant to a single
+// task. Key decisions are always kept; constraints and out-of-scope items are
+// kept only when they share a significant word with the task title or
+// description.
+func SummaryExcerpt(summary, title, description string) string {
+	if summary == "" {
+		return ""
+	}
+	taskWords := significantWords(title + " " + description)
+
+	var b strings.Builder
+	for _, section := range parseSummarySections(summary) {
+		items := section.items
+		if section.heading != summaryDecisionsHeading {
+			items = nil
+			for _, item := range section.items {
+				if sharesWord(significantWords(item), taskWords) {
+					items = append(items, item)
+				}
+			}
+		}
+		writeSummarySection(&b, section.heading, items)
+	}
+	return b.String()
+}
+
+type summarySection struct {
+	heading string
+	items   []string
+}
+
+func parseSummarySections(summary string) []summarySection {
+	var sections []summarySection
+	for _, line := range strings.Split(summary, "\n") {
+		switch {
+		case strings.HasPrefix(line, "## "):
+			sections = append(sections, summarySection{heading: line})
+		case strings.HasPrefix(line, "- ") && len(sections) > 0:
+			last := &sections[len(sections)-1]
+			last.items = append(last.items, strings.TrimPrefix(line, "- "))
+		}
+	}
+	return sections
+}
+
+func writeSummarySection(b *strings.Builder, heading string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	if len(items) > maxSummaryItems {
+		items = items[:maxSummaryItems]
+	}
+	if b.Len() > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString(heading + "\n")
+	for _, item := range items {
+		b.WriteString("- " + item + "\n")
+	}
+}
+
+// summaryItem normalizes a session log line into a single summary entry by
+// stripping markdown list/heading markers and truncating long lines.
+func summaryItem(line string) string {
+	line = strings.Join(strings.Fields(line), " ")
+	line = strings.TrimLeft(line, "-*#> ")
+	line = strings.TrimSpace(strings.Trim(line, "*"))
+	if len(line) > maxSummaryItemLen {
+		line = line[:maxSummaryItemLen] + "..."
+	}
+	return line
+}
+
+func containsAny(s string, markers []string) bool {
+	for _, m := range markers {
+		if strings.Contains(s, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// significantWords returns the lowercased words of s that are at least four
+// characters long, ignoring punctuation.
+func significantWords(s string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_'
+	}) {
+		if len(w) >= 4 {
+			words[w] = true
+		}
+	}
+	return words
+}
+
+func sharesWord(a, b map[string]bool) bool {
+	for w := range a {
+		if b[w] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package epic
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildPlanningSummary(t *testing.T) {
+	e := NewEpic("repo_123", "Auth", "Add authentication")
+	e.PlanningPrompt = "Keep it simple"
+	e.SessionLog = []string{
+		"system: Planning started. Analyzing epic and generating task breakdown...",
+		"user: Split the token refresh into its own task",
+		"I'll start by reading the existing handlers.",
+		"- **Tokens must expire after 15 minutes**",
+		"- Tokens must expire after 15 minutes",
+		"OAuth providers are out of scope and deferred to a follow-up epic.",
+		"system: Planning complete. Proposed 2 tasks.",
+	}
+	e.ProposedTasks = []ProposedTask{
+		{TempID: "t1", Title: "Add login endpoint"},
+		{TempID: "t2", Title: "Add token refresh"},
+	}
+
+	summary := BuildPlanningSummary(e)
+
+	expected := `## Key Decisions
+- Planning guidance: Keep it simple
+- Requested change: Split the token refresh into its own task
+- Broken into 2 tasks: Add login endpoint; Add token refresh
+
+## Constraints
+- Tokens must expire after 15 minutes
+
+## Out of Scope
+- OAuth providers are out of scope and deferred to a follow-up epic.
+`
+	assert.Equal(t, expected, summary)
+}
+
+func TestBuildPlanningSummary_Empty(t *testing.T) {
+	e := NewEpic("repo_123", "Auth", "Add authentication")
+	assert.Empty(t, BuildPlanningSummary(e))
+}
+
+func TestBuildPlanningSummary_CapsSectionsAndLength(t *testing.T) {
+	e := NewEpic("repo_123", "Auth", "Add authentication")
+	for i := 0; i < maxSummaryItems+5; i++ {
+		e.SessionLog = append(e.SessionLog, "Handlers must validate input "+strings.Repeat("x", i))
+	}
+	e.SessionLog = append(e.SessionLog, "You must "+strings.Repeat("y", maxSummaryItemLen))
+
+	summary := BuildPlanningSummary(e)
+	assert.Equal(t, maxSummaryItems, strings.Count(summary, "\n- "))
+	for _, line := range strings.Split(summary, "\n") {
+		assert.LessOrEqual(t, len(line), maxSummaryItemLen+len("- ..."))
+	}
+}
+
+func TestSummaryExcerpt(t *testing.T) {
+	summary := `## Key Decisions
+- Broken into 2 tasks: Add login endpoint; Add token refresh
+
+## Constraints
+- Tokens must expire after 15 minutes
+- The login form must support dark mode
+
+## Out of Scope
+- OAuth providers are out of scope
+`
+
+	excerpt := SummaryExcerpt(summary, "Add token refresh", "Refresh expired tokens in the background")
+
+	expected := `## Key Decisions
+- Broken into 2 tasks: Add login endpoint; Add token refresh
+
+## Constraints
+- Tokens must expire after 15 minutes
+`
+	assert.Equal(t, expected, excerpt)
+	assert.Empty(t, SummaryExcerpt("", "Add token refresh", ""))
+}
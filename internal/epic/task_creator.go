@@ -24,7 +24,7 @@ func NewTaskIDString() string {
 }
 
 // TaskCreateFunc is a function that creates a task and returns its ID.
-type TaskCreateFunc func(ctx context.Context, repoID, title, description string, dependsOn, acceptanceCriteria []string, epicID string, ready bool, model string) (string, error)
+type TaskCreateFunc func(ctx context.Context, repoID, title, description string, dependsOn, acceptanceCriteria []string, epicID string, ready bool, model string, maxCostUSD float64) (string, error)
 
 // TaskCreatorFunc adapts a function to the TaskCreator interface.
 type TaskCreatorFunc struct {
@@ -36,8 +36,8 @@ func NewTaskCreatorFunc(fn TaskCreateFunc) *TaskCreatorFunc {
 	return &TaskCreatorFunc{fn: fn}
 }
 
-func (f *TaskCreatorFunc) CreateTaskFromEpic(ctx context.Context, repoID, title, description string, dependsOn, acceptanceCriteria []string, epicID string, ready bool, model string) (string, error) {
-	return f.fn(ctx, repoID, title, description, dependsOn, acceptanceCriteria, epicID, ready, model)
+func (f *TaskCreatorFunc) CreateTaskFromEpic(ctx context.Context, repoID, title, description string, dependsOn, acceptanceCriteria []string, epicID string, ready bool, model string, maxCostUSD float64) (string, error) {
+	return f.fn(ctx, repoID, title, description, dependsOn, acceptanceCriteria, epicID, ready, model, maxCostUSD)
 }
 
 // TaskStatusReadFunc is a function that reads a task status by ID.
@@ -57,6 +57,23 @@ func (f *TaskStatusReaderFunc) ReadTaskStatus(ctx context.Context, taskID string
 	return f.fn(ctx, taskID)
 }
 
+// TaskCloseFunc is a function that closes a task by ID.
+type TaskCloseFunc func(ctx context.Context, taskID, reason string) error
+
+// TaskCloserFunc adapts a function to the TaskCloser interface.
+type TaskCloserFunc struct {
+	fn TaskCloseFunc
+}
+
+// NewTaskCloserFunc creates a TaskCloser from a function.
+func NewTaskCloserFunc(fn TaskCloseFunc) *TaskCloserFunc {
+	return &TaskCloserFunc{fn: fn}
+}
+
+func (f *TaskCloserFunc) CloseTaskFromEpic(ctx context.Context, taskID, reason string) error {
+	return f.fn(ctx, taskID, reason)
+}
+
 // Now is a helper for generating timestamps.
 func Now() time.Time {
 	return time.Now()
@@ -0,0 +1,111 @@
+package epic
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TranscriptRole identifies who wrote a transcript entry.
+type TranscriptRole string
+
+const (
+	RoleUser   TranscriptRole = "user"   // Messages and instructions from the user
+	RoleAgent  TranscriptRole = "agent"  // Output of the planning agent
+	RoleSystem TranscriptRole = "system" // Lifecycle notes from Verve
+)
+
+// TranscriptEntry is one entry of an epic's planning transcript: the
+// session log with who wrote each line and when.
+type TranscriptEntry struct {
+	Role      TranscriptRole `json:"role"`
+	Content   string         `json:"content"`
+	Timestamp int64          `json:"timestamp"` // Unix epoch
+}
+
+// maxAgentTranscriptBytes caps the transcript handed to a planning agent. It
+// travels in an environment variable, so only the most recent entries are
+// kept.
+const maxAgentTranscriptBytes = 32 << 10
+
+// NewTranscriptEntry parses a session log line. Lines written by Verve are
+// prefixed with "user: " or "system: "; everything else is agent output.
+func NewTranscriptEntry(line string, at time.Time) TranscriptEntry {
+	entry := TranscriptEntry{Role: RoleAgent, Content: line, Timestamp: at.Unix()}
+	for _, role := range []TranscriptRole{RoleUser, RoleSystem} {
+		if content, ok := strings.CutPrefix(line, string(role)+": "); ok {
+			entry.Role = role
+			entry.Content = content
+			break
+		}
+	}
+	return entry
+}
+
+// NewTranscriptEntries parses session log lines written at the same time.
+func NewTranscriptEntries(lines []string, at time.Time) []TranscriptEntry {
+	entries := make([]TranscriptEntry, len(lines))
+	for i, line := range lines {
+		entries[i] = NewTranscriptEntry(line, at)
+	}
+	return entries
+}
+
+// RenderTranscriptMarkdown renders the epic's planning transcript as a
+// markdown document. Consecutive entries with the same role are grouped
+// under one heading.
+func RenderTranscriptMarkdown(e *Epic) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Planning transcript: %s\n\n", e.Title)
+	fmt.Fprintf(&b, "- Epic: %s\n- Status: %s\n", e.ID, e.Status)
+	if e.PlanningPrompt != "" {
+		fmt.Fprintf(&b, "- Planning prompt: %s\n", e.PlanningPrompt)
+	}
+
+	var prev TranscriptRole
+	for _, entry := range e.Transcript {
+		if entry.Role != prev {
+			fmt.Fprintf(&b, "\n## %s · %s\n\n", roleTitle(entry.Role), time.Unix(entry.Timestamp, 0).UTC().Format(time.RFC3339))
+			prev = entry.Role
+		}
+		if entry.Role == RoleAgent {
+			b.WriteString("    " + entry.Content + "\n")
+			continue
+		}
+		b.WriteString(entry.Content + "\n")
+	}
+	return b.String()
+}
+
+func roleTitle(role TranscriptRole) string {
+	switch role {
+	case RoleUser:
+		return "User"
+	case RoleSystem:
+		return "System"
+	default:
+		return "Agent"
+	}
+}
+
+// AgentTranscript renders the epic's planning transcript for the agent
+// planning it next, so a session that was stopped or lost its agent can
+// pick up where it left off. The oldest entries are dropped when the
+// transcript is too long.
+func AgentTranscript(entries []TranscriptEntry) string {
+	var lines []string
+	size := 0
+	for i := len(entries) - 1; i >= 0; i-- {
+		line := fmt.Sprintf("[%s] %s", entries[i].Role, entries[i].Content)
+		if size+len(line)+1 > maxAgentTranscriptBytes {
+			lines = append(lines, fmt.Sprintf("[system] %d earlier entries omitted.", i+1))
+			break
+		}
+		size += len(line) + 1
+		lines = append(lines, line)
+	}
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,60 @@
+package epic
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTranscriptEntry(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	tests := map[string]struct {
+		line string
+		want TranscriptEntry
+	}{
+		"user":                  {line: "user: Split the UI task", want: TranscriptEntry{Role: RoleUser, Content: "Split the UI task", Timestamp: at.Unix()}},
+		"system":                {line: "system: Stopped by user.", want: TranscriptEntry{Role: RoleSystem, Content: "Stopped by user.", Timestamp: at.Unix()}},
+		"agent":                 {line: "Reading internal/epic/store.go", want: TranscriptEntry{Role: RoleAgent, Content: "Reading internal/epic/store.go", Timestamp: at.Unix()}},
+		"no space after prefix": {line: "user:name", want: TranscriptEntry{Role: RoleAgent, Content: "user:name", Timestamp: at.Unix()}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewTranscriptEntry(tt.line, at))
+		})
+	}
+}
+
+func TestRenderTranscriptMarkdown(t *testing.T) {
+	e := &Epic{ID: NewEpicID(), Title: "Billing", Status: StatusDraft, Transcript: []TranscriptEntry{
+		{Role: RoleSystem, Content: "Planning started.", Timestamp: 1700000000},
+		{Role: RoleAgent, Content: "Exploring the repo", Timestamp: 1700000001},
+		{Role: RoleAgent, Content: "Found internal/billing", Timestamp: 1700000002},
+		{Role: RoleUser, Content: "Keep it small", Timestamp: 1700000003},
+	}}
+
+	md := RenderTranscriptMarkdown(e)
+	assert.True(t, strings.HasPrefix(md, "# Planning transcript: Billing\n"))
+	assert.Contains(t, md, "## System · 2023-11-14T22:13:20Z\n\nPlanning started.\n")
+	assert.Contains(t, md, "## Agent · 2023-11-14T22:13:21Z\n\n    Exploring the repo\n    Found internal/billing\n")
+	assert.Contains(t, md, "## User · 2023-11-14T22:13:23Z\n\nKeep it small\n")
+}
+
+func TestAgentTranscript(t *testing.T) {
+	entries := []TranscriptEntry{
+		{Role: RoleUser, Content: "Keep it small"},
+		{Role: RoleAgent, Content: "Exploring the repo"},
+	}
+	assert.Equal(t, "[user] Keep it small\n[agent] Exploring the repo", AgentTranscript(entries))
+	assert.Empty(t, AgentTranscript(nil))
+
+	long := strings.Repeat("x", maxAgentTranscriptBytes/2)
+	got := AgentTranscript([]TranscriptEntry{
+		{Role: RoleAgent, Content: long},
+		{Role: RoleAgent, Content: long},
+		{Role: RoleUser, Content: "Latest"},
+	})
+	assert.True(t, strings.HasPrefix(got, "[system] 1 earlier entries omitted.\n"), "oldest entries are dropped first")
+	assert.True(t, strings.HasSuffix(got, "[user] Latest"))
+}
@@ -1,14 +1,19 @@
 package epicapi
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/joshjon/kit/server"
 	"github.com/labstack/echo/v4"
 
+	"github.com/vervesh/verve/internal/audit"
 	"github.com/vervesh/verve/internal/epic"
 	"github.com/vervesh/verve/internal/logkey"
+	"github.com/vervesh/verve/internal/msgcat"
 	"github.com/vervesh/verve/internal/repo"
 	"github.com/vervesh/verve/internal/setting"
 	"github.com/vervesh/verve/internal/task"
@@ -20,11 +25,27 @@ type HTTPHandler struct {
 	repoStore      *repo.Store
 	taskStore      *task.Store
 	settingService *setting.Service
+	auditStore     *audit.Store
+}
+
+// Option configures an HTTPHandler.
+type Option func(*HTTPHandler)
+
+// WithAuditLog dates task completions in epic progress from the audit log
+// rather than from when the task was last updated.
+func WithAuditLog(s *audit.Store) Option {
+	return func(h *HTTPHandler) {
+		h.auditStore = s
+	}
 }
 
 // NewHTTPHandler creates a new HTTPHandler.
-func NewHTTPHandler(store *epic.Store, repoStore *repo.Store, taskStore *task.Store, settingService *setting.Service) *HTTPHandler {
-	return &HTTPHandler{store: store, repoStore: repoStore, taskStore: taskStore, settingService: settingService}
+func NewHTTPHandler(store *epic.Store, repoStore *repo.Store, taskStore *task.Store, settingService *setting.Service, opts ...Option) *HTTPHandler {
+	h := &HTTPHandler{store: store, repoStore: repoStore, taskStore: taskStore, settingService: settingService}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // Register adds the epic endpoints to the provided Echo router group.
@@ -37,17 +58,23 @@ func (h *HTTPHandler) Register(g *echo.Group) {
 	// Epic operations (globally unique IDs)
 	g.GET("/epics/:id", h.GetEpic)
 	g.GET("/epics/:id/tasks", h.GetEpicTasks)
+	g.GET("/epics/:id/progress", h.GetEpicProgress)
+	g.GET("/epics/:id/transcript", h.GetEpicTranscript)
 	g.DELETE("/epics/:id", h.DeleteEpic)
 
 	// Planning session
 	g.POST("/epics/:id/plan", h.StartPlanning)
+	g.POST("/epics/:id/resume", h.ResumePlanning)
 	g.PUT("/epics/:id/proposed-tasks", h.UpdateProposedTasks)
 	g.POST("/epics/:id/session-message", h.SendSessionMessage)
+	g.GET("/epics/:id/suggested-dependencies", h.GetSuggestedDependencies)
+	g.POST("/epics/:id/suggested-dependencies/apply", h.ApplySuggestedDependencies)
 
 	// Confirmation
 	g.POST("/epics/:id/confirm", h.ConfirmEpic)
 	g.POST("/epics/:id/close", h.CloseEpic)
 	g.POST("/epics/:id/stop", h.StopEpic)
+	g.POST("/epics/:id/replan", h.ReplanEpic)
 }
 
 // CreateEpic handles POST /repos/:repo_id/epics
@@ -65,13 +92,22 @@ func (h *HTTPHandler) CreateEpic(c echo.Context) error {
 		return err
 	}
 	if r.SetupStatus != repo.SetupStatusReady {
-		return echo.NewHTTPError(http.StatusConflict, "repository setup is not complete — finish setup before adding epics")
+		return echo.NewHTTPError(http.StatusConflict, msgcat.Text(msgcat.ErrRepoSetupIncompleteEpics))
 	}
 
 	e := epic.NewEpic(repoID.String(), req.Title, req.Description)
 	e.PlanningPrompt = req.PlanningPrompt
+	e.MaxCostUSD = req.MaxCostUSD
 
 	model := req.Model
+	if model == "" {
+		// Inherit the model of the repo's team.
+		defaults, err := h.repoStore.TeamDefaults(c.Request().Context(), r)
+		if err != nil {
+			return err
+		}
+		model = defaults.Model
+	}
 	if model == "" && h.settingService != nil {
 		model = h.settingService.Get(setting.KeyDefaultModel)
 	}
@@ -187,6 +223,27 @@ func (h *HTTPHandler) StartPlanning(c echo.Context) error {
 	return server.SetResponse(c, http.StatusOK, e)
 }
 
+// ResumePlanning handles POST /epics/:id/resume
+func (h *HTTPHandler) ResumePlanning(c echo.Context) error {
+	req, err := server.BindRequest[ResumePlanningRequest](c)
+	if err != nil {
+		return err
+	}
+	id := epic.MustParseEpicID(req.ID)
+	c.Set(logkey.EpicID, id.String())
+
+	ctx := c.Request().Context()
+	if err := h.store.ResumePlanning(ctx, id, req.Message); err != nil {
+		return err
+	}
+
+	e, err := h.store.ReadEpic(ctx, id)
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, e)
+}
+
 // UpdateProposedTasks handles PUT /epics/:id/proposed-tasks
 func (h *HTTPHandler) UpdateProposedTasks(c echo.Context) error {
 	req, err := server.BindRequest[UpdateProposedTasksRequest](c)
@@ -208,6 +265,48 @@ func (h *HTTPHandler) UpdateProposedTasks(c echo.Context) error {
 	return server.SetResponse(c, http.StatusOK, e)
 }
 
+// GetSuggestedDependencies handles GET /epics/:id/suggested-dependencies —
+// lists proposed tasks that likely overlap and should be sequenced.
+func (h *HTTPHandler) GetSuggestedDependencies(c echo.Context) error {
+	req, err := server.BindRequest[EpicIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := epic.MustParseEpicID(req.ID)
+	c.Set(logkey.EpicID, id.String())
+
+	suggestions, err := h.store.SuggestDependencies(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	if suggestions == nil {
+		suggestions = []epic.DependencySuggestion{}
+	}
+	return server.SetResponseList(c, http.StatusOK, suggestions, "")
+}
+
+// ApplySuggestedDependencies handles POST /epics/:id/suggested-dependencies/apply —
+// adds the suggested dependencies to the proposed tasks.
+func (h *HTTPHandler) ApplySuggestedDependencies(c echo.Context) error {
+	req, err := server.BindRequest[EpicIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := epic.MustParseEpicID(req.ID)
+	c.Set(logkey.EpicID, id.String())
+
+	ctx := c.Request().Context()
+	if err := h.store.ApplySuggestedDependencies(ctx, id); err != nil {
+		return err
+	}
+
+	e, err := h.store.ReadEpic(ctx, id)
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, e)
+}
+
 // SendSessionMessage handles POST /epics/:id/session-message — queues a change
 // request for the epic plan. The epic transitions back to planning status and
 // a worker will pick it up with the feedback as context.
@@ -245,7 +344,7 @@ func (h *HTTPHandler) ConfirmEpic(c echo.Context) error {
 	c.Set(logkey.EpicID, id.String())
 
 	ctx := c.Request().Context()
-	if err := h.store.ConfirmEpic(ctx, id, req.NotReady); err != nil {
+	if err := h.store.ConfirmEpicTasks(ctx, id, req.TempIDs, req.NotReady, req.SharedBranch); err != nil {
 		return err
 	}
 
@@ -271,7 +370,7 @@ func (h *HTTPHandler) CloseEpic(c echo.Context) error {
 	}
 
 	if h.taskStore != nil {
-		if err := h.taskStore.BulkCloseTasksByEpic(ctx, id.String(), "Epic closed"); err != nil {
+		if err := h.taskStore.BulkCloseTasksByEpic(ctx, id.String(), msgcat.Text(msgcat.TaskClosedEpicClosed)); err != nil {
 			c.Logger().Errorf("failed to bulk close tasks for epic %s: %v", id, err)
 		}
 	}
@@ -304,6 +403,45 @@ func (h *HTTPHandler) StopEpic(c echo.Context) error {
 	return server.SetResponse(c, http.StatusOK, e)
 }
 
+// ReplanEpic handles POST /epics/:id/replan
+func (h *HTTPHandler) ReplanEpic(c echo.Context) error {
+	req, err := server.BindRequest[ReplanEpicRequest](c)
+	if err != nil {
+		return err
+	}
+	id := epic.MustParseEpicID(req.ID)
+	c.Set(logkey.EpicID, id.String())
+
+	ctx := c.Request().Context()
+	e, err := h.store.ReadEpic(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	tasks := h.readEpicTasks(ctx, e)
+	outcomes := make([]epic.TaskOutcome, 0, len(tasks))
+	for _, t := range tasks {
+		outcomes = append(outcomes, epic.TaskOutcome{
+			ID:                 t.ID.String(),
+			Title:              t.Title,
+			Description:        t.Description,
+			Status:             string(t.Status),
+			PullRequestURL:     t.PullRequestURL,
+			DependsOn:          t.DependsOn,
+			AcceptanceCriteria: t.AcceptanceCriteria,
+		})
+	}
+	if err := h.store.Replan(ctx, id, req.Prompt, outcomes); err != nil {
+		return err
+	}
+
+	e, err = h.store.ReadEpic(ctx, id)
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, e)
+}
+
 // EpicTaskSummary contains the status summary for a task in an epic.
 type EpicTaskSummary struct {
 	ID     string `json:"id"`
@@ -327,7 +465,81 @@ func (h *HTTPHandler) GetEpicTasks(c echo.Context) error {
 		return err
 	}
 
-	summaries := make([]EpicTaskSummary, 0, len(e.TaskIDs))
+	tasks := h.readEpicTasks(ctx, e)
+	summaries := make([]EpicTaskSummary, 0, len(tasks))
+	for _, t := range tasks {
+		summaries = append(summaries, EpicTaskSummary{
+			ID:     t.ID.String(),
+			Number: t.Number,
+			Title:  t.Title,
+			Status: string(t.Status),
+		})
+	}
+
+	return server.SetResponseList(c, http.StatusOK, summaries, "")
+}
+
+// GetEpicProgress handles GET /epics/:id/progress
+func (h *HTTPHandler) GetEpicProgress(c echo.Context) error {
+	req, err := server.BindRequest[EpicIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := epic.MustParseEpicID(req.ID)
+	c.Set(logkey.EpicID, id.String())
+
+	ctx := c.Request().Context()
+	e, err := h.store.ReadEpic(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	tasks := h.readEpicTasks(ctx, e)
+	completedAt := make(map[task.TaskID]time.Time)
+	for _, t := range tasks {
+		if !taskCompleted(t.Status) {
+			continue
+		}
+		completedAt[t.ID] = t.UpdatedAt
+		if h.auditStore == nil {
+			continue
+		}
+		at, ok, err := h.auditStore.LastTransition(ctx, t.ID.String(), string(t.Status))
+		if err != nil {
+			return err
+		}
+		if ok {
+			completedAt[t.ID] = at
+		}
+	}
+
+	return server.SetResponse(c, http.StatusOK, newEpicProgress(e, tasks, completedAt))
+}
+
+// GetEpicTranscript handles GET /epics/:id/transcript?format=json|markdown
+func (h *HTTPHandler) GetEpicTranscript(c echo.Context) error {
+	req, err := server.BindRequest[EpicTranscriptRequest](c)
+	if err != nil {
+		return err
+	}
+	id := epic.MustParseEpicID(req.ID)
+	c.Set(logkey.EpicID, id.String())
+
+	e, err := h.store.ReadEpic(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	if req.Format == transcriptFormatMarkdown {
+		filename := fmt.Sprintf("epic-%d-transcript.md", e.Number)
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+		return c.Blob(http.StatusOK, "text/markdown; charset=utf-8", []byte(epic.RenderTranscriptMarkdown(e)))
+	}
+	return server.SetResponse(c, http.StatusOK, e.Transcript)
+}
+
+// readEpicTasks reads the epic's tasks, skipping any that no longer exist.
+func (h *HTTPHandler) readEpicTasks(ctx context.Context, e *epic.Epic) []*task.Task {
+	tasks := make([]*task.Task, 0, len(e.TaskIDs))
 	for _, taskIDStr := range e.TaskIDs {
 		taskID, parseErr := task.ParseTaskID(taskIDStr)
 		if parseErr != nil {
@@ -337,13 +549,7 @@ func (h *HTTPHandler) GetEpicTasks(c echo.Context) error {
 		if readErr != nil {
 			continue
 		}
-		summaries = append(summaries, EpicTaskSummary{
-			ID:     t.ID.String(),
-			Number: t.Number,
-			Title:  t.Title,
-			Status: string(t.Status),
-		})
+		tasks = append(tasks, t)
 	}
-
-	return server.SetResponseList(c, http.StatusOK, summaries, "")
+	return tasks
 }
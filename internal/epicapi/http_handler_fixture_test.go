@@ -15,8 +15,10 @@ import (
 	"github.com/joshjon/kit/testutil"
 	"github.com/stretchr/testify/require"
 
+	"github.com/vervesh/verve/internal/audit"
 	"github.com/vervesh/verve/internal/epic"
 	"github.com/vervesh/verve/internal/epicapi"
+	"github.com/vervesh/verve/internal/openapi"
 	"github.com/vervesh/verve/internal/repo"
 	"github.com/vervesh/verve/internal/sqlite"
 	"github.com/vervesh/verve/internal/task"
@@ -40,8 +42,8 @@ type stubTaskCreator struct {
 	taskStore *task.Store
 }
 
-func (s *stubTaskCreator) CreateTaskFromEpic(ctx context.Context, repoID, title, description string, dependsOn, acceptanceCriteria []string, epicID string, ready bool, model string) (string, error) {
-	tsk := task.NewTask(repoID, title, description, dependsOn, acceptanceCriteria, 0, false, false, model, ready)
+func (s *stubTaskCreator) CreateTaskFromEpic(ctx context.Context, repoID, title, description string, dependsOn, acceptanceCriteria []string, epicID string, ready bool, model string, maxCostUSD float64) (string, error) {
+	tsk := task.NewTask(repoID, title, description, dependsOn, acceptanceCriteria, maxCostUSD, false, false, model, ready)
 	tsk.EpicID = epicID
 	if err := s.taskStore.CreateTask(ctx, tsk); err != nil {
 		return "", err
@@ -64,12 +66,19 @@ func newFixture(t *testing.T) *fixture {
 	logger := log.NewLogger(log.WithNop())
 	tc := &stubTaskCreator{taskRepo: taskRepo, taskStore: taskStore}
 	epicStore := epic.NewStore(epicRepo, tc, logger)
+	epicStore.SetTaskStatusReader(epic.NewTaskStatusReaderFunc(taskStore.ReadTaskStatus))
+	epicStore.SetTaskCloser(epic.NewTaskCloserFunc(taskStore.CloseTaskFromEpic))
 
-	handler := epicapi.NewHTTPHandler(epicStore, repoStore, taskStore, nil)
+	auditStore := audit.NewStore(sqlite.NewAuditRepository(db), logger)
+	taskStore.SetStatusListener(auditStore)
+
+	handler := epicapi.NewHTTPHandler(epicStore, repoStore, taskStore, nil, epicapi.WithAuditLog(auditStore))
 
 	srv, err := server.NewServer(testutil.GetFreePort(t))
 	require.NoError(t, err)
-	srv.Register("/api/v1", handler)
+	spec := openapi.New("Verve API", "test")
+	spec.Add("/api/v1", handler.Routes()...)
+	srv.Register("/api/v1", handler, spec.ValidateRequests)
 
 	go srv.Start()
 	err = srv.WaitHealthy(10, 100*time.Millisecond)
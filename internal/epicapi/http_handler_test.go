@@ -1,23 +1,29 @@
 package epicapi_test
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"testing"
 
 	"github.com/joshjon/kit/server"
 	"github.com/joshjon/kit/testutil"
+	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/vervesh/verve/internal/epic"
 	"github.com/vervesh/verve/internal/epicapi"
+	"github.com/vervesh/verve/internal/task"
+	"github.com/vervesh/verve/pkg/verveclient"
 )
 
 func TestCreateEpic_Success(t *testing.T) {
 	f := newFixture(t)
 
-	req := epicapi.CreateEpicRequest{
+	req := verveclient.CreateEpicRequest{
 		Title:       "My Epic",
 		Description: "Epic description",
 	}
@@ -31,7 +37,7 @@ func TestCreateEpic_Success(t *testing.T) {
 func TestCreateEpic_EmptyTitle(t *testing.T) {
 	f := newFixture(t)
 
-	req := epicapi.CreateEpicRequest{
+	req := verveclient.CreateEpicRequest{
 		Title:       "",
 		Description: "desc",
 	}
@@ -45,7 +51,7 @@ func TestCreateEpic_InvalidRepoID(t *testing.T) {
 
 	// Use a URL with an invalid repo ID
 	url := f.Server.Address() + "/api/v1/repos/bad-id/epics"
-	req := epicapi.CreateEpicRequest{
+	req := verveclient.CreateEpicRequest{
 		Title:       "Epic",
 		Description: "desc",
 	}
@@ -173,7 +179,7 @@ func TestSendSessionMessage(t *testing.T) {
 	f := newFixture(t)
 	e := f.seedDraftEpic("Epic", "desc")
 
-	req := epicapi.SessionMessageRequest{
+	req := verveclient.SessionMessageRequest{
 		Message: "Please add error handling",
 	}
 	res := testutil.Post[server.Response[epic.Epic]](t, f.epicActionURL(e.ID, "session-message"), req)
@@ -186,12 +192,46 @@ func TestConfirmEpic(t *testing.T) {
 	f := newFixture(t)
 	e := f.seedDraftEpic("Epic", "desc")
 
-	req := epicapi.ConfirmEpicRequest{}
+	req := verveclient.ConfirmEpicRequest{}
 	res := testutil.Post[server.Response[epic.Epic]](t, f.epicActionURL(e.ID, "confirm"), req)
 	assert.Equal(t, epic.StatusActive, res.Data.Status)
 	assert.Len(t, res.Data.TaskIDs, 1)
 }
 
+func TestConfirmEpic_SharedBranch(t *testing.T) {
+	f := newFixture(t)
+	e := f.seedDraftEpic("Epic", "desc")
+
+	req := verveclient.ConfirmEpicRequest{SharedBranch: true}
+	res := testutil.Post[server.Response[epic.Epic]](t, f.epicActionURL(e.ID, "confirm"), req)
+	assert.True(t, res.Data.SharedBranch)
+	assert.Equal(t, "epic/"+e.ID.String(), res.Data.BranchName())
+}
+
+func TestConfirmEpic_Partial(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	e := f.seedEpic("Epic", "desc")
+	require.NoError(t, f.EpicStore.CompletePlanning(ctx, e.ID, []epic.ProposedTask{
+		{TempID: "t1", Title: "Sub-task 1", Description: "desc"},
+		{TempID: "t2", Title: "Sub-task 2", Description: "desc", DependsOnTempIDs: []string{"t1"}},
+	}))
+
+	httpRes := doJSON(t, http.MethodPost, f.epicActionURL(e.ID, "confirm"), verveclient.ConfirmEpicRequest{TempIDs: []string{"t2"}})
+	httpRes.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode, "t2 depends on t1, which is not being confirmed")
+
+	res := testutil.Post[server.Response[epic.Epic]](t, f.epicActionURL(e.ID, "confirm"), verveclient.ConfirmEpicRequest{TempIDs: []string{"t1"}})
+	assert.Equal(t, epic.StatusActive, res.Data.Status)
+	assert.Len(t, res.Data.TaskIDs, 1)
+	require.Len(t, res.Data.ProposedTasks, 1)
+	assert.Equal(t, "t2", res.Data.ProposedTasks[0].TempID)
+
+	res = testutil.Post[server.Response[epic.Epic]](t, f.epicActionURL(e.ID, "confirm"), verveclient.ConfirmEpicRequest{})
+	assert.Len(t, res.Data.TaskIDs, 2)
+	assert.Empty(t, res.Data.ProposedTasks)
+}
+
 func TestCloseEpic(t *testing.T) {
 	f := newFixture(t)
 	e := f.seedEpic("Epic", "desc")
@@ -210,6 +250,47 @@ func TestStopEpic_Success(t *testing.T) {
 	assert.Contains(t, res.Data.SessionLog, "system: Stopped by user.")
 }
 
+func TestGetEpicTranscript(t *testing.T) {
+	f := newFixture(t)
+	e := f.seedDraftEpic("Billing", "desc")
+	require.NoError(t, f.EpicStore.AppendSessionLog(context.Background(), e.ID, []string{"user: Split it up", "Reading the repo"}))
+
+	res := testutil.Get[server.Response[[]epic.TranscriptEntry]](t, f.epicActionURL(e.ID, "transcript"))
+	require.Len(t, res.Data, 2)
+	assert.Equal(t, epic.RoleUser, res.Data[0].Role)
+	assert.Equal(t, "Split it up", res.Data[0].Content)
+	assert.Equal(t, epic.RoleAgent, res.Data[1].Role)
+
+	httpRes, err := testutil.DefaultClient.Get(f.epicActionURL(e.ID, "transcript") + "?format=markdown")
+	require.NoError(t, err)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusOK, httpRes.StatusCode)
+	assert.Contains(t, httpRes.Header.Get(echo.HeaderContentType), "text/markdown")
+	assert.Contains(t, httpRes.Header.Get(echo.HeaderContentDisposition), fmt.Sprintf("epic-%d-transcript.md", e.Number))
+	body, err := io.ReadAll(httpRes.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "# Planning transcript: Billing")
+	assert.Contains(t, string(body), "Split it up")
+
+	httpRes, err = testutil.DefaultClient.Get(f.epicActionURL(e.ID, "transcript") + "?format=pdf")
+	require.NoError(t, err)
+	httpRes.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+}
+
+func TestResumePlanning(t *testing.T) {
+	f := newFixture(t)
+	e := f.seedDraftEpic("Draft Epic", "desc")
+
+	res := testutil.Post[server.Response[epic.Epic]](t, f.epicActionURL(e.ID, "resume"), verveclient.ResumePlanningRequest{Message: "Add a docs task"})
+	assert.Equal(t, epic.StatusPlanning, res.Data.Status)
+	assert.Contains(t, res.Data.SessionLog, "user: Add a docs task")
+
+	httpRes := doJSON(t, http.MethodPost, f.epicActionURL(e.ID, "resume"), verveclient.ResumePlanningRequest{})
+	httpRes.Body.Close()
+	assert.NotEqual(t, http.StatusOK, httpRes.StatusCode, "epic is already planning")
+}
+
 func TestStopEpic_NotPlanning(t *testing.T) {
 	f := newFixture(t)
 	e := f.seedDraftEpic("Draft Epic", "desc")
@@ -224,10 +305,119 @@ func TestGetEpicTasks(t *testing.T) {
 	e := f.seedDraftEpic("Epic", "desc")
 
 	// Confirm to create real tasks
-	req := epicapi.ConfirmEpicRequest{}
+	req := verveclient.ConfirmEpicRequest{}
 	testutil.Post[server.Response[epic.Epic]](t, f.epicActionURL(e.ID, "confirm"), req)
 
 	res := testutil.Get[server.ResponseList[epicapi.EpicTaskSummary]](t, f.epicActionURL(e.ID, "tasks"))
 	assert.Len(t, res.Data, 1)
 	assert.Equal(t, "Sub-task 1", res.Data[0].Title)
 }
+
+func TestReplanEpic(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	e := f.seedDraftEpic("Epic", "desc")
+	testutil.Post[server.Response[epic.Epic]](t, f.epicActionURL(e.ID, "confirm"), verveclient.ConfirmEpicRequest{})
+	confirmed, err := f.EpicStore.ReadEpic(ctx, e.ID)
+	require.NoError(t, err)
+	require.Len(t, confirmed.TaskIDs, 1)
+	original := confirmed.TaskIDs[0]
+
+	res := testutil.Post[server.Response[epic.Epic]](t, f.epicActionURL(e.ID, "replan"), verveclient.ReplanEpicRequest{Prompt: "Replace the sub-task"})
+	assert.Equal(t, epic.StatusPlanning, res.Data.Status)
+	require.Len(t, res.Data.ProposedTasks, 1)
+	assert.Equal(t, original, res.Data.ProposedTasks[0].TempID, "remaining tasks keep their task IDs")
+
+	// The agent drops the original task and proposes a replacement.
+	require.NoError(t, f.EpicStore.CompletePlanning(ctx, e.ID, []epic.ProposedTask{
+		{TempID: "t1", Title: "Sub-task 1b", Description: "desc"},
+	}))
+	res = testutil.Post[server.Response[epic.Epic]](t, f.epicActionURL(e.ID, "confirm"), verveclient.ConfirmEpicRequest{})
+	assert.Equal(t, epic.StatusActive, res.Data.Status)
+	require.Len(t, res.Data.TaskIDs, 2)
+
+	dropped, err := f.TaskStore.ReadTask(ctx, task.MustParseTaskID(original))
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusClosed, dropped.Status)
+	assert.Equal(t, "Dropped when the epic was re-planned", dropped.CloseReason)
+}
+
+func TestReplanEpic_NotActive(t *testing.T) {
+	f := newFixture(t)
+	e := f.seedDraftEpic("Epic", "desc")
+
+	httpRes := doJSON(t, http.MethodPost, f.epicActionURL(e.ID, "replan"), verveclient.ReplanEpicRequest{})
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, httpRes.StatusCode)
+}
+
+func TestGetEpicProgress(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	e := f.seedEpic("Epic", "desc")
+	require.NoError(t, f.EpicStore.CompletePlanning(ctx, e.ID, []epic.ProposedTask{
+		{TempID: "t1", Title: "Sub-task 1", Description: "desc"},
+		{TempID: "t2", Title: "Sub-task 2", Description: "desc"},
+	}))
+
+	res := testutil.Get[server.Response[epicapi.EpicProgress]](t, f.epicActionURL(e.ID, "progress"))
+	assert.Equal(t, 0, res.Data.TotalTasks)
+	assert.Equal(t, 2, res.Data.EstimatedRemainingTasks, "expected proposed tasks before confirmation")
+	assert.Empty(t, res.Data.Burndown)
+
+	testutil.Post[server.Response[epic.Epic]](t, f.epicActionURL(e.ID, "confirm"), verveclient.ConfirmEpicRequest{})
+	confirmed, err := f.EpicStore.ReadEpic(ctx, e.ID)
+	require.NoError(t, err)
+	require.Len(t, confirmed.TaskIDs, 2)
+	require.NoError(t, f.TaskStore.CloseTask(ctx, task.MustParseTaskID(confirmed.TaskIDs[0]), "not needed"))
+
+	res = testutil.Get[server.Response[epicapi.EpicProgress]](t, f.epicActionURL(e.ID, "progress"))
+	assert.Equal(t, 2, res.Data.TotalTasks)
+	assert.Equal(t, 1, res.Data.CompletedTasks)
+	assert.Equal(t, 1, res.Data.EstimatedRemainingTasks)
+	assert.Equal(t, 1, res.Data.StatusCounts["closed"])
+	assert.Equal(t, 1, res.Data.StatusCounts["pending"])
+	assert.Equal(t, 0, res.Data.StatusCounts["failed"])
+	require.Len(t, res.Data.Burndown, 2)
+	assert.Equal(t, 2, res.Data.Burndown[0].Remaining)
+	assert.Equal(t, 1, res.Data.Burndown[1].Completed)
+	assert.Equal(t, 1, res.Data.Burndown[1].Remaining)
+}
+
+func TestGetEpicProgress_NotFound(t *testing.T) {
+	f := newFixture(t)
+
+	httpRes := doJSON(t, http.MethodGet, f.epicActionURL(epic.NewEpicID(), "progress"), nil)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusNotFound, httpRes.StatusCode)
+}
+
+func TestRoutes_MatchRegister(t *testing.T) {
+	h := epicapi.NewHTTPHandler(nil, nil, nil, nil)
+	e := echo.New()
+	h.Register(e.Group(""))
+
+	var registered, described []string
+	for _, r := range e.Routes() {
+		registered = append(registered, r.Method+" "+r.Path)
+	}
+	for _, r := range h.Routes() {
+		described = append(described, r.Method+" "+r.Path)
+	}
+	assert.ElementsMatch(t, registered, described)
+}
+
+func TestUpdateProposedTasks_MalformedBody(t *testing.T) {
+	f := newFixture(t)
+	e := f.seedDraftEpic("Epic", "desc")
+
+	httpRes := doJSON(t, http.MethodPut, f.epicActionURL(e.ID, "proposed-tasks"), map[string]any{
+		"tasks": []map[string]any{{"title": "Add API"}, {"title": "Add UI", "depends_on_temp_ids": "t1"}},
+	})
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+
+	var res server.ResponseError
+	require.NoError(t, json.NewDecoder(httpRes.Body).Decode(&res))
+	assert.Equal(t, []string{"tasks[1].depends_on_temp_ids: [must be an array]"}, res.Error.Details)
+}
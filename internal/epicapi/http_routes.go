@@ -0,0 +1,44 @@
+package epicapi
+
+import (
+	"net/http"
+
+	"github.com/vervesh/verve/internal/epic"
+	"github.com/vervesh/verve/internal/openapi"
+)
+
+// Routes describes the endpoints Register adds, for the OpenAPI document.
+// Keep it in step with Register.
+func (h *HTTPHandler) Routes() []openapi.Route {
+	routes := []openapi.Route{
+		// Epic CRUD (repo-scoped)
+		{Method: http.MethodPost, Path: "/repos/:repo_id/epics", Summary: "Create an epic", Request: CreateEpicRequest{}, Response: epic.Epic{}, Status: http.StatusCreated},
+		{Method: http.MethodGet, Path: "/repos/:repo_id/epics", Summary: "List a repo's epics", Request: RepoIDRequest{}, Response: epic.Epic{}, List: true},
+		{Method: http.MethodGet, Path: "/repos/:repo_id/epics/:number", Summary: "Get an epic by its number in the repo", Request: EpicByNumberRequest{}, Response: epic.Epic{}},
+
+		// Epic operations (globally unique IDs)
+		{Method: http.MethodGet, Path: "/epics/:id", Summary: "Get an epic", Request: EpicIDRequest{}, Response: epic.Epic{}},
+		{Method: http.MethodGet, Path: "/epics/:id/tasks", Summary: "List an epic's tasks", Request: EpicIDRequest{}, Response: EpicTaskSummary{}, List: true},
+		{Method: http.MethodGet, Path: "/epics/:id/progress", Summary: "Get an epic's progress and burndown", Request: EpicIDRequest{}, Response: EpicProgress{}},
+		{Method: http.MethodGet, Path: "/epics/:id/transcript", Summary: "Export an epic's planning transcript as JSON or markdown", Request: EpicTranscriptRequest{}, Response: epic.TranscriptEntry{}, List: true},
+		{Method: http.MethodDelete, Path: "/epics/:id", Summary: "Delete an epic", Request: EpicIDRequest{}},
+
+		// Planning session
+		{Method: http.MethodPost, Path: "/epics/:id/plan", Summary: "Start planning an epic", Request: StartPlanningRequest{}, Response: epic.Epic{}},
+		{Method: http.MethodPost, Path: "/epics/:id/resume", Summary: "Resume an epic's planning session from its transcript", Request: ResumePlanningRequest{}, Response: epic.Epic{}},
+		{Method: http.MethodPut, Path: "/epics/:id/proposed-tasks", Summary: "Replace an epic's proposed tasks", Request: UpdateProposedTasksRequest{}, Response: epic.Epic{}},
+		{Method: http.MethodPost, Path: "/epics/:id/session-message", Summary: "Send a message to the planning agent", Request: SessionMessageRequest{}, Response: epic.Epic{}},
+		{Method: http.MethodGet, Path: "/epics/:id/suggested-dependencies", Summary: "Suggest dependencies between proposed tasks", Request: EpicIDRequest{}, Response: epic.DependencySuggestion{}, List: true},
+		{Method: http.MethodPost, Path: "/epics/:id/suggested-dependencies/apply", Summary: "Apply the suggested dependencies", Request: EpicIDRequest{}, Response: epic.Epic{}},
+
+		// Confirmation
+		{Method: http.MethodPost, Path: "/epics/:id/confirm", Summary: "Confirm some or all of an epic's proposed tasks, creating them", Request: ConfirmEpicRequest{}, Response: epic.Epic{}},
+		{Method: http.MethodPost, Path: "/epics/:id/close", Summary: "Close an epic", Request: EpicIDRequest{}, Response: epic.Epic{}},
+		{Method: http.MethodPost, Path: "/epics/:id/replan", Summary: "Re-plan an active epic's remaining work", Request: ReplanEpicRequest{}, Response: epic.Epic{}},
+		{Method: http.MethodPost, Path: "/epics/:id/stop", Summary: "Stop an epic's planning session", Request: EpicIDRequest{}, Response: epic.Epic{}},
+	}
+	for i := range routes {
+		routes[i].Tag = "epics"
+	}
+	return routes
+}
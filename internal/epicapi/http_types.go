@@ -1,30 +1,32 @@
 package epicapi
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/cohesivestack/valgo"
 
 	"github.com/vervesh/verve/internal/epic"
 	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/pkg/verveclient"
 )
 
 // --- Request types ---
 
 // CreateEpicRequest is the request body for creating an epic.
 type CreateEpicRequest struct {
-	RepoID         string `param:"repo_id" json:"-"`
-	Title          string `json:"title"`
-	Description    string `json:"description"`
-	PlanningPrompt string `json:"planning_prompt,omitempty"`
-	Model          string `json:"model,omitempty"`
+	RepoID string `param:"repo_id" json:"-"`
+	verveclient.CreateEpicRequest
 }
 
 func (r CreateEpicRequest) Validate() error {
-	return valgo.
+	v := valgo.
 		In("params", valgo.Is(repo.RepoIDValidator(r.RepoID, "repo_id"))).
-		Is(valgo.String(r.Title, "title").Not().Blank().MaxLength(200)).
-		ToError()
+		Is(valgo.String(r.Title, "title").Not().Blank().MaxLength(200))
+	if r.MaxCostUSD < 0 {
+		v = v.AddErrorMessage("max_cost_usd", "must not be negative")
+	}
+	return v.ToError()
 }
 
 // RepoIDRequest captures the :repo_id path parameter.
@@ -62,8 +64,8 @@ func (r EpicIDRequest) Validate() error {
 
 // StartPlanningRequest is the request body for starting a planning session.
 type StartPlanningRequest struct {
-	ID     string `param:"id" json:"-"`
-	Prompt string `json:"prompt"`
+	ID string `param:"id" json:"-"`
+	verveclient.StartPlanningRequest
 }
 
 func (r StartPlanningRequest) Validate() error {
@@ -72,6 +74,39 @@ func (r StartPlanningRequest) Validate() error {
 		ToError()
 }
 
+// ResumePlanningRequest is the request body for resuming a planning session.
+type ResumePlanningRequest struct {
+	ID string `param:"id" json:"-"`
+	verveclient.ResumePlanningRequest
+}
+
+func (r ResumePlanningRequest) Validate() error {
+	return valgo.In("params", valgo.Is(epic.EpicIDValidator(r.ID, "id"))).
+		Is(valgo.String(r.Message, "message").MaxLength(10000)).
+		ToError()
+}
+
+// Transcript export formats.
+const (
+	transcriptFormatJSON     = "json"
+	transcriptFormatMarkdown = "markdown"
+)
+
+// EpicTranscriptRequest captures the :id path parameter and the format to
+// export the planning transcript in.
+type EpicTranscriptRequest struct {
+	ID     string `param:"id" json:"-"`
+	Format string `query:"format" json:"-"` // "json" (default) or "markdown"
+}
+
+func (r EpicTranscriptRequest) Validate() error {
+	return valgo.In("params", valgo.Is(epic.EpicIDValidator(r.ID, "id"))).
+		Is(valgo.String(r.Format, "format").Passing(func(s string) bool {
+			return s == "" || s == transcriptFormatJSON || s == transcriptFormatMarkdown
+		}, "Must be json or markdown")).
+		ToError()
+}
+
 // UpdateProposedTasksRequest is the request body for updating proposed tasks.
 type UpdateProposedTasksRequest struct {
 	ID    string              `param:"id" json:"-"`
@@ -82,10 +117,22 @@ func (r UpdateProposedTasksRequest) Validate() error {
 	return valgo.In("params", valgo.Is(epic.EpicIDValidator(r.ID, "id"))).ToError()
 }
 
+// ReplanEpicRequest is the request body for re-planning an active epic.
+type ReplanEpicRequest struct {
+	ID string `param:"id" json:"-"`
+	verveclient.ReplanEpicRequest
+}
+
+func (r ReplanEpicRequest) Validate() error {
+	return valgo.In("params", valgo.Is(epic.EpicIDValidator(r.ID, "id"))).
+		Is(valgo.String(r.Prompt, "prompt").MaxLength(10000)).
+		ToError()
+}
+
 // SessionMessageRequest is the request body for sending a message in a planning session.
 type SessionMessageRequest struct {
-	ID      string `param:"id" json:"-"`
-	Message string `json:"message"`
+	ID string `param:"id" json:"-"`
+	verveclient.SessionMessageRequest
 }
 
 func (r SessionMessageRequest) Validate() error {
@@ -96,10 +143,22 @@ func (r SessionMessageRequest) Validate() error {
 
 // ConfirmEpicRequest is the request body for confirming an epic.
 type ConfirmEpicRequest struct {
-	ID       string `param:"id" json:"-"`
-	NotReady bool   `json:"not_ready,omitempty"`
+	ID string `param:"id" json:"-"`
+	verveclient.ConfirmEpicRequest
 }
 
 func (r ConfirmEpicRequest) Validate() error {
-	return valgo.In("params", valgo.Is(epic.EpicIDValidator(r.ID, "id"))).ToError()
+	v := valgo.In("params", valgo.Is(epic.EpicIDValidator(r.ID, "id")))
+	for i, tempID := range r.TempIDs {
+		v = v.Is(valgo.String(tempID, fmt.Sprintf("temp_ids[%d]", i)).Not().Blank())
+	}
+	return v.ToError()
 }
+
+// --- Response types ---
+
+// EpicProgress is the response body for the epic progress endpoint.
+type EpicProgress = verveclient.EpicProgress
+
+// BurndownPoint is a point on an epic's burndown chart.
+type BurndownPoint = verveclient.BurndownPoint
@@ -0,0 +1,98 @@
+package epicapi
+
+import (
+	"slices"
+	"time"
+
+	"github.com/vervesh/verve/internal/epic"
+	"github.com/vervesh/verve/internal/task"
+)
+
+// taskStatuses lists every task status so progress reports them all.
+var taskStatuses = []task.Status{
+	task.StatusPending,
+	task.StatusRunning,
+	task.StatusReview,
+	task.StatusMerged,
+	task.StatusClosed,
+	task.StatusFailed,
+}
+
+// taskCompleted reports whether a task in status counts towards completing
+// its epic. Failed tasks still block the epic, so they remain.
+func taskCompleted(s task.Status) bool {
+	return s == task.StatusMerged || s == task.StatusClosed
+}
+
+// newEpicProgress summarizes the epic's tasks. completedAt holds when each
+// completed task completed.
+func newEpicProgress(e *epic.Epic, tasks []*task.Task, completedAt map[task.TaskID]time.Time) EpicProgress {
+	p := EpicProgress{
+		EpicID:       e.ID.String(),
+		Status:       string(e.Status),
+		StatusCounts: make(map[string]int, len(taskStatuses)),
+		TotalTasks:   len(tasks),
+		Burndown:     []BurndownPoint{},
+	}
+	for _, s := range taskStatuses {
+		p.StatusCounts[string(s)] = 0
+	}
+
+	var (
+		start      time.Time
+		merged     int
+		mergedCost float64
+		times      []time.Time
+	)
+	for _, t := range tasks {
+		p.StatusCounts[string(t.Status)]++
+		p.Cost.TasksUSD += t.CostUSD
+		if start.IsZero() || t.CreatedAt.Before(start) {
+			start = t.CreatedAt
+		}
+		if at, ok := completedAt[t.ID]; ok {
+			times = append(times, at)
+		}
+		if t.Status == task.StatusMerged {
+			merged++
+			mergedCost += t.CostUSD
+		}
+	}
+	p.CompletedTasks = len(times)
+	// Proposed tasks not yet confirmed are still to be done.
+	p.EstimatedRemainingTasks = p.TotalTasks - p.CompletedTasks + len(e.ProposedTasks)
+	if e.Status == epic.StatusPlanning || e.Status == epic.StatusDraft {
+		p.EstimatedRemainingTasks = len(e.ProposedTasks)
+	}
+
+	p.Cost.PlanningUSD = e.CostUSD
+	p.Cost.SpentUSD = p.Cost.PlanningUSD + p.Cost.TasksUSD
+	p.Cost.EstimatedTotalUSD = p.Cost.SpentUSD
+	if merged > 0 {
+		avg := mergedCost / float64(merged)
+		for _, t := range tasks {
+			if _, ok := completedAt[t.ID]; !ok && t.CostUSD < avg {
+				p.Cost.EstimatedTotalUSD += avg - t.CostUSD
+			}
+		}
+	}
+
+	if len(tasks) == 0 {
+		return p
+	}
+	slices.SortFunc(times, time.Time.Compare)
+	p.Burndown = append(p.Burndown, BurndownPoint{Time: start, Remaining: p.TotalTasks})
+	for i, at := range times {
+		p.Burndown = append(p.Burndown, BurndownPoint{Time: at, Completed: i + 1, Remaining: p.TotalTasks - i - 1})
+	}
+
+	remaining := p.TotalTasks - p.CompletedTasks
+	if p.CompletedTasks > 0 && remaining > 0 && (e.Status == epic.StatusReady || e.Status == epic.StatusActive) {
+		last := times[len(times)-1]
+		if elapsed := last.Sub(start); elapsed > 0 {
+			eta := last.Add(elapsed / time.Duration(p.CompletedTasks) * time.Duration(remaining))
+			p.EstimatedCompletionAt = &eta
+		}
+	}
+	return p
+}
@@ -0,0 +1,88 @@
+// Package epicbranch manages the shared integration branch of epics whose
+// tasks branch from and merge into epic/<id> instead of the default branch.
+package epicbranch
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/joshjon/kit/log"
+
+	"github.com/vervesh/verve/internal/epic"
+	"github.com/vervesh/verve/internal/githubtoken"
+	"github.com/vervesh/verve/internal/msgcat"
+	"github.com/vervesh/verve/internal/repo"
+)
+
+// Service opens the pull request that merges a completed epic's integration
+// branch into the repo's default branch.
+type Service struct {
+	epics       *epic.Store
+	repos       *repo.Store
+	githubToken *githubtoken.Service
+	logger      log.Logger
+}
+
+var _ epic.EventListener = (*Service)(nil)
+
+// NewService creates a new epic branch Service.
+func NewService(epics *epic.Store, repos *repo.Store, githubToken *githubtoken.Service, logger log.Logger) *Service {
+	return &Service{
+		epics:       epics,
+		repos:       repos,
+		githubToken: githubToken,
+		logger:      logger.With("component", "epic_branch_service"),
+	}
+}
+
+// EpicCompleted opens the epic's integration PR once all its tasks have
+// merged into the integration branch. Epics without a shared branch are
+// ignored.
+func (s *Service) EpicCompleted(ctx context.Context, e *epic.Epic) {
+	if !e.SharedBranch || e.PRNumber > 0 {
+		return
+	}
+	if err := s.openPullRequest(ctx, e); err != nil {
+		s.logger.Error("failed to open epic integration pull request", "epic.id", e.ID.String(), "epic.branch", e.BranchName(), "error", err)
+	}
+}
+
+// EpicBudgetExceeded is a no-op; planning never touches the integration
+// branch.
+func (s *Service) EpicBudgetExceeded(context.Context, *epic.Epic) {}
+
+func (s *Service) openPullRequest(ctx context.Context, e *epic.Epic) error {
+	gh := s.githubToken.GetClient()
+	if gh == nil {
+		return fmt.Errorf("no GitHub token configured")
+	}
+	repoID, err := repo.ParseRepoID(e.RepoID)
+	if err != nil {
+		return err
+	}
+	r, err := s.repos.ReadRepo(ctx, repoID)
+	if err != nil {
+		return err
+	}
+
+	branch := e.BranchName()
+	prURL, prNumber, err := gh.FindPRForBranch(ctx, r.Owner, r.Name, branch)
+	if err != nil {
+		return fmt.Errorf("find pr: %w", err)
+	}
+	if prNumber == 0 {
+		base, err := gh.GetDefaultBranch(ctx, r.Owner, r.Name)
+		if err != nil {
+			return fmt.Errorf("get default branch: %w", err)
+		}
+		title := msgcat.Text(msgcat.EpicPRTitle, e.Title)
+		body := msgcat.Text(msgcat.EpicPRBody, e.Number, len(e.TaskIDs), e.Description)
+		prURL, prNumber, err = gh.CreatePR(ctx, r.Owner, r.Name, branch, base, title, body)
+		if err != nil {
+			return fmt.Errorf("create pr: %w", err)
+		}
+	}
+
+	s.logger.Info("opened epic integration pull request", "epic.id", e.ID.String(), "pr.url", prURL)
+	return s.epics.SetPullRequest(ctx, e.ID, prURL, prNumber)
+}
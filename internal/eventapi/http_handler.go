@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
@@ -11,24 +14,53 @@ import (
 	"github.com/vervesh/verve/internal/task"
 )
 
-// HTTPHandler handles SSE event HTTP requests.
+// sseRetry is the reconnection delay suggested to SSE clients.
+const sseRetry = 3 * time.Second
+
+// HTTPHandler handles SSE and WebSocket event HTTP requests.
 type HTTPHandler struct {
-	taskStore *task.Store
-	repoStore *repo.Store
+	taskStore      *task.Store
+	repoStore      *repo.Store
+	originPatterns []string
+}
+
+// Option configures an HTTPHandler.
+type Option func(h *HTTPHandler)
+
+// WithAllowedOrigins allows WebSocket connections from the given origins
+// (e.g. "http://localhost:5173") in addition to same-origin requests.
+func WithAllowedOrigins(origins ...string) Option {
+	return func(h *HTTPHandler) {
+		for _, o := range origins {
+			if u, err := url.Parse(o); err == nil && u.Host != "" {
+				h.originPatterns = append(h.originPatterns, u.Host)
+			}
+		}
+	}
 }
 
 // NewHTTPHandler creates a new HTTPHandler.
-func NewHTTPHandler(taskStore *task.Store, repoStore *repo.Store) *HTTPHandler {
-	return &HTTPHandler{taskStore: taskStore, repoStore: repoStore}
+func NewHTTPHandler(taskStore *task.Store, repoStore *repo.Store, opts ...Option) *HTTPHandler {
+	h := &HTTPHandler{taskStore: taskStore, repoStore: repoStore}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // Register adds the endpoints to the provided Echo router group.
 func (h *HTTPHandler) Register(g *echo.Group) {
 	g.GET("/events", h.Events)
+	g.GET("/ws", h.WebSocket)
 }
 
 // Events handles GET /events as a Server-Sent Events stream.
 // Optionally filtered by ?repo_id=xxx.
+//
+// Every event carries an SSE id. When a client reconnects with the
+// Last-Event-ID header and the missed events are still buffered, they are
+// replayed instead of sending a fresh init event; otherwise the client
+// receives init as on a new connection.
 func (h *HTTPHandler) Events(c echo.Context) error {
 	repoIDFilter := c.QueryParam("repo_id")
 
@@ -40,28 +72,55 @@ func (h *HTTPHandler) Events(c echo.Context) error {
 
 	ctx := c.Request().Context()
 
-	// Send init event with task list (logs nil'd), filtered by repo if specified.
-	var tasks []*task.Task
-	var err error
-	if repoIDFilter != "" {
-		tasks, err = h.taskStore.ListTasksByRepo(ctx, repoIDFilter)
-	} else {
-		tasks, err = h.taskStore.ListTasks(ctx)
-	}
-	if err != nil {
-		return err
-	}
-	for _, t := range tasks {
-		t.Logs = nil
-	}
-	if err := writeSSE(w, "init", tasks); err != nil {
-		return err
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", sseRetry.Milliseconds()); err != nil {
+		return nil
 	}
 
-	// Subscribe to broker and stream events.
-	ch := h.taskStore.Subscribe()
+	var (
+		ch      chan task.Event
+		missed  []task.Event
+		resumed bool
+	)
+	if lastEventID, err := strconv.ParseUint(c.Request().Header.Get("Last-Event-ID"), 10, 64); err == nil {
+		ch, missed, resumed = h.taskStore.Resume(lastEventID)
+	} else {
+		ch = h.taskStore.Subscribe()
+	}
 	defer h.taskStore.Unsubscribe(ch)
 
+	if resumed {
+		for _, event := range missed {
+			if repoIDFilter != "" && event.RepoID != repoIDFilter {
+				continue
+			}
+			if err := writeSSE(w, event.ID, event.Type, event); err != nil {
+				return nil
+			}
+		}
+	} else {
+		// Events with IDs up to this point are reflected in the task list
+		// loaded below; later events arrive on the subscription.
+		lastEventID := h.taskStore.LastEventID()
+
+		// Send init event with task list (logs nil'd), filtered by repo if specified.
+		var tasks []*task.Task
+		var err error
+		if repoIDFilter != "" {
+			tasks, err = h.taskStore.ListTasksByRepo(ctx, repoIDFilter)
+		} else {
+			tasks, err = h.taskStore.ListTasks(ctx)
+		}
+		if err != nil {
+			return err
+		}
+		for _, t := range tasks {
+			t.Logs = nil
+		}
+		if err := writeSSE(w, lastEventID, "init", tasks); err != nil {
+			return err
+		}
+	}
+
 	for {
 		select {
 		case event := <-ch:
@@ -69,7 +128,7 @@ func (h *HTTPHandler) Events(c echo.Context) error {
 			if repoIDFilter != "" && event.RepoID != repoIDFilter {
 				continue
 			}
-			if err := writeSSE(w, event.Type, event); err != nil {
+			if err := writeSSE(w, event.ID, event.Type, event); err != nil {
 				return nil
 			}
 		case <-ctx.Done():
@@ -78,12 +137,12 @@ func (h *HTTPHandler) Events(c echo.Context) error {
 	}
 }
 
-func writeSSE(w *echo.Response, event string, data any) error {
+func writeSSE(w *echo.Response, id uint64, event string, data any) error {
 	b, err := json.Marshal(data)
 	if err != nil {
 		return err
 	}
-	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, b); err != nil {
+	if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", id, event, b); err != nil {
 		return err
 	}
 	w.Flush()
@@ -0,0 +1,60 @@
+package eventapi_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/joshjon/kit/server"
+	"github.com/joshjon/kit/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/app"
+	"github.com/vervesh/verve/internal/eventapi"
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/sqlite"
+	"github.com/vervesh/verve/internal/task"
+)
+
+type fixture struct {
+	Server    *server.Server
+	TaskStore *task.Store
+	Repo      *repo.Repo
+	OtherRepo *repo.Repo
+	t         *testing.T
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	db := sqlite.NewTestDB(t)
+	taskStore := task.NewStore(sqlite.NewTaskRepository(db), task.NewBroker(nil))
+	repoStore := repo.NewStore(sqlite.NewRepoRepository(db))
+
+	handler := eventapi.NewHTTPHandler(taskStore, repoStore)
+
+	srv, err := server.NewServer(testutil.GetFreePort(t), server.WithRequestTimeout(server.DefaultRequestTimeout, app.StreamingPaths...))
+	require.NoError(t, err)
+	srv.Register("/api/v1", handler)
+
+	go srv.Start()
+	err = srv.WaitHealthy(10, 100*time.Millisecond)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	r, _ := repo.NewRepo("owner/test-repo")
+	require.NoError(t, repoStore.CreateRepo(ctx, r))
+	other, _ := repo.NewRepo("owner/other-repo")
+	require.NoError(t, repoStore.CreateRepo(ctx, other))
+
+	t.Cleanup(func() { srv.Stop(context.Background()) })
+
+	return &fixture{Server: srv, TaskStore: taskStore, Repo: r, OtherRepo: other, t: t}
+}
+
+func (f *fixture) createTask(r *repo.Repo, title string) *task.Task {
+	f.t.Helper()
+	tsk := task.NewTask(r.ID.String(), title, "", nil, nil, 0, false, false, "", true)
+	require.NoError(f.t, f.TaskStore.CreateTask(context.Background(), tsk))
+	return tsk
+}
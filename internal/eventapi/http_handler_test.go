@@ -0,0 +1,144 @@
+package eventapi_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/task"
+)
+
+type sseEvent struct {
+	ID    string
+	Event string
+	Data  string
+	Retry string
+}
+
+type sseStream struct {
+	t       *testing.T
+	scanner *bufio.Scanner
+	events  chan sseEvent
+}
+
+// openEvents connects to GET /events, optionally resuming from lastEventID.
+func (f *fixture) openEvents(lastEventID string) *sseStream {
+	f.t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.Server.Address()+"/api/v1/events", http.NoBody)
+	require.NoError(f.t, err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(f.t, err)
+	f.t.Cleanup(func() {
+		cancel()
+		_ = res.Body.Close()
+	})
+
+	s := &sseStream{t: f.t, scanner: bufio.NewScanner(res.Body), events: make(chan sseEvent, 16)}
+	go s.read()
+	return s
+}
+
+func (s *sseStream) read() {
+	defer close(s.events)
+	var ev sseEvent
+	for s.scanner.Scan() {
+		line := s.scanner.Text()
+		if line == "" {
+			s.events <- ev
+			ev = sseEvent{}
+			continue
+		}
+		field, value, _ := strings.Cut(line, ": ")
+		switch field {
+		case "id":
+			ev.ID = value
+		case "event":
+			ev.Event = value
+		case "data":
+			ev.Data = value
+		case "retry":
+			ev.Retry = value
+		}
+	}
+}
+
+func (s *sseStream) next() sseEvent {
+	s.t.Helper()
+	select {
+	case ev, ok := <-s.events:
+		require.True(s.t, ok, "stream closed")
+		return ev
+	case <-time.After(5 * time.Second):
+		require.FailNow(s.t, "timed out waiting for SSE event")
+		return sseEvent{}
+	}
+}
+
+func taskIDFromEvent(t *testing.T, ev sseEvent) string {
+	t.Helper()
+	var e task.Event
+	require.NoError(t, json.Unmarshal([]byte(ev.Data), &e))
+	require.NotNil(t, e.Task)
+	return e.Task.ID.String()
+}
+
+func TestEvents_RetryHintAndIDs(t *testing.T) {
+	f := newFixture(t)
+	stream := f.openEvents("")
+
+	assert.Equal(t, "3000", stream.next().Retry)
+
+	initEv := stream.next()
+	assert.Equal(t, "init", initEv.Event)
+	initID, err := strconv.ParseUint(initEv.ID, 10, 64)
+	require.NoError(t, err)
+
+	created := f.createTask(f.Repo, "New task")
+	ev := stream.next()
+	assert.Equal(t, task.EventTaskCreated, ev.Event)
+	assert.Equal(t, strconv.FormatUint(initID+1, 10), ev.ID)
+	assert.Equal(t, created.ID.String(), taskIDFromEvent(t, ev))
+}
+
+func TestEvents_ResumeReplaysMissedEvents(t *testing.T) {
+	f := newFixture(t)
+	lastEventID := strconv.FormatUint(f.TaskStore.LastEventID(), 10)
+
+	first := f.createTask(f.Repo, "Missed one")
+	second := f.createTask(f.Repo, "Missed two")
+
+	stream := f.openEvents(lastEventID)
+	assert.NotEmpty(t, stream.next().Retry)
+
+	ev := stream.next()
+	assert.Equal(t, task.EventTaskCreated, ev.Event, "expected replay instead of init")
+	assert.Equal(t, first.ID.String(), taskIDFromEvent(t, ev))
+	ev = stream.next()
+	assert.Equal(t, second.ID.String(), taskIDFromEvent(t, ev))
+
+	live := f.createTask(f.Repo, "Live")
+	assert.Equal(t, live.ID.String(), taskIDFromEvent(t, stream.next()))
+}
+
+func TestEvents_ResumeUnknownIDSendsInit(t *testing.T) {
+	f := newFixture(t)
+	f.createTask(f.Repo, "Existing")
+
+	for _, id := range []string{"1", "not-a-number"} {
+		stream := f.openEvents(id)
+		assert.NotEmpty(t, stream.next().Retry)
+		assert.Equal(t, "init", stream.next().Event, "Last-Event-ID %q", id)
+	}
+}
@@ -0,0 +1,285 @@
+package eventapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/labstack/echo/v4"
+
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/task"
+)
+
+const (
+	wsPingInterval = 30 * time.Second
+	wsWriteTimeout = 10 * time.Second
+	wsReadLimit    = 64 * 1024
+)
+
+// Message types sent by WebSocket clients.
+const (
+	wsMessageSubscribe   = "subscribe"
+	wsMessageUnsubscribe = "unsubscribe"
+	wsMessagePing        = "ping"
+)
+
+// Message types sent by the server in addition to the broker event types.
+const (
+	wsMessageInit       = "init"
+	wsMessageSubscribed = "subscribed"
+	wsMessagePong       = "pong"
+	wsMessageError      = "error"
+)
+
+// wsClientMessage is a message sent by a WebSocket client. Subscribe and
+// unsubscribe add or remove repos and tasks from the connection's filter.
+type wsClientMessage struct {
+	Type    string   `json:"type"`
+	RepoIDs []string `json:"repo_ids,omitempty"`
+	TaskIDs []string `json:"task_ids,omitempty"`
+}
+
+// wsInitMessage is sent once the connection is established with the current
+// task list, matching the SSE init event.
+type wsInitMessage struct {
+	Type  string       `json:"type"`
+	Tasks []*task.Task `json:"tasks"`
+}
+
+// wsSubscribedMessage acknowledges a subscription change with the resulting
+// filter. Empty repo and task lists mean every event is streamed.
+type wsSubscribedMessage struct {
+	Type    string   `json:"type"`
+	RepoIDs []string `json:"repo_ids"`
+	TaskIDs []string `json:"task_ids"`
+}
+
+type wsErrorMessage struct {
+	Type  string `json:"type"`
+	Error string `json:"error"`
+}
+
+type wsTypeMessage struct {
+	Type string `json:"type"`
+}
+
+// wsRead is a client message or the error from decoding it.
+type wsRead struct {
+	msg wsClientMessage
+	err error
+}
+
+// wsFilter tracks the repos and tasks a connection is subscribed to. An empty
+// filter matches every event.
+type wsFilter struct {
+	repoIDs map[string]bool
+	taskIDs map[string]bool
+}
+
+func newWSFilter() *wsFilter {
+	return &wsFilter{repoIDs: make(map[string]bool), taskIDs: make(map[string]bool)}
+}
+
+func (f *wsFilter) matches(e task.Event) bool {
+	if len(f.repoIDs) == 0 && len(f.taskIDs) == 0 {
+		return true
+	}
+	if e.RepoID != "" && f.repoIDs[e.RepoID] {
+		return true
+	}
+	taskID := e.TaskID.String()
+	if e.Task != nil {
+		taskID = e.Task.ID.String()
+	}
+	return f.taskIDs[taskID]
+}
+
+// apply validates and applies a subscribe or unsubscribe message.
+func (f *wsFilter) apply(msg wsClientMessage) error {
+	for _, id := range msg.RepoIDs {
+		if _, err := repo.ParseRepoID(id); err != nil {
+			return errors.New("invalid repo ID: " + id)
+		}
+	}
+	for _, id := range msg.TaskIDs {
+		if _, err := task.ParseTaskID(id); err != nil {
+			return errors.New("invalid task ID: " + id)
+		}
+	}
+	subscribe := msg.Type == wsMessageSubscribe
+	for _, id := range msg.RepoIDs {
+		setOrDelete(f.repoIDs, id, subscribe)
+	}
+	for _, id := range msg.TaskIDs {
+		setOrDelete(f.taskIDs, id, subscribe)
+	}
+	return nil
+}
+
+func (f *wsFilter) subscribed() wsSubscribedMessage {
+	msg := wsSubscribedMessage{Type: wsMessageSubscribed, RepoIDs: []string{}, TaskIDs: []string{}}
+	for id := range f.repoIDs {
+		msg.RepoIDs = append(msg.RepoIDs, id)
+	}
+	for id := range f.taskIDs {
+		msg.TaskIDs = append(msg.TaskIDs, id)
+	}
+	return msg
+}
+
+func setOrDelete(m map[string]bool, key string, set bool) {
+	if set {
+		m[key] = true
+	} else {
+		delete(m, key)
+	}
+}
+
+// WebSocket handles GET /ws, streaming the same events as GET /events over a
+// WebSocket. The initial filter can be set with ?repo_id=xxx and changed at
+// any time by sending subscribe or unsubscribe messages. The server pings the
+// client periodically and replies to application-level ping messages for
+// clients that can't send protocol pings.
+func (h *HTTPHandler) WebSocket(c echo.Context) error {
+	conn, err := websocket.Accept(c.Response(), c.Request(), &websocket.AcceptOptions{
+		OriginPatterns: h.originPatterns,
+	})
+	if err != nil {
+		// Accept has already written the HTTP error response.
+		return nil
+	}
+	defer func() { _ = conn.CloseNow() }()
+	conn.SetReadLimit(wsReadLimit)
+
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	defer cancel()
+
+	filter := newWSFilter()
+	repoIDFilter := c.QueryParam("repo_id")
+	if repoIDFilter != "" {
+		if err := filter.apply(wsClientMessage{Type: wsMessageSubscribe, RepoIDs: []string{repoIDFilter}}); err != nil {
+			_ = conn.Close(websocket.StatusPolicyViolation, err.Error())
+			return nil
+		}
+	}
+
+	// Subscribe before loading the snapshot so no events are missed between
+	// the two.
+	ch := h.taskStore.Subscribe()
+	defer h.taskStore.Unsubscribe(ch)
+
+	var tasks []*task.Task
+	if repoIDFilter != "" {
+		tasks, err = h.taskStore.ListTasksByRepo(ctx, repoIDFilter)
+	} else {
+		tasks, err = h.taskStore.ListTasks(ctx)
+	}
+	if err != nil {
+		_ = conn.Close(websocket.StatusInternalError, "failed to list tasks")
+		return nil
+	}
+	for _, t := range tasks {
+		t.Logs = nil
+	}
+	if err := writeWS(ctx, conn, wsInitMessage{Type: wsMessageInit, Tasks: tasks}); err != nil {
+		return nil
+	}
+
+	reads := make(chan wsRead)
+	go func() {
+		defer cancel()
+		readWS(ctx, conn, reads)
+	}()
+	go func() {
+		defer cancel()
+		keepaliveWS(ctx, conn, wsPingInterval)
+	}()
+
+	for {
+		select {
+		case event := <-ch:
+			if !filter.matches(event) {
+				continue
+			}
+			if err := writeWS(ctx, conn, event); err != nil {
+				return nil
+			}
+		case r := <-reads:
+			if err := writeWS(ctx, conn, handleWSMessage(filter, r)); err != nil {
+				return nil
+			}
+		case <-ctx.Done():
+			_ = conn.Close(websocket.StatusNormalClosure, "")
+			return nil
+		}
+	}
+}
+
+// handleWSMessage applies a client message and returns the reply.
+func handleWSMessage(filter *wsFilter, r wsRead) any {
+	if r.err != nil {
+		return wsErrorMessage{Type: wsMessageError, Error: "invalid message: " + r.err.Error()}
+	}
+	switch r.msg.Type {
+	case wsMessageSubscribe, wsMessageUnsubscribe:
+		if err := filter.apply(r.msg); err != nil {
+			return wsErrorMessage{Type: wsMessageError, Error: err.Error()}
+		}
+		return filter.subscribed()
+	case wsMessagePing:
+		return wsTypeMessage{Type: wsMessagePong}
+	default:
+		return wsErrorMessage{Type: wsMessageError, Error: "unknown message type: " + r.msg.Type}
+	}
+}
+
+// readWS decodes client messages until the connection is closed. Reading also
+// processes control frames, so it must run for pings to receive pongs.
+func readWS(ctx context.Context, conn *websocket.Conn, reads chan<- wsRead) {
+	for {
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			return
+		}
+		var r wsRead
+		r.err = json.Unmarshal(data, &r.msg)
+		select {
+		case reads <- r:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// keepaliveWS pings the client every interval and returns when a ping is not
+// answered within the write timeout.
+func keepaliveWS(ctx context.Context, conn *websocket.Conn, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			pctx, cancel := context.WithTimeout(ctx, wsWriteTimeout)
+			err := conn.Ping(pctx)
+			cancel()
+			if err != nil {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func writeWS(ctx context.Context, conn *websocket.Conn, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	wctx, cancel := context.WithTimeout(ctx, wsWriteTimeout)
+	defer cancel()
+	return conn.Write(wctx, websocket.MessageText, b)
+}
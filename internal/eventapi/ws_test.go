@@ -0,0 +1,157 @@
+package eventapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/task"
+)
+
+// dial connects to the WebSocket endpoint and consumes the init message.
+func (f *fixture) dial(query string) *websocket.Conn {
+	f.t.Helper()
+	url := strings.Replace(fmt.Sprintf("%s/api/v1/ws%s", f.Server.Address(), query), "http", "ws", 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, _, err := websocket.Dial(ctx, url, nil)
+	require.NoError(f.t, err)
+	f.t.Cleanup(func() { _ = conn.CloseNow() })
+
+	msg := readMessage(f.t, conn)
+	require.Equal(f.t, "init", msg["type"])
+	return conn
+}
+
+func readMessage(t *testing.T, conn *websocket.Conn) map[string]any {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, data, err := conn.Read(ctx)
+	require.NoError(t, err)
+	var msg map[string]any
+	require.NoError(t, json.Unmarshal(data, &msg))
+	return msg
+}
+
+func sendMessage(t *testing.T, conn *websocket.Conn, msg any) {
+	t.Helper()
+	b, err := json.Marshal(msg)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, conn.Write(ctx, websocket.MessageText, b))
+}
+
+func TestWebSocket_StreamsEvents(t *testing.T) {
+	f := newFixture(t)
+	existing := f.createTask(f.Repo, "Existing task")
+
+	url := strings.Replace(f.Server.Address()+"/api/v1/ws", "http", "ws", 1)
+	conn, _, err := websocket.Dial(context.Background(), url, nil)
+	require.NoError(t, err)
+	defer conn.CloseNow()
+
+	initMsg := readMessage(t, conn)
+	assert.Equal(t, "init", initMsg["type"])
+	tasks := initMsg["tasks"].([]any)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, existing.ID.String(), tasks[0].(map[string]any)["id"])
+
+	created := f.createTask(f.Repo, "New task")
+	msg := readMessage(t, conn)
+	assert.Equal(t, task.EventTaskCreated, msg["type"])
+	assert.Equal(t, created.ID.String(), msg["task"].(map[string]any)["id"])
+}
+
+func TestWebSocket_RepoQueryFilter(t *testing.T) {
+	f := newFixture(t)
+	conn := f.dial("?repo_id=" + f.Repo.ID.String())
+
+	f.createTask(f.OtherRepo, "Other repo task")
+	created := f.createTask(f.Repo, "Repo task")
+
+	msg := readMessage(t, conn)
+	assert.Equal(t, created.ID.String(), msg["task"].(map[string]any)["id"], "expected other repo event to be filtered")
+}
+
+func TestWebSocket_SubscribeAndUnsubscribe(t *testing.T) {
+	f := newFixture(t)
+	conn := f.dial("")
+
+	sendMessage(t, conn, map[string]any{"type": "subscribe", "repo_ids": []string{f.OtherRepo.ID.String()}})
+	ack := readMessage(t, conn)
+	assert.Equal(t, "subscribed", ack["type"])
+	assert.Equal(t, []any{f.OtherRepo.ID.String()}, ack["repo_ids"])
+
+	f.createTask(f.Repo, "Filtered out")
+	other := f.createTask(f.OtherRepo, "Subscribed")
+	msg := readMessage(t, conn)
+	assert.Equal(t, other.ID.String(), msg["task"].(map[string]any)["id"])
+
+	// Subscribing to a single task streams its log events, which carry no repo.
+	tsk := f.createTask(f.Repo, "Log streaming")
+	sendMessage(t, conn, map[string]any{"type": "subscribe", "task_ids": []string{tsk.ID.String()}})
+	ack = readMessage(t, conn)
+	assert.Equal(t, []any{tsk.ID.String()}, ack["task_ids"])
+
+	require.NoError(t, f.TaskStore.AppendTaskLogs(context.Background(), tsk.ID, 1, []string{"hello"}))
+	msg = readMessage(t, conn)
+	assert.Equal(t, task.EventLogsAppended, msg["type"])
+	assert.Equal(t, tsk.ID.String(), msg["task_id"])
+
+	sendMessage(t, conn, map[string]any{"type": "unsubscribe", "repo_ids": []string{f.OtherRepo.ID.String()}, "task_ids": []string{tsk.ID.String()}})
+	ack = readMessage(t, conn)
+	assert.Empty(t, ack["repo_ids"])
+	assert.Empty(t, ack["task_ids"])
+
+	// An empty filter streams every event again.
+	created := f.createTask(f.Repo, "Unfiltered")
+	msg = readMessage(t, conn)
+	assert.Equal(t, created.ID.String(), msg["task"].(map[string]any)["id"])
+}
+
+func TestWebSocket_Ping(t *testing.T) {
+	f := newFixture(t)
+	conn := f.dial("")
+
+	sendMessage(t, conn, map[string]any{"type": "ping"})
+	assert.Equal(t, "pong", readMessage(t, conn)["type"])
+
+	// Protocol-level pings are answered while the client is reading.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	readCtx := conn.CloseRead(ctx)
+	require.NoError(t, conn.Ping(readCtx))
+}
+
+func TestWebSocket_InvalidMessages(t *testing.T) {
+	f := newFixture(t)
+	conn := f.dial("")
+
+	tests := []any{
+		"not json",
+		map[string]any{"type": "dance"},
+		map[string]any{"type": "subscribe", "repo_ids": []string{"nope"}},
+		map[string]any{"type": "subscribe", "task_ids": []string{"nope"}},
+	}
+	for _, msg := range tests {
+		if s, ok := msg.(string); ok {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			require.NoError(t, conn.Write(ctx, websocket.MessageText, []byte(s)))
+			cancel()
+		} else {
+			sendMessage(t, conn, msg)
+		}
+		reply := readMessage(t, conn)
+		assert.Equal(t, "error", reply["type"])
+		assert.NotEmpty(t, reply["error"])
+	}
+}
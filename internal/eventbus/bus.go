@@ -0,0 +1,219 @@
+// Package eventbus shares task events between API replicas through the
+// database they already share, so SSE and WebSocket clients connected to any
+// replica receive every replica's events, and work made claimable on one
+// replica wakes the polls waiting on all of them.
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/joshjon/kit/log"
+
+	"github.com/vervesh/verve/internal/conversation"
+	"github.com/vervesh/verve/internal/epic"
+	"github.com/vervesh/verve/internal/task"
+)
+
+const (
+	// DefaultPollInterval is how often a replica checks for events published
+	// by the others, and so roughly the extra latency of those events.
+	DefaultPollInterval = 250 * time.Millisecond
+	// DefaultRetention is how long events are kept on the bus. A replica
+	// that can't poll for longer, such as during a database outage, misses
+	// the events in between, like an SSE client whose replay buffer ran out.
+	DefaultRetention = 5 * time.Minute
+
+	pollBatchSize = 500
+	pruneInterval = time.Minute
+
+	// pendingType marks the bus messages that wake other replicas' polls for
+	// pending work. They aren't task events, so they never reach a broker.
+	pendingType = "tasks_pending"
+)
+
+var (
+	_ task.Notifier                = (*Bus)(nil)
+	_ task.PendingNotifier         = (*Bus)(nil)
+	_ epic.PendingNotifier         = (*Bus)(nil)
+	_ conversation.PendingNotifier = (*Bus)(nil)
+)
+
+// PendingWaker wakes a replica's polls waiting for pending work.
+// *task.Store, *epic.Store and *conversation.Store implement it.
+type PendingWaker interface {
+	WakePending()
+}
+
+// PendingWakers wakes the polls of every waker in it. Polls wait for tasks,
+// epics and conversations, so a replica passes all three stores.
+type PendingWakers []PendingWaker
+
+// WakePending implements PendingWaker.
+func (ws PendingWakers) WakePending() {
+	for _, w := range ws {
+		w.WakePending()
+	}
+}
+
+// Option configures a Bus.
+type Option func(*Bus)
+
+// WithPollInterval overrides how often the bus polls for other replicas'
+// events.
+func WithPollInterval(d time.Duration) Option {
+	return func(b *Bus) {
+		b.pollInterval = d
+	}
+}
+
+// WithRetention overrides how long events are kept on the bus.
+func WithRetention(d time.Duration) Option {
+	return func(b *Bus) {
+		b.retention = d
+	}
+}
+
+// Bus is a task.Notifier that passes events between replicas through a
+// database table. Every replica appends the events its broker publishes and
+// polls for the ones appended by the others, which it hands to its broker
+// with Receive. A replica's own events are fanned out locally by its broker
+// and skipped when polled.
+type Bus struct {
+	repo         Repository
+	origin       string
+	pollInterval time.Duration
+	retention    time.Duration
+	logger       log.Logger
+}
+
+// New creates a Bus that publishes on behalf of origin, which must be unique
+// per replica.
+func New(repo Repository, origin string, logger log.Logger, opts ...Option) *Bus {
+	b := &Bus{
+		repo:         repo,
+		origin:       origin,
+		pollInterval: DefaultPollInterval,
+		retention:    DefaultRetention,
+		logger:       logger.With("component", "event_bus", "event_bus.origin", origin),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Notify appends an event published by this replica to the bus.
+func (b *Bus) Notify(ctx context.Context, payload []byte) error {
+	if err := b.repo.AppendBusEvent(ctx, b.origin, payload); err != nil {
+		b.logger.Error("failed to publish event to bus", "error", err)
+		return err
+	}
+	return nil
+}
+
+// NotifyPending tells the other replicas that tasks, epics or conversations
+// may have become claimable.
+func (b *Bus) NotifyPending(ctx context.Context) error {
+	return b.Notify(ctx, []byte(`{"type":"`+pendingType+`"}`))
+}
+
+// Start delivers the events other replicas publish from now on to broker,
+// and their pending notifications to waker, until ctx is cancelled or the
+// returned stop function is called. Stop blocks until polling has stopped.
+func (b *Bus) Start(ctx context.Context, broker *task.Broker, waker PendingWaker) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	// Only events published after startup are delivered; clients load the
+	// current state when they connect.
+	lastID, err := b.repo.LatestBusEventID(ctx)
+	if err != nil {
+		b.logger.Error("failed to read latest bus event", "error", err)
+	}
+	go func() {
+		defer close(done)
+		b.run(ctx, broker, waker, lastID)
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+func (b *Bus) run(ctx context.Context, broker *task.Broker, waker PendingWaker, lastID int64) {
+	ticker := time.NewTicker(b.pollInterval)
+	defer ticker.Stop()
+	lastPrune := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			lastID = b.poll(ctx, broker, waker, lastID)
+			if time.Since(lastPrune) >= pruneInterval {
+				lastPrune = time.Now()
+				b.prune(ctx)
+			}
+		}
+	}
+}
+
+// poll delivers the events appended after lastID by other replicas and
+// returns the ID of the last event read. Pending notifications read in the
+// same poll wake the waiting polls once.
+func (b *Bus) poll(ctx context.Context, broker *task.Broker, waker PendingWaker, lastID int64) int64 {
+	var pending bool
+	defer func() {
+		if pending {
+			waker.WakePending()
+		}
+	}()
+
+	for {
+		msgs, err := b.repo.ListBusEventsAfter(ctx, lastID, pollBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				b.logger.Error("failed to poll event bus", "error", err)
+			}
+			return lastID
+		}
+		for _, m := range msgs {
+			lastID = m.ID
+			if m.Origin == b.origin {
+				continue
+			}
+			var ev task.Event
+			if err := json.Unmarshal(m.Payload, &ev); err != nil {
+				b.logger.Warn("skipping malformed bus event", "event_bus.id", m.ID, "error", err)
+				continue
+			}
+			if ev.Type == pendingType {
+				pending = true
+				continue
+			}
+			broker.Receive(ev)
+		}
+		if len(msgs) < pollBatchSize {
+			return lastID
+		}
+	}
+}
+
+// prune deletes events older than the retention. Every replica prunes, which
+// is harmless as deletes of the same rows don't conflict.
+func (b *Bus) prune(ctx context.Context) {
+	n, err := b.repo.DeleteBusEventsBefore(ctx, time.Now().Add(-b.retention))
+	if err != nil {
+		if ctx.Err() == nil {
+			b.logger.Error("failed to prune event bus", "error", err)
+		}
+		return
+	}
+	if n > 0 {
+		b.logger.Debug("pruned event bus", "count", n)
+	}
+}
@@ -0,0 +1,180 @@
+package eventbus_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/joshjon/kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/conversation"
+	"github.com/vervesh/verve/internal/epic"
+	"github.com/vervesh/verve/internal/eventbus"
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/sqlite"
+	"github.com/vervesh/verve/internal/task"
+)
+
+func TestBus_SharesEventsBetweenReplicas(t *testing.T) {
+	db := sqlite.NewTestDB(t)
+	repo := sqlite.NewEventBusRepository(db)
+	logger := log.NewLogger(log.WithNop())
+	ctx := context.Background()
+
+	busA := eventbus.New(repo, "replica-a", logger, eventbus.WithPollInterval(10*time.Millisecond))
+	busB := eventbus.New(repo, "replica-b", logger, eventbus.WithPollInterval(10*time.Millisecond))
+	brokerA := task.NewBroker(busA)
+	brokerB := task.NewBroker(busB)
+	defer busA.Start(ctx, brokerA, nopWaker{})()
+	defer busB.Start(ctx, brokerB, nopWaker{})()
+
+	chA := brokerA.Subscribe()
+	defer brokerA.Unsubscribe(chA)
+	chB := brokerB.Subscribe()
+	defer brokerB.Unsubscribe(chB)
+
+	id := task.NewTaskID()
+	brokerA.Publish(ctx, task.Event{Type: task.EventLogsAppended, TaskID: id, Attempt: 1, Logs: []string{"hello"}})
+
+	select {
+	case ev := <-chA:
+		assert.False(t, ev.Remote, "the publishing replica fans out locally")
+	case <-time.After(time.Second):
+		require.Fail(t, "timed out waiting for local event")
+	}
+	select {
+	case ev := <-chB:
+		assert.True(t, ev.Remote)
+		assert.Equal(t, task.EventLogsAppended, ev.Type)
+		assert.Equal(t, id, ev.TaskID)
+		assert.Equal(t, []string{"hello"}, ev.Logs)
+	case <-time.After(time.Second):
+		require.Fail(t, "timed out waiting for event from the other replica")
+	}
+
+	// The publishing replica skips its own event on the bus.
+	select {
+	case ev := <-chA:
+		assert.Fail(t, "unexpected duplicate event", ev.Type)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestBus_SharesPendingNotifications(t *testing.T) {
+	db := sqlite.NewTestDB(t)
+	repo := sqlite.NewEventBusRepository(db)
+	taskRepo := sqlite.NewTaskRepository(db)
+	logger := log.NewLogger(log.WithNop())
+	ctx := context.Background()
+
+	busA := eventbus.New(repo, "replica-a", logger, eventbus.WithPollInterval(10*time.Millisecond))
+	busB := eventbus.New(repo, "replica-b", logger, eventbus.WithPollInterval(10*time.Millisecond))
+	brokerA := task.NewBroker(busA)
+	brokerB := task.NewBroker(busB)
+	storeA := task.NewStore(taskRepo, brokerA)
+	storeB := task.NewStore(taskRepo, brokerB)
+	storeA.SetPendingNotifier(busA)
+	storeB.SetPendingNotifier(busB)
+	defer busA.Start(ctx, brokerA, storeA)()
+	defer busB.Start(ctx, brokerB, storeB)()
+
+	chB := brokerB.Subscribe()
+	defer brokerB.Unsubscribe(chB)
+
+	storeA.NotifyPending()
+
+	select {
+	case <-storeA.WaitForPending():
+	case <-time.After(time.Second):
+		require.Fail(t, "timed out waiting for the local wakeup")
+	}
+	select {
+	case <-storeB.WaitForPending():
+	case <-time.After(time.Second):
+		require.Fail(t, "timed out waiting for the wakeup from the other replica")
+	}
+
+	// The notifying replica skips its own notification on the bus, and the
+	// notification isn't a task event.
+	select {
+	case <-storeA.WaitForPending():
+		assert.Fail(t, "unexpected duplicate wakeup")
+	case ev := <-chB:
+		assert.Fail(t, "unexpected event", ev.Type)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestBus_SharesPendingEpicsAndConversations(t *testing.T) {
+	db := sqlite.NewTestDB(t)
+	busRepo := sqlite.NewEventBusRepository(db)
+	logger := log.NewLogger(log.WithNop())
+	ctx := context.Background()
+	r, err := repo.NewRepo("owner/name")
+	require.NoError(t, err)
+	require.NoError(t, repo.NewStore(sqlite.NewRepoRepository(db)).CreateRepo(ctx, r))
+
+	busA := eventbus.New(busRepo, "replica-a", logger, eventbus.WithPollInterval(10*time.Millisecond))
+	busB := eventbus.New(busRepo, "replica-b", logger, eventbus.WithPollInterval(10*time.Millisecond))
+	epicsA := epic.NewStore(sqlite.NewEpicRepository(db), nil, logger)
+	epicsA.SetPendingNotifier(busA)
+	convsA := conversation.NewStore(sqlite.NewConversationRepository(db), logger)
+	convsA.SetPendingNotifier(busA)
+	tasksB := task.NewStore(sqlite.NewTaskRepository(db), task.NewBroker(busB))
+	epicsB := epic.NewStore(sqlite.NewEpicRepository(db), nil, logger)
+	convsB := conversation.NewStore(sqlite.NewConversationRepository(db), logger)
+	defer busA.Start(ctx, task.NewBroker(busA), eventbus.PendingWakers{epicsA, convsA})()
+	defer busB.Start(ctx, task.NewBroker(busB), eventbus.PendingWakers{tasksB, epicsB, convsB})()
+
+	waitWoken := func(ch <-chan struct{}, what string) {
+		t.Helper()
+		select {
+		case <-ch:
+		case <-time.After(time.Second):
+			require.Fail(t, "timed out waiting for the other replica to wake "+what)
+		}
+	}
+
+	// A new epic on one replica wakes every kind of poll on the other.
+	require.NoError(t, epicsA.CreateEpic(ctx, epic.NewEpic(r.ID.String(), "title", "desc")))
+	waitWoken(epicsB.WaitForPending(), "epic polls")
+	waitWoken(convsB.WaitForPending(), "conversation polls")
+	waitWoken(tasksB.WaitForPending(), "task polls")
+
+	// So does a message waiting for an agent.
+	conv := conversation.NewConversation(r.ID.String(), "title", "")
+	require.NoError(t, convsA.CreateConversation(ctx, conv))
+	require.NoError(t, convsA.SendMessage(ctx, conv.ID, "hello"))
+	waitWoken(convsB.WaitForPending(), "conversation polls")
+}
+
+func TestBus_StartsAfterExistingEvents(t *testing.T) {
+	db := sqlite.NewTestDB(t)
+	repo := sqlite.NewEventBusRepository(db)
+	logger := log.NewLogger(log.WithNop())
+	ctx := context.Background()
+
+	require.NoError(t, repo.AppendBusEvent(ctx, "replica-a", []byte(`{"type":"task_deleted"}`)))
+
+	bus := eventbus.New(repo, "replica-b", logger, eventbus.WithPollInterval(10*time.Millisecond))
+	broker := task.NewBroker(bus)
+	ch := broker.Subscribe()
+	defer broker.Unsubscribe(ch)
+	defer bus.Start(ctx, broker, nopWaker{})()
+
+	select {
+	case ev := <-ch:
+		assert.Fail(t, "events from before startup are not replayed", ev.Type)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	n, err := repo.DeleteBusEventsBefore(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+}
+
+type nopWaker struct{}
+
+func (nopWaker) WakePending() {}
@@ -0,0 +1,29 @@
+package eventbus
+
+import (
+	"context"
+	"time"
+)
+
+// Message is an event payload stored on the bus.
+type Message struct {
+	ID      int64
+	Origin  string // The replica that published the event
+	Payload []byte
+}
+
+// Repository is the interface for the shared table the bus passes events
+// through.
+type Repository interface {
+	// AppendBusEvent stores a payload published by origin.
+	AppendBusEvent(ctx context.Context, origin string, payload []byte) error
+	// ListBusEventsAfter returns up to limit messages with an ID greater
+	// than afterID, oldest first.
+	ListBusEventsAfter(ctx context.Context, afterID int64, limit int) ([]*Message, error)
+	// LatestBusEventID returns the ID of the newest message, or zero when
+	// there are none.
+	LatestBusEventID(ctx context.Context) (int64, error)
+	// DeleteBusEventsBefore deletes the messages stored before the given
+	// time and returns how many were deleted.
+	DeleteBusEventsBefore(ctx context.Context, before time.Time) (int64, error)
+}
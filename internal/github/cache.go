@@ -0,0 +1,170 @@
+package github
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// cacheTTL is how long a cached response is reused by background
+	// requests without asking GitHub. It spans a couple of PR sync cycles at
+	// the default interval, so unchanged PRs aren't refetched every cycle.
+	cacheTTL = time.Minute
+	// maxCachedResponses caps the responses kept in the cache.
+	maxCachedResponses = 1000
+	// maxCachedBodyBytes is the largest response body kept in the cache;
+	// larger responses, such as big diffs, are fetched in full every time.
+	maxCachedBodyBytes = 1 << 20
+)
+
+// responseCache keeps GET responses that carry an ETag, keyed by the
+// request's URL, and so by owner, repo, PR and endpoint. Fresh entries are
+// reused as is by background requests; older ones are revalidated with a
+// conditional request.
+type responseCache struct {
+	now func() time.Time
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cachedResponse
+}
+
+// cachedResponse is a response replayed from the cache.
+type cachedResponse struct {
+	etag      string
+	header    http.Header
+	body      []byte
+	checkedAt time.Time // When GitHub last confirmed the response
+}
+
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{now: time.Now, ttl: ttl, entries: map[string]cachedResponse{}}
+}
+
+// get returns the cached response for key. The cache may be nil.
+func (rc *responseCache) get(key string) (cachedResponse, bool) {
+	if rc == nil || key == "" {
+		return cachedResponse{}, false
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	entry, ok := rc.entries[key]
+	return entry, ok
+}
+
+// fresh reports whether entry may be reused without revalidating it.
+func (rc *responseCache) fresh(entry cachedResponse) bool {
+	return rc.now().Sub(entry.checkedAt) < rc.ttl
+}
+
+// revalidated records that GitHub confirmed the response for key is
+// unchanged.
+func (rc *responseCache) revalidated(key string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if entry, ok := rc.entries[key]; ok {
+		entry.checkedAt = rc.now()
+		rc.entries[key] = entry
+	}
+}
+
+// store caches resp. Its body is read and replaced so the caller can still
+// read it.
+func (rc *responseCache) store(key string, resp *http.Response) error {
+	if rc == nil {
+		return nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedBodyBytes+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return err
+	}
+	if len(body) > maxCachedBodyBytes {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return nil
+	}
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if _, ok := rc.entries[key]; !ok && len(rc.entries) >= maxCachedResponses {
+		// Evict an arbitrary entry; it is only refetched in full next time.
+		for k := range rc.entries {
+			delete(rc.entries, k)
+			break
+		}
+	}
+	rc.entries[key] = cachedResponse{
+		etag:      resp.Header.Get("ETag"),
+		header:    resp.Header.Clone(),
+		body:      body,
+		checkedAt: rc.now(),
+	}
+	return nil
+}
+
+// invalidateRepo drops the cached responses of the repo u belongs to.
+func (rc *responseCache) invalidateRepo(u *url.URL) {
+	if rc == nil {
+		return
+	}
+	base := repoURL(u)
+	if base == "" {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for k := range rc.entries {
+		_, keyURL, _ := strings.Cut(k, " ")
+		if rest, ok := strings.CutPrefix(keyURL, base); ok && (rest == "" || rest[0] == '/' || rest[0] == '?') {
+			delete(rc.entries, k)
+		}
+	}
+}
+
+// repoURL returns the API URL of the repo u belongs to, such as
+// https://api.github.com/repos/owner/name, or "" when u isn't a repo URL.
+func repoURL(u *url.URL) string {
+	rest, ok := strings.CutPrefix(u.Path, "/repos/")
+	if !ok {
+		return ""
+	}
+	parts := strings.SplitN(rest, "/", 3)
+	if len(parts) < 2 {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host + "/repos/" + parts[0] + "/" + parts[1]
+}
+
+func (r cachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(r.body)),
+		ContentLength: int64(len(r.body)),
+		Request:       req,
+	}
+}
+
+// cacheKey identifies a GET request's response in the cache: its Accept
+// header, as the same URL returns JSON or a diff, and its URL. It is empty
+// for other methods.
+func cacheKey(req *http.Request) string {
+	if req.Method != http.MethodGet {
+		return ""
+	}
+	return req.Header.Get("Accept") + " " + req.URL.String()
+}
@@ -0,0 +1,70 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ResponseCache(t *testing.T) {
+	var hits, notModified atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if r.Method != http.MethodGet {
+			_, _ = fmt.Fprint(w, `{}`)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = fmt.Fprint(w, `{"default_branch":"main"}`)
+	}))
+	defer srv.Close()
+	c := newRateLimitedTestClient(srv)
+	now := time.Now()
+	c.cache.now = func() time.Time { return now }
+	bg := Background(context.Background())
+
+	for range 3 {
+		branch, err := c.GetDefaultBranch(bg, "owner", "repo")
+		require.NoError(t, err)
+		assert.Equal(t, "main", branch)
+	}
+	assert.Equal(t, int32(1), hits.Load(), "background requests reuse a fresh response")
+
+	_, err := c.GetDefaultBranch(context.Background(), "owner", "repo")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), notModified.Load(), "urgent requests always revalidate")
+
+	now = now.Add(cacheTTL)
+	_, err = c.GetDefaultBranch(bg, "owner", "repo")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), notModified.Load(), "background requests revalidate a stale response")
+	_, err = c.GetDefaultBranch(bg, "owner", "repo")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), hits.Load(), "a revalidated response is fresh again")
+
+	_, err = c.ClosePR(context.Background(), "owner", "repo", 1)
+	require.NoError(t, err)
+	_, err = c.GetDefaultBranch(bg, "owner", "repo")
+	require.NoError(t, err)
+	assert.Equal(t, int32(5), hits.Load())
+	assert.Equal(t, int32(2), notModified.Load(), "a write drops the repo's cached responses")
+}
+
+func TestRepoURL(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPatch, "https://api.github.com/repos/owner/repo/pulls/1", nil)
+	assert.Equal(t, "https://api.github.com/repos/owner/repo", repoURL(req.URL))
+	req = httptest.NewRequest(http.MethodPost, "https://api.github.com/graphql", nil)
+	assert.Empty(t, repoURL(req.URL))
+}
@@ -7,7 +7,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"slices"
 	"strings"
+	"time"
 )
 
 // GitHubRepo represents a repository returned by the GitHub API.
@@ -24,6 +27,8 @@ type GitHubRepo struct {
 type Client struct {
 	token      string
 	httpClient *http.Client
+	limits     *rateLimiter   // nil disables rate limiting and the response cache
+	cache      *responseCache // nil disables the response cache
 }
 
 // NewClient creates a new GitHub API client.
@@ -45,6 +50,8 @@ func NewClient(token string, insecureSkipVerify bool) *Client {
 	return &Client{
 		token:      token,
 		httpClient: httpClient,
+		limits:     newRateLimiter(),
+		cache:      newResponseCache(cacheTTL),
 	}
 }
 
@@ -58,7 +65,7 @@ func (c *Client) ListAccessibleRepos(ctx context.Context) ([]*GitHubRepo, error)
 	}
 	c.setHeaders(req)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -106,7 +113,7 @@ func (c *Client) IsPRMerged(ctx context.Context, owner, repo string, prNumber in
 	}
 	c.setHeaders(req)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return false, err
 	}
@@ -139,9 +146,13 @@ const (
 // IndividualCheck represents a single CI check with its status and link.
 type IndividualCheck struct {
 	Name       string `json:"name"`
-	Status     string `json:"status"`     // "queued", "in_progress", "completed", "pending", "success", "failure", "error"
-	Conclusion string `json:"conclusion"` // "success", "failure", "neutral", "cancelled", "skipped", "timed_out", ""
-	URL        string `json:"url"`        // Link to the check on GitHub
+	Status     string `json:"status"`            // "queued", "in_progress", "completed", "pending", "success", "failure", "error"
+	Conclusion string `json:"conclusion"`        // "success", "failure", "neutral", "cancelled", "skipped", "timed_out", ""
+	URL        string `json:"url"`               // Link to the check on GitHub
+	Ignored    bool   `json:"ignored,omitempty"` // Listed in the repo's ignored checks, so it doesn't affect the status
+	// Informational is set for checks that aren't required while only
+	// required checks count, so they don't affect the status either.
+	Informational bool `json:"informational,omitempty"`
 }
 
 // CheckResult holds the result of a PR check query.
@@ -151,15 +162,100 @@ type CheckResult struct {
 	FailedRunIDs     []int64 // GitHub Actions job IDs for failed check runs
 	FailedNames      []string
 	CheckRunsSkipped bool              // True when check runs API returned 403 (fine-grained PAT)
-	Checks          []IndividualCheck // Individual check details
+	Checks           []IndividualCheck // Individual check details
+}
+
+// CheckPolicy selects which of a PR's checks count towards its check status.
+// Checks that don't count are still listed, marked as ignored or
+// informational.
+type CheckPolicy struct {
+	// Ignored names checks that never count, such as known-flaky ones.
+	Ignored []string
+	// RequiredOnly counts only required checks.
+	RequiredOnly bool
+	// Required names the required checks when RequiredOnly is set. Empty
+	// uses the status checks required to merge into the PR's base branch.
+	// When the base branch requires none either, every check counts.
+	Required []string
+}
+
+// ignores reports whether the check named name is in the ignored list.
+func (p CheckPolicy) ignores(name string) bool {
+	return slices.Contains(p.Ignored, name)
+}
+
+// informational reports whether the check named name doesn't count because
+// it isn't required.
+func (p CheckPolicy) informational(name string) bool {
+	return p.RequiredOnly && len(p.Required) > 0 && !slices.Contains(p.Required, name)
+}
+
+// resolveCheckPolicy fills in the required checks of policy from the branch
+// protection and rulesets of base when only required checks count and none
+// are configured.
+func (c *Client) resolveCheckPolicy(ctx context.Context, owner, repo, base string, policy CheckPolicy) (CheckPolicy, error) {
+	if !policy.RequiredOnly || len(policy.Required) > 0 {
+		return policy, nil
+	}
+	required, err := c.GetRequiredChecks(ctx, owner, repo, base)
+	if err != nil {
+		return policy, fmt.Errorf("get required checks of %s: %w", base, err)
+	}
+	policy.Required = required
+	return policy, nil
+}
+
+// GetRequiredChecks returns the names of the status checks required to merge
+// into branch, from both its branch protection and the repo's rulesets.
+// Protection the token can't read counts as requiring no checks.
+func (c *Client) GetRequiredChecks(ctx context.Context, owner, repo, branch string) ([]string, error) {
+	// The branch summarises its protection, which unlike the protection
+	// endpoint doesn't need admin access to read.
+	var b struct {
+		Protection struct {
+			RequiredStatusChecks struct {
+				Contexts []string `json:"contexts"`
+			} `json:"required_status_checks"`
+		} `json:"protection"`
+	}
+	branchURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/branches/%s", owner, repo, url.PathEscape(branch))
+	if _, err := c.getOptionalJSON(ctx, branchURL, &b); err != nil {
+		return nil, err
+	}
+	required := b.Protection.RequiredStatusChecks.Contexts
+
+	var rules []struct {
+		Type       string `json:"type"`
+		Parameters struct {
+			RequiredStatusChecks []struct {
+				Context string `json:"context"`
+			} `json:"required_status_checks"`
+		} `json:"parameters"`
+	}
+	rulesURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/rules/branches/%s", owner, repo, url.PathEscape(branch))
+	if _, err := c.getOptionalJSON(ctx, rulesURL, &rules); err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if rule.Type != "required_status_checks" {
+			continue
+		}
+		for _, check := range rule.Parameters.RequiredStatusChecks {
+			required = append(required, check.Context)
+		}
+	}
+
+	slices.Sort(required)
+	return slices.Compact(required), nil
 }
 
 // GetPRCheckStatus returns the combined check status for a PR's head commit.
 // It checks both GitHub Actions (check runs) and legacy commit statuses.
 // The check runs endpoint requires the "Checks" permission which is not
 // available on fine-grained PATs, so a 403 is handled gracefully by falling
-// back to commit statuses only.
-func (c *Client) GetPRCheckStatus(ctx context.Context, owner, repo string, prNumber int) (*CheckResult, error) {
+// back to commit statuses only. Checks that don't count under policy are
+// listed but don't affect the status.
+func (c *Client) GetPRCheckStatus(ctx context.Context, owner, repo string, prNumber int, policy CheckPolicy) (*CheckResult, error) {
 	// Step 1: Get the PR to find the head SHA.
 	prURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d", owner, repo, prNumber)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, prURL, http.NoBody)
@@ -168,7 +264,7 @@ func (c *Client) GetPRCheckStatus(ctx context.Context, owner, repo string, prNum
 	}
 	c.setHeaders(req)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -182,23 +278,23 @@ func (c *Client) GetPRCheckStatus(ctx context.Context, owner, repo string, prNum
 		Head struct {
 			SHA string `json:"sha"`
 		} `json:"head"`
+		Base struct {
+			Ref string `json:"ref"`
+		} `json:"base"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
 		return nil, err
 	}
 	headSHA := pr.Head.SHA
+	policy, err = c.resolveCheckPolicy(ctx, owner, repo, pr.Base.Ref, policy)
+	if err != nil {
+		return nil, err
+	}
 
 	// Step 2: Get check runs for the head SHA (GitHub Actions).
 	// This endpoint requires the "Checks" permission which is NOT available
 	// on fine-grained PATs (only GitHub Apps). A 403 is expected and handled
 	// by skipping check runs and relying on commit statuses only.
-	type checkRun struct {
-		ID         int64   `json:"id"`
-		Name       string  `json:"name"`
-		Status     string  `json:"status"`
-		Conclusion *string `json:"conclusion"`
-		HTMLURL    string  `json:"html_url"`
-	}
 	var checkRuns []checkRun
 	var checkRunsSkipped bool
 
@@ -209,7 +305,7 @@ func (c *Client) GetPRCheckStatus(ctx context.Context, owner, repo string, prNum
 	}
 	c.setHeaders(req)
 
-	resp, err = c.httpClient.Do(req)
+	resp, err = c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -237,7 +333,7 @@ func (c *Client) GetPRCheckStatus(ctx context.Context, owner, repo string, prNum
 	}
 	c.setHeaders(req)
 
-	resp, err = c.httpClient.Do(req)
+	resp, err = c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -248,19 +344,39 @@ func (c *Client) GetPRCheckStatus(ctx context.Context, owner, repo string, prNum
 	}
 
 	var commitStatus struct {
-		State    string `json:"state"` // "success", "failure", "pending"
-		Statuses []struct {
-			Context   string `json:"context"`
-			State     string `json:"state"`
-			TargetURL string `json:"target_url"`
-		} `json:"statuses"`
+		State    string          `json:"state"` // "success", "failure", "pending"
+		Statuses []commitContext `json:"statuses"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&commitStatus); err != nil {
 		return nil, err
 	}
 
+	return buildCheckResult(checkRuns, commitStatus.Statuses, checkRunsSkipped, policy), nil
+}
+
+// checkRun is a check run on a PR's head commit, such as a GitHub Actions job.
+type checkRun struct {
+	ID         int64   `json:"id"`
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`
+	Conclusion *string `json:"conclusion"`
+	HTMLURL    string  `json:"html_url"`
+}
+
+// commitContext is a legacy commit status on a PR's head commit.
+type commitContext struct {
+	Context   string `json:"context"`
+	State     string `json:"state"`
+	TargetURL string `json:"target_url"`
+}
+
+// buildCheckResult combines the check runs and commit statuses of a PR's head
+// commit into its check result. Checks that don't count under policy are
+// marked as such and left out of the status. Its required checks must
+// already be resolved.
+func buildCheckResult(checkRuns []checkRun, statuses []commitContext, checkRunsSkipped bool, policy CheckPolicy) *CheckResult {
 	// Build individual check details.
-	checks := make([]IndividualCheck, 0, len(checkRuns)+len(commitStatus.Statuses))
+	checks := make([]IndividualCheck, 0, len(checkRuns)+len(statuses))
 
 	for _, run := range checkRuns {
 		conclusion := ""
@@ -272,15 +388,20 @@ func (c *Client) GetPRCheckStatus(ctx context.Context, owner, repo string, prNum
 			Status:     run.Status,
 			Conclusion: conclusion,
 			URL:        run.HTMLURL,
+			Ignored:    policy.ignores(run.Name),
+			// Ignoring a check takes precedence over it not being required.
+			Informational: !policy.ignores(run.Name) && policy.informational(run.Name),
 		})
 	}
 
-	for _, s := range commitStatus.Statuses {
+	for _, s := range statuses {
 		checks = append(checks, IndividualCheck{
-			Name:       s.Context,
-			Status:     "completed",
-			Conclusion: s.State,
-			URL:        s.TargetURL,
+			Name:          s.Context,
+			Status:        "completed",
+			Conclusion:    s.State,
+			URL:           s.TargetURL,
+			Ignored:       policy.ignores(s.Context),
+			Informational: !policy.ignores(s.Context) && policy.informational(s.Context),
 		})
 	}
 
@@ -290,6 +411,9 @@ func (c *Client) GetPRCheckStatus(ctx context.Context, owner, repo string, prNum
 	hasPending := false
 
 	for _, run := range checkRuns {
+		if policy.ignores(run.Name) || policy.informational(run.Name) {
+			continue
+		}
 		if run.Status != "completed" {
 			hasPending = true
 			continue
@@ -300,7 +424,15 @@ func (c *Client) GetPRCheckStatus(ctx context.Context, owner, repo string, prNum
 		}
 	}
 
-	for _, s := range commitStatus.Statuses {
+	for _, s := range statuses {
+		if strings.HasPrefix(s.Context, VerveStatusContextPrefix) {
+			// Verve's own statuses gate merging on human review; they are
+			// not CI results the agent can fix.
+			continue
+		}
+		if policy.ignores(s.Context) || policy.informational(s.Context) {
+			continue
+		}
 		switch s.State {
 		case "pending":
 			hasPending = true
@@ -317,16 +449,16 @@ func (c *Client) GetPRCheckStatus(ctx context.Context, owner, repo string, prNum
 			FailedNames:      failedNames,
 			CheckRunsSkipped: checkRunsSkipped,
 			Checks:           checks,
-		}, nil
+		}
 	}
 	if hasPending {
-		return &CheckResult{Status: CheckStatusPending, CheckRunsSkipped: checkRunsSkipped, Checks: checks}, nil
+		return &CheckResult{Status: CheckStatusPending, CheckRunsSkipped: checkRunsSkipped, Checks: checks}
 	}
 
 	// If no check runs and no statuses exist, the repository has no CI configured.
 	// Treat as success since there are no checks to wait for.
 
-	return &CheckResult{Status: CheckStatusSuccess, CheckRunsSkipped: checkRunsSkipped, Checks: checks}, nil
+	return &CheckResult{Status: CheckStatusSuccess, CheckRunsSkipped: checkRunsSkipped, Checks: checks}
 }
 
 // PRMergeability holds the mergeability state of a PR.
@@ -334,6 +466,34 @@ type PRMergeability struct {
 	Mergeable      *bool  // nil = not yet computed by GitHub
 	MergeableState string // "clean", "dirty", "blocked", "behind", "unstable"
 	HasConflicts   bool   // true when mergeable_state == "dirty"
+	Behind         bool   // true when mergeable_state == "behind"
+}
+
+// BranchAction is what a PR's branch needs to catch up with its base.
+type BranchAction int
+
+const (
+	// BranchCurrent needs nothing: the branch isn't behind its base.
+	BranchCurrent BranchAction = iota
+	// BranchUpdate is for a branch behind its base, which GitHub can
+	// merge the base into.
+	BranchUpdate
+	// BranchResolveConflicts is for a branch that conflicts with its base.
+	// GitHub refuses to update it, so only the agent can.
+	BranchResolveConflicts
+)
+
+// BranchAction reports what the PR's branch needs to catch up with its
+// base.
+func (m *PRMergeability) BranchAction() BranchAction {
+	switch {
+	case m.HasConflicts:
+		return BranchResolveConflicts
+	case m.Behind:
+		return BranchUpdate
+	default:
+		return BranchCurrent
+	}
 }
 
 // GetPRMergeability checks whether a PR has merge conflicts.
@@ -346,7 +506,7 @@ func (c *Client) GetPRMergeability(ctx context.Context, owner, repo string, prNu
 	}
 	c.setHeaders(req)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -368,6 +528,7 @@ func (c *Client) GetPRMergeability(ctx context.Context, owner, repo string, prNu
 		Mergeable:      pr.Mergeable,
 		MergeableState: pr.MergeableState,
 		HasConflicts:   pr.MergeableState == "dirty",
+		Behind:         pr.MergeableState == "behind",
 	}, nil
 }
 
@@ -382,7 +543,7 @@ func (c *Client) getFailedStepName(ctx context.Context, owner, repoName string,
 	}
 	c.setHeaders(req)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return ""
 	}
@@ -465,8 +626,9 @@ func extractStepLogs(rawLog, stepName string) string {
 // GetFailedCheckLogs fetches the log output of failed check runs for a PR.
 // For each failed job it identifies the exact failed step and returns the last
 // 150 lines of that step's logs. Returns a combined, truncated string (~8KB max).
-func (c *Client) GetFailedCheckLogs(ctx context.Context, owner, repoName string, prNumber int) (string, error) {
-	checkResult, err := c.GetPRCheckStatus(ctx, owner, repoName, prNumber)
+// Checks that don't count under policy are skipped.
+func (c *Client) GetFailedCheckLogs(ctx context.Context, owner, repoName string, prNumber int, policy CheckPolicy) (string, error) {
+	checkResult, err := c.GetPRCheckStatus(ctx, owner, repoName, prNumber, policy)
 	if err != nil {
 		return "", fmt.Errorf("get check status: %w", err)
 	}
@@ -499,7 +661,7 @@ func (c *Client) GetFailedCheckLogs(ctx context.Context, owner, repoName string,
 		}
 		c.setHeaders(req)
 
-		resp, err := c.httpClient.Do(req)
+		resp, err := c.do(req)
 		if err != nil {
 			continue
 		}
@@ -539,6 +701,96 @@ func (c *Client) GetFailedCheckLogs(ctx context.Context, owner, repoName string,
 	return strings.Join(parts, "\n\n"), nil
 }
 
+// GetJobRunID returns the ID of the GitHub Actions workflow run a job belongs
+// to. Check runs created by GitHub Actions are jobs; it returns 0 for a check
+// run from another app, which has no job.
+func (c *Client) GetJobRunID(ctx context.Context, owner, repoName string, jobID int64) (int64, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/actions/jobs/%d", owner, repoName, jobID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return 0, err
+	}
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	var job struct {
+		RunID int64 `json:"run_id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		return 0, err
+	}
+	return job.RunID, nil
+}
+
+// RerunFailedJobs re-runs the failed jobs of a GitHub Actions workflow run,
+// and the jobs that depend on them. Returns a *RejectedError when GitHub
+// refuses, for example because the run is still in progress or too old.
+func (c *Client) RerunFailedJobs(ctx context.Context, owner, repoName string, runID int64) error {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/actions/runs/%d/rerun-failed-jobs", owner, repoName, runID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, http.NoBody)
+	if err != nil {
+		return err
+	}
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusCreated:
+		return nil
+	case http.StatusForbidden, http.StatusConflict, http.StatusUnprocessableEntity:
+		return newRejectedError(resp)
+	default:
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("GitHub API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+}
+
+// UpdatePRBranch merges the latest commits of a PR's base branch into its
+// head branch. GitHub updates the branch asynchronously. Returns a
+// *RejectedError when GitHub refuses, for example because the base can't be
+// merged in without conflicts.
+func (c *Client) UpdatePRBranch(ctx context.Context, owner, repoName string, prNumber int) error {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/update-branch", owner, repoName, prNumber)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, http.NoBody)
+	if err != nil {
+		return err
+	}
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return nil
+	case http.StatusForbidden, http.StatusConflict, http.StatusUnprocessableEntity:
+		return newRejectedError(resp)
+	default:
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("GitHub API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+}
+
 // maxDiffSize is the maximum number of bytes to read from a PR diff response
 // to avoid memory issues with very large diffs.
 const maxDiffSize = 5 * 1024 * 1024 // 5MB
@@ -556,7 +808,7 @@ func (c *Client) GetPRDiff(ctx context.Context, owner, repo string, prNumber int
 	req.Header.Set("Authorization", "Bearer "+c.token)
 	req.Header.Set("Accept", "application/vnd.github.v3.diff")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return "", err
 	}
@@ -574,6 +826,124 @@ func (c *Client) GetPRDiff(ctx context.Context, owner, repo string, prNumber int
 	return string(body), nil
 }
 
+// Merge methods accepted by MergePR.
+const (
+	MergeMethodMerge  = "merge"
+	MergeMethodSquash = "squash"
+	MergeMethodRebase = "rebase"
+)
+
+// MergeMethods lists the merge methods accepted by MergePR.
+var MergeMethods = []string{MergeMethodMerge, MergeMethodSquash, MergeMethodRebase}
+
+// RejectedError is returned when GitHub refuses to approve or merge a pull
+// request, for example because it has conflicts, its required checks or
+// reviews are missing, or the token's user opened it, to re-run a workflow
+// run, or to update a PR's branch. Message is GitHub's explanation.
+type RejectedError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("GitHub rejected the request (status %d): %s", e.StatusCode, e.Message)
+}
+
+// newRejectedError reads GitHub's error message from resp.
+func newRejectedError(resp *http.Response) *RejectedError {
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var body struct {
+		Message string `json:"message"`
+		Errors  []any  `json:"errors"`
+	}
+	message := strings.TrimSpace(string(respBody))
+	if json.Unmarshal(respBody, &body) == nil && body.Message != "" {
+		message = body.Message
+		// Review validation failures carry the reason as a string in errors.
+		for _, e := range body.Errors {
+			if s, ok := e.(string); ok {
+				message += ": " + s
+			}
+		}
+	}
+	return &RejectedError{StatusCode: resp.StatusCode, Message: message}
+}
+
+// ApprovePR submits an approving review on a pull request with an optional
+// body. GitHub does not let a user approve their own pull request.
+func (c *Client) ApprovePR(ctx context.Context, owner, repoName string, prNumber int, body string) error {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/reviews", owner, repoName, prNumber)
+	payload, err := json.Marshal(map[string]string{"event": "APPROVE", "body": body})
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(string(payload)))
+	if err != nil {
+		return err
+	}
+	c.setHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnprocessableEntity:
+		return newRejectedError(resp)
+	default:
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("GitHub API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+}
+
+// MergePR merges a pull request with the given merge method and returns the
+// SHA of the resulting commit. Returns a *RejectedError when GitHub refuses
+// to merge it.
+func (c *Client) MergePR(ctx context.Context, owner, repoName string, prNumber int, method string) (string, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/merge", owner, repoName, prNumber)
+	payload, err := json.Marshal(map[string]string{"merge_method": method})
+	if err != nil {
+		return "", fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, strings.NewReader(string(payload)))
+	if err != nil {
+		return "", err
+	}
+	c.setHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusMethodNotAllowed, http.StatusConflict, http.StatusUnprocessableEntity:
+		// Not mergeable, head changed, or the merge method is not allowed.
+		return "", newRejectedError(resp)
+	default:
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("GitHub API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var result struct {
+		SHA string `json:"sha"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+	return result.SHA, nil
+}
+
 // ClosePR closes an open pull request and returns the head branch name.
 func (c *Client) ClosePR(ctx context.Context, owner, repoName string, prNumber int) (headBranch string, err error) {
 	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d", owner, repoName, prNumber)
@@ -586,7 +956,7 @@ func (c *Client) ClosePR(ctx context.Context, owner, repoName string, prNumber i
 	c.setHeaders(req)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return "", err
 	}
@@ -617,7 +987,7 @@ func (c *Client) DeleteBranch(ctx context.Context, owner, repoName, branch strin
 	}
 	c.setHeaders(req)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return err
 	}
@@ -629,6 +999,34 @@ func (c *Client) DeleteBranch(ctx context.Context, owner, repoName, branch strin
 	return nil
 }
 
+// UpdatePRBase changes the branch a pull request targets.
+func (c *Client) UpdatePRBase(ctx context.Context, owner, repoName string, prNumber int, base string) error {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d", owner, repoName, prNumber)
+	payload, err := json.Marshal(map[string]string{"base": base})
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, strings.NewReader(string(payload)))
+	if err != nil {
+		return err
+	}
+	c.setHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("GitHub API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
 // UpdatePR updates the title and body of an existing pull request.
 func (c *Client) UpdatePR(ctx context.Context, owner, repoName string, prNumber int, title, body string) error {
 	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d", owner, repoName, prNumber)
@@ -649,7 +1047,7 @@ func (c *Client) UpdatePR(ctx context.Context, owner, repoName string, prNumber
 	c.setHeaders(req)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return err
 	}
@@ -674,7 +1072,7 @@ func (c *Client) FindPRForBranch(ctx context.Context, owner, repo, branch string
 	}
 	c.setHeaders(req)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return "", 0, err
 	}
@@ -698,6 +1096,893 @@ func (c *Client) FindPRForBranch(ctx context.Context, owner, repo, branch string
 	return prs[0].HTMLURL, prs[0].Number, nil
 }
 
+// GetDefaultBranch returns the name of a repository's default branch.
+func (c *Client) GetDefaultBranch(ctx context.Context, owner, repo string) (string, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s", owner, repo)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return "", err
+	}
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	var r struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return "", err
+	}
+	return r.DefaultBranch, nil
+}
+
+// CreatePR opens a pull request merging head into base. Returns the PR URL
+// and number.
+func (c *Client) CreatePR(ctx context.Context, owner, repo, head, base, title, body string) (string, int, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls", owner, repo)
+
+	payload := map[string]string{
+		"title": title,
+		"body":  body,
+		"head":  head,
+		"base":  base,
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return "", 0, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(string(payloadBytes)))
+	if err != nil {
+		return "", 0, err
+	}
+	c.setHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", 0, fmt.Errorf("GitHub API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var pr struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
+		return "", 0, err
+	}
+	return pr.HTMLURL, pr.Number, nil
+}
+
+// PRReview represents a review submitted on a pull request.
+type PRReview struct {
+	ID    int64
+	User  string
+	State string // "APPROVED", "CHANGES_REQUESTED", "COMMENTED", "DISMISSED", "PENDING"
+	Body  string
+}
+
+// ListPRReviews returns the reviews submitted on a PR in chronological order.
+func (c *Client) ListPRReviews(ctx context.Context, owner, repo string, prNumber int) ([]*PRReview, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/reviews?per_page=100", owner, repo, prNumber)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	var apiReviews []struct {
+		ID    int64  `json:"id"`
+		State string `json:"state"`
+		Body  string `json:"body"`
+		User  struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&apiReviews); err != nil {
+		return nil, err
+	}
+
+	reviews := make([]*PRReview, len(apiReviews))
+	for i, r := range apiReviews {
+		reviews[i] = &PRReview{
+			ID:    r.ID,
+			User:  r.User.Login,
+			State: r.State,
+			Body:  r.Body,
+		}
+	}
+	return reviews, nil
+}
+
+// PRReviewComment represents an inline comment left on a PR diff.
+type PRReviewComment struct {
+	ID       int64
+	ReviewID int64 // ID of the review the comment belongs to
+	User     string
+	Path     string
+	Line     int // 0 when the comment is not attached to a line
+	Body     string
+}
+
+// ListPRReviewComments returns the inline review comments on a PR.
+func (c *Client) ListPRReviewComments(ctx context.Context, owner, repo string, prNumber int) ([]*PRReviewComment, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/comments?per_page=100", owner, repo, prNumber)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	var apiComments []struct {
+		ID       int64  `json:"id"`
+		ReviewID int64  `json:"pull_request_review_id"`
+		Path     string `json:"path"`
+		Line     *int   `json:"line"`
+		Body     string `json:"body"`
+		User     struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&apiComments); err != nil {
+		return nil, err
+	}
+
+	comments := make([]*PRReviewComment, len(apiComments))
+	for i, cm := range apiComments {
+		comments[i] = &PRReviewComment{
+			ID:       cm.ID,
+			ReviewID: cm.ReviewID,
+			User:     cm.User.Login,
+			Path:     cm.Path,
+			Body:     cm.Body,
+		}
+		if cm.Line != nil {
+			comments[i].Line = *cm.Line
+		}
+	}
+	return comments, nil
+}
+
+// ChangeRequestFeedback builds agent feedback from "changes requested" reviews
+// submitted after afterReviewID, including each review's inline comments.
+// Returns the feedback text and the ID of the newest change request review,
+// or an empty string and 0 if there are no new change requests.
+func ChangeRequestFeedback(reviews []*PRReview, comments []*PRReviewComment, afterReviewID int64) (string, int64) {
+	var latestID int64
+	var b strings.Builder
+	for _, r := range reviews {
+		if r.State != "CHANGES_REQUESTED" || r.ID <= afterReviewID {
+			continue
+		}
+		if r.ID > latestID {
+			latestID = r.ID
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		fmt.Fprintf(&b, "PR review from @%s requested changes:", r.User)
+		if body := strings.TrimSpace(r.Body); body != "" {
+			b.WriteString("\n" + body)
+		}
+		for _, cm := range comments {
+			if cm.ReviewID != r.ID {
+				continue
+			}
+			location := cm.Path
+			if cm.Line > 0 {
+				location = fmt.Sprintf("%s:%d", cm.Path, cm.Line)
+			}
+			fmt.Fprintf(&b, "\n- %s: %s", location, strings.TrimSpace(cm.Body))
+		}
+	}
+	if latestID == 0 {
+		return "", 0
+	}
+	return b.String(), latestID
+}
+
+// GetPRHeadRef returns the name of a pull request's head branch.
+func (c *Client) GetPRHeadRef(ctx context.Context, owner, repo string, prNumber int) (string, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d", owner, repo, prNumber)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return "", err
+	}
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	var pr struct {
+		Head struct {
+			Ref string `json:"ref"`
+		} `json:"head"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
+		return "", err
+	}
+	return pr.Head.Ref, nil
+}
+
+// GetPRBaseRef returns the name of the branch a pull request targets.
+func (c *Client) GetPRBaseRef(ctx context.Context, owner, repo string, prNumber int) (string, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d", owner, repo, prNumber)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return "", err
+	}
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	var pr struct {
+		Base struct {
+			Ref string `json:"ref"`
+		} `json:"base"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
+		return "", err
+	}
+	return pr.Base.Ref, nil
+}
+
+// GetPRHeadSHA returns the SHA of a PR's head commit.
+func (c *Client) GetPRHeadSHA(ctx context.Context, owner, repo string, prNumber int) (string, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d", owner, repo, prNumber)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return "", err
+	}
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	var pr struct {
+		Head struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
+		return "", err
+	}
+	return pr.Head.SHA, nil
+}
+
+// PRDetails is the title, body and head branch of a pull request.
+type PRDetails struct {
+	Title   string
+	Body    string
+	HeadRef string
+}
+
+// GetPRDetails returns a pull request's title, body and head branch.
+func (c *Client) GetPRDetails(ctx context.Context, owner, repo string, prNumber int) (*PRDetails, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d", owner, repo, prNumber)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	var pr struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+		Head  struct {
+			Ref string `json:"ref"`
+		} `json:"head"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
+		return nil, err
+	}
+	return &PRDetails{Title: pr.Title, Body: pr.Body, HeadRef: pr.Head.Ref}, nil
+}
+
+// ListPRCommitMessages returns the messages of a pull request's commits,
+// oldest first. GitHub lists at most 250 commits per PR.
+func (c *Client) ListPRCommitMessages(ctx context.Context, owner, repo string, prNumber int) ([]string, error) {
+	var messages []string
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/commits?per_page=100&page=%d", owner, repo, prNumber, page)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+		if err != nil {
+			return nil, err
+		}
+		c.setHeaders(req)
+
+		resp, err := c.do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		var commits []struct {
+			Commit struct {
+				Message string `json:"message"`
+			} `json:"commit"`
+		}
+		if resp.StatusCode != http.StatusOK {
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+		}
+		err = json.NewDecoder(resp.Body).Decode(&commits)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range commits {
+			messages = append(messages, c.Commit.Message)
+		}
+		if len(commits) < 100 {
+			return messages, nil
+		}
+	}
+}
+
+// VerveStatusContextPrefix prefixes the context of commit statuses Verve sets
+// on agent PRs. GetPRCheckStatus ignores them when deciding whether checks
+// failed.
+const VerveStatusContextPrefix = "verve/"
+
+// CommitStatus is a status reported on a commit through the legacy status
+// API. Branch protection can require a status context to be "success" before
+// a PR merges.
+type CommitStatus struct {
+	State       string // "success", "failure", "error" or "pending"
+	Context     string
+	Description string // Truncated to GitHub's 140 character limit
+	TargetURL   string
+}
+
+// SetCommitStatus creates a status for a commit, replacing any earlier status
+// with the same context.
+func (c *Client) SetCommitStatus(ctx context.Context, owner, repo, sha string, status CommitStatus) error {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/statuses/%s", owner, repo, sha)
+
+	description := status.Description
+	if len(description) > 140 {
+		description = description[:137] + "..."
+	}
+	payload := map[string]string{
+		"state":       status.State,
+		"context":     status.Context,
+		"description": description,
+	}
+	if status.TargetURL != "" {
+		payload["target_url"] = status.TargetURL
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(string(payloadBytes)))
+	if err != nil {
+		return err
+	}
+	c.setHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("GitHub API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// DispatchWorkflow triggers a workflow_dispatch event for a workflow file
+// (e.g. "full-tests.yml") on the given branch. The workflow must declare a
+// workflow_dispatch trigger accepting the given inputs.
+func (c *Client) DispatchWorkflow(ctx context.Context, owner, repo, workflowFile, ref string, inputs map[string]string) error {
+	file := url.PathEscape(workflowFile)
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/actions/workflows/%s/dispatches", owner, repo, file)
+
+	payload := map[string]any{"ref": ref}
+	if len(inputs) > 0 {
+		payload["inputs"] = inputs
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(string(payloadBytes)))
+	if err != nil {
+		return err
+	}
+	c.setHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusNoContent {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("GitHub API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// WorkflowRun is a GitHub Actions workflow run.
+type WorkflowRun struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`     // "queued", "in_progress", "completed", ...
+	Conclusion string `json:"conclusion"` // "success", "failure", "cancelled", "timed_out", ... once completed
+	HTMLURL    string `json:"html_url"`
+}
+
+// FindDispatchedRun returns the newest workflow_dispatch run of a workflow on
+// a branch created at or after since. It returns nil when GitHub has not
+// created the run yet.
+func (c *Client) FindDispatchedRun(ctx context.Context, owner, repo, workflowFile, branch string, since time.Time) (*WorkflowRun, error) {
+	query := url.Values{
+		"event":    {"workflow_dispatch"},
+		"branch":   {branch},
+		"created":  {">=" + since.UTC().Format(time.RFC3339)},
+		"per_page": {"1"},
+	}
+	file := url.PathEscape(workflowFile)
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/actions/workflows/%s/runs?%s", owner, repo, file, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		WorkflowRuns []*WorkflowRun `json:"workflow_runs"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	if len(body.WorkflowRuns) == 0 {
+		return nil, nil
+	}
+	return body.WorkflowRuns[0], nil
+}
+
+// ApplyDispatchedRun folds the run of a workflow dispatched for the PR into
+// its check result, so the PR is not considered green until that run has
+// passed. A nil run (not created yet) or an unfinished run keeps an otherwise
+// successful result pending; a run that did not succeed fails it.
+func ApplyDispatchedRun(result *CheckResult, workflowFile string, run *WorkflowRun) {
+	check := IndividualCheck{Name: workflowFile, Status: "queued"}
+	if run != nil {
+		if run.Name != "" {
+			check.Name = run.Name
+		}
+		check.Status = run.Status
+		check.Conclusion = run.Conclusion
+		check.URL = run.HTMLURL
+	}
+	result.Checks = append(result.Checks, check)
+
+	switch {
+	case run == nil || run.Status != "completed":
+		if result.Status == CheckStatusSuccess {
+			result.Status = CheckStatusPending
+		}
+	case run.Conclusion != "success" && run.Conclusion != "skipped" && run.Conclusion != "neutral":
+		result.Status = CheckStatusFailure
+		result.FailedNames = append(result.FailedNames, check.Name)
+		result.Summary = fmt.Sprint(result.FailedNames)
+	}
+}
+
+// Security alert kinds.
+const (
+	AlertKindCodeScanning   = "code_scanning"
+	AlertKindSecretScanning = "secret_scanning"
+)
+
+// SecurityAlert is an open code-scanning or secret-scanning alert.
+type SecurityAlert struct {
+	Kind     string // AlertKindCodeScanning or AlertKindSecretScanning
+	Number   int
+	Name     string // Rule description or secret type
+	Severity string // Empty for secret-scanning alerts
+	Path     string
+	Line     int
+	URL      string
+}
+
+// ListPRSecurityAlerts returns the open code-scanning and secret-scanning
+// alerts a PR introduces: code-scanning alerts on the PR's merge ref that are
+// not open on the default branch, and secret-scanning alerts with a location
+// in one of the PR's commits. An alert type that is not enabled for the repo,
+// or that the token cannot read, is skipped rather than reported as an error.
+func (c *Client) ListPRSecurityAlerts(ctx context.Context, owner, repo string, prNumber int) ([]*SecurityAlert, error) {
+	codeAlerts, err := c.listPRCodeScanningAlerts(ctx, owner, repo, prNumber)
+	if err != nil {
+		return nil, fmt.Errorf("code scanning: %w", err)
+	}
+	secretAlerts, err := c.listPRSecretScanningAlerts(ctx, owner, repo, prNumber)
+	if err != nil {
+		return nil, fmt.Errorf("secret scanning: %w", err)
+	}
+	return append(secretAlerts, codeAlerts...), nil
+}
+
+type codeScanningAlert struct {
+	Number  int    `json:"number"`
+	HTMLURL string `json:"html_url"`
+	Rule    struct {
+		Description           string `json:"description"`
+		Severity              string `json:"severity"`
+		SecuritySeverityLevel string `json:"security_severity_level"`
+	} `json:"rule"`
+	MostRecentInstance struct {
+		Location struct {
+			Path      string `json:"path"`
+			StartLine int    `json:"start_line"`
+		} `json:"location"`
+	} `json:"most_recent_instance"`
+}
+
+func (c *Client) listPRCodeScanningAlerts(ctx context.Context, owner, repo string, prNumber int) ([]*SecurityAlert, error) {
+	base := fmt.Sprintf("https://api.github.com/repos/%s/%s/code-scanning/alerts?state=open&per_page=100", owner, repo)
+
+	var prAlerts []codeScanningAlert
+	ok, err := c.getOptionalJSON(ctx, fmt.Sprintf("%s&ref=refs/pull/%d/merge", base, prNumber), &prAlerts)
+	if err != nil || !ok || len(prAlerts) == 0 {
+		return nil, err
+	}
+	// Without a ref, alerts are listed for the default branch.
+	var defaultAlerts []codeScanningAlert
+	if _, err := c.getOptionalJSON(ctx, base, &defaultAlerts); err != nil {
+		return nil, err
+	}
+	existing := make(map[int]bool, len(defaultAlerts))
+	for _, a := range defaultAlerts {
+		existing[a.Number] = true
+	}
+
+	var alerts []*SecurityAlert
+	for _, a := range prAlerts {
+		if existing[a.Number] {
+			continue
+		}
+		severity := a.Rule.SecuritySeverityLevel
+		if severity == "" {
+			severity = a.Rule.Severity
+		}
+		alerts = append(alerts, &SecurityAlert{
+			Kind:     AlertKindCodeScanning,
+			Number:   a.Number,
+			Name:     a.Rule.Description,
+			Severity: severity,
+			Path:     a.MostRecentInstance.Location.Path,
+			Line:     a.MostRecentInstance.Location.StartLine,
+			URL:      a.HTMLURL,
+		})
+	}
+	return alerts, nil
+}
+
+func (c *Client) listPRSecretScanningAlerts(ctx context.Context, owner, repo string, prNumber int) ([]*SecurityAlert, error) {
+	var openAlerts []struct {
+		Number                int    `json:"number"`
+		SecretTypeDisplayName string `json:"secret_type_display_name"`
+		HTMLURL               string `json:"html_url"`
+	}
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/secret-scanning/alerts?state=open&per_page=100", owner, repo)
+	ok, err := c.getOptionalJSON(ctx, url, &openAlerts)
+	if err != nil || !ok || len(openAlerts) == 0 {
+		return nil, err
+	}
+
+	var commits []struct {
+		SHA string `json:"sha"`
+	}
+	url = fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/commits?per_page=100", owner, repo, prNumber)
+	if _, err := c.getOptionalJSON(ctx, url, &commits); err != nil {
+		return nil, err
+	}
+	prCommits := make(map[string]bool, len(commits))
+	for _, cm := range commits {
+		prCommits[cm.SHA] = true
+	}
+
+	var alerts []*SecurityAlert
+	for _, a := range openAlerts {
+		var locations []struct {
+			Type    string `json:"type"`
+			Details struct {
+				Path      string `json:"path"`
+				StartLine int    `json:"start_line"`
+				CommitSHA string `json:"commit_sha"`
+			} `json:"details"`
+		}
+		url := fmt.Sprintf("https://api.github.com/repos/%s/%s/secret-scanning/alerts/%d/locations?per_page=100", owner, repo, a.Number)
+		if _, err := c.getOptionalJSON(ctx, url, &locations); err != nil {
+			return nil, err
+		}
+		for _, loc := range locations {
+			if loc.Type != "commit" || !prCommits[loc.Details.CommitSHA] {
+				continue
+			}
+			alerts = append(alerts, &SecurityAlert{
+				Kind:   AlertKindSecretScanning,
+				Number: a.Number,
+				Name:   a.SecretTypeDisplayName,
+				Path:   loc.Details.Path,
+				Line:   loc.Details.StartLine,
+				URL:    a.HTMLURL,
+			})
+			break
+		}
+	}
+	return alerts, nil
+}
+
+// getOptionalJSON decodes a GET response into v. It returns false without an
+// error when the endpoint is unavailable: GitHub answers 403 or 404 when a
+// feature such as code scanning is disabled for the repo, when the resource
+// doesn't exist or when the token lacks access.
+func (c *Client) getOptionalJSON(ctx context.Context, url string, v any) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return false, err
+	}
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusForbidden, http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SecurityAlertFeedback builds agent feedback asking it to remediate the
+// given alerts. Secret values are never included, only where they were found.
+func SecurityAlertFeedback(alerts []*SecurityAlert) string {
+	var b strings.Builder
+	b.WriteString("The PR introduces security alerts that must be fixed before it can be merged:")
+	for _, a := range alerts {
+		location := a.Path
+		if a.Line > 0 {
+			location = fmt.Sprintf("%s:%d", a.Path, a.Line)
+		}
+		switch a.Kind {
+		case AlertKindSecretScanning:
+			fmt.Fprintf(&b, "\n- Secret scanning alert #%d: %s committed in %s. Remove the secret from the code and the branch history and load it from configuration instead.", a.Number, a.Name, location)
+		default:
+			severity := ""
+			if a.Severity != "" {
+				severity = " (" + a.Severity + ")"
+			}
+			fmt.Fprintf(&b, "\n- Code scanning alert #%d%s: %s at %s", a.Number, severity, a.Name, location)
+		}
+		if a.URL != "" {
+			fmt.Fprintf(&b, " [%s]", a.URL)
+		}
+	}
+	return b.String()
+}
+
+// DisableAutoMerge turns off auto-merge on a PR so it cannot merge on its own
+// while it has unresolved problems. It returns false when auto-merge was not
+// enabled.
+func (c *Client) DisableAutoMerge(ctx context.Context, owner, repo string, prNumber int) (bool, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d", owner, repo, prNumber)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return false, err
+	}
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	var pr struct {
+		NodeID    string          `json:"node_id"`
+		AutoMerge json.RawMessage `json:"auto_merge"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
+		return false, err
+	}
+	if len(pr.AutoMerge) == 0 || string(pr.AutoMerge) == "null" {
+		return false, nil
+	}
+
+	// Auto-merge can only be disabled through the GraphQL API.
+	payload, err := json.Marshal(map[string]any{
+		"query":     "mutation($id: ID!) { disablePullRequestAutoMerge(input: {pullRequestId: $id}) { clientMutationId } }",
+		"variables": map[string]string{"id": pr.NodeID},
+	})
+	if err != nil {
+		return false, fmt.Errorf("marshal payload: %w", err)
+	}
+	gqlReq, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.github.com/graphql", strings.NewReader(string(payload)))
+	if err != nil {
+		return false, err
+	}
+	c.setHeaders(gqlReq)
+	gqlReq.Header.Set("Content-Type", "application/json")
+
+	gqlResp, err := c.do(gqlReq)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = gqlResp.Body.Close() }()
+
+	if gqlResp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("GitHub GraphQL API returned status %d", gqlResp.StatusCode)
+	}
+	var result struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(gqlResp.Body).Decode(&result); err != nil {
+		return false, err
+	}
+	if len(result.Errors) > 0 {
+		return false, fmt.Errorf("disable auto-merge: %s", result.Errors[0].Message)
+	}
+	return true, nil
+}
+
+// UpsertPRComment posts a comment on a PR, or edits the comment with the
+// given ID when it is non-zero and still exists. It returns the comment's ID.
+func (c *Client) UpsertPRComment(ctx context.Context, owner, repo string, prNumber int, commentID int64, body string) (int64, error) {
+	payload, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return 0, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	method, url, want := http.MethodPost, fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/%d/comments", owner, repo, prNumber), http.StatusCreated
+	if commentID > 0 {
+		method, url, want = http.MethodPatch, fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/comments/%d", owner, repo, commentID), http.StatusOK
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, strings.NewReader(string(payload)))
+	if err != nil {
+		return 0, err
+	}
+	c.setHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// The comment was deleted; post a new one.
+	if commentID > 0 && resp.StatusCode == http.StatusNotFound {
+		return c.UpsertPRComment(ctx, owner, repo, prNumber, 0, body)
+	}
+	if resp.StatusCode != want {
+		respBody, _ := io.ReadAll(resp.Body)
+		return 0, fmt.Errorf("GitHub API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var comment struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&comment); err != nil {
+		return 0, err
+	}
+	return comment.ID, nil
+}
+
 func (c *Client) setHeaders(req *http.Request) {
 	req.Header.Set("Authorization", "Bearer "+c.token)
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
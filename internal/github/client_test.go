@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -155,6 +157,187 @@ func TestClient_ClosePR(t *testing.T) {
 	assert.Equal(t, "feature-branch", branch)
 }
 
+func TestClient_ApprovePR(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method, "expected POST method")
+		assert.Equal(t, "/repos/owner/repo/pulls/42/reviews", r.URL.Path)
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "APPROVE", body["event"])
+		assert.Equal(t, "LGTM", body["body"])
+		json.NewEncoder(w).Encode(map[string]any{"id": 1, "state": "APPROVED"})
+	}))
+	defer server.Close()
+
+	c := &Client{
+		token:      "test-token",
+		httpClient: server.Client(),
+	}
+	server.Client().Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme = "http"
+		r.URL.Host = server.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	require.NoError(t, c.ApprovePR(context.Background(), "owner", "repo", 42, "LGTM"))
+}
+
+func TestClient_ApprovePR_OwnPullRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]any{
+			"message": "Unprocessable Entity",
+			"errors":  []string{"Can not approve your own pull request"},
+		})
+	}))
+	defer server.Close()
+
+	c := &Client{
+		token:      "test-token",
+		httpClient: server.Client(),
+	}
+	server.Client().Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme = "http"
+		r.URL.Host = server.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	err := c.ApprovePR(context.Background(), "owner", "repo", 42, "")
+	var rejected *RejectedError
+	require.ErrorAs(t, err, &rejected)
+	assert.Equal(t, "Unprocessable Entity: Can not approve your own pull request", rejected.Message)
+}
+
+func TestClient_MergePR(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method, "expected PUT method")
+		assert.Equal(t, "/repos/owner/repo/pulls/42/merge", r.URL.Path)
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, MergeMethodSquash, body["merge_method"])
+		json.NewEncoder(w).Encode(map[string]any{"sha": "abc123", "merged": true})
+	}))
+	defer server.Close()
+
+	c := &Client{
+		token:      "test-token",
+		httpClient: server.Client(),
+	}
+	server.Client().Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme = "http"
+		r.URL.Host = server.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	sha, err := c.MergePR(context.Background(), "owner", "repo", 42, MergeMethodSquash)
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", sha)
+}
+
+func TestClient_MergePR_NotMergeable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(map[string]any{"message": "Pull Request is not mergeable"})
+	}))
+	defer server.Close()
+
+	c := &Client{
+		token:      "test-token",
+		httpClient: server.Client(),
+	}
+	server.Client().Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme = "http"
+		r.URL.Host = server.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	_, err := c.MergePR(context.Background(), "owner", "repo", 42, MergeMethodMerge)
+	var rejected *RejectedError
+	require.ErrorAs(t, err, &rejected)
+	assert.Equal(t, http.StatusMethodNotAllowed, rejected.StatusCode)
+	assert.Equal(t, "Pull Request is not mergeable", rejected.Message)
+}
+
+func TestClient_RerunFailedJobs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/actions/jobs/7":
+			json.NewEncoder(w).Encode(map[string]any{"id": 7, "run_id": 99})
+		case "/repos/owner/repo/actions/jobs/8":
+			w.WriteHeader(http.StatusNotFound)
+		case "/repos/owner/repo/actions/runs/99/rerun-failed-jobs":
+			assert.Equal(t, http.MethodPost, r.Method)
+			w.WriteHeader(http.StatusCreated)
+		case "/repos/owner/repo/actions/runs/100/rerun-failed-jobs":
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string]any{"message": "Unable to retry this workflow run because it was created over a month ago"})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	c := &Client{
+		token:      "test-token",
+		httpClient: server.Client(),
+	}
+	server.Client().Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme = "http"
+		r.URL.Host = server.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	ctx := context.Background()
+	runID, err := c.GetJobRunID(ctx, "owner", "repo", 7)
+	require.NoError(t, err)
+	assert.Equal(t, int64(99), runID)
+
+	runID, err = c.GetJobRunID(ctx, "owner", "repo", 8)
+	require.NoError(t, err)
+	assert.Zero(t, runID, "check runs from other apps have no workflow run")
+
+	require.NoError(t, c.RerunFailedJobs(ctx, "owner", "repo", 99))
+
+	var rejected *RejectedError
+	require.ErrorAs(t, c.RerunFailedJobs(ctx, "owner", "repo", 100), &rejected)
+	assert.Equal(t, http.StatusForbidden, rejected.StatusCode)
+}
+
+func TestClient_UpdatePRBranch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method, "expected PUT method")
+		switch r.URL.Path {
+		case "/repos/owner/repo/pulls/1/update-branch":
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(map[string]string{"message": "Updating pull request branch."})
+		case "/repos/owner/repo/pulls/2/update-branch":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			json.NewEncoder(w).Encode(map[string]string{"message": "merge conflict between base and head"})
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	c := &Client{
+		token:      "test-token",
+		httpClient: server.Client(),
+	}
+	server.Client().Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme = "http"
+		r.URL.Host = server.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	ctx := context.Background()
+	require.NoError(t, c.UpdatePRBranch(ctx, "owner", "repo", 1))
+
+	var rejected *RejectedError
+	require.ErrorAs(t, c.UpdatePRBranch(ctx, "owner", "repo", 2), &rejected)
+	assert.Equal(t, http.StatusUnprocessableEntity, rejected.StatusCode)
+	assert.Equal(t, "merge conflict between base and head", rejected.Message)
+}
+
 func TestClient_DeleteBranch(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "DELETE", r.Method, "expected DELETE method")
@@ -295,6 +478,30 @@ func TestClient_GetPRMergeability_Dirty(t *testing.T) {
 	assert.True(t, result.HasConflicts, "expected HasConflicts=true for dirty state")
 }
 
+func TestPRMergeability_BranchAction(t *testing.T) {
+	tests := []struct {
+		state string
+		want  BranchAction
+	}{
+		{state: "clean", want: BranchCurrent},
+		{state: "blocked", want: BranchCurrent},
+		{state: "unstable", want: BranchCurrent},
+		{state: "behind", want: BranchUpdate},
+		// GitHub refuses to update a conflicting branch with 422.
+		{state: "dirty", want: BranchResolveConflicts},
+	}
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			m := &PRMergeability{
+				MergeableState: tt.state,
+				HasConflicts:   tt.state == "dirty",
+				Behind:         tt.state == "behind",
+			}
+			assert.Equal(t, tt.want, m.BranchAction())
+		})
+	}
+}
+
 func TestCheckStatusConstants(t *testing.T) {
 	assert.Equal(t, CheckStatus("pending"), CheckStatusPending)
 	assert.Equal(t, CheckStatus("success"), CheckStatusSuccess)
@@ -445,7 +652,7 @@ func TestGetFailedCheckLogs_StepExtraction(t *testing.T) {
 		return http.DefaultTransport.RoundTrip(r)
 	})
 
-	result, err := c.GetFailedCheckLogs(context.Background(), "owner", "repo", 1)
+	result, err := c.GetFailedCheckLogs(context.Background(), "owner", "repo", 1, CheckPolicy{})
 	require.NoError(t, err)
 
 	// Should contain the failed step name in the header
@@ -459,6 +666,769 @@ func TestGetFailedCheckLogs_StepExtraction(t *testing.T) {
 	assert.NotContains(t, result, "uploading...")
 }
 
+func TestClient_ListPRReviews(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/owner/repo/pulls/7/reviews", r.URL.Path)
+		json.NewEncoder(w).Encode([]map[string]any{
+			{"id": 1, "state": "COMMENTED", "body": "looks fine", "user": map[string]string{"login": "alice"}},
+			{"id": 2, "state": "CHANGES_REQUESTED", "body": "please rename", "user": map[string]string{"login": "bob"}},
+		})
+	}))
+	defer server.Close()
+
+	c := &Client{
+		token:      "test-token",
+		httpClient: server.Client(),
+	}
+	server.Client().Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme = "http"
+		r.URL.Host = server.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	reviews, err := c.ListPRReviews(context.Background(), "owner", "repo", 7)
+	require.NoError(t, err)
+	require.Len(t, reviews, 2)
+	assert.Equal(t, int64(2), reviews[1].ID)
+	assert.Equal(t, "bob", reviews[1].User)
+	assert.Equal(t, "CHANGES_REQUESTED", reviews[1].State)
+	assert.Equal(t, "please rename", reviews[1].Body)
+}
+
+func TestClient_ListPRReviewComments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/owner/repo/pulls/7/comments", r.URL.Path)
+		json.NewEncoder(w).Encode([]map[string]any{
+			{"id": 10, "pull_request_review_id": 2, "path": "main.go", "line": 12, "body": "typo", "user": map[string]string{"login": "bob"}},
+			{"id": 11, "pull_request_review_id": 2, "path": "README.md", "line": nil, "body": "outdated", "user": map[string]string{"login": "bob"}},
+		})
+	}))
+	defer server.Close()
+
+	c := &Client{
+		token:      "test-token",
+		httpClient: server.Client(),
+	}
+	server.Client().Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme = "http"
+		r.URL.Host = server.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	comments, err := c.ListPRReviewComments(context.Background(), "owner", "repo", 7)
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+	assert.Equal(t, int64(2), comments[0].ReviewID)
+	assert.Equal(t, "main.go", comments[0].Path)
+	assert.Equal(t, 12, comments[0].Line)
+	assert.Equal(t, 0, comments[1].Line)
+}
+
+func TestChangeRequestFeedback(t *testing.T) {
+	reviews := []*PRReview{
+		{ID: 1, User: "alice", State: "CHANGES_REQUESTED", Body: "old request"},
+		{ID: 2, User: "bob", State: "COMMENTED", Body: "nit"},
+		{ID: 3, User: "carol", State: "CHANGES_REQUESTED", Body: "please add tests"},
+	}
+	comments := []*PRReviewComment{
+		{ID: 10, ReviewID: 3, Path: "main.go", Line: 12, Body: "handle the error"},
+		{ID: 11, ReviewID: 3, Path: "README.md", Body: "document the flag"},
+		{ID: 12, ReviewID: 2, Path: "main.go", Line: 1, Body: "unrelated"},
+	}
+
+	feedback, latestID := ChangeRequestFeedback(reviews, comments, 1)
+	assert.Equal(t, int64(3), latestID)
+	assert.Equal(t, "PR review from @carol requested changes:\nplease add tests\n- main.go:12: handle the error\n- README.md: document the flag", feedback)
+
+	feedback, latestID = ChangeRequestFeedback(reviews, comments, 3)
+	assert.Empty(t, feedback, "reviews already processed should be ignored")
+	assert.Equal(t, int64(0), latestID)
+}
+
+func TestClient_GetPRHeadRef(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method, "expected GET method")
+		assert.Equal(t, "/repos/owner/repo/pulls/42", r.URL.Path)
+		json.NewEncoder(w).Encode(map[string]any{
+			"head": map[string]string{"ref": "verve/task-7"},
+		})
+	}))
+	defer server.Close()
+
+	c := &Client{
+		token:      "test-token",
+		httpClient: server.Client(),
+	}
+	server.Client().Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme = "http"
+		r.URL.Host = server.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	ref, err := c.GetPRHeadRef(context.Background(), "owner", "repo", 42)
+	require.NoError(t, err)
+	assert.Equal(t, "verve/task-7", ref)
+}
+
+func TestClient_UpdatePRBase(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PATCH", r.Method, "expected PATCH method")
+		assert.Equal(t, "/repos/owner/repo/pulls/42", r.URL.Path)
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]string{"base": "main"}, body)
+		json.NewEncoder(w).Encode(map[string]any{"number": 42})
+	}))
+	defer server.Close()
+
+	c := &Client{
+		token:      "test-token",
+		httpClient: server.Client(),
+	}
+	server.Client().Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme = "http"
+		r.URL.Host = server.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	require.NoError(t, c.UpdatePRBase(context.Background(), "owner", "repo", 42, "main"))
+}
+
+func TestClient_GetPRDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/owner/repo/pulls/42", r.URL.Path)
+		json.NewEncoder(w).Encode(map[string]any{
+			"title": "fix: handle empty body",
+			"body":  "Resolves tsk_123",
+			"head":  map[string]string{"ref": "verve/task-7"},
+		})
+	}))
+	defer server.Close()
+
+	c := &Client{
+		token:      "test-token",
+		httpClient: server.Client(),
+	}
+	server.Client().Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme = "http"
+		r.URL.Host = server.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	pr, err := c.GetPRDetails(context.Background(), "owner", "repo", 42)
+	require.NoError(t, err)
+	assert.Equal(t, &PRDetails{Title: "fix: handle empty body", Body: "Resolves tsk_123", HeadRef: "verve/task-7"}, pr)
+}
+
+func TestClient_ListPRCommitMessages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/owner/repo/pulls/42/commits", r.URL.Path)
+		var commits []map[string]any
+		if r.URL.Query().Get("page") == "1" {
+			for i := range 100 {
+				commits = append(commits, map[string]any{"commit": map[string]string{"message": "commit " + strconv.Itoa(i)}})
+			}
+		} else {
+			commits = append(commits, map[string]any{"commit": map[string]string{"message": "fix: last"}})
+		}
+		json.NewEncoder(w).Encode(commits)
+	}))
+	defer server.Close()
+
+	c := &Client{
+		token:      "test-token",
+		httpClient: server.Client(),
+	}
+	server.Client().Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme = "http"
+		r.URL.Host = server.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	messages, err := c.ListPRCommitMessages(context.Background(), "owner", "repo", 42)
+	require.NoError(t, err)
+	require.Len(t, messages, 101)
+	assert.Equal(t, "commit 0", messages[0])
+	assert.Equal(t, "fix: last", messages[100])
+}
+
+func TestClient_GetPRHeadSHA(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method, "expected GET method")
+		assert.Equal(t, "/repos/owner/repo/pulls/42", r.URL.Path)
+		json.NewEncoder(w).Encode(map[string]any{
+			"head": map[string]string{"ref": "verve/task-7", "sha": "abc123"},
+		})
+	}))
+	defer server.Close()
+
+	c := &Client{
+		token:      "test-token",
+		httpClient: server.Client(),
+	}
+	server.Client().Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme = "http"
+		r.URL.Host = server.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	sha, err := c.GetPRHeadSHA(context.Background(), "owner", "repo", 42)
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", sha)
+}
+
+func TestClient_SetCommitStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method, "expected POST method")
+		assert.Equal(t, "/repos/owner/repo/statuses/abc123", r.URL.Path)
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "failure", body["state"])
+		assert.Equal(t, "verve/provenance", body["context"])
+		assert.Len(t, body["description"], 140)
+		assert.NotContains(t, body, "target_url")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	c := &Client{
+		token:      "test-token",
+		httpClient: server.Client(),
+	}
+	server.Client().Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme = "http"
+		r.URL.Host = server.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	err := c.SetCommitStatus(context.Background(), "owner", "repo", "abc123", CommitStatus{
+		State:       "failure",
+		Context:     "verve/provenance",
+		Description: strings.Repeat("x", 200),
+	})
+	require.NoError(t, err)
+}
+
+func TestClient_CreatePR(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method, "expected POST method")
+		assert.Equal(t, "/repos/owner/repo/pulls", r.URL.Path)
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "epic/epc_123", body["head"])
+		assert.Equal(t, "main", body["base"])
+		assert.Equal(t, "Epic", body["title"])
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]any{
+			"number":   7,
+			"html_url": "https://github.com/owner/repo/pull/7",
+		})
+	}))
+	defer server.Close()
+
+	c := &Client{
+		token:      "test-token",
+		httpClient: server.Client(),
+	}
+	server.Client().Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme = "http"
+		r.URL.Host = server.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	url, number, err := c.CreatePR(context.Background(), "owner", "repo", "epic/epc_123", "main", "Epic", "body")
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/owner/repo/pull/7", url)
+	assert.Equal(t, 7, number)
+}
+
+func TestClient_UpsertPRComment(t *testing.T) {
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "report", body["body"])
+		switch {
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/owner/repo/issues/comments/7":
+			json.NewEncoder(w).Encode(map[string]any{"id": 7})
+		case r.Method == http.MethodPatch:
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/issues/42/comments":
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]any{"id": 99})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	c := &Client{
+		token:      "test-token",
+		httpClient: server.Client(),
+	}
+	server.Client().Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme = "http"
+		r.URL.Host = server.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	id, err := c.UpsertPRComment(context.Background(), "owner", "repo", 42, 0, "report")
+	require.NoError(t, err)
+	assert.Equal(t, int64(99), id)
+
+	id, err = c.UpsertPRComment(context.Background(), "owner", "repo", 42, 7, "report")
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), id)
+
+	// A deleted comment is replaced with a new one.
+	id, err = c.UpsertPRComment(context.Background(), "owner", "repo", 42, 8, "report")
+	require.NoError(t, err)
+	assert.Equal(t, int64(99), id)
+
+	assert.Equal(t, []string{
+		"POST /repos/owner/repo/issues/42/comments",
+		"PATCH /repos/owner/repo/issues/comments/7",
+		"PATCH /repos/owner/repo/issues/comments/8",
+		"POST /repos/owner/repo/issues/42/comments",
+	}, calls)
+}
+
+func TestClient_GetPRCheckStatus_IgnoresVerveStatuses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		switch {
+		case strings.HasSuffix(path, "/pulls/1"):
+			json.NewEncoder(w).Encode(map[string]any{
+				"head": map[string]string{"sha": "abc123"},
+			})
+		case strings.HasSuffix(path, "/check-runs"):
+			json.NewEncoder(w).Encode(map[string]any{"check_runs": []any{}})
+		case strings.HasSuffix(path, "/status"):
+			json.NewEncoder(w).Encode(map[string]any{
+				"state": "failure",
+				"statuses": []map[string]string{
+					{"context": "ci/build", "state": "success"},
+					{"context": "verve/provenance", "state": "failure"},
+				},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := &Client{
+		token:      "test-token",
+		httpClient: server.Client(),
+	}
+	server.Client().Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme = "http"
+		r.URL.Host = server.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	result, err := c.GetPRCheckStatus(context.Background(), "owner", "repo", 1, CheckPolicy{})
+	require.NoError(t, err)
+	assert.Equal(t, CheckStatusSuccess, result.Status)
+	// The status is still listed so the UI can show it.
+	assert.Len(t, result.Checks, 2)
+}
+
+func TestClient_GetPRCheckStatus_RequiredOnly(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		switch {
+		case strings.HasSuffix(path, "/pulls/1"):
+			json.NewEncoder(w).Encode(map[string]any{
+				"head": map[string]string{"sha": "abc123"},
+				"base": map[string]string{"ref": "release/1.x"},
+			})
+		case path == "/repos/owner/repo/branches/release/1.x":
+			json.NewEncoder(w).Encode(map[string]any{
+				"protection": map[string]any{
+					"required_status_checks": map[string]any{"contexts": []string{"ci/build"}},
+				},
+			})
+		case path == "/repos/owner/repo/rules/branches/release/1.x":
+			json.NewEncoder(w).Encode([]map[string]any{
+				{"type": "pull_request"},
+				{"type": "required_status_checks", "parameters": map[string]any{
+					"required_status_checks": []map[string]any{{"context": "lint"}, {"context": "ci/build"}},
+				}},
+			})
+		case strings.HasSuffix(path, "/check-runs"):
+			json.NewEncoder(w).Encode(map[string]any{"check_runs": []map[string]any{
+				{"id": 1, "name": "lint", "status": "completed", "conclusion": "success"},
+				{"id": 2, "name": "docs-preview", "status": "completed", "conclusion": "failure"},
+			}})
+		case strings.HasSuffix(path, "/status"):
+			json.NewEncoder(w).Encode(map[string]any{
+				"state":    "success",
+				"statuses": []map[string]string{{"context": "ci/build", "state": "success"}},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := &Client{
+		token:      "test-token",
+		httpClient: server.Client(),
+	}
+	server.Client().Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme = "http"
+		r.URL.Host = server.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	required, err := c.GetRequiredChecks(context.Background(), "owner", "repo", "release/1.x")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ci/build", "lint"}, required)
+
+	// Only the branch's required checks count; the failed preview is
+	// informational.
+	result, err := c.GetPRCheckStatus(context.Background(), "owner", "repo", 1, CheckPolicy{RequiredOnly: true})
+	require.NoError(t, err)
+	assert.Equal(t, CheckStatusSuccess, result.Status)
+	require.Len(t, result.Checks, 3)
+	assert.False(t, result.Checks[0].Informational)
+	assert.True(t, result.Checks[1].Informational)
+
+	// A configured subset takes the place of the branch's required checks.
+	result, err = c.GetPRCheckStatus(context.Background(), "owner", "repo", 1, CheckPolicy{RequiredOnly: true, Required: []string{"docs-preview"}})
+	require.NoError(t, err)
+	assert.Equal(t, CheckStatusFailure, result.Status)
+	assert.Equal(t, []string{"docs-preview"}, result.FailedNames)
+
+	// Without the mode every check counts.
+	result, err = c.GetPRCheckStatus(context.Background(), "owner", "repo", 1, CheckPolicy{})
+	require.NoError(t, err)
+	assert.Equal(t, CheckStatusFailure, result.Status)
+}
+
+func TestBuildCheckResult_RequiredOnlyWithoutRequiredChecks(t *testing.T) {
+	failure := "failure"
+	runs := []checkRun{{ID: 1, Name: "build", Status: "completed", Conclusion: &failure}}
+
+	// A base branch that requires no checks leaves every check counting.
+	result := buildCheckResult(runs, nil, false, CheckPolicy{RequiredOnly: true})
+	assert.Equal(t, CheckStatusFailure, result.Status)
+	assert.False(t, result.Checks[0].Informational)
+}
+
+func TestBuildCheckResult_IgnoredChecks(t *testing.T) {
+	failure := "failure"
+	runs := []checkRun{
+		{ID: 1, Name: "build", Status: "completed", Conclusion: new(string)},
+		{ID: 2, Name: "nightly-canary", Status: "completed", Conclusion: &failure},
+		{ID: 3, Name: "slow-e2e", Status: "in_progress"},
+	}
+	*runs[0].Conclusion = "success"
+	statuses := []commitContext{{Context: "ci/flaky", State: "error"}}
+
+	result := buildCheckResult(runs, statuses, false, CheckPolicy{Ignored: []string{"nightly-canary", "slow-e2e", "ci/flaky"}})
+	assert.Equal(t, CheckStatusSuccess, result.Status)
+	assert.Empty(t, result.FailedNames)
+	assert.Empty(t, result.FailedRunIDs)
+	require.Len(t, result.Checks, 4)
+	assert.False(t, result.Checks[0].Ignored)
+	assert.True(t, result.Checks[1].Ignored)
+
+	result = buildCheckResult(runs, statuses, false, CheckPolicy{})
+	assert.Equal(t, CheckStatusFailure, result.Status)
+	assert.Equal(t, []string{"nightly-canary", "ci/flaky"}, result.FailedNames)
+	assert.Equal(t, []int64{2}, result.FailedRunIDs)
+}
+
+func TestClient_DispatchWorkflow(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method, "expected POST method")
+		assert.Equal(t, "/repos/owner/repo/actions/workflows/full-tests.yml/dispatches", r.URL.Path)
+		var body struct {
+			Ref    string            `json:"ref"`
+			Inputs map[string]string `json:"inputs"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "verve/task-7", body.Ref)
+		assert.Equal(t, map[string]string{"suite": "all"}, body.Inputs)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c := &Client{
+		token:      "test-token",
+		httpClient: server.Client(),
+	}
+	server.Client().Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme = "http"
+		r.URL.Host = server.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	err := c.DispatchWorkflow(context.Background(), "owner", "repo", "full-tests.yml", "verve/task-7", map[string]string{"suite": "all"})
+	require.NoError(t, err)
+}
+
+func TestClient_DispatchWorkflow_NoDispatchTrigger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"message":"Workflow does not have 'workflow_dispatch' trigger"}`))
+	}))
+	defer server.Close()
+
+	c := &Client{
+		token:      "test-token",
+		httpClient: server.Client(),
+	}
+	server.Client().Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme = "http"
+		r.URL.Host = server.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	err := c.DispatchWorkflow(context.Background(), "owner", "repo", "ci.yml", "main", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "workflow_dispatch")
+}
+
+func TestClient_FindDispatchedRun(t *testing.T) {
+	since := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/owner/repo/actions/workflows/full-tests.yml/runs", r.URL.Path)
+		q := r.URL.Query()
+		assert.Equal(t, "workflow_dispatch", q.Get("event"))
+		assert.Equal(t, "verve/task-7", q.Get("branch"))
+		assert.Equal(t, ">=2026-03-01T12:00:00Z", q.Get("created"))
+		json.NewEncoder(w).Encode(map[string]any{
+			"workflow_runs": []map[string]any{
+				{"id": 99, "name": "Full tests", "status": "in_progress", "conclusion": nil, "html_url": "https://github.com/owner/repo/actions/runs/99"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	c := &Client{
+		token:      "test-token",
+		httpClient: server.Client(),
+	}
+	server.Client().Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme = "http"
+		r.URL.Host = server.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	run, err := c.FindDispatchedRun(context.Background(), "owner", "repo", "full-tests.yml", "verve/task-7", since)
+	require.NoError(t, err)
+	require.NotNil(t, run)
+	assert.Equal(t, int64(99), run.ID)
+	assert.Equal(t, "Full tests", run.Name)
+	assert.Equal(t, "in_progress", run.Status)
+	assert.Empty(t, run.Conclusion)
+}
+
+func TestClient_FindDispatchedRun_NotCreatedYet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"workflow_runs": []any{}})
+	}))
+	defer server.Close()
+
+	c := &Client{
+		token:      "test-token",
+		httpClient: server.Client(),
+	}
+	server.Client().Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme = "http"
+		r.URL.Host = server.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	run, err := c.FindDispatchedRun(context.Background(), "owner", "repo", "full-tests.yml", "verve/task-7", time.Now())
+	require.NoError(t, err)
+	assert.Nil(t, run)
+}
+
+func TestApplyDispatchedRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     CheckStatus
+		run        *WorkflowRun
+		wantStatus CheckStatus
+		wantFailed []string
+		wantCheck  string
+	}{
+		{"run not created yet", CheckStatusSuccess, nil, CheckStatusPending, nil, "queued"},
+		{"run in progress", CheckStatusSuccess, &WorkflowRun{Name: "Full tests", Status: "in_progress"}, CheckStatusPending, nil, "in_progress"},
+		{"run passed", CheckStatusSuccess, &WorkflowRun{Name: "Full tests", Status: "completed", Conclusion: "success"}, CheckStatusSuccess, nil, "completed"},
+		{"run failed", CheckStatusSuccess, &WorkflowRun{Name: "Full tests", Status: "completed", Conclusion: "failure"}, CheckStatusFailure, []string{"Full tests"}, "completed"},
+		{"run cancelled while other checks pending", CheckStatusPending, &WorkflowRun{Name: "Full tests", Status: "completed", Conclusion: "cancelled"}, CheckStatusFailure, []string{"Full tests"}, "completed"},
+		{"other checks already failed", CheckStatusFailure, nil, CheckStatusFailure, nil, "queued"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &CheckResult{Status: tt.status}
+			ApplyDispatchedRun(result, "full-tests.yml", tt.run)
+			assert.Equal(t, tt.wantStatus, result.Status)
+			assert.Equal(t, tt.wantFailed, result.FailedNames)
+			require.Len(t, result.Checks, 1)
+			assert.Equal(t, tt.wantCheck, result.Checks[0].Status)
+			if tt.run == nil {
+				assert.Equal(t, "full-tests.yml", result.Checks[0].Name)
+			}
+		})
+	}
+}
+
+func TestClient_ListPRSecurityAlerts(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/code-scanning/alerts", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "open", r.URL.Query().Get("state"))
+		alert := func(number int, desc string) map[string]any {
+			return map[string]any{
+				"number":   number,
+				"html_url": "https://github.com/owner/repo/security/code-scanning/" + strconv.Itoa(number),
+				"rule":     map[string]any{"description": desc, "severity": "error", "security_severity_level": "high"},
+				"most_recent_instance": map[string]any{
+					"location": map[string]any{"path": "db/query.go", "start_line": 12},
+				},
+			}
+		}
+		if r.URL.Query().Get("ref") == "refs/pull/42/merge" {
+			json.NewEncoder(w).Encode([]any{alert(1, "Existing issue"), alert(2, "SQL injection")})
+			return
+		}
+		// Default branch
+		json.NewEncoder(w).Encode([]any{alert(1, "Existing issue")})
+	})
+	mux.HandleFunc("/repos/owner/repo/secret-scanning/alerts", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]map[string]any{
+			{"number": 7, "secret_type_display_name": "AWS Access Key ID", "html_url": "https://github.com/owner/repo/security/secret-scanning/7"},
+			{"number": 8, "secret_type_display_name": "Slack Token", "html_url": "https://github.com/owner/repo/security/secret-scanning/8"},
+		})
+	})
+	mux.HandleFunc("/repos/owner/repo/pulls/42/commits", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]map[string]any{{"sha": "abc123"}})
+	})
+	mux.HandleFunc("/repos/owner/repo/secret-scanning/alerts/7/locations", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]map[string]any{
+			{"type": "commit", "details": map[string]any{"path": "config.go", "start_line": 3, "commit_sha": "abc123"}},
+		})
+	})
+	mux.HandleFunc("/repos/owner/repo/secret-scanning/alerts/8/locations", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]map[string]any{
+			{"type": "commit", "details": map[string]any{"path": "old.go", "start_line": 1, "commit_sha": "def456"}},
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := &Client{
+		token:      "test-token",
+		httpClient: server.Client(),
+	}
+	server.Client().Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme = "http"
+		r.URL.Host = server.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	alerts, err := c.ListPRSecurityAlerts(context.Background(), "owner", "repo", 42)
+	require.NoError(t, err)
+	require.Len(t, alerts, 2)
+
+	assert.Equal(t, AlertKindSecretScanning, alerts[0].Kind)
+	assert.Equal(t, 7, alerts[0].Number)
+	assert.Equal(t, "AWS Access Key ID", alerts[0].Name)
+	assert.Equal(t, "config.go", alerts[0].Path)
+
+	assert.Equal(t, AlertKindCodeScanning, alerts[1].Kind)
+	assert.Equal(t, 2, alerts[1].Number)
+	assert.Equal(t, "SQL injection", alerts[1].Name)
+	assert.Equal(t, "high", alerts[1].Severity)
+	assert.Equal(t, 12, alerts[1].Line)
+}
+
+func TestClient_ListPRSecurityAlerts_ScanningDisabled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	c := &Client{
+		token:      "test-token",
+		httpClient: server.Client(),
+	}
+	server.Client().Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme = "http"
+		r.URL.Host = server.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	alerts, err := c.ListPRSecurityAlerts(context.Background(), "owner", "repo", 42)
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+}
+
+func TestClient_DisableAutoMerge(t *testing.T) {
+	tests := []struct {
+		name         string
+		autoMerge    any
+		wantDisabled bool
+	}{
+		{"auto-merge enabled", map[string]any{"merge_method": "squash"}, true},
+		{"auto-merge not enabled", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mutated bool
+			mux := http.NewServeMux()
+			mux.HandleFunc("/repos/owner/repo/pulls/42", func(w http.ResponseWriter, r *http.Request) {
+				json.NewEncoder(w).Encode(map[string]any{"node_id": "PR_node", "auto_merge": tt.autoMerge})
+			})
+			mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+				mutated = true
+				var body struct {
+					Query     string            `json:"query"`
+					Variables map[string]string `json:"variables"`
+				}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				assert.Contains(t, body.Query, "disablePullRequestAutoMerge")
+				assert.Equal(t, "PR_node", body.Variables["id"])
+				w.Write([]byte(`{"data":{"disablePullRequestAutoMerge":{"clientMutationId":null}}}`))
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			c := &Client{
+				token:      "test-token",
+				httpClient: server.Client(),
+			}
+			server.Client().Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				r.URL.Scheme = "http"
+				r.URL.Host = server.Listener.Addr().String()
+				return http.DefaultTransport.RoundTrip(r)
+			})
+
+			disabled, err := c.DisableAutoMerge(context.Background(), "owner", "repo", 42)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantDisabled, disabled)
+			assert.Equal(t, tt.wantDisabled, mutated)
+		})
+	}
+}
+
+func TestSecurityAlertFeedback(t *testing.T) {
+	feedback := SecurityAlertFeedback([]*SecurityAlert{
+		{Kind: AlertKindSecretScanning, Number: 7, Name: "AWS Access Key ID", Path: "config.go", Line: 3},
+		{Kind: AlertKindCodeScanning, Number: 2, Name: "SQL injection", Severity: "high", Path: "db/query.go", Line: 12, URL: "https://github.com/owner/repo/security/code-scanning/2"},
+	})
+	assert.Contains(t, feedback, "Secret scanning alert #7: AWS Access Key ID committed in config.go:3")
+	assert.Contains(t, feedback, "Code scanning alert #2 (high): SQL injection at db/query.go:12 [https://github.com/owner/repo/security/code-scanning/2]")
+}
+
 // roundTripFunc is a helper to override HTTP transport for tests.
 type roundTripFunc func(*http.Request) (*http.Response, error)
 
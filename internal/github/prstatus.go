@@ -0,0 +1,221 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// prStatusBatchSize is the most PRs fetched by one GraphQL query, which
+	// keeps each query well below GitHub's node limits.
+	prStatusBatchSize = 50
+	// prStatusMaxContexts is the most checks fetched per PR. A PR with more
+	// is left out of the batch result so its checks are fetched in full.
+	prStatusMaxContexts = 100
+)
+
+// PRStatus is the state of a PR as fetched in bulk by GetPRStatuses.
+type PRStatus struct {
+	Merged       bool
+	Mergeability *PRMergeability
+	BaseRef      string // Branch the PR targets
+	// Checks is the combined check result of the PR's head commit, or nil
+	// when it couldn't be fetched in bulk and must be fetched with
+	// GetPRCheckStatus.
+	Checks *CheckResult
+}
+
+// GetPRStatuses fetches the merge state, mergeability and checks of many PRs
+// of a repo with one GraphQL query per batch of PRs, instead of several REST
+// requests per PR. PRs that couldn't be fetched, such as ones that don't
+// exist, are missing from the result. Checks that don't count under policy
+// don't affect the check results.
+func (c *Client) GetPRStatuses(ctx context.Context, owner, repo string, prNumbers []int, policy CheckPolicy) (map[int]*PRStatus, error) {
+	statuses := make(map[int]*PRStatus, len(prNumbers))
+	// Required checks are resolved once per base branch.
+	policies := make(map[string]CheckPolicy)
+	for start := 0; start < len(prNumbers); start += prStatusBatchSize {
+		batch := prNumbers[start:min(start+prStatusBatchSize, len(prNumbers))]
+		if err := c.getPRStatusBatch(ctx, owner, repo, batch, policy, policies, statuses); err != nil {
+			return nil, err
+		}
+	}
+	return statuses, nil
+}
+
+func (c *Client) getPRStatusBatch(ctx context.Context, owner, repo string, prNumbers []int, policy CheckPolicy, policies map[string]CheckPolicy, statuses map[int]*PRStatus) error {
+	var q strings.Builder
+	q.WriteString("query($owner: String!, $name: String!) { repository(owner: $owner, name: $name) {")
+	for _, n := range prNumbers {
+		fmt.Fprintf(&q, " pr%d: pullRequest(number: %d) { ...status }", n, n)
+	}
+	q.WriteString(" } }\n")
+	q.WriteString(prStatusFragment)
+
+	payload, err := json.Marshal(map[string]any{
+		"query":     q.String(),
+		"variables": map[string]string{"owner": owner, "name": repo},
+	})
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.github.com/graphql", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	c.setHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GitHub GraphQL API returned status %d", resp.StatusCode)
+	}
+
+	// Errors for single PRs, such as one that doesn't exist, come back next
+	// to the data of the others, so only a missing repository fails the
+	// batch.
+	var result struct {
+		Data struct {
+			Repository map[string]*graphQLPRStatus `json:"repository"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+	if result.Data.Repository == nil {
+		if len(result.Errors) > 0 {
+			return fmt.Errorf("get pr statuses: %s", result.Errors[0].Message)
+		}
+		return fmt.Errorf("get pr statuses: repository %s/%s not found", owner, repo)
+	}
+	for _, n := range prNumbers {
+		pr := result.Data.Repository[fmt.Sprintf("pr%d", n)]
+		if pr == nil {
+			continue
+		}
+		prPolicy, ok := policies[pr.BaseRefName]
+		if !ok {
+			prPolicy, err = c.resolveCheckPolicy(ctx, owner, repo, pr.BaseRefName, policy)
+			if err != nil {
+				return err
+			}
+			policies[pr.BaseRefName] = prPolicy
+		}
+		statuses[n] = pr.status(prPolicy)
+	}
+	return nil
+}
+
+// prStatusFragment selects what GetPRStatuses needs of each PR. The checks
+// come from the head commit's status check rollup, which holds both check
+// runs and legacy commit statuses.
+var prStatusFragment = fmt.Sprintf(`fragment status on PullRequest {
+  merged
+  mergeable
+  mergeStateStatus
+  baseRefName
+  commits(last: 1) {
+    nodes {
+      commit {
+        statusCheckRollup {
+          contexts(first: %d) {
+            pageInfo { hasNextPage }
+            nodes {
+              __typename
+              ... on CheckRun { databaseId name status conclusion detailsUrl }
+              ... on StatusContext { context state targetUrl }
+            }
+          }
+        }
+      }
+    }
+  }
+}`, prStatusMaxContexts)
+
+type graphQLPRStatus struct {
+	Merged           bool   `json:"merged"`
+	Mergeable        string `json:"mergeable"`        // "MERGEABLE", "CONFLICTING", "UNKNOWN"
+	MergeStateStatus string `json:"mergeStateStatus"` // "CLEAN", "DIRTY", "BLOCKED", ...
+	BaseRefName      string `json:"baseRefName"`
+	Commits          struct {
+		Nodes []struct {
+			Commit struct {
+				StatusCheckRollup *struct {
+					Contexts struct {
+						PageInfo struct {
+							HasNextPage bool `json:"hasNextPage"`
+						} `json:"pageInfo"`
+						Nodes []struct {
+							Typename   string `json:"__typename"`
+							DatabaseID int64  `json:"databaseId"`
+							Name       string `json:"name"`
+							Status     string `json:"status"`
+							Conclusion string `json:"conclusion"`
+							DetailsURL string `json:"detailsUrl"`
+							Context    string `json:"context"`
+							State      string `json:"state"`
+							TargetURL  string `json:"targetUrl"`
+						} `json:"nodes"`
+					} `json:"contexts"`
+				} `json:"statusCheckRollup"`
+			} `json:"commit"`
+		} `json:"nodes"`
+	} `json:"commits"`
+}
+
+// status converts the GraphQL result to the values the REST API returns, so
+// it is judged the same way as GetPRMergeability and GetPRCheckStatus.
+func (pr *graphQLPRStatus) status(policy CheckPolicy) *PRStatus {
+	state := strings.ToLower(pr.MergeStateStatus)
+	m := &PRMergeability{MergeableState: state, HasConflicts: state == "dirty", Behind: state == "behind"}
+	switch pr.Mergeable {
+	case "MERGEABLE":
+		m.Mergeable = new(bool)
+		*m.Mergeable = true
+	case "CONFLICTING":
+		m.Mergeable = new(bool)
+	}
+	status := &PRStatus{Merged: pr.Merged, Mergeability: m, BaseRef: pr.BaseRefName}
+
+	if len(pr.Commits.Nodes) == 0 {
+		return status
+	}
+	rollup := pr.Commits.Nodes[0].Commit.StatusCheckRollup
+	if rollup == nil {
+		// No checks or statuses: the repo has no CI configured.
+		status.Checks = buildCheckResult(nil, nil, false, CheckPolicy{})
+		return status
+	}
+	if rollup.Contexts.PageInfo.HasNextPage {
+		return status
+	}
+	var runs []checkRun
+	var contexts []commitContext
+	for _, n := range rollup.Contexts.Nodes {
+		switch n.Typename {
+		case "CheckRun":
+			run := checkRun{ID: n.DatabaseID, Name: n.Name, Status: strings.ToLower(n.Status), HTMLURL: n.DetailsURL}
+			if n.Conclusion != "" {
+				conclusion := strings.ToLower(n.Conclusion)
+				run.Conclusion = &conclusion
+			}
+			runs = append(runs, run)
+		case "StatusContext":
+			contexts = append(contexts, commitContext{Context: n.Context, State: strings.ToLower(n.State), TargetURL: n.TargetURL})
+		}
+	}
+	status.Checks = buildCheckResult(runs, contexts, false, policy)
+	return status
+}
@@ -0,0 +1,81 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetPRStatuses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/graphql", r.URL.Path)
+		var body struct {
+			Query     string            `json:"query"`
+			Variables map[string]string `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Contains(t, body.Query, "pr1: pullRequest(number: 1)")
+		assert.Contains(t, body.Query, "pr3: pullRequest(number: 3)")
+		assert.Equal(t, map[string]string{"owner": "owner", "name": "repo"}, body.Variables)
+
+		_, _ = w.Write([]byte(`{
+			"data": {"repository": {
+				"pr1": {"merged": true, "mergeable": "UNKNOWN", "mergeStateStatus": "UNKNOWN", "commits": {"nodes": []}},
+				"pr2": {"merged": false, "mergeable": "CONFLICTING", "mergeStateStatus": "DIRTY", "commits": {"nodes": [
+					{"commit": {"statusCheckRollup": {"contexts": {"pageInfo": {"hasNextPage": false}, "nodes": [
+						{"__typename": "CheckRun", "databaseId": 11, "name": "test", "status": "COMPLETED", "conclusion": "FAILURE", "detailsUrl": "https://ci/11"},
+						{"__typename": "CheckRun", "databaseId": 12, "name": "lint", "status": "IN_PROGRESS", "conclusion": null},
+						{"__typename": "StatusContext", "context": "verve/review", "state": "PENDING"}
+					]}}}}
+				]}},
+				"pr3": null
+			}},
+			"errors": [{"message": "Could not resolve to a PullRequest with the number of 3."}]
+		}`))
+	}))
+	defer srv.Close()
+	c := newRateLimitedTestClient(srv)
+
+	statuses, err := c.GetPRStatuses(context.Background(), "owner", "repo", []int{1, 2, 3}, CheckPolicy{})
+	require.NoError(t, err)
+	require.Len(t, statuses, 2, "a PR that couldn't be fetched is left out")
+
+	assert.True(t, statuses[1].Merged)
+	assert.Nil(t, statuses[1].Mergeability.Mergeable, "mergeability not yet computed")
+
+	pr := statuses[2]
+	assert.False(t, pr.Merged)
+	require.NotNil(t, pr.Mergeability.Mergeable)
+	assert.False(t, *pr.Mergeability.Mergeable)
+	assert.True(t, pr.Mergeability.HasConflicts)
+	require.NotNil(t, pr.Checks)
+	assert.Equal(t, CheckStatusFailure, pr.Checks.Status)
+	assert.Equal(t, []string{"test"}, pr.Checks.FailedNames)
+	assert.Equal(t, []int64{11}, pr.Checks.FailedRunIDs)
+	assert.Len(t, pr.Checks.Checks, 3)
+}
+
+func TestGraphQLPRStatus_Checks(t *testing.T) {
+	var pr graphQLPRStatus
+	require.NoError(t, json.Unmarshal([]byte(`{"commits": {"nodes": [{"commit": {"statusCheckRollup": null}}]}}`), &pr))
+	assert.Equal(t, CheckStatusSuccess, pr.status(CheckPolicy{}).Checks.Status, "no checks means no CI to wait for")
+
+	require.NoError(t, json.Unmarshal([]byte(`{"commits": {"nodes": [{"commit": {"statusCheckRollup": {"contexts": {"pageInfo": {"hasNextPage": true}, "nodes": []}}}}]}}`), &pr))
+	assert.Nil(t, pr.status(CheckPolicy{}).Checks, "truncated checks are fetched in full")
+}
+
+func TestClient_GetPRStatuses_RepoNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": {"repository": null}, "errors": [{"message": "Could not resolve to a Repository"}]}`))
+	}))
+	defer srv.Close()
+	c := newRateLimitedTestClient(srv)
+
+	_, err := c.GetPRStatuses(context.Background(), "owner", "repo", []int{1}, CheckPolicy{})
+	assert.ErrorContains(t, err, "Could not resolve to a Repository")
+}
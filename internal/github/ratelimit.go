@@ -0,0 +1,206 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// backgroundReserveDivisor sets the share of a rate limit bucket, one
+	// tenth, that background requests leave for urgent ones.
+	backgroundReserveDivisor = 10
+)
+
+// ErrRateLimited is returned for requests not sent because the GitHub rate
+// limit they draw from is exhausted.
+var ErrRateLimited = errors.New("github rate limit exhausted")
+
+// RateLimit is the state of one of GitHub's rate limit buckets, such as
+// "core" for the REST API or "graphql", as last reported by the API.
+type RateLimit struct {
+	Resource  string    `json:"resource"`
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Used      int       `json:"used"`
+	Reset     time.Time `json:"reset"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type backgroundKey struct{}
+
+// Background marks the requests made with the returned context as
+// non-urgent. When the bucket they draw from is nearly spent, they wait for
+// it to reset instead of using up the requests left for urgent calls.
+func Background(ctx context.Context) context.Context {
+	return context.WithValue(ctx, backgroundKey{}, true)
+}
+
+func isBackground(ctx context.Context) bool {
+	background, _ := ctx.Value(backgroundKey{}).(bool)
+	return background
+}
+
+// rateLimiter tracks the rate limits reported by the API and holds back
+// requests that would exceed them.
+type rateLimiter struct {
+	now func() time.Time
+
+	mu         sync.Mutex
+	limits     map[string]RateLimit
+	retryAfter time.Time // Set by a secondary rate limit
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{now: time.Now, limits: map[string]RateLimit{}}
+}
+
+// RateLimits returns the rate limit buckets the client has drawn from, as
+// last reported by the API.
+func (c *Client) RateLimits() []RateLimit {
+	if c == nil || c.limits == nil {
+		return []RateLimit{}
+	}
+	return c.limits.snapshot()
+}
+
+// do sends req through the response cache and within the rate limit.
+// Background requests reuse a cached response while it is fresh; other GET
+// requests with a cached response are sent as conditional requests, which
+// don't count against the rate limit when nothing changed. Urgent requests
+// fail with ErrRateLimited while the bucket is exhausted; background
+// requests wait for it to reset once it is nearly spent.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if c.limits == nil {
+		return c.httpClient.Do(req)
+	}
+
+	key := cacheKey(req)
+	cached, ok := c.cache.get(key)
+	if ok && isBackground(req.Context()) && c.cache.fresh(cached) {
+		return cached.response(req), nil
+	}
+	if err := c.limits.wait(req); err != nil {
+		return nil, err
+	}
+	if ok {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	c.limits.record(resp)
+
+	switch {
+	case ok && resp.StatusCode == http.StatusNotModified:
+		_ = resp.Body.Close()
+		c.cache.revalidated(key)
+		return cached.response(req), nil
+	case key != "" && resp.StatusCode == http.StatusOK && resp.Header.Get("ETag") != "":
+		if err := c.cache.store(key, resp); err != nil {
+			return nil, err
+		}
+	case key == "" && resp.StatusCode < http.StatusBadRequest:
+		// A write may change anything cached for the repo.
+		c.cache.invalidateRepo(req.URL)
+	}
+	return resp, nil
+}
+
+// wait returns once req may be sent.
+func (l *rateLimiter) wait(req *http.Request) error {
+	ctx := req.Context()
+	resource := requestResource(req)
+	background := isBackground(ctx)
+	for {
+		until, reason := l.blockedUntil(resource, background)
+		if until.IsZero() {
+			return nil
+		}
+		if !background {
+			return fmt.Errorf("%w: %s until %s", ErrRateLimited, reason, until.Format(time.RFC3339))
+		}
+		timer := time.NewTimer(until.Sub(l.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// blockedUntil returns when requests for the resource may be sent again, or
+// zero if they may be sent now.
+func (l *rateLimiter) blockedUntil(resource string, background bool) (time.Time, string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	if now.Before(l.retryAfter) {
+		return l.retryAfter, "secondary rate limit"
+	}
+	lim, ok := l.limits[resource]
+	if !ok || !now.Before(lim.Reset) {
+		return time.Time{}, ""
+	}
+	if lim.Remaining <= 0 {
+		return lim.Reset, resource + " rate limit exhausted"
+	}
+	if background && lim.Remaining <= lim.Limit/backgroundReserveDivisor {
+		return lim.Reset, resource + " rate limit reserved for urgent requests"
+	}
+	return time.Time{}, ""
+}
+
+// record updates the rate limits from a response's headers.
+func (l *rateLimiter) record(resp *http.Response) {
+	h := resp.Header
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+
+	if limit, err := strconv.Atoi(h.Get("X-RateLimit-Limit")); err == nil {
+		lim := RateLimit{Resource: h.Get("X-RateLimit-Resource"), Limit: limit, UpdatedAt: now}
+		if lim.Resource == "" {
+			lim.Resource = requestResource(resp.Request)
+		}
+		lim.Remaining, _ = strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+		lim.Used, _ = strconv.Atoi(h.Get("X-RateLimit-Used"))
+		if reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			lim.Reset = time.Unix(reset, 0)
+		}
+		l.limits[lim.Resource] = lim
+	}
+
+	if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
+		if secs, err := strconv.Atoi(h.Get("Retry-After")); err == nil {
+			l.retryAfter = now.Add(time.Duration(secs) * time.Second)
+		}
+	}
+}
+
+func (l *rateLimiter) snapshot() []RateLimit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := make([]RateLimit, 0, len(l.limits))
+	for _, lim := range l.limits {
+		out = append(out, lim)
+	}
+	slices.SortFunc(out, func(a, b RateLimit) int { return strings.Compare(a.Resource, b.Resource) })
+	return out
+}
+
+// requestResource returns the rate limit bucket a request draws from.
+func requestResource(req *http.Request) string {
+	if req != nil && req.URL.Path == "/graphql" {
+		return "graphql"
+	}
+	return "core"
+}
@@ -0,0 +1,100 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRateLimitedTestClient returns a client with rate limiting whose
+// requests are sent to srv.
+func newRateLimitedTestClient(srv *httptest.Server) *Client {
+	c := NewClient("test-token", false)
+	c.httpClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		r = r.Clone(r.Context())
+		r.URL.Scheme = "http"
+		r.URL.Host = srv.Listener.Addr().String()
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+	return c
+}
+
+func TestClient_RateLimits(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Unix()
+	remaining := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		w.Header().Set("X-RateLimit-Used", strconv.Itoa(5000-remaining))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+		w.Header().Set("X-RateLimit-Resource", "core")
+		_, _ = fmt.Fprint(w, `{"default_branch":"main"}`)
+	}))
+	defer srv.Close()
+	c := newRateLimitedTestClient(srv)
+
+	assert.Empty(t, c.RateLimits())
+
+	_, err := c.GetDefaultBranch(context.Background(), "owner", "repo")
+	require.NoError(t, err)
+	limits := c.RateLimits()
+	require.Len(t, limits, 1)
+	assert.Equal(t, "core", limits[0].Resource)
+	assert.Equal(t, 5000, limits[0].Limit)
+	assert.Equal(t, 0, limits[0].Remaining)
+	assert.Equal(t, reset, limits[0].Reset.Unix())
+
+	_, err = c.GetDefaultBranch(context.Background(), "owner", "repo")
+	assert.ErrorIs(t, err, ErrRateLimited, "urgent requests fail fast while the bucket is exhausted")
+
+	ctx, cancel := context.WithTimeout(Background(context.Background()), 50*time.Millisecond)
+	defer cancel()
+	_, err = c.GetDefaultBranch(ctx, "owner", "repo")
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "background requests wait for the reset")
+}
+
+func TestClient_BackgroundReserve(t *testing.T) {
+	c := NewClient("test-token", false)
+	c.limits.limits["core"] = RateLimit{Resource: "core", Limit: 5000, Remaining: 400, Reset: time.Now().Add(time.Hour)}
+
+	until, _ := c.limits.blockedUntil("core", false)
+	assert.True(t, until.IsZero(), "urgent requests may use the reserve")
+	until, _ = c.limits.blockedUntil("core", true)
+	assert.False(t, until.IsZero(), "background requests leave the reserve alone")
+
+	c.limits.limits["core"] = RateLimit{Resource: "core", Limit: 5000, Remaining: 0, Reset: time.Now().Add(-time.Second)}
+	until, _ = c.limits.blockedUntil("core", true)
+	assert.True(t, until.IsZero(), "a bucket past its reset is replenished")
+}
+
+func TestClient_ConditionalRequests(t *testing.T) {
+	var hits, notModified atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = fmt.Fprint(w, `{"default_branch":"main"}`)
+	}))
+	defer srv.Close()
+	c := newRateLimitedTestClient(srv)
+
+	for range 3 {
+		branch, err := c.GetDefaultBranch(context.Background(), "owner", "repo")
+		require.NoError(t, err)
+		assert.Equal(t, "main", branch, "a not modified response replays the cached body")
+	}
+	assert.Equal(t, int32(3), hits.Load())
+	assert.Equal(t, int32(2), notModified.Load())
+}
@@ -0,0 +1,133 @@
+package githubtoken
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// githubAPIURL is the GitHub REST API installation tokens are minted on.
+	githubAPIURL = "https://api.github.com"
+	// installationTokenMargin is how long before it expires an installation
+	// token is replaced, so agents never start with one about to lapse.
+	installationTokenMargin = 10 * time.Minute
+	// appJWTLifetime is how long the JWT a GitHub App authenticates as
+	// itself with is valid. GitHub allows at most 10 minutes.
+	appJWTLifetime = 9 * time.Minute
+)
+
+// newHTTPClient creates the client installation tokens are minted with.
+func newHTTPClient(insecureSkipVerify bool) *http.Client {
+	client := &http.Client{Timeout: 10 * time.Second}
+	if insecureSkipVerify {
+		client.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true, //nolint:gosec // intentional for TLS-intercepting proxies
+			},
+		}
+	}
+	return client
+}
+
+// parseAppKey parses a GitHub App's PEM private key. GitHub issues PKCS#1
+// keys; PKCS#8 keys are accepted too.
+func parseAppKey(pemKey string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, errors.New("secret must be the GitHub App's PEM private key")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.New("secret must be the GitHub App's PEM private key")
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("GitHub App private key must be an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// appJWT signs the JWT a GitHub App authenticates as itself with. It is
+// backdated a minute to allow for clock drift.
+func appJWT(appID int64, key *rsa.PrivateKey, now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": strconv.FormatInt(appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// installationToken mints an installation access token for a GitHub App
+// installation. Tokens last an hour.
+func (s *Service) installationToken(ctx context.Context, appID, installationID int64, privateKey string) (string, time.Time, error) {
+	key, err := parseAppKey(privateKey)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	jwt, err := appJWT(appID, key, time.Now())
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("sign app jwt: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/app/installations/%d/access_tokens", s.apiURL, installationID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, http.NoBody)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", time.Time{}, fmt.Errorf("GitHub API returned status %d: %s", resp.StatusCode, body)
+	}
+
+	var res struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return "", time.Time{}, fmt.Errorf("decode installation token: %w", err)
+	}
+	if res.Token == "" {
+		return "", time.Time{}, errors.New("GitHub API returned no installation token")
+	}
+	return res.Token, res.ExpiresAt, nil
+}
@@ -0,0 +1,87 @@
+package githubtoken
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// plainCipher stores secrets as given.
+type plainCipher struct{}
+
+func (plainCipher) Encrypt(_ context.Context, plaintext string) (string, error) {
+	return plaintext, nil
+}
+func (plainCipher) Decrypt(_ context.Context, value string) (string, error) { return value, nil }
+func (plainCipher) NeedsRewrap(string) bool                                 { return false }
+
+func TestCredentialToken_App(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+
+	var minted atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		if r.URL.Path != "/app/installations/42/access_tokens" {
+			http.NotFound(w, r)
+			return
+		}
+
+		jwt, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		require.True(t, ok)
+		parts := strings.Split(jwt, ".")
+		require.Len(t, parts, 3)
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+		require.NoError(t, err)
+		require.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], sig), "jwt is signed with the app key")
+		claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, err)
+		assert.Contains(t, string(claims), `"iss":"7"`)
+
+		minted.Add(1)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"token":      "ghs_installation",
+			"expires_at": time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+		})
+	}))
+	defer srv.Close()
+
+	s := NewService(nil, plainCipher{}, false)
+	s.apiURL = srv.URL
+	cred := &Credential{ID: NewCredentialID(), Name: "app", Kind: CredentialApp, AppID: 7, InstallationID: 42, Secret: keyPEM}
+
+	token, err := s.credentialToken(context.Background(), cred)
+	require.NoError(t, err)
+	assert.Equal(t, "ghs_installation", token)
+
+	_, err = s.credentialToken(context.Background(), cred)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), minted.Load(), "the installation token is cached until it nears expiry")
+
+	cred.InstallationID = 43
+	_, err = s.credentialToken(context.Background(), cred)
+	assert.ErrorContains(t, err, "status 404", "a changed credential mints a new token")
+}
+
+func TestParseAppKey_Invalid(t *testing.T) {
+	_, err := parseAppKey("not a key")
+	assert.Error(t, err)
+}
@@ -0,0 +1,342 @@
+package githubtoken
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/joshjon/kit/errtag"
+)
+
+// CredentialKind is how a credential authenticates with GitHub.
+type CredentialKind string
+
+const (
+	CredentialToken CredentialKind = "token" // A personal access token
+	CredentialApp   CredentialKind = "app"   // A GitHub App installation
+)
+
+// ErrTagCredentialNotFound tags errors for credential-not-found cases (HTTP 404).
+type ErrTagCredentialNotFound = errtag.NotFound
+
+// ErrTagCredentialConflict tags errors for credential-conflict cases, such as
+// a duplicate name or a repo already covered by another credential (HTTP 409).
+type ErrTagCredentialConflict = errtag.Conflict
+
+// ErrTagInvalidCredential tags errors for credentials that can't be saved as
+// given (HTTP 400).
+type ErrTagInvalidCredential = errtag.InvalidArgument
+
+// repoPattern matches a Credential's Repos entries: "owner/name" or "owner/*".
+var repoPattern = regexp.MustCompile(`^[a-z0-9-]+/([a-z0-9._-]+|\*)$`)
+
+// Credential is a GitHub credential used instead of the global token for the
+// repos it covers, such as a token for another org or a GitHub App
+// installation with its own rate limit.
+type Credential struct {
+	ID   CredentialID   `json:"id"`
+	Name string         `json:"name"`
+	Kind CredentialKind `json:"kind"`
+	// Repos lists the repos the credential covers: "owner/name" for a
+	// single repo and "owner/*" for every repo of an owner. A repo's own
+	// entry wins over its owner's.
+	Repos []string `json:"repos"`
+	// Secret is the personal access token or the app's PEM private key. It
+	// is never returned by the API and is stored encrypted.
+	Secret         string    `json:"-"`
+	AppID          int64     `json:"app_id,omitempty"`
+	InstallationID int64     `json:"installation_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Validate checks the credential's fields. An empty secret is allowed, as an
+// update keeps the current one.
+func (c *Credential) Validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return errors.New("name is required")
+	}
+	for _, r := range c.Repos {
+		if !repoPattern.MatchString(strings.ToLower(r)) {
+			return fmt.Errorf("repos entry %q must be owner/name or owner/*", r)
+		}
+	}
+	switch c.Kind {
+	case CredentialToken:
+		if c.Secret != "" && !IsValidTokenPrefix(c.Secret) {
+			return errors.New("secret must be a GitHub personal access token starting with ghp_ or github_pat_")
+		}
+	case CredentialApp:
+		if c.AppID <= 0 {
+			return errors.New("app_id is required")
+		}
+		if c.InstallationID <= 0 {
+			return errors.New("installation_id is required")
+		}
+		if c.Secret != "" {
+			if _, err := parseAppKey(c.Secret); err != nil {
+				return err
+			}
+		}
+	default:
+		return errors.New("kind must be token or app")
+	}
+	return nil
+}
+
+// CredentialRepository defines the data access methods for GitHub
+// credentials. The credentials it stores and returns carry the encrypted
+// secret.
+type CredentialRepository interface {
+	CreateGitHubCredential(ctx context.Context, c *Credential) error
+	ReadGitHubCredential(ctx context.Context, id CredentialID) (*Credential, error)
+	ListGitHubCredentials(ctx context.Context) ([]*Credential, error)
+	UpdateGitHubCredential(ctx context.Context, c *Credential) error
+	DeleteGitHubCredential(ctx context.Context, id CredentialID) error
+}
+
+// WithCredentials stores GitHub credentials mapped to repos and owners in
+// repo. Their tokens are tried before the global token: a repo's own
+// credential as the "repo" source, then its owner's as the "org" source.
+func WithCredentials(repo CredentialRepository) Option {
+	return func(s *Service) {
+		s.credentials = repo
+		s.sources = append(s.sources,
+			credentialSource{s: s, name: "repo", scope: strings.ToLower},
+			credentialSource{s: s, name: "org", scope: func(repoFullName string) string {
+				owner, _, _ := strings.Cut(strings.ToLower(repoFullName), "/")
+				return owner + "/*"
+			}},
+		)
+	}
+}
+
+// HasCredentials reports whether GitHub credentials can be stored.
+func (s *Service) HasCredentials() bool {
+	return s.credentials != nil
+}
+
+// ListCredentials returns the stored credentials without their secrets.
+func (s *Service) ListCredentials(ctx context.Context) ([]*Credential, error) {
+	creds, err := s.credentials.ListGitHubCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range creds {
+		c.Secret = ""
+	}
+	return creds, nil
+}
+
+// CreateCredential validates, encrypts and stores a new credential. The
+// secret is cleared from c once it is stored.
+func (s *Service) CreateCredential(ctx context.Context, c *Credential) error {
+	if c.Secret == "" {
+		return invalidCredential("secret is required")
+	}
+	if err := s.prepareCredential(ctx, c); err != nil {
+		return err
+	}
+	now := time.Now()
+	c.ID = NewCredentialID()
+	c.CreatedAt, c.UpdatedAt = now, now
+	if err := s.credentials.CreateGitHubCredential(ctx, c); err != nil {
+		return err
+	}
+	c.Secret = ""
+	return nil
+}
+
+// UpdateCredential validates and stores changes to a credential. An empty
+// secret keeps the current one unless the kind changes. The secret is
+// cleared from c once it is stored.
+func (s *Service) UpdateCredential(ctx context.Context, c *Credential) error {
+	current, err := s.credentials.ReadGitHubCredential(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+	if c.Secret == "" && c.Kind != current.Kind {
+		return invalidCredential("secret is required when changing kind")
+	}
+	keepSecret := c.Secret == ""
+	if err := s.prepareCredential(ctx, c); err != nil {
+		return err
+	}
+	if keepSecret {
+		c.Secret = current.Secret
+	}
+	c.CreatedAt = current.CreatedAt
+	c.UpdatedAt = time.Now()
+	if err := s.credentials.UpdateGitHubCredential(ctx, c); err != nil {
+		return err
+	}
+	c.Secret = ""
+	return nil
+}
+
+// DeleteCredential removes a credential. Its repos fall back to their
+// owner's credential or the global token.
+func (s *Service) DeleteCredential(ctx context.Context, id CredentialID) error {
+	if err := s.credentials.DeleteGitHubCredential(ctx, id); err != nil {
+		return err
+	}
+	s.credMu.Lock()
+	defer s.credMu.Unlock()
+	delete(s.credTokens, id.String())
+	return nil
+}
+
+// RewrapCredentials re-encrypts the secrets of credentials encrypted under a
+// previous key under the current one. It reports whether any secret was
+// re-encrypted.
+func (s *Service) RewrapCredentials(ctx context.Context) (bool, error) {
+	if s.credentials == nil {
+		return false, nil
+	}
+	creds, err := s.credentials.ListGitHubCredentials(ctx)
+	if err != nil {
+		return false, err
+	}
+	rewrapped := false
+	for _, c := range creds {
+		if !s.cipher.NeedsRewrap(c.Secret) {
+			continue
+		}
+		plaintext, err := s.cipher.Decrypt(ctx, c.Secret)
+		if err != nil {
+			return rewrapped, fmt.Errorf("decrypt github credential %s: %w", c.Name, err)
+		}
+		if c.Secret, err = s.cipher.Encrypt(ctx, plaintext); err != nil {
+			return rewrapped, fmt.Errorf("encrypt github credential %s: %w", c.Name, err)
+		}
+		// UpdatedAt is left alone: the credential itself is unchanged.
+		if err := s.credentials.UpdateGitHubCredential(ctx, c); err != nil {
+			return rewrapped, err
+		}
+		rewrapped = true
+	}
+	return rewrapped, nil
+}
+
+// prepareCredential normalizes and validates c, checks its repos aren't
+// covered by another credential and encrypts a new secret.
+func (s *Service) prepareCredential(ctx context.Context, c *Credential) error {
+	c.Name = strings.TrimSpace(c.Name)
+	c.Secret = strings.TrimSpace(c.Secret)
+	repos := make([]string, 0, len(c.Repos))
+	for _, r := range c.Repos {
+		if r = strings.ToLower(strings.TrimSpace(r)); r != "" && !slices.Contains(repos, r) {
+			repos = append(repos, r)
+		}
+	}
+	c.Repos = repos
+	if c.Kind == CredentialToken {
+		c.AppID, c.InstallationID = 0, 0
+	}
+	if err := c.Validate(); err != nil {
+		return invalidCredential(err.Error())
+	}
+
+	others, err := s.credentials.ListGitHubCredentials(ctx)
+	if err != nil {
+		return err
+	}
+	for _, other := range others {
+		if other.ID == c.ID {
+			continue
+		}
+		for _, r := range c.Repos {
+			if slices.Contains(other.Repos, r) {
+				msg := fmt.Sprintf("%s is already covered by credential %q", r, other.Name)
+				return errtag.Tag[ErrTagCredentialConflict](errors.New(msg), errtag.WithMsg(msg))
+			}
+		}
+	}
+
+	if c.Secret != "" {
+		encrypted, err := s.cipher.Encrypt(ctx, c.Secret)
+		if err != nil {
+			return fmt.Errorf("encrypt github credential: %w", err)
+		}
+		c.Secret = encrypted
+	}
+	return nil
+}
+
+func invalidCredential(reason string) error {
+	return errtag.Tag[ErrTagInvalidCredential](errors.New(reason), errtag.WithMsg(reason))
+}
+
+// credentialToken returns the token a stored credential authenticates with:
+// the personal access token itself, or an installation token for a GitHub
+// App, which is cached until shortly before it expires.
+func (s *Service) credentialToken(ctx context.Context, c *Credential) (string, error) {
+	s.credMu.Lock()
+	defer s.credMu.Unlock()
+
+	cached, ok := s.credTokens[c.ID.String()]
+	if ok && cached.from == c.fingerprint() &&
+		(cached.expiresAt.IsZero() || time.Now().Before(cached.expiresAt.Add(-installationTokenMargin))) {
+		return cached.token, nil
+	}
+
+	secret, err := s.cipher.Decrypt(ctx, c.Secret)
+	if err != nil {
+		return "", fmt.Errorf("decrypt github credential %s: %w", c.Name, err)
+	}
+	next := cachedToken{from: c.fingerprint(), token: secret}
+	if c.Kind == CredentialApp {
+		next.token, next.expiresAt, err = s.installationToken(ctx, c.AppID, c.InstallationID, secret)
+		if err != nil {
+			return "", fmt.Errorf("github app credential %s: %w", c.Name, err)
+		}
+	}
+	if s.credTokens == nil {
+		s.credTokens = map[string]cachedToken{}
+	}
+	s.credTokens[c.ID.String()] = next
+	return next.token, nil
+}
+
+// cachedToken is the token of a stored credential. It is replaced when the
+// credential changes or, for an installation token, before it expires.
+type cachedToken struct {
+	from      string // The fingerprint of the credential the token is for
+	token     string
+	expiresAt time.Time // Zero for personal access tokens, which don't expire
+}
+
+// fingerprint identifies what a stored credential authenticates as, to tell
+// when its cached token is stale.
+func (c *Credential) fingerprint() string {
+	return fmt.Sprintf("%s:%d:%d:%s", c.Kind, c.AppID, c.InstallationID, c.Secret)
+}
+
+// credentialSource supplies the tokens of stored credentials covering a repo
+// one way: by its name or by its owner.
+type credentialSource struct {
+	s    *Service
+	name string
+	// scope returns the Repos entry that covers the repo this way.
+	scope func(repoFullName string) string
+}
+
+func (src credentialSource) Name() string { return src.name }
+
+func (src credentialSource) TokenFor(ctx context.Context, repoFullName string) (string, error) {
+	creds, err := src.s.credentials.ListGitHubCredentials(ctx)
+	if err != nil {
+		return "", err
+	}
+	want := src.scope(repoFullName)
+	for _, c := range creds {
+		if slices.Contains(c.Repos, want) {
+			return src.s.credentialToken(ctx, c)
+		}
+	}
+	return "", nil
+}
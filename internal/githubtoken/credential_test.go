@@ -0,0 +1,98 @@
+package githubtoken_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/joshjon/kit/errtag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/githubtoken"
+	"github.com/vervesh/verve/internal/sqlite"
+)
+
+func newTestServiceWithCredentials(t *testing.T) *githubtoken.Service {
+	t.Helper()
+	db := sqlite.NewTestDB(t)
+	return githubtoken.NewService(sqlite.NewGitHubTokenRepository(db), newEnvelope(t), false,
+		githubtoken.WithCredentials(sqlite.NewGitHubCredentialRepository(db)))
+}
+
+func TestService_Credentials(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestServiceWithCredentials(t)
+	require.NoError(t, svc.SaveToken(ctx, "ghp_global1234"))
+
+	org := &githubtoken.Credential{Name: "acme", Kind: githubtoken.CredentialToken, Repos: []string{"Acme/*"}, Secret: "ghp_acmeorg1"}
+	require.NoError(t, svc.CreateCredential(ctx, org))
+	assert.Empty(t, org.Secret, "the secret is cleared once stored")
+	assert.Equal(t, []string{"acme/*"}, org.Repos, "repos are normalized")
+
+	api := &githubtoken.Credential{Name: "acme api", Kind: githubtoken.CredentialToken, Repos: []string{"acme/api"}, Secret: "github_pat_acmeapi"}
+	require.NoError(t, svc.CreateCredential(ctx, api))
+
+	token, err := svc.TokenFor(ctx, "acme/api")
+	require.NoError(t, err)
+	assert.Equal(t, "github_pat_acmeapi", token, "a repo's own credential wins over its owner's")
+
+	token, err = svc.TokenFor(ctx, "ACME/web")
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_acmeorg1", token)
+
+	token, err = svc.TokenFor(ctx, "other/web")
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_global1234", token)
+
+	res := svc.Resolve(ctx, "acme/web")
+	assert.Equal(t, "org", res.Source)
+
+	// Updating without a secret keeps the current one.
+	org.Repos = []string{"acme/*", "acme-labs/*"}
+	require.NoError(t, svc.UpdateCredential(ctx, org))
+	token, err = svc.TokenFor(ctx, "acme-labs/x")
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_acmeorg1", token)
+
+	creds, err := svc.ListCredentials(ctx)
+	require.NoError(t, err)
+	require.Len(t, creds, 2)
+	for _, c := range creds {
+		assert.Empty(t, c.Secret, "secrets are never listed")
+	}
+
+	require.NoError(t, svc.DeleteCredential(ctx, api.ID))
+	token, err = svc.TokenFor(ctx, "acme/api")
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_acmeorg1", token, "falls back to the owner's credential")
+}
+
+func TestService_CreateCredential_Invalid(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestServiceWithCredentials(t)
+	require.NoError(t, svc.CreateCredential(ctx, &githubtoken.Credential{
+		Name: "acme", Kind: githubtoken.CredentialToken, Repos: []string{"acme/*"}, Secret: "ghp_acmeorg1",
+	}))
+
+	tests := []struct {
+		name string
+		cred githubtoken.Credential
+	}{
+		{"no secret", githubtoken.Credential{Name: "a", Kind: githubtoken.CredentialToken}},
+		{"bad token", githubtoken.Credential{Name: "a", Kind: githubtoken.CredentialToken, Secret: "nope"}},
+		{"bad repo", githubtoken.Credential{Name: "a", Kind: githubtoken.CredentialToken, Secret: "ghp_x", Repos: []string{"acme"}}},
+		{"app without ids", githubtoken.Credential{Name: "a", Kind: githubtoken.CredentialApp, Secret: "key"}},
+		{"bad kind", githubtoken.Credential{Name: "a", Kind: "oauth", Secret: "ghp_x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.CreateCredential(ctx, &tt.cred)
+			assert.True(t, errtag.HasTag[githubtoken.ErrTagInvalidCredential](err), "got %v", err)
+		})
+	}
+
+	err := svc.CreateCredential(ctx, &githubtoken.Credential{
+		Name: "acme again", Kind: githubtoken.CredentialToken, Repos: []string{"acme/*"}, Secret: "ghp_acmeorg2",
+	})
+	assert.True(t, errtag.HasTag[githubtoken.ErrTagCredentialConflict](err), "a repo can only be covered by one credential")
+}
@@ -0,0 +1,45 @@
+package githubtoken
+
+import (
+	"github.com/cohesivestack/valgo"
+	"github.com/joshjon/kit/id"
+	"go.jetify.com/typeid"
+
+	"github.com/vervesh/verve/internal/msgcat"
+)
+
+type credentialPrefix struct{}
+
+func (credentialPrefix) Prefix() string { return "ghc" }
+
+// CredentialID is the unique identifier for a Credential.
+type CredentialID struct {
+	typeid.TypeID[credentialPrefix]
+}
+
+// NewCredentialID generates a new unique CredentialID.
+func NewCredentialID() CredentialID {
+	return id.New[CredentialID]()
+}
+
+// ParseCredentialID parses a string into a CredentialID.
+func ParseCredentialID(s string) (CredentialID, error) {
+	return id.Parse[CredentialID](s)
+}
+
+// MustParseCredentialID parses a string into a CredentialID, panicking on
+// failure.
+func MustParseCredentialID(s string) CredentialID {
+	return id.MustParse[CredentialID](s)
+}
+
+// CredentialIDValidator returns a valgo Validator that checks whether the
+// given string is a valid CredentialID.
+func CredentialIDValidator(identifier string, nameAndTitle ...string) *valgo.ValidatorString[string] {
+	return valgo.String(identifier, nameAndTitle...).
+		Not().Blank().
+		Passing(func(_ string) bool {
+			_, err := ParseCredentialID(identifier)
+			return err == nil
+		}, msgcat.Text(msgcat.ErrInvalidID, "github credential"))
+}
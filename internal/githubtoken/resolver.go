@@ -0,0 +1,126 @@
+package githubtoken
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SourceGlobal is the name of the instance-wide token in resolutions. It is
+// always the last source tried.
+const SourceGlobal = "global"
+
+// Source supplies GitHub tokens narrower than the global token, such as a
+// token for a single repo or for every repo of an org. Sources are tried in
+// the order they are passed to WithSources, before the global token.
+type Source interface {
+	// Name identifies the source in resolutions, e.g. "repo" or "org".
+	Name() string
+	// TokenFor returns the token the source holds for the repo, or "" if it
+	// has none.
+	TokenFor(ctx context.Context, repoFullName string) (string, error)
+}
+
+// Option configures a Service.
+type Option func(*Service)
+
+// WithSources adds token sources tried, in order, before the global token.
+func WithSources(sources ...Source) Option {
+	return func(s *Service) {
+		s.sources = append(s.sources, sources...)
+	}
+}
+
+// Resolution describes which credential a repo's agents and API calls use.
+// It never includes the token itself.
+type Resolution struct {
+	RepoFullName string `json:"repo_full_name"`
+	// Source is the name of the source that supplied the token, or empty
+	// when no source has one.
+	Source string `json:"source,omitempty"`
+	// TokenType is "classic" or "fine_grained" for personal access tokens
+	// and "installation" for GitHub App installation tokens.
+	TokenType string `json:"token_type,omitempty"`
+	// TokenSuffix is the last four characters of the token, to tell tokens
+	// apart.
+	TokenSuffix string `json:"token_suffix,omitempty"`
+	// Chain lists every source in resolution order with whether it has a
+	// token for the repo. Sources after the one that supplied the token are
+	// not consulted.
+	Chain []SourceResult `json:"chain"`
+}
+
+// SourceResult is one step of a Resolution.
+type SourceResult struct {
+	Source    string `json:"source"`
+	HasToken  bool   `json:"has_token"`
+	Consulted bool   `json:"consulted"`
+	Error     string `json:"error,omitempty"`
+}
+
+// TokenFor returns the token to use for the repo: the first source with a
+// token for it, falling back to the global token. It returns "" when no
+// source has a token. A failing source is an error rather than a reason to
+// fall back to a broader token.
+func (s *Service) TokenFor(ctx context.Context, repoFullName string) (string, error) {
+	for _, src := range s.sources {
+		token, err := src.TokenFor(ctx, repoFullName)
+		if err != nil {
+			return "", fmt.Errorf("%s github token: %w", src.Name(), err)
+		}
+		if token != "" {
+			return token, nil
+		}
+	}
+	return s.GetToken(), nil
+}
+
+// Resolve reports which source TokenFor would use for the repo and what
+// every source in the chain holds for it.
+func (s *Service) Resolve(ctx context.Context, repoFullName string) *Resolution {
+	res := &Resolution{RepoFullName: repoFullName}
+	var token string
+	resolved := false
+	for _, src := range s.sources {
+		step := SourceResult{Source: src.Name()}
+		if !resolved {
+			step.Consulted = true
+			t, err := src.TokenFor(ctx, repoFullName)
+			switch {
+			case err != nil:
+				// TokenFor fails here, so no later source is used.
+				step.Error = err.Error()
+				resolved = true
+			case t != "":
+				step.HasToken = true
+				res.Source, token, resolved = src.Name(), t, true
+			}
+		}
+		res.Chain = append(res.Chain, step)
+	}
+
+	global := SourceResult{Source: SourceGlobal, HasToken: s.HasToken(), Consulted: !resolved}
+	res.Chain = append(res.Chain, global)
+	if global.Consulted && global.HasToken {
+		token = s.GetToken()
+		res.Source = SourceGlobal
+	}
+	if res.Source != "" {
+		res.TokenType = tokenType(token)
+		res.TokenSuffix = token[max(0, len(token)-4):]
+	}
+	return res
+}
+
+func tokenType(token string) string {
+	switch {
+	case strings.HasPrefix(token, fineGrainedTokenPrefix):
+		return "fine_grained"
+	case strings.HasPrefix(token, classicTokenPrefix):
+		return "classic"
+	case strings.HasPrefix(token, installationTokenPrefix):
+		return "installation"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,90 @@
+package githubtoken_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/githubtoken"
+	"github.com/vervesh/verve/internal/sqlite"
+)
+
+// mapSource holds tokens keyed by repo full name.
+type mapSource struct {
+	name   string
+	tokens map[string]string
+	err    error
+}
+
+func (s *mapSource) Name() string { return s.name }
+
+func (s *mapSource) TokenFor(_ context.Context, repoFullName string) (string, error) {
+	return s.tokens[repoFullName], s.err
+}
+
+func newTestServiceWithSources(t *testing.T, sources ...githubtoken.Source) *githubtoken.Service {
+	t.Helper()
+	db := sqlite.NewTestDB(t)
+	return githubtoken.NewService(sqlite.NewGitHubTokenRepository(db), newEnvelope(t), false, githubtoken.WithSources(sources...))
+}
+
+func TestService_TokenFor(t *testing.T) {
+	ctx := context.Background()
+	repoSource := &mapSource{name: "repo", tokens: map[string]string{"owner/a": "ghp_repoaaaa"}}
+	orgSource := &mapSource{name: "org", tokens: map[string]string{"owner/a": "ghp_orgaaaa", "owner/b": "github_pat_orgbbbb"}}
+	svc := newTestServiceWithSources(t, repoSource, orgSource)
+	require.NoError(t, svc.SaveToken(ctx, "ghp_global1234"))
+
+	token, err := svc.TokenFor(ctx, "owner/a")
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_repoaaaa", token, "the narrowest source wins")
+
+	token, err = svc.TokenFor(ctx, "owner/b")
+	require.NoError(t, err)
+	assert.Equal(t, "github_pat_orgbbbb", token)
+
+	token, err = svc.TokenFor(ctx, "other/c")
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_global1234", token, "falls back to the global token")
+
+	orgSource.err = errors.New("boom")
+	_, err = svc.TokenFor(ctx, "other/c")
+	assert.ErrorContains(t, err, "org github token: boom", "a failing source must not fall back to a broader token")
+}
+
+func TestService_Resolve(t *testing.T) {
+	ctx := context.Background()
+	repoSource := &mapSource{name: "repo", tokens: map[string]string{"owner/a": "ghp_repoaaaa"}}
+	svc := newTestServiceWithSources(t, repoSource)
+
+	res := svc.Resolve(ctx, "owner/b")
+	assert.Empty(t, res.Source, "no source has a token")
+	assert.Equal(t, []githubtoken.SourceResult{
+		{Source: "repo", Consulted: true},
+		{Source: githubtoken.SourceGlobal, Consulted: true},
+	}, res.Chain)
+
+	require.NoError(t, svc.SaveToken(ctx, "github_pat_global9876"))
+	res = svc.Resolve(ctx, "owner/b")
+	assert.Equal(t, githubtoken.SourceGlobal, res.Source)
+	assert.Equal(t, "fine_grained", res.TokenType)
+	assert.Equal(t, "9876", res.TokenSuffix)
+
+	res = svc.Resolve(ctx, "owner/a")
+	assert.Equal(t, "repo", res.Source)
+	assert.Equal(t, "classic", res.TokenType)
+	assert.Equal(t, "aaaa", res.TokenSuffix)
+	assert.Equal(t, []githubtoken.SourceResult{
+		{Source: "repo", HasToken: true, Consulted: true},
+		{Source: githubtoken.SourceGlobal, HasToken: true},
+	}, res.Chain, "sources after the one used are not consulted")
+
+	repoSource.err = errors.New("boom")
+	res = svc.Resolve(ctx, "owner/a")
+	assert.Empty(t, res.Source)
+	assert.Equal(t, "boom", res.Chain[0].Error)
+	assert.False(t, res.Chain[1].Consulted)
+}
@@ -0,0 +1,95 @@
+package githubtoken
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/vervesh/verve/internal/github"
+)
+
+const (
+	// secretReadTimeout bounds a read of the token from the secrets manager.
+	secretReadTimeout = 10 * time.Second
+	// secretRetryInterval is how soon a failed read is retried.
+	secretRetryInterval = 30 * time.Second
+)
+
+// ErrTokenManaged is returned when saving or deleting a token that is read
+// from a secrets manager.
+var ErrTokenManaged = errors.New("github token is managed by a secrets manager")
+
+// SecretReader reads the token from a secrets manager.
+// keyprovider.SecretReader implements it.
+type SecretReader interface {
+	ReadSecret(ctx context.Context, ref string) (string, error)
+}
+
+// WithSecret reads the global token from a secrets manager instead of the
+// database. It is read on first use and again once ttl has passed, so a
+// token rotated in the secrets manager is picked up without a restart.
+func WithSecret(reader SecretReader, ref string, ttl time.Duration) Option {
+	return func(s *Service) {
+		s.secret = reader
+		s.secretRef = ref
+		s.secretTTL = ttl
+	}
+}
+
+// Managed reports whether the token is read from a secrets manager, in which
+// case it can't be saved or deleted through Verve.
+func (s *Service) Managed() bool {
+	return s.secret != nil
+}
+
+// SecretError returns the error of the last failed read from the secrets
+// manager, or nil. The token read before it, if any, is still used.
+func (s *Service) SecretError() error {
+	s.refreshSecret()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.secretErr
+}
+
+// refreshSecret reads the token from the secrets manager when it hasn't been
+// read yet or its TTL has passed. Reads are serialized so callers arriving
+// together share one.
+func (s *Service) refreshSecret() {
+	if s.secret == nil || s.secretFresh() {
+		return
+	}
+	s.readMu.Lock()
+	defer s.readMu.Unlock()
+	if s.secretFresh() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretReadTimeout)
+	defer cancel()
+	token, err := s.secret.ReadSecret(ctx, s.secretRef)
+	token = strings.TrimSpace(token)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.secretErr = err
+		s.secretExpiry = time.Now().Add(min(s.secretTTL, secretRetryInterval))
+		return
+	}
+	s.secretErr = nil
+	s.secretExpiry = time.Now().Add(s.secretTTL)
+	if token != s.token {
+		s.token = token
+		s.client = nil
+		if token != "" {
+			s.client = github.NewClient(token, s.insecureSkipVerify)
+		}
+	}
+}
+
+func (s *Service) secretFresh() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return time.Now().Before(s.secretExpiry)
+}
@@ -3,11 +3,11 @@ package githubtoken
 import (
 	"context"
 	"errors"
+	"net/http"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/vervesh/verve/internal/crypto"
 	"github.com/vervesh/verve/internal/github"
 )
 
@@ -16,6 +16,8 @@ const (
 	classicTokenPrefix = "ghp_"
 	// fineGrainedTokenPrefix is the prefix for fine-grained personal access tokens.
 	fineGrainedTokenPrefix = "github_pat_" //nolint:gosec // not a credential, just a prefix pattern
+	// installationTokenPrefix is the prefix for GitHub App installation tokens.
+	installationTokenPrefix = "ghs_" //nolint:gosec // not a credential, just a prefix pattern
 )
 
 // IsValidTokenPrefix checks whether a token has a recognised GitHub PAT prefix.
@@ -33,30 +35,74 @@ type Repository interface {
 	DeleteGitHubToken(ctx context.Context) error
 }
 
+// Cipher encrypts the token at rest. *keyprovider.Envelope implements it.
+type Cipher interface {
+	Encrypt(ctx context.Context, plaintext string) (string, error)
+	Decrypt(ctx context.Context, value string) (string, error)
+	// NeedsRewrap reports whether value was encrypted under a key other
+	// than the current one and should be encrypted again.
+	NeedsRewrap(value string) bool
+}
+
 // Service manages the GitHub token lifecycle: encryption, storage, and
 // in-memory caching of the decrypted token and GitHub client.
 type Service struct {
 	repo               Repository
-	key                []byte
+	cipher             Cipher
 	insecureSkipVerify bool
-
-	mu     sync.RWMutex
-	token  string
-	client *github.Client
+	sources            []Source
+	credentials        CredentialRepository // nil unless WithCredentials is used
+	httpClient         *http.Client
+	apiURL             string
+
+	// Set when the token is read from a secrets manager.
+	secret    SecretReader
+	secretRef string
+	secretTTL time.Duration
+	readMu    sync.Mutex // Serializes reads from the secrets manager
+
+	mu           sync.RWMutex
+	token        string
+	client       *github.Client
+	secretExpiry time.Time // When the token must be read from the secrets manager again
+	secretErr    error
+
+	credMu     sync.Mutex // Serializes minting of installation tokens
+	credTokens map[string]cachedToken
 }
 
-// NewService creates a new GitHubTokenService.
-func NewService(repo Repository, encryptionKey []byte, insecureSkipVerify bool) *Service {
-	return &Service{
+// NewService creates a new GitHubTokenService. cipher may be nil when the
+// token is read from a secrets manager.
+func NewService(repo Repository, cipher Cipher, insecureSkipVerify bool, opts ...Option) *Service {
+	s := &Service{
 		repo:               repo,
-		key:                encryptionKey,
+		cipher:             cipher,
 		insecureSkipVerify: insecureSkipVerify,
+		httpClient:         newHTTPClient(insecureSkipVerify),
+		apiURL:             githubAPIURL,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // Load reads the encrypted token from the database and hydrates the in-memory
-// cache. Call this on server startup. If no token is stored, this is a no-op.
+// cache. Call this on server startup. If no token is stored, this is a no-op,
+// as it is when the token is read from a secrets manager on first use.
+// A token or credential encrypted under a previous key is re-encrypted
+// under the current one, completing a key rotation.
 func (s *Service) Load(ctx context.Context) error {
+	if _, err := s.RewrapCredentials(ctx); err != nil {
+		return err
+	}
+	if s.Managed() {
+		return nil
+	}
+	if _, err := s.Rewrap(ctx); err != nil {
+		return err
+	}
+
 	encrypted, err := s.repo.ReadGitHubToken(ctx)
 	if err != nil {
 		if errors.Is(err, ErrTokenNotFound) {
@@ -65,7 +111,7 @@ func (s *Service) Load(ctx context.Context) error {
 		return err
 	}
 
-	plaintext, err := crypto.Decrypt(s.key, encrypted)
+	plaintext, err := s.cipher.Decrypt(ctx, encrypted)
 	if err != nil {
 		return err
 	}
@@ -77,10 +123,45 @@ func (s *Service) Load(ctx context.Context) error {
 	return nil
 }
 
+// Rewrap re-encrypts the stored token under the current key if it was
+// encrypted under a previous one. It reports whether the token was
+// re-encrypted.
+func (s *Service) Rewrap(ctx context.Context) (bool, error) {
+	if s.Managed() {
+		return false, nil
+	}
+	encrypted, err := s.repo.ReadGitHubToken(ctx)
+	if err != nil {
+		if errors.Is(err, ErrTokenNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !s.cipher.NeedsRewrap(encrypted) {
+		return false, nil
+	}
+
+	plaintext, err := s.cipher.Decrypt(ctx, encrypted)
+	if err != nil {
+		return false, err
+	}
+	rewrapped, err := s.cipher.Encrypt(ctx, plaintext)
+	if err != nil {
+		return false, err
+	}
+	if err := s.repo.UpsertGitHubToken(ctx, rewrapped, time.Now()); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // SaveToken encrypts the token, stores it in the database, and updates the
 // in-memory cache.
 func (s *Service) SaveToken(ctx context.Context, plaintext string) error {
-	encrypted, err := crypto.Encrypt(s.key, plaintext)
+	if s.Managed() {
+		return ErrTokenManaged
+	}
+	encrypted, err := s.cipher.Encrypt(ctx, plaintext)
 	if err != nil {
 		return err
 	}
@@ -97,8 +178,10 @@ func (s *Service) SaveToken(ctx context.Context, plaintext string) error {
 }
 
 // GetToken returns the cached decrypted token. Returns empty string if no
-// token is configured.
+// token is configured. A token from a secrets manager is read again once
+// its TTL has passed.
 func (s *Service) GetToken() string {
+	s.refreshSecret()
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	return s.token
@@ -107,6 +190,7 @@ func (s *Service) GetToken() string {
 // GetClient returns the cached GitHub client. Returns nil if no token is
 // configured.
 func (s *Service) GetClient() *github.Client {
+	s.refreshSecret()
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	return s.client
@@ -114,6 +198,7 @@ func (s *Service) GetClient() *github.Client {
 
 // HasToken reports whether a GitHub token is currently configured.
 func (s *Service) HasToken() bool {
+	s.refreshSecret()
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	return s.token != ""
@@ -121,6 +206,7 @@ func (s *Service) HasToken() bool {
 
 // IsFineGrained reports whether the configured token is a fine-grained PAT.
 func (s *Service) IsFineGrained() bool {
+	s.refreshSecret()
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	return strings.HasPrefix(s.token, fineGrainedTokenPrefix)
@@ -129,6 +215,9 @@ func (s *Service) IsFineGrained() bool {
 // DeleteToken removes the token from the database and clears the in-memory
 // cache.
 func (s *Service) DeleteToken(ctx context.Context) error {
+	if s.Managed() {
+		return ErrTokenManaged
+	}
 	if err := s.repo.DeleteGitHubToken(ctx); err != nil {
 		return err
 	}
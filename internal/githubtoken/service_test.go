@@ -2,6 +2,7 @@ package githubtoken_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 
 	"github.com/vervesh/verve/internal/crypto"
 	"github.com/vervesh/verve/internal/githubtoken"
+	"github.com/vervesh/verve/internal/keyprovider"
 	"github.com/vervesh/verve/internal/sqlite"
 )
 
@@ -17,18 +19,25 @@ func validKey() []byte {
 	return []byte("0123456789abcdef0123456789abcdef")
 }
 
+func newEnvelope(t *testing.T) *keyprovider.Envelope {
+	t.Helper()
+	envelope, err := keyprovider.New(keyprovider.Config{EncryptionKey: validKey()})
+	require.NoError(t, err)
+	return envelope
+}
+
 func newTestService(t *testing.T) *githubtoken.Service {
 	t.Helper()
 	db := sqlite.NewTestDB(t)
 	repo := sqlite.NewGitHubTokenRepository(db)
-	return githubtoken.NewService(repo, validKey(), false)
+	return githubtoken.NewService(repo, newEnvelope(t), false)
 }
 
 func newTestServiceAndRepo(t *testing.T) (*githubtoken.Service, *sqlite.GitHubTokenRepository) {
 	t.Helper()
 	db := sqlite.NewTestDB(t)
 	repo := sqlite.NewGitHubTokenRepository(db)
-	svc := githubtoken.NewService(repo, validKey(), false)
+	svc := githubtoken.NewService(repo, newEnvelope(t), false)
 	return svc, repo
 }
 
@@ -112,12 +121,19 @@ func TestService_Load(t *testing.T) {
 	require.NoError(t, repo.UpsertGitHubToken(context.Background(), encrypted, time.Now()))
 
 	// Create a fresh service and load from the DB
-	svc := githubtoken.NewService(repo, key, false)
+	envelope := newEnvelope(t)
+	svc := githubtoken.NewService(repo, envelope, false)
 	err = svc.Load(context.Background())
 	require.NoError(t, err, "load")
 
 	assert.Equal(t, "ghp_loaded_token", svc.GetToken())
 	assert.True(t, svc.HasToken(), "expected HasToken to return true after load")
+
+	// The token encrypted before envelope encryption is rewrapped on load.
+	stored, err := repo.ReadGitHubToken(context.Background())
+	require.NoError(t, err)
+	assert.NotEqual(t, encrypted, stored)
+	assert.False(t, envelope.NeedsRewrap(stored))
 }
 
 func TestService_Load_NoToken(t *testing.T) {
@@ -128,3 +144,52 @@ func TestService_Load_NoToken(t *testing.T) {
 
 	assert.False(t, svc.HasToken(), "expected HasToken to return false when no token stored")
 }
+
+// fakeSecretReader serves the token held in value, failing when err is set.
+type fakeSecretReader struct {
+	value string
+	err   error
+	reads int
+}
+
+func (r *fakeSecretReader) ReadSecret(_ context.Context, ref string) (string, error) {
+	r.reads++
+	if ref != "verve/github" {
+		return "", errors.New("unknown secret " + ref)
+	}
+	return r.value, r.err
+}
+
+func TestService_Secret(t *testing.T) {
+	reader := &fakeSecretReader{value: "ghp_from_vault\n"}
+	svc := githubtoken.NewService(nil, nil, false, githubtoken.WithSecret(reader, "verve/github", time.Hour))
+	ctx := context.Background()
+
+	require.NoError(t, svc.Load(ctx))
+	assert.Equal(t, 0, reader.reads, "the secret is read on first use")
+
+	assert.True(t, svc.Managed())
+	assert.Equal(t, "ghp_from_vault", svc.GetToken())
+	assert.NotNil(t, svc.GetClient())
+	assert.Equal(t, 1, reader.reads, "the secret is cached until its TTL passes")
+
+	assert.ErrorIs(t, svc.SaveToken(ctx, "ghp_other"), githubtoken.ErrTokenManaged)
+	assert.ErrorIs(t, svc.DeleteToken(ctx), githubtoken.ErrTokenManaged)
+}
+
+func TestService_Secret_Refresh(t *testing.T) {
+	reader := &fakeSecretReader{value: "ghp_first"}
+	svc := githubtoken.NewService(nil, nil, false, githubtoken.WithSecret(reader, "verve/github", 0))
+
+	assert.Equal(t, "ghp_first", svc.GetToken())
+
+	// A rotated token is picked up once the TTL has passed.
+	reader.value = "github_pat_second"
+	assert.Equal(t, "github_pat_second", svc.GetToken())
+	assert.True(t, svc.IsFineGrained())
+
+	// A failed read keeps the token read before it.
+	reader.err = errors.New("permission denied")
+	assert.Equal(t, "github_pat_second", svc.GetToken())
+	assert.ErrorContains(t, svc.SecretError(), "permission denied")
+}
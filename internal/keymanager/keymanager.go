@@ -18,6 +18,26 @@ const (
 
 type config struct {
 	EncryptionKey string `json:"encryption_key"`
+	// APIURL and APIKey are read by the CLI's client commands; the server
+	// never writes them.
+	APIURL string `json:"api_url,omitempty"`
+	APIKey string `json:"api_key,omitempty"`
+}
+
+// ClientConfig is where the CLI's client commands reach the API server.
+type ClientConfig struct {
+	APIURL string
+	APIKey string
+}
+
+// LoadClientConfig reads the API server URL and key stored in the config
+// file. Fields are empty when the file or the fields are absent.
+func LoadClientConfig() (ClientConfig, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return ClientConfig{}, err
+	}
+	return ClientConfig{APIURL: cfg.APIURL, APIKey: cfg.APIKey}, nil
 }
 
 // ResolveEncryptionKey determines the encryption key to use.
@@ -89,26 +109,40 @@ func configPath() string {
 }
 
 func loadKey() (string, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return "", err
+	}
+	return cfg.EncryptionKey, nil
+}
+
+func loadConfig() (config, error) {
 	data, err := os.ReadFile(configPath())
 	if err != nil {
 		if os.IsNotExist(err) {
-			return "", nil
+			return config{}, nil
 		}
-		return "", err
+		return config{}, err
 	}
 	var cfg config
 	if err := json.Unmarshal(data, &cfg); err != nil {
-		return "", fmt.Errorf("parse config %s: %w", configPath(), err)
+		return config{}, fmt.Errorf("parse config %s: %w", configPath(), err)
 	}
-	return cfg.EncryptionKey, nil
+	return cfg, nil
 }
 
+// storeKey writes the key to the config file, keeping its other fields.
 func storeKey(key string) error {
 	dir := configDir()
 	if err := os.MkdirAll(dir, 0o700); err != nil {
 		return err
 	}
-	data, err := json.MarshalIndent(config{EncryptionKey: key}, "", "  ")
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	cfg.EncryptionKey = key
+	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,159 @@
+package keyprovider
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AWSConfig configures an AWSKMSProvider.
+type AWSConfig struct {
+	// KeyID is the KMS key ID, ARN or alias. Automatic key rotation in KMS
+	// is transparent: earlier key material still unwraps.
+	KeyID           string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // Set for temporary credentials
+}
+
+// AWSKMSProvider wraps data keys with an AWS KMS key. Requests are signed
+// with static credentials; instance and task roles are not resolved.
+type AWSKMSProvider struct {
+	cfg      AWSConfig
+	client   *http.Client
+	endpoint string
+	now      func() time.Time
+}
+
+// NewAWSKMSProvider creates an AWSKMSProvider. A nil client uses a default
+// one.
+func NewAWSKMSProvider(cfg AWSConfig, client *http.Client) (*AWSKMSProvider, error) {
+	if cfg.KeyID == "" || cfg.Region == "" {
+		return nil, errors.New("aws kms key provider requires a key ID and region")
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, errors.New("aws kms key provider requires AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	return &AWSKMSProvider{
+		cfg:      cfg,
+		client:   client,
+		endpoint: "https://kms." + cfg.Region + ".amazonaws.com/",
+		now:      time.Now,
+	}, nil
+}
+
+func (p *AWSKMSProvider) KeyID() string {
+	return ProviderAWSKMS + ":" + p.cfg.KeyID
+}
+
+func (p *AWSKMSProvider) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	in := struct {
+		KeyID     string `json:"KeyId"`
+		Plaintext []byte `json:"Plaintext"`
+	}{p.cfg.KeyID, dataKey}
+	var res struct {
+		CiphertextBlob []byte `json:"CiphertextBlob"`
+	}
+	if err := p.call(ctx, "Encrypt", in, &res); err != nil {
+		return nil, err
+	}
+	return res.CiphertextBlob, nil
+}
+
+func (p *AWSKMSProvider) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	in := struct {
+		KeyID          string `json:"KeyId"`
+		CiphertextBlob []byte `json:"CiphertextBlob"`
+	}{p.cfg.KeyID, wrapped}
+	var res struct {
+		Plaintext []byte `json:"Plaintext"`
+	}
+	if err := p.call(ctx, "Decrypt", in, &res); err != nil {
+		return nil, err
+	}
+	return res.Plaintext, nil
+}
+
+func (p *AWSKMSProvider) call(ctx context.Context, action string, in any, out any) error {
+	req, body, err := newJSONRequest(ctx, p.endpoint, in)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService."+action)
+	signAWS(req, body, p.cfg, "kms", p.now())
+	return doJSON(p.client, req, out)
+}
+
+// signAWS adds an AWS Signature Version 4 Authorization header for service
+// to req, a JSON protocol request with an X-Amz-Target header.
+func signAWS(req *http.Request, body []byte, cfg AWSConfig, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	if cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", cfg.SessionToken)
+	}
+
+	// Headers must be listed in sorted order.
+	headers := []string{"content-type", "host", "x-amz-date"}
+	if cfg.SessionToken != "" {
+		headers = append(headers, "x-amz-security-token")
+	}
+	headers = append(headers, "x-amz-target")
+	var canonicalHeaders strings.Builder
+	for _, h := range headers {
+		v := req.Header.Get(h)
+		if h == "host" {
+			v = req.URL.Host
+		}
+		canonicalHeaders.WriteString(h + ":" + strings.TrimSpace(v) + "\n")
+	}
+	signedHeaders := strings.Join(headers, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		sha256Hex(body),
+	}, "\n")
+
+	scope := date + "/" + cfg.Region + "/" + service + "/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+cfg.SecretAccessKey), date)
+	key = hmacSHA256(key, cfg.Region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,85 @@
+package keyprovider
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Provider names accepted by Config.Provider.
+const (
+	ProviderEnv    = "env"
+	ProviderAWSKMS = "awskms"
+	ProviderGCPKMS = "gcpkms"
+	ProviderVault  = "vault"
+)
+
+// Config selects and configures the provider that wraps data keys.
+type Config struct {
+	Provider string // ProviderEnv (default), ProviderAWSKMS, ProviderGCPKMS or ProviderVault
+	// KeyID is the AWS KMS key ID or ARN, the GCP KMS CryptoKey resource
+	// name, or the Vault transit key name.
+	KeyID string
+	// EncryptionKey is the env provider's key. With another provider it is
+	// only used to read secrets written before switching to that provider.
+	EncryptionKey []byte
+	// PreviousEncryptionKeys are keys that were EncryptionKey before a
+	// rotation, used to read secrets written under them.
+	PreviousEncryptionKeys [][]byte
+
+	AWS   AWSConfig   // KeyID is taken from Config.KeyID
+	GCP   GCPConfig   // KeyName is taken from Config.KeyID
+	Vault VaultConfig // KeyName is taken from Config.KeyID
+
+	HTTPClient *http.Client // Client for remote providers; nil uses a default
+}
+
+// New creates the Envelope for cfg. It returns nil when the env provider is
+// selected without a key, in which case secrets can't be stored.
+//
+// Env keys in the config can always still decrypt: secrets they wrapped, or
+// encrypted directly before envelope encryption, are read and report
+// NeedsRewrap so callers can move them under the primary key.
+func New(cfg Config) (*Envelope, error) {
+	var envProviders []Provider
+	var legacyKeys [][]byte
+	for _, key := range append([][]byte{cfg.EncryptionKey}, cfg.PreviousEncryptionKeys...) {
+		if key == nil {
+			continue
+		}
+		p, err := NewEnvProvider(key)
+		if err != nil {
+			return nil, err
+		}
+		envProviders = append(envProviders, p)
+		legacyKeys = append(legacyKeys, key)
+	}
+
+	var primary Provider
+	previous := envProviders
+	var err error
+	switch cfg.Provider {
+	case "", ProviderEnv:
+		if cfg.EncryptionKey == nil {
+			return nil, nil
+		}
+		primary, previous = envProviders[0], envProviders[1:]
+	case ProviderAWSKMS:
+		aws := cfg.AWS
+		aws.KeyID = cfg.KeyID
+		primary, err = NewAWSKMSProvider(aws, cfg.HTTPClient)
+	case ProviderGCPKMS:
+		gcp := cfg.GCP
+		gcp.KeyName = cfg.KeyID
+		primary, err = NewGCPKMSProvider(gcp, cfg.HTTPClient)
+	case ProviderVault:
+		vault := cfg.Vault
+		vault.KeyName = cfg.KeyID
+		primary, err = NewVaultProvider(vault, cfg.HTTPClient)
+	default:
+		return nil, fmt.Errorf("unknown key provider %q (expected %s, %s, %s or %s)", cfg.Provider, ProviderEnv, ProviderAWSKMS, ProviderGCPKMS, ProviderVault)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewEnvelope(primary, WithPrevious(previous...), WithLegacyKeys(legacyKeys...)), nil
+}
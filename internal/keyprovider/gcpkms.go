@@ -0,0 +1,129 @@
+package keyprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	gcpKMSEndpoint   = "https://cloudkms.googleapis.com"
+	gcpMetadataToken = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+// GCPConfig configures a GCPKMSProvider.
+type GCPConfig struct {
+	// KeyName is the CryptoKey resource name, e.g.
+	// projects/p/locations/global/keyRings/r/cryptoKeys/k. Cloud KMS picks
+	// the primary version to encrypt and the right version to decrypt, so
+	// rotating the key is transparent.
+	KeyName string
+	// AccessToken is an OAuth access token for Cloud KMS. When empty, tokens
+	// for the instance's service account are fetched from the metadata
+	// server.
+	AccessToken string
+}
+
+// GCPKMSProvider wraps data keys with a Google Cloud KMS key.
+type GCPKMSProvider struct {
+	cfg      GCPConfig
+	client   *http.Client
+	endpoint string
+	tokenURL string
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// NewGCPKMSProvider creates a GCPKMSProvider. A nil client uses a default
+// one.
+func NewGCPKMSProvider(cfg GCPConfig, client *http.Client) (*GCPKMSProvider, error) {
+	if cfg.KeyName == "" {
+		return nil, errors.New("gcp kms key provider requires a crypto key name")
+	}
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	return &GCPKMSProvider{cfg: cfg, client: client, endpoint: gcpKMSEndpoint, tokenURL: gcpMetadataToken}, nil
+}
+
+func (p *GCPKMSProvider) KeyID() string {
+	return ProviderGCPKMS + ":" + p.cfg.KeyName
+}
+
+func (p *GCPKMSProvider) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	var res struct {
+		Ciphertext []byte `json:"ciphertext"`
+	}
+	if err := p.call(ctx, "encrypt", map[string][]byte{"plaintext": dataKey}, &res); err != nil {
+		return nil, err
+	}
+	return res.Ciphertext, nil
+}
+
+func (p *GCPKMSProvider) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	var res struct {
+		Plaintext []byte `json:"plaintext"`
+	}
+	if err := p.call(ctx, "decrypt", map[string][]byte{"ciphertext": wrapped}, &res); err != nil {
+		return nil, err
+	}
+	return res.Plaintext, nil
+}
+
+func (p *GCPKMSProvider) call(ctx context.Context, op string, in any, out any) error {
+	token, err := p.accessToken(ctx)
+	if err != nil {
+		return fmt.Errorf("gcp access token: %w", err)
+	}
+	req, _, err := newJSONRequest(ctx, p.endpoint+"/v1/"+p.cfg.KeyName+":"+op, in)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return doJSON(p.client, req, out)
+}
+
+// accessToken returns the configured token, or a cached metadata server
+// token that is refreshed shortly before it expires.
+func (p *GCPKMSProvider) accessToken(ctx context.Context) (string, error) {
+	if p.cfg.AccessToken != "" {
+		return p.cfg.AccessToken, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.token != "" && time.Now().Before(p.tokenExpiry) {
+		return p.token, nil
+	}
+
+	token, expiry, err := fetchGCPMetadataToken(ctx, p.client, p.tokenURL)
+	if err != nil {
+		return "", err
+	}
+	p.token, p.tokenExpiry = token, expiry
+	return p.token, nil
+}
+
+// fetchGCPMetadataToken fetches an access token for the instance's service
+// account from the metadata server. The expiry returned leaves a minute to
+// spare.
+func fetchGCPMetadataToken(ctx context.Context, client *http.Client, tokenURL string) (string, time.Time, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL, http.NoBody)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	var res struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := doJSON(client, req, &res); err != nil {
+		return "", time.Time{}, err
+	}
+	return res.AccessToken, time.Now().Add(time.Duration(res.ExpiresIn)*time.Second - time.Minute), nil
+}
@@ -0,0 +1,182 @@
+// Package keyprovider encrypts secrets at rest with envelope encryption. Each
+// value is encrypted with a fresh data key, and the data key is wrapped by a
+// key encryption key held by a Provider: a static key from the environment,
+// AWS KMS, GCP KMS or a HashiCorp Vault transit key. Secrets that are
+// managed elsewhere, such as the encryption key itself, can be read from
+// Vault, AWS Secrets Manager or GCP Secret Manager with a SecretReader.
+package keyprovider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vervesh/verve/internal/crypto"
+)
+
+// Provider wraps and unwraps data keys with a key encryption key.
+type Provider interface {
+	// KeyID identifies the key encryption key. It is stored with each
+	// wrapped data key, so values are unwrapped by the provider that wrapped
+	// them and can be rewrapped after the primary key changes.
+	KeyID() string
+	WrapKey(ctx context.Context, dataKey []byte) ([]byte, error)
+	UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// envelopePrefix marks envelope encrypted values. Values encrypted directly
+// with a key before envelope encryption are plain base64, which never
+// contains a colon.
+const envelopePrefix = "envelope:v1:"
+
+// dataKeySize is the size of the AES-256 data keys.
+const dataKeySize = 32
+
+type envelope struct {
+	KeyID      string `json:"kid"`
+	WrappedKey []byte `json:"wk"`
+	Ciphertext string `json:"ct"`
+}
+
+// Envelope encrypts values under data keys wrapped by its primary provider.
+// Values wrapped by a previous provider, or encrypted directly with a legacy
+// key before envelope encryption, still decrypt and report NeedsRewrap.
+type Envelope struct {
+	primary    Provider
+	previous   []Provider
+	legacyKeys [][]byte
+}
+
+// Option configures an Envelope.
+type Option func(*Envelope)
+
+// WithPrevious adds providers of key encryption keys that were primary
+// before a rotation, so values they wrapped can still be read.
+func WithPrevious(providers ...Provider) Option {
+	return func(e *Envelope) {
+		e.previous = append(e.previous, providers...)
+	}
+}
+
+// WithLegacyKeys adds keys that values were encrypted with directly, before
+// envelope encryption.
+func WithLegacyKeys(keys ...[]byte) Option {
+	return func(e *Envelope) {
+		e.legacyKeys = append(e.legacyKeys, keys...)
+	}
+}
+
+// NewEnvelope creates an Envelope that wraps new data keys with primary.
+func NewEnvelope(primary Provider, opts ...Option) *Envelope {
+	e := &Envelope{primary: primary}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// KeyID returns the ID of the primary key encryption key.
+func (e *Envelope) KeyID() string {
+	return e.primary.KeyID()
+}
+
+// Encrypt encrypts plaintext under a new data key wrapped by the primary
+// provider.
+func (e *Envelope) Encrypt(ctx context.Context, plaintext string) (string, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("generate data key: %w", err)
+	}
+	ciphertext, err := crypto.Encrypt(dataKey, plaintext)
+	if err != nil {
+		return "", err
+	}
+	wrapped, err := e.primary.WrapKey(ctx, dataKey)
+	if err != nil {
+		return "", fmt.Errorf("wrap data key: %w", err)
+	}
+	b, err := json.Marshal(envelope{KeyID: e.primary.KeyID(), WrappedKey: wrapped, Ciphertext: ciphertext})
+	if err != nil {
+		return "", err
+	}
+	return envelopePrefix + base64.StdEncoding.EncodeToString(b), nil
+}
+
+// Decrypt decrypts a value returned by Encrypt, or a legacy value encrypted
+// directly with one of the legacy keys.
+func (e *Envelope) Decrypt(ctx context.Context, value string) (string, error) {
+	encoded, ok := strings.CutPrefix(value, envelopePrefix)
+	if !ok {
+		return e.decryptLegacy(value)
+	}
+	env, err := decodeEnvelope(encoded)
+	if err != nil {
+		return "", err
+	}
+	p := e.provider(env.KeyID)
+	if p == nil {
+		return "", fmt.Errorf("no key provider configured for key %q", env.KeyID)
+	}
+	dataKey, err := p.UnwrapKey(ctx, env.WrappedKey)
+	if err != nil {
+		return "", fmt.Errorf("unwrap data key: %w", err)
+	}
+	return crypto.Decrypt(dataKey, env.Ciphertext)
+}
+
+// NeedsRewrap reports whether value should be encrypted again to move it
+// under the primary key: it is a legacy value or its data key was wrapped by
+// a previous provider.
+func (e *Envelope) NeedsRewrap(value string) bool {
+	encoded, ok := strings.CutPrefix(value, envelopePrefix)
+	if !ok {
+		return true
+	}
+	env, err := decodeEnvelope(encoded)
+	if err != nil {
+		return false
+	}
+	return env.KeyID != e.primary.KeyID()
+}
+
+func (e *Envelope) provider(keyID string) Provider {
+	if e.primary.KeyID() == keyID {
+		return e.primary
+	}
+	for _, p := range e.previous {
+		if p.KeyID() == keyID {
+			return p
+		}
+	}
+	return nil
+}
+
+func (e *Envelope) decryptLegacy(value string) (string, error) {
+	if len(e.legacyKeys) == 0 {
+		return "", errors.New("value is not envelope encrypted and no legacy key is configured")
+	}
+	var err error
+	for _, key := range e.legacyKeys {
+		var plaintext string
+		if plaintext, err = crypto.Decrypt(key, value); err == nil {
+			return plaintext, nil
+		}
+	}
+	return "", fmt.Errorf("decrypt legacy value: %w", err)
+}
+
+func decodeEnvelope(encoded string) (envelope, error) {
+	b, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return envelope{}, fmt.Errorf("decode envelope: %w", err)
+	}
+	var env envelope
+	if err := json.Unmarshal(b, &env); err != nil {
+		return envelope{}, fmt.Errorf("decode envelope: %w", err)
+	}
+	return env, nil
+}
@@ -0,0 +1,345 @@
+package keyprovider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/crypto"
+)
+
+func testKey(c byte) []byte {
+	return []byte(strings.Repeat(string(c), 32))
+}
+
+func TestEnvelope_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	envelope, err := New(Config{EncryptionKey: testKey('a')})
+	require.NoError(t, err)
+
+	encrypted, err := envelope.Encrypt(ctx, "secret")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encrypted, envelopePrefix))
+	assert.False(t, envelope.NeedsRewrap(encrypted))
+
+	decrypted, err := envelope.Decrypt(ctx, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", decrypted)
+
+	other, err := envelope.Encrypt(ctx, "secret")
+	require.NoError(t, err)
+	assert.NotEqual(t, encrypted, other, "each value should get its own data key")
+}
+
+func TestEnvelope_LegacyValue(t *testing.T) {
+	ctx := context.Background()
+	legacy, err := crypto.Encrypt(testKey('a'), "secret")
+	require.NoError(t, err)
+
+	envelope, err := New(Config{EncryptionKey: testKey('a')})
+	require.NoError(t, err)
+	decrypted, err := envelope.Decrypt(ctx, legacy)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", decrypted)
+	assert.True(t, envelope.NeedsRewrap(legacy))
+
+	withoutKey := NewEnvelope(&fakeProvider{id: "fake"})
+	_, err = withoutKey.Decrypt(ctx, legacy)
+	assert.Error(t, err)
+}
+
+func TestEnvelope_Rotation(t *testing.T) {
+	ctx := context.Background()
+	before, err := New(Config{EncryptionKey: testKey('a')})
+	require.NoError(t, err)
+	encrypted, err := before.Encrypt(ctx, "secret")
+	require.NoError(t, err)
+
+	// Rotate to a new env key, keeping the old one as previous.
+	after, err := New(Config{EncryptionKey: testKey('b'), PreviousEncryptionKeys: [][]byte{testKey('a')}})
+	require.NoError(t, err)
+	assert.NotEqual(t, before.KeyID(), after.KeyID())
+	assert.True(t, after.NeedsRewrap(encrypted))
+	decrypted, err := after.Decrypt(ctx, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", decrypted)
+
+	// Move to a KMS provider; the env key still reads older values.
+	kms := &fakeProvider{id: "fake"}
+	moved := NewEnvelope(kms, WithPrevious(mustEnvProvider(t, testKey('b'))))
+	rewrapped, err := after.Encrypt(ctx, "secret")
+	require.NoError(t, err)
+	assert.True(t, moved.NeedsRewrap(rewrapped))
+	decrypted, err = moved.Decrypt(ctx, rewrapped)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", decrypted)
+
+	// Without the key that wrapped a value, it can't be read.
+	_, err = NewEnvelope(kms).Decrypt(ctx, rewrapped)
+	assert.ErrorContains(t, err, "no key provider configured")
+}
+
+func TestNew(t *testing.T) {
+	envelope, err := New(Config{})
+	require.NoError(t, err)
+	assert.Nil(t, envelope, "env provider without a key stores no secrets")
+
+	_, err = New(Config{Provider: "hsm"})
+	assert.ErrorContains(t, err, `unknown key provider "hsm"`)
+
+	_, err = New(Config{EncryptionKey: []byte("short")})
+	assert.Error(t, err)
+
+	_, err = New(Config{Provider: ProviderVault, Vault: VaultConfig{Address: "https://vault", Token: "t"}})
+	assert.Error(t, err, "vault requires a key name")
+
+	envelope, err = New(Config{Provider: ProviderVault, KeyID: "verve", Vault: VaultConfig{Address: "https://vault", Token: "t"}})
+	require.NoError(t, err)
+	assert.Equal(t, "vault:transit/verve", envelope.KeyID())
+}
+
+func TestVaultProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "s.token", r.Header.Get("X-Vault-Token"))
+		var in map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/verve":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]string{"ciphertext": "vault:v1:" + in["plaintext"]}})
+		case "/v1/transit/decrypt/verve":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]string{"plaintext": strings.TrimPrefix(in["ciphertext"], "vault:v1:")}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	p, err := NewVaultProvider(VaultConfig{Address: srv.URL + "/", Token: "s.token", KeyName: "verve"}, srv.Client())
+	require.NoError(t, err)
+	testProviderRoundTrip(t, p)
+}
+
+func TestGCPKMSProvider(t *testing.T) {
+	tokenFetches := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			assert.Equal(t, "Google", r.Header.Get("Metadata-Flavor"))
+			tokenFetches++
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "ya29.token", "expires_in": 3600})
+			return
+		}
+		assert.Equal(t, "Bearer ya29.token", r.Header.Get("Authorization"))
+		var in map[string][]byte
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+		switch r.URL.Path {
+		case "/v1/projects/p/cryptoKeys/k:encrypt":
+			_ = json.NewEncoder(w).Encode(map[string][]byte{"ciphertext": reverse(in["plaintext"])})
+		case "/v1/projects/p/cryptoKeys/k:decrypt":
+			_ = json.NewEncoder(w).Encode(map[string][]byte{"plaintext": reverse(in["ciphertext"])})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	p, err := NewGCPKMSProvider(GCPConfig{KeyName: "projects/p/cryptoKeys/k"}, srv.Client())
+	require.NoError(t, err)
+	p.endpoint = srv.URL
+	p.tokenURL = srv.URL + "/token"
+	testProviderRoundTrip(t, p)
+	assert.Equal(t, 1, tokenFetches, "metadata token should be cached")
+}
+
+func TestAWSKMSProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/x-amz-json-1.1", r.Header.Get("Content-Type"))
+		assert.Equal(t, "20260102T030405Z", r.Header.Get("X-Amz-Date"))
+		assert.Equal(t, "session", r.Header.Get("X-Amz-Security-Token"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"),
+			"AWS4-HMAC-SHA256 Credential=AKID/20260102/us-east-1/kms/aws4_request, SignedHeaders=content-type;host;x-amz-date;x-amz-security-token;x-amz-target, Signature="))
+
+		var in map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+		assert.Equal(t, "alias/verve", in["KeyId"])
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.Encrypt":
+			b, _ := base64.StdEncoding.DecodeString(in["Plaintext"].(string))
+			_ = json.NewEncoder(w).Encode(map[string][]byte{"CiphertextBlob": reverse(b)})
+		case "TrentService.Decrypt":
+			b, _ := base64.StdEncoding.DecodeString(in["CiphertextBlob"].(string))
+			_ = json.NewEncoder(w).Encode(map[string][]byte{"Plaintext": reverse(b)})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	p, err := NewAWSKMSProvider(AWSConfig{
+		KeyID:           "alias/verve",
+		Region:          "us-east-1",
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+		SessionToken:    "session",
+	}, srv.Client())
+	require.NoError(t, err)
+	p.endpoint = srv.URL + "/"
+	p.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+	testProviderRoundTrip(t, p)
+}
+
+func TestProvider_ErrorResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+	}))
+	t.Cleanup(srv.Close)
+
+	p, err := NewVaultProvider(VaultConfig{Address: srv.URL, Token: "t", KeyName: "verve"}, srv.Client())
+	require.NoError(t, err)
+	_, err = p.WrapKey(context.Background(), testKey('a'))
+	assert.ErrorContains(t, err, "403 Forbidden")
+	assert.ErrorContains(t, err, "permission denied")
+}
+
+func TestNewSecretReader(t *testing.T) {
+	r, err := NewSecretReader(SecretsConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, r)
+
+	_, err = NewSecretReader(SecretsConfig{Backend: "keychain"})
+	assert.ErrorContains(t, err, `unknown secrets backend "keychain"`)
+
+	_, err = NewSecretReader(SecretsConfig{Backend: SecretsAWSSecretsManager, AWS: AWSConfig{Region: "us-east-1"}})
+	assert.Error(t, err, "aws secrets manager requires credentials")
+}
+
+func TestVaultSecretReader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "s.token", r.Header.Get("X-Vault-Token"))
+		if r.URL.Path != "/v1/kv/data/verve/github" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"data": map[string]string{"value": "ghp_a", "token": "ghp_b"}}})
+	}))
+	t.Cleanup(srv.Close)
+
+	r, err := NewVaultSecretReader(VaultConfig{Address: srv.URL, Token: "s.token"}, "kv", srv.Client())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	v, err := r.ReadSecret(ctx, "verve/github")
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_a", v)
+
+	v, err = r.ReadSecret(ctx, "verve/github#token")
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_b", v)
+
+	_, err = r.ReadSecret(ctx, "verve/github#missing")
+	assert.ErrorContains(t, err, `no field "missing"`)
+}
+
+func TestAWSSecretReader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"),
+			"AWS4-HMAC-SHA256 Credential=AKID/20260102/eu-west-1/secretsmanager/aws4_request, SignedHeaders=content-type;host;x-amz-date;x-amz-target, Signature="))
+		var in map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+		secrets := map[string]string{
+			"verve/github": "ghp_a",
+			"verve/keys":   `{"current":"abc"}`,
+		}
+		secret, ok := secrets[in["SecretId"]]
+		if !ok {
+			http.Error(w, `{"__type":"ResourceNotFoundException"}`, http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"SecretString": secret})
+	}))
+	t.Cleanup(srv.Close)
+
+	r, err := NewAWSSecretReader(AWSConfig{Region: "eu-west-1", AccessKeyID: "AKID", SecretAccessKey: "secret"}, srv.Client())
+	require.NoError(t, err)
+	r.endpoint = srv.URL + "/"
+	r.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+	ctx := context.Background()
+
+	v, err := r.ReadSecret(ctx, "verve/github")
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_a", v)
+
+	v, err = r.ReadSecret(ctx, "verve/keys#current")
+	require.NoError(t, err)
+	assert.Equal(t, "abc", v)
+
+	_, err = r.ReadSecret(ctx, "verve/missing")
+	assert.ErrorContains(t, err, "ResourceNotFoundException")
+}
+
+func TestGCPSecretReader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer ya29.token", r.Header.Get("Authorization"))
+		if r.URL.Path != "/v1/projects/p/secrets/github/versions/latest:access" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"payload": map[string]string{"data": base64.StdEncoding.EncodeToString([]byte("ghp_a"))}})
+	}))
+	t.Cleanup(srv.Close)
+
+	r := NewGCPSecretReader(GCPConfig{AccessToken: "ya29.token"}, srv.Client())
+	r.endpoint = srv.URL
+	v, err := r.ReadSecret(context.Background(), "projects/p/secrets/github")
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_a", v)
+}
+
+func testProviderRoundTrip(t *testing.T, p Provider) {
+	t.Helper()
+	ctx := context.Background()
+	envelope := NewEnvelope(p)
+	encrypted, err := envelope.Encrypt(ctx, "secret")
+	require.NoError(t, err)
+	decrypted, err := envelope.Decrypt(ctx, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", decrypted)
+}
+
+func mustEnvProvider(t *testing.T, key []byte) *EnvProvider {
+	t.Helper()
+	p, err := NewEnvProvider(key)
+	require.NoError(t, err)
+	return p
+}
+
+// fakeProvider wraps data keys by reversing them.
+type fakeProvider struct {
+	id string
+}
+
+func (p *fakeProvider) KeyID() string { return p.id }
+
+func (p *fakeProvider) WrapKey(_ context.Context, dataKey []byte) ([]byte, error) {
+	return reverse(dataKey), nil
+}
+
+func (p *fakeProvider) UnwrapKey(_ context.Context, wrapped []byte) ([]byte, error) {
+	return reverse(wrapped), nil
+}
+
+func reverse(b []byte) []byte {
+	out := make([]byte, len(b))
+	for i := range b {
+		out[len(b)-1-i] = b[i]
+	}
+	return out
+}
@@ -0,0 +1,247 @@
+package keyprovider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Secrets manager names accepted by SecretsConfig.Backend.
+const (
+	SecretsVault             = "vault"
+	SecretsAWSSecretsManager = "awssecretsmanager"
+	SecretsGCPSecretManager  = "gcpsecretmanager"
+)
+
+const gcpSecretManagerEndpoint = "https://secretmanager.googleapis.com"
+
+// SecretReader reads secrets, such as the encryption key or the GitHub
+// token, from a secrets manager instead of the environment or the database.
+type SecretReader interface {
+	// ReadSecret returns the secret ref names. The ref format depends on the
+	// backend; see the reader types.
+	ReadSecret(ctx context.Context, ref string) (string, error)
+}
+
+// SecretsConfig selects and configures the secrets manager secrets are read
+// from. Credentials are shared with the key providers.
+type SecretsConfig struct {
+	Backend string // SecretsVault, SecretsAWSSecretsManager or SecretsGCPSecretManager
+	AWS     AWSConfig
+	GCP     GCPConfig
+	Vault   VaultConfig
+	VaultKV string // Vault KV v2 secrets engine mount (default: secret)
+
+	HTTPClient *http.Client // nil uses a default
+}
+
+// NewSecretReader creates the reader for cfg. It returns nil when no backend
+// is configured.
+func NewSecretReader(cfg SecretsConfig) (SecretReader, error) {
+	switch cfg.Backend {
+	case "":
+		return nil, nil
+	case SecretsVault:
+		return NewVaultSecretReader(cfg.Vault, cfg.VaultKV, cfg.HTTPClient)
+	case SecretsAWSSecretsManager:
+		return NewAWSSecretReader(cfg.AWS, cfg.HTTPClient)
+	case SecretsGCPSecretManager:
+		return NewGCPSecretReader(cfg.GCP, cfg.HTTPClient), nil
+	default:
+		return nil, fmt.Errorf("unknown secrets backend %q (expected %s, %s or %s)", cfg.Backend, SecretsVault, SecretsAWSSecretsManager, SecretsGCPSecretManager)
+	}
+}
+
+// splitSecretRef splits "name#field" into the secret name and the field to
+// read from it, which is empty when the ref names no field.
+func splitSecretRef(ref string) (name, field string) {
+	name, field, _ = strings.Cut(ref, "#")
+	return name, field
+}
+
+// jsonField returns field of a JSON object secret, or the whole secret when
+// field is empty.
+func jsonField(secret, field string) (string, error) {
+	if field == "" {
+		return secret, nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(secret), &fields); err != nil {
+		return "", fmt.Errorf("secret is not a JSON object, so it has no field %q", field)
+	}
+	v, ok := fields[field].(string)
+	if !ok {
+		return "", fmt.Errorf("secret has no string field %q", field)
+	}
+	return v, nil
+}
+
+// VaultSecretReader reads secrets from a HashiCorp Vault KV v2 secrets
+// engine. Refs are "path#field"; the field defaults to "value".
+type VaultSecretReader struct {
+	cfg    VaultConfig
+	mount  string
+	client *http.Client
+}
+
+// NewVaultSecretReader creates a VaultSecretReader for the KV v2 engine at
+// mount. A nil client uses a default one.
+func NewVaultSecretReader(cfg VaultConfig, mount string, client *http.Client) (*VaultSecretReader, error) {
+	if cfg.Address == "" || cfg.Token == "" {
+		return nil, errors.New("vault secrets backend requires an address and token")
+	}
+	if mount == "" {
+		mount = "secret"
+	}
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	cfg.Address = strings.TrimRight(cfg.Address, "/")
+	return &VaultSecretReader{cfg: cfg, mount: strings.Trim(mount, "/"), client: client}, nil
+}
+
+func (r *VaultSecretReader) ReadSecret(ctx context.Context, ref string) (string, error) {
+	path, field := splitSecretRef(ref)
+	if field == "" {
+		field = "value"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.cfg.Address+"/v1/"+r.mount+"/data/"+strings.Trim(path, "/"), http.NoBody)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", r.cfg.Token)
+	var res struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := doJSON(r.client, req, &res); err != nil {
+		return "", err
+	}
+	v, ok := res.Data.Data[field].(string)
+	if !ok {
+		return "", fmt.Errorf("vault secret %s has no field %q", path, field)
+	}
+	return v, nil
+}
+
+// AWSSecretReader reads secrets from AWS Secrets Manager. Refs are a secret
+// name or ARN, with "#key" to read a key of a JSON secret.
+type AWSSecretReader struct {
+	cfg      AWSConfig
+	client   *http.Client
+	endpoint string
+	now      func() time.Time
+}
+
+// NewAWSSecretReader creates an AWSSecretReader. A nil client uses a default
+// one.
+func NewAWSSecretReader(cfg AWSConfig, client *http.Client) (*AWSSecretReader, error) {
+	if cfg.Region == "" || cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, errors.New("aws secrets manager requires AWS_REGION, AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	return &AWSSecretReader{
+		cfg:      cfg,
+		client:   client,
+		endpoint: "https://secretsmanager." + cfg.Region + ".amazonaws.com/",
+		now:      time.Now,
+	}, nil
+}
+
+func (r *AWSSecretReader) ReadSecret(ctx context.Context, ref string) (string, error) {
+	name, field := splitSecretRef(ref)
+	req, body, err := newJSONRequest(ctx, r.endpoint, map[string]string{"SecretId": name})
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	signAWS(req, body, r.cfg, "secretsmanager", r.now())
+	var res struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := doJSON(r.client, req, &res); err != nil {
+		return "", err
+	}
+	return jsonField(res.SecretString, field)
+}
+
+// GCPSecretReader reads secrets from Google Cloud Secret Manager. Refs are a
+// secret resource name, projects/p/secrets/s, which reads the latest version,
+// or a version resource name, with "#key" to read a key of a JSON secret.
+type GCPSecretReader struct {
+	cfg      GCPConfig
+	client   *http.Client
+	endpoint string
+	tokenURL string
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// NewGCPSecretReader creates a GCPSecretReader. A nil client uses a default
+// one.
+func NewGCPSecretReader(cfg GCPConfig, client *http.Client) *GCPSecretReader {
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	return &GCPSecretReader{cfg: cfg, client: client, endpoint: gcpSecretManagerEndpoint, tokenURL: gcpMetadataToken}
+}
+
+func (r *GCPSecretReader) ReadSecret(ctx context.Context, ref string) (string, error) {
+	name, field := splitSecretRef(ref)
+	if !strings.Contains(name, "/versions/") {
+		name += "/versions/latest"
+	}
+	token, err := r.accessToken(ctx)
+	if err != nil {
+		return "", fmt.Errorf("gcp access token: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.endpoint+"/v1/"+(&url.URL{Path: name}).EscapedPath()+":access", http.NoBody)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	var res struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := doJSON(r.client, req, &res); err != nil {
+		return "", err
+	}
+	secret, err := base64.StdEncoding.DecodeString(res.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("decode gcp secret payload: %w", err)
+	}
+	return jsonField(string(secret), field)
+}
+
+func (r *GCPSecretReader) accessToken(ctx context.Context) (string, error) {
+	if r.cfg.AccessToken != "" {
+		return r.cfg.AccessToken, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.token != "" && time.Now().Before(r.tokenExpiry) {
+		return r.token, nil
+	}
+	token, expiry, err := fetchGCPMetadataToken(ctx, r.client, r.tokenURL)
+	if err != nil {
+		return "", err
+	}
+	r.token, r.tokenExpiry = token, expiry
+	return r.token, nil
+}
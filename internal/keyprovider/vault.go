@@ -0,0 +1,83 @@
+package keyprovider
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// VaultConfig configures a VaultProvider.
+type VaultConfig struct {
+	Address string // Vault server address, e.g. https://vault.example.com:8200
+	Token   string
+	Mount   string // Transit secrets engine mount path (default: transit)
+	KeyName string // Transit key name
+}
+
+// VaultProvider wraps data keys with a HashiCorp Vault transit key. Rotating
+// the transit key in Vault is transparent: older key versions still unwrap
+// as long as Vault's min_decryption_version allows.
+type VaultProvider struct {
+	cfg    VaultConfig
+	client *http.Client
+}
+
+// NewVaultProvider creates a VaultProvider. A nil client uses a default one.
+func NewVaultProvider(cfg VaultConfig, client *http.Client) (*VaultProvider, error) {
+	if cfg.Address == "" || cfg.Token == "" || cfg.KeyName == "" {
+		return nil, errors.New("vault key provider requires an address, token and transit key name")
+	}
+	if cfg.Mount == "" {
+		cfg.Mount = "transit"
+	}
+	cfg.Address = strings.TrimRight(cfg.Address, "/")
+	cfg.Mount = strings.Trim(cfg.Mount, "/")
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	return &VaultProvider{cfg: cfg, client: client}, nil
+}
+
+func (p *VaultProvider) KeyID() string {
+	return ProviderVault + ":" + p.cfg.Mount + "/" + p.cfg.KeyName
+}
+
+type vaultResponse struct {
+	Data struct {
+		Ciphertext string `json:"ciphertext"`
+		Plaintext  string `json:"plaintext"`
+	} `json:"data"`
+}
+
+func (p *VaultProvider) WrapKey(ctx context.Context, dataKey []byte) ([]byte, error) {
+	var res vaultResponse
+	in := map[string]string{"plaintext": base64.StdEncoding.EncodeToString(dataKey)}
+	if err := p.call(ctx, "encrypt", in, &res); err != nil {
+		return nil, err
+	}
+	if res.Data.Ciphertext == "" {
+		return nil, errors.New("vault returned no ciphertext")
+	}
+	return []byte(res.Data.Ciphertext), nil
+}
+
+func (p *VaultProvider) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	var res vaultResponse
+	if err := p.call(ctx, "decrypt", map[string]string{"ciphertext": string(wrapped)}, &res); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(res.Data.Plaintext)
+}
+
+func (p *VaultProvider) call(ctx context.Context, op string, in any, out any) error {
+	endpoint := p.cfg.Address + "/v1/" + p.cfg.Mount + "/" + op + "/" + url.PathEscape(p.cfg.KeyName)
+	req, _, err := newJSONRequest(ctx, endpoint, in)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", p.cfg.Token)
+	return doJSON(p.client, req, out)
+}
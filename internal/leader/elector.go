@@ -0,0 +1,151 @@
+package leader
+
+import (
+	"context"
+	"crypto/rand"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/joshjon/kit/log"
+)
+
+const (
+	// DefaultLeaseTTL is how long a lease stays valid without renewal. If the
+	// leader crashes, another replica takes over once the lease expires.
+	DefaultLeaseTTL = 30 * time.Second
+
+	releaseTimeout = 5 * time.Second
+)
+
+// ElectorOption configures an Elector.
+type ElectorOption func(*Elector)
+
+// WithRenewInterval overrides how often the elector attempts to acquire or
+// renew the lease. Defaults to a third of the lease TTL.
+func WithRenewInterval(d time.Duration) ElectorOption {
+	return func(e *Elector) {
+		e.renewInterval = d
+	}
+}
+
+// Elector elects a single leader among API replicas sharing a database using
+// a lease. Singleton background jobs check IsLeader before each run so they
+// execute on exactly one replica. The leader renews its lease periodically;
+// if it stops renewing, another replica acquires the lease once it expires.
+type Elector struct {
+	repo          Repository
+	name          string
+	holder        string
+	ttl           time.Duration
+	renewInterval time.Duration
+	logger        log.Logger
+
+	leader atomic.Bool
+}
+
+// NewElector creates an Elector that competes for the named lease on behalf
+// of holder, which must be unique per replica.
+func NewElector(repo Repository, name, holder string, ttl time.Duration, logger log.Logger, opts ...ElectorOption) *Elector {
+	if ttl <= 0 {
+		ttl = DefaultLeaseTTL
+	}
+	e := &Elector{
+		repo:   repo,
+		name:   name,
+		holder: holder,
+		ttl:    ttl,
+		logger: logger.With("component", "leader_elector", "leader.lease", name, "leader.holder", holder),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	if e.renewInterval <= 0 {
+		e.renewInterval = ttl / 3
+	}
+	return e
+}
+
+// NewHolderID returns an identifier for this replica, made of the hostname
+// and a random suffix so restarted processes on the same host are distinct.
+func NewHolderID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "verve"
+	}
+	return host + "-" + rand.Text()[:8]
+}
+
+// IsLeader reports whether this replica currently holds the lease.
+func (e *Elector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Start makes an initial attempt to acquire the lease, so IsLeader is accurate
+// as soon as Start returns, then keeps acquiring or renewing it in the
+// background until ctx is cancelled or the returned stop function is called.
+// The lease is released on shutdown so another replica can take over without
+// waiting for it to expire. Stop blocks until the lease has been released.
+func (e *Elector) Start(ctx context.Context) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	e.tryAcquire(ctx)
+	go func() {
+		defer close(done)
+		e.run(ctx)
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+func (e *Elector) run(ctx context.Context) {
+	ticker := time.NewTicker(e.renewInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			e.release(context.WithoutCancel(ctx))
+			return
+		case <-ticker.C:
+			e.tryAcquire(ctx)
+		}
+	}
+}
+
+func (e *Elector) tryAcquire(ctx context.Context) {
+	ok, err := e.repo.AcquireLease(ctx, e.name, e.holder, e.ttl)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		// Without a confirmed renewal the lease may expire and be taken by
+		// another replica, so stop acting as leader.
+		e.logger.Error("failed to acquire leader lease", "error", err)
+		ok = false
+	}
+	if e.leader.Swap(ok) != ok {
+		if ok {
+			e.logger.Info("acquired leadership")
+		} else {
+			e.logger.Warn("lost leadership")
+		}
+	}
+}
+
+func (e *Elector) release(ctx context.Context) {
+	if !e.leader.Swap(false) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, releaseTimeout)
+	defer cancel()
+	if err := e.repo.ReleaseLease(ctx, e.name, e.holder); err != nil {
+		e.logger.Error("failed to release leader lease", "error", err)
+		return
+	}
+	e.logger.Info("released leadership")
+}
@@ -0,0 +1,64 @@
+package leader_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/joshjon/kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/leader"
+	"github.com/vervesh/verve/internal/sqlite"
+)
+
+func TestElector_SingleLeader(t *testing.T) {
+	db := sqlite.NewTestDB(t)
+	leases := sqlite.NewLeaseRepository(db)
+	logger := log.NewLogger(log.WithNop())
+	ctx := context.Background()
+
+	a := leader.NewElector(leases, "jobs", "replica-a", time.Minute, logger, leader.WithRenewInterval(10*time.Millisecond))
+	b := leader.NewElector(leases, "jobs", "replica-b", time.Minute, logger, leader.WithRenewInterval(10*time.Millisecond))
+
+	stopA := a.Start(ctx)
+	stopB := b.Start(ctx)
+	defer stopB()
+
+	assert.True(t, a.IsLeader())
+	assert.False(t, b.IsLeader())
+
+	// Leadership is stable while the leader keeps renewing.
+	time.Sleep(50 * time.Millisecond)
+	assert.True(t, a.IsLeader())
+	assert.False(t, b.IsLeader())
+
+	// Stopping the leader releases the lease and the other replica takes over.
+	stopA()
+	assert.False(t, a.IsLeader())
+	require.Eventually(t, b.IsLeader, time.Second, 10*time.Millisecond)
+}
+
+func TestElector_ContextCancelReleasesLease(t *testing.T) {
+	db := sqlite.NewTestDB(t)
+	leases := sqlite.NewLeaseRepository(db)
+	logger := log.NewLogger(log.WithNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a := leader.NewElector(leases, "jobs", "replica-a", time.Minute, logger)
+	stop := a.Start(ctx)
+	require.True(t, a.IsLeader())
+
+	cancel()
+	stop()
+	assert.False(t, a.IsLeader())
+
+	ok, err := leases.AcquireLease(context.Background(), "jobs", "replica-b", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "expected lease to be released on shutdown")
+}
+
+func TestNewHolderID_Unique(t *testing.T) {
+	assert.NotEqual(t, leader.NewHolderID(), leader.NewHolderID())
+}
@@ -0,0 +1,16 @@
+package leader
+
+import (
+	"context"
+	"time"
+)
+
+// Repository is the interface for persisting leadership leases.
+type Repository interface {
+	// AcquireLease acquires the named lease for holder, or renews it if holder
+	// already owns it, so that it expires ttl from now. It returns false if
+	// another holder owns an unexpired lease.
+	AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	// ReleaseLease releases the named lease if it is owned by holder.
+	ReleaseLease(ctx context.Context, name, holder string) error
+}
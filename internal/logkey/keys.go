@@ -5,6 +5,30 @@ const (
 	RepoID         = "repo.id"
 	EpicID         = "epic.id"
 	ConversationID = "conversation.id"
+	SinkID         = "notification.sink_id"
+	WebhookID      = "webhook.id"
+	TeamID         = "team.id"
+	UserID         = "user.id"
+
+	// CIDispatchError is set when dispatching a repo's CI workflow for an
+	// agent PR fails without failing the request.
+	CIDispatchError = "ci_dispatch.error"
+
+	// ProvenanceScanError is set when scanning an agent PR's diff for
+	// license and provenance concerns fails without failing the request.
+	ProvenanceScanError = "provenance_scan.error"
+
+	// ProtectedPathCheckError is set when checking an agent PR's diff
+	// against the repo's protected paths fails without failing the request.
+	ProtectedPathCheckError = "protected_path_check.error"
+
+	// SelfReviewError is set when the automated self-review of an agent PR
+	// fails without failing the request.
+	SelfReviewError = "self_review.error"
+
+	// CompletionValidationError is set when running a repo's completion
+	// validations on an agent PR fails without failing the request.
+	CompletionValidationError = "completion_validation.error"
 )
 
-var HTTPKeys = []string{TaskID, RepoID, EpicID, ConversationID}
+var HTTPKeys = []string{TaskID, RepoID, EpicID, ConversationID, SinkID, WebhookID, TeamID, UserID, CIDispatchError, ProvenanceScanError, ProtectedPathCheckError, SelfReviewError, CompletionValidationError}
@@ -0,0 +1,176 @@
+package mcp
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/vervesh/verve/internal/msgcat"
+)
+
+// HTTPHandler serves the MCP HTTP transports behind a bearer token:
+//
+//   - POST /mcp is the streamable HTTP transport. Each request carries one
+//     message and the response, if any, is returned as JSON.
+//   - GET /mcp/sse and POST /mcp/messages are the legacy HTTP+SSE
+//     transport for older clients. Responses to posted messages are sent
+//     on the session's event stream. Sessions live in memory, so with
+//     several API replicas a session's requests must reach the same one.
+type HTTPHandler struct {
+	handler Handler
+	token   string
+
+	mu       sync.Mutex
+	sessions map[string]*sseSession
+}
+
+// sseSession is an open legacy SSE stream.
+type sseSession struct {
+	messages chan []byte
+	done     chan struct{}
+}
+
+// NewHTTPHandler creates a new HTTPHandler serving h to clients presenting
+// token.
+func NewHTTPHandler(h Handler, token string) *HTTPHandler {
+	return &HTTPHandler{handler: h, token: token, sessions: map[string]*sseSession{}}
+}
+
+// Register adds the endpoints to the provided Echo router group.
+func (h *HTTPHandler) Register(g *echo.Group) {
+	mcp := g.Group("/mcp", requireToken(h.token))
+	mcp.POST("", h.Post)
+	mcp.GET("", h.Get)
+	mcp.GET("/sse", h.SSE)
+	mcp.POST("/messages", h.PostMessage)
+}
+
+// Post handles POST /mcp
+func (h *HTTPHandler) Post(c echo.Context) error {
+	msg, err := io.ReadAll(io.LimitReader(c.Request().Body, maxMessageSize))
+	if err != nil {
+		return err
+	}
+	res, err := h.handler.Handle(c.Request().Context(), msg)
+	if err != nil {
+		return err
+	}
+	if res == nil {
+		return c.NoContent(http.StatusAccepted)
+	}
+	return c.JSONBlob(http.StatusOK, res)
+}
+
+// Get handles GET /mcp. The server never sends messages unprompted, so it
+// offers no stream for them.
+func (h *HTTPHandler) Get(c echo.Context) error {
+	return echo.NewHTTPError(http.StatusMethodNotAllowed)
+}
+
+// SSE handles GET /mcp/sse. The first event names the endpoint the client
+// posts its messages to.
+func (h *HTTPHandler) SSE(c echo.Context) error {
+	id, err := newSessionID()
+	if err != nil {
+		return err
+	}
+	session := &sseSession{messages: make(chan []byte, 16), done: make(chan struct{})}
+	h.mu.Lock()
+	h.sessions[id] = session
+	h.mu.Unlock()
+	defer func() {
+		h.mu.Lock()
+		delete(h.sessions, id)
+		h.mu.Unlock()
+		close(session.done)
+	}()
+
+	w := c.Response()
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	endpoint := strings.TrimSuffix(c.Request().URL.Path, "/sse") + "/messages?session_id=" + id
+	if err := writeEvent(w, "endpoint", []byte(endpoint)); err != nil {
+		return nil
+	}
+
+	ctx := c.Request().Context()
+	for {
+		select {
+		case msg := <-session.messages:
+			if err := writeEvent(w, "message", msg); err != nil {
+				return nil
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// PostMessage handles POST /mcp/messages?session_id=xxx
+func (h *HTTPHandler) PostMessage(c echo.Context) error {
+	h.mu.Lock()
+	session, ok := h.sessions[c.QueryParam("session_id")]
+	h.mu.Unlock()
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, msgcat.Text(msgcat.ErrMCPSessionNotFound))
+	}
+
+	msg, err := io.ReadAll(io.LimitReader(c.Request().Body, maxMessageSize))
+	if err != nil {
+		return err
+	}
+	res, err := h.handler.Handle(c.Request().Context(), msg)
+	if err != nil {
+		return err
+	}
+	if res != nil {
+		select {
+		case session.messages <- res:
+		case <-session.done:
+			return echo.NewHTTPError(http.StatusNotFound, msgcat.Text(msgcat.ErrMCPSessionNotFound))
+		case <-c.Request().Context().Done():
+			return c.Request().Context().Err()
+		}
+	}
+	return c.NoContent(http.StatusAccepted)
+}
+
+func writeEvent(w *echo.Response, event string, data []byte) error {
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+		return err
+	}
+	w.Flush()
+	return nil
+}
+
+func newSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// requireToken returns middleware that rejects requests without the MCP
+// token as a bearer token.
+func requireToken(mcpToken string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if !ok || mcpToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(mcpToken)) != 1 {
+				return echo.NewHTTPError(http.StatusUnauthorized, msgcat.Text(msgcat.ErrInvalidMCPToken))
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package mcp_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/joshjon/kit/log"
+	"github.com/joshjon/kit/server"
+	"github.com/joshjon/kit/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/app"
+	"github.com/vervesh/verve/internal/epic"
+	"github.com/vervesh/verve/internal/mcp"
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/sqlite"
+	"github.com/vervesh/verve/internal/task"
+)
+
+const mcpToken = "test-mcp-token"
+
+type fixture struct {
+	Server    *server.Server
+	TaskStore *task.Store
+	EpicStore *epic.Store
+	Repo      *repo.Repo
+	// OtherRepo is outside the MCP scope.
+	OtherRepo *repo.Repo
+	t         *testing.T
+	nextID    int
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	db := sqlite.NewTestDB(t)
+	logger := log.NewLogger(log.WithNop())
+
+	taskStore := task.NewStore(sqlite.NewTaskRepository(db), task.NewBroker(nil))
+	repoStore := repo.NewStore(sqlite.NewRepoRepository(db))
+	epicStore := epic.NewStore(sqlite.NewEpicRepository(db), nil, logger)
+
+	tools := mcp.Tools(taskStore, epicStore, repoStore, nil, mcp.Scope{Repos: []string{"owner/test-repo"}})
+	handler := mcp.NewHTTPHandler(mcp.NewServer("test", tools, logger), mcpToken)
+
+	srv, err := server.NewServer(testutil.GetFreePort(t), server.WithRequestTimeout(server.DefaultRequestTimeout, app.StreamingPaths...))
+	require.NoError(t, err)
+	srv.Register("/api/v1", handler)
+
+	go srv.Start()
+	err = srv.WaitHealthy(10, 100*time.Millisecond)
+	require.NoError(t, err)
+
+	// Pre-create a repo in scope, ready for tasks, and one outside it.
+	ctx := context.Background()
+	r, _ := repo.NewRepo("owner/test-repo")
+	require.NoError(t, repoStore.CreateRepo(ctx, r))
+	require.NoError(t, repoStore.UpdateRepoSetupStatus(ctx, r.ID, repo.SetupStatusReady))
+	r.SetupStatus = repo.SetupStatusReady
+	other, _ := repo.NewRepo("owner/other-repo")
+	require.NoError(t, repoStore.CreateRepo(ctx, other))
+	require.NoError(t, repoStore.UpdateRepoSetupStatus(ctx, other.ID, repo.SetupStatusReady))
+
+	t.Cleanup(func() { srv.Stop(context.Background()) })
+
+	return &fixture{
+		Server:    srv,
+		TaskStore: taskStore,
+		EpicStore: epicStore,
+		Repo:      r,
+		OtherRepo: other,
+		t:         t,
+	}
+}
+
+// --- URL helpers ---
+
+func (f *fixture) mcpURL() string {
+	return fmt.Sprintf("%s/api/v1/mcp", f.Server.Address())
+}
+
+func (f *fixture) sseURL() string {
+	return fmt.Sprintf("%s/api/v1/mcp/sse", f.Server.Address())
+}
+
+// --- Seed helpers ---
+
+func (f *fixture) seedTask(r *repo.Repo, title string) *task.Task {
+	f.t.Helper()
+	t := task.NewTask(r.ID.String(), title, "desc", nil, nil, 0, false, false, "sonnet", true)
+	require.NoError(f.t, f.TaskStore.CreateTask(context.Background(), t))
+	return t
+}
+
+// --- JSON-RPC helpers ---
+
+type rpcResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type toolResult struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	IsError bool `json:"isError"`
+}
+
+// call sends a JSON-RPC request to the streamable HTTP endpoint.
+func (f *fixture) call(method string, params any) rpcResponse {
+	f.t.Helper()
+	f.nextID++
+	httpRes := post(f.t, f.mcpURL(), mcpToken, map[string]any{"jsonrpc": "2.0", "id": f.nextID, "method": method, "params": params})
+	defer httpRes.Body.Close()
+	require.Equal(f.t, http.StatusOK, httpRes.StatusCode)
+	var res rpcResponse
+	require.NoError(f.t, json.NewDecoder(httpRes.Body).Decode(&res))
+	return res
+}
+
+// callTool calls a tool and returns its text result.
+func (f *fixture) callTool(name string, args any) (string, bool) {
+	f.t.Helper()
+	res := f.call("tools/call", map[string]any{"name": name, "arguments": args})
+	require.Nil(f.t, res.Error)
+	var result toolResult
+	require.NoError(f.t, json.Unmarshal(res.Result, &result))
+	require.Len(f.t, result.Content, 1)
+	return result.Content[0].Text, result.IsError
+}
+
+func post(t *testing.T, url, token string, body any) *http.Response {
+	t.Helper()
+	b, err := json.Marshal(body)
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(b))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	httpRes, err := testutil.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return httpRes
+}
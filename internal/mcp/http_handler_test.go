@@ -0,0 +1,214 @@
+package mcp_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/joshjon/kit/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/epic"
+	"github.com/vervesh/verve/internal/task"
+)
+
+// --- Auth ---
+
+func TestMCP_RequireToken(t *testing.T) {
+	f := newFixture(t)
+	body := map[string]any{"jsonrpc": "2.0", "id": 1, "method": "ping"}
+
+	httpRes := post(t, f.mcpURL(), "", body)
+	httpRes.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, httpRes.StatusCode)
+
+	httpRes = post(t, f.mcpURL(), "wrong", body)
+	httpRes.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, httpRes.StatusCode)
+}
+
+// --- Streamable HTTP ---
+
+func TestMCP_Initialize(t *testing.T) {
+	f := newFixture(t)
+
+	res := f.call("initialize", map[string]any{"protocolVersion": "2025-03-26", "capabilities": map[string]any{}})
+	require.Nil(t, res.Error)
+	var result struct {
+		ProtocolVersion string `json:"protocolVersion"`
+		ServerInfo      struct {
+			Name string `json:"name"`
+		} `json:"serverInfo"`
+	}
+	require.NoError(t, json.Unmarshal(res.Result, &result))
+	assert.Equal(t, "2025-03-26", result.ProtocolVersion)
+	assert.Equal(t, "verve", result.ServerInfo.Name)
+
+	httpRes := post(t, f.mcpURL(), mcpToken, map[string]any{"jsonrpc": "2.0", "method": "notifications/initialized"})
+	httpRes.Body.Close()
+	assert.Equal(t, http.StatusAccepted, httpRes.StatusCode, "notifications get no response")
+}
+
+func TestMCP_ListTools(t *testing.T) {
+	f := newFixture(t)
+
+	res := f.call("tools/list", nil)
+	require.Nil(t, res.Error)
+	var result struct {
+		Tools []struct {
+			Name        string         `json:"name"`
+			InputSchema map[string]any `json:"inputSchema"`
+		} `json:"tools"`
+	}
+	require.NoError(t, json.Unmarshal(res.Result, &result))
+	var names []string
+	for _, tool := range result.Tools {
+		names = append(names, tool.Name)
+		assert.Equal(t, "object", tool.InputSchema["type"], tool.Name)
+	}
+	assert.Equal(t, []string{"list_repos", "list_tasks", "get_task", "get_task_logs", "create_task", "create_epic", "get_epic"}, names)
+}
+
+// --- Tools ---
+
+func TestMCP_ListRepos_Scoped(t *testing.T) {
+	f := newFixture(t)
+
+	text, isError := f.callTool("list_repos", nil)
+	require.False(t, isError, text)
+	assert.Contains(t, text, "owner/test-repo")
+	assert.NotContains(t, text, "owner/other-repo")
+}
+
+func TestMCP_CreateTask(t *testing.T) {
+	f := newFixture(t)
+
+	text, isError := f.callTool("create_task", map[string]any{
+		"repo":                "owner/test-repo",
+		"title":               "Add health check",
+		"acceptance_criteria": []string{"GET /healthz returns 200"},
+	})
+	require.False(t, isError, text)
+	var created task.Task
+	require.NoError(t, json.Unmarshal([]byte(text), &created))
+	assert.Equal(t, f.Repo.ID.String(), created.RepoID)
+	assert.Equal(t, "sonnet", created.Model, "expected the default model")
+	assert.True(t, created.Ready)
+
+	text, isError = f.callTool("get_task", map[string]any{"task_id": created.ID.String()})
+	require.False(t, isError, text)
+	var got task.Task
+	require.NoError(t, json.Unmarshal([]byte(text), &got))
+	assert.Equal(t, "Add health check", got.Title)
+	assert.Equal(t, task.StatusPending, got.Status)
+
+	text, isError = f.callTool("list_tasks", map[string]any{"repo": f.Repo.ID.String(), "status": "pending"})
+	require.False(t, isError, text)
+	assert.Contains(t, text, created.ID.String())
+}
+
+func TestMCP_CreateTask_InvalidArguments(t *testing.T) {
+	f := newFixture(t)
+
+	text, isError := f.callTool("create_task", map[string]any{"repo": "owner/test-repo", "title": "  "})
+	assert.True(t, isError)
+	assert.Contains(t, text, "title")
+
+	text, isError = f.callTool("create_task", map[string]any{"title": "No repo"})
+	assert.True(t, isError)
+	assert.Equal(t, "repo is required", text)
+}
+
+func TestMCP_OutOfScope(t *testing.T) {
+	f := newFixture(t)
+
+	text, isError := f.callTool("create_task", map[string]any{"repo": "owner/other-repo", "title": "Sneaky"})
+	assert.True(t, isError)
+	assert.Contains(t, text, "owner/other-repo is not available")
+
+	other := f.seedTask(f.OtherRepo, "Other task")
+	text, isError = f.callTool("get_task", map[string]any{"task_id": other.ID.String()})
+	assert.True(t, isError)
+	assert.Equal(t, "Task not found", text)
+
+	text, isError = f.callTool("get_task_logs", map[string]any{"task_id": other.ID.String()})
+	assert.True(t, isError)
+	assert.Equal(t, "Task not found", text)
+}
+
+func TestMCP_GetTaskLogs_Tail(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedTask(f.Repo, "Logged task")
+	require.NoError(t, f.TaskStore.AppendTaskLogs(context.Background(), tsk.ID, 1, []string{"one", "two", "three"}))
+
+	text, isError := f.callTool("get_task_logs", map[string]any{"task_id": tsk.ID.String(), "tail": 2})
+	require.False(t, isError, text)
+	assert.Equal(t, "two\nthree", text)
+}
+
+func TestMCP_CreateEpic(t *testing.T) {
+	f := newFixture(t)
+
+	text, isError := f.callTool("create_epic", map[string]any{"repo": "owner/test-repo", "title": "Billing", "planning_prompt": "Keep it small"})
+	require.False(t, isError, text)
+	var created epic.Epic
+	require.NoError(t, json.Unmarshal([]byte(text), &created))
+	assert.Equal(t, epic.StatusPlanning, created.Status)
+	assert.Equal(t, "Keep it small", created.PlanningPrompt)
+
+	text, isError = f.callTool("get_epic", map[string]any{"epic_id": created.ID.String()})
+	require.False(t, isError, text)
+	assert.Contains(t, text, "Billing")
+}
+
+// --- Legacy HTTP+SSE ---
+
+func TestMCP_SSE(t *testing.T) {
+	f := newFixture(t)
+
+	req, err := http.NewRequest(http.MethodGet, f.sseURL(), nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+mcpToken)
+	httpRes, err := testutil.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer httpRes.Body.Close()
+	require.Equal(t, http.StatusOK, httpRes.StatusCode)
+
+	events := bufio.NewScanner(httpRes.Body)
+	readData := func(event string) string {
+		t.Helper()
+		var gotEvent string
+		for events.Scan() {
+			line := events.Text()
+			if v, ok := strings.CutPrefix(line, "event: "); ok {
+				gotEvent = v
+			}
+			if v, ok := strings.CutPrefix(line, "data: "); ok {
+				require.Equal(t, event, gotEvent)
+				return v
+			}
+		}
+		require.FailNow(t, "stream ended", events.Err())
+		return ""
+	}
+
+	endpoint := readData("endpoint")
+	assert.True(t, strings.HasPrefix(endpoint, "/api/v1/mcp/messages?session_id="), endpoint)
+
+	postRes := post(t, f.Server.Address()+endpoint, mcpToken, map[string]any{"jsonrpc": "2.0", "id": 7, "method": "ping"})
+	postRes.Body.Close()
+	assert.Equal(t, http.StatusAccepted, postRes.StatusCode)
+
+	var res rpcResponse
+	require.NoError(t, json.Unmarshal([]byte(readData("message")), &res))
+	assert.JSONEq(t, "7", string(res.ID))
+	assert.Nil(t, res.Error)
+
+	postRes = post(t, f.mcpURL()+"/messages?session_id=unknown", mcpToken, map[string]any{"jsonrpc": "2.0", "id": 8, "method": "ping"})
+	postRes.Body.Close()
+	assert.Equal(t, http.StatusNotFound, postRes.StatusCode)
+}
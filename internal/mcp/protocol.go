@@ -0,0 +1,109 @@
+package mcp
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// protocolVersions are the MCP protocol versions the server speaks, newest
+// first. A client asking for an unsupported version is offered the newest.
+var protocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+const jsonrpcVersion = "2.0"
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// request is a JSON-RPC request or, when it has no ID, a notification.
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+func (r request) isNotification() bool {
+	return len(r.ID) == 0
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+type initializeParams struct {
+	ProtocolVersion string `json:"protocolVersion"`
+}
+
+type initializeResult struct {
+	ProtocolVersion string         `json:"protocolVersion"`
+	Capabilities    map[string]any `json:"capabilities"`
+	ServerInfo      implementation `json:"serverInfo"`
+	Instructions    string         `json:"instructions,omitempty"`
+}
+
+type implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type listToolsResult struct {
+	Tools []Tool `json:"tools"`
+}
+
+type callToolParams struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+type callToolResult struct {
+	Content []content `json:"content"`
+	IsError bool      `json:"isError,omitempty"`
+}
+
+type content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// negotiateVersion returns the protocol version to use with a client that
+// asked for requested.
+func negotiateVersion(requested string) string {
+	if slices.Contains(protocolVersions, requested) {
+		return requested
+	}
+	return protocolVersions[0]
+}
+
+// errorResponse encodes an error response to msg. The ID is taken from msg
+// when it can be read; nil is returned when msg is a notification, which
+// gets no response.
+func errorResponse(msg []byte, code int, message string) []byte {
+	var req request
+	if err := json.Unmarshal(msg, &req); err == nil && req.isNotification() {
+		return nil
+	}
+	b, _ := json.Marshal(response{
+		JSONRPC: jsonrpcVersion,
+		ID:      req.ID,
+		Error:   &rpcError{Code: code, Message: message},
+	})
+	return b
+}
@@ -0,0 +1,199 @@
+// Package mcp exposes Verve as a Model Context Protocol server, so MCP
+// clients such as Claude Desktop can create tasks and epics, check their
+// status and read task logs as tools.
+//
+// The server speaks JSON-RPC over the streamable HTTP and legacy HTTP+SSE
+// transports (see HTTPHandler). The stdio transport (see ServeStdio) is a
+// bridge run by the CLI that forwards each message to the API server.
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cohesivestack/valgo"
+	"github.com/joshjon/kit/errtag"
+	"github.com/joshjon/kit/log"
+
+	"github.com/vervesh/verve/internal/openapi"
+)
+
+const serverName = "verve"
+
+// Handler handles a single JSON-RPC message and returns the encoded
+// response, or nil when the message gets none (notifications).
+type Handler interface {
+	Handle(ctx context.Context, msg []byte) ([]byte, error)
+}
+
+// HandlerFunc adapts a function to a Handler.
+type HandlerFunc func(ctx context.Context, msg []byte) ([]byte, error)
+
+// Handle calls f(ctx, msg).
+func (f HandlerFunc) Handle(ctx context.Context, msg []byte) ([]byte, error) {
+	return f(ctx, msg)
+}
+
+// Tool is an operation MCP clients can call.
+type Tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema *openapi.Schema `json:"inputSchema"`
+	// Call runs the tool with the client's arguments. A string result is
+	// returned to the client as is; anything else is encoded as JSON.
+	Call func(ctx context.Context, args json.RawMessage) (any, error) `json:"-"`
+}
+
+// ToolError is a tool failure reported to the client, such as a missing
+// argument, as opposed to an internal error.
+type ToolError string
+
+func (e ToolError) Error() string {
+	return string(e)
+}
+
+// Server is an MCP server exposing a set of tools. It implements Handler.
+type Server struct {
+	version string
+	tools   []Tool
+	byName  map[string]Tool
+	logger  log.Logger
+}
+
+// NewServer creates a new Server reporting version and exposing tools.
+func NewServer(version string, tools []Tool, logger log.Logger) *Server {
+	s := &Server{version: version, tools: tools, byName: make(map[string]Tool, len(tools)), logger: logger}
+	for _, t := range tools {
+		s.byName[t.Name] = t
+	}
+	return s
+}
+
+// Handle handles a single JSON-RPC message. Batches are not supported.
+func (s *Server) Handle(ctx context.Context, msg []byte) ([]byte, error) {
+	msg = bytes.TrimSpace(msg)
+	if len(msg) > 0 && msg[0] == '[' {
+		return errorResponse(nil, codeInvalidRequest, "batches are not supported"), nil
+	}
+	var req request
+	if err := json.Unmarshal(msg, &req); err != nil {
+		return errorResponse(nil, codeParseError, "parse error"), nil
+	}
+	if req.Method == "" {
+		// A response to a request we never send, or not JSON-RPC at all.
+		if req.isNotification() {
+			return nil, nil
+		}
+		return errorResponse(msg, codeInvalidRequest, "invalid request"), nil
+	}
+
+	result, err := s.dispatch(ctx, req)
+	if req.isNotification() {
+		return nil, nil
+	}
+	res := response{JSONRPC: jsonrpcVersion, ID: req.ID, Result: result}
+	if err != nil {
+		var rerr *rpcError
+		if !errors.As(err, &rerr) {
+			s.logger.Error("mcp request failed", "mcp.method", req.Method, "error", err)
+			rerr = &rpcError{Code: codeInternalError, Message: "internal error"}
+		}
+		res.Result, res.Error = nil, rerr
+	}
+	return json.Marshal(res)
+}
+
+func (s *Server) dispatch(ctx context.Context, req request) (any, error) {
+	switch req.Method {
+	case "initialize":
+		var params initializeParams
+		if err := unmarshalParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		return initializeResult{
+			ProtocolVersion: negotiateVersion(params.ProtocolVersion),
+			Capabilities:    map[string]any{"tools": map[string]any{}},
+			ServerInfo:      implementation{Name: serverName, Version: s.version},
+			Instructions:    "Verve runs AI coding agents against GitHub repos. Tasks and epics belong to a repo; use list_repos to find one.",
+		}, nil
+	case "notifications/initialized", "notifications/cancelled":
+		return nil, nil
+	case "ping":
+		return struct{}{}, nil
+	case "tools/list":
+		return listToolsResult{Tools: s.tools}, nil
+	case "tools/call":
+		var params callToolParams
+		if err := unmarshalParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		tool, ok := s.byName[params.Name]
+		if !ok {
+			return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("unknown tool %q", params.Name)}
+		}
+		return s.callTool(ctx, tool, params.Arguments), nil
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("method %q not found", req.Method)}
+	}
+}
+
+// callTool runs a tool. Failures are returned as error results so the
+// model calling the tool can see what went wrong.
+func (s *Server) callTool(ctx context.Context, tool Tool, args json.RawMessage) callToolResult {
+	if len(args) == 0 || string(args) == "null" {
+		args = json.RawMessage("{}")
+	}
+	out, err := tool.Call(ctx, args)
+	if err != nil {
+		return callToolResult{Content: []content{{Type: "text", Text: s.errorText(tool, err)}}, IsError: true}
+	}
+	text, ok := out.(string)
+	if !ok {
+		b, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return callToolResult{Content: []content{{Type: "text", Text: s.errorText(tool, err)}}, IsError: true}
+		}
+		text = string(b)
+	}
+	return callToolResult{Content: []content{{Type: "text", Text: text}}}
+}
+
+// errorText describes a tool failure. Only tool, validation and tagged
+// store errors are described; anything else is logged and reported as an
+// internal error.
+func (s *Server) errorText(tool Tool, err error) string {
+	var terr ToolError
+	if errors.As(err, &terr) {
+		return terr.Error()
+	}
+	var verr *valgo.Error
+	if errors.As(err, &verr) {
+		var details []string
+		for _, v := range verr.Errors() {
+			details = append(details, fmt.Sprintf("%s: %s", v.Name(), strings.Join(v.Messages(), ", ")))
+		}
+		sort.Strings(details)
+		return "invalid arguments: " + strings.Join(details, "; ")
+	}
+	var tagged errtag.Tagger
+	if errors.As(err, &tagged) {
+		return tagged.Msg()
+	}
+	s.logger.Error("mcp tool failed", "mcp.tool", tool.Name, "error", err)
+	return "internal error"
+}
+
+func unmarshalParams(params json.RawMessage, v any) error {
+	if len(params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return &rpcError{Code: codeInvalidParams, Message: "invalid params: " + err.Error()}
+	}
+	return nil
+}
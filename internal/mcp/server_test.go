@@ -0,0 +1,136 @@
+package mcp_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/joshjon/kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/mcp"
+	"github.com/vervesh/verve/internal/openapi"
+)
+
+func newTestServer() *mcp.Server {
+	echoTool := mcp.Tool{
+		Name:        "echo",
+		InputSchema: &openapi.Schema{Type: "object"},
+		Call: func(_ context.Context, args json.RawMessage) (any, error) {
+			var in struct {
+				Text string `json:"text"`
+			}
+			if err := json.Unmarshal(args, &in); err != nil {
+				return nil, err
+			}
+			if in.Text == "" {
+				return nil, mcp.ToolError("text is required")
+			}
+			return in.Text, nil
+		},
+	}
+	failTool := mcp.Tool{
+		Name:        "fail",
+		InputSchema: &openapi.Schema{Type: "object"},
+		Call: func(context.Context, json.RawMessage) (any, error) {
+			return nil, errors.New("database is locked")
+		},
+	}
+	return mcp.NewServer("1.2.3", []mcp.Tool{echoTool, failTool}, log.NewLogger(log.WithNop()))
+}
+
+func TestServer_Handle(t *testing.T) {
+	s := newTestServer()
+
+	tests := map[string]struct {
+		msg  string
+		want string // JSON; empty when no response is expected
+	}{
+		"initialize": {
+			msg:  `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05"}}`,
+			want: `{"jsonrpc":"2.0","id":1,"result":{"protocolVersion":"2024-11-05","capabilities":{"tools":{}},"serverInfo":{"name":"verve","version":"1.2.3"},"instructions":"Verve runs AI coding agents against GitHub repos. Tasks and epics belong to a repo; use list_repos to find one."}}`,
+		},
+		"initialize unsupported version": {
+			msg:  `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"1999-01-01"}}`,
+			want: `{"jsonrpc":"2.0","id":1,"result":{"protocolVersion":"2025-06-18","capabilities":{"tools":{}},"serverInfo":{"name":"verve","version":"1.2.3"},"instructions":"Verve runs AI coding agents against GitHub repos. Tasks and epics belong to a repo; use list_repos to find one."}}`,
+		},
+		"notification": {
+			msg: `{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		},
+		"ping": {
+			msg:  `{"jsonrpc":"2.0","id":"a","method":"ping"}`,
+			want: `{"jsonrpc":"2.0","id":"a","result":{}}`,
+		},
+		"tool result": {
+			msg:  `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo","arguments":{"text":"hi"}}}`,
+			want: `{"jsonrpc":"2.0","id":2,"result":{"content":[{"type":"text","text":"hi"}]}}`,
+		},
+		"tool error": {
+			msg:  `{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"echo"}}`,
+			want: `{"jsonrpc":"2.0","id":3,"result":{"content":[{"type":"text","text":"text is required"}],"isError":true}}`,
+		},
+		"internal tool error": {
+			msg:  `{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"fail","arguments":{}}}`,
+			want: `{"jsonrpc":"2.0","id":4,"result":{"content":[{"type":"text","text":"internal error"}],"isError":true}}`,
+		},
+		"unknown tool": {
+			msg:  `{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"nope"}}`,
+			want: `{"jsonrpc":"2.0","id":5,"error":{"code":-32602,"message":"unknown tool \"nope\""}}`,
+		},
+		"unknown method": {
+			msg:  `{"jsonrpc":"2.0","id":6,"method":"resources/list"}`,
+			want: `{"jsonrpc":"2.0","id":6,"error":{"code":-32601,"message":"method \"resources/list\" not found"}}`,
+		},
+		"parse error": {
+			msg:  `{"jsonrpc":`,
+			want: `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"parse error"}}`,
+		},
+		"batch": {
+			msg:  `[{"jsonrpc":"2.0","id":7,"method":"ping"}]`,
+			want: `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"batches are not supported"}}`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			res, err := s.Handle(context.Background(), []byte(tt.msg))
+			require.NoError(t, err)
+			if tt.want == "" {
+				assert.Nil(t, res)
+				return
+			}
+			assert.JSONEq(t, tt.want, string(res))
+		})
+	}
+}
+
+func TestServeStdio(t *testing.T) {
+	in := strings.Join([]string{
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		``,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"text":"hi"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"ping"}`,
+	}, "\n")
+
+	var out bytes.Buffer
+	require.NoError(t, mcp.ServeStdio(context.Background(), strings.NewReader(in), &out, newTestServer()))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":1,"result":{"content":[{"type":"text","text":"hi"}]}}`, lines[0])
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":2,"result":{}}`, lines[1])
+}
+
+func TestServeStdio_HandlerError(t *testing.T) {
+	h := mcp.HandlerFunc(func(context.Context, []byte) ([]byte, error) {
+		return nil, errors.New("connection refused")
+	})
+	in := `{"jsonrpc":"2.0","method":"notifications/initialized"}` + "\n" + `{"jsonrpc":"2.0","id":9,"method":"ping"}` + "\n"
+
+	var out bytes.Buffer
+	require.NoError(t, mcp.ServeStdio(context.Background(), strings.NewReader(in), &out, h))
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":9,"error":{"code":-32603,"message":"connection refused"}}`, out.String())
+}
@@ -0,0 +1,42 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+)
+
+// maxMessageSize is the largest JSON-RPC message the stdio transport reads.
+const maxMessageSize = 4 << 20
+
+// ServeStdio serves the MCP stdio transport: newline-delimited JSON-RPC
+// messages are read from r and handled one at a time, and responses are
+// written to w. It returns when r is exhausted or ctx is done.
+//
+// A handler error, such as the API server being unreachable, is returned to
+// the client as an error response rather than ending the session.
+func ServeStdio(ctx context.Context, r io.Reader, w io.Writer, h Handler) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxMessageSize)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		msg := bytes.TrimSpace(scanner.Bytes())
+		if len(msg) == 0 {
+			continue
+		}
+		res, err := h.Handle(ctx, msg)
+		if err != nil {
+			res = errorResponse(msg, codeInternalError, err.Error())
+		}
+		if res == nil {
+			continue
+		}
+		if _, err := w.Write(append(res, '\n')); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,459 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/vervesh/verve/internal/epic"
+	"github.com/vervesh/verve/internal/epicapi"
+	"github.com/vervesh/verve/internal/msgcat"
+	"github.com/vervesh/verve/internal/openapi"
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/setting"
+	"github.com/vervesh/verve/internal/task"
+	"github.com/vervesh/verve/internal/taskapi"
+	"github.com/vervesh/verve/pkg/verveclient"
+)
+
+const (
+	defaultLogTail = 200
+	maxLogTail     = 2000
+)
+
+// Scope limits the repos MCP clients can see and act on.
+type Scope struct {
+	Repos []string // Repo full names (owner/name); empty allows every repo
+}
+
+func (s Scope) allows(fullName string) bool {
+	return len(s.Repos) == 0 || slices.ContainsFunc(s.Repos, func(name string) bool {
+		return strings.EqualFold(name, fullName)
+	})
+}
+
+// toolset implements the tools on top of the stores.
+type toolset struct {
+	taskStore      *task.Store
+	epicStore      *epic.Store
+	repoStore      *repo.Store
+	settingService *setting.Service
+	scope          Scope
+}
+
+// Tools returns the tools for managing tasks and epics in the repos scope
+// allows. Tasks and epics of other repos are reported as not found.
+func Tools(taskStore *task.Store, epicStore *epic.Store, repoStore *repo.Store, settingService *setting.Service, scope Scope) []Tool {
+	ts := &toolset{taskStore: taskStore, epicStore: epicStore, repoStore: repoStore, settingService: settingService, scope: scope}
+	repoProp := str("Repo owner/name (e.g. acme/api) or repo ID")
+	return []Tool{
+		{
+			Name:        "list_repos",
+			Description: "List the repos tasks and epics can be created in.",
+			InputSchema: object(nil),
+			Call:        ts.listRepos,
+		},
+		{
+			Name:        "list_tasks",
+			Description: "List a repo's tasks with their status and pull request.",
+			InputSchema: object(map[string]*openapi.Schema{
+				"repo":   repoProp,
+				"status": str("Only list tasks with this status: pending, running, review, merged, closed or failed"),
+			}, "repo"),
+			Call: ts.listTasks,
+		},
+		{
+			Name:        "get_task",
+			Description: "Get a task, including its status, attempts, cost and pull request. Use get_task_logs for its logs.",
+			InputSchema: object(map[string]*openapi.Schema{
+				"task_id": str("Task ID"),
+			}, "task_id"),
+			Call: ts.getTask,
+		},
+		{
+			Name:        "get_task_logs",
+			Description: "Get the last lines of a task's agent logs.",
+			InputSchema: object(map[string]*openapi.Schema{
+				"task_id": str("Task ID"),
+				"attempt": integer("Only return this attempt's logs (default: every attempt)"),
+				"tail":    integer(fmt.Sprintf("Number of lines to return (default %d, at most %d)", defaultLogTail, maxLogTail)),
+			}, "task_id"),
+			Call: ts.getTaskLogs,
+		},
+		{
+			Name:        "create_task",
+			Description: "Create a task for an agent to work on in a repo. The agent opens a pull request when it is done.",
+			InputSchema: object(map[string]*openapi.Schema{
+				"repo":                repoProp,
+				"title":               str("Short summary of the change"),
+				"description":         str("What the agent should do"),
+				"acceptance_criteria": stringList("Conditions the change must meet"),
+				"depends_on":          stringList("IDs of tasks that must finish first"),
+				"model":               str("Model to run the task with (default: the server's default model)"),
+			}, "repo", "title"),
+			Call: ts.createTask,
+		},
+		{
+			Name:        "create_epic",
+			Description: "Create an epic. A planning agent breaks it into proposed tasks, which are created once the epic is confirmed in Verve.",
+			InputSchema: object(map[string]*openapi.Schema{
+				"repo":            repoProp,
+				"title":           str("Short summary of the deliverable"),
+				"description":     str("What the epic should deliver"),
+				"planning_prompt": str("Extra instructions for the planning agent"),
+				"model":           str("Model to plan with (default: the server's default model)"),
+			}, "repo", "title"),
+			Call: ts.createEpic,
+		},
+		{
+			Name:        "get_epic",
+			Description: "Get an epic, including its status, proposed tasks and the IDs of its tasks.",
+			InputSchema: object(map[string]*openapi.Schema{
+				"epic_id": str("Epic ID"),
+			}, "epic_id"),
+			Call: ts.getEpic,
+		},
+	}
+}
+
+// taskSummary is a task as listed by list_tasks.
+type taskSummary struct {
+	ID             task.TaskID `json:"id"`
+	Number         int         `json:"number"`
+	Title          string      `json:"title"`
+	Status         task.Status `json:"status"`
+	EpicID         string      `json:"epic_id,omitempty"`
+	PullRequestURL string      `json:"pull_request_url,omitempty"`
+	CostUSD        float64     `json:"cost_usd"`
+}
+
+type repoSummary struct {
+	ID          repo.RepoID `json:"id"`
+	FullName    string      `json:"full_name"`
+	Summary     string      `json:"summary,omitempty"`
+	SetupStatus string      `json:"setup_status"`
+}
+
+func (ts *toolset) listRepos(ctx context.Context, _ json.RawMessage) (any, error) {
+	repos, err := ts.repoStore.ListRepos(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := []repoSummary{}
+	for _, r := range repos {
+		if ts.scope.allows(r.FullName) {
+			out = append(out, repoSummary{ID: r.ID, FullName: r.FullName, Summary: r.Summary, SetupStatus: r.SetupStatus})
+		}
+	}
+	return out, nil
+}
+
+func (ts *toolset) listTasks(ctx context.Context, raw json.RawMessage) (any, error) {
+	var args struct {
+		Repo   string `json:"repo"`
+		Status string `json:"status"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	r, err := ts.readRepo(ctx, args.Repo)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := ts.taskStore.ListTasksByRepo(ctx, r.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	out := []taskSummary{}
+	for _, t := range tasks {
+		if t.Type != task.TaskTypeTask || (args.Status != "" && string(t.Status) != args.Status) {
+			continue
+		}
+		out = append(out, taskSummary{
+			ID:             t.ID,
+			Number:         t.Number,
+			Title:          t.Title,
+			Status:         t.Status,
+			EpicID:         t.EpicID,
+			PullRequestURL: t.PullRequestURL,
+			CostUSD:        t.CostUSD,
+		})
+	}
+	return out, nil
+}
+
+func (ts *toolset) getTask(ctx context.Context, raw json.RawMessage) (any, error) {
+	var args struct {
+		TaskID string `json:"task_id"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	t, err := ts.readTask(ctx, args.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	t.Logs = nil
+	t.ComputeDuration()
+	return t, nil
+}
+
+func (ts *toolset) getTaskLogs(ctx context.Context, raw json.RawMessage) (any, error) {
+	var args struct {
+		TaskID  string `json:"task_id"`
+		Attempt int    `json:"attempt"`
+		Tail    int    `json:"tail"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	t, err := ts.readTask(ctx, args.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	tail := args.Tail
+	if tail <= 0 {
+		tail = defaultLogTail
+	}
+	tail = min(tail, maxLogTail)
+
+	var lines []string
+	if args.Attempt > 0 {
+		err = ts.taskStore.StreamAttemptLogs(ctx, t.ID, args.Attempt, func(batch []string) error {
+			lines = append(lines, batch...)
+			return nil
+		})
+	} else {
+		lines, err = ts.taskStore.ReadTaskLogs(ctx, t.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return "No logs yet.", nil
+	}
+	if len(lines) > tail {
+		lines = lines[len(lines)-tail:]
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func (ts *toolset) createTask(ctx context.Context, raw json.RawMessage) (any, error) {
+	var args struct {
+		Repo               string   `json:"repo"`
+		Title              string   `json:"title"`
+		Description        string   `json:"description"`
+		AcceptanceCriteria []string `json:"acceptance_criteria"`
+		DependsOn          []string `json:"depends_on"`
+		Model              string   `json:"model"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	r, err := ts.readRepo(ctx, args.Repo)
+	if err != nil {
+		return nil, err
+	}
+	req := taskapi.CreateTaskRequest{
+		RepoID: r.ID.String(),
+		CreateTaskRequest: verveclient.CreateTaskRequest{
+			Title:              args.Title,
+			Description:        args.Description,
+			AcceptanceCriteria: args.AcceptanceCriteria,
+			DependsOn:          args.DependsOn,
+			Model:              args.Model,
+		},
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	// Block task creation until repo setup is complete.
+	if r.SetupStatus != repo.SetupStatusReady {
+		return nil, ToolError(msgcat.Text(msgcat.ErrRepoSetupIncompleteTasks))
+	}
+
+	defaults, err := ts.repoStore.TeamDefaults(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	t := task.NewTask(r.ID.String(), req.Title, req.Description, req.DependsOn, req.AcceptanceCriteria, ts.maxCostUSD(defaults), false, false, ts.model(req.Model, defaults), true)
+	if err := ts.taskStore.CreateTask(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (ts *toolset) createEpic(ctx context.Context, raw json.RawMessage) (any, error) {
+	var args struct {
+		Repo           string `json:"repo"`
+		Title          string `json:"title"`
+		Description    string `json:"description"`
+		PlanningPrompt string `json:"planning_prompt"`
+		Model          string `json:"model"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	r, err := ts.readRepo(ctx, args.Repo)
+	if err != nil {
+		return nil, err
+	}
+	req := epicapi.CreateEpicRequest{
+		RepoID: r.ID.String(),
+		CreateEpicRequest: verveclient.CreateEpicRequest{
+			Title:          args.Title,
+			Description:    args.Description,
+			PlanningPrompt: args.PlanningPrompt,
+			Model:          args.Model,
+		},
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	// Block epic creation until repo setup is complete.
+	if r.SetupStatus != repo.SetupStatusReady {
+		return nil, ToolError(msgcat.Text(msgcat.ErrRepoSetupIncompleteEpics))
+	}
+
+	e := epic.NewEpic(r.ID.String(), req.Title, req.Description)
+	e.PlanningPrompt = req.PlanningPrompt
+	defaults, err := ts.repoStore.TeamDefaults(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	e.Model = ts.model(req.Model, defaults)
+	if err := ts.epicStore.CreateEpic(ctx, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (ts *toolset) getEpic(ctx context.Context, raw json.RawMessage) (any, error) {
+	var args struct {
+		EpicID string `json:"epic_id"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	id, err := epic.ParseEpicID(args.EpicID)
+	if err != nil {
+		return nil, ToolError(msgcat.Text(msgcat.ErrInvalidID, "epic"))
+	}
+	e, err := ts.epicStore.ReadEpic(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ts.inScope(ctx, e.RepoID) {
+		return nil, ToolError(msgcat.Text(msgcat.ErrEpicNotFound))
+	}
+	return e, nil
+}
+
+// readRepo reads a repo by its full name or ID, rejecting repos outside the
+// scope.
+func (ts *toolset) readRepo(ctx context.Context, ref string) (*repo.Repo, error) {
+	var (
+		r   *repo.Repo
+		err error
+	)
+	switch {
+	case ref == "":
+		return nil, ToolError("repo is required")
+	case strings.Contains(ref, "/"):
+		r, err = ts.repoStore.ReadRepoByFullName(ctx, ref)
+	default:
+		id, perr := repo.ParseRepoID(ref)
+		if perr != nil {
+			return nil, ToolError(msgcat.Text(msgcat.ErrInvalidID, "repo"))
+		}
+		r, err = ts.repoStore.ReadRepo(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !ts.scope.allows(r.FullName) {
+		return nil, ToolError(msgcat.Text(msgcat.ErrRepoOutOfMCPScope, r.FullName))
+	}
+	return r, nil
+}
+
+// readTask reads a task, reporting tasks of repos outside the scope as not
+// found.
+func (ts *toolset) readTask(ctx context.Context, idStr string) (*task.Task, error) {
+	id, err := task.ParseTaskID(idStr)
+	if err != nil {
+		return nil, ToolError(msgcat.Text(msgcat.ErrInvalidID, "task"))
+	}
+	t, err := ts.taskStore.ReadTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ts.inScope(ctx, t.RepoID) {
+		return nil, ToolError(msgcat.Text(msgcat.ErrTaskNotFound))
+	}
+	return t, nil
+}
+
+func (ts *toolset) inScope(ctx context.Context, repoID string) bool {
+	if len(ts.scope.Repos) == 0 {
+		return true
+	}
+	id, err := repo.ParseRepoID(repoID)
+	if err != nil {
+		return false
+	}
+	r, err := ts.repoStore.ReadRepo(ctx, id)
+	return err == nil && ts.scope.allows(r.FullName)
+}
+
+// model returns the model to run with when the client asked for requested,
+// falling back to the model the repo inherits from its team.
+func (ts *toolset) model(requested string, defaults repo.TeamDefaults) string {
+	model := requested
+	if model == "" {
+		model = defaults.Model
+	}
+	if model == "" && ts.settingService != nil {
+		model = ts.settingService.Get(setting.KeyDefaultModel)
+	}
+	if model == "" {
+		model = "sonnet"
+	}
+	return model
+}
+
+// maxCostUSD returns the budget of a task created without one.
+func (ts *toolset) maxCostUSD(defaults repo.TeamDefaults) float64 {
+	if defaults.MaxCostUSD == 0 && ts.settingService != nil {
+		return ts.settingService.Float(setting.KeyDefaultMaxCostUSD)
+	}
+	return defaults.MaxCostUSD
+}
+
+func decodeArgs(raw json.RawMessage, v any) error {
+	if err := json.Unmarshal(raw, v); err != nil {
+		return ToolError("invalid arguments: " + err.Error())
+	}
+	return nil
+}
+
+func object(props map[string]*openapi.Schema, required ...string) *openapi.Schema {
+	if props == nil {
+		props = map[string]*openapi.Schema{}
+	}
+	return &openapi.Schema{Type: "object", Properties: props, Required: required}
+}
+
+func str(description string) *openapi.Schema {
+	return &openapi.Schema{Type: "string", Description: description}
+}
+
+func integer(description string) *openapi.Schema {
+	return &openapi.Schema{Type: "integer", Description: description}
+}
+
+func stringList(description string) *openapi.Schema {
+	return &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string"}, Description: description}
+}
@@ -15,6 +15,7 @@ type PlanningEpic struct {
 	Title     string
 	RepoID    string
 	Model     string
+	CostUSD   float64
 	ClaimedAt *time.Time
 }
 
@@ -23,9 +24,17 @@ type PlanningEpicLister interface {
 	ListPlanningEpicsForMetrics(ctx context.Context) ([]PlanningEpic, error)
 }
 
+// PlanningCostReader reports the accumulated planning cost across all epics.
+// Epic listers may optionally implement it to include planning spend in the
+// total cost.
+type PlanningCostReader interface {
+	TotalPlanningCost(ctx context.Context) (float64, error)
+}
+
 // PlanningEpicListerFunc adapts a function to the PlanningEpicLister interface.
 type PlanningEpicListerFunc struct {
-	fn func(ctx context.Context) ([]PlanningEpic, error)
+	fn     func(ctx context.Context) ([]PlanningEpic, error)
+	costFn func(ctx context.Context) (float64, error)
 }
 
 // NewPlanningEpicListerFunc creates a PlanningEpicLister from a function.
@@ -33,10 +42,23 @@ func NewPlanningEpicListerFunc(fn func(ctx context.Context) ([]PlanningEpic, err
 	return &PlanningEpicListerFunc{fn: fn}
 }
 
+// WithPlanningCost sets the function used to read the total planning cost.
+func (f *PlanningEpicListerFunc) WithPlanningCost(fn func(ctx context.Context) (float64, error)) *PlanningEpicListerFunc {
+	f.costFn = fn
+	return f
+}
+
 func (f *PlanningEpicListerFunc) ListPlanningEpicsForMetrics(ctx context.Context) ([]PlanningEpic, error) {
 	return f.fn(ctx)
 }
 
+func (f *PlanningEpicListerFunc) TotalPlanningCost(ctx context.Context) (float64, error) {
+	if f.costFn == nil {
+		return 0, nil
+	}
+	return f.costFn(ctx)
+}
+
 // TaskLister lists all tasks for metrics computation.
 type TaskLister interface {
 	ListTasks(ctx context.Context) ([]*task.Task, error)
@@ -57,9 +79,13 @@ type Metrics struct {
 	CompletedTasks int `json:"completed_tasks"`
 	// Failed tasks
 	FailedTasks int `json:"failed_tasks"`
+	// Failed tasks per failure category, largest first
+	FailureCategories []task.FailureCategoryCount `json:"failure_categories"`
 
-	// Total cost across all tasks (USD)
+	// Total cost across all tasks and epic planning sessions (USD)
 	TotalCostUSD float64 `json:"total_cost_usd"`
+	// Cost of epic planning sessions (USD), included in TotalCostUSD
+	PlanningCostUSD float64 `json:"planning_cost_usd"`
 
 	// Details about each currently running agent
 	ActiveAgents []ActiveAgent `json:"active_agents"`
@@ -113,6 +139,7 @@ func Compute(ctx context.Context, lister TaskLister, epicLister PlanningEpicList
 	m := &Metrics{}
 
 	var recentTerminal []*task.Task
+	var failed []*task.Task
 
 	for _, t := range tasks {
 		m.TotalTasks++
@@ -146,8 +173,10 @@ func Compute(ctx context.Context, lister TaskLister, epicLister PlanningEpicList
 		case task.StatusFailed:
 			m.FailedTasks++
 			recentTerminal = append(recentTerminal, t)
+			failed = append(failed, t)
 		}
 	}
+	m.FailureCategories = task.CountFailureCategories(failed)
 
 	// Include epics that are actively being planned as active agents.
 	if epicLister != nil {
@@ -160,6 +189,7 @@ func Compute(ctx context.Context, lister TaskLister, epicLister PlanningEpicList
 					TaskTitle:  ep.Title,
 					RepoID:     ep.RepoID,
 					Model:      ep.Model,
+					CostUSD:    ep.CostUSD,
 					EpicID:     ep.ID,
 					IsPlanning: true,
 					EpicTitle:  ep.Title,
@@ -172,6 +202,12 @@ func Compute(ctx context.Context, lister TaskLister, epicLister PlanningEpicList
 				m.ActiveAgents = append(m.ActiveAgents, agent)
 			}
 		}
+		if costReader, ok := epicLister.(PlanningCostReader); ok {
+			if cost, err := costReader.TotalPlanningCost(ctx); err == nil {
+				m.PlanningCostUSD = cost
+				m.TotalCostUSD += cost
+			}
+		}
 	}
 
 	// Sort terminal tasks by updated_at descending (most recent first) and take top 10.
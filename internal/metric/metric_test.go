@@ -195,3 +195,94 @@ func TestCompute_RecentCompletionsLimit(t *testing.T) {
 	// Should be limited to 10
 	assert.Len(t, metrics.RecentCompletions, 10)
 }
+
+func TestCompute_IncludesPlanningCost(t *testing.T) {
+	tsk := task.NewTask("repo_1", "task", "desc", nil, nil, 0, false, false, "sonnet", true)
+	tsk.CostUSD = 2.0
+	lister := &mockTaskLister{tasks: []*task.Task{tsk}}
+
+	epicLister := NewPlanningEpicListerFunc(
+		func(ctx context.Context) ([]PlanningEpic, error) {
+			return []PlanningEpic{{ID: "epc_planning1", Title: "Plan", CostUSD: 0.25}}, nil
+		},
+	).WithPlanningCost(func(ctx context.Context) (float64, error) {
+		return 0.75, nil
+	})
+
+	metrics, err := Compute(context.Background(), lister, epicLister, nil)
+	require.NoError(t, err)
+
+	assert.InDelta(t, 0.75, metrics.PlanningCostUSD, 0.0001)
+	assert.InDelta(t, 2.75, metrics.TotalCostUSD, 0.0001)
+	require.Len(t, metrics.ActiveAgents, 1)
+	assert.InDelta(t, 0.25, metrics.ActiveAgents[0].CostUSD, 0.0001)
+}
+
+func TestCompute_FailureCategories(t *testing.T) {
+	newFailed := func(category string) *task.Task {
+		tsk := task.NewTask("repo_1", "failed task", "desc", nil, nil, 0, false, false, "sonnet", true)
+		tsk.Status = task.StatusFailed
+		tsk.FailureCategory = category
+		return tsk
+	}
+	lister := &mockTaskLister{tasks: []*task.Task{
+		newFailed(task.FailureMaxAttempts),
+		newFailed("ci_failure"),
+		newFailed("ci_failure"),
+		newFailed(""),
+	}}
+
+	metrics, err := Compute(context.Background(), lister, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []task.FailureCategoryCount{
+		{Category: "ci_failure", Count: 2},
+		{Category: task.FailureMaxAttempts, Count: 1},
+		{Category: task.FailureUnknown, Count: 1},
+	}, metrics.FailureCategories)
+}
+
+func TestComputeReviewLatency(t *testing.T) {
+	now := time.Now()
+	since := now.Add(-30 * 24 * time.Hour)
+	span := func(inReview time.Duration, open bool, sla time.Duration) *task.ReviewSpan {
+		s := &task.ReviewSpan{TaskID: task.NewTaskID(), RepoID: "repo_1", Title: "Task", SLA: sla}
+		if open {
+			s.StartedAt = now.Add(-inReview)
+		} else {
+			end := now.Add(-time.Hour)
+			s.StartedAt = end.Add(-inReview)
+			s.EndedAt = &end
+		}
+		return s
+	}
+
+	spans := []*task.ReviewSpan{
+		span(1*time.Hour, false, 0),
+		span(2*time.Hour, false, 0),
+		span(3*time.Hour, false, 4*time.Hour),
+		span(10*time.Hour, false, 4*time.Hour),
+		span(5*time.Hour, true, 4*time.Hour),
+		span(8*time.Hour, true, 4*time.Hour),
+		span(30*time.Minute, true, 4*time.Hour),
+	}
+	rl := ComputeReviewLatency(spans, "repo_1", since, now)
+
+	assert.Equal(t, 4, rl.Completed)
+	assert.Equal(t, 2*time.Hour.Seconds(), rl.P50Seconds)
+	assert.Equal(t, 10*time.Hour.Seconds(), rl.P90Seconds)
+	assert.Equal(t, 10*time.Hour.Seconds(), rl.MaxSeconds)
+	assert.Equal(t, 3, rl.Open)
+	assert.Equal(t, 3, rl.Overdue)
+	require.Len(t, rl.OverdueTasks, 2)
+	assert.Equal(t, spans[5].TaskID.String(), rl.OverdueTasks[0].TaskID)
+	assert.Equal(t, spans[4].TaskID.String(), rl.OverdueTasks[1].TaskID)
+	assert.Equal(t, int64(4*time.Hour.Seconds()), rl.OverdueTasks[0].SLASeconds)
+}
+
+func TestComputeReviewLatency_Empty(t *testing.T) {
+	rl := ComputeReviewLatency(nil, "", time.Now().Add(-time.Hour), time.Now())
+	assert.Zero(t, rl.Completed)
+	assert.Zero(t, rl.P95Seconds)
+	assert.Zero(t, rl.Open)
+	assert.NotNil(t, rl.OverdueTasks)
+}
@@ -0,0 +1,81 @@
+package metric
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/vervesh/verve/internal/costguard"
+	"github.com/vervesh/verve/internal/task"
+)
+
+// ReviewLatency reports how long tasks wait in review.
+type ReviewLatency struct {
+	RepoID string    `json:"repo_id,omitempty"`
+	Since  time.Time `json:"since"`
+
+	// Reviews that ended since Since, and percentiles of how long they took
+	Completed  int     `json:"completed"`
+	P50Seconds float64 `json:"p50_seconds"`
+	P90Seconds float64 `json:"p90_seconds"`
+	P95Seconds float64 `json:"p95_seconds"`
+	MaxSeconds float64 `json:"max_seconds"`
+
+	// Tasks in review now
+	Open int `json:"open"`
+	// Completed and open reviews that outlasted their repo's review SLA
+	Overdue int `json:"overdue"`
+	// Tasks in review now for longer than their repo's review SLA, longest
+	// waiting first
+	OverdueTasks []OverdueTask `json:"overdue_tasks"`
+}
+
+// OverdueTask describes a task in review for longer than its repo's review
+// SLA.
+type OverdueTask struct {
+	TaskID          string    `json:"task_id"`
+	TaskNumber      int       `json:"task_number"`
+	TaskTitle       string    `json:"task_title"`
+	RepoID          string    `json:"repo_id"`
+	InReviewSince   time.Time `json:"in_review_since"`
+	InReviewSeconds int64     `json:"in_review_seconds"`
+	SLASeconds      int64     `json:"sla_seconds"`
+}
+
+// ComputeReviewLatency summarizes the review spans of tasks that were in
+// review at any point since since, as listed by task.Store.ListReviewSpans.
+func ComputeReviewLatency(spans []*task.ReviewSpan, repoID string, since, now time.Time) *ReviewLatency {
+	rl := &ReviewLatency{RepoID: repoID, Since: since, OverdueTasks: []OverdueTask{}}
+	var durations []float64
+	for _, span := range spans {
+		if span.Overdue(now) {
+			rl.Overdue++
+		}
+		if span.EndedAt != nil {
+			durations = append(durations, span.Duration(now).Seconds())
+			continue
+		}
+		rl.Open++
+		if span.Overdue(now) {
+			rl.OverdueTasks = append(rl.OverdueTasks, OverdueTask{
+				TaskID:          span.TaskID.String(),
+				TaskNumber:      span.TaskNumber,
+				TaskTitle:       span.Title,
+				RepoID:          span.RepoID,
+				InReviewSince:   span.StartedAt,
+				InReviewSeconds: int64(span.Duration(now).Seconds()),
+				SLASeconds:      int64(span.SLA.Seconds()),
+			})
+		}
+	}
+
+	rl.Completed = len(durations)
+	rl.P50Seconds = costguard.Percentile(durations, 50)
+	rl.P90Seconds = costguard.Percentile(durations, 90)
+	rl.P95Seconds = costguard.Percentile(durations, 95)
+	rl.MaxSeconds = costguard.Percentile(durations, 100)
+	slices.SortFunc(rl.OverdueTasks, func(a, b OverdueTask) int {
+		return cmp.Compare(b.InReviewSeconds, a.InReviewSeconds)
+	})
+	return rl
+}
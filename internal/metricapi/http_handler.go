@@ -1,7 +1,9 @@
 package metricapi
 
 import (
+	"cmp"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/joshjon/kit/server"
@@ -9,6 +11,7 @@ import (
 
 	"github.com/vervesh/verve/internal/metric"
 	"github.com/vervesh/verve/internal/task"
+	"github.com/vervesh/verve/internal/throughput"
 	"github.com/vervesh/verve/internal/workertracker"
 )
 
@@ -17,16 +20,20 @@ type HTTPHandler struct {
 	store          *task.Store
 	epicLister     metric.PlanningEpicLister
 	workerRegistry *workertracker.Registry
+	throughput     throughput.Repository
 }
 
 // NewHTTPHandler creates a new HTTPHandler.
-func NewHTTPHandler(store *task.Store, epicLister metric.PlanningEpicLister, workerRegistry *workertracker.Registry) *HTTPHandler {
-	return &HTTPHandler{store: store, epicLister: epicLister, workerRegistry: workerRegistry}
+func NewHTTPHandler(store *task.Store, epicLister metric.PlanningEpicLister, workerRegistry *workertracker.Registry, throughputRepo throughput.Repository) *HTTPHandler {
+	return &HTTPHandler{store: store, epicLister: epicLister, workerRegistry: workerRegistry, throughput: throughputRepo}
 }
 
 // Register adds the endpoints to the provided Echo router group.
 func (h *HTTPHandler) Register(g *echo.Group) {
 	g.GET("/metrics", h.GetMetrics)
+	g.GET("/workers", h.ListWorkers)
+	g.GET("/reports/review-latency", h.GetReviewLatency)
+	g.GET("/reports/throughput", h.GetThroughput)
 }
 
 // GetMetrics handles GET /metrics
@@ -43,3 +50,61 @@ func (h *HTTPHandler) GetMetrics(c echo.Context) error {
 	}
 	return server.SetResponse(c, http.StatusOK, metrics)
 }
+
+// ListWorkers handles GET /workers
+// Returns the workers that polled or sent a heartbeat recently, with their
+// hostname, version, capacity and the tasks they are running.
+func (h *HTTPHandler) ListWorkers(c echo.Context) error {
+	workers := []workertracker.WorkerInfo{}
+	if h.workerRegistry != nil {
+		workers = h.workerRegistry.ListWorkers(2 * time.Minute)
+	}
+	slices.SortFunc(workers, func(a, b workertracker.WorkerInfo) int {
+		return a.ConnectedAt.Compare(b.ConnectedAt)
+	})
+	return server.SetResponseList(c, http.StatusOK, workers, "")
+}
+
+// GetReviewLatency handles GET /reports/review-latency
+// Returns percentiles of how long tasks waited in review over the reporting
+// window, with the tasks in review now past their repo's review SLA.
+func (h *HTTPHandler) GetReviewLatency(c echo.Context) error {
+	req, err := server.BindRequest[ReportRequest](c)
+	if err != nil {
+		return err
+	}
+	days := cmp.Or(req.Days, defaultReportDays)
+	now := time.Now()
+	since := now.AddDate(0, 0, -days)
+
+	spans, err := h.store.ListReviewSpans(c.Request().Context(), req.RepoID, since)
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, metric.ComputeReviewLatency(spans, req.RepoID, since, now))
+}
+
+// GetThroughput handles GET /reports/throughput
+// Returns the tasks created, merged and failed per day in each repo over the
+// reporting window, with percentiles of how long tasks took to be claimed,
+// to reach review and to merge.
+func (h *HTTPHandler) GetThroughput(c echo.Context) error {
+	req, err := server.BindRequest[ReportRequest](c)
+	if err != nil {
+		return err
+	}
+	days := cmp.Or(req.Days, defaultReportDays)
+	now := time.Now()
+	since := throughput.Since(days, now)
+
+	ctx := c.Request().Context()
+	creations, err := h.throughput.ListCreationsSince(ctx, since)
+	if err != nil {
+		return err
+	}
+	transitions, err := h.throughput.ListTransitionsSince(ctx, since)
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, throughput.Compute(creations, transitions, req.RepoID, days, now))
+}
@@ -14,13 +14,15 @@ import (
 	"github.com/vervesh/verve/internal/repo"
 	"github.com/vervesh/verve/internal/sqlite"
 	"github.com/vervesh/verve/internal/task"
+	"github.com/vervesh/verve/internal/workertracker"
 )
 
 type fixture struct {
-	Server   *server.Server
-	TaskRepo task.Repository
-	Repo     *repo.Repo
-	t        *testing.T
+	Server         *server.Server
+	TaskRepo       task.Repository
+	WorkerRegistry *workertracker.Registry
+	Repo           *repo.Repo
+	t              *testing.T
 }
 
 func newFixture(t *testing.T) *fixture {
@@ -34,7 +36,8 @@ func newFixture(t *testing.T) *fixture {
 	repoRepo := sqlite.NewRepoRepository(db)
 	repoStore := repo.NewStore(repoRepo)
 
-	handler := metricapi.NewHTTPHandler(taskStore, nil, nil)
+	registry := workertracker.New()
+	handler := metricapi.NewHTTPHandler(taskStore, nil, registry, sqlite.NewThroughputRepository(db))
 
 	srv, err := server.NewServer(testutil.GetFreePort(t))
 	require.NoError(t, err)
@@ -51,10 +54,11 @@ func newFixture(t *testing.T) *fixture {
 	t.Cleanup(func() { srv.Stop(context.Background()) })
 
 	return &fixture{
-		Server:   srv,
-		TaskRepo: taskRepo,
-		Repo:     r,
-		t:        t,
+		Server:         srv,
+		TaskRepo:       taskRepo,
+		WorkerRegistry: registry,
+		Repo:           r,
+		t:              t,
 	}
 }
 
@@ -62,6 +66,18 @@ func (f *fixture) metricsURL() string {
 	return fmt.Sprintf("%s/api/v1/metrics", f.Server.Address())
 }
 
+func (f *fixture) workersURL() string {
+	return fmt.Sprintf("%s/api/v1/workers", f.Server.Address())
+}
+
+func (f *fixture) reviewLatencyURL(query string) string {
+	return fmt.Sprintf("%s/api/v1/reports/review-latency?%s", f.Server.Address(), query)
+}
+
+func (f *fixture) throughputURL(query string) string {
+	return fmt.Sprintf("%s/api/v1/reports/throughput?%s", f.Server.Address(), query)
+}
+
 func (f *fixture) seedTask(title string, status task.Status) *task.Task {
 	f.t.Helper()
 	ctx := context.Background()
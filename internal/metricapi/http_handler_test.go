@@ -1,14 +1,21 @@
 package metricapi_test
 
 import (
+	"context"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/joshjon/kit/server"
 	"github.com/joshjon/kit/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/vervesh/verve/internal/metric"
+	"github.com/vervesh/verve/internal/repo"
 	"github.com/vervesh/verve/internal/task"
+	"github.com/vervesh/verve/internal/throughput"
+	"github.com/vervesh/verve/internal/workertracker"
 )
 
 func TestGetMetrics_Empty(t *testing.T) {
@@ -39,3 +46,82 @@ func TestGetMetrics_WithTasks(t *testing.T) {
 	assert.Equal(t, 1, res.Data.FailedTasks)
 	assert.Equal(t, 1, res.Data.CompletedTasks)
 }
+
+func TestListWorkers(t *testing.T) {
+	f := newFixture(t)
+
+	res := testutil.Get[server.ResponseList[workertracker.WorkerInfo]](t, f.workersURL())
+	assert.Empty(t, res.Data)
+
+	f.WorkerRegistry.Register(workertracker.Registration{WorkerID: "worker-1", Hostname: "host-a", Version: "v1.0.0", MaxConcurrentTasks: 2})
+	f.WorkerRegistry.Heartbeat("worker-1", []string{"tsk_a"})
+
+	res = testutil.Get[server.ResponseList[workertracker.WorkerInfo]](t, f.workersURL())
+	require.Len(t, res.Data, 1)
+	assert.Equal(t, "host-a", res.Data[0].Hostname)
+	assert.Equal(t, "v1.0.0", res.Data[0].Version)
+	assert.Equal(t, 2, res.Data[0].MaxConcurrentTasks)
+	assert.Equal(t, []string{"tsk_a"}, res.Data[0].ActiveTaskIDs)
+}
+
+func TestGetReviewLatency(t *testing.T) {
+	f := newFixture(t)
+
+	f.seedTask("Pending Task", task.StatusPending)
+	f.seedTask("Review Task", task.StatusReview)
+	merged := f.seedTask("Merged Task", task.StatusReview)
+	require.NoError(t, f.TaskRepo.UpdateTaskStatus(context.Background(), merged.ID, task.StatusMerged))
+
+	res := testutil.Get[server.Response[metric.ReviewLatency]](t, f.reviewLatencyURL("repo_id="+f.Repo.ID.String()))
+	assert.Equal(t, f.Repo.ID.String(), res.Data.RepoID)
+	assert.Equal(t, 1, res.Data.Completed)
+	assert.Equal(t, 1, res.Data.Open)
+	assert.Zero(t, res.Data.Overdue)
+	assert.Empty(t, res.Data.OverdueTasks)
+
+	res = testutil.Get[server.Response[metric.ReviewLatency]](t, f.reviewLatencyURL("days=7"))
+	assert.Empty(t, res.Data.RepoID)
+	assert.Equal(t, 1, res.Data.Completed)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, -7), res.Data.Since, time.Minute)
+}
+
+func TestGetReviewLatency_Invalid(t *testing.T) {
+	f := newFixture(t)
+
+	for _, query := range []string{"days=-1", "days=1000", "repo_id=nope"} {
+		httpRes, err := testutil.DefaultClient.Get(f.reviewLatencyURL(query))
+		require.NoError(t, err)
+		httpRes.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode, query)
+	}
+}
+
+func TestGetThroughput(t *testing.T) {
+	f := newFixture(t)
+
+	f.seedTask("First Task", task.StatusPending)
+	f.seedTask("Second Task", task.StatusRunning)
+
+	res := testutil.Get[server.Response[throughput.Report]](t, f.throughputURL("days=7"))
+	assert.Equal(t, 7, res.Data.Days)
+	require.Len(t, res.Data.Repos, 1)
+	assert.Equal(t, f.Repo.ID.String(), res.Data.Repos[0].RepoID)
+	assert.Equal(t, 2, res.Data.Repos[0].Created)
+	require.Len(t, res.Data.Repos[0].PerDay, 7)
+	assert.Equal(t, 2, res.Data.Repos[0].PerDay[6].Created)
+
+	res = testutil.Get[server.Response[throughput.Report]](t, f.throughputURL("repo_id="+repo.NewRepoID().String()))
+	assert.Equal(t, 30, res.Data.Days)
+	assert.Empty(t, res.Data.Repos)
+}
+
+func TestGetThroughput_Invalid(t *testing.T) {
+	f := newFixture(t)
+
+	for _, query := range []string{"days=-1", "days=1000", "repo_id=nope"} {
+		httpRes, err := testutil.DefaultClient.Get(f.throughputURL(query))
+		require.NoError(t, err)
+		httpRes.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode, query)
+	}
+}
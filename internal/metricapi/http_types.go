@@ -0,0 +1,28 @@
+package metricapi
+
+import (
+	"github.com/cohesivestack/valgo"
+
+	"github.com/vervesh/verve/internal/repo"
+)
+
+const (
+	defaultReportDays = 30  // Reporting window of the /reports endpoints
+	maxReportDays     = 365 // Longest reporting window of the /reports endpoints
+)
+
+// ReportRequest captures the optional ?repo_id= filter and ?days= reporting
+// window of the /reports endpoints. An empty repo ID reports on every repo
+// and 0 days uses the default of 30 days.
+type ReportRequest struct {
+	RepoID string `query:"repo_id" json:"-"`
+	Days   int    `query:"days" json:"-"`
+}
+
+func (r ReportRequest) Validate() error {
+	v := valgo.Is(valgo.Int(r.Days, "days").Between(0, maxReportDays))
+	if r.RepoID != "" {
+		v = v.Is(repo.RepoIDValidator(r.RepoID, "repo_id"))
+	}
+	return v.ToError()
+}
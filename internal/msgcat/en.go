@@ -0,0 +1,119 @@
+package msgcat
+
+// english is the DefaultLocale catalog. Every ID must have an entry here.
+var english = map[ID]string{
+	TaskClosedWorkerTimeout:        "Worker timeout: no heartbeat received",
+	TaskFailedMaxRuntime:           "Max runtime exceeded: the agent ran longer than the task's max runtime",
+	TaskClosedByAdmin:              "Forced by admin: %s",
+	TaskClosedNoChanges:            "No changes needed — the codebase already meets the required criteria",
+	TaskClosedEpicClosed:           "Epic closed",
+	TaskClosedEpicReplanned:        "Dropped when the epic was re-planned",
+	TaskClosedSecurityAlert:        "The agent could not remediate the security alerts introduced by its pull request",
+	TaskFailedProtectedPath:        "The agent's pull request changes protected paths: %s",
+	TaskFailedCompletionValidation: "The agent could not make its pull request pass the repository's completion validations: %s",
+
+	ErrInvalidID:                  "Must be a valid %s ID",
+	ErrTaskNotFound:               "Task not found",
+	ErrTaskConflict:               "Task conflict",
+	ErrTaskNotPending:             "task is no longer pending",
+	ErrTaskNotRunning:             "task is not running",
+	ErrTaskNotFailed:              "task is not failed",
+	ErrTaskAlreadyClosed:          "task is already closed or merged",
+	ErrTaskAttemptEnded:           "task attempt has already ended",
+	ErrTaskVersionMismatch:        "task has changed since it was read; reload it and try again",
+	ErrTaskInvalidIfMatch:         "If-Match must be a task version such as \"3\", matching expected_version if both are given",
+	ErrTaskMoveDependencies:       "task has dependencies or dependents outside the target repo; pass drop_dependencies to drop them",
+	ErrTaskNotTrashed:             "task is not in the trash",
+	ErrTaskNoPR:                   "task has no pull request or branch",
+	ErrTaskNotInReview:            "task is not in review",
+	ErrTaskNoFailedChecks:         "the task's pull request has no failed GitHub Actions runs to re-run",
+	ErrTaskNotReviewOrFailed:      "task is not in review or failed status",
+	ErrTaskSkipPRWithDraftPR:      "skip_pr and draft_pr are mutually exclusive",
+	ErrTaskAdditionalRepos:        "additional repos must be distinct and different from the task's repo",
+	ErrTaskPullRequestRepo:        "pull request repo %s is not one of the task's additional repos",
+	ErrTaskStackOnNotDependency:   "stack_on must be one of the task's dependencies",
+	ErrTaskInvalidStackParent:     "a task can only be stacked on a task in the same repo that opens a pull request",
+	ErrProvenanceReviewNotFound:   "pull request has not been scanned for provenance",
+	ErrProvenanceNotAcknowledged:  "the pull request's provenance findings must be acknowledged before it is merged",
+	ErrProposalNotFound:           "task has no proposal",
+	ErrProposalNotPending:         "task has no proposal awaiting approval",
+	ErrAttemptNotFound:            "attempt not found",
+	ErrNoteNotFound:               "note not found",
+	ErrNoteConverted:              "note has already been converted to a task",
+	ErrCommentNotFound:            "comment not found",
+	ErrCommentNotAuthor:           "only the comment's author or an admin can change it",
+	ErrAttachmentNotFound:         "attachment not found",
+	ErrAttachmentExists:           "a file with this name is already attached to the task",
+	ErrAttachmentLimit:            "a task can have at most %d attachments",
+	ErrAttachmentTooLarge:         "attachment must not be larger than %d bytes",
+	ErrAttachmentType:             "attachment must be one of %s and its contents must match its extension",
+	ErrAttachmentsNotConfigured:   "attachment storage is not configured",
+	ErrArtifactNotFound:           "artifact not found",
+	ErrArtifactExists:             "the attempt already has an artifact with this name",
+	ErrArtifactLimit:              "an attempt can record at most %d artifacts",
+	ErrArtifactTooLarge:           "artifact must not be larger than %d bytes",
+	ErrArtifactName:               "artifact name must be a relative path without hidden or parent segments",
+	ErrEpicNotFound:               "Epic not found",
+	ErrEpicConflict:               "Epic conflict",
+	ErrConversationNotFound:       "Conversation not found",
+	ErrConversationConflict:       "Conversation conflict",
+	ErrConversationNotActive:      "conversation must be active to generate tasks",
+	ErrConversationHasEpic:        "conversation already has a linked epic",
+	ErrSinkNotFound:               "Notification sink not found",
+	ErrSinkConflict:               "Notification sink conflict",
+	ErrWebhookNotFound:            "Webhook not found",
+	ErrWebhookConflict:            "Webhook conflict",
+	ErrRepoSetupIncompleteTasks:   "repository setup is not complete — finish setup before adding tasks",
+	ErrRepoSetupIncompleteEpics:   "repository setup is not complete — finish setup before adding epics",
+	ErrRepoSetupIncompleteConvos:  "repository setup is not complete — finish setup before starting conversations",
+	ErrRepoSyncDisabled:           "PR sync is disabled for this repository",
+	ErrRepoSyncUnavailable:        "PR sync is not available",
+	ErrGitHubTokenNotConfigured:   "GitHub token not configured",
+	ErrGitHubTokenManaged:         "the GitHub token is read from a secrets manager; change it there",
+	ErrEncryptionKeyNotConfigured: "encryption key not configured",
+	ErrSettingsUnavailable:        "settings not available",
+	ErrInvalidAdminToken:          "invalid admin token",
+	ErrBackupNotSupported:         "backups are only supported for file-backed SQLite",
+	ErrBackupDirNotConfigured:     "BACKUP_DIR is not configured",
+	ErrInvalidMCPToken:            "invalid MCP token",
+	ErrInvalidUserToken:           "missing or invalid API token",
+	ErrRoleRequired:               "the %s role is required",
+	ErrLastAdmin:                  "the last admin can't be removed while no admin token is configured",
+	ErrOIDCNotConfigured:          "single sign-on is not configured",
+	ErrOIDCLoginExpired:           "the sign-in expired or was started in another browser; try again",
+	ErrOIDCLoginFailed:            "single sign-on failed: %s",
+	ErrOIDCNoRole:                 "none of your groups are allowed to use Verve",
+	ErrMCPSessionNotFound:         "MCP session not found",
+	ErrRepoOutOfMCPScope:          "repo %s is not available to MCP clients",
+	ErrWorkerNotRegistered:        "worker is not registered",
+	ErrPermitNotFound:             "agent permit not found or expired",
+
+	StatusProvenanceFlagged:      "%d possible license or provenance issue(s) need acknowledgment in Verve",
+	StatusProvenanceClear:        "No license or provenance issues found",
+	StatusProvenanceAcknowledged: "Provenance findings acknowledged",
+
+	EpicPRTitle: "%s",
+	EpicPRBody:  "Merges the integration branch of Verve epic #%d, which combines the work of its %d tasks.\n\n%s",
+
+	NoteTaskDescription: "%s\n\nNoted by the agent while working on task #%d (%s).",
+
+	NotifyTaskFailedTitle:           "Task failed: %s",
+	NotifyTaskFailedMessage:         "Failed after %d attempt(s).",
+	NotifyTaskBudgetExceededTitle:   "Task budget exceeded: %s",
+	NotifyTaskBudgetExceededMessage: "Spent $%.2f of $%.2f. The task has been marked as failed.",
+	NotifyTaskNeedsReviewTitle:      "Task ready for review: %s",
+	NotifyPRMergedTitle:             "Pull request merged: %s",
+	NotifyReviewOverdueTitle:        "Review overdue: %s",
+	NotifyReviewOverdueMessage:      "In review for %g hours, past the repo's review SLA of %g hours.",
+	NotifyEpicCompletedTitle:        "Epic completed: %s",
+	NotifyEpicCompletedMessage:      "All %d tasks have finished.",
+	NotifyEpicBudgetExceededTitle:   "Epic planning budget exceeded: %s",
+	NotifyEpicBudgetExceededMessage: "Spent $%.2f of $%.2f. The planning session has been stopped.",
+	NotifyTaskCostAnomalyTitle:      "Runaway task cost: %s",
+	NotifyTaskCostAnomalyMessage:    "Spent $%.2f, far beyond the repo's usual task cost (anomaly threshold $%.2f).",
+	NotifySpendSpikeTitle:           "Spend spike across Verve",
+	NotifySpendSpikeMessage:         "$%.2f was spent in the last hour (anomaly threshold $%.2f).",
+	NotifyDispatchPausedMessage:     "Dispatching new work to agents is paused until an admin acknowledges the pause.",
+	NotifyTestTitle:                 "Verve test notification",
+	NotifyTestMessage:               "Notifications are configured correctly.",
+}
@@ -0,0 +1,214 @@
+// Package msgcat is the catalog of user-facing messages: close reasons, API
+// error messages and notification texts. Each message has a stable ID so
+// callers and tests refer to it by ID rather than by its English text, and
+// translations can be registered per locale.
+package msgcat
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ID identifies a message in the catalog.
+type ID string
+
+// DefaultLocale is the locale messages are rendered in when no other locale
+// is requested. Every ID has a DefaultLocale entry.
+const DefaultLocale = "en"
+
+// Task close and failure reasons.
+const (
+	TaskClosedWorkerTimeout        ID = "task.closed.worker_timeout"
+	TaskFailedMaxRuntime           ID = "task.failed.max_runtime"
+	TaskClosedByAdmin              ID = "task.closed.by_admin" // args: reason
+	TaskClosedNoChanges            ID = "task.closed.no_changes"
+	TaskClosedEpicClosed           ID = "task.closed.epic_closed"
+	TaskClosedEpicReplanned        ID = "task.closed.epic_replanned"
+	TaskClosedSecurityAlert        ID = "task.closed.security_alert"
+	TaskFailedProtectedPath        ID = "task.failed.protected_path"        // args: comma-separated paths
+	TaskFailedCompletionValidation ID = "task.failed.completion_validation" // args: comma-separated rules
+)
+
+// API error messages.
+const (
+	ErrInvalidID                  ID = "error.invalid_id" // args: entity name
+	ErrTaskNotFound               ID = "error.task.not_found"
+	ErrTaskConflict               ID = "error.task.conflict"
+	ErrTaskNotPending             ID = "error.task.not_pending"
+	ErrTaskNotRunning             ID = "error.task.not_running"
+	ErrTaskNotFailed              ID = "error.task.not_failed"
+	ErrTaskAlreadyClosed          ID = "error.task.already_closed"
+	ErrTaskAttemptEnded           ID = "error.task.attempt_ended"
+	ErrTaskVersionMismatch        ID = "error.task.version_mismatch"
+	ErrTaskInvalidIfMatch         ID = "error.task.invalid_if_match"
+	ErrTaskMoveDependencies       ID = "error.task.move_dependencies"
+	ErrTaskNotTrashed             ID = "error.task.not_trashed"
+	ErrTaskNoPR                   ID = "error.task.no_pr"
+	ErrTaskNotInReview            ID = "error.task.not_in_review"
+	ErrTaskNoFailedChecks         ID = "error.task.no_failed_checks"
+	ErrTaskNotReviewOrFailed      ID = "error.task.not_review_or_failed"
+	ErrTaskSkipPRWithDraftPR      ID = "error.task.skip_pr_with_draft_pr"
+	ErrTaskAdditionalRepos        ID = "error.task.additional_repos"
+	ErrTaskPullRequestRepo        ID = "error.task.pull_request_repo"
+	ErrTaskStackOnNotDependency   ID = "error.task.stack_on_not_dependency"
+	ErrTaskInvalidStackParent     ID = "error.task.invalid_stack_parent"
+	ErrProvenanceReviewNotFound   ID = "error.provenance_review.not_found"
+	ErrProvenanceNotAcknowledged  ID = "error.provenance_review.not_acknowledged"
+	ErrProposalNotFound           ID = "error.proposal.not_found"
+	ErrProposalNotPending         ID = "error.proposal.not_pending"
+	ErrAttemptNotFound            ID = "error.attempt.not_found"
+	ErrNoteNotFound               ID = "error.note.not_found"
+	ErrNoteConverted              ID = "error.note.converted"
+	ErrCommentNotFound            ID = "error.comment.not_found"
+	ErrCommentNotAuthor           ID = "error.comment.not_author"
+	ErrAttachmentNotFound         ID = "error.attachment.not_found"
+	ErrAttachmentExists           ID = "error.attachment.exists"
+	ErrAttachmentLimit            ID = "error.attachment.limit"     // args: max attachments
+	ErrAttachmentTooLarge         ID = "error.attachment.too_large" // args: max bytes
+	ErrAttachmentType             ID = "error.attachment.type"      // args: comma-separated extensions
+	ErrAttachmentsNotConfigured   ID = "error.attachments.not_configured"
+	ErrArtifactNotFound           ID = "error.artifact.not_found"
+	ErrArtifactExists             ID = "error.artifact.exists"
+	ErrArtifactLimit              ID = "error.artifact.limit"     // args: max artifacts
+	ErrArtifactTooLarge           ID = "error.artifact.too_large" // args: max bytes
+	ErrArtifactName               ID = "error.artifact.name"
+	ErrEpicNotFound               ID = "error.epic.not_found"
+	ErrEpicConflict               ID = "error.epic.conflict"
+	ErrConversationNotFound       ID = "error.conversation.not_found"
+	ErrConversationConflict       ID = "error.conversation.conflict"
+	ErrConversationNotActive      ID = "error.conversation.not_active"
+	ErrConversationHasEpic        ID = "error.conversation.has_epic"
+	ErrSinkNotFound               ID = "error.sink.not_found"
+	ErrSinkConflict               ID = "error.sink.conflict"
+	ErrWebhookNotFound            ID = "error.webhook.not_found"
+	ErrWebhookConflict            ID = "error.webhook.conflict"
+	ErrRepoSetupIncompleteTasks   ID = "error.repo.setup_incomplete.tasks"
+	ErrRepoSetupIncompleteEpics   ID = "error.repo.setup_incomplete.epics"
+	ErrRepoSetupIncompleteConvos  ID = "error.repo.setup_incomplete.conversations"
+	ErrRepoSyncDisabled           ID = "error.repo.sync_disabled"
+	ErrRepoSyncUnavailable        ID = "error.repo.sync_unavailable"
+	ErrGitHubTokenNotConfigured   ID = "error.github_token.not_configured"
+	ErrGitHubTokenManaged         ID = "error.github_token.managed"
+	ErrEncryptionKeyNotConfigured ID = "error.encryption_key.not_configured"
+	ErrSettingsUnavailable        ID = "error.settings.unavailable"
+	ErrInvalidAdminToken          ID = "error.admin_token.invalid"
+	ErrBackupNotSupported         ID = "error.backup.not_supported"
+	ErrBackupDirNotConfigured     ID = "error.backup.dir_not_configured"
+	ErrInvalidMCPToken            ID = "error.mcp_token.invalid"
+	ErrInvalidUserToken           ID = "error.user_token.invalid"
+	ErrRoleRequired               ID = "error.role.required" // args: role
+	ErrLastAdmin                  ID = "error.user.last_admin"
+	ErrOIDCNotConfigured          ID = "error.oidc.not_configured"
+	ErrOIDCLoginExpired           ID = "error.oidc.login_expired"
+	ErrOIDCLoginFailed            ID = "error.oidc.login_failed" // args: reason
+	ErrOIDCNoRole                 ID = "error.oidc.no_role"
+	ErrMCPSessionNotFound         ID = "error.mcp_session.not_found"
+	ErrRepoOutOfMCPScope          ID = "error.repo.out_of_mcp_scope" // args: repo full name
+	ErrWorkerNotRegistered        ID = "error.worker.not_registered"
+	ErrPermitNotFound             ID = "error.permit.not_found"
+)
+
+// GitHub commit status descriptions.
+const (
+	StatusProvenanceFlagged      ID = "status.provenance.flagged" // args: finding count
+	StatusProvenanceClear        ID = "status.provenance.clear"
+	StatusProvenanceAcknowledged ID = "status.provenance.acknowledged"
+)
+
+// Pull request text written by the server.
+const (
+	EpicPRTitle ID = "pr.epic.title" // args: epic title
+	EpicPRBody  ID = "pr.epic.body"  // args: epic number, task count, epic description
+)
+
+// Task text written by the server.
+const (
+	NoteTaskDescription ID = "task.note.description" // args: note text, source task number, source task title
+)
+
+// Notification titles and messages.
+const (
+	NotifyTaskFailedTitle           ID = "notify.task_failed.title"            // args: task title
+	NotifyTaskFailedMessage         ID = "notify.task_failed.message"          // args: attempts
+	NotifyTaskBudgetExceededTitle   ID = "notify.task_budget_exceeded.title"   // args: task title
+	NotifyTaskBudgetExceededMessage ID = "notify.task_budget_exceeded.message" // args: cost, budget
+	NotifyTaskNeedsReviewTitle      ID = "notify.task_needs_review.title"      // args: task title
+	NotifyPRMergedTitle             ID = "notify.pr_merged.title"              // args: task title
+	NotifyReviewOverdueTitle        ID = "notify.review_overdue.title"         // args: task title
+	NotifyReviewOverdueMessage      ID = "notify.review_overdue.message"       // args: hours in review, review SLA hours
+	NotifyEpicCompletedTitle        ID = "notify.epic_completed.title"         // args: epic title
+	NotifyEpicCompletedMessage      ID = "notify.epic_completed.message"       // args: task count
+	NotifyEpicBudgetExceededTitle   ID = "notify.epic_budget_exceeded.title"   // args: epic title
+	NotifyEpicBudgetExceededMessage ID = "notify.epic_budget_exceeded.message" // args: cost, budget
+	NotifyTaskCostAnomalyTitle      ID = "notify.task_cost_anomaly.title"      // args: task title
+	NotifyTaskCostAnomalyMessage    ID = "notify.task_cost_anomaly.message"    // args: cost, threshold
+	NotifySpendSpikeTitle           ID = "notify.spend_spike.title"
+	NotifySpendSpikeMessage         ID = "notify.spend_spike.message" // args: hourly spend, threshold
+	NotifyDispatchPausedMessage     ID = "notify.dispatch_paused.message"
+	NotifyTestTitle                 ID = "notify.test.title"
+	NotifyTestMessage               ID = "notify.test.message"
+)
+
+var (
+	mu       sync.RWMutex
+	catalogs = map[string]map[ID]string{DefaultLocale: english}
+)
+
+// Register adds or replaces translations for a locale. Messages missing from
+// the locale fall back to DefaultLocale.
+func Register(locale string, messages map[ID]string) {
+	mu.Lock()
+	defer mu.Unlock()
+	locale = normalizeLocale(locale)
+	catalog := catalogs[locale]
+	if catalog == nil {
+		catalog = make(map[ID]string, len(messages))
+		catalogs[locale] = catalog
+	}
+	for id, text := range messages {
+		catalog[id] = text
+	}
+}
+
+// Text renders a message in DefaultLocale.
+func Text(id ID, args ...any) string {
+	return Localize(DefaultLocale, id, args...)
+}
+
+// Localize renders a message in the given locale, formatting args into it
+// like fmt.Sprintf. A regional locale such as "pt-BR" falls back to its
+// language ("pt") and then to DefaultLocale. An unknown ID renders as the ID
+// itself so a missing entry is visible rather than blank.
+func Localize(locale string, id ID, args ...any) string {
+	format, ok := lookup(locale, id)
+	if !ok {
+		return string(id)
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+func lookup(locale string, id ID) (string, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	locale = normalizeLocale(locale)
+	for {
+		if text, ok := catalogs[locale][id]; ok {
+			return text, true
+		}
+		i := strings.LastIndex(locale, "-")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	text, ok := catalogs[DefaultLocale][id]
+	return text, ok
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
@@ -0,0 +1,39 @@
+package msgcat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestText(t *testing.T) {
+	assert.Equal(t, "Task not found", Text(ErrTaskNotFound))
+	assert.Equal(t, "Forced by admin: worker vanished", Text(TaskClosedByAdmin, "worker vanished"))
+	assert.Equal(t, "Spent $1.20 of $1.00. The task has been marked as failed.", Text(NotifyTaskBudgetExceededMessage, 1.2, 1.0))
+}
+
+func TestText_UnknownID(t *testing.T) {
+	assert.Equal(t, "does.not.exist", Text("does.not.exist"))
+}
+
+func TestLocalize(t *testing.T) {
+	Register("pt", map[ID]string{ErrTaskNotFound: "Tarefa não encontrada"})
+	Register("pt-BR", map[ID]string{TaskClosedEpicClosed: "Épico fechado"})
+	t.Cleanup(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		delete(catalogs, "pt")
+		delete(catalogs, "pt-br")
+	})
+
+	assert.Equal(t, "Épico fechado", Localize("pt_BR", TaskClosedEpicClosed))
+	assert.Equal(t, "Tarefa não encontrada", Localize("pt-BR", ErrTaskNotFound), "falls back to the language")
+	assert.Equal(t, "Epic not found", Localize("pt-BR", ErrEpicNotFound), "falls back to the default locale")
+	assert.Equal(t, "Task not found", Localize("fr", ErrTaskNotFound))
+}
+
+func TestEnglishCatalogComplete(t *testing.T) {
+	for id, text := range english {
+		assert.NotEmpty(t, text, id)
+	}
+}
@@ -0,0 +1,44 @@
+package notification
+
+import (
+	"github.com/cohesivestack/valgo"
+	"github.com/joshjon/kit/id"
+	"go.jetify.com/typeid"
+
+	"github.com/vervesh/verve/internal/msgcat"
+)
+
+type sinkPrefix struct{}
+
+func (sinkPrefix) Prefix() string { return "snk" }
+
+// SinkID is the unique identifier for a notification Sink.
+type SinkID struct {
+	typeid.TypeID[sinkPrefix]
+}
+
+// NewSinkID generates a new unique SinkID.
+func NewSinkID() SinkID {
+	return id.New[SinkID]()
+}
+
+// ParseSinkID parses a string into a SinkID.
+func ParseSinkID(s string) (SinkID, error) {
+	return id.Parse[SinkID](s)
+}
+
+// MustParseSinkID parses a string into a SinkID, panicking on failure.
+func MustParseSinkID(s string) SinkID {
+	return id.MustParse[SinkID](s)
+}
+
+// SinkIDValidator returns a valgo Validator that checks whether the given
+// string is a valid SinkID.
+func SinkIDValidator(identifier string, nameAndTitle ...string) *valgo.ValidatorString[string] {
+	return valgo.String(identifier, nameAndTitle...).
+		Not().Blank().
+		Passing(func(_ string) bool {
+			_, err := ParseSinkID(identifier)
+			return err == nil
+		}, msgcat.Text(msgcat.ErrInvalidID, "sink"))
+}
@@ -0,0 +1,122 @@
+package notification
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Kind identifies the type of destination a notification Sink delivers to.
+type Kind string
+
+const (
+	KindSlack   Kind = "slack"   // Slack incoming webhook
+	KindDiscord Kind = "discord" // Discord channel webhook
+	KindWebhook Kind = "webhook" // Generic JSON webhook
+)
+
+// EventType identifies an event that can trigger a notification.
+type EventType string
+
+const (
+	EventTaskFailed      EventType = "task_failed"       // Task failed and needs attention
+	EventTaskNeedsReview EventType = "task_needs_review" // Task finished and is awaiting review
+	EventPRMerged        EventType = "pr_merged"         // Task's pull request was merged
+	EventBudgetExceeded  EventType = "budget_exceeded"   // Task or epic planning cost reached its budget
+	EventEpicCompleted   EventType = "epic_completed"    // All tasks in an epic finished
+	EventCostAnomaly     EventType = "cost_anomaly"      // Runaway task cost or instance-wide spend spike
+	EventReviewOverdue   EventType = "review_overdue"    // Task has been in review longer than the repo's review SLA
+)
+
+// AllEventTypes lists every event type a sink can subscribe to.
+var AllEventTypes = []EventType{
+	EventTaskFailed,
+	EventTaskNeedsReview,
+	EventPRMerged,
+	EventBudgetExceeded,
+	EventEpicCompleted,
+	EventCostAnomaly,
+	EventReviewOverdue,
+}
+
+// ValidKind returns true if the given kind is a supported sink kind.
+func ValidKind(k Kind) bool {
+	switch k {
+	case KindSlack, KindDiscord, KindWebhook:
+		return true
+	}
+	return false
+}
+
+// ValidEventType returns true if the given event type is supported.
+func ValidEventType(e EventType) bool {
+	return slices.Contains(AllEventTypes, e)
+}
+
+// Sink is a notification destination of a repo or of a team. A team's
+// sinks receive the events of every repo in the team.
+type Sink struct {
+	ID        SinkID      `json:"id"`
+	RepoID    string      `json:"repo_id,omitempty"`
+	TeamID    string      `json:"team_id,omitempty"`
+	Kind      Kind        `json:"kind"`
+	URL       string      `json:"-"` // Webhook URLs embed credentials and are never returned by the API
+	Events    []EventType `json:"events"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// NewSink creates a new Sink. An empty events list subscribes the sink to all
+// event types.
+func NewSink(repoID string, kind Kind, url string, events []EventType) *Sink {
+	if events == nil {
+		events = []EventType{}
+	}
+	return &Sink{
+		ID:        NewSinkID(),
+		RepoID:    repoID,
+		Kind:      kind,
+		URL:       url,
+		Events:    events,
+		CreatedAt: time.Now(),
+	}
+}
+
+// NewTeamSink creates a new Sink for a team. An empty events list subscribes
+// the sink to all event types.
+func NewTeamSink(teamID string, kind Kind, url string, events []EventType) *Sink {
+	s := NewSink("", kind, url, events)
+	s.TeamID = teamID
+	return s
+}
+
+// Subscribed returns true if the sink should receive the given event type.
+func (s *Sink) Subscribed(e EventType) bool {
+	return len(s.Events) == 0 || slices.Contains(s.Events, e)
+}
+
+// MaskedURL returns the sink URL with its path hidden, since webhook URLs
+// embed their secret token in the path (e.g. https://hooks.slack.com/•••).
+func (s *Sink) MaskedURL() string {
+	u, err := url.Parse(s.URL)
+	if err != nil || u.Host == "" {
+		return "•••"
+	}
+	if strings.Trim(u.Path, "/") == "" && u.RawQuery == "" {
+		return u.Scheme + "://" + u.Host
+	}
+	return u.Scheme + "://" + u.Host + "/•••"
+}
+
+// Notification is a single message delivered to subscribed sinks.
+type Notification struct {
+	Event        EventType `json:"event"`
+	RepoID       string    `json:"repo_id"`
+	RepoFullName string    `json:"repo_full_name,omitempty"`
+	Title        string    `json:"title"`
+	Message      string    `json:"message,omitempty"`
+	URL          string    `json:"url,omitempty"`
+	TaskID       string    `json:"task_id,omitempty"`
+	EpicID       string    `json:"epic_id,omitempty"`
+	Timestamp    time.Time `json:"timestamp"`
+}
@@ -0,0 +1,101 @@
+package notification
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSink_Subscribed(t *testing.T) {
+	all := NewSink("repo_1", KindSlack, "https://hooks.slack.com/services/T/B/X", nil)
+	for _, e := range AllEventTypes {
+		assert.True(t, all.Subscribed(e), "empty events should subscribe to %s", e)
+	}
+
+	some := NewSink("repo_1", KindSlack, "https://hooks.slack.com/services/T/B/X", []EventType{EventTaskFailed, EventPRMerged})
+	assert.True(t, some.Subscribed(EventTaskFailed))
+	assert.True(t, some.Subscribed(EventPRMerged))
+	assert.False(t, some.Subscribed(EventEpicCompleted))
+}
+
+func TestSink_MaskedURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://hooks.slack.com/services/T000/B000/XXXX", "https://hooks.slack.com/•••"},
+		{"https://discord.com/api/webhooks/123/abc", "https://discord.com/•••"},
+		{"https://example.com/?token=secret", "https://example.com/•••"},
+		{"http://localhost:9000", "http://localhost:9000"},
+		{"not a url", "•••"},
+	}
+	for _, tt := range tests {
+		s := &Sink{URL: tt.url}
+		assert.Equal(t, tt.want, s.MaskedURL(), tt.url)
+	}
+}
+
+func TestValidKindAndEventType(t *testing.T) {
+	assert.True(t, ValidKind(KindSlack))
+	assert.True(t, ValidKind(KindDiscord))
+	assert.True(t, ValidKind(KindWebhook))
+	assert.False(t, ValidKind("email"))
+
+	assert.True(t, ValidEventType(EventBudgetExceeded))
+	assert.False(t, ValidEventType("task_created"))
+}
+
+func TestPayload(t *testing.T) {
+	n := Notification{
+		Event:        EventTaskNeedsReview,
+		RepoID:       "repo_1",
+		RepoFullName: "owner/repo",
+		Title:        "Task ready for review: Add login",
+		Message:      "Looks good",
+		URL:          "https://github.com/owner/repo/pull/7",
+		Timestamp:    time.Unix(1700000000, 0).UTC(),
+	}
+
+	t.Run("slack", func(t *testing.T) {
+		body, err := payload(KindSlack, n)
+		require.NoError(t, err)
+		var got map[string]string
+		require.NoError(t, json.Unmarshal(body, &got))
+		assert.Equal(t, "*Task ready for review: Add login* (owner/repo)\nLooks good\n<https://github.com/owner/repo/pull/7|View pull request>", got["text"])
+	})
+
+	t.Run("discord", func(t *testing.T) {
+		body, err := payload(KindDiscord, n)
+		require.NoError(t, err)
+		var got map[string]string
+		require.NoError(t, json.Unmarshal(body, &got))
+		assert.Equal(t, "**Task ready for review: Add login** (owner/repo)\nLooks good\nhttps://github.com/owner/repo/pull/7", got["content"])
+	})
+
+	t.Run("discord truncates long content", func(t *testing.T) {
+		long := n
+		long.Message = strings.Repeat("x", 3000)
+		body, err := payload(KindDiscord, long)
+		require.NoError(t, err)
+		var got map[string]string
+		require.NoError(t, json.Unmarshal(body, &got))
+		assert.Len(t, got["content"], discordMaxContentLen)
+	})
+
+	t.Run("webhook", func(t *testing.T) {
+		body, err := payload(KindWebhook, n)
+		require.NoError(t, err)
+		var got Notification
+		require.NoError(t, json.Unmarshal(body, &got))
+		assert.Equal(t, n, got)
+	})
+
+	t.Run("unknown kind", func(t *testing.T) {
+		_, err := payload("email", n)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,12 @@
+package notification
+
+import "context"
+
+// Repository is the interface for performing CRUD operations on notification Sinks.
+type Repository interface {
+	CreateSink(ctx context.Context, sink *Sink) error
+	ReadSink(ctx context.Context, id SinkID) (*Sink, error)
+	ListSinksByRepo(ctx context.Context, repoID string) ([]*Sink, error)
+	ListSinksByTeam(ctx context.Context, teamID string) ([]*Sink, error)
+	DeleteSink(ctx context.Context, id SinkID) error
+}
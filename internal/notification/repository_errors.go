@@ -0,0 +1,25 @@
+package notification
+
+import (
+	"github.com/joshjon/kit/errtag"
+
+	"github.com/vervesh/verve/internal/msgcat"
+)
+
+// ErrTagSinkNotFound indicates a notification sink was not found.
+type ErrTagSinkNotFound struct{ errtag.NotFound }
+
+func (ErrTagSinkNotFound) Msg() string { return msgcat.Text(msgcat.ErrSinkNotFound) }
+
+func (e ErrTagSinkNotFound) Unwrap() error {
+	return errtag.Tag[errtag.NotFound](e.Cause())
+}
+
+// ErrTagSinkConflict indicates a notification sink conflict (e.g. duplicate ID).
+type ErrTagSinkConflict struct{ errtag.Conflict }
+
+func (ErrTagSinkConflict) Msg() string { return msgcat.Text(msgcat.ErrSinkConflict) }
+
+func (e ErrTagSinkConflict) Unwrap() error {
+	return errtag.Tag[errtag.Conflict](e.Cause())
+}
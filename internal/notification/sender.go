@@ -0,0 +1,107 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// discordMaxContentLen is Discord's message content length limit.
+const discordMaxContentLen = 2000
+
+// Sender delivers notifications to sink URLs over HTTP.
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender creates a new Sender. A nil client uses a default client with a
+// 10 second timeout.
+func NewSender(client *http.Client) *Sender {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Sender{client: client}
+}
+
+// Send formats the notification for the sink's kind and posts it to the sink
+// URL. Any non-2xx response is returned as an error.
+func (s *Sender) Send(ctx context.Context, sink *Sink, n Notification) error {
+	body, err := payload(sink.Kind, n)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sink.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "verve")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send %s notification: %w", sink.Kind, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("send %s notification: unexpected status %d: %s", sink.Kind, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
+
+// payload builds the JSON request body for the given sink kind.
+func payload(kind Kind, n Notification) ([]byte, error) {
+	switch kind {
+	case KindSlack:
+		return json.Marshal(map[string]string{"text": slackText(n)})
+	case KindDiscord:
+		return json.Marshal(map[string]string{"content": discordContent(n)})
+	case KindWebhook:
+		return json.Marshal(n)
+	default:
+		return nil, fmt.Errorf("unsupported sink kind %q", kind)
+	}
+}
+
+// slackText formats a notification using Slack mrkdwn.
+func slackText(n Notification) string {
+	var b strings.Builder
+	b.WriteString("*" + n.Title + "*")
+	if n.RepoFullName != "" {
+		b.WriteString(" (" + n.RepoFullName + ")")
+	}
+	if n.Message != "" {
+		b.WriteString("\n" + n.Message)
+	}
+	if n.URL != "" {
+		b.WriteString("\n<" + n.URL + "|View pull request>")
+	}
+	return b.String()
+}
+
+// discordContent formats a notification using Discord markdown.
+func discordContent(n Notification) string {
+	var b strings.Builder
+	b.WriteString("**" + n.Title + "**")
+	if n.RepoFullName != "" {
+		b.WriteString(" (" + n.RepoFullName + ")")
+	}
+	if n.Message != "" {
+		b.WriteString("\n" + n.Message)
+	}
+	if n.URL != "" {
+		b.WriteString("\n" + n.URL)
+	}
+	content := b.String()
+	if len(content) > discordMaxContentLen {
+		content = content[:discordMaxContentLen-3] + "..."
+	}
+	return content
+}
@@ -0,0 +1,268 @@
+package notification
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/joshjon/kit/log"
+
+	"github.com/vervesh/verve/internal/costguard"
+	"github.com/vervesh/verve/internal/epic"
+	"github.com/vervesh/verve/internal/logkey"
+	"github.com/vervesh/verve/internal/msgcat"
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/task"
+)
+
+// RepoReader reads repos so notifications can include the repo name and
+// reach the sinks of the repo's team.
+type RepoReader interface {
+	ReadRepo(ctx context.Context, id repo.RepoID) (*repo.Repo, error)
+}
+
+// Service manages repo and team notification sinks and delivers notifications for
+// task and epic lifecycle events and cost anomalies. It implements
+// task.StatusListener, epic.EventListener and costguard.Alerter.
+type Service struct {
+	repo       Repository
+	repoReader RepoReader
+	sender     *Sender
+	logger     log.Logger
+
+	// Tracks in-flight deliveries so callers can wait for them to finish.
+	wg sync.WaitGroup
+}
+
+var (
+	_ task.StatusListener = (*Service)(nil)
+	_ epic.EventListener  = (*Service)(nil)
+)
+
+// NewService creates a new notification Service.
+func NewService(repo Repository, repoReader RepoReader, sender *Sender, logger log.Logger) *Service {
+	return &Service{
+		repo:       repo,
+		repoReader: repoReader,
+		sender:     sender,
+		logger:     logger.With("component", "notification_service"),
+	}
+}
+
+// CreateSink creates a new notification sink.
+func (s *Service) CreateSink(ctx context.Context, sink *Sink) error {
+	return s.repo.CreateSink(ctx, sink)
+}
+
+// ReadSink reads a notification sink by ID.
+func (s *Service) ReadSink(ctx context.Context, id SinkID) (*Sink, error) {
+	return s.repo.ReadSink(ctx, id)
+}
+
+// ListSinksByRepo returns all notification sinks configured for a repo.
+func (s *Service) ListSinksByRepo(ctx context.Context, repoID string) ([]*Sink, error) {
+	return s.repo.ListSinksByRepo(ctx, repoID)
+}
+
+// ListSinksByTeam returns all notification sinks configured for a team.
+func (s *Service) ListSinksByTeam(ctx context.Context, teamID string) ([]*Sink, error) {
+	return s.repo.ListSinksByTeam(ctx, teamID)
+}
+
+// DeleteSink deletes a notification sink.
+func (s *Service) DeleteSink(ctx context.Context, id SinkID) error {
+	return s.repo.DeleteSink(ctx, id)
+}
+
+// TestSink synchronously sends a test notification to the sink so the caller
+// can verify the URL is reachable and accepted.
+func (s *Service) TestSink(ctx context.Context, sink *Sink) error {
+	n := Notification{
+		RepoID:    sink.RepoID,
+		Title:     msgcat.Text(msgcat.NotifyTestTitle),
+		Message:   msgcat.Text(msgcat.NotifyTestMessage),
+		Timestamp: time.Now(),
+	}
+	if r := s.readRepo(ctx, sink.RepoID); r != nil {
+		n.RepoFullName = r.FullName
+	}
+	return s.sender.Send(ctx, sink, n)
+}
+
+// Notify delivers the notification to every sink of the repo, and of the
+// repo's team, subscribed to the notification's event type. Delivery is
+// asynchronous; failures are logged and never returned to the caller.
+func (s *Service) Notify(ctx context.Context, n Notification) {
+	sinks, err := s.repo.ListSinksByRepo(ctx, n.RepoID)
+	if err != nil {
+		s.logger.Error("failed to list notification sinks", logkey.RepoID, n.RepoID, "error", err)
+		return
+	}
+	r := s.readRepo(ctx, n.RepoID)
+	if r != nil && r.TeamID != "" {
+		teamSinks, err := s.repo.ListSinksByTeam(ctx, r.TeamID)
+		if err != nil {
+			s.logger.Error("failed to list team notification sinks", logkey.RepoID, n.RepoID, "error", err)
+			return
+		}
+		sinks = append(sinks, teamSinks...)
+	}
+
+	var targets []*Sink
+	for _, sink := range sinks {
+		if sink.Subscribed(n.Event) {
+			targets = append(targets, sink)
+		}
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	if n.RepoFullName == "" && r != nil {
+		n.RepoFullName = r.FullName
+	}
+	if n.Timestamp.IsZero() {
+		n.Timestamp = time.Now()
+	}
+
+	// Deliveries outlive the triggering request.
+	ctx = context.WithoutCancel(ctx)
+	for _, sink := range targets {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			if err := s.sender.Send(ctx, sink, n); err != nil {
+				s.logger.Warn("notification delivery failed",
+					logkey.SinkID, sink.ID.String(),
+					"notification.kind", sink.Kind,
+					"notification.event", n.Event,
+					"error", err,
+				)
+			}
+		}()
+	}
+}
+
+// Wait blocks until all in-flight deliveries have finished.
+func (s *Service) Wait() {
+	s.wg.Wait()
+}
+
+// TaskStatusChanged notifies sinks when a task fails, is ready for review or
+// has its pull request merged. A task failed because its cost reached its
+// budget is reported as EventBudgetExceeded.
+func (s *Service) TaskStatusChanged(ctx context.Context, t *task.Task) {
+	n := Notification{
+		RepoID: t.RepoID,
+		URL:    t.PullRequestURL,
+		TaskID: t.ID.String(),
+	}
+	switch t.Status {
+	case task.StatusFailed:
+		if t.MaxCostUSD > 0 && t.CostUSD >= t.MaxCostUSD {
+			n.Event = EventBudgetExceeded
+			n.Title = msgcat.Text(msgcat.NotifyTaskBudgetExceededTitle, t.Title)
+			n.Message = msgcat.Text(msgcat.NotifyTaskBudgetExceededMessage, t.CostUSD, t.MaxCostUSD)
+			break
+		}
+		n.Event = EventTaskFailed
+		n.Title = msgcat.Text(msgcat.NotifyTaskFailedTitle, t.Title)
+		n.Message = t.CloseReason
+		if n.Message == "" {
+			n.Message = msgcat.Text(msgcat.NotifyTaskFailedMessage, t.Attempt)
+		}
+	case task.StatusReview:
+		n.Event = EventTaskNeedsReview
+		n.Title = msgcat.Text(msgcat.NotifyTaskNeedsReviewTitle, t.Title)
+	case task.StatusMerged:
+		n.Event = EventPRMerged
+		n.Title = msgcat.Text(msgcat.NotifyPRMergedTitle, t.Title)
+	default:
+		return
+	}
+	s.Notify(ctx, n)
+}
+
+// EpicCompleted notifies sinks that all tasks in an epic have finished.
+func (s *Service) EpicCompleted(ctx context.Context, e *epic.Epic) {
+	s.Notify(ctx, Notification{
+		Event:   EventEpicCompleted,
+		RepoID:  e.RepoID,
+		Title:   msgcat.Text(msgcat.NotifyEpicCompletedTitle, e.Title),
+		Message: msgcat.Text(msgcat.NotifyEpicCompletedMessage, len(e.TaskIDs)),
+		EpicID:  e.ID.String(),
+	})
+}
+
+// EpicBudgetExceeded notifies sinks that an epic's planning session was
+// stopped because its cost reached the planning budget.
+func (s *Service) EpicBudgetExceeded(ctx context.Context, e *epic.Epic) {
+	s.Notify(ctx, Notification{
+		Event:   EventBudgetExceeded,
+		RepoID:  e.RepoID,
+		Title:   msgcat.Text(msgcat.NotifyEpicBudgetExceededTitle, e.Title),
+		Message: msgcat.Text(msgcat.NotifyEpicBudgetExceededMessage, e.CostUSD, e.MaxCostUSD),
+		EpicID:  e.ID.String(),
+	})
+}
+
+// CostAnomaly notifies sinks of a runaway spend pattern detected by the cost
+// guard. Task cost anomalies go to the task's repo; hourly spend spikes go to
+// every repo that spent during the hour.
+func (s *Service) CostAnomaly(ctx context.Context, a costguard.Anomaly, paused bool) {
+	n := Notification{Event: EventCostAnomaly, Timestamp: a.DetectedAt}
+	repoIDs := a.RepoIDs
+	if a.Kind == costguard.KindTaskCost {
+		n.Title = msgcat.Text(msgcat.NotifyTaskCostAnomalyTitle, a.TaskTitle)
+		n.Message = msgcat.Text(msgcat.NotifyTaskCostAnomalyMessage, a.CostUSD, a.ThresholdUSD)
+		n.TaskID = a.TaskID
+		repoIDs = []string{a.RepoID}
+	} else {
+		n.Title = msgcat.Text(msgcat.NotifySpendSpikeTitle)
+		n.Message = msgcat.Text(msgcat.NotifySpendSpikeMessage, a.CostUSD, a.ThresholdUSD)
+	}
+	if paused {
+		n.Message += " " + msgcat.Text(msgcat.NotifyDispatchPausedMessage)
+	}
+	for _, repoID := range repoIDs {
+		n.RepoID = repoID
+		n.RepoFullName = ""
+		s.Notify(ctx, n)
+	}
+}
+
+// ReviewOverdue notifies sinks that a task has been in review for longer
+// than its repo's review SLA.
+func (s *Service) ReviewOverdue(ctx context.Context, o *task.OverdueReview) {
+	s.Notify(ctx, Notification{
+		Event:   EventReviewOverdue,
+		RepoID:  o.Task.RepoID,
+		Title:   msgcat.Text(msgcat.NotifyReviewOverdueTitle, o.Task.Title),
+		Message: msgcat.Text(msgcat.NotifyReviewOverdueMessage, hours(time.Since(o.StartedAt)), hours(o.SLA)),
+		URL:     o.Task.PullRequestURL,
+		TaskID:  o.Task.ID.String(),
+	})
+}
+
+// hours returns d in hours, rounded to one decimal place.
+func hours(d time.Duration) float64 {
+	return math.Round(d.Hours()*10) / 10
+}
+
+// readRepo reads the repo with the given ID, or returns nil when it can't be
+// read.
+func (s *Service) readRepo(ctx context.Context, repoID string) *repo.Repo {
+	if s.repoReader == nil || repoID == "" {
+		return nil
+	}
+	id, err := repo.ParseRepoID(repoID)
+	if err != nil {
+		return nil
+	}
+	r, err := s.repoReader.ReadRepo(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return r
+}
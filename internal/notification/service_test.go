@@ -0,0 +1,318 @@
+package notification_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joshjon/kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/costguard"
+	"github.com/vervesh/verve/internal/epic"
+	"github.com/vervesh/verve/internal/msgcat"
+	"github.com/vervesh/verve/internal/notification"
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/sqlite"
+	"github.com/vervesh/verve/internal/task"
+	"github.com/vervesh/verve/internal/team"
+)
+
+// recordingServer captures JSON bodies posted to it.
+type recordingServer struct {
+	*httptest.Server
+	mu     sync.Mutex
+	bodies []map[string]any
+	status int
+}
+
+func newRecordingServer(t *testing.T) *recordingServer {
+	t.Helper()
+	rs := &recordingServer{status: http.StatusOK}
+	rs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		rs.mu.Lock()
+		rs.bodies = append(rs.bodies, body)
+		status := rs.status
+		rs.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(rs.Close)
+	return rs
+}
+
+func (rs *recordingServer) received() []map[string]any {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return append([]map[string]any(nil), rs.bodies...)
+}
+
+type serviceFixture struct {
+	service *notification.Service
+	repoID  string
+}
+
+func newServiceFixture(t *testing.T) *serviceFixture {
+	t.Helper()
+	db := sqlite.NewTestDB(t)
+	repoStore := repo.NewStore(sqlite.NewRepoRepository(db))
+	r, err := repo.NewRepo("owner/test-repo")
+	require.NoError(t, err)
+	require.NoError(t, repoStore.CreateRepo(context.Background(), r))
+
+	service := notification.NewService(
+		sqlite.NewNotificationSinkRepository(db),
+		repoStore,
+		notification.NewSender(nil),
+		log.NewLogger(log.WithNop()),
+	)
+	return &serviceFixture{service: service, repoID: r.ID.String()}
+}
+
+func (f *serviceFixture) addSink(t *testing.T, kind notification.Kind, url string, events ...notification.EventType) *notification.Sink {
+	t.Helper()
+	sink := notification.NewSink(f.repoID, kind, url, events)
+	require.NoError(t, f.service.CreateSink(context.Background(), sink))
+	return sink
+}
+
+func TestService_SinkCRUD(t *testing.T) {
+	f := newServiceFixture(t)
+	ctx := context.Background()
+
+	sink := f.addSink(t, notification.KindDiscord, "https://discord.com/api/webhooks/1/abc", notification.EventTaskFailed)
+
+	read, err := f.service.ReadSink(ctx, sink.ID)
+	require.NoError(t, err)
+	assert.Equal(t, notification.KindDiscord, read.Kind)
+	assert.Equal(t, "https://discord.com/api/webhooks/1/abc", read.URL)
+	assert.Equal(t, []notification.EventType{notification.EventTaskFailed}, read.Events)
+
+	sinks, err := f.service.ListSinksByRepo(ctx, f.repoID)
+	require.NoError(t, err)
+	require.Len(t, sinks, 1)
+
+	require.NoError(t, f.service.DeleteSink(ctx, sink.ID))
+	_, err = f.service.ReadSink(ctx, sink.ID)
+	assert.Error(t, err)
+}
+
+func TestService_Notify_OnlySubscribedSinks(t *testing.T) {
+	f := newServiceFixture(t)
+	failures := newRecordingServer(t)
+	everything := newRecordingServer(t)
+	f.addSink(t, notification.KindSlack, failures.URL, notification.EventTaskFailed)
+	f.addSink(t, notification.KindWebhook, everything.URL)
+
+	f.service.Notify(context.Background(), notification.Notification{
+		Event:  notification.EventPRMerged,
+		RepoID: f.repoID,
+		Title:  "Pull request merged: Add login",
+	})
+	f.service.Wait()
+
+	assert.Empty(t, failures.received(), "sink not subscribed to pr_merged")
+	got := everything.received()
+	require.Len(t, got, 1)
+	assert.Equal(t, "pr_merged", got[0]["event"])
+	assert.Equal(t, "owner/test-repo", got[0]["repo_full_name"])
+	assert.NotEmpty(t, got[0]["timestamp"])
+}
+
+func TestService_Notify_TeamSinks(t *testing.T) {
+	db := sqlite.NewTestDB(t)
+	ctx := context.Background()
+	tm := team.NewTeam("Platform")
+	require.NoError(t, sqlite.NewTeamRepository(db).CreateTeam(ctx, tm))
+
+	repos := sqlite.NewRepoRepository(db)
+	inTeam, err := repo.NewRepo("owner/in-team")
+	require.NoError(t, err)
+	require.NoError(t, repos.CreateRepo(ctx, inTeam))
+	require.NoError(t, repos.UpdateRepoTeam(ctx, inTeam.ID, tm.ID.String()))
+	other, err := repo.NewRepo("owner/other")
+	require.NoError(t, err)
+	require.NoError(t, repos.CreateRepo(ctx, other))
+
+	service := notification.NewService(
+		sqlite.NewNotificationSinkRepository(db),
+		repo.NewStore(repos),
+		notification.NewSender(nil),
+		log.NewLogger(log.WithNop()),
+	)
+	srv := newRecordingServer(t)
+	require.NoError(t, service.CreateSink(ctx, notification.NewTeamSink(tm.ID.String(), notification.KindWebhook, srv.URL, nil)))
+
+	sinks, err := service.ListSinksByTeam(ctx, tm.ID.String())
+	require.NoError(t, err)
+	require.Len(t, sinks, 1)
+	assert.Equal(t, tm.ID.String(), sinks[0].TeamID)
+	assert.Empty(t, sinks[0].RepoID)
+
+	for _, r := range []*repo.Repo{inTeam, other} {
+		service.Notify(ctx, notification.Notification{
+			Event:  notification.EventTaskFailed,
+			RepoID: r.ID.String(),
+			Title:  "Task failed: Add login",
+		})
+	}
+	service.Wait()
+
+	got := srv.received()
+	require.Len(t, got, 1, "only repos in the team reach its sinks")
+	assert.Equal(t, "owner/in-team", got[0]["repo_full_name"])
+}
+
+func TestService_TaskStatusChanged(t *testing.T) {
+	tests := []struct {
+		name      string
+		mutate    func(tsk *task.Task)
+		wantEvent notification.EventType
+		wantTitle msgcat.ID
+	}{
+		{
+			name:      "failed",
+			mutate:    func(tsk *task.Task) { tsk.Status = task.StatusFailed; tsk.CloseReason = "Worker timeout" },
+			wantEvent: notification.EventTaskFailed,
+			wantTitle: msgcat.NotifyTaskFailedTitle,
+		},
+		{
+			name: "failed over budget",
+			mutate: func(tsk *task.Task) {
+				tsk.Status = task.StatusFailed
+				tsk.MaxCostUSD = 1
+				tsk.CostUSD = 1.2
+			},
+			wantEvent: notification.EventBudgetExceeded,
+			wantTitle: msgcat.NotifyTaskBudgetExceededTitle,
+		},
+		{
+			name:      "review",
+			mutate:    func(tsk *task.Task) { tsk.Status = task.StatusReview },
+			wantEvent: notification.EventTaskNeedsReview,
+			wantTitle: msgcat.NotifyTaskNeedsReviewTitle,
+		},
+		{
+			name:      "merged",
+			mutate:    func(tsk *task.Task) { tsk.Status = task.StatusMerged },
+			wantEvent: notification.EventPRMerged,
+			wantTitle: msgcat.NotifyPRMergedTitle,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newServiceFixture(t)
+			srv := newRecordingServer(t)
+			f.addSink(t, notification.KindWebhook, srv.URL)
+
+			tsk := task.NewTask(f.repoID, "Add login", "desc", nil, nil, 0, false, false, "", true)
+			tsk.PullRequestURL = "https://github.com/owner/test-repo/pull/7"
+			tt.mutate(tsk)
+
+			f.service.TaskStatusChanged(context.Background(), tsk)
+			f.service.Wait()
+
+			got := srv.received()
+			require.Len(t, got, 1)
+			assert.Equal(t, string(tt.wantEvent), got[0]["event"])
+			assert.Equal(t, msgcat.Text(tt.wantTitle, "Add login"), got[0]["title"])
+			assert.Equal(t, tsk.ID.String(), got[0]["task_id"])
+			assert.Equal(t, tsk.PullRequestURL, got[0]["url"])
+		})
+	}
+
+	t.Run("other statuses are ignored", func(t *testing.T) {
+		f := newServiceFixture(t)
+		srv := newRecordingServer(t)
+		f.addSink(t, notification.KindWebhook, srv.URL)
+
+		tsk := task.NewTask(f.repoID, "Add login", "desc", nil, nil, 0, false, false, "", true)
+		tsk.Status = task.StatusRunning
+		f.service.TaskStatusChanged(context.Background(), tsk)
+		f.service.Wait()
+
+		assert.Empty(t, srv.received())
+	})
+}
+
+func TestService_EpicEvents(t *testing.T) {
+	f := newServiceFixture(t)
+	srv := newRecordingServer(t)
+	f.addSink(t, notification.KindSlack, srv.URL)
+
+	e := epic.NewEpic(f.repoID, "Auth overhaul", "desc")
+	e.TaskIDs = []string{"tsk_1", "tsk_2"}
+	e.CostUSD = 2.5
+	e.MaxCostUSD = 2
+
+	f.service.EpicCompleted(context.Background(), e)
+	f.service.EpicBudgetExceeded(context.Background(), e)
+	f.service.Wait()
+
+	var texts []string
+	for _, body := range srv.received() {
+		texts = append(texts, body["text"].(string))
+	}
+	assert.ElementsMatch(t, []string{
+		"*Epic completed: Auth overhaul* (owner/test-repo)\nAll 2 tasks have finished.",
+		"*Epic planning budget exceeded: Auth overhaul* (owner/test-repo)\nSpent $2.50 of $2.00. The planning session has been stopped.",
+	}, texts)
+}
+
+func TestService_CostAnomaly(t *testing.T) {
+	f := newServiceFixture(t)
+	srv := newRecordingServer(t)
+	f.addSink(t, notification.KindSlack, srv.URL, notification.EventCostAnomaly)
+
+	now := time.Now()
+	f.service.CostAnomaly(context.Background(), costguard.Anomaly{
+		Kind:         costguard.KindTaskCost,
+		RepoID:       f.repoID,
+		TaskID:       "tsk_1",
+		TaskTitle:    "Fix login",
+		CostUSD:      12,
+		ThresholdUSD: 4.5,
+		DetectedAt:   now,
+	}, false)
+	f.service.CostAnomaly(context.Background(), costguard.Anomaly{
+		Kind:         costguard.KindHourlySpend,
+		RepoIDs:      []string{f.repoID},
+		CostUSD:      40,
+		ThresholdUSD: 8,
+		DetectedAt:   now,
+	}, true)
+	f.service.Wait()
+
+	var texts []string
+	for _, body := range srv.received() {
+		texts = append(texts, body["text"].(string))
+	}
+	assert.ElementsMatch(t, []string{
+		"*Runaway task cost: Fix login* (owner/test-repo)\nSpent $12.00, far beyond the repo's usual task cost (anomaly threshold $4.50).",
+		"*Spend spike across Verve* (owner/test-repo)\n$40.00 was spent in the last hour (anomaly threshold $8.00). Dispatching new work to agents is paused until an admin acknowledges the pause.",
+	}, texts)
+}
+
+func TestService_TestSink(t *testing.T) {
+	f := newServiceFixture(t)
+	srv := newRecordingServer(t)
+	sink := notification.NewSink(f.repoID, notification.KindDiscord, srv.URL, nil)
+
+	require.NoError(t, f.service.TestSink(context.Background(), sink))
+	got := srv.received()
+	require.Len(t, got, 1)
+	assert.Contains(t, got[0]["content"], msgcat.Text(msgcat.NotifyTestTitle))
+
+	srv.mu.Lock()
+	srv.status = http.StatusNotFound
+	srv.mu.Unlock()
+	err := f.service.TestSink(context.Background(), sink)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected status 404")
+}
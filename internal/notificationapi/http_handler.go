@@ -0,0 +1,189 @@
+package notificationapi
+
+import (
+	"net/http"
+
+	"github.com/joshjon/kit/server"
+	"github.com/labstack/echo/v4"
+
+	"github.com/vervesh/verve/internal/logkey"
+	"github.com/vervesh/verve/internal/notification"
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/team"
+)
+
+// HTTPHandler handles notification sink settings HTTP requests.
+type HTTPHandler struct {
+	notificationService *notification.Service
+	repoStore           *repo.Store
+	teamStore           *team.Store
+}
+
+// NewHTTPHandler creates a new HTTPHandler.
+func NewHTTPHandler(notificationService *notification.Service, repoStore *repo.Store, teamStore *team.Store) *HTTPHandler {
+	return &HTTPHandler{
+		notificationService: notificationService,
+		repoStore:           repoStore,
+		teamStore:           teamStore,
+	}
+}
+
+// Register adds the notification endpoints to the provided Echo router group.
+func (h *HTTPHandler) Register(g *echo.Group) {
+	g.GET("/repos/:repo_id/notification-sinks", h.ListSinks)
+	g.POST("/repos/:repo_id/notification-sinks", h.CreateSink)
+	g.POST("/repos/:repo_id/notification-sinks/test", h.TestSinkURL)
+
+	g.GET("/teams/:team_id/notification-sinks", h.ListTeamSinks)
+	g.POST("/teams/:team_id/notification-sinks", h.CreateTeamSink)
+
+	g.DELETE("/notification-sinks/:id", h.DeleteSink)
+	g.POST("/notification-sinks/:id/test", h.TestSink)
+}
+
+// ListSinks handles GET /repos/:repo_id/notification-sinks
+func (h *HTTPHandler) ListSinks(c echo.Context) error {
+	req, err := server.BindRequest[RepoIDRequest](c)
+	if err != nil {
+		return err
+	}
+	repoID := repo.MustParseRepoID(req.RepoID)
+	c.Set(logkey.RepoID, repoID.String())
+
+	sinks, err := h.notificationService.ListSinksByRepo(c.Request().Context(), repoID.String())
+	if err != nil {
+		return err
+	}
+
+	resp := make([]SinkResponse, len(sinks))
+	for i, s := range sinks {
+		resp[i] = newSinkResponse(s)
+	}
+	return server.SetResponseList(c, http.StatusOK, resp, "")
+}
+
+// CreateSink handles POST /repos/:repo_id/notification-sinks
+func (h *HTTPHandler) CreateSink(c echo.Context) error {
+	req, err := server.BindRequest[CreateSinkRequest](c)
+	if err != nil {
+		return err
+	}
+	repoID := repo.MustParseRepoID(req.RepoID)
+	c.Set(logkey.RepoID, repoID.String())
+
+	if _, err := h.repoStore.ReadRepo(c.Request().Context(), repoID); err != nil {
+		return err
+	}
+
+	sink := notification.NewSink(repoID.String(), req.Kind, req.URL, req.Events)
+	if err := h.notificationService.CreateSink(c.Request().Context(), sink); err != nil {
+		return err
+	}
+	c.Set(logkey.SinkID, sink.ID.String())
+
+	return server.SetResponse(c, http.StatusCreated, newSinkResponse(sink))
+}
+
+// ListTeamSinks handles GET /teams/:team_id/notification-sinks
+func (h *HTTPHandler) ListTeamSinks(c echo.Context) error {
+	req, err := server.BindRequest[TeamIDRequest](c)
+	if err != nil {
+		return err
+	}
+	teamID := team.MustParseTeamID(req.TeamID)
+	c.Set(logkey.TeamID, teamID.String())
+
+	sinks, err := h.notificationService.ListSinksByTeam(c.Request().Context(), teamID.String())
+	if err != nil {
+		return err
+	}
+
+	resp := make([]SinkResponse, len(sinks))
+	for i, s := range sinks {
+		resp[i] = newSinkResponse(s)
+	}
+	return server.SetResponseList(c, http.StatusOK, resp, "")
+}
+
+// CreateTeamSink handles POST /teams/:team_id/notification-sinks
+// The sink receives the events of every repo in the team.
+func (h *HTTPHandler) CreateTeamSink(c echo.Context) error {
+	req, err := server.BindRequest[CreateTeamSinkRequest](c)
+	if err != nil {
+		return err
+	}
+	teamID := team.MustParseTeamID(req.TeamID)
+	c.Set(logkey.TeamID, teamID.String())
+
+	if _, err := h.teamStore.ReadTeam(c.Request().Context(), teamID); err != nil {
+		return err
+	}
+
+	sink := notification.NewTeamSink(teamID.String(), req.Kind, req.URL, req.Events)
+	if err := h.notificationService.CreateSink(c.Request().Context(), sink); err != nil {
+		return err
+	}
+	c.Set(logkey.SinkID, sink.ID.String())
+
+	return server.SetResponse(c, http.StatusCreated, newSinkResponse(sink))
+}
+
+// TestSinkURL handles POST /repos/:repo_id/notification-sinks/test
+// It sends a test notification to an unsaved sink URL.
+func (h *HTTPHandler) TestSinkURL(c echo.Context) error {
+	req, err := server.BindRequest[TestSinkURLRequest](c)
+	if err != nil {
+		return err
+	}
+	repoID := repo.MustParseRepoID(req.RepoID)
+	c.Set(logkey.RepoID, repoID.String())
+
+	sink := notification.NewSink(repoID.String(), req.Kind, req.URL, nil)
+	return h.sendTest(c, sink)
+}
+
+// DeleteSink handles DELETE /notification-sinks/:id
+func (h *HTTPHandler) DeleteSink(c echo.Context) error {
+	req, err := server.BindRequest[SinkIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := notification.MustParseSinkID(req.ID)
+	c.Set(logkey.SinkID, id.String())
+
+	ctx := c.Request().Context()
+
+	if _, err := h.notificationService.ReadSink(ctx, id); err != nil {
+		return err
+	}
+	if err := h.notificationService.DeleteSink(ctx, id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// TestSink handles POST /notification-sinks/:id/test
+func (h *HTTPHandler) TestSink(c echo.Context) error {
+	req, err := server.BindRequest[SinkIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := notification.MustParseSinkID(req.ID)
+	c.Set(logkey.SinkID, id.String())
+
+	sink, err := h.notificationService.ReadSink(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return h.sendTest(c, sink)
+}
+
+// sendTest sends a test notification and reports delivery failures in the
+// response body rather than as an HTTP error, since the request itself
+// succeeded.
+func (h *HTTPHandler) sendTest(c echo.Context, sink *notification.Sink) error {
+	if err := h.notificationService.TestSink(c.Request().Context(), sink); err != nil {
+		return server.SetResponse(c, http.StatusOK, TestSinkResponse{Success: false, Error: err.Error()})
+	}
+	return server.SetResponse(c, http.StatusOK, TestSinkResponse{Success: true})
+}
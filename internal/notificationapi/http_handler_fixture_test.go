@@ -0,0 +1,150 @@
+package notificationapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joshjon/kit/log"
+	"github.com/joshjon/kit/server"
+	"github.com/joshjon/kit/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/notification"
+	"github.com/vervesh/verve/internal/notificationapi"
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/sqlite"
+	"github.com/vervesh/verve/internal/team"
+)
+
+type fixture struct {
+	Server              *server.Server
+	NotificationService *notification.Service
+	Repo                *repo.Repo
+	Team                *team.Team
+	t                   *testing.T
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	db := sqlite.NewTestDB(t)
+	logger := log.NewLogger(log.WithNop())
+
+	repoRepo := sqlite.NewRepoRepository(db)
+	repoStore := repo.NewStore(repoRepo)
+
+	notificationRepo := sqlite.NewNotificationSinkRepository(db)
+	notificationService := notification.NewService(notificationRepo, repoStore, notification.NewSender(nil), logger)
+
+	teamStore := team.NewStore(sqlite.NewTeamRepository(db))
+
+	handler := notificationapi.NewHTTPHandler(notificationService, repoStore, teamStore)
+
+	srv, err := server.NewServer(testutil.GetFreePort(t))
+	require.NoError(t, err)
+	srv.Register("/api/v1", handler)
+
+	go srv.Start()
+	err = srv.WaitHealthy(10, 100*time.Millisecond)
+	require.NoError(t, err)
+
+	// Pre-create a repo for use in tests.
+	r, _ := repo.NewRepo("owner/test-repo")
+	require.NoError(t, repoStore.CreateRepo(context.Background(), r))
+
+	// Pre-create a team for use in tests.
+	tm := team.NewTeam("Platform")
+	require.NoError(t, teamStore.CreateTeam(context.Background(), tm))
+
+	t.Cleanup(func() { srv.Stop(context.Background()) })
+
+	return &fixture{
+		Server:              srv,
+		NotificationService: notificationService,
+		Repo:                r,
+		Team:                tm,
+		t:                   t,
+	}
+}
+
+// --- URL helpers ---
+
+func (f *fixture) repoSinksURL() string {
+	return fmt.Sprintf("%s/api/v1/repos/%s/notification-sinks", f.Server.Address(), f.Repo.ID)
+}
+
+func (f *fixture) teamSinksURL() string {
+	return fmt.Sprintf("%s/api/v1/teams/%s/notification-sinks", f.Server.Address(), f.Team.ID)
+}
+
+func (f *fixture) sinkURL(id string) string {
+	return fmt.Sprintf("%s/api/v1/notification-sinks/%s", f.Server.Address(), id)
+}
+
+// --- Seed helpers ---
+
+func (f *fixture) seedSink(kind notification.Kind, url string, events ...notification.EventType) *notification.Sink {
+	f.t.Helper()
+	sink := notification.NewSink(f.Repo.ID.String(), kind, url, events)
+	require.NoError(f.t, f.NotificationService.CreateSink(context.Background(), sink))
+	return sink
+}
+
+// --- Webhook receiver ---
+
+// webhookReceiver is a test HTTP server that records posted bodies and
+// responds with a configurable status code.
+type webhookReceiver struct {
+	*httptest.Server
+	mu     sync.Mutex
+	bodies [][]byte
+	status int
+}
+
+func newWebhookReceiver(t *testing.T, status int) *webhookReceiver {
+	t.Helper()
+	wr := &webhookReceiver{status: status}
+	wr.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		wr.mu.Lock()
+		wr.bodies = append(wr.bodies, body)
+		wr.mu.Unlock()
+		w.WriteHeader(wr.status)
+	}))
+	t.Cleanup(wr.Close)
+	return wr
+}
+
+func (wr *webhookReceiver) count() int {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	return len(wr.bodies)
+}
+
+// --- HTTP helpers ---
+
+func doJSON(t *testing.T, method, url string, body any) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, mustJSONReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	httpRes, err := testutil.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return httpRes
+}
+
+func mustJSONReader(v any) io.Reader {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return bytes.NewReader(b)
+}
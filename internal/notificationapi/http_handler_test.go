@@ -0,0 +1,162 @@
+package notificationapi_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/joshjon/kit/server"
+	"github.com/joshjon/kit/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/notification"
+	"github.com/vervesh/verve/internal/notificationapi"
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/team"
+)
+
+// --- Create Sink ---
+
+func TestCreateSink_Success(t *testing.T) {
+	f := newFixture(t)
+
+	req := notificationapi.CreateSinkRequest{
+		Kind:   notification.KindSlack,
+		URL:    "https://hooks.slack.com/services/T000/B000/XXXX",
+		Events: []notification.EventType{notification.EventTaskFailed},
+	}
+	res := testutil.Post[server.Response[notificationapi.SinkResponse]](t, f.repoSinksURL(), req)
+	assert.Equal(t, notification.KindSlack, res.Data.Kind)
+	assert.Equal(t, f.Repo.ID.String(), res.Data.RepoID)
+	assert.Equal(t, "https://hooks.slack.com/•••", res.Data.URL, "expected URL to be masked")
+	assert.Equal(t, []notification.EventType{notification.EventTaskFailed}, res.Data.Events)
+}
+
+func TestCreateSink_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		req  notificationapi.CreateSinkRequest
+	}{
+		{"unknown kind", notificationapi.CreateSinkRequest{Kind: "email", URL: "https://example.com/hook"}},
+		{"invalid url", notificationapi.CreateSinkRequest{Kind: notification.KindWebhook, URL: "ftp://example.com"}},
+		{"empty url", notificationapi.CreateSinkRequest{Kind: notification.KindWebhook}},
+		{"unknown event", notificationapi.CreateSinkRequest{Kind: notification.KindWebhook, URL: "https://example.com/hook", Events: []notification.EventType{"task_created"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			httpRes := doJSON(t, http.MethodPost, f.repoSinksURL(), tt.req)
+			defer httpRes.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+		})
+	}
+}
+
+func TestCreateSink_RepoNotFound(t *testing.T) {
+	f := newFixture(t)
+
+	url := f.Server.Address() + "/api/v1/repos/" + repo.NewRepoID().String() + "/notification-sinks"
+	req := notificationapi.CreateSinkRequest{Kind: notification.KindWebhook, URL: "https://example.com/hook"}
+	httpRes := doJSON(t, http.MethodPost, url, req)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusNotFound, httpRes.StatusCode)
+}
+
+// --- List Sinks ---
+
+func TestListSinks(t *testing.T) {
+	f := newFixture(t)
+	f.seedSink(notification.KindSlack, "https://hooks.slack.com/services/T/B/X")
+	f.seedSink(notification.KindDiscord, "https://discord.com/api/webhooks/1/abc", notification.EventEpicCompleted)
+
+	res := testutil.Get[server.ResponseList[notificationapi.SinkResponse]](t, f.repoSinksURL())
+	require.Len(t, res.Data, 2)
+	for _, s := range res.Data {
+		assert.Contains(t, s.URL, "•••", "expected URL to be masked")
+	}
+}
+
+// --- Team Sinks ---
+
+func TestCreateTeamSink_Success(t *testing.T) {
+	f := newFixture(t)
+
+	req := notificationapi.CreateTeamSinkRequest{Kind: notification.KindWebhook, URL: "https://example.com/hook"}
+	res := testutil.Post[server.Response[notificationapi.SinkResponse]](t, f.teamSinksURL(), req)
+	assert.Equal(t, f.Team.ID.String(), res.Data.TeamID)
+	assert.Empty(t, res.Data.RepoID)
+
+	list := testutil.Get[server.ResponseList[notificationapi.SinkResponse]](t, f.teamSinksURL())
+	require.Len(t, list.Data, 1)
+	assert.Equal(t, res.Data.ID, list.Data[0].ID)
+
+	repoList := testutil.Get[server.ResponseList[notificationapi.SinkResponse]](t, f.repoSinksURL())
+	assert.Empty(t, repoList.Data, "team sinks are not listed with the repo's")
+}
+
+func TestCreateTeamSink_TeamNotFound(t *testing.T) {
+	f := newFixture(t)
+
+	url := f.Server.Address() + "/api/v1/teams/" + team.NewTeamID().String() + "/notification-sinks"
+	req := notificationapi.CreateTeamSinkRequest{Kind: notification.KindWebhook, URL: "https://example.com/hook"}
+	httpRes := doJSON(t, http.MethodPost, url, req)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusNotFound, httpRes.StatusCode)
+}
+
+// --- Delete Sink ---
+
+func TestDeleteSink_Success(t *testing.T) {
+	f := newFixture(t)
+	sink := f.seedSink(notification.KindSlack, "https://hooks.slack.com/services/T/B/X")
+
+	testutil.Delete(t, f.sinkURL(sink.ID.String()))
+
+	res := testutil.Get[server.ResponseList[notificationapi.SinkResponse]](t, f.repoSinksURL())
+	assert.Empty(t, res.Data)
+}
+
+func TestDeleteSink_NotFound(t *testing.T) {
+	f := newFixture(t)
+
+	httpRes := doJSON(t, http.MethodDelete, f.sinkURL(notification.NewSinkID().String()), nil)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusNotFound, httpRes.StatusCode)
+}
+
+// --- Test Sink ---
+
+func TestTestSink_Success(t *testing.T) {
+	f := newFixture(t)
+	receiver := newWebhookReceiver(t, http.StatusOK)
+	sink := f.seedSink(notification.KindWebhook, receiver.URL)
+
+	res := testutil.Post[server.Response[notificationapi.TestSinkResponse]](t, f.sinkURL(sink.ID.String())+"/test", nil)
+	assert.True(t, res.Data.Success)
+	assert.Empty(t, res.Data.Error)
+	assert.Equal(t, 1, receiver.count())
+}
+
+func TestTestSink_DeliveryFailure(t *testing.T) {
+	f := newFixture(t)
+	receiver := newWebhookReceiver(t, http.StatusForbidden)
+	sink := f.seedSink(notification.KindSlack, receiver.URL)
+
+	res := testutil.Post[server.Response[notificationapi.TestSinkResponse]](t, f.sinkURL(sink.ID.String())+"/test", nil)
+	assert.False(t, res.Data.Success)
+	assert.Contains(t, res.Data.Error, "unexpected status 403")
+}
+
+func TestTestSinkURL(t *testing.T) {
+	f := newFixture(t)
+	receiver := newWebhookReceiver(t, http.StatusNoContent)
+
+	req := notificationapi.TestSinkURLRequest{Kind: notification.KindDiscord, URL: receiver.URL}
+	res := testutil.Post[server.Response[notificationapi.TestSinkResponse]](t, f.repoSinksURL()+"/test", req)
+	assert.True(t, res.Data.Success)
+	assert.Equal(t, 1, receiver.count())
+
+	// Testing a URL does not save it.
+	list := testutil.Get[server.ResponseList[notificationapi.SinkResponse]](t, f.repoSinksURL())
+	assert.Empty(t, list.Data)
+}
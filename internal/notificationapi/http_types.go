@@ -0,0 +1,133 @@
+package notificationapi
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/cohesivestack/valgo"
+
+	"github.com/vervesh/verve/internal/notification"
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/team"
+)
+
+// RepoIDRequest captures the :repo_id path parameter.
+type RepoIDRequest struct {
+	RepoID string `param:"repo_id" json:"-"`
+}
+
+func (r RepoIDRequest) Validate() error {
+	return valgo.In("params", valgo.Is(repo.RepoIDValidator(r.RepoID, "repo_id"))).ToError()
+}
+
+// TeamIDRequest captures the :team_id path parameter.
+type TeamIDRequest struct {
+	TeamID string `param:"team_id" json:"-"`
+}
+
+func (r TeamIDRequest) Validate() error {
+	return valgo.In("params", valgo.Is(team.TeamIDValidator(r.TeamID, "team_id"))).ToError()
+}
+
+// SinkIDRequest captures the :id path parameter.
+type SinkIDRequest struct {
+	ID string `param:"id" json:"-"`
+}
+
+func (r SinkIDRequest) Validate() error {
+	return valgo.In("params", valgo.Is(notification.SinkIDValidator(r.ID, "id"))).ToError()
+}
+
+// CreateSinkRequest is the request body for configuring a notification sink.
+// An empty events list subscribes the sink to all event types.
+type CreateSinkRequest struct {
+	RepoID string                   `param:"repo_id" json:"-"`
+	Kind   notification.Kind        `json:"kind"`
+	URL    string                   `json:"url"`
+	Events []notification.EventType `json:"events,omitempty"`
+}
+
+func (r CreateSinkRequest) Validate() error {
+	v := valgo.In("params", valgo.Is(repo.RepoIDValidator(r.RepoID, "repo_id")))
+	v = validateSink(v, r.Kind, r.URL)
+	return validateEvents(v, r.Events).ToError()
+}
+
+// CreateTeamSinkRequest is the request body for configuring a team's
+// notification sink. An empty events list subscribes the sink to all event
+// types.
+type CreateTeamSinkRequest struct {
+	TeamID string                   `param:"team_id" json:"-"`
+	Kind   notification.Kind        `json:"kind"`
+	URL    string                   `json:"url"`
+	Events []notification.EventType `json:"events,omitempty"`
+}
+
+func (r CreateTeamSinkRequest) Validate() error {
+	v := valgo.In("params", valgo.Is(team.TeamIDValidator(r.TeamID, "team_id")))
+	v = validateSink(v, r.Kind, r.URL)
+	return validateEvents(v, r.Events).ToError()
+}
+
+// TestSinkURLRequest is the request body for sending a test notification to
+// a sink URL before it is saved.
+type TestSinkURLRequest struct {
+	RepoID string            `param:"repo_id" json:"-"`
+	Kind   notification.Kind `json:"kind"`
+	URL    string            `json:"url"`
+}
+
+func (r TestSinkURLRequest) Validate() error {
+	v := valgo.In("params", valgo.Is(repo.RepoIDValidator(r.RepoID, "repo_id")))
+	return validateSink(v, r.Kind, r.URL).ToError()
+}
+
+func validateSink(v *valgo.Validation, kind notification.Kind, sinkURL string) *valgo.Validation {
+	if !notification.ValidKind(kind) {
+		v = v.AddErrorMessage("kind", "must be slack, discord or webhook")
+	}
+	u, err := url.Parse(sinkURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		v = v.AddErrorMessage("url", "must be a valid http or https URL")
+	}
+	return v
+}
+
+func validateEvents(v *valgo.Validation, events []notification.EventType) *valgo.Validation {
+	for _, e := range events {
+		if !notification.ValidEventType(e) {
+			return v.AddErrorMessage("events", "must only contain task_failed, task_needs_review, pr_merged, budget_exceeded, epic_completed or cost_anomaly")
+		}
+	}
+	return v
+}
+
+// SinkResponse describes a configured notification sink. The URL is masked
+// because webhook URLs embed their secret token.
+type SinkResponse struct {
+	ID        string                   `json:"id"`
+	RepoID    string                   `json:"repo_id,omitempty"`
+	TeamID    string                   `json:"team_id,omitempty"`
+	Kind      notification.Kind        `json:"kind"`
+	URL       string                   `json:"url"`
+	Events    []notification.EventType `json:"events"`
+	CreatedAt time.Time                `json:"created_at"`
+}
+
+func newSinkResponse(s *notification.Sink) SinkResponse {
+	return SinkResponse{
+		ID:        s.ID.String(),
+		RepoID:    s.RepoID,
+		TeamID:    s.TeamID,
+		Kind:      s.Kind,
+		URL:       s.MaskedURL(),
+		Events:    s.Events,
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+// TestSinkResponse reports the outcome of a test notification.
+type TestSinkResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
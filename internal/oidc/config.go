@@ -0,0 +1,88 @@
+package oidc
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/vervesh/verve/internal/user"
+)
+
+// DefaultGroupsClaim is the ID token claim listing the user's groups when
+// the config doesn't name one.
+const DefaultGroupsClaim = "groups"
+
+// Config is the OpenID Connect client configuration, stored in the settings.
+// Users signing in get the most privileged role any of their groups map to,
+// or DefaultRole when none do.
+type Config struct {
+	IssuerURL string `json:"issuer_url"`
+	ClientID  string `json:"client_id"`
+	// ClientSecret is encrypted at rest and never returned by the API.
+	ClientSecret string `json:"client_secret,omitempty"`
+	// RedirectURL is the callback registered with the provider:
+	// https://<verve host>/api/v1/auth/oidc/callback.
+	RedirectURL string               `json:"redirect_url"`
+	Scopes      []string             `json:"scopes,omitempty"` // Requested in addition to "openid"
+	GroupsClaim string               `json:"groups_claim,omitempty"`
+	GroupRoles  map[string]user.Role `json:"group_roles,omitempty"`
+	// DefaultRole is granted to users in none of the mapped groups. Empty
+	// refuses them.
+	DefaultRole user.Role `json:"default_role,omitempty"`
+}
+
+// Validate reports the first problem with the config.
+func (c Config) Validate() error {
+	u, err := url.Parse(c.IssuerURL)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return errors.New("issuer_url must be an http(s) URL")
+	}
+	if strings.TrimSpace(c.ClientID) == "" {
+		return errors.New("client_id is required")
+	}
+	if u, err := url.Parse(c.RedirectURL); err != nil || u.Host == "" {
+		return errors.New("redirect_url must be an absolute URL")
+	}
+	for group, role := range c.GroupRoles {
+		if !user.ValidRole(string(role)) {
+			return errors.New("group_roles[" + group + "] must be one of viewer, operator or admin")
+		}
+	}
+	if c.DefaultRole != "" && !user.ValidRole(string(c.DefaultRole)) {
+		return errors.New("default_role must be one of viewer, operator or admin")
+	}
+	return nil
+}
+
+// groupsClaim returns the claim holding the user's groups.
+func (c Config) groupsClaim() string {
+	if c.GroupsClaim != "" {
+		return c.GroupsClaim
+	}
+	return DefaultGroupsClaim
+}
+
+// scopes returns the scopes to request: "openid" and the configured ones.
+func (c Config) scopes() []string {
+	scopes := []string{"openid"}
+	for _, s := range c.Scopes {
+		if s != "openid" {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes
+}
+
+// RoleFor returns the role granted to a member of groups. ok is false when
+// the user may not sign in.
+func (c Config) RoleFor(groups []string) (role user.Role, ok bool) {
+	for _, g := range groups {
+		if r, mapped := c.GroupRoles[g]; mapped && (role == "" || r.Allows(role)) {
+			role = r
+		}
+	}
+	if role == "" {
+		role = c.DefaultRole
+	}
+	return role, role != ""
+}
@@ -0,0 +1,55 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"time"
+)
+
+// loginTTL is how long a user has to sign in with the provider.
+const loginTTL = 10 * time.Minute
+
+// Login is a sign-in in progress. It is kept in an encrypted cookie between
+// the redirect to the provider and the callback, so any replica can finish
+// it.
+type Login struct {
+	State     string `json:"state"`
+	Nonce     string `json:"nonce"`
+	Verifier  string `json:"verifier"` // PKCE code verifier
+	Redirect  string `json:"redirect"` // UI path to return to once signed in
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// NewLogin starts a sign-in that returns the user to redirect, a path in the
+// UI. Anything else returns them to the dashboard, so the login can't be used
+// to send users to another site.
+func NewLogin(redirect string) *Login {
+	if !strings.HasPrefix(redirect, "/") || strings.HasPrefix(redirect, "//") || strings.HasPrefix(redirect, "/\\") {
+		redirect = "/"
+	}
+	return &Login{
+		State:     randomString(),
+		Nonce:     randomString(),
+		Verifier:  randomString(),
+		Redirect:  redirect,
+		ExpiresAt: time.Now().Add(loginTTL).Unix(),
+	}
+}
+
+// Expired reports whether the sign-in took too long.
+func (l *Login) Expired() bool {
+	return time.Now().Unix() > l.ExpiresAt
+}
+
+func randomString() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
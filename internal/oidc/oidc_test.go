@@ -0,0 +1,209 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/oidc"
+	"github.com/vervesh/verve/internal/user"
+)
+
+// testProvider is an OpenID Connect provider issuing RS256 ID tokens.
+type testProvider struct {
+	*httptest.Server
+	key    *rsa.PrivateKey
+	claims map[string]any // Claims of the ID token the token endpoint returns
+}
+
+func newTestProvider(t *testing.T) *testProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	p := &testProvider{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.URL,
+			"authorization_endpoint": p.URL + "/authorize",
+			"token_endpoint":         p.URL + "/token",
+			"jwks_uri":               p.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "k1",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.PostFormValue("code") != "good-code" || r.PostFormValue("code_verifier") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": p.sign(t, p.claims)})
+	})
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+	return p
+}
+
+func (p *testProvider) sign(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "kid": "k1", "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	sum := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, sum[:])
+	require.NoError(t, err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func (p *testProvider) config() oidc.Config {
+	return oidc.Config{
+		IssuerURL:   p.URL,
+		ClientID:    "verve",
+		RedirectURL: "https://verve.example.com/api/v1/auth/oidc/callback",
+		GroupRoles:  map[string]user.Role{"eng": user.RoleOperator},
+	}
+}
+
+func (p *testProvider) validClaims(nonce string) map[string]any {
+	return map[string]any{
+		"iss":    p.URL,
+		"aud":    "verve",
+		"sub":    "u-123",
+		"email":  "ada@example.com",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"nonce":  nonce,
+		"groups": []string{"eng"},
+	}
+}
+
+func TestProvider_SignIn(t *testing.T) {
+	tp := newTestProvider(t)
+	ctx := context.Background()
+
+	p, err := oidc.Discover(ctx, http.DefaultClient, tp.config())
+	require.NoError(t, err)
+
+	login := oidc.NewLogin("/tasks")
+	authURL, err := url.Parse(p.AuthCodeURL(login.State, login.Nonce, login.Verifier))
+	require.NoError(t, err)
+	assert.Equal(t, tp.URL+"/authorize", authURL.Scheme+"://"+authURL.Host+authURL.Path)
+	assert.Equal(t, login.State, authURL.Query().Get("state"))
+	assert.Equal(t, "openid", authURL.Query().Get("scope"))
+	assert.Equal(t, "S256", authURL.Query().Get("code_challenge_method"))
+
+	tp.claims = tp.validClaims(login.Nonce)
+	idToken, err := p.Exchange(ctx, "good-code", login.Verifier)
+	require.NoError(t, err)
+
+	claims, err := p.VerifyIDToken(ctx, idToken, login.Nonce)
+	require.NoError(t, err)
+	assert.Equal(t, "u-123", claims.Subject)
+	assert.Equal(t, "ada@example.com", claims.DisplayName())
+	assert.Equal(t, []string{"eng"}, claims.Groups)
+
+	_, err = p.Exchange(ctx, "bad-code", login.Verifier)
+	assert.Error(t, err)
+}
+
+func TestProvider_VerifyIDToken_Rejects(t *testing.T) {
+	tp := newTestProvider(t)
+	ctx := context.Background()
+	p, err := oidc.Discover(ctx, http.DefaultClient, tp.config())
+	require.NoError(t, err)
+
+	tests := map[string]func(claims map[string]any){
+		"wrong issuer":   func(c map[string]any) { c["iss"] = "https://evil.example.com" },
+		"wrong audience": func(c map[string]any) { c["aud"] = "someone-else" },
+		"expired":        func(c map[string]any) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+		"wrong nonce":    func(c map[string]any) { c["nonce"] = "replayed" },
+		"no subject":     func(c map[string]any) { delete(c, "sub") },
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			claims := tp.validClaims("n1")
+			mutate(claims)
+			_, err := p.VerifyIDToken(ctx, tp.sign(t, claims), "n1")
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("tampered", func(t *testing.T) {
+		raw := tp.sign(t, tp.validClaims("n1"))
+		forged, err := json.Marshal(map[string]any{"iss": tp.URL, "aud": "verve", "sub": "admin", "exp": time.Now().Add(time.Hour).Unix(), "nonce": "n1"})
+		require.NoError(t, err)
+		parts := strings.Split(raw, ".")
+		parts[1] = base64.RawURLEncoding.EncodeToString(forged)
+		_, err = p.VerifyIDToken(ctx, parts[0]+"."+parts[1]+"."+parts[2], "n1")
+		assert.Error(t, err)
+	})
+}
+
+func TestDiscover_IssuerMismatch(t *testing.T) {
+	tp := newTestProvider(t)
+	cfg := tp.config()
+	cfg.IssuerURL = tp.URL + "/other"
+
+	_, err := oidc.Discover(context.Background(), http.DefaultClient, cfg)
+	assert.Error(t, err)
+}
+
+func TestConfig_RoleFor(t *testing.T) {
+	cfg := oidc.Config{GroupRoles: map[string]user.Role{
+		"eng":       user.RoleOperator,
+		"platform":  user.RoleAdmin,
+		"marketing": user.RoleViewer,
+	}}
+
+	tests := []struct {
+		name        string
+		groups      []string
+		defaultRole user.Role
+		want        user.Role
+		wantOK      bool
+	}{
+		{name: "highest mapped role", groups: []string{"marketing", "platform", "eng"}, want: user.RoleAdmin, wantOK: true},
+		{name: "single group", groups: []string{"eng"}, want: user.RoleOperator, wantOK: true},
+		{name: "unmapped refused", groups: []string{"sales"}},
+		{name: "unmapped gets default", groups: []string{"sales"}, defaultRole: user.RoleViewer, want: user.RoleViewer, wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.DefaultRole = tt.defaultRole
+			role, ok := cfg.RoleFor(tt.groups)
+			assert.Equal(t, tt.want, role)
+			assert.Equal(t, tt.wantOK, ok)
+		})
+	}
+}
+
+func TestNewLogin_Redirect(t *testing.T) {
+	assert.Equal(t, "/tasks/tsk_1", oidc.NewLogin("/tasks/tsk_1").Redirect)
+	assert.Equal(t, "/", oidc.NewLogin("https://evil.example.com").Redirect)
+	assert.Equal(t, "/", oidc.NewLogin("//evil.example.com").Redirect)
+	assert.Equal(t, "/", oidc.NewLogin("").Redirect)
+}
@@ -0,0 +1,175 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/vervesh/verve/internal/setting"
+)
+
+// ErrNotConfigured is returned when no OIDC provider is configured.
+var ErrNotConfigured = errors.New("oidc is not configured")
+
+// Cipher encrypts the client secret at rest and the state of sign-ins in
+// progress. *keyprovider.Envelope implements it.
+type Cipher interface {
+	Encrypt(ctx context.Context, plaintext string) (string, error)
+	Decrypt(ctx context.Context, value string) (string, error)
+	// NeedsRewrap reports whether value was encrypted under a key other
+	// than the current one and should be encrypted again.
+	NeedsRewrap(value string) bool
+}
+
+// Service stores the OIDC configuration in the settings and signs users in
+// with the configured provider.
+type Service struct {
+	settings *setting.Service
+	cipher   Cipher
+	client   *http.Client
+
+	mu       sync.Mutex
+	provider *Provider // Discovered for the current config; reset when it changes
+}
+
+// NewService creates a new Service.
+func NewService(settings *setting.Service, cipher Cipher) *Service {
+	return &Service{
+		settings: settings,
+		cipher:   cipher,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Config returns the configuration with the client secret decrypted, or
+// ErrNotConfigured.
+func (s *Service) Config(ctx context.Context) (*Config, error) {
+	v := s.settings.Get(setting.KeyOIDC)
+	if v == "" {
+		return nil, ErrNotConfigured
+	}
+	var cfg Config
+	if err := json.Unmarshal([]byte(v), &cfg); err != nil {
+		return nil, fmt.Errorf("parse oidc setting: %w", err)
+	}
+	if cfg.ClientSecret != "" {
+		secret, err := s.cipher.Decrypt(ctx, cfg.ClientSecret)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt oidc client secret: %w", err)
+		}
+		cfg.ClientSecret = secret
+	}
+	return &cfg, nil
+}
+
+// SaveConfig stores the configuration. An empty client secret keeps the
+// current one, so the configuration can be edited without re-entering it.
+func (s *Service) SaveConfig(ctx context.Context, cfg Config) error {
+	if cfg.ClientSecret == "" {
+		if current, err := s.Config(ctx); err == nil {
+			cfg.ClientSecret = current.ClientSecret
+		}
+	}
+	if cfg.ClientSecret != "" {
+		secret, err := s.cipher.Encrypt(ctx, cfg.ClientSecret)
+		if err != nil {
+			return fmt.Errorf("encrypt oidc client secret: %w", err)
+		}
+		cfg.ClientSecret = secret
+	}
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("marshal oidc setting: %w", err)
+	}
+	if err := s.settings.Set(ctx, setting.KeyOIDC, string(b)); err != nil {
+		return err
+	}
+	s.resetProvider()
+	return nil
+}
+
+// Rewrap re-encrypts the stored client secret under the current key if it
+// was encrypted under a previous one. It reports whether the secret was
+// re-encrypted.
+func (s *Service) Rewrap(ctx context.Context) (bool, error) {
+	v := s.settings.Get(setting.KeyOIDC)
+	if v == "" {
+		return false, nil
+	}
+	var stored Config
+	if err := json.Unmarshal([]byte(v), &stored); err != nil {
+		return false, fmt.Errorf("parse oidc setting: %w", err)
+	}
+	if stored.ClientSecret == "" || !s.cipher.NeedsRewrap(stored.ClientSecret) {
+		return false, nil
+	}
+	cfg, err := s.Config(ctx)
+	if err != nil {
+		return false, err
+	}
+	if err := s.SaveConfig(ctx, *cfg); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DeleteConfig removes the configuration, disabling OIDC sign-in. Sessions
+// already issued last until they expire.
+func (s *Service) DeleteConfig(ctx context.Context) error {
+	if err := s.settings.Delete(ctx, setting.KeyOIDC); err != nil {
+		return err
+	}
+	s.resetProvider()
+	return nil
+}
+
+// Provider returns the configured provider, discovering it on first use.
+func (s *Service) Provider(ctx context.Context) (*Provider, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.provider != nil {
+		return s.provider, nil
+	}
+	cfg, err := s.Config(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p, err := Discover(ctx, s.client, *cfg)
+	if err != nil {
+		return nil, err
+	}
+	s.provider = p
+	return p, nil
+}
+
+func (s *Service) resetProvider() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.provider = nil
+}
+
+// SealLogin encrypts a sign-in in progress for its cookie.
+func (s *Service) SealLogin(ctx context.Context, l *Login) (string, error) {
+	b, err := json.Marshal(l)
+	if err != nil {
+		return "", err
+	}
+	return s.cipher.Encrypt(ctx, string(b))
+}
+
+// OpenLogin decrypts a sign-in in progress from its cookie.
+func (s *Service) OpenLogin(ctx context.Context, sealed string) (*Login, error) {
+	v, err := s.cipher.Decrypt(ctx, sealed)
+	if err != nil {
+		return nil, err
+	}
+	var l Login
+	if err := json.Unmarshal([]byte(v), &l); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
@@ -0,0 +1,28 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HTTPHandler serves an OpenAPI document.
+type HTTPHandler struct {
+	doc *Document
+}
+
+// NewHTTPHandler creates a new HTTPHandler serving doc.
+func NewHTTPHandler(doc *Document) *HTTPHandler {
+	return &HTTPHandler{doc: doc}
+}
+
+// Register adds the endpoints to the provided Echo router group.
+func (h *HTTPHandler) Register(g *echo.Group) {
+	g.GET("/openapi.json", h.GetSpec)
+}
+
+// GetSpec handles GET /openapi.json. The document is served bare rather than
+// in a data envelope so OpenAPI tooling can read it.
+func (h *HTTPHandler) GetSpec(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.doc)
+}
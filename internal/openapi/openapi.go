@@ -0,0 +1,228 @@
+// Package openapi describes the HTTP API as an OpenAPI 3 document built from
+// the request and response types the handlers already bind and return, and
+// validates request bodies against it.
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Version is the OpenAPI version documents are written in.
+const Version = "3.0.3"
+
+// errorSchemaName is the component describing kit's error envelope.
+const errorSchemaName = "Error"
+
+// Route describes one endpoint a handler registers.
+type Route struct {
+	Method string
+	// Path is the Echo route path relative to the group prefix, e.g.
+	// "/tasks/:id".
+	Path    string
+	Summary string
+	Tag     string
+	// Request is a value of the type the handler binds. Fields tagged param
+	// or query become parameters and JSON fields become the request body.
+	Request any
+	// Response is a value of the type in the response's data envelope, or
+	// nil when the endpoint responds with no content.
+	Response any
+	// List marks Response as the element type of a list envelope.
+	List bool
+	// Status is the success status. It defaults to 200, or 204 when
+	// Response is nil.
+	Status int
+	// Stream marks a server-sent events endpoint.
+	Stream bool
+	// Upload is the name of the multipart form field an upload endpoint
+	// reads its file from. The body is then described as multipart form
+	// data rather than JSON.
+	Upload string
+	// Download marks an endpoint that responds with a file.
+	Download   bool
+	Deprecated bool
+}
+
+// Document is an OpenAPI document.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+
+	schemas *schemaBuilder
+}
+
+// Info is the document's metadata.
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Components holds the named schemas referenced from operations.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// PathItem maps lower-case HTTP methods to operations.
+type PathItem map[string]*Operation
+
+// Operation describes one endpoint.
+type Operation struct {
+	Summary     string              `json:"summary,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Deprecated  bool                `json:"deprecated,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter is a path or query parameter.
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody is an operation's request body.
+type RequestBody struct {
+	Content map[string]MediaType `json:"content"`
+}
+
+// Response is one of an operation's responses.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType is the schema of a body in one content type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// New creates an empty document.
+func New(title, version string) *Document {
+	d := &Document{
+		OpenAPI:    Version,
+		Info:       Info{Title: title, Version: version},
+		Paths:      map[string]PathItem{},
+		Components: Components{Schemas: map[string]*Schema{}},
+	}
+	d.schemas = newSchemaBuilder(d.Components.Schemas)
+	d.Components.Schemas[errorSchemaName] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"error": {
+				Type: "object",
+				Properties: map[string]*Schema{
+					"message": {Type: "string"},
+					"details": {Type: "array", Items: &Schema{Type: "string"}},
+				},
+				Required: []string{"message"},
+			},
+		},
+		Required: []string{"error"},
+	}
+	return d
+}
+
+// Add describes routes registered under the group prefix. It must not be
+// called once the document is being served.
+func (d *Document) Add(prefix string, routes ...Route) {
+	for _, r := range routes {
+		path := pathTemplate(prefix + r.Path)
+		item := d.Paths[path]
+		if item == nil {
+			item = PathItem{}
+			d.Paths[path] = item
+		}
+		item[strings.ToLower(r.Method)] = d.operation(r)
+	}
+}
+
+func (d *Document) operation(r Route) *Operation {
+	op := &Operation{
+		Summary:    r.Summary,
+		Deprecated: r.Deprecated,
+		Responses: map[string]Response{
+			"default": {Description: "Error", Content: jsonContent(ref(errorSchemaName))},
+		},
+	}
+	if r.Tag != "" {
+		op.Tags = []string{r.Tag}
+	}
+	if r.Request != nil {
+		t := reflect.TypeOf(r.Request)
+		op.Parameters = d.schemas.parameters(t)
+		if body := d.schemas.body(t); body != nil {
+			op.RequestBody = &RequestBody{Content: jsonContent(body)}
+		}
+	}
+	if r.Upload != "" {
+		op.RequestBody = &RequestBody{Content: map[string]MediaType{"multipart/form-data": {Schema: &Schema{
+			Type:       "object",
+			Properties: map[string]*Schema{r.Upload: binarySchema()},
+			Required:   []string{r.Upload},
+		}}}}
+	}
+
+	status := r.Status
+	switch {
+	case status != 0:
+	case r.Response == nil && !r.Stream && !r.Download:
+		status = http.StatusNoContent
+	default:
+		status = http.StatusOK
+	}
+	res := Response{Description: http.StatusText(status)}
+	switch {
+	case r.Stream:
+		res.Content = map[string]MediaType{"text/event-stream": {Schema: &Schema{Type: "string"}}}
+	case r.Download:
+		res.Content = map[string]MediaType{"application/octet-stream": {Schema: binarySchema()}}
+	case r.Response != nil:
+		data := d.schemas.schema(reflect.TypeOf(r.Response))
+		envelope := &Schema{Type: "object", Required: []string{"data"}}
+		if r.List {
+			envelope.Properties = map[string]*Schema{
+				"data":             {Type: "array", Items: data},
+				"next_page_cursor": {Type: "string"},
+			}
+		} else {
+			envelope.Properties = map[string]*Schema{"data": data}
+		}
+		res.Content = jsonContent(envelope)
+	}
+	op.Responses[strconv.Itoa(status)] = res
+	return op
+}
+
+// lookup returns the operation for an Echo route path such as
+// "/api/v1/tasks/:id", or nil if the document doesn't describe it.
+func (d *Document) lookup(method, echoPath string) *Operation {
+	return d.Paths[pathTemplate(echoPath)][strings.ToLower(method)]
+}
+
+// pathTemplate converts Echo path parameters (":id") to OpenAPI ones
+// ("{id}").
+func pathTemplate(echoPath string) string {
+	segments := strings.Split(echoPath, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") {
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func binarySchema() *Schema {
+	return &Schema{Type: "string", Format: "binary"}
+}
+
+func jsonContent(s *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: s}}
+}
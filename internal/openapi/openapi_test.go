@@ -0,0 +1,150 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cohesivestack/valgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type widgetID struct{ s string }
+
+func (id widgetID) MarshalText() ([]byte, error) { return []byte(id.s), nil }
+
+type Part struct {
+	Name   string `json:"name"`
+	Count  int    `json:"count,omitempty"`
+	Parent *Part  `json:"parent,omitempty"`
+}
+
+type Widget struct {
+	ID        widgetID          `json:"id"`
+	Parts     []Part            `json:"parts"`
+	CreatedAt time.Time         `json:"created_at"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Secret    string            `json:"-"`
+}
+
+type WidgetBody struct {
+	Title string `json:"title"`
+	Size  *int   `json:"size,omitempty"`
+	Parts []Part `json:"parts,omitempty"`
+}
+
+type CreateWidgetRequest struct {
+	RepoID string `param:"repo_id" json:"-"`
+	DryRun bool   `query:"dry_run" json:"-"`
+	WidgetBody
+}
+
+func newTestDocument() *Document {
+	doc := New("Test API", "1.0.0")
+	doc.Add("/api/v1",
+		Route{Method: http.MethodPost, Path: "/repos/:repo_id/widgets", Tag: "widgets", Request: CreateWidgetRequest{}, Response: Widget{}, Status: http.StatusCreated},
+		Route{Method: http.MethodGet, Path: "/repos/:repo_id/widgets", Request: struct {
+			RepoID string `param:"repo_id" json:"-"`
+		}{}, Response: Widget{}, List: true},
+		Route{Method: http.MethodDelete, Path: "/widgets/:id", Deprecated: true},
+		Route{Method: http.MethodPost, Path: "/widgets/:id/image", Upload: "file", Status: http.StatusCreated},
+		Route{Method: http.MethodGet, Path: "/widgets/:id/image", Download: true},
+	)
+	return doc
+}
+
+func TestDocument_Add(t *testing.T) {
+	doc := newTestDocument()
+
+	create := doc.Paths["/api/v1/repos/{repo_id}/widgets"]["post"]
+	require.NotNil(t, create)
+	assert.Equal(t, []string{"widgets"}, create.Tags)
+	assert.Equal(t, []Parameter{
+		{Name: "repo_id", In: "path", Required: true, Schema: &Schema{Type: "string"}},
+		{Name: "dry_run", In: "query", Schema: &Schema{Type: "boolean"}},
+	}, create.Parameters)
+
+	body := create.RequestBody.Content["application/json"].Schema
+	assert.Empty(t, body.Required, "request bodies leave presence checks to Validate")
+	assert.Equal(t, &Schema{Type: "integer", Nullable: true}, body.Properties["size"])
+	assert.Equal(t, &Schema{Type: "array", Items: ref("Part"), Nullable: true}, body.Properties["parts"])
+
+	created := create.Responses["201"].Content["application/json"].Schema
+	assert.Equal(t, ref("Widget"), created.Properties["data"])
+	assert.Equal(t, ref(errorSchemaName), create.Responses["default"].Content["application/json"].Schema)
+
+	list := doc.Paths["/api/v1/repos/{repo_id}/widgets"]["get"]
+	require.NotNil(t, list)
+	assert.Nil(t, list.RequestBody)
+	data := list.Responses["200"].Content["application/json"].Schema.Properties["data"]
+	assert.Equal(t, &Schema{Type: "array", Items: ref("Widget")}, data)
+
+	del := doc.Paths["/api/v1/widgets/{id}"]["delete"]
+	require.NotNil(t, del)
+	assert.True(t, del.Deprecated)
+	assert.Contains(t, del.Responses, "204")
+
+	upload := doc.Paths["/api/v1/widgets/{id}/image"]["post"]
+	require.NotNil(t, upload)
+	form := upload.RequestBody.Content["multipart/form-data"].Schema
+	assert.Equal(t, &Schema{Type: "string", Format: "binary"}, form.Properties["file"])
+	assert.Equal(t, []string{"file"}, form.Required)
+
+	download := doc.Paths["/api/v1/widgets/{id}/image"]["get"]
+	require.NotNil(t, download)
+	assert.Equal(t, &Schema{Type: "string", Format: "binary"}, download.Responses["200"].Content["application/octet-stream"].Schema)
+
+	widget := doc.Components.Schemas["Widget"]
+	require.NotNil(t, widget)
+	assert.Equal(t, []string{"created_at", "id", "parts"}, widget.Required)
+	assert.Equal(t, &Schema{Type: "string"}, widget.Properties["id"])
+	assert.Equal(t, &Schema{Type: "string", Format: "date-time"}, widget.Properties["created_at"])
+	assert.Equal(t, &Schema{Type: "object", AdditionalProperties: &Schema{Type: "string"}, Nullable: true}, widget.Properties["labels"])
+	assert.NotContains(t, widget.Properties, "Secret")
+
+	part := doc.Components.Schemas["Part"]
+	require.NotNil(t, part)
+	assert.Equal(t, &Schema{AllOf: []*Schema{ref("Part")}, Nullable: true}, part.Properties["parent"])
+}
+
+func TestDocument_ValidateBody(t *testing.T) {
+	doc := newTestDocument()
+	schema := doc.lookup(http.MethodPost, "/api/v1/repos/:repo_id/widgets").RequestBody.Content["application/json"].Schema
+
+	tests := map[string]struct {
+		body      string
+		wantField string
+		wantMsg   string
+	}{
+		"valid":          {body: `{"title":"a","size":3,"parts":[{"name":"p","parent":{"name":"q"}}]}`},
+		"empty":          {body: ``},
+		"missing fields": {body: `{}`},
+		"unknown field":  {body: `{"title":"a","colour":"red"}`},
+		"null":           {body: `{"title":null,"parts":null}`},
+		"invalid json":   {body: `{"title":`, wantField: "body", wantMsg: "must be valid JSON"},
+		"not an object":  {body: `[]`, wantField: "body", wantMsg: "must be an object"},
+		"wrong type":     {body: `{"title":1}`, wantField: "title", wantMsg: "must be a string"},
+		"fraction":       {body: `{"size":1.5}`, wantField: "size", wantMsg: "must be an integer"},
+		"nested":         {body: `{"parts":[{"name":"p"},{"name":"q","count":"2"}]}`, wantField: "parts[1].count", wantMsg: "must be an integer"},
+		"nested ref":     {body: `{"parts":[{"parent":{"name":false}}]}`, wantField: "parts[0].parent.name", wantMsg: "must be a string"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := doc.validateBody(schema, []byte(tt.body))
+			if tt.wantField == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var verr *valgo.Error
+			require.ErrorAs(t, err, &verr)
+			require.Contains(t, verr.Errors(), tt.wantField)
+			assert.Equal(t, []string{tt.wantMsg}, verr.Errors()[tt.wantField].Messages())
+		})
+	}
+}
+
+func TestPathTemplate(t *testing.T) {
+	assert.Equal(t, "/api/v1/tasks/{id}/attempts/{attempt}/logs", pathTemplate("/api/v1/tasks/:id/attempts/:attempt/logs"))
+	assert.Equal(t, "/api/v1/tasks/bulk-retry", pathTemplate("/api/v1/tasks/bulk-retry"))
+}
@@ -0,0 +1,236 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Schema is an OpenAPI schema object.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+const refPrefix = "#/components/schemas/"
+
+func ref(name string) *Schema {
+	return &Schema{Ref: refPrefix + name}
+}
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	rawMessageType    = reflect.TypeFor[json.RawMessage]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// schemaBuilder derives schemas from Go types the way encoding/json encodes
+// them. Named struct types become components and are referenced by name.
+type schemaBuilder struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+	types      map[string]reflect.Type
+}
+
+func newSchemaBuilder(components map[string]*Schema) *schemaBuilder {
+	return &schemaBuilder{
+		components: components,
+		names:      map[reflect.Type]string{},
+		types:      map[string]reflect.Type{},
+	}
+}
+
+// schema returns the schema of values of type t.
+func (b *schemaBuilder) schema(t reflect.Type) *Schema {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		nullable = true
+	}
+	s := b.nonNull(t)
+	switch t.Kind() {
+	case reflect.Slice, reflect.Map:
+		nullable = nullable || t.Elem().Kind() != reflect.Uint8
+	}
+	if !nullable {
+		return s
+	}
+	if s.Ref != "" {
+		// Siblings of $ref are ignored, so wrap the reference.
+		return &Schema{AllOf: []*Schema{s}, Nullable: true}
+	}
+	s.Nullable = true
+	return s
+}
+
+func (b *schemaBuilder) nonNull(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		return &Schema{}
+	case t.Implements(textMarshalerType), reflect.PointerTo(t).Implements(textMarshalerType):
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: b.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.object(t)
+		}
+		return b.named(t)
+	default:
+		// Interfaces can hold anything.
+		return &Schema{}
+	}
+}
+
+// named returns a reference to the component for a named struct type,
+// adding the component the first time the type is seen.
+func (b *schemaBuilder) named(t reflect.Type) *Schema {
+	if name, ok := b.names[t]; ok {
+		return ref(name)
+	}
+	name := t.Name()
+	if _, taken := b.types[name]; taken {
+		// Two packages use the same type name; qualify the second.
+		pkg := []rune(path.Base(t.PkgPath()))
+		pkg[0] = unicode.ToUpper(pkg[0])
+		name = string(pkg) + name
+	}
+	b.names[t] = name
+	b.types[name] = t
+
+	// Register before building so self-referencing types terminate.
+	s := &Schema{}
+	b.components[name] = s
+	*s = *b.object(t)
+	return ref(name)
+}
+
+// object returns the inline schema of a struct type. Fields encoding/json
+// always writes are required.
+func (b *schemaBuilder) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for _, f := range fields(t) {
+		name, omitempty, ok := jsonName(f)
+		if !ok {
+			continue
+		}
+		if _, dup := s.Properties[name]; dup {
+			// A shallower field hides deeper ones of the same name.
+			continue
+		}
+		s.Properties[name] = b.schema(f.Type)
+		if !omitempty {
+			s.Required = append(s.Required, name)
+		}
+	}
+	slices.Sort(s.Required)
+	return s
+}
+
+// body returns the request body schema for a request type, or nil if the
+// type has no JSON fields. No field is required: a missing field decodes to
+// its zero value and the type's Validate decides whether that is allowed.
+func (b *schemaBuilder) body(t reflect.Type) *Schema {
+	s := b.object(t)
+	if len(s.Properties) == 0 {
+		return nil
+	}
+	s.Required = nil
+	return s
+}
+
+// parameters returns the path and query parameters bound by a request type.
+func (b *schemaBuilder) parameters(t reflect.Type) []Parameter {
+	var params []Parameter
+	for _, f := range fields(t) {
+		if name := f.Tag.Get("param"); name != "" {
+			params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+	}
+	for _, f := range fields(t) {
+		if name := f.Tag.Get("query"); name != "" {
+			params = append(params, Parameter{Name: name, In: "query", Schema: b.schema(f.Type)})
+		}
+	}
+	return params
+}
+
+// fields returns the exported fields of a struct type with untagged
+// embedded structs flattened, as encoding/json and Echo's binder see them.
+// Shallower fields come first.
+func fields(t reflect.Type) []reflect.StructField {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var direct, embedded []reflect.StructField
+	for i := range t.NumField() {
+		f := t.Field(i)
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		tagName, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch {
+		case f.Anonymous && ft.Kind() == reflect.Struct && tagName == "":
+			embedded = append(embedded, fields(ft)...)
+		case f.IsExported():
+			direct = append(direct, f)
+		}
+	}
+	return append(direct, embedded...)
+}
+
+// jsonName returns the name encoding/json gives a field and whether it is
+// left out when empty. ok is false for fields encoding/json skips.
+func jsonName(f reflect.StructField) (name string, omitempty, ok bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = f.Name
+	}
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == "omitempty" || opt == "omitzero" {
+			omitempty = true
+		}
+	}
+	return name, omitempty, true
+}
@@ -0,0 +1,149 @@
+// Package pathguard checks agent pull request diffs against a repo's
+// protected paths: files such as CI workflows, deploy manifests and secrets
+// that agents must never change.
+//
+// Patterns use path.Match syntax with two additions:
+//   - A pattern without a slash matches any path segment, so ".env*" matches
+//     ".env" and "config/.env.local" alike.
+//   - "**" matches any number of directories, so "deploy/**/*.yaml" matches
+//     "deploy/prod/app.yaml".
+//
+// A pattern also matches everything below a directory it matches, so
+// ".github/workflows" protects every workflow file.
+package pathguard
+
+import (
+	"bufio"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Violation is a changed file that matches a protected path pattern.
+type Violation struct {
+	Path    string `json:"path"`
+	Pattern string `json:"pattern"`
+}
+
+// ValidatePattern reports whether a protected path pattern is well formed.
+func ValidatePattern(pattern string) error {
+	p := strings.Trim(pattern, "/")
+	if p == "" {
+		return fmt.Errorf("protected path %q is empty", pattern)
+	}
+	for _, seg := range strings.Split(p, "/") {
+		if _, err := path.Match(seg, ""); err != nil {
+			return fmt.Errorf("protected path %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Match reports whether file, a slash-separated path relative to the repo
+// root, matches the protected path pattern.
+func Match(pattern, file string) bool {
+	pattern = strings.Trim(pattern, "/")
+	if pattern == "" {
+		return false
+	}
+	segs := strings.Split(strings.Trim(file, "/"), "/")
+	if !strings.Contains(pattern, "/") && pattern != "**" {
+		for _, seg := range segs {
+			if ok, _ := path.Match(pattern, seg); ok {
+				return true
+			}
+		}
+		return false
+	}
+	return matchSegments(strings.Split(pattern, "/"), segs)
+}
+
+// matchSegments reports whether the pattern segments match the leading path
+// segments. Matching a leading directory matches everything below it.
+func matchSegments(pattern, segs []string) bool {
+	if len(pattern) == 0 {
+		return true
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segs); i++ {
+			if matchSegments(pattern[1:], segs[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segs) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segs[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], segs[1:])
+}
+
+// Check returns the files changed by a unified diff that match any of the
+// patterns, reporting each file once against the first pattern it matches.
+// Deleted and renamed files count by their old path as well as their new one.
+func Check(patterns []string, diff string) []Violation {
+	if len(patterns) == 0 {
+		return nil
+	}
+	var violations []Violation
+	for _, file := range ChangedFiles(diff) {
+		for _, p := range patterns {
+			if Match(p, file) {
+				violations = append(violations, Violation{Path: file, Pattern: p})
+				break
+			}
+		}
+	}
+	return violations
+}
+
+// ChangedFiles returns the paths a unified git diff touches, in the order
+// they first appear.
+func ChangedFiles(diff string) []string {
+	var files []string
+	seen := map[string]bool{}
+	add := func(file string) {
+		if file == "" || file == "/dev/null" || seen[file] {
+			return
+		}
+		seen[file] = true
+		files = append(files, file)
+	}
+
+	// File headers end at the first hunk; removed lines inside a hunk can
+	// look like "--- " headers.
+	inHeader := false
+	sc := bufio.NewScanner(strings.NewReader(diff))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			inHeader = true
+			// "a/<path> b/<path>" is only unambiguous when both paths are
+			// the same; renames are picked up from the lines that follow.
+			rest := strings.TrimPrefix(line, "diff --git ")
+			if n := len(rest); n%2 == 1 {
+				a, b := rest[:n/2], rest[n/2+1:]
+				if strings.HasPrefix(a, "a/") && strings.HasPrefix(b, "b/") && a[2:] == b[2:] {
+					add(a[2:])
+				}
+			}
+		case strings.HasPrefix(line, "@@"):
+			inHeader = false
+		case !inHeader:
+		case strings.HasPrefix(line, "--- "):
+			add(strings.TrimPrefix(strings.TrimPrefix(line, "--- "), "a/"))
+		case strings.HasPrefix(line, "+++ "):
+			add(strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/"))
+		case strings.HasPrefix(line, "rename from "):
+			add(strings.TrimPrefix(line, "rename from "))
+		case strings.HasPrefix(line, "rename to "):
+			add(strings.TrimPrefix(line, "rename to "))
+		}
+	}
+	return files
+}
@@ -0,0 +1,102 @@
+package pathguard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		file    string
+		want    bool
+	}{
+		{".github/workflows", ".github/workflows/ci.yml", true},
+		{".github/workflows/", ".github/workflows/ci.yml", true},
+		{"/.github/workflows", ".github/workflows/ci.yml", true},
+		{".github/workflows", ".github/dependabot.yml", false},
+		{".env", ".env", true},
+		{".env", "services/api/.env", true},
+		{".env*", "services/api/.env.production", true},
+		{".env", ".envrc.example", false},
+		{"*.pem", "certs/server.pem", true},
+		{"deploy/*.yaml", "deploy/app.yaml", true},
+		{"deploy/*.yaml", "deploy/prod/app.yaml", false},
+		{"deploy/**/*.yaml", "deploy/prod/app.yaml", true},
+		{"deploy/**/*.yaml", "deploy/app.yaml", true},
+		{"deploy/**", "deploy/prod/app.yaml", true},
+		{"deploy", "src/deploy.go", false},
+		{"**/secrets", "config/prod/secrets/key.json", true},
+		{"", "main.go", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.file, func(t *testing.T) {
+			assert.Equal(t, tt.want, Match(tt.pattern, tt.file))
+		})
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	require.NoError(t, ValidatePattern(".github/workflows"))
+	require.NoError(t, ValidatePattern("deploy/**/*.yaml"))
+	assert.Error(t, ValidatePattern(""))
+	assert.Error(t, ValidatePattern("/"))
+	assert.Error(t, ValidatePattern("config/[a-"))
+}
+
+const sampleDiff = `diff --git a/.github/workflows/ci.yml b/.github/workflows/ci.yml
+index 1111111..2222222 100644
+--- a/.github/workflows/ci.yml
++++ b/.github/workflows/ci.yml
+@@ -1,3 +1,3 @@
+ name: ci
+--- removed line that looks like a header
++on: push
+diff --git a/main.go b/main.go
+index 3333333..4444444 100644
+--- a/main.go
++++ b/main.go
+@@ -1 +1 @@
+-package old
++package main
+diff --git a/.env b/.env
+deleted file mode 100644
+index 5555555..0000000
+--- a/.env
++++ /dev/null
+@@ -1 +0,0 @@
+-SECRET=1
+diff --git a/deploy/app.yaml b/k8s/app.yaml
+similarity index 100%
+rename from deploy/app.yaml
+rename to k8s/app.yaml
+diff --git a/logo.png b/logo.png
+new file mode 100644
+index 0000000..6666666
+Binary files /dev/null and b/logo.png differ
+`
+
+func TestChangedFiles(t *testing.T) {
+	assert.Equal(t, []string{
+		".github/workflows/ci.yml",
+		"main.go",
+		".env",
+		"deploy/app.yaml",
+		"k8s/app.yaml",
+		"logo.png",
+	}, ChangedFiles(sampleDiff))
+}
+
+func TestCheck(t *testing.T) {
+	violations := Check([]string{".github/workflows", "deploy/**", ".env*"}, sampleDiff)
+	assert.Equal(t, []Violation{
+		{Path: ".github/workflows/ci.yml", Pattern: ".github/workflows"},
+		{Path: ".env", Pattern: ".env*"},
+		{Path: "deploy/app.yaml", Pattern: "deploy/**"},
+	}, violations)
+
+	assert.Empty(t, Check(nil, sampleDiff))
+	assert.Empty(t, Check([]string{"*.pem"}, sampleDiff))
+}
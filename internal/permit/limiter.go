@@ -0,0 +1,317 @@
+package permit
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is how long a permit is held without being renewed. Workers
+// renew permits while their agent runs; a permit whose worker disappeared
+// frees up once it expires.
+const DefaultTTL = 2 * time.Minute
+
+// DefaultRunTokens is how many tokens a run is expected to use when no
+// estimate is configured.
+const DefaultRunTokens = 100_000
+
+// Limits caps how many agents run at once and how quickly they start,
+// across every worker polling the server. Zero values are unlimited.
+type Limits struct {
+	MaxConcurrent   int                    // Permits held at once
+	TokensPerMinute int                    // Tokens granted per minute
+	RunTokens       int                    // Tokens each permit draws from the token buckets (0 = DefaultRunTokens)
+	Models          map[string]ModelLimits // Limits on the runs of a single model, on top of the global ones
+}
+
+// ModelLimits caps the runs of a single model. Zero values are unlimited.
+type ModelLimits struct {
+	MaxConcurrent   int
+	TokensPerMinute int
+}
+
+// Enabled reports whether any limit is set.
+func (l Limits) Enabled() bool {
+	if l.MaxConcurrent > 0 || l.TokensPerMinute > 0 {
+		return true
+	}
+	for _, m := range l.Models {
+		if m.MaxConcurrent > 0 || m.TokensPerMinute > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (l Limits) runTokens() int {
+	if l.RunTokens > 0 {
+		return l.RunTokens
+	}
+	return DefaultRunTokens
+}
+
+// ParseModelLimits parses per-model limits given as a comma-separated list
+// of MODEL:MAX_CONCURRENT[:TOKENS_PER_MINUTE] entries, e.g.
+// "opus:2:400000,sonnet:8".
+func ParseModelLimits(s string) (map[string]ModelLimits, error) {
+	limits := make(map[string]ModelLimits)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid model limit %q: want MODEL:MAX_CONCURRENT[:TOKENS_PER_MINUTE]", entry)
+		}
+		var m ModelLimits
+		var err error
+		if m.MaxConcurrent, err = strconv.Atoi(parts[1]); err != nil || m.MaxConcurrent < 0 {
+			return nil, fmt.Errorf("invalid model limit %q: max concurrent must be a non-negative integer", entry)
+		}
+		if len(parts) == 3 {
+			if m.TokensPerMinute, err = strconv.Atoi(parts[2]); err != nil || m.TokensPerMinute < 0 {
+				return nil, fmt.Errorf("invalid model limit %q: tokens per minute must be a non-negative integer", entry)
+			}
+		}
+		limits[parts[0]] = m
+	}
+	return limits, nil
+}
+
+// Permit allows a worker to run one agent. It must be renewed before it
+// expires and released when the agent finishes.
+type Permit struct {
+	ID        string    `json:"id"`
+	WorkerID  string    `json:"worker_id,omitempty"`
+	TaskID    string    `json:"task_id,omitempty"`
+	Model     string    `json:"model,omitempty"`
+	GrantedAt time.Time `json:"granted_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Request describes the agent run a permit is requested for.
+type Request struct {
+	WorkerID string
+	TaskID   string
+	Model    string
+}
+
+// Denial explains why a permit was not granted.
+type Denial struct {
+	Reason     string        // The limit that was reached
+	RetryAfter time.Duration // How long to wait before asking again
+}
+
+// Limiter hands out permits within its limits. It keeps its state in
+// memory, so replicas serving agents enforce their limits separately. It is
+// safe for concurrent use.
+type Limiter struct {
+	mu      sync.Mutex
+	limits  Limits
+	ttl     time.Duration
+	now     func() time.Time
+	permits map[string]*Permit
+	global  *bucket
+	models  map[string]*bucket
+}
+
+// NewLimiter creates a limiter enforcing limits. Permits expire ttl after
+// they were granted or last renewed (DefaultTTL when zero).
+func NewLimiter(limits Limits, ttl time.Duration) *Limiter {
+	return newLimiter(limits, ttl, time.Now)
+}
+
+func newLimiter(limits Limits, ttl time.Duration, now func() time.Time) *Limiter {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	l := &Limiter{
+		limits:  limits,
+		ttl:     ttl,
+		now:     now,
+		permits: make(map[string]*Permit),
+		models:  make(map[string]*bucket),
+	}
+	if limits.TokensPerMinute > 0 {
+		l.global = newBucket(limits.TokensPerMinute, l.now())
+	}
+	for model, m := range limits.Models {
+		if m.TokensPerMinute > 0 {
+			l.models[model] = newBucket(m.TokensPerMinute, l.now())
+		}
+	}
+	return l
+}
+
+// TTL returns how long a permit lasts without being renewed.
+func (l *Limiter) TTL() time.Duration {
+	return l.ttl
+}
+
+// Acquire grants a permit for req, or returns a denial naming the limit
+// that was reached and when to try again.
+func (l *Limiter) Acquire(req Request) (*Permit, *Denial) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.expire(now)
+
+	if l.limits.MaxConcurrent > 0 && len(l.permits) >= l.limits.MaxConcurrent {
+		return nil, &Denial{Reason: "max concurrent agents reached", RetryAfter: l.nextExpiry(now, "")}
+	}
+	modelLimits := l.limits.Models[req.Model]
+	if modelLimits.MaxConcurrent > 0 && l.countModel(req.Model) >= modelLimits.MaxConcurrent {
+		return nil, &Denial{Reason: fmt.Sprintf("max concurrent %s agents reached", req.Model), RetryAfter: l.nextExpiry(now, req.Model)}
+	}
+
+	tokens := l.limits.runTokens()
+	if l.global != nil {
+		if wait := l.global.wait(now, tokens); wait > 0 {
+			return nil, &Denial{Reason: "tokens per minute limit reached", RetryAfter: wait}
+		}
+	}
+	modelBucket := l.models[req.Model]
+	if modelBucket != nil {
+		if wait := modelBucket.wait(now, tokens); wait > 0 {
+			return nil, &Denial{Reason: fmt.Sprintf("%s tokens per minute limit reached", req.Model), RetryAfter: wait}
+		}
+	}
+	if l.global != nil {
+		l.global.take(tokens)
+	}
+	if modelBucket != nil {
+		modelBucket.take(tokens)
+	}
+
+	p := &Permit{
+		ID:        newPermitID(),
+		WorkerID:  req.WorkerID,
+		TaskID:    req.TaskID,
+		Model:     req.Model,
+		GrantedAt: now,
+		ExpiresAt: now.Add(l.ttl),
+	}
+	l.permits[p.ID] = p
+	copied := *p
+	return &copied, nil
+}
+
+// Renew extends a permit's expiry. It returns false when the permit is
+// unknown or already expired.
+func (l *Limiter) Renew(id string) (*Permit, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.expire(now)
+	p, ok := l.permits[id]
+	if !ok {
+		return nil, false
+	}
+	p.ExpiresAt = now.Add(l.ttl)
+	copied := *p
+	return &copied, true
+}
+
+// Release returns a permit. Tokens it drew are not given back; the buckets
+// refill with time. It returns false when the permit is unknown or already
+// expired.
+func (l *Limiter) Release(id string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.expire(l.now())
+	if _, ok := l.permits[id]; !ok {
+		return false
+	}
+	delete(l.permits, id)
+	return true
+}
+
+// Held returns how many permits are held.
+func (l *Limiter) Held() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.expire(l.now())
+	return len(l.permits)
+}
+
+func (l *Limiter) expire(now time.Time) {
+	for id, p := range l.permits {
+		if !now.Before(p.ExpiresAt) {
+			delete(l.permits, id)
+		}
+	}
+}
+
+func (l *Limiter) countModel(model string) int {
+	n := 0
+	for _, p := range l.permits {
+		if p.Model == model {
+			n++
+		}
+	}
+	return n
+}
+
+// nextExpiry returns how long until the earliest permit expires, limited to
+// permits for model when it isn't empty. Permits are usually released well
+// before then, so it is capped at a few seconds.
+func (l *Limiter) nextExpiry(now time.Time, model string) time.Duration {
+	wait := l.ttl
+	for _, p := range l.permits {
+		if model != "" && p.Model != model {
+			continue
+		}
+		wait = min(wait, p.ExpiresAt.Sub(now))
+	}
+	return min(max(wait, time.Second), maxConcurrencyRetry)
+}
+
+// maxConcurrencyRetry is the longest a worker is asked to wait for a
+// concurrency slot before asking again.
+const maxConcurrencyRetry = 5 * time.Second
+
+// bucket is a token bucket holding up to a minute's worth of tokens, refilled
+// continuously.
+type bucket struct {
+	perMinute float64
+	tokens    float64
+	updated   time.Time
+}
+
+func newBucket(perMinute int, now time.Time) *bucket {
+	return &bucket{perMinute: float64(perMinute), tokens: float64(perMinute), updated: now}
+}
+
+// wait refills the bucket and returns how long until it holds tokens, or 0
+// when it already does. A run larger than the bucket only needs it full.
+func (b *bucket) wait(now time.Time, tokens int) time.Duration {
+	b.tokens = math.Min(b.perMinute, b.tokens+now.Sub(b.updated).Minutes()*b.perMinute)
+	b.updated = now
+	need := math.Min(float64(tokens), b.perMinute)
+	if b.tokens >= need {
+		return 0
+	}
+	return time.Duration((need - b.tokens) / b.perMinute * float64(time.Minute))
+}
+
+// take draws tokens from the bucket, which may leave it in debt for runs
+// larger than the bucket.
+func (b *bucket) take(tokens int) {
+	b.tokens -= float64(tokens)
+}
+
+func newPermitID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return "pmt_" + hex.EncodeToString(b)
+}
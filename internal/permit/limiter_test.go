@@ -0,0 +1,160 @@
+package permit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLimiter(limits Limits) (*Limiter, *time.Time) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	return newLimiter(limits, time.Minute, func() time.Time { return now }), &now
+}
+
+func TestLimiter_MaxConcurrent(t *testing.T) {
+	l, _ := newTestLimiter(Limits{MaxConcurrent: 2})
+
+	p1, denial := l.Acquire(Request{WorkerID: "w1", Model: "sonnet"})
+	require.Nil(t, denial)
+	_, denial = l.Acquire(Request{WorkerID: "w2", Model: "opus"})
+	require.Nil(t, denial)
+
+	_, denial = l.Acquire(Request{WorkerID: "w3", Model: "sonnet"})
+	require.NotNil(t, denial)
+	assert.Equal(t, "max concurrent agents reached", denial.Reason)
+	assert.Greater(t, denial.RetryAfter, time.Duration(0))
+
+	assert.True(t, l.Release(p1.ID))
+	_, denial = l.Acquire(Request{WorkerID: "w3", Model: "sonnet"})
+	assert.Nil(t, denial)
+}
+
+func TestLimiter_ModelMaxConcurrent(t *testing.T) {
+	l, _ := newTestLimiter(Limits{Models: map[string]ModelLimits{"opus": {MaxConcurrent: 1}}})
+
+	_, denial := l.Acquire(Request{Model: "opus"})
+	require.Nil(t, denial)
+
+	_, denial = l.Acquire(Request{Model: "opus"})
+	require.NotNil(t, denial)
+	assert.Equal(t, "max concurrent opus agents reached", denial.Reason)
+
+	_, denial = l.Acquire(Request{Model: "sonnet"})
+	assert.Nil(t, denial, "other models are not limited")
+}
+
+func TestLimiter_TokensPerMinute(t *testing.T) {
+	l, now := newTestLimiter(Limits{TokensPerMinute: 200_000, RunTokens: 100_000})
+
+	for range 2 {
+		_, denial := l.Acquire(Request{Model: "sonnet"})
+		require.Nil(t, denial)
+	}
+	_, denial := l.Acquire(Request{Model: "sonnet"})
+	require.NotNil(t, denial)
+	assert.Equal(t, "tokens per minute limit reached", denial.Reason)
+	assert.Equal(t, 30*time.Second, denial.RetryAfter)
+
+	*now = now.Add(30 * time.Second)
+	_, denial = l.Acquire(Request{Model: "sonnet"})
+	assert.Nil(t, denial)
+}
+
+func TestLimiter_ModelTokensPerMinute(t *testing.T) {
+	l, _ := newTestLimiter(Limits{
+		RunTokens: 100_000,
+		Models:    map[string]ModelLimits{"opus": {TokensPerMinute: 100_000}},
+	})
+
+	_, denial := l.Acquire(Request{Model: "opus"})
+	require.Nil(t, denial)
+	_, denial = l.Acquire(Request{Model: "opus"})
+	require.NotNil(t, denial)
+	assert.Equal(t, "opus tokens per minute limit reached", denial.Reason)
+	assert.Equal(t, time.Minute, denial.RetryAfter)
+}
+
+func TestLimiter_RunLargerThanBucket(t *testing.T) {
+	l, now := newTestLimiter(Limits{TokensPerMinute: 50_000, RunTokens: 100_000})
+
+	_, denial := l.Acquire(Request{})
+	require.Nil(t, denial, "a full bucket grants a run larger than it")
+
+	_, denial = l.Acquire(Request{})
+	require.NotNil(t, denial)
+	assert.Equal(t, 2*time.Minute, denial.RetryAfter, "the run's debt is paid off first")
+
+	*now = now.Add(2 * time.Minute)
+	_, denial = l.Acquire(Request{})
+	assert.Nil(t, denial)
+}
+
+func TestLimiter_RenewAndExpire(t *testing.T) {
+	l, now := newTestLimiter(Limits{MaxConcurrent: 1})
+
+	p, denial := l.Acquire(Request{WorkerID: "w1"})
+	require.Nil(t, denial)
+	assert.Equal(t, now.Add(time.Minute), p.ExpiresAt)
+
+	*now = now.Add(45 * time.Second)
+	renewed, ok := l.Renew(p.ID)
+	require.True(t, ok)
+	assert.Equal(t, now.Add(time.Minute), renewed.ExpiresAt)
+
+	*now = now.Add(45 * time.Second)
+	assert.Equal(t, 1, l.Held(), "renewed permit is still held")
+
+	*now = now.Add(time.Minute)
+	assert.Equal(t, 0, l.Held())
+	_, ok = l.Renew(p.ID)
+	assert.False(t, ok)
+	assert.False(t, l.Release(p.ID))
+
+	_, denial = l.Acquire(Request{WorkerID: "w2"})
+	assert.Nil(t, denial, "expired permit frees its slot")
+}
+
+func TestLimits_Enabled(t *testing.T) {
+	assert.False(t, Limits{}.Enabled())
+	assert.False(t, Limits{RunTokens: 1000, Models: map[string]ModelLimits{"opus": {}}}.Enabled())
+	assert.True(t, Limits{MaxConcurrent: 1}.Enabled())
+	assert.True(t, Limits{Models: map[string]ModelLimits{"opus": {TokensPerMinute: 1}}}.Enabled())
+}
+
+func TestParseModelLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    map[string]ModelLimits
+		wantErr bool
+	}{
+		{name: "empty", in: "", want: map[string]ModelLimits{}},
+		{
+			name: "concurrency and tokens",
+			in:   "opus:2:400000, sonnet:8",
+			want: map[string]ModelLimits{
+				"opus":   {MaxConcurrent: 2, TokensPerMinute: 400000},
+				"sonnet": {MaxConcurrent: 8},
+			},
+		},
+		{name: "tokens only", in: "opus:0:100000", want: map[string]ModelLimits{"opus": {TokensPerMinute: 100000}}},
+		{name: "missing concurrency", in: "opus", wantErr: true},
+		{name: "missing model", in: ":2", wantErr: true},
+		{name: "negative", in: "opus:-1", wantErr: true},
+		{name: "not a number", in: "opus:2:lots", wantErr: true},
+		{name: "too many parts", in: "opus:1:2:3", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseModelLimits(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
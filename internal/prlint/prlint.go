@@ -0,0 +1,274 @@
+// Package prlint validates an agent's pull request against a repo's
+// conventions when the agent reports success: PR title prefix and format, a
+// reference to the task in the PR body, branch naming, commit count and
+// Conventional Commits subjects. Each convention is a Validator; the ones a
+// repo enables are built from its Config. The conventions are also described
+// to the agent up front, and a PR title that only breaks them in a way that
+// can be fixed mechanically is fixed without the agent.
+package prlint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Rule names.
+const (
+	RuleTitlePrefix         = "title_prefix"
+	RuleTitlePattern        = "title_pattern"
+	RuleTaskID              = "task_id"
+	RuleBranchPattern       = "branch_pattern"
+	RuleMaxCommits          = "max_commits"
+	RuleConventionalCommits = "conventional_commits"
+)
+
+// Config selects the validations a repo runs on agent pull requests. The zero
+// value enables none.
+type Config struct {
+	// TitlePrefix is text the PR title must start with, such as a ticket
+	// prefix like "PROJ-123: ".
+	TitlePrefix string `json:"title_prefix,omitempty"`
+	// TitlePattern is a regular expression the PR title must match.
+	TitlePattern string `json:"title_pattern,omitempty"`
+	// RequireTaskID requires the PR body to mention the task's ID.
+	RequireTaskID bool `json:"require_task_id,omitempty"`
+	// BranchPattern is a regular expression the PR head branch must match.
+	BranchPattern string `json:"branch_pattern,omitempty"`
+	// MaxCommits caps the number of commits on the PR. Zero means no limit.
+	MaxCommits int `json:"max_commits,omitempty"`
+	// ConventionalCommits requires every commit subject to follow the
+	// Conventional Commits format, e.g. "fix(api): handle empty body".
+	ConventionalCommits bool `json:"conventional_commits,omitempty"`
+}
+
+// Validate reports whether the config's patterns compile and its limits are
+// in range.
+func (c Config) Validate() error {
+	if strings.ContainsAny(c.TitlePrefix, "\r\n") {
+		return fmt.Errorf("title prefix must be a single line")
+	}
+	if _, err := regexp.Compile(c.TitlePattern); err != nil {
+		return fmt.Errorf("title pattern: %w", err)
+	}
+	if _, err := regexp.Compile(c.BranchPattern); err != nil {
+		return fmt.Errorf("branch pattern: %w", err)
+	}
+	if c.MaxCommits < 0 {
+		return fmt.Errorf("max commits must not be negative")
+	}
+	return nil
+}
+
+// Empty reports whether the config enables no validations.
+func (c Config) Empty() bool {
+	return c == Config{}
+}
+
+// PullRequest is the part of a pull request the validators look at.
+type PullRequest struct {
+	TaskID string
+	Title  string
+	Body   string
+	Branch string
+	// Commits are the PR's commit messages, oldest first.
+	Commits []string
+}
+
+// Violation is a convention the pull request breaks.
+type Violation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Validator checks a pull request against one convention and returns a
+// message for each violation.
+type Validator struct {
+	Rule  string
+	Check func(pr *PullRequest) []string
+}
+
+// Validators returns the validators the config enables. Patterns that do not
+// compile are skipped; Validate rejects them before they are stored.
+func (c Config) Validators() []Validator {
+	var vs []Validator
+	if c.TitlePrefix != "" {
+		vs = append(vs, Validator{Rule: RuleTitlePrefix, Check: hasTitlePrefix(c.TitlePrefix)})
+	}
+	if c.TitlePattern != "" {
+		if re, err := regexp.Compile(c.TitlePattern); err == nil {
+			vs = append(vs, Validator{Rule: RuleTitlePattern, Check: matchTitle(re)})
+		}
+	}
+	if c.RequireTaskID {
+		vs = append(vs, Validator{Rule: RuleTaskID, Check: mentionsTaskID})
+	}
+	if c.BranchPattern != "" {
+		if re, err := regexp.Compile(c.BranchPattern); err == nil {
+			vs = append(vs, Validator{Rule: RuleBranchPattern, Check: matchBranch(re)})
+		}
+	}
+	if c.MaxCommits > 0 {
+		vs = append(vs, Validator{Rule: RuleMaxCommits, Check: maxCommits(c.MaxCommits)})
+	}
+	if c.ConventionalCommits {
+		vs = append(vs, Validator{Rule: RuleConventionalCommits, Check: conventionalCommits})
+	}
+	return vs
+}
+
+// Guidelines describes the conventions the config enforces, one per line, for
+// the agent's prompt. It returns "" when the config enforces none the agent
+// controls; the branch name is chosen for the agent.
+func (c Config) Guidelines(taskID string) string {
+	var lines []string
+	if c.TitlePrefix != "" {
+		lines = append(lines, fmt.Sprintf("- The PR title must start with %q.", c.TitlePrefix))
+	}
+	if c.TitlePattern != "" {
+		lines = append(lines, fmt.Sprintf("- The PR title must match the regular expression `%s`.", c.TitlePattern))
+	}
+	if c.RequireTaskID && taskID != "" {
+		lines = append(lines, fmt.Sprintf("- The PR body must mention the task ID %s.", taskID))
+	}
+	if c.MaxCommits > 0 {
+		lines = append(lines, fmt.Sprintf("- Make at most %d commit(s); squash them if needed.", c.MaxCommits))
+	}
+	if c.ConventionalCommits {
+		lines = append(lines, `- Every commit subject must follow Conventional Commits, e.g. "fix(api): handle empty body".`)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FixTitle returns a title for the pull request that meets the config's title
+// rules when its own title doesn't: its title or, failing that, the subject
+// of one of its commits, with the title prefix added where missing. It
+// returns false when the title already meets them or no fix does.
+func (c Config) FixTitle(pr *PullRequest) (string, bool) {
+	if c.titleOK(pr.Title) {
+		return "", false
+	}
+	candidates := []string{strings.TrimSpace(pr.Title)}
+	for _, m := range pr.Commits {
+		subject, _, _ := strings.Cut(m, "\n")
+		candidates = append(candidates, strings.TrimSpace(subject))
+	}
+	for _, title := range candidates {
+		if title == "" || strings.HasPrefix(title, "Merge ") {
+			continue
+		}
+		if !strings.HasPrefix(title, c.TitlePrefix) {
+			title = c.TitlePrefix + title
+		}
+		if c.titleOK(title) {
+			return title, true
+		}
+	}
+	return "", false
+}
+
+// titleOK reports whether title meets the config's title rules.
+func (c Config) titleOK(title string) bool {
+	pr := &PullRequest{Title: title}
+	for _, v := range c.Validators() {
+		if (v.Rule == RuleTitlePrefix || v.Rule == RuleTitlePattern) && len(v.Check(pr)) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Check runs the validators over the pull request in order.
+func Check(validators []Validator, pr *PullRequest) []Violation {
+	var violations []Violation
+	for _, v := range validators {
+		for _, msg := range v.Check(pr) {
+			violations = append(violations, Violation{Rule: v.Rule, Message: msg})
+		}
+	}
+	return violations
+}
+
+// Rules returns the distinct rules the violations break, sorted.
+func Rules(violations []Violation) []string {
+	seen := map[string]bool{}
+	var rules []string
+	for _, v := range violations {
+		if !seen[v.Rule] {
+			seen[v.Rule] = true
+			rules = append(rules, v.Rule)
+		}
+	}
+	sort.Strings(rules)
+	return rules
+}
+
+// Feedback formats the violations as instructions for the agent's retry.
+func Feedback(violations []Violation) string {
+	var b strings.Builder
+	b.WriteString("The pull request does not meet the repository's conventions. Fix the following, then update the pull request:\n")
+	for _, v := range violations {
+		fmt.Fprintf(&b, "\n- %s", v.Message)
+	}
+	return b.String()
+}
+
+func hasTitlePrefix(prefix string) func(pr *PullRequest) []string {
+	return func(pr *PullRequest) []string {
+		if strings.HasPrefix(pr.Title, prefix) {
+			return nil
+		}
+		return []string{fmt.Sprintf("PR title %q must start with %q", pr.Title, prefix)}
+	}
+}
+
+func matchTitle(re *regexp.Regexp) func(pr *PullRequest) []string {
+	return func(pr *PullRequest) []string {
+		if re.MatchString(pr.Title) {
+			return nil
+		}
+		return []string{fmt.Sprintf("PR title %q must match the pattern %s", pr.Title, re)}
+	}
+}
+
+func mentionsTaskID(pr *PullRequest) []string {
+	if pr.TaskID == "" || strings.Contains(pr.Body, pr.TaskID) {
+		return nil
+	}
+	return []string{fmt.Sprintf("PR body must reference the task ID %s", pr.TaskID)}
+}
+
+func matchBranch(re *regexp.Regexp) func(pr *PullRequest) []string {
+	return func(pr *PullRequest) []string {
+		if re.MatchString(pr.Branch) {
+			return nil
+		}
+		return []string{fmt.Sprintf("Branch %q must match the pattern %s", pr.Branch, re)}
+	}
+}
+
+func maxCommits(limit int) func(pr *PullRequest) []string {
+	return func(pr *PullRequest) []string {
+		if len(pr.Commits) <= limit {
+			return nil
+		}
+		return []string{fmt.Sprintf("PR has %d commits; squash them to at most %d", len(pr.Commits), limit)}
+	}
+}
+
+// conventionalSubject matches "type(scope)!: description".
+var conventionalSubject = regexp.MustCompile(`^[a-z]+(\([^()\s]+\))?!?: \S`)
+
+func conventionalCommits(pr *PullRequest) []string {
+	var msgs []string
+	for _, c := range pr.Commits {
+		subject, _, _ := strings.Cut(c, "\n")
+		subject = strings.TrimSpace(subject)
+		if strings.HasPrefix(subject, "Merge ") || conventionalSubject.MatchString(subject) {
+			continue
+		}
+		msgs = append(msgs, fmt.Sprintf("Commit subject %q must follow Conventional Commits, e.g. \"fix(api): handle empty body\"", subject))
+	}
+	return msgs
+}
@@ -0,0 +1,137 @@
+package prlint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"empty", Config{}, false},
+		{"all rules", Config{TitlePrefix: "PROJ-1: ", TitlePattern: `^\[.+\] `, RequireTaskID: true, BranchPattern: `^verve/`, MaxCommits: 3, ConventionalCommits: true}, false},
+		{"bad title pattern", Config{TitlePattern: `([a-`}, true},
+		{"bad branch pattern", Config{BranchPattern: `*`}, true},
+		{"multi-line title prefix", Config{TitlePrefix: "PROJ\n"}, true},
+		{"negative max commits", Config{MaxCommits: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	cfg := Config{
+		TitlePattern:        `^(feat|fix): `,
+		RequireTaskID:       true,
+		BranchPattern:       `^verve/task-\d+$`,
+		MaxCommits:          2,
+		ConventionalCommits: true,
+	}
+
+	pr := &PullRequest{
+		TaskID:  "tsk_123",
+		Title:   "fix: handle empty body",
+		Body:    "Resolves tsk_123",
+		Branch:  "verve/task-7",
+		Commits: []string{"fix(api): handle empty body\n\nDetails", "Merge branch 'main' into verve/task-7"},
+	}
+	assert.Empty(t, Check(cfg.Validators(), pr))
+
+	pr = &PullRequest{
+		TaskID:  "tsk_123",
+		Title:   "Handle empty body",
+		Body:    "Fixes the bug",
+		Branch:  "feature/empty-body",
+		Commits: []string{"fix: one", "wip", "Update handler"},
+	}
+	violations := Check(cfg.Validators(), pr)
+	var rules []string
+	for _, v := range violations {
+		rules = append(rules, v.Rule)
+	}
+	assert.Equal(t, []string{
+		RuleTitlePattern,
+		RuleTaskID,
+		RuleBranchPattern,
+		RuleMaxCommits,
+		RuleConventionalCommits,
+		RuleConventionalCommits,
+	}, rules)
+	assert.Equal(t, []string{RuleBranchPattern, RuleConventionalCommits, RuleMaxCommits, RuleTaskID, RuleTitlePattern}, Rules(violations))
+	assert.Contains(t, Feedback(violations), "PR body must reference the task ID tsk_123")
+	assert.Contains(t, Feedback(violations), `Commit subject "wip" must follow Conventional Commits`)
+}
+
+func TestCheck_NoValidators(t *testing.T) {
+	assert.Empty(t, Config{}.Validators())
+	assert.True(t, Config{}.Empty())
+	assert.Empty(t, Check(nil, &PullRequest{Title: "anything"}))
+}
+
+func TestCheck_TitlePrefix(t *testing.T) {
+	validators := Config{TitlePrefix: "[api] "}.Validators()
+	assert.Empty(t, Check(validators, &PullRequest{Title: "[api] Handle empty body"}))
+
+	violations := Check(validators, &PullRequest{Title: "Handle empty body"})
+	assert.Equal(t, []Violation{{Rule: RuleTitlePrefix, Message: `PR title "Handle empty body" must start with "[api] "`}}, violations)
+}
+
+func TestConfig_Guidelines(t *testing.T) {
+	assert.Empty(t, Config{}.Guidelines("tsk_123"))
+	assert.Empty(t, Config{BranchPattern: `^verve/`}.Guidelines("tsk_123"), "the agent doesn't choose its branch")
+
+	cfg := Config{TitlePrefix: "PROJ: ", TitlePattern: `^PROJ: [A-Z]`, RequireTaskID: true, MaxCommits: 1, ConventionalCommits: true}
+	assert.Equal(t, `- The PR title must start with "PROJ: ".
+- The PR title must match the regular expression `+"`^PROJ: [A-Z]`"+`.
+- The PR body must mention the task ID tsk_123.
+- Make at most 1 commit(s); squash them if needed.
+- Every commit subject must follow Conventional Commits, e.g. "fix(api): handle empty body".`, cfg.Guidelines("tsk_123"))
+}
+
+func TestConfig_FixTitle(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		pr      PullRequest
+		want    string
+		wantFix bool
+	}{
+		{"no title rules", Config{ConventionalCommits: true}, PullRequest{Title: "Handle empty body"}, "", false},
+		{"already valid", Config{TitlePrefix: "PROJ: "}, PullRequest{Title: "PROJ: Handle empty body"}, "", false},
+		{"adds prefix", Config{TitlePrefix: "PROJ: "}, PullRequest{Title: "Handle empty body"}, "PROJ: Handle empty body", true},
+		{
+			"uses commit subject",
+			Config{TitlePattern: `^(feat|fix)(\(.+\))?: `},
+			PullRequest{Title: "Handle empty body", Commits: []string{"Merge branch 'main'", "fix(api): handle empty body\n\nDetails"}},
+			"fix(api): handle empty body",
+			true,
+		},
+		{
+			"prefix and commit subject",
+			Config{TitlePrefix: "[api] ", TitlePattern: `^\[api\] fix: `},
+			PullRequest{Title: "Handle empty body", Commits: []string{"fix: handle empty body"}},
+			"[api] fix: handle empty body",
+			true,
+		},
+		{"no fix", Config{TitlePattern: `^fix: `}, PullRequest{Title: "Handle empty body", Commits: []string{"wip"}}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.cfg.FixTitle(&tt.pr)
+			assert.Equal(t, tt.wantFix, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
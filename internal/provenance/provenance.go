@@ -0,0 +1,262 @@
+// Package provenance scans agent pull request diffs for code that may have
+// been copied verbatim from elsewhere: license headers and unusually large
+// blocks of added code. Findings are advisory; they require a human to
+// acknowledge them before the PR is merged.
+package provenance
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StatusContext is the GitHub commit status context used to gate merging on
+// acknowledgment of provenance findings.
+const StatusContext = "verve/provenance"
+
+// Defaults for the provenance scanner.
+const (
+	DefaultLargeBlockLines = 200
+	DefaultCommandTimeout  = time.Minute
+)
+
+// Finding kinds.
+const (
+	KindLicenseHeader = "license_header"
+	KindLargeBlock    = "large_block"
+	KindExternal      = "external"
+)
+
+// Finding is a suspicious addition in a PR diff.
+type Finding struct {
+	Kind   string `json:"kind"`
+	Path   string `json:"path"`
+	Line   int    `json:"line,omitempty"`  // First added line in the new file
+	Lines  int    `json:"lines,omitempty"` // Number of added lines covered
+	Detail string `json:"detail"`
+}
+
+// Config configures a Scanner.
+type Config struct {
+	// LargeBlockLines is the number of contiguous added lines in a single
+	// hunk at which the block is flagged. Zero uses DefaultLargeBlockLines;
+	// negative disables the check.
+	LargeBlockLines int
+	// Command is an optional external scanner run with "sh -c". It receives
+	// the unified diff on stdin and must print a JSON array of findings on
+	// stdout.
+	Command string
+	// CommandTimeout bounds the external scanner's run time. Zero uses
+	// DefaultCommandTimeout. The scan runs while the agent's completion
+	// request is open, so this should stay well under the request timeout.
+	CommandTimeout time.Duration
+}
+
+// Scanner runs the built-in heuristics and the optional external scanner
+// against a PR diff.
+type Scanner struct {
+	cfg Config
+}
+
+// NewScanner creates a new Scanner.
+func NewScanner(cfg Config) *Scanner {
+	if cfg.LargeBlockLines == 0 {
+		cfg.LargeBlockLines = DefaultLargeBlockLines
+	}
+	if cfg.CommandTimeout <= 0 {
+		cfg.CommandTimeout = DefaultCommandTimeout
+	}
+	return &Scanner{cfg: cfg}
+}
+
+// Scan returns the findings for a unified diff. Findings from the built-in
+// heuristics are returned even when the external scanner fails.
+func (s *Scanner) Scan(ctx context.Context, diff string) ([]Finding, error) {
+	findings := ScanDiff(diff, s.cfg.LargeBlockLines)
+	if s.cfg.Command == "" {
+		return findings, nil
+	}
+	external, err := s.runCommand(ctx, diff)
+	if err != nil {
+		return findings, fmt.Errorf("external scanner: %w", err)
+	}
+	return append(findings, external...), nil
+}
+
+func (s *Scanner) runCommand(ctx context.Context, diff string) ([]Finding, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.CommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", s.cfg.Command)
+	cmd.Stdin = strings.NewReader(diff)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+
+	out := bytes.TrimSpace(stdout.Bytes())
+	if len(out) == 0 {
+		return nil, nil
+	}
+	var findings []Finding
+	if err := json.Unmarshal(out, &findings); err != nil {
+		return nil, fmt.Errorf("decode findings: %w", err)
+	}
+	for i := range findings {
+		if findings[i].Kind == "" {
+			findings[i].Kind = KindExternal
+		}
+	}
+	return findings, nil
+}
+
+// licensePatterns match lines that commonly appear in license headers. Each
+// pattern is reported at most once per file.
+var licensePatterns = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	{"SPDX license identifier", regexp.MustCompile(`SPDX-License-Identifier:`)},
+	{"copyright notice", regexp.MustCompile(`(?i)copyright\s+(\(c\)|©|\d{4})`)},
+	{"license grant", regexp.MustCompile(`(?i)licensed under the`)},
+	{"GPL license text", regexp.MustCompile(`(?i)GNU (Affero |Lesser )?General Public License`)},
+	{"Apache license text", regexp.MustCompile(`(?i)Apache License,? Version`)},
+	{"Mozilla license text", regexp.MustCompile(`(?i)Mozilla Public License`)},
+	{"MIT license text", regexp.MustCompile(`(?i)Permission is hereby granted, free of charge`)},
+	{"BSD license text", regexp.MustCompile(`(?i)Redistribution and use in source and binary forms`)},
+}
+
+// generatedFiles are files that legitimately contain large machine-written
+// additions and are skipped by the large block check.
+var generatedFiles = map[string]bool{
+	"package-lock.json": true,
+	"pnpm-lock.yaml":    true,
+	"yarn.lock":         true,
+	"go.sum":            true,
+	"Cargo.lock":        true,
+	"poetry.lock":       true,
+	"Gemfile.lock":      true,
+	"composer.lock":     true,
+}
+
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// ScanDiff applies the built-in heuristics to a unified diff: added lines
+// matching common license header text, and runs of at least largeBlockLines
+// contiguous added lines (negative disables the run check). Deleted files and
+// removed lines are ignored.
+func ScanDiff(diff string, largeBlockLines int) []Finding {
+	var (
+		findings []Finding
+		file     string
+		seen     map[string]bool // license patterns already reported for file
+		newLine  int             // line number in the new file of the next line
+		runStart int
+		runLen   int
+	)
+
+	flushRun := func() {
+		if largeBlockLines > 0 && runLen >= largeBlockLines && !generatedFiles[path.Base(file)] {
+			findings = append(findings, Finding{
+				Kind:   KindLargeBlock,
+				Path:   file,
+				Line:   runStart,
+				Lines:  runLen,
+				Detail: fmt.Sprintf("%d contiguous added lines", runLen),
+			})
+		}
+		runLen = 0
+	}
+
+	sc := bufio.NewScanner(strings.NewReader(diff))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flushRun()
+			file = ""
+			seen = map[string]bool{}
+		case strings.HasPrefix(line, "+++ "):
+			file = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+			if file == "/dev/null" {
+				file = ""
+			}
+		case strings.HasPrefix(line, "--- "):
+			// Old file name; only the new one is reported.
+		case strings.HasPrefix(line, "@@"):
+			flushRun()
+			if m := hunkHeader.FindStringSubmatch(line); m != nil {
+				newLine, _ = strconv.Atoi(m[1])
+			}
+		case strings.HasPrefix(line, "+"):
+			if file == "" {
+				continue
+			}
+			if runLen == 0 {
+				runStart = newLine
+			}
+			runLen++
+			text := line[1:]
+			for _, p := range licensePatterns {
+				if seen[p.name] || !p.pattern.MatchString(text) {
+					continue
+				}
+				seen[p.name] = true
+				findings = append(findings, Finding{
+					Kind:   KindLicenseHeader,
+					Path:   file,
+					Line:   newLine,
+					Detail: fmt.Sprintf("%s: %s", p.name, truncate(strings.TrimSpace(text), 120)),
+				})
+			}
+			newLine++
+		case strings.HasPrefix(line, "-"):
+			// Removed lines don't advance the new file and don't break a run
+			// of additions that replaces them.
+		default:
+			flushRun()
+			newLine++
+		}
+	}
+	flushRun()
+	return findings
+}
+
+// Equal reports whether two sets of findings are the same, ignoring order.
+func Equal(a, b []Finding) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[Finding]int, len(a))
+	for _, f := range a {
+		counts[f]++
+	}
+	for _, f := range b {
+		if counts[f] == 0 {
+			return false
+		}
+		counts[f]--
+	}
+	return true
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "…"
+}
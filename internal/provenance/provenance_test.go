@@ -0,0 +1,141 @@
+package provenance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func addedFile(name string, lines []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "diff --git a/%s b/%s\n", name, name)
+	b.WriteString("new file mode 100644\n--- /dev/null\n")
+	fmt.Fprintf(&b, "+++ b/%s\n", name)
+	fmt.Fprintf(&b, "@@ -0,0 +1,%d @@\n", len(lines))
+	for _, l := range lines {
+		b.WriteString("+" + l + "\n")
+	}
+	return b.String()
+}
+
+func numberedLines(n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("x := %d", i)
+	}
+	return lines
+}
+
+func TestScanDiff_LicenseHeader(t *testing.T) {
+	diff := addedFile("vendor/util.go", []string{
+		"// Copyright (c) 2019 Someone Else",
+		"// SPDX-License-Identifier: GPL-3.0-or-later",
+		"// Copyright (c) 2020 Someone Else",
+		"package util",
+	})
+
+	findings := ScanDiff(diff, DefaultLargeBlockLines)
+	require.Len(t, findings, 2)
+	assert.Equal(t, KindLicenseHeader, findings[0].Kind)
+	assert.Equal(t, "vendor/util.go", findings[0].Path)
+	assert.Equal(t, 1, findings[0].Line)
+	assert.Contains(t, findings[0].Detail, "copyright notice")
+	assert.Equal(t, 2, findings[1].Line)
+	assert.Contains(t, findings[1].Detail, "SPDX")
+}
+
+func TestScanDiff_IgnoresRemovedAndContextLines(t *testing.T) {
+	diff := `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@
+ // Copyright 2021 Existing Author
+-// Licensed under the MIT license
++// Internal tool
+ package main
+`
+	assert.Empty(t, ScanDiff(diff, DefaultLargeBlockLines))
+}
+
+func TestScanDiff_LargeBlock(t *testing.T) {
+	diff := addedFile("big.go", numberedLines(12)) + addedFile("small.go", numberedLines(5))
+
+	findings := ScanDiff(diff, 10)
+	require.Len(t, findings, 1)
+	assert.Equal(t, Finding{Kind: KindLargeBlock, Path: "big.go", Line: 1, Lines: 12, Detail: "12 contiguous added lines"}, findings[0])
+}
+
+func TestScanDiff_LargeBlockSplitByContext(t *testing.T) {
+	diff := `diff --git a/a.go b/a.go
+--- a/a.go
++++ b/a.go
+@@ -10,2 +10,10 @@
++a
++b
++c
++d
++e
+ unchanged
++f
++g
++h
++i
+`
+	assert.Empty(t, ScanDiff(diff, 6))
+
+	findings := ScanDiff(diff, 4)
+	require.Len(t, findings, 2)
+	assert.Equal(t, 10, findings[0].Line)
+	assert.Equal(t, 5, findings[0].Lines)
+	assert.Equal(t, 16, findings[1].Line)
+	assert.Equal(t, 4, findings[1].Lines)
+}
+
+func TestScanDiff_SkipsLockfiles(t *testing.T) {
+	diff := addedFile("ui/package-lock.json", numberedLines(50))
+	assert.Empty(t, ScanDiff(diff, 10))
+}
+
+func TestScanDiff_LargeBlockDisabled(t *testing.T) {
+	diff := addedFile("big.go", numberedLines(500))
+	assert.Empty(t, ScanDiff(diff, -1))
+}
+
+func TestScanner_ExternalCommand(t *testing.T) {
+	s := NewScanner(Config{
+		LargeBlockLines: -1,
+		Command:         `grep -q 'secret sauce' && echo '[{"path":"a.go","line":3,"detail":"matches known snippet"}]' || true`,
+	})
+
+	findings, err := s.Scan(context.Background(), addedFile("a.go", []string{"secret sauce"}))
+	require.NoError(t, err)
+	assert.Equal(t, []Finding{{Kind: KindExternal, Path: "a.go", Line: 3, Detail: "matches known snippet"}}, findings)
+
+	findings, err = s.Scan(context.Background(), addedFile("a.go", []string{"plain"}))
+	require.NoError(t, err)
+	assert.Empty(t, findings)
+}
+
+func TestScanner_ExternalCommandFailure(t *testing.T) {
+	s := NewScanner(Config{Command: "echo boom >&2; exit 2"})
+
+	findings, err := s.Scan(context.Background(), addedFile("a.go", []string{"// SPDX-License-Identifier: MIT"}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+	// Built-in findings are still returned.
+	require.Len(t, findings, 1)
+	assert.Equal(t, KindLicenseHeader, findings[0].Kind)
+}
+
+func TestEqual(t *testing.T) {
+	a := Finding{Kind: KindLargeBlock, Path: "a.go", Lines: 300}
+	b := Finding{Kind: KindLicenseHeader, Path: "b.go", Line: 1}
+	assert.True(t, Equal([]Finding{a, b}, []Finding{b, a}))
+	assert.True(t, Equal(nil, []Finding{}))
+	assert.False(t, Equal([]Finding{a}, []Finding{b}))
+	assert.False(t, Equal([]Finding{a, a}, []Finding{a, b}))
+}
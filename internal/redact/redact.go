@@ -2,6 +2,7 @@
 package redact
 
 import (
+	"encoding/json"
 	"regexp"
 )
 
@@ -73,3 +74,35 @@ func Lines(lines []string) []string {
 	}
 	return redacted
 }
+
+// JSON redacts sensitive data from every string value in a JSON document.
+// Redacting the encoded document directly could consume closing quotes and
+// braces, so values are redacted individually and the document re-encoded.
+// Input that isn't valid JSON is returned unchanged.
+func JSON(data []byte) []byte {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return data
+	}
+	out, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case string:
+		return Line(v)
+	case []any:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+	case map[string]any:
+		for k := range v {
+			v[k] = redactValue(v[k])
+		}
+	}
+	return v
+}
@@ -172,3 +172,12 @@ func TestLines_DoesNotModifyOriginal(t *testing.T) {
 
 	assert.Equal(t, original, input[0], "Lines should not modify the original slice")
 }
+
+func TestJSON(t *testing.T) {
+	in := `{"name":"Bash","detail":"curl -H Authorization:Bearer sk-abc123def456","nested":[{"token":"password=hunter2"}],"n":1}`
+	got := JSON([]byte(in))
+	assert.JSONEq(t, `{"name":"Bash","detail":"curl -H Authorization:Bearer [REDACTED]","nested":[{"token":"password=[REDACTED]"}],"n":1}`, string(got))
+
+	invalid := []byte(`{"detail":`)
+	assert.Equal(t, invalid, JSON(invalid), "expected invalid JSON to be returned unchanged")
+}
@@ -4,6 +4,10 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/vervesh/verve/internal/github"
+	"github.com/vervesh/verve/internal/prlint"
+	"github.com/vervesh/verve/internal/task"
 )
 
 // SetupStatus represents the current state of repository setup.
@@ -17,19 +21,69 @@ const (
 
 // Repo represents a GitHub repository added to Verve.
 type Repo struct {
-	ID               RepoID     `json:"id"`
-	Owner            string     `json:"owner"`
-	Name             string     `json:"name"`
-	FullName         string     `json:"full_name"`
-	Summary          string     `json:"summary"`
-	TechStack        []string   `json:"tech_stack"`
-	SetupStatus      string     `json:"setup_status"`
-	HasCode          bool       `json:"has_code"`
-	HasCLAUDEMD      bool       `json:"has_claude_md"`
-	HasREADME        bool       `json:"has_readme"`
-	Expectations     string     `json:"expectations"`
-	SetupCompletedAt *time.Time `json:"setup_completed_at,omitempty"`
-	CreatedAt        time.Time  `json:"created_at"`
+	ID                       RepoID            `json:"id"`
+	Owner                    string            `json:"owner"`
+	Name                     string            `json:"name"`
+	FullName                 string            `json:"full_name"`
+	Summary                  string            `json:"summary"`
+	TechStack                []string          `json:"tech_stack"`
+	SetupStatus              string            `json:"setup_status"`
+	HasCode                  bool              `json:"has_code"`
+	HasCLAUDEMD              bool              `json:"has_claude_md"`
+	HasREADME                bool              `json:"has_readme"`
+	Expectations             string            `json:"expectations"`
+	SetupCompletedAt         *time.Time        `json:"setup_completed_at,omitempty"`
+	CIWorkflow               *CIWorkflow       `json:"ci_workflow,omitempty"`
+	ProtectedPaths           []string          `json:"protected_paths"`
+	ShadowMode               bool              `json:"shadow_mode"`
+	CompletionValidations    *prlint.Config    `json:"completion_validations,omitempty"`
+	RetryPolicy              *task.RetryPolicy `json:"retry_policy,omitempty"`
+	MaxRuntimeSeconds        int               `json:"max_runtime_seconds"`
+	RequiredLabels           []string          `json:"required_labels"`
+	IgnoredChecks            []string          `json:"ignored_checks"`
+	CheckGating              *CheckGating      `json:"check_gating,omitempty"`
+	FallbackModels           []string          `json:"fallback_models"`
+	Paused                   bool              `json:"paused"`
+	SyncDisabled             bool              `json:"sync_disabled"`
+	SyncIntervalSeconds      int               `json:"sync_interval_seconds"`
+	ReviewSLASeconds         int               `json:"review_sla_seconds"`
+	WorkspaceCacheGeneration int               `json:"workspace_cache_generation"`
+	TeamID                   string            `json:"team_id,omitempty"`
+	CreatedAt                time.Time         `json:"created_at"`
+}
+
+// CIWorkflow is a GitHub Actions workflow the server dispatches on the PR
+// branch whenever an agent opens or updates a pull request. The PR's checks
+// are not considered passing until the dispatched run has succeeded.
+type CIWorkflow struct {
+	// File is the workflow file name or ID, e.g. "full-tests.yml". The
+	// workflow must declare a workflow_dispatch trigger.
+	File string `json:"file"`
+	// Inputs are passed to the workflow_dispatch event.
+	Inputs map[string]string `json:"inputs,omitempty"`
+}
+
+// CheckGating limits the CI checks that gate the repo's agent pull requests
+// to the required ones. Other checks are informational: their failures don't
+// fail the PR's check status or send the task back to the agent.
+type CheckGating struct {
+	// RequiredOnly turns the limit on.
+	RequiredOnly bool `json:"required_only"`
+	// Checks names the checks that gate pull requests. Empty uses the status
+	// checks required by the branch protection and rulesets of each PR's
+	// base branch.
+	Checks []string `json:"checks,omitempty"`
+}
+
+// CheckPolicy returns which checks count towards the check status of the
+// repo's agent pull requests.
+func (r *Repo) CheckPolicy() github.CheckPolicy {
+	policy := github.CheckPolicy{Ignored: r.IgnoredChecks}
+	if r.CheckGating != nil && r.CheckGating.RequiredOnly {
+		policy.RequiredOnly = true
+		policy.Required = r.CheckGating.Checks
+	}
+	return policy
 }
 
 // NewRepo creates a new Repo from a full name (e.g., "owner/repo").
@@ -39,13 +93,16 @@ func NewRepo(fullName string) (*Repo, error) {
 		return nil, fmt.Errorf("invalid repo full name %q: expected owner/name", fullName)
 	}
 	return &Repo{
-		ID:          NewRepoID(),
-		Owner:       parts[0],
-		Name:        parts[1],
-		FullName:    fullName,
-		TechStack:   []string{},
-		SetupStatus: SetupStatusPending,
-		CreatedAt:   time.Now(),
+		ID:             NewRepoID(),
+		Owner:          parts[0],
+		Name:           parts[1],
+		FullName:       fullName,
+		TechStack:      []string{},
+		SetupStatus:    SetupStatusPending,
+		ProtectedPaths: []string{},
+		IgnoredChecks:  []string{},
+		FallbackModels: []string{},
+		CreatedAt:      time.Now(),
 	}, nil
 }
 
@@ -3,6 +3,9 @@ package repo
 import (
 	"context"
 	"time"
+
+	"github.com/vervesh/verve/internal/prlint"
+	"github.com/vervesh/verve/internal/task"
 )
 
 // SetupScanResult holds the results of scanning a repository.
@@ -34,5 +37,22 @@ type Repository interface {
 	UpdateRepoExpectations(ctx context.Context, id RepoID, update ExpectationsUpdate) error
 	UpdateRepoSummary(ctx context.Context, id RepoID, summary string) error
 	UpdateRepoTechStack(ctx context.Context, id RepoID, techStack []string) error
+	UpdateRepoCIWorkflow(ctx context.Context, id RepoID, workflow *CIWorkflow) error
+	UpdateRepoProtectedPaths(ctx context.Context, id RepoID, patterns []string) error
+	UpdateRepoShadowMode(ctx context.Context, id RepoID, enabled bool) error
+	UpdateRepoCompletionValidations(ctx context.Context, id RepoID, cfg *prlint.Config) error
+	UpdateRepoRetryPolicy(ctx context.Context, id RepoID, policy *task.RetryPolicy) error
+	UpdateRepoMaxRuntime(ctx context.Context, id RepoID, seconds int) error
+	UpdateRepoRequiredLabels(ctx context.Context, id RepoID, labels []string) error
+	UpdateRepoIgnoredChecks(ctx context.Context, id RepoID, names []string) error
+	UpdateRepoCheckGating(ctx context.Context, id RepoID, gating *CheckGating) error
+	UpdateRepoFallbackModels(ctx context.Context, id RepoID, models []string) error
+	UpdateRepoPaused(ctx context.Context, id RepoID, paused bool) error
+	UpdateRepoSyncDisabled(ctx context.Context, id RepoID, disabled bool) error
+	UpdateRepoSyncInterval(ctx context.Context, id RepoID, seconds int) error
+	UpdateRepoReviewSLA(ctx context.Context, id RepoID, seconds int) error
+	IncrementRepoWorkspaceCacheGeneration(ctx context.Context, id RepoID) error
+	UpdateRepoTeam(ctx context.Context, id RepoID, teamID string) error
 	ListReposBySetupStatus(ctx context.Context, status string) ([]*Repo, error)
+	ListReposByTeam(ctx context.Context, teamID string) ([]*Repo, error)
 }
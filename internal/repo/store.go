@@ -3,6 +3,9 @@ package repo
 import (
 	"context"
 	"fmt"
+
+	"github.com/vervesh/verve/internal/prlint"
+	"github.com/vervesh/verve/internal/task"
 )
 
 // validSetupTransitions defines which status transitions are allowed.
@@ -15,9 +18,30 @@ var validSetupTransitions = map[string][]string{
 	SetupStatusReady:       {SetupStatusScanning, SetupStatusConfiguring},
 }
 
+// TeamDefaults are the settings a repo inherits from its team.
+type TeamDefaults struct {
+	Model      string  // Default model for new work; empty for none
+	MaxCostUSD float64 // Default task budget; 0 for none
+}
+
+// TeamDefaultsReader reads the settings of a team. It returns an
+// errtag.NotFound error when the team doesn't exist.
+type TeamDefaultsReader interface {
+	TeamDefaults(ctx context.Context, teamID string) (TeamDefaults, error)
+}
+
+// TeamDefaultsReaderFunc adapts a function to the TeamDefaultsReader
+// interface.
+type TeamDefaultsReaderFunc func(ctx context.Context, teamID string) (TeamDefaults, error)
+
+func (f TeamDefaultsReaderFunc) TeamDefaults(ctx context.Context, teamID string) (TeamDefaults, error) {
+	return f(ctx, teamID)
+}
+
 // Store wraps a Repository and adds application-level concerns.
 type Store struct {
-	repo Repository
+	repo         Repository
+	teamDefaults TeamDefaultsReader
 }
 
 // NewStore creates a new Store backed by the given Repository.
@@ -25,6 +49,22 @@ func NewStore(repo Repository) *Store {
 	return &Store{repo: repo}
 }
 
+// SetTeamDefaultsReader sets the TeamDefaultsReader consulted for the
+// settings repos inherit from their team. Without one, repos inherit
+// nothing and can't be assigned to a team.
+func (s *Store) SetTeamDefaultsReader(reader TeamDefaultsReader) {
+	s.teamDefaults = reader
+}
+
+// TeamDefaults returns the settings the repo inherits from its team. A repo
+// without a team inherits nothing.
+func (s *Store) TeamDefaults(ctx context.Context, r *Repo) (TeamDefaults, error) {
+	if r.TeamID == "" || s.teamDefaults == nil {
+		return TeamDefaults{}, nil
+	}
+	return s.teamDefaults.TeamDefaults(ctx, r.TeamID)
+}
+
 // CreateRepo creates a new repo.
 func (s *Store) CreateRepo(ctx context.Context, repo *Repo) error {
 	return s.repo.CreateRepo(ctx, repo)
@@ -103,6 +143,141 @@ func (s *Store) UpdateRepoTechStack(ctx context.Context, id RepoID, techStack []
 	return s.repo.UpdateRepoTechStack(ctx, id, techStack)
 }
 
+// UpdateRepoCIWorkflow sets the workflow dispatched for agent pull requests.
+// A nil workflow or one without a file clears it.
+func (s *Store) UpdateRepoCIWorkflow(ctx context.Context, id RepoID, workflow *CIWorkflow) error {
+	if workflow != nil && workflow.File == "" {
+		workflow = nil
+	}
+	return s.repo.UpdateRepoCIWorkflow(ctx, id, workflow)
+}
+
+// UpdateRepoProtectedPaths replaces the path globs agents must not change in
+// the repo. An empty list removes the protection.
+func (s *Store) UpdateRepoProtectedPaths(ctx context.Context, id RepoID, patterns []string) error {
+	if patterns == nil {
+		patterns = []string{}
+	}
+	return s.repo.UpdateRepoProtectedPaths(ctx, id, patterns)
+}
+
+// UpdateRepoShadowMode turns shadow mode on or off for the repo. Agents in
+// shadow mode record their diff for review instead of pushing.
+func (s *Store) UpdateRepoShadowMode(ctx context.Context, id RepoID, enabled bool) error {
+	return s.repo.UpdateRepoShadowMode(ctx, id, enabled)
+}
+
+// UpdateRepoCompletionValidations sets the validations agent pull requests
+// must pass when the agent reports success. A nil or empty config clears them.
+func (s *Store) UpdateRepoCompletionValidations(ctx context.Context, id RepoID, cfg *prlint.Config) error {
+	if cfg != nil && cfg.Empty() {
+		cfg = nil
+	}
+	return s.repo.UpdateRepoCompletionValidations(ctx, id, cfg)
+}
+
+// UpdateRepoRetryPolicy sets the retry policy that overrides the
+// instance-wide one for the repo's tasks. A nil or empty policy clears it.
+func (s *Store) UpdateRepoRetryPolicy(ctx context.Context, id RepoID, policy *task.RetryPolicy) error {
+	if policy != nil && policy.Empty() {
+		policy = nil
+	}
+	return s.repo.UpdateRepoRetryPolicy(ctx, id, policy)
+}
+
+// UpdateRepoMaxRuntime sets the default max runtime, in seconds, of the
+// repo's tasks. 0 removes the limit.
+func (s *Store) UpdateRepoMaxRuntime(ctx context.Context, id RepoID, seconds int) error {
+	return s.repo.UpdateRepoMaxRuntime(ctx, id, seconds)
+}
+
+// UpdateRepoRequiredLabels sets the worker labels every task in the repo
+// needs. An empty list lets any worker run the repo's tasks.
+func (s *Store) UpdateRepoRequiredLabels(ctx context.Context, id RepoID, labels []string) error {
+	return s.repo.UpdateRepoRequiredLabels(ctx, id, labels)
+}
+
+// UpdateRepoIgnoredChecks replaces the CI checks that don't count towards
+// the check status of the repo's agent PRs. An empty list counts every check.
+func (s *Store) UpdateRepoIgnoredChecks(ctx context.Context, id RepoID, names []string) error {
+	if names == nil {
+		names = []string{}
+	}
+	return s.repo.UpdateRepoIgnoredChecks(ctx, id, names)
+}
+
+// UpdateRepoCheckGating sets which CI checks gate the repo's agent PRs. A
+// nil gating or one without RequiredOnly lets every check gate them.
+func (s *Store) UpdateRepoCheckGating(ctx context.Context, id RepoID, gating *CheckGating) error {
+	if gating != nil && !gating.RequiredOnly {
+		gating = nil
+	}
+	return s.repo.UpdateRepoCheckGating(ctx, id, gating)
+}
+
+// UpdateRepoFallbackModels replaces the models new tasks in the repo fall
+// back to, in order, when their agent is rate-limited or overloaded. An empty
+// list disables falling back.
+func (s *Store) UpdateRepoFallbackModels(ctx context.Context, id RepoID, models []string) error {
+	if models == nil {
+		models = []string{}
+	}
+	return s.repo.UpdateRepoFallbackModels(ctx, id, models)
+}
+
+// UpdateRepoPaused pauses or resumes the repo's queue. Pending tasks in a
+// paused repo are not handed to workers; tasks can still be created and
+// running ones are not interrupted.
+func (s *Store) UpdateRepoPaused(ctx context.Context, id RepoID, paused bool) error {
+	return s.repo.UpdateRepoPaused(ctx, id, paused)
+}
+
+// UpdateRepoSyncDisabled turns background PR sync for the repo off or back
+// on. While it is off, merges, reviews and CI results of the repo's pull
+// requests are not picked up.
+func (s *Store) UpdateRepoSyncDisabled(ctx context.Context, id RepoID, disabled bool) error {
+	return s.repo.UpdateRepoSyncDisabled(ctx, id, disabled)
+}
+
+// UpdateRepoSyncInterval sets how often the repo's pull requests are synced,
+// overriding the global sync interval. 0 removes the override.
+func (s *Store) UpdateRepoSyncInterval(ctx context.Context, id RepoID, seconds int) error {
+	return s.repo.UpdateRepoSyncInterval(ctx, id, seconds)
+}
+
+// UpdateRepoReviewSLA sets how long the repo's tasks may sit in review
+// before they are reported overdue. 0 removes the SLA.
+func (s *Store) UpdateRepoReviewSLA(ctx context.Context, id RepoID, seconds int) error {
+	return s.repo.UpdateRepoReviewSLA(ctx, id, seconds)
+}
+
+// InvalidateWorkspaceCache bumps the repo's workspace cache generation so
+// workers discard their cached clone and dependency caches for the repo
+// before the next agent run.
+func (s *Store) InvalidateWorkspaceCache(ctx context.Context, id RepoID) error {
+	return s.repo.IncrementRepoWorkspaceCacheGeneration(ctx, id)
+}
+
+// UpdateRepoTeam moves the repo into the team. An empty teamID removes it
+// from its team.
+func (s *Store) UpdateRepoTeam(ctx context.Context, id RepoID, teamID string) error {
+	if teamID != "" {
+		if s.teamDefaults == nil {
+			return fmt.Errorf("teams are not available")
+		}
+		// Reading the team's settings checks it exists.
+		if _, err := s.teamDefaults.TeamDefaults(ctx, teamID); err != nil {
+			return err
+		}
+	}
+	return s.repo.UpdateRepoTeam(ctx, id, teamID)
+}
+
+// ListReposByTeam returns the repos in a team.
+func (s *Store) ListReposByTeam(ctx context.Context, teamID string) ([]*Repo, error) {
+	return s.repo.ListReposByTeam(ctx, teamID)
+}
+
 // ListReposBySetupStatus returns all repos with the given setup status.
 func (s *Store) ListReposBySetupStatus(ctx context.Context, status string) ([]*Repo, error) {
 	return s.repo.ListReposBySetupStatus(ctx, status)
@@ -10,6 +10,7 @@ import (
 	"github.com/vervesh/verve/internal/github"
 	"github.com/vervesh/verve/internal/githubtoken"
 	"github.com/vervesh/verve/internal/logkey"
+	"github.com/vervesh/verve/internal/msgcat"
 	"github.com/vervesh/verve/internal/repo"
 	"github.com/vervesh/verve/internal/task"
 )
@@ -19,11 +20,31 @@ type HTTPHandler struct {
 	repoStore          *repo.Store
 	taskStore          *task.Store
 	githubTokenService *githubtoken.Service
+	syncer             RepoSyncer
+}
+
+// RepoSyncer queues an immediate PR sync of a repo.
+type RepoSyncer interface {
+	RequestRepoSync(repoID string)
+}
+
+// Option configures an HTTPHandler.
+type Option func(*HTTPHandler)
+
+// WithRepoSyncer enables POST /repos/:repo_id/sync.
+func WithRepoSyncer(s RepoSyncer) Option {
+	return func(h *HTTPHandler) {
+		h.syncer = s
+	}
 }
 
 // NewHTTPHandler creates a new HTTPHandler.
-func NewHTTPHandler(repoStore *repo.Store, taskStore *task.Store, githubTokenService *githubtoken.Service) *HTTPHandler {
-	return &HTTPHandler{repoStore: repoStore, taskStore: taskStore, githubTokenService: githubTokenService}
+func NewHTTPHandler(repoStore *repo.Store, taskStore *task.Store, githubTokenService *githubtoken.Service, opts ...Option) *HTTPHandler {
+	h := &HTTPHandler{repoStore: repoStore, taskStore: taskStore, githubTokenService: githubTokenService}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // Register adds the endpoints to the provided Echo router group.
@@ -40,6 +61,11 @@ func (h *HTTPHandler) Register(g *echo.Group) {
 	g.POST("/repos/:repo_id/setup/skip", h.SkipSetup)
 	g.POST("/repos/:repo_id/setup/submit", h.SubmitSetup)
 	g.POST("/repos/:repo_id/setup/confirm", h.ConfirmSetup)
+
+	g.PUT("/repos/:repo_id/paused", h.SetPaused)
+	g.POST("/repos/:repo_id/sync", h.SyncRepo)
+	g.DELETE("/repos/:repo_id/workspace-cache", h.InvalidateWorkspaceCache)
+	g.GET("/repos/:repo_id/credentials/resolve", h.ResolveCredentials)
 }
 
 // ListRepos handles GET /repos
@@ -103,7 +129,7 @@ func (h *HTTPHandler) RemoveRepo(c echo.Context) error {
 func (h *HTTPHandler) ListAvailableRepos(c echo.Context) error {
 	gh := h.githubClient()
 	if gh == nil {
-		return echo.NewHTTPError(http.StatusServiceUnavailable, "GitHub token not configured")
+		return echo.NewHTTPError(http.StatusServiceUnavailable, msgcat.Text(msgcat.ErrGitHubTokenNotConfigured))
 	}
 
 	repos, err := gh.ListAccessibleRepos(c.Request().Context())
@@ -168,6 +194,90 @@ func (h *HTTPHandler) UpdateSetup(c echo.Context) error {
 		}
 	}
 
+	if req.CIWorkflow != nil {
+		if err := h.repoStore.UpdateRepoCIWorkflow(ctx, id, req.CIWorkflow); err != nil {
+			return err
+		}
+	}
+
+	if req.ProtectedPaths != nil {
+		if err := h.repoStore.UpdateRepoProtectedPaths(ctx, id, *req.ProtectedPaths); err != nil {
+			return err
+		}
+	}
+
+	if req.ShadowMode != nil {
+		if err := h.repoStore.UpdateRepoShadowMode(ctx, id, *req.ShadowMode); err != nil {
+			return err
+		}
+	}
+
+	if req.CompletionValidations != nil {
+		if err := h.repoStore.UpdateRepoCompletionValidations(ctx, id, req.CompletionValidations); err != nil {
+			return err
+		}
+	}
+
+	if req.RetryPolicy != nil {
+		if err := h.repoStore.UpdateRepoRetryPolicy(ctx, id, req.RetryPolicy); err != nil {
+			return err
+		}
+	}
+
+	if req.MaxRuntimeSeconds != nil {
+		if err := h.repoStore.UpdateRepoMaxRuntime(ctx, id, *req.MaxRuntimeSeconds); err != nil {
+			return err
+		}
+	}
+
+	if req.RequiredLabels != nil {
+		if err := h.repoStore.UpdateRepoRequiredLabels(ctx, id, *req.RequiredLabels); err != nil {
+			return err
+		}
+	}
+
+	if req.IgnoredChecks != nil {
+		if err := h.repoStore.UpdateRepoIgnoredChecks(ctx, id, *req.IgnoredChecks); err != nil {
+			return err
+		}
+	}
+
+	if req.CheckGating != nil {
+		if err := h.repoStore.UpdateRepoCheckGating(ctx, id, req.CheckGating); err != nil {
+			return err
+		}
+	}
+
+	if req.FallbackModels != nil {
+		if err := h.repoStore.UpdateRepoFallbackModels(ctx, id, *req.FallbackModels); err != nil {
+			return err
+		}
+	}
+
+	if req.SyncDisabled != nil {
+		if err := h.repoStore.UpdateRepoSyncDisabled(ctx, id, *req.SyncDisabled); err != nil {
+			return err
+		}
+	}
+
+	if req.SyncIntervalSeconds != nil {
+		if err := h.repoStore.UpdateRepoSyncInterval(ctx, id, *req.SyncIntervalSeconds); err != nil {
+			return err
+		}
+	}
+
+	if req.ReviewSLASeconds != nil {
+		if err := h.repoStore.UpdateRepoReviewSLA(ctx, id, *req.ReviewSLASeconds); err != nil {
+			return err
+		}
+	}
+
+	if req.TeamID != nil {
+		if err := h.repoStore.UpdateRepoTeam(ctx, id, *req.TeamID); err != nil {
+			return err
+		}
+	}
+
 	if req.MarkReady {
 		if err := h.repoStore.UpdateRepoSetupStatus(ctx, id, repo.SetupStatusReady); err != nil {
 			return err
@@ -218,6 +328,125 @@ func (h *HTTPHandler) Rescan(c echo.Context) error {
 	return server.SetResponse(c, http.StatusOK, r)
 }
 
+// SetPaused handles PUT /repos/:repo_id/paused — pauses or resumes the repo's
+// queue. Workers are handed no pending tasks from a paused repo, while new
+// tasks are still accepted and running ones finish.
+func (h *HTTPHandler) SetPaused(c echo.Context) error {
+	req, err := server.BindRequest[SetPausedRequest](c)
+	if err != nil {
+		return err
+	}
+
+	id := repo.MustParseRepoID(req.RepoID)
+	c.Set(logkey.RepoID, id.String())
+
+	ctx := c.Request().Context()
+
+	if _, err := h.repoStore.ReadRepo(ctx, id); err != nil {
+		return err
+	}
+	if err := h.repoStore.UpdateRepoPaused(ctx, id, req.Paused); err != nil {
+		return err
+	}
+	if !req.Paused {
+		h.taskStore.NotifyPending()
+	}
+
+	r, err := h.repoStore.ReadRepo(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	h.taskStore.PublishRepoEvent(ctx, id.String(), r)
+
+	return server.SetResponse(c, http.StatusOK, r)
+}
+
+// SyncRepo handles POST /repos/:repo_id/sync — queues a sync of the repo's
+// pull requests to run right away instead of waiting for its next scheduled
+// sync.
+func (h *HTTPHandler) SyncRepo(c echo.Context) error {
+	req, err := server.BindRequest[RepoIDRequest](c)
+	if err != nil {
+		return err
+	}
+
+	id := repo.MustParseRepoID(req.RepoID)
+	c.Set(logkey.RepoID, id.String())
+
+	if h.syncer == nil {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, msgcat.Text(msgcat.ErrRepoSyncUnavailable))
+	}
+	r, err := h.repoStore.ReadRepo(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	if r.SyncDisabled {
+		return echo.NewHTTPError(http.StatusConflict, msgcat.Text(msgcat.ErrRepoSyncDisabled))
+	}
+	h.syncer.RequestRepoSync(id.String())
+	return c.NoContent(http.StatusAccepted)
+}
+
+// InvalidateWorkspaceCache handles DELETE /repos/:repo_id/workspace-cache —
+// tells workers to discard the repo's cached clone and dependency caches so
+// the next agent run starts from a fresh workspace.
+func (h *HTTPHandler) InvalidateWorkspaceCache(c echo.Context) error {
+	req, err := server.BindRequest[RepoIDRequest](c)
+	if err != nil {
+		return err
+	}
+
+	id := repo.MustParseRepoID(req.RepoID)
+	c.Set(logkey.RepoID, id.String())
+
+	ctx := c.Request().Context()
+
+	if _, err := h.repoStore.ReadRepo(ctx, id); err != nil {
+		return err
+	}
+	if err := h.repoStore.InvalidateWorkspaceCache(ctx, id); err != nil {
+		return err
+	}
+
+	r, err := h.repoStore.ReadRepo(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	h.taskStore.PublishRepoEvent(ctx, id.String(), r)
+
+	return server.SetResponse(c, http.StatusOK, r)
+}
+
+// ResolveCredentials handles GET /repos/:repo_id/credentials/resolve — it
+// reports which GitHub token source the repo's agents and API calls use and
+// what each source in the fallback chain holds, without revealing tokens.
+func (h *HTTPHandler) ResolveCredentials(c echo.Context) error {
+	req, err := server.BindRequest[RepoIDRequest](c)
+	if err != nil {
+		return err
+	}
+
+	id := repo.MustParseRepoID(req.RepoID)
+	c.Set(logkey.RepoID, id.String())
+
+	ctx := c.Request().Context()
+
+	r, err := h.repoStore.ReadRepo(ctx, id)
+	if err != nil {
+		return err
+	}
+	if h.githubTokenService == nil {
+		// Without an encryption key no token can be stored.
+		return server.SetResponse(c, http.StatusOK, &githubtoken.Resolution{
+			RepoFullName: r.FullName,
+			Chain:        []githubtoken.SourceResult{{Source: githubtoken.SourceGlobal, Consulted: true}},
+		})
+	}
+	return server.SetResponse(c, http.StatusOK, h.githubTokenService.Resolve(ctx, r.FullName))
+}
+
 // SkipSetup handles POST /repos/:repo_id/setup/skip — marks a repo as ready
 // without requiring a scan. Useful for pre-existing repos that were added
 // before the setup scan feature.
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"slices"
+	"sync"
 	"testing"
 	"time"
 
@@ -26,7 +28,7 @@ type fixture struct {
 	t         *testing.T
 }
 
-func newFixture(t *testing.T) *fixture {
+func newFixture(t *testing.T, opts ...repoapi.Option) *fixture {
 	t.Helper()
 
 	db := sqlite.NewTestDB(t)
@@ -37,7 +39,7 @@ func newFixture(t *testing.T) *fixture {
 	taskRepo := sqlite.NewTaskRepository(db)
 	taskStore := task.NewStore(taskRepo, broker)
 
-	handler := repoapi.NewHTTPHandler(repoStore, taskStore, nil)
+	handler := repoapi.NewHTTPHandler(repoStore, taskStore, nil, opts...)
 
 	srv, err := server.NewServer(testutil.GetFreePort(t))
 	require.NoError(t, err)
@@ -93,10 +95,44 @@ func (f *fixture) repoConfirmSetupURL(id repo.RepoID) string {
 	return fmt.Sprintf("%s/api/v1/repos/%s/setup/confirm", f.Server.Address(), id)
 }
 
+func (f *fixture) repoPausedURL(id repo.RepoID) string {
+	return fmt.Sprintf("%s/api/v1/repos/%s/paused", f.Server.Address(), id)
+}
+
+func (f *fixture) repoSyncURL(id repo.RepoID) string {
+	return fmt.Sprintf("%s/api/v1/repos/%s/sync", f.Server.Address(), id)
+}
+
+func (f *fixture) repoWorkspaceCacheURL(id repo.RepoID) string {
+	return fmt.Sprintf("%s/api/v1/repos/%s/workspace-cache", f.Server.Address(), id)
+}
+
+func (f *fixture) repoCredentialsResolveURL(id repo.RepoID) string {
+	return fmt.Sprintf("%s/api/v1/repos/%s/credentials/resolve", f.Server.Address(), id)
+}
+
 func (f *fixture) availableReposURL() string {
 	return fmt.Sprintf("%s/api/v1/repos/available", f.Server.Address())
 }
 
+// fakeSyncer records the repos whose sync was requested.
+type fakeSyncer struct {
+	mu        sync.Mutex
+	requested []string
+}
+
+func (s *fakeSyncer) RequestRepoSync(repoID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requested = append(s.requested, repoID)
+}
+
+func (s *fakeSyncer) Requested() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requested)
+}
+
 func mustJSONReader(v any) io.Reader {
 	b, err := json.Marshal(v)
 	if err != nil {
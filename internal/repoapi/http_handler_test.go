@@ -12,8 +12,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/vervesh/verve/internal/githubtoken"
+	"github.com/vervesh/verve/internal/prlint"
 	"github.com/vervesh/verve/internal/repo"
 	"github.com/vervesh/verve/internal/repoapi"
+	"github.com/vervesh/verve/internal/task"
 )
 
 func TestAddRepo_Success(t *testing.T) {
@@ -226,6 +229,354 @@ func TestUpdateSetup_SummaryEmpty(t *testing.T) {
 	assert.Equal(t, "", res.Data.Summary)
 }
 
+func TestUpdateSetup_CIWorkflow(t *testing.T) {
+	f := newFixture(t)
+	r := f.addRepo("owner/test-repo")
+
+	req := repoapi.UpdateSetupRequest{CIWorkflow: &repo.CIWorkflow{
+		File:   "full-tests.yml",
+		Inputs: map[string]string{"suite": "all"},
+	}}
+	res := doPatch[server.Response[repo.Repo]](t, f.repoSetupURL(r.ID), req)
+	require.NotNil(t, res.Data.CIWorkflow)
+	assert.Equal(t, "full-tests.yml", res.Data.CIWorkflow.File)
+	assert.Equal(t, map[string]string{"suite": "all"}, res.Data.CIWorkflow.Inputs)
+
+	// An empty file clears the workflow
+	req2 := repoapi.UpdateSetupRequest{CIWorkflow: &repo.CIWorkflow{}}
+	res = doPatch[server.Response[repo.Repo]](t, f.repoSetupURL(r.ID), req2)
+	assert.Nil(t, res.Data.CIWorkflow)
+}
+
+func TestUpdateSetup_ProtectedPaths(t *testing.T) {
+	f := newFixture(t)
+	r := f.addRepo("owner/test-repo")
+	assert.Empty(t, r.ProtectedPaths)
+
+	paths := []string{".github/workflows", "deploy/**", ".env*"}
+	res := doPatch[server.Response[repo.Repo]](t, f.repoSetupURL(r.ID), repoapi.UpdateSetupRequest{ProtectedPaths: &paths})
+	assert.Equal(t, paths, res.Data.ProtectedPaths)
+
+	// An empty list removes the protection
+	res = doPatch[server.Response[repo.Repo]](t, f.repoSetupURL(r.ID), repoapi.UpdateSetupRequest{ProtectedPaths: &[]string{}})
+	assert.Empty(t, res.Data.ProtectedPaths)
+}
+
+func TestUpdateSetup_ProtectedPathsInvalid(t *testing.T) {
+	f := newFixture(t)
+	r := f.addRepo("owner/test-repo")
+
+	paths := []string{"config/[a-"}
+	req, err := http.NewRequest(http.MethodPatch, f.repoSetupURL(r.ID), mustJSONReader(repoapi.UpdateSetupRequest{ProtectedPaths: &paths}))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	httpRes, err := testutil.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer httpRes.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+}
+
+func TestUpdateSetup_ShadowMode(t *testing.T) {
+	f := newFixture(t)
+	r := f.addRepo("owner/test-repo")
+	assert.False(t, r.ShadowMode)
+
+	enabled := true
+	res := doPatch[server.Response[repo.Repo]](t, f.repoSetupURL(r.ID), repoapi.UpdateSetupRequest{ShadowMode: &enabled})
+	assert.True(t, res.Data.ShadowMode)
+
+	// Omitting the field leaves shadow mode unchanged
+	summary := "updated"
+	res = doPatch[server.Response[repo.Repo]](t, f.repoSetupURL(r.ID), repoapi.UpdateSetupRequest{Summary: &summary})
+	assert.True(t, res.Data.ShadowMode)
+
+	disabled := false
+	res = doPatch[server.Response[repo.Repo]](t, f.repoSetupURL(r.ID), repoapi.UpdateSetupRequest{ShadowMode: &disabled})
+	assert.False(t, res.Data.ShadowMode)
+}
+
+func TestUpdateSetup_CompletionValidations(t *testing.T) {
+	f := newFixture(t)
+	r := f.addRepo("owner/test-repo")
+	assert.Nil(t, r.CompletionValidations)
+
+	cfg := prlint.Config{TitlePrefix: "[api] ", TitlePattern: `^\[api\] (feat|fix): `, RequireTaskID: true, MaxCommits: 1}
+	res := doPatch[server.Response[repo.Repo]](t, f.repoSetupURL(r.ID), repoapi.UpdateSetupRequest{CompletionValidations: &cfg})
+	require.NotNil(t, res.Data.CompletionValidations)
+	assert.Equal(t, cfg, *res.Data.CompletionValidations)
+
+	// An empty config clears the validations
+	res = doPatch[server.Response[repo.Repo]](t, f.repoSetupURL(r.ID), repoapi.UpdateSetupRequest{CompletionValidations: &prlint.Config{}})
+	assert.Nil(t, res.Data.CompletionValidations)
+}
+
+func TestUpdateSetup_CompletionValidationsInvalid(t *testing.T) {
+	f := newFixture(t)
+	r := f.addRepo("owner/test-repo")
+
+	for _, cfg := range []prlint.Config{
+		{BranchPattern: "verve/(task"},
+		{TitlePrefix: "PROJ\n"},
+	} {
+		req, err := http.NewRequest(http.MethodPatch, f.repoSetupURL(r.ID), mustJSONReader(repoapi.UpdateSetupRequest{CompletionValidations: &cfg}))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		httpRes, err := testutil.DefaultClient.Do(req)
+		require.NoError(t, err)
+		httpRes.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+	}
+}
+
+func TestUpdateSetup_RetryPolicy(t *testing.T) {
+	f := newFixture(t)
+	r := f.addRepo("owner/test-repo")
+	assert.Nil(t, r.RetryPolicy)
+
+	policy := task.RetryPolicy{MaxAttempts: 8, CategoryLimits: map[string]int{"ci_failure": 4}}
+	res := doPatch[server.Response[repo.Repo]](t, f.repoSetupURL(r.ID), repoapi.UpdateSetupRequest{RetryPolicy: &policy})
+	require.NotNil(t, res.Data.RetryPolicy)
+	assert.Equal(t, policy, *res.Data.RetryPolicy)
+
+	// An empty policy falls back to the instance-wide one
+	res = doPatch[server.Response[repo.Repo]](t, f.repoSetupURL(r.ID), repoapi.UpdateSetupRequest{RetryPolicy: &task.RetryPolicy{}})
+	assert.Nil(t, res.Data.RetryPolicy)
+}
+
+func TestUpdateSetup_RetryPolicyInvalid(t *testing.T) {
+	f := newFixture(t)
+	r := f.addRepo("owner/test-repo")
+
+	policy := task.RetryPolicy{CategoryLimits: map[string]int{"rate_limit": 0}}
+	req, err := http.NewRequest(http.MethodPatch, f.repoSetupURL(r.ID), mustJSONReader(repoapi.UpdateSetupRequest{RetryPolicy: &policy}))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	httpRes, err := testutil.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer httpRes.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+}
+
+func TestUpdateSetup_MaxRuntime(t *testing.T) {
+	f := newFixture(t)
+	r := f.addRepo("owner/test-repo")
+	assert.Zero(t, r.MaxRuntimeSeconds)
+
+	seconds := 4 * 3600
+	res := doPatch[server.Response[repo.Repo]](t, f.repoSetupURL(r.ID), repoapi.UpdateSetupRequest{MaxRuntimeSeconds: &seconds})
+	assert.Equal(t, seconds, res.Data.MaxRuntimeSeconds)
+
+	// Other updates leave the max runtime alone
+	shadow := true
+	res = doPatch[server.Response[repo.Repo]](t, f.repoSetupURL(r.ID), repoapi.UpdateSetupRequest{ShadowMode: &shadow})
+	assert.Equal(t, seconds, res.Data.MaxRuntimeSeconds)
+
+	seconds = 0
+	res = doPatch[server.Response[repo.Repo]](t, f.repoSetupURL(r.ID), repoapi.UpdateSetupRequest{MaxRuntimeSeconds: &seconds})
+	assert.Zero(t, res.Data.MaxRuntimeSeconds)
+}
+
+func TestUpdateSetup_Sync(t *testing.T) {
+	f := newFixture(t)
+	r := f.addRepo("owner/test-repo")
+	assert.False(t, r.SyncDisabled)
+	assert.Zero(t, r.SyncIntervalSeconds)
+
+	disabled, seconds := true, 300
+	res := doPatch[server.Response[repo.Repo]](t, f.repoSetupURL(r.ID), repoapi.UpdateSetupRequest{SyncDisabled: &disabled, SyncIntervalSeconds: &seconds})
+	assert.True(t, res.Data.SyncDisabled)
+	assert.Equal(t, seconds, res.Data.SyncIntervalSeconds)
+
+	disabled, seconds = false, 0
+	res = doPatch[server.Response[repo.Repo]](t, f.repoSetupURL(r.ID), repoapi.UpdateSetupRequest{SyncDisabled: &disabled, SyncIntervalSeconds: &seconds})
+	assert.False(t, res.Data.SyncDisabled)
+	assert.Zero(t, res.Data.SyncIntervalSeconds)
+
+	invalid := 1
+	req, err := http.NewRequest(http.MethodPatch, f.repoSetupURL(r.ID), mustJSONReader(repoapi.UpdateSetupRequest{SyncIntervalSeconds: &invalid}))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	httpRes, err := testutil.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+}
+
+func TestUpdateSetup_ReviewSLA(t *testing.T) {
+	f := newFixture(t)
+	r := f.addRepo("owner/test-repo")
+	assert.Zero(t, r.ReviewSLASeconds)
+
+	seconds := 24 * 60 * 60
+	res := doPatch[server.Response[repo.Repo]](t, f.repoSetupURL(r.ID), repoapi.UpdateSetupRequest{ReviewSLASeconds: &seconds})
+	assert.Equal(t, seconds, res.Data.ReviewSLASeconds)
+
+	seconds = 0
+	res = doPatch[server.Response[repo.Repo]](t, f.repoSetupURL(r.ID), repoapi.UpdateSetupRequest{ReviewSLASeconds: &seconds})
+	assert.Zero(t, res.Data.ReviewSLASeconds)
+
+	invalid := 30
+	req, err := http.NewRequest(http.MethodPatch, f.repoSetupURL(r.ID), mustJSONReader(repoapi.UpdateSetupRequest{ReviewSLASeconds: &invalid}))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	httpRes, err := testutil.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+}
+
+func TestUpdateSetup_RequiredLabels(t *testing.T) {
+	f := newFixture(t)
+	r := f.addRepo("owner/test-repo")
+	assert.Empty(t, r.RequiredLabels)
+
+	labels := []string{"gpu", "region.eu"}
+	res := doPatch[server.Response[repo.Repo]](t, f.repoSetupURL(r.ID), repoapi.UpdateSetupRequest{RequiredLabels: &labels})
+	assert.Equal(t, labels, res.Data.RequiredLabels)
+
+	labels = []string{}
+	res = doPatch[server.Response[repo.Repo]](t, f.repoSetupURL(r.ID), repoapi.UpdateSetupRequest{RequiredLabels: &labels})
+	assert.Empty(t, res.Data.RequiredLabels)
+
+	invalid := []string{"GPU"}
+	req, err := http.NewRequest(http.MethodPatch, f.repoSetupURL(r.ID), mustJSONReader(repoapi.UpdateSetupRequest{RequiredLabels: &invalid}))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	httpRes, err := testutil.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+}
+
+func TestUpdateSetup_IgnoredChecks(t *testing.T) {
+	f := newFixture(t)
+	r := f.addRepo("owner/test-repo")
+	assert.Empty(t, r.IgnoredChecks)
+
+	checks := []string{"nightly-canary", "ci/flaky"}
+	res := doPatch[server.Response[repo.Repo]](t, f.repoSetupURL(r.ID), repoapi.UpdateSetupRequest{IgnoredChecks: &checks})
+	assert.Equal(t, checks, res.Data.IgnoredChecks)
+
+	checks = []string{}
+	res = doPatch[server.Response[repo.Repo]](t, f.repoSetupURL(r.ID), repoapi.UpdateSetupRequest{IgnoredChecks: &checks})
+	assert.Empty(t, res.Data.IgnoredChecks)
+
+	invalid := []string{" "}
+	req, err := http.NewRequest(http.MethodPatch, f.repoSetupURL(r.ID), mustJSONReader(repoapi.UpdateSetupRequest{IgnoredChecks: &invalid}))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	httpRes, err := testutil.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+}
+
+func TestUpdateSetup_CheckGating(t *testing.T) {
+	f := newFixture(t)
+	r := f.addRepo("owner/test-repo")
+	assert.Nil(t, r.CheckGating)
+
+	gating := repo.CheckGating{RequiredOnly: true, Checks: []string{"build", "test"}}
+	res := doPatch[server.Response[repo.Repo]](t, f.repoSetupURL(r.ID), repoapi.UpdateSetupRequest{CheckGating: &gating})
+	require.NotNil(t, res.Data.CheckGating)
+	assert.Equal(t, gating, *res.Data.CheckGating)
+
+	// Gating without required_only lets every check gate again.
+	res = doPatch[server.Response[repo.Repo]](t, f.repoSetupURL(r.ID), repoapi.UpdateSetupRequest{CheckGating: &repo.CheckGating{}})
+	assert.Nil(t, res.Data.CheckGating)
+}
+
+func TestUpdateSetup_FallbackModels(t *testing.T) {
+	f := newFixture(t)
+	r := f.addRepo("owner/test-repo")
+	assert.Empty(t, r.FallbackModels)
+
+	models := []string{"sonnet", "haiku"}
+	res := doPatch[server.Response[repo.Repo]](t, f.repoSetupURL(r.ID), repoapi.UpdateSetupRequest{FallbackModels: &models})
+	assert.Equal(t, models, res.Data.FallbackModels)
+
+	invalid := []string{"sonnet", "sonnet"}
+	req, err := http.NewRequest(http.MethodPatch, f.repoSetupURL(r.ID), mustJSONReader(repoapi.UpdateSetupRequest{FallbackModels: &invalid}))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	httpRes, err := testutil.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+}
+
+func TestSetPaused(t *testing.T) {
+	f := newFixture(t)
+	r := f.addRepo("owner/test-repo")
+	assert.False(t, r.Paused)
+
+	res := testutil.Put[server.Response[repo.Repo]](t, f.repoPausedURL(r.ID), repoapi.SetPausedRequest{Paused: true})
+	assert.True(t, res.Data.Paused)
+
+	stored, err := f.RepoStore.ReadRepo(context.Background(), r.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.Paused)
+
+	res = testutil.Put[server.Response[repo.Repo]](t, f.repoPausedURL(r.ID), repoapi.SetPausedRequest{Paused: false})
+	assert.False(t, res.Data.Paused)
+}
+
+func TestSyncRepo(t *testing.T) {
+	syncer := &fakeSyncer{}
+	f := newFixture(t, repoapi.WithRepoSyncer(syncer))
+	r := f.addRepo("owner/test-repo")
+
+	httpRes, err := testutil.DefaultClient.Post(f.repoSyncURL(r.ID), "application/json", nil)
+	require.NoError(t, err)
+	httpRes.Body.Close()
+	assert.Equal(t, http.StatusAccepted, httpRes.StatusCode)
+	assert.Equal(t, []string{r.ID.String()}, syncer.Requested())
+
+	// A repo with sync disabled can't be synced on demand
+	require.NoError(t, f.RepoStore.UpdateRepoSyncDisabled(context.Background(), r.ID, true))
+	httpRes, err = testutil.DefaultClient.Post(f.repoSyncURL(r.ID), "application/json", nil)
+	require.NoError(t, err)
+	httpRes.Body.Close()
+	assert.Equal(t, http.StatusConflict, httpRes.StatusCode)
+	assert.Len(t, syncer.Requested(), 1)
+}
+
+func TestSyncRepo_NoSyncer(t *testing.T) {
+	f := newFixture(t)
+	r := f.addRepo("owner/test-repo")
+
+	httpRes, err := testutil.DefaultClient.Post(f.repoSyncURL(r.ID), "application/json", nil)
+	require.NoError(t, err)
+	httpRes.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, httpRes.StatusCode)
+}
+
+func TestInvalidateWorkspaceCache(t *testing.T) {
+	f := newFixture(t)
+	r := f.addRepo("owner/test-repo")
+	assert.Equal(t, 0, r.WorkspaceCacheGeneration)
+
+	testutil.Delete(t, f.repoWorkspaceCacheURL(r.ID))
+	testutil.Delete(t, f.repoWorkspaceCacheURL(r.ID))
+
+	stored, err := f.RepoStore.ReadRepo(context.Background(), r.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored.WorkspaceCacheGeneration)
+}
+
+func TestResolveCredentials_NoTokenService(t *testing.T) {
+	f := newFixture(t)
+	r := f.addRepo("owner/test-repo")
+
+	res := testutil.Get[server.Response[githubtoken.Resolution]](t, f.repoCredentialsResolveURL(r.ID))
+	assert.Equal(t, "owner/test-repo", res.Data.RepoFullName)
+	assert.Empty(t, res.Data.Source)
+	assert.Equal(t, []githubtoken.SourceResult{{Source: githubtoken.SourceGlobal, Consulted: true}}, res.Data.Chain)
+}
+
 func TestRescan_Success(t *testing.T) {
 	f := newFixture(t)
 	r := f.addRepo("owner/test-repo")
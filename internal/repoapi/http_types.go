@@ -1,11 +1,17 @@
 package repoapi
 
 import (
+	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/cohesivestack/valgo"
 
+	"github.com/vervesh/verve/internal/pathguard"
+	"github.com/vervesh/verve/internal/prlint"
 	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/task"
+	"github.com/vervesh/verve/internal/team"
 )
 
 // AddRepoRequest is the request body for adding a repo.
@@ -40,6 +46,40 @@ func (r RepoIDRequest) Validate() error {
 	return valgo.In("params", valgo.Is(repo.RepoIDValidator(r.RepoID, "repo_id"))).ToError()
 }
 
+// SetPausedRequest is the request body for pausing or resuming a repo's
+// queue.
+type SetPausedRequest struct {
+	RepoID string `param:"repo_id" json:"-"`
+	Paused bool   `json:"paused"`
+}
+
+func (r SetPausedRequest) Validate() error {
+	return valgo.In("params", valgo.Is(repo.RepoIDValidator(r.RepoID, "repo_id"))).ToError()
+}
+
+// Limits on a repo's ignored and required checks.
+const (
+	maxCheckNames      = 50
+	maxCheckNameLength = 255
+)
+
+// Bounds of a repo's sync interval override: syncing more often than every
+// few seconds only burns GitHub rate limit.
+const (
+	minSyncIntervalSeconds = 5
+	maxSyncIntervalSeconds = 24 * 60 * 60
+)
+
+// Bounds of a repo's review SLA. Overdue reviews are checked every minute.
+const (
+	minReviewSLASeconds = 60
+	maxReviewSLASeconds = 30 * 24 * 60 * 60
+)
+
+// maxTitlePrefixLen caps the PR title prefix of a repo's completion
+// validations.
+const maxTitlePrefixLen = 100
+
 // UpdateSetupRequest is the request body for updating repo setup configuration.
 // All fields are optional — only provided fields are updated.
 type UpdateSetupRequest struct {
@@ -47,11 +87,117 @@ type UpdateSetupRequest struct {
 	Summary      *string  `json:"summary,omitempty"`
 	Expectations *string  `json:"expectations,omitempty"`
 	TechStack    *[]string `json:"tech_stack,omitempty"`
+	// CIWorkflow sets the workflow dispatched for agent pull requests. A
+	// workflow with an empty file clears it.
+	CIWorkflow *repo.CIWorkflow `json:"ci_workflow,omitempty"`
+	// ProtectedPaths replaces the path globs agents must not change. An
+	// empty list removes the protection.
+	ProtectedPaths *[]string `json:"protected_paths,omitempty"`
+	// ShadowMode turns shadow mode on or off. In shadow mode agents record
+	// their diff for review instead of pushing or opening pull requests.
+	ShadowMode *bool `json:"shadow_mode,omitempty"`
+	// CompletionValidations replaces the validations agent pull requests
+	// must pass when the agent reports success. An empty object clears them.
+	CompletionValidations *prlint.Config `json:"completion_validations,omitempty"`
+	// RetryPolicy replaces the repo's override of the instance-wide retry
+	// policy. An empty object clears it.
+	RetryPolicy *task.RetryPolicy `json:"retry_policy,omitempty"`
+	// MaxRuntimeSeconds sets the default max runtime of the repo's tasks.
+	// 0 removes the limit.
+	MaxRuntimeSeconds *int `json:"max_runtime_seconds,omitempty"`
+	// RequiredLabels replaces the worker labels every task in the repo
+	// needs. An empty list lets any worker run the repo's tasks.
+	RequiredLabels *[]string `json:"required_labels,omitempty"`
+	// IgnoredChecks replaces the CI check names, such as known-flaky checks,
+	// that don't count towards the check status of agent PRs. An empty list
+	// counts every check.
+	IgnoredChecks *[]string `json:"ignored_checks,omitempty"`
+	// CheckGating limits the checks that gate agent PRs to the required
+	// ones. Gating without required_only lets every check gate them.
+	CheckGating *repo.CheckGating `json:"check_gating,omitempty"`
+	// FallbackModels replaces the models new tasks fall back to, in order,
+	// when their agent is rate-limited or overloaded. An empty list disables
+	// falling back.
+	FallbackModels *[]string `json:"fallback_models,omitempty"`
+	// SyncDisabled turns background PR sync for the repo off or back on.
+	SyncDisabled *bool `json:"sync_disabled,omitempty"`
+	// SyncIntervalSeconds sets how often the repo's pull requests are
+	// synced, overriding the global sync interval. 0 removes the override.
+	SyncIntervalSeconds *int `json:"sync_interval_seconds,omitempty"`
+	// ReviewSLASeconds sets how long the repo's tasks may sit in review
+	// before they are reported overdue. 0 removes the SLA.
+	ReviewSLASeconds *int `json:"review_sla_seconds,omitempty"`
+	// TeamID moves the repo into the team, whose settings it then inherits.
+	// An empty ID removes it from its team.
+	TeamID       *string  `json:"team_id,omitempty"`
 	MarkReady    bool     `json:"mark_ready"`
 }
 
 func (r UpdateSetupRequest) Validate() error {
-	return valgo.In("params", valgo.Is(repo.RepoIDValidator(r.RepoID, "repo_id"))).ToError()
+	v := valgo.In("params", valgo.Is(repo.RepoIDValidator(r.RepoID, "repo_id")))
+	if r.ProtectedPaths != nil {
+		for i, p := range *r.ProtectedPaths {
+			v = v.Is(valgo.String(p, fmt.Sprintf("protected_paths[%d]", i)).Passing(
+				func(s string) bool { return pathguard.ValidatePattern(s) == nil },
+				"Must be a valid path glob",
+			))
+		}
+	}
+	if cv := r.CompletionValidations; cv != nil {
+		validRegexp := func(s string) bool {
+			_, err := regexp.Compile(s)
+			return err == nil
+		}
+		singleLine := func(s string) bool { return !strings.ContainsAny(s, "\r\n") }
+		v = v.Is(valgo.String(cv.TitlePrefix, "completion_validations.title_prefix").MaxLength(maxTitlePrefixLen).Passing(singleLine, "Must be a single line")).
+			Is(valgo.String(cv.TitlePattern, "completion_validations.title_pattern").Passing(validRegexp, "Must be a valid regular expression")).
+			Is(valgo.String(cv.BranchPattern, "completion_validations.branch_pattern").Passing(validRegexp, "Must be a valid regular expression")).
+			Is(valgo.Int(cv.MaxCommits, "completion_validations.max_commits").GreaterOrEqualTo(0))
+	}
+	if r.RetryPolicy != nil {
+		if err := r.RetryPolicy.Validate(); err != nil {
+			v = v.AddErrorMessage("retry_policy", err.Error())
+		}
+	}
+	if r.MaxRuntimeSeconds != nil {
+		v = v.Is(valgo.Int(*r.MaxRuntimeSeconds, "max_runtime_seconds").GreaterOrEqualTo(0))
+	}
+	if r.RequiredLabels != nil {
+		if err := task.ValidateLabels(*r.RequiredLabels); err != nil {
+			v = v.AddErrorMessage("required_labels", err.Error())
+		}
+	}
+	if r.IgnoredChecks != nil {
+		if len(*r.IgnoredChecks) > maxCheckNames {
+			v = v.AddErrorMessage("ignored_checks", fmt.Sprintf("must have at most %d checks", maxCheckNames))
+		}
+		for i, name := range *r.IgnoredChecks {
+			v = v.Is(valgo.String(name, fmt.Sprintf("ignored_checks[%d]", i)).Not().Blank().MaxLength(maxCheckNameLength))
+		}
+	}
+	if r.CheckGating != nil {
+		if len(r.CheckGating.Checks) > maxCheckNames {
+			v = v.AddErrorMessage("check_gating.checks", fmt.Sprintf("must have at most %d checks", maxCheckNames))
+		}
+		for i, name := range r.CheckGating.Checks {
+			v = v.Is(valgo.String(name, fmt.Sprintf("check_gating.checks[%d]", i)).Not().Blank().MaxLength(maxCheckNameLength))
+		}
+	}
+	if r.FallbackModels != nil {
+		if err := task.ValidateFallbackModels(*r.FallbackModels); err != nil {
+			v = v.AddErrorMessage("fallback_models", err.Error())
+		}
+	}
+	if r.SyncIntervalSeconds != nil && *r.SyncIntervalSeconds != 0 {
+		v = v.Is(valgo.Int(*r.SyncIntervalSeconds, "sync_interval_seconds").Between(minSyncIntervalSeconds, maxSyncIntervalSeconds))
+	}
+	if r.ReviewSLASeconds != nil && *r.ReviewSLASeconds != 0 {
+		v = v.Is(valgo.Int(*r.ReviewSLASeconds, "review_sla_seconds").Between(minReviewSLASeconds, maxReviewSLASeconds))
+	}
+	if r.TeamID != nil && *r.TeamID != "" {
+		v = v.Is(team.TeamIDValidator(*r.TeamID, "team_id"))
+	}
+	return v.ToError()
 }
 
 // SubmitSetupRequest is the request body for submitting repo setup configuration
@@ -0,0 +1,461 @@
+// Package selfreview runs a rules-based review over an agent's pull request
+// diff once the PR is opened. It checks that the diff touches what the task's
+// acceptance criteria talk about, that no TODOs or debugging code were left
+// behind, and that the change stays within file count and size limits. The
+// result is advisory: it is posted as a PR comment and scored on the task,
+// but never blocks the PR.
+package selfreview
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Defaults for the reviewer's thresholds.
+const (
+	DefaultMaxFiles     = 30
+	DefaultMaxFileLines = 500
+	DefaultMaxLines     = 1500
+)
+
+// Check names.
+const (
+	CheckAcceptanceCriteria = "acceptance_criteria"
+	CheckLeftovers          = "leftovers"
+	CheckFileCount          = "file_count"
+	CheckDiffSize           = "diff_size"
+)
+
+// checkWeights are each check's share of the score. Disabled checks are left
+// out and the score is scaled to the remaining weight.
+var checkWeights = map[string]float64{
+	CheckAcceptanceCriteria: 40,
+	CheckLeftovers:          20,
+	CheckFileCount:          20,
+	CheckDiffSize:           20,
+}
+
+// maxFindings caps how many findings a single check lists.
+const maxFindings = 20
+
+// Check is the outcome of one review rule.
+type Check struct {
+	Name     string   `json:"name"`
+	Passed   bool     `json:"passed"`
+	Detail   string   `json:"detail"`
+	Findings []string `json:"findings,omitempty"`
+
+	credit float64 // fraction of the check's weight earned
+}
+
+// Report is the result of reviewing a PR diff. Score runs from 0 to 100.
+type Report struct {
+	Score  int     `json:"score"`
+	Checks []Check `json:"checks"`
+}
+
+// Passed reports whether every check passed.
+func (r *Report) Passed() bool {
+	for _, c := range r.Checks {
+		if !c.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Config configures a Reviewer. For each threshold zero uses the default and
+// a negative value disables the check.
+type Config struct {
+	// MaxFiles is the number of changed files above which the file count
+	// check fails.
+	MaxFiles int
+	// MaxFileLines is the number of changed lines in a single file above
+	// which the diff size check fails.
+	MaxFileLines int
+	// MaxLines is the total number of changed lines above which the diff
+	// size check fails.
+	MaxLines int
+	// SkipLeftovers disables the TODO and debugging code check.
+	SkipLeftovers bool
+}
+
+// Reviewer applies the review rules to PR diffs.
+type Reviewer struct {
+	cfg Config
+}
+
+// NewReviewer creates a new Reviewer.
+func NewReviewer(cfg Config) *Reviewer {
+	if cfg.MaxFiles == 0 {
+		cfg.MaxFiles = DefaultMaxFiles
+	}
+	if cfg.MaxFileLines == 0 {
+		cfg.MaxFileLines = DefaultMaxFileLines
+	}
+	if cfg.MaxLines == 0 {
+		cfg.MaxLines = DefaultMaxLines
+	}
+	return &Reviewer{cfg: cfg}
+}
+
+// Review checks a unified diff against the task's acceptance criteria and
+// the configured thresholds.
+func (r *Reviewer) Review(diff string, criteria []string) *Report {
+	files := parseDiff(diff)
+
+	checks := []Check{checkCriteria(files, criteria)}
+	if !r.cfg.SkipLeftovers {
+		checks = append(checks, checkLeftovers(files))
+	}
+	if r.cfg.MaxFiles > 0 {
+		checks = append(checks, checkFileCount(files, r.cfg.MaxFiles))
+	}
+	if r.cfg.MaxFileLines > 0 || r.cfg.MaxLines > 0 {
+		checks = append(checks, checkDiffSize(files, r.cfg.MaxFileLines, r.cfg.MaxLines))
+	}
+
+	var earned, possible float64
+	for _, c := range checks {
+		earned += checkWeights[c.Name] * c.credit
+		possible += checkWeights[c.Name]
+	}
+	return &Report{Score: int(math.Round(100 * earned / possible)), Checks: checks}
+}
+
+// fileDiff holds the parts of one file's diff the rules look at.
+type fileDiff struct {
+	path    string
+	added   []addedLine
+	changed int // added plus removed lines
+}
+
+type addedLine struct {
+	line int // line number in the new file
+	text string
+}
+
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+func parseDiff(diff string) []*fileDiff {
+	var (
+		files   []*fileDiff
+		cur     *fileDiff
+		inHunk  bool
+		newLine int
+	)
+	sc := bufio.NewScanner(strings.NewReader(diff))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			cur = &fileDiff{}
+			files = append(files, cur)
+			inHunk = false
+		case cur == nil:
+		case !inHunk && strings.HasPrefix(line, "--- "):
+			if p := strings.TrimPrefix(strings.TrimPrefix(line, "--- "), "a/"); p != "/dev/null" {
+				cur.path = p
+			}
+		case !inHunk && strings.HasPrefix(line, "+++ "):
+			if p := strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/"); p != "/dev/null" {
+				cur.path = p
+			}
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+			if m := hunkHeader.FindStringSubmatch(line); m != nil {
+				_, _ = fmt.Sscan(m[1], &newLine)
+			}
+		case !inHunk:
+		case strings.HasPrefix(line, "+"):
+			cur.added = append(cur.added, addedLine{line: newLine, text: line[1:]})
+			cur.changed++
+			newLine++
+		case strings.HasPrefix(line, "-"):
+			cur.changed++
+		case strings.HasPrefix(line, `\`):
+			// "\ No newline at end of file"
+		default:
+			newLine++
+		}
+	}
+	// Renames and mode changes without a hunk have no "---"/"+++" lines.
+	return slices.DeleteFunc(files, func(f *fileDiff) bool { return f.path == "" })
+}
+
+// stopWords are common words in acceptance criteria that say nothing about
+// what the change touches.
+var stopWords = map[string]bool{
+	"able": true, "also": true, "after": true, "before": true, "being": true,
+	"both": true, "each": true, "every": true, "from": true, "have": true,
+	"into": true, "long": true, "make": true, "more": true, "must": true,
+	"only": true, "other": true, "should": true, "some": true, "such": true,
+	"than": true, "that": true, "their": true, "them": true, "then": true,
+	"there": true, "these": true, "they": true, "this": true, "those": true,
+	"when": true, "where": true, "which": true, "while": true, "will": true,
+	"with": true, "within": true, "without": true, "work": true, "works": true,
+	"would": true, "your": true, "user": true, "users": true, "correct": true,
+	"correctly": true, "properly": true, "still": true, "added": true, "adds": true,
+	"existing": true, "code": true, "test": true, "tests": true, "pass": true,
+	"passes": true,
+}
+
+// checkCriteria reports the acceptance criteria whose keywords mostly do not
+// appear in the added lines or changed file paths. A criterion counts as
+// covered when at least half of its keywords appear.
+func checkCriteria(files []*fileDiff, criteria []string) Check {
+	c := Check{Name: CheckAcceptanceCriteria}
+	var nonEmpty []string
+	for _, cr := range criteria {
+		if strings.TrimSpace(cr) != "" {
+			nonEmpty = append(nonEmpty, cr)
+		}
+	}
+	if len(nonEmpty) == 0 {
+		c.Passed, c.credit = true, 1
+		c.Detail = "The task has no acceptance criteria"
+		return c
+	}
+
+	corpus := map[string]bool{}
+	for _, f := range files {
+		for _, w := range words(f.path) {
+			corpus[w] = true
+		}
+		for _, l := range f.added {
+			for _, w := range words(l.text) {
+				corpus[w] = true
+			}
+		}
+	}
+
+	covered := 0
+	for _, cr := range nonEmpty {
+		kws := keywords(cr)
+		hits := 0
+		for _, k := range kws {
+			if corpus[k] {
+				hits++
+			}
+		}
+		if len(kws) == 0 || 2*hits >= len(kws) {
+			covered++
+			continue
+		}
+		c.Findings = append(c.Findings, cr)
+	}
+	c.credit = float64(covered) / float64(len(nonEmpty))
+	c.Passed = covered == len(nonEmpty)
+	c.Detail = fmt.Sprintf("%d of %d acceptance criteria appear to be addressed by the diff", covered, len(nonEmpty))
+	c.Findings = capFindings(c.Findings)
+	return c
+}
+
+// keywords returns the distinct stemmed words of a criterion that are long
+// enough to be meaningful and not stop words.
+func keywords(s string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, w := range words(s) {
+		if len(w) < 4 || stopWords[w] || seen[w] {
+			continue
+		}
+		seen[w] = true
+		out = append(out, w)
+	}
+	return out
+}
+
+// words splits text into lowercase, stemmed words. Identifiers are split at
+// underscores, dashes and camelCase boundaries so "loginHandler" yields
+// "login" and "handler".
+func words(s string) []string {
+	var out []string
+	var cur []rune
+	flush := func() {
+		if len(cur) > 0 {
+			out = append(out, stem(strings.ToLower(string(cur))))
+			cur = cur[:0]
+		}
+	}
+	runes := []rune(s)
+	for i, ch := range runes {
+		switch {
+		case unicode.IsLetter(ch) || unicode.IsDigit(ch):
+			if unicode.IsUpper(ch) && len(cur) > 0 && i > 0 && unicode.IsLower(runes[i-1]) {
+				flush()
+			}
+			cur = append(cur, ch)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return out
+}
+
+// stem strips common English suffixes so "uploads", "uploaded" and
+// "uploading" compare equal.
+func stem(w string) string {
+	for _, suffix := range []string{"ing", "ed", "es", "s"} {
+		if strings.HasSuffix(w, suffix) && len(w)-len(suffix) >= 4 {
+			return strings.TrimSuffix(w, suffix)
+		}
+	}
+	return w
+}
+
+// leftoverPatterns match added lines that look like unfinished work or
+// debugging code.
+var leftoverPatterns = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	{"TODO comment", regexp.MustCompile(`\b(TODO|FIXME|XXX|HACK)\b`)},
+	{"console.log call", regexp.MustCompile(`\bconsole\.(log|debug)\(`)},
+	{"debugger statement", regexp.MustCompile(`^\s*debugger;?\s*$`)},
+	{"Python breakpoint", regexp.MustCompile(`\b(pdb\.set_trace|breakpoint)\(\)`)},
+	{"Ruby breakpoint", regexp.MustCompile(`\bbinding\.(pry|irb)\b`)},
+	{"Rust dbg! macro", regexp.MustCompile(`\bdbg!\(`)},
+	{"Go debug dump", regexp.MustCompile(`\b(spew\.Dump|pp\.Println)\(`)},
+}
+
+// checkLeftovers reports added lines matching leftoverPatterns.
+func checkLeftovers(files []*fileDiff) Check {
+	c := Check{Name: CheckLeftovers}
+	count := 0
+	for _, f := range files {
+		if generatedFiles[path.Base(f.path)] {
+			continue
+		}
+		for _, l := range f.added {
+			for _, p := range leftoverPatterns {
+				if p.pattern.MatchString(l.text) {
+					count++
+					c.Findings = append(c.Findings, fmt.Sprintf("%s:%d: %s", f.path, l.line, p.name))
+					break
+				}
+			}
+		}
+	}
+	c.Passed = count == 0
+	if c.Passed {
+		c.credit = 1
+		c.Detail = "No TODOs or debugging code added"
+	} else {
+		c.Detail = fmt.Sprintf("%d added %s look like TODOs or debugging code", count, plural(count, "line", "lines"))
+	}
+	c.Findings = capFindings(c.Findings)
+	return c
+}
+
+// generatedFiles are lockfiles whose contents the agent doesn't write.
+var generatedFiles = map[string]bool{
+	"package-lock.json": true,
+	"pnpm-lock.yaml":    true,
+	"yarn.lock":         true,
+	"go.sum":            true,
+	"Cargo.lock":        true,
+	"poetry.lock":       true,
+	"Gemfile.lock":      true,
+	"composer.lock":     true,
+}
+
+func checkFileCount(files []*fileDiff, maxFiles int) Check {
+	c := Check{Name: CheckFileCount, Passed: len(files) <= maxFiles}
+	c.Detail = fmt.Sprintf("%d %s changed (limit %d)", len(files), plural(len(files), "file", "files"), maxFiles)
+	if c.Passed {
+		c.credit = 1
+	}
+	return c
+}
+
+// checkDiffSize fails when a single file or the whole diff changes more lines
+// than allowed. Lockfiles count towards neither limit.
+func checkDiffSize(files []*fileDiff, maxFileLines, maxLines int) Check {
+	c := Check{Name: CheckDiffSize}
+	total := 0
+	var large []*fileDiff
+	for _, f := range files {
+		if generatedFiles[path.Base(f.path)] {
+			continue
+		}
+		total += f.changed
+		if maxFileLines > 0 && f.changed > maxFileLines {
+			large = append(large, f)
+		}
+	}
+	sort.SliceStable(large, func(i, j int) bool { return large[i].changed > large[j].changed })
+	for _, f := range large {
+		c.Findings = append(c.Findings, fmt.Sprintf("%s: %d changed lines", f.path, f.changed))
+	}
+
+	overTotal := maxLines > 0 && total > maxLines
+	c.Passed = !overTotal && len(large) == 0
+	if c.Passed {
+		c.credit = 1
+	}
+	switch {
+	case overTotal:
+		c.Detail = fmt.Sprintf("%d changed lines (limit %d)", total, maxLines)
+	case len(large) > 0:
+		c.Detail = fmt.Sprintf("%d %s over %d changed lines", len(large), plural(len(large), "file", "files"), maxFileLines)
+	default:
+		c.Detail = fmt.Sprintf("%d changed lines", total)
+	}
+	c.Findings = capFindings(c.Findings)
+	return c
+}
+
+func capFindings(findings []string) []string {
+	if len(findings) <= maxFindings {
+		return findings
+	}
+	rest := len(findings) - maxFindings
+	return append(findings[:maxFindings:maxFindings], fmt.Sprintf("... and %d more", rest))
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+// CommentMarker identifies the self-review comment on a PR.
+const CommentMarker = "<!-- verve:self-review -->"
+
+// checkTitles are the headings used for each check in the PR comment.
+var checkTitles = map[string]string{
+	CheckAcceptanceCriteria: "Acceptance criteria",
+	CheckLeftovers:          "TODOs and debugging code",
+	CheckFileCount:          "Files changed",
+	CheckDiffSize:           "Diff size",
+}
+
+// Markdown renders the report as a PR comment.
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	b.WriteString(CommentMarker + "\n")
+	fmt.Fprintf(&b, "### Verve self-review: %d/100\n\n", r.Score)
+	for _, c := range r.Checks {
+		mark := "✅"
+		if !c.Passed {
+			mark = "⚠️"
+		}
+		fmt.Fprintf(&b, "%s **%s**: %s\n", mark, checkTitles[c.Name], c.Detail)
+		for _, f := range c.Findings {
+			fmt.Fprintf(&b, "  - %s\n", f)
+		}
+	}
+	b.WriteString("\n<sub>Automated checks run on the agent's diff. They are advisory and do not block merging.</sub>\n")
+	return b.String()
+}
@@ -0,0 +1,124 @@
+package selfreview
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleDiff = `diff --git a/internal/avatar/upload.go b/internal/avatar/upload.go
+new file mode 100644
+index 0000000..1111111
+--- /dev/null
++++ b/internal/avatar/upload.go
+@@ -0,0 +1,6 @@
++package avatar
++
++// UploadAvatar stores a resized profile image.
++func UploadAvatar(userID string, img []byte) error {
++	// TODO: validate the image size
++	return resizeImage(img)
++}
+diff --git a/ui/src/Profile.tsx b/ui/src/Profile.tsx
+index 2222222..3333333 100644
+--- a/ui/src/Profile.tsx
++++ b/ui/src/Profile.tsx
+@@ -10,3 +10,4 @@ export function Profile() {
+ 	const user = useUser();
+-	return <Page user={user} />;
++	console.log(user);
++	return <Page user={user} avatarUpload />;
+ }
+diff --git a/old.txt b/new.txt
+similarity index 100%
+rename from old.txt
+rename to new.txt
+`
+
+func TestReview(t *testing.T) {
+	r := NewReviewer(Config{})
+	report := r.Review(sampleDiff, []string{
+		"Users can upload an avatar image from the profile page",
+		"Avatars are cached by the CDN for one week",
+	})
+
+	require.Len(t, report.Checks, 4)
+
+	criteria := report.Checks[0]
+	assert.Equal(t, CheckAcceptanceCriteria, criteria.Name)
+	assert.False(t, criteria.Passed)
+	assert.Equal(t, "1 of 2 acceptance criteria appear to be addressed by the diff", criteria.Detail)
+	assert.Equal(t, []string{"Avatars are cached by the CDN for one week"}, criteria.Findings)
+
+	leftovers := report.Checks[1]
+	assert.Equal(t, CheckLeftovers, leftovers.Name)
+	assert.False(t, leftovers.Passed)
+	assert.Equal(t, []string{
+		"internal/avatar/upload.go:5: TODO comment",
+		"ui/src/Profile.tsx:11: console.log call",
+	}, leftovers.Findings)
+
+	files := report.Checks[2]
+	assert.Equal(t, CheckFileCount, files.Name)
+	assert.True(t, files.Passed)
+	assert.Equal(t, "2 files changed (limit 30)", files.Detail)
+
+	size := report.Checks[3]
+	assert.Equal(t, CheckDiffSize, size.Name)
+	assert.True(t, size.Passed)
+	assert.Equal(t, "10 changed lines", size.Detail)
+
+	// Half the criteria weight (20) plus file count and size (40) of 100.
+	assert.Equal(t, 60, report.Score)
+	assert.False(t, report.Passed())
+}
+
+func TestReview_NoCriteria(t *testing.T) {
+	report := NewReviewer(Config{}).Review("", nil)
+	assert.Equal(t, 100, report.Score)
+	assert.True(t, report.Passed())
+	assert.Equal(t, "The task has no acceptance criteria", report.Checks[0].Detail)
+}
+
+func TestReview_Thresholds(t *testing.T) {
+	var b strings.Builder
+	for i := range 3 {
+		fmt.Fprintf(&b, "diff --git a/f%d.go b/f%d.go\n--- a/f%d.go\n+++ b/f%d.go\n@@ -1 +1,%d @@\n", i, i, i, i, 5*(i+1))
+		for range 5 * (i + 1) {
+			b.WriteString("+x := 1\n")
+		}
+	}
+
+	report := NewReviewer(Config{MaxFiles: 2, MaxFileLines: 8, MaxLines: 100}).Review(b.String(), nil)
+	require.Len(t, report.Checks, 4)
+	assert.False(t, report.Checks[2].Passed)
+	assert.Equal(t, "3 files changed (limit 2)", report.Checks[2].Detail)
+	assert.False(t, report.Checks[3].Passed)
+	assert.Equal(t, "2 files over 8 changed lines", report.Checks[3].Detail)
+	assert.Equal(t, []string{"f2.go: 15 changed lines", "f1.go: 10 changed lines"}, report.Checks[3].Findings)
+
+	report = NewReviewer(Config{MaxFiles: 10, MaxFileLines: -1, MaxLines: 20}).Review(b.String(), nil)
+	assert.Equal(t, "30 changed lines (limit 20)", report.Checks[3].Detail)
+
+	// Disabled checks are left out of the report and the score.
+	report = NewReviewer(Config{MaxFiles: -1, MaxFileLines: -1, MaxLines: -1, SkipLeftovers: true}).Review(b.String(), nil)
+	require.Len(t, report.Checks, 1)
+	assert.Equal(t, 100, report.Score)
+}
+
+func TestWords(t *testing.T) {
+	assert.Equal(t, []string{"upload", "avatar", "handler", "profile", "page"}, words("uploadAvatarHandler profile_pages"))
+}
+
+func TestReport_Markdown(t *testing.T) {
+	report := NewReviewer(Config{}).Review(sampleDiff, nil)
+	md := report.Markdown()
+	assert.True(t, strings.HasPrefix(md, CommentMarker+"\n"))
+	assert.Contains(t, md, "### Verve self-review: 80/100")
+	assert.Contains(t, md, "⚠️ **TODOs and debugging code**: 2 added lines look like TODOs or debugging code")
+	assert.Contains(t, md, "  - ui/src/Profile.tsx:11: console.log call")
+	assert.Contains(t, md, "✅ **Files changed**: 2 files changed (limit 30)")
+}
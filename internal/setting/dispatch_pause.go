@@ -0,0 +1,44 @@
+package setting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/vervesh/verve/internal/costguard"
+)
+
+var _ costguard.PauseStore = (*Service)(nil)
+
+// ReadDispatchPause returns the active dispatch pause, or nil when dispatch
+// is not paused. It reads the database rather than the cache so that a pause
+// tripped or acknowledged on another replica takes effect immediately.
+func (s *Service) ReadDispatchPause(ctx context.Context) (*costguard.Pause, error) {
+	v, err := s.repo.ReadSetting(ctx, KeyDispatchPause)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var p costguard.Pause
+	if err := json.Unmarshal([]byte(v), &p); err != nil {
+		return nil, fmt.Errorf("parse dispatch pause setting: %w", err)
+	}
+	return &p, nil
+}
+
+// SetDispatchPause stores the dispatch pause.
+func (s *Service) SetDispatchPause(ctx context.Context, p *costguard.Pause) error {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("marshal dispatch pause: %w", err)
+	}
+	return s.Set(ctx, KeyDispatchPause, string(b))
+}
+
+// ClearDispatchPause removes the dispatch pause.
+func (s *Service) ClearDispatchPause(ctx context.Context) error {
+	return s.Delete(ctx, KeyDispatchPause)
+}
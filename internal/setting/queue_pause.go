@@ -0,0 +1,71 @@
+package setting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// QueuePause is an instance-wide maintenance pause set by an operator, such
+// as to drain workers before an upgrade. While it is set agents are handed no
+// new work, but tasks are still accepted and running work is not interrupted.
+type QueuePause struct {
+	Reason   string    `json:"reason,omitempty"`
+	PausedAt time.Time `json:"paused_at"`
+}
+
+// ReadQueuePause returns the queue pause, or nil when the queue is not
+// paused. Like ReadDispatchPause it reads the database rather than the cache
+// so that every replica sees a pause as soon as it is set or lifted.
+func (s *Service) ReadQueuePause(ctx context.Context) (*QueuePause, error) {
+	v, err := s.repo.ReadSetting(ctx, KeyQueuePause)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var p QueuePause
+	if err := json.Unmarshal([]byte(v), &p); err != nil {
+		return nil, fmt.Errorf("parse queue pause setting: %w", err)
+	}
+	return &p, nil
+}
+
+// PauseQueue pauses the queue. When it is already paused the reason is
+// replaced but the time it was paused at is kept.
+func (s *Service) PauseQueue(ctx context.Context, reason string) (*QueuePause, error) {
+	existing, err := s.ReadQueuePause(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p := &QueuePause{Reason: strings.TrimSpace(reason), PausedAt: time.Now().UTC()}
+	if existing != nil {
+		p.PausedAt = existing.PausedAt
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("marshal queue pause: %w", err)
+	}
+	if err := s.Set(ctx, KeyQueuePause, string(b)); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ResumeQueue lifts the queue pause.
+func (s *Service) ResumeQueue(ctx context.Context) error {
+	return s.Delete(ctx, KeyQueuePause)
+}
+
+// QueuePaused reports whether the queue is paused.
+func (s *Service) QueuePaused(ctx context.Context) (bool, error) {
+	p, err := s.ReadQueuePause(ctx)
+	if err != nil {
+		return false, err
+	}
+	return p != nil, nil
+}
@@ -0,0 +1,36 @@
+package setting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/vervesh/verve/internal/task"
+)
+
+// RetryPolicy returns the instance-wide retry policy. The zero policy is
+// returned when none is configured.
+func (s *Service) RetryPolicy() (task.RetryPolicy, error) {
+	var policy task.RetryPolicy
+	v := s.Get(KeyRetryPolicy)
+	if v == "" {
+		return policy, nil
+	}
+	if err := json.Unmarshal([]byte(v), &policy); err != nil {
+		return task.RetryPolicy{}, fmt.Errorf("parse retry policy setting: %w", err)
+	}
+	return policy, nil
+}
+
+// SetRetryPolicy stores the instance-wide retry policy. An empty policy
+// removes the setting so the built-in defaults apply.
+func (s *Service) SetRetryPolicy(ctx context.Context, policy task.RetryPolicy) error {
+	if policy.Empty() {
+		return s.Delete(ctx, KeyRetryPolicy)
+	}
+	b, err := json.Marshal(policy)
+	if err != nil {
+		return fmt.Errorf("marshal retry policy: %w", err)
+	}
+	return s.Set(ctx, KeyRetryPolicy, string(b))
+}
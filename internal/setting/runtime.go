@@ -0,0 +1,106 @@
+package setting
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Runtime setting keys. Changes take effect without a restart.
+const (
+	// KeyDefaultMaxCostUSD holds the budget of tasks created without one
+	// whose repo and team set none.
+	KeyDefaultMaxCostUSD = "default_max_cost_usd"
+	// KeySyncInterval holds how often PR status is synced from GitHub,
+	// overriding SYNC_INTERVAL.
+	KeySyncInterval = "sync_interval"
+	// KeyReapInterval holds how often stale work is timed out, overriding
+	// REAP_INTERVAL.
+	KeyReapInterval = "reap_interval"
+)
+
+// Type is the type of a runtime setting's value.
+type Type string
+
+const (
+	TypeString   Type = "string"
+	TypeFloat    Type = "float"
+	TypeDuration Type = "duration"
+)
+
+// minRuntimeInterval is the shortest interval a background job can be set to
+// run at, so a typo can't flood GitHub or the database.
+const minRuntimeInterval = 5 * time.Second
+
+// Definition describes a setting that can be changed while the server runs.
+type Definition struct {
+	Key         string `json:"key"`
+	Type        Type   `json:"type"`
+	Description string `json:"description"`
+}
+
+// Runtime lists the settings that can be changed while the server runs.
+var Runtime = []Definition{
+	{Key: KeyDefaultModel, Type: TypeString, Description: "Model tasks, epics and conversations use when none is chosen"},
+	{Key: KeyDefaultMaxCostUSD, Type: TypeFloat, Description: "Budget in USD of tasks created without one when their repo and team set none (0 = no budget)"},
+	{Key: KeySyncInterval, Type: TypeDuration, Description: "How often PR status is synced from GitHub (e.g. 30s)"},
+	{Key: KeyReapInterval, Type: TypeDuration, Description: "How often stale tasks, epics and conversations are timed out (e.g. 1m)"},
+}
+
+// LookupRuntime returns the definition of the runtime setting key.
+func LookupRuntime(key string) (Definition, bool) {
+	for _, d := range Runtime {
+		if d.Key == key {
+			return d, true
+		}
+	}
+	return Definition{}, false
+}
+
+// Validate checks value is a valid value of the setting.
+func (d Definition) Validate(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return errors.New("must not be blank")
+	}
+	switch d.Type {
+	case TypeFloat:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return errors.New("must be a number")
+		}
+		if f < 0 {
+			return errors.New("must not be negative")
+		}
+	case TypeDuration:
+		dur, err := time.ParseDuration(value)
+		if err != nil {
+			return errors.New("must be a duration such as 30s or 5m")
+		}
+		if dur < minRuntimeInterval {
+			return fmt.Errorf("must be at least %s", minRuntimeInterval)
+		}
+	}
+	return nil
+}
+
+// Duration returns the value of the duration setting key, or fallback when it
+// is unset or invalid.
+func (s *Service) Duration(key string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(s.Get(key))
+	if err != nil || d < minRuntimeInterval {
+		return fallback
+	}
+	return d
+}
+
+// Float returns the value of the number setting key, or 0 when it is unset or
+// invalid.
+func (s *Service) Float(key string) float64 {
+	f, err := strconv.ParseFloat(s.Get(key), 64)
+	if err != nil || f < 0 {
+		return 0
+	}
+	return f
+}
@@ -3,12 +3,26 @@ package setting
 import (
 	"context"
 	"errors"
+	"slices"
 	"strings"
 	"sync"
 )
 
 // Well-known setting keys.
-const KeyDefaultModel = "default_model"
+const (
+	KeyDefaultModel = "default_model"
+	// KeyRetryPolicy holds the instance-wide task.RetryPolicy as JSON.
+	KeyRetryPolicy = "retry_policy"
+	// KeyDispatchPause holds the active costguard.Pause as JSON while
+	// dispatching new work is paused.
+	KeyDispatchPause = "dispatch_pause"
+	// KeyQueuePause holds the QueuePause as JSON while an operator has
+	// paused the queue.
+	KeyQueuePause = "queue_pause"
+	// KeyOIDC holds the oidc.Config as JSON, with the client secret
+	// encrypted.
+	KeyOIDC = "oidc"
+)
 
 // ErrNotFound is returned when a setting key does not exist.
 var ErrNotFound = errors.New("setting not found")
@@ -60,30 +74,52 @@ type Repository interface {
 	ListSettings(ctx context.Context) (map[string]string, error)
 }
 
-// Service provides cached access to key-value settings.
+// Service provides cached access to key-value settings. Subscribers are
+// notified when a setting changes, whether through this service or, once Load
+// picks it up, on another replica.
 type Service struct {
-	repo  Repository
-	mu    sync.RWMutex
-	cache map[string]string
+	repo     Repository
+	mu       sync.RWMutex
+	cache    map[string]string
+	watchers map[*watcher]struct{}
+}
+
+type watcher struct {
+	keys []string
+	ch   chan struct{}
 }
 
 // NewService creates a new settings service.
 func NewService(repo Repository) *Service {
 	return &Service{
-		repo:  repo,
-		cache: make(map[string]string),
+		repo:     repo,
+		cache:    make(map[string]string),
+		watchers: make(map[*watcher]struct{}),
 	}
 }
 
-// Load reads all settings from the database into the cache.
+// Load reads all settings from the database into the cache, notifying
+// subscribers of the settings that changed since the last load.
 func (s *Service) Load(ctx context.Context) error {
 	settings, err := s.repo.ListSettings(ctx)
 	if err != nil {
 		return err
 	}
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	var changed []string
+	for k, v := range settings {
+		if old, ok := s.cache[k]; !ok || old != v {
+			changed = append(changed, k)
+		}
+	}
+	for k := range s.cache {
+		if _, ok := settings[k]; !ok {
+			changed = append(changed, k)
+		}
+	}
 	s.cache = settings
+	s.mu.Unlock()
+	s.notify(changed...)
 	return nil
 }
 
@@ -100,8 +136,12 @@ func (s *Service) Set(ctx context.Context, key, value string) error {
 		return err
 	}
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	old, ok := s.cache[key]
 	s.cache[key] = value
+	s.mu.Unlock()
+	if !ok || old != value {
+		s.notify(key)
+	}
 	return nil
 }
 
@@ -111,7 +151,43 @@ func (s *Service) Delete(ctx context.Context, key string) error {
 		return err
 	}
 	s.mu.Lock()
-	defer s.mu.Unlock()
+	_, ok := s.cache[key]
 	delete(s.cache, key)
+	s.mu.Unlock()
+	if ok {
+		s.notify(key)
+	}
 	return nil
 }
+
+// Subscribe returns a channel that receives when any of keys changes, and a
+// function that stops the notifications. Changes are coalesced, so receivers
+// read the settings they need after each receive.
+func (s *Service) Subscribe(keys ...string) (<-chan struct{}, func()) {
+	w := &watcher{keys: keys, ch: make(chan struct{}, 1)}
+	s.mu.Lock()
+	s.watchers[w] = struct{}{}
+	s.mu.Unlock()
+	return w.ch, func() {
+		s.mu.Lock()
+		delete(s.watchers, w)
+		s.mu.Unlock()
+	}
+}
+
+func (s *Service) notify(keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for w := range s.watchers {
+		if !slices.ContainsFunc(keys, func(k string) bool { return slices.Contains(w.keys, k) }) {
+			continue
+		}
+		select {
+		case w.ch <- struct{}{}:
+		default: // A notification is already pending
+		}
+	}
+}
@@ -3,12 +3,15 @@ package setting_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/vervesh/verve/internal/costguard"
 	"github.com/vervesh/verve/internal/setting"
 	"github.com/vervesh/verve/internal/sqlite"
+	"github.com/vervesh/verve/internal/task"
 )
 
 func newTestSettingService(t *testing.T) *setting.Service {
@@ -65,6 +68,161 @@ func TestService_Load(t *testing.T) {
 	assert.Equal(t, "value2", svc.Get("key2"))
 }
 
+func TestService_RetryPolicy(t *testing.T) {
+	svc := newTestSettingService(t)
+	ctx := context.Background()
+
+	policy, err := svc.RetryPolicy()
+	require.NoError(t, err)
+	assert.True(t, policy.Empty(), "expected zero policy when none is configured")
+
+	want := task.RetryPolicy{MaxAttempts: 8, CategoryLimits: map[string]int{"rate_limit": 5}}
+	require.NoError(t, svc.SetRetryPolicy(ctx, want))
+	policy, err = svc.RetryPolicy()
+	require.NoError(t, err)
+	assert.Equal(t, want, policy)
+
+	// An empty policy removes the setting
+	require.NoError(t, svc.SetRetryPolicy(ctx, task.RetryPolicy{}))
+	assert.Empty(t, svc.Get(setting.KeyRetryPolicy))
+}
+
+func TestService_DispatchPause(t *testing.T) {
+	db := sqlite.NewTestDB(t)
+	svc := setting.NewService(sqlite.NewSettingRepository(db))
+	other := setting.NewService(sqlite.NewSettingRepository(db))
+	ctx := context.Background()
+
+	p, err := svc.ReadDispatchPause(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, p)
+
+	want := &costguard.Pause{Reason: "runaway spend", PausedAt: time.Now().UTC().Truncate(time.Second)}
+	require.NoError(t, svc.SetDispatchPause(ctx, want))
+
+	// Another replica's service sees the pause without reloading its cache.
+	p, err = other.ReadDispatchPause(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, want, p)
+
+	require.NoError(t, other.ClearDispatchPause(ctx))
+	p, err = svc.ReadDispatchPause(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, p)
+}
+
+func TestService_QueuePause(t *testing.T) {
+	db := sqlite.NewTestDB(t)
+	svc := setting.NewService(sqlite.NewSettingRepository(db))
+	other := setting.NewService(sqlite.NewSettingRepository(db))
+	ctx := context.Background()
+
+	paused, err := svc.QueuePaused(ctx)
+	require.NoError(t, err)
+	assert.False(t, paused)
+
+	first, err := svc.PauseQueue(ctx, " upgrading ")
+	require.NoError(t, err)
+	assert.Equal(t, "upgrading", first.Reason)
+
+	// Another replica's service sees the pause without reloading its cache.
+	paused, err = other.QueuePaused(ctx)
+	require.NoError(t, err)
+	assert.True(t, paused)
+
+	// Pausing again replaces the reason but keeps when it was paused.
+	second, err := other.PauseQueue(ctx, "CI is down")
+	require.NoError(t, err)
+	assert.Equal(t, "CI is down", second.Reason)
+	assert.True(t, first.PausedAt.Equal(second.PausedAt))
+
+	require.NoError(t, svc.ResumeQueue(ctx))
+	p, err := other.ReadQueuePause(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, p)
+}
+
+func TestService_Subscribe(t *testing.T) {
+	db := sqlite.NewTestDB(t)
+	svc := setting.NewService(sqlite.NewSettingRepository(db))
+	other := setting.NewService(sqlite.NewSettingRepository(db))
+	ctx := context.Background()
+
+	changes, stop := svc.Subscribe(setting.KeySyncInterval)
+	defer stop()
+
+	require.NoError(t, svc.Set(ctx, setting.KeyDefaultModel, "opus"))
+	assert.Empty(t, changes, "other keys don't notify")
+
+	require.NoError(t, svc.Set(ctx, setting.KeySyncInterval, "1m"))
+	require.NoError(t, svc.Set(ctx, setting.KeySyncInterval, "2m"))
+	assert.Len(t, changes, 1, "changes are coalesced")
+	<-changes
+
+	require.NoError(t, svc.Set(ctx, setting.KeySyncInterval, "2m"))
+	assert.Empty(t, changes, "unchanged values don't notify")
+
+	// Another replica's change is picked up on the next load.
+	require.NoError(t, other.Set(ctx, setting.KeySyncInterval, "3m"))
+	assert.Empty(t, changes)
+	require.NoError(t, svc.Load(ctx))
+	assert.Len(t, changes, 1)
+	<-changes
+	assert.Equal(t, 3*time.Minute, svc.Duration(setting.KeySyncInterval, time.Minute))
+
+	require.NoError(t, other.Delete(ctx, setting.KeySyncInterval))
+	require.NoError(t, svc.Load(ctx))
+	assert.Len(t, changes, 1)
+	<-changes
+	assert.Equal(t, time.Minute, svc.Duration(setting.KeySyncInterval, time.Minute))
+
+	stop()
+	require.NoError(t, svc.Set(ctx, setting.KeySyncInterval, "4m"))
+	assert.Empty(t, changes)
+}
+
+func TestDefinition_Validate(t *testing.T) {
+	tests := []struct {
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{key: setting.KeyDefaultModel, value: "opus"},
+		{key: setting.KeyDefaultModel, value: " ", wantErr: true},
+		{key: setting.KeyDefaultMaxCostUSD, value: "2.5"},
+		{key: setting.KeyDefaultMaxCostUSD, value: "0"},
+		{key: setting.KeyDefaultMaxCostUSD, value: "lots", wantErr: true},
+		{key: setting.KeyDefaultMaxCostUSD, value: "-1", wantErr: true},
+		{key: setting.KeySyncInterval, value: "45s"},
+		{key: setting.KeySyncInterval, value: "1s", wantErr: true},
+		{key: setting.KeyReapInterval, value: "5", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			d, ok := setting.LookupRuntime(tt.key)
+			require.True(t, ok)
+			err := d.Validate(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	_, ok := setting.LookupRuntime(setting.KeyRetryPolicy)
+	assert.False(t, ok)
+}
+
+func TestService_Float(t *testing.T) {
+	svc := newTestSettingService(t)
+	ctx := context.Background()
+
+	assert.Zero(t, svc.Float(setting.KeyDefaultMaxCostUSD))
+	require.NoError(t, svc.Set(ctx, setting.KeyDefaultMaxCostUSD, "2.5"))
+	assert.InDelta(t, 2.5, svc.Float(setting.KeyDefaultMaxCostUSD), 0.001)
+}
+
 func TestKeyDefaultModel(t *testing.T) {
 	assert.Equal(t, "default_model", setting.KeyDefaultModel)
 }
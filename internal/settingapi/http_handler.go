@@ -1,13 +1,18 @@
 package settingapi
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/joshjon/kit/server"
 	"github.com/labstack/echo/v4"
 
+	"github.com/vervesh/verve/internal/github"
 	"github.com/vervesh/verve/internal/githubtoken"
+	"github.com/vervesh/verve/internal/msgcat"
+	"github.com/vervesh/verve/internal/oidc"
 	"github.com/vervesh/verve/internal/setting"
+	"github.com/vervesh/verve/internal/task"
 )
 
 // HTTPHandler handles settings HTTP requests.
@@ -15,14 +20,30 @@ type HTTPHandler struct {
 	githubTokenService *githubtoken.Service
 	settingService     *setting.Service
 	models             []setting.ModelOption
+	oidcService        *oidc.Service
+}
+
+// Option configures an HTTPHandler.
+type Option func(*HTTPHandler)
+
+// WithOIDC enables the OIDC sign-in settings. The client secret is
+// encrypted, so they need an encryption key like the GitHub token.
+func WithOIDC(s *oidc.Service) Option {
+	return func(h *HTTPHandler) {
+		h.oidcService = s
+	}
 }
 
 // NewHTTPHandler creates a new HTTPHandler.
-func NewHTTPHandler(githubTokenService *githubtoken.Service, settingService *setting.Service, models []setting.ModelOption) *HTTPHandler {
+func NewHTTPHandler(githubTokenService *githubtoken.Service, settingService *setting.Service, models []setting.ModelOption, opts ...Option) *HTTPHandler {
 	if len(models) == 0 {
 		models = setting.DefaultModels
 	}
-	return &HTTPHandler{githubTokenService: githubTokenService, settingService: settingService, models: models}
+	h := &HTTPHandler{githubTokenService: githubTokenService, settingService: settingService, models: models}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // Register adds the endpoints to the provided Echo router group.
@@ -30,16 +51,32 @@ func (h *HTTPHandler) Register(g *echo.Group) {
 	g.PUT("/settings/github-token", h.SaveGitHubToken)
 	g.GET("/settings/github-token", h.GetGitHubTokenStatus)
 	g.DELETE("/settings/github-token", h.DeleteGitHubToken)
+	g.GET("/settings/github-token/rate-limit", h.GetGitHubRateLimit)
+	g.GET("/settings/github-credentials", h.ListGitHubCredentials)
+	g.POST("/settings/github-credentials", h.CreateGitHubCredential)
+	g.PUT("/settings/github-credentials/:credential_id", h.UpdateGitHubCredential)
+	g.DELETE("/settings/github-credentials/:credential_id", h.DeleteGitHubCredential)
 	g.PUT("/settings/default-model", h.SaveDefaultModel)
 	g.GET("/settings/default-model", h.GetDefaultModel)
 	g.DELETE("/settings/default-model", h.DeleteDefaultModel)
+	g.PUT("/settings/retry-policy", h.SaveRetryPolicy)
+	g.GET("/settings/retry-policy", h.GetRetryPolicy)
+	g.DELETE("/settings/retry-policy", h.DeleteRetryPolicy)
+	g.PUT("/settings/queue-paused", h.SaveQueuePaused)
+	g.GET("/settings/queue-paused", h.GetQueuePaused)
+	g.GET("/settings/runtime", h.ListRuntimeSettings)
+	g.PUT("/settings/runtime/:key", h.SaveRuntimeSetting)
+	g.DELETE("/settings/runtime/:key", h.DeleteRuntimeSetting)
+	g.PUT("/settings/oidc", h.SaveOIDC)
+	g.GET("/settings/oidc", h.GetOIDC)
+	g.DELETE("/settings/oidc", h.DeleteOIDC)
 	g.GET("/settings/models", h.ListModels)
 }
 
 // SaveGitHubToken handles PUT /settings/github-token
 func (h *HTTPHandler) SaveGitHubToken(c echo.Context) error {
 	if h.githubTokenService == nil {
-		return echo.NewHTTPError(http.StatusServiceUnavailable, "encryption key not configured")
+		return echo.NewHTTPError(http.StatusServiceUnavailable, msgcat.Text(msgcat.ErrEncryptionKeyNotConfigured))
 	}
 
 	req, err := server.BindRequest[SaveGitHubTokenRequest](c)
@@ -48,6 +85,9 @@ func (h *HTTPHandler) SaveGitHubToken(c echo.Context) error {
 	}
 
 	if err := h.githubTokenService.SaveToken(c.Request().Context(), req.Token); err != nil {
+		if errors.Is(err, githubtoken.ErrTokenManaged) {
+			return echo.NewHTTPError(http.StatusConflict, msgcat.Text(msgcat.ErrGitHubTokenManaged))
+		}
 		return err
 	}
 	return c.NoContent(http.StatusNoContent)
@@ -55,27 +95,124 @@ func (h *HTTPHandler) SaveGitHubToken(c echo.Context) error {
 
 // GetGitHubTokenStatus handles GET /settings/github-token
 func (h *HTTPHandler) GetGitHubTokenStatus(c echo.Context) error {
-	configured := h.githubTokenService != nil && h.githubTokenService.HasToken()
-	fineGrained := h.githubTokenService != nil && h.githubTokenService.IsFineGrained()
-	return server.SetResponse(c, http.StatusOK, GitHubTokenStatusResponse{Configured: configured, FineGrained: fineGrained})
+	if h.githubTokenService == nil {
+		return server.SetResponse(c, http.StatusOK, GitHubTokenStatusResponse{})
+	}
+	res := GitHubTokenStatusResponse{
+		Configured:  h.githubTokenService.HasToken(),
+		FineGrained: h.githubTokenService.IsFineGrained(),
+		Managed:     h.githubTokenService.Managed(),
+	}
+	if err := h.githubTokenService.SecretError(); err != nil {
+		res.Error = err.Error()
+	}
+	return server.SetResponse(c, http.StatusOK, res)
 }
 
 // DeleteGitHubToken handles DELETE /settings/github-token
 func (h *HTTPHandler) DeleteGitHubToken(c echo.Context) error {
 	if h.githubTokenService == nil {
-		return echo.NewHTTPError(http.StatusServiceUnavailable, "encryption key not configured")
+		return echo.NewHTTPError(http.StatusServiceUnavailable, msgcat.Text(msgcat.ErrEncryptionKeyNotConfigured))
 	}
 
 	if err := h.githubTokenService.DeleteToken(c.Request().Context()); err != nil {
+		if errors.Is(err, githubtoken.ErrTokenManaged) {
+			return echo.NewHTTPError(http.StatusConflict, msgcat.Text(msgcat.ErrGitHubTokenManaged))
+		}
 		return err
 	}
 	return c.NoContent(http.StatusNoContent)
 }
 
+// GetGitHubRateLimit handles GET /settings/github-token/rate-limit
+// Returns the global token's rate limit buckets as last reported by GitHub.
+// A bucket is only listed once a request has drawn from it.
+func (h *HTTPHandler) GetGitHubRateLimit(c echo.Context) error {
+	if h.githubTokenService == nil {
+		return server.SetResponseList(c, http.StatusOK, []github.RateLimit{}, "")
+	}
+	return server.SetResponseList(c, http.StatusOK, h.githubTokenService.GetClient().RateLimits(), "")
+}
+
+// ListGitHubCredentials handles GET /settings/github-credentials
+// Secrets are never returned.
+func (h *HTTPHandler) ListGitHubCredentials(c echo.Context) error {
+	if !h.hasGitHubCredentials() {
+		return server.SetResponseList(c, http.StatusOK, []*githubtoken.Credential{}, "")
+	}
+	creds, err := h.githubTokenService.ListCredentials(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return server.SetResponseList(c, http.StatusOK, creds, "")
+}
+
+// CreateGitHubCredential handles POST /settings/github-credentials
+func (h *HTTPHandler) CreateGitHubCredential(c echo.Context) error {
+	if !h.hasGitHubCredentials() {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, msgcat.Text(msgcat.ErrEncryptionKeyNotConfigured))
+	}
+
+	req, err := server.BindRequest[CreateGitHubCredentialRequest](c)
+	if err != nil {
+		return err
+	}
+
+	cred := req.credential()
+	if err := h.githubTokenService.CreateCredential(c.Request().Context(), cred); err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusCreated, cred)
+}
+
+// UpdateGitHubCredential handles PUT /settings/github-credentials/:credential_id
+// An empty secret keeps the current one.
+func (h *HTTPHandler) UpdateGitHubCredential(c echo.Context) error {
+	if !h.hasGitHubCredentials() {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, msgcat.Text(msgcat.ErrEncryptionKeyNotConfigured))
+	}
+
+	req, err := server.BindRequest[UpdateGitHubCredentialRequest](c)
+	if err != nil {
+		return err
+	}
+
+	cred := req.credential()
+	cred.ID = githubtoken.MustParseCredentialID(req.CredentialID)
+	if err := h.githubTokenService.UpdateCredential(c.Request().Context(), cred); err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, cred)
+}
+
+// DeleteGitHubCredential handles DELETE /settings/github-credentials/:credential_id
+// The credential's repos fall back to their owner's credential or the global
+// token.
+func (h *HTTPHandler) DeleteGitHubCredential(c echo.Context) error {
+	if !h.hasGitHubCredentials() {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, msgcat.Text(msgcat.ErrEncryptionKeyNotConfigured))
+	}
+
+	req, err := server.BindRequest[GitHubCredentialIDRequest](c)
+	if err != nil {
+		return err
+	}
+
+	id := githubtoken.MustParseCredentialID(req.CredentialID)
+	if err := h.githubTokenService.DeleteCredential(c.Request().Context(), id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *HTTPHandler) hasGitHubCredentials() bool {
+	return h.githubTokenService != nil && h.githubTokenService.HasCredentials()
+}
+
 // SaveDefaultModel handles PUT /settings/default-model
 func (h *HTTPHandler) SaveDefaultModel(c echo.Context) error {
 	if h.settingService == nil {
-		return echo.NewHTTPError(http.StatusServiceUnavailable, "settings not available")
+		return echo.NewHTTPError(http.StatusServiceUnavailable, msgcat.Text(msgcat.ErrSettingsUnavailable))
 	}
 
 	req, err := server.BindRequest[DefaultModelRequest](c)
@@ -101,7 +238,7 @@ func (h *HTTPHandler) GetDefaultModel(c echo.Context) error {
 // DeleteDefaultModel handles DELETE /settings/default-model
 func (h *HTTPHandler) DeleteDefaultModel(c echo.Context) error {
 	if h.settingService == nil {
-		return echo.NewHTTPError(http.StatusServiceUnavailable, "settings not available")
+		return echo.NewHTTPError(http.StatusServiceUnavailable, msgcat.Text(msgcat.ErrSettingsUnavailable))
 	}
 
 	if err := h.settingService.Delete(c.Request().Context(), setting.KeyDefaultModel); err != nil {
@@ -110,6 +247,192 @@ func (h *HTTPHandler) DeleteDefaultModel(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// SaveRetryPolicy handles PUT /settings/retry-policy
+func (h *HTTPHandler) SaveRetryPolicy(c echo.Context) error {
+	if h.settingService == nil {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, msgcat.Text(msgcat.ErrSettingsUnavailable))
+	}
+
+	req, err := server.BindRequest[RetryPolicyRequest](c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.settingService.SetRetryPolicy(c.Request().Context(), req.RetryPolicy); err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, RetryPolicyResponse{
+		Policy:     req.RetryPolicy,
+		Defaults:   task.DefaultRetryPolicy,
+		Configured: !req.RetryPolicy.Empty(),
+	})
+}
+
+// GetRetryPolicy handles GET /settings/retry-policy
+func (h *HTTPHandler) GetRetryPolicy(c echo.Context) error {
+	var policy task.RetryPolicy
+	if h.settingService != nil {
+		var err error
+		if policy, err = h.settingService.RetryPolicy(); err != nil {
+			return err
+		}
+	}
+	return server.SetResponse(c, http.StatusOK, RetryPolicyResponse{
+		Policy:     policy,
+		Defaults:   task.DefaultRetryPolicy,
+		Configured: !policy.Empty(),
+	})
+}
+
+// DeleteRetryPolicy handles DELETE /settings/retry-policy
+func (h *HTTPHandler) DeleteRetryPolicy(c echo.Context) error {
+	if h.settingService == nil {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, msgcat.Text(msgcat.ErrSettingsUnavailable))
+	}
+
+	if err := h.settingService.Delete(c.Request().Context(), setting.KeyRetryPolicy); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// SaveQueuePaused handles PUT /settings/queue-paused
+func (h *HTTPHandler) SaveQueuePaused(c echo.Context) error {
+	if h.settingService == nil {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, msgcat.Text(msgcat.ErrSettingsUnavailable))
+	}
+
+	req, err := server.BindRequest[QueuePausedRequest](c)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if !req.Paused {
+		if err := h.settingService.ResumeQueue(ctx); err != nil {
+			return err
+		}
+		return server.SetResponse(c, http.StatusOK, newQueuePausedResponse(nil))
+	}
+	p, err := h.settingService.PauseQueue(ctx, req.Reason)
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, newQueuePausedResponse(p))
+}
+
+// GetQueuePaused handles GET /settings/queue-paused
+func (h *HTTPHandler) GetQueuePaused(c echo.Context) error {
+	var p *setting.QueuePause
+	if h.settingService != nil {
+		var err error
+		if p, err = h.settingService.ReadQueuePause(c.Request().Context()); err != nil {
+			return err
+		}
+	}
+	return server.SetResponse(c, http.StatusOK, newQueuePausedResponse(p))
+}
+
+// ListRuntimeSettings handles GET /settings/runtime
+func (h *HTTPHandler) ListRuntimeSettings(c echo.Context) error {
+	settings := make([]RuntimeSetting, len(setting.Runtime))
+	for i, d := range setting.Runtime {
+		settings[i] = RuntimeSetting{Definition: d}
+		if h.settingService != nil {
+			settings[i].Value = h.settingService.Get(d.Key)
+		}
+	}
+	return server.SetResponseList(c, http.StatusOK, settings, "")
+}
+
+// SaveRuntimeSetting handles PUT /settings/runtime/:key
+// The change takes effect without a restart.
+func (h *HTTPHandler) SaveRuntimeSetting(c echo.Context) error {
+	if h.settingService == nil {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, msgcat.Text(msgcat.ErrSettingsUnavailable))
+	}
+
+	req, err := server.BindRequest[RuntimeSettingRequest](c)
+	if err != nil {
+		return err
+	}
+
+	d, _ := setting.LookupRuntime(req.Key)
+	if err := h.settingService.Set(c.Request().Context(), d.Key, req.Value); err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, RuntimeSetting{Definition: d, Value: req.Value})
+}
+
+// DeleteRuntimeSetting handles DELETE /settings/runtime/:key
+// The server's configured default applies again.
+func (h *HTTPHandler) DeleteRuntimeSetting(c echo.Context) error {
+	if h.settingService == nil {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, msgcat.Text(msgcat.ErrSettingsUnavailable))
+	}
+
+	req, err := server.BindRequest[RuntimeSettingKeyRequest](c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.settingService.Delete(c.Request().Context(), req.Key); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// SaveOIDC handles PUT /settings/oidc
+func (h *HTTPHandler) SaveOIDC(c echo.Context) error {
+	if h.oidcService == nil {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, msgcat.Text(msgcat.ErrEncryptionKeyNotConfigured))
+	}
+
+	req, err := server.BindRequest[OIDCRequest](c)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if err := h.oidcService.SaveConfig(ctx, req.Config); err != nil {
+		return err
+	}
+	cfg, err := h.oidcService.Config(ctx)
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, newOIDCResponse(cfg))
+}
+
+// GetOIDC handles GET /settings/oidc
+// The client secret is never returned, only whether one is set.
+func (h *HTTPHandler) GetOIDC(c echo.Context) error {
+	if h.oidcService == nil {
+		return server.SetResponse(c, http.StatusOK, OIDCResponse{})
+	}
+	cfg, err := h.oidcService.Config(c.Request().Context())
+	if errors.Is(err, oidc.ErrNotConfigured) {
+		return server.SetResponse(c, http.StatusOK, OIDCResponse{})
+	}
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, newOIDCResponse(cfg))
+}
+
+// DeleteOIDC handles DELETE /settings/oidc
+// Sessions already issued last until they expire.
+func (h *HTTPHandler) DeleteOIDC(c echo.Context) error {
+	if h.oidcService == nil {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, msgcat.Text(msgcat.ErrEncryptionKeyNotConfigured))
+	}
+
+	if err := h.oidcService.DeleteConfig(c.Request().Context()); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
 // ListModels handles GET /settings/models
 func (h *HTTPHandler) ListModels(c echo.Context) error {
 	return server.SetResponseList(c, http.StatusOK, h.models, "")
@@ -54,10 +54,30 @@ func (f *fixture) defaultModelURL() string {
 	return fmt.Sprintf("%s/api/v1/settings/default-model", f.Server.Address())
 }
 
+func (f *fixture) retryPolicyURL() string {
+	return fmt.Sprintf("%s/api/v1/settings/retry-policy", f.Server.Address())
+}
+
+func (f *fixture) queuePausedURL() string {
+	return fmt.Sprintf("%s/api/v1/settings/queue-paused", f.Server.Address())
+}
+
+func (f *fixture) runtimeSettingsURL() string {
+	return fmt.Sprintf("%s/api/v1/settings/runtime", f.Server.Address())
+}
+
 func (f *fixture) githubTokenURL() string {
 	return fmt.Sprintf("%s/api/v1/settings/github-token", f.Server.Address())
 }
 
+func (f *fixture) githubCredentialsURL() string {
+	return fmt.Sprintf("%s/api/v1/settings/github-credentials", f.Server.Address())
+}
+
+func (f *fixture) oidcURL() string {
+	return fmt.Sprintf("%s/api/v1/settings/oidc", f.Server.Address())
+}
+
 func mustJSONReader(v any) io.Reader {
 	b, err := json.Marshal(v)
 	if err != nil {
@@ -3,13 +3,19 @@ package settingapi_test
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/joshjon/kit/server"
 	"github.com/joshjon/kit/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/vervesh/verve/internal/github"
+	"github.com/vervesh/verve/internal/githubtoken"
+	"github.com/vervesh/verve/internal/oidc"
+	"github.com/vervesh/verve/internal/setting"
 	"github.com/vervesh/verve/internal/settingapi"
+	"github.com/vervesh/verve/internal/task"
 )
 
 func TestGetDefaultModel_Default(t *testing.T) {
@@ -43,6 +49,109 @@ func TestSaveDefaultModel_EmptyModel(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, res.StatusCode, "expected validation error for empty model")
 }
 
+func TestRetryPolicy(t *testing.T) {
+	f := newFixture(t)
+
+	res := testutil.Get[server.Response[settingapi.RetryPolicyResponse]](t, f.retryPolicyURL())
+	assert.False(t, res.Data.Configured)
+	assert.Equal(t, task.DefaultRetryPolicy, res.Data.Defaults)
+
+	req := settingapi.RetryPolicyRequest{RetryPolicy: task.RetryPolicy{MaxAttempts: 8, CategoryLimits: map[string]int{"rate_limit": 5}}}
+	res = testutil.Put[server.Response[settingapi.RetryPolicyResponse]](t, f.retryPolicyURL(), req)
+	assert.True(t, res.Data.Configured)
+
+	res = testutil.Get[server.Response[settingapi.RetryPolicyResponse]](t, f.retryPolicyURL())
+	assert.Equal(t, req.RetryPolicy, res.Data.Policy)
+
+	testutil.Delete(t, f.retryPolicyURL())
+	res = testutil.Get[server.Response[settingapi.RetryPolicyResponse]](t, f.retryPolicyURL())
+	assert.False(t, res.Data.Configured)
+}
+
+func TestSaveRetryPolicy_Invalid(t *testing.T) {
+	f := newFixture(t)
+
+	req := settingapi.RetryPolicyRequest{RetryPolicy: task.RetryPolicy{MaxAttempts: -1}}
+	httpReq, err := http.NewRequest(http.MethodPut, f.retryPolicyURL(), mustJSONReader(req))
+	require.NoError(t, err)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	res, err := testutil.DefaultClient.Do(httpReq)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func TestQueuePaused(t *testing.T) {
+	f := newFixture(t)
+
+	res := testutil.Get[server.Response[settingapi.QueuePausedResponse]](t, f.queuePausedURL())
+	assert.False(t, res.Data.Paused)
+
+	res = testutil.Put[server.Response[settingapi.QueuePausedResponse]](t, f.queuePausedURL(), settingapi.QueuePausedRequest{Paused: true, Reason: "upgrading"})
+	assert.True(t, res.Data.Paused)
+	assert.Equal(t, "upgrading", res.Data.Reason)
+	require.NotNil(t, res.Data.PausedAt)
+
+	res = testutil.Get[server.Response[settingapi.QueuePausedResponse]](t, f.queuePausedURL())
+	assert.True(t, res.Data.Paused)
+	assert.Equal(t, "upgrading", res.Data.Reason)
+
+	res = testutil.Put[server.Response[settingapi.QueuePausedResponse]](t, f.queuePausedURL(), settingapi.QueuePausedRequest{Paused: false})
+	assert.False(t, res.Data.Paused)
+	assert.Nil(t, res.Data.PausedAt)
+
+	res = testutil.Get[server.Response[settingapi.QueuePausedResponse]](t, f.queuePausedURL())
+	assert.False(t, res.Data.Paused)
+}
+
+func TestRuntimeSettings(t *testing.T) {
+	f := newFixture(t)
+
+	list := testutil.Get[server.ResponseList[settingapi.RuntimeSetting]](t, f.runtimeSettingsURL())
+	require.Len(t, list.Data, len(setting.Runtime))
+	for _, s := range list.Data {
+		assert.Empty(t, s.Value)
+	}
+
+	res := testutil.Put[server.Response[settingapi.RuntimeSetting]](t, f.runtimeSettingsURL()+"/"+setting.KeySyncInterval, settingapi.RuntimeSettingRequest{Value: "2m"})
+	assert.Equal(t, setting.TypeDuration, res.Data.Type)
+	assert.Equal(t, "2m", res.Data.Value)
+	assert.Equal(t, 2*time.Minute, f.SettingService.Duration(setting.KeySyncInterval, time.Minute))
+
+	testutil.Delete(t, f.runtimeSettingsURL()+"/"+setting.KeySyncInterval)
+	assert.Equal(t, time.Minute, f.SettingService.Duration(setting.KeySyncInterval, time.Minute))
+}
+
+func TestSaveRuntimeSetting_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value string
+	}{
+		{name: "unknown key", key: "colour", value: "blue"},
+		{name: "bad duration", key: setting.KeySyncInterval, value: "often"},
+		{name: "interval too short", key: setting.KeyReapInterval, value: "1ms"},
+		{name: "negative budget", key: setting.KeyDefaultMaxCostUSD, value: "-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+
+			httpReq, err := http.NewRequest(http.MethodPut, f.runtimeSettingsURL()+"/"+tt.key, mustJSONReader(settingapi.RuntimeSettingRequest{Value: tt.value}))
+			require.NoError(t, err)
+			httpReq.Header.Set("Content-Type", "application/json")
+
+			res, err := testutil.DefaultClient.Do(httpReq)
+			require.NoError(t, err)
+			defer res.Body.Close()
+
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		})
+	}
+}
+
 func TestGetGitHubTokenStatus_NotConfigured(t *testing.T) {
 	f := newFixture(t)
 
@@ -64,3 +173,63 @@ func TestSaveGitHubToken_NoService(t *testing.T) {
 
 	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
 }
+
+func TestGetGitHubRateLimit_NotConfigured(t *testing.T) {
+	f := newFixture(t)
+
+	res := testutil.Get[server.ResponseList[github.RateLimit]](t, f.githubTokenURL()+"/rate-limit")
+	assert.Empty(t, res.Data, "expected no rate limits when no token service")
+}
+
+func TestListGitHubCredentials_NotConfigured(t *testing.T) {
+	f := newFixture(t)
+
+	res := testutil.Get[server.ResponseList[githubtoken.Credential]](t, f.githubCredentialsURL())
+	assert.Empty(t, res.Data, "expected no credentials when no token service")
+}
+
+func TestCreateGitHubCredential_NoService(t *testing.T) {
+	f := newFixture(t)
+
+	req := settingapi.CreateGitHubCredentialRequest{GitHubCredentialRequest: settingapi.GitHubCredentialRequest{
+		Name:   "acme",
+		Kind:   githubtoken.CredentialToken,
+		Repos:  []string{"acme/*"},
+		Secret: "ghp_test",
+	}}
+	httpReq, err := http.NewRequest(http.MethodPost, f.githubCredentialsURL(), mustJSONReader(req))
+	require.NoError(t, err)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	res, err := testutil.DefaultClient.Do(httpReq)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+}
+
+func TestGetOIDC_NotConfigured(t *testing.T) {
+	f := newFixture(t)
+
+	res := testutil.Get[server.Response[settingapi.OIDCResponse]](t, f.oidcURL())
+	assert.False(t, res.Data.Configured, "expected configured=false when no oidc service")
+}
+
+func TestSaveOIDC_NoService(t *testing.T) {
+	f := newFixture(t)
+
+	req := settingapi.OIDCRequest{Config: oidc.Config{
+		IssuerURL:   "https://idp.example.com",
+		ClientID:    "verve",
+		RedirectURL: "https://verve.example.com/api/v1/auth/oidc/callback",
+	}}
+	httpReq, err := http.NewRequest(http.MethodPut, f.oidcURL(), mustJSONReader(req))
+	require.NoError(t, err)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	res, err := testutil.DefaultClient.Do(httpReq)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+}
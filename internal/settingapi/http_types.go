@@ -1,9 +1,14 @@
 package settingapi
 
 import (
+	"time"
+
 	"github.com/cohesivestack/valgo"
 
 	"github.com/vervesh/verve/internal/githubtoken"
+	"github.com/vervesh/verve/internal/oidc"
+	"github.com/vervesh/verve/internal/setting"
+	"github.com/vervesh/verve/internal/task"
 )
 
 // SaveGitHubTokenRequest is the request body for saving a GitHub token.
@@ -21,9 +26,73 @@ func (r SaveGitHubTokenRequest) Validate() error {
 }
 
 // GitHubTokenStatusResponse indicates whether a GitHub token is configured.
+// Managed tokens are read from a secrets manager and can't be changed here;
+// Error reports why the last read failed.
 type GitHubTokenStatusResponse struct {
-	Configured  bool `json:"configured"`
-	FineGrained bool `json:"fine_grained,omitempty"`
+	Configured  bool   `json:"configured"`
+	FineGrained bool   `json:"fine_grained,omitempty"`
+	Managed     bool   `json:"managed,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// GitHubCredentialIDRequest captures the :credential_id path parameter.
+type GitHubCredentialIDRequest struct {
+	CredentialID string `param:"credential_id" json:"-"`
+}
+
+func (r GitHubCredentialIDRequest) Validate() error {
+	return valgo.In("params", valgo.Is(githubtoken.CredentialIDValidator(r.CredentialID, "credential_id"))).ToError()
+}
+
+// GitHubCredentialRequest is the request body for a GitHub credential. Secret
+// is the personal access token or the GitHub App's PEM private key.
+type GitHubCredentialRequest struct {
+	Name           string                     `json:"name"`
+	Kind           githubtoken.CredentialKind `json:"kind"`
+	Repos          []string                   `json:"repos"`
+	Secret         string                     `json:"secret,omitempty"`
+	AppID          int64                      `json:"app_id,omitempty"`
+	InstallationID int64                      `json:"installation_id,omitempty"`
+}
+
+func (r GitHubCredentialRequest) credential() *githubtoken.Credential {
+	return &githubtoken.Credential{
+		Name:           r.Name,
+		Kind:           r.Kind,
+		Repos:          r.Repos,
+		Secret:         r.Secret,
+		AppID:          r.AppID,
+		InstallationID: r.InstallationID,
+	}
+}
+
+// CreateGitHubCredentialRequest is the request body for creating a GitHub
+// credential.
+type CreateGitHubCredentialRequest struct {
+	GitHubCredentialRequest
+}
+
+func (r CreateGitHubCredentialRequest) Validate() error {
+	v := valgo.Is(valgo.String(r.Secret, "secret").Not().Blank())
+	if err := r.credential().Validate(); err != nil {
+		v = v.AddErrorMessage("credential", err.Error())
+	}
+	return v.ToError()
+}
+
+// UpdateGitHubCredentialRequest is the request body for updating a GitHub
+// credential. An empty secret keeps the current one.
+type UpdateGitHubCredentialRequest struct {
+	CredentialID string `param:"credential_id" json:"-"`
+	GitHubCredentialRequest
+}
+
+func (r UpdateGitHubCredentialRequest) Validate() error {
+	v := valgo.In("params", valgo.Is(githubtoken.CredentialIDValidator(r.CredentialID, "credential_id")))
+	if err := r.credential().Validate(); err != nil {
+		v = v.AddErrorMessage("credential", err.Error())
+	}
+	return v.ToError()
 }
 
 // DefaultModelRequest is the request body for setting the default model.
@@ -40,3 +109,119 @@ type DefaultModelResponse struct {
 	Model      string `json:"model"`
 	Configured bool   `json:"configured"`
 }
+
+// RetryPolicyRequest is the request body for setting the instance-wide retry
+// policy. Unset fields fall back to the built-in defaults.
+type RetryPolicyRequest struct {
+	task.RetryPolicy
+}
+
+func (r RetryPolicyRequest) Validate() error {
+	if err := r.RetryPolicy.Validate(); err != nil {
+		return valgo.AddErrorMessage("retry_policy", err.Error()).ToError()
+	}
+	return nil
+}
+
+// RetryPolicyResponse is the response for getting the instance-wide retry
+// policy.
+type RetryPolicyResponse struct {
+	Policy task.RetryPolicy `json:"policy"`
+	// Defaults are the built-in values used for fields the policy leaves unset.
+	Defaults   task.RetryPolicy `json:"defaults"`
+	Configured bool             `json:"configured"`
+}
+
+// maxQueuePauseReasonLen caps the reason given for pausing the queue.
+const maxQueuePauseReasonLen = 500
+
+// QueuePausedRequest is the request body for pausing or resuming the queue.
+// While it is paused agents are handed no new work; tasks can still be
+// created.
+type QueuePausedRequest struct {
+	Paused bool   `json:"paused"`
+	Reason string `json:"reason,omitempty"`
+}
+
+func (r QueuePausedRequest) Validate() error {
+	return valgo.Is(valgo.String(r.Reason, "reason").MaxLength(maxQueuePauseReasonLen)).ToError()
+}
+
+// QueuePausedResponse reports whether the queue is paused.
+type QueuePausedResponse struct {
+	Paused   bool       `json:"paused"`
+	Reason   string     `json:"reason,omitempty"`
+	PausedAt *time.Time `json:"paused_at,omitempty"`
+}
+
+func newQueuePausedResponse(p *setting.QueuePause) QueuePausedResponse {
+	if p == nil {
+		return QueuePausedResponse{}
+	}
+	return QueuePausedResponse{Paused: true, Reason: p.Reason, PausedAt: &p.PausedAt}
+}
+
+// RuntimeSettingKeyRequest captures the :key path parameter.
+type RuntimeSettingKeyRequest struct {
+	Key string `param:"key" json:"-"`
+}
+
+func (r RuntimeSettingKeyRequest) Validate() error {
+	return valgo.In("params", valgo.Is(runtimeKeyValidator(r.Key))).ToError()
+}
+
+// RuntimeSettingRequest is the request body for changing a runtime setting.
+type RuntimeSettingRequest struct {
+	Key   string `param:"key" json:"-"`
+	Value string `json:"value"`
+}
+
+func (r RuntimeSettingRequest) Validate() error {
+	v := valgo.In("params", valgo.Is(runtimeKeyValidator(r.Key)))
+	if d, ok := setting.LookupRuntime(r.Key); ok {
+		if err := d.Validate(r.Value); err != nil {
+			v.AddErrorMessage("value", err.Error())
+		}
+	}
+	return v.ToError()
+}
+
+func runtimeKeyValidator(key string) valgo.Validator {
+	return valgo.String(key, "key").Passing(func(k string) bool {
+		_, ok := setting.LookupRuntime(k)
+		return ok
+	}, "Must be a runtime setting")
+}
+
+// RuntimeSetting is a setting that can be changed while the server runs.
+// Value is empty while the server's configured default applies.
+type RuntimeSetting struct {
+	setting.Definition
+	Value string `json:"value"`
+}
+
+// OIDCRequest is the request body for configuring OIDC sign-in. An empty
+// client secret keeps the one already configured.
+type OIDCRequest struct {
+	oidc.Config
+}
+
+func (r OIDCRequest) Validate() error {
+	if err := r.Config.Validate(); err != nil {
+		return valgo.AddErrorMessage("oidc", err.Error()).ToError()
+	}
+	return nil
+}
+
+// OIDCResponse is the response for getting the OIDC sign-in configuration.
+type OIDCResponse struct {
+	Config          oidc.Config `json:"config"` // The client secret is left out
+	HasClientSecret bool        `json:"has_client_secret"`
+	Configured      bool        `json:"configured"`
+}
+
+func newOIDCResponse(cfg *oidc.Config) OIDCResponse {
+	res := OIDCResponse{Config: *cfg, HasClientSecret: cfg.ClientSecret != "", Configured: true}
+	res.Config.ClientSecret = ""
+	return res
+}
@@ -0,0 +1,71 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/vervesh/verve/internal/audit"
+	"github.com/vervesh/verve/internal/sqlite/sqlc"
+)
+
+var _ audit.Repository = (*AuditRepository)(nil)
+
+// AuditRepository implements audit.Repository using SQLite.
+type AuditRepository struct {
+	db *sqlc.Queries
+}
+
+// NewAuditRepository creates a new AuditRepository backed by the given SQLite DB.
+func NewAuditRepository(db DB) *AuditRepository {
+	return &AuditRepository{
+		db: sqlc.New(db),
+	}
+}
+
+func (r *AuditRepository) CreateEntry(ctx context.Context, entry *audit.Entry) error {
+	return r.db.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ID:         entry.ID.String(),
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		FromStatus: entry.FromStatus,
+		ToStatus:   entry.ToStatus,
+		Reason:     entry.Reason,
+		CreatedAt:  entry.CreatedAt.Unix(),
+	})
+}
+
+func (r *AuditRepository) ListEntries(ctx context.Context, entityID string, limit int) ([]*audit.Entry, error) {
+	var (
+		rows []*sqlc.AuditLog
+		err  error
+	)
+	if entityID != "" {
+		rows, err = r.db.ListAuditLogsByEntity(ctx, sqlc.ListAuditLogsByEntityParams{
+			EntityID: entityID,
+			Limit:    int64(limit),
+		})
+	} else {
+		rows, err = r.db.ListAuditLogs(ctx, int64(limit))
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*audit.Entry, len(rows))
+	for i := range rows {
+		out[i] = unmarshalAuditLog(rows[i])
+	}
+	return out, nil
+}
+
+func unmarshalAuditLog(in *sqlc.AuditLog) *audit.Entry {
+	return &audit.Entry{
+		ID:         audit.MustParseEntryID(in.ID),
+		Action:     in.Action,
+		EntityType: in.EntityType,
+		EntityID:   in.EntityID,
+		FromStatus: in.FromStatus,
+		ToStatus:   in.ToStatus,
+		Reason:     in.Reason,
+		CreatedAt:  unixToTime(in.CreatedAt),
+	}
+}
@@ -0,0 +1,156 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// ErrSnapshotTooNew is returned when restoring a snapshot taken by a newer
+// release, whose schema this release doesn't know.
+var ErrSnapshotTooNew = errors.New("snapshot schema is newer than this release")
+
+// preRestoreSuffix is appended to the database files replaced by a restore.
+const preRestoreSuffix = ".pre-restore"
+
+// Backup writes a consistent snapshot of the database to dest with VACUUM
+// INTO. The snapshot is taken in a single read transaction, so writes carry
+// on while it is made, and it is one compacted file with no WAL. It uses a
+// connection of its own so it doesn't hold up the writer.
+//
+// dest must not exist. The snapshot is written next to it and renamed into
+// place, so dest never holds a partial snapshot.
+func (d *FileDB) Backup(ctx context.Context, dest string) error {
+	if _, err := os.Stat(dest); err == nil {
+		return fmt.Errorf("backup destination %s already exists", dest)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("stat backup destination: %w", err)
+	}
+
+	q := url.Values{}
+	q.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", defaultBusyTimeout.Milliseconds()))
+	conn, err := openConns(ctx, "file:"+d.path+"?"+q.Encode(), 1)
+	if err != nil {
+		return fmt.Errorf("open backup connection: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	partial := dest + ".partial"
+	_ = os.Remove(partial)
+	if _, err := conn.ExecContext(ctx, "VACUUM INTO ?", partial); err != nil {
+		_ = os.Remove(partial)
+		return fmt.Errorf("vacuum into %s: %w", partial, err)
+	}
+	if err := os.Rename(partial, dest); err != nil {
+		_ = os.Remove(partial)
+		return fmt.Errorf("move backup into place: %w", err)
+	}
+	return nil
+}
+
+// CheckSnapshot reads the schema status of the snapshot at path relative to
+// the migrations in fsys, without modifying it. It fails if the snapshot
+// isn't a migrated database, was left dirty by a failed migration, or is at a
+// version newer than fsys knows about. Snapshots from older releases pass
+// and are brought up to date by the usual migrations.
+func CheckSnapshot(ctx context.Context, path string, fsys fs.FS) (MigrationStatus, error) {
+	if _, err := os.Stat(path); err != nil {
+		return MigrationStatus{}, fmt.Errorf("open snapshot: %w", err)
+	}
+	q := url.Values{}
+	q.Set("mode", "ro")
+	q.Add("_pragma", "query_only(1)")
+	db, err := openConns(ctx, "file:"+path+"?"+q.Encode(), 1)
+	if err != nil {
+		return MigrationStatus{}, fmt.Errorf("open snapshot: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	status, err := ReadMigrationStatus(ctx, db, fsys)
+	if err != nil {
+		return MigrationStatus{}, fmt.Errorf("read snapshot schema: %w", err)
+	}
+	if status.Current == 0 {
+		return MigrationStatus{}, fmt.Errorf("snapshot %s has no schema version: not a verve database", path)
+	}
+	if status.Dirty {
+		return MigrationStatus{}, fmt.Errorf("snapshot schema is dirty at version %d: a migration failed part way before it was taken", status.Current)
+	}
+	migrations, err := ListMigrations(fsys)
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+	if len(migrations) > 0 && status.Current > migrations[len(migrations)-1].Version {
+		return MigrationStatus{}, fmt.Errorf("%w: snapshot is at version %d but this release only knows version %d",
+			ErrSnapshotTooNew, status.Current, migrations[len(migrations)-1].Version)
+	}
+	return status, nil
+}
+
+// RestoreFile replaces the database <name>.db in dir with the snapshot at
+// src once it passes CheckSnapshot, and returns the snapshot's schema
+// status. Nothing may have the database open: stop every server using it
+// first. The replaced database and its WAL are kept next to it with a
+// ".pre-restore" suffix, replacing those of any earlier restore.
+func RestoreFile(ctx context.Context, dir, name, src string, fsys fs.FS) (MigrationStatus, error) {
+	status, err := CheckSnapshot(ctx, src, fsys)
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return MigrationStatus{}, fmt.Errorf("create sqlite dir: %w", err)
+	}
+	path := filepath.Join(dir, name+".db")
+
+	// Copy first so a failed copy leaves the current database untouched.
+	restoring := path + ".restoring"
+	if err := copyFile(src, restoring); err != nil {
+		_ = os.Remove(restoring)
+		return MigrationStatus{}, fmt.Errorf("copy snapshot: %w", err)
+	}
+
+	// A WAL left behind would be replayed into the restored file, so the
+	// old one moves aside with the database it belongs to.
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		old := path + suffix
+		if err := os.Remove(old + preRestoreSuffix); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			_ = os.Remove(restoring)
+			return MigrationStatus{}, fmt.Errorf("remove previous pre-restore file: %w", err)
+		}
+		if err := os.Rename(old, old+preRestoreSuffix); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			_ = os.Remove(restoring)
+			return MigrationStatus{}, fmt.Errorf("move current database aside: %w", err)
+		}
+	}
+	if err := os.Rename(restoring, path); err != nil {
+		return MigrationStatus{}, fmt.Errorf("move snapshot into place: %w", err)
+	}
+	return status, nil
+}
+
+func copyFile(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,74 @@
+package sqlite_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/sqlite"
+	"github.com/vervesh/verve/internal/sqlite/migrations"
+)
+
+func TestFileDB_BackupAndRestore(t *testing.T) {
+	ctx := context.Background()
+	db := newFileDB(t, t.TempDir())
+	require.NoError(t, sqlite.Migrate(ctx, db.Writer(), migrations.FS))
+
+	r, err := repo.NewRepo("owner/test-repo")
+	require.NoError(t, err)
+	require.NoError(t, sqlite.NewRepoRepository(db).CreateRepo(ctx, r))
+
+	snapshot := filepath.Join(t.TempDir(), "snapshot.db")
+	require.NoError(t, db.Backup(ctx, snapshot))
+	assert.Error(t, db.Backup(ctx, snapshot), "an existing backup is never overwritten")
+
+	status, err := sqlite.CheckSnapshot(ctx, snapshot, migrations.FS)
+	require.NoError(t, err)
+	assert.Empty(t, status.Pending)
+
+	// Restore over a database that has diverged from the snapshot.
+	target := t.TempDir()
+	existing, err := sqlite.OpenFile(ctx, target, "verve")
+	require.NoError(t, err)
+	require.NoError(t, sqlite.Migrate(ctx, existing.Writer(), migrations.FS))
+	require.NoError(t, existing.Close())
+
+	_, err = sqlite.RestoreFile(ctx, target, "verve", snapshot, migrations.FS)
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(target, "verve.db.pre-restore"))
+	assert.NoError(t, err, "the replaced database is kept")
+
+	restored := newFileDB(t, target)
+	got, err := sqlite.NewRepoRepository(restored).ReadRepo(ctx, r.ID)
+	require.NoError(t, err)
+	assert.Equal(t, r.FullName, got.FullName)
+}
+
+func TestCheckSnapshot_Rejects(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := sqlite.CheckSnapshot(ctx, filepath.Join(t.TempDir(), "missing.db"), migrations.FS)
+		assert.Error(t, err)
+	})
+
+	t.Run("unmigrated", func(t *testing.T) {
+		db := newFileDB(t, t.TempDir())
+		_, err := sqlite.CheckSnapshot(ctx, db.Path(), migrations.FS)
+		assert.ErrorContains(t, err, "not a verve database")
+	})
+
+	t.Run("newer release", func(t *testing.T) {
+		db := newFileDB(t, t.TempDir())
+		require.NoError(t, sqlite.Migrate(ctx, db.Writer(), migrations.FS))
+		older := fstest.MapFS{"0001_init.up.sql": {Data: []byte("SELECT 1;")}}
+		_, err := sqlite.CheckSnapshot(ctx, db.Path(), older)
+		assert.ErrorIs(t, err, sqlite.ErrSnapshotTooNew)
+	})
+}
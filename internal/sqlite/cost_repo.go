@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/vervesh/verve/internal/costguard"
+	"github.com/vervesh/verve/internal/sqlite/sqlc"
+)
+
+var _ costguard.Repository = (*CostRepository)(nil)
+
+// CostRepository implements costguard.Repository using SQLite.
+type CostRepository struct {
+	db *sqlc.Queries
+}
+
+// NewCostRepository creates a new CostRepository backed by the given SQLite DB.
+func NewCostRepository(db DB) *CostRepository {
+	return &CostRepository{db: sqlc.New(db)}
+}
+
+func (r *CostRepository) ListFinishedTaskCosts(ctx context.Context, limit int) ([]costguard.TaskCost, error) {
+	rows, err := r.db.ListFinishedTaskCosts(ctx, int64(limit))
+	if err != nil {
+		return nil, err
+	}
+	costs := make([]costguard.TaskCost, len(rows))
+	for i, row := range rows {
+		costs[i] = costguard.TaskCost{TaskID: row.ID, RepoID: row.RepoID, Title: row.Title, CostUSD: row.CostUsd}
+	}
+	return costs, nil
+}
+
+func (r *CostRepository) ListActiveTaskCosts(ctx context.Context) ([]costguard.TaskCost, error) {
+	rows, err := r.db.ListActiveTaskCosts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	costs := make([]costguard.TaskCost, len(rows))
+	for i, row := range rows {
+		costs[i] = costguard.TaskCost{TaskID: row.ID, RepoID: row.RepoID, Title: row.Title, CostUSD: row.CostUsd}
+	}
+	return costs, nil
+}
+
+func (r *CostRepository) ListRepoSpendSince(ctx context.Context, since time.Time) ([]costguard.RepoSpend, error) {
+	rows, err := r.db.ListRepoSpendSince(ctx, since.Unix())
+	if err != nil {
+		return nil, err
+	}
+	spend := make([]costguard.RepoSpend, len(rows))
+	for i, row := range rows {
+		spend[i] = costguard.RepoSpend{RepoID: row.RepoID, CostUSD: row.CostUsd}
+	}
+	return spend, nil
+}
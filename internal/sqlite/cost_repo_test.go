@@ -0,0 +1,58 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/costguard"
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/sqlite"
+	"github.com/vervesh/verve/internal/task"
+)
+
+func TestCostRepository(t *testing.T) {
+	db := sqlite.NewTestDB(t)
+	ctx := context.Background()
+
+	r, err := repo.NewRepo("owner/test-repo")
+	require.NoError(t, err)
+	require.NoError(t, sqlite.NewRepoRepository(db).CreateRepo(ctx, r))
+
+	tasks := sqlite.NewTaskRepository(db)
+	seed := func(title string, status task.Status, cost float64) *task.Task {
+		tsk := task.NewTask(r.ID.String(), title, "", nil, nil, 0, false, false, "", true)
+		require.NoError(t, tasks.CreateTask(ctx, tsk))
+		require.NoError(t, tasks.UpdateTaskStatus(ctx, tsk.ID, status))
+		if cost > 0 {
+			require.NoError(t, tasks.StartTaskAttempt(ctx, tsk.ID, 1, "", "sonnet"))
+			require.NoError(t, tasks.AddCost(ctx, tsk.ID, cost))
+			require.NoError(t, tasks.AddTaskAttemptCost(ctx, tsk.ID, cost))
+		}
+		return tsk
+	}
+	merged := seed("merged", task.StatusMerged, 1.5)
+	seed("failed without cost", task.StatusFailed, 0)
+	running := seed("running", task.StatusRunning, 4)
+
+	costs := sqlite.NewCostRepository(db)
+
+	finished, err := costs.ListFinishedTaskCosts(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []costguard.TaskCost{{TaskID: merged.ID.String(), RepoID: r.ID.String(), Title: "merged", CostUSD: 1.5}}, finished)
+
+	active, err := costs.ListActiveTaskCosts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []costguard.TaskCost{{TaskID: running.ID.String(), RepoID: r.ID.String(), Title: "running", CostUSD: 4}}, active)
+
+	spend, err := costs.ListRepoSpendSince(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []costguard.RepoSpend{{RepoID: r.ID.String(), CostUSD: 5.5}}, spend)
+
+	spend, err = costs.ListRepoSpendSince(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, spend)
+}
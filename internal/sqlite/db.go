@@ -7,7 +7,7 @@ import (
 )
 
 // DB is the interface required by the sqlite package for database access.
-// It is satisfied by *sql.DB.
+// It is satisfied by *sql.DB and *FileDB.
 type DB interface {
 	sqlc.DBTX
 	tx.SQLiteTxer
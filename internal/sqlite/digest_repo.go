@@ -0,0 +1,62 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/vervesh/verve/internal/digest"
+	"github.com/vervesh/verve/internal/sqlite/sqlc"
+	"github.com/vervesh/verve/internal/task"
+)
+
+var _ digest.Repository = (*DigestRepository)(nil)
+
+// DigestRepository implements digest.Repository using SQLite.
+type DigestRepository struct {
+	db *sqlc.Queries
+}
+
+// NewDigestRepository creates a new DigestRepository backed by the given SQLite DB.
+func NewDigestRepository(db DB) *DigestRepository {
+	return &DigestRepository{db: sqlc.New(db)}
+}
+
+func (r *DigestRepository) ListTasksUpdatedSince(ctx context.Context, repoID string, since time.Time) ([]*task.Task, error) {
+	rows, err := r.db.ListRepoTasksUpdatedSince(ctx, sqlc.ListRepoTasksUpdatedSinceParams{
+		RepoID: repoID,
+		Since:  since.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalTaskList(rows), nil
+}
+
+func (r *DigestRepository) ListAttemptsSince(ctx context.Context, repoID string, since time.Time) ([]digest.Attempt, error) {
+	rows, err := r.db.ListRepoTaskAttemptsSince(ctx, sqlc.ListRepoTaskAttemptsSinceParams{
+		RepoID: repoID,
+		Since:  since.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	attempts := make([]digest.Attempt, len(rows))
+	for i, row := range rows {
+		attempts[i] = digest.Attempt{
+			TaskID:    row.TaskID,
+			TaskTitle: row.TaskTitle,
+			Attempt: task.Attempt{
+				Number:      int(row.Attempt),
+				RetryReason: row.RetryReason,
+				Result:      row.Result,
+				CostUSD:     row.CostUsd,
+				StartedAt:   unixToTime(row.StartedAt),
+				EndedAt:     unixPtrToTimePtr(row.EndedAt),
+			},
+		}
+		if row.TaskNumber != nil {
+			attempts[i].TaskNumber = int(*row.TaskNumber)
+		}
+	}
+	return attempts, nil
+}
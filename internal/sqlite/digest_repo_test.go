@@ -0,0 +1,58 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/sqlite"
+	"github.com/vervesh/verve/internal/task"
+)
+
+func TestDigestRepository(t *testing.T) {
+	db := sqlite.NewTestDB(t)
+	ctx := context.Background()
+
+	r, err := repo.NewRepo("owner/test-repo")
+	require.NoError(t, err)
+	require.NoError(t, sqlite.NewRepoRepository(db).CreateRepo(ctx, r))
+
+	tasks := sqlite.NewTaskRepository(db)
+	tsk := task.NewTask(r.ID.String(), "merged", "", nil, nil, 0, false, false, "", true)
+	require.NoError(t, tasks.CreateTask(ctx, tsk))
+	require.NoError(t, tasks.StartTaskAttempt(ctx, tsk.ID, 1, "", "sonnet"))
+	require.NoError(t, tasks.AddTaskAttemptCost(ctx, tsk.ID, 1.5))
+	require.NoError(t, tasks.EndTaskAttempt(ctx, tsk.ID, string(task.StatusMerged)))
+	require.NoError(t, tasks.UpdateTaskStatus(ctx, tsk.ID, task.StatusMerged))
+
+	digests := sqlite.NewDigestRepository(db)
+	since := time.Now().Add(-time.Hour)
+
+	updated, err := digests.ListTasksUpdatedSince(ctx, r.ID.String(), since)
+	require.NoError(t, err)
+	require.Len(t, updated, 1)
+	assert.Equal(t, tsk.ID, updated[0].ID)
+	assert.Equal(t, task.StatusMerged, updated[0].Status)
+
+	attempts, err := digests.ListAttemptsSince(ctx, r.ID.String(), since)
+	require.NoError(t, err)
+	require.Len(t, attempts, 1)
+	assert.Equal(t, tsk.ID.String(), attempts[0].TaskID)
+	assert.Equal(t, "merged", attempts[0].TaskTitle)
+	assert.Equal(t, 1, attempts[0].Number)
+	assert.Equal(t, string(task.StatusMerged), attempts[0].Result)
+	assert.InDelta(t, 1.5, attempts[0].CostUSD, 0.001)
+	assert.NotNil(t, attempts[0].EndedAt)
+
+	updated, err = digests.ListTasksUpdatedSince(ctx, "repo_other", since)
+	require.NoError(t, err)
+	assert.Empty(t, updated)
+
+	attempts, err = digests.ListAttemptsSince(ctx, r.ID.String(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, attempts)
+}
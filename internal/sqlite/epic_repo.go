@@ -43,6 +43,10 @@ func (r *EpicRepository) CreateEpic(ctx context.Context, e *epic.Epic) error {
 	if e.Model != "" {
 		model = &e.Model
 	}
+	var maxCost *float64
+	if e.MaxCostUSD > 0 {
+		maxCost = &e.MaxCostUSD
+	}
 	err := r.db.CreateEpic(ctx, sqlc.CreateEpicParams{
 		ID:             e.ID.String(),
 		RepoID:         e.RepoID,
@@ -55,6 +59,7 @@ func (r *EpicRepository) CreateEpic(ctx context.Context, e *epic.Epic) error {
 		SessionLog:     string(sessionLogJSON),
 		NotReady:       notReady,
 		Model:          model,
+		MaxCostUsd:     maxCost,
 		CreatedAt:      e.CreatedAt.Unix(),
 		UpdatedAt:      e.UpdatedAt.Unix(),
 	})
@@ -128,6 +133,10 @@ func (r *EpicRepository) UpdateEpic(ctx context.Context, e *epic.Epic) error {
 	if e.Model != "" {
 		model = &e.Model
 	}
+	var maxCost *float64
+	if e.MaxCostUSD > 0 {
+		maxCost = &e.MaxCostUSD
+	}
 	return tagEpicErr(r.db.UpdateEpic(ctx, sqlc.UpdateEpicParams{
 		Title:          e.Title,
 		Description:    e.Description,
@@ -138,6 +147,7 @@ func (r *EpicRepository) UpdateEpic(ctx context.Context, e *epic.Epic) error {
 		SessionLog:     string(sessionLogJSON),
 		NotReady:       notReady,
 		Model:          model,
+		MaxCostUsd:     maxCost,
 		ID:             e.ID.String(),
 	}))
 }
@@ -175,12 +185,50 @@ func (r *EpicRepository) AppendSessionLog(ctx context.Context, id epic.EpicID, l
 	_ = json.Unmarshal([]byte(existing.SessionLog), &existingLog)
 	existingLog = append(existingLog, lines...)
 	logJSON, _ := json.Marshal(existingLog)
+	var transcript []epic.TranscriptEntry
+	_ = json.Unmarshal([]byte(existing.Transcript), &transcript)
+	transcript = append(transcript, epic.NewTranscriptEntries(lines, time.Now())...)
+	transcriptJSON, _ := json.Marshal(transcript)
 	return tagEpicErr(r.db.AppendSessionLog(ctx, sqlc.AppendSessionLogParams{
 		SessionLog: string(logJSON),
+		Transcript: string(transcriptJSON),
 		ID:         id.String(),
 	}))
 }
 
+func (r *EpicRepository) AddEpicCost(ctx context.Context, id epic.EpicID, costUSD float64) error {
+	return tagEpicErr(r.db.AddEpicCost(ctx, sqlc.AddEpicCostParams{
+		CostUsd: costUSD,
+		ID:      id.String(),
+	}))
+}
+
+func (r *EpicRepository) SetPlanningSummary(ctx context.Context, id epic.EpicID, summary string) error {
+	var ps *string
+	if summary != "" {
+		ps = &summary
+	}
+	return tagEpicErr(r.db.SetEpicPlanningSummary(ctx, sqlc.SetEpicPlanningSummaryParams{
+		PlanningSummary: ps,
+		ID:              id.String(),
+	}))
+}
+
+func (r *EpicRepository) SetSharedBranch(ctx context.Context, id epic.EpicID, shared bool) error {
+	return tagEpicErr(r.db.SetEpicSharedBranch(ctx, sqlc.SetEpicSharedBranchParams{
+		SharedBranch: boolToInt64(shared),
+		ID:           id.String(),
+	}))
+}
+
+func (r *EpicRepository) SetPullRequest(ctx context.Context, id epic.EpicID, prURL string, prNumber int) error {
+	return tagEpicErr(r.db.SetEpicPullRequest(ctx, sqlc.SetEpicPullRequestParams{
+		PullRequestUrl: &prURL,
+		PrNumber:       ptr(int64(prNumber)),
+		ID:             id.String(),
+	}))
+}
+
 func (r *EpicRepository) DeleteEpic(ctx context.Context, id epic.EpicID) error {
 	return tagEpicErr(r.db.DeleteEpic(ctx, id.String()))
 }
@@ -268,6 +316,8 @@ func unmarshalEpic(in *sqlc.Epic) *epic.Epic {
 		LastHeartbeatAt: unixPtrToTimePtr(in.LastHeartbeatAt),
 		Feedback:        in.Feedback,
 		FeedbackType:    in.FeedbackType,
+		CostUSD:         in.CostUsd,
+		SharedBranch:    in.SharedBranch != 0,
 		CreatedAt:       unixToTime(in.CreatedAt),
 		UpdatedAt:       unixToTime(in.UpdatedAt),
 	}
@@ -280,6 +330,18 @@ func unmarshalEpic(in *sqlc.Epic) *epic.Epic {
 	if in.Model != nil {
 		e.Model = *in.Model
 	}
+	if in.MaxCostUsd != nil {
+		e.MaxCostUSD = *in.MaxCostUsd
+	}
+	if in.PlanningSummary != nil {
+		e.PlanningSummary = *in.PlanningSummary
+	}
+	if in.PullRequestUrl != nil {
+		e.PullRequestURL = *in.PullRequestUrl
+	}
+	if in.PrNumber != nil {
+		e.PRNumber = int(*in.PrNumber)
+	}
 	_ = json.Unmarshal([]byte(in.ProposedTasks), &e.ProposedTasks)
 	if e.ProposedTasks == nil {
 		e.ProposedTasks = []epic.ProposedTask{}
@@ -292,6 +354,10 @@ func unmarshalEpic(in *sqlc.Epic) *epic.Epic {
 	if e.SessionLog == nil {
 		e.SessionLog = []string{}
 	}
+	_ = json.Unmarshal([]byte(in.Transcript), &e.Transcript)
+	if e.Transcript == nil {
+		e.Transcript = []epic.TranscriptEntry{}
+	}
 	return e
 }
 
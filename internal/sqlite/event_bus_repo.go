@@ -0,0 +1,46 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/vervesh/verve/internal/eventbus"
+	"github.com/vervesh/verve/internal/sqlite/sqlc"
+)
+
+var _ eventbus.Repository = (*EventBusRepository)(nil)
+
+// EventBusRepository implements eventbus.Repository using SQLite.
+type EventBusRepository struct {
+	db *sqlc.Queries
+}
+
+// NewEventBusRepository creates a new EventBusRepository backed by the given
+// SQLite DB.
+func NewEventBusRepository(db DB) *EventBusRepository {
+	return &EventBusRepository{db: sqlc.New(db)}
+}
+
+func (r *EventBusRepository) AppendBusEvent(ctx context.Context, origin string, payload []byte) error {
+	return r.db.AppendBusEvent(ctx, sqlc.AppendBusEventParams{Origin: origin, Payload: string(payload)})
+}
+
+func (r *EventBusRepository) ListBusEventsAfter(ctx context.Context, afterID int64, limit int) ([]*eventbus.Message, error) {
+	rows, err := r.db.ListBusEventsAfter(ctx, sqlc.ListBusEventsAfterParams{ID: afterID, Limit: int64(limit)})
+	if err != nil {
+		return nil, err
+	}
+	msgs := make([]*eventbus.Message, len(rows))
+	for i, row := range rows {
+		msgs[i] = &eventbus.Message{ID: row.ID, Origin: row.Origin, Payload: []byte(row.Payload)}
+	}
+	return msgs, nil
+}
+
+func (r *EventBusRepository) LatestBusEventID(ctx context.Context) (int64, error) {
+	return r.db.LatestBusEventID(ctx)
+}
+
+func (r *EventBusRepository) DeleteBusEventsBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.db.DeleteBusEventsBefore(ctx, before.Unix())
+}
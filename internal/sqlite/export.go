@@ -0,0 +1,349 @@
+package sqlite
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ExportFormatVersion is the version of the export layout written by Export.
+const ExportFormatVersion = 1
+
+// exportManifestFile is the name of the manifest in an export directory.
+const exportManifestFile = "manifest.json"
+
+// ErrImportTargetNotEmpty is returned when importing into a database that
+// already holds data in the exported tables.
+var ErrImportTargetNotEmpty = errors.New("import target database is not empty")
+
+// exportTable is a table included in exports. Tables are listed parents
+// first, so importing them in order satisfies their foreign keys.
+type exportTable struct {
+	name string
+	// key is the column other exported tables reference, if any.
+	key string
+	// refs maps a column to the exported table whose key it references.
+	refs map[string]string
+}
+
+var exportTables = []exportTable{
+	{name: "team", key: "id"},
+	{name: "repo", key: "id", refs: map[string]string{"team_id": "team"}},
+	{name: "epic", key: "id", refs: map[string]string{"repo_id": "repo"}},
+	{name: "task", key: "id", refs: map[string]string{"repo_id": "repo", "epic_id": "epic"}},
+	{name: "task_attempt", refs: map[string]string{"task_id": "task"}},
+	{name: "task_log", refs: map[string]string{"task_id": "task"}},
+	{name: "task_log_archive", refs: map[string]string{"task_id": "task"}},
+	{name: "task_comment", refs: map[string]string{"task_id": "task"}},
+	{name: "setting"},
+}
+
+// ExportManifest describes an export directory. Each table is stored next to
+// it as <table>.jsonl, one JSON object per row keyed by column name.
+type ExportManifest struct {
+	FormatVersion int           `json:"format_version"`
+	SchemaVersion uint          `json:"schema_version"`
+	ExportedAt    time.Time     `json:"exported_at"`
+	Tables        []ExportCount `json:"tables"`
+}
+
+// ExportCount is the number of rows exported from a table.
+type ExportCount struct {
+	Table string `json:"table"`
+	Rows  int    `json:"rows"`
+}
+
+// Export writes the repos, teams, epics, tasks with their attempts, logs
+// (archived or not) and comments, and settings in db to dir as JSON lines,
+// along with a manifest recording schemaVersion. Rows hold plain column
+// values, so the export can be imported into any backend at the same schema
+// version. dir is created if needed and must not already hold an export.
+func Export(ctx context.Context, db DB, dir string, schemaVersion uint) (*ExportManifest, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create export dir: %w", err)
+	}
+	manifestPath := filepath.Join(dir, exportManifestFile)
+	if _, err := os.Stat(manifestPath); err == nil {
+		return nil, fmt.Errorf("%s already holds an export", dir)
+	}
+
+	m := &ExportManifest{
+		FormatVersion: ExportFormatVersion,
+		SchemaVersion: schemaVersion,
+		ExportedAt:    time.Now().UTC(),
+	}
+	for _, t := range exportTables {
+		n, err := exportTableRows(ctx, db, t.name, filepath.Join(dir, t.name+".jsonl"))
+		if err != nil {
+			return nil, fmt.Errorf("export %s: %w", t.name, err)
+		}
+		m.Tables = append(m.Tables, ExportCount{Table: t.name, Rows: n})
+	}
+
+	// The manifest is written last, so a directory with one holds a complete
+	// export.
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(manifestPath, append(b, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("write manifest: %w", err)
+	}
+	return m, nil
+}
+
+func exportTableRows(ctx context.Context, db DB, table, path string) (int, error) {
+	rows, err := db.QueryContext(ctx, "SELECT * FROM "+table)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = rows.Close() }()
+	cols, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = f.Close() }()
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+
+	n := 0
+	values := make([]any, len(cols))
+	ptrs := make([]any, len(cols))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return n, err
+		}
+		row := make(map[string]any, len(cols))
+		for i, col := range cols {
+			// Drivers may return TEXT as bytes, which JSON would encode as
+			// base64.
+			if b, ok := values[i].([]byte); ok {
+				row[col] = string(b)
+			} else {
+				row[col] = values[i]
+			}
+		}
+		if err := enc.Encode(row); err != nil {
+			return n, err
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return n, err
+	}
+	if err := w.Flush(); err != nil {
+		return n, err
+	}
+	return n, f.Sync()
+}
+
+// ReadExportManifest reads the manifest of the export in dir.
+func ReadExportManifest(dir string) (*ExportManifest, error) {
+	b, err := os.ReadFile(filepath.Join(dir, exportManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	var m ExportManifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+	return &m, nil
+}
+
+// Import loads the export in dir into db, which must be migrated to
+// schemaVersion and hold no data in the exported tables. Every reference
+// between exported rows, such as a task's repo, is checked before anything
+// is written, and the rows are inserted in one transaction so a failed
+// import leaves db untouched.
+func Import(ctx context.Context, db DB, dir string, schemaVersion uint) (*ExportManifest, error) {
+	m, err := ReadExportManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	if m.FormatVersion != ExportFormatVersion {
+		return nil, fmt.Errorf("unsupported export format version %d", m.FormatVersion)
+	}
+	if m.SchemaVersion != schemaVersion {
+		return nil, fmt.Errorf("export is at schema version %d but the target database is at version %d: export and import with the same release", m.SchemaVersion, schemaVersion)
+	}
+
+	tables := make(map[string][]map[string]any, len(exportTables))
+	for _, t := range exportTables {
+		rows, err := readExportRows(filepath.Join(dir, t.name+".jsonl"))
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", t.name, err)
+		}
+		tables[t.name] = rows
+	}
+	if err := checkExportRefs(tables); err != nil {
+		return nil, err
+	}
+
+	for _, t := range exportTables {
+		var n int
+		if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+t.name).Scan(&n); err != nil {
+			return nil, fmt.Errorf("count %s: %w", t.name, err)
+		}
+		if n > 0 {
+			return nil, fmt.Errorf("%w: table %s has %d rows", ErrImportTargetNotEmpty, t.name, n)
+		}
+	}
+
+	txn, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = txn.Rollback() }()
+	for _, t := range exportTables {
+		if err := importTableRows(ctx, txn, t.name, tables[t.name]); err != nil {
+			return nil, fmt.Errorf("import %s: %w", t.name, err)
+		}
+	}
+	if err := txn.Commit(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func readExportRows(path string) ([]map[string]any, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var rows []map[string]any
+	dec := json.NewDecoder(bufio.NewReader(f))
+	// Numbers stay exact until they're converted for the column.
+	dec.UseNumber()
+	for dec.More() {
+		var row map[string]any
+		if err := dec.Decode(&row); err != nil {
+			return nil, fmt.Errorf("row %d: %w", len(rows)+1, err)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// checkExportRefs checks that every reference between exported rows points
+// at a row in the export.
+func checkExportRefs(tables map[string][]map[string]any) error {
+	keys := map[string]map[string]bool{}
+	for _, t := range exportTables {
+		if t.key == "" {
+			continue
+		}
+		keys[t.name] = map[string]bool{}
+		for _, row := range tables[t.name] {
+			keys[t.name][fmt.Sprint(row[t.key])] = true
+		}
+	}
+	var errs []error
+	for _, t := range exportTables {
+		for i, row := range tables[t.name] {
+			for col, parent := range t.refs {
+				v, ok := row[col]
+				if !ok || v == nil {
+					continue
+				}
+				if !keys[parent][fmt.Sprint(v)] {
+					errs = append(errs, fmt.Errorf("%s row %d: %s %v references a missing %s", t.name, i+1, col, v, parent))
+				}
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("export fails referential integrity: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func importTableRows(ctx context.Context, txn *sql.Tx, table string, rows []map[string]any) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	// Take the columns from the target so a column the export doesn't know
+	// about fails loudly instead of being dropped.
+	target, err := tableColumns(ctx, txn, table)
+	if err != nil {
+		return err
+	}
+
+	stmts := map[string]*sql.Stmt{}
+	defer func() {
+		for _, s := range stmts {
+			_ = s.Close()
+		}
+	}()
+	for i, row := range rows {
+		cols := make([]string, 0, len(row))
+		for col := range row {
+			if !slices.Contains(target, col) {
+				return fmt.Errorf("row %d: column %s does not exist in the target", i+1, col)
+			}
+			cols = append(cols, col)
+		}
+		slices.Sort(cols)
+
+		// Rows of a table almost always share their columns, so one prepared
+		// statement serves them all.
+		key := strings.Join(cols, ",")
+		stmt, ok := stmts[key]
+		if !ok {
+			query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, key, strings.TrimSuffix(strings.Repeat("?, ", len(cols)), ", "))
+			if stmt, err = txn.PrepareContext(ctx, query); err != nil {
+				return err
+			}
+			stmts[key] = stmt
+		}
+		args := make([]any, len(cols))
+		for j, col := range cols {
+			args[j] = importValue(row[col])
+		}
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			return fmt.Errorf("row %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func tableColumns(ctx context.Context, txn *sql.Tx, table string) ([]string, error) {
+	rows, err := txn.QueryContext(ctx, "SELECT * FROM "+table+" LIMIT 0")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	return rows.Columns()
+}
+
+// importValue converts a decoded JSON value to a column value. Integral
+// numbers are stored as integers; the column's type affinity turns them
+// back into reals where needed.
+func importValue(v any) any {
+	n, ok := v.(json.Number)
+	if !ok {
+		return v
+	}
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	f, _ := n.Float64()
+	return f
+}
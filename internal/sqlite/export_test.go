@@ -0,0 +1,100 @@
+package sqlite_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/epic"
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/sqlite"
+	"github.com/vervesh/verve/internal/sqlite/migrations"
+	"github.com/vervesh/verve/internal/task"
+)
+
+func schemaVersion(t *testing.T, db *sqlite.FileDB) uint {
+	t.Helper()
+	status, err := sqlite.ReadMigrationStatus(context.Background(), db.Writer(), migrations.FS)
+	require.NoError(t, err)
+	return status.Current
+}
+
+func newMigratedFileDB(t *testing.T) *sqlite.FileDB {
+	t.Helper()
+	db := newFileDB(t, t.TempDir())
+	require.NoError(t, sqlite.Migrate(context.Background(), db.Writer(), migrations.FS))
+	return db
+}
+
+func TestExportImport(t *testing.T) {
+	ctx := context.Background()
+	src := newMigratedFileDB(t)
+
+	r, err := repo.NewRepo("owner/test-repo")
+	require.NoError(t, err)
+	require.NoError(t, sqlite.NewRepoRepository(src).CreateRepo(ctx, r))
+	e := epic.NewEpic(r.ID.String(), "epic", "")
+	require.NoError(t, sqlite.NewEpicRepository(src).CreateEpic(ctx, e))
+	tasks := sqlite.NewTaskRepository(src)
+	tsk := task.NewTask(r.ID.String(), "title", "desc", nil, nil, 1.5, false, false, "", true)
+	tsk.EpicID = e.ID.String()
+	require.NoError(t, tasks.CreateTask(ctx, tsk))
+	require.NoError(t, tasks.AppendTaskLogs(ctx, tsk.ID, 1, []string{"one", "two"}))
+	require.NoError(t, sqlite.NewSettingRepository(src).UpsertSetting(ctx, "default_model", "sonnet"))
+
+	dir := filepath.Join(t.TempDir(), "export")
+	m, err := sqlite.Export(ctx, src, dir, schemaVersion(t, src))
+	require.NoError(t, err)
+	assert.Contains(t, m.Tables, sqlite.ExportCount{Table: "task_log", Rows: 1})
+	_, err = sqlite.Export(ctx, src, dir, schemaVersion(t, src))
+	assert.Error(t, err, "an export is never overwritten")
+
+	dst := newMigratedFileDB(t)
+	_, err = sqlite.Import(ctx, dst, dir, schemaVersion(t, dst))
+	require.NoError(t, err)
+
+	got, err := sqlite.NewTaskRepository(dst).ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, tsk.Title, got.Title)
+	assert.Equal(t, 1.5, got.MaxCostUSD)
+	assert.Equal(t, e.ID.String(), got.EpicID)
+	logs, err := sqlite.NewTaskRepository(dst).ReadTaskLogs(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one", "two"}, logs)
+	model, err := sqlite.NewSettingRepository(dst).ReadSetting(ctx, "default_model")
+	require.NoError(t, err)
+	assert.Equal(t, "sonnet", model)
+
+	_, err = sqlite.Import(ctx, dst, dir, schemaVersion(t, dst))
+	assert.ErrorIs(t, err, sqlite.ErrImportTargetNotEmpty)
+}
+
+func TestImport_Rejects(t *testing.T) {
+	ctx := context.Background()
+	src := newMigratedFileDB(t)
+	r, err := repo.NewRepo("owner/test-repo")
+	require.NoError(t, err)
+	require.NoError(t, sqlite.NewRepoRepository(src).CreateRepo(ctx, r))
+	tsk := task.NewTask(r.ID.String(), "title", "desc", nil, nil, 0, false, false, "", true)
+	require.NoError(t, sqlite.NewTaskRepository(src).CreateTask(ctx, tsk))
+
+	dir := filepath.Join(t.TempDir(), "export")
+	_, err = sqlite.Export(ctx, src, dir, schemaVersion(t, src))
+	require.NoError(t, err)
+
+	dst := newMigratedFileDB(t)
+	_, err = sqlite.Import(ctx, dst, dir, schemaVersion(t, dst)+1)
+	assert.ErrorContains(t, err, "schema version")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "repo.jsonl"), nil, 0o644))
+	_, err = sqlite.Import(ctx, dst, dir, schemaVersion(t, dst))
+	assert.ErrorContains(t, err, "references a missing repo")
+
+	all, err := sqlite.NewTaskRepository(dst).ListTasks(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, all, "a rejected import writes nothing")
+}
@@ -0,0 +1,230 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	defaultBusyTimeout = 5 * time.Second
+	defaultReadConns   = 4
+	// walAutocheckpointPages is how many WAL pages a commit may leave behind
+	// before SQLite checkpoints automatically (SQLite's own default).
+	walAutocheckpointPages = 1000
+	// journalSizeLimit is the size in bytes the WAL is truncated back to after
+	// a checkpoint so a burst of writes doesn't leave a large file behind.
+	journalSizeLimit = 64 << 20
+)
+
+var _ DB = (*FileDB)(nil)
+
+// FileDB is a file-backed SQLite database that serializes writes through a
+// single connection.
+//
+// SQLite allows one writer at a time. When several pooled connections write
+// concurrently, a deferred transaction that reads first and then tries to
+// write fails immediately with SQLITE_BUSY instead of waiting on the busy
+// timeout. FileDB routes every write and transaction through one writer
+// connection, so writers in this process queue on the connection pool, and
+// the writer's transactions take the write lock up front (BEGIN IMMEDIATE) so
+// writers in other processes wait on the busy timeout rather than failing.
+// Read-only queries use a separate pool of query-only connections, which WAL
+// mode lets run concurrently with the writer.
+type FileDB struct {
+	path   string
+	writer *sql.DB
+	reader *sql.DB
+}
+
+// FileOption configures OpenFile.
+type FileOption func(opts *fileOptions)
+
+type fileOptions struct {
+	busyTimeout time.Duration
+	readConns   int
+}
+
+// WithBusyTimeout sets how long a connection waits for a lock held by another
+// connection or process before failing with SQLITE_BUSY.
+func WithBusyTimeout(d time.Duration) FileOption {
+	return func(opts *fileOptions) {
+		opts.busyTimeout = d
+	}
+}
+
+// WithReadConns sets the maximum number of concurrent read connections.
+func WithReadConns(n int) FileOption {
+	return func(opts *fileOptions) {
+		opts.readConns = n
+	}
+}
+
+// OpenFile opens (creating if needed) the SQLite database <name>.db in dir in
+// WAL mode.
+func OpenFile(ctx context.Context, dir, name string, opts ...FileOption) (*FileDB, error) {
+	o := fileOptions{busyTimeout: defaultBusyTimeout, readConns: defaultReadConns}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create sqlite dir: %w", err)
+	}
+	path := filepath.Join(dir, name+".db")
+
+	// Open the writer first: it switches the database to WAL mode, which
+	// persists in the file, before any reader connects.
+	writer, err := openConns(ctx, fileDSN(path, o.busyTimeout, false), 1)
+	if err != nil {
+		return nil, fmt.Errorf("open writer: %w", err)
+	}
+	reader, err := openConns(ctx, fileDSN(path, o.busyTimeout, true), max(o.readConns, 1))
+	if err != nil {
+		_ = writer.Close()
+		return nil, fmt.Errorf("open reader: %w", err)
+	}
+	return &FileDB{path: path, writer: writer, reader: reader}, nil
+}
+
+// fileDSN builds a modernc.org/sqlite DSN. Pragmas are applied to every new
+// connection in the pool.
+func fileDSN(path string, busyTimeout time.Duration, readOnly bool) string {
+	q := url.Values{}
+	q.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeout.Milliseconds()))
+	q.Add("_pragma", "foreign_keys(1)")
+	// NORMAL only syncs at checkpoints, which in WAL mode is durable across
+	// application crashes and avoids an fsync per commit.
+	q.Add("_pragma", "synchronous(NORMAL)")
+	if readOnly {
+		// Fail loudly if a write is ever routed to the read pool instead of
+		// silently competing with the writer for the lock.
+		q.Add("_pragma", "query_only(1)")
+	} else {
+		q.Add("_pragma", "journal_mode(WAL)")
+		q.Add("_pragma", fmt.Sprintf("wal_autocheckpoint(%d)", walAutocheckpointPages))
+		q.Add("_pragma", fmt.Sprintf("journal_size_limit(%d)", journalSizeLimit))
+		q.Set("_txlock", "immediate")
+	}
+	return "file:" + path + "?" + q.Encode()
+}
+
+func openConns(ctx context.Context, dsn string, maxConns int) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(maxConns)
+	db.SetMaxIdleConns(maxConns)
+	// Connections are cheap to keep and the pragmas are per connection, so
+	// never recycle them.
+	db.SetConnMaxLifetime(0)
+	db.SetConnMaxIdleTime(0)
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// Path returns the path of the database file.
+func (d *FileDB) Path() string {
+	return d.path
+}
+
+// Writer returns the single writer connection. It is used for schema
+// migrations, which need a plain *sql.DB.
+func (d *FileDB) Writer() *sql.DB {
+	return d.writer
+}
+
+// Close closes the reader and writer connections.
+func (d *FileDB) Close() error {
+	return errors.Join(d.reader.Close(), d.writer.Close())
+}
+
+func (d *FileDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return d.writer.ExecContext(ctx, query, args...)
+}
+
+func (d *FileDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return d.writer.PrepareContext(ctx, query)
+}
+
+func (d *FileDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return d.route(query).QueryContext(ctx, query, args...)
+}
+
+func (d *FileDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return d.route(query).QueryRowContext(ctx, query, args...)
+}
+
+func (d *FileDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return d.writer.BeginTx(ctx, opts)
+}
+
+func (d *FileDB) Conn(ctx context.Context) (*sql.Conn, error) {
+	return d.writer.Conn(ctx)
+}
+
+// route returns the read pool for read-only queries and the writer otherwise.
+func (d *FileDB) route(query string) *sql.DB {
+	if isReadOnlyQuery(query) {
+		return d.reader
+	}
+	return d.writer
+}
+
+// isReadOnlyQuery reports whether query is a plain SELECT, skipping leading
+// comments such as sqlc's "-- name:" line. Anything else, including
+// INSERT/UPDATE ... RETURNING, CTEs and PRAGMAs, is treated as a write.
+func isReadOnlyQuery(query string) bool {
+	q := strings.TrimSpace(query)
+	for strings.HasPrefix(q, "--") {
+		_, rest, ok := strings.Cut(q, "\n")
+		if !ok {
+			return false
+		}
+		q = strings.TrimSpace(rest)
+	}
+	const selectKeyword = "select"
+	return len(q) > len(selectKeyword) &&
+		strings.EqualFold(q[:len(selectKeyword)], selectKeyword) &&
+		!isIdentChar(q[len(selectKeyword)])
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// CheckpointResult is the outcome of a WAL checkpoint.
+type CheckpointResult struct {
+	// Busy is true when the checkpoint could not complete because readers or
+	// a writer were using the WAL. The next checkpoint picks up the rest.
+	Busy bool
+	// LogFrames is the number of frames in the WAL before the checkpoint.
+	LogFrames int
+	// CheckpointedFrames is the number of frames copied into the database.
+	CheckpointedFrames int
+}
+
+// Checkpoint copies the WAL into the database file and truncates the WAL.
+// SQLite checkpoints automatically on commit, but only passively: a steady
+// stream of readers can keep the WAL from ever being reset, letting it grow
+// without bound. A periodic truncating checkpoint waits (up to the busy
+// timeout) for readers to finish and resets the WAL.
+func (d *FileDB) Checkpoint(ctx context.Context) (CheckpointResult, error) {
+	var res CheckpointResult
+	var busy int
+	err := d.writer.QueryRowContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)").Scan(&busy, &res.LogFrames, &res.CheckpointedFrames)
+	if err != nil {
+		return CheckpointResult{}, fmt.Errorf("wal checkpoint: %w", err)
+	}
+	res.Busy = busy != 0
+	return res, nil
+}
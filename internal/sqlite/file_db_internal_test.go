@@ -0,0 +1,30 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsReadOnlyQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"SELECT 1", true},
+		{"  select id FROM task", true},
+		{"-- name: ReadTask :one\nSELECT id FROM task WHERE id = ?", true},
+		{"-- a\n-- b\nSELECT 1", true},
+		{"SELECT\nid FROM task", true},
+		{"-- name: ClaimTask :one\nUPDATE task SET status = 'running' RETURNING id", false},
+		{"INSERT INTO task (id) VALUES (?) RETURNING id", false},
+		{"WITH t AS (SELECT 1) DELETE FROM task", false},
+		{"PRAGMA busy_timeout", false},
+		{"selected", false},
+		{"-- only a comment", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, isReadOnlyQuery(tt.query), "%q", tt.query)
+	}
+}
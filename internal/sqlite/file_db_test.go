@@ -0,0 +1,213 @@
+package sqlite_test
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/sqlite"
+	"github.com/vervesh/verve/internal/sqlite/migrations"
+	"github.com/vervesh/verve/internal/task"
+)
+
+func newFileDB(t *testing.T, dir string) *sqlite.FileDB {
+	t.Helper()
+	db, err := sqlite.OpenFile(context.Background(), dir, "verve")
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, db.Close()) })
+	return db
+}
+
+func TestFileDB_ConcurrentWorkers(t *testing.T) {
+	const (
+		workers      = 10
+		tasks        = 100
+		logBatches   = 5
+		costPerClaim = 0.01
+	)
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// Two handles on the same file stand in for two API replicas, so writes
+	// contend both within a process and across connections to the file.
+	replicas := []*sqlite.FileDB{newFileDB(t, dir), newFileDB(t, dir)}
+	require.NoError(t, sqlite.Migrate(ctx, replicas[0].Writer(), migrations.FS))
+
+	r, err := repo.NewRepo("owner/test-repo")
+	require.NoError(t, err)
+	require.NoError(t, sqlite.NewRepoRepository(replicas[0]).CreateRepo(ctx, r))
+
+	stores := make([]*task.Store, len(replicas))
+	for i, db := range replicas {
+		stores[i] = task.NewStore(sqlite.NewTaskRepository(db), task.NewBroker(nil))
+	}
+	for i := 0; i < tasks; i++ {
+		tsk := task.NewTask(r.ID.String(), "title", "desc", nil, nil, 0, false, false, "", true)
+		require.NoError(t, stores[i%len(stores)].CreateTask(ctx, tsk))
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		errs    []error
+		claimed = make(map[task.TaskID]int)
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(store *task.Store) {
+			defer wg.Done()
+			fail := func(err error) {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+			for {
+				tsk, err := store.ClaimPendingTask(ctx, nil, nil)
+				if err != nil {
+					fail(err)
+					return
+				}
+				if tsk == nil {
+					return
+				}
+				mu.Lock()
+				claimed[tsk.ID]++
+				mu.Unlock()
+
+				for i := 0; i < logBatches; i++ {
+					if err := store.AppendTaskLogs(ctx, tsk.ID, tsk.Attempt, []string{"line"}); err != nil {
+						fail(err)
+					}
+					if _, err := store.Heartbeat(ctx, tsk.ID); err != nil {
+						fail(err)
+					}
+				}
+				if err := store.AddCost(ctx, tsk.ID, costPerClaim); err != nil {
+					fail(err)
+				}
+				if err := store.UpdateTaskStatus(ctx, tsk.ID, task.StatusClosed); err != nil {
+					fail(err)
+				}
+			}
+		}(stores[w%len(stores)])
+	}
+	wg.Wait()
+
+	require.Empty(t, errs, "expected no busy errors under concurrent workers")
+	require.Len(t, claimed, tasks)
+	for id, n := range claimed {
+		assert.Equal(t, 1, n, "task %s claimed more than once", id)
+	}
+
+	all, err := stores[0].ListTasks(ctx)
+	require.NoError(t, err)
+	for _, tsk := range all {
+		assert.Equal(t, task.StatusClosed, tsk.Status)
+		logs, err := stores[1].ReadTaskLogs(ctx, tsk.ID)
+		require.NoError(t, err)
+		assert.Len(t, logs, logBatches)
+	}
+}
+
+func TestFileDB_RoutesWritesToWriter(t *testing.T) {
+	db := newFileDB(t, t.TempDir())
+	ctx := context.Background()
+
+	_, err := db.ExecContext(ctx, "CREATE TABLE kv (k TEXT PRIMARY KEY, v TEXT NOT NULL)")
+	require.NoError(t, err)
+
+	// Queries that write and return rows must not be sent to the query-only
+	// read pool.
+	var k string
+	err = db.QueryRowContext(ctx, "-- name: PutKV :one\nINSERT INTO kv (k, v) VALUES ('a', '1') RETURNING k").Scan(&k)
+	require.NoError(t, err)
+	assert.Equal(t, "a", k)
+
+	var v string
+	err = db.QueryRowContext(ctx, "-- name: ReadKV :one\nSELECT v FROM kv WHERE k = ?", "a").Scan(&v)
+	require.NoError(t, err)
+	assert.Equal(t, "1", v)
+
+	var mode string
+	require.NoError(t, db.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&mode))
+	assert.Equal(t, "wal", mode)
+}
+
+func TestFileDB_Checkpoint(t *testing.T) {
+	db := newFileDB(t, t.TempDir())
+	ctx := context.Background()
+
+	_, err := db.ExecContext(ctx, "CREATE TABLE kv (k INTEGER PRIMARY KEY, v TEXT NOT NULL)")
+	require.NoError(t, err)
+	for i := 0; i < 100; i++ {
+		_, err := db.ExecContext(ctx, "INSERT INTO kv (v) VALUES ('value')")
+		require.NoError(t, err)
+	}
+
+	res, err := db.Checkpoint(ctx)
+	require.NoError(t, err)
+	assert.False(t, res.Busy)
+	assert.Equal(t, res.LogFrames, res.CheckpointedFrames)
+
+	info, err := os.Stat(db.Path() + "-wal")
+	require.NoError(t, err)
+	assert.Zero(t, info.Size(), "expected wal to be truncated")
+}
+
+// BenchmarkFileDB_ConcurrentLogAppends measures log append throughput with
+// 10 tasks streaming logs at once, with and without log write batching.
+func BenchmarkFileDB_ConcurrentLogAppends(b *testing.B) {
+	const tasks = 10
+	for _, bc := range []struct {
+		name     string
+		interval time.Duration
+	}{
+		{"unbatched", 0},
+		{"batched", 5 * time.Millisecond},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			ctx := context.Background()
+			db, err := sqlite.OpenFile(ctx, b.TempDir(), "verve")
+			require.NoError(b, err)
+			b.Cleanup(func() { _ = db.Close() })
+			require.NoError(b, sqlite.Migrate(ctx, db.Writer(), migrations.FS))
+
+			r, err := repo.NewRepo("owner/test-repo")
+			require.NoError(b, err)
+			require.NoError(b, sqlite.NewRepoRepository(db).CreateRepo(ctx, r))
+			store := task.NewStore(sqlite.NewTaskRepository(db), task.NewBroker(nil))
+			store.SetLogBatchInterval(bc.interval)
+
+			ids := make([]task.TaskID, tasks)
+			for i := range ids {
+				tsk := task.NewTask(r.ID.String(), "title", "desc", nil, nil, 0, false, false, "", true)
+				require.NoError(b, store.CreateTask(ctx, tsk))
+				ids[i] = tsk.ID
+			}
+			lines := []string{"building...", "running tests...", "ok"}
+
+			b.ResetTimer()
+			var wg sync.WaitGroup
+			for _, id := range ids {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < b.N; i++ {
+						if err := store.AppendTaskLogs(ctx, id, 1, lines); err != nil {
+							b.Error(err)
+							return
+						}
+					}
+				}()
+			}
+			wg.Wait()
+			b.ReportMetric(float64(tasks*b.N)/b.Elapsed().Seconds(), "appends/s")
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/joshjon/kit/errtag"
+
+	"github.com/vervesh/verve/internal/githubtoken"
+	"github.com/vervesh/verve/internal/sqlite/sqlc"
+)
+
+var _ githubtoken.CredentialRepository = (*GitHubCredentialRepository)(nil)
+
+// GitHubCredentialRepository implements githubtoken.CredentialRepository using SQLite.
+type GitHubCredentialRepository struct {
+	db *sqlc.Queries
+}
+
+// NewGitHubCredentialRepository creates a new GitHubCredentialRepository backed by the given SQLite DB.
+func NewGitHubCredentialRepository(dbtx DB) *GitHubCredentialRepository {
+	return &GitHubCredentialRepository{db: sqlc.New(dbtx)}
+}
+
+func (r *GitHubCredentialRepository) CreateGitHubCredential(ctx context.Context, c *githubtoken.Credential) error {
+	return tagGitHubCredentialErr(r.db.CreateGitHubCredential(ctx, sqlc.CreateGitHubCredentialParams{
+		ID:              c.ID.String(),
+		Name:            c.Name,
+		Kind:            string(c.Kind),
+		Repos:           marshalJSONStrings(c.Repos),
+		EncryptedSecret: c.Secret,
+		AppID:           c.AppID,
+		InstallationID:  c.InstallationID,
+		CreatedAt:       c.CreatedAt.Unix(),
+		UpdatedAt:       c.UpdatedAt.Unix(),
+	}))
+}
+
+func (r *GitHubCredentialRepository) ReadGitHubCredential(ctx context.Context, id githubtoken.CredentialID) (*githubtoken.Credential, error) {
+	row, err := r.db.ReadGitHubCredential(ctx, id.String())
+	if err != nil {
+		return nil, tagGitHubCredentialErr(err)
+	}
+	return unmarshalGitHubCredential(row), nil
+}
+
+func (r *GitHubCredentialRepository) ListGitHubCredentials(ctx context.Context) ([]*githubtoken.Credential, error) {
+	rows, err := r.db.ListGitHubCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*githubtoken.Credential, len(rows))
+	for i, row := range rows {
+		out[i] = unmarshalGitHubCredential(row)
+	}
+	return out, nil
+}
+
+func (r *GitHubCredentialRepository) UpdateGitHubCredential(ctx context.Context, c *githubtoken.Credential) error {
+	return tagGitHubCredentialErr(r.db.UpdateGitHubCredential(ctx, sqlc.UpdateGitHubCredentialParams{
+		Name:            c.Name,
+		Kind:            string(c.Kind),
+		Repos:           marshalJSONStrings(c.Repos),
+		EncryptedSecret: c.Secret,
+		AppID:           c.AppID,
+		InstallationID:  c.InstallationID,
+		UpdatedAt:       c.UpdatedAt.Unix(),
+		ID:              c.ID.String(),
+	}))
+}
+
+func (r *GitHubCredentialRepository) DeleteGitHubCredential(ctx context.Context, id githubtoken.CredentialID) error {
+	return r.db.DeleteGitHubCredential(ctx, id.String())
+}
+
+func unmarshalGitHubCredential(in *sqlc.GithubCredential) *githubtoken.Credential {
+	return &githubtoken.Credential{
+		ID:             githubtoken.MustParseCredentialID(in.ID),
+		Name:           in.Name,
+		Kind:           githubtoken.CredentialKind(in.Kind),
+		Repos:          unmarshalJSONStrings(in.Repos),
+		Secret:         in.EncryptedSecret,
+		AppID:          in.AppID,
+		InstallationID: in.InstallationID,
+		CreatedAt:      unixToTime(in.CreatedAt),
+		UpdatedAt:      unixToTime(in.UpdatedAt),
+	}
+}
+
+func tagGitHubCredentialErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return errtag.Tag[githubtoken.ErrTagCredentialNotFound](err)
+	}
+	if isSQLiteErrCode(err, sqliteConstraint, sqliteConstraintUnique, sqliteConstraintPrimaryKey) {
+		return errtag.Tag[githubtoken.ErrTagCredentialConflict](err)
+	}
+	return err
+}
@@ -0,0 +1,40 @@
+package sqlite
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/vervesh/verve/internal/leader"
+	"github.com/vervesh/verve/internal/sqlite/sqlc"
+)
+
+var _ leader.Repository = (*LeaseRepository)(nil)
+
+// LeaseRepository implements leader.Repository using SQLite. Lease expiry is
+// evaluated against the database clock so replicas with skewed clocks agree
+// on when a lease has expired.
+type LeaseRepository struct {
+	db *sqlc.Queries
+}
+
+// NewLeaseRepository creates a new LeaseRepository backed by the given SQLite DB.
+func NewLeaseRepository(db DB) *LeaseRepository {
+	return &LeaseRepository{db: sqlc.New(db)}
+}
+
+func (r *LeaseRepository) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	rows, err := r.db.AcquireLease(ctx, sqlc.AcquireLeaseParams{
+		Name:       name,
+		Holder:     holder,
+		TtlSeconds: int64(math.Ceil(ttl.Seconds())),
+	})
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (r *LeaseRepository) ReleaseLease(ctx context.Context, name, holder string) error {
+	return r.db.ReleaseLease(ctx, sqlc.ReleaseLeaseParams{Name: name, Holder: holder})
+}
@@ -0,0 +1,74 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/sqlite"
+)
+
+func TestLeaseRepository_AcquireLease(t *testing.T) {
+	db := sqlite.NewTestDB(t)
+	leases := sqlite.NewLeaseRepository(db)
+	ctx := context.Background()
+
+	ok, err := leases.AcquireLease(ctx, "jobs", "replica-a", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "expected first holder to acquire the lease")
+
+	ok, err = leases.AcquireLease(ctx, "jobs", "replica-a", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "expected holder to renew its own lease")
+
+	ok, err = leases.AcquireLease(ctx, "jobs", "replica-b", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok, "expected unexpired lease to be held by another replica")
+
+	ok, err = leases.AcquireLease(ctx, "other", "replica-b", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "expected leases to be independent by name")
+}
+
+func TestLeaseRepository_AcquireExpiredLease(t *testing.T) {
+	db := sqlite.NewTestDB(t)
+	leases := sqlite.NewLeaseRepository(db)
+	ctx := context.Background()
+
+	// A zero TTL lease expires immediately.
+	ok, err := leases.AcquireLease(ctx, "jobs", "replica-a", 0)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = leases.AcquireLease(ctx, "jobs", "replica-b", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "expected expired lease to be taken over")
+
+	ok, err = leases.AcquireLease(ctx, "jobs", "replica-a", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok, "expected previous holder to have lost the lease")
+}
+
+func TestLeaseRepository_ReleaseLease(t *testing.T) {
+	db := sqlite.NewTestDB(t)
+	leases := sqlite.NewLeaseRepository(db)
+	ctx := context.Background()
+
+	ok, err := leases.AcquireLease(ctx, "jobs", "replica-a", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// Releasing a lease held by someone else is a no-op.
+	require.NoError(t, leases.ReleaseLease(ctx, "jobs", "replica-b"))
+	ok, err = leases.AcquireLease(ctx, "jobs", "replica-b", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, leases.ReleaseLease(ctx, "jobs", "replica-a"))
+	ok, err = leases.AcquireLease(ctx, "jobs", "replica-b", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "expected released lease to be acquirable")
+}
@@ -0,0 +1,171 @@
+package sqlite_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/joshjon/kit/errtag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/sqlite"
+	"github.com/vervesh/verve/internal/sqlite/migrations"
+	"github.com/vervesh/verve/internal/task"
+)
+
+// backend opens a migrated database for the lifecycle suite. The server
+// only has SQLite repositories, so the suite runs against the in-memory
+// database the tests use and a file-backed one like a server with
+// SQLITE_DIR, holding both to the same behavior.
+type backend struct {
+	name string
+	open func(t *testing.T) sqlite.DB
+}
+
+var backends = []backend{
+	{name: "memory", open: func(t *testing.T) sqlite.DB { return sqlite.NewTestDB(t) }},
+	{name: "file", open: func(t *testing.T) sqlite.DB {
+		db := newFileDB(t, t.TempDir())
+		require.NoError(t, sqlite.Migrate(context.Background(), db.Writer(), migrations.FS))
+		return db
+	}},
+}
+
+func forEachBackend(t *testing.T, fn func(t *testing.T, db sqlite.DB)) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) { fn(t, b.open(t)) })
+	}
+}
+
+func seedLifecycleRepo(t *testing.T, db sqlite.DB) *repo.Repo {
+	t.Helper()
+	r, err := repo.NewRepo("owner/test-repo")
+	require.NoError(t, err)
+	require.NoError(t, sqlite.NewRepoRepository(db).CreateRepo(context.Background(), r))
+	return r
+}
+
+func TestLifecycle_Task(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db sqlite.DB) {
+		ctx := context.Background()
+		r := seedLifecycleRepo(t, db)
+		store := task.NewStore(sqlite.NewTaskRepository(db), task.NewBroker(nil))
+
+		tsk := task.NewTask(r.ID.String(), "title", "desc", nil, nil, 0, false, false, "", true)
+		require.NoError(t, store.CreateTask(ctx, tsk))
+
+		claimed, err := store.ClaimPendingTask(ctx, nil, nil)
+		require.NoError(t, err)
+		require.NotNil(t, claimed)
+		assert.Equal(t, tsk.ID, claimed.ID)
+		assert.Equal(t, task.StatusRunning, claimed.Status)
+		assert.Equal(t, 1, claimed.Attempt)
+
+		none, err := store.ClaimPendingTask(ctx, nil, nil)
+		require.NoError(t, err)
+		assert.Nil(t, none, "a running task can't be claimed again")
+
+		require.NoError(t, store.AppendTaskLogs(ctx, tsk.ID, 1, []string{"first", "second"}))
+		require.NoError(t, store.UpdateTaskStatus(ctx, tsk.ID, task.StatusReview))
+
+		require.NoError(t, store.RetryTask(ctx, tsk.ID, "ci_failure:tests", "tests failed"))
+		retried, err := store.ReadTask(ctx, tsk.ID)
+		require.NoError(t, err)
+		assert.Equal(t, task.StatusPending, retried.Status)
+		assert.Equal(t, 2, retried.Attempt)
+
+		claimed, err = store.ClaimPendingTask(ctx, nil, nil)
+		require.NoError(t, err)
+		require.NotNil(t, claimed)
+		assert.Equal(t, 2, claimed.Attempt)
+		require.NoError(t, store.AppendTaskLogs(ctx, tsk.ID, 2, []string{"third"}))
+		require.NoError(t, store.UpdateTaskStatus(ctx, tsk.ID, task.StatusMerged))
+
+		logs, err := store.ReadTaskLogs(ctx, tsk.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"first", "second", "third"}, logs)
+		attempts, err := store.ListAttempts(ctx, tsk.ID)
+		require.NoError(t, err)
+		assert.Len(t, attempts, 2)
+
+		require.NoError(t, store.DeleteTask(ctx, tsk.ID))
+		_, err = store.ReadTask(ctx, tsk.ID)
+		assert.True(t, errtag.HasTag[task.ErrTagTaskNotFound](err), "got %v", err)
+	})
+}
+
+func TestLifecycle_ConcurrentClaimTask(t *testing.T) {
+	const claimers = 20
+	forEachBackend(t, func(t *testing.T, db sqlite.DB) {
+		ctx := context.Background()
+		r := seedLifecycleRepo(t, db)
+		tasks := sqlite.NewTaskRepository(db)
+
+		tsk := task.NewTask(r.ID.String(), "title", "desc", nil, nil, 0, false, false, "", true)
+		require.NoError(t, tasks.CreateTask(ctx, tsk))
+
+		var (
+			wg   sync.WaitGroup
+			wins atomic.Int32
+		)
+		for i := 0; i < claimers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ok, err := tasks.ClaimTask(ctx, tsk.ID)
+				assert.NoError(t, err)
+				if ok {
+					wins.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(1), wins.Load(), "exactly one claim wins")
+	})
+}
+
+func TestLifecycle_ConcurrentClaimPendingTask(t *testing.T) {
+	const (
+		workers = 8
+		count   = 40
+	)
+	forEachBackend(t, func(t *testing.T, db sqlite.DB) {
+		ctx := context.Background()
+		r := seedLifecycleRepo(t, db)
+		store := task.NewStore(sqlite.NewTaskRepository(db), task.NewBroker(nil))
+		for i := 0; i < count; i++ {
+			tsk := task.NewTask(r.ID.String(), "title", "desc", nil, nil, 0, false, false, "", true)
+			require.NoError(t, store.CreateTask(ctx, tsk))
+		}
+
+		var (
+			wg      sync.WaitGroup
+			mu      sync.Mutex
+			claimed = make(map[task.TaskID]int)
+		)
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					tsk, err := store.ClaimPendingTask(ctx, nil, nil)
+					if !assert.NoError(t, err) || tsk == nil {
+						return
+					}
+					mu.Lock()
+					claimed[tsk.ID]++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		require.Len(t, claimed, count)
+		for id, n := range claimed {
+			assert.Equal(t, 1, n, "task %s claimed more than once", id)
+		}
+	})
+}
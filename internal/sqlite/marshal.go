@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/vervesh/verve/internal/provenance"
+	"github.com/vervesh/verve/internal/selfreview"
 	"github.com/vervesh/verve/internal/sqlite/sqlc"
 	"github.com/vervesh/verve/internal/task"
 )
@@ -37,6 +39,9 @@ func unmarshalTask(in *sqlc.Task) *task.Task {
 	if in.CloseReason != nil {
 		t.CloseReason = *in.CloseReason
 	}
+	if in.FailureCategory != nil {
+		t.FailureCategory = *in.FailureCategory
+	}
 	t.Attempt = int(in.Attempt)
 	t.MaxAttempts = int(in.MaxAttempts)
 	if in.RetryReason != nil {
@@ -49,12 +54,15 @@ func unmarshalTask(in *sqlc.Task) *task.Task {
 		t.RetryContext = *in.RetryContext
 	}
 	t.ConsecutiveFailures = int(in.ConsecutiveFailures)
+	t.LastReviewID = in.LastReviewID
 	t.CostUSD = in.CostUsd
 	if in.MaxCostUsd != nil {
 		t.MaxCostUSD = *in.MaxCostUsd
 	}
+	t.MaxRuntimeSeconds = int(in.MaxRuntimeSeconds)
 	t.SkipPR = in.SkipPr != 0
 	t.DraftPR = in.DraftPr != 0
+	t.PlanOnly = in.PlanOnly != 0
 	t.Ready = in.Ready != 0
 	if in.Model != nil {
 		t.Model = *in.Model
@@ -65,7 +73,25 @@ func unmarshalTask(in *sqlc.Task) *task.Task {
 	if in.EpicID != nil {
 		t.EpicID = *in.EpicID
 	}
+	if in.StackParentID != nil {
+		t.StackParentID = *in.StackParentID
+	}
+	if ids := unmarshalJSONStrings(in.AdditionalRepoIds); len(ids) > 0 {
+		t.AdditionalRepoIDs = ids
+	}
+	if labels := unmarshalJSONStrings(in.RequiredLabels); len(labels) > 0 {
+		t.RequiredLabels = labels
+	}
+	if models := unmarshalJSONStrings(in.FallbackModels); len(models) > 0 {
+		t.FallbackModels = models
+	}
+	t.ActiveModel = in.ActiveModel
+	t.Version = in.Version
+	t.PullRequests = unmarshalPullRequests(in.PullRequests)
 	t.StartedAt = unixPtrToTimePtr(in.StartedAt)
+	t.NotBefore = unixPtrToTimePtr(in.NotBefore)
+	t.DeletedAt = unixPtrToTimePtr(in.DeletedAt)
+	t.ArchivedAt = unixPtrToTimePtr(in.ArchivedAt)
 	t.ComputeDuration()
 	return t
 }
@@ -95,6 +121,269 @@ func unmarshalJSONStrings(s string) []string {
 	return ss
 }
 
+// pullRequestRow is the stored form of a task.PullRequest. Unlike the API
+// representation it includes the PR's last processed review ID.
+type pullRequestRow struct {
+	RepoID       string `json:"repo_id"`
+	URL          string `json:"url"`
+	Number       int    `json:"number"`
+	Merged       bool   `json:"merged"`
+	LastReviewID int64  `json:"last_review_id,omitempty"`
+}
+
+func marshalPullRequests(prs []task.PullRequest) string {
+	rows := make([]pullRequestRow, len(prs))
+	for i, pr := range prs {
+		rows[i] = pullRequestRow(pr)
+	}
+	b, _ := json.Marshal(rows)
+	return string(b)
+}
+
+func unmarshalPullRequests(s string) []task.PullRequest {
+	var rows []pullRequestRow
+	_ = json.Unmarshal([]byte(s), &rows)
+	if len(rows) == 0 {
+		return nil
+	}
+	prs := make([]task.PullRequest, len(rows))
+	for i, row := range rows {
+		prs[i] = task.PullRequest(row)
+	}
+	return prs
+}
+
+func unmarshalTaskAttempt(in *sqlc.TaskAttempt) *task.Attempt {
+	return &task.Attempt{
+		Number:      int(in.Attempt),
+		RetryReason: in.RetryReason,
+		Result:      in.Result,
+		CostUSD:     in.CostUsd,
+		StartedAt:   unixToTime(in.StartedAt),
+		EndedAt:     unixPtrToTimePtr(in.EndedAt),
+		Model:       in.Model,
+	}
+}
+
+func unmarshalTaskEvent(in *sqlc.TaskEventLog) *task.AgentEvent {
+	return &task.AgentEvent{
+		ID:        in.ID,
+		Attempt:   int(in.Attempt),
+		Type:      task.AgentEventType(in.Type),
+		Payload:   json.RawMessage(in.Payload),
+		CreatedAt: unixToTime(in.CreatedAt),
+	}
+}
+
+// marshalAgentEventPayload stores an empty payload as an empty JSON object.
+func marshalAgentEventPayload(payload json.RawMessage) string {
+	if len(payload) == 0 {
+		return "{}"
+	}
+	return string(payload)
+}
+
+func unmarshalTaskProgress(in *sqlc.TaskProgress) *task.Progress {
+	var steps []task.Step
+	_ = json.Unmarshal([]byte(in.Steps), &steps)
+	if steps == nil {
+		steps = []task.Step{}
+	}
+	return &task.Progress{
+		Attempt:   int(in.Attempt),
+		Steps:     steps,
+		UpdatedAt: unixToTime(in.UpdatedAt),
+	}
+}
+
+func marshalProgressSteps(steps []task.Step) string {
+	if steps == nil {
+		steps = []task.Step{}
+	}
+	b, _ := json.Marshal(steps)
+	return string(b)
+}
+
+func unmarshalTaskNudge(in *sqlc.TaskNudge) *task.Nudge {
+	return &task.Nudge{
+		ID:          in.ID,
+		Attempt:     int(in.Attempt),
+		Message:     in.Message,
+		CreatedAt:   unixToTime(in.CreatedAt),
+		DeliveredAt: unixPtrToTimePtr(in.DeliveredAt),
+	}
+}
+
+func unmarshalTaskNudgeList(in []*sqlc.TaskNudge) []*task.Nudge {
+	out := make([]*task.Nudge, len(in))
+	for i := range in {
+		out[i] = unmarshalTaskNudge(in[i])
+	}
+	return out
+}
+
+func unmarshalTaskComment(in *sqlc.TaskComment) *task.Comment {
+	c := &task.Comment{
+		ID:        in.ID,
+		TaskID:    in.TaskID,
+		Author:    in.Author,
+		Body:      in.Body,
+		CreatedAt: unixToTime(in.CreatedAt),
+		UpdatedAt: unixToTime(in.UpdatedAt),
+	}
+	if in.Attempt != nil {
+		attempt := int(*in.Attempt)
+		c.Attempt = &attempt
+	}
+	if in.AuthorID != nil {
+		c.AuthorID = *in.AuthorID
+	}
+	return c
+}
+
+func unmarshalTaskCommentList(in []*sqlc.TaskComment) []*task.Comment {
+	out := make([]*task.Comment, len(in))
+	for i := range in {
+		out[i] = unmarshalTaskComment(in[i])
+	}
+	return out
+}
+
+func unmarshalTaskArtifact(in *sqlc.TaskArtifact) *task.Artifact {
+	return &task.Artifact{
+		ID:          in.ID,
+		TaskID:      in.TaskID,
+		Attempt:     int(in.Attempt),
+		Name:        in.Name,
+		ContentType: in.ContentType,
+		SizeBytes:   in.SizeBytes,
+		StorageKey:  in.StorageKey,
+		CreatedAt:   unixToTime(in.CreatedAt),
+	}
+}
+
+func unmarshalTaskArtifactList(in []*sqlc.TaskArtifact) []*task.Artifact {
+	out := make([]*task.Artifact, len(in))
+	for i := range in {
+		out[i] = unmarshalTaskArtifact(in[i])
+	}
+	return out
+}
+
+func unmarshalTaskAttachment(in *sqlc.TaskAttachment) *task.Attachment {
+	return &task.Attachment{
+		ID:          in.ID,
+		TaskID:      in.TaskID,
+		Filename:    in.Filename,
+		ContentType: in.ContentType,
+		SizeBytes:   in.SizeBytes,
+		StorageKey:  in.StorageKey,
+		UploadedBy:  in.UploadedBy,
+		CreatedAt:   unixToTime(in.CreatedAt),
+	}
+}
+
+func unmarshalTaskAttachmentList(in []*sqlc.TaskAttachment) []*task.Attachment {
+	out := make([]*task.Attachment, len(in))
+	for i := range in {
+		out[i] = unmarshalTaskAttachment(in[i])
+	}
+	return out
+}
+
+func unmarshalTaskNote(in *sqlc.TaskNote) *task.Note {
+	n := &task.Note{
+		ID:        in.ID,
+		TaskID:    in.TaskID,
+		RepoID:    in.RepoID,
+		Attempt:   int(in.Attempt),
+		Text:      in.Text,
+		CreatedAt: unixToTime(in.CreatedAt),
+	}
+	if in.ConvertedTaskID != nil {
+		n.ConvertedTaskID = *in.ConvertedTaskID
+	}
+	return n
+}
+
+func unmarshalTaskNoteList(in []*sqlc.TaskNote) []*task.Note {
+	out := make([]*task.Note, len(in))
+	for i := range in {
+		out[i] = unmarshalTaskNote(in[i])
+	}
+	return out
+}
+
+func unmarshalTaskProvenanceReview(in *sqlc.TaskProvenanceReview) *task.ProvenanceReview {
+	var findings []provenance.Finding
+	_ = json.Unmarshal([]byte(in.Findings), &findings)
+	if findings == nil {
+		findings = []provenance.Finding{}
+	}
+	return &task.ProvenanceReview{
+		Findings:       findings,
+		HeadSHA:        in.HeadSha,
+		ScannedAt:      unixToTime(in.ScannedAt),
+		AcknowledgedAt: unixPtrToTimePtr(in.AcknowledgedAt),
+	}
+}
+
+func marshalProvenanceFindings(findings []provenance.Finding) string {
+	if findings == nil {
+		findings = []provenance.Finding{}
+	}
+	b, _ := json.Marshal(findings)
+	return string(b)
+}
+
+func unmarshalTaskSelfReview(in *sqlc.TaskSelfReview) *task.SelfReview {
+	var checks []selfreview.Check
+	_ = json.Unmarshal([]byte(in.Checks), &checks)
+	if checks == nil {
+		checks = []selfreview.Check{}
+	}
+	return &task.SelfReview{
+		Score:      int(in.Score),
+		Checks:     checks,
+		HeadSHA:    in.HeadSha,
+		CommentID:  in.CommentID,
+		ReviewedAt: unixToTime(in.ReviewedAt),
+	}
+}
+
+func marshalSelfReviewChecks(checks []selfreview.Check) string {
+	if checks == nil {
+		checks = []selfreview.Check{}
+	}
+	b, _ := json.Marshal(checks)
+	return string(b)
+}
+
+func unmarshalTaskProposal(in *sqlc.TaskProposal) *task.Proposal {
+	var files []task.ProposalFile
+	_ = json.Unmarshal([]byte(in.Files), &files)
+	if files == nil {
+		files = []task.ProposalFile{}
+	}
+	return &task.Proposal{
+		Attempt:    int(in.Attempt),
+		Summary:    in.Summary,
+		Approach:   in.Approach,
+		Files:      files,
+		Risks:      unmarshalJSONStrings(in.Risks),
+		CreatedAt:  unixToTime(in.CreatedAt),
+		ApprovedAt: unixPtrToTimePtr(in.ApprovedAt),
+	}
+}
+
+func marshalProposalFiles(files []task.ProposalFile) string {
+	if files == nil {
+		files = []task.ProposalFile{}
+	}
+	b, _ := json.Marshal(files)
+	return string(b)
+}
+
 func unixToTime(secs int64) time.Time {
 	return time.Unix(secs, 0).UTC()
 }
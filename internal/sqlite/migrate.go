@@ -0,0 +1,150 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/joshjon/kit/sqlitedb"
+)
+
+// migrationsTable is the table golang-migrate records the schema version in.
+const migrationsTable = "schema_migrations"
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.up\.sql$`)
+
+// ErrPendingMigrations is returned when the database schema is behind the
+// migrations embedded in the binary.
+var ErrPendingMigrations = errors.New("database has pending migrations")
+
+// Migration is an up migration embedded in the binary.
+type Migration struct {
+	Version uint
+	Name    string
+}
+
+// MigrationStatus describes the database schema version relative to the
+// embedded migrations.
+type MigrationStatus struct {
+	// Current is the applied schema version, or 0 when no migrations have
+	// been applied.
+	Current uint
+	// Dirty is true when a migration failed part way and the schema must be
+	// repaired manually.
+	Dirty bool
+	// Pending lists the migrations newer than Current, in order.
+	Pending []Migration
+}
+
+// Latest returns the version the schema will be at once all pending
+// migrations are applied.
+func (s MigrationStatus) Latest() uint {
+	if len(s.Pending) == 0 {
+		return s.Current
+	}
+	return s.Pending[len(s.Pending)-1].Version
+}
+
+// ListMigrations returns the up migrations in fsys ordered by version.
+func ListMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+	var migrations []Migration
+	for _, e := range entries {
+		m := migrationFilePattern.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(m[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse migration version %q: %w", e.Name(), err)
+		}
+		migrations = append(migrations, Migration{Version: uint(version), Name: m[2]})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// ReadMigrationStatus reports the applied schema version of db and the
+// migrations in fsys that have not been applied yet. It does not modify the
+// database.
+func ReadMigrationStatus(ctx context.Context, db *sql.DB, fsys fs.FS) (MigrationStatus, error) {
+	migrations, err := ListMigrations(fsys)
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+
+	var status MigrationStatus
+
+	var tables int
+	err = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", migrationsTable).Scan(&tables)
+	if err != nil {
+		return MigrationStatus{}, fmt.Errorf("check migrations table: %w", err)
+	}
+	if tables > 0 {
+		var version int64
+		err = db.QueryRowContext(ctx, "SELECT version, dirty FROM "+migrationsTable+" LIMIT 1").Scan(&version, &status.Dirty)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+		case err != nil:
+			return MigrationStatus{}, fmt.Errorf("read schema version: %w", err)
+		case version > 0:
+			status.Current = uint(version)
+		}
+	}
+
+	for _, m := range migrations {
+		if m.Version > status.Current {
+			status.Pending = append(status.Pending, m)
+		}
+	}
+	return status, nil
+}
+
+// MigrateOption configures Migrate.
+type MigrateOption func(o *migrateOptions)
+
+type migrateOptions struct {
+	lockTimeout time.Duration
+}
+
+// WithLockTimeout bounds how long each migration waits for the database write
+// lock before failing, instead of blocking behind other writers. It sets the
+// SQLite busy_timeout for the duration of the migration, so it must not be
+// used with drivers that don't support PRAGMA statements (e.g. Turso/libSQL).
+func WithLockTimeout(d time.Duration) MigrateOption {
+	return func(o *migrateOptions) {
+		o.lockTimeout = d
+	}
+}
+
+// Migrate applies all pending migrations in fsys to db.
+func Migrate(ctx context.Context, db *sql.DB, fsys fs.FS, opts ...MigrateOption) error {
+	var o migrateOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.lockTimeout > 0 {
+		var prev int64
+		if err := db.QueryRowContext(ctx, "PRAGMA busy_timeout").Scan(&prev); err != nil {
+			return fmt.Errorf("read busy timeout: %w", err)
+		}
+		if _, err := db.ExecContext(ctx, fmt.Sprintf("PRAGMA busy_timeout = %d", o.lockTimeout.Milliseconds())); err != nil {
+			return fmt.Errorf("set busy timeout: %w", err)
+		}
+		defer func() {
+			_, _ = db.ExecContext(context.WithoutCancel(ctx), fmt.Sprintf("PRAGMA busy_timeout = %d", prev))
+		}()
+	}
+
+	return sqlitedb.Migrate(db, fsys)
+}
@@ -0,0 +1,144 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/joshjon/kit/sqlitedb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/sqlite"
+	"github.com/vervesh/verve/internal/sqlite/migrations"
+)
+
+func newEmptyDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sqlitedb.Open(context.Background(), sqlitedb.WithInMemory())
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, db.Close()) })
+	return db
+}
+
+func TestListMigrations(t *testing.T) {
+	all, err := sqlite.ListMigrations(migrations.FS)
+	require.NoError(t, err)
+	require.NotEmpty(t, all)
+
+	assert.Equal(t, sqlite.Migration{Version: 1, Name: "create_repos"}, all[0])
+	for i := 1; i < len(all); i++ {
+		assert.Greater(t, all[i].Version, all[i-1].Version, "expected migrations ordered by version")
+	}
+}
+
+func TestReadMigrationStatus_EmptyDatabase(t *testing.T) {
+	db := newEmptyDB(t)
+	all, err := sqlite.ListMigrations(migrations.FS)
+	require.NoError(t, err)
+
+	status, err := sqlite.ReadMigrationStatus(context.Background(), db, migrations.FS)
+	require.NoError(t, err)
+	assert.Equal(t, uint(0), status.Current)
+	assert.False(t, status.Dirty)
+	assert.Equal(t, all, status.Pending)
+	assert.Equal(t, all[len(all)-1].Version, status.Latest())
+}
+
+func TestReadMigrationStatus_PartiallyMigrated(t *testing.T) {
+	db := newEmptyDB(t)
+	require.NoError(t, sqlitedb.Migrate(db, migrations.FS, sqlitedb.WithVersion(5)))
+
+	status, err := sqlite.ReadMigrationStatus(context.Background(), db, migrations.FS)
+	require.NoError(t, err)
+	assert.Equal(t, uint(5), status.Current)
+	require.NotEmpty(t, status.Pending)
+	assert.Equal(t, uint(6), status.Pending[0].Version)
+}
+
+func TestReadMigrationStatus_UpToDate(t *testing.T) {
+	db := sqlite.NewTestDB(t)
+
+	status, err := sqlite.ReadMigrationStatus(context.Background(), db, migrations.FS)
+	require.NoError(t, err)
+	assert.Empty(t, status.Pending)
+	assert.Equal(t, status.Current, status.Latest())
+}
+
+func TestReadMigrationStatus_Dirty(t *testing.T) {
+	db := sqlite.NewTestDB(t)
+	_, err := db.Exec("UPDATE schema_migrations SET dirty = 1")
+	require.NoError(t, err)
+
+	status, err := sqlite.ReadMigrationStatus(context.Background(), db, migrations.FS)
+	require.NoError(t, err)
+	assert.True(t, status.Dirty)
+}
+
+func TestMigrate_LockTimeout(t *testing.T) {
+	db := newEmptyDB(t)
+	ctx := context.Background()
+
+	var before int64
+	require.NoError(t, db.QueryRow("PRAGMA busy_timeout").Scan(&before))
+
+	require.NoError(t, sqlite.Migrate(ctx, db, migrations.FS, sqlite.WithLockTimeout(250*time.Millisecond)))
+
+	status, err := sqlite.ReadMigrationStatus(ctx, db, migrations.FS)
+	require.NoError(t, err)
+	assert.Empty(t, status.Pending)
+
+	var after int64
+	require.NoError(t, db.QueryRow("PRAGMA busy_timeout").Scan(&after))
+	assert.Equal(t, before, after, "expected busy timeout to be restored after migrating")
+}
+
+// migrationsUpTo returns the migrations up to and including version.
+// sqlitedb.Migrate always migrates up before it moves to a version, so a
+// database is held at an older version by leaving the later migrations out.
+func migrationsUpTo(t *testing.T, version uint) fs.FS {
+	t.Helper()
+	all, err := sqlite.ListMigrations(migrations.FS)
+	require.NoError(t, err)
+	fsys := fstest.MapFS{}
+	for _, m := range all {
+		if m.Version > version {
+			break
+		}
+		name := fmt.Sprintf("%04d_%s.up.sql", m.Version, m.Name)
+		data, err := fs.ReadFile(migrations.FS, name)
+		require.NoError(t, err)
+		fsys[name] = &fstest.MapFile{Data: data}
+	}
+	return fsys
+}
+
+func TestMigrate_EpicRebuildKeepsLinks(t *testing.T) {
+	db := newEmptyDB(t)
+	require.NoError(t, sqlitedb.Migrate(db, migrationsUpTo(t, 40)))
+
+	for _, stmt := range []string{
+		`INSERT INTO repo (id, owner, name, full_name) VALUES ('repo_1', 'o', 'r', 'o/r')`,
+		`INSERT INTO epic (id, repo_id, title, number) VALUES ('epc_1', 'repo_1', 'Epic', 1)`,
+		`INSERT INTO task (id, repo_id, description, epic_id) VALUES ('tsk_1', 'repo_1', 'Task', 'epc_1')`,
+		`INSERT INTO conversation (id, repo_id, title, epic_id) VALUES ('cnv_1', 'repo_1', 'Chat', 'epc_1')`,
+	} {
+		_, err := db.Exec(stmt)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, sqlitedb.Migrate(db, migrationsUpTo(t, 41)))
+
+	var taskEpicID, convEpicID string
+	require.NoError(t, db.QueryRow(`SELECT epic_id FROM task WHERE id = 'tsk_1'`).Scan(&taskEpicID))
+	require.NoError(t, db.QueryRow(`SELECT epic_id FROM conversation WHERE id = 'cnv_1'`).Scan(&convEpicID))
+	assert.Equal(t, "epc_1", taskEpicID)
+	assert.Equal(t, "epc_1", convEpicID)
+
+	_, err := db.Exec(`UPDATE epic SET feedback_type = 'replan' WHERE id = 'epc_1'`)
+	require.NoError(t, err)
+}
@@ -0,0 +1,2 @@
+ALTER TABLE epic ADD COLUMN cost_usd REAL NOT NULL DEFAULT 0;
+ALTER TABLE epic ADD COLUMN max_cost_usd REAL;
@@ -0,0 +1 @@
+ALTER TABLE task ADD COLUMN last_review_id INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+ALTER TABLE epic ADD COLUMN planning_summary TEXT;
@@ -0,0 +1,9 @@
+CREATE TABLE notification_sink (
+    id         TEXT PRIMARY KEY,
+    repo_id    TEXT NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    kind       TEXT NOT NULL CHECK (kind IN ('slack', 'discord', 'webhook')),
+    url        TEXT NOT NULL,
+    events     TEXT NOT NULL DEFAULT '[]',
+    created_at INTEGER NOT NULL DEFAULT (unixepoch())
+);
+CREATE INDEX idx_notification_sink_repo_id ON notification_sink(repo_id);
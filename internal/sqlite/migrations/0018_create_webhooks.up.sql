@@ -0,0 +1,22 @@
+CREATE TABLE webhook_subscription (
+    id          TEXT PRIMARY KEY,
+    url         TEXT NOT NULL,
+    secret      TEXT NOT NULL,
+    event_types TEXT NOT NULL DEFAULT '[]',
+    repo_id     TEXT REFERENCES repo(id) ON DELETE CASCADE,
+    created_at  INTEGER NOT NULL DEFAULT (unixepoch())
+);
+
+CREATE TABLE webhook_delivery (
+    id              TEXT PRIMARY KEY,
+    subscription_id TEXT NOT NULL REFERENCES webhook_subscription(id) ON DELETE CASCADE,
+    event_id        TEXT NOT NULL,
+    event_type      TEXT NOT NULL,
+    attempt         INTEGER NOT NULL,
+    status_code     INTEGER,
+    error           TEXT,
+    response_body   TEXT,
+    duration_ms     INTEGER NOT NULL DEFAULT 0,
+    created_at      INTEGER NOT NULL DEFAULT (unixepoch())
+);
+CREATE INDEX idx_webhook_delivery_subscription_id ON webhook_delivery(subscription_id);
@@ -0,0 +1,12 @@
+CREATE TABLE audit_log (
+    id          TEXT PRIMARY KEY,
+    action      TEXT NOT NULL,
+    entity_type TEXT NOT NULL,
+    entity_id   TEXT NOT NULL,
+    from_status TEXT NOT NULL DEFAULT '',
+    to_status   TEXT NOT NULL DEFAULT '',
+    reason      TEXT NOT NULL,
+    created_at  INTEGER NOT NULL
+);
+
+CREATE INDEX idx_audit_log_entity ON audit_log(entity_id, id);
@@ -0,0 +1,26 @@
+CREATE TABLE task_attempt (
+    task_id      TEXT    NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    attempt      INTEGER NOT NULL,
+    retry_reason TEXT    NOT NULL DEFAULT '',
+    result       TEXT    NOT NULL DEFAULT '',
+    cost_usd     REAL    NOT NULL DEFAULT 0,
+    started_at   INTEGER NOT NULL DEFAULT (unixepoch()),
+    ended_at     INTEGER,
+    PRIMARY KEY (task_id, attempt)
+);
+
+-- Backfill attempts from existing logs. Earlier attempts were retried, and
+-- the current attempt ended in the task's status unless it is still running.
+INSERT INTO task_attempt (task_id, attempt, result, started_at, ended_at)
+SELECT l.task_id,
+       l.attempt,
+       CASE
+           WHEN l.attempt < t.attempt THEN 'retried'
+           WHEN t.status IN ('running', 'pending') THEN ''
+           ELSE t.status
+       END,
+       MIN(l.created_at),
+       CASE WHEN t.status = 'running' AND l.attempt = t.attempt THEN NULL ELSE MAX(l.created_at) END
+FROM task_log l
+JOIN task t ON t.id = l.task_id
+GROUP BY l.task_id, l.attempt;
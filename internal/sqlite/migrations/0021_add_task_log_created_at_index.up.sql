@@ -0,0 +1,3 @@
+-- Log retention deletes by age. Without an index every retention run scans
+-- the whole of task_log, which is by far the largest table.
+CREATE INDEX idx_task_log_created_at ON task_log(created_at);
@@ -0,0 +1,11 @@
+-- Structured events reported by agents (PR created, tool calls, cost, ...),
+-- stored separately from the plain log lines in task_log.
+CREATE TABLE task_event_log (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id    TEXT    NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    attempt    INTEGER NOT NULL DEFAULT 1,
+    type       TEXT    NOT NULL,
+    payload    TEXT    NOT NULL DEFAULT '{}',
+    created_at INTEGER NOT NULL DEFAULT (unixepoch())
+);
+CREATE INDEX idx_task_event_log_task_id ON task_event_log(task_id, id);
@@ -0,0 +1,8 @@
+-- The agent's latest reported plan for a task: an ordered step list with the
+-- status of each step, rebuilt from plan/step agent events as they arrive.
+CREATE TABLE task_progress (
+    task_id    TEXT    PRIMARY KEY REFERENCES task(id) ON DELETE CASCADE,
+    attempt    INTEGER NOT NULL DEFAULT 1,
+    steps      TEXT    NOT NULL DEFAULT '[]',
+    updated_at INTEGER NOT NULL DEFAULT (unixepoch())
+);
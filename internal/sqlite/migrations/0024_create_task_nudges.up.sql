@@ -0,0 +1,11 @@
+-- Messages the user sends to the agent of a running task. Undelivered
+-- nudges are handed to the worker with its next heartbeat.
+CREATE TABLE task_nudge (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id      TEXT    NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    attempt      INTEGER NOT NULL DEFAULT 1,
+    message      TEXT    NOT NULL,
+    created_at   INTEGER NOT NULL DEFAULT (unixepoch()),
+    delivered_at INTEGER
+);
+CREATE INDEX idx_task_nudge_task_id ON task_nudge(task_id, id);
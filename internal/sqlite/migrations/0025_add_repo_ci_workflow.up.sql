@@ -0,0 +1,13 @@
+-- Per-repo workflow dispatched when an agent opens or updates a pull
+-- request, stored as JSON ({"file": ..., "inputs": {...}}). Empty when the
+-- repo relies only on the checks GitHub runs on its own.
+ALTER TABLE repo ADD COLUMN ci_workflow TEXT NOT NULL DEFAULT '';
+
+-- The most recent CI workflow dispatch for a task. Check-status logic waits
+-- for the run created by this dispatch before treating the PR as green.
+CREATE TABLE task_ci_dispatch (
+    task_id       TEXT    PRIMARY KEY REFERENCES task(id) ON DELETE CASCADE,
+    workflow_file TEXT    NOT NULL,
+    ref           TEXT    NOT NULL,
+    dispatched_at INTEGER NOT NULL
+);
@@ -0,0 +1,4 @@
+-- Bumped whenever a user invalidates the repo's cached agent workspace.
+-- Workers label their per-repo workspace volumes with the generation they
+-- were created for and discard volumes from an older generation.
+ALTER TABLE repo ADD COLUMN workspace_cache_generation INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,10 @@
+-- The latest license and provenance scan of a task's PR diff. Findings are
+-- stored as a JSON array; acknowledged_at is set once a human has reviewed
+-- them and cleared the PR for merging.
+CREATE TABLE task_provenance_review (
+    task_id         TEXT    PRIMARY KEY REFERENCES task(id) ON DELETE CASCADE,
+    findings        TEXT    NOT NULL DEFAULT '[]',
+    head_sha        TEXT    NOT NULL,
+    scanned_at      INTEGER NOT NULL,
+    acknowledged_at INTEGER
+);
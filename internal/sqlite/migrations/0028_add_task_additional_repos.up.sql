@@ -0,0 +1,2 @@
+ALTER TABLE task ADD COLUMN additional_repo_ids TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE task ADD COLUMN pull_requests TEXT NOT NULL DEFAULT '[]';
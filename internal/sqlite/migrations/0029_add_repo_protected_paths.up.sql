@@ -0,0 +1,3 @@
+-- Path globs (JSON array) agents must not change in the repo. Agent pull
+-- requests touching a matching path fail the task.
+ALTER TABLE repo ADD COLUMN protected_paths TEXT NOT NULL DEFAULT '[]';
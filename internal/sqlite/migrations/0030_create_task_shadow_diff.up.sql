@@ -0,0 +1,8 @@
+-- The diff produced by a task's latest run in shadow mode, where the agent
+-- works on the repo but never pushes or opens a pull request.
+CREATE TABLE task_shadow_diff (
+    task_id    TEXT    PRIMARY KEY REFERENCES task(id) ON DELETE CASCADE,
+    attempt    INTEGER NOT NULL,
+    diff       TEXT    NOT NULL,
+    created_at INTEGER NOT NULL
+);
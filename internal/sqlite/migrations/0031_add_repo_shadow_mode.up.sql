@@ -0,0 +1,3 @@
+-- Whether agents run against the repo in shadow mode: they record their diff
+-- for review instead of pushing a branch or opening a pull request.
+ALTER TABLE repo ADD COLUMN shadow_mode INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,11 @@
+-- The latest automated self-review of a task's PR. Checks are stored as a
+-- JSON array; comment_id is the PR comment the report was posted as, so
+-- later reviews edit it instead of posting again.
+CREATE TABLE task_self_review (
+    task_id     TEXT    PRIMARY KEY REFERENCES task(id) ON DELETE CASCADE,
+    score       INTEGER NOT NULL,
+    checks      TEXT    NOT NULL DEFAULT '[]',
+    head_sha    TEXT    NOT NULL,
+    comment_id  INTEGER NOT NULL DEFAULT 0,
+    reviewed_at INTEGER NOT NULL
+);
@@ -0,0 +1,6 @@
+-- Whether an epic's tasks share an integration branch (epic/<id>): task PRs
+-- target it instead of the default branch, and a final PR merges it into the
+-- default branch once every task is done.
+ALTER TABLE epic ADD COLUMN shared_branch INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE epic ADD COLUMN pull_request_url TEXT;
+ALTER TABLE epic ADD COLUMN pr_number INTEGER;
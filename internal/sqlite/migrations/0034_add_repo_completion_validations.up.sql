@@ -0,0 +1,3 @@
+-- Validations (JSON object) agent pull requests must pass when the agent
+-- reports success. Empty when the repo runs none.
+ALTER TABLE repo ADD COLUMN completion_validations TEXT NOT NULL DEFAULT '';
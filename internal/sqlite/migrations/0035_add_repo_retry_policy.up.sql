@@ -0,0 +1,3 @@
+-- Retry policy (JSON object) overriding the instance-wide policy for the
+-- repo's tasks. Empty when the repo uses the instance-wide policy.
+ALTER TABLE repo ADD COLUMN retry_policy TEXT NOT NULL DEFAULT '';
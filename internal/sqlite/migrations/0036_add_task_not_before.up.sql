@@ -0,0 +1,3 @@
+-- Unix time before which a pending task must not be claimed. Set when a
+-- rate-limited task is retried with backoff; NULL when it can run right away.
+ALTER TABLE task ADD COLUMN not_before INTEGER;
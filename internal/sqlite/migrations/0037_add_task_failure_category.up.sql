@@ -0,0 +1,3 @@
+-- Why a failed task failed (e.g. max_attempts, ci_failure, worker_timeout),
+-- used to group failures for triage. NULL for tasks that have not failed.
+ALTER TABLE task ADD COLUMN failure_category TEXT;
@@ -0,0 +1,13 @@
+-- Notes the agent leaves for humans while working on a task, such as
+-- follow-up work it discovered. A note can be converted into a new task.
+CREATE TABLE task_note (
+    id                INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id           TEXT    NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    repo_id           TEXT    NOT NULL,
+    attempt           INTEGER NOT NULL DEFAULT 1,
+    text              TEXT    NOT NULL,
+    created_at        INTEGER NOT NULL DEFAULT (unixepoch()),
+    converted_task_id TEXT
+);
+CREATE INDEX idx_task_note_repo_id ON task_note(repo_id, id);
+CREATE INDEX idx_task_note_task_id ON task_note(task_id);
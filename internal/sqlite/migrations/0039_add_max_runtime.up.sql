@@ -0,0 +1,6 @@
+-- Longest a task attempt may run, in seconds, before the worker kills it and
+-- the task fails. 0 uses the repo's max_runtime_seconds.
+ALTER TABLE task ADD COLUMN max_runtime_seconds INTEGER NOT NULL DEFAULT 0;
+
+-- Default max runtime, in seconds, for the repo's tasks. 0 means no limit.
+ALTER TABLE repo ADD COLUMN max_runtime_seconds INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,6 @@
+-- Worker labels the task needs, as a JSON array. Only workers that have all
+-- of them (and all of the repo's required_labels) can claim the task.
+ALTER TABLE task ADD COLUMN required_labels TEXT NOT NULL DEFAULT '[]';
+
+-- Worker labels every task in the repo needs, as a JSON array.
+ALTER TABLE repo ADD COLUMN required_labels TEXT NOT NULL DEFAULT '[]';
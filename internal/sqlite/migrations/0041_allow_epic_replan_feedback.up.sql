@@ -0,0 +1,61 @@
+-- Replanning an epic sends it back to planning with 'replan' feedback.
+-- SQLite can't alter a CHECK constraint, so the epic table is recreated.
+
+-- Migrations run in a transaction with foreign keys enabled, so dropping epic
+-- sets the epic_id of its tasks and conversations to NULL. Keep the links to
+-- restore them afterwards.
+CREATE TEMP TABLE task_epic AS
+    SELECT id, epic_id FROM task WHERE epic_id IS NOT NULL;
+CREATE TEMP TABLE conversation_epic AS
+    SELECT id, epic_id FROM conversation WHERE epic_id IS NOT NULL;
+
+CREATE TABLE epic_new (
+    id                TEXT PRIMARY KEY,
+    repo_id           TEXT    NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    title             TEXT    NOT NULL,
+    description       TEXT    NOT NULL DEFAULT '',
+    status            TEXT    NOT NULL DEFAULT 'draft'
+                      CHECK(status IN ('draft', 'planning', 'ready', 'active', 'completed', 'closed')),
+    proposed_tasks    TEXT    NOT NULL DEFAULT '[]',
+    task_ids          TEXT    NOT NULL DEFAULT '[]',
+    planning_prompt   TEXT,
+    session_log       TEXT    NOT NULL DEFAULT '[]',
+    not_ready         INTEGER NOT NULL DEFAULT 0,
+    claimed_at        INTEGER,
+    last_heartbeat_at INTEGER,
+    feedback          TEXT,
+    feedback_type     TEXT    CHECK(feedback_type IN ('message', 'confirmed', 'closed', 'replan')),
+    model             TEXT,
+    created_at        INTEGER NOT NULL DEFAULT (unixepoch()),
+    updated_at        INTEGER NOT NULL DEFAULT (unixepoch()),
+    number            INTEGER,
+    cost_usd          REAL    NOT NULL DEFAULT 0,
+    max_cost_usd      REAL,
+    planning_summary  TEXT,
+    shared_branch     INTEGER NOT NULL DEFAULT 0,
+    pull_request_url  TEXT,
+    pr_number         INTEGER
+);
+INSERT INTO epic_new (id, repo_id, title, description, status, proposed_tasks, task_ids,
+    planning_prompt, session_log, not_ready, claimed_at, last_heartbeat_at, feedback,
+    feedback_type, model, created_at, updated_at, number, cost_usd, max_cost_usd,
+    planning_summary, shared_branch, pull_request_url, pr_number)
+    SELECT id, repo_id, title, description, status, proposed_tasks, task_ids,
+    planning_prompt, session_log, not_ready, claimed_at, last_heartbeat_at, feedback,
+    feedback_type, model, created_at, updated_at, number, cost_usd, max_cost_usd,
+    planning_summary, shared_branch, pull_request_url, pr_number
+    FROM epic;
+DROP TABLE epic;
+ALTER TABLE epic_new RENAME TO epic;
+CREATE INDEX idx_epic_repo_id ON epic(repo_id);
+CREATE INDEX idx_epic_status ON epic(status);
+CREATE UNIQUE INDEX idx_epic_repo_number ON epic(repo_id, number) WHERE number IS NOT NULL;
+
+UPDATE task SET
+    epic_id = (SELECT epic_id FROM task_epic WHERE task_epic.id = task.id)
+WHERE id IN (SELECT id FROM task_epic);
+UPDATE conversation SET
+    epic_id = (SELECT epic_id FROM conversation_epic WHERE conversation_epic.id = conversation.id)
+WHERE id IN (SELECT id FROM conversation_epic);
+DROP TABLE task_epic;
+DROP TABLE conversation_epic;
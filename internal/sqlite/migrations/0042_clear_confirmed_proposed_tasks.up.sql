@@ -0,0 +1,5 @@
+-- Confirming an epic now removes the proposed tasks it creates, leaving only
+-- those still to be confirmed. Clear the proposals of epics confirmed
+-- before, which were all created at once.
+UPDATE epic SET proposed_tasks = '[]'
+WHERE task_ids != '[]' AND status IN ('ready', 'active', 'completed', 'closed');
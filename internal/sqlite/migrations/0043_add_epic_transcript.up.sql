@@ -0,0 +1,23 @@
+-- Planning transcript as a JSON array of {role, content, timestamp}: the
+-- session log with who wrote each line and when.
+ALTER TABLE epic ADD COLUMN transcript TEXT NOT NULL DEFAULT '[]';
+
+-- Backfill from the session log. When earlier lines were written isn't
+-- known, so they take the epic's last update time.
+UPDATE epic SET transcript = (
+    SELECT json_group_array(json_object(
+        'role', CASE
+            WHEN line.value LIKE 'user: %' THEN 'user'
+            WHEN line.value LIKE 'system: %' THEN 'system'
+            ELSE 'agent'
+        END,
+        'content', CASE
+            WHEN line.value LIKE 'user: %' THEN substr(line.value, 7)
+            WHEN line.value LIKE 'system: %' THEN substr(line.value, 9)
+            ELSE line.value
+        END,
+        'timestamp', epic.updated_at
+    ))
+    FROM (SELECT value FROM json_each(epic.session_log) ORDER BY key) AS line
+)
+WHERE session_log != '[]';
@@ -0,0 +1,32 @@
+-- Teams group repos and hold settings their repos inherit.
+CREATE TABLE team (
+    id                   TEXT PRIMARY KEY,
+    name                 TEXT    NOT NULL UNIQUE,
+    default_model        TEXT    NOT NULL DEFAULT '',
+    default_max_cost_usd REAL    NOT NULL DEFAULT 0,
+    created_at           INTEGER NOT NULL DEFAULT (unixepoch())
+);
+
+-- The team a repo belongs to. Deleting the team leaves its repos without one.
+ALTER TABLE repo ADD COLUMN team_id TEXT REFERENCES team(id) ON DELETE SET NULL;
+CREATE INDEX idx_repo_team_id ON repo(team_id);
+
+-- Notification sinks belong to either a repo or a team. A team's sinks
+-- receive the events of every repo in it. SQLite can't drop the NOT NULL
+-- constraint on repo_id, so the table is recreated.
+CREATE TABLE notification_sink_new (
+    id         TEXT PRIMARY KEY,
+    repo_id    TEXT REFERENCES repo(id) ON DELETE CASCADE,
+    kind       TEXT NOT NULL CHECK (kind IN ('slack', 'discord', 'webhook')),
+    url        TEXT NOT NULL,
+    events     TEXT NOT NULL DEFAULT '[]',
+    created_at INTEGER NOT NULL DEFAULT (unixepoch()),
+    team_id    TEXT REFERENCES team(id) ON DELETE CASCADE,
+    CHECK ((repo_id IS NULL) != (team_id IS NULL))
+);
+INSERT INTO notification_sink_new (id, repo_id, kind, url, events, created_at)
+    SELECT id, repo_id, kind, url, events, created_at FROM notification_sink;
+DROP TABLE notification_sink;
+ALTER TABLE notification_sink_new RENAME TO notification_sink;
+CREATE INDEX idx_notification_sink_repo_id ON notification_sink(repo_id);
+CREATE INDEX idx_notification_sink_team_id ON notification_sink(team_id);
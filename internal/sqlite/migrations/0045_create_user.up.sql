@@ -0,0 +1,9 @@
+-- Users call the API with their own token and are granted access by role.
+-- Only a hash of the token is stored.
+CREATE TABLE user (
+    id         TEXT PRIMARY KEY,
+    name       TEXT    NOT NULL UNIQUE,
+    role       TEXT    NOT NULL CHECK (role IN ('viewer', 'operator', 'admin')),
+    token_hash TEXT    NOT NULL UNIQUE,
+    created_at INTEGER NOT NULL DEFAULT (unixepoch())
+);
@@ -0,0 +1,13 @@
+-- Short-lived sessions of people signed in through OIDC. Each carries the
+-- role their groups mapped to when it was issued. Only a hash of the token
+-- is stored.
+CREATE TABLE user_session (
+    id         TEXT PRIMARY KEY,
+    subject    TEXT    NOT NULL,
+    name       TEXT    NOT NULL,
+    role       TEXT    NOT NULL CHECK (role IN ('viewer', 'operator', 'admin')),
+    token_hash TEXT    NOT NULL UNIQUE,
+    expires_at INTEGER NOT NULL,
+    created_at INTEGER NOT NULL DEFAULT (unixepoch())
+);
+CREATE INDEX idx_user_session_expires_at ON user_session(expires_at);
@@ -0,0 +1,15 @@
+-- GitHub credentials used instead of the global token for the repos and
+-- owners they cover: personal access tokens and GitHub App installations.
+-- repos is a JSON array of "owner/name" and "owner/*" entries. The secret is
+-- the token or the app's private key, encrypted like the global token.
+CREATE TABLE github_credential (
+    id               TEXT PRIMARY KEY,
+    name             TEXT    NOT NULL UNIQUE,
+    kind             TEXT    NOT NULL CHECK (kind IN ('token', 'app')),
+    repos            TEXT    NOT NULL DEFAULT '[]',
+    encrypted_secret TEXT    NOT NULL,
+    app_id           INTEGER NOT NULL DEFAULT 0,
+    installation_id  INTEGER NOT NULL DEFAULT 0,
+    created_at       INTEGER NOT NULL,
+    updated_at       INTEGER NOT NULL
+);
@@ -0,0 +1,13 @@
+-- Task events shared between API replicas when EVENT_BUS=database. Each
+-- replica appends the events it publishes and polls for the others', so SSE
+-- and WebSocket clients see every replica's events. origin identifies the
+-- publishing replica. Rows are only kept for a few minutes; AUTOINCREMENT
+-- keeps IDs increasing after old rows are deleted.
+CREATE TABLE event_bus (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    origin     TEXT    NOT NULL,
+    payload    TEXT    NOT NULL,
+    created_at INTEGER NOT NULL
+);
+
+CREATE INDEX idx_event_bus_created_at ON event_bus (created_at);
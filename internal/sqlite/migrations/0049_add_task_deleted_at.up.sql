@@ -0,0 +1,6 @@
+-- Unix time a task was moved to the trash. Trashed tasks keep their logs and
+-- attempts but are hidden everywhere except the trash, and are purged once
+-- TASK_TRASH_RETENTION has passed. NULL for tasks that are not trashed.
+ALTER TABLE task ADD COLUMN deleted_at INTEGER;
+
+CREATE INDEX idx_task_deleted_at ON task(deleted_at) WHERE deleted_at IS NOT NULL;
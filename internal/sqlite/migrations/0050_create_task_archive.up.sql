@@ -0,0 +1,17 @@
+-- Unix time a merged or closed task was archived. Archived tasks are left out
+-- of task lists unless they are asked for, and their logs are moved to
+-- task_log_archive. NULL for tasks that are not archived.
+ALTER TABLE task ADD COLUMN archived_at INTEGER;
+
+-- Covers listing a repo's live tasks, so archived and trashed tasks no longer
+-- slow it down however many pile up.
+CREATE INDEX idx_task_repo_live ON task(repo_id, created_at) WHERE archived_at IS NULL AND deleted_at IS NULL;
+
+-- The logs of an archived task as one JSON array of {attempt, lines} batches,
+-- in the order they were written. They move back to task_log when the task is
+-- unarchived.
+CREATE TABLE task_log_archive (
+    task_id     TEXT    PRIMARY KEY REFERENCES task(id) ON DELETE CASCADE,
+    batches     TEXT    NOT NULL DEFAULT '[]',
+    archived_at INTEGER NOT NULL DEFAULT (unixepoch())
+);
@@ -0,0 +1,13 @@
+-- Comments people leave on a task to discuss it or review its work. A
+-- comment may refer to one of the task's attempts.
+CREATE TABLE task_comment (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id    TEXT    NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    attempt    INTEGER,
+    author     TEXT    NOT NULL,
+    author_id  TEXT,
+    body       TEXT    NOT NULL,
+    created_at INTEGER NOT NULL DEFAULT (unixepoch()),
+    updated_at INTEGER NOT NULL DEFAULT (unixepoch())
+);
+CREATE INDEX idx_task_comment_task_id ON task_comment(task_id, id);
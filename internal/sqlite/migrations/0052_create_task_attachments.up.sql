@@ -0,0 +1,14 @@
+-- Files attached to a task for its agent to read, such as design
+-- screenshots or error logs. The contents live in attachment storage under
+-- storage_key; this table holds their metadata.
+CREATE TABLE task_attachment (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id      TEXT    NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    filename     TEXT    NOT NULL,
+    content_type TEXT    NOT NULL,
+    size_bytes   INTEGER NOT NULL,
+    storage_key  TEXT    NOT NULL,
+    uploaded_by  TEXT    NOT NULL,
+    created_at   INTEGER NOT NULL DEFAULT (unixepoch()),
+    UNIQUE (task_id, filename)
+);
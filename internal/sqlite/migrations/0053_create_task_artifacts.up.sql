@@ -0,0 +1,16 @@
+-- Files an agent produced during an attempt, such as test reports, coverage
+-- summaries or screenshots. The contents live in attachment storage under
+-- storage_key; this table holds their metadata.
+CREATE TABLE task_artifact (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id      TEXT    NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    attempt      INTEGER NOT NULL,
+    name         TEXT    NOT NULL,
+    content_type TEXT    NOT NULL,
+    size_bytes   INTEGER NOT NULL,
+    storage_key  TEXT    NOT NULL,
+    created_at   INTEGER NOT NULL DEFAULT (unixepoch()),
+    UNIQUE (task_id, attempt, name)
+);
+
+CREATE INDEX idx_task_artifact_created_at ON task_artifact (created_at);
@@ -0,0 +1,4 @@
+-- CI check names (JSON array) that don't count towards an agent PR's check
+-- status, such as known-flaky checks unrelated to the change, so their
+-- failures never send the task back to the agent.
+ALTER TABLE repo ADD COLUMN ignored_checks TEXT NOT NULL DEFAULT '[]';
@@ -0,0 +1,3 @@
+-- Per-repo limit on the CI checks that gate agent PRs, stored as JSON
+-- ({"required_only": true, "checks": [...]}). Empty when every check gates.
+ALTER TABLE repo ADD COLUMN check_gating TEXT NOT NULL DEFAULT '';
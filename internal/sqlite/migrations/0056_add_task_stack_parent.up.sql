@@ -0,0 +1,4 @@
+-- The dependency a stacked task branches off. Its PR targets the parent's
+-- branch until the parent's PR is merged, then it is retargeted at the
+-- branch the parent merged into. NULL for tasks that aren't stacked.
+ALTER TABLE task ADD COLUMN stack_parent_id TEXT;
@@ -0,0 +1,17 @@
+-- Whether the task's next run only proposes a change plan instead of
+-- implementing it. Cleared when the proposal is approved.
+ALTER TABLE task ADD COLUMN plan_only INTEGER NOT NULL DEFAULT 0;
+
+-- The change plan produced by a task's latest plan-only run. files and risks
+-- are JSON arrays. approved_at is set once a human approves the plan for
+-- implementation.
+CREATE TABLE task_proposal (
+    task_id     TEXT    PRIMARY KEY REFERENCES task(id) ON DELETE CASCADE,
+    attempt     INTEGER NOT NULL,
+    summary     TEXT    NOT NULL,
+    approach    TEXT    NOT NULL,
+    files       TEXT    NOT NULL DEFAULT '[]',
+    risks       TEXT    NOT NULL DEFAULT '[]',
+    created_at  INTEGER NOT NULL,
+    approved_at INTEGER
+);
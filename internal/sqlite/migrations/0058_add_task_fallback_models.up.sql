@@ -0,0 +1,11 @@
+-- Models (JSON array) a task falls back to, in order, when its agent is
+-- rate-limited or overloaded, and the model its next attempt runs with once
+-- it has fallen back ('' while it runs with its configured model).
+ALTER TABLE task ADD COLUMN fallback_models TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE task ADD COLUMN active_model TEXT NOT NULL DEFAULT '';
+
+-- Default fallback models (JSON array) for new tasks in the repo.
+ALTER TABLE repo ADD COLUMN fallback_models TEXT NOT NULL DEFAULT '[]';
+
+-- Model the attempt's agent ran with.
+ALTER TABLE task_attempt ADD COLUMN model TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+-- Whether the repo's queue is paused: its pending tasks are not handed to
+-- workers until it is resumed. Tasks can still be created.
+ALTER TABLE repo ADD COLUMN paused INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+-- Per-repo PR sync settings: sync_disabled stops background sync of the
+-- repo's pull requests, and sync_interval_seconds overrides the global sync
+-- interval (0 = use the global interval).
+ALTER TABLE repo ADD COLUMN sync_disabled INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE repo ADD COLUMN sync_interval_seconds INTEGER NOT NULL DEFAULT 0;
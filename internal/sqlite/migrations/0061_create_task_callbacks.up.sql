@@ -0,0 +1,11 @@
+-- Idempotency keys of the worker callbacks applied to each task attempt, so
+-- a callback the worker sends again after losing the response is applied at
+-- most once.
+CREATE TABLE task_callback (
+    task_id         TEXT    NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    attempt         INTEGER NOT NULL,
+    idempotency_key TEXT    NOT NULL,
+    created_at      INTEGER NOT NULL DEFAULT (unixepoch()),
+    PRIMARY KEY (task_id, attempt, idempotency_key)
+);
+CREATE INDEX idx_task_callback_created_at ON task_callback(created_at);
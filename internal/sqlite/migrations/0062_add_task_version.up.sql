@@ -0,0 +1,9 @@
+-- Version of the task, bumped on every update so edits made from a stale
+-- read of the task can be rejected.
+ALTER TABLE task ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+CREATE TRIGGER task_version AFTER UPDATE ON task
+WHEN NEW.version = OLD.version
+BEGIN
+    UPDATE task SET version = OLD.version + 1 WHERE id = NEW.id;
+END;
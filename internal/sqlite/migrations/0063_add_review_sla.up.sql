@@ -0,0 +1,32 @@
+-- Per-repo review SLA: how long a task may sit in review before it is
+-- reported overdue (0 = no SLA).
+ALTER TABLE repo ADD COLUMN review_sla_seconds INTEGER NOT NULL DEFAULT 0;
+
+-- Each time a task spent in review. A span is open (ended_at NULL) while the
+-- task is in review, and overdue_at records when it was reported overdue.
+CREATE TABLE task_review (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id    TEXT    NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    started_at INTEGER NOT NULL DEFAULT (unixepoch()),
+    ended_at   INTEGER,
+    overdue_at INTEGER
+);
+CREATE INDEX idx_task_review_task_id ON task_review(task_id);
+CREATE INDEX idx_task_review_open ON task_review(started_at) WHERE ended_at IS NULL;
+
+CREATE TRIGGER task_review_start AFTER UPDATE OF status ON task
+WHEN NEW.status = 'review' AND OLD.status <> 'review'
+BEGIN
+    INSERT INTO task_review (task_id) VALUES (NEW.id);
+END;
+
+CREATE TRIGGER task_review_end AFTER UPDATE OF status ON task
+WHEN OLD.status = 'review' AND NEW.status <> 'review'
+BEGIN
+    UPDATE task_review SET ended_at = unixepoch()
+    WHERE task_id = NEW.id AND ended_at IS NULL;
+END;
+
+-- Tasks already in review are taken to have entered it at their last update.
+INSERT INTO task_review (task_id, started_at)
+SELECT id, updated_at FROM task WHERE status = 'review';
@@ -0,0 +1,2 @@
+-- Throughput reports read the task status changes recorded over a window.
+CREATE INDEX idx_audit_log_created_at ON audit_log(created_at);
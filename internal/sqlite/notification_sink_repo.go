@@ -0,0 +1,117 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/joshjon/kit/errtag"
+
+	"github.com/vervesh/verve/internal/notification"
+	"github.com/vervesh/verve/internal/sqlite/sqlc"
+)
+
+var _ notification.Repository = (*NotificationSinkRepository)(nil)
+
+// NotificationSinkRepository implements notification.Repository using SQLite.
+type NotificationSinkRepository struct {
+	db *sqlc.Queries
+}
+
+// NewNotificationSinkRepository creates a new NotificationSinkRepository backed by the given SQLite DB.
+func NewNotificationSinkRepository(db DB) *NotificationSinkRepository {
+	return &NotificationSinkRepository{
+		db: sqlc.New(db),
+	}
+}
+
+func (r *NotificationSinkRepository) CreateSink(ctx context.Context, sink *notification.Sink) error {
+	eventsJSON, _ := json.Marshal(sink.Events)
+	var repoID, teamID *string
+	if sink.RepoID != "" {
+		repoID = ptr(sink.RepoID)
+	}
+	if sink.TeamID != "" {
+		teamID = ptr(sink.TeamID)
+	}
+	err := r.db.CreateNotificationSink(ctx, sqlc.CreateNotificationSinkParams{
+		ID:        sink.ID.String(),
+		RepoID:    repoID,
+		TeamID:    teamID,
+		Kind:      string(sink.Kind),
+		Url:       sink.URL,
+		Events:    string(eventsJSON),
+		CreatedAt: sink.CreatedAt.Unix(),
+	})
+	return tagNotificationSinkErr(err)
+}
+
+func (r *NotificationSinkRepository) ReadSink(ctx context.Context, id notification.SinkID) (*notification.Sink, error) {
+	row, err := r.db.ReadNotificationSink(ctx, id.String())
+	if err != nil {
+		return nil, tagNotificationSinkErr(err)
+	}
+	return unmarshalNotificationSink(row), nil
+}
+
+func (r *NotificationSinkRepository) ListSinksByRepo(ctx context.Context, repoID string) ([]*notification.Sink, error) {
+	rows, err := r.db.ListNotificationSinksByRepo(ctx, &repoID)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalNotificationSinks(rows), nil
+}
+
+func (r *NotificationSinkRepository) ListSinksByTeam(ctx context.Context, teamID string) ([]*notification.Sink, error) {
+	rows, err := r.db.ListNotificationSinksByTeam(ctx, &teamID)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalNotificationSinks(rows), nil
+}
+
+func (r *NotificationSinkRepository) DeleteSink(ctx context.Context, id notification.SinkID) error {
+	return tagNotificationSinkErr(r.db.DeleteNotificationSink(ctx, id.String()))
+}
+
+func unmarshalNotificationSinks(rows []*sqlc.NotificationSink) []*notification.Sink {
+	out := make([]*notification.Sink, len(rows))
+	for i := range rows {
+		out[i] = unmarshalNotificationSink(rows[i])
+	}
+	return out
+}
+
+func unmarshalNotificationSink(in *sqlc.NotificationSink) *notification.Sink {
+	s := &notification.Sink{
+		ID:        notification.MustParseSinkID(in.ID),
+		Kind:      notification.Kind(in.Kind),
+		URL:       in.Url,
+		CreatedAt: unixToTime(in.CreatedAt),
+	}
+	if in.RepoID != nil {
+		s.RepoID = *in.RepoID
+	}
+	if in.TeamID != nil {
+		s.TeamID = *in.TeamID
+	}
+	_ = json.Unmarshal([]byte(in.Events), &s.Events)
+	if s.Events == nil {
+		s.Events = []notification.EventType{}
+	}
+	return s
+}
+
+func tagNotificationSinkErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return errtag.Tag[notification.ErrTagSinkNotFound](err)
+	}
+	if isSQLiteErrCode(err, sqliteConstraint, sqliteConstraintUnique, sqliteConstraintPrimaryKey) {
+		return errtag.Tag[notification.ErrTagSinkConflict](err)
+	}
+	return err
+}
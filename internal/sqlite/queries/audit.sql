@@ -0,0 +1,9 @@
+-- name: CreateAuditLog :exec
+INSERT INTO audit_log (id, action, entity_type, entity_id, from_status, to_status, reason, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListAuditLogs :many
+SELECT * FROM audit_log ORDER BY id DESC LIMIT ?;
+
+-- name: ListAuditLogsByEntity :many
+SELECT * FROM audit_log WHERE entity_id = ? ORDER BY id DESC LIMIT ?;
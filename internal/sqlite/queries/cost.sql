@@ -0,0 +1,16 @@
+-- name: ListFinishedTaskCosts :many
+SELECT id, repo_id, title, cost_usd FROM task
+WHERE status IN ('review', 'merged', 'closed', 'failed') AND cost_usd > 0 AND deleted_at IS NULL
+ORDER BY created_at DESC
+LIMIT ?;
+
+-- name: ListActiveTaskCosts :many
+SELECT id, repo_id, title, cost_usd FROM task
+WHERE status IN ('pending', 'running') AND cost_usd > 0 AND deleted_at IS NULL;
+
+-- name: ListRepoSpendSince :many
+SELECT t.repo_id, CAST(SUM(a.cost_usd) AS REAL) AS cost_usd
+FROM task_attempt a
+JOIN task t ON t.id = a.task_id
+WHERE COALESCE(a.ended_at, a.started_at) >= CAST(sqlc.arg(since) AS INTEGER)
+GROUP BY t.repo_id;
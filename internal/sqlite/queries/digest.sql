@@ -0,0 +1,12 @@
+-- name: ListRepoTasksUpdatedSince :many
+SELECT * FROM task
+WHERE repo_id = sqlc.arg(repo_id) AND updated_at >= CAST(sqlc.arg(since) AS INTEGER) AND deleted_at IS NULL
+ORDER BY updated_at DESC;
+
+-- name: ListRepoTaskAttemptsSince :many
+SELECT a.task_id, t.number AS task_number, t.title AS task_title, a.attempt, a.retry_reason, a.result, a.cost_usd, a.started_at, a.ended_at
+FROM task_attempt a
+JOIN task t ON t.id = a.task_id
+WHERE t.repo_id = sqlc.arg(repo_id)
+  AND (a.started_at >= CAST(sqlc.arg(since) AS INTEGER) OR a.ended_at >= CAST(sqlc.arg(since) AS INTEGER))
+ORDER BY COALESCE(a.ended_at, a.started_at) DESC;
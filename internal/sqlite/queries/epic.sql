@@ -1,6 +1,6 @@
 -- name: CreateEpic :exec
-INSERT INTO epic (id, repo_id, title, description, status, proposed_tasks, task_ids, planning_prompt, session_log, not_ready, model, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+INSERT INTO epic (id, repo_id, title, description, status, proposed_tasks, task_ids, planning_prompt, session_log, not_ready, model, max_cost_usd, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: ReadEpic :one
 SELECT * FROM epic WHERE id = ?;
@@ -22,6 +22,7 @@ UPDATE epic SET
   session_log = ?,
   not_ready = ?,
   model = ?,
+  max_cost_usd = ?,
   updated_at = unixepoch()
 WHERE id = ?;
 
@@ -37,8 +38,20 @@ WHERE id = ?;
 UPDATE epic SET task_ids = ?, updated_at = unixepoch()
 WHERE id = ?;
 
+-- name: SetEpicPlanningSummary :exec
+UPDATE epic SET planning_summary = ?, updated_at = unixepoch()
+WHERE id = ?;
+
+-- name: SetEpicSharedBranch :exec
+UPDATE epic SET shared_branch = ?, updated_at = unixepoch()
+WHERE id = ?;
+
+-- name: SetEpicPullRequest :exec
+UPDATE epic SET pull_request_url = ?, pr_number = ?, updated_at = unixepoch()
+WHERE id = ?;
+
 -- name: AppendSessionLog :exec
-UPDATE epic SET session_log = ?, updated_at = unixepoch()
+UPDATE epic SET session_log = ?, transcript = ?, updated_at = unixepoch()
 WHERE id = ?;
 
 -- name: DeleteEpic :exec
@@ -101,3 +114,6 @@ SELECT * FROM epic WHERE repo_id = ? AND number = ?;
 SELECT * FROM epic
 WHERE status = 'active'
 ORDER BY created_at ASC;
+
+-- name: AddEpicCost :exec
+UPDATE epic SET cost_usd = cost_usd + ?, updated_at = unixepoch() WHERE id = ?;
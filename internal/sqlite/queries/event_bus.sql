@@ -0,0 +1,11 @@
+-- name: AppendBusEvent :exec
+INSERT INTO event_bus (origin, payload, created_at) VALUES (?, ?, unixepoch());
+
+-- name: ListBusEventsAfter :many
+SELECT * FROM event_bus WHERE id > ? ORDER BY id LIMIT ?;
+
+-- name: LatestBusEventID :one
+SELECT CAST(COALESCE(MAX(id), 0) AS INTEGER) FROM event_bus;
+
+-- name: DeleteBusEventsBefore :execrows
+DELETE FROM event_bus WHERE created_at < ?;
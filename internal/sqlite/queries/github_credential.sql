@@ -0,0 +1,23 @@
+-- name: CreateGitHubCredential :exec
+INSERT INTO github_credential (id, name, kind, repos, encrypted_secret, app_id, installation_id, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ReadGitHubCredential :one
+SELECT * FROM github_credential WHERE id = ?;
+
+-- name: ListGitHubCredentials :many
+SELECT * FROM github_credential ORDER BY name ASC;
+
+-- name: UpdateGitHubCredential :exec
+UPDATE github_credential
+SET name = ?,
+    kind = ?,
+    repos = ?,
+    encrypted_secret = ?,
+    app_id = ?,
+    installation_id = ?,
+    updated_at = ?
+WHERE id = ?;
+
+-- name: DeleteGitHubCredential :exec
+DELETE FROM github_credential WHERE id = ?;
@@ -0,0 +1,8 @@
+-- name: AcquireLease :execrows
+INSERT INTO lease (name, holder, expires_at)
+VALUES (sqlc.arg(name), sqlc.arg(holder), unixepoch() + CAST(sqlc.arg(ttl_seconds) AS INTEGER))
+ON CONFLICT (name) DO UPDATE SET holder = excluded.holder, expires_at = excluded.expires_at
+WHERE lease.holder = excluded.holder OR lease.expires_at <= unixepoch();
+
+-- name: ReleaseLease :exec
+DELETE FROM lease WHERE name = ? AND holder = ?;
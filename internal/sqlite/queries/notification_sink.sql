@@ -0,0 +1,15 @@
+-- name: CreateNotificationSink :exec
+INSERT INTO notification_sink (id, repo_id, team_id, kind, url, events, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: ReadNotificationSink :one
+SELECT * FROM notification_sink WHERE id = ?;
+
+-- name: ListNotificationSinksByRepo :many
+SELECT * FROM notification_sink WHERE repo_id = ? ORDER BY created_at ASC;
+
+-- name: ListNotificationSinksByTeam :many
+SELECT * FROM notification_sink WHERE team_id = ? ORDER BY created_at ASC;
+
+-- name: DeleteNotificationSink :exec
+DELETE FROM notification_sink WHERE id = ?;
@@ -35,6 +35,81 @@ SET expectations = ?,
     setup_completed_at = ?
 WHERE id = ?;
 
+-- name: UpdateRepoCIWorkflow :exec
+UPDATE repo
+SET ci_workflow = ?
+WHERE id = ?;
+
+-- name: UpdateRepoProtectedPaths :exec
+UPDATE repo
+SET protected_paths = ?
+WHERE id = ?;
+
+-- name: UpdateRepoShadowMode :exec
+UPDATE repo
+SET shadow_mode = ?
+WHERE id = ?;
+
+-- name: UpdateRepoCompletionValidations :exec
+UPDATE repo
+SET completion_validations = ?
+WHERE id = ?;
+
+-- name: UpdateRepoRetryPolicy :exec
+UPDATE repo
+SET retry_policy = ?
+WHERE id = ?;
+
+-- name: UpdateRepoMaxRuntime :exec
+UPDATE repo
+SET max_runtime_seconds = ?
+WHERE id = ?;
+
+-- name: UpdateRepoRequiredLabels :exec
+UPDATE repo
+SET required_labels = ?
+WHERE id = ?;
+
+-- name: UpdateRepoFallbackModels :exec
+UPDATE repo
+SET fallback_models = ?
+WHERE id = ?;
+
+-- name: UpdateRepoIgnoredChecks :exec
+UPDATE repo
+SET ignored_checks = ?
+WHERE id = ?;
+
+-- name: UpdateRepoCheckGating :exec
+UPDATE repo
+SET check_gating = ?
+WHERE id = ?;
+
+-- name: UpdateRepoPaused :exec
+UPDATE repo
+SET paused = ?
+WHERE id = ?;
+
+-- name: UpdateRepoSyncDisabled :exec
+UPDATE repo
+SET sync_disabled = ?
+WHERE id = ?;
+
+-- name: UpdateRepoSyncInterval :exec
+UPDATE repo
+SET sync_interval_seconds = ?
+WHERE id = ?;
+
+-- name: UpdateRepoReviewSLA :exec
+UPDATE repo
+SET review_sla_seconds = ?
+WHERE id = ?;
+
+-- name: IncrementRepoWorkspaceCacheGeneration :exec
+UPDATE repo
+SET workspace_cache_generation = workspace_cache_generation + 1
+WHERE id = ?;
+
 -- name: UpdateRepoSummary :exec
 UPDATE repo
 SET summary = ?
@@ -47,3 +122,11 @@ WHERE id = ?;
 
 -- name: ListReposBySetupStatus :many
 SELECT * FROM repo WHERE setup_status = ? ORDER BY created_at DESC;
+
+-- name: UpdateRepoTeam :exec
+UPDATE repo
+SET team_id = ?
+WHERE id = ?;
+
+-- name: ListReposByTeam :many
+SELECT * FROM repo WHERE team_id = ? ORDER BY full_name ASC;
@@ -1,18 +1,18 @@
 -- name: CreateTask :exec
-INSERT INTO task (id, repo_id, type, title, description, status, depends_on, attempt, max_attempts, acceptance_criteria_list, max_cost_usd, skip_pr, draft_pr, model, ready, epic_id, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+INSERT INTO task (id, repo_id, type, title, description, status, depends_on, attempt, max_attempts, acceptance_criteria_list, max_cost_usd, max_runtime_seconds, required_labels, fallback_models, skip_pr, draft_pr, model, ready, epic_id, additional_repo_ids, stack_parent_id, plan_only, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: ReadTask :one
-SELECT * FROM task WHERE id = ?;
+SELECT * FROM task WHERE id = ? AND deleted_at IS NULL;
 
 -- name: ListTasks :many
-SELECT * FROM task WHERE type = 'task' ORDER BY created_at DESC;
+SELECT * FROM task WHERE type = 'task' AND deleted_at IS NULL AND archived_at IS NULL ORDER BY created_at DESC;
 
 -- name: ListTasksByRepo :many
-SELECT * FROM task WHERE repo_id = ? AND type = 'task' ORDER BY created_at DESC;
+SELECT * FROM task WHERE repo_id = ? AND type = 'task' AND deleted_at IS NULL AND archived_at IS NULL ORDER BY created_at DESC;
 
 -- name: ListPendingTasks :many
-SELECT * FROM task WHERE status = 'pending' AND ready = 1 ORDER BY created_at ASC;
+SELECT * FROM task WHERE status = 'pending' AND ready = 1 AND (not_before IS NULL OR not_before <= unixepoch()) AND deleted_at IS NULL ORDER BY created_at ASC;
 
 -- name: AppendTaskLogs :exec
 INSERT INTO task_log (task_id, attempt, lines) VALUES (?, ?, ?);
@@ -28,28 +28,35 @@ WHERE id = ?;
 UPDATE task SET pull_request_url = ?, pr_number = ?, status = 'review', updated_at = unixepoch()
 WHERE id = ?;
 
+-- name: SetTaskPullRequests :exec
+UPDATE task SET pull_requests = ?, updated_at = unixepoch()
+WHERE id = ?;
+
 -- name: ListTasksInReview :many
-SELECT * FROM task WHERE status = 'review';
+SELECT * FROM task WHERE status = 'review' AND deleted_at IS NULL;
 
 -- name: ListTasksInReviewByRepo :many
-SELECT * FROM task WHERE repo_id = ? AND status = 'review';
+SELECT * FROM task WHERE repo_id = ? AND status = 'review' AND deleted_at IS NULL;
+
+-- name: ListFailedTasksByRepo :many
+SELECT * FROM task WHERE repo_id = ? AND status = 'failed' AND deleted_at IS NULL ORDER BY updated_at DESC;
 
 -- name: CloseTask :exec
 UPDATE task SET status = 'closed', close_reason = ?, updated_at = unixepoch()
 WHERE id = ?;
 
 -- name: TaskExists :one
-SELECT EXISTS(SELECT 1 FROM task WHERE id = ?);
+SELECT EXISTS(SELECT 1 FROM task WHERE id = ? AND deleted_at IS NULL);
 
 -- name: ReadTaskStatus :one
-SELECT status FROM task WHERE id = ?;
+SELECT status FROM task WHERE id = ? AND deleted_at IS NULL;
 
 -- name: ClaimTask :execrows
-UPDATE task SET status = 'running', started_at = unixepoch(), updated_at = unixepoch()
-WHERE id = ? AND status = 'pending' AND ready = 1;
+UPDATE task SET status = 'running', started_at = unixepoch(), not_before = NULL, updated_at = unixepoch()
+WHERE id = ? AND status = 'pending' AND ready = 1 AND (not_before IS NULL OR not_before <= unixepoch()) AND deleted_at IS NULL;
 
 -- name: HasTasksForRepo :one
-SELECT EXISTS(SELECT 1 FROM task WHERE repo_id = ?);
+SELECT EXISTS(SELECT 1 FROM task WHERE repo_id = ? AND deleted_at IS NULL);
 
 -- name: RetryTask :execrows
 UPDATE task SET status = 'pending', attempt = attempt + 1, retry_reason = ?, started_at = NULL, updated_at = unixepoch()
@@ -61,6 +68,15 @@ UPDATE task SET agent_status = ?, updated_at = unixepoch() WHERE id = ?;
 -- name: SetRetryContext :exec
 UPDATE task SET retry_context = ?, updated_at = unixepoch() WHERE id = ?;
 
+-- name: SetTaskActiveModel :exec
+UPDATE task SET active_model = ?, updated_at = unixepoch() WHERE id = ?;
+
+-- name: SetTaskLastReviewID :exec
+UPDATE task SET last_review_id = ?, updated_at = unixepoch() WHERE id = ?;
+
+-- name: SetTaskPlanOnly :exec
+UPDATE task SET plan_only = ?, updated_at = unixepoch() WHERE id = ?;
+
 -- name: AddTaskCost :exec
 UPDATE task SET cost_usd = cost_usd + ?, updated_at = unixepoch() WHERE id = ?;
 
@@ -70,16 +86,19 @@ UPDATE task SET consecutive_failures = ?, updated_at = unixepoch() WHERE id = ?;
 -- name: SetCloseReason :exec
 UPDATE task SET close_reason = ?, updated_at = unixepoch() WHERE id = ?;
 
+-- name: SetFailureCategory :exec
+UPDATE task SET failure_category = ?, updated_at = unixepoch() WHERE id = ?;
+
 -- name: SetBranchName :exec
 UPDATE task SET branch_name = ?, status = 'review', updated_at = unixepoch() WHERE id = ?;
 
 -- name: ListTasksInReviewNoPR :many
-SELECT * FROM task WHERE status = 'review' AND branch_name IS NOT NULL AND pr_number IS NULL;
+SELECT * FROM task WHERE status = 'review' AND branch_name IS NOT NULL AND pr_number IS NULL AND deleted_at IS NULL;
 
 -- name: ManualRetryTask :execrows
 UPDATE task SET status = 'pending', attempt = attempt + 1,
   retry_reason = ?, retry_context = NULL,
-  close_reason = NULL, consecutive_failures = 0,
+  close_reason = NULL, failure_category = NULL, consecutive_failures = 0,
   started_at = NULL, updated_at = unixepoch()
 WHERE id = ? AND status = 'failed';
 
@@ -107,42 +126,76 @@ WHERE id = ?;
 
 -- name: UpdatePendingTask :execrows
 UPDATE task SET
-  title = ?,
-  description = ?,
-  depends_on = ?,
-  acceptance_criteria_list = ?,
-  max_cost_usd = ?,
-  skip_pr = ?,
-  draft_pr = ?,
-  model = ?,
-  ready = ?,
+  title = sqlc.arg(title),
+  description = sqlc.arg(description),
+  depends_on = sqlc.arg(depends_on),
+  acceptance_criteria_list = sqlc.arg(acceptance_criteria_list),
+  max_cost_usd = sqlc.narg(max_cost_usd),
+  max_runtime_seconds = sqlc.arg(max_runtime_seconds),
+  required_labels = sqlc.arg(required_labels),
+  skip_pr = sqlc.arg(skip_pr),
+  draft_pr = sqlc.arg(draft_pr),
+  model = sqlc.narg(model),
+  ready = sqlc.arg(ready),
   updated_at = unixepoch()
-WHERE id = ? AND status = 'pending';
+WHERE id = sqlc.arg(id) AND status = 'pending'
+  AND (CAST(sqlc.arg(expected_version) AS INTEGER) = 0 OR version = CAST(sqlc.arg(expected_version) AS INTEGER));
 
 -- name: ScheduleRetryFromRunning :execrows
-UPDATE task SET status = 'pending', attempt = attempt + 1, retry_reason = ?, started_at = NULL, updated_at = unixepoch()
+UPDATE task SET status = 'pending', attempt = attempt + 1, retry_reason = ?, not_before = ?, started_at = NULL, updated_at = unixepoch()
 WHERE id = ? AND status = 'running';
 
 -- name: StartOverTask :execrows
 UPDATE task SET
   status = 'pending',
-  title = ?,
-  description = ?,
-  acceptance_criteria_list = ?,
+  title = sqlc.arg(title),
+  description = sqlc.arg(description),
+  acceptance_criteria_list = sqlc.arg(acceptance_criteria_list),
   attempt = 1,
-  max_attempts = 5,
+  max_attempts = sqlc.arg(max_attempts),
   retry_reason = NULL,
   retry_context = NULL,
   close_reason = NULL,
+  failure_category = NULL,
   agent_status = NULL,
   consecutive_failures = 0,
   cost_usd = 0,
   pull_request_url = NULL,
   pr_number = NULL,
   branch_name = NULL,
+  pull_requests = '[]',
   started_at = NULL,
+  active_model = '',
+  archived_at = NULL,
+  updated_at = unixepoch()
+WHERE id = sqlc.arg(id) AND status IN ('review', 'failed', 'closed')
+  AND (CAST(sqlc.arg(expected_version) AS INTEGER) = 0 OR version = CAST(sqlc.arg(expected_version) AS INTEGER));
+
+-- name: MoveTask :execrows
+UPDATE task SET
+  repo_id = sqlc.arg(repo_id),
+  number = CASE WHEN number IS NULL THEN NULL ELSE (SELECT COALESCE(MAX(t2.number), 0) + 1 FROM task t2 WHERE t2.repo_id = sqlc.arg(repo_id)) END,
+  depends_on = sqlc.arg(depends_on),
+  additional_repo_ids = sqlc.arg(additional_repo_ids),
+  max_attempts = sqlc.arg(max_attempts),
+  epic_id = NULL,
+  stack_parent_id = NULL,
+  pull_request_url = NULL,
+  pr_number = NULL,
+  branch_name = NULL,
+  pull_requests = '[]',
   updated_at = unixepoch()
-WHERE id = ? AND status IN ('review', 'failed', 'closed');
+WHERE id = sqlc.arg(id) AND status = 'pending'
+  AND (CAST(sqlc.arg(expected_version) AS INTEGER) = 0 OR version = CAST(sqlc.arg(expected_version) AS INTEGER));
+
+-- name: AbandonTask :execrows
+UPDATE task SET status = 'pending', started_at = NULL, updated_at = unixepoch()
+WHERE id = ? AND status = 'running';
+
+-- name: AbandonStaleTask :execrows
+UPDATE task SET status = 'pending', started_at = NULL, updated_at = unixepoch()
+WHERE id = sqlc.arg(id) AND status = 'running'
+  AND COALESCE(last_heartbeat_at, started_at) < CAST(sqlc.arg(before) AS INTEGER);
 
 -- name: StopTask :execrows
 UPDATE task SET status = 'pending', ready = 0, close_reason = ?,
@@ -150,13 +203,26 @@ UPDATE task SET status = 'pending', ready = 0, close_reason = ?,
 WHERE id = ? AND status = 'running';
 
 -- name: Heartbeat :execrows
-UPDATE task SET last_heartbeat_at = unixepoch() WHERE id = ? AND status = 'running';
+UPDATE task SET last_heartbeat_at = unixepoch() WHERE id = ? AND status = 'running' AND deleted_at IS NULL;
+
+-- name: ListRepoRequiredLabels :many
+SELECT id, required_labels FROM repo WHERE required_labels != '[]';
+
+-- name: ListPausedRepoIDs :many
+SELECT id FROM repo WHERE paused = 1;
 
 -- name: ListStaleTasks :many
-SELECT * FROM task WHERE status = 'running' AND last_heartbeat_at IS NOT NULL AND last_heartbeat_at < ? ORDER BY started_at;
+SELECT * FROM task WHERE status = 'running' AND last_heartbeat_at IS NOT NULL AND last_heartbeat_at < ? AND deleted_at IS NULL ORDER BY started_at;
+
+-- name: ListOverrunTasks :many
+SELECT task.* FROM task JOIN repo ON repo.id = task.repo_id
+WHERE task.status = 'running' AND task.started_at IS NOT NULL AND task.deleted_at IS NULL
+  AND COALESCE(NULLIF(task.max_runtime_seconds, 0), repo.max_runtime_seconds) > 0
+  AND task.started_at + COALESCE(NULLIF(task.max_runtime_seconds, 0), repo.max_runtime_seconds) < CAST(sqlc.arg(now) AS INTEGER)
+ORDER BY task.started_at;
 
 -- name: ListTasksByEpic :many
-SELECT * FROM task WHERE epic_id = ? ORDER BY created_at ASC;
+SELECT * FROM task WHERE epic_id = ? AND deleted_at IS NULL ORDER BY created_at ASC;
 
 -- name: BulkCloseTasksByEpic :exec
 UPDATE task SET status = 'closed', close_reason = ?, updated_at = unixepoch()
@@ -176,7 +242,41 @@ DELETE FROM task WHERE epic_id = ?;
 UPDATE task SET number = (SELECT COALESCE(MAX(t2.number), 0) + 1 FROM task t2 WHERE t2.repo_id = sqlc.arg(repo_id)) WHERE task.id = sqlc.arg(id) RETURNING number;
 
 -- name: ReadTaskByNumber :one
-SELECT * FROM task WHERE repo_id = ? AND number = ?;
+SELECT * FROM task WHERE repo_id = ? AND number = ? AND deleted_at IS NULL;
+
+-- name: TrashTask :execrows
+UPDATE task SET deleted_at = unixepoch(), epic_id = NULL, updated_at = unixepoch()
+WHERE id = ? AND deleted_at IS NULL;
+
+-- name: RestoreTask :execrows
+UPDATE task SET deleted_at = NULL, updated_at = unixepoch()
+WHERE id = ? AND deleted_at IS NOT NULL;
+
+-- name: ListTrashedTasksByRepo :many
+SELECT * FROM task WHERE repo_id = ? AND deleted_at IS NOT NULL ORDER BY deleted_at DESC;
+
+-- name: ListTrashedTaskIDsBefore :many
+SELECT id FROM task WHERE deleted_at IS NOT NULL AND deleted_at < CAST(sqlc.arg(before) AS INTEGER);
+
+-- name: ArchiveTask :execrows
+UPDATE task SET archived_at = unixepoch()
+WHERE id = ? AND status IN ('merged', 'closed') AND archived_at IS NULL AND deleted_at IS NULL;
+
+-- name: UnarchiveTask :execrows
+UPDATE task SET archived_at = NULL
+WHERE id = ? AND archived_at IS NOT NULL;
+
+-- name: ListArchivedTasksByRepo :many
+SELECT * FROM task WHERE repo_id = ? AND type = 'task' AND archived_at IS NOT NULL AND deleted_at IS NULL ORDER BY created_at DESC;
+
+-- name: ListArchivableTaskIDs :many
+SELECT id FROM task
+WHERE type = 'task' AND status IN ('merged', 'closed') AND archived_at IS NULL AND deleted_at IS NULL
+  AND updated_at < CAST(sqlc.arg(before) AS INTEGER)
+ORDER BY updated_at
+LIMIT sqlc.arg(max_tasks);
 
 -- name: DeleteExpiredLogs :execrows
-DELETE FROM task_log WHERE created_at < ?;
+DELETE FROM task_log WHERE id IN (
+  SELECT id FROM task_log WHERE created_at < ? ORDER BY id LIMIT ?
+);
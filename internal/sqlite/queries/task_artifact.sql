@@ -0,0 +1,24 @@
+-- name: CreateTaskArtifact :one
+INSERT INTO task_artifact (task_id, attempt, name, content_type, size_bytes, storage_key) VALUES (?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: ListTaskArtifacts :many
+SELECT * FROM task_artifact WHERE task_id = ? ORDER BY attempt, id;
+
+-- name: ReadTaskArtifact :one
+SELECT * FROM task_artifact WHERE id = ? AND task_id = ?;
+
+-- name: CountTaskArtifacts :one
+SELECT COUNT(*) FROM task_artifact WHERE task_id = ? AND attempt = ?;
+
+-- name: ListExpiredTaskArtifactKeys :many
+SELECT storage_key FROM task_artifact WHERE created_at < CAST(sqlc.arg(before) AS INTEGER);
+
+-- name: DeleteExpiredTaskArtifacts :execrows
+DELETE FROM task_artifact WHERE created_at < CAST(sqlc.arg(before) AS INTEGER);
+
+-- name: DeleteTaskArtifacts :exec
+DELETE FROM task_artifact WHERE task_id = ?;
+
+-- name: BulkDeleteTaskArtifactsByEpic :exec
+DELETE FROM task_artifact WHERE task_id IN (SELECT id FROM task WHERE epic_id = ?);
@@ -0,0 +1,21 @@
+-- name: CreateTaskAttachment :one
+INSERT INTO task_attachment (task_id, filename, content_type, size_bytes, storage_key, uploaded_by) VALUES (?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: ListTaskAttachments :many
+SELECT * FROM task_attachment WHERE task_id = ? ORDER BY id;
+
+-- name: ReadTaskAttachment :one
+SELECT * FROM task_attachment WHERE id = ? AND task_id = ?;
+
+-- name: DeleteTaskAttachment :execrows
+DELETE FROM task_attachment WHERE id = ? AND task_id = ?;
+
+-- name: CountTaskAttachments :one
+SELECT COUNT(*) FROM task_attachment WHERE task_id = ?;
+
+-- name: DeleteTaskAttachments :exec
+DELETE FROM task_attachment WHERE task_id = ?;
+
+-- name: BulkDeleteTaskAttachmentsByEpic :exec
+DELETE FROM task_attachment WHERE task_id IN (SELECT id FROM task WHERE epic_id = ?);
@@ -0,0 +1,21 @@
+-- name: StartTaskAttempt :exec
+INSERT INTO task_attempt (task_id, attempt, retry_reason, model, started_at)
+VALUES (?, ?, ?, ?, unixepoch())
+ON CONFLICT (task_id, attempt) DO UPDATE SET result = '', ended_at = NULL, model = excluded.model;
+
+-- name: EndTaskAttempt :exec
+UPDATE task_attempt SET result = ?, ended_at = unixepoch()
+WHERE task_id = ? AND ended_at IS NULL;
+
+-- name: AddTaskAttemptCost :exec
+UPDATE task_attempt SET cost_usd = cost_usd + ?
+WHERE rowid = (SELECT a.rowid FROM task_attempt a WHERE a.task_id = ? ORDER BY a.attempt DESC LIMIT 1);
+
+-- name: ListTaskAttempts :many
+SELECT * FROM task_attempt WHERE task_id = ? ORDER BY attempt;
+
+-- name: DeleteTaskAttempts :exec
+DELETE FROM task_attempt WHERE task_id = ?;
+
+-- name: BulkDeleteTaskAttemptsByEpic :exec
+DELETE FROM task_attempt WHERE task_id IN (SELECT id FROM task WHERE epic_id = ?);
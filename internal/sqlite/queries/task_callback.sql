@@ -0,0 +1,12 @@
+-- name: RecordTaskCallback :execrows
+INSERT INTO task_callback (task_id, attempt, idempotency_key) VALUES (?, ?, ?)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteTaskCallback :exec
+DELETE FROM task_callback WHERE task_id = ? AND attempt = ? AND idempotency_key = ?;
+
+-- name: DeleteTaskCallbacks :exec
+DELETE FROM task_callback WHERE task_id = ?;
+
+-- name: DeleteTaskCallbacksBefore :execrows
+DELETE FROM task_callback WHERE created_at < ?;
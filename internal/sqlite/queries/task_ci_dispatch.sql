@@ -0,0 +1,12 @@
+-- name: UpsertTaskCIDispatch :exec
+INSERT INTO task_ci_dispatch (task_id, workflow_file, ref, dispatched_at) VALUES (?, ?, ?, ?)
+ON CONFLICT (task_id) DO UPDATE SET workflow_file = excluded.workflow_file, ref = excluded.ref, dispatched_at = excluded.dispatched_at;
+
+-- name: ReadTaskCIDispatch :one
+SELECT * FROM task_ci_dispatch WHERE task_id = ?;
+
+-- name: DeleteTaskCIDispatch :exec
+DELETE FROM task_ci_dispatch WHERE task_id = ?;
+
+-- name: BulkDeleteTaskCIDispatchesByEpic :exec
+DELETE FROM task_ci_dispatch WHERE task_id IN (SELECT id FROM task WHERE epic_id = ?);
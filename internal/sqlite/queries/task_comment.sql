@@ -0,0 +1,31 @@
+-- name: CreateTaskComment :one
+INSERT INTO task_comment (task_id, attempt, author, author_id, body) VALUES (?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: ListTaskComments :many
+SELECT * FROM task_comment WHERE task_id = ? ORDER BY id;
+
+-- name: ReadTaskComment :one
+SELECT * FROM task_comment WHERE id = ? AND task_id = ?;
+
+-- name: UpdateTaskComment :one
+UPDATE task_comment SET body = ?, updated_at = unixepoch() WHERE id = ? AND task_id = ?
+RETURNING *;
+
+-- name: DeleteTaskComment :execrows
+DELETE FROM task_comment WHERE id = ? AND task_id = ?;
+
+-- name: CountTaskComments :one
+SELECT COUNT(*) FROM task_comment WHERE task_id = ?;
+
+-- name: CountTaskCommentsByRepo :many
+SELECT task_comment.task_id, COUNT(*) AS comment_count FROM task_comment
+JOIN task ON task.id = task_comment.task_id
+WHERE task.repo_id = ?
+GROUP BY task_comment.task_id;
+
+-- name: DeleteTaskComments :exec
+DELETE FROM task_comment WHERE task_id = ?;
+
+-- name: BulkDeleteTaskCommentsByEpic :exec
+DELETE FROM task_comment WHERE task_id IN (SELECT id FROM task WHERE epic_id = ?);
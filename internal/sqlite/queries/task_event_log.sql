@@ -0,0 +1,12 @@
+-- name: AppendTaskEvent :one
+INSERT INTO task_event_log (task_id, attempt, type, payload) VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: ListTaskEvents :many
+SELECT * FROM task_event_log WHERE task_id = ? ORDER BY id;
+
+-- name: DeleteTaskEvents :exec
+DELETE FROM task_event_log WHERE task_id = ?;
+
+-- name: BulkDeleteTaskEventsByEpic :exec
+DELETE FROM task_event_log WHERE task_id IN (SELECT id FROM task WHERE epic_id = ?);
@@ -0,0 +1,11 @@
+-- name: CreateTaskLogArchive :exec
+INSERT INTO task_log_archive (task_id, batches) VALUES (?, ?);
+
+-- name: ReadTaskLogArchive :one
+SELECT batches FROM task_log_archive WHERE task_id = ?;
+
+-- name: DeleteTaskLogArchive :exec
+DELETE FROM task_log_archive WHERE task_id = ?;
+
+-- name: BulkDeleteTaskLogArchivesByEpic :exec
+DELETE FROM task_log_archive WHERE task_id IN (SELECT id FROM task WHERE epic_id = ?);
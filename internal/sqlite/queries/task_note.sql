@@ -0,0 +1,18 @@
+-- name: CreateTaskNote :one
+INSERT INTO task_note (task_id, repo_id, attempt, text) VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: ListRepoTaskNotes :many
+SELECT * FROM task_note WHERE repo_id = ? ORDER BY id DESC;
+
+-- name: ReadTaskNote :one
+SELECT * FROM task_note WHERE id = ?;
+
+-- name: SetTaskNoteConvertedTask :execrows
+UPDATE task_note SET converted_task_id = ? WHERE id = ? AND converted_task_id IS NULL;
+
+-- name: DeleteTaskNotes :exec
+DELETE FROM task_note WHERE task_id = ?;
+
+-- name: BulkDeleteTaskNotesByEpic :exec
+DELETE FROM task_note WHERE task_id IN (SELECT id FROM task WHERE epic_id = ?);
@@ -0,0 +1,16 @@
+-- name: CreateTaskNudge :one
+INSERT INTO task_nudge (task_id, attempt, message) VALUES (?, ?, ?)
+RETURNING *;
+
+-- name: ListTaskNudges :many
+SELECT * FROM task_nudge WHERE task_id = ? ORDER BY id;
+
+-- name: DeliverTaskNudges :many
+UPDATE task_nudge SET delivered_at = ? WHERE task_id = ? AND delivered_at IS NULL
+RETURNING *;
+
+-- name: DeleteTaskNudges :exec
+DELETE FROM task_nudge WHERE task_id = ?;
+
+-- name: BulkDeleteTaskNudgesByEpic :exec
+DELETE FROM task_nudge WHERE task_id IN (SELECT id FROM task WHERE epic_id = ?);
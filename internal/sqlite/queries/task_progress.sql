@@ -0,0 +1,12 @@
+-- name: UpsertTaskProgress :exec
+INSERT INTO task_progress (task_id, attempt, steps, updated_at) VALUES (?, ?, ?, ?)
+ON CONFLICT (task_id) DO UPDATE SET attempt = excluded.attempt, steps = excluded.steps, updated_at = excluded.updated_at;
+
+-- name: ReadTaskProgress :one
+SELECT * FROM task_progress WHERE task_id = ?;
+
+-- name: DeleteTaskProgress :exec
+DELETE FROM task_progress WHERE task_id = ?;
+
+-- name: BulkDeleteTaskProgressByEpic :exec
+DELETE FROM task_progress WHERE task_id IN (SELECT id FROM task WHERE epic_id = ?);
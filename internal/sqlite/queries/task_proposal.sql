@@ -0,0 +1,15 @@
+-- name: UpsertTaskProposal :exec
+INSERT INTO task_proposal (task_id, attempt, summary, approach, files, risks, created_at, approved_at) VALUES (?, ?, ?, ?, ?, ?, ?, NULL)
+ON CONFLICT (task_id) DO UPDATE SET attempt = excluded.attempt, summary = excluded.summary, approach = excluded.approach, files = excluded.files, risks = excluded.risks, created_at = excluded.created_at, approved_at = NULL;
+
+-- name: ReadTaskProposal :one
+SELECT * FROM task_proposal WHERE task_id = ?;
+
+-- name: ApproveTaskProposal :execrows
+UPDATE task_proposal SET approved_at = ? WHERE task_id = ? AND approved_at IS NULL;
+
+-- name: DeleteTaskProposal :exec
+DELETE FROM task_proposal WHERE task_id = ?;
+
+-- name: BulkDeleteTaskProposalsByEpic :exec
+DELETE FROM task_proposal WHERE task_id IN (SELECT id FROM task WHERE epic_id = ?);
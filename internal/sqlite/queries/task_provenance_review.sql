@@ -0,0 +1,12 @@
+-- name: UpsertTaskProvenanceReview :exec
+INSERT INTO task_provenance_review (task_id, findings, head_sha, scanned_at, acknowledged_at) VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (task_id) DO UPDATE SET findings = excluded.findings, head_sha = excluded.head_sha, scanned_at = excluded.scanned_at, acknowledged_at = excluded.acknowledged_at;
+
+-- name: ReadTaskProvenanceReview :one
+SELECT * FROM task_provenance_review WHERE task_id = ?;
+
+-- name: DeleteTaskProvenanceReview :exec
+DELETE FROM task_provenance_review WHERE task_id = ?;
+
+-- name: BulkDeleteTaskProvenanceReviewsByEpic :exec
+DELETE FROM task_provenance_review WHERE task_id IN (SELECT id FROM task WHERE epic_id = ?);
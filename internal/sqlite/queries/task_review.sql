@@ -0,0 +1,23 @@
+-- name: ListOverdueReviews :many
+SELECT r.id, r.task_id, r.started_at, repo.review_sla_seconds
+FROM task_review r
+JOIN task t ON t.id = r.task_id
+JOIN repo ON repo.id = t.repo_id
+WHERE r.ended_at IS NULL AND r.overdue_at IS NULL AND t.deleted_at IS NULL
+  AND repo.review_sla_seconds > 0
+  AND r.started_at + repo.review_sla_seconds <= CAST(sqlc.arg(now) AS INTEGER)
+ORDER BY r.started_at;
+
+-- name: MarkReviewOverdue :execrows
+UPDATE task_review SET overdue_at = ?
+WHERE id = ? AND ended_at IS NULL AND overdue_at IS NULL;
+
+-- name: ListReviewSpans :many
+SELECT r.task_id, t.number AS task_number, t.repo_id, t.title AS task_title, r.started_at, r.ended_at, repo.review_sla_seconds
+FROM task_review r
+JOIN task t ON t.id = r.task_id
+JOIN repo ON repo.id = t.repo_id
+WHERE t.deleted_at IS NULL
+  AND (CAST(sqlc.arg(repo_id) AS TEXT) = '' OR t.repo_id = CAST(sqlc.arg(repo_id) AS TEXT))
+  AND (r.ended_at IS NULL OR r.ended_at >= CAST(sqlc.arg(since) AS INTEGER))
+ORDER BY r.started_at;
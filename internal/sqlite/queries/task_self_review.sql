@@ -0,0 +1,12 @@
+-- name: UpsertTaskSelfReview :exec
+INSERT INTO task_self_review (task_id, score, checks, head_sha, comment_id, reviewed_at) VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (task_id) DO UPDATE SET score = excluded.score, checks = excluded.checks, head_sha = excluded.head_sha, comment_id = excluded.comment_id, reviewed_at = excluded.reviewed_at;
+
+-- name: ReadTaskSelfReview :one
+SELECT * FROM task_self_review WHERE task_id = ?;
+
+-- name: DeleteTaskSelfReview :exec
+DELETE FROM task_self_review WHERE task_id = ?;
+
+-- name: BulkDeleteTaskSelfReviewsByEpic :exec
+DELETE FROM task_self_review WHERE task_id IN (SELECT id FROM task WHERE epic_id = ?);
@@ -0,0 +1,12 @@
+-- name: UpsertTaskShadowDiff :exec
+INSERT INTO task_shadow_diff (task_id, attempt, diff, created_at) VALUES (?, ?, ?, ?)
+ON CONFLICT (task_id) DO UPDATE SET attempt = excluded.attempt, diff = excluded.diff, created_at = excluded.created_at;
+
+-- name: ReadTaskShadowDiff :one
+SELECT * FROM task_shadow_diff WHERE task_id = ?;
+
+-- name: DeleteTaskShadowDiff :exec
+DELETE FROM task_shadow_diff WHERE task_id = ?;
+
+-- name: BulkDeleteTaskShadowDiffsByEpic :exec
+DELETE FROM task_shadow_diff WHERE task_id IN (SELECT id FROM task WHERE epic_id = ?);
@@ -0,0 +1,19 @@
+-- name: CreateTeam :exec
+INSERT INTO team (id, name, default_model, default_max_cost_usd, created_at)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: ReadTeam :one
+SELECT * FROM team WHERE id = ?;
+
+-- name: ListTeams :many
+SELECT * FROM team ORDER BY name ASC;
+
+-- name: UpdateTeam :exec
+UPDATE team
+SET name = ?,
+    default_model = ?,
+    default_max_cost_usd = ?
+WHERE id = ?;
+
+-- name: DeleteTeam :exec
+DELETE FROM team WHERE id = ?;
@@ -0,0 +1,15 @@
+-- name: ListTaskCreationsSince :many
+SELECT repo_id, created_at FROM task
+WHERE type = 'task' AND deleted_at IS NULL AND created_at >= CAST(sqlc.arg(since) AS INTEGER)
+ORDER BY created_at;
+
+-- name: ListTaskTransitionsSince :many
+SELECT a.entity_id AS task_id, t.repo_id, t.created_at AS task_created_at, a.to_status, a.created_at
+FROM audit_log a
+JOIN task t ON t.id = a.entity_id
+WHERE a.entity_type = 'task' AND a.to_status <> '' AND t.deleted_at IS NULL
+  AND a.entity_id IN (
+    SELECT entity_id FROM audit_log
+    WHERE entity_type = 'task' AND created_at >= CAST(sqlc.arg(since) AS INTEGER)
+  )
+ORDER BY a.entity_id, a.created_at, a.id;
@@ -0,0 +1,34 @@
+-- name: CreateUser :exec
+INSERT INTO user (id, name, role, token_hash, created_at)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: ReadUser :one
+SELECT * FROM user WHERE id = ?;
+
+-- name: ReadUserByTokenHash :one
+SELECT * FROM user WHERE token_hash = ?;
+
+-- name: ListUsers :many
+SELECT * FROM user ORDER BY name ASC;
+
+-- name: UpdateUserRole :execrows
+UPDATE user SET role = ? WHERE id = ?;
+
+-- name: UpdateUserTokenHash :execrows
+UPDATE user SET token_hash = ? WHERE id = ?;
+
+-- name: DeleteUser :exec
+DELETE FROM user WHERE id = ?;
+
+-- name: CreateUserSession :exec
+INSERT INTO user_session (id, subject, name, role, token_hash, expires_at, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: ReadUserSessionByTokenHash :one
+SELECT * FROM user_session WHERE token_hash = ?;
+
+-- name: DeleteUserSessionByTokenHash :exec
+DELETE FROM user_session WHERE token_hash = ?;
+
+-- name: DeleteExpiredUserSessions :exec
+DELETE FROM user_session WHERE expires_at <= ?;
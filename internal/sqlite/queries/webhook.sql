@@ -0,0 +1,25 @@
+-- name: CreateWebhookSubscription :exec
+INSERT INTO webhook_subscription (id, url, secret, event_types, repo_id, created_at)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: ReadWebhookSubscription :one
+SELECT * FROM webhook_subscription WHERE id = ?;
+
+-- name: ListWebhookSubscriptions :many
+SELECT * FROM webhook_subscription ORDER BY created_at ASC;
+
+-- name: DeleteWebhookSubscription :exec
+DELETE FROM webhook_subscription WHERE id = ?;
+
+-- name: CreateWebhookDelivery :exec
+INSERT INTO webhook_delivery (id, subscription_id, event_id, event_type, attempt, status_code, error, response_body, duration_ms, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_delivery WHERE subscription_id = ? ORDER BY id DESC LIMIT ?;
+
+-- name: PruneWebhookDeliveries :exec
+DELETE FROM webhook_delivery
+WHERE subscription_id = sqlc.arg(subscription_id) AND id NOT IN (
+  SELECT d.id FROM webhook_delivery d WHERE d.subscription_id = sqlc.arg(subscription_id) ORDER BY d.id DESC LIMIT sqlc.arg(keep)
+);
@@ -3,12 +3,15 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 
 	"github.com/joshjon/kit/errtag"
 
+	"github.com/vervesh/verve/internal/prlint"
 	"github.com/vervesh/verve/internal/repo"
 	"github.com/vervesh/verve/internal/sqlite/sqlc"
+	"github.com/vervesh/verve/internal/task"
 )
 
 var _ repo.Repository = (*RepoRepository)(nil)
@@ -111,6 +114,108 @@ func (r *RepoRepository) UpdateRepoTechStack(ctx context.Context, id repo.RepoID
 	}))
 }
 
+func (r *RepoRepository) UpdateRepoCIWorkflow(ctx context.Context, id repo.RepoID, workflow *repo.CIWorkflow) error {
+	return tagRepoErr(r.db.UpdateRepoCIWorkflow(ctx, sqlc.UpdateRepoCIWorkflowParams{
+		CiWorkflow: marshalCIWorkflow(workflow),
+		ID:         id.String(),
+	}))
+}
+
+func (r *RepoRepository) UpdateRepoProtectedPaths(ctx context.Context, id repo.RepoID, patterns []string) error {
+	return tagRepoErr(r.db.UpdateRepoProtectedPaths(ctx, sqlc.UpdateRepoProtectedPathsParams{
+		ProtectedPaths: marshalJSONStrings(patterns),
+		ID:             id.String(),
+	}))
+}
+
+func (r *RepoRepository) UpdateRepoShadowMode(ctx context.Context, id repo.RepoID, enabled bool) error {
+	return tagRepoErr(r.db.UpdateRepoShadowMode(ctx, sqlc.UpdateRepoShadowModeParams{
+		ShadowMode: boolToInt64(enabled),
+		ID:         id.String(),
+	}))
+}
+
+func (r *RepoRepository) UpdateRepoCompletionValidations(ctx context.Context, id repo.RepoID, cfg *prlint.Config) error {
+	return tagRepoErr(r.db.UpdateRepoCompletionValidations(ctx, sqlc.UpdateRepoCompletionValidationsParams{
+		CompletionValidations: marshalCompletionValidations(cfg),
+		ID:                    id.String(),
+	}))
+}
+
+func (r *RepoRepository) UpdateRepoRetryPolicy(ctx context.Context, id repo.RepoID, policy *task.RetryPolicy) error {
+	return tagRepoErr(r.db.UpdateRepoRetryPolicy(ctx, sqlc.UpdateRepoRetryPolicyParams{
+		RetryPolicy: marshalRetryPolicy(policy),
+		ID:          id.String(),
+	}))
+}
+
+func (r *RepoRepository) UpdateRepoMaxRuntime(ctx context.Context, id repo.RepoID, seconds int) error {
+	return tagRepoErr(r.db.UpdateRepoMaxRuntime(ctx, sqlc.UpdateRepoMaxRuntimeParams{
+		MaxRuntimeSeconds: int64(seconds),
+		ID:                id.String(),
+	}))
+}
+
+func (r *RepoRepository) UpdateRepoRequiredLabels(ctx context.Context, id repo.RepoID, labels []string) error {
+	return tagRepoErr(r.db.UpdateRepoRequiredLabels(ctx, sqlc.UpdateRepoRequiredLabelsParams{
+		RequiredLabels: marshalJSONStrings(labels),
+		ID:             id.String(),
+	}))
+}
+
+func (r *RepoRepository) UpdateRepoIgnoredChecks(ctx context.Context, id repo.RepoID, names []string) error {
+	return tagRepoErr(r.db.UpdateRepoIgnoredChecks(ctx, sqlc.UpdateRepoIgnoredChecksParams{
+		IgnoredChecks: marshalJSONStrings(names),
+		ID:            id.String(),
+	}))
+}
+
+func (r *RepoRepository) UpdateRepoCheckGating(ctx context.Context, id repo.RepoID, gating *repo.CheckGating) error {
+	return tagRepoErr(r.db.UpdateRepoCheckGating(ctx, sqlc.UpdateRepoCheckGatingParams{
+		CheckGating: marshalCheckGating(gating),
+		ID:          id.String(),
+	}))
+}
+
+func (r *RepoRepository) UpdateRepoFallbackModels(ctx context.Context, id repo.RepoID, models []string) error {
+	return tagRepoErr(r.db.UpdateRepoFallbackModels(ctx, sqlc.UpdateRepoFallbackModelsParams{
+		FallbackModels: marshalJSONStrings(models),
+		ID:             id.String(),
+	}))
+}
+
+func (r *RepoRepository) UpdateRepoPaused(ctx context.Context, id repo.RepoID, paused bool) error {
+	return tagRepoErr(r.db.UpdateRepoPaused(ctx, sqlc.UpdateRepoPausedParams{
+		Paused: boolToInt64(paused),
+		ID:     id.String(),
+	}))
+}
+
+func (r *RepoRepository) UpdateRepoSyncDisabled(ctx context.Context, id repo.RepoID, disabled bool) error {
+	return tagRepoErr(r.db.UpdateRepoSyncDisabled(ctx, sqlc.UpdateRepoSyncDisabledParams{
+		SyncDisabled: boolToInt64(disabled),
+		ID:           id.String(),
+	}))
+}
+
+func (r *RepoRepository) UpdateRepoSyncInterval(ctx context.Context, id repo.RepoID, seconds int) error {
+	return tagRepoErr(r.db.UpdateRepoSyncInterval(ctx, sqlc.UpdateRepoSyncIntervalParams{
+		SyncIntervalSeconds: int64(seconds),
+		ID:                  id.String(),
+	}))
+}
+
+func (r *RepoRepository) UpdateRepoReviewSLA(ctx context.Context, id repo.RepoID, seconds int) error {
+	return tagRepoErr(r.db.UpdateRepoReviewSLA(ctx, sqlc.UpdateRepoReviewSLAParams{
+		ReviewSlaSeconds: int64(seconds),
+		ID:               id.String(),
+	}))
+}
+
+func (r *RepoRepository) IncrementRepoWorkspaceCacheGeneration(ctx context.Context, id repo.RepoID) error {
+	return tagRepoErr(r.db.IncrementRepoWorkspaceCacheGeneration(ctx, id.String()))
+}
+
 func (r *RepoRepository) ListReposBySetupStatus(ctx context.Context, status string) ([]*repo.Repo, error) {
 	rows, err := r.db.ListReposBySetupStatus(ctx, status)
 	if err != nil {
@@ -123,25 +228,142 @@ func (r *RepoRepository) ListReposBySetupStatus(ctx context.Context, status stri
 	return out, nil
 }
 
+func (r *RepoRepository) UpdateRepoTeam(ctx context.Context, id repo.RepoID, teamID string) error {
+	var team *string
+	if teamID != "" {
+		team = ptr(teamID)
+	}
+	return tagRepoErr(r.db.UpdateRepoTeam(ctx, sqlc.UpdateRepoTeamParams{
+		TeamID: team,
+		ID:     id.String(),
+	}))
+}
+
+func (r *RepoRepository) ListReposByTeam(ctx context.Context, teamID string) ([]*repo.Repo, error) {
+	rows, err := r.db.ListReposByTeam(ctx, &teamID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*repo.Repo, len(rows))
+	for i, row := range rows {
+		out[i] = unmarshalRepo(row)
+	}
+	return out, nil
+}
+
 func unmarshalRepo(in *sqlc.Repo) *repo.Repo {
 	rp := &repo.Repo{
-		ID:               repo.MustParseRepoID(in.ID),
-		Owner:            in.Owner,
-		Name:             in.Name,
-		FullName:         in.FullName,
-		Summary:          in.Summary,
-		TechStack:        unmarshalJSONStrings(in.TechStack),
-		SetupStatus:      in.SetupStatus,
-		HasCode:          in.HasCode != 0,
-		HasCLAUDEMD:      in.HasClaudeMd != 0,
-		HasREADME:        in.HasReadme != 0,
-		Expectations:     in.Expectations,
-		SetupCompletedAt: unixPtrToTimePtr(in.SetupCompletedAt),
-		CreatedAt:        unixToTime(in.CreatedAt),
+		ID:                       repo.MustParseRepoID(in.ID),
+		Owner:                    in.Owner,
+		Name:                     in.Name,
+		FullName:                 in.FullName,
+		Summary:                  in.Summary,
+		TechStack:                unmarshalJSONStrings(in.TechStack),
+		SetupStatus:              in.SetupStatus,
+		HasCode:                  in.HasCode != 0,
+		HasCLAUDEMD:              in.HasClaudeMd != 0,
+		HasREADME:                in.HasReadme != 0,
+		Expectations:             in.Expectations,
+		SetupCompletedAt:         unixPtrToTimePtr(in.SetupCompletedAt),
+		CIWorkflow:               unmarshalCIWorkflow(in.CiWorkflow),
+		ProtectedPaths:           unmarshalJSONStrings(in.ProtectedPaths),
+		ShadowMode:               in.ShadowMode != 0,
+		CompletionValidations:    unmarshalCompletionValidations(in.CompletionValidations),
+		RetryPolicy:              unmarshalRetryPolicy(in.RetryPolicy),
+		MaxRuntimeSeconds:        int(in.MaxRuntimeSeconds),
+		RequiredLabels:           unmarshalJSONStrings(in.RequiredLabels),
+		IgnoredChecks:            unmarshalJSONStrings(in.IgnoredChecks),
+		CheckGating:              unmarshalCheckGating(in.CheckGating),
+		FallbackModels:           unmarshalJSONStrings(in.FallbackModels),
+		Paused:                   in.Paused != 0,
+		SyncDisabled:             in.SyncDisabled != 0,
+		SyncIntervalSeconds:      int(in.SyncIntervalSeconds),
+		ReviewSLASeconds:         int(in.ReviewSlaSeconds),
+		WorkspaceCacheGeneration: int(in.WorkspaceCacheGeneration),
+		CreatedAt:                unixToTime(in.CreatedAt),
+	}
+	if in.TeamID != nil {
+		rp.TeamID = *in.TeamID
 	}
 	return rp
 }
 
+func marshalCIWorkflow(w *repo.CIWorkflow) string {
+	if w == nil {
+		return ""
+	}
+	b, _ := json.Marshal(w)
+	return string(b)
+}
+
+func unmarshalCIWorkflow(s string) *repo.CIWorkflow {
+	if s == "" {
+		return nil
+	}
+	var w repo.CIWorkflow
+	if err := json.Unmarshal([]byte(s), &w); err != nil || w.File == "" {
+		return nil
+	}
+	return &w
+}
+
+func marshalCompletionValidations(cfg *prlint.Config) string {
+	if cfg == nil {
+		return ""
+	}
+	b, _ := json.Marshal(cfg)
+	return string(b)
+}
+
+func unmarshalCompletionValidations(s string) *prlint.Config {
+	if s == "" {
+		return nil
+	}
+	var cfg prlint.Config
+	if err := json.Unmarshal([]byte(s), &cfg); err != nil || cfg.Empty() {
+		return nil
+	}
+	return &cfg
+}
+
+func marshalRetryPolicy(policy *task.RetryPolicy) string {
+	if policy == nil {
+		return ""
+	}
+	b, _ := json.Marshal(policy)
+	return string(b)
+}
+
+func unmarshalRetryPolicy(s string) *task.RetryPolicy {
+	if s == "" {
+		return nil
+	}
+	var policy task.RetryPolicy
+	if err := json.Unmarshal([]byte(s), &policy); err != nil || policy.Empty() {
+		return nil
+	}
+	return &policy
+}
+
+func marshalCheckGating(gating *repo.CheckGating) string {
+	if gating == nil {
+		return ""
+	}
+	b, _ := json.Marshal(gating)
+	return string(b)
+}
+
+func unmarshalCheckGating(s string) *repo.CheckGating {
+	if s == "" {
+		return nil
+	}
+	var gating repo.CheckGating
+	if err := json.Unmarshal([]byte(s), &gating); err != nil || !gating.RequiredOnly {
+		return nil
+	}
+	return &gating
+}
+
 func boolToInt64(b bool) int64 {
 	if b {
 		return 1
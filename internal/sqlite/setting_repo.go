@@ -2,6 +2,8 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 
 	"github.com/vervesh/verve/internal/setting"
 	"github.com/vervesh/verve/internal/sqlite/sqlc"
@@ -24,7 +26,11 @@ func (r *SettingRepository) UpsertSetting(ctx context.Context, key, value string
 }
 
 func (r *SettingRepository) ReadSetting(ctx context.Context, key string) (string, error) {
-	return r.db.ReadSetting(ctx, key)
+	value, err := r.db.ReadSetting(ctx, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", setting.ErrNotFound
+	}
+	return value, err
 }
 
 func (r *SettingRepository) DeleteSetting(ctx context.Context, key string) error {
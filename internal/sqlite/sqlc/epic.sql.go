@@ -9,18 +9,33 @@ import (
 	"context"
 )
 
+const addEpicCost = `-- name: AddEpicCost :exec
+UPDATE epic SET cost_usd = cost_usd + ?, updated_at = unixepoch() WHERE id = ?
+`
+
+type AddEpicCostParams struct {
+	CostUsd float64
+	ID      string
+}
+
+func (q *Queries) AddEpicCost(ctx context.Context, arg AddEpicCostParams) error {
+	_, err := q.db.ExecContext(ctx, addEpicCost, arg.CostUsd, arg.ID)
+	return err
+}
+
 const appendSessionLog = `-- name: AppendSessionLog :exec
-UPDATE epic SET session_log = ?, updated_at = unixepoch()
+UPDATE epic SET session_log = ?, transcript = ?, updated_at = unixepoch()
 WHERE id = ?
 `
 
 type AppendSessionLogParams struct {
 	SessionLog string
+	Transcript string
 	ID         string
 }
 
 func (q *Queries) AppendSessionLog(ctx context.Context, arg AppendSessionLogParams) error {
-	_, err := q.db.ExecContext(ctx, appendSessionLog, arg.SessionLog, arg.ID)
+	_, err := q.db.ExecContext(ctx, appendSessionLog, arg.SessionLog, arg.Transcript, arg.ID)
 	return err
 }
 
@@ -70,8 +85,8 @@ func (q *Queries) ClearEpicFeedback(ctx context.Context, id string) error {
 }
 
 const createEpic = `-- name: CreateEpic :exec
-INSERT INTO epic (id, repo_id, title, description, status, proposed_tasks, task_ids, planning_prompt, session_log, not_ready, model, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+INSERT INTO epic (id, repo_id, title, description, status, proposed_tasks, task_ids, planning_prompt, session_log, not_ready, model, max_cost_usd, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type CreateEpicParams struct {
@@ -86,6 +101,7 @@ type CreateEpicParams struct {
 	SessionLog     string
 	NotReady       int64
 	Model          *string
+	MaxCostUsd     *float64
 	CreatedAt      int64
 	UpdatedAt      int64
 }
@@ -103,6 +119,7 @@ func (q *Queries) CreateEpic(ctx context.Context, arg CreateEpicParams) error {
 		arg.SessionLog,
 		arg.NotReady,
 		arg.Model,
+		arg.MaxCostUsd,
 		arg.CreatedAt,
 		arg.UpdatedAt,
 	)
@@ -131,7 +148,7 @@ func (q *Queries) EpicHeartbeat(ctx context.Context, id string) error {
 }
 
 const listActiveEpics = `-- name: ListActiveEpics :many
-SELECT id, repo_id, title, description, status, proposed_tasks, task_ids, planning_prompt, session_log, not_ready, claimed_at, last_heartbeat_at, feedback, feedback_type, model, created_at, updated_at, number FROM epic
+SELECT id, repo_id, title, description, status, proposed_tasks, task_ids, planning_prompt, session_log, not_ready, claimed_at, last_heartbeat_at, feedback, feedback_type, model, created_at, updated_at, number, cost_usd, max_cost_usd, planning_summary, shared_branch, pull_request_url, pr_number, transcript FROM epic
 WHERE status = 'active'
 ORDER BY created_at ASC
 `
@@ -164,6 +181,13 @@ func (q *Queries) ListActiveEpics(ctx context.Context) ([]*Epic, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Number,
+			&i.CostUsd,
+			&i.MaxCostUsd,
+			&i.PlanningSummary,
+			&i.SharedBranch,
+			&i.PullRequestUrl,
+			&i.PrNumber,
+			&i.Transcript,
 		); err != nil {
 			return nil, err
 		}
@@ -179,7 +203,7 @@ func (q *Queries) ListActiveEpics(ctx context.Context) ([]*Epic, error) {
 }
 
 const listEpics = `-- name: ListEpics :many
-SELECT id, repo_id, title, description, status, proposed_tasks, task_ids, planning_prompt, session_log, not_ready, claimed_at, last_heartbeat_at, feedback, feedback_type, model, created_at, updated_at, number FROM epic ORDER BY created_at DESC
+SELECT id, repo_id, title, description, status, proposed_tasks, task_ids, planning_prompt, session_log, not_ready, claimed_at, last_heartbeat_at, feedback, feedback_type, model, created_at, updated_at, number, cost_usd, max_cost_usd, planning_summary, shared_branch, pull_request_url, pr_number, transcript FROM epic ORDER BY created_at DESC
 `
 
 func (q *Queries) ListEpics(ctx context.Context) ([]*Epic, error) {
@@ -210,6 +234,13 @@ func (q *Queries) ListEpics(ctx context.Context) ([]*Epic, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Number,
+			&i.CostUsd,
+			&i.MaxCostUsd,
+			&i.PlanningSummary,
+			&i.SharedBranch,
+			&i.PullRequestUrl,
+			&i.PrNumber,
+			&i.Transcript,
 		); err != nil {
 			return nil, err
 		}
@@ -225,7 +256,7 @@ func (q *Queries) ListEpics(ctx context.Context) ([]*Epic, error) {
 }
 
 const listEpicsByRepo = `-- name: ListEpicsByRepo :many
-SELECT id, repo_id, title, description, status, proposed_tasks, task_ids, planning_prompt, session_log, not_ready, claimed_at, last_heartbeat_at, feedback, feedback_type, model, created_at, updated_at, number FROM epic WHERE repo_id = ? ORDER BY created_at DESC
+SELECT id, repo_id, title, description, status, proposed_tasks, task_ids, planning_prompt, session_log, not_ready, claimed_at, last_heartbeat_at, feedback, feedback_type, model, created_at, updated_at, number, cost_usd, max_cost_usd, planning_summary, shared_branch, pull_request_url, pr_number, transcript FROM epic WHERE repo_id = ? ORDER BY created_at DESC
 `
 
 func (q *Queries) ListEpicsByRepo(ctx context.Context, repoID string) ([]*Epic, error) {
@@ -256,6 +287,13 @@ func (q *Queries) ListEpicsByRepo(ctx context.Context, repoID string) ([]*Epic,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Number,
+			&i.CostUsd,
+			&i.MaxCostUsd,
+			&i.PlanningSummary,
+			&i.SharedBranch,
+			&i.PullRequestUrl,
+			&i.PrNumber,
+			&i.Transcript,
 		); err != nil {
 			return nil, err
 		}
@@ -271,7 +309,7 @@ func (q *Queries) ListEpicsByRepo(ctx context.Context, repoID string) ([]*Epic,
 }
 
 const listPlanningEpics = `-- name: ListPlanningEpics :many
-SELECT id, repo_id, title, description, status, proposed_tasks, task_ids, planning_prompt, session_log, not_ready, claimed_at, last_heartbeat_at, feedback, feedback_type, model, created_at, updated_at, number FROM epic
+SELECT id, repo_id, title, description, status, proposed_tasks, task_ids, planning_prompt, session_log, not_ready, claimed_at, last_heartbeat_at, feedback, feedback_type, model, created_at, updated_at, number, cost_usd, max_cost_usd, planning_summary, shared_branch, pull_request_url, pr_number, transcript FROM epic
 WHERE status = 'planning' AND claimed_at IS NULL
 ORDER BY created_at ASC
 `
@@ -304,6 +342,13 @@ func (q *Queries) ListPlanningEpics(ctx context.Context) ([]*Epic, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Number,
+			&i.CostUsd,
+			&i.MaxCostUsd,
+			&i.PlanningSummary,
+			&i.SharedBranch,
+			&i.PullRequestUrl,
+			&i.PrNumber,
+			&i.Transcript,
 		); err != nil {
 			return nil, err
 		}
@@ -319,7 +364,7 @@ func (q *Queries) ListPlanningEpics(ctx context.Context) ([]*Epic, error) {
 }
 
 const listStaleEpics = `-- name: ListStaleEpics :many
-SELECT id, repo_id, title, description, status, proposed_tasks, task_ids, planning_prompt, session_log, not_ready, claimed_at, last_heartbeat_at, feedback, feedback_type, model, created_at, updated_at, number FROM epic
+SELECT id, repo_id, title, description, status, proposed_tasks, task_ids, planning_prompt, session_log, not_ready, claimed_at, last_heartbeat_at, feedback, feedback_type, model, created_at, updated_at, number, cost_usd, max_cost_usd, planning_summary, shared_branch, pull_request_url, pr_number, transcript FROM epic
 WHERE claimed_at IS NOT NULL
   AND last_heartbeat_at < ?
   AND status IN ('planning', 'draft')
@@ -354,6 +399,13 @@ func (q *Queries) ListStaleEpics(ctx context.Context, lastHeartbeatAt *int64) ([
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Number,
+			&i.CostUsd,
+			&i.MaxCostUsd,
+			&i.PlanningSummary,
+			&i.SharedBranch,
+			&i.PullRequestUrl,
+			&i.PrNumber,
+			&i.Transcript,
 		); err != nil {
 			return nil, err
 		}
@@ -369,7 +421,7 @@ func (q *Queries) ListStaleEpics(ctx context.Context, lastHeartbeatAt *int64) ([
 }
 
 const readEpic = `-- name: ReadEpic :one
-SELECT id, repo_id, title, description, status, proposed_tasks, task_ids, planning_prompt, session_log, not_ready, claimed_at, last_heartbeat_at, feedback, feedback_type, model, created_at, updated_at, number FROM epic WHERE id = ?
+SELECT id, repo_id, title, description, status, proposed_tasks, task_ids, planning_prompt, session_log, not_ready, claimed_at, last_heartbeat_at, feedback, feedback_type, model, created_at, updated_at, number, cost_usd, max_cost_usd, planning_summary, shared_branch, pull_request_url, pr_number, transcript FROM epic WHERE id = ?
 `
 
 func (q *Queries) ReadEpic(ctx context.Context, id string) (*Epic, error) {
@@ -394,12 +446,19 @@ func (q *Queries) ReadEpic(ctx context.Context, id string) (*Epic, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Number,
+		&i.CostUsd,
+		&i.MaxCostUsd,
+		&i.PlanningSummary,
+		&i.SharedBranch,
+		&i.PullRequestUrl,
+		&i.PrNumber,
+		&i.Transcript,
 	)
 	return &i, err
 }
 
 const readEpicByNumber = `-- name: ReadEpicByNumber :one
-SELECT id, repo_id, title, description, status, proposed_tasks, task_ids, planning_prompt, session_log, not_ready, claimed_at, last_heartbeat_at, feedback, feedback_type, model, created_at, updated_at, number FROM epic WHERE repo_id = ? AND number = ?
+SELECT id, repo_id, title, description, status, proposed_tasks, task_ids, planning_prompt, session_log, not_ready, claimed_at, last_heartbeat_at, feedback, feedback_type, model, created_at, updated_at, number, cost_usd, max_cost_usd, planning_summary, shared_branch, pull_request_url, pr_number, transcript FROM epic WHERE repo_id = ? AND number = ?
 `
 
 type ReadEpicByNumberParams struct {
@@ -429,6 +488,13 @@ func (q *Queries) ReadEpicByNumber(ctx context.Context, arg ReadEpicByNumberPara
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Number,
+		&i.CostUsd,
+		&i.MaxCostUsd,
+		&i.PlanningSummary,
+		&i.SharedBranch,
+		&i.PullRequestUrl,
+		&i.PrNumber,
+		&i.Transcript,
 	)
 	return &i, err
 }
@@ -466,6 +532,52 @@ func (q *Queries) SetEpicFeedback(ctx context.Context, arg SetEpicFeedbackParams
 	return err
 }
 
+const setEpicPlanningSummary = `-- name: SetEpicPlanningSummary :exec
+UPDATE epic SET planning_summary = ?, updated_at = unixepoch()
+WHERE id = ?
+`
+
+type SetEpicPlanningSummaryParams struct {
+	PlanningSummary *string
+	ID              string
+}
+
+func (q *Queries) SetEpicPlanningSummary(ctx context.Context, arg SetEpicPlanningSummaryParams) error {
+	_, err := q.db.ExecContext(ctx, setEpicPlanningSummary, arg.PlanningSummary, arg.ID)
+	return err
+}
+
+const setEpicPullRequest = `-- name: SetEpicPullRequest :exec
+UPDATE epic SET pull_request_url = ?, pr_number = ?, updated_at = unixepoch()
+WHERE id = ?
+`
+
+type SetEpicPullRequestParams struct {
+	PullRequestUrl *string
+	PrNumber       *int64
+	ID             string
+}
+
+func (q *Queries) SetEpicPullRequest(ctx context.Context, arg SetEpicPullRequestParams) error {
+	_, err := q.db.ExecContext(ctx, setEpicPullRequest, arg.PullRequestUrl, arg.PrNumber, arg.ID)
+	return err
+}
+
+const setEpicSharedBranch = `-- name: SetEpicSharedBranch :exec
+UPDATE epic SET shared_branch = ?, updated_at = unixepoch()
+WHERE id = ?
+`
+
+type SetEpicSharedBranchParams struct {
+	SharedBranch int64
+	ID           string
+}
+
+func (q *Queries) SetEpicSharedBranch(ctx context.Context, arg SetEpicSharedBranchParams) error {
+	_, err := q.db.ExecContext(ctx, setEpicSharedBranch, arg.SharedBranch, arg.ID)
+	return err
+}
+
 const setEpicTaskIDs = `-- name: SetEpicTaskIDs :exec
 UPDATE epic SET task_ids = ?, updated_at = unixepoch()
 WHERE id = ?
@@ -492,6 +604,7 @@ UPDATE epic SET
   session_log = ?,
   not_ready = ?,
   model = ?,
+  max_cost_usd = ?,
   updated_at = unixepoch()
 WHERE id = ?
 `
@@ -506,6 +619,7 @@ type UpdateEpicParams struct {
 	SessionLog     string
 	NotReady       int64
 	Model          *string
+	MaxCostUsd     *float64
 	ID             string
 }
 
@@ -520,6 +634,7 @@ func (q *Queries) UpdateEpic(ctx context.Context, arg UpdateEpicParams) error {
 		arg.SessionLog,
 		arg.NotReady,
 		arg.Model,
+		arg.MaxCostUsd,
 		arg.ID,
 	)
 	return err
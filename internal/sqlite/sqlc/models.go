@@ -4,6 +4,17 @@
 
 package sqlc
 
+type AuditLog struct {
+	ID         string
+	Action     string
+	EntityType string
+	EntityID   string
+	FromStatus string
+	ToStatus   string
+	Reason     string
+	CreatedAt  int64
+}
+
 type Conversation struct {
 	ID              string
 	RepoID          string
@@ -38,6 +49,32 @@ type Epic struct {
 	CreatedAt       int64
 	UpdatedAt       int64
 	Number          *int64
+	CostUsd         float64
+	MaxCostUsd      *float64
+	PlanningSummary *string
+	SharedBranch    int64
+	PullRequestUrl  *string
+	PrNumber        *int64
+	Transcript      string
+}
+
+type EventBus struct {
+	ID        int64
+	Origin    string
+	Payload   string
+	CreatedAt int64
+}
+
+type GithubCredential struct {
+	ID              string
+	Name            string
+	Kind            string
+	Repos           string
+	EncryptedSecret string
+	AppID           int64
+	InstallationID  int64
+	CreatedAt       int64
+	UpdatedAt       int64
 }
 
 type GithubToken struct {
@@ -47,20 +84,52 @@ type GithubToken struct {
 	UpdatedAt      int64
 }
 
+type Lease struct {
+	Name      string
+	Holder    string
+	ExpiresAt int64
+}
+
+type NotificationSink struct {
+	ID        string
+	RepoID    *string
+	Kind      string
+	Url       string
+	Events    string
+	CreatedAt int64
+	TeamID    *string
+}
+
 type Repo struct {
-	ID               string
-	Owner            string
-	Name             string
-	FullName         string
-	CreatedAt        int64
-	Summary          string
-	TechStack        string
-	SetupStatus      string
-	HasCode          int64
-	HasClaudeMd      int64
-	HasReadme        int64
-	Expectations     string
-	SetupCompletedAt *int64
+	ID                       string
+	Owner                    string
+	Name                     string
+	FullName                 string
+	CreatedAt                int64
+	Summary                  string
+	TechStack                string
+	SetupStatus              string
+	HasCode                  int64
+	HasClaudeMd              int64
+	HasReadme                int64
+	Expectations             string
+	SetupCompletedAt         *int64
+	CiWorkflow               string
+	WorkspaceCacheGeneration int64
+	ProtectedPaths           string
+	ShadowMode               int64
+	CompletionValidations    string
+	RetryPolicy              string
+	MaxRuntimeSeconds        int64
+	RequiredLabels           string
+	TeamID                   *string
+	IgnoredChecks            string
+	CheckGating              string
+	FallbackModels           string
+	Paused                   int64
+	SyncDisabled             int64
+	SyncIntervalSeconds      int64
+	ReviewSlaSeconds         int64
 }
 
 type Setting struct {
@@ -100,6 +169,87 @@ type Task struct {
 	UpdatedAt              int64
 	Type                   string
 	Number                 *int64
+	LastReviewID           int64
+	AdditionalRepoIds      string
+	PullRequests           string
+	NotBefore              *int64
+	FailureCategory        *string
+	MaxRuntimeSeconds      int64
+	RequiredLabels         string
+	DeletedAt              *int64
+	ArchivedAt             *int64
+	StackParentID          *string
+	PlanOnly               int64
+	FallbackModels         string
+	ActiveModel            string
+	Version                int64
+}
+
+type TaskArtifact struct {
+	ID          int64
+	TaskID      string
+	Attempt     int64
+	Name        string
+	ContentType string
+	SizeBytes   int64
+	StorageKey  string
+	CreatedAt   int64
+}
+
+type TaskAttachment struct {
+	ID          int64
+	TaskID      string
+	Filename    string
+	ContentType string
+	SizeBytes   int64
+	StorageKey  string
+	UploadedBy  string
+	CreatedAt   int64
+}
+
+type TaskAttempt struct {
+	TaskID      string
+	Attempt     int64
+	RetryReason string
+	Result      string
+	CostUsd     float64
+	StartedAt   int64
+	EndedAt     *int64
+	Model       string
+}
+
+type TaskCallback struct {
+	TaskID         string
+	Attempt        int64
+	IdempotencyKey string
+	CreatedAt      int64
+}
+
+type TaskCiDispatch struct {
+	TaskID       string
+	WorkflowFile string
+	Ref          string
+	DispatchedAt int64
+}
+
+type TaskComment struct {
+	ID        int64
+	TaskID    string
+	Attempt   *int64
+	Author    string
+	AuthorID  *string
+	Body      string
+	CreatedAt int64
+	UpdatedAt int64
+}
+
+type TaskEventLog struct {
+	ID        int64
+	TaskID    string
+	Attempt   int64
+	Type      string
+	Payload   string
+	CreatedAt int64
 }
 
 type TaskLog struct {
@@ -109,3 +259,126 @@ type TaskLog struct {
 	Attempt   int64
 	CreatedAt int64
 }
+
+type TaskLogArchive struct {
+	TaskID     string
+	Batches    string
+	ArchivedAt int64
+}
+
+type TaskNote struct {
+	ID              int64
+	TaskID          string
+	RepoID          string
+	Attempt         int64
+	Text            string
+	CreatedAt       int64
+	ConvertedTaskID *string
+}
+
+type TaskNudge struct {
+	ID          int64
+	TaskID      string
+	Attempt     int64
+	Message     string
+	CreatedAt   int64
+	DeliveredAt *int64
+}
+
+type TaskProgress struct {
+	TaskID    string
+	Attempt   int64
+	Steps     string
+	UpdatedAt int64
+}
+
+type TaskProvenanceReview struct {
+	TaskID         string
+	Findings       string
+	HeadSha        string
+	ScannedAt      int64
+	AcknowledgedAt *int64
+}
+
+type TaskProposal struct {
+	TaskID     string
+	Attempt    int64
+	Summary    string
+	Approach   string
+	Files      string
+	Risks      string
+	CreatedAt  int64
+	ApprovedAt *int64
+}
+
+type TaskReview struct {
+	ID        int64
+	TaskID    string
+	StartedAt int64
+	EndedAt   *int64
+	OverdueAt *int64
+}
+
+type TaskSelfReview struct {
+	TaskID     string
+	Score      int64
+	Checks     string
+	HeadSha    string
+	CommentID  int64
+	ReviewedAt int64
+}
+
+type TaskShadowDiff struct {
+	TaskID    string
+	Attempt   int64
+	Diff      string
+	CreatedAt int64
+}
+
+type Team struct {
+	ID                string
+	Name              string
+	DefaultModel      string
+	DefaultMaxCostUsd float64
+	CreatedAt         int64
+}
+
+type User struct {
+	ID        string
+	Name      string
+	Role      string
+	TokenHash string
+	CreatedAt int64
+}
+
+type UserSession struct {
+	ID        string
+	Subject   string
+	Name      string
+	Role      string
+	TokenHash string
+	ExpiresAt int64
+	CreatedAt int64
+}
+
+type WebhookDelivery struct {
+	ID             string
+	SubscriptionID string
+	EventID        string
+	EventType      string
+	Attempt        int64
+	StatusCode     *int64
+	Error          *string
+	ResponseBody   *string
+	DurationMs     int64
+	CreatedAt      int64
+}
+
+type WebhookSubscription struct {
+	ID         string
+	Url        string
+	Secret     string
+	EventTypes string
+	RepoID     *string
+	CreatedAt  int64
+}
@@ -9,13 +9,36 @@ import (
 )
 
 type Querier interface {
+	AbandonStaleTask(ctx context.Context, arg AbandonStaleTaskParams) (int64, error)
+	AbandonTask(ctx context.Context, id string) (int64, error)
+	AcquireLease(ctx context.Context, arg AcquireLeaseParams) (int64, error)
+	AddEpicCost(ctx context.Context, arg AddEpicCostParams) error
+	AddTaskAttemptCost(ctx context.Context, arg AddTaskAttemptCostParams) error
 	AddTaskCost(ctx context.Context, arg AddTaskCostParams) error
+	AppendBusEvent(ctx context.Context, arg AppendBusEventParams) error
 	AppendSessionLog(ctx context.Context, arg AppendSessionLogParams) error
+	AppendTaskEvent(ctx context.Context, arg AppendTaskEventParams) (*TaskEventLog, error)
 	AppendTaskLogs(ctx context.Context, arg AppendTaskLogsParams) error
+	ApproveTaskProposal(ctx context.Context, arg ApproveTaskProposalParams) (int64, error)
+	ArchiveTask(ctx context.Context, id string) (int64, error)
 	AssignEpicNumber(ctx context.Context, arg AssignEpicNumberParams) (*int64, error)
 	AssignTaskNumber(ctx context.Context, arg AssignTaskNumberParams) (*int64, error)
 	BulkCloseTasksByEpic(ctx context.Context, arg BulkCloseTasksByEpicParams) error
+	BulkDeleteTaskArtifactsByEpic(ctx context.Context, epicID *string) error
+	BulkDeleteTaskAttachmentsByEpic(ctx context.Context, epicID *string) error
+	BulkDeleteTaskAttemptsByEpic(ctx context.Context, epicID *string) error
+	BulkDeleteTaskCIDispatchesByEpic(ctx context.Context, epicID *string) error
+	BulkDeleteTaskCommentsByEpic(ctx context.Context, epicID *string) error
+	BulkDeleteTaskEventsByEpic(ctx context.Context, epicID *string) error
+	BulkDeleteTaskLogArchivesByEpic(ctx context.Context, epicID *string) error
 	BulkDeleteTaskLogsByEpic(ctx context.Context, epicID *string) error
+	BulkDeleteTaskNotesByEpic(ctx context.Context, epicID *string) error
+	BulkDeleteTaskNudgesByEpic(ctx context.Context, epicID *string) error
+	BulkDeleteTaskProgressByEpic(ctx context.Context, epicID *string) error
+	BulkDeleteTaskProposalsByEpic(ctx context.Context, epicID *string) error
+	BulkDeleteTaskProvenanceReviewsByEpic(ctx context.Context, epicID *string) error
+	BulkDeleteTaskSelfReviewsByEpic(ctx context.Context, epicID *string) error
+	BulkDeleteTaskShadowDiffsByEpic(ctx context.Context, epicID *string) error
 	BulkDeleteTasksByEpic(ctx context.Context, epicID *string) error
 	ClaimConversation(ctx context.Context, id string) (int64, error)
 	ClaimEpic(ctx context.Context, id string) (int64, error)
@@ -24,56 +47,166 @@ type Querier interface {
 	ClearEpicIDForTasks(ctx context.Context, epicID *string) error
 	CloseTask(ctx context.Context, arg CloseTaskParams) error
 	ConversationHeartbeat(ctx context.Context, id string) error
+	CountTaskArtifacts(ctx context.Context, arg CountTaskArtifactsParams) (int64, error)
+	CountTaskAttachments(ctx context.Context, taskID string) (int64, error)
+	CountTaskComments(ctx context.Context, taskID string) (int64, error)
+	CountTaskCommentsByRepo(ctx context.Context, repoID string) ([]*CountTaskCommentsByRepoRow, error)
+	CreateAuditLog(ctx context.Context, arg CreateAuditLogParams) error
 	CreateConversation(ctx context.Context, arg CreateConversationParams) error
 	CreateEpic(ctx context.Context, arg CreateEpicParams) error
+	CreateGitHubCredential(ctx context.Context, arg CreateGitHubCredentialParams) error
+	CreateNotificationSink(ctx context.Context, arg CreateNotificationSinkParams) error
 	CreateRepo(ctx context.Context, arg CreateRepoParams) error
 	CreateTask(ctx context.Context, arg CreateTaskParams) error
+	CreateTaskArtifact(ctx context.Context, arg CreateTaskArtifactParams) (*TaskArtifact, error)
+	CreateTaskAttachment(ctx context.Context, arg CreateTaskAttachmentParams) (*TaskAttachment, error)
+	CreateTaskComment(ctx context.Context, arg CreateTaskCommentParams) (*TaskComment, error)
+	CreateTaskLogArchive(ctx context.Context, arg CreateTaskLogArchiveParams) error
+	CreateTaskNote(ctx context.Context, arg CreateTaskNoteParams) (*TaskNote, error)
+	CreateTaskNudge(ctx context.Context, arg CreateTaskNudgeParams) (*TaskNudge, error)
+	CreateTeam(ctx context.Context, arg CreateTeamParams) error
+	CreateUser(ctx context.Context, arg CreateUserParams) error
+	CreateUserSession(ctx context.Context, arg CreateUserSessionParams) error
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) error
+	CreateWebhookSubscription(ctx context.Context, arg CreateWebhookSubscriptionParams) error
+	DeleteBusEventsBefore(ctx context.Context, createdAt int64) (int64, error)
 	DeleteConversation(ctx context.Context, id string) error
 	DeleteEpic(ctx context.Context, id string) error
-	DeleteExpiredLogs(ctx context.Context, createdAt int64) (int64, error)
+	DeleteExpiredLogs(ctx context.Context, arg DeleteExpiredLogsParams) (int64, error)
+	DeleteExpiredTaskArtifacts(ctx context.Context, before int64) (int64, error)
+	DeleteExpiredUserSessions(ctx context.Context, expiresAt int64) error
+	DeleteGitHubCredential(ctx context.Context, id string) error
 	DeleteGitHubToken(ctx context.Context) error
+	DeleteNotificationSink(ctx context.Context, id string) error
 	DeleteRepo(ctx context.Context, id string) error
 	DeleteSetting(ctx context.Context, key string) error
 	DeleteTask(ctx context.Context, id string) error
+	DeleteTaskArtifacts(ctx context.Context, taskID string) error
+	DeleteTaskAttachment(ctx context.Context, arg DeleteTaskAttachmentParams) (int64, error)
+	DeleteTaskAttachments(ctx context.Context, taskID string) error
+	DeleteTaskAttempts(ctx context.Context, taskID string) error
+	DeleteTaskCIDispatch(ctx context.Context, taskID string) error
+	DeleteTaskCallback(ctx context.Context, arg DeleteTaskCallbackParams) error
+	DeleteTaskCallbacks(ctx context.Context, taskID string) error
+	DeleteTaskCallbacksBefore(ctx context.Context, createdAt int64) (int64, error)
+	DeleteTaskComment(ctx context.Context, arg DeleteTaskCommentParams) (int64, error)
+	DeleteTaskComments(ctx context.Context, taskID string) error
+	DeleteTaskEvents(ctx context.Context, taskID string) error
+	DeleteTaskLogArchive(ctx context.Context, taskID string) error
 	DeleteTaskLogs(ctx context.Context, taskID string) error
+	DeleteTaskNotes(ctx context.Context, taskID string) error
+	DeleteTaskNudges(ctx context.Context, taskID string) error
+	DeleteTaskProgress(ctx context.Context, taskID string) error
+	DeleteTaskProposal(ctx context.Context, taskID string) error
+	DeleteTaskProvenanceReview(ctx context.Context, taskID string) error
+	DeleteTaskSelfReview(ctx context.Context, taskID string) error
+	DeleteTaskShadowDiff(ctx context.Context, taskID string) error
+	DeleteTeam(ctx context.Context, id string) error
+	DeleteUser(ctx context.Context, id string) error
+	DeleteUserSessionByTokenHash(ctx context.Context, tokenHash string) error
+	DeleteWebhookSubscription(ctx context.Context, id string) error
+	DeliverTaskNudges(ctx context.Context, arg DeliverTaskNudgesParams) ([]*TaskNudge, error)
+	EndTaskAttempt(ctx context.Context, arg EndTaskAttemptParams) error
 	EpicHeartbeat(ctx context.Context, id string) error
 	FeedbackRetryTask(ctx context.Context, arg FeedbackRetryTaskParams) (int64, error)
 	HasTasksForRepo(ctx context.Context, repoID string) (int64, error)
 	Heartbeat(ctx context.Context, id string) (int64, error)
+	IncrementRepoWorkspaceCacheGeneration(ctx context.Context, id string) error
+	LatestBusEventID(ctx context.Context) (int64, error)
 	ListActiveConversations(ctx context.Context) ([]*Conversation, error)
 	ListActiveEpics(ctx context.Context) ([]*Epic, error)
+	ListActiveTaskCosts(ctx context.Context) ([]*ListActiveTaskCostsRow, error)
+	ListArchivableTaskIDs(ctx context.Context, arg ListArchivableTaskIDsParams) ([]string, error)
+	ListArchivedTasksByRepo(ctx context.Context, repoID string) ([]*Task, error)
+	ListAuditLogs(ctx context.Context, limit int64) ([]*AuditLog, error)
+	ListAuditLogsByEntity(ctx context.Context, arg ListAuditLogsByEntityParams) ([]*AuditLog, error)
+	ListBusEventsAfter(ctx context.Context, arg ListBusEventsAfterParams) ([]*EventBus, error)
 	ListConversationsByRepo(ctx context.Context, repoID string) ([]*Conversation, error)
 	ListEpics(ctx context.Context) ([]*Epic, error)
 	ListEpicsByRepo(ctx context.Context, repoID string) ([]*Epic, error)
+	ListExpiredTaskArtifactKeys(ctx context.Context, before int64) ([]string, error)
+	ListFailedTasksByRepo(ctx context.Context, repoID string) ([]*Task, error)
+	ListFinishedTaskCosts(ctx context.Context, limit int64) ([]*ListFinishedTaskCostsRow, error)
+	ListGitHubCredentials(ctx context.Context) ([]*GithubCredential, error)
+	ListNotificationSinksByRepo(ctx context.Context, repoID *string) ([]*NotificationSink, error)
+	ListNotificationSinksByTeam(ctx context.Context, teamID *string) ([]*NotificationSink, error)
+	ListOverdueReviews(ctx context.Context, now int64) ([]*ListOverdueReviewsRow, error)
+	ListOverrunTasks(ctx context.Context, now int64) ([]*Task, error)
+	ListPausedRepoIDs(ctx context.Context) ([]string, error)
 	ListPendingConversations(ctx context.Context) ([]*Conversation, error)
 	ListPendingTasks(ctx context.Context) ([]*Task, error)
 	ListPlanningEpics(ctx context.Context) ([]*Epic, error)
+	ListRepoRequiredLabels(ctx context.Context) ([]*ListRepoRequiredLabelsRow, error)
+	ListRepoSpendSince(ctx context.Context, since int64) ([]*ListRepoSpendSinceRow, error)
+	ListRepoTaskAttemptsSince(ctx context.Context, arg ListRepoTaskAttemptsSinceParams) ([]*ListRepoTaskAttemptsSinceRow, error)
+	ListRepoTaskNotes(ctx context.Context, repoID string) ([]*TaskNote, error)
+	ListRepoTasksUpdatedSince(ctx context.Context, arg ListRepoTasksUpdatedSinceParams) ([]*Task, error)
 	ListRepos(ctx context.Context) ([]*Repo, error)
 	ListReposBySetupStatus(ctx context.Context, setupStatus string) ([]*Repo, error)
+	ListReposByTeam(ctx context.Context, teamID *string) ([]*Repo, error)
+	ListReviewSpans(ctx context.Context, arg ListReviewSpansParams) ([]*ListReviewSpansRow, error)
 	ListSettings(ctx context.Context) ([]*ListSettingsRow, error)
 	ListStaleConversations(ctx context.Context, lastHeartbeatAt *int64) ([]*Conversation, error)
 	ListStaleEpics(ctx context.Context, lastHeartbeatAt *int64) ([]*Epic, error)
 	ListStaleTasks(ctx context.Context, lastHeartbeatAt *int64) ([]*Task, error)
+	ListTaskArtifacts(ctx context.Context, taskID string) ([]*TaskArtifact, error)
+	ListTaskAttachments(ctx context.Context, taskID string) ([]*TaskAttachment, error)
+	ListTaskAttempts(ctx context.Context, taskID string) ([]*TaskAttempt, error)
+	ListTaskComments(ctx context.Context, taskID string) ([]*TaskComment, error)
+	ListTaskCreationsSince(ctx context.Context, since int64) ([]*ListTaskCreationsSinceRow, error)
+	ListTaskEvents(ctx context.Context, taskID string) ([]*TaskEventLog, error)
+	ListTaskNudges(ctx context.Context, taskID string) ([]*TaskNudge, error)
+	ListTaskTransitionsSince(ctx context.Context, since int64) ([]*ListTaskTransitionsSinceRow, error)
 	ListTasks(ctx context.Context) ([]*Task, error)
 	ListTasksByEpic(ctx context.Context, epicID *string) ([]*Task, error)
 	ListTasksByRepo(ctx context.Context, repoID string) ([]*Task, error)
 	ListTasksInReview(ctx context.Context) ([]*Task, error)
 	ListTasksInReviewByRepo(ctx context.Context, repoID string) ([]*Task, error)
 	ListTasksInReviewNoPR(ctx context.Context) ([]*Task, error)
+	ListTeams(ctx context.Context) ([]*Team, error)
+	ListTrashedTaskIDsBefore(ctx context.Context, before int64) ([]string, error)
+	ListTrashedTasksByRepo(ctx context.Context, repoID string) ([]*Task, error)
+	ListUsers(ctx context.Context) ([]*User, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]*WebhookDelivery, error)
+	ListWebhookSubscriptions(ctx context.Context) ([]*WebhookSubscription, error)
 	ManualRetryTask(ctx context.Context, arg ManualRetryTaskParams) (int64, error)
+	MarkReviewOverdue(ctx context.Context, arg MarkReviewOverdueParams) (int64, error)
+	MoveTask(ctx context.Context, arg MoveTaskParams) (int64, error)
+	PruneWebhookDeliveries(ctx context.Context, arg PruneWebhookDeliveriesParams) error
 	ReadConversation(ctx context.Context, id string) (*Conversation, error)
 	ReadEpic(ctx context.Context, id string) (*Epic, error)
 	ReadEpicByNumber(ctx context.Context, arg ReadEpicByNumberParams) (*Epic, error)
+	ReadGitHubCredential(ctx context.Context, id string) (*GithubCredential, error)
 	ReadGitHubToken(ctx context.Context) (string, error)
+	ReadNotificationSink(ctx context.Context, id string) (*NotificationSink, error)
 	ReadRepo(ctx context.Context, id string) (*Repo, error)
 	ReadRepoByFullName(ctx context.Context, fullName string) (*Repo, error)
 	ReadSetting(ctx context.Context, key string) (string, error)
 	ReadTask(ctx context.Context, id string) (*Task, error)
+	ReadTaskArtifact(ctx context.Context, arg ReadTaskArtifactParams) (*TaskArtifact, error)
+	ReadTaskAttachment(ctx context.Context, arg ReadTaskAttachmentParams) (*TaskAttachment, error)
 	ReadTaskByNumber(ctx context.Context, arg ReadTaskByNumberParams) (*Task, error)
+	ReadTaskCIDispatch(ctx context.Context, taskID string) (*TaskCiDispatch, error)
+	ReadTaskComment(ctx context.Context, arg ReadTaskCommentParams) (*TaskComment, error)
+	ReadTaskLogArchive(ctx context.Context, taskID string) (string, error)
 	ReadTaskLogs(ctx context.Context, taskID string) ([]*ReadTaskLogsRow, error)
+	ReadTaskNote(ctx context.Context, id int64) (*TaskNote, error)
+	ReadTaskProgress(ctx context.Context, taskID string) (*TaskProgress, error)
+	ReadTaskProposal(ctx context.Context, taskID string) (*TaskProposal, error)
+	ReadTaskProvenanceReview(ctx context.Context, taskID string) (*TaskProvenanceReview, error)
+	ReadTaskSelfReview(ctx context.Context, taskID string) (*TaskSelfReview, error)
+	ReadTaskShadowDiff(ctx context.Context, taskID string) (*TaskShadowDiff, error)
 	ReadTaskStatus(ctx context.Context, id string) (string, error)
+	ReadTeam(ctx context.Context, id string) (*Team, error)
+	ReadUser(ctx context.Context, id string) (*User, error)
+	ReadUserByTokenHash(ctx context.Context, tokenHash string) (*User, error)
+	ReadUserSessionByTokenHash(ctx context.Context, tokenHash string) (*UserSession, error)
+	ReadWebhookSubscription(ctx context.Context, id string) (*WebhookSubscription, error)
+	RecordTaskCallback(ctx context.Context, arg RecordTaskCallbackParams) (int64, error)
 	ReleaseConversationClaim(ctx context.Context, id string) error
 	ReleaseEpicClaim(ctx context.Context, id string) error
+	ReleaseLease(ctx context.Context, arg ReleaseLeaseParams) error
+	RestoreTask(ctx context.Context, id string) (int64, error)
 	RetryTask(ctx context.Context, arg RetryTaskParams) (int64, error)
 	ScheduleRetryFromRunning(ctx context.Context, arg ScheduleRetryFromRunningParams) (int64, error)
 	SetAgentStatus(ctx context.Context, arg SetAgentStatusParams) error
@@ -84,27 +217,65 @@ type Querier interface {
 	SetConversationMessages(ctx context.Context, arg SetConversationMessagesParams) error
 	SetDependsOn(ctx context.Context, arg SetDependsOnParams) error
 	SetEpicFeedback(ctx context.Context, arg SetEpicFeedbackParams) error
+	SetEpicPlanningSummary(ctx context.Context, arg SetEpicPlanningSummaryParams) error
+	SetEpicPullRequest(ctx context.Context, arg SetEpicPullRequestParams) error
+	SetEpicSharedBranch(ctx context.Context, arg SetEpicSharedBranchParams) error
 	SetEpicTaskIDs(ctx context.Context, arg SetEpicTaskIDsParams) error
+	SetFailureCategory(ctx context.Context, arg SetFailureCategoryParams) error
 	SetPendingMessage(ctx context.Context, arg SetPendingMessageParams) error
 	SetReady(ctx context.Context, arg SetReadyParams) error
 	SetRetryContext(ctx context.Context, arg SetRetryContextParams) error
+	SetTaskActiveModel(ctx context.Context, arg SetTaskActiveModelParams) error
+	SetTaskLastReviewID(ctx context.Context, arg SetTaskLastReviewIDParams) error
+	SetTaskNoteConvertedTask(ctx context.Context, arg SetTaskNoteConvertedTaskParams) (int64, error)
+	SetTaskPlanOnly(ctx context.Context, arg SetTaskPlanOnlyParams) error
 	SetTaskPullRequest(ctx context.Context, arg SetTaskPullRequestParams) error
+	SetTaskPullRequests(ctx context.Context, arg SetTaskPullRequestsParams) error
 	StartOverTask(ctx context.Context, arg StartOverTaskParams) (int64, error)
+	StartTaskAttempt(ctx context.Context, arg StartTaskAttemptParams) error
 	StopTask(ctx context.Context, arg StopTaskParams) (int64, error)
 	TaskExists(ctx context.Context, id string) (int64, error)
+	TrashTask(ctx context.Context, id string) (int64, error)
+	UnarchiveTask(ctx context.Context, id string) (int64, error)
 	UpdateConversationStatus(ctx context.Context, arg UpdateConversationStatusParams) error
 	UpdateEpic(ctx context.Context, arg UpdateEpicParams) error
 	UpdateEpicStatus(ctx context.Context, arg UpdateEpicStatusParams) error
+	UpdateGitHubCredential(ctx context.Context, arg UpdateGitHubCredentialParams) error
 	UpdatePendingTask(ctx context.Context, arg UpdatePendingTaskParams) (int64, error)
 	UpdateProposedTasks(ctx context.Context, arg UpdateProposedTasksParams) error
+	UpdateRepoCIWorkflow(ctx context.Context, arg UpdateRepoCIWorkflowParams) error
+	UpdateRepoCheckGating(ctx context.Context, arg UpdateRepoCheckGatingParams) error
+	UpdateRepoCompletionValidations(ctx context.Context, arg UpdateRepoCompletionValidationsParams) error
 	UpdateRepoExpectations(ctx context.Context, arg UpdateRepoExpectationsParams) error
+	UpdateRepoFallbackModels(ctx context.Context, arg UpdateRepoFallbackModelsParams) error
+	UpdateRepoIgnoredChecks(ctx context.Context, arg UpdateRepoIgnoredChecksParams) error
+	UpdateRepoMaxRuntime(ctx context.Context, arg UpdateRepoMaxRuntimeParams) error
+	UpdateRepoPaused(ctx context.Context, arg UpdateRepoPausedParams) error
+	UpdateRepoProtectedPaths(ctx context.Context, arg UpdateRepoProtectedPathsParams) error
+	UpdateRepoRequiredLabels(ctx context.Context, arg UpdateRepoRequiredLabelsParams) error
+	UpdateRepoRetryPolicy(ctx context.Context, arg UpdateRepoRetryPolicyParams) error
+	UpdateRepoReviewSLA(ctx context.Context, arg UpdateRepoReviewSLAParams) error
+	UpdateRepoSyncDisabled(ctx context.Context, arg UpdateRepoSyncDisabledParams) error
+	UpdateRepoSyncInterval(ctx context.Context, arg UpdateRepoSyncIntervalParams) error
 	UpdateRepoSetupScan(ctx context.Context, arg UpdateRepoSetupScanParams) error
 	UpdateRepoSetupStatus(ctx context.Context, arg UpdateRepoSetupStatusParams) error
+	UpdateRepoShadowMode(ctx context.Context, arg UpdateRepoShadowModeParams) error
 	UpdateRepoSummary(ctx context.Context, arg UpdateRepoSummaryParams) error
+	UpdateRepoTeam(ctx context.Context, arg UpdateRepoTeamParams) error
 	UpdateRepoTechStack(ctx context.Context, arg UpdateRepoTechStackParams) error
+	UpdateTaskComment(ctx context.Context, arg UpdateTaskCommentParams) (*TaskComment, error)
 	UpdateTaskStatus(ctx context.Context, arg UpdateTaskStatusParams) error
+	UpdateTeam(ctx context.Context, arg UpdateTeamParams) error
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (int64, error)
+	UpdateUserTokenHash(ctx context.Context, arg UpdateUserTokenHashParams) (int64, error)
 	UpsertGitHubToken(ctx context.Context, arg UpsertGitHubTokenParams) error
 	UpsertSetting(ctx context.Context, arg UpsertSettingParams) error
+	UpsertTaskCIDispatch(ctx context.Context, arg UpsertTaskCIDispatchParams) error
+	UpsertTaskProgress(ctx context.Context, arg UpsertTaskProgressParams) error
+	UpsertTaskProposal(ctx context.Context, arg UpsertTaskProposalParams) error
+	UpsertTaskProvenanceReview(ctx context.Context, arg UpsertTaskProvenanceReviewParams) error
+	UpsertTaskSelfReview(ctx context.Context, arg UpsertTaskSelfReviewParams) error
+	UpsertTaskShadowDiff(ctx context.Context, arg UpsertTaskShadowDiffParams) error
 }
 
 var _ Querier = (*Queries)(nil)
@@ -42,8 +42,19 @@ func (q *Queries) DeleteRepo(ctx context.Context, id string) error {
 	return err
 }
 
+const incrementRepoWorkspaceCacheGeneration = `-- name: IncrementRepoWorkspaceCacheGeneration :exec
+UPDATE repo
+SET workspace_cache_generation = workspace_cache_generation + 1
+WHERE id = ?
+`
+
+func (q *Queries) IncrementRepoWorkspaceCacheGeneration(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, incrementRepoWorkspaceCacheGeneration, id)
+	return err
+}
+
 const listRepos = `-- name: ListRepos :many
-SELECT id, owner, name, full_name, created_at, summary, tech_stack, setup_status, has_code, has_claude_md, has_readme, expectations, setup_completed_at FROM repo ORDER BY created_at DESC
+SELECT id, owner, name, full_name, created_at, summary, tech_stack, setup_status, has_code, has_claude_md, has_readme, expectations, setup_completed_at, ci_workflow, workspace_cache_generation, protected_paths, shadow_mode, completion_validations, retry_policy, max_runtime_seconds, required_labels, team_id, ignored_checks, check_gating, fallback_models, paused, sync_disabled, sync_interval_seconds, review_sla_seconds FROM repo ORDER BY created_at DESC
 `
 
 func (q *Queries) ListRepos(ctx context.Context) ([]*Repo, error) {
@@ -69,6 +80,22 @@ func (q *Queries) ListRepos(ctx context.Context) ([]*Repo, error) {
 			&i.HasReadme,
 			&i.Expectations,
 			&i.SetupCompletedAt,
+			&i.CiWorkflow,
+			&i.WorkspaceCacheGeneration,
+			&i.ProtectedPaths,
+			&i.ShadowMode,
+			&i.CompletionValidations,
+			&i.RetryPolicy,
+			&i.MaxRuntimeSeconds,
+			&i.RequiredLabels,
+			&i.TeamID,
+			&i.IgnoredChecks,
+			&i.CheckGating,
+			&i.FallbackModels,
+			&i.Paused,
+			&i.SyncDisabled,
+			&i.SyncIntervalSeconds,
+			&i.ReviewSlaSeconds,
 		); err != nil {
 			return nil, err
 		}
@@ -84,7 +111,7 @@ func (q *Queries) ListRepos(ctx context.Context) ([]*Repo, error) {
 }
 
 const listReposBySetupStatus = `-- name: ListReposBySetupStatus :many
-SELECT id, owner, name, full_name, created_at, summary, tech_stack, setup_status, has_code, has_claude_md, has_readme, expectations, setup_completed_at FROM repo WHERE setup_status = ? ORDER BY created_at DESC
+SELECT id, owner, name, full_name, created_at, summary, tech_stack, setup_status, has_code, has_claude_md, has_readme, expectations, setup_completed_at, ci_workflow, workspace_cache_generation, protected_paths, shadow_mode, completion_validations, retry_policy, max_runtime_seconds, required_labels, team_id, ignored_checks, check_gating, fallback_models, paused, sync_disabled, sync_interval_seconds, review_sla_seconds FROM repo WHERE setup_status = ? ORDER BY created_at DESC
 `
 
 func (q *Queries) ListReposBySetupStatus(ctx context.Context, setupStatus string) ([]*Repo, error) {
@@ -110,6 +137,79 @@ func (q *Queries) ListReposBySetupStatus(ctx context.Context, setupStatus string
 			&i.HasReadme,
 			&i.Expectations,
 			&i.SetupCompletedAt,
+			&i.CiWorkflow,
+			&i.WorkspaceCacheGeneration,
+			&i.ProtectedPaths,
+			&i.ShadowMode,
+			&i.CompletionValidations,
+			&i.RetryPolicy,
+			&i.MaxRuntimeSeconds,
+			&i.RequiredLabels,
+			&i.TeamID,
+			&i.IgnoredChecks,
+			&i.CheckGating,
+			&i.FallbackModels,
+			&i.Paused,
+			&i.SyncDisabled,
+			&i.SyncIntervalSeconds,
+			&i.ReviewSlaSeconds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listReposByTeam = `-- name: ListReposByTeam :many
+SELECT id, owner, name, full_name, created_at, summary, tech_stack, setup_status, has_code, has_claude_md, has_readme, expectations, setup_completed_at, ci_workflow, workspace_cache_generation, protected_paths, shadow_mode, completion_validations, retry_policy, max_runtime_seconds, required_labels, team_id, ignored_checks, check_gating, fallback_models, paused, sync_disabled, sync_interval_seconds, review_sla_seconds FROM repo WHERE team_id = ? ORDER BY full_name ASC
+`
+
+func (q *Queries) ListReposByTeam(ctx context.Context, teamID *string) ([]*Repo, error) {
+	rows, err := q.db.QueryContext(ctx, listReposByTeam, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Repo
+	for rows.Next() {
+		var i Repo
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Name,
+			&i.FullName,
+			&i.CreatedAt,
+			&i.Summary,
+			&i.TechStack,
+			&i.SetupStatus,
+			&i.HasCode,
+			&i.HasClaudeMd,
+			&i.HasReadme,
+			&i.Expectations,
+			&i.SetupCompletedAt,
+			&i.CiWorkflow,
+			&i.WorkspaceCacheGeneration,
+			&i.ProtectedPaths,
+			&i.ShadowMode,
+			&i.CompletionValidations,
+			&i.RetryPolicy,
+			&i.MaxRuntimeSeconds,
+			&i.RequiredLabels,
+			&i.TeamID,
+			&i.IgnoredChecks,
+			&i.CheckGating,
+			&i.FallbackModels,
+			&i.Paused,
+			&i.SyncDisabled,
+			&i.SyncIntervalSeconds,
+			&i.ReviewSlaSeconds,
 		); err != nil {
 			return nil, err
 		}
@@ -125,7 +225,7 @@ func (q *Queries) ListReposBySetupStatus(ctx context.Context, setupStatus string
 }
 
 const readRepo = `-- name: ReadRepo :one
-SELECT id, owner, name, full_name, created_at, summary, tech_stack, setup_status, has_code, has_claude_md, has_readme, expectations, setup_completed_at FROM repo WHERE id = ?
+SELECT id, owner, name, full_name, created_at, summary, tech_stack, setup_status, has_code, has_claude_md, has_readme, expectations, setup_completed_at, ci_workflow, workspace_cache_generation, protected_paths, shadow_mode, completion_validations, retry_policy, max_runtime_seconds, required_labels, team_id, ignored_checks, check_gating, fallback_models, paused, sync_disabled, sync_interval_seconds, review_sla_seconds FROM repo WHERE id = ?
 `
 
 func (q *Queries) ReadRepo(ctx context.Context, id string) (*Repo, error) {
@@ -145,12 +245,28 @@ func (q *Queries) ReadRepo(ctx context.Context, id string) (*Repo, error) {
 		&i.HasReadme,
 		&i.Expectations,
 		&i.SetupCompletedAt,
+		&i.CiWorkflow,
+		&i.WorkspaceCacheGeneration,
+		&i.ProtectedPaths,
+		&i.ShadowMode,
+		&i.CompletionValidations,
+		&i.RetryPolicy,
+		&i.MaxRuntimeSeconds,
+		&i.RequiredLabels,
+		&i.TeamID,
+		&i.IgnoredChecks,
+		&i.CheckGating,
+		&i.FallbackModels,
+		&i.Paused,
+		&i.SyncDisabled,
+		&i.SyncIntervalSeconds,
+		&i.ReviewSlaSeconds,
 	)
 	return &i, err
 }
 
 const readRepoByFullName = `-- name: ReadRepoByFullName :one
-SELECT id, owner, name, full_name, created_at, summary, tech_stack, setup_status, has_code, has_claude_md, has_readme, expectations, setup_completed_at FROM repo WHERE full_name = ?
+SELECT id, owner, name, full_name, created_at, summary, tech_stack, setup_status, has_code, has_claude_md, has_readme, expectations, setup_completed_at, ci_workflow, workspace_cache_generation, protected_paths, shadow_mode, completion_validations, retry_policy, max_runtime_seconds, required_labels, team_id, ignored_checks, check_gating, fallback_models, paused, sync_disabled, sync_interval_seconds, review_sla_seconds FROM repo WHERE full_name = ?
 `
 
 func (q *Queries) ReadRepoByFullName(ctx context.Context, fullName string) (*Repo, error) {
@@ -170,10 +286,74 @@ func (q *Queries) ReadRepoByFullName(ctx context.Context, fullName string) (*Rep
 		&i.HasReadme,
 		&i.Expectations,
 		&i.SetupCompletedAt,
+		&i.CiWorkflow,
+		&i.WorkspaceCacheGeneration,
+		&i.ProtectedPaths,
+		&i.ShadowMode,
+		&i.CompletionValidations,
+		&i.RetryPolicy,
+		&i.MaxRuntimeSeconds,
+		&i.RequiredLabels,
+		&i.TeamID,
+		&i.IgnoredChecks,
+		&i.CheckGating,
+		&i.FallbackModels,
+		&i.Paused,
+		&i.SyncDisabled,
+		&i.SyncIntervalSeconds,
+		&i.ReviewSlaSeconds,
 	)
 	return &i, err
 }
 
+const updateRepoCIWorkflow = `-- name: UpdateRepoCIWorkflow :exec
+UPDATE repo
+SET ci_workflow = ?
+WHERE id = ?
+`
+
+type UpdateRepoCIWorkflowParams struct {
+	CiWorkflow string
+	ID         string
+}
+
+func (q *Queries) UpdateRepoCIWorkflow(ctx context.Context, arg UpdateRepoCIWorkflowParams) error {
+	_, err := q.db.ExecContext(ctx, updateRepoCIWorkflow, arg.CiWorkflow, arg.ID)
+	return err
+}
+
+const updateRepoCheckGating = `-- name: UpdateRepoCheckGating :exec
+UPDATE repo
+SET check_gating = ?
+WHERE id = ?
+`
+
+type UpdateRepoCheckGatingParams struct {
+	CheckGating string
+	ID          string
+}
+
+func (q *Queries) UpdateRepoCheckGating(ctx context.Context, arg UpdateRepoCheckGatingParams) error {
+	_, err := q.db.ExecContext(ctx, updateRepoCheckGating, arg.CheckGating, arg.ID)
+	return err
+}
+
+const updateRepoCompletionValidations = `-- name: UpdateRepoCompletionValidations :exec
+UPDATE repo
+SET completion_validations = ?
+WHERE id = ?
+`
+
+type UpdateRepoCompletionValidationsParams struct {
+	CompletionValidations string
+	ID                    string
+}
+
+func (q *Queries) UpdateRepoCompletionValidations(ctx context.Context, arg UpdateRepoCompletionValidationsParams) error {
+	_, err := q.db.ExecContext(ctx, updateRepoCompletionValidations, arg.CompletionValidations, arg.ID)
+	return err
+}
+
 const updateRepoExpectations = `-- name: UpdateRepoExpectations :exec
 UPDATE repo
 SET expectations = ?,
@@ -192,6 +372,134 @@ func (q *Queries) UpdateRepoExpectations(ctx context.Context, arg UpdateRepoExpe
 	return err
 }
 
+const updateRepoFallbackModels = `-- name: UpdateRepoFallbackModels :exec
+UPDATE repo
+SET fallback_models = ?
+WHERE id = ?
+`
+
+type UpdateRepoFallbackModelsParams struct {
+	FallbackModels string
+	ID             string
+}
+
+func (q *Queries) UpdateRepoFallbackModels(ctx context.Context, arg UpdateRepoFallbackModelsParams) error {
+	_, err := q.db.ExecContext(ctx, updateRepoFallbackModels, arg.FallbackModels, arg.ID)
+	return err
+}
+
+const updateRepoIgnoredChecks = `-- name: UpdateRepoIgnoredChecks :exec
+UPDATE repo
+SET ignored_checks = ?
+WHERE id = ?
+`
+
+type UpdateRepoIgnoredChecksParams struct {
+	IgnoredChecks string
+	ID            string
+}
+
+func (q *Queries) UpdateRepoIgnoredChecks(ctx context.Context, arg UpdateRepoIgnoredChecksParams) error {
+	_, err := q.db.ExecContext(ctx, updateRepoIgnoredChecks, arg.IgnoredChecks, arg.ID)
+	return err
+}
+
+const updateRepoMaxRuntime = `-- name: UpdateRepoMaxRuntime :exec
+UPDATE repo
+SET max_runtime_seconds = ?
+WHERE id = ?
+`
+
+type UpdateRepoMaxRuntimeParams struct {
+	MaxRuntimeSeconds int64
+	ID                string
+}
+
+func (q *Queries) UpdateRepoMaxRuntime(ctx context.Context, arg UpdateRepoMaxRuntimeParams) error {
+	_, err := q.db.ExecContext(ctx, updateRepoMaxRuntime, arg.MaxRuntimeSeconds, arg.ID)
+	return err
+}
+
+const updateRepoPaused = `-- name: UpdateRepoPaused :exec
+UPDATE repo
+SET paused = ?
+WHERE id = ?
+`
+
+type UpdateRepoPausedParams struct {
+	Paused int64
+	ID     string
+}
+
+func (q *Queries) UpdateRepoPaused(ctx context.Context, arg UpdateRepoPausedParams) error {
+	_, err := q.db.ExecContext(ctx, updateRepoPaused, arg.Paused, arg.ID)
+	return err
+}
+
+const updateRepoProtectedPaths = `-- name: UpdateRepoProtectedPaths :exec
+UPDATE repo
+SET protected_paths = ?
+WHERE id = ?
+`
+
+type UpdateRepoProtectedPathsParams struct {
+	ProtectedPaths string
+	ID             string
+}
+
+func (q *Queries) UpdateRepoProtectedPaths(ctx context.Context, arg UpdateRepoProtectedPathsParams) error {
+	_, err := q.db.ExecContext(ctx, updateRepoProtectedPaths, arg.ProtectedPaths, arg.ID)
+	return err
+}
+
+const updateRepoRequiredLabels = `-- name: UpdateRepoRequiredLabels :exec
+UPDATE repo
+SET required_labels = ?
+WHERE id = ?
+`
+
+type UpdateRepoRequiredLabelsParams struct {
+	RequiredLabels string
+	ID             string
+}
+
+func (q *Queries) UpdateRepoRequiredLabels(ctx context.Context, arg UpdateRepoRequiredLabelsParams) error {
+	_, err := q.db.ExecContext(ctx, updateRepoRequiredLabels, arg.RequiredLabels, arg.ID)
+	return err
+}
+
+const updateRepoRetryPolicy = `-- name: UpdateRepoRetryPolicy :exec
+UPDATE repo
+SET retry_policy = ?
+WHERE id = ?
+`
+
+type UpdateRepoRetryPolicyParams struct {
+	RetryPolicy string
+	ID          string
+}
+
+func (q *Queries) UpdateRepoRetryPolicy(ctx context.Context, arg UpdateRepoRetryPolicyParams) error {
+	_, err := q.db.ExecContext(ctx, updateRepoRetryPolicy, arg.RetryPolicy, arg.ID)
+	return err
+}
+
+const updateRepoReviewSLA = `-- name: UpdateRepoReviewSLA :exec
+UPDATE repo
+SET review_sla_seconds = ?
+WHERE id = ?
+`
+
+type UpdateRepoReviewSLAParams struct {
+	ReviewSlaSeconds int64
+	ID               string
+}
+
+func (q *Queries) UpdateRepoReviewSLA(ctx context.Context, arg UpdateRepoReviewSLAParams) error {
+	_, err := q.db.ExecContext(ctx, updateRepoReviewSLA, arg.ReviewSlaSeconds, arg.ID)
+	return err
+}
+
 const updateRepoSetupScan = `-- name: UpdateRepoSetupScan :exec
 UPDATE repo
 SET summary = ?,
@@ -242,6 +550,54 @@ func (q *Queries) UpdateRepoSetupStatus(ctx context.Context, arg UpdateRepoSetup
 	return err
 }
 
+const updateRepoShadowMode = `-- name: UpdateRepoShadowMode :exec
+UPDATE repo
+SET shadow_mode = ?
+WHERE id = ?
+`
+
+type UpdateRepoShadowModeParams struct {
+	ShadowMode int64
+	ID         string
+}
+
+func (q *Queries) UpdateRepoShadowMode(ctx context.Context, arg UpdateRepoShadowModeParams) error {
+	_, err := q.db.ExecContext(ctx, updateRepoShadowMode, arg.ShadowMode, arg.ID)
+	return err
+}
+
+const updateRepoSyncDisabled = `-- name: UpdateRepoSyncDisabled :exec
+UPDATE repo
+SET sync_disabled = ?
+WHERE id = ?
+`
+
+type UpdateRepoSyncDisabledParams struct {
+	SyncDisabled int64
+	ID           string
+}
+
+func (q *Queries) UpdateRepoSyncDisabled(ctx context.Context, arg UpdateRepoSyncDisabledParams) error {
+	_, err := q.db.ExecContext(ctx, updateRepoSyncDisabled, arg.SyncDisabled, arg.ID)
+	return err
+}
+
+const updateRepoSyncInterval = `-- name: UpdateRepoSyncInterval :exec
+UPDATE repo
+SET sync_interval_seconds = ?
+WHERE id = ?
+`
+
+type UpdateRepoSyncIntervalParams struct {
+	SyncIntervalSeconds int64
+	ID                  string
+}
+
+func (q *Queries) UpdateRepoSyncInterval(ctx context.Context, arg UpdateRepoSyncIntervalParams) error {
+	_, err := q.db.ExecContext(ctx, updateRepoSyncInterval, arg.SyncIntervalSeconds, arg.ID)
+	return err
+}
+
 const updateRepoSummary = `-- name: UpdateRepoSummary :exec
 UPDATE repo
 SET summary = ?
@@ -273,3 +629,19 @@ func (q *Queries) UpdateRepoTechStack(ctx context.Context, arg UpdateRepoTechSta
 	_, err := q.db.ExecContext(ctx, updateRepoTechStack, arg.TechStack, arg.ID)
 	return err
 }
+
+const updateRepoTeam = `-- name: UpdateRepoTeam :exec
+UPDATE repo
+SET team_id = ?
+WHERE id = ?
+`
+
+type UpdateRepoTeamParams struct {
+	TeamID *string
+	ID     string
+}
+
+func (q *Queries) UpdateRepoTeam(ctx context.Context, arg UpdateRepoTeamParams) error {
+	_, err := q.db.ExecContext(ctx, updateRepoTeam, arg.TeamID, arg.ID)
+	return err
+}
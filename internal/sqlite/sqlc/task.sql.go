@@ -9,6 +9,38 @@ import (
 	"context"
 )
 
+const abandonStaleTask = `-- name: AbandonStaleTask :execrows
+UPDATE task SET status = 'pending', started_at = NULL, updated_at = unixepoch()
+WHERE id = ? AND status = 'running'
+  AND COALESCE(last_heartbeat_at, started_at) < CAST(? AS INTEGER)
+`
+
+type AbandonStaleTaskParams struct {
+	ID     string
+	Before int64
+}
+
+func (q *Queries) AbandonStaleTask(ctx context.Context, arg AbandonStaleTaskParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, abandonStaleTask, arg.ID, arg.Before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const abandonTask = `-- name: AbandonTask :execrows
+UPDATE task SET status = 'pending', started_at = NULL, updated_at = unixepoch()
+WHERE id = ? AND status = 'running'
+`
+
+func (q *Queries) AbandonTask(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, abandonTask, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const addTaskCost = `-- name: AddTaskCost :exec
 UPDATE task SET cost_usd = cost_usd + ?, updated_at = unixepoch() WHERE id = ?
 `
@@ -38,6 +70,19 @@ func (q *Queries) AppendTaskLogs(ctx context.Context, arg AppendTaskLogsParams)
 	return err
 }
 
+const archiveTask = `-- name: ArchiveTask :execrows
+UPDATE task SET archived_at = unixepoch()
+WHERE id = ? AND status IN ('merged', 'closed') AND archived_at IS NULL AND deleted_at IS NULL
+`
+
+func (q *Queries) ArchiveTask(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, archiveTask, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const assignTaskNumber = `-- name: AssignTaskNumber :one
 UPDATE task SET number = (SELECT COALESCE(MAX(t2.number), 0) + 1 FROM task t2 WHERE t2.repo_id = ?1) WHERE task.id = ?2 RETURNING number
 `
@@ -88,8 +133,8 @@ func (q *Queries) BulkDeleteTasksByEpic(ctx context.Context, epicID *string) err
 }
 
 const claimTask = `-- name: ClaimTask :execrows
-UPDATE task SET status = 'running', started_at = unixepoch(), updated_at = unixepoch()
-WHERE id = ? AND status = 'pending' AND ready = 1
+UPDATE task SET status = 'running', started_at = unixepoch(), not_before = NULL, updated_at = unixepoch()
+WHERE id = ? AND status = 'pending' AND ready = 1 AND (not_before IS NULL OR not_before <= unixepoch()) AND deleted_at IS NULL
 `
 
 func (q *Queries) ClaimTask(ctx context.Context, id string) (int64, error) {
@@ -126,8 +171,8 @@ func (q *Queries) CloseTask(ctx context.Context, arg CloseTaskParams) error {
 }
 
 const createTask = `-- name: CreateTask :exec
-INSERT INTO task (id, repo_id, type, title, description, status, depends_on, attempt, max_attempts, acceptance_criteria_list, max_cost_usd, skip_pr, draft_pr, model, ready, epic_id, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+INSERT INTO task (id, repo_id, type, title, description, status, depends_on, attempt, max_attempts, acceptance_criteria_list, max_cost_usd, max_runtime_seconds, required_labels, fallback_models, skip_pr, draft_pr, model, ready, epic_id, additional_repo_ids, stack_parent_id, plan_only, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type CreateTaskParams struct {
@@ -142,11 +187,17 @@ type CreateTaskParams struct {
 	MaxAttempts            int64
 	AcceptanceCriteriaList string
 	MaxCostUsd             *float64
+	MaxRuntimeSeconds      int64
+	RequiredLabels         string
+	FallbackModels         string
 	SkipPr                 int64
 	DraftPr                int64
 	Model                  *string
 	Ready                  int64
 	EpicID                 *string
+	AdditionalRepoIds      string
+	StackParentID          *string
+	PlanOnly               int64
 	CreatedAt              int64
 	UpdatedAt              int64
 }
@@ -164,11 +215,17 @@ func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) error {
 		arg.MaxAttempts,
 		arg.AcceptanceCriteriaList,
 		arg.MaxCostUsd,
+		arg.MaxRuntimeSeconds,
+		arg.RequiredLabels,
+		arg.FallbackModels,
 		arg.SkipPr,
 		arg.DraftPr,
 		arg.Model,
 		arg.Ready,
 		arg.EpicID,
+		arg.AdditionalRepoIds,
+		arg.StackParentID,
+		arg.PlanOnly,
 		arg.CreatedAt,
 		arg.UpdatedAt,
 	)
@@ -176,11 +233,18 @@ func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) error {
 }
 
 const deleteExpiredLogs = `-- name: DeleteExpiredLogs :execrows
-DELETE FROM task_log WHERE created_at < ?
+DELETE FROM task_log WHERE id IN (
+  SELECT id FROM task_log WHERE created_at < ? ORDER BY id LIMIT ?
+)
 `
 
-func (q *Queries) DeleteExpiredLogs(ctx context.Context, createdAt int64) (int64, error) {
-	result, err := q.db.ExecContext(ctx, deleteExpiredLogs, createdAt)
+type DeleteExpiredLogsParams struct {
+	CreatedAt int64
+	Limit     int64
+}
+
+func (q *Queries) DeleteExpiredLogs(ctx context.Context, arg DeleteExpiredLogsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredLogs, arg.CreatedAt, arg.Limit)
 	if err != nil {
 		return 0, err
 	}
@@ -228,7 +292,7 @@ func (q *Queries) FeedbackRetryTask(ctx context.Context, arg FeedbackRetryTaskPa
 }
 
 const hasTasksForRepo = `-- name: HasTasksForRepo :one
-SELECT EXISTS(SELECT 1 FROM task WHERE repo_id = ?)
+SELECT EXISTS(SELECT 1 FROM task WHERE repo_id = ? AND deleted_at IS NULL)
 `
 
 func (q *Queries) HasTasksForRepo(ctx context.Context, repoID string) (int64, error) {
@@ -239,7 +303,7 @@ func (q *Queries) HasTasksForRepo(ctx context.Context, repoID string) (int64, er
 }
 
 const heartbeat = `-- name: Heartbeat :execrows
-UPDATE task SET last_heartbeat_at = unixepoch() WHERE id = ? AND status = 'running'
+UPDATE task SET last_heartbeat_at = unixepoch() WHERE id = ? AND status = 'running' AND deleted_at IS NULL
 `
 
 func (q *Queries) Heartbeat(ctx context.Context, id string) (int64, error) {
@@ -250,8 +314,291 @@ func (q *Queries) Heartbeat(ctx context.Context, id string) (int64, error) {
 	return result.RowsAffected()
 }
 
+const listArchivableTaskIDs = `-- name: ListArchivableTaskIDs :many
+SELECT id FROM task
+WHERE type = 'task' AND status IN ('merged', 'closed') AND archived_at IS NULL AND deleted_at IS NULL
+  AND updated_at < CAST(?1 AS INTEGER)
+ORDER BY updated_at
+LIMIT ?2
+`
+
+type ListArchivableTaskIDsParams struct {
+	Before   int64
+	MaxTasks int64
+}
+
+func (q *Queries) ListArchivableTaskIDs(ctx context.Context, arg ListArchivableTaskIDsParams) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listArchivableTaskIDs, arg.Before, arg.MaxTasks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listArchivedTasksByRepo = `-- name: ListArchivedTasksByRepo :many
+SELECT id, repo_id, title, description, status, pull_request_url, pr_number, depends_on, close_reason, attempt, max_attempts, retry_reason, acceptance_criteria_list, agent_status, retry_context, consecutive_failures, cost_usd, max_cost_usd, skip_pr, draft_pr, branch_name, model, started_at, ready, last_heartbeat_at, epic_id, created_at, updated_at, type, number, last_review_id, additional_repo_ids, pull_requests, not_before, failure_category, max_runtime_seconds, required_labels, deleted_at, archived_at, stack_parent_id, plan_only, fallback_models, active_model, version FROM task WHERE repo_id = ? AND type = 'task' AND archived_at IS NOT NULL AND deleted_at IS NULL ORDER BY created_at DESC
+`
+
+func (q *Queries) ListArchivedTasksByRepo(ctx context.Context, repoID string) ([]*Task, error) {
+	rows, err := q.db.QueryContext(ctx, listArchivedTasksByRepo, repoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Task
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.RepoID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.PullRequestUrl,
+			&i.PrNumber,
+			&i.DependsOn,
+			&i.CloseReason,
+			&i.Attempt,
+			&i.MaxAttempts,
+			&i.RetryReason,
+			&i.AcceptanceCriteriaList,
+			&i.AgentStatus,
+			&i.RetryContext,
+			&i.ConsecutiveFailures,
+			&i.CostUsd,
+			&i.MaxCostUsd,
+			&i.SkipPr,
+			&i.DraftPr,
+			&i.BranchName,
+			&i.Model,
+			&i.StartedAt,
+			&i.Ready,
+			&i.LastHeartbeatAt,
+			&i.EpicID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Type,
+			&i.Number,
+			&i.LastReviewID,
+			&i.AdditionalRepoIds,
+			&i.PullRequests,
+			&i.NotBefore,
+			&i.FailureCategory,
+			&i.MaxRuntimeSeconds,
+			&i.RequiredLabels,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.StackParentID,
+			&i.PlanOnly,
+			&i.FallbackModels,
+			&i.ActiveModel,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFailedTasksByRepo = `-- name: ListFailedTasksByRepo :many
+SELECT id, repo_id, title, description, status, pull_request_url, pr_number, depends_on, close_reason, attempt, max_attempts, retry_reason, acceptance_criteria_list, agent_status, retry_context, consecutive_failures, cost_usd, max_cost_usd, skip_pr, draft_pr, branch_name, model, started_at, ready, last_heartbeat_at, epic_id, created_at, updated_at, type, number, last_review_id, additional_repo_ids, pull_requests, not_before, failure_category, max_runtime_seconds, required_labels, deleted_at, archived_at, stack_parent_id, plan_only, fallback_models, active_model, version FROM task WHERE repo_id = ? AND status = 'failed' AND deleted_at IS NULL ORDER BY updated_at DESC
+`
+
+func (q *Queries) ListFailedTasksByRepo(ctx context.Context, repoID string) ([]*Task, error) {
+	rows, err := q.db.QueryContext(ctx, listFailedTasksByRepo, repoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Task
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.RepoID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.PullRequestUrl,
+			&i.PrNumber,
+			&i.DependsOn,
+			&i.CloseReason,
+			&i.Attempt,
+			&i.MaxAttempts,
+			&i.RetryReason,
+			&i.AcceptanceCriteriaList,
+			&i.AgentStatus,
+			&i.RetryContext,
+			&i.ConsecutiveFailures,
+			&i.CostUsd,
+			&i.MaxCostUsd,
+			&i.SkipPr,
+			&i.DraftPr,
+			&i.BranchName,
+			&i.Model,
+			&i.StartedAt,
+			&i.Ready,
+			&i.LastHeartbeatAt,
+			&i.EpicID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Type,
+			&i.Number,
+			&i.LastReviewID,
+			&i.AdditionalRepoIds,
+			&i.PullRequests,
+			&i.NotBefore,
+			&i.FailureCategory,
+			&i.MaxRuntimeSeconds,
+			&i.RequiredLabels,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.StackParentID,
+			&i.PlanOnly,
+			&i.FallbackModels,
+			&i.ActiveModel,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOverrunTasks = `-- name: ListOverrunTasks :many
+SELECT task.id, task.repo_id, task.title, task.description, task.status, task.pull_request_url, task.pr_number, task.depends_on, task.close_reason, task.attempt, task.max_attempts, task.retry_reason, task.acceptance_criteria_list, task.agent_status, task.retry_context, task.consecutive_failures, task.cost_usd, task.max_cost_usd, task.skip_pr, task.draft_pr, task.branch_name, task.model, task.started_at, task.ready, task.last_heartbeat_at, task.epic_id, task.created_at, task.updated_at, task.type, task.number, task.last_review_id, task.additional_repo_ids, task.pull_requests, task.not_before, task.failure_category, task.max_runtime_seconds, task.required_labels, task.deleted_at, task.archived_at, task.stack_parent_id, task.plan_only, task.fallback_models, task.active_model, task.version FROM task JOIN repo ON repo.id = task.repo_id
+WHERE task.status = 'running' AND task.started_at IS NOT NULL AND task.deleted_at IS NULL
+  AND COALESCE(NULLIF(task.max_runtime_seconds, 0), repo.max_runtime_seconds) > 0
+  AND task.started_at + COALESCE(NULLIF(task.max_runtime_seconds, 0), repo.max_runtime_seconds) < CAST(?1 AS INTEGER)
+ORDER BY task.started_at
+`
+
+func (q *Queries) ListOverrunTasks(ctx context.Context, now int64) ([]*Task, error) {
+	rows, err := q.db.QueryContext(ctx, listOverrunTasks, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Task
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.RepoID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.PullRequestUrl,
+			&i.PrNumber,
+			&i.DependsOn,
+			&i.CloseReason,
+			&i.Attempt,
+			&i.MaxAttempts,
+			&i.RetryReason,
+			&i.AcceptanceCriteriaList,
+			&i.AgentStatus,
+			&i.RetryContext,
+			&i.ConsecutiveFailures,
+			&i.CostUsd,
+			&i.MaxCostUsd,
+			&i.SkipPr,
+			&i.DraftPr,
+			&i.BranchName,
+			&i.Model,
+			&i.StartedAt,
+			&i.Ready,
+			&i.LastHeartbeatAt,
+			&i.EpicID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Type,
+			&i.Number,
+			&i.LastReviewID,
+			&i.AdditionalRepoIds,
+			&i.PullRequests,
+			&i.NotBefore,
+			&i.FailureCategory,
+			&i.MaxRuntimeSeconds,
+			&i.RequiredLabels,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.StackParentID,
+			&i.PlanOnly,
+			&i.FallbackModels,
+			&i.ActiveModel,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPausedRepoIDs = `-- name: ListPausedRepoIDs :many
+SELECT id FROM repo WHERE paused = 1
+`
+
+func (q *Queries) ListPausedRepoIDs(ctx context.Context) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listPausedRepoIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPendingTasks = `-- name: ListPendingTasks :many
-SELECT id, repo_id, title, description, status, pull_request_url, pr_number, depends_on, close_reason, attempt, max_attempts, retry_reason, acceptance_criteria_list, agent_status, retry_context, consecutive_failures, cost_usd, max_cost_usd, skip_pr, draft_pr, branch_name, model, started_at, ready, last_heartbeat_at, epic_id, created_at, updated_at, type, number FROM task WHERE status = 'pending' AND ready = 1 ORDER BY created_at ASC
+SELECT id, repo_id, title, description, status, pull_request_url, pr_number, depends_on, close_reason, attempt, max_attempts, retry_reason, acceptance_criteria_list, agent_status, retry_context, consecutive_failures, cost_usd, max_cost_usd, skip_pr, draft_pr, branch_name, model, started_at, ready, last_heartbeat_at, epic_id, created_at, updated_at, type, number, last_review_id, additional_repo_ids, pull_requests, not_before, failure_category, max_runtime_seconds, required_labels, deleted_at, archived_at, stack_parent_id, plan_only, fallback_models, active_model, version FROM task WHERE status = 'pending' AND ready = 1 AND (not_before IS NULL OR not_before <= unixepoch()) AND deleted_at IS NULL ORDER BY created_at ASC
 `
 
 func (q *Queries) ListPendingTasks(ctx context.Context) ([]*Task, error) {
@@ -294,6 +641,20 @@ func (q *Queries) ListPendingTasks(ctx context.Context) ([]*Task, error) {
 			&i.UpdatedAt,
 			&i.Type,
 			&i.Number,
+			&i.LastReviewID,
+			&i.AdditionalRepoIds,
+			&i.PullRequests,
+			&i.NotBefore,
+			&i.FailureCategory,
+			&i.MaxRuntimeSeconds,
+			&i.RequiredLabels,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.StackParentID,
+			&i.PlanOnly,
+			&i.FallbackModels,
+			&i.ActiveModel,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -308,8 +669,40 @@ func (q *Queries) ListPendingTasks(ctx context.Context) ([]*Task, error) {
 	return items, nil
 }
 
+const listRepoRequiredLabels = `-- name: ListRepoRequiredLabels :many
+SELECT id, required_labels FROM repo WHERE required_labels != '[]'
+`
+
+type ListRepoRequiredLabelsRow struct {
+	ID             string
+	RequiredLabels string
+}
+
+func (q *Queries) ListRepoRequiredLabels(ctx context.Context) ([]*ListRepoRequiredLabelsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listRepoRequiredLabels)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListRepoRequiredLabelsRow
+	for rows.Next() {
+		var i ListRepoRequiredLabelsRow
+		if err := rows.Scan(&i.ID, &i.RequiredLabels); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listStaleTasks = `-- name: ListStaleTasks :many
-SELECT id, repo_id, title, description, status, pull_request_url, pr_number, depends_on, close_reason, attempt, max_attempts, retry_reason, acceptance_criteria_list, agent_status, retry_context, consecutive_failures, cost_usd, max_cost_usd, skip_pr, draft_pr, branch_name, model, started_at, ready, last_heartbeat_at, epic_id, created_at, updated_at, type, number FROM task WHERE status = 'running' AND last_heartbeat_at IS NOT NULL AND last_heartbeat_at < ? ORDER BY started_at
+SELECT id, repo_id, title, description, status, pull_request_url, pr_number, depends_on, close_reason, attempt, max_attempts, retry_reason, acceptance_criteria_list, agent_status, retry_context, consecutive_failures, cost_usd, max_cost_usd, skip_pr, draft_pr, branch_name, model, started_at, ready, last_heartbeat_at, epic_id, created_at, updated_at, type, number, last_review_id, additional_repo_ids, pull_requests, not_before, failure_category, max_runtime_seconds, required_labels, deleted_at, archived_at, stack_parent_id, plan_only, fallback_models, active_model, version FROM task WHERE status = 'running' AND last_heartbeat_at IS NOT NULL AND last_heartbeat_at < ? AND deleted_at IS NULL ORDER BY started_at
 `
 
 func (q *Queries) ListStaleTasks(ctx context.Context, lastHeartbeatAt *int64) ([]*Task, error) {
@@ -352,6 +745,20 @@ func (q *Queries) ListStaleTasks(ctx context.Context, lastHeartbeatAt *int64) ([
 			&i.UpdatedAt,
 			&i.Type,
 			&i.Number,
+			&i.LastReviewID,
+			&i.AdditionalRepoIds,
+			&i.PullRequests,
+			&i.NotBefore,
+			&i.FailureCategory,
+			&i.MaxRuntimeSeconds,
+			&i.RequiredLabels,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.StackParentID,
+			&i.PlanOnly,
+			&i.FallbackModels,
+			&i.ActiveModel,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -367,7 +774,7 @@ func (q *Queries) ListStaleTasks(ctx context.Context, lastHeartbeatAt *int64) ([
 }
 
 const listTasks = `-- name: ListTasks :many
-SELECT id, repo_id, title, description, status, pull_request_url, pr_number, depends_on, close_reason, attempt, max_attempts, retry_reason, acceptance_criteria_list, agent_status, retry_context, consecutive_failures, cost_usd, max_cost_usd, skip_pr, draft_pr, branch_name, model, started_at, ready, last_heartbeat_at, epic_id, created_at, updated_at, type, number FROM task WHERE type = 'task' ORDER BY created_at DESC
+SELECT id, repo_id, title, description, status, pull_request_url, pr_number, depends_on, close_reason, attempt, max_attempts, retry_reason, acceptance_criteria_list, agent_status, retry_context, consecutive_failures, cost_usd, max_cost_usd, skip_pr, draft_pr, branch_name, model, started_at, ready, last_heartbeat_at, epic_id, created_at, updated_at, type, number, last_review_id, additional_repo_ids, pull_requests, not_before, failure_category, max_runtime_seconds, required_labels, deleted_at, archived_at, stack_parent_id, plan_only, fallback_models, active_model, version FROM task WHERE type = 'task' AND deleted_at IS NULL AND archived_at IS NULL ORDER BY created_at DESC
 `
 
 func (q *Queries) ListTasks(ctx context.Context) ([]*Task, error) {
@@ -410,6 +817,20 @@ func (q *Queries) ListTasks(ctx context.Context) ([]*Task, error) {
 			&i.UpdatedAt,
 			&i.Type,
 			&i.Number,
+			&i.LastReviewID,
+			&i.AdditionalRepoIds,
+			&i.PullRequests,
+			&i.NotBefore,
+			&i.FailureCategory,
+			&i.MaxRuntimeSeconds,
+			&i.RequiredLabels,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.StackParentID,
+			&i.PlanOnly,
+			&i.FallbackModels,
+			&i.ActiveModel,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -425,7 +846,7 @@ func (q *Queries) ListTasks(ctx context.Context) ([]*Task, error) {
 }
 
 const listTasksByEpic = `-- name: ListTasksByEpic :many
-SELECT id, repo_id, title, description, status, pull_request_url, pr_number, depends_on, close_reason, attempt, max_attempts, retry_reason, acceptance_criteria_list, agent_status, retry_context, consecutive_failures, cost_usd, max_cost_usd, skip_pr, draft_pr, branch_name, model, started_at, ready, last_heartbeat_at, epic_id, created_at, updated_at, type, number FROM task WHERE epic_id = ? ORDER BY created_at ASC
+SELECT id, repo_id, title, description, status, pull_request_url, pr_number, depends_on, close_reason, attempt, max_attempts, retry_reason, acceptance_criteria_list, agent_status, retry_context, consecutive_failures, cost_usd, max_cost_usd, skip_pr, draft_pr, branch_name, model, started_at, ready, last_heartbeat_at, epic_id, created_at, updated_at, type, number, last_review_id, additional_repo_ids, pull_requests, not_before, failure_category, max_runtime_seconds, required_labels, deleted_at, archived_at, stack_parent_id, plan_only, fallback_models, active_model, version FROM task WHERE epic_id = ? AND deleted_at IS NULL ORDER BY created_at ASC
 `
 
 func (q *Queries) ListTasksByEpic(ctx context.Context, epicID *string) ([]*Task, error) {
@@ -468,6 +889,20 @@ func (q *Queries) ListTasksByEpic(ctx context.Context, epicID *string) ([]*Task,
 			&i.UpdatedAt,
 			&i.Type,
 			&i.Number,
+			&i.LastReviewID,
+			&i.AdditionalRepoIds,
+			&i.PullRequests,
+			&i.NotBefore,
+			&i.FailureCategory,
+			&i.MaxRuntimeSeconds,
+			&i.RequiredLabels,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.StackParentID,
+			&i.PlanOnly,
+			&i.FallbackModels,
+			&i.ActiveModel,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -483,7 +918,7 @@ func (q *Queries) ListTasksByEpic(ctx context.Context, epicID *string) ([]*Task,
 }
 
 const listTasksByRepo = `-- name: ListTasksByRepo :many
-SELECT id, repo_id, title, description, status, pull_request_url, pr_number, depends_on, close_reason, attempt, max_attempts, retry_reason, acceptance_criteria_list, agent_status, retry_context, consecutive_failures, cost_usd, max_cost_usd, skip_pr, draft_pr, branch_name, model, started_at, ready, last_heartbeat_at, epic_id, created_at, updated_at, type, number FROM task WHERE repo_id = ? AND type = 'task' ORDER BY created_at DESC
+SELECT id, repo_id, title, description, status, pull_request_url, pr_number, depends_on, close_reason, attempt, max_attempts, retry_reason, acceptance_criteria_list, agent_status, retry_context, consecutive_failures, cost_usd, max_cost_usd, skip_pr, draft_pr, branch_name, model, started_at, ready, last_heartbeat_at, epic_id, created_at, updated_at, type, number, last_review_id, additional_repo_ids, pull_requests, not_before, failure_category, max_runtime_seconds, required_labels, deleted_at, archived_at, stack_parent_id, plan_only, fallback_models, active_model, version FROM task WHERE repo_id = ? AND type = 'task' AND deleted_at IS NULL AND archived_at IS NULL ORDER BY created_at DESC
 `
 
 func (q *Queries) ListTasksByRepo(ctx context.Context, repoID string) ([]*Task, error) {
@@ -526,6 +961,20 @@ func (q *Queries) ListTasksByRepo(ctx context.Context, repoID string) ([]*Task,
 			&i.UpdatedAt,
 			&i.Type,
 			&i.Number,
+			&i.LastReviewID,
+			&i.AdditionalRepoIds,
+			&i.PullRequests,
+			&i.NotBefore,
+			&i.FailureCategory,
+			&i.MaxRuntimeSeconds,
+			&i.RequiredLabels,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.StackParentID,
+			&i.PlanOnly,
+			&i.FallbackModels,
+			&i.ActiveModel,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -541,7 +990,7 @@ func (q *Queries) ListTasksByRepo(ctx context.Context, repoID string) ([]*Task,
 }
 
 const listTasksInReview = `-- name: ListTasksInReview :many
-SELECT id, repo_id, title, description, status, pull_request_url, pr_number, depends_on, close_reason, attempt, max_attempts, retry_reason, acceptance_criteria_list, agent_status, retry_context, consecutive_failures, cost_usd, max_cost_usd, skip_pr, draft_pr, branch_name, model, started_at, ready, last_heartbeat_at, epic_id, created_at, updated_at, type, number FROM task WHERE status = 'review'
+SELECT id, repo_id, title, description, status, pull_request_url, pr_number, depends_on, close_reason, attempt, max_attempts, retry_reason, acceptance_criteria_list, agent_status, retry_context, consecutive_failures, cost_usd, max_cost_usd, skip_pr, draft_pr, branch_name, model, started_at, ready, last_heartbeat_at, epic_id, created_at, updated_at, type, number, last_review_id, additional_repo_ids, pull_requests, not_before, failure_category, max_runtime_seconds, required_labels, deleted_at, archived_at, stack_parent_id, plan_only, fallback_models, active_model, version FROM task WHERE status = 'review' AND deleted_at IS NULL
 `
 
 func (q *Queries) ListTasksInReview(ctx context.Context) ([]*Task, error) {
@@ -584,6 +1033,20 @@ func (q *Queries) ListTasksInReview(ctx context.Context) ([]*Task, error) {
 			&i.UpdatedAt,
 			&i.Type,
 			&i.Number,
+			&i.LastReviewID,
+			&i.AdditionalRepoIds,
+			&i.PullRequests,
+			&i.NotBefore,
+			&i.FailureCategory,
+			&i.MaxRuntimeSeconds,
+			&i.RequiredLabels,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.StackParentID,
+			&i.PlanOnly,
+			&i.FallbackModels,
+			&i.ActiveModel,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -599,7 +1062,7 @@ func (q *Queries) ListTasksInReview(ctx context.Context) ([]*Task, error) {
 }
 
 const listTasksInReviewByRepo = `-- name: ListTasksInReviewByRepo :many
-SELECT id, repo_id, title, description, status, pull_request_url, pr_number, depends_on, close_reason, attempt, max_attempts, retry_reason, acceptance_criteria_list, agent_status, retry_context, consecutive_failures, cost_usd, max_cost_usd, skip_pr, draft_pr, branch_name, model, started_at, ready, last_heartbeat_at, epic_id, created_at, updated_at, type, number FROM task WHERE repo_id = ? AND status = 'review'
+SELECT id, repo_id, title, description, status, pull_request_url, pr_number, depends_on, close_reason, attempt, max_attempts, retry_reason, acceptance_criteria_list, agent_status, retry_context, consecutive_failures, cost_usd, max_cost_usd, skip_pr, draft_pr, branch_name, model, started_at, ready, last_heartbeat_at, epic_id, created_at, updated_at, type, number, last_review_id, additional_repo_ids, pull_requests, not_before, failure_category, max_runtime_seconds, required_labels, deleted_at, archived_at, stack_parent_id, plan_only, fallback_models, active_model, version FROM task WHERE repo_id = ? AND status = 'review' AND deleted_at IS NULL
 `
 
 func (q *Queries) ListTasksInReviewByRepo(ctx context.Context, repoID string) ([]*Task, error) {
@@ -642,6 +1105,20 @@ func (q *Queries) ListTasksInReviewByRepo(ctx context.Context, repoID string) ([
 			&i.UpdatedAt,
 			&i.Type,
 			&i.Number,
+			&i.LastReviewID,
+			&i.AdditionalRepoIds,
+			&i.PullRequests,
+			&i.NotBefore,
+			&i.FailureCategory,
+			&i.MaxRuntimeSeconds,
+			&i.RequiredLabels,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.StackParentID,
+			&i.PlanOnly,
+			&i.FallbackModels,
+			&i.ActiveModel,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -657,7 +1134,7 @@ func (q *Queries) ListTasksInReviewByRepo(ctx context.Context, repoID string) ([
 }
 
 const listTasksInReviewNoPR = `-- name: ListTasksInReviewNoPR :many
-SELECT id, repo_id, title, description, status, pull_request_url, pr_number, depends_on, close_reason, attempt, max_attempts, retry_reason, acceptance_criteria_list, agent_status, retry_context, consecutive_failures, cost_usd, max_cost_usd, skip_pr, draft_pr, branch_name, model, started_at, ready, last_heartbeat_at, epic_id, created_at, updated_at, type, number FROM task WHERE status = 'review' AND branch_name IS NOT NULL AND pr_number IS NULL
+SELECT id, repo_id, title, description, status, pull_request_url, pr_number, depends_on, close_reason, attempt, max_attempts, retry_reason, acceptance_criteria_list, agent_status, retry_context, consecutive_failures, cost_usd, max_cost_usd, skip_pr, draft_pr, branch_name, model, started_at, ready, last_heartbeat_at, epic_id, created_at, updated_at, type, number, last_review_id, additional_repo_ids, pull_requests, not_before, failure_category, max_runtime_seconds, required_labels, deleted_at, archived_at, stack_parent_id, plan_only, fallback_models, active_model, version FROM task WHERE status = 'review' AND branch_name IS NOT NULL AND pr_number IS NULL AND deleted_at IS NULL
 `
 
 func (q *Queries) ListTasksInReviewNoPR(ctx context.Context) ([]*Task, error) {
@@ -700,6 +1177,119 @@ func (q *Queries) ListTasksInReviewNoPR(ctx context.Context) ([]*Task, error) {
 			&i.UpdatedAt,
 			&i.Type,
 			&i.Number,
+			&i.LastReviewID,
+			&i.AdditionalRepoIds,
+			&i.PullRequests,
+			&i.NotBefore,
+			&i.FailureCategory,
+			&i.MaxRuntimeSeconds,
+			&i.RequiredLabels,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.StackParentID,
+			&i.PlanOnly,
+			&i.FallbackModels,
+			&i.ActiveModel,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrashedTaskIDsBefore = `-- name: ListTrashedTaskIDsBefore :many
+SELECT id FROM task WHERE deleted_at IS NOT NULL AND deleted_at < CAST(?1 AS INTEGER)
+`
+
+func (q *Queries) ListTrashedTaskIDsBefore(ctx context.Context, before int64) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listTrashedTaskIDsBefore, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrashedTasksByRepo = `-- name: ListTrashedTasksByRepo :many
+SELECT id, repo_id, title, description, status, pull_request_url, pr_number, depends_on, close_reason, attempt, max_attempts, retry_reason, acceptance_criteria_list, agent_status, retry_context, consecutive_failures, cost_usd, max_cost_usd, skip_pr, draft_pr, branch_name, model, started_at, ready, last_heartbeat_at, epic_id, created_at, updated_at, type, number, last_review_id, additional_repo_ids, pull_requests, not_before, failure_category, max_runtime_seconds, required_labels, deleted_at, archived_at, stack_parent_id, plan_only, fallback_models, active_model, version FROM task WHERE repo_id = ? AND deleted_at IS NOT NULL ORDER BY deleted_at DESC
+`
+
+func (q *Queries) ListTrashedTasksByRepo(ctx context.Context, repoID string) ([]*Task, error) {
+	rows, err := q.db.QueryContext(ctx, listTrashedTasksByRepo, repoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Task
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.RepoID,
+			&i.Title,
+			&i.Description,
+			&i.Status,
+			&i.PullRequestUrl,
+			&i.PrNumber,
+			&i.DependsOn,
+			&i.CloseReason,
+			&i.Attempt,
+			&i.MaxAttempts,
+			&i.RetryReason,
+			&i.AcceptanceCriteriaList,
+			&i.AgentStatus,
+			&i.RetryContext,
+			&i.ConsecutiveFailures,
+			&i.CostUsd,
+			&i.MaxCostUsd,
+			&i.SkipPr,
+			&i.DraftPr,
+			&i.BranchName,
+			&i.Model,
+			&i.StartedAt,
+			&i.Ready,
+			&i.LastHeartbeatAt,
+			&i.EpicID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Type,
+			&i.Number,
+			&i.LastReviewID,
+			&i.AdditionalRepoIds,
+			&i.PullRequests,
+			&i.NotBefore,
+			&i.FailureCategory,
+			&i.MaxRuntimeSeconds,
+			&i.RequiredLabels,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+			&i.StackParentID,
+			&i.PlanOnly,
+			&i.FallbackModels,
+			&i.ActiveModel,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -717,7 +1307,7 @@ func (q *Queries) ListTasksInReviewNoPR(ctx context.Context) ([]*Task, error) {
 const manualRetryTask = `-- name: ManualRetryTask :execrows
 UPDATE task SET status = 'pending', attempt = attempt + 1,
   retry_reason = ?, retry_context = NULL,
-  close_reason = NULL, consecutive_failures = 0,
+  close_reason = NULL, failure_category = NULL, consecutive_failures = 0,
   started_at = NULL, updated_at = unixepoch()
 WHERE id = ? AND status = 'failed'
 `
@@ -735,8 +1325,50 @@ func (q *Queries) ManualRetryTask(ctx context.Context, arg ManualRetryTaskParams
 	return result.RowsAffected()
 }
 
+const moveTask = `-- name: MoveTask :execrows
+UPDATE task SET
+  repo_id = ?1,
+  number = CASE WHEN number IS NULL THEN NULL ELSE (SELECT COALESCE(MAX(t2.number), 0) + 1 FROM task t2 WHERE t2.repo_id = ?1) END,
+  depends_on = ?2,
+  additional_repo_ids = ?3,
+  max_attempts = ?4,
+  epic_id = NULL,
+  stack_parent_id = NULL,
+  pull_request_url = NULL,
+  pr_number = NULL,
+  branch_name = NULL,
+  pull_requests = '[]',
+  updated_at = unixepoch()
+WHERE id = ?5 AND status = 'pending'
+  AND (CAST(?6 AS INTEGER) = 0 OR version = CAST(?6 AS INTEGER))
+`
+
+type MoveTaskParams struct {
+	RepoID            string
+	DependsOn         string
+	AdditionalRepoIds string
+	MaxAttempts       int64
+	ID                string
+	ExpectedVersion   int64
+}
+
+func (q *Queries) MoveTask(ctx context.Context, arg MoveTaskParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, moveTask,
+		arg.RepoID,
+		arg.DependsOn,
+		arg.AdditionalRepoIds,
+		arg.MaxAttempts,
+		arg.ID,
+		arg.ExpectedVersion,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const readTask = `-- name: ReadTask :one
-SELECT id, repo_id, title, description, status, pull_request_url, pr_number, depends_on, close_reason, attempt, max_attempts, retry_reason, acceptance_criteria_list, agent_status, retry_context, consecutive_failures, cost_usd, max_cost_usd, skip_pr, draft_pr, branch_name, model, started_at, ready, last_heartbeat_at, epic_id, created_at, updated_at, type, number FROM task WHERE id = ?
+SELECT id, repo_id, title, description, status, pull_request_url, pr_number, depends_on, close_reason, attempt, max_attempts, retry_reason, acceptance_criteria_list, agent_status, retry_context, consecutive_failures, cost_usd, max_cost_usd, skip_pr, draft_pr, branch_name, model, started_at, ready, last_heartbeat_at, epic_id, created_at, updated_at, type, number, last_review_id, additional_repo_ids, pull_requests, not_before, failure_category, max_runtime_seconds, required_labels, deleted_at, archived_at, stack_parent_id, plan_only, fallback_models, active_model, version FROM task WHERE id = ? AND deleted_at IS NULL
 `
 
 func (q *Queries) ReadTask(ctx context.Context, id string) (*Task, error) {
@@ -773,12 +1405,26 @@ func (q *Queries) ReadTask(ctx context.Context, id string) (*Task, error) {
 		&i.UpdatedAt,
 		&i.Type,
 		&i.Number,
+		&i.LastReviewID,
+		&i.AdditionalRepoIds,
+		&i.PullRequests,
+		&i.NotBefore,
+		&i.FailureCategory,
+		&i.MaxRuntimeSeconds,
+		&i.RequiredLabels,
+		&i.DeletedAt,
+		&i.ArchivedAt,
+		&i.StackParentID,
+		&i.PlanOnly,
+		&i.FallbackModels,
+		&i.ActiveModel,
+		&i.Version,
 	)
 	return &i, err
 }
 
 const readTaskByNumber = `-- name: ReadTaskByNumber :one
-SELECT id, repo_id, title, description, status, pull_request_url, pr_number, depends_on, close_reason, attempt, max_attempts, retry_reason, acceptance_criteria_list, agent_status, retry_context, consecutive_failures, cost_usd, max_cost_usd, skip_pr, draft_pr, branch_name, model, started_at, ready, last_heartbeat_at, epic_id, created_at, updated_at, type, number FROM task WHERE repo_id = ? AND number = ?
+SELECT id, repo_id, title, description, status, pull_request_url, pr_number, depends_on, close_reason, attempt, max_attempts, retry_reason, acceptance_criteria_list, agent_status, retry_context, consecutive_failures, cost_usd, max_cost_usd, skip_pr, draft_pr, branch_name, model, started_at, ready, last_heartbeat_at, epic_id, created_at, updated_at, type, number, last_review_id, additional_repo_ids, pull_requests, not_before, failure_category, max_runtime_seconds, required_labels, deleted_at, archived_at, stack_parent_id, plan_only, fallback_models, active_model, version FROM task WHERE repo_id = ? AND number = ? AND deleted_at IS NULL
 `
 
 type ReadTaskByNumberParams struct {
@@ -820,6 +1466,20 @@ func (q *Queries) ReadTaskByNumber(ctx context.Context, arg ReadTaskByNumberPara
 		&i.UpdatedAt,
 		&i.Type,
 		&i.Number,
+		&i.LastReviewID,
+		&i.AdditionalRepoIds,
+		&i.PullRequests,
+		&i.NotBefore,
+		&i.FailureCategory,
+		&i.MaxRuntimeSeconds,
+		&i.RequiredLabels,
+		&i.DeletedAt,
+		&i.ArchivedAt,
+		&i.StackParentID,
+		&i.PlanOnly,
+		&i.FallbackModels,
+		&i.ActiveModel,
+		&i.Version,
 	)
 	return &i, err
 }
@@ -857,7 +1517,7 @@ func (q *Queries) ReadTaskLogs(ctx context.Context, taskID string) ([]*ReadTaskL
 }
 
 const readTaskStatus = `-- name: ReadTaskStatus :one
-SELECT status FROM task WHERE id = ?
+SELECT status FROM task WHERE id = ? AND deleted_at IS NULL
 `
 
 func (q *Queries) ReadTaskStatus(ctx context.Context, id string) (string, error) {
@@ -867,6 +1527,19 @@ func (q *Queries) ReadTaskStatus(ctx context.Context, id string) (string, error)
 	return status, err
 }
 
+const restoreTask = `-- name: RestoreTask :execrows
+UPDATE task SET deleted_at = NULL, updated_at = unixepoch()
+WHERE id = ? AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) RestoreTask(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restoreTask, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const retryTask = `-- name: RetryTask :execrows
 UPDATE task SET status = 'pending', attempt = attempt + 1, retry_reason = ?, started_at = NULL, updated_at = unixepoch()
 WHERE id = ? AND status = 'review'
@@ -886,17 +1559,18 @@ func (q *Queries) RetryTask(ctx context.Context, arg RetryTaskParams) (int64, er
 }
 
 const scheduleRetryFromRunning = `-- name: ScheduleRetryFromRunning :execrows
-UPDATE task SET status = 'pending', attempt = attempt + 1, retry_reason = ?, started_at = NULL, updated_at = unixepoch()
+UPDATE task SET status = 'pending', attempt = attempt + 1, retry_reason = ?, not_before = ?, started_at = NULL, updated_at = unixepoch()
 WHERE id = ? AND status = 'running'
 `
 
 type ScheduleRetryFromRunningParams struct {
 	RetryReason *string
+	NotBefore   *int64
 	ID          string
 }
 
 func (q *Queries) ScheduleRetryFromRunning(ctx context.Context, arg ScheduleRetryFromRunningParams) (int64, error) {
-	result, err := q.db.ExecContext(ctx, scheduleRetryFromRunning, arg.RetryReason, arg.ID)
+	result, err := q.db.ExecContext(ctx, scheduleRetryFromRunning, arg.RetryReason, arg.NotBefore, arg.ID)
 	if err != nil {
 		return 0, err
 	}
@@ -974,6 +1648,20 @@ func (q *Queries) SetDependsOn(ctx context.Context, arg SetDependsOnParams) erro
 	return err
 }
 
+const setFailureCategory = `-- name: SetFailureCategory :exec
+UPDATE task SET failure_category = ?, updated_at = unixepoch() WHERE id = ?
+`
+
+type SetFailureCategoryParams struct {
+	FailureCategory *string
+	ID              string
+}
+
+func (q *Queries) SetFailureCategory(ctx context.Context, arg SetFailureCategoryParams) error {
+	_, err := q.db.ExecContext(ctx, setFailureCategory, arg.FailureCategory, arg.ID)
+	return err
+}
+
 const setReady = `-- name: SetReady :exec
 UPDATE task SET ready = ?, updated_at = unixepoch()
 WHERE id = ?
@@ -1003,6 +1691,48 @@ func (q *Queries) SetRetryContext(ctx context.Context, arg SetRetryContextParams
 	return err
 }
 
+const setTaskActiveModel = `-- name: SetTaskActiveModel :exec
+UPDATE task SET active_model = ?, updated_at = unixepoch() WHERE id = ?
+`
+
+type SetTaskActiveModelParams struct {
+	ActiveModel string
+	ID          string
+}
+
+func (q *Queries) SetTaskActiveModel(ctx context.Context, arg SetTaskActiveModelParams) error {
+	_, err := q.db.ExecContext(ctx, setTaskActiveModel, arg.ActiveModel, arg.ID)
+	return err
+}
+
+const setTaskLastReviewID = `-- name: SetTaskLastReviewID :exec
+UPDATE task SET last_review_id = ?, updated_at = unixepoch() WHERE id = ?
+`
+
+type SetTaskLastReviewIDParams struct {
+	LastReviewID int64
+	ID           string
+}
+
+func (q *Queries) SetTaskLastReviewID(ctx context.Context, arg SetTaskLastReviewIDParams) error {
+	_, err := q.db.ExecContext(ctx, setTaskLastReviewID, arg.LastReviewID, arg.ID)
+	return err
+}
+
+const setTaskPlanOnly = `-- name: SetTaskPlanOnly :exec
+UPDATE task SET plan_only = ?, updated_at = unixepoch() WHERE id = ?
+`
+
+type SetTaskPlanOnlyParams struct {
+	PlanOnly int64
+	ID       string
+}
+
+func (q *Queries) SetTaskPlanOnly(ctx context.Context, arg SetTaskPlanOnlyParams) error {
+	_, err := q.db.ExecContext(ctx, setTaskPlanOnly, arg.PlanOnly, arg.ID)
+	return err
+}
+
 const setTaskPullRequest = `-- name: SetTaskPullRequest :exec
 UPDATE task SET pull_request_url = ?, pr_number = ?, status = 'review', updated_at = unixepoch()
 WHERE id = ?
@@ -1019,33 +1749,55 @@ func (q *Queries) SetTaskPullRequest(ctx context.Context, arg SetTaskPullRequest
 	return err
 }
 
+const setTaskPullRequests = `-- name: SetTaskPullRequests :exec
+UPDATE task SET pull_requests = ?, updated_at = unixepoch()
+WHERE id = ?
+`
+
+type SetTaskPullRequestsParams struct {
+	PullRequests string
+	ID           string
+}
+
+func (q *Queries) SetTaskPullRequests(ctx context.Context, arg SetTaskPullRequestsParams) error {
+	_, err := q.db.ExecContext(ctx, setTaskPullRequests, arg.PullRequests, arg.ID)
+	return err
+}
+
 const startOverTask = `-- name: StartOverTask :execrows
 UPDATE task SET
   status = 'pending',
-  title = ?,
-  description = ?,
-  acceptance_criteria_list = ?,
+  title = ?1,
+  description = ?2,
+  acceptance_criteria_list = ?3,
   attempt = 1,
-  max_attempts = 5,
+  max_attempts = ?4,
   retry_reason = NULL,
   retry_context = NULL,
   close_reason = NULL,
+  failure_category = NULL,
   agent_status = NULL,
   consecutive_failures = 0,
   cost_usd = 0,
   pull_request_url = NULL,
   pr_number = NULL,
   branch_name = NULL,
+  pull_requests = '[]',
   started_at = NULL,
+  active_model = '',
+  archived_at = NULL,
   updated_at = unixepoch()
-WHERE id = ? AND status IN ('review', 'failed', 'closed')
+WHERE id = ?5 AND status IN ('review', 'failed', 'closed')
+  AND (CAST(?6 AS INTEGER) = 0 OR version = CAST(?6 AS INTEGER))
 `
 
 type StartOverTaskParams struct {
 	Title                  string
 	Description            string
 	AcceptanceCriteriaList string
+	MaxAttempts            int64
 	ID                     string
+	ExpectedVersion        int64
 }
 
 func (q *Queries) StartOverTask(ctx context.Context, arg StartOverTaskParams) (int64, error) {
@@ -1053,7 +1805,9 @@ func (q *Queries) StartOverTask(ctx context.Context, arg StartOverTaskParams) (i
 		arg.Title,
 		arg.Description,
 		arg.AcceptanceCriteriaList,
+		arg.MaxAttempts,
 		arg.ID,
+		arg.ExpectedVersion,
 	)
 	if err != nil {
 		return 0, err
@@ -1081,7 +1835,7 @@ func (q *Queries) StopTask(ctx context.Context, arg StopTaskParams) (int64, erro
 }
 
 const taskExists = `-- name: TaskExists :one
-SELECT EXISTS(SELECT 1 FROM task WHERE id = ?)
+SELECT EXISTS(SELECT 1 FROM task WHERE id = ? AND deleted_at IS NULL)
 `
 
 func (q *Queries) TaskExists(ctx context.Context, id string) (int64, error) {
@@ -1091,19 +1845,48 @@ func (q *Queries) TaskExists(ctx context.Context, id string) (int64, error) {
 	return column_1, err
 }
 
+const trashTask = `-- name: TrashTask :execrows
+UPDATE task SET deleted_at = unixepoch(), epic_id = NULL, updated_at = unixepoch()
+WHERE id = ? AND deleted_at IS NULL
+`
+
+func (q *Queries) TrashTask(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, trashTask, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const unarchiveTask = `-- name: UnarchiveTask :execrows
+UPDATE task SET archived_at = NULL
+WHERE id = ? AND archived_at IS NOT NULL
+`
+
+func (q *Queries) UnarchiveTask(ctx context.Context, id string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, unarchiveTask, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updatePendingTask = `-- name: UpdatePendingTask :execrows
 UPDATE task SET
-  title = ?,
-  description = ?,
-  depends_on = ?,
-  acceptance_criteria_list = ?,
-  max_cost_usd = ?,
-  skip_pr = ?,
-  draft_pr = ?,
-  model = ?,
-  ready = ?,
+  title = ?1,
+  description = ?2,
+  depends_on = ?3,
+  acceptance_criteria_list = ?4,
+  max_cost_usd = ?5,
+  max_runtime_seconds = ?6,
+  required_labels = ?7,
+  skip_pr = ?8,
+  draft_pr = ?9,
+  model = ?10,
+  ready = ?11,
   updated_at = unixepoch()
-WHERE id = ? AND status = 'pending'
+WHERE id = ?12 AND status = 'pending'
+  AND (CAST(?13 AS INTEGER) = 0 OR version = CAST(?13 AS INTEGER))
 `
 
 type UpdatePendingTaskParams struct {
@@ -1112,11 +1895,14 @@ type UpdatePendingTaskParams struct {
 	DependsOn              string
 	AcceptanceCriteriaList string
 	MaxCostUsd             *float64
+	MaxRuntimeSeconds      int64
+	RequiredLabels         string
 	SkipPr                 int64
 	DraftPr                int64
 	Model                  *string
 	Ready                  int64
 	ID                     string
+	ExpectedVersion        int64
 }
 
 func (q *Queries) UpdatePendingTask(ctx context.Context, arg UpdatePendingTaskParams) (int64, error) {
@@ -1126,11 +1912,14 @@ func (q *Queries) UpdatePendingTask(ctx context.Context, arg UpdatePendingTaskPa
 		arg.DependsOn,
 		arg.AcceptanceCriteriaList,
 		arg.MaxCostUsd,
+		arg.MaxRuntimeSeconds,
+		arg.RequiredLabels,
 		arg.SkipPr,
 		arg.DraftPr,
 		arg.Model,
 		arg.Ready,
 		arg.ID,
+		arg.ExpectedVersion,
 	)
 	if err != nil {
 		return 0, err
@@ -1,9 +1,12 @@
 package sqlite
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"slices"
 	"strings"
 	"time"
 
@@ -16,6 +19,10 @@ import (
 
 var _ task.Repository = (*TaskRepository)(nil)
 
+// expiredLogsBatchSize is the number of log batches deleted per statement
+// when applying log retention.
+const expiredLogsBatchSize = 500
+
 // TaskRepository implements task.Repository using SQLite.
 type TaskRepository struct {
 	dbtx sqlc.DBTX
@@ -69,6 +76,10 @@ func (r *TaskRepository) CreateTask(ctx context.Context, t *task.Task) error {
 	if t.DraftPR {
 		draftPR = 1
 	}
+	var planOnly int64
+	if t.PlanOnly {
+		planOnly = 1
+	}
 	var ready int64
 	if t.Ready {
 		ready = 1
@@ -81,6 +92,10 @@ func (r *TaskRepository) CreateTask(ctx context.Context, t *task.Task) error {
 	if t.EpicID != "" {
 		epicID = &t.EpicID
 	}
+	var stackParentID *string
+	if t.StackParentID != "" {
+		stackParentID = &t.StackParentID
+	}
 	taskType := t.Type
 	if taskType == "" {
 		taskType = task.TaskTypeTask
@@ -97,11 +112,17 @@ func (r *TaskRepository) CreateTask(ctx context.Context, t *task.Task) error {
 		MaxAttempts:           int64(t.MaxAttempts),
 		AcceptanceCriteriaList: marshalJSONStrings(t.AcceptanceCriteria),
 		MaxCostUsd:            maxCostUSD,
+		MaxRuntimeSeconds:     int64(t.MaxRuntimeSeconds),
+		RequiredLabels:        marshalJSONStrings(t.RequiredLabels),
+		FallbackModels:        marshalJSONStrings(t.FallbackModels),
 		SkipPr:                skipPR,
 		DraftPr:               draftPR,
 		Model:                 model,
 		Ready:                 ready,
 		EpicID:                epicID,
+		AdditionalRepoIds:     marshalJSONStrings(t.AdditionalRepoIDs),
+		StackParentID:         stackParentID,
+		PlanOnly:              planOnly,
 		CreatedAt:             t.CreatedAt.Unix(),
 		UpdatedAt:             t.UpdatedAt.Unix(),
 	})
@@ -176,7 +197,7 @@ func (r *TaskRepository) ListPendingTasksByRepos(ctx context.Context, repoIDs []
 	if len(repoIDs) == 0 {
 		return nil, nil
 	}
-	query := "SELECT id, repo_id, title, description, status, pull_request_url, pr_number, depends_on, close_reason, attempt, max_attempts, retry_reason, acceptance_criteria_list, agent_status, retry_context, consecutive_failures, cost_usd, max_cost_usd, skip_pr, draft_pr, branch_name, model, started_at, ready, last_heartbeat_at, epic_id, created_at, updated_at, type, number FROM task WHERE status = 'pending' AND ready = 1 AND repo_id IN (?" + strings.Repeat(",?", len(repoIDs)-1) + ") ORDER BY created_at ASC"
+	query := "SELECT id, repo_id, title, description, status, pull_request_url, pr_number, depends_on, close_reason, attempt, max_attempts, retry_reason, acceptance_criteria_list, agent_status, retry_context, consecutive_failures, cost_usd, max_cost_usd, skip_pr, draft_pr, branch_name, model, started_at, ready, last_heartbeat_at, epic_id, created_at, updated_at, type, number, last_review_id, stack_parent_id, plan_only, fallback_models, active_model, version FROM task WHERE status = 'pending' AND ready = 1 AND (not_before IS NULL OR not_before <= unixepoch()) AND deleted_at IS NULL AND repo_id IN (?" + strings.Repeat(",?", len(repoIDs)-1) + ") ORDER BY created_at ASC"
 	args := make([]any, len(repoIDs))
 	for i, id := range repoIDs {
 		args[i] = id
@@ -189,7 +210,7 @@ func (r *TaskRepository) ListPendingTasksByRepos(ctx context.Context, repoIDs []
 	var tasks []*task.Task
 	for rows.Next() {
 		var t sqlc.Task
-		if err := rows.Scan(&t.ID, &t.RepoID, &t.Title, &t.Description, &t.Status, &t.PullRequestUrl, &t.PrNumber, &t.DependsOn, &t.CloseReason, &t.Attempt, &t.MaxAttempts, &t.RetryReason, &t.AcceptanceCriteriaList, &t.AgentStatus, &t.RetryContext, &t.ConsecutiveFailures, &t.CostUsd, &t.MaxCostUsd, &t.SkipPr, &t.DraftPr, &t.BranchName, &t.Model, &t.StartedAt, &t.Ready, &t.LastHeartbeatAt, &t.EpicID, &t.CreatedAt, &t.UpdatedAt, &t.Type, &t.Number); err != nil {
+		if err := rows.Scan(&t.ID, &t.RepoID, &t.Title, &t.Description, &t.Status, &t.PullRequestUrl, &t.PrNumber, &t.DependsOn, &t.CloseReason, &t.Attempt, &t.MaxAttempts, &t.RetryReason, &t.AcceptanceCriteriaList, &t.AgentStatus, &t.RetryContext, &t.ConsecutiveFailures, &t.CostUsd, &t.MaxCostUsd, &t.SkipPr, &t.DraftPr, &t.BranchName, &t.Model, &t.StartedAt, &t.Ready, &t.LastHeartbeatAt, &t.EpicID, &t.CreatedAt, &t.UpdatedAt, &t.Type, &t.Number, &t.LastReviewID, &t.StackParentID, &t.PlanOnly, &t.FallbackModels, &t.ActiveModel, &t.Version); err != nil {
 			return nil, err
 		}
 		tasks = append(tasks, unmarshalTask(&t))
@@ -205,6 +226,20 @@ func (r *TaskRepository) AppendTaskLogs(ctx context.Context, id task.TaskID, att
 	}))
 }
 
+func (r *TaskRepository) AppendTaskLogBatches(ctx context.Context, batches []task.LogBatch) error {
+	if len(batches) == 0 {
+		return nil
+	}
+	// sqlc can't generate a multi-row insert, so the query is built here.
+	args := make([]any, 0, 3*len(batches))
+	for _, b := range batches {
+		args = append(args, b.TaskID.String(), int64(b.Attempt), marshalJSONStrings(b.Lines))
+	}
+	query := "INSERT INTO task_log (task_id, attempt, lines) VALUES (?, ?, ?)" + strings.Repeat(", (?, ?, ?)", len(batches)-1)
+	_, err := r.dbtx.ExecContext(ctx, query, args...)
+	return tagTaskErr(err)
+}
+
 func (r *TaskRepository) ReadTaskLogs(ctx context.Context, id task.TaskID) ([]string, error) {
 	batches, err := r.db.ReadTaskLogs(ctx, id.String())
 	if err != nil {
@@ -254,6 +289,13 @@ func (r *TaskRepository) SetTaskPullRequest(ctx context.Context, id task.TaskID,
 	}))
 }
 
+func (r *TaskRepository) SetTaskPullRequests(ctx context.Context, id task.TaskID, prs []task.PullRequest) error {
+	return tagTaskErr(r.db.SetTaskPullRequests(ctx, sqlc.SetTaskPullRequestsParams{
+		ID:           id.String(),
+		PullRequests: marshalPullRequests(prs),
+	}))
+}
+
 func (r *TaskRepository) ListTasksInReview(ctx context.Context) ([]*task.Task, error) {
 	rows, err := r.db.ListTasksInReview(ctx)
 	if err != nil {
@@ -270,6 +312,14 @@ func (r *TaskRepository) ListTasksInReviewByRepo(ctx context.Context, repoID str
 	return unmarshalTaskList(rows), nil
 }
 
+func (r *TaskRepository) ListFailedTasksByRepo(ctx context.Context, repoID string) ([]*task.Task, error) {
+	rows, err := r.db.ListFailedTasksByRepo(ctx, repoID)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalTaskList(rows), nil
+}
+
 func (r *TaskRepository) HasTasksForRepo(ctx context.Context, repoID string) (bool, error) {
 	result, err := r.db.HasTasksForRepo(ctx, repoID)
 	if err != nil {
@@ -314,9 +364,14 @@ func (r *TaskRepository) RetryTask(ctx context.Context, id task.TaskID, reason s
 	return rows > 0, tagTaskErr(err)
 }
 
-func (r *TaskRepository) ScheduleRetryFromRunning(ctx context.Context, id task.TaskID, reason string) (bool, error) {
+func (r *TaskRepository) ScheduleRetryFromRunning(ctx context.Context, id task.TaskID, reason string, notBefore *time.Time) (bool, error) {
+	var notBeforeUnix *int64
+	if notBefore != nil {
+		notBeforeUnix = ptr(notBefore.Unix())
+	}
 	rows, err := r.db.ScheduleRetryFromRunning(ctx, sqlc.ScheduleRetryFromRunningParams{
 		RetryReason: &reason,
+		NotBefore:   notBeforeUnix,
 		ID:          id.String(),
 	})
 	return rows > 0, tagTaskErr(err)
@@ -343,6 +398,13 @@ func (r *TaskRepository) AddCost(ctx context.Context, id task.TaskID, costUSD fl
 	}))
 }
 
+func (r *TaskRepository) SetLastReviewID(ctx context.Context, id task.TaskID, reviewID int64) error {
+	return tagTaskErr(r.db.SetTaskLastReviewID(ctx, sqlc.SetTaskLastReviewIDParams{
+		LastReviewID: reviewID,
+		ID:           id.String(),
+	}))
+}
+
 func (r *TaskRepository) SetConsecutiveFailures(ctx context.Context, id task.TaskID, count int) error {
 	return tagTaskErr(r.db.SetConsecutiveFailures(ctx, sqlc.SetConsecutiveFailuresParams{
 		ConsecutiveFailures: int64(count),
@@ -357,6 +419,13 @@ func (r *TaskRepository) SetCloseReason(ctx context.Context, id task.TaskID, rea
 	}))
 }
 
+func (r *TaskRepository) SetFailureCategory(ctx context.Context, id task.TaskID, category string) error {
+	return tagTaskErr(r.db.SetFailureCategory(ctx, sqlc.SetFailureCategoryParams{
+		FailureCategory: &category,
+		ID:              id.String(),
+	}))
+}
+
 func (r *TaskRepository) SetBranchName(ctx context.Context, id task.TaskID, branchName string) error {
 	return tagTaskErr(r.db.SetBranchName(ctx, sqlc.SetBranchNameParams{
 		BranchName: &branchName,
@@ -389,6 +458,45 @@ func (r *TaskRepository) FeedbackRetryTask(ctx context.Context, id task.TaskID,
 }
 
 func (r *TaskRepository) DeleteTaskLogs(ctx context.Context, id task.TaskID) error {
+	if err := r.db.DeleteTaskAttempts(ctx, id.String()); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.DeleteTaskEvents(ctx, id.String()); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.DeleteTaskProgress(ctx, id.String()); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.DeleteTaskNudges(ctx, id.String()); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.DeleteTaskCallbacks(ctx, id.String()); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.DeleteTaskNotes(ctx, id.String()); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.DeleteTaskCIDispatch(ctx, id.String()); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.DeleteTaskProvenanceReview(ctx, id.String()); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.DeleteTaskShadowDiff(ctx, id.String()); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.DeleteTaskProposal(ctx, id.String()); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.DeleteTaskSelfReview(ctx, id.String()); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.DeleteTaskLogArchive(ctx, id.String()); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.DeleteTaskArtifacts(ctx, id.String()); err != nil {
+		return tagTaskErr(err)
+	}
 	return tagTaskErr(r.db.DeleteTaskLogs(ctx, id.String()))
 }
 
@@ -447,11 +555,14 @@ func (r *TaskRepository) UpdatePendingTask(ctx context.Context, id task.TaskID,
 		DependsOn:              marshalJSONStrings(params.DependsOn),
 		AcceptanceCriteriaList: marshalJSONStrings(params.AcceptanceCriteria),
 		MaxCostUsd:             maxCostUSD,
+		MaxRuntimeSeconds:      int64(params.MaxRuntimeSeconds),
+		RequiredLabels:         marshalJSONStrings(params.RequiredLabels),
 		SkipPr:                 skipPR,
 		DraftPr:                draftPR,
 		Model:                  model,
 		Ready:                  ready,
 		ID:                     id.String(),
+		ExpectedVersion:        params.ExpectedVersion,
 	})
 	return rows > 0, tagTaskErr(err)
 }
@@ -461,7 +572,34 @@ func (r *TaskRepository) StartOverTask(ctx context.Context, id task.TaskID, para
 		Title:                  params.Title,
 		Description:            params.Description,
 		AcceptanceCriteriaList: marshalJSONStrings(params.AcceptanceCriteria),
+		MaxAttempts:            int64(params.MaxAttempts),
 		ID:                     id.String(),
+		ExpectedVersion:        params.ExpectedVersion,
+	})
+	return rows > 0, tagTaskErr(err)
+}
+
+func (r *TaskRepository) MoveTask(ctx context.Context, id task.TaskID, params task.MoveTaskParams) (bool, error) {
+	rows, err := r.db.MoveTask(ctx, sqlc.MoveTaskParams{
+		RepoID:            params.RepoID,
+		DependsOn:         marshalJSONStrings(params.DependsOn),
+		AdditionalRepoIds: marshalJSONStrings(params.AdditionalRepoIDs),
+		MaxAttempts:       int64(params.MaxAttempts),
+		ID:                id.String(),
+		ExpectedVersion:   params.ExpectedVersion,
+	})
+	return rows > 0, tagTaskErr(err)
+}
+
+func (r *TaskRepository) AbandonTask(ctx context.Context, id task.TaskID) (bool, error) {
+	rows, err := r.db.AbandonTask(ctx, id.String())
+	return rows > 0, tagTaskErr(err)
+}
+
+func (r *TaskRepository) AbandonStaleTask(ctx context.Context, id task.TaskID, before time.Time) (bool, error) {
+	rows, err := r.db.AbandonStaleTask(ctx, sqlc.AbandonStaleTaskParams{
+		ID:     id.String(),
+		Before: before.Unix(),
 	})
 	return rows > 0, tagTaskErr(err)
 }
@@ -488,10 +626,161 @@ func (r *TaskRepository) ListStaleTasks(ctx context.Context, before time.Time) (
 	return unmarshalTaskList(rows), nil
 }
 
+func (r *TaskRepository) ListOverrunTasks(ctx context.Context, now time.Time) ([]*task.Task, error) {
+	rows, err := r.db.ListOverrunTasks(ctx, now.Unix())
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalTaskList(rows), nil
+}
+
+func (r *TaskRepository) ListRepoRequiredLabels(ctx context.Context) (map[string][]string, error) {
+	rows, err := r.db.ListRepoRequiredLabels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string][]string, len(rows))
+	for _, row := range rows {
+		out[row.ID] = unmarshalJSONStrings(row.RequiredLabels)
+	}
+	return out, nil
+}
+
+func (r *TaskRepository) ListPausedRepoIDs(ctx context.Context) ([]string, error) {
+	return r.db.ListPausedRepoIDs(ctx)
+}
+
 func (r *TaskRepository) DeleteTask(ctx context.Context, id task.TaskID) error {
+	// Comments and attachments are kept when a task's logs are deleted, such
+	// as when it is started over, so they go with the task itself.
+	if err := r.db.DeleteTaskComments(ctx, id.String()); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.DeleteTaskAttachments(ctx, id.String()); err != nil {
+		return tagTaskErr(err)
+	}
 	return tagTaskErr(r.db.DeleteTask(ctx, id.String()))
 }
 
+func (r *TaskRepository) TrashTask(ctx context.Context, id task.TaskID) (bool, error) {
+	rows, err := r.db.TrashTask(ctx, id.String())
+	return rows > 0, tagTaskErr(err)
+}
+
+func (r *TaskRepository) RestoreTask(ctx context.Context, id task.TaskID) (bool, error) {
+	rows, err := r.db.RestoreTask(ctx, id.String())
+	return rows > 0, tagTaskErr(err)
+}
+
+func (r *TaskRepository) ListTrashedTasksByRepo(ctx context.Context, repoID string) ([]*task.Task, error) {
+	rows, err := r.db.ListTrashedTasksByRepo(ctx, repoID)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalTaskList(rows), nil
+}
+
+// archivedLogBatch is a log batch of an archived task as stored in
+// task_log_archive. Lines holds the batch's JSON array as written to task_log.
+type archivedLogBatch struct {
+	Attempt int64           `json:"attempt"`
+	Lines   json.RawMessage `json:"lines"`
+}
+
+// ArchiveTask marks a merged or closed task archived and moves its logs into
+// task_log_archive. Call it in a transaction so the logs are never lost
+// half-way.
+func (r *TaskRepository) ArchiveTask(ctx context.Context, id task.TaskID) (bool, error) {
+	rows, err := r.db.ArchiveTask(ctx, id.String())
+	if err != nil || rows == 0 {
+		return false, tagTaskErr(err)
+	}
+	logs, err := r.db.ReadTaskLogs(ctx, id.String())
+	if err != nil {
+		return false, tagTaskErr(err)
+	}
+	batches := make([]archivedLogBatch, len(logs))
+	for i, l := range logs {
+		batches[i] = archivedLogBatch{Attempt: l.Attempt, Lines: json.RawMessage(l.Lines)}
+	}
+	b, err := json.Marshal(batches)
+	if err != nil {
+		return false, err
+	}
+	if err := r.db.CreateTaskLogArchive(ctx, sqlc.CreateTaskLogArchiveParams{
+		TaskID:  id.String(),
+		Batches: string(b),
+	}); err != nil {
+		return false, tagTaskErr(err)
+	}
+	return true, tagTaskErr(r.db.DeleteTaskLogs(ctx, id.String()))
+}
+
+// UnarchiveTask clears a task's archived mark and moves its logs back from
+// task_log_archive. Call it in a transaction.
+func (r *TaskRepository) UnarchiveTask(ctx context.Context, id task.TaskID) (bool, error) {
+	rows, err := r.db.UnarchiveTask(ctx, id.String())
+	if err != nil || rows == 0 {
+		return false, tagTaskErr(err)
+	}
+	archived, err := r.db.ReadTaskLogArchive(ctx, id.String())
+	if errors.Is(err, sql.ErrNoRows) {
+		return true, nil
+	}
+	if err != nil {
+		return false, tagTaskErr(err)
+	}
+	var batches []archivedLogBatch
+	if err := json.Unmarshal([]byte(archived), &batches); err != nil {
+		return false, err
+	}
+	for _, b := range batches {
+		if err := r.db.AppendTaskLogs(ctx, sqlc.AppendTaskLogsParams{
+			TaskID:  id.String(),
+			Attempt: b.Attempt,
+			Lines:   string(b.Lines),
+		}); err != nil {
+			return false, tagTaskErr(err)
+		}
+	}
+	return true, tagTaskErr(r.db.DeleteTaskLogArchive(ctx, id.String()))
+}
+
+func (r *TaskRepository) ListArchivedTasksByRepo(ctx context.Context, repoID string) ([]*task.Task, error) {
+	rows, err := r.db.ListArchivedTasksByRepo(ctx, repoID)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalTaskList(rows), nil
+}
+
+func (r *TaskRepository) ListArchivableTaskIDs(ctx context.Context, before time.Time, limit int) ([]task.TaskID, error) {
+	ids, err := r.db.ListArchivableTaskIDs(ctx, sqlc.ListArchivableTaskIDsParams{
+		Before:   before.Unix(),
+		MaxTasks: int64(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]task.TaskID, len(ids))
+	for i, id := range ids {
+		out[i] = task.MustParseTaskID(id)
+	}
+	return out, nil
+}
+
+func (r *TaskRepository) ListTrashedTaskIDsBefore(ctx context.Context, before time.Time) ([]task.TaskID, error) {
+	ids, err := r.db.ListTrashedTaskIDsBefore(ctx, before.Unix())
+	if err != nil {
+		return nil, err
+	}
+	out := make([]task.TaskID, len(ids))
+	for i, id := range ids {
+		out[i] = task.MustParseTaskID(id)
+	}
+	return out, nil
+}
+
 func (r *TaskRepository) ListTasksByEpic(ctx context.Context, epicID string) ([]*task.Task, error) {
 	rows, err := r.db.ListTasksByEpic(ctx, &epicID)
 	if err != nil {
@@ -512,7 +801,51 @@ func (r *TaskRepository) ClearEpicIDForTasks(ctx context.Context, epicID string)
 }
 
 func (r *TaskRepository) BulkDeleteTasksByEpic(ctx context.Context, epicID string) error {
-	// Delete logs first (FK constraint)
+	// Delete attempts, events, progress, nudges, notes, comments,
+	// attachments, artifacts, CI dispatches, provenance reviews, shadow
+	// diffs, self-reviews, archived logs and logs first (FK constraint)
+	if err := r.db.BulkDeleteTaskAttemptsByEpic(ctx, &epicID); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.BulkDeleteTaskEventsByEpic(ctx, &epicID); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.BulkDeleteTaskProgressByEpic(ctx, &epicID); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.BulkDeleteTaskNudgesByEpic(ctx, &epicID); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.BulkDeleteTaskNotesByEpic(ctx, &epicID); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.BulkDeleteTaskCommentsByEpic(ctx, &epicID); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.BulkDeleteTaskAttachmentsByEpic(ctx, &epicID); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.BulkDeleteTaskArtifactsByEpic(ctx, &epicID); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.BulkDeleteTaskCIDispatchesByEpic(ctx, &epicID); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.BulkDeleteTaskProvenanceReviewsByEpic(ctx, &epicID); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.BulkDeleteTaskShadowDiffsByEpic(ctx, &epicID); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.BulkDeleteTaskProposalsByEpic(ctx, &epicID); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.BulkDeleteTaskSelfReviewsByEpic(ctx, &epicID); err != nil {
+		return tagTaskErr(err)
+	}
+	if err := r.db.BulkDeleteTaskLogArchivesByEpic(ctx, &epicID); err != nil {
+		return tagTaskErr(err)
+	}
 	if err := r.db.BulkDeleteTaskLogsByEpic(ctx, &epicID); err != nil {
 		return tagTaskErr(err)
 	}
@@ -529,7 +862,48 @@ func (r *TaskRepository) BulkDeleteTasksByIDs(ctx context.Context, ids []string)
 	for i, id := range ids {
 		args[i] = id
 	}
-	// Delete logs first (FK constraint)
+	// Delete attempts, events, progress, nudges, notes, comments,
+	// attachments, artifacts, CI dispatches, provenance reviews, shadow
+	// diffs, self-reviews, archived logs and logs first (FK constraint)
+	if _, err := r.dbtx.ExecContext(ctx, "DELETE FROM task_attempt WHERE task_id IN ("+placeholders+")", args...); err != nil {
+		return tagTaskErr(err)
+	}
+	if _, err := r.dbtx.ExecContext(ctx, "DELETE FROM task_event_log WHERE task_id IN ("+placeholders+")", args...); err != nil {
+		return tagTaskErr(err)
+	}
+	if _, err := r.dbtx.ExecContext(ctx, "DELETE FROM task_progress WHERE task_id IN ("+placeholders+")", args...); err != nil {
+		return tagTaskErr(err)
+	}
+	if _, err := r.dbtx.ExecContext(ctx, "DELETE FROM task_nudge WHERE task_id IN ("+placeholders+")", args...); err != nil {
+		return tagTaskErr(err)
+	}
+	if _, err := r.dbtx.ExecContext(ctx, "DELETE FROM task_note WHERE task_id IN ("+placeholders+")", args...); err != nil {
+		return tagTaskErr(err)
+	}
+	if _, err := r.dbtx.ExecContext(ctx, "DELETE FROM task_comment WHERE task_id IN ("+placeholders+")", args...); err != nil {
+		return tagTaskErr(err)
+	}
+	if _, err := r.dbtx.ExecContext(ctx, "DELETE FROM task_attachment WHERE task_id IN ("+placeholders+")", args...); err != nil {
+		return tagTaskErr(err)
+	}
+	if _, err := r.dbtx.ExecContext(ctx, "DELETE FROM task_artifact WHERE task_id IN ("+placeholders+")", args...); err != nil {
+		return tagTaskErr(err)
+	}
+	if _, err := r.dbtx.ExecContext(ctx, "DELETE FROM task_ci_dispatch WHERE task_id IN ("+placeholders+")", args...); err != nil {
+		return tagTaskErr(err)
+	}
+	if _, err := r.dbtx.ExecContext(ctx, "DELETE FROM task_provenance_review WHERE task_id IN ("+placeholders+")", args...); err != nil {
+		return tagTaskErr(err)
+	}
+	if _, err := r.dbtx.ExecContext(ctx, "DELETE FROM task_shadow_diff WHERE task_id IN ("+placeholders+")", args...); err != nil {
+		return tagTaskErr(err)
+	}
+	if _, err := r.dbtx.ExecContext(ctx, "DELETE FROM task_self_review WHERE task_id IN ("+placeholders+")", args...); err != nil {
+		return tagTaskErr(err)
+	}
+	if _, err := r.dbtx.ExecContext(ctx, "DELETE FROM task_log_archive WHERE task_id IN ("+placeholders+")", args...); err != nil {
+		return tagTaskErr(err)
+	}
 	if _, err := r.dbtx.ExecContext(ctx, "DELETE FROM task_log WHERE task_id IN ("+placeholders+")", args...); err != nil {
 		return tagTaskErr(err)
 	}
@@ -539,9 +913,28 @@ func (r *TaskRepository) BulkDeleteTasksByIDs(ctx context.Context, ids []string)
 	return nil
 }
 
+// DeleteExpiredLogs deletes expired log batches in chunks of
+// expiredLogsBatchSize rows. Each chunk is its own statement so the write
+// lock is released between chunks and retention never blocks log appends
+// from running agents for long, however large the backlog.
 func (r *TaskRepository) DeleteExpiredLogs(ctx context.Context, before time.Time) (int64, error) {
-	n, err := r.db.DeleteExpiredLogs(ctx, before.Unix())
-	return n, tagTaskErr(err)
+	var total int64
+	for {
+		n, err := r.db.DeleteExpiredLogs(ctx, sqlc.DeleteExpiredLogsParams{
+			CreatedAt: before.Unix(),
+			Limit:     expiredLogsBatchSize,
+		})
+		total += n
+		if err != nil {
+			return total, tagTaskErr(err)
+		}
+		if n < expiredLogsBatchSize {
+			return total, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+	}
 }
 
 func (r *TaskRepository) ListTasksInReviewNoPR(ctx context.Context) ([]*task.Task, error) {
@@ -552,6 +945,612 @@ func (r *TaskRepository) ListTasksInReviewNoPR(ctx context.Context) ([]*task.Tas
 	return unmarshalTaskList(rows), nil
 }
 
+func (r *TaskRepository) StartTaskAttempt(ctx context.Context, id task.TaskID, attempt int, retryReason, model string) error {
+	return tagTaskErr(r.db.StartTaskAttempt(ctx, sqlc.StartTaskAttemptParams{
+		TaskID:      id.String(),
+		Attempt:     int64(attempt),
+		RetryReason: retryReason,
+		Model:       model,
+	}))
+}
+
+func (r *TaskRepository) EndTaskAttempt(ctx context.Context, id task.TaskID, result string) error {
+	return tagTaskErr(r.db.EndTaskAttempt(ctx, sqlc.EndTaskAttemptParams{
+		Result: result,
+		TaskID: id.String(),
+	}))
+}
+
+func (r *TaskRepository) AddTaskAttemptCost(ctx context.Context, id task.TaskID, costUSD float64) error {
+	return tagTaskErr(r.db.AddTaskAttemptCost(ctx, sqlc.AddTaskAttemptCostParams{
+		CostUsd: costUSD,
+		TaskID:  id.String(),
+	}))
+}
+
+func (r *TaskRepository) ListTaskAttempts(ctx context.Context, id task.TaskID) ([]*task.Attempt, error) {
+	rows, err := r.db.ListTaskAttempts(ctx, id.String())
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	attempts := make([]*task.Attempt, len(rows))
+	for i, row := range rows {
+		attempts[i] = unmarshalTaskAttempt(row)
+	}
+	return attempts, nil
+}
+
+func (r *TaskRepository) StreamTaskAttemptLogs(ctx context.Context, id task.TaskID, attempt int, fn func(lines []string) error) error {
+	rows, err := r.dbtx.QueryContext(ctx, "SELECT lines FROM task_log WHERE task_id = ? AND attempt = ? ORDER BY id", id.String(), attempt)
+	if err != nil {
+		return tagTaskErr(err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var linesJSON string
+		if err := rows.Scan(&linesJSON); err != nil {
+			return err
+		}
+		if err := fn(unmarshalJSONStrings(linesJSON)); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *TaskRepository) AppendTaskEvents(ctx context.Context, id task.TaskID, attempt int, events []*task.AgentEvent) error {
+	for _, e := range events {
+		row, err := r.db.AppendTaskEvent(ctx, sqlc.AppendTaskEventParams{
+			TaskID:  id.String(),
+			Attempt: int64(attempt),
+			Type:    string(e.Type),
+			Payload: marshalAgentEventPayload(e.Payload),
+		})
+		if err != nil {
+			return tagTaskErr(err)
+		}
+		*e = *unmarshalTaskEvent(row)
+	}
+	return nil
+}
+
+func (r *TaskRepository) ListTaskEvents(ctx context.Context, id task.TaskID) ([]*task.AgentEvent, error) {
+	rows, err := r.db.ListTaskEvents(ctx, id.String())
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	events := make([]*task.AgentEvent, len(rows))
+	for i, row := range rows {
+		events[i] = unmarshalTaskEvent(row)
+	}
+	return events, nil
+}
+
+func (r *TaskRepository) ReadTaskProgress(ctx context.Context, id task.TaskID) (*task.Progress, error) {
+	row, err := r.db.ReadTaskProgress(ctx, id.String())
+	if errors.Is(err, sql.ErrNoRows) {
+		return &task.Progress{Steps: []task.Step{}}, nil
+	}
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	return unmarshalTaskProgress(row), nil
+}
+
+func (r *TaskRepository) SetTaskProgress(ctx context.Context, id task.TaskID, progress *task.Progress) error {
+	return tagTaskErr(r.db.UpsertTaskProgress(ctx, sqlc.UpsertTaskProgressParams{
+		TaskID:    id.String(),
+		Attempt:   int64(progress.Attempt),
+		Steps:     marshalProgressSteps(progress.Steps),
+		UpdatedAt: progress.UpdatedAt.Unix(),
+	}))
+}
+
+func (r *TaskRepository) CreateTaskNudge(ctx context.Context, id task.TaskID, attempt int, message string) (*task.Nudge, error) {
+	row, err := r.db.CreateTaskNudge(ctx, sqlc.CreateTaskNudgeParams{
+		TaskID:  id.String(),
+		Attempt: int64(attempt),
+		Message: message,
+	})
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	return unmarshalTaskNudge(row), nil
+}
+
+func (r *TaskRepository) ListTaskNudges(ctx context.Context, id task.TaskID) ([]*task.Nudge, error) {
+	rows, err := r.db.ListTaskNudges(ctx, id.String())
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	return unmarshalTaskNudgeList(rows), nil
+}
+
+func (r *TaskRepository) DeliverTaskNudges(ctx context.Context, id task.TaskID, now time.Time) ([]*task.Nudge, error) {
+	deliveredAt := now.Unix()
+	rows, err := r.db.DeliverTaskNudges(ctx, sqlc.DeliverTaskNudgesParams{
+		DeliveredAt: &deliveredAt,
+		TaskID:      id.String(),
+	})
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	// UPDATE ... RETURNING does not guarantee row order.
+	slices.SortFunc(rows, func(a, b *sqlc.TaskNudge) int { return cmp.Compare(a.ID, b.ID) })
+	return unmarshalTaskNudgeList(rows), nil
+}
+
+func (r *TaskRepository) CreateTaskNote(ctx context.Context, id task.TaskID, repoID string, attempt int, text string) (*task.Note, error) {
+	row, err := r.db.CreateTaskNote(ctx, sqlc.CreateTaskNoteParams{
+		TaskID:  id.String(),
+		RepoID:  repoID,
+		Attempt: int64(attempt),
+		Text:    text,
+	})
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	return unmarshalTaskNote(row), nil
+}
+
+func (r *TaskRepository) ListRepoNotes(ctx context.Context, repoID string) ([]*task.Note, error) {
+	rows, err := r.db.ListRepoTaskNotes(ctx, repoID)
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	return unmarshalTaskNoteList(rows), nil
+}
+
+func (r *TaskRepository) ReadTaskNote(ctx context.Context, noteID int64) (*task.Note, error) {
+	row, err := r.db.ReadTaskNote(ctx, noteID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	return unmarshalTaskNote(row), nil
+}
+
+func (r *TaskRepository) SetTaskNoteConverted(ctx context.Context, noteID int64, taskID task.TaskID) (bool, error) {
+	converted := taskID.String()
+	n, err := r.db.SetTaskNoteConvertedTask(ctx, sqlc.SetTaskNoteConvertedTaskParams{
+		ConvertedTaskID: &converted,
+		ID:              noteID,
+	})
+	if err != nil {
+		return false, tagTaskErr(err)
+	}
+	return n > 0, nil
+}
+
+func (r *TaskRepository) CreateTaskComment(ctx context.Context, comment *task.Comment) (*task.Comment, error) {
+	params := sqlc.CreateTaskCommentParams{
+		TaskID: comment.TaskID,
+		Author: comment.Author,
+		Body:   comment.Body,
+	}
+	if comment.Attempt != nil {
+		attempt := int64(*comment.Attempt)
+		params.Attempt = &attempt
+	}
+	if comment.AuthorID != "" {
+		params.AuthorID = &comment.AuthorID
+	}
+	row, err := r.db.CreateTaskComment(ctx, params)
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	return unmarshalTaskComment(row), nil
+}
+
+func (r *TaskRepository) ListTaskComments(ctx context.Context, id task.TaskID) ([]*task.Comment, error) {
+	rows, err := r.db.ListTaskComments(ctx, id.String())
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	return unmarshalTaskCommentList(rows), nil
+}
+
+func (r *TaskRepository) ReadTaskComment(ctx context.Context, id task.TaskID, commentID int64) (*task.Comment, error) {
+	row, err := r.db.ReadTaskComment(ctx, sqlc.ReadTaskCommentParams{
+		ID:     commentID,
+		TaskID: id.String(),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	return unmarshalTaskComment(row), nil
+}
+
+func (r *TaskRepository) UpdateTaskComment(ctx context.Context, id task.TaskID, commentID int64, body string) (*task.Comment, error) {
+	row, err := r.db.UpdateTaskComment(ctx, sqlc.UpdateTaskCommentParams{
+		Body:   body,
+		ID:     commentID,
+		TaskID: id.String(),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	return unmarshalTaskComment(row), nil
+}
+
+func (r *TaskRepository) DeleteTaskComment(ctx context.Context, id task.TaskID, commentID int64) (bool, error) {
+	n, err := r.db.DeleteTaskComment(ctx, sqlc.DeleteTaskCommentParams{
+		ID:     commentID,
+		TaskID: id.String(),
+	})
+	if err != nil {
+		return false, tagTaskErr(err)
+	}
+	return n > 0, nil
+}
+
+func (r *TaskRepository) CountTaskComments(ctx context.Context, id task.TaskID) (int, error) {
+	n, err := r.db.CountTaskComments(ctx, id.String())
+	if err != nil {
+		return 0, tagTaskErr(err)
+	}
+	return int(n), nil
+}
+
+func (r *TaskRepository) CountTaskCommentsByRepo(ctx context.Context, repoID string) (map[string]int, error) {
+	rows, err := r.db.CountTaskCommentsByRepo(ctx, repoID)
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.TaskID] = int(row.CommentCount)
+	}
+	return counts, nil
+}
+
+func (r *TaskRepository) CreateTaskAttachment(ctx context.Context, attachment *task.Attachment) (*task.Attachment, error) {
+	row, err := r.db.CreateTaskAttachment(ctx, sqlc.CreateTaskAttachmentParams{
+		TaskID:      attachment.TaskID,
+		Filename:    attachment.Filename,
+		ContentType: attachment.ContentType,
+		SizeBytes:   attachment.SizeBytes,
+		StorageKey:  attachment.StorageKey,
+		UploadedBy:  attachment.UploadedBy,
+	})
+	if isSQLiteErrCode(err, sqliteConstraint, sqliteConstraintUnique) {
+		return nil, task.ErrAttachmentExists
+	}
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	return unmarshalTaskAttachment(row), nil
+}
+
+func (r *TaskRepository) ListTaskAttachments(ctx context.Context, id task.TaskID) ([]*task.Attachment, error) {
+	rows, err := r.db.ListTaskAttachments(ctx, id.String())
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	return unmarshalTaskAttachmentList(rows), nil
+}
+
+func (r *TaskRepository) ReadTaskAttachment(ctx context.Context, id task.TaskID, attachmentID int64) (*task.Attachment, error) {
+	row, err := r.db.ReadTaskAttachment(ctx, sqlc.ReadTaskAttachmentParams{
+		ID:     attachmentID,
+		TaskID: id.String(),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	return unmarshalTaskAttachment(row), nil
+}
+
+func (r *TaskRepository) DeleteTaskAttachment(ctx context.Context, id task.TaskID, attachmentID int64) (bool, error) {
+	n, err := r.db.DeleteTaskAttachment(ctx, sqlc.DeleteTaskAttachmentParams{
+		ID:     attachmentID,
+		TaskID: id.String(),
+	})
+	if err != nil {
+		return false, tagTaskErr(err)
+	}
+	return n > 0, nil
+}
+
+func (r *TaskRepository) CountTaskAttachments(ctx context.Context, id task.TaskID) (int, error) {
+	n, err := r.db.CountTaskAttachments(ctx, id.String())
+	if err != nil {
+		return 0, tagTaskErr(err)
+	}
+	return int(n), nil
+}
+
+func (r *TaskRepository) CreateTaskArtifact(ctx context.Context, artifact *task.Artifact) (*task.Artifact, error) {
+	row, err := r.db.CreateTaskArtifact(ctx, sqlc.CreateTaskArtifactParams{
+		TaskID:      artifact.TaskID,
+		Attempt:     int64(artifact.Attempt),
+		Name:        artifact.Name,
+		ContentType: artifact.ContentType,
+		SizeBytes:   artifact.SizeBytes,
+		StorageKey:  artifact.StorageKey,
+	})
+	if isSQLiteErrCode(err, sqliteConstraint, sqliteConstraintUnique) {
+		return nil, task.ErrArtifactExists
+	}
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	return unmarshalTaskArtifact(row), nil
+}
+
+func (r *TaskRepository) ListTaskArtifacts(ctx context.Context, id task.TaskID) ([]*task.Artifact, error) {
+	rows, err := r.db.ListTaskArtifacts(ctx, id.String())
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	return unmarshalTaskArtifactList(rows), nil
+}
+
+func (r *TaskRepository) ReadTaskArtifact(ctx context.Context, id task.TaskID, artifactID int64) (*task.Artifact, error) {
+	row, err := r.db.ReadTaskArtifact(ctx, sqlc.ReadTaskArtifactParams{
+		ID:     artifactID,
+		TaskID: id.String(),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	return unmarshalTaskArtifact(row), nil
+}
+
+func (r *TaskRepository) CountTaskArtifacts(ctx context.Context, id task.TaskID, attempt int) (int, error) {
+	n, err := r.db.CountTaskArtifacts(ctx, sqlc.CountTaskArtifactsParams{
+		TaskID:  id.String(),
+		Attempt: int64(attempt),
+	})
+	if err != nil {
+		return 0, tagTaskErr(err)
+	}
+	return int(n), nil
+}
+
+func (r *TaskRepository) DeleteExpiredTaskArtifacts(ctx context.Context, before time.Time) ([]string, error) {
+	keys, err := r.db.ListExpiredTaskArtifactKeys(ctx, before.Unix())
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	if _, err := r.db.DeleteExpiredTaskArtifacts(ctx, before.Unix()); err != nil {
+		return nil, tagTaskErr(err)
+	}
+	return keys, nil
+}
+
+func (r *TaskRepository) SetTaskCIDispatch(ctx context.Context, id task.TaskID, dispatch *task.CIDispatch) error {
+	return tagTaskErr(r.db.UpsertTaskCIDispatch(ctx, sqlc.UpsertTaskCIDispatchParams{
+		TaskID:       id.String(),
+		WorkflowFile: dispatch.WorkflowFile,
+		Ref:          dispatch.Ref,
+		DispatchedAt: dispatch.DispatchedAt.Unix(),
+	}))
+}
+
+func (r *TaskRepository) ReadTaskCIDispatch(ctx context.Context, id task.TaskID) (*task.CIDispatch, error) {
+	row, err := r.db.ReadTaskCIDispatch(ctx, id.String())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	return &task.CIDispatch{
+		WorkflowFile: row.WorkflowFile,
+		Ref:          row.Ref,
+		DispatchedAt: unixToTime(row.DispatchedAt),
+	}, nil
+}
+
+func (r *TaskRepository) SetTaskProvenanceReview(ctx context.Context, id task.TaskID, review *task.ProvenanceReview) error {
+	var acknowledgedAt *int64
+	if review.AcknowledgedAt != nil {
+		acknowledgedAt = ptr(review.AcknowledgedAt.Unix())
+	}
+	return tagTaskErr(r.db.UpsertTaskProvenanceReview(ctx, sqlc.UpsertTaskProvenanceReviewParams{
+		TaskID:         id.String(),
+		Findings:       marshalProvenanceFindings(review.Findings),
+		HeadSha:        review.HeadSHA,
+		ScannedAt:      review.ScannedAt.Unix(),
+		AcknowledgedAt: acknowledgedAt,
+	}))
+}
+
+func (r *TaskRepository) ReadTaskProvenanceReview(ctx context.Context, id task.TaskID) (*task.ProvenanceReview, error) {
+	row, err := r.db.ReadTaskProvenanceReview(ctx, id.String())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	return unmarshalTaskProvenanceReview(row), nil
+}
+
+func (r *TaskRepository) SetTaskShadowDiff(ctx context.Context, id task.TaskID, diff *task.ShadowDiff) error {
+	return tagTaskErr(r.db.UpsertTaskShadowDiff(ctx, sqlc.UpsertTaskShadowDiffParams{
+		TaskID:    id.String(),
+		Attempt:   int64(diff.Attempt),
+		Diff:      diff.Diff,
+		CreatedAt: diff.CreatedAt.Unix(),
+	}))
+}
+
+func (r *TaskRepository) ReadTaskShadowDiff(ctx context.Context, id task.TaskID) (*task.ShadowDiff, error) {
+	row, err := r.db.ReadTaskShadowDiff(ctx, id.String())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	return &task.ShadowDiff{
+		Attempt:   int(row.Attempt),
+		Diff:      row.Diff,
+		CreatedAt: unixToTime(row.CreatedAt),
+	}, nil
+}
+
+func (r *TaskRepository) SetTaskProposal(ctx context.Context, id task.TaskID, proposal *task.Proposal) error {
+	return tagTaskErr(r.db.UpsertTaskProposal(ctx, sqlc.UpsertTaskProposalParams{
+		TaskID:    id.String(),
+		Attempt:   int64(proposal.Attempt),
+		Summary:   proposal.Summary,
+		Approach:  proposal.Approach,
+		Files:     marshalProposalFiles(proposal.Files),
+		Risks:     marshalJSONStrings(proposal.Risks),
+		CreatedAt: proposal.CreatedAt.Unix(),
+	}))
+}
+
+func (r *TaskRepository) ReadTaskProposal(ctx context.Context, id task.TaskID) (*task.Proposal, error) {
+	row, err := r.db.ReadTaskProposal(ctx, id.String())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	return unmarshalTaskProposal(row), nil
+}
+
+func (r *TaskRepository) ApproveTaskProposal(ctx context.Context, id task.TaskID, approvedAt time.Time) (bool, error) {
+	rows, err := r.db.ApproveTaskProposal(ctx, sqlc.ApproveTaskProposalParams{
+		ApprovedAt: ptr(approvedAt.Unix()),
+		TaskID:     id.String(),
+	})
+	return rows > 0, tagTaskErr(err)
+}
+
+func (r *TaskRepository) SetTaskPlanOnly(ctx context.Context, id task.TaskID, planOnly bool) error {
+	var planOnlyInt int64
+	if planOnly {
+		planOnlyInt = 1
+	}
+	return tagTaskErr(r.db.SetTaskPlanOnly(ctx, sqlc.SetTaskPlanOnlyParams{
+		PlanOnly: planOnlyInt,
+		ID:       id.String(),
+	}))
+}
+
+func (r *TaskRepository) SetTaskActiveModel(ctx context.Context, id task.TaskID, model string) error {
+	return tagTaskErr(r.db.SetTaskActiveModel(ctx, sqlc.SetTaskActiveModelParams{
+		ActiveModel: model,
+		ID:          id.String(),
+	}))
+}
+
+func (r *TaskRepository) SetTaskSelfReview(ctx context.Context, id task.TaskID, review *task.SelfReview) error {
+	return tagTaskErr(r.db.UpsertTaskSelfReview(ctx, sqlc.UpsertTaskSelfReviewParams{
+		TaskID:     id.String(),
+		Score:      int64(review.Score),
+		Checks:     marshalSelfReviewChecks(review.Checks),
+		HeadSha:    review.HeadSHA,
+		CommentID:  review.CommentID,
+		ReviewedAt: review.ReviewedAt.Unix(),
+	}))
+}
+
+func (r *TaskRepository) ReadTaskSelfReview(ctx context.Context, id task.TaskID) (*task.SelfReview, error) {
+	row, err := r.db.ReadTaskSelfReview(ctx, id.String())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	return unmarshalTaskSelfReview(row), nil
+}
+
+func (r *TaskRepository) RecordTaskCallback(ctx context.Context, id task.TaskID, attempt int, key string) (bool, error) {
+	rows, err := r.db.RecordTaskCallback(ctx, sqlc.RecordTaskCallbackParams{
+		TaskID:         id.String(),
+		Attempt:        int64(attempt),
+		IdempotencyKey: key,
+	})
+	return rows > 0, tagTaskErr(err)
+}
+
+func (r *TaskRepository) DeleteTaskCallback(ctx context.Context, id task.TaskID, attempt int, key string) error {
+	return tagTaskErr(r.db.DeleteTaskCallback(ctx, sqlc.DeleteTaskCallbackParams{
+		TaskID:         id.String(),
+		Attempt:        int64(attempt),
+		IdempotencyKey: key,
+	}))
+}
+
+func (r *TaskRepository) DeleteExpiredTaskCallbacks(ctx context.Context, before time.Time) (int64, error) {
+	return r.db.DeleteTaskCallbacksBefore(ctx, before.Unix())
+}
+
+func (r *TaskRepository) ListOverdueReviews(ctx context.Context, now time.Time) ([]*task.OverdueReview, error) {
+	rows, err := r.db.ListOverdueReviews(ctx, now.Unix())
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	reviews := make([]*task.OverdueReview, len(rows))
+	for i, row := range rows {
+		reviews[i] = &task.OverdueReview{
+			ID:        row.ID,
+			TaskID:    task.MustParseTaskID(row.TaskID),
+			StartedAt: unixToTime(row.StartedAt),
+			SLA:       time.Duration(row.ReviewSlaSeconds) * time.Second,
+		}
+	}
+	return reviews, nil
+}
+
+func (r *TaskRepository) MarkReviewOverdue(ctx context.Context, id int64, at time.Time) (bool, error) {
+	rows, err := r.db.MarkReviewOverdue(ctx, sqlc.MarkReviewOverdueParams{
+		OverdueAt: ptr(at.Unix()),
+		ID:        id,
+	})
+	return rows > 0, tagTaskErr(err)
+}
+
+func (r *TaskRepository) ListReviewSpans(ctx context.Context, repoID string, since time.Time) ([]*task.ReviewSpan, error) {
+	rows, err := r.db.ListReviewSpans(ctx, sqlc.ListReviewSpansParams{
+		RepoID: repoID,
+		Since:  since.Unix(),
+	})
+	if err != nil {
+		return nil, tagTaskErr(err)
+	}
+	spans := make([]*task.ReviewSpan, len(rows))
+	for i, row := range rows {
+		spans[i] = &task.ReviewSpan{
+			TaskID:    task.MustParseTaskID(row.TaskID),
+			RepoID:    row.RepoID,
+			Title:     row.TaskTitle,
+			StartedAt: unixToTime(row.StartedAt),
+			EndedAt:   unixPtrToTimePtr(row.EndedAt),
+			SLA:       time.Duration(row.ReviewSlaSeconds) * time.Second,
+		}
+		if row.TaskNumber != nil {
+			spans[i].TaskNumber = int(*row.TaskNumber)
+		}
+	}
+	return spans, nil
+}
+
 func (r *TaskRepository) WithTx(txn tx.Tx) task.Repository {
 	return r.txer.WithTx(r, txn)
 }
@@ -0,0 +1,152 @@
+package sqlite_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/sqlite"
+	"github.com/vervesh/verve/internal/task"
+)
+
+func TestTaskRepository_DeleteExpiredLogs(t *testing.T) {
+	db := sqlite.NewTestDB(t)
+	ctx := context.Background()
+
+	r, err := repo.NewRepo("owner/test-repo")
+	require.NoError(t, err)
+	require.NoError(t, sqlite.NewRepoRepository(db).CreateRepo(ctx, r))
+
+	tasks := sqlite.NewTaskRepository(db)
+	tsk := task.NewTask(r.ID.String(), "title", "desc", nil, nil, 0, false, false, "", true)
+	require.NoError(t, tasks.CreateTask(ctx, tsk))
+
+	// Spans several delete chunks.
+	const batches = 1234
+	for i := 0; i < batches; i++ {
+		require.NoError(t, tasks.AppendTaskLogs(ctx, tsk.ID, 1, []string{"line"}))
+	}
+
+	n, err := tasks.DeleteExpiredLogs(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, n, "expected recent logs to be kept")
+
+	n, err = tasks.DeleteExpiredLogs(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(batches), n)
+
+	logs, err := tasks.ReadTaskLogs(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Empty(t, logs)
+}
+
+func TestTaskRepository_TaskEvents(t *testing.T) {
+	db := sqlite.NewTestDB(t)
+	ctx := context.Background()
+
+	r, err := repo.NewRepo("owner/test-repo")
+	require.NoError(t, err)
+	require.NoError(t, sqlite.NewRepoRepository(db).CreateRepo(ctx, r))
+
+	tasks := sqlite.NewTaskRepository(db)
+	tsk := task.NewTask(r.ID.String(), "title", "desc", nil, nil, 0, false, false, "", true)
+	require.NoError(t, tasks.CreateTask(ctx, tsk))
+
+	events := []*task.AgentEvent{
+		{Type: task.AgentEventToolCall, Payload: json.RawMessage(`{"name":"Edit"}`)},
+		{Type: task.AgentEventNoChanges},
+	}
+	require.NoError(t, tasks.AppendTaskEvents(ctx, tsk.ID, 2, events))
+	assert.NotZero(t, events[0].ID, "expected IDs to be set")
+	assert.Greater(t, events[1].ID, events[0].ID)
+	assert.Equal(t, 2, events[1].Attempt)
+	assert.JSONEq(t, `{}`, string(events[1].Payload))
+
+	stored, err := tasks.ListTaskEvents(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, events, stored)
+
+	require.NoError(t, tasks.DeleteTaskLogs(ctx, tsk.ID))
+	stored, err = tasks.ListTaskEvents(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+}
+
+func TestTaskRepository_ListOverrunTasks(t *testing.T) {
+	db := sqlite.NewTestDB(t)
+	ctx := context.Background()
+
+	repos := sqlite.NewRepoRepository(db)
+	r, err := repo.NewRepo("owner/test-repo")
+	require.NoError(t, err)
+	require.NoError(t, repos.CreateRepo(ctx, r))
+
+	tasks := sqlite.NewTaskRepository(db)
+	seed := func(maxRuntimeSeconds int) *task.Task {
+		tsk := task.NewTask(r.ID.String(), "title", "desc", nil, nil, 0, false, false, "", true)
+		tsk.MaxRuntimeSeconds = maxRuntimeSeconds
+		require.NoError(t, tasks.CreateTask(ctx, tsk))
+		claimed, err := tasks.ClaimTask(ctx, tsk.ID)
+		require.NoError(t, err)
+		require.True(t, claimed)
+		return tsk
+	}
+	short := seed(60)
+	long := seed(3 * 3600)
+	inherits := seed(0)
+
+	overrun, err := tasks.ListOverrunTasks(ctx, time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, overrun, 1, "tasks without a max runtime have no limit")
+	assert.Equal(t, short.ID, overrun[0].ID)
+	assert.Equal(t, 60, overrun[0].MaxRuntimeSeconds)
+
+	// The repo default applies to tasks that don't set their own.
+	require.NoError(t, repos.UpdateRepoMaxRuntime(ctx, r.ID, 3600))
+	overrun, err = tasks.ListOverrunTasks(ctx, time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	ids := make([]task.TaskID, 0, len(overrun))
+	for _, tsk := range overrun {
+		ids = append(ids, tsk.ID)
+	}
+	assert.ElementsMatch(t, []task.TaskID{short.ID, inherits.ID}, ids)
+	assert.NotContains(t, ids, long.ID)
+
+	overrun, err = tasks.ListOverrunTasks(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, overrun)
+}
+
+func TestTaskRepository_AppendTaskLogBatches(t *testing.T) {
+	db := sqlite.NewTestDB(t)
+	ctx := context.Background()
+
+	r, err := repo.NewRepo("owner/test-repo")
+	require.NoError(t, err)
+	require.NoError(t, sqlite.NewRepoRepository(db).CreateRepo(ctx, r))
+
+	tasks := sqlite.NewTaskRepository(db)
+	tsk1 := task.NewTask(r.ID.String(), "title", "desc", nil, nil, 0, false, false, "", true)
+	tsk2 := task.NewTask(r.ID.String(), "title", "desc", nil, nil, 0, false, false, "", true)
+	require.NoError(t, tasks.CreateTask(ctx, tsk1))
+	require.NoError(t, tasks.CreateTask(ctx, tsk2))
+
+	require.NoError(t, tasks.AppendTaskLogBatches(ctx, []task.LogBatch{
+		{TaskID: tsk1.ID, Attempt: 1, Lines: []string{"a1"}},
+		{TaskID: tsk2.ID, Attempt: 1, Lines: []string{"b1", "b2"}},
+		{TaskID: tsk1.ID, Attempt: 1, Lines: []string{"a2"}},
+	}))
+	require.NoError(t, tasks.AppendTaskLogBatches(ctx, nil))
+
+	logs, err := tasks.ReadTaskLogs(ctx, tsk1.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a1", "a2"}, logs)
+	logs, err = tasks.ReadTaskLogs(ctx, tsk2.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b1", "b2"}, logs)
+}
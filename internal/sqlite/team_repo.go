@@ -0,0 +1,90 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/joshjon/kit/errtag"
+
+	"github.com/vervesh/verve/internal/sqlite/sqlc"
+	"github.com/vervesh/verve/internal/team"
+)
+
+var _ team.Repository = (*TeamRepository)(nil)
+
+// TeamRepository implements team.Repository using SQLite.
+type TeamRepository struct {
+	db *sqlc.Queries
+}
+
+// NewTeamRepository creates a new TeamRepository backed by the given SQLite DB.
+func NewTeamRepository(db DB) *TeamRepository {
+	return &TeamRepository{db: sqlc.New(db)}
+}
+
+func (r *TeamRepository) CreateTeam(ctx context.Context, t *team.Team) error {
+	return tagTeamErr(r.db.CreateTeam(ctx, sqlc.CreateTeamParams{
+		ID:                t.ID.String(),
+		Name:              t.Name,
+		DefaultModel:      t.DefaultModel,
+		DefaultMaxCostUsd: t.DefaultMaxCostUSD,
+		CreatedAt:         t.CreatedAt.Unix(),
+	}))
+}
+
+func (r *TeamRepository) ReadTeam(ctx context.Context, id team.TeamID) (*team.Team, error) {
+	row, err := r.db.ReadTeam(ctx, id.String())
+	if err != nil {
+		return nil, tagTeamErr(err)
+	}
+	return unmarshalTeam(row), nil
+}
+
+func (r *TeamRepository) ListTeams(ctx context.Context) ([]*team.Team, error) {
+	rows, err := r.db.ListTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*team.Team, len(rows))
+	for i, row := range rows {
+		out[i] = unmarshalTeam(row)
+	}
+	return out, nil
+}
+
+func (r *TeamRepository) UpdateTeam(ctx context.Context, t *team.Team) error {
+	return tagTeamErr(r.db.UpdateTeam(ctx, sqlc.UpdateTeamParams{
+		Name:              t.Name,
+		DefaultModel:      t.DefaultModel,
+		DefaultMaxCostUsd: t.DefaultMaxCostUSD,
+		ID:                t.ID.String(),
+	}))
+}
+
+func (r *TeamRepository) DeleteTeam(ctx context.Context, id team.TeamID) error {
+	return tagTeamErr(r.db.DeleteTeam(ctx, id.String()))
+}
+
+func unmarshalTeam(in *sqlc.Team) *team.Team {
+	return &team.Team{
+		ID:                team.MustParseTeamID(in.ID),
+		Name:              in.Name,
+		DefaultModel:      in.DefaultModel,
+		DefaultMaxCostUSD: in.DefaultMaxCostUsd,
+		CreatedAt:         unixToTime(in.CreatedAt),
+	}
+}
+
+func tagTeamErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return errtag.Tag[team.ErrTagTeamNotFound](err)
+	}
+	if isSQLiteErrCode(err, sqliteConstraint, sqliteConstraintUnique, sqliteConstraintPrimaryKey) {
+		return errtag.Tag[team.ErrTagTeamConflict](err)
+	}
+	return err
+}
@@ -0,0 +1,55 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/vervesh/verve/internal/sqlite/sqlc"
+	"github.com/vervesh/verve/internal/task"
+	"github.com/vervesh/verve/internal/throughput"
+)
+
+var _ throughput.Repository = (*ThroughputRepository)(nil)
+
+// ThroughputRepository implements throughput.Repository using SQLite.
+type ThroughputRepository struct {
+	db *sqlc.Queries
+}
+
+// NewThroughputRepository creates a new ThroughputRepository backed by the given SQLite DB.
+func NewThroughputRepository(db DB) *ThroughputRepository {
+	return &ThroughputRepository{db: sqlc.New(db)}
+}
+
+func (r *ThroughputRepository) ListCreationsSince(ctx context.Context, since time.Time) ([]throughput.Creation, error) {
+	rows, err := r.db.ListTaskCreationsSince(ctx, since.Unix())
+	if err != nil {
+		return nil, err
+	}
+	creations := make([]throughput.Creation, len(rows))
+	for i, row := range rows {
+		creations[i] = throughput.Creation{
+			RepoID:    row.RepoID,
+			CreatedAt: unixToTime(row.CreatedAt),
+		}
+	}
+	return creations, nil
+}
+
+func (r *ThroughputRepository) ListTransitionsSince(ctx context.Context, since time.Time) ([]throughput.Transition, error) {
+	rows, err := r.db.ListTaskTransitionsSince(ctx, since.Unix())
+	if err != nil {
+		return nil, err
+	}
+	transitions := make([]throughput.Transition, len(rows))
+	for i, row := range rows {
+		transitions[i] = throughput.Transition{
+			TaskID:        row.TaskID,
+			RepoID:        row.RepoID,
+			TaskCreatedAt: unixToTime(row.TaskCreatedAt),
+			To:            task.Status(row.ToStatus),
+			At:            unixToTime(row.CreatedAt),
+		}
+	}
+	return transitions, nil
+}
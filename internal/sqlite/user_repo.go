@@ -0,0 +1,150 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/joshjon/kit/errtag"
+
+	"github.com/vervesh/verve/internal/sqlite/sqlc"
+	"github.com/vervesh/verve/internal/user"
+)
+
+var _ user.Repository = (*UserRepository)(nil)
+
+// UserRepository implements user.Repository using SQLite.
+type UserRepository struct {
+	db *sqlc.Queries
+}
+
+// NewUserRepository creates a new UserRepository backed by the given SQLite DB.
+func NewUserRepository(db DB) *UserRepository {
+	return &UserRepository{db: sqlc.New(db)}
+}
+
+func (r *UserRepository) CreateUser(ctx context.Context, u *user.User) error {
+	return tagUserErr(r.db.CreateUser(ctx, sqlc.CreateUserParams{
+		ID:        u.ID.String(),
+		Name:      u.Name,
+		Role:      string(u.Role),
+		TokenHash: u.TokenHash,
+		CreatedAt: u.CreatedAt.Unix(),
+	}))
+}
+
+func (r *UserRepository) ReadUser(ctx context.Context, id user.UserID) (*user.User, error) {
+	row, err := r.db.ReadUser(ctx, id.String())
+	if err != nil {
+		return nil, tagUserErr(err)
+	}
+	return unmarshalUser(row), nil
+}
+
+func (r *UserRepository) ReadUserByTokenHash(ctx context.Context, tokenHash string) (*user.User, error) {
+	row, err := r.db.ReadUserByTokenHash(ctx, tokenHash)
+	if err != nil {
+		return nil, tagUserErr(err)
+	}
+	return unmarshalUser(row), nil
+}
+
+func (r *UserRepository) ListUsers(ctx context.Context) ([]*user.User, error) {
+	rows, err := r.db.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*user.User, len(rows))
+	for i, row := range rows {
+		out[i] = unmarshalUser(row)
+	}
+	return out, nil
+}
+
+func (r *UserRepository) UpdateUserRole(ctx context.Context, id user.UserID, role user.Role) error {
+	n, err := r.db.UpdateUserRole(ctx, sqlc.UpdateUserRoleParams{
+		Role: string(role),
+		ID:   id.String(),
+	})
+	return tagUserErr(rowsAffectedErr(n, err))
+}
+
+func (r *UserRepository) UpdateUserTokenHash(ctx context.Context, id user.UserID, tokenHash string) error {
+	n, err := r.db.UpdateUserTokenHash(ctx, sqlc.UpdateUserTokenHashParams{
+		TokenHash: tokenHash,
+		ID:        id.String(),
+	})
+	return tagUserErr(rowsAffectedErr(n, err))
+}
+
+func (r *UserRepository) DeleteUser(ctx context.Context, id user.UserID) error {
+	return tagUserErr(r.db.DeleteUser(ctx, id.String()))
+}
+
+func (r *UserRepository) CreateSession(ctx context.Context, s *user.Session) error {
+	return tagUserErr(r.db.CreateUserSession(ctx, sqlc.CreateUserSessionParams{
+		ID:        s.ID.String(),
+		Subject:   s.Subject,
+		Name:      s.Name,
+		Role:      string(s.Role),
+		TokenHash: s.TokenHash,
+		ExpiresAt: s.ExpiresAt.Unix(),
+		CreatedAt: s.CreatedAt.Unix(),
+	}))
+}
+
+func (r *UserRepository) ReadSessionByTokenHash(ctx context.Context, tokenHash string) (*user.Session, error) {
+	row, err := r.db.ReadUserSessionByTokenHash(ctx, tokenHash)
+	if err != nil {
+		return nil, tagUserErr(err)
+	}
+	return &user.Session{
+		ID:        user.MustParseSessionID(row.ID),
+		Subject:   row.Subject,
+		Name:      row.Name,
+		Role:      user.Role(row.Role),
+		TokenHash: row.TokenHash,
+		ExpiresAt: unixToTime(row.ExpiresAt),
+		CreatedAt: unixToTime(row.CreatedAt),
+	}, nil
+}
+
+func (r *UserRepository) DeleteSessionByTokenHash(ctx context.Context, tokenHash string) error {
+	return r.db.DeleteUserSessionByTokenHash(ctx, tokenHash)
+}
+
+func (r *UserRepository) DeleteExpiredSessions(ctx context.Context, now time.Time) error {
+	return r.db.DeleteExpiredUserSessions(ctx, now.Unix())
+}
+
+func unmarshalUser(in *sqlc.User) *user.User {
+	return &user.User{
+		ID:        user.MustParseUserID(in.ID),
+		Name:      in.Name,
+		Role:      user.Role(in.Role),
+		TokenHash: in.TokenHash,
+		CreatedAt: unixToTime(in.CreatedAt),
+	}
+}
+
+// rowsAffectedErr reports sql.ErrNoRows when an update matched no rows.
+func rowsAffectedErr(n int64, err error) error {
+	if err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return err
+}
+
+func tagUserErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return errtag.Tag[user.ErrTagUserNotFound](err)
+	}
+	if isSQLiteErrCode(err, sqliteConstraint, sqliteConstraintUnique, sqliteConstraintPrimaryKey) {
+		return errtag.Tag[user.ErrTagUserConflict](err)
+	}
+	return err
+}
@@ -0,0 +1,165 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/joshjon/kit/errtag"
+
+	"github.com/vervesh/verve/internal/sqlite/sqlc"
+	"github.com/vervesh/verve/internal/webhook"
+)
+
+var _ webhook.Repository = (*WebhookRepository)(nil)
+
+// WebhookRepository implements webhook.Repository using SQLite.
+type WebhookRepository struct {
+	db *sqlc.Queries
+}
+
+// NewWebhookRepository creates a new WebhookRepository backed by the given SQLite DB.
+func NewWebhookRepository(db DB) *WebhookRepository {
+	return &WebhookRepository{
+		db: sqlc.New(db),
+	}
+}
+
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, sub *webhook.Subscription) error {
+	eventTypesJSON, _ := json.Marshal(sub.EventTypes)
+	var repoID *string
+	if sub.RepoID != "" {
+		repoID = ptr(sub.RepoID)
+	}
+	err := r.db.CreateWebhookSubscription(ctx, sqlc.CreateWebhookSubscriptionParams{
+		ID:         sub.ID.String(),
+		Url:        sub.URL,
+		Secret:     sub.Secret,
+		EventTypes: string(eventTypesJSON),
+		RepoID:     repoID,
+		CreatedAt:  sub.CreatedAt.Unix(),
+	})
+	return tagWebhookErr(err)
+}
+
+func (r *WebhookRepository) ReadSubscription(ctx context.Context, id webhook.SubscriptionID) (*webhook.Subscription, error) {
+	row, err := r.db.ReadWebhookSubscription(ctx, id.String())
+	if err != nil {
+		return nil, tagWebhookErr(err)
+	}
+	return unmarshalWebhookSubscription(row), nil
+}
+
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context) ([]*webhook.Subscription, error) {
+	rows, err := r.db.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*webhook.Subscription, len(rows))
+	for i := range rows {
+		out[i] = unmarshalWebhookSubscription(rows[i])
+	}
+	return out, nil
+}
+
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, id webhook.SubscriptionID) error {
+	return tagWebhookErr(r.db.DeleteWebhookSubscription(ctx, id.String()))
+}
+
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, d *webhook.Delivery) error {
+	params := sqlc.CreateWebhookDeliveryParams{
+		ID:             d.ID.String(),
+		SubscriptionID: d.SubscriptionID.String(),
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Attempt:        int64(d.Attempt),
+		DurationMs:     d.DurationMs,
+		CreatedAt:      d.CreatedAt.Unix(),
+	}
+	if d.StatusCode != 0 {
+		params.StatusCode = ptr(int64(d.StatusCode))
+	}
+	if d.Error != "" {
+		params.Error = ptr(d.Error)
+	}
+	if d.ResponseBody != "" {
+		params.ResponseBody = ptr(d.ResponseBody)
+	}
+	return tagWebhookErr(r.db.CreateWebhookDelivery(ctx, params))
+}
+
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, subID webhook.SubscriptionID, limit int) ([]*webhook.Delivery, error) {
+	rows, err := r.db.ListWebhookDeliveries(ctx, sqlc.ListWebhookDeliveriesParams{
+		SubscriptionID: subID.String(),
+		Limit:          int64(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*webhook.Delivery, len(rows))
+	for i := range rows {
+		out[i] = unmarshalWebhookDelivery(rows[i])
+	}
+	return out, nil
+}
+
+func (r *WebhookRepository) PruneDeliveries(ctx context.Context, subID webhook.SubscriptionID, keep int) error {
+	return r.db.PruneWebhookDeliveries(ctx, sqlc.PruneWebhookDeliveriesParams{
+		SubscriptionID: subID.String(),
+		Keep:           int64(keep),
+	})
+}
+
+func unmarshalWebhookSubscription(in *sqlc.WebhookSubscription) *webhook.Subscription {
+	s := &webhook.Subscription{
+		ID:        webhook.MustParseSubscriptionID(in.ID),
+		URL:       in.Url,
+		Secret:    in.Secret,
+		CreatedAt: unixToTime(in.CreatedAt),
+	}
+	if in.RepoID != nil {
+		s.RepoID = *in.RepoID
+	}
+	_ = json.Unmarshal([]byte(in.EventTypes), &s.EventTypes)
+	if s.EventTypes == nil {
+		s.EventTypes = []string{}
+	}
+	return s
+}
+
+func unmarshalWebhookDelivery(in *sqlc.WebhookDelivery) *webhook.Delivery {
+	d := &webhook.Delivery{
+		ID:             webhook.MustParseDeliveryID(in.ID),
+		SubscriptionID: webhook.MustParseSubscriptionID(in.SubscriptionID),
+		EventID:        in.EventID,
+		EventType:      in.EventType,
+		Attempt:        int(in.Attempt),
+		DurationMs:     in.DurationMs,
+		CreatedAt:      unixToTime(in.CreatedAt),
+	}
+	if in.StatusCode != nil {
+		d.StatusCode = int(*in.StatusCode)
+	}
+	if in.Error != nil {
+		d.Error = *in.Error
+	}
+	if in.ResponseBody != nil {
+		d.ResponseBody = *in.ResponseBody
+	}
+	d.Success = d.Error == "" && d.StatusCode >= 200 && d.StatusCode < 300
+	return d
+}
+
+func tagWebhookErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return errtag.Tag[webhook.ErrTagSubscriptionNotFound](err)
+	}
+	if isSQLiteErrCode(err, sqliteConstraint, sqliteConstraintUnique, sqliteConstraintPrimaryKey) {
+		return errtag.Tag[webhook.ErrTagSubscriptionConflict](err)
+	}
+	return err
+}
@@ -0,0 +1,56 @@
+package task
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// AgentEventType identifies the kind of structured event an agent reports.
+type AgentEventType string
+
+const (
+	AgentEventPRCreated    AgentEventType = "pr_created"    // {"url", "number"}
+	AgentEventPRUpdated    AgentEventType = "pr_updated"    // {"url", "number"}
+	AgentEventBranchPushed AgentEventType = "branch_pushed" // {"branch"}
+	AgentEventStatus       AgentEventType = "status"        // The agent's VERVE_STATUS object
+	AgentEventNoChanges    AgentEventType = "no_changes"    // {}
+	AgentEventCost         AgentEventType = "cost"          // {"cost_usd"}
+	AgentEventToolCall     AgentEventType = "tool_call"     // {"name", "detail"}
+	AgentEventPlan         AgentEventType = "plan"          // {"steps": ["title", ...]}
+	AgentEventStep         AgentEventType = "step"          // {"index", "status": "started"|"completed"}
+	AgentEventNote         AgentEventType = "note"          // {"text"}
+
+	AgentEventBudgetExceeded AgentEventType = "budget_exceeded" // {"cost_usd", "budget_usd"}
+)
+
+// AllAgentEventTypes lists every supported agent event type.
+var AllAgentEventTypes = []AgentEventType{
+	AgentEventPRCreated,
+	AgentEventPRUpdated,
+	AgentEventBranchPushed,
+	AgentEventStatus,
+	AgentEventNoChanges,
+	AgentEventCost,
+	AgentEventToolCall,
+	AgentEventPlan,
+	AgentEventStep,
+	AgentEventNote,
+	AgentEventBudgetExceeded,
+}
+
+// ValidAgentEventType returns true if the given agent event type is supported.
+func ValidAgentEventType(t AgentEventType) bool {
+	return slices.Contains(AllAgentEventTypes, t)
+}
+
+// AgentEvent is a structured event reported by an agent during an attempt,
+// stored alongside the attempt's plain log lines. Payload is a JSON object
+// whose shape depends on Type.
+type AgentEvent struct {
+	ID        int64           `json:"id"`
+	Attempt   int             `json:"attempt"`
+	Type      AgentEventType  `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+}
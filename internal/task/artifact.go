@@ -0,0 +1,68 @@
+package task
+
+import (
+	"crypto/rand"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Artifact limits.
+const (
+	// MaxArtifactSize caps the size of one artifact.
+	MaxArtifactSize = 25 << 20
+	// MaxArtifactsPerAttempt caps the number of artifacts an attempt can
+	// record.
+	MaxArtifactsPerAttempt = 50
+	// maxArtifactNameLength caps the length of an artifact's name.
+	maxArtifactNameLength = 255
+)
+
+// Artifact is a file an agent produced during an attempt, such as a test
+// report, coverage summary or screenshot, kept for reviewers after the agent's
+// container is gone. Its contents live in attachment storage.
+type Artifact struct {
+	ID          int64     `json:"id"`
+	TaskID      string    `json:"task_id"`
+	Attempt     int       `json:"attempt"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	StorageKey  string    `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CleanArtifactName returns name as a clean slash-separated relative path,
+// such as "coverage/summary.txt", or false if it is empty, too long, escapes
+// its directory, has hidden segments or contains control characters.
+func CleanArtifactName(name string) (string, bool) {
+	name = path.Clean(strings.ReplaceAll(strings.TrimSpace(name), `\`, "/"))
+	if name == "." || strings.HasPrefix(name, "/") || len(name) > maxArtifactNameLength {
+		return "", false
+	}
+	if strings.IndexFunc(name, unicode.IsControl) >= 0 {
+		return "", false
+	}
+	for _, segment := range strings.Split(name, "/") {
+		if segment[0] == '.' {
+			return "", false
+		}
+	}
+	return name, true
+}
+
+// ArtifactContentType returns the content type an artifact is served with.
+// Artifacts of the types attachments allow keep that type; anything else is
+// served as a download of unknown type.
+func ArtifactContentType(name string) string {
+	if contentType, ok := attachmentTypes[strings.ToLower(path.Ext(name))]; ok {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// newArtifactKey returns a new storage key for one of a task's artifacts.
+func newArtifactKey(id TaskID) string {
+	return id.String() + "/artifacts/" + rand.Text()
+}
@@ -0,0 +1,113 @@
+package task
+
+import (
+	"context"
+	"crypto/rand"
+	"io"
+	"maps"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Attachment limits.
+const (
+	// DefaultMaxAttachmentSize caps the size of one attachment unless the
+	// store is configured with another limit.
+	DefaultMaxAttachmentSize = 10 << 20
+	// MaxAttachmentsPerTask caps the number of files attached to a task.
+	MaxAttachmentsPerTask = 20
+	// maxAttachmentFilenameLength caps the length of an attachment's name.
+	maxAttachmentFilenameLength = 255
+)
+
+// attachmentTypes maps the file extensions attachments may have to the
+// content type they are stored and served with. Binary types must also be
+// recognised by http.DetectContentType as that type; text types must be
+// detected as text.
+var attachmentTypes = map[string]string{
+	".png":   "image/png",
+	".jpg":   "image/jpeg",
+	".jpeg":  "image/jpeg",
+	".gif":   "image/gif",
+	".webp":  "image/webp",
+	".pdf":   "application/pdf",
+	".txt":   "text/plain",
+	".log":   "text/plain",
+	".md":    "text/markdown",
+	".csv":   "text/csv",
+	".json":  "application/json",
+	".yaml":  "application/yaml",
+	".yml":   "application/yaml",
+	".xml":   "application/xml",
+	".diff":  "text/x-diff",
+	".patch": "text/x-diff",
+}
+
+// AttachmentStorage holds the contents of task attachments by key.
+type AttachmentStorage interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Attachment is a file attached to a task for its agent to read, such as a
+// design screenshot or an error log. Its contents live in attachment storage.
+type Attachment struct {
+	ID          int64     `json:"id"`
+	TaskID      string    `json:"task_id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	StorageKey  string    `json:"-"`
+	UploadedBy  string    `json:"uploaded_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CleanAttachmentFilename returns the base name of an uploaded file, or false
+// if it is empty, hidden, too long or contains control characters.
+func CleanAttachmentFilename(name string) (string, bool) {
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.TrimSpace(name)
+	if name == "" || name[0] == '.' || len(name) > maxAttachmentFilenameLength {
+		return "", false
+	}
+	if strings.IndexFunc(name, unicode.IsControl) >= 0 {
+		return "", false
+	}
+	return name, true
+}
+
+// AttachmentContentType returns the content type an attachment named
+// filename is stored and served with, or false if its extension is not an
+// allowed type or data does not look like that type.
+func AttachmentContentType(filename string, data []byte) (string, bool) {
+	contentType, ok := attachmentTypes[strings.ToLower(filepath.Ext(filename))]
+	if !ok {
+		return "", false
+	}
+	detected := http.DetectContentType(data)
+	switch {
+	case strings.HasPrefix(contentType, "image/"), contentType == "application/pdf":
+		return contentType, detected == contentType
+	default:
+		return contentType, strings.HasPrefix(detected, "text/")
+	}
+}
+
+// newAttachmentKey returns a new storage key for one of a task's
+// attachments. Keys are random so a replaced file never reuses a key.
+func newAttachmentKey(id TaskID) string {
+	return id.String() + "/" + rand.Text()
+}
+
+// AttachmentExtensions returns the file extensions attachments may have, in
+// sorted order.
+func AttachmentExtensions() []string {
+	return slices.Sorted(maps.Keys(attachmentTypes))
+}
@@ -0,0 +1,28 @@
+package task
+
+import "time"
+
+// Attempt results that don't correspond to a task status. Otherwise an
+// attempt's result is the status the task moved to when the attempt ended.
+const (
+	AttemptResultRetried = "retried" // Agent hit a retryable error and the task was requeued
+	AttemptResultStopped = "stopped" // Attempt was interrupted by the user
+	// AttemptResultAbandoned marks an attempt handed back by a worker
+	// that shut down while running it.
+	AttemptResultAbandoned = "abandoned"
+)
+
+// Attempt describes a single run of a task by an agent. An attempt starts
+// when a worker claims the task and ends when the task leaves running. Logs
+// are keyed by attempt number.
+type Attempt struct {
+	Number      int    `json:"number"`
+	RetryReason string `json:"retry_reason,omitempty"`
+	// Model is the model the attempt's agent ran with, which differs from
+	// the task's model once it has fallen back after a rate limit.
+	Model     string     `json:"model,omitempty"`
+	Result    string     `json:"result,omitempty"`
+	CostUSD   float64    `json:"cost_usd"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"sync"
+	"time"
 )
 
 // EventType identifies the kind of SSE event.
@@ -13,10 +14,35 @@ const (
 	EventTaskDeleted  = "task_deleted"
 	EventLogsAppended = "logs_appended"
 	EventRepoUpdated  = "repo_updated"
+
+	EventAgentEventsAppended = "agent_events_appended"
+	EventTaskProgress        = "task_progress"
+	EventTaskNudges          = "task_nudges"
+	EventTaskProvenance      = "task_provenance"
+
+	EventCommentCreated = "comment_created"
+	EventCommentUpdated = "comment_updated"
+	EventCommentDeleted = "comment_deleted"
+
+	// EventReviewOverdue is published once for each stay in review that
+	// outlasts the repo's review SLA.
+	EventReviewOverdue = "review_overdue"
 )
 
+// DefaultReplayBufferSize is the number of recent events a Broker keeps so
+// reconnecting subscribers can resume without missing events.
+const DefaultReplayBufferSize = 1000
+
+// subscriberBufferSize is the capacity of each subscriber channel.
+const subscriberBufferSize = 64
+
 // Event represents a task or repo mutation broadcast to SSE subscribers.
 type Event struct {
+	// ID increases monotonically for each event fanned out by this Broker.
+	// IDs are assigned on fan-out, so they are local to the server instance.
+	// They start from the Broker's creation time in microseconds so IDs
+	// from before a server restart are never mistaken for current ones.
+	ID      uint64   `json:"id,omitempty"`
 	Type    string   `json:"type"`
 	RepoID  string   `json:"repo_id,omitempty"`
 	Task    *Task    `json:"task,omitempty"`
@@ -24,44 +50,123 @@ type Event struct {
 	Logs    []string `json:"logs,omitempty"`
 	Attempt int      `json:"attempt,omitempty"`
 	Repo    any      `json:"repo,omitempty"`
+
+	AgentEvents []*AgentEvent     `json:"agent_events,omitempty"`
+	Progress    *Progress         `json:"progress,omitempty"`
+	Nudges      []*Nudge          `json:"nudges,omitempty"`
+	Provenance  *ProvenanceReview `json:"provenance,omitempty"`
+	Comment     *Comment          `json:"comment,omitempty"`
+
+	// Remote is set on events published by another server instance and
+	// received through the Notifier's shared event bus. Subscribers with
+	// side effects, such as webhook delivery, skip them so each event acts
+	// once across instances.
+	Remote bool `json:"-"`
 }
 
-// Notifier sends event payloads to an external notification system.
-// The listen side of the notification system calls Broker.Receive to
-// complete the fan-out.
+// Notifier sends event payloads to an external notification system shared
+// by the server instances. The listen side of the notification system calls
+// Broker.Receive on every other instance to complete the fan-out; an
+// instance's own events are already fanned out locally, so it must not
+// receive them again.
 type Notifier interface {
 	Notify(ctx context.Context, payload []byte) error
 }
 
+// PendingNotifier tells the other server instances that tasks may have
+// become claimable, so their waiting polls look for them again without
+// waiting for the poll to time out. The listen side calls Store.WakePending
+// on every other instance.
+type PendingNotifier interface {
+	NotifyPending(ctx context.Context) error
+}
+
 // Broker fans out task events to SSE subscribers. When a Notifier is
-// configured, events are routed through the external notification system
-// so that multiple server instances can share events. When nil, events
-// are fanned out directly in-process.
+// configured, events are also sent through the external notification system
+// so that subscribers on other server instances receive them. When nil,
+// events only reach this instance's subscribers.
 type Broker struct {
 	mu       sync.RWMutex
 	subs     map[chan Event]struct{}
 	notifier Notifier
+
+	// replay is a ring buffer of recent events. The event with ID n is
+	// stored at index (n-1) % len(replay).
+	replay  []Event
+	startID uint64
+	lastID  uint64
+}
+
+// BrokerOption configures a Broker.
+type BrokerOption func(b *Broker)
+
+// WithReplayBufferSize sets how many recent events are kept for Resume. A
+// size of zero disables replay.
+func WithReplayBufferSize(size int) BrokerOption {
+	return func(b *Broker) {
+		b.replay = make([]Event, max(size, 0))
+	}
 }
 
 // NewBroker creates a new Broker. If notifier is non-nil, Publish sends
 // events through the external notification system; otherwise events are
 // fanned out locally.
-func NewBroker(notifier Notifier) *Broker {
-	return &Broker{
+func NewBroker(notifier Notifier, opts ...BrokerOption) *Broker {
+	startID := uint64(time.Now().UnixMicro())
+	b := &Broker{
 		subs:     make(map[chan Event]struct{}),
 		notifier: notifier,
+		replay:   make([]Event, DefaultReplayBufferSize),
+		startID:  startID,
+		lastID:   startID,
+	}
+	for _, opt := range opts {
+		opt(b)
 	}
+	return b
 }
 
 // Subscribe returns a buffered channel that receives task events.
 func (b *Broker) Subscribe() chan Event {
-	ch := make(chan Event, 64)
+	ch := make(chan Event, subscriberBufferSize)
 	b.mu.Lock()
 	b.subs[ch] = struct{}{}
 	b.mu.Unlock()
 	return ch
 }
 
+// Resume subscribes to task events and returns the buffered events published
+// after afterID, so a reconnecting subscriber receives every event it missed
+// followed by new events on the channel. ok is false when the missed events
+// are no longer buffered (or afterID is from before a server restart), in
+// which case the subscriber must reload its state.
+func (b *Broker) Resume(afterID uint64) (ch chan Event, missed []Event, ok bool) {
+	ch = make(chan Event, subscriberBufferSize)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs[ch] = struct{}{}
+
+	if afterID > b.lastID {
+		return ch, nil, false
+	}
+	oldest := b.lastID - min(b.lastID-b.startID, uint64(len(b.replay))) + 1
+	if afterID+1 < oldest {
+		return ch, nil, false
+	}
+	for id := afterID + 1; id <= b.lastID; id++ {
+		missed = append(missed, b.replay[(id-1)%uint64(len(b.replay))])
+	}
+	return ch, missed, true
+}
+
+// LastEventID returns the ID of the most recently fanned out event. Before
+// any events it returns the ID the first event will follow.
+func (b *Broker) LastEventID() uint64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.lastID
+}
+
 // Unsubscribe removes and closes a subscriber channel.
 func (b *Broker) Unsubscribe(ch chan Event) {
 	b.mu.Lock()
@@ -70,31 +175,37 @@ func (b *Broker) Unsubscribe(ch chan Event) {
 	close(ch)
 }
 
-// Publish sends an event. If a notifier is configured, the event is
-// serialized and sent through the external notification system (the
-// LISTEN side calls Receive to fan out locally). Otherwise, fans out
-// directly to local subscribers.
+// Publish fans an event out to local subscribers. If a notifier is
+// configured, the event is also serialized and sent through the external
+// notification system, whose listen side calls Receive on the other
+// instances.
 func (b *Broker) Publish(ctx context.Context, event Event) {
+	b.fanOut(event)
 	if b.notifier != nil {
 		payload, err := json.Marshal(event)
 		if err != nil {
 			return
 		}
 		_ = b.notifier.Notify(ctx, payload)
-		return
 	}
-	b.fanOut(event)
 }
 
-// Receive is called by an external listener (e.g., PG LISTEN loop) to
-// inject an event for local fan-out to SSE subscribers.
+// Receive is called by an external listener, such as the shared event bus,
+// to fan out an event published by another instance to local subscribers.
+// The event is marked Remote.
 func (b *Broker) Receive(event Event) {
+	event.Remote = true
 	b.fanOut(event)
 }
 
 func (b *Broker) fanOut(event Event) {
-	b.mu.RLock()
-	defer b.mu.RUnlock()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lastID++
+	event.ID = b.lastID
+	if len(b.replay) > 0 {
+		b.replay[(event.ID-1)%uint64(len(b.replay))] = event
+	}
 	for ch := range b.subs {
 		select {
 		case ch <- event:
@@ -2,6 +2,7 @@ package task
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -67,6 +68,7 @@ func TestBroker_Receive(t *testing.T) {
 	select {
 	case received := <-ch:
 		assert.Equal(t, EventLogsAppended, received.Type)
+		assert.True(t, received.Remote, "received events come from another instance")
 	case <-time.After(time.Second):
 		require.Fail(t, "timed out waiting for event")
 	}
@@ -90,15 +92,19 @@ func TestBroker_PublishWithNotifier(t *testing.T) {
 	event := Event{Type: EventTaskCreated, RepoID: "repo_123"}
 	broker.Publish(context.Background(), event)
 
-	// When notifier is set, events go through notifier, not directly to subscribers
-	assert.Len(t, notifier.payloads, 1)
+	// The event is sent to the other instances through the notifier...
+	require.Len(t, notifier.payloads, 1)
+	var sent Event
+	require.NoError(t, json.Unmarshal(notifier.payloads[0], &sent))
+	assert.Equal(t, EventTaskCreated, sent.Type)
 
-	// Subscriber should NOT receive directly (goes through notifier)
+	// ...and fanned out locally right away, not marked remote.
 	select {
-	case <-ch:
-		assert.Fail(t, "expected no direct event when notifier is set")
-	case <-time.After(50 * time.Millisecond):
-		// Expected: event goes to notifier instead
+	case received := <-ch:
+		assert.Equal(t, EventTaskCreated, received.Type)
+		assert.False(t, received.Remote)
+	case <-time.After(time.Second):
+		require.Fail(t, "timed out waiting for event")
 	}
 }
 
@@ -132,3 +138,84 @@ func TestEventConstants(t *testing.T) {
 	assert.Equal(t, "task_updated", EventTaskUpdated)
 	assert.Equal(t, "logs_appended", EventLogsAppended)
 }
+
+func TestBroker_AssignsIncreasingIDs(t *testing.T) {
+	broker := NewBroker(nil)
+	ch := broker.Subscribe()
+	defer broker.Unsubscribe(ch)
+
+	start := broker.LastEventID()
+	assert.NotZero(t, start)
+
+	broker.Publish(context.Background(), Event{Type: EventTaskCreated})
+	broker.Publish(context.Background(), Event{Type: EventTaskUpdated})
+
+	first, second := <-ch, <-ch
+	assert.Equal(t, start+1, first.ID)
+	assert.Equal(t, start+2, second.ID)
+	assert.Equal(t, second.ID, broker.LastEventID())
+}
+
+func TestBroker_Resume(t *testing.T) {
+	broker := NewBroker(nil)
+	ctx := context.Background()
+	start := broker.LastEventID()
+	for i := 1; i <= 3; i++ {
+		broker.Publish(ctx, Event{Type: EventTaskUpdated, Attempt: i})
+	}
+
+	ch, missed, ok := broker.Resume(start + 1)
+	defer broker.Unsubscribe(ch)
+	require.True(t, ok)
+	require.Len(t, missed, 2)
+	assert.Equal(t, 2, missed[0].Attempt)
+	assert.Equal(t, 3, missed[1].Attempt)
+
+	// New events continue on the channel.
+	broker.Publish(ctx, Event{Type: EventTaskUpdated, Attempt: 4})
+	select {
+	case received := <-ch:
+		assert.Equal(t, start+4, received.ID)
+	case <-time.After(time.Second):
+		require.Fail(t, "timed out waiting for event")
+	}
+}
+
+func TestBroker_ResumeUpToDate(t *testing.T) {
+	broker := NewBroker(nil)
+	broker.Publish(context.Background(), Event{Type: EventTaskUpdated})
+
+	ch, missed, ok := broker.Resume(broker.LastEventID())
+	defer broker.Unsubscribe(ch)
+	assert.True(t, ok)
+	assert.Empty(t, missed)
+}
+
+func TestBroker_ResumeGap(t *testing.T) {
+	broker := NewBroker(nil, WithReplayBufferSize(2))
+	ctx := context.Background()
+	start := broker.LastEventID()
+	for i := 1; i <= 3; i++ {
+		broker.Publish(ctx, Event{Type: EventTaskUpdated, Attempt: i})
+	}
+
+	ch, missed, ok := broker.Resume(start)
+	broker.Unsubscribe(ch)
+	assert.False(t, ok, "expected evicted events to require a reload")
+	assert.Empty(t, missed)
+
+	ch, missed, ok = broker.Resume(start + 1)
+	broker.Unsubscribe(ch)
+	require.True(t, ok)
+	require.Len(t, missed, 2)
+	assert.Equal(t, 2, missed[0].Attempt)
+	assert.Equal(t, 3, missed[1].Attempt)
+
+	// IDs from a previous server run are ahead of or behind the buffer.
+	ch, _, ok = broker.Resume(start + 100)
+	broker.Unsubscribe(ch)
+	assert.False(t, ok)
+	ch, _, ok = broker.Resume(1)
+	broker.Unsubscribe(ch)
+	assert.False(t, ok)
+}
@@ -0,0 +1,143 @@
+package task
+
+import (
+	"context"
+	"errors"
+
+	"github.com/joshjon/kit/errtag"
+
+	"github.com/vervesh/verve/internal/msgcat"
+)
+
+// BulkAction is an action BulkTaskAction applies to each of several tasks.
+type BulkAction string
+
+const (
+	BulkActionClose    BulkAction = "close"
+	BulkActionDelete   BulkAction = "delete"
+	BulkActionSetReady BulkAction = "set-ready"
+	BulkActionRetry    BulkAction = "retry"
+)
+
+// Valid reports whether a is a known bulk action.
+func (a BulkAction) Valid() bool {
+	switch a {
+	case BulkActionClose, BulkActionDelete, BulkActionSetReady, BulkActionRetry:
+		return true
+	}
+	return false
+}
+
+// BulkActionParams selects the tasks of a repo BulkTaskAction acts on and
+// how.
+type BulkActionParams struct {
+	Action BulkAction
+	// TaskIDs are the tasks to act on. When empty, every unarchived task of
+	// the repo with Status is acted on instead.
+	TaskIDs []TaskID
+	Status  Status
+
+	// Reason is the close reason of BulkActionClose.
+	Reason string
+	// Ready is the ready flag BulkActionSetReady sets.
+	Ready bool
+	// Instructions are the retry instructions of BulkActionRetry.
+	Instructions string
+}
+
+// BulkActionResult is the outcome of a bulk action for one task.
+type BulkActionResult struct {
+	TaskID  TaskID
+	Applied bool
+	// Skipped is why the action was not applied, when it wasn't.
+	Skipped string
+	// Task is the task before the action, or nil when it was not found.
+	Task *Task
+}
+
+// BulkTaskAction applies p.Action to the selected tasks of a repo in one
+// transaction and publishes their updates once committed. A task the action
+// doesn't apply to, such as a retry of a task that is not failed or a task of
+// another repo, is skipped and reported in its result. Any other failure
+// rolls back the whole action.
+func (s *Store) BulkTaskAction(ctx context.Context, repoID string, p BulkActionParams) ([]BulkActionResult, error) {
+	// Resolved up front since the resolver reads through other stores,
+	// which can't see the transaction.
+	policy, err := s.retryPolicy(ctx, repoID)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []BulkActionResult
+	effects, err := s.inTx(ctx, policy, func(ctx context.Context, ts *Store) error {
+		results = nil
+		ids := p.TaskIDs
+		if len(ids) == 0 {
+			tasks, err := ts.repo.ListTasksByRepo(ctx, repoID)
+			if err != nil {
+				return err
+			}
+			for _, t := range tasks {
+				if t.Status == p.Status {
+					ids = append(ids, t.ID)
+				}
+			}
+		}
+		for _, id := range ids {
+			res, err := ts.bulkAction(ctx, repoID, id, p)
+			if err != nil {
+				return err
+			}
+			results = append(results, res)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	effects.flush(ctx, s)
+	return results, nil
+}
+
+func (s *Store) bulkAction(ctx context.Context, repoID string, id TaskID, p BulkActionParams) (BulkActionResult, error) {
+	res := BulkActionResult{TaskID: id}
+	t, err := s.repo.ReadTask(ctx, id)
+	if errtag.HasTag[ErrTagTaskNotFound](err) || (err == nil && t.RepoID != repoID) {
+		res.Skipped = msgcat.Text(msgcat.ErrTaskNotFound)
+		return res, nil
+	}
+	if err != nil {
+		return res, err
+	}
+	res.Task = t
+
+	switch p.Action {
+	case BulkActionClose:
+		if t.Status == StatusClosed || t.Status == StatusMerged {
+			res.Skipped = msgcat.Text(msgcat.ErrTaskAlreadyClosed)
+			return res, nil
+		}
+		err = s.CloseTask(ctx, id, p.Reason)
+	case BulkActionDelete:
+		err = s.DeleteTask(ctx, id)
+	case BulkActionSetReady:
+		if t.Status != StatusPending {
+			res.Skipped = msgcat.Text(msgcat.ErrTaskNotPending)
+			return res, nil
+		}
+		err = s.SetReady(ctx, id, p.Ready)
+	case BulkActionRetry:
+		if t.Status != StatusFailed {
+			res.Skipped = msgcat.Text(msgcat.ErrTaskNotFailed)
+			return res, nil
+		}
+		err = s.ManualRetryTask(ctx, id, p.Instructions)
+	default:
+		return res, errors.New("unknown bulk action " + string(p.Action))
+	}
+	if err != nil {
+		return res, err
+	}
+	res.Applied = true
+	return res, nil
+}
@@ -0,0 +1,165 @@
+package task
+
+import (
+	"context"
+	"time"
+
+	"github.com/joshjon/kit/tx"
+)
+
+// CallbackRetention is how long the idempotency keys of worker callbacks are
+// kept. A worker resends a callback within seconds of losing its response,
+// so this only needs to outlast the worker's retries.
+const CallbackRetention = 24 * time.Hour
+
+// applyOnce runs fn with a copy of the store whose writes share one
+// transaction, so a failure part-way through leaves the task as it was.
+// The events and notifications fn causes are held back until the
+// transaction commits and then sent once per task.
+//
+// key identifies the worker callback fn applies. When the attempt already
+// applied a callback with the same key, fn is skipped and applyOnce returns
+// false. An empty key is never deduplicated, and attempt 0 means the task's
+// current attempt. fn must only write through the store it is given: the
+// transaction holds the database's write lock until fn returns.
+func (s *Store) applyOnce(ctx context.Context, id TaskID, attempt int, key string, fn func(ctx context.Context, ts *Store) error) (bool, error) {
+	t, err := s.repo.ReadTask(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if attempt == 0 {
+		attempt = t.Attempt
+	}
+	// Resolved up front since the resolver reads through other stores,
+	// which can't see the transaction.
+	policy, err := s.retryPolicy(ctx, t.RepoID)
+	if err != nil {
+		return false, err
+	}
+
+	var applied bool
+	effects, err := s.inTx(ctx, policy, func(ctx context.Context, ts *Store) error {
+		applied = false
+		if key != "" {
+			ok, err := ts.repo.RecordTaskCallback(ctx, id, attempt, key)
+			if err != nil || !ok {
+				return err
+			}
+		}
+		applied = true
+		return fn(ctx, ts)
+	})
+	if err != nil {
+		return false, err
+	}
+	if _, ok := effects.tasks[id]; ok {
+		effects.tasks[id] = t.Status
+	}
+	effects.flush(ctx, s)
+	return applied, nil
+}
+
+// inTx runs fn with a copy of the store whose writes share one transaction
+// and resolve policy as every repo's retry policy. It returns the events and
+// notifications fn caused, for the caller to flush once it has adjusted
+// them.
+func (s *Store) inTx(ctx context.Context, policy RetryPolicy, fn func(ctx context.Context, ts *Store) error) (*txEffects, error) {
+	var effects *txEffects
+	err := s.repo.BeginTxFunc(ctx, func(ctx context.Context, _ tx.Tx, repo Repository) error {
+		effects = &txEffects{tasks: make(map[TaskID]Status)}
+		return fn(ctx, s.txStore(repo, policy, effects))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return effects, nil
+}
+
+// AppendTaskLogsOnce is AppendTaskLogs for the worker callback identified by
+// key. It returns false without appending when the attempt already appended
+// the callback's lines. An empty key is never deduplicated.
+func (s *Store) AppendTaskLogsOnce(ctx context.Context, id TaskID, attempt int, key string, logs []string) (bool, error) {
+	if key == "" {
+		return true, s.AppendTaskLogs(ctx, id, attempt, logs)
+	}
+	// Recorded first rather than in a transaction with the append so the
+	// lines can still be batched with other agents' logs.
+	ok, err := s.repo.RecordTaskCallback(ctx, id, attempt, key)
+	if err != nil || !ok {
+		return false, err
+	}
+	if err := s.AppendTaskLogs(ctx, id, attempt, logs); err != nil {
+		// Let the worker's retry append the lines.
+		_ = s.repo.DeleteTaskCallback(ctx, id, attempt, key)
+		return false, err
+	}
+	return true, nil
+}
+
+// DeleteExpiredCallbacks deletes the idempotency keys of worker callbacks
+// recorded more than retention ago. Returns the number deleted.
+func (s *Store) DeleteExpiredCallbacks(ctx context.Context, retention time.Duration) (int64, error) {
+	return s.repo.DeleteExpiredTaskCallbacks(ctx, time.Now().Add(-retention))
+}
+
+// txStore returns a copy of the store that writes through repo, a
+// transaction, and records its events and notifications in effects.
+func (s *Store) txStore(repo Repository, policy RetryPolicy, effects *txEffects) *Store {
+	return &Store{
+		repo:              repo,
+		broker:            s.broker,
+		statusListener:    s.statusListener,
+		retryPolicies:     fixedRetryPolicy(policy),
+		trashRetention:    s.trashRetention,
+		attachments:       s.attachments,
+		maxAttachmentSize: s.maxAttachmentSize,
+		effects:           effects,
+	}
+}
+
+// txEffects are the events and notifications held back until a transaction
+// commits.
+type txEffects struct {
+	// Tasks updated in the transaction and their status before it, or ""
+	// when only updates that keep the status were made.
+	tasks   map[TaskID]Status
+	deleted []Event
+	// Storage keys of the attachments of deleted tasks.
+	attachmentKeys []string
+	pending        bool
+}
+
+// taskUpdated records an update to a task. prev is its status before the
+// update when the update may change it.
+func (e *txEffects) taskUpdated(id TaskID, prev Status) {
+	if cur, ok := e.tasks[id]; !ok || cur == "" {
+		e.tasks[id] = prev
+	}
+}
+
+// flush publishes one update per task through the committed store s,
+// notifying the status listener of tasks whose status changed.
+func (e *txEffects) flush(ctx context.Context, s *Store) {
+	s.deleteAttachmentContents(ctx, e.attachmentKeys)
+	for _, event := range e.deleted {
+		delete(e.tasks, event.TaskID)
+		s.broker.Publish(ctx, event)
+	}
+	for id, prev := range e.tasks {
+		if prev == "" {
+			s.publishTaskUpdated(ctx, id)
+		} else {
+			s.publishStatusChange(ctx, id, prev)
+		}
+	}
+	if e.pending {
+		s.notifyPending()
+	}
+}
+
+// fixedRetryPolicy resolves the same retry policy for every repo.
+func fixedRetryPolicy(p RetryPolicy) RetryPolicyResolver {
+	return RetryPolicyResolverFunc(func(context.Context, string) (RetryPolicy, error) {
+		return p, nil
+	})
+}
@@ -0,0 +1,12 @@
+package task
+
+import "time"
+
+// CIDispatch records the repo's CI workflow being dispatched on a task's PR
+// branch. Check-status logic waits for the run created by the latest
+// dispatch before treating the PR's checks as passing.
+type CIDispatch struct {
+	WorkflowFile string    `json:"workflow_file"`
+	Ref          string    `json:"ref"`
+	DispatchedAt time.Time `json:"dispatched_at"`
+}
@@ -0,0 +1,24 @@
+package task
+
+import "time"
+
+// MaxCommentLength caps the length of a comment's body.
+const MaxCommentLength = 10000
+
+// AnonymousAuthor is the author of comments left by unauthenticated callers
+// that don't name themselves.
+const AnonymousAuthor = "anonymous"
+
+// Comment is a message a person left on a task to discuss it or review its
+// work. A comment can refer to one of the task's attempts, such as review
+// notes on the pull request an attempt opened.
+type Comment struct {
+	ID        int64     `json:"id"`
+	TaskID    string    `json:"task_id"`
+	Attempt   *int      `json:"attempt,omitempty"`
+	Author    string    `json:"author"`
+	AuthorID  string    `json:"author_id,omitempty"` // Empty for comments left without a user token
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,125 @@
+package task
+
+import (
+	"context"
+
+	"github.com/vervesh/verve/internal/msgcat"
+)
+
+// CompletionParams is the outcome of a task attempt as reported by its
+// worker.
+type CompletionParams struct {
+	// Attempt the outcome is for, or 0 for the task's current attempt.
+	Attempt int
+	// IdempotencyKey identifies the report so a resent one is applied at
+	// most once. Empty means the report is never deduplicated.
+	IdempotencyKey string
+
+	Success     bool
+	Error       string
+	Retryable   bool
+	AgentStatus string
+	CostUSD     float64
+	NoChanges   bool
+
+	PullRequestURL string
+	PRNumber       int
+	// PullRequests are the PRs opened in a multi-repo task's additional
+	// repos.
+	PullRequests []PullRequest
+	ShadowDiff   string
+	Proposal     *Proposal
+	BranchName   string
+
+	MaxRuntimeExceeded bool
+	BudgetExceeded     bool
+}
+
+// OpenedPullRequest reports whether the outcome records PRs the agent
+// opened.
+func (p CompletionParams) OpenedPullRequest() bool {
+	return !p.MaxRuntimeExceeded && !p.BudgetExceeded && p.Success &&
+		(p.PullRequestURL != "" || len(p.PullRequests) > 0)
+}
+
+// CompleteTask records the outcome of a running task's attempt in one
+// transaction, so a failure part-way through leaves the task running, and
+// publishes one update for it once committed. Returns ErrTaskNotRunning if
+// the task is not running and ErrAttemptEnded if p is for an earlier
+// attempt. Returns false without applying p when an outcome with the same
+// idempotency key was already recorded for the attempt.
+func (s *Store) CompleteTask(ctx context.Context, id TaskID, p CompletionParams) (bool, error) {
+	return s.applyOnce(ctx, id, p.Attempt, p.IdempotencyKey, func(ctx context.Context, ts *Store) error {
+		return ts.complete(ctx, id, p)
+	})
+}
+
+func (s *Store) complete(ctx context.Context, id TaskID, p CompletionParams) error {
+	t, err := s.repo.ReadTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	if p.Attempt != 0 && p.Attempt != t.Attempt {
+		return ErrAttemptEnded
+	}
+	if t.Status != StatusRunning {
+		return ErrTaskNotRunning
+	}
+
+	if p.AgentStatus != "" {
+		if err := s.SetAgentStatus(ctx, id, p.AgentStatus); err != nil {
+			return err
+		}
+	}
+	if p.CostUSD > 0 {
+		if err := s.AddCost(ctx, id, p.CostUSD); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case p.MaxRuntimeExceeded:
+		if err := s.SetCloseReason(ctx, id, msgcat.Text(msgcat.TaskFailedMaxRuntime)); err != nil {
+			return err
+		}
+		return s.FailTask(ctx, id, FailureMaxRuntime)
+	case p.BudgetExceeded:
+		// The retry's budget check fails the task now that the attempt's
+		// cost is recorded, unless its max cost was raised meanwhile.
+		return s.ScheduleRetry(ctx, id, FailureBudgetExceeded+": "+p.Error)
+	case !p.Success:
+		if p.Retryable {
+			return s.ScheduleRetry(ctx, id, RetryCategoryRateLimit+": "+p.Error)
+		}
+		if t.HasPullRequest() || t.BranchName != "" {
+			return s.UpdateTaskStatus(ctx, id, StatusReview)
+		}
+		return s.FailTask(ctx, id, FailureAgentError)
+	case p.OpenedPullRequest():
+		if p.PullRequestURL != "" {
+			if err := s.SetTaskPullRequest(ctx, id, p.PullRequestURL, p.PRNumber); err != nil {
+				return err
+			}
+		}
+		if len(p.PullRequests) > 0 {
+			return s.SetTaskPullRequests(ctx, id, p.PullRequests)
+		}
+		return nil
+	case p.ShadowDiff != "":
+		return s.RecordShadowDiff(ctx, id, p.ShadowDiff)
+	case p.Proposal != nil:
+		return s.RecordProposal(ctx, id, p.Proposal)
+	case p.BranchName != "":
+		return s.SetTaskBranch(ctx, id, p.BranchName)
+	default:
+		if t.HasPullRequest() || t.BranchName != "" {
+			return s.UpdateTaskStatus(ctx, id, StatusReview)
+		}
+		if p.NoChanges {
+			if err := s.SetCloseReason(ctx, id, msgcat.Text(msgcat.TaskClosedNoChanges)); err != nil {
+				return err
+			}
+		}
+		return s.UpdateTaskStatus(ctx, id, StatusClosed)
+	}
+}
@@ -0,0 +1,63 @@
+package task
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// Failure categories recorded on failed tasks. A task failed by the circuit
+// breaker is recorded under the kind of its retry category instead, e.g.
+// "ci_failure", "rate_limit" or "security_alert".
+const (
+	FailureMaxAttempts    = "max_attempts"    // Retry budget used up
+	FailureBudgetExceeded = "budget_exceeded" // Cost reached max_cost_usd
+	FailureAgentError     = "agent_error"     // Agent failed without a PR or branch
+	FailureWorkerTimeout  = "worker_timeout"  // No heartbeat from the worker
+	FailureMaxRuntime     = "max_runtime"     // Attempt ran past max_runtime_seconds
+	FailureProtectedPath  = "protected_path"  // PR changes protected paths
+	FailureForced         = "forced"          // Forced to failed by an admin
+	// FailureUnknown groups failed tasks with no recorded category, such as
+	// tasks that failed before categories were recorded.
+	FailureUnknown = "unknown"
+)
+
+// failureKind returns the failure category for a retry category: its kind,
+// the part before the first colon.
+func failureKind(category string) string {
+	kind, _, _ := strings.Cut(category, ":")
+	return kind
+}
+
+// FailureCategoryCount is the number of failed tasks in a failure category.
+type FailureCategoryCount struct {
+	Category string `json:"category"`
+	Count    int    `json:"count"`
+}
+
+// CountFailureCategories groups the failed tasks among tasks by failure
+// category, largest group first.
+func CountFailureCategories(tasks []*Task) []FailureCategoryCount {
+	counts := make(map[string]int)
+	for _, t := range tasks {
+		if t.Status != StatusFailed {
+			continue
+		}
+		category := t.FailureCategory
+		if category == "" {
+			category = FailureUnknown
+		}
+		counts[category]++
+	}
+	out := make([]FailureCategoryCount, 0, len(counts))
+	for category, count := range counts {
+		out = append(out, FailureCategoryCount{Category: category, Count: count})
+	}
+	slices.SortFunc(out, func(a, b FailureCategoryCount) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Category, b.Category)
+	})
+	return out
+}
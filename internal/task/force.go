@@ -0,0 +1,53 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/joshjon/kit/errtag"
+)
+
+// ErrTagInvalidForcedStatus indicates a forced status change was rejected
+// because it would leave the task in a state the rest of the system can't
+// handle.
+type ErrTagInvalidForcedStatus struct{ errtag.Conflict }
+
+func (e ErrTagInvalidForcedStatus) Unwrap() error {
+	return errtag.Tag[errtag.Conflict](e.Cause())
+}
+
+// ValidStatus reports whether s is a known task status.
+func ValidStatus(s Status) bool {
+	switch s {
+	case StatusPending, StatusRunning, StatusReview, StatusMerged, StatusClosed, StatusFailed:
+		return true
+	}
+	return false
+}
+
+// ValidateForcedStatus checks whether t may be forced into status to. Forced
+// changes bypass the normal lifecycle, but some transitions can never be
+// repaired into a consistent state:
+//   - merged tasks are final because their pull request has been merged
+//   - only a worker can move a task to running by claiming it
+//   - review requires a pull request or branch, and merged a pull request
+func ValidateForcedStatus(t *Task, to Status) error {
+	var reason string
+	switch {
+	case !ValidStatus(to):
+		reason = fmt.Sprintf("unknown task status %q", to)
+	case t.Status == to:
+		reason = fmt.Sprintf("task is already %s", to)
+	case t.Status == StatusMerged:
+		reason = "merged tasks cannot change status because their pull request has been merged"
+	case to == StatusRunning:
+		reason = "tasks cannot be forced to running because only a worker can claim a task"
+	case to == StatusReview && t.PullRequestURL == "" && len(t.PullRequests) == 0 && t.BranchName == "":
+		reason = "task has no pull request or branch to review"
+	case to == StatusMerged && t.PullRequestURL == "" && len(t.PullRequests) == 0:
+		reason = "task has no pull request to mark as merged"
+	default:
+		return nil
+	}
+	return errtag.Tag[ErrTagInvalidForcedStatus](errors.New(reason), errtag.WithMsg(reason))
+}
@@ -0,0 +1,40 @@
+package task
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateForcedStatus(t *testing.T) {
+	tests := map[string]struct {
+		task    Task
+		to      Status
+		wantErr bool
+	}{
+		"pending to failed":         {task: Task{Status: StatusPending}, to: StatusFailed},
+		"running to pending":        {task: Task{Status: StatusRunning}, to: StatusPending},
+		"failed to review with PR":  {task: Task{Status: StatusFailed, PullRequestURL: "https://github.com/o/r/pull/1"}, to: StatusReview},
+		"failed to review w/branch": {task: Task{Status: StatusFailed, BranchName: "verve/x"}, to: StatusReview},
+		"review to merged":          {task: Task{Status: StatusReview, PullRequestURL: "https://github.com/o/r/pull/1"}, to: StatusMerged},
+		"unknown status":            {task: Task{Status: StatusPending}, to: "paused", wantErr: true},
+		"same status":               {task: Task{Status: StatusPending}, to: StatusPending, wantErr: true},
+		"merged to running":         {task: Task{Status: StatusMerged}, to: StatusRunning, wantErr: true},
+		"merged to pending":         {task: Task{Status: StatusMerged}, to: StatusPending, wantErr: true},
+		"failed to running":         {task: Task{Status: StatusFailed}, to: StatusRunning, wantErr: true},
+		"review without PR":         {task: Task{Status: StatusFailed}, to: StatusReview, wantErr: true},
+		"merged without PR":         {task: Task{Status: StatusReview, BranchName: "verve/x"}, to: StatusMerged, wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateForcedStatus(&tt.task, tt.to)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			var tagErr ErrTagInvalidForcedStatus
+			assert.True(t, errors.As(err, &tagErr), "expected ErrTagInvalidForcedStatus, got %v", err)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package task
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// MaxLabels is the most labels a task, repo or worker may have.
+const MaxLabels = 20
+
+// labelPattern matches a worker label such as "gpu" or "region.eu-west".
+var labelPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,62}$`)
+
+// ValidateLabels reports whether labels can be used as worker labels or as a
+// task's or repo's required labels.
+func ValidateLabels(labels []string) error {
+	if len(labels) > MaxLabels {
+		return fmt.Errorf("at most %d labels are allowed", MaxLabels)
+	}
+	for _, l := range labels {
+		if !labelPattern.MatchString(l) {
+			return fmt.Errorf("invalid label %q: must be lowercase letters, digits, '.', '_' or '-', starting with a letter or digit, and at most 63 characters", l)
+		}
+	}
+	return nil
+}
+
+// HasLabels reports whether have includes every label in required. Any set
+// of labels satisfies an empty required list.
+func HasLabels(have, required []string) bool {
+	for _, l := range required {
+		if !slices.Contains(have, l) {
+			return false
+		}
+	}
+	return true
+}
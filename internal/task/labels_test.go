@@ -0,0 +1,43 @@
+package task
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateLabels(t *testing.T) {
+	tests := map[string]struct {
+		labels  []string
+		wantErr bool
+	}{
+		"none":          {nil, false},
+		"valid":         {[]string{"gpu", "region.eu-west-1", "arch_arm64"}, false},
+		"uppercase":     {[]string{"GPU"}, true},
+		"leading dash":  {[]string{"-gpu"}, true},
+		"space":         {[]string{"gpu large"}, true},
+		"empty":         {[]string{""}, true},
+		"too long":      {[]string{strings.Repeat("a", 64)}, true},
+		"too many":      {make([]string, MaxLabels+1), true},
+		"max length ok": {[]string{strings.Repeat("a", 63)}, false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateLabels(tt.labels)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHasLabels(t *testing.T) {
+	assert.True(t, HasLabels(nil, nil))
+	assert.True(t, HasLabels([]string{"gpu"}, nil))
+	assert.True(t, HasLabels([]string{"gpu", "linux"}, []string{"linux"}))
+	assert.False(t, HasLabels(nil, []string{"gpu"}))
+	assert.False(t, HasLabels([]string{"gpu"}, []string{"gpu", "linux"}))
+}
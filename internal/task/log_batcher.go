@@ -0,0 +1,98 @@
+package task
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// maxLogBatchesPerFlush caps the log batches written by one insert. A flush
+// that fills up is written without waiting out the rest of the interval.
+const maxLogBatchesPerFlush = 100
+
+// LogBatch is a batch of log lines appended to a task attempt.
+type LogBatch struct {
+	TaskID  TaskID
+	Attempt int
+	Lines   []string
+}
+
+// logBatcher combines log appends from concurrent agents into one insert.
+//
+// The first append to arrive waits up to the interval for others to join it
+// and then writes them all; every caller still waits for its own lines to be
+// stored, so an append that returns without error is durable. This trades up
+// to one interval of latency for far fewer write transactions, which SQLite
+// serializes.
+type logBatcher struct {
+	interval time.Duration
+	repo     Repository
+
+	mu      sync.Mutex
+	pending *logFlush
+}
+
+// logFlush is the set of log batches written together by one insert.
+type logFlush struct {
+	batches []LogBatch
+	errs    []error       // Per batch, set once done is closed
+	full    chan struct{} // Closed when the flush reaches maxLogBatchesPerFlush
+	done    chan struct{}
+}
+
+// append stores batch along with any appends that arrive within the interval.
+func (b *logBatcher) append(ctx context.Context, batch LogBatch) error {
+	b.mu.Lock()
+	f := b.pending
+	leader := f == nil
+	if leader {
+		f = &logFlush{full: make(chan struct{}), done: make(chan struct{})}
+		b.pending = f
+	}
+	i := len(f.batches)
+	f.batches = append(f.batches, batch)
+	if len(f.batches) == maxLogBatchesPerFlush {
+		b.pending = nil
+		close(f.full)
+	}
+	b.mu.Unlock()
+
+	if leader {
+		b.flush(ctx, f)
+	}
+	select {
+	case <-f.done:
+		return f.errs[i]
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// flush waits for the interval to pass or f to fill up, then writes f. Other
+// callers' lines depend on it, so the write isn't cancelled with ctx.
+func (b *logBatcher) flush(ctx context.Context, f *logFlush) {
+	timer := time.NewTimer(b.interval)
+	select {
+	case <-timer.C:
+	case <-f.full:
+		timer.Stop()
+	}
+	b.mu.Lock()
+	if b.pending == f {
+		b.pending = nil
+	}
+	b.mu.Unlock()
+
+	ctx = context.WithoutCancel(ctx)
+	f.errs = make([]error, len(f.batches))
+	if err := b.repo.AppendTaskLogBatches(ctx, f.batches); err != nil && len(f.batches) > 1 {
+		// One bad batch, such as one for a deleted task, fails the whole
+		// insert. Write them one at a time so only its caller gets the error.
+		for i, batch := range f.batches {
+			f.errs[i] = b.repo.AppendTaskLogs(ctx, batch.TaskID, batch.Attempt, batch.Lines)
+		}
+	} else if err != nil {
+		f.errs[0] = err
+	}
+	close(f.done)
+}
@@ -0,0 +1,53 @@
+package task
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Limits on a task's or repo's fallback models.
+const (
+	MaxFallbackModels      = 5
+	maxFallbackModelLength = 100
+)
+
+// ValidateFallbackModels reports whether models can be used as a task's or
+// repo's fallback models.
+func ValidateFallbackModels(models []string) error {
+	if len(models) > MaxFallbackModels {
+		return fmt.Errorf("at most %d fallback models are allowed", MaxFallbackModels)
+	}
+	for i, m := range models {
+		if m == "" || strings.ContainsFunc(m, unicode.IsSpace) || len(m) > maxFallbackModelLength {
+			return fmt.Errorf("invalid fallback model %q: must be a model name without spaces, at most %d characters", m, maxFallbackModelLength)
+		}
+		if slices.Index(models, m) != i {
+			return fmt.Errorf("fallback model %q is listed more than once", m)
+		}
+	}
+	return nil
+}
+
+// RunModel returns the model the task's next attempt runs with: the
+// fallback model it switched to after being rate-limited, or its configured
+// model.
+func (t *Task) RunModel() string {
+	if t.ActiveModel != "" {
+		return t.ActiveModel
+	}
+	return t.Model
+}
+
+// nextFallbackModel returns the model after the one the task runs with in
+// its fallback chain (its model followed by its fallback models), or "" when
+// there is none left to fall back to.
+func (t *Task) nextFallbackModel() string {
+	chain := append([]string{t.Model}, t.FallbackModels...)
+	i := slices.Index(chain, t.RunModel())
+	if i < 0 || i+1 >= len(chain) {
+		return ""
+	}
+	return chain[i+1]
+}
@@ -0,0 +1,51 @@
+package task
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateFallbackModels(t *testing.T) {
+	tests := map[string]struct {
+		models  []string
+		wantErr bool
+	}{
+		"none":      {nil, false},
+		"valid":     {[]string{"sonnet", "claude-haiku-4-5"}, false},
+		"empty":     {[]string{""}, true},
+		"space":     {[]string{"son net"}, true},
+		"duplicate": {[]string{"sonnet", "sonnet"}, true},
+		"too long":  {[]string{strings.Repeat("a", maxFallbackModelLength+1)}, true},
+		"too many":  {[]string{"a", "b", "c", "d", "e", "f"}, true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateFallbackModels(tt.models)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTask_NextFallbackModel(t *testing.T) {
+	tsk := &Task{Model: "opus", FallbackModels: []string{"sonnet", "haiku"}}
+	assert.Equal(t, "opus", tsk.RunModel())
+	assert.Equal(t, "sonnet", tsk.nextFallbackModel())
+
+	tsk.ActiveModel = "sonnet"
+	assert.Equal(t, "sonnet", tsk.RunModel())
+	assert.Equal(t, "haiku", tsk.nextFallbackModel())
+
+	tsk.ActiveModel = "haiku"
+	assert.Empty(t, tsk.nextFallbackModel(), "expected no model after the last fallback")
+
+	tsk.ActiveModel = "removed"
+	assert.Empty(t, tsk.nextFallbackModel(), "expected no model once the active model left the chain")
+
+	assert.Empty(t, (&Task{Model: "opus"}).nextFallbackModel())
+}
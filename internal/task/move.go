@@ -0,0 +1,121 @@
+package task
+
+import (
+	"context"
+	"slices"
+
+	"github.com/joshjon/kit/errtag"
+)
+
+// MoveTask moves a pending task filed against the wrong repo to
+// params.RepoID. The task gets the next number in that repo and leaves its
+// epic, stack parent, PRs and branch behind, since they belong to its old
+// repo. Dependencies may only link it to tasks of the target repo: unless
+// dropDependencies is set, a move that would leave the task depending on a
+// task of another repo, or another repo's task depending on it, fails with
+// ErrDependenciesOutsideRepo. With it set, those dependencies are dropped.
+//
+// Returns the task as it was before the move, ErrTaskNotPending if it is not
+// pending and ErrTaskVersionMismatch if it has changed since
+// params.ExpectedVersion.
+func (s *Store) MoveTask(ctx context.Context, id TaskID, params MoveTaskParams, dropDependencies bool) (*Task, error) {
+	t, err := s.repo.ReadTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if params.ExpectedVersion != 0 && params.ExpectedVersion != t.Version {
+		return nil, ErrTaskVersionMismatch
+	}
+	if t.Status != StatusPending {
+		return nil, ErrTaskNotPending
+	}
+	if t.RepoID == params.RepoID {
+		return t, nil
+	}
+	// Pinned to the version read so the dependencies checked are the ones
+	// the move keeps.
+	params.ExpectedVersion = t.Version
+
+	params.DependsOn = []string{}
+	outside := false
+	for _, depID := range t.DependsOn {
+		dep, err := ParseTaskID(depID)
+		if err != nil {
+			return nil, err
+		}
+		d, err := s.repo.ReadTask(ctx, dep)
+		if err != nil && !errtag.HasTag[ErrTagTaskNotFound](err) {
+			return nil, err
+		}
+		if err == nil && d.RepoID == params.RepoID {
+			params.DependsOn = append(params.DependsOn, depID)
+		} else {
+			outside = true
+		}
+	}
+	all, err := s.repo.ListTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var dependents []TaskID
+	for _, other := range all {
+		if other.RepoID != params.RepoID && slices.Contains(other.DependsOn, id.String()) {
+			dependents = append(dependents, other.ID)
+		}
+	}
+	if (outside || len(dependents) > 0) && !dropDependencies {
+		return nil, ErrDependenciesOutsideRepo
+	}
+
+	params.AdditionalRepoIDs = []string{}
+	for _, repoID := range t.AdditionalRepoIDs {
+		if repoID != params.RepoID {
+			params.AdditionalRepoIDs = append(params.AdditionalRepoIDs, repoID)
+		}
+	}
+	policy, err := s.retryPolicy(ctx, params.RepoID)
+	if err != nil {
+		return nil, err
+	}
+	params.MaxAttempts = policy.MaxAttempts
+
+	effects, err := s.inTx(ctx, policy, func(ctx context.Context, ts *Store) error {
+		for _, dependent := range dependents {
+			if err := ts.RemoveDependency(ctx, dependent, id.String()); err != nil {
+				return err
+			}
+		}
+		ok, err := ts.repo.MoveTask(ctx, id, params)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			cur, err := ts.repo.ReadTask(ctx, id)
+			if err != nil {
+				return err
+			}
+			if cur.Status != StatusPending {
+				return ErrTaskNotPending
+			}
+			return ErrTaskVersionMismatch
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	effects.flush(ctx, s)
+
+	// The task leaves its old repo's board and joins the new one's.
+	s.broker.Publish(ctx, Event{Type: EventTaskDeleted, RepoID: t.RepoID, TaskID: id})
+	moved, err := s.repo.ReadTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	moved.Logs = nil
+	s.broker.Publish(ctx, Event{Type: EventTaskCreated, RepoID: moved.RepoID, Task: moved})
+	if moved.Ready {
+		s.notifyPending()
+	}
+	return t, nil
+}
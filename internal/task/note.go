@@ -0,0 +1,68 @@
+package task
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/vervesh/verve/internal/msgcat"
+)
+
+// MaxNoteLength caps the length of a note's text.
+const MaxNoteLength = 4000
+
+// maxNoteTitleLength caps the title of a task converted from a note, matching
+// the API's limit on task titles.
+const maxNoteTitleLength = 150
+
+// Note is a message the agent left for humans while working on a task, such
+// as follow-up work it discovered that is out of scope for the task. Agents
+// report notes with a note event (the VERVE_NOTE marker), and a note can be
+// converted into a new task.
+type Note struct {
+	ID              int64     `json:"id"`
+	TaskID          string    `json:"task_id"`
+	RepoID          string    `json:"repo_id"`
+	Attempt         int       `json:"attempt"`
+	Text            string    `json:"text"`
+	CreatedAt       time.Time `json:"created_at"`
+	ConvertedTaskID string    `json:"converted_task_id,omitempty"`
+}
+
+type noteEventPayload struct {
+	Text string `json:"text"`
+}
+
+// noteText returns the text of a note event, trimmed and capped at
+// MaxNoteLength. It returns false for other event types and empty notes.
+func noteText(ev *AgentEvent) (string, bool) {
+	if ev.Type != AgentEventNote {
+		return "", false
+	}
+	var payload noteEventPayload
+	if err := json.Unmarshal(ev.Payload, &payload); err != nil {
+		return "", false
+	}
+	text := strings.TrimSpace(payload.Text)
+	if text == "" {
+		return "", false
+	}
+	return truncateRunes(text, MaxNoteLength), true
+}
+
+// NoteTask returns a new pending task for the follow-up work described by a
+// note left on the task source. The title is the note's first line.
+func NoteTask(note *Note, source *Task, model string) *Task {
+	title, _, _ := strings.Cut(note.Text, "\n")
+	title = truncateRunes(strings.TrimSpace(title), maxNoteTitleLength)
+	description := msgcat.Text(msgcat.NoteTaskDescription, note.Text, source.Number, source.Title)
+	return NewTask(note.RepoID, title, description, nil, nil, 0, false, false, model, true)
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
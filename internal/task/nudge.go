@@ -0,0 +1,17 @@
+package task
+
+import "time"
+
+// MaxNudgeLength caps the length of a nudge message.
+const MaxNudgeLength = 4000
+
+// Nudge is a message the user sent to the agent of a running task to steer
+// it without stopping and retrying. Nudges are handed to the worker with its
+// next heartbeat, which drops them into the agent container.
+type Nudge struct {
+	ID          int64      `json:"id"`
+	Attempt     int        `json:"attempt"`
+	Message     string     `json:"message"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+}
@@ -0,0 +1,103 @@
+package task
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// StepStatus is the state of a step in an agent's plan.
+type StepStatus string
+
+const (
+	StepPending    StepStatus = "pending"
+	StepInProgress StepStatus = "in_progress"
+	StepCompleted  StepStatus = "completed"
+)
+
+// maxPlanSteps caps how many steps of a reported plan are kept.
+const maxPlanSteps = 100
+
+// Step is a single step of an agent's plan.
+type Step struct {
+	Title       string     `json:"title"`
+	Status      StepStatus `json:"status"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// Progress is the plan the agent reported for a task's current attempt: an
+// ordered step list with the status of each step. It is built up from plan
+// and step agent events.
+type Progress struct {
+	Attempt   int       `json:"attempt"`
+	Steps     []Step    `json:"steps"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type planEventPayload struct {
+	Steps []string `json:"steps"`
+}
+
+type stepEventPayload struct {
+	Index  *int   `json:"index"`
+	Status string `json:"status"`
+}
+
+// Apply updates the progress with a plan or step event, returning whether
+// the progress changed. A plan replaces the step list; steps that keep their
+// title and position within the same attempt keep their status. A step event
+// marks a step of the current attempt's plan started or completed. Other
+// event types and malformed payloads are ignored.
+func (p *Progress) Apply(ev *AgentEvent, now time.Time) bool {
+	switch ev.Type {
+	case AgentEventPlan:
+		var payload planEventPayload
+		if err := json.Unmarshal(ev.Payload, &payload); err != nil || len(payload.Steps) == 0 {
+			return false
+		}
+		if len(payload.Steps) > maxPlanSteps {
+			payload.Steps = payload.Steps[:maxPlanSteps]
+		}
+		steps := make([]Step, len(payload.Steps))
+		for i, title := range payload.Steps {
+			steps[i] = Step{Title: title, Status: StepPending}
+			if ev.Attempt == p.Attempt && i < len(p.Steps) && p.Steps[i].Title == title {
+				steps[i] = p.Steps[i]
+			}
+		}
+		p.Attempt = ev.Attempt
+		p.Steps = steps
+		p.UpdatedAt = now
+		return true
+
+	case AgentEventStep:
+		var payload stepEventPayload
+		if err := json.Unmarshal(ev.Payload, &payload); err != nil || payload.Index == nil {
+			return false
+		}
+		i := *payload.Index
+		if ev.Attempt != p.Attempt || i < 0 || i >= len(p.Steps) {
+			return false
+		}
+		step := &p.Steps[i]
+		switch payload.Status {
+		case "started":
+			if step.Status != StepPending {
+				return false
+			}
+			step.Status = StepInProgress
+			step.StartedAt = &now
+		case "completed":
+			if step.Status == StepCompleted {
+				return false
+			}
+			step.Status = StepCompleted
+			step.CompletedAt = &now
+		default:
+			return false
+		}
+		p.UpdatedAt = now
+		return true
+	}
+	return false
+}
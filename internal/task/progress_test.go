@@ -0,0 +1,153 @@
+package task
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func planEvent(attempt int, steps ...string) *AgentEvent {
+	payload, _ := json.Marshal(planEventPayload{Steps: steps})
+	return &AgentEvent{Attempt: attempt, Type: AgentEventPlan, Payload: payload}
+}
+
+func stepEvent(attempt, index int, status string) *AgentEvent {
+	payload, _ := json.Marshal(stepEventPayload{Index: &index, Status: status})
+	return &AgentEvent{Attempt: attempt, Type: AgentEventStep, Payload: payload}
+}
+
+func stepStatuses(p Progress) []StepStatus {
+	statuses := make([]StepStatus, len(p.Steps))
+	for i, s := range p.Steps {
+		statuses[i] = s.Status
+	}
+	return statuses
+}
+
+func TestProgress_Apply(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		events      []*AgentEvent
+		wantChanged bool
+		wantAttempt int
+		wantTitles  []string
+		wantStatus  []StepStatus
+	}{
+		{
+			name:        "plan sets pending steps",
+			events:      []*AgentEvent{planEvent(1, "Read code", "Fix bug")},
+			wantChanged: true,
+			wantAttempt: 1,
+			wantTitles:  []string{"Read code", "Fix bug"},
+			wantStatus:  []StepStatus{StepPending, StepPending},
+		},
+		{
+			name:        "step started",
+			events:      []*AgentEvent{planEvent(1, "Read code", "Fix bug"), stepEvent(1, 0, "started")},
+			wantChanged: true,
+			wantAttempt: 1,
+			wantTitles:  []string{"Read code", "Fix bug"},
+			wantStatus:  []StepStatus{StepInProgress, StepPending},
+		},
+		{
+			name:        "step completed",
+			events:      []*AgentEvent{planEvent(1, "Read code", "Fix bug"), stepEvent(1, 0, "started"), stepEvent(1, 0, "completed")},
+			wantChanged: true,
+			wantAttempt: 1,
+			wantTitles:  []string{"Read code", "Fix bug"},
+			wantStatus:  []StepStatus{StepCompleted, StepPending},
+		},
+		{
+			name:        "replan keeps matching steps",
+			events:      []*AgentEvent{planEvent(1, "Read code", "Fix bug"), stepEvent(1, 0, "completed"), stepEvent(1, 1, "started"), planEvent(1, "Read code", "Fix tests")},
+			wantChanged: true,
+			wantAttempt: 1,
+			wantTitles:  []string{"Read code", "Fix tests"},
+			wantStatus:  []StepStatus{StepCompleted, StepPending},
+		},
+		{
+			name:        "plan for new attempt resets steps",
+			events:      []*AgentEvent{planEvent(1, "Read code"), stepEvent(1, 0, "completed"), planEvent(2, "Read code")},
+			wantChanged: true,
+			wantAttempt: 2,
+			wantTitles:  []string{"Read code"},
+			wantStatus:  []StepStatus{StepPending},
+		},
+		{
+			name:        "step for other attempt ignored",
+			events:      []*AgentEvent{planEvent(2, "Read code"), stepEvent(1, 0, "started")},
+			wantChanged: false,
+			wantAttempt: 2,
+			wantTitles:  []string{"Read code"},
+			wantStatus:  []StepStatus{StepPending},
+		},
+		{
+			name:        "step out of range ignored",
+			events:      []*AgentEvent{planEvent(1, "Read code"), stepEvent(1, 1, "started")},
+			wantChanged: false,
+			wantAttempt: 1,
+			wantTitles:  []string{"Read code"},
+			wantStatus:  []StepStatus{StepPending},
+		},
+		{
+			name:        "unknown step status ignored",
+			events:      []*AgentEvent{planEvent(1, "Read code"), stepEvent(1, 0, "skipped")},
+			wantChanged: false,
+			wantAttempt: 1,
+			wantTitles:  []string{"Read code"},
+			wantStatus:  []StepStatus{StepPending},
+		},
+		{
+			name:        "empty plan ignored",
+			events:      []*AgentEvent{planEvent(1, "Read code"), planEvent(1)},
+			wantChanged: false,
+			wantAttempt: 1,
+			wantTitles:  []string{"Read code"},
+			wantStatus:  []StepStatus{StepPending},
+		},
+		{
+			name:        "other event types ignored",
+			events:      []*AgentEvent{planEvent(1, "Read code"), {Attempt: 1, Type: AgentEventStatus, Payload: json.RawMessage(`{}`)}},
+			wantChanged: false,
+			wantAttempt: 1,
+			wantTitles:  []string{"Read code"},
+			wantStatus:  []StepStatus{StepPending},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p Progress
+			var changed bool
+			for _, ev := range tt.events {
+				changed = p.Apply(ev, now)
+			}
+			assert.Equal(t, tt.wantChanged, changed)
+			assert.Equal(t, tt.wantAttempt, p.Attempt)
+			require.Len(t, p.Steps, len(tt.wantTitles))
+			for i, title := range tt.wantTitles {
+				assert.Equal(t, title, p.Steps[i].Title)
+			}
+			assert.Equal(t, tt.wantStatus, stepStatuses(p))
+		})
+	}
+}
+
+func TestProgress_Apply_Timestamps(t *testing.T) {
+	start := time.Now()
+	end := start.Add(time.Minute)
+
+	var p Progress
+	p.Apply(planEvent(1, "Read code"), start)
+	require.True(t, p.Apply(stepEvent(1, 0, "started"), start))
+	require.True(t, p.Apply(stepEvent(1, 0, "completed"), end))
+
+	require.NotNil(t, p.Steps[0].StartedAt)
+	require.NotNil(t, p.Steps[0].CompletedAt)
+	assert.Equal(t, start, *p.Steps[0].StartedAt)
+	assert.Equal(t, end, *p.Steps[0].CompletedAt)
+	assert.Equal(t, end, p.UpdatedAt)
+}
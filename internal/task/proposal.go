@@ -0,0 +1,45 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Proposal is the change plan produced by a task's latest plan-only run: what
+// the agent would change and how, without having written any code. Approving
+// it sends the task back to the agent to implement the plan.
+type Proposal struct {
+	Attempt    int            `json:"attempt"`
+	Summary    string         `json:"summary"`
+	Approach   string         `json:"approach"`
+	Files      []ProposalFile `json:"files"`
+	Risks      []string       `json:"risks"`
+	CreatedAt  time.Time      `json:"created_at"`
+	ApprovedAt *time.Time     `json:"approved_at,omitempty"`
+}
+
+// ProposalFile is a file a proposal expects to add, change or remove.
+type ProposalFile struct {
+	Path   string `json:"path"`
+	Change string `json:"change"`
+}
+
+// Format renders the proposal as plain text for the agent's prompt.
+func (p *Proposal) Format() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Summary: %s\n\nApproach:\n%s\n", p.Summary, p.Approach)
+	if len(p.Files) > 0 {
+		b.WriteString("\nFiles:\n")
+		for _, f := range p.Files {
+			fmt.Fprintf(&b, "- %s: %s\n", f.Path, f.Change)
+		}
+	}
+	if len(p.Risks) > 0 {
+		b.WriteString("\nRisks:\n")
+		for _, r := range p.Risks {
+			fmt.Fprintf(&b, "- %s\n", r)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,23 @@
+package task
+
+import (
+	"time"
+
+	"github.com/vervesh/verve/internal/provenance"
+)
+
+// ProvenanceReview is the latest license and provenance scan of a task's PR
+// diff. Findings must be acknowledged by a human before the PR is cleared
+// for merging.
+type ProvenanceReview struct {
+	Findings       []provenance.Finding `json:"findings"`
+	HeadSHA        string               `json:"head_sha"`
+	ScannedAt      time.Time            `json:"scanned_at"`
+	AcknowledgedAt *time.Time           `json:"acknowledged_at,omitempty"`
+}
+
+// NeedsAcknowledgment reports whether the review has findings that a human
+// has not yet acknowledged.
+func (r *ProvenanceReview) NeedsAcknowledgment() bool {
+	return len(r.Findings) > 0 && r.AcknowledgedAt == nil
+}
@@ -26,12 +26,21 @@ type TaskRepository interface {
 	ListPendingTasks(ctx context.Context) ([]*Task, error)
 	ListPendingTasksByRepos(ctx context.Context, repoIDs []string) ([]*Task, error)
 	AppendTaskLogs(ctx context.Context, id TaskID, attempt int, logs []string) error
+	// AppendTaskLogBatches appends several log batches, possibly for
+	// different tasks, in a single insert.
+	AppendTaskLogBatches(ctx context.Context, batches []LogBatch) error
 	ReadTaskLogs(ctx context.Context, id TaskID) ([]string, error)
 	StreamTaskLogs(ctx context.Context, id TaskID, fn func(attempt int, lines []string) error) error
 	UpdateTaskStatus(ctx context.Context, id TaskID, status Status) error
 	SetTaskPullRequest(ctx context.Context, id TaskID, prURL string, prNumber int) error
+	// SetTaskPullRequests replaces the pull requests recorded for a
+	// multi-repo task's additional repos.
+	SetTaskPullRequests(ctx context.Context, id TaskID, prs []PullRequest) error
 	ListTasksInReview(ctx context.Context) ([]*Task, error)
 	ListTasksInReviewByRepo(ctx context.Context, repoID string) ([]*Task, error)
+	// ListFailedTasksByRepo returns a repo's failed tasks, most recently
+	// failed first.
+	ListFailedTasksByRepo(ctx context.Context, repoID string) ([]*Task, error)
 	CloseTask(ctx context.Context, id TaskID, reason string) error
 	TaskExists(ctx context.Context, id TaskID) (bool, error)
 	ReadTaskStatus(ctx context.Context, id TaskID) (Status, error)
@@ -44,13 +53,20 @@ type TaskRepository interface {
 	// ScheduleRetryFromRunning atomically transitions a task from running → pending,
 	// increments attempt, and records the retry reason. Used when the agent hits a
 	// retryable error (e.g. Claude rate limit or session max usage exceeded).
+	// A non-nil notBefore keeps the task from being claimed until that time.
 	// Returns false if the task was not in running status.
-	ScheduleRetryFromRunning(ctx context.Context, id TaskID, reason string) (bool, error)
+	ScheduleRetryFromRunning(ctx context.Context, id TaskID, reason string, notBefore *time.Time) (bool, error)
 	SetAgentStatus(ctx context.Context, id TaskID, status string) error
 	SetRetryContext(ctx context.Context, id TaskID, retryCtx string) error
 	AddCost(ctx context.Context, id TaskID, costUSD float64) error
 	SetConsecutiveFailures(ctx context.Context, id TaskID, count int) error
+	// SetLastReviewID records the ID of the most recent PR review that has
+	// been processed so the same review is not turned into feedback twice.
+	SetLastReviewID(ctx context.Context, id TaskID, reviewID int64) error
 	SetCloseReason(ctx context.Context, id TaskID, reason string) error
+	// SetFailureCategory records why a task failed. It is cleared when the
+	// task is retried or started over.
+	SetFailureCategory(ctx context.Context, id TaskID, category string) error
 	SetBranchName(ctx context.Context, id TaskID, branchName string) error
 	ListTasksInReviewNoPR(ctx context.Context) ([]*Task, error)
 	ManualRetryTask(ctx context.Context, id TaskID, instructions string) (bool, error)
@@ -61,20 +77,36 @@ type TaskRepository interface {
 	// a fresh retry budget — failures after user-requested changes are
 	// caused by those changes, not by the original code.
 	FeedbackRetryTask(ctx context.Context, id TaskID, feedback string) (bool, error)
+	// DeleteTaskLogs deletes all logs, agent events and attempt records for a task.
 	DeleteTaskLogs(ctx context.Context, id TaskID) error
 	RemoveDependency(ctx context.Context, id TaskID, depID string) error
 	SetReady(ctx context.Context, id TaskID, ready bool) error
 	// UpdatePendingTask atomically updates a pending task's editable fields.
-	// Returns false if the task was not in pending status.
+	// Returns false if the task was not in pending status or its version did
+	// not match params.ExpectedVersion.
 	UpdatePendingTask(ctx context.Context, id TaskID, params UpdatePendingTaskParams) (bool, error)
 	// StartOverTask resets a task from review, failed, or closed back to pending with
 	// fresh metadata. Clears logs, PR, branch, agent status, cost, and retry
 	// state. Optionally updates title, description, and acceptance criteria.
-	// Returns false if the task was not in review, failed, or closed status.
+	// Returns false if the task was not in review, failed, or closed status
+	// or its version did not match params.ExpectedVersion.
 	StartOverTask(ctx context.Context, id TaskID, params StartOverTaskParams) (bool, error)
+	// MoveTask moves a pending task to another repo, giving it the next
+	// number there and clearing its epic, stack parent, PRs and branch.
+	// Returns false if the task was not in pending status or its version
+	// did not match params.ExpectedVersion.
+	MoveTask(ctx context.Context, id TaskID, params MoveTaskParams) (bool, error)
 	// StopTask atomically transitions a task from running → pending with ready=false,
 	// recording the stop reason. Returns false if the task was not in running status.
 	StopTask(ctx context.Context, id TaskID, reason string) (bool, error)
+	// AbandonTask atomically transitions a task from running → pending so
+	// another worker can claim it. Returns false if the task was not in
+	// running status.
+	AbandonTask(ctx context.Context, id TaskID) (bool, error)
+	// AbandonStaleTask is AbandonTask for a task whose last heartbeat (or
+	// start, if it never sent one) is before the given time. Returns false if
+	// the task was not running or has heartbeated since.
+	AbandonStaleTask(ctx context.Context, id TaskID, before time.Time) (bool, error)
 	// Heartbeat updates the last heartbeat time for a running task.
 	// Returns true if the task is still running (row was updated), false if the
 	// task no longer exists or is no longer in running status (e.g. stopped,
@@ -82,18 +114,195 @@ type TaskRepository interface {
 	Heartbeat(ctx context.Context, id TaskID) (bool, error)
 	// ListStaleTasks returns running tasks whose last heartbeat is before the given time.
 	ListStaleTasks(ctx context.Context, before time.Time) ([]*Task, error)
+	// ListOverrunTasks returns running tasks whose current attempt has run
+	// past their max runtime, or their repo's default max runtime, at now.
+	ListOverrunTasks(ctx context.Context, now time.Time) ([]*Task, error)
+	// ListRepoRequiredLabels returns the required labels of every repo that
+	// has any, keyed by repo ID.
+	ListRepoRequiredLabels(ctx context.Context) (map[string][]string, error)
+	// ListPausedRepoIDs returns the IDs of the repos whose queue is paused.
+	ListPausedRepoIDs(ctx context.Context) ([]string, error)
 	DeleteTask(ctx context.Context, id TaskID) error
+	// TrashTask moves a task to the trash, detaching it from its epic. Its
+	// logs and attempts are kept until it is purged. Returns false if the
+	// task does not exist or is already trashed.
+	TrashTask(ctx context.Context, id TaskID) (bool, error)
+	// RestoreTask moves a trashed task back out of the trash. Returns false if
+	// the task is not in the trash.
+	RestoreTask(ctx context.Context, id TaskID) (bool, error)
+	// ListTrashedTasksByRepo returns a repo's trashed tasks, most recently
+	// trashed first.
+	ListTrashedTasksByRepo(ctx context.Context, repoID string) ([]*Task, error)
+	// ListTrashedTaskIDsBefore returns the IDs of tasks trashed before the
+	// given time.
+	ListTrashedTaskIDsBefore(ctx context.Context, before time.Time) ([]TaskID, error)
+	// ArchiveTask archives a merged or closed task, moving its logs to the
+	// log archive. Returns false if the task is not merged or closed, or is
+	// already archived.
+	ArchiveTask(ctx context.Context, id TaskID) (bool, error)
+	// UnarchiveTask unarchives a task, moving its logs back from the log
+	// archive. Returns false if the task is not archived.
+	UnarchiveTask(ctx context.Context, id TaskID) (bool, error)
+	// ListArchivedTasksByRepo returns a repo's archived tasks, newest first.
+	ListArchivedTasksByRepo(ctx context.Context, repoID string) ([]*Task, error)
+	// ListArchivableTaskIDs returns the IDs of up to limit unarchived tasks
+	// that have been merged or closed since before the given time, oldest
+	// first.
+	ListArchivableTaskIDs(ctx context.Context, before time.Time, limit int) ([]TaskID, error)
 	// ListTasksByEpic returns all tasks belonging to a given epic.
 	ListTasksByEpic(ctx context.Context, epicID string) ([]*Task, error)
 	// BulkCloseTasksByEpic closes all non-terminal tasks for an epic.
 	BulkCloseTasksByEpic(ctx context.Context, epicID, reason string) error
 	// ClearEpicIDForTasks removes the epic_id FK from all tasks for a given epic.
 	ClearEpicIDForTasks(ctx context.Context, epicID string) error
-	// BulkDeleteTasksByEpic deletes all tasks (and their logs) for a given epic.
+	// BulkDeleteTasksByEpic deletes all tasks (and their logs, events and attempts) for a given epic.
 	BulkDeleteTasksByEpic(ctx context.Context, epicID string) error
-	// BulkDeleteTasksByIDs deletes tasks (and their logs) by their IDs.
+	// BulkDeleteTasksByIDs deletes tasks (and their logs, events and attempts) by their IDs.
 	BulkDeleteTasksByIDs(ctx context.Context, ids []string) error
 	// DeleteExpiredLogs deletes all log entries older than the given time.
 	// Returns the number of log batches deleted.
 	DeleteExpiredLogs(ctx context.Context, before time.Time) (int64, error)
+	// StartTaskAttempt records the start of an attempt and the model it runs
+	// with. Reclaiming an attempt that was stopped before it was retried
+	// reopens it.
+	StartTaskAttempt(ctx context.Context, id TaskID, attempt int, retryReason, model string) error
+	// EndTaskAttempt records the result of the task's open attempt. It is a
+	// no-op when no attempt is open.
+	EndTaskAttempt(ctx context.Context, id TaskID, result string) error
+	// AddTaskAttemptCost adds to the cost of the task's latest attempt.
+	AddTaskAttemptCost(ctx context.Context, id TaskID, costUSD float64) error
+	ListTaskAttempts(ctx context.Context, id TaskID) ([]*Attempt, error)
+	// StreamTaskAttemptLogs iterates the log batches of a single attempt.
+	StreamTaskAttemptLogs(ctx context.Context, id TaskID, attempt int, fn func(lines []string) error) error
+	// AppendTaskEvents stores agent events reported during an attempt,
+	// setting each event's ID, Attempt and CreatedAt.
+	AppendTaskEvents(ctx context.Context, id TaskID, attempt int, events []*AgentEvent) error
+	// ListTaskEvents returns a task's agent events across all attempts,
+	// oldest first.
+	ListTaskEvents(ctx context.Context, id TaskID) ([]*AgentEvent, error)
+	// ReadTaskProgress returns the agent's latest reported plan for a task,
+	// or an empty progress if none has been reported.
+	ReadTaskProgress(ctx context.Context, id TaskID) (*Progress, error)
+	// SetTaskProgress replaces a task's progress.
+	SetTaskProgress(ctx context.Context, id TaskID, progress *Progress) error
+	// CreateTaskNudge stores a message for the agent of a task's attempt.
+	CreateTaskNudge(ctx context.Context, id TaskID, attempt int, message string) (*Nudge, error)
+	// ListTaskNudges returns a task's nudges across all attempts, oldest first.
+	ListTaskNudges(ctx context.Context, id TaskID) ([]*Nudge, error)
+	// DeliverTaskNudges marks a task's undelivered nudges delivered and
+	// returns them, oldest first.
+	DeliverTaskNudges(ctx context.Context, id TaskID, now time.Time) ([]*Nudge, error)
+	// CreateTaskNote stores a note the agent left during a task's attempt.
+	CreateTaskNote(ctx context.Context, id TaskID, repoID string, attempt int, text string) (*Note, error)
+	// ListRepoNotes returns the notes left on a repo's tasks, newest first.
+	ListRepoNotes(ctx context.Context, repoID string) ([]*Note, error)
+	// ReadTaskNote returns a note, or nil if it does not exist.
+	ReadTaskNote(ctx context.Context, noteID int64) (*Note, error)
+	// SetTaskNoteConverted records the task a note was converted to. It
+	// returns false when the note was already converted.
+	SetTaskNoteConverted(ctx context.Context, noteID int64, taskID TaskID) (bool, error)
+	// CreateTaskComment stores a comment on a task and returns it with its ID
+	// and timestamps set.
+	CreateTaskComment(ctx context.Context, comment *Comment) (*Comment, error)
+	// ListTaskComments returns a task's comments, oldest first.
+	ListTaskComments(ctx context.Context, id TaskID) ([]*Comment, error)
+	// ReadTaskComment returns a comment on a task, or nil if it does not
+	// exist.
+	ReadTaskComment(ctx context.Context, id TaskID, commentID int64) (*Comment, error)
+	// UpdateTaskComment replaces the body of a comment on a task and returns
+	// the updated comment, or nil if it does not exist.
+	UpdateTaskComment(ctx context.Context, id TaskID, commentID int64, body string) (*Comment, error)
+	// DeleteTaskComment deletes a comment on a task. It returns false when
+	// the comment does not exist.
+	DeleteTaskComment(ctx context.Context, id TaskID, commentID int64) (bool, error)
+	// CountTaskComments returns the number of comments on a task.
+	CountTaskComments(ctx context.Context, id TaskID) (int, error)
+	// CountTaskCommentsByRepo returns the number of comments on each of a
+	// repo's tasks, keyed by task ID. Tasks without comments are left out.
+	CountTaskCommentsByRepo(ctx context.Context, repoID string) (map[string]int, error)
+	// CreateTaskAttachment stores an attachment's metadata and returns it
+	// with its ID and creation time set. It returns ErrAttachmentExists when
+	// the task already has an attachment with the same filename.
+	CreateTaskAttachment(ctx context.Context, attachment *Attachment) (*Attachment, error)
+	// ListTaskAttachments returns a task's attachments, oldest first.
+	ListTaskAttachments(ctx context.Context, id TaskID) ([]*Attachment, error)
+	// ReadTaskAttachment returns an attachment of a task, or nil if it does
+	// not exist.
+	ReadTaskAttachment(ctx context.Context, id TaskID, attachmentID int64) (*Attachment, error)
+	// DeleteTaskAttachment deletes an attachment's metadata. It returns false
+	// when the attachment does not exist.
+	DeleteTaskAttachment(ctx context.Context, id TaskID, attachmentID int64) (bool, error)
+	// CountTaskAttachments returns the number of files attached to a task.
+	CountTaskAttachments(ctx context.Context, id TaskID) (int, error)
+	// CreateTaskArtifact stores an artifact's metadata and returns it with
+	// its ID and creation time set. It returns ErrArtifactExists when the
+	// attempt already has an artifact with the same name.
+	CreateTaskArtifact(ctx context.Context, artifact *Artifact) (*Artifact, error)
+	// ListTaskArtifacts returns a task's artifacts ordered by attempt, oldest
+	// first.
+	ListTaskArtifacts(ctx context.Context, id TaskID) ([]*Artifact, error)
+	// ReadTaskArtifact returns an artifact of a task, or nil if it does not
+	// exist.
+	ReadTaskArtifact(ctx context.Context, id TaskID, artifactID int64) (*Artifact, error)
+	// CountTaskArtifacts returns the number of artifacts an attempt of a task
+	// recorded.
+	CountTaskArtifacts(ctx context.Context, id TaskID, attempt int) (int, error)
+	// DeleteExpiredTaskArtifacts deletes the metadata of artifacts created
+	// before the given time and returns their storage keys.
+	DeleteExpiredTaskArtifacts(ctx context.Context, before time.Time) ([]string, error)
+	// SetTaskCIDispatch replaces the record of a task's latest CI workflow
+	// dispatch.
+	SetTaskCIDispatch(ctx context.Context, id TaskID, dispatch *CIDispatch) error
+	// ReadTaskCIDispatch returns a task's latest CI workflow dispatch, or nil
+	// if the workflow has never been dispatched for it.
+	ReadTaskCIDispatch(ctx context.Context, id TaskID) (*CIDispatch, error)
+	// SetTaskProvenanceReview replaces a task's provenance review.
+	SetTaskProvenanceReview(ctx context.Context, id TaskID, review *ProvenanceReview) error
+	// ReadTaskProvenanceReview returns a task's provenance review, or nil if
+	// its PR has not been scanned.
+	ReadTaskProvenanceReview(ctx context.Context, id TaskID) (*ProvenanceReview, error)
+	// SetTaskSelfReview replaces a task's self-review.
+	SetTaskSelfReview(ctx context.Context, id TaskID, review *SelfReview) error
+	// ReadTaskSelfReview returns a task's self-review, or nil if its PR has
+	// not been reviewed.
+	ReadTaskSelfReview(ctx context.Context, id TaskID) (*SelfReview, error)
+	// SetTaskShadowDiff replaces the diff from a task's latest shadow run.
+	SetTaskShadowDiff(ctx context.Context, id TaskID, diff *ShadowDiff) error
+	// ReadTaskShadowDiff returns the diff from a task's latest shadow run, or
+	// nil if it has never run in shadow mode.
+	ReadTaskShadowDiff(ctx context.Context, id TaskID) (*ShadowDiff, error)
+	// SetTaskProposal replaces the proposal from a task's latest plan-only
+	// run, clearing any earlier approval.
+	SetTaskProposal(ctx context.Context, id TaskID, proposal *Proposal) error
+	// ReadTaskProposal returns the proposal from a task's latest plan-only
+	// run, or nil if it has never run in plan-only mode.
+	ReadTaskProposal(ctx context.Context, id TaskID) (*Proposal, error)
+	// ApproveTaskProposal marks a task's proposal approved. It returns false
+	// if the task has no proposal or it was already approved.
+	ApproveTaskProposal(ctx context.Context, id TaskID, approvedAt time.Time) (bool, error)
+	// SetTaskPlanOnly sets whether a task's next run only proposes a plan.
+	SetTaskPlanOnly(ctx context.Context, id TaskID, planOnly bool) error
+	// SetTaskActiveModel sets the model a task's next attempt runs with in
+	// place of its configured model. Empty restores the configured model.
+	SetTaskActiveModel(ctx context.Context, id TaskID, model string) error
+	// RecordTaskCallback records that the worker callback identified by key
+	// was applied to an attempt of a task. It returns false when the attempt
+	// already recorded the key.
+	RecordTaskCallback(ctx context.Context, id TaskID, attempt int, key string) (bool, error)
+	// DeleteTaskCallback forgets a recorded callback so it can be applied
+	// again.
+	DeleteTaskCallback(ctx context.Context, id TaskID, attempt int, key string) error
+	// DeleteExpiredTaskCallbacks deletes the callbacks recorded before the
+	// given time. Returns the number deleted.
+	DeleteExpiredTaskCallbacks(ctx context.Context, before time.Time) (int64, error)
+	// ListOverdueReviews lists the open review spans not yet reported overdue
+	// that have outlasted their repo's review SLA at now, oldest first.
+	ListOverdueReviews(ctx context.Context, now time.Time) ([]*OverdueReview, error)
+	// MarkReviewOverdue records that a review span was reported overdue. It
+	// returns false if the span already ended or was reported.
+	MarkReviewOverdue(ctx context.Context, id int64, at time.Time) (bool, error)
+	// ListReviewSpans lists the review spans of tasks not deleted that are
+	// still open or ended at or after since, oldest first. An empty repoID
+	// lists spans of every repo.
+	ListReviewSpans(ctx context.Context, repoID string, since time.Time) ([]*ReviewSpan, error)
 }
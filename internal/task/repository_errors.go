@@ -4,6 +4,8 @@ import (
 	"errors"
 
 	"github.com/joshjon/kit/errtag"
+
+	"github.com/vervesh/verve/internal/msgcat"
 )
 
 // ErrTaskNotPending is returned when an update is attempted on a task that is
@@ -16,18 +18,104 @@ var ErrTaskNotPending = errtag.Tag[ErrTagTaskNotPending](
 // is not in pending status.
 type ErrTagTaskNotPending struct{ errtag.Conflict }
 
-func (ErrTagTaskNotPending) Msg() string { return "task is no longer pending" }
+func (ErrTagTaskNotPending) Msg() string { return msgcat.Text(msgcat.ErrTaskNotPending) }
 
 func (e ErrTagTaskNotPending) Unwrap() error {
 	return errtag.Tag[errtag.Conflict](e.Cause())
 }
 
+// ErrTaskNotRunning is returned when a nudge is sent to, or a completion
+// reported for, a task that is not running.
+var ErrTaskNotRunning = errtag.Tag[ErrTagTaskNotRunning](
+	errors.New("task is not running"),
+)
+
+// ErrTagTaskNotRunning indicates an operation was rejected because the task
+// is not in running status.
+type ErrTagTaskNotRunning struct{ errtag.Conflict }
+
+func (ErrTagTaskNotRunning) Msg() string { return msgcat.Text(msgcat.ErrTaskNotRunning) }
+
+func (e ErrTagTaskNotRunning) Unwrap() error {
+	return errtag.Tag[errtag.Conflict](e.Cause())
+}
+
+// ErrAttemptEnded is returned when a completion is reported for an attempt
+// of a task that was since superseded by a retry.
+var ErrAttemptEnded = errtag.Tag[ErrTagAttemptEnded](
+	errors.New("task attempt has already ended"),
+)
+
+// ErrTagAttemptEnded indicates an operation was rejected because it was for
+// an earlier attempt of the task.
+type ErrTagAttemptEnded struct{ errtag.Conflict }
+
+func (ErrTagAttemptEnded) Msg() string { return msgcat.Text(msgcat.ErrTaskAttemptEnded) }
+
+func (e ErrTagAttemptEnded) Unwrap() error {
+	return errtag.Tag[errtag.Conflict](e.Cause())
+}
+
+// ErrTaskVersionMismatch is returned when an update is made from a read of a
+// task that has since been changed.
+var ErrTaskVersionMismatch = errtag.Tag[ErrTagTaskVersionMismatch](
+	errors.New("task version mismatch"),
+)
+
+// ErrTagTaskVersionMismatch indicates an update was rejected because the
+// task's version didn't match the expected version.
+type ErrTagTaskVersionMismatch struct{ errtag.Conflict }
+
+func (ErrTagTaskVersionMismatch) Msg() string { return msgcat.Text(msgcat.ErrTaskVersionMismatch) }
+
+func (e ErrTagTaskVersionMismatch) Unwrap() error {
+	return errtag.Tag[errtag.Conflict](e.Cause())
+}
+
+// ErrDependenciesOutsideRepo is returned when moving a task to a repo other
+// than the repo of its dependencies or of the tasks that depend on it,
+// without dropping those dependencies.
+var ErrDependenciesOutsideRepo = errtag.Tag[ErrTagDependenciesOutsideRepo](
+	errors.New("task has dependencies outside the target repo"),
+)
+
+// ErrTagDependenciesOutsideRepo indicates a move was rejected because it
+// would leave dependencies spanning repos.
+type ErrTagDependenciesOutsideRepo struct{ errtag.Conflict }
+
+func (ErrTagDependenciesOutsideRepo) Msg() string { return msgcat.Text(msgcat.ErrTaskMoveDependencies) }
+
+func (e ErrTagDependenciesOutsideRepo) Unwrap() error {
+	return errtag.Tag[errtag.Conflict](e.Cause())
+}
+
+// ErrTaskNotTrashed is returned when restoring a task that is not in the
+// trash, either because it was never deleted or because it has been purged.
+var ErrTaskNotTrashed = errtag.Tag[ErrTagTaskNotTrashed](
+	errors.New("task is not in the trash"),
+)
+
+// ErrTagTaskNotTrashed indicates a task is not in the trash.
+type ErrTagTaskNotTrashed struct{ errtag.NotFound }
+
+func (ErrTagTaskNotTrashed) Msg() string { return msgcat.Text(msgcat.ErrTaskNotTrashed) }
+
+func (e ErrTagTaskNotTrashed) Unwrap() error {
+	return errtag.Tag[errtag.NotFound](e.Cause())
+}
+
 // ErrTaskNotFailed is returned when a move-to-review is attempted on a task
 // that is not in failed status.
 var ErrTaskNotFailed = errtag.Tag[ErrTagTaskConflict](
 	errors.New("task is not in failed status"),
 )
 
+// ErrTaskNotArchived is returned when unarchiving a task that is not
+// archived.
+var ErrTaskNotArchived = errtag.Tag[ErrTagTaskConflict](
+	errors.New("task is not archived"),
+)
+
 // ErrTaskNoPR is returned when a move-to-review is attempted on a failed task
 // that has no PR or branch.
 var ErrTaskNoPR = errtag.Tag[ErrTagTaskNoPR](
@@ -37,16 +125,32 @@ var ErrTaskNoPR = errtag.Tag[ErrTagTaskNoPR](
 // ErrTagTaskNoPR indicates a task has no PR or branch to move to review.
 type ErrTagTaskNoPR struct{ errtag.InvalidArgument }
 
-func (ErrTagTaskNoPR) Msg() string { return "task has no pull request or branch" }
+func (ErrTagTaskNoPR) Msg() string { return msgcat.Text(msgcat.ErrTaskNoPR) }
 
 func (e ErrTagTaskNoPR) Unwrap() error {
 	return errtag.Tag[errtag.InvalidArgument](e.Cause())
 }
 
+// ErrInvalidStackParent is returned when a task is stacked on a task in
+// another repo or one that doesn't open a pull request.
+var ErrInvalidStackParent = errtag.Tag[ErrTagInvalidStackParent](
+	errors.New("invalid stack parent"),
+)
+
+// ErrTagInvalidStackParent indicates a task can't be stacked on the given
+// dependency.
+type ErrTagInvalidStackParent struct{ errtag.InvalidArgument }
+
+func (ErrTagInvalidStackParent) Msg() string { return msgcat.Text(msgcat.ErrTaskInvalidStackParent) }
+
+func (e ErrTagInvalidStackParent) Unwrap() error {
+	return errtag.Tag[errtag.InvalidArgument](e.Cause())
+}
+
 // ErrTagTaskNotFound indicates a task was not found.
 type ErrTagTaskNotFound struct{ errtag.NotFound }
 
-func (ErrTagTaskNotFound) Msg() string { return "Task not found" }
+func (ErrTagTaskNotFound) Msg() string { return msgcat.Text(msgcat.ErrTaskNotFound) }
 
 func (e ErrTagTaskNotFound) Unwrap() error {
 	return errtag.Tag[errtag.NotFound](e.Cause())
@@ -55,8 +159,230 @@ func (e ErrTagTaskNotFound) Unwrap() error {
 // ErrTagTaskConflict indicates a task conflict (e.g. duplicate ID).
 type ErrTagTaskConflict struct{ errtag.Conflict }
 
-func (ErrTagTaskConflict) Msg() string { return "Task conflict" }
+func (ErrTagTaskConflict) Msg() string { return msgcat.Text(msgcat.ErrTaskConflict) }
 
 func (e ErrTagTaskConflict) Unwrap() error {
 	return errtag.Tag[errtag.Conflict](e.Cause())
 }
+
+// ErrProvenanceReviewNotFound is returned when acknowledging the provenance
+// review of a task whose PR has not been scanned.
+var ErrProvenanceReviewNotFound = errtag.Tag[ErrTagProvenanceReviewNotFound](
+	errors.New("provenance review not found"),
+)
+
+// ErrTagProvenanceReviewNotFound indicates a task has no provenance review.
+type ErrTagProvenanceReviewNotFound struct{ errtag.NotFound }
+
+func (ErrTagProvenanceReviewNotFound) Msg() string {
+	return msgcat.Text(msgcat.ErrProvenanceReviewNotFound)
+}
+
+func (e ErrTagProvenanceReviewNotFound) Unwrap() error {
+	return errtag.Tag[errtag.NotFound](e.Cause())
+}
+
+// ErrProposalNotFound is returned when approving the proposal of a task that
+// has never run in plan-only mode.
+var ErrProposalNotFound = errtag.Tag[ErrTagProposalNotFound](
+	errors.New("proposal not found"),
+)
+
+// ErrTagProposalNotFound indicates a task has no proposal.
+type ErrTagProposalNotFound struct{ errtag.NotFound }
+
+func (ErrTagProposalNotFound) Msg() string { return msgcat.Text(msgcat.ErrProposalNotFound) }
+
+func (e ErrTagProposalNotFound) Unwrap() error {
+	return errtag.Tag[errtag.NotFound](e.Cause())
+}
+
+// ErrProposalNotPending is returned when approving a proposal that was
+// already approved or whose task is not a plan-only task in review.
+var ErrProposalNotPending = errtag.Tag[ErrTagProposalNotPending](
+	errors.New("proposal is not awaiting approval"),
+)
+
+// ErrTagProposalNotPending indicates a task's proposal can't be approved.
+type ErrTagProposalNotPending struct{ errtag.Conflict }
+
+func (ErrTagProposalNotPending) Msg() string { return msgcat.Text(msgcat.ErrProposalNotPending) }
+
+func (e ErrTagProposalNotPending) Unwrap() error {
+	return errtag.Tag[errtag.Conflict](e.Cause())
+}
+
+// ErrNoteNotFound is returned when a note does not exist.
+var ErrNoteNotFound = errtag.Tag[ErrTagNoteNotFound](
+	errors.New("note not found"),
+)
+
+// ErrTagNoteNotFound indicates a note was not found.
+type ErrTagNoteNotFound struct{ errtag.NotFound }
+
+func (ErrTagNoteNotFound) Msg() string { return msgcat.Text(msgcat.ErrNoteNotFound) }
+
+func (e ErrTagNoteNotFound) Unwrap() error {
+	return errtag.Tag[errtag.NotFound](e.Cause())
+}
+
+// ErrCommentNotFound is returned when a task has no comment with the given
+// ID.
+var ErrCommentNotFound = errtag.Tag[ErrTagCommentNotFound](
+	errors.New("comment not found"),
+)
+
+// ErrTagCommentNotFound indicates a comment was not found.
+type ErrTagCommentNotFound struct{ errtag.NotFound }
+
+func (ErrTagCommentNotFound) Msg() string { return msgcat.Text(msgcat.ErrCommentNotFound) }
+
+func (e ErrTagCommentNotFound) Unwrap() error {
+	return errtag.Tag[errtag.NotFound](e.Cause())
+}
+
+// ErrCommentNotAuthor is returned when a user other than a comment's author
+// or an admin edits or deletes it.
+var ErrCommentNotAuthor = errtag.Tag[ErrTagCommentNotAuthor](
+	errors.New("comment belongs to another user"),
+)
+
+// ErrTagCommentNotAuthor indicates a comment was edited by someone other
+// than its author.
+type ErrTagCommentNotAuthor struct{ errtag.Forbidden }
+
+func (ErrTagCommentNotAuthor) Msg() string { return msgcat.Text(msgcat.ErrCommentNotAuthor) }
+
+func (e ErrTagCommentNotAuthor) Unwrap() error {
+	return errtag.Tag[errtag.Forbidden](e.Cause())
+}
+
+// ErrAttachmentNotFound is returned when a task has no attachment with the
+// given ID.
+var ErrAttachmentNotFound = errtag.Tag[ErrTagAttachmentNotFound](
+	errors.New("attachment not found"),
+)
+
+// ErrTagAttachmentNotFound indicates an attachment was not found.
+type ErrTagAttachmentNotFound struct{ errtag.NotFound }
+
+func (ErrTagAttachmentNotFound) Msg() string { return msgcat.Text(msgcat.ErrAttachmentNotFound) }
+
+func (e ErrTagAttachmentNotFound) Unwrap() error {
+	return errtag.Tag[errtag.NotFound](e.Cause())
+}
+
+// ErrAttachmentExists is returned when a file is attached to a task that
+// already has an attachment with the same name.
+var ErrAttachmentExists = errtag.Tag[ErrTagAttachmentConflict](
+	errors.New("attachment already exists"),
+)
+
+// ErrAttachmentLimit is returned when a file is attached to a task that
+// already has MaxAttachmentsPerTask attachments.
+var ErrAttachmentLimit = errtag.Tag[ErrTagAttachmentLimit](
+	errors.New("attachment limit reached"),
+)
+
+// ErrTagAttachmentConflict indicates a file with the same name is already
+// attached to the task.
+type ErrTagAttachmentConflict struct{ errtag.Conflict }
+
+func (ErrTagAttachmentConflict) Msg() string { return msgcat.Text(msgcat.ErrAttachmentExists) }
+
+func (e ErrTagAttachmentConflict) Unwrap() error {
+	return errtag.Tag[errtag.Conflict](e.Cause())
+}
+
+// ErrTagAttachmentLimit indicates a task has no room for more attachments.
+type ErrTagAttachmentLimit struct{ errtag.Conflict }
+
+func (ErrTagAttachmentLimit) Msg() string {
+	return msgcat.Text(msgcat.ErrAttachmentLimit, MaxAttachmentsPerTask)
+}
+
+func (e ErrTagAttachmentLimit) Unwrap() error {
+	return errtag.Tag[errtag.Conflict](e.Cause())
+}
+
+// ErrAttachmentsNotConfigured is returned by the attachment methods of a
+// Store without attachment storage.
+var ErrAttachmentsNotConfigured = errors.New("attachment storage not configured")
+
+// ErrArtifactNotFound is returned when a task has no artifact with the given
+// ID.
+var ErrArtifactNotFound = errtag.Tag[ErrTagArtifactNotFound](
+	errors.New("artifact not found"),
+)
+
+// ErrTagArtifactNotFound indicates an artifact was not found.
+type ErrTagArtifactNotFound struct{ errtag.NotFound }
+
+func (ErrTagArtifactNotFound) Msg() string { return msgcat.Text(msgcat.ErrArtifactNotFound) }
+
+func (e ErrTagArtifactNotFound) Unwrap() error {
+	return errtag.Tag[errtag.NotFound](e.Cause())
+}
+
+// ErrArtifactExists is returned when an attempt records an artifact under a
+// name it already used.
+var ErrArtifactExists = errtag.Tag[ErrTagArtifactConflict](
+	errors.New("artifact already exists"),
+)
+
+// ErrArtifactLimit is returned when an attempt that already recorded
+// MaxArtifactsPerAttempt artifacts records another.
+var ErrArtifactLimit = errtag.Tag[ErrTagArtifactLimit](
+	errors.New("artifact limit reached"),
+)
+
+// ErrTagArtifactConflict indicates the attempt already has an artifact with
+// the same name.
+type ErrTagArtifactConflict struct{ errtag.Conflict }
+
+func (ErrTagArtifactConflict) Msg() string { return msgcat.Text(msgcat.ErrArtifactExists) }
+
+func (e ErrTagArtifactConflict) Unwrap() error {
+	return errtag.Tag[errtag.Conflict](e.Cause())
+}
+
+// ErrTagArtifactLimit indicates an attempt has no room for more artifacts.
+type ErrTagArtifactLimit struct{ errtag.Conflict }
+
+func (ErrTagArtifactLimit) Msg() string {
+	return msgcat.Text(msgcat.ErrArtifactLimit, MaxArtifactsPerAttempt)
+}
+
+func (e ErrTagArtifactLimit) Unwrap() error {
+	return errtag.Tag[errtag.Conflict](e.Cause())
+}
+
+// ErrAttemptNotFound is returned when a task has no attempt with the given
+// number.
+var ErrAttemptNotFound = errtag.Tag[ErrTagAttemptNotFound](
+	errors.New("attempt not found"),
+)
+
+// ErrTagAttemptNotFound indicates an attempt was not found.
+type ErrTagAttemptNotFound struct{ errtag.NotFound }
+
+func (ErrTagAttemptNotFound) Msg() string { return msgcat.Text(msgcat.ErrAttemptNotFound) }
+
+func (e ErrTagAttemptNotFound) Unwrap() error {
+	return errtag.Tag[errtag.NotFound](e.Cause())
+}
+
+// ErrNoteConverted is returned when converting a note that has already been
+// converted to a task.
+var ErrNoteConverted = errtag.Tag[ErrTagNoteConverted](
+	errors.New("note already converted"),
+)
+
+// ErrTagNoteConverted indicates a note has already been converted to a task.
+type ErrTagNoteConverted struct{ errtag.Conflict }
+
+func (ErrTagNoteConverted) Msg() string { return msgcat.Text(msgcat.ErrNoteConverted) }
+
+func (e ErrTagNoteConverted) Unwrap() error {
+	return errtag.Tag[errtag.Conflict](e.Cause())
+}
@@ -0,0 +1,183 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Default retry limits, used when neither the instance nor the repo
+// configures them.
+const (
+	DefaultMaxAttempts             = 5
+	DefaultCircuitBreakerThreshold = 3
+)
+
+// Rate-limit backoff used when the retry policy sets no schedule: the delay
+// doubles from DefaultRateLimitBackoff with each consecutive rate-limit retry,
+// up to MaxRateLimitBackoff.
+const (
+	DefaultRateLimitBackoff = 30 * time.Second
+	MaxRateLimitBackoff     = 15 * time.Minute
+)
+
+// RetryCategoryRateLimit is the failure category of retries scheduled after
+// an agent hits a rate limit.
+const RetryCategoryRateLimit = "rate_limit"
+
+// RetryCategoryWorkerLost is the failure category of retries scheduled after
+// a task's worker stopped sending heartbeats (see RetryPolicy.RequeueStale).
+const RetryCategoryWorkerLost = "worker_lost"
+
+// rateLimitJitter is the largest fraction of the delay added as jitter so
+// tasks rate-limited together don't all retry at the same moment.
+const rateLimitJitter = 0.2
+
+// RetryPolicy controls how failing tasks are retried. Zero fields are unset:
+// an instance-wide policy fills in the defaults and a repo's policy overrides
+// the instance-wide one field by field (see Merge).
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts a new task gets.
+	MaxAttempts int `json:"max_attempts,omitempty"`
+	// CircuitBreakerThreshold is the number of consecutive failures of the
+	// same category that fail a task.
+	CircuitBreakerThreshold int `json:"circuit_breaker_threshold,omitempty"`
+	// CategoryLimits overrides CircuitBreakerThreshold per failure category.
+	// Keys are either an exact category (e.g. "ci_failure:tests") or its
+	// kind, the part before the first colon (e.g. "ci_failure").
+	CategoryLimits map[string]int `json:"category_limits,omitempty"`
+	// RateLimitBackoffSeconds is the delay before each successive rate-limit
+	// retry. The last entry repeats for any further retries.
+	RateLimitBackoffSeconds []int `json:"rate_limit_backoff_seconds,omitempty"`
+	// RequeueStale sends running tasks whose worker stopped sending
+	// heartbeats back to pending for another attempt, instead of failing
+	// them. The retry counts towards MaxAttempts and the circuit breaker.
+	RequeueStale *bool `json:"requeue_stale,omitempty"`
+}
+
+// DefaultRetryPolicy is the policy used when nothing is configured.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:             DefaultMaxAttempts,
+	CircuitBreakerThreshold: DefaultCircuitBreakerThreshold,
+}
+
+// Validate reports whether every limit in the policy is in range.
+func (p RetryPolicy) Validate() error {
+	if p.MaxAttempts < 0 {
+		return fmt.Errorf("max attempts must not be negative")
+	}
+	if p.CircuitBreakerThreshold < 0 {
+		return fmt.Errorf("circuit breaker threshold must not be negative")
+	}
+	for category, limit := range p.CategoryLimits {
+		if strings.TrimSpace(category) == "" {
+			return fmt.Errorf("category limit has an empty category")
+		}
+		if limit < 1 {
+			return fmt.Errorf("category limit for %q must be at least 1", category)
+		}
+	}
+	for _, s := range p.RateLimitBackoffSeconds {
+		if s < 0 {
+			return fmt.Errorf("rate limit backoff must not be negative")
+		}
+	}
+	return nil
+}
+
+// Empty reports whether the policy sets nothing.
+func (p RetryPolicy) Empty() bool {
+	return p.MaxAttempts == 0 && p.CircuitBreakerThreshold == 0 &&
+		len(p.CategoryLimits) == 0 && len(p.RateLimitBackoffSeconds) == 0 &&
+		p.RequeueStale == nil
+}
+
+// RequeuesStale reports whether stale tasks are requeued rather than failed.
+func (p RetryPolicy) RequeuesStale() bool {
+	return p.RequeueStale != nil && *p.RequeueStale
+}
+
+// Merge returns p with every field o sets overriding p's. Category limits
+// are merged per category.
+func (p RetryPolicy) Merge(o RetryPolicy) RetryPolicy {
+	if o.MaxAttempts > 0 {
+		p.MaxAttempts = o.MaxAttempts
+	}
+	if o.CircuitBreakerThreshold > 0 {
+		p.CircuitBreakerThreshold = o.CircuitBreakerThreshold
+	}
+	if len(o.CategoryLimits) > 0 {
+		limits := make(map[string]int, len(p.CategoryLimits)+len(o.CategoryLimits))
+		for k, v := range p.CategoryLimits {
+			limits[k] = v
+		}
+		for k, v := range o.CategoryLimits {
+			limits[k] = v
+		}
+		p.CategoryLimits = limits
+	}
+	if len(o.RateLimitBackoffSeconds) > 0 {
+		p.RateLimitBackoffSeconds = o.RateLimitBackoffSeconds
+	}
+	if o.RequeueStale != nil {
+		p.RequeueStale = o.RequeueStale
+	}
+	return p
+}
+
+// Threshold returns the number of consecutive failures of category that fail
+// a task. An exact category limit wins over a limit for the category's kind.
+func (p RetryPolicy) Threshold(category string) int {
+	if limit, ok := p.CategoryLimits[category]; ok {
+		return limit
+	}
+	kind, _, _ := strings.Cut(category, ":")
+	if limit, ok := p.CategoryLimits[kind]; ok {
+		return limit
+	}
+	if p.CircuitBreakerThreshold > 0 {
+		return p.CircuitBreakerThreshold
+	}
+	return DefaultCircuitBreakerThreshold
+}
+
+// RateLimitDelay returns the delay before the nth consecutive rate-limit
+// retry (1-based), or zero when no backoff is configured.
+func (p RetryPolicy) RateLimitDelay(n int) time.Duration {
+	if len(p.RateLimitBackoffSeconds) == 0 || n < 1 {
+		return 0
+	}
+	i := min(n, len(p.RateLimitBackoffSeconds)) - 1
+	return time.Duration(p.RateLimitBackoffSeconds[i]) * time.Second
+}
+
+// RateLimitBackoff returns the delay before the nth consecutive rate-limit
+// retry (1-based): the policy's schedule when it sets one, otherwise
+// exponential backoff from DefaultRateLimitBackoff capped at
+// MaxRateLimitBackoff. jitter, in [0, 1), adds up to 20% of the delay.
+func (p RetryPolicy) RateLimitBackoff(n int, jitter float64) time.Duration {
+	n = max(n, 1)
+	delay := p.RateLimitDelay(n)
+	if len(p.RateLimitBackoffSeconds) == 0 {
+		delay = MaxRateLimitBackoff
+		if n <= 16 {
+			delay = min(DefaultRateLimitBackoff<<(n-1), MaxRateLimitBackoff)
+		}
+	}
+	return delay + time.Duration(float64(delay)*rateLimitJitter*jitter)
+}
+
+// RetryPolicyResolver returns the retry policy configured for a repo's tasks,
+// with the repo's policy already merged over the instance-wide one.
+type RetryPolicyResolver interface {
+	RetryPolicy(ctx context.Context, repoID string) (RetryPolicy, error)
+}
+
+// RetryPolicyResolverFunc adapts a function to the RetryPolicyResolver
+// interface.
+type RetryPolicyResolverFunc func(ctx context.Context, repoID string) (RetryPolicy, error)
+
+func (f RetryPolicyResolverFunc) RetryPolicy(ctx context.Context, repoID string) (RetryPolicy, error) {
+	return f(ctx, repoID)
+}
@@ -0,0 +1,97 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Validate(t *testing.T) {
+	tests := map[string]struct {
+		policy  RetryPolicy
+		wantErr bool
+	}{
+		"empty":                 {RetryPolicy{}, false},
+		"default":               {DefaultRetryPolicy, false},
+		"full":                  {RetryPolicy{MaxAttempts: 8, CircuitBreakerThreshold: 4, CategoryLimits: map[string]int{"rate_limit": 6}, RateLimitBackoffSeconds: []int{30, 120}}, false},
+		"negative max attempts": {RetryPolicy{MaxAttempts: -1}, true},
+		"negative threshold":    {RetryPolicy{CircuitBreakerThreshold: -1}, true},
+		"zero category limit":   {RetryPolicy{CategoryLimits: map[string]int{"ci_failure": 0}}, true},
+		"blank category":        {RetryPolicy{CategoryLimits: map[string]int{" ": 2}}, true},
+		"negative backoff":      {RetryPolicy{RateLimitBackoffSeconds: []int{30, -1}}, true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Merge(t *testing.T) {
+	instance := RetryPolicy{
+		MaxAttempts:             8,
+		CategoryLimits:          map[string]int{"ci_failure": 4, "rate_limit": 5},
+		RateLimitBackoffSeconds: []int{60},
+	}
+	requeue := false
+	repo := RetryPolicy{
+		CircuitBreakerThreshold: 2,
+		CategoryLimits:          map[string]int{"rate_limit": 10},
+		RequeueStale:            &requeue,
+	}
+
+	got := DefaultRetryPolicy.Merge(instance).Merge(repo)
+	assert.Equal(t, RetryPolicy{
+		MaxAttempts:             8,
+		CircuitBreakerThreshold: 2,
+		CategoryLimits:          map[string]int{"ci_failure": 4, "rate_limit": 10},
+		RateLimitBackoffSeconds: []int{60},
+		RequeueStale:            &requeue,
+	}, got)
+	assert.False(t, got.RequeuesStale())
+	assert.Equal(t, map[string]int{"ci_failure": 4, "rate_limit": 5}, instance.CategoryLimits, "merge must not modify its inputs")
+	assert.True(t, RetryPolicy{}.Empty())
+	assert.False(t, repo.Empty())
+}
+
+func TestRetryPolicy_Threshold(t *testing.T) {
+	p := RetryPolicy{
+		CircuitBreakerThreshold: 4,
+		CategoryLimits:          map[string]int{"ci_failure": 2, "ci_failure:lint": 6},
+	}
+	assert.Equal(t, 6, p.Threshold("ci_failure:lint"), "exact category wins")
+	assert.Equal(t, 2, p.Threshold("ci_failure:tests"), "falls back to the category kind")
+	assert.Equal(t, 4, p.Threshold("security_alert:dependabot"))
+	assert.Equal(t, DefaultCircuitBreakerThreshold, RetryPolicy{}.Threshold("ci_failure"))
+}
+
+func TestRetryPolicy_RateLimitDelay(t *testing.T) {
+	p := RetryPolicy{RateLimitBackoffSeconds: []int{30, 60, 300}}
+	assert.Equal(t, 30*time.Second, p.RateLimitDelay(1))
+	assert.Equal(t, 60*time.Second, p.RateLimitDelay(2))
+	assert.Equal(t, 300*time.Second, p.RateLimitDelay(3))
+	assert.Equal(t, 300*time.Second, p.RateLimitDelay(7), "last entry repeats")
+	assert.Zero(t, p.RateLimitDelay(0))
+	assert.Zero(t, RetryPolicy{}.RateLimitDelay(1))
+}
+
+func TestRetryPolicy_RateLimitBackoff(t *testing.T) {
+	var p RetryPolicy
+	assert.Equal(t, 30*time.Second, p.RateLimitBackoff(1, 0))
+	assert.Equal(t, 60*time.Second, p.RateLimitBackoff(2, 0))
+	assert.Equal(t, 4*time.Minute, p.RateLimitBackoff(4, 0))
+	assert.Equal(t, MaxRateLimitBackoff, p.RateLimitBackoff(6, 0), "capped")
+	assert.Equal(t, MaxRateLimitBackoff, p.RateLimitBackoff(100, 0), "no overflow")
+	assert.Equal(t, 36*time.Second, p.RateLimitBackoff(1, 1), "jitter adds up to 20%")
+
+	p.RateLimitBackoffSeconds = []int{10, 100}
+	assert.Equal(t, 10*time.Second, p.RateLimitBackoff(1, 0), "policy schedule wins")
+	assert.Equal(t, 100*time.Second, p.RateLimitBackoff(5, 0))
+	assert.Equal(t, 11*time.Second, p.RateLimitBackoff(0, 0.5), "n is at least 1")
+}
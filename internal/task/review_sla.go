@@ -0,0 +1,89 @@
+package task
+
+import (
+	"context"
+	"time"
+)
+
+// ReviewSLACheckInterval is how often overdue reviews are looked for, and so
+// roughly how late past its repo's review SLA a review is reported.
+const ReviewSLACheckInterval = time.Minute
+
+// ReviewSpan is a stay of a task in review. A task that goes back to review,
+// such as after a retry, starts a new span.
+type ReviewSpan struct {
+	TaskID     TaskID
+	TaskNumber int
+	RepoID     string
+	Title      string
+	StartedAt  time.Time
+	// EndedAt is nil while the task is still in review.
+	EndedAt *time.Time
+	// SLA is the repo's current review SLA, or 0 when it has none.
+	SLA time.Duration
+}
+
+// Duration returns how long the span lasted, or has lasted so far at now
+// when it is still open.
+func (r *ReviewSpan) Duration(now time.Time) time.Duration {
+	if r.EndedAt != nil {
+		return r.EndedAt.Sub(r.StartedAt)
+	}
+	return now.Sub(r.StartedAt)
+}
+
+// Overdue reports whether the span outlasted its repo's review SLA at now.
+func (r *ReviewSpan) Overdue(now time.Time) bool {
+	return r.SLA > 0 && r.Duration(now) > r.SLA
+}
+
+// OverdueReview is an open review span that has outlasted its repo's review
+// SLA.
+type OverdueReview struct {
+	ID        int64
+	TaskID    TaskID
+	StartedAt time.Time
+	SLA       time.Duration
+	// Task is the task in review, set by Store.CheckReviewSLAs.
+	Task *Task
+}
+
+// CheckReviewSLAs reports the tasks that have been in review for longer
+// than their repo's review SLA. Each stay in review is reported once: an
+// EventReviewOverdue is published for its task and it is returned, for the
+// caller to notify about.
+func (s *Store) CheckReviewSLAs(ctx context.Context) ([]*OverdueReview, error) {
+	now := time.Now()
+	overdue, err := s.repo.ListOverdueReviews(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	var reported []*OverdueReview
+	for _, o := range overdue {
+		// Marked first so a replica taking over the leader lease part-way
+		// through doesn't report the review again.
+		ok, err := s.repo.MarkReviewOverdue(ctx, o.ID, now)
+		if err != nil {
+			return reported, err
+		}
+		if !ok {
+			continue
+		}
+		t, err := s.repo.ReadTask(ctx, o.TaskID)
+		if err != nil {
+			return reported, err
+		}
+		t.Logs = nil
+		o.Task = t
+		s.broker.Publish(ctx, Event{Type: EventReviewOverdue, RepoID: t.RepoID, Task: t, TaskID: t.ID})
+		reported = append(reported, o)
+	}
+	return reported, nil
+}
+
+// ListReviewSpans lists the review spans of a repo's tasks, or of every
+// repo's when repoID is empty, that are still open or ended at or after
+// since.
+func (s *Store) ListReviewSpans(ctx context.Context, repoID string, since time.Time) ([]*ReviewSpan, error) {
+	return s.repo.ListReviewSpans(ctx, repoID, since)
+}
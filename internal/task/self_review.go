@@ -0,0 +1,18 @@
+package task
+
+import (
+	"time"
+
+	"github.com/vervesh/verve/internal/selfreview"
+)
+
+// SelfReview is the latest automated self-review of a task's PR: a score out
+// of 100 and the checks it was derived from. The report is also posted as a
+// PR comment, which later reviews of the same PR edit in place.
+type SelfReview struct {
+	Score      int                `json:"score"`
+	Checks     []selfreview.Check `json:"checks"`
+	HeadSHA    string             `json:"head_sha"`
+	CommentID  int64              `json:"-"`
+	ReviewedAt time.Time          `json:"reviewed_at"`
+}
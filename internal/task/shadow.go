@@ -0,0 +1,12 @@
+package task
+
+import "time"
+
+// ShadowDiff is the diff produced by a task's latest run in shadow mode,
+// where the agent works on the repo but never pushes or opens a pull request.
+// It is kept for a human to judge the agent's output.
+type ShadowDiff struct {
+	Attempt   int       `json:"attempt"`
+	Diff      string    `json:"diff"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -1,16 +1,39 @@
 package task
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"math/rand/v2"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/joshjon/kit/tx"
+
+	"github.com/vervesh/verve/internal/msgcat"
+	"github.com/vervesh/verve/internal/prlint"
+	"github.com/vervesh/verve/internal/provenance"
 )
 
+// StatusListener is notified after a task transitions to a new status.
+type StatusListener interface {
+	TaskStatusChanged(ctx context.Context, t *Task)
+}
+
+// StatusListeners notifies each listener in turn.
+type StatusListeners []StatusListener
+
+// TaskStatusChanged notifies every listener of t's new status.
+func (ls StatusListeners) TaskStatusChanged(ctx context.Context, t *Task) {
+	for _, l := range ls {
+		l.TaskStatusChanged(ctx, t)
+	}
+}
+
 // Store wraps a Repository and adds application-level concerns such as
 // pending task notification, dependency validation, and event broadcasting.
 type Store struct {
@@ -19,10 +42,36 @@ type Store struct {
 	pendingMu sync.Mutex
 	pendingCh chan struct{}
 
+	// Sends pending notifications to the other server instances. Nil wakes
+	// only this instance's polls.
+	pendingNotifier PendingNotifier
+
+	// Notified of status transitions (e.g. for external notifications).
+	statusListener StatusListener
+
+	// Resolves the retry policy for a task's repo. Nil uses DefaultRetryPolicy.
+	retryPolicies RetryPolicyResolver
+
+	// Combines concurrent log appends into one insert. Nil writes each
+	// append on its own.
+	logBatcher *logBatcher
+
+	// How long deleted tasks stay in the trash before they are purged. Zero
+	// deletes tasks right away.
+	trashRetention time.Duration
+
+	// Holds attachment contents. Nil disables attachments.
+	attachments       AttachmentStorage
+	maxAttachmentSize int64
+
 	// Stop queue: IDs of tasks that have been stopped, delivered via poll.
 	pendingStopsMu sync.Mutex
 	pendingStops   []TaskID
 	pendingStopCh  chan struct{} // buffered(1), signals when stops are queued
+
+	// Set on the copy applyOnce hands its callback, which holds back events
+	// and notifications until the transaction commits.
+	effects *txEffects
 }
 
 // NewStore creates a new Store backed by the given Repository and Broker.
@@ -35,11 +84,96 @@ func NewStore(repo Repository, broker *Broker) *Store {
 	}
 }
 
+// SetStatusListener sets the StatusListener notified of task status
+// transitions. This is set after construction to avoid circular dependencies.
+func (s *Store) SetStatusListener(listener StatusListener) {
+	s.statusListener = listener
+}
+
+// SetPendingNotifier sets the PendingNotifier that shares pending task
+// notifications with the other server instances.
+func (s *Store) SetPendingNotifier(notifier PendingNotifier) {
+	s.pendingNotifier = notifier
+}
+
+// SetRetryPolicyResolver sets the RetryPolicyResolver consulted when tasks
+// are created and retried. This is set after construction to avoid circular
+// dependencies.
+func (s *Store) SetRetryPolicyResolver(resolver RetryPolicyResolver) {
+	s.retryPolicies = resolver
+}
+
+// SetLogBatchInterval makes AppendTaskLogs combine the appends that arrive
+// within interval of each other into one insert. Each call still returns
+// once its own lines are stored. Zero, the default, writes each append on its
+// own.
+func (s *Store) SetLogBatchInterval(interval time.Duration) {
+	if interval <= 0 {
+		s.logBatcher = nil
+		return
+	}
+	s.logBatcher = &logBatcher{interval: interval, repo: s.repo}
+}
+
+// SetTrashRetention makes DeleteTask and BulkDeleteTasksByIDs move tasks to
+// the trash, from which RestoreTask can recover them until PurgeTrash removes
+// them once retention has passed. Zero, the default, deletes tasks right
+// away.
+func (s *Store) SetTrashRetention(retention time.Duration) {
+	s.trashRetention = max(retention, 0)
+}
+
+// TrashRetention returns how long deleted tasks stay in the trash, or zero
+// when tasks are deleted right away.
+func (s *Store) TrashRetention() time.Duration {
+	return s.trashRetention
+}
+
+// SetAttachmentStorage enables task attachments, storing their contents in
+// storage. maxSize caps the size of one attachment; zero uses
+// DefaultMaxAttachmentSize.
+func (s *Store) SetAttachmentStorage(storage AttachmentStorage, maxSize int64) {
+	s.attachments = storage
+	s.maxAttachmentSize = cmp.Or(maxSize, DefaultMaxAttachmentSize)
+}
+
+// MaxAttachmentSize returns the largest attachment the store accepts, or
+// zero when attachments are disabled.
+func (s *Store) MaxAttachmentSize() int64 {
+	if s.attachments == nil {
+		return 0
+	}
+	return s.maxAttachmentSize
+}
+
+// retryPolicy returns the effective retry policy for a repo's tasks.
+func (s *Store) retryPolicy(ctx context.Context, repoID string) (RetryPolicy, error) {
+	if s.retryPolicies == nil {
+		return DefaultRetryPolicy, nil
+	}
+	p, err := s.retryPolicies.RetryPolicy(ctx, repoID)
+	if err != nil {
+		return RetryPolicy{}, fmt.Errorf("resolve retry policy: %w", err)
+	}
+	return DefaultRetryPolicy.Merge(p), nil
+}
+
 // Subscribe returns a channel that receives task events.
 func (s *Store) Subscribe() chan Event {
 	return s.broker.Subscribe()
 }
 
+// Resume subscribes to task events and returns the events published after
+// afterID. See Broker.Resume.
+func (s *Store) Resume(afterID uint64) (chan Event, []Event, bool) {
+	return s.broker.Resume(afterID)
+}
+
+// LastEventID returns the ID of the most recently published event.
+func (s *Store) LastEventID() uint64 {
+	return s.broker.LastEventID()
+}
+
 // Unsubscribe removes and closes a subscriber channel.
 func (s *Store) Unsubscribe(ch chan Event) {
 	s.broker.Unsubscribe(ch)
@@ -62,6 +196,28 @@ func (s *Store) CreateTask(ctx context.Context, task *Task) error {
 		}
 	}
 
+	if task.StackParentID != "" {
+		parentID, err := ParseTaskID(task.StackParentID)
+		if err != nil {
+			return ErrInvalidStackParent
+		}
+		parent, err := s.repo.ReadTask(ctx, parentID)
+		if err != nil {
+			return err
+		}
+		if parent.RepoID != task.RepoID || parent.SkipPR {
+			return ErrInvalidStackParent
+		}
+	}
+
+	if task.Type == TaskTypeTask {
+		policy, err := s.retryPolicy(ctx, task.RepoID)
+		if err != nil {
+			return err
+		}
+		task.MaxAttempts = policy.MaxAttempts
+	}
+
 	if err := s.repo.CreateTask(ctx, task); err != nil {
 		return err
 	}
@@ -76,8 +232,9 @@ func (s *Store) CreateTask(ctx context.Context, task *Task) error {
 }
 
 // CreateTaskFromEpic creates a task associated with an epic. Dependencies
-// are not validated since they are created in the same batch.
-func (s *Store) CreateTaskFromEpic(ctx context.Context, repoID, title, description string, dependsOn, acceptanceCriteria []string, epicID string, ready bool, model string) (string, error) {
+// are not validated since they are created in the same batch. A zero
+// maxCostUSD leaves the task without a budget.
+func (s *Store) CreateTaskFromEpic(ctx context.Context, repoID, title, description string, dependsOn, acceptanceCriteria []string, epicID string, ready bool, model string, maxCostUSD float64) (string, error) {
 	if dependsOn == nil {
 		dependsOn = []string{}
 	}
@@ -87,8 +244,13 @@ func (s *Store) CreateTaskFromEpic(ctx context.Context, repoID, title, descripti
 	if model == "" {
 		model = "sonnet"
 	}
-	t := NewTask(repoID, title, description, dependsOn, acceptanceCriteria, 0, false, false, model, ready)
+	t := NewTask(repoID, title, description, dependsOn, acceptanceCriteria, maxCostUSD, false, false, model, ready)
 	t.EpicID = epicID
+	policy, err := s.retryPolicy(ctx, repoID)
+	if err != nil {
+		return "", err
+	}
+	t.MaxAttempts = policy.MaxAttempts
 	if err := s.repo.CreateTask(ctx, t); err != nil {
 		return "", err
 	}
@@ -124,16 +286,21 @@ func (s *Store) ReadTaskStatus(ctx context.Context, idStr string) (string, error
 	return string(status), nil
 }
 
-// ListTasks returns all tasks.
+// ListTasks returns all tasks that are not archived.
 func (s *Store) ListTasks(ctx context.Context) ([]*Task, error) {
 	return s.repo.ListTasks(ctx)
 }
 
-// ListTasksByRepo returns all tasks for a given repo.
+// ListTasksByRepo returns all tasks for a given repo that are not archived.
 func (s *Store) ListTasksByRepo(ctx context.Context, repoID string) ([]*Task, error) {
 	return s.repo.ListTasksByRepo(ctx, repoID)
 }
 
+// ListArchivedTasksByRepo returns a repo's archived tasks, newest first.
+func (s *Store) ListArchivedTasksByRepo(ctx context.Context, repoID string) ([]*Task, error) {
+	return s.repo.ListArchivedTasksByRepo(ctx, repoID)
+}
+
 // ListTasksByEpic returns all tasks belonging to a given epic.
 func (s *Store) ListTasksByEpic(ctx context.Context, epicID string) ([]*Task, error) {
 	return s.repo.ListTasksByEpic(ctx, epicID)
@@ -144,6 +311,12 @@ func (s *Store) ListTasksInReviewByRepo(ctx context.Context, repoID string) ([]*
 	return s.repo.ListTasksInReviewByRepo(ctx, repoID)
 }
 
+// ListFailedTasksByRepo returns a repo's failed tasks, most recently failed
+// first.
+func (s *Store) ListFailedTasksByRepo(ctx context.Context, repoID string) ([]*Task, error) {
+	return s.repo.ListFailedTasksByRepo(ctx, repoID)
+}
+
 // HasTasksForRepo checks whether any tasks exist for a given repo.
 func (s *Store) HasTasksForRepo(ctx context.Context, repoID string) (bool, error) {
 	return s.repo.HasTasksForRepo(ctx, repoID)
@@ -151,9 +324,10 @@ func (s *Store) HasTasksForRepo(ctx context.Context, repoID string) (bool, error
 
 // RetryTask transitions a task from review back to pending for another attempt.
 // category classifies the failure type (e.g. "ci_failure:tests", "merge_conflict")
-// for circuit breaker detection. If the same category fails consecutively
-// >= 3 times, the task is failed immediately. Categories include the specific
-// failed check names so that different CI failures don't trip the breaker.
+// for circuit breaker detection. If the same category fails consecutively as
+// many times as the repo's RetryPolicy allows (3 by default), the task is
+// failed immediately. Categories include the specific failed check names so
+// that different CI failures don't trip the breaker.
 //
 // Merge conflict retries are exempt from the max attempts limit and circuit
 // breaker because resolving conflicts can be an ongoing process when there is
@@ -168,7 +342,7 @@ func (s *Store) RetryTask(ctx context.Context, id TaskID, category, reason strin
 
 	// Budget check: fail if cost exceeds max
 	if t.MaxCostUSD > 0 && t.CostUSD >= t.MaxCostUSD {
-		return s.UpdateTaskStatus(ctx, id, StatusFailed)
+		return s.FailTask(ctx, id, FailureBudgetExceeded)
 	}
 
 	// Merge conflict retries do not count towards max attempts or trigger
@@ -188,7 +362,12 @@ func (s *Store) RetryTask(ctx context.Context, id TaskID, category, reason strin
 	}
 
 	if t.Attempt >= t.MaxAttempts {
-		return s.UpdateTaskStatus(ctx, id, StatusFailed)
+		return s.FailTask(ctx, id, FailureMaxAttempts)
+	}
+
+	policy, err := s.retryPolicy(ctx, t.RepoID)
+	if err != nil {
+		return err
 	}
 
 	// Circuit breaker: detect consecutive same-category failures.
@@ -199,9 +378,9 @@ func (s *Store) RetryTask(ctx context.Context, id TaskID, category, reason strin
 		consecutiveFailures = t.ConsecutiveFailures + 1
 	}
 
-	if consecutiveFailures >= 3 {
-		// Same failure type three times in a row — fail fast
-		return s.UpdateTaskStatus(ctx, id, StatusFailed)
+	if consecutiveFailures >= policy.Threshold(category) {
+		// Same failure type too many times in a row — fail fast
+		return s.FailTask(ctx, id, failureKind(category))
 	}
 
 	// Update consecutive failure count
@@ -225,7 +404,10 @@ func (s *Store) RetryTask(ctx context.Context, id TaskID, category, reason strin
 // ScheduleRetry transitions a running task back to pending for another attempt.
 // This is used when the agent hits a retryable error such as Claude rate limits
 // or session max usage exceeded. The task keeps its existing PR/branch info so
-// the next attempt can continue where the previous one left off.
+// the next attempt can continue where the previous one left off. A rate-limited
+// task with fallback models left runs its next attempt with the next one (see
+// Task.RunModel); other rate-limit retries are not claimable until their backoff
+// (see RetryPolicy.RateLimitBackoff) has passed.
 func (s *Store) ScheduleRetry(ctx context.Context, id TaskID, reason string) error {
 	t, err := s.repo.ReadTask(ctx, id)
 	if err != nil {
@@ -234,39 +416,93 @@ func (s *Store) ScheduleRetry(ctx context.Context, id TaskID, reason string) err
 
 	// Budget check: fail if cost exceeds max
 	if t.MaxCostUSD > 0 && t.CostUSD >= t.MaxCostUSD {
-		return s.UpdateTaskStatus(ctx, id, StatusFailed)
+		return s.FailTask(ctx, id, FailureBudgetExceeded)
 	}
 
 	if t.Attempt >= t.MaxAttempts {
-		return s.UpdateTaskStatus(ctx, id, StatusFailed)
+		return s.FailTask(ctx, id, FailureMaxAttempts)
+	}
+
+	policy, err := s.retryPolicy(ctx, t.RepoID)
+	if err != nil {
+		return err
+	}
+
+	// Rate-limited tasks switch to their next fallback model if they have
+	// one, rather than waiting for the limit to lift.
+	category, _, _ := strings.Cut(reason, ":")
+	var fallbackModel string
+	if category == RetryCategoryRateLimit {
+		fallbackModel = t.nextFallbackModel()
 	}
 
-	// Circuit breaker: same retryable error twice in a row → fail
+	// Circuit breaker: same retryable error repeatedly in a row → fail.
+	// Switching to a fallback model starts the count over.
 	consecutiveFailures := 1
-	if t.RetryReason == reason {
+	if t.RetryReason == reason && fallbackModel == "" {
 		consecutiveFailures = t.ConsecutiveFailures + 1
 	}
-	if consecutiveFailures >= 3 {
-		return s.UpdateTaskStatus(ctx, id, StatusFailed)
+	if consecutiveFailures >= policy.Threshold(category) {
+		return s.FailTask(ctx, id, category)
 	}
 
 	if err := s.repo.SetConsecutiveFailures(ctx, id, consecutiveFailures); err != nil {
 		return err
 	}
 
-	ok, err := s.repo.ScheduleRetryFromRunning(ctx, id, reason)
+	// Rate-limited tasks without a fallback model back off so the next
+	// attempt doesn't hit the same limit straight away.
+	var notBefore *time.Time
+	if category == RetryCategoryRateLimit && fallbackModel == "" {
+		n, err := s.consecutiveRateLimitRetries(ctx, id)
+		if err != nil {
+			return err
+		}
+		if delay := policy.RateLimitBackoff(n+1, rand.Float64()); delay > 0 {
+			at := time.Now().Add(delay).UTC()
+			notBefore = &at
+		}
+	}
+
+	ok, err := s.repo.ScheduleRetryFromRunning(ctx, id, reason, notBefore)
 	if err != nil {
 		return err
 	}
 	if !ok {
 		return nil // task was not in running status
 	}
+	if err := s.repo.EndTaskAttempt(ctx, id, AttemptResultRetried); err != nil {
+		return err
+	}
+	if fallbackModel != "" {
+		if err := s.repo.SetTaskActiveModel(ctx, id, fallbackModel); err != nil {
+			return err
+		}
+	}
 
 	s.notifyPending()
 	s.publishTaskUpdated(ctx, id)
 	return nil
 }
 
+// consecutiveRateLimitRetries returns the number of the task's most recent
+// attempts in a row that were started by a rate-limit retry.
+func (s *Store) consecutiveRateLimitRetries(ctx context.Context, id TaskID) (int, error) {
+	attempts, err := s.repo.ListTaskAttempts(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for i := len(attempts) - 1; i >= 0; i-- {
+		category, _, _ := strings.Cut(attempts[i].RetryReason, ":")
+		if category != RetryCategoryRateLimit {
+			break
+		}
+		n++
+	}
+	return n, nil
+}
+
 // ManualRetryTask transitions a failed task back to pending for another attempt.
 // instructions contains optional guidance for the agent on the retry.
 // Previous attempt logs are preserved — the UI shows them in separate tabs.
@@ -284,6 +520,33 @@ func (s *Store) ManualRetryTask(ctx context.Context, id TaskID, instructions str
 	return nil
 }
 
+// BulkRetryTasks transitions several failed tasks back to pending at once,
+// giving each the same optional instructions. It returns the IDs of the tasks
+// that were retried; tasks that are not failed are skipped. On error, the
+// tasks retried before it stay retried.
+func (s *Store) BulkRetryTasks(ctx context.Context, ids []TaskID, instructions string) ([]TaskID, error) {
+	var retried []TaskID
+	var err error
+	for _, id := range ids {
+		var ok bool
+		ok, err = s.repo.ManualRetryTask(ctx, id, instructions)
+		if err != nil {
+			break
+		}
+		if ok {
+			retried = append(retried, id)
+		}
+	}
+
+	if len(retried) > 0 {
+		s.notifyPending()
+		for _, id := range retried {
+			s.publishTaskUpdated(ctx, id)
+		}
+	}
+	return retried, err
+}
+
 // FeedbackRetryTask transitions a task in review back to pending so the agent
 // can iterate on its solution based on the user's feedback. Unlike ManualRetryTask,
 // it preserves the existing PR/branch so the agent pushes fixes to the same branch.
@@ -300,7 +563,7 @@ func (s *Store) FeedbackRetryTask(ctx context.Context, id TaskID, feedback strin
 
 	// Budget check
 	if t.MaxCostUSD > 0 && t.CostUSD >= t.MaxCostUSD {
-		return s.UpdateTaskStatus(ctx, id, StatusFailed)
+		return s.FailTask(ctx, id, FailureBudgetExceeded)
 	}
 
 	ok, err := s.repo.FeedbackRetryTask(ctx, id, feedback)
@@ -316,6 +579,92 @@ func (s *Store) FeedbackRetryTask(ctx context.Context, id TaskID, feedback strin
 	return nil
 }
 
+// ReviewFeedbackRetryTask records the latest processed PR review and
+// transitions the task back to pending with the review comments as feedback.
+// The review ID is recorded first so that a task which cannot be retried (e.g.
+// budget exceeded) does not keep reprocessing the same review.
+func (s *Store) ReviewFeedbackRetryTask(ctx context.Context, id TaskID, reviewID int64, feedback string) error {
+	if err := s.repo.SetLastReviewID(ctx, id, reviewID); err != nil {
+		return err
+	}
+	return s.FeedbackRetryTask(ctx, id, feedback)
+}
+
+// SecurityAlertRetryTask sends a task in review back to the agent to
+// remediate security alerts its PR introduced, attaching the alert details as
+// retry context. Like a review feedback retry it does not use up the retry
+// budget. category identifies the alerts (e.g. "security_alert:secret_scanning#3");
+// if the previous attempt was already sent back for exactly these alerts the
+// agent could not fix them, so the task fails instead of looping.
+func (s *Store) SecurityAlertRetryTask(ctx context.Context, id TaskID, category, reason, details string) error {
+	return s.contextFeedbackRetryTask(ctx, id, category, reason, details, msgcat.Text(msgcat.TaskClosedSecurityAlert))
+}
+
+// CompletionValidationRetryTask sends a task in review back to the agent to
+// fix the completion validations its PR failed, attaching the violations as
+// retry context. As with security alerts, a task sent back for the same rules
+// twice in a row fails instead of looping.
+func (s *Store) CompletionValidationRetryTask(ctx context.Context, id TaskID, violations []prlint.Violation) error {
+	rules := prlint.Rules(violations)
+	category := "completion_validation:" + strings.Join(rules, ",")
+	reason := fmt.Sprintf("%s: PR fails %d completion validation(s)", category, len(violations))
+	closeReason := msgcat.Text(msgcat.TaskFailedCompletionValidation, strings.Join(rules, ", "))
+	return s.contextFeedbackRetryTask(ctx, id, category, reason, prlint.Feedback(violations), closeReason)
+}
+
+// contextFeedbackRetryTask transitions a task in review back to pending with
+// details as retry context, or fails it with closeReason when the previous
+// attempt was already sent back for the same category.
+func (s *Store) contextFeedbackRetryTask(ctx context.Context, id TaskID, category, reason, details, closeReason string) error {
+	t, err := s.repo.ReadTask(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if strings.HasPrefix(t.RetryReason, category+":") {
+		if err := s.repo.SetCloseReason(ctx, id, closeReason); err != nil {
+			return err
+		}
+		return s.FailTask(ctx, id, failureKind(category))
+	}
+
+	// Budget check
+	if t.MaxCostUSD > 0 && t.CostUSD >= t.MaxCostUSD {
+		return s.FailTask(ctx, id, FailureBudgetExceeded)
+	}
+
+	var ok bool
+	err = s.repo.BeginTxFunc(ctx, func(ctx context.Context, _ tx.Tx, repo Repository) error {
+		var err error
+		ok, err = repo.FeedbackRetryTask(ctx, id, reason)
+		if err != nil || !ok {
+			return err
+		}
+		// Feedback retries clear the retry context, so set it afterwards.
+		return repo.SetRetryContext(ctx, id, details)
+	})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil // task was not in review status
+	}
+
+	s.notifyPending()
+	s.publishTaskUpdated(ctx, id)
+	return nil
+}
+
+// FailProtectedPaths fails a task whose pull requests change paths its repo
+// protects, recording the offending paths as the close reason. The PRs stay
+// open so a human can inspect them and move the task back to review.
+func (s *Store) FailProtectedPaths(ctx context.Context, id TaskID, paths []string) error {
+	if err := s.repo.SetCloseReason(ctx, id, msgcat.Text(msgcat.TaskFailedProtectedPath, strings.Join(paths, ", "))); err != nil {
+		return err
+	}
+	return s.FailTask(ctx, id, FailureProtectedPath)
+}
+
 // MoveToReview transitions a failed task back to review status. This is only
 // allowed when the task has a PR or branch from a previous attempt — the user
 // wants to treat the existing PR as reviewable despite the agent failure.
@@ -327,13 +676,13 @@ func (s *Store) MoveToReview(ctx context.Context, id TaskID) error {
 	if t.Status != StatusFailed {
 		return ErrTaskNotFailed
 	}
-	if t.PRNumber == 0 && t.BranchName == "" {
+	if !t.HasPullRequest() && t.BranchName == "" {
 		return ErrTaskNoPR
 	}
 	if err := s.repo.UpdateTaskStatus(ctx, id, StatusReview); err != nil {
 		return err
 	}
-	s.publishTaskUpdated(ctx, id)
+	s.publishStatusChange(ctx, id, t.Status)
 	return nil
 }
 
@@ -422,9 +771,12 @@ func (s *Store) SetRetryContext(ctx context.Context, id TaskID, retryCtx string)
 	return s.repo.SetRetryContext(ctx, id, retryCtx)
 }
 
-// AddCost adds to the accumulated cost for a task.
+// AddCost adds to the accumulated cost for a task and its latest attempt.
 func (s *Store) AddCost(ctx context.Context, id TaskID, costUSD float64) error {
-	return s.repo.AddCost(ctx, id, costUSD)
+	if err := s.repo.AddCost(ctx, id, costUSD); err != nil {
+		return err
+	}
+	return s.repo.AddTaskAttemptCost(ctx, id, costUSD)
 }
 
 // SetCloseReason sets the close/failure reason on a task without changing its status.
@@ -465,7 +817,8 @@ func (s *Store) SetReady(ctx context.Context, id TaskID, ready bool) error {
 }
 
 // UpdatePendingTask updates a pending task's editable fields. If the task is no
-// longer in pending status, the update is rejected with a conflict error.
+// longer in pending status, or has changed since params.ExpectedVersion, the
+// update is rejected with a conflict error.
 func (s *Store) UpdatePendingTask(ctx context.Context, id TaskID, params UpdatePendingTaskParams) error {
 	// Validate all dependencies exist
 	for _, depID := range params.DependsOn {
@@ -487,7 +840,14 @@ func (s *Store) UpdatePendingTask(ctx context.Context, id TaskID, params UpdateP
 		return err
 	}
 	if !ok {
-		return ErrTaskNotPending
+		t, err := s.repo.ReadTask(ctx, id)
+		if err != nil {
+			return err
+		}
+		if t.Status != StatusPending {
+			return ErrTaskNotPending
+		}
+		return ErrTaskVersionMismatch
 	}
 	if params.Ready {
 		s.notifyPending()
@@ -499,26 +859,52 @@ func (s *Store) UpdatePendingTask(ctx context.Context, id TaskID, params UpdateP
 // StartOverTask resets a task from review, failed, or closed back to pending, clearing
 // all metadata (logs, PR, branch, agent status, cost) and optionally updating
 // the task details (title, description, acceptance criteria). Returns the task
-// before reset so the caller can close the PR if needed.
+// before reset so the caller can close the PR if needed. Returns
+// ErrTaskVersionMismatch if the task has changed since params.ExpectedVersion.
 func (s *Store) StartOverTask(ctx context.Context, id TaskID, params StartOverTaskParams) (*Task, error) {
 	// Read the task before reset so we can return PR info for cleanup.
 	t, err := s.repo.ReadTask(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if params.ExpectedVersion != 0 && params.ExpectedVersion != t.Version {
+		return nil, ErrTaskVersionMismatch
+	}
+	// Pinned to the version read so the returned PRs are the ones the reset
+	// clears.
+	params.ExpectedVersion = t.Version
+
+	policy, err := s.retryPolicy(ctx, t.RepoID)
+	if err != nil {
+		return nil, err
+	}
+	params.MaxAttempts = policy.MaxAttempts
 
 	ok, err := s.repo.StartOverTask(ctx, id, params)
 	if err != nil {
 		return nil, err
 	}
 	if !ok {
+		cur, err := s.repo.ReadTask(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		switch cur.Status {
+		case StatusReview, StatusFailed, StatusClosed:
+			return nil, ErrTaskVersionMismatch
+		}
 		return nil, nil // task was not in review, failed, or closed status
 	}
 
-	// Delete all logs for a clean slate.
+	// Delete all logs and artifacts for a clean slate.
+	artifactKeys, err := s.listArtifactKeys(ctx, id)
+	if err != nil {
+		return nil, err
+	}
 	if err := s.repo.DeleteTaskLogs(ctx, id); err != nil {
 		return nil, err
 	}
+	s.deleteAttachmentContents(ctx, artifactKeys)
 
 	s.notifyPending()
 	s.publishTaskUpdated(ctx, id)
@@ -527,11 +913,23 @@ func (s *Store) StartOverTask(ctx context.Context, id TaskID, params StartOverTa
 
 // ClaimPendingTask finds a pending task with all dependencies met and claims it
 // by setting its status to running. When repoIDs is non-empty, only tasks
-// belonging to those repos are considered. The read-check-claim flow is wrapped
-// in a transaction and uses optimistic locking (WHERE status = 'pending') so
-// that concurrent workers cannot claim the same task.
-func (s *Store) ClaimPendingTask(ctx context.Context, repoIDs []string) (*Task, error) {
-	var claimed *Task
+// belonging to those repos are considered. Tasks in paused repos, and tasks
+// whose required labels, or whose repo's required labels, are not all in
+// workerLabels are skipped. The read-check-claim flow is wrapped in a
+// transaction and uses optimistic locking (WHERE status = 'pending') so that
+// concurrent workers cannot claim the same task.
+func (s *Store) ClaimPendingTask(ctx context.Context, repoIDs []string, workerLabels []string) (*Task, error) {
+	claimed, err := s.ClaimPendingTasks(ctx, repoIDs, workerLabels, 1)
+	if err != nil || len(claimed) == 0 {
+		return nil, err
+	}
+	return claimed[0], nil
+}
+
+// ClaimPendingTasks claims up to limit pending tasks in one transaction, in the
+// order ClaimPendingTask would claim them one at a time.
+func (s *Store) ClaimPendingTasks(ctx context.Context, repoIDs []string, workerLabels []string, limit int) ([]*Task, error) {
+	var claimed []*Task
 	err := s.repo.BeginTxFunc(ctx, func(ctx context.Context, _ tx.Tx, repo Repository) error {
 		var pending []*Task
 		var err error
@@ -543,8 +941,22 @@ func (s *Store) ClaimPendingTask(ctx context.Context, repoIDs []string) (*Task,
 		if err != nil {
 			return err
 		}
+		repoLabels, err := repo.ListRepoRequiredLabels(ctx)
+		if err != nil {
+			return err
+		}
+		pausedRepos, err := repo.ListPausedRepoIDs(ctx)
+		if err != nil {
+			return err
+		}
 		for _, t := range pending {
-			if !dependenciesMet(ctx, repo, t.DependsOn) {
+			if slices.Contains(pausedRepos, t.RepoID) {
+				continue
+			}
+			if !HasLabels(workerLabels, t.RequiredLabels) || !HasLabels(workerLabels, repoLabels[t.RepoID]) {
+				continue
+			}
+			if !dependenciesMet(ctx, repo, t) {
 				continue
 			}
 			ok, err := repo.ClaimTask(ctx, t.ID)
@@ -554,27 +966,51 @@ func (s *Store) ClaimPendingTask(ctx context.Context, repoIDs []string) (*Task,
 			if !ok {
 				continue // Already claimed by another worker
 			}
+			if err := repo.StartTaskAttempt(ctx, t.ID, t.Attempt, t.RetryReason, t.RunModel()); err != nil {
+				return err
+			}
 			t.Status = StatusRunning
-			claimed = t
-			return nil
+			claimed = append(claimed, t)
+			if len(claimed) >= limit {
+				return nil
+			}
 		}
 		return nil
 	})
-	if err == nil && claimed != nil {
-		t := *claimed
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range claimed {
+		t := *c
 		t.Logs = nil
-		s.broker.Publish(ctx, Event{Type: EventTaskUpdated, RepoID: claimed.RepoID, Task: &t})
+		s.broker.Publish(ctx, Event{Type: EventTaskUpdated, RepoID: c.RepoID, Task: &t})
+		if s.statusListener != nil {
+			s.statusListener.TaskStatusChanged(ctx, &t)
+		}
 	}
-	return claimed, err
+	return claimed, nil
 }
 
-// dependenciesMet checks if all dependency tasks are in a terminal success state.
-func dependenciesMet(ctx context.Context, repo Repository, dependsOn []string) bool {
-	for _, depID := range dependsOn {
+// dependenciesMet checks if all dependency tasks are in a terminal success
+// state. The dependency a task is stacked on only needs an open pull request,
+// since the task branches off the parent's branch.
+func dependenciesMet(ctx context.Context, repo Repository, t *Task) bool {
+	stackParent := t.StackParent()
+	for _, depID := range t.DependsOn {
 		id, err := ParseTaskID(depID)
 		if err != nil {
 			return false
 		}
+		if depID == stackParent {
+			parent, err := repo.ReadTask(ctx, id)
+			if err != nil {
+				return false
+			}
+			if !parent.Stackable() && parent.Status != StatusMerged && parent.Status != StatusClosed {
+				return false
+			}
+			continue
+		}
 		status, err := repo.ReadTaskStatus(ctx, id)
 		if err != nil {
 			return false
@@ -599,75 +1035,963 @@ func (s *Store) StreamTaskLogs(ctx context.Context, id TaskID, fn func(attempt i
 
 // AppendTaskLogs appends log lines to a task for the given attempt.
 func (s *Store) AppendTaskLogs(ctx context.Context, id TaskID, attempt int, logs []string) error {
-	if err := s.repo.AppendTaskLogs(ctx, id, attempt, logs); err != nil {
+	var err error
+	if s.logBatcher != nil {
+		err = s.logBatcher.append(ctx, LogBatch{TaskID: id, Attempt: attempt, Lines: logs})
+	} else {
+		err = s.repo.AppendTaskLogs(ctx, id, attempt, logs)
+	}
+	if err != nil {
 		return err
 	}
 	s.broker.Publish(ctx, Event{Type: EventLogsAppended, TaskID: id, Attempt: attempt, Logs: logs})
 	return nil
 }
 
-
-// DeleteExpiredLogs deletes all log entries older than the given retention duration.
-// Returns the number of log batches deleted.
-func (s *Store) DeleteExpiredLogs(ctx context.Context, retention time.Duration) (int64, error) {
-	before := time.Now().Add(-retention)
-	return s.repo.DeleteExpiredLogs(ctx, before)
+// ListAttempts returns a task's attempts, oldest first.
+func (s *Store) ListAttempts(ctx context.Context, id TaskID) ([]*Attempt, error) {
+	return s.repo.ListTaskAttempts(ctx, id)
 }
 
-// Heartbeat updates the last heartbeat time for a running task.
-// Returns true if the task is still running, false if it was stopped, closed,
-// or deleted — signalling the worker to cancel execution.
-func (s *Store) Heartbeat(ctx context.Context, id TaskID) (bool, error) {
-	return s.repo.Heartbeat(ctx, id)
+// StreamAttemptLogs iterates the log batches of a single attempt, calling fn
+// for each batch.
+func (s *Store) StreamAttemptLogs(ctx context.Context, id TaskID, attempt int, fn func(lines []string) error) error {
+	return s.repo.StreamTaskAttemptLogs(ctx, id, attempt, fn)
 }
 
-// TimeoutStaleTasks fails running tasks whose heartbeat has expired.
-func (s *Store) TimeoutStaleTasks(ctx context.Context, timeout time.Duration) (int, error) {
-	threshold := time.Now().Add(-timeout)
-	tasks, err := s.repo.ListStaleTasks(ctx, threshold)
+// AppendAgentEvents stores structured events reported by the agent during an
+// attempt and publishes them to subscribers. Plan and step events also update
+// the task's progress, which is published as its own event, and note events
+// are stored as notes on the task.
+func (s *Store) AppendAgentEvents(ctx context.Context, id TaskID, attempt int, events []*AgentEvent) error {
+	var (
+		progress *Progress
+		repoID   string
+	)
+	err := s.repo.BeginTxFunc(ctx, func(ctx context.Context, _ tx.Tx, repo Repository) error {
+		if err := repo.AppendTaskEvents(ctx, id, attempt, events); err != nil {
+			return err
+		}
+		current, err := repo.ReadTaskProgress(ctx, id)
+		if err != nil {
+			return err
+		}
+		changed := false
+		var notes []string
+		now := time.Now()
+		for _, e := range events {
+			if current.Apply(e, now) {
+				changed = true
+			}
+			if text, ok := noteText(e); ok {
+				notes = append(notes, text)
+			}
+		}
+		if !changed && len(notes) == 0 {
+			return nil
+		}
+		t, err := repo.ReadTask(ctx, id)
+		if err != nil {
+			return err
+		}
+		for _, text := range notes {
+			if _, err := repo.CreateTaskNote(ctx, id, t.RepoID, attempt, text); err != nil {
+				return err
+			}
+		}
+		if !changed {
+			return nil
+		}
+		if err := repo.SetTaskProgress(ctx, id, current); err != nil {
+			return err
+		}
+		progress, repoID = current, t.RepoID
+		return nil
+	})
 	if err != nil {
-		return 0, err
+		return err
 	}
-	count := 0
-	for _, t := range tasks {
-		_ = s.repo.SetCloseReason(ctx, t.ID, "Worker timeout: no heartbeat received")
-		if err := s.repo.UpdateTaskStatus(ctx, t.ID, StatusFailed); err != nil {
-			continue
-		}
-		count++
-		s.publishTaskUpdated(ctx, t.ID)
+	s.broker.Publish(ctx, Event{Type: EventAgentEventsAppended, TaskID: id, Attempt: attempt, AgentEvents: events})
+	if progress != nil {
+		s.broker.Publish(ctx, Event{Type: EventTaskProgress, RepoID: repoID, TaskID: id, Attempt: progress.Attempt, Progress: progress})
 	}
-	return count, nil
+	return nil
 }
 
-// UpdateTaskStatus updates a task's status.
-func (s *Store) UpdateTaskStatus(ctx context.Context, id TaskID, status Status) error {
-	if err := s.repo.UpdateTaskStatus(ctx, id, status); err != nil {
-		return err
-	}
-	s.publishTaskUpdated(ctx, id)
-	return nil
+// ReadProgress returns the agent's latest reported plan for a task.
+func (s *Store) ReadProgress(ctx context.Context, id TaskID) (*Progress, error) {
+	return s.repo.ReadTaskProgress(ctx, id)
 }
 
-// SetTaskPullRequest sets the PR URL and number, moving the task to review status.
-func (s *Store) SetTaskPullRequest(ctx context.Context, id TaskID, prURL string, prNumber int) error {
-	if err := s.repo.SetTaskPullRequest(ctx, id, prURL, prNumber); err != nil {
-		return err
+// NudgeTask queues a message for the agent of a running task so the user can
+// redirect it without stopping and retrying. The worker picks the nudge up
+// with its next heartbeat.
+func (s *Store) NudgeTask(ctx context.Context, id TaskID, message string) (*Nudge, error) {
+	t, err := s.repo.ReadTask(ctx, id)
+	if err != nil {
+		return nil, err
 	}
-	s.publishTaskUpdated(ctx, id)
-	return nil
+	if t.Status != StatusRunning {
+		return nil, ErrTaskNotRunning
+	}
+	nudge, err := s.repo.CreateTaskNudge(ctx, id, t.Attempt, message)
+	if err != nil {
+		return nil, err
+	}
+	s.broker.Publish(ctx, Event{Type: EventTaskNudges, RepoID: t.RepoID, TaskID: id, Attempt: t.Attempt, Nudges: []*Nudge{nudge}})
+	return nudge, nil
 }
 
-// ListTasksInReview returns all tasks in review status.
-func (s *Store) ListTasksInReview(ctx context.Context) ([]*Task, error) {
-	return s.repo.ListTasksInReview(ctx)
+// ListNudges returns a task's nudges across all attempts, oldest first.
+func (s *Store) ListNudges(ctx context.Context, id TaskID) ([]*Nudge, error) {
+	return s.repo.ListTaskNudges(ctx, id)
 }
 
-// SetTaskBranch sets the branch name and moves the task to review status.
+// DeliverNudges hands a task's undelivered nudges to the worker, marking them
+// delivered.
+func (s *Store) DeliverNudges(ctx context.Context, id TaskID) ([]*Nudge, error) {
+	nudges, err := s.repo.DeliverTaskNudges(ctx, id, time.Now())
+	if err != nil || len(nudges) == 0 {
+		return nudges, err
+	}
+	if t, err := s.repo.ReadTask(ctx, id); err == nil {
+		s.broker.Publish(ctx, Event{Type: EventTaskNudges, RepoID: t.RepoID, TaskID: id, Attempt: t.Attempt, Nudges: nudges})
+	}
+	return nudges, nil
+}
+
+// ListRepoNotes returns the notes agents left on a repo's tasks, newest
+// first.
+func (s *Store) ListRepoNotes(ctx context.Context, repoID string) ([]*Note, error) {
+	return s.repo.ListRepoNotes(ctx, repoID)
+}
+
+// ReadNote returns a note.
+func (s *Store) ReadNote(ctx context.Context, noteID int64) (*Note, error) {
+	note, err := s.repo.ReadTaskNote(ctx, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if note == nil {
+		return nil, ErrNoteNotFound
+	}
+	return note, nil
+}
+
+// ConvertNote creates a pending task for the follow-up work described by a
+// note and records the task on the note. A note can only be converted once.
+func (s *Store) ConvertNote(ctx context.Context, noteID int64, model string) (*Task, error) {
+	note, err := s.ReadNote(ctx, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if note.ConvertedTaskID != "" {
+		return nil, ErrNoteConverted
+	}
+	sourceID, err := ParseTaskID(note.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	source, err := s.repo.ReadTask(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := s.retryPolicy(ctx, note.RepoID)
+	if err != nil {
+		return nil, err
+	}
+
+	task := NoteTask(note, source, model)
+	task.MaxAttempts = policy.MaxAttempts
+	err = s.repo.BeginTxFunc(ctx, func(ctx context.Context, _ tx.Tx, repo Repository) error {
+		converted, err := repo.SetTaskNoteConverted(ctx, noteID, task.ID)
+		if err != nil {
+			return err
+		}
+		if !converted {
+			return ErrNoteConverted
+		}
+		return repo.CreateTask(ctx, task)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.notifyPending()
+
+	t := *task
+	t.Logs = nil
+	s.broker.Publish(ctx, Event{Type: EventTaskCreated, RepoID: task.RepoID, Task: &t})
+	return task, nil
+}
+
+// CreateComment adds a comment to a task. A comment referring to an attempt
+// must name one the task has started. Returns ErrAttemptNotFound otherwise.
+func (s *Store) CreateComment(ctx context.Context, id TaskID, comment *Comment) (*Comment, error) {
+	t, err := s.repo.ReadTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.Attempt != nil && (*comment.Attempt < 1 || *comment.Attempt > t.Attempt) {
+		return nil, ErrAttemptNotFound
+	}
+	comment.TaskID = id.String()
+	created, err := s.repo.CreateTaskComment(ctx, comment)
+	if err != nil {
+		return nil, err
+	}
+	s.broker.Publish(ctx, Event{Type: EventCommentCreated, RepoID: t.RepoID, TaskID: id, Comment: created})
+	return created, nil
+}
+
+// ListComments returns a task's comments, oldest first.
+func (s *Store) ListComments(ctx context.Context, id TaskID) ([]*Comment, error) {
+	return s.repo.ListTaskComments(ctx, id)
+}
+
+// ReadComment returns a comment on a task. Returns ErrCommentNotFound if the
+// task has no such comment.
+func (s *Store) ReadComment(ctx context.Context, id TaskID, commentID int64) (*Comment, error) {
+	comment, err := s.repo.ReadTaskComment(ctx, id, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment == nil {
+		return nil, ErrCommentNotFound
+	}
+	return comment, nil
+}
+
+// UpdateComment replaces the body of a comment on a task. Returns
+// ErrCommentNotFound if the task has no such comment.
+func (s *Store) UpdateComment(ctx context.Context, id TaskID, commentID int64, body string) (*Comment, error) {
+	t, err := s.repo.ReadTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := s.repo.UpdateTaskComment(ctx, id, commentID, body)
+	if err != nil {
+		return nil, err
+	}
+	if comment == nil {
+		return nil, ErrCommentNotFound
+	}
+	s.broker.Publish(ctx, Event{Type: EventCommentUpdated, RepoID: t.RepoID, TaskID: id, Comment: comment})
+	return comment, nil
+}
+
+// DeleteComment deletes a comment on a task. Returns ErrCommentNotFound if
+// the task has no such comment.
+func (s *Store) DeleteComment(ctx context.Context, id TaskID, commentID int64) error {
+	comment, err := s.ReadComment(ctx, id, commentID)
+	if err != nil {
+		return err
+	}
+	t, err := s.repo.ReadTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	deleted, err := s.repo.DeleteTaskComment(ctx, id, commentID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrCommentNotFound
+	}
+	s.broker.Publish(ctx, Event{Type: EventCommentDeleted, RepoID: t.RepoID, TaskID: id, Comment: comment})
+	return nil
+}
+
+// CountComments returns the number of comments on a task.
+func (s *Store) CountComments(ctx context.Context, id TaskID) (int, error) {
+	return s.repo.CountTaskComments(ctx, id)
+}
+
+// SetCommentCounts sets the CommentCount of each of a repo's tasks.
+func (s *Store) SetCommentCounts(ctx context.Context, repoID string, tasks []*Task) error {
+	counts, err := s.repo.CountTaskCommentsByRepo(ctx, repoID)
+	if err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		t.CommentCount = counts[t.ID.String()]
+	}
+	return nil
+}
+
+// CreateAttachment attaches a file to a task. The caller validates the
+// filename, content type and size. Returns ErrAttachmentLimit when the task
+// already has MaxAttachmentsPerTask attachments and ErrAttachmentExists when
+// it has one with the same filename.
+func (s *Store) CreateAttachment(ctx context.Context, id TaskID, attachment *Attachment, data []byte) (*Attachment, error) {
+	if s.attachments == nil {
+		return nil, ErrAttachmentsNotConfigured
+	}
+	if _, err := s.repo.ReadTask(ctx, id); err != nil {
+		return nil, err
+	}
+	n, err := s.repo.CountTaskAttachments(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if n >= MaxAttachmentsPerTask {
+		return nil, ErrAttachmentLimit
+	}
+
+	attachment.TaskID = id.String()
+	attachment.SizeBytes = int64(len(data))
+	attachment.StorageKey = newAttachmentKey(id)
+	if err := s.attachments.Put(ctx, attachment.StorageKey, data); err != nil {
+		return nil, fmt.Errorf("store attachment: %w", err)
+	}
+	created, err := s.repo.CreateTaskAttachment(ctx, attachment)
+	if err != nil {
+		_ = s.attachments.Delete(ctx, attachment.StorageKey)
+		return nil, err
+	}
+	return created, nil
+}
+
+// ListAttachments returns a task's attachments, oldest first. It returns an
+// empty list when attachments are disabled.
+func (s *Store) ListAttachments(ctx context.Context, id TaskID) ([]*Attachment, error) {
+	if s.attachments == nil {
+		return []*Attachment{}, nil
+	}
+	return s.repo.ListTaskAttachments(ctx, id)
+}
+
+// OpenAttachment returns an attachment of a task along with its contents,
+// which the caller closes. Returns ErrAttachmentNotFound if the task has no
+// such attachment.
+func (s *Store) OpenAttachment(ctx context.Context, id TaskID, attachmentID int64) (*Attachment, io.ReadCloser, error) {
+	if s.attachments == nil {
+		return nil, nil, ErrAttachmentsNotConfigured
+	}
+	attachment, err := s.repo.ReadTaskAttachment(ctx, id, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attachment == nil {
+		return nil, nil, ErrAttachmentNotFound
+	}
+	rc, err := s.attachments.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read attachment: %w", err)
+	}
+	return attachment, rc, nil
+}
+
+// DeleteAttachment removes an attachment from a task. Returns
+// ErrAttachmentNotFound if the task has no such attachment.
+func (s *Store) DeleteAttachment(ctx context.Context, id TaskID, attachmentID int64) error {
+	if s.attachments == nil {
+		return ErrAttachmentsNotConfigured
+	}
+	attachment, err := s.repo.ReadTaskAttachment(ctx, id, attachmentID)
+	if err != nil {
+		return err
+	}
+	if attachment == nil {
+		return ErrAttachmentNotFound
+	}
+	deleted, err := s.repo.DeleteTaskAttachment(ctx, id, attachmentID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrAttachmentNotFound
+	}
+	// The metadata is gone, so contents left behind by a failed delete are
+	// unreachable but harmless.
+	_ = s.attachments.Delete(ctx, attachment.StorageKey)
+	return nil
+}
+
+// CreateArtifact records a file an agent produced during an attempt of a
+// task. The caller validates the name and size. Returns ErrAttemptNotFound
+// when the task has not reached the attempt, ErrArtifactLimit when the
+// attempt already recorded MaxArtifactsPerAttempt artifacts and
+// ErrArtifactExists when it recorded one with the same name.
+func (s *Store) CreateArtifact(ctx context.Context, id TaskID, artifact *Artifact, data []byte) (*Artifact, error) {
+	if s.attachments == nil {
+		return nil, ErrAttachmentsNotConfigured
+	}
+	t, err := s.repo.ReadTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if artifact.Attempt < 1 || artifact.Attempt > t.Attempt {
+		return nil, ErrAttemptNotFound
+	}
+	n, err := s.repo.CountTaskArtifacts(ctx, id, artifact.Attempt)
+	if err != nil {
+		return nil, err
+	}
+	if n >= MaxArtifactsPerAttempt {
+		return nil, ErrArtifactLimit
+	}
+
+	artifact.TaskID = id.String()
+	artifact.ContentType = ArtifactContentType(artifact.Name)
+	artifact.SizeBytes = int64(len(data))
+	artifact.StorageKey = newArtifactKey(id)
+	if err := s.attachments.Put(ctx, artifact.StorageKey, data); err != nil {
+		return nil, fmt.Errorf("store artifact: %w", err)
+	}
+	created, err := s.repo.CreateTaskArtifact(ctx, artifact)
+	if err != nil {
+		_ = s.attachments.Delete(ctx, artifact.StorageKey)
+		return nil, err
+	}
+	return created, nil
+}
+
+// ListArtifacts returns a task's artifacts ordered by attempt. When attempt
+// is greater than zero only that attempt's artifacts are returned. It
+// returns an empty list when attachment storage is disabled.
+func (s *Store) ListArtifacts(ctx context.Context, id TaskID, attempt int) ([]*Artifact, error) {
+	if s.attachments == nil {
+		return []*Artifact{}, nil
+	}
+	artifacts, err := s.repo.ListTaskArtifacts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if attempt == 0 {
+		return artifacts, nil
+	}
+	return slices.DeleteFunc(artifacts, func(a *Artifact) bool { return a.Attempt != attempt }), nil
+}
+
+// OpenArtifact returns an artifact of a task along with its contents, which
+// the caller closes. Returns ErrArtifactNotFound if the task has no such
+// artifact.
+func (s *Store) OpenArtifact(ctx context.Context, id TaskID, artifactID int64) (*Artifact, io.ReadCloser, error) {
+	if s.attachments == nil {
+		return nil, nil, ErrAttachmentsNotConfigured
+	}
+	artifact, err := s.repo.ReadTaskArtifact(ctx, id, artifactID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if artifact == nil {
+		return nil, nil, ErrArtifactNotFound
+	}
+	rc, err := s.attachments.Get(ctx, artifact.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read artifact: %w", err)
+	}
+	return artifact, rc, nil
+}
+
+// DeleteExpiredArtifacts deletes artifacts older than the given retention
+// duration along with their contents. Returns the number of artifacts
+// deleted.
+func (s *Store) DeleteExpiredArtifacts(ctx context.Context, retention time.Duration) (int, error) {
+	keys, err := s.repo.DeleteExpiredTaskArtifacts(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+	if s.attachments != nil {
+		s.deleteAttachmentContents(ctx, keys)
+	}
+	return len(keys), nil
+}
+
+// listAttachmentKeys returns the storage keys of the given tasks'
+// attachments and artifacts, so their contents can be removed once the tasks
+// are deleted.
+func (s *Store) listAttachmentKeys(ctx context.Context, ids ...TaskID) ([]string, error) {
+	if s.attachments == nil {
+		return nil, nil
+	}
+	var keys []string
+	for _, id := range ids {
+		attachments, err := s.repo.ListTaskAttachments(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range attachments {
+			keys = append(keys, a.StorageKey)
+		}
+		artifactKeys, err := s.listArtifactKeys(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, artifactKeys...)
+	}
+	return keys, nil
+}
+
+// listArtifactKeys returns the storage keys of a task's artifacts, which are
+// deleted along with its logs.
+func (s *Store) listArtifactKeys(ctx context.Context, id TaskID) ([]string, error) {
+	if s.attachments == nil {
+		return nil, nil
+	}
+	artifacts, err := s.repo.ListTaskArtifacts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, len(artifacts))
+	for i, a := range artifacts {
+		keys[i] = a.StorageKey
+	}
+	return keys, nil
+}
+
+// deleteAttachmentContents removes attachment contents from storage. Failures
+// only leave unreachable objects behind, so they are ignored.
+func (s *Store) deleteAttachmentContents(ctx context.Context, keys []string) {
+	if s.effects != nil {
+		// Kept until the deletion commits.
+		s.effects.attachmentKeys = append(s.effects.attachmentKeys, keys...)
+		return
+	}
+	for _, key := range keys {
+		_ = s.attachments.Delete(ctx, key)
+	}
+}
+
+// RecordCIDispatch records that the repo's CI workflow was dispatched on the
+// task's PR branch, replacing any earlier dispatch.
+func (s *Store) RecordCIDispatch(ctx context.Context, id TaskID, dispatch *CIDispatch) error {
+	return s.repo.SetTaskCIDispatch(ctx, id, dispatch)
+}
+
+// ReadCIDispatch returns the task's latest CI workflow dispatch, or nil if
+// none was made.
+func (s *Store) ReadCIDispatch(ctx context.Context, id TaskID) (*CIDispatch, error) {
+	return s.repo.ReadTaskCIDispatch(ctx, id)
+}
+
+// RecordProvenanceReview stores the result of scanning the task's PR diff and
+// notifies subscribers. An earlier acknowledgment is kept when the findings
+// are unchanged, so re-scanning a PR after unrelated commits doesn't ask the
+// reviewer to acknowledge the same additions again.
+func (s *Store) RecordProvenanceReview(ctx context.Context, id TaskID, review *ProvenanceReview) error {
+	prev, err := s.repo.ReadTaskProvenanceReview(ctx, id)
+	if err != nil {
+		return err
+	}
+	if prev != nil && prev.AcknowledgedAt != nil && provenance.Equal(prev.Findings, review.Findings) {
+		review.AcknowledgedAt = prev.AcknowledgedAt
+	}
+	if err := s.repo.SetTaskProvenanceReview(ctx, id, review); err != nil {
+		return err
+	}
+	s.publishProvenance(ctx, id, review)
+	return nil
+}
+
+// ReadProvenanceReview returns the task's provenance review, or nil if its PR
+// has not been scanned.
+func (s *Store) ReadProvenanceReview(ctx context.Context, id TaskID) (*ProvenanceReview, error) {
+	return s.repo.ReadTaskProvenanceReview(ctx, id)
+}
+
+// AcknowledgeProvenanceReview records that a human has reviewed the task's
+// provenance findings. Acknowledging an already acknowledged review is a
+// no-op.
+func (s *Store) AcknowledgeProvenanceReview(ctx context.Context, id TaskID) (*ProvenanceReview, error) {
+	review, err := s.repo.ReadTaskProvenanceReview(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if review == nil {
+		return nil, ErrProvenanceReviewNotFound
+	}
+	if review.AcknowledgedAt != nil {
+		return review, nil
+	}
+	now := time.Now().UTC()
+	review.AcknowledgedAt = &now
+	if err := s.repo.SetTaskProvenanceReview(ctx, id, review); err != nil {
+		return nil, err
+	}
+	s.publishProvenance(ctx, id, review)
+	return review, nil
+}
+
+// RecordSelfReview stores the result of the automated self-review of the
+// task's PR.
+func (s *Store) RecordSelfReview(ctx context.Context, id TaskID, review *SelfReview) error {
+	return s.repo.SetTaskSelfReview(ctx, id, review)
+}
+
+// ReadSelfReview returns the task's self-review, or nil if its PR has not
+// been reviewed.
+func (s *Store) ReadSelfReview(ctx context.Context, id TaskID) (*SelfReview, error) {
+	return s.repo.ReadTaskSelfReview(ctx, id)
+}
+
+// RecordShadowDiff stores the diff from a shadow-mode run of the task and
+// moves the task to review so a human can inspect it. Nothing was pushed, so
+// the task has no branch or pull request.
+func (s *Store) RecordShadowDiff(ctx context.Context, id TaskID, diff string) error {
+	t, err := s.repo.ReadTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.SetTaskShadowDiff(ctx, id, &ShadowDiff{
+		Attempt:   t.Attempt,
+		Diff:      diff,
+		CreatedAt: time.Now().UTC(),
+	}); err != nil {
+		return err
+	}
+	return s.UpdateTaskStatus(ctx, id, StatusReview)
+}
+
+// ReadShadowDiff returns the diff from the task's latest shadow-mode run, or
+// nil if it has never run in shadow mode.
+func (s *Store) ReadShadowDiff(ctx context.Context, id TaskID) (*ShadowDiff, error) {
+	return s.repo.ReadTaskShadowDiff(ctx, id)
+}
+
+// RecordProposal stores the change plan from a plan-only run of the task and
+// moves the task to review so a human can approve it or request changes.
+// Recording a new proposal clears the approval of an earlier one.
+func (s *Store) RecordProposal(ctx context.Context, id TaskID, proposal *Proposal) error {
+	t, err := s.repo.ReadTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	proposal.Attempt = t.Attempt
+	proposal.CreatedAt = time.Now().UTC()
+	proposal.ApprovedAt = nil
+	if err := s.repo.SetTaskProposal(ctx, id, proposal); err != nil {
+		return err
+	}
+	return s.UpdateTaskStatus(ctx, id, StatusReview)
+}
+
+// ReadProposal returns the proposal from the task's latest plan-only run, or
+// nil if it has never run in plan-only mode.
+func (s *Store) ReadProposal(ctx context.Context, id TaskID) (*Proposal, error) {
+	return s.repo.ReadTaskProposal(ctx, id)
+}
+
+// ApproveProposal approves the proposal of a plan-only task in review and
+// sends the task back to the agent to implement it. The task stops being
+// plan-only, so the next run opens a pull request as usual. Like a feedback
+// retry, it does not use up the retry budget.
+func (s *Store) ApproveProposal(ctx context.Context, id TaskID) error {
+	t, err := s.repo.ReadTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	proposal, err := s.repo.ReadTaskProposal(ctx, id)
+	if err != nil {
+		return err
+	}
+	if proposal == nil {
+		return ErrProposalNotFound
+	}
+	if !t.PlanOnly || t.Status != StatusReview || proposal.ApprovedAt != nil {
+		return ErrProposalNotPending
+	}
+
+	err = s.repo.BeginTxFunc(ctx, func(ctx context.Context, _ tx.Tx, repo Repository) error {
+		ok, err := repo.ApproveTaskProposal(ctx, id, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrProposalNotPending
+		}
+		if err := repo.SetTaskPlanOnly(ctx, id, false); err != nil {
+			return err
+		}
+		ok, err = repo.FeedbackRetryTask(ctx, id, "proposal_approved: implement the approved proposal")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrProposalNotPending
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.notifyPending()
+	s.publishTaskUpdated(ctx, id)
+	return nil
+}
+
+func (s *Store) publishProvenance(ctx context.Context, id TaskID, review *ProvenanceReview) {
+	if t, err := s.repo.ReadTask(ctx, id); err == nil {
+		s.broker.Publish(ctx, Event{Type: EventTaskProvenance, RepoID: t.RepoID, TaskID: id, Provenance: review})
+	}
+}
+
+// ListAgentEvents returns a task's agent events, oldest first. When attempt is
+// non-zero only that attempt's events are returned.
+func (s *Store) ListAgentEvents(ctx context.Context, id TaskID, attempt int) ([]*AgentEvent, error) {
+	events, err := s.repo.ListTaskEvents(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if attempt == 0 {
+		return events, nil
+	}
+	return slices.DeleteFunc(events, func(e *AgentEvent) bool { return e.Attempt != attempt }), nil
+}
+
+// DeleteExpiredLogs deletes all log entries older than the given retention duration.
+// Returns the number of log batches deleted.
+func (s *Store) DeleteExpiredLogs(ctx context.Context, retention time.Duration) (int64, error) {
+	before := time.Now().Add(-retention)
+	return s.repo.DeleteExpiredLogs(ctx, before)
+}
+
+// Heartbeat updates the last heartbeat time for a running task.
+// Returns true if the task is still running, false if it was stopped, closed,
+// or deleted — signalling the worker to cancel execution.
+func (s *Store) Heartbeat(ctx context.Context, id TaskID) (bool, error) {
+	return s.repo.Heartbeat(ctx, id)
+}
+
+// TimeoutStaleTasks fails running tasks that have run past their max runtime
+// (see Task.MaxRuntimeSeconds) or whose heartbeat has expired, recording a
+// different close reason and failure category for each. Tasks with an expired
+// heartbeat whose retry policy sets RequeueStale are requeued instead (see
+// requeueStaleTask). It returns how many tasks were failed and requeued.
+func (s *Store) TimeoutStaleTasks(ctx context.Context, timeout time.Duration) (failed, requeued int, err error) {
+	now := time.Now()
+	overrun, err := s.repo.ListOverrunTasks(ctx, now)
+	if err != nil {
+		return 0, 0, err
+	}
+	failed = s.failRunningTasks(ctx, overrun, msgcat.Text(msgcat.TaskFailedMaxRuntime), FailureMaxRuntime)
+
+	stale, err := s.repo.ListStaleTasks(ctx, now.Add(-timeout))
+	if err != nil {
+		return failed, 0, err
+	}
+	var fail []*Task
+	for _, t := range stale {
+		policy, err := s.retryPolicy(ctx, t.RepoID)
+		if err != nil || !policy.RequeuesStale() {
+			fail = append(fail, t)
+			continue
+		}
+		ok, err := s.requeueStaleTask(ctx, t)
+		switch {
+		case err != nil:
+			continue
+		case ok:
+			requeued++
+		default:
+			failed++
+		}
+	}
+	failed += s.failRunningTasks(ctx, fail, msgcat.Text(msgcat.TaskClosedWorkerTimeout), FailureWorkerTimeout)
+	return failed, requeued, nil
+}
+
+// requeueStaleTask schedules another attempt of a running task whose worker
+// stopped sending heartbeats, keeping its branch and PR. The usual max
+// attempts and circuit breaker rules apply, so a task that keeps losing its
+// worker still fails. It reports whether the task went back to pending.
+func (s *Store) requeueStaleTask(ctx context.Context, t *Task) (bool, error) {
+	reason := RetryCategoryWorkerLost + ": " + msgcat.Text(msgcat.TaskClosedWorkerTimeout)
+	if err := s.ScheduleRetry(ctx, t.ID, reason); err != nil {
+		return false, err
+	}
+	after, err := s.repo.ReadTask(ctx, t.ID)
+	if err != nil {
+		return false, err
+	}
+	if after.Status != StatusFailed {
+		return after.Status == StatusPending, nil
+	}
+	_ = s.repo.SetCloseReason(ctx, t.ID, msgcat.Text(msgcat.TaskClosedWorkerTimeout))
+	return false, nil
+}
+
+// failRunningTasks fails the given running tasks with a close reason and
+// failure category, returning how many were failed.
+func (s *Store) failRunningTasks(ctx context.Context, tasks []*Task, reason, category string) int {
+	count := 0
+	for _, t := range tasks {
+		_ = s.repo.SetCloseReason(ctx, t.ID, reason)
+		_ = s.repo.SetFailureCategory(ctx, t.ID, category)
+		if err := s.repo.UpdateTaskStatus(ctx, t.ID, StatusFailed); err != nil {
+			continue
+		}
+		_ = s.repo.EndTaskAttempt(ctx, t.ID, string(StatusFailed))
+		count++
+		s.publishStatusChange(ctx, t.ID, t.Status)
+	}
+	return count
+}
+
+// FailTask fails a task, recording category (see the Failure* constants) as
+// why it failed.
+func (s *Store) FailTask(ctx context.Context, id TaskID, category string) error {
+	if err := s.repo.SetFailureCategory(ctx, id, category); err != nil {
+		return err
+	}
+	return s.UpdateTaskStatus(ctx, id, StatusFailed)
+}
+
+// UpdateTaskStatus updates a task's status.
+func (s *Store) UpdateTaskStatus(ctx context.Context, id TaskID, status Status) error {
+	prev := s.currentStatus(ctx, id)
+	if err := s.repo.UpdateTaskStatus(ctx, id, status); err != nil {
+		return err
+	}
+	if status != StatusRunning {
+		if err := s.repo.EndTaskAttempt(ctx, id, string(status)); err != nil {
+			return err
+		}
+	}
+	s.publishStatusChange(ctx, id, prev)
+	return nil
+}
+
+// ForceStatus sets a task's status outside the normal lifecycle to repair a
+// task left in an inconsistent state. The transition must pass
+// ValidateForcedStatus. Forcing a task to failed or closed records the reason
+// as its close reason. Returns the task's previous status.
+func (s *Store) ForceStatus(ctx context.Context, id TaskID, status Status, reason string) (Status, error) {
+	t, err := s.repo.ReadTask(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if err := ValidateForcedStatus(t, status); err != nil {
+		return "", err
+	}
+	// Only merged and closed tasks stay archived.
+	if t.ArchivedAt != nil && status != StatusMerged && status != StatusClosed {
+		if _, err := s.unarchiveTask(ctx, id); err != nil {
+			return "", err
+		}
+	}
+	if status == StatusFailed || status == StatusClosed {
+		if err := s.repo.SetCloseReason(ctx, id, msgcat.Text(msgcat.TaskClosedByAdmin, reason)); err != nil {
+			return "", err
+		}
+	}
+	if status == StatusFailed {
+		if err := s.repo.SetFailureCategory(ctx, id, FailureForced); err != nil {
+			return "", err
+		}
+	}
+	if err := s.repo.UpdateTaskStatus(ctx, id, status); err != nil {
+		return "", err
+	}
+	if err := s.repo.EndTaskAttempt(ctx, id, string(status)); err != nil {
+		return "", err
+	}
+	s.publishStatusChange(ctx, id, t.Status)
+	if status == StatusPending && t.Ready {
+		s.notifyPending()
+	}
+	return t.Status, nil
+}
+
+// SetTaskPullRequest sets the PR URL and number, moving the task to review status.
+func (s *Store) SetTaskPullRequest(ctx context.Context, id TaskID, prURL string, prNumber int) error {
+	prev := s.currentStatus(ctx, id)
+	if err := s.repo.SetTaskPullRequest(ctx, id, prURL, prNumber); err != nil {
+		return err
+	}
+	if err := s.repo.EndTaskAttempt(ctx, id, string(StatusReview)); err != nil {
+		return err
+	}
+	s.publishStatusChange(ctx, id, prev)
+	return nil
+}
+
+// SetTaskPullRequests records the pull requests the agent opened in a
+// multi-repo task's additional repos and moves the task to review status.
+// Each PR replaces the one previously recorded for its repo; a PR that is
+// reported again keeps its merged state and processed reviews, and repos the
+// agent did not report keep their earlier PR.
+func (s *Store) SetTaskPullRequests(ctx context.Context, id TaskID, prs []PullRequest) error {
+	t, err := s.repo.ReadTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	updated := slices.Clone(t.PullRequests)
+	for _, pr := range prs {
+		i := slices.IndexFunc(updated, func(p PullRequest) bool { return p.RepoID == pr.RepoID })
+		switch {
+		case i < 0:
+			updated = append(updated, pr)
+		case updated[i].Number != pr.Number:
+			updated[i] = pr
+		default:
+			updated[i].URL = pr.URL
+		}
+	}
+	if err := s.repo.SetTaskPullRequests(ctx, id, updated); err != nil {
+		return err
+	}
+	if t.Status != StatusReview {
+		return s.UpdateTaskStatus(ctx, id, StatusReview)
+	}
+	s.publishTaskUpdated(ctx, id)
+	return nil
+}
+
+// MarkPullRequestMerged records that the PR opened in one of a multi-repo
+// task's additional repos has been merged.
+func (s *Store) MarkPullRequestMerged(ctx context.Context, id TaskID, repoID string) error {
+	return s.updatePullRequest(ctx, id, repoID, func(pr *PullRequest) {
+		pr.Merged = true
+	})
+}
+
+// PullRequestReviewFeedbackRetryTask is ReviewFeedbackRetryTask for a review
+// of the PR opened in one of a multi-repo task's additional repos. The review
+// ID is recorded on that PR rather than the task.
+func (s *Store) PullRequestReviewFeedbackRetryTask(ctx context.Context, id TaskID, repoID string, reviewID int64, feedback string) error {
+	err := s.updatePullRequest(ctx, id, repoID, func(pr *PullRequest) {
+		pr.LastReviewID = reviewID
+	})
+	if err != nil {
+		return err
+	}
+	return s.FeedbackRetryTask(ctx, id, feedback)
+}
+
+// updatePullRequest applies fn to the task's PR for repoID. It is a no-op
+// when the task has no PR for the repo.
+func (s *Store) updatePullRequest(ctx context.Context, id TaskID, repoID string, fn func(pr *PullRequest)) error {
+	t, err := s.repo.ReadTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(t.PullRequests, func(p PullRequest) bool { return p.RepoID == repoID })
+	if i < 0 {
+		return nil
+	}
+	fn(&t.PullRequests[i])
+	if err := s.repo.SetTaskPullRequests(ctx, id, t.PullRequests); err != nil {
+		return err
+	}
+	s.publishTaskUpdated(ctx, id)
+	return nil
+}
+
+// ListTasksInReview returns all tasks in review status.
+func (s *Store) ListTasksInReview(ctx context.Context) ([]*Task, error) {
+	return s.repo.ListTasksInReview(ctx)
+}
+
+// SetTaskBranch sets the branch name and moves the task to review status.
 func (s *Store) SetTaskBranch(ctx context.Context, id TaskID, branchName string) error {
 	if err := s.repo.SetBranchName(ctx, id, branchName); err != nil {
 		return err
 	}
+	if err := s.repo.EndTaskAttempt(ctx, id, string(StatusReview)); err != nil {
+		return err
+	}
 	s.publishTaskUpdated(ctx, id)
 	return nil
 }
@@ -688,11 +2012,56 @@ func (s *Store) StopTask(ctx context.Context, id TaskID, reason string) error {
 	if !ok {
 		return nil // task was not in running status
 	}
+	if err := s.repo.EndTaskAttempt(ctx, id, AttemptResultStopped); err != nil {
+		return err
+	}
 	s.queueStop(id)
 	s.publishTaskUpdated(ctx, id)
 	return nil
 }
 
+// AbandonTask returns a running task to pending after its worker shut down
+// without finishing it, so another worker can pick it up right away rather
+// than after the heartbeat timeout. The attempt is not counted against the
+// retry budget. Returns false if the task was not running.
+func (s *Store) AbandonTask(ctx context.Context, id TaskID) (bool, error) {
+	ok, err := s.repo.AbandonTask(ctx, id)
+	if err != nil || !ok {
+		return false, err
+	}
+	if err := s.rescheduleAbandoned(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ReleaseOrphanedTask returns a running task to pending after the worker
+// that claimed it disappeared, like AbandonTask. The task is only released
+// if it hasn't sent a heartbeat since before, so a task that is still alive,
+// e.g. because its worker's heartbeats reached another replica, is left
+// running. Returns false if the task was not released.
+func (s *Store) ReleaseOrphanedTask(ctx context.Context, id TaskID, before time.Time) (bool, error) {
+	ok, err := s.repo.AbandonStaleTask(ctx, id, before)
+	if err != nil || !ok {
+		return false, err
+	}
+	if err := s.rescheduleAbandoned(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// rescheduleAbandoned ends the attempt of a task that was returned to pending
+// by AbandonTask or ReleaseOrphanedTask and wakes up pollers.
+func (s *Store) rescheduleAbandoned(ctx context.Context, id TaskID) error {
+	if err := s.repo.EndTaskAttempt(ctx, id, AttemptResultAbandoned); err != nil {
+		return err
+	}
+	s.notifyPending()
+	s.publishStatusChange(ctx, id, StatusRunning)
+	return nil
+}
+
 // queueStop appends a task ID to the pending stops list and signals
 // the stop channel so the poll-based stop loop can deliver it.
 func (s *Store) queueStop(id TaskID) {
@@ -727,13 +2096,27 @@ func (s *Store) DrainStops() []TaskID {
 
 // CloseTask closes a task with an optional reason.
 func (s *Store) CloseTask(ctx context.Context, id TaskID, reason string) error {
+	prev := s.currentStatus(ctx, id)
 	if err := s.repo.CloseTask(ctx, id, reason); err != nil {
 		return err
 	}
-	s.publishTaskUpdated(ctx, id)
+	if err := s.repo.EndTaskAttempt(ctx, id, string(StatusClosed)); err != nil {
+		return err
+	}
+	s.publishStatusChange(ctx, id, prev)
 	return nil
 }
 
+// CloseTaskFromEpic closes a task by its ID string. It satisfies the epic
+// package's TaskCloser interface.
+func (s *Store) CloseTaskFromEpic(ctx context.Context, idStr, reason string) error {
+	id, err := ParseTaskID(idStr)
+	if err != nil {
+		return err
+	}
+	return s.CloseTask(ctx, id, reason)
+}
+
 // BulkCloseTasksByEpic closes all non-terminal tasks for an epic and publishes
 // update events for each affected task.
 func (s *Store) BulkCloseTasksByEpic(ctx context.Context, epicID, reason string) error {
@@ -747,10 +2130,16 @@ func (s *Store) BulkCloseTasksByEpic(ctx context.Context, epicID, reason string)
 		return err
 	}
 
-	// Publish update events for tasks that were actually closed.
+	// End running attempts and publish update events for tasks that were
+	// actually closed.
 	for _, t := range tasks {
+		if t.Status == StatusRunning {
+			if err := s.repo.EndTaskAttempt(ctx, t.ID, string(StatusClosed)); err != nil {
+				return err
+			}
+		}
 		if t.Status != StatusClosed && t.Status != StatusMerged {
-			s.publishTaskUpdated(ctx, t.ID)
+			s.publishStatusChange(ctx, t.ID, t.Status)
 		}
 	}
 	return nil
@@ -792,9 +2181,18 @@ func (s *Store) BulkDeleteTasksByEpic(ctx context.Context, epicID string) error
 		}
 	}
 
+	ids := make([]TaskID, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.ID
+	}
+	attachmentKeys, err := s.listAttachmentKeys(ctx, ids...)
+	if err != nil {
+		return err
+	}
 	if err := s.repo.BulkDeleteTasksByEpic(ctx, epicID); err != nil {
 		return err
 	}
+	s.deleteAttachmentContents(ctx, attachmentKeys)
 
 	// Publish deletion events.
 	for _, t := range tasks {
@@ -803,8 +2201,9 @@ func (s *Store) BulkDeleteTasksByEpic(ctx context.Context, epicID string) error
 	return nil
 }
 
-// BulkDeleteTasksByIDs deletes multiple tasks (and their logs) by ID
-// and publishes deletion events for each affected task.
+// BulkDeleteTasksByIDs deletes multiple tasks (and their logs) by ID, or
+// moves them to the trash when a trash retention is set, and publishes
+// deletion events for each affected task.
 func (s *Store) BulkDeleteTasksByIDs(ctx context.Context, ids []string) error {
 	if len(ids) == 0 {
 		return nil
@@ -846,8 +2245,25 @@ func (s *Store) BulkDeleteTasksByIDs(ctx context.Context, ids []string) error {
 		}
 	}
 
-	if err := s.repo.BulkDeleteTasksByIDs(ctx, ids); err != nil {
-		return err
+	if s.trashRetention > 0 {
+		for _, t := range toDelete {
+			if _, err := s.repo.TrashTask(ctx, t.ID); err != nil {
+				return err
+			}
+		}
+	} else {
+		taskIDs := make([]TaskID, len(toDelete))
+		for i, t := range toDelete {
+			taskIDs[i] = t.ID
+		}
+		attachmentKeys, err := s.listAttachmentKeys(ctx, taskIDs...)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.BulkDeleteTasksByIDs(ctx, ids); err != nil {
+			return err
+		}
+		s.deleteAttachmentContents(ctx, attachmentKeys)
 	}
 
 	// Publish deletion events.
@@ -858,6 +2274,8 @@ func (s *Store) BulkDeleteTasksByIDs(ctx context.Context, ids []string) error {
 }
 
 // DeleteTask deletes a task, its logs, and removes it from any other tasks' dependency lists.
+// When a trash retention is set, the task and its logs are moved to the trash
+// instead.
 func (s *Store) DeleteTask(ctx context.Context, id TaskID) error {
 	// Read task before deletion for event publishing
 	t, err := s.repo.ReadTask(ctx, id)
@@ -881,21 +2299,157 @@ func (s *Store) DeleteTask(ctx context.Context, id TaskID) error {
 		}
 	}
 
+	if s.trashRetention > 0 {
+		if _, err := s.repo.TrashTask(ctx, id); err != nil {
+			return err
+		}
+	} else if err := s.hardDeleteTask(ctx, id); err != nil {
+		return err
+	}
+
+	// Publish deletion event
+	event := Event{Type: EventTaskDeleted, RepoID: t.RepoID, TaskID: id}
+	if s.effects != nil {
+		s.effects.deleted = append(s.effects.deleted, event)
+		return nil
+	}
+	s.broker.Publish(ctx, event)
+	return nil
+}
+
+func (s *Store) hardDeleteTask(ctx context.Context, id TaskID) error {
+	attachmentKeys, err := s.listAttachmentKeys(ctx, id)
+	if err != nil {
+		return err
+	}
 	// Delete task logs first (task_log has a FK reference to task)
 	if err := s.repo.DeleteTaskLogs(ctx, id); err != nil {
 		return err
 	}
-
-	// Delete the task
 	if err := s.repo.DeleteTask(ctx, id); err != nil {
 		return err
 	}
-
-	// Publish deletion event
-	s.broker.Publish(ctx, Event{Type: EventTaskDeleted, RepoID: t.RepoID, TaskID: id})
+	s.deleteAttachmentContents(ctx, attachmentKeys)
 	return nil
 }
 
+// ListTrashedTasksByRepo returns a repo's trashed tasks, most recently
+// trashed first.
+func (s *Store) ListTrashedTasksByRepo(ctx context.Context, repoID string) ([]*Task, error) {
+	return s.repo.ListTrashedTasksByRepo(ctx, repoID)
+}
+
+// RestoreTask moves a trashed task back out of the trash with its logs and
+// attempts. It comes back without its epic and without the dependency edges
+// removed when it was deleted. Returns ErrTaskNotTrashed if the task is not
+// in the trash.
+func (s *Store) RestoreTask(ctx context.Context, id TaskID) (*Task, error) {
+	ok, err := s.repo.RestoreTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrTaskNotTrashed
+	}
+	t, err := s.repo.ReadTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if t.Status == StatusPending && t.Ready {
+		s.notifyPending()
+	}
+	tc := *t
+	tc.Logs = nil
+	s.broker.Publish(ctx, Event{Type: EventTaskCreated, RepoID: t.RepoID, Task: &tc})
+	return t, nil
+}
+
+// archiveBatchSize is the number of tasks ArchiveTasks archives per query.
+const archiveBatchSize = 100
+
+// ArchiveTasks archives every task that has been merged or closed for longer
+// than after, moving its logs to the log archive, and returns the number of
+// tasks archived. Each task is archived in its own transaction.
+func (s *Store) ArchiveTasks(ctx context.Context, after time.Duration) (int, error) {
+	before := time.Now().Add(-after)
+	archived := 0
+	for {
+		ids, err := s.repo.ListArchivableTaskIDs(ctx, before, archiveBatchSize)
+		if err != nil {
+			return archived, err
+		}
+		for _, id := range ids {
+			ok, err := s.archiveTask(ctx, id)
+			if err != nil {
+				return archived, err
+			}
+			if ok {
+				archived++
+			}
+		}
+		if len(ids) < archiveBatchSize {
+			return archived, nil
+		}
+	}
+}
+
+func (s *Store) archiveTask(ctx context.Context, id TaskID) (bool, error) {
+	var archived bool
+	err := s.repo.BeginTxFunc(ctx, func(ctx context.Context, _ tx.Tx, repo Repository) error {
+		var err error
+		archived, err = repo.ArchiveTask(ctx, id)
+		return err
+	})
+	return archived, err
+}
+
+// UnarchiveTask brings an archived task back into task lists with its logs.
+// Returns ErrTaskNotArchived if the task is not archived.
+func (s *Store) UnarchiveTask(ctx context.Context, id TaskID) (*Task, error) {
+	ok, err := s.unarchiveTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrTaskNotArchived
+	}
+	t, err := s.repo.ReadTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.broker.Publish(ctx, Event{Type: EventTaskUpdated, RepoID: t.RepoID, Task: t})
+	return t, nil
+}
+
+func (s *Store) unarchiveTask(ctx context.Context, id TaskID) (bool, error) {
+	var unarchived bool
+	err := s.repo.BeginTxFunc(ctx, func(ctx context.Context, _ tx.Tx, repo Repository) error {
+		var err error
+		unarchived, err = repo.UnarchiveTask(ctx, id)
+		return err
+	})
+	return unarchived, err
+}
+
+// PurgeTrash permanently deletes the tasks that have been in the trash for
+// longer than the trash retention, along with their logs. Returns the number
+// of tasks deleted.
+func (s *Store) PurgeTrash(ctx context.Context) (int, error) {
+	if s.trashRetention <= 0 {
+		return 0, nil
+	}
+	ids, err := s.repo.ListTrashedTaskIDsBefore(ctx, time.Now().Add(-s.trashRetention))
+	if err != nil {
+		return 0, err
+	}
+	for i, id := range ids {
+		if err := s.hardDeleteTask(ctx, id); err != nil {
+			return i, err
+		}
+	}
+	return len(ids), nil
+}
+
 // WaitForPending returns a channel that signals when a pending task might be available.
 func (s *Store) WaitForPending() <-chan struct{} {
 	s.pendingMu.Lock()
@@ -903,7 +2457,26 @@ func (s *Store) WaitForPending() <-chan struct{} {
 	return s.pendingCh
 }
 
+// NotifyPending wakes waiting polls so they look for pending tasks again,
+// such as after a paused repo is resumed.
+func (s *Store) NotifyPending() {
+	s.notifyPending()
+}
+
 func (s *Store) notifyPending() {
+	if s.effects != nil {
+		s.effects.pending = true
+		return
+	}
+	s.WakePending()
+	if s.pendingNotifier != nil {
+		_ = s.pendingNotifier.NotifyPending(context.Background())
+	}
+}
+
+// WakePending wakes this instance's waiting polls without notifying the
+// other instances. It is called by the listen side of a PendingNotifier.
+func (s *Store) WakePending() {
 	s.pendingMu.Lock()
 	defer s.pendingMu.Unlock()
 	select {
@@ -912,13 +2485,45 @@ func (s *Store) notifyPending() {
 	}
 }
 
-func (s *Store) publishTaskUpdated(ctx context.Context, id TaskID) {
+func (s *Store) publishTaskUpdated(ctx context.Context, id TaskID) *Task {
+	if s.effects != nil {
+		s.effects.taskUpdated(id, "")
+		return nil
+	}
 	t, err := s.repo.ReadTask(ctx, id)
 	if err != nil {
-		return
+		return nil
 	}
 	t.Logs = nil
 	s.broker.Publish(ctx, Event{Type: EventTaskUpdated, RepoID: t.RepoID, Task: t})
+	return t
+}
+
+// publishStatusChange publishes a task update and notifies the status listener
+// if the task's status differs from prev.
+func (s *Store) publishStatusChange(ctx context.Context, id TaskID, prev Status) {
+	if s.effects != nil {
+		s.effects.taskUpdated(id, prev)
+		return
+	}
+	t := s.publishTaskUpdated(ctx, id)
+	if t == nil || s.statusListener == nil || t.Status == prev {
+		return
+	}
+	s.statusListener.TaskStatusChanged(ctx, t)
+}
+
+// currentStatus returns the task's status for transition detection. It only
+// reads the task when a status listener is set.
+func (s *Store) currentStatus(ctx context.Context, id TaskID) Status {
+	if s.statusListener == nil {
+		return ""
+	}
+	t, err := s.repo.ReadTask(ctx, id)
+	if err != nil {
+		return ""
+	}
+	return t.Status
 }
 
 // PublishRepoEvent publishes a repo_updated SSE event so the UI can react
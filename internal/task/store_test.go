@@ -3,12 +3,21 @@ package task_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/joshjon/kit/errtag"
+	"github.com/joshjon/kit/tx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/vervesh/verve/internal/msgcat"
+	"github.com/vervesh/verve/internal/prlint"
+	"github.com/vervesh/verve/internal/provenance"
 	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/selfreview"
 	"github.com/vervesh/verve/internal/sqlite"
 	"github.com/vervesh/verve/internal/task"
 )
@@ -219,6 +228,58 @@ func TestStore_RetryTask_CircuitBreakerAllowsSecondRetry(t *testing.T) {
 	assert.NotEqual(t, task.StatusFailed, read.Status, "second consecutive failure should still allow retry")
 }
 
+// staticRetryPolicy resolves the same retry policy for every repo.
+func staticRetryPolicy(p task.RetryPolicy) task.RetryPolicyResolverFunc {
+	return func(context.Context, string) (task.RetryPolicy, error) {
+		return p, nil
+	}
+}
+
+func TestStore_CreateTask_RetryPolicyMaxAttempts(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+	f.store.SetRetryPolicyResolver(staticRetryPolicy(task.RetryPolicy{MaxAttempts: 8}))
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.store.CreateTask(ctx, tsk))
+
+	read, err := f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 8, read.MaxAttempts)
+
+	id, err := f.store.CreateTaskFromEpic(ctx, f.repoID, "epic task", "desc", nil, nil, "", true, "", 0)
+	require.NoError(t, err)
+	read, err = f.taskRepo.ReadTask(ctx, task.MustParseTaskID(id))
+	require.NoError(t, err)
+	assert.Equal(t, 8, read.MaxAttempts)
+}
+
+func TestStore_RetryTask_CategoryLimit(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+	f.store.SetRetryPolicyResolver(staticRetryPolicy(task.RetryPolicy{CategoryLimits: map[string]int{"ci_failure": 2}}))
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
+	require.NoError(t, f.taskRepo.SetTaskPullRequest(ctx, tsk.ID, "https://github.com/org/repo/pull/1", 1))
+	require.NoError(t, f.taskRepo.SetConsecutiveFailures(ctx, tsk.ID, 1))
+	ok, err := f.taskRepo.RetryTask(ctx, tsk.ID, "ci_failure:tests: CI tests failed")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
+	require.NoError(t, f.taskRepo.SetTaskPullRequest(ctx, tsk.ID, "https://github.com/org/repo/pull/1", 1))
+	require.NoError(t, f.taskRepo.SetConsecutiveFailures(ctx, tsk.ID, 1))
+
+	err = f.store.RetryTask(ctx, tsk.ID, "ci_failure:tests", "CI tests failed again")
+	require.NoError(t, err)
+
+	read, err := f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	// The default threshold would allow a second failure; the ci_failure limit does not.
+	assert.Equal(t, task.StatusFailed, read.Status)
+}
+
 func TestStore_RetryTask_DifferentCategory(t *testing.T) {
 	f := newTestTaskFixture(t)
 	ctx := context.Background()
@@ -306,6 +367,49 @@ func TestStore_ManualRetryTask(t *testing.T) {
 	assert.Equal(t, task.StatusPending, read.Status)
 }
 
+func TestStore_BulkRetryTasks(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	var failed []task.TaskID
+	for _, title := range []string{"first", "second"} {
+		tsk := f.newTask(title, "desc", true)
+		require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+		require.NoError(t, f.store.FailTask(ctx, tsk.ID, task.FailureAgentError))
+		failed = append(failed, tsk.ID)
+	}
+	running := f.newTask("running", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, running))
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, running.ID, task.StatusRunning))
+
+	list, err := f.store.ListFailedTasksByRepo(ctx, f.repoID)
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+
+	select {
+	case <-f.store.WaitForPending():
+	default:
+	}
+
+	retried, err := f.store.BulkRetryTasks(ctx, append(failed, running.ID), "check the flaky test")
+	require.NoError(t, err)
+	assert.Equal(t, failed, retried, "only failed tasks are retried")
+
+	for _, id := range failed {
+		read, err := f.taskRepo.ReadTask(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, task.StatusPending, read.Status)
+		assert.Equal(t, "check the flaky test", read.RetryReason)
+		assert.Empty(t, read.FailureCategory, "retrying clears the failure category")
+	}
+
+	select {
+	case <-f.store.WaitForPending():
+	default:
+		assert.Fail(t, "expected pending notification after bulk retry")
+	}
+}
+
 func TestStore_ManualRetryTask_NotFailed(t *testing.T) {
 	f := newTestTaskFixture(t)
 	ctx := context.Background()
@@ -365,6 +469,129 @@ func TestStore_FeedbackRetryTask_IgnoresMaxAttempts(t *testing.T) {
 	assert.NotEqual(t, task.StatusFailed, read.Status, "feedback should not fail task at max attempts")
 }
 
+func TestStore_ReviewFeedbackRetryTask(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
+	require.NoError(t, f.taskRepo.SetTaskPullRequest(ctx, tsk.ID, "https://github.com/org/repo/pull/1", 1))
+
+	err := f.store.ReviewFeedbackRetryTask(ctx, tsk.ID, 42, "PR review from @alice requested changes:\nrename the handler")
+	require.NoError(t, err)
+
+	read, err := f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusPending, read.Status)
+	assert.Equal(t, int64(42), read.LastReviewID)
+	assert.Equal(t, "PR review from @alice requested changes:\nrename the handler", read.RetryReason)
+}
+
+func TestStore_SecurityAlertRetryTask(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
+	require.NoError(t, f.taskRepo.SetTaskPullRequest(ctx, tsk.ID, "https://github.com/org/repo/pull/1", 1))
+
+	category := "security_alert:secret_scanning#7"
+	reason := category + ": PR introduces 1 security alert(s)"
+	err := f.store.SecurityAlertRetryTask(ctx, tsk.ID, category, reason, "Secret scanning alert #7 in config.go:3")
+	require.NoError(t, err)
+
+	read, err := f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusPending, read.Status)
+	assert.Equal(t, reason, read.RetryReason)
+	assert.Equal(t, "Secret scanning alert #7 in config.go:3", read.RetryContext)
+
+	// The agent's attempt leaves the same alert open: fail rather than loop.
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
+	require.NoError(t, f.taskRepo.SetTaskPullRequest(ctx, tsk.ID, "https://github.com/org/repo/pull/1", 1))
+	err = f.store.SecurityAlertRetryTask(ctx, tsk.ID, category, reason, "Secret scanning alert #7 in config.go:3")
+	require.NoError(t, err)
+
+	read, err = f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusFailed, read.Status)
+	assert.Equal(t, msgcat.Text(msgcat.TaskClosedSecurityAlert), read.CloseReason)
+}
+
+func TestStore_CompletionValidationRetryTask(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
+	require.NoError(t, f.taskRepo.SetTaskPullRequest(ctx, tsk.ID, "https://github.com/org/repo/pull/1", 1))
+
+	violations := []prlint.Violation{
+		{Rule: prlint.RuleTitlePattern, Message: `PR title "Fix bug" must match the pattern ^fix: `},
+		{Rule: prlint.RuleMaxCommits, Message: "PR has 4 commits; squash them to at most 1"},
+	}
+	err := f.store.CompletionValidationRetryTask(ctx, tsk.ID, violations)
+	require.NoError(t, err)
+
+	read, err := f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusPending, read.Status)
+	assert.Equal(t, "completion_validation:max_commits,title_pattern: PR fails 2 completion validation(s)", read.RetryReason)
+	assert.Equal(t, prlint.Feedback(violations), read.RetryContext)
+
+	// The next attempt breaks the same rules: fail rather than loop.
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
+	require.NoError(t, f.taskRepo.SetTaskPullRequest(ctx, tsk.ID, "https://github.com/org/repo/pull/1", 1))
+	err = f.store.CompletionValidationRetryTask(ctx, tsk.ID, violations)
+	require.NoError(t, err)
+
+	read, err = f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusFailed, read.Status)
+	assert.Equal(t, msgcat.Text(msgcat.TaskFailedCompletionValidation, "max_commits, title_pattern"), read.CloseReason)
+}
+
+func TestStore_FailProtectedPaths(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
+	require.NoError(t, f.store.SetTaskPullRequest(ctx, tsk.ID, "https://github.com/org/repo/pull/1", 1))
+
+	err := f.store.FailProtectedPaths(ctx, tsk.ID, []string{".github/workflows/ci.yml", ".env"})
+	require.NoError(t, err)
+
+	read, err := f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusFailed, read.Status)
+	assert.Equal(t, msgcat.Text(msgcat.TaskFailedProtectedPath, ".github/workflows/ci.yml, .env"), read.CloseReason)
+	assert.Equal(t, 1, read.PRNumber)
+}
+
+func TestStore_ReviewFeedbackRetryTask_BudgetExceededRecordsReview(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	tsk := f.newTaskWithBudget("title", "desc", 5.0)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
+	require.NoError(t, f.taskRepo.SetTaskPullRequest(ctx, tsk.ID, "https://github.com/org/repo/pull/1", 1))
+	require.NoError(t, f.taskRepo.AddCost(ctx, tsk.ID, 6.0))
+
+	err := f.store.ReviewFeedbackRetryTask(ctx, tsk.ID, 7, "fix the tests")
+	require.NoError(t, err)
+
+	read, err := f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusFailed, read.Status)
+	assert.Equal(t, int64(7), read.LastReviewID, "review should be recorded so it is not reprocessed")
+}
+
 func TestStore_FeedbackRetryTask_IncrementsAttemptAndMaxAttempts(t *testing.T) {
 	f := newTestTaskFixture(t)
 	ctx := context.Background()
@@ -455,7 +682,7 @@ func TestStore_FeedbackRetryTask_ThenAutomatedRetryGetsFullBudget(t *testing.T)
 func TestStore_ClaimPendingTask_NoPending(t *testing.T) {
 	f := newTestTaskFixture(t)
 
-	claimed, err := f.store.ClaimPendingTask(context.Background(), nil)
+	claimed, err := f.store.ClaimPendingTask(context.Background(), nil, nil)
 	require.NoError(t, err)
 	assert.Nil(t, claimed, "expected nil claimed task when no pending tasks")
 }
@@ -467,7 +694,7 @@ func TestStore_ClaimPendingTask_Success(t *testing.T) {
 	tsk := f.newTask("title", "desc", true)
 	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
 
-	claimed, err := f.store.ClaimPendingTask(ctx, nil)
+	claimed, err := f.store.ClaimPendingTask(ctx, nil, nil)
 	require.NoError(t, err)
 	require.NotNil(t, claimed, "expected non-nil claimed task")
 	assert.Equal(t, task.StatusRunning, claimed.Status)
@@ -480,11 +707,126 @@ func TestStore_ClaimPendingTask_WithRepoFilter(t *testing.T) {
 	tsk := f.newTask("title", "desc", true)
 	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
 
-	claimed, err := f.store.ClaimPendingTask(ctx, []string{f.repoID})
+	claimed, err := f.store.ClaimPendingTask(ctx, []string{f.repoID}, nil)
 	require.NoError(t, err)
 	require.NotNil(t, claimed, "expected non-nil claimed task")
 }
 
+func TestStore_ClaimPendingTasks(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	for range 3 {
+		require.NoError(t, f.taskRepo.CreateTask(ctx, f.newTask("title", "desc", true)))
+	}
+
+	claimed, err := f.store.ClaimPendingTasks(ctx, nil, nil, 2)
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	for _, c := range claimed {
+		assert.Equal(t, task.StatusRunning, c.Status)
+	}
+
+	claimed, err = f.store.ClaimPendingTasks(ctx, nil, nil, 2)
+	require.NoError(t, err)
+	assert.Len(t, claimed, 1, "only the remaining task should be claimed")
+}
+
+func TestStore_ClaimPendingTask_RequiredLabels(t *testing.T) {
+	db := sqlite.NewTestDB(t)
+	ctx := context.Background()
+
+	repos := sqlite.NewRepoRepository(db)
+	r, err := repo.NewRepo("owner/test-repo")
+	require.NoError(t, err)
+	require.NoError(t, repos.CreateRepo(ctx, r))
+	store := task.NewStore(sqlite.NewTaskRepository(db), task.NewBroker(nil))
+
+	tsk := task.NewTask(r.ID.String(), "title", "desc", nil, nil, 0, false, false, "", true)
+	tsk.RequiredLabels = []string{"gpu"}
+	require.NoError(t, store.CreateTask(ctx, tsk))
+
+	claimed, err := store.ClaimPendingTask(ctx, nil, []string{"linux"})
+	require.NoError(t, err)
+	assert.Nil(t, claimed, "worker without the task's labels should not claim it")
+
+	// The repo's required labels apply on top of the task's.
+	require.NoError(t, repos.UpdateRepoRequiredLabels(ctx, r.ID, []string{"linux"}))
+	claimed, err = store.ClaimPendingTask(ctx, nil, []string{"gpu"})
+	require.NoError(t, err)
+	assert.Nil(t, claimed, "worker without the repo's labels should not claim it")
+
+	claimed, err = store.ClaimPendingTask(ctx, nil, []string{"gpu", "linux", "arm64"})
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	assert.Equal(t, tsk.ID, claimed.ID)
+	assert.Equal(t, []string{"gpu"}, claimed.RequiredLabels)
+}
+
+func TestStore_ClaimPendingTask_PausedRepo(t *testing.T) {
+	db := sqlite.NewTestDB(t)
+	ctx := context.Background()
+
+	repos := sqlite.NewRepoRepository(db)
+	r, err := repo.NewRepo("owner/test-repo")
+	require.NoError(t, err)
+	require.NoError(t, repos.CreateRepo(ctx, r))
+	store := task.NewStore(sqlite.NewTaskRepository(db), task.NewBroker(nil))
+
+	require.NoError(t, repos.UpdateRepoPaused(ctx, r.ID, true))
+	tsk := task.NewTask(r.ID.String(), "title", "desc", nil, nil, 0, false, false, "", true)
+	require.NoError(t, store.CreateTask(ctx, tsk), "tasks can be created in a paused repo")
+
+	claimed, err := store.ClaimPendingTask(ctx, nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, claimed, "tasks in a paused repo should not be claimed")
+
+	require.NoError(t, repos.UpdateRepoPaused(ctx, r.ID, false))
+	claimed, err = store.ClaimPendingTask(ctx, nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	assert.Equal(t, tsk.ID, claimed.ID)
+}
+
+func TestStore_ClaimPendingTask_Stacked(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	parent := f.newTask("parent", "desc", true)
+	require.NoError(t, f.store.CreateTask(ctx, parent))
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, parent.ID, task.StatusRunning))
+
+	child := f.newTaskWithDeps("child", "desc", []string{parent.ID.String()})
+	child.StackParentID = parent.ID.String()
+	require.NoError(t, f.store.CreateTask(ctx, child))
+
+	claimed, err := f.store.ClaimPendingTask(ctx, nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, claimed, "stacked task should wait for its parent's pull request")
+
+	require.NoError(t, f.taskRepo.SetBranchName(ctx, parent.ID, "verve/parent"))
+	require.NoError(t, f.taskRepo.SetTaskPullRequest(ctx, parent.ID, "https://github.com/org/repo/pull/1", 1))
+
+	claimed, err = f.store.ClaimPendingTask(ctx, nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, claimed, "stacked task should be claimable once its parent is in review")
+	assert.Equal(t, child.ID, claimed.ID)
+	assert.Equal(t, parent.ID.String(), claimed.StackParent())
+}
+
+func TestStore_CreateTask_InvalidStackParent(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	parent := task.NewTask(f.repoID, "parent", "desc", nil, nil, 0, true, false, "", true)
+	require.NoError(t, f.store.CreateTask(ctx, parent))
+
+	child := f.newTaskWithDeps("child", "desc", []string{parent.ID.String()})
+	child.StackParentID = parent.ID.String()
+	err := f.store.CreateTask(ctx, child)
+	assert.True(t, errtag.HasTag[task.ErrTagInvalidStackParent](err), "a task without a pull request cannot be stacked on")
+}
+
 func TestStore_AppendTaskLogs(t *testing.T) {
 	f := newTestTaskFixture(t)
 	ctx := context.Background()
@@ -500,6 +842,441 @@ func TestStore_AppendTaskLogs(t *testing.T) {
 	assert.Len(t, logs, 2)
 }
 
+func TestStore_AppendTaskLogs_Batched(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+	f.store.SetLogBatchInterval(20 * time.Millisecond)
+
+	const tasks = 10
+	ids := make([]task.TaskID, tasks)
+	for i := range ids {
+		tsk := f.newTask("title", "desc", true)
+		require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+		ids[i] = tsk.ID
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, tasks+1)
+	for i, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = f.store.AppendTaskLogs(ctx, id, 1, []string{"line 1", "line 2"})
+		}()
+	}
+	// A batch for a task that doesn't exist fails on its own.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		errs[tasks] = f.store.AppendTaskLogs(ctx, task.NewTaskID(), 1, []string{"orphan"})
+	}()
+	wg.Wait()
+
+	for i := range ids {
+		require.NoError(t, errs[i])
+	}
+	assert.Error(t, errs[tasks])
+	for _, id := range ids {
+		logs, err := f.store.ReadTaskLogs(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"line 1", "line 2"}, logs, "each append is stored once it returns")
+	}
+}
+
+func TestStore_AppendAgentEvents_PublishesProgress(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+
+	ch := f.store.Subscribe()
+	defer f.store.Unsubscribe(ch)
+
+	err := f.store.AppendAgentEvents(ctx, tsk.ID, 1, []*task.AgentEvent{
+		{Type: task.AgentEventPlan, Payload: json.RawMessage(`{"steps":["Read code","Fix bug"]}`)},
+		{Type: task.AgentEventStep, Payload: json.RawMessage(`{"index":0,"status":"started"}`)},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, task.EventAgentEventsAppended, (<-ch).Type)
+	event := <-ch
+	assert.Equal(t, task.EventTaskProgress, event.Type)
+	assert.Equal(t, f.repoID, event.RepoID)
+	assert.Equal(t, tsk.ID, event.TaskID)
+	require.NotNil(t, event.Progress)
+	require.Len(t, event.Progress.Steps, 2)
+	assert.Equal(t, task.StepInProgress, event.Progress.Steps[0].Status)
+
+	// Events that don't change the plan publish no progress event.
+	err = f.store.AppendAgentEvents(ctx, tsk.ID, 1, []*task.AgentEvent{
+		{Type: task.AgentEventToolCall, Payload: json.RawMessage(`{"name":"Read"}`)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, task.EventAgentEventsAppended, (<-ch).Type)
+	select {
+	case event := <-ch:
+		assert.Fail(t, "unexpected event", event.Type)
+	default:
+	}
+
+	progress, err := f.store.ReadProgress(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, progress.Attempt)
+	assert.Equal(t, "Fix bug", progress.Steps[1].Title)
+	assert.Equal(t, task.StepPending, progress.Steps[1].Status)
+}
+
+func TestStore_NudgeTask(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+
+	_, err := f.store.NudgeTask(ctx, tsk.ID, "too early")
+	assert.True(t, errtag.HasTag[task.ErrTagTaskNotRunning](err), "got %v", err)
+
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
+
+	ch := f.store.Subscribe()
+	defer f.store.Unsubscribe(ch)
+
+	first, err := f.store.NudgeTask(ctx, tsk.ID, "first")
+	require.NoError(t, err)
+	event := <-ch
+	assert.Equal(t, task.EventTaskNudges, event.Type)
+	require.Len(t, event.Nudges, 1)
+	assert.Nil(t, event.Nudges[0].DeliveredAt)
+
+	_, err = f.store.NudgeTask(ctx, tsk.ID, "second")
+	require.NoError(t, err)
+	<-ch
+
+	delivered, err := f.store.DeliverNudges(ctx, tsk.ID)
+	require.NoError(t, err)
+	require.Len(t, delivered, 2)
+	assert.Equal(t, first.ID, delivered[0].ID)
+	assert.Equal(t, "second", delivered[1].Message)
+	assert.NotNil(t, delivered[0].DeliveredAt)
+	event = <-ch
+	assert.Equal(t, task.EventTaskNudges, event.Type)
+	assert.Len(t, event.Nudges, 2)
+
+	// Delivered nudges are not handed out again.
+	delivered, err = f.store.DeliverNudges(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Empty(t, delivered)
+
+	nudges, err := f.store.ListNudges(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Len(t, nudges, 2)
+}
+
+func TestStore_Notes(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	tsk := f.newTask("Fix login", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+
+	err := f.store.AppendAgentEvents(ctx, tsk.ID, 1, []*task.AgentEvent{
+		{Type: task.AgentEventNote, Payload: json.RawMessage(`{"text":"  The session module needs refactoring\nIt mixes storage and HTTP.  "}`)},
+		{Type: task.AgentEventNote, Payload: json.RawMessage(`{"text":" "}`)},
+		{Type: task.AgentEventToolCall, Payload: json.RawMessage(`{"name":"Read"}`)},
+	})
+	require.NoError(t, err)
+
+	notes, err := f.store.ListRepoNotes(ctx, f.repoID)
+	require.NoError(t, err)
+	require.Len(t, notes, 1, "empty notes are dropped")
+	note := notes[0]
+	assert.Equal(t, tsk.ID.String(), note.TaskID)
+	assert.Equal(t, 1, note.Attempt)
+	assert.Equal(t, "The session module needs refactoring\nIt mixes storage and HTTP.", note.Text)
+	assert.Empty(t, note.ConvertedTaskID)
+
+	ch := f.store.Subscribe()
+	defer f.store.Unsubscribe(ch)
+
+	created, err := f.store.ConvertNote(ctx, note.ID, "sonnet")
+	require.NoError(t, err)
+	assert.Equal(t, "The session module needs refactoring", created.Title)
+	assert.Contains(t, created.Description, "It mixes storage and HTTP.")
+	assert.Contains(t, created.Description, "Fix login")
+	assert.Equal(t, task.StatusPending, created.Status)
+	assert.Equal(t, f.repoID, created.RepoID)
+	event := <-ch
+	assert.Equal(t, task.EventTaskCreated, event.Type)
+	assert.Equal(t, created.ID, event.Task.ID)
+
+	note, err = f.store.ReadNote(ctx, note.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID.String(), note.ConvertedTaskID)
+
+	_, err = f.store.ConvertNote(ctx, note.ID, "sonnet")
+	assert.True(t, errtag.HasTag[task.ErrTagNoteConverted](err), "got %v", err)
+
+	_, err = f.store.ConvertNote(ctx, note.ID+100, "sonnet")
+	assert.True(t, errtag.HasTag[task.ErrTagNoteNotFound](err), "got %v", err)
+}
+
+func TestStore_RecordCIDispatch(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+
+	dispatch, err := f.store.ReadCIDispatch(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Nil(t, dispatch)
+
+	first := time.Now().Add(-time.Hour).Truncate(time.Second)
+	require.NoError(t, f.store.RecordCIDispatch(ctx, tsk.ID, &task.CIDispatch{WorkflowFile: "full-tests.yml", Ref: "verve/task-1", DispatchedAt: first}))
+
+	// A later dispatch replaces the earlier one.
+	second := first.Add(30 * time.Minute)
+	require.NoError(t, f.store.RecordCIDispatch(ctx, tsk.ID, &task.CIDispatch{WorkflowFile: "full-tests.yml", Ref: "verve/task-1", DispatchedAt: second}))
+
+	dispatch, err = f.store.ReadCIDispatch(ctx, tsk.ID)
+	require.NoError(t, err)
+	require.NotNil(t, dispatch)
+	assert.Equal(t, "full-tests.yml", dispatch.WorkflowFile)
+	assert.Equal(t, "verve/task-1", dispatch.Ref)
+	assert.True(t, second.Equal(dispatch.DispatchedAt))
+
+	// Deleting the task removes its dispatch record.
+	require.NoError(t, f.store.DeleteTask(ctx, tsk.ID))
+	dispatch, err = f.store.ReadCIDispatch(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Nil(t, dispatch)
+}
+
+func TestStore_ProvenanceReview(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+
+	review, err := f.store.ReadProvenanceReview(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Nil(t, review)
+
+	_, err = f.store.AcknowledgeProvenanceReview(ctx, tsk.ID)
+	assert.True(t, errtag.HasTag[task.ErrTagProvenanceReviewNotFound](err), "got %v", err)
+
+	ch := f.store.Subscribe()
+	defer f.store.Unsubscribe(ch)
+
+	findings := []provenance.Finding{{Kind: provenance.KindLicenseHeader, Path: "util.go", Line: 1, Detail: "SPDX license identifier"}}
+	scannedAt := time.Now().Truncate(time.Second)
+	require.NoError(t, f.store.RecordProvenanceReview(ctx, tsk.ID, &task.ProvenanceReview{Findings: findings, HeadSHA: "abc", ScannedAt: scannedAt}))
+	event := <-ch
+	assert.Equal(t, task.EventTaskProvenance, event.Type)
+	require.NotNil(t, event.Provenance)
+	assert.True(t, event.Provenance.NeedsAcknowledgment())
+
+	review, err = f.store.AcknowledgeProvenanceReview(ctx, tsk.ID)
+	require.NoError(t, err)
+	require.NotNil(t, review.AcknowledgedAt)
+	assert.False(t, review.NeedsAcknowledgment())
+	assert.Equal(t, task.EventTaskProvenance, (<-ch).Type)
+
+	// Re-scanning with the same findings keeps the acknowledgment.
+	require.NoError(t, f.store.RecordProvenanceReview(ctx, tsk.ID, &task.ProvenanceReview{Findings: findings, HeadSHA: "def", ScannedAt: scannedAt}))
+	<-ch
+	review, err = f.store.ReadProvenanceReview(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "def", review.HeadSHA)
+	assert.Equal(t, findings, review.Findings)
+	assert.NotNil(t, review.AcknowledgedAt)
+
+	// New findings need to be acknowledged again.
+	findings = append(findings, provenance.Finding{Kind: provenance.KindLargeBlock, Path: "big.go", Line: 1, Lines: 400, Detail: "400 contiguous added lines"})
+	require.NoError(t, f.store.RecordProvenanceReview(ctx, tsk.ID, &task.ProvenanceReview{Findings: findings, HeadSHA: "ghi", ScannedAt: scannedAt}))
+	<-ch
+	review, err = f.store.ReadProvenanceReview(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.True(t, review.NeedsAcknowledgment())
+
+	// Deleting the task removes its review.
+	require.NoError(t, f.store.DeleteTask(ctx, tsk.ID))
+	review, err = f.store.ReadProvenanceReview(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Nil(t, review)
+}
+
+func TestStore_RecordSelfReview(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+
+	review, err := f.store.ReadSelfReview(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Nil(t, review)
+
+	checks := []selfreview.Check{
+		{Name: selfreview.CheckLeftovers, Passed: false, Detail: "1 leftover", Findings: []string{"main.go:3: TODO"}},
+	}
+	require.NoError(t, f.store.RecordSelfReview(ctx, tsk.ID, &task.SelfReview{
+		Score:      80,
+		Checks:     checks,
+		HeadSHA:    "abc123",
+		CommentID:  42,
+		ReviewedAt: time.Now(),
+	}))
+
+	review, err = f.store.ReadSelfReview(ctx, tsk.ID)
+	require.NoError(t, err)
+	require.NotNil(t, review)
+	assert.Equal(t, 80, review.Score)
+	assert.Equal(t, checks, review.Checks)
+	assert.Equal(t, "abc123", review.HeadSHA)
+	assert.Equal(t, int64(42), review.CommentID)
+
+	// Deleting the task removes its self-review.
+	require.NoError(t, f.store.DeleteTask(ctx, tsk.ID))
+	review, err = f.store.ReadSelfReview(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Nil(t, review)
+}
+
+func TestStore_RecordShadowDiff(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
+
+	diff, err := f.store.ReadShadowDiff(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Nil(t, diff)
+
+	patch := "diff --git a/main.go b/main.go\n+package main\n"
+	require.NoError(t, f.store.RecordShadowDiff(ctx, tsk.ID, patch))
+
+	read, err := f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusReview, read.Status)
+	assert.Zero(t, read.PRNumber)
+	assert.Empty(t, read.BranchName)
+
+	diff, err = f.store.ReadShadowDiff(ctx, tsk.ID)
+	require.NoError(t, err)
+	require.NotNil(t, diff)
+	assert.Equal(t, patch, diff.Diff)
+	assert.Equal(t, read.Attempt, diff.Attempt)
+
+	// Deleting the task removes its shadow diff.
+	require.NoError(t, f.store.DeleteTask(ctx, tsk.ID))
+	diff, err = f.store.ReadShadowDiff(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Nil(t, diff)
+}
+
+func TestStore_ApproveProposal(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	tsk := f.newTask("title", "desc", true)
+	tsk.PlanOnly = true
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
+
+	assert.True(t, errtag.HasTag[task.ErrTagProposalNotFound](f.store.ApproveProposal(ctx, tsk.ID)))
+
+	require.NoError(t, f.store.RecordProposal(ctx, tsk.ID, &task.Proposal{
+		Summary:  "Add caching",
+		Approach: "Wrap the client",
+		Files:    []task.ProposalFile{{Path: "client.go", Change: "add cache"}},
+		Risks:    []string{"stale reads"},
+	}))
+
+	read, err := f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusReview, read.Status)
+	assert.True(t, read.PlanOnly)
+
+	proposal, err := f.store.ReadProposal(ctx, tsk.ID)
+	require.NoError(t, err)
+	require.NotNil(t, proposal)
+	assert.Equal(t, "Add caching", proposal.Summary)
+	assert.Equal(t, []task.ProposalFile{{Path: "client.go", Change: "add cache"}}, proposal.Files)
+	assert.Equal(t, []string{"stale reads"}, proposal.Risks)
+	assert.Equal(t, read.Attempt, proposal.Attempt)
+	assert.Nil(t, proposal.ApprovedAt)
+
+	require.NoError(t, f.store.ApproveProposal(ctx, tsk.ID))
+
+	read, err = f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusPending, read.Status)
+	assert.False(t, read.PlanOnly, "approving ends plan-only mode")
+	assert.Equal(t, tsk.Attempt+1, read.Attempt)
+	assert.Contains(t, read.RetryReason, "proposal_approved")
+
+	proposal, err = f.store.ReadProposal(ctx, tsk.ID)
+	require.NoError(t, err)
+	require.NotNil(t, proposal)
+	assert.NotNil(t, proposal.ApprovedAt)
+
+	// The proposal can only be approved once.
+	assert.True(t, errtag.HasTag[task.ErrTagProposalNotPending](f.store.ApproveProposal(ctx, tsk.ID)))
+}
+
+func TestStore_SetTaskPullRequests(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	tsk := f.newTask("title", "desc", true)
+	tsk.AdditionalRepoIDs = []string{"repo_a", "repo_b"}
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
+
+	// Only an additional repo changed: the task still moves to review.
+	require.NoError(t, f.store.SetTaskPullRequests(ctx, tsk.ID, []task.PullRequest{
+		{RepoID: "repo_a", URL: "https://github.com/owner/a/pull/1", Number: 1},
+	}))
+	read, err := f.store.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusReview, read.Status)
+	assert.Equal(t, []string{"repo_a", "repo_b"}, read.AdditionalRepoIDs)
+	assert.True(t, read.HasPullRequest())
+
+	require.NoError(t, f.store.MarkPullRequestMerged(ctx, tsk.ID, "repo_a"))
+	require.NoError(t, f.store.PullRequestReviewFeedbackRetryTask(ctx, tsk.ID, "repo_a", 9, "fix it"))
+	read, err = f.store.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusPending, read.Status)
+	assert.Equal(t, int64(9), read.PullRequests[0].LastReviewID)
+
+	// A PR reported again keeps its state; repos not reported keep theirs.
+	require.NoError(t, f.store.SetTaskPullRequests(ctx, tsk.ID, []task.PullRequest{
+		{RepoID: "repo_b", URL: "https://github.com/owner/b/pull/2", Number: 2},
+		{RepoID: "repo_a", URL: "https://github.com/owner/a/pull/1", Number: 1},
+	}))
+	read, err = f.store.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusReview, read.Status)
+	assert.Equal(t, []task.PullRequest{
+		{RepoID: "repo_a", URL: "https://github.com/owner/a/pull/1", Number: 1, Merged: true, LastReviewID: 9},
+		{RepoID: "repo_b", URL: "https://github.com/owner/b/pull/2", Number: 2},
+	}, read.PullRequests)
+
+	// A new PR for a repo replaces the old one.
+	require.NoError(t, f.store.SetTaskPullRequests(ctx, tsk.ID, []task.PullRequest{
+		{RepoID: "repo_a", URL: "https://github.com/owner/a/pull/3", Number: 3},
+	}))
+	read, err = f.store.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.PullRequest{RepoID: "repo_a", URL: "https://github.com/owner/a/pull/3", Number: 3}, read.PullRequests[0])
+}
+
 func TestStore_WaitForPending(t *testing.T) {
 	f := newTestTaskFixture(t)
 
@@ -539,20 +1316,166 @@ func TestStore_StopTask_QueuesStop(t *testing.T) {
 	// Stop the task
 	require.NoError(t, f.store.StopTask(ctx, tsk.ID, "test stop"))
 
-	// DrainStops should return the stopped task ID
-	stops := f.store.DrainStops()
-	require.Len(t, stops, 1)
-	assert.Equal(t, tsk.ID, stops[0])
+	// DrainStops should return the stopped task ID
+	stops := f.store.DrainStops()
+	require.Len(t, stops, 1)
+	assert.Equal(t, tsk.ID, stops[0])
+
+	// Second drain should return nil
+	stops = f.store.DrainStops()
+	assert.Nil(t, stops)
+
+	// Verify task transitioned to pending/not-ready
+	read, err := f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusPending, read.Status)
+	assert.False(t, read.Ready)
+}
+
+func TestStore_AbandonTask(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+	claimed, err := f.store.ClaimPendingTask(ctx, nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+
+	ok, err := f.store.AbandonTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	read, err := f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusPending, read.Status)
+	assert.True(t, read.Ready)
+	assert.Equal(t, claimed.Attempt, read.Attempt, "abandoning should not use up an attempt")
+
+	attempts, err := f.store.ListAttempts(ctx, tsk.ID)
+	require.NoError(t, err)
+	require.Len(t, attempts, 1)
+	assert.Equal(t, task.AttemptResultAbandoned, attempts[0].Result)
+
+	// The task is immediately claimable again.
+	reclaimed, err := f.store.ClaimPendingTask(ctx, nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, reclaimed)
+	assert.Equal(t, tsk.ID, reclaimed.ID)
+
+	// Abandoning a task that isn't running is a no-op.
+	pending := f.newTask("pending", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, pending))
+	ok, err = f.store.AbandonTask(ctx, pending.ID)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestStore_TimeoutStaleTasks(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+	_, err := f.store.ClaimPendingTask(ctx, nil, nil)
+	require.NoError(t, err)
+	_, err = f.store.Heartbeat(ctx, tsk.ID)
+	require.NoError(t, err)
+
+	// A negative timeout makes the latest heartbeat stale.
+	failed, requeued, err := f.store.TimeoutStaleTasks(ctx, -time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, failed)
+	assert.Zero(t, requeued)
+
+	read, err := f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusFailed, read.Status)
+	assert.Equal(t, task.FailureWorkerTimeout, read.FailureCategory)
+}
+
+func TestStore_TimeoutStaleTasks_Requeue(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+	requeue := true
+	f.store.SetRetryPolicyResolver(staticRetryPolicy(task.RetryPolicy{MaxAttempts: 2, RequeueStale: &requeue}))
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.store.CreateTask(ctx, tsk))
+	claimAndHeartbeat := func() {
+		t.Helper()
+		claimed, err := f.store.ClaimPendingTask(ctx, nil, nil)
+		require.NoError(t, err)
+		require.NotNil(t, claimed)
+		_, err = f.store.Heartbeat(ctx, tsk.ID)
+		require.NoError(t, err)
+	}
+	claimAndHeartbeat()
+	// Linking a PR moves the task to review; the worker is lost while it
+	// still runs.
+	require.NoError(t, f.taskRepo.SetTaskPullRequest(ctx, tsk.ID, "https://github.com/org/repo/pull/1", 1))
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
+
+	failed, requeued, err := f.store.TimeoutStaleTasks(ctx, -time.Minute)
+	require.NoError(t, err)
+	assert.Zero(t, failed)
+	assert.Equal(t, 1, requeued)
+
+	read, err := f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusPending, read.Status)
+	assert.Equal(t, 2, read.Attempt)
+	assert.Contains(t, read.RetryReason, task.RetryCategoryWorkerLost+":")
+	assert.Equal(t, 1, read.PRNumber, "the PR should be kept for the next attempt")
+
+	// The retry counts towards max attempts.
+	claimAndHeartbeat()
+	failed, requeued, err = f.store.TimeoutStaleTasks(ctx, -time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, failed)
+	assert.Zero(t, requeued)
+
+	read, err = f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusFailed, read.Status)
+	assert.Equal(t, task.FailureMaxAttempts, read.FailureCategory)
+}
+
+func TestStore_ReleaseOrphanedTask(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+	claimed, err := f.store.ClaimPendingTask(ctx, nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	_, err = f.store.Heartbeat(ctx, tsk.ID)
+	require.NoError(t, err)
+
+	// A task that heartbeated since the cutoff is still alive.
+	ok, err := f.store.ReleaseOrphanedTask(ctx, tsk.ID, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	assert.False(t, ok)
 
-	// Second drain should return nil
-	stops = f.store.DrainStops()
-	assert.Nil(t, stops)
+	ok, err = f.store.ReleaseOrphanedTask(ctx, tsk.ID, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, ok)
 
-	// Verify task transitioned to pending/not-ready
 	read, err := f.taskRepo.ReadTask(ctx, tsk.ID)
 	require.NoError(t, err)
 	assert.Equal(t, task.StatusPending, read.Status)
-	assert.False(t, read.Ready)
+	assert.Equal(t, claimed.Attempt, read.Attempt, "releasing should not use up an attempt")
+
+	attempts, err := f.store.ListAttempts(ctx, tsk.ID)
+	require.NoError(t, err)
+	require.Len(t, attempts, 1)
+	assert.Equal(t, task.AttemptResultAbandoned, attempts[0].Result)
+
+	// Releasing a task that isn't running is a no-op.
+	ok, err = f.store.ReleaseOrphanedTask(ctx, tsk.ID, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, ok)
 }
 
 func TestStore_WaitForStop_Signals(t *testing.T) {
@@ -650,6 +1573,80 @@ func TestStore_SetTaskPullRequest(t *testing.T) {
 	assert.Equal(t, 42, read.PRNumber)
 }
 
+type recordingStatusListener struct {
+	changes []task.Status
+}
+
+func (l *recordingStatusListener) TaskStatusChanged(_ context.Context, t *task.Task) {
+	l.changes = append(l.changes, t.Status)
+}
+
+func TestStore_StatusListener(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+	listener := &recordingStatusListener{}
+	f.store.SetStatusListener(listener)
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
+
+	require.NoError(t, f.store.SetTaskPullRequest(ctx, tsk.ID, "https://github.com/org/repo/pull/42", 42))
+	assert.Equal(t, []task.Status{task.StatusReview}, listener.changes)
+
+	// Re-setting the PR on a task already in review is not a transition.
+	require.NoError(t, f.store.SetTaskPullRequest(ctx, tsk.ID, "https://github.com/org/repo/pull/42", 42))
+	require.NoError(t, f.store.UpdateTaskStatus(ctx, tsk.ID, task.StatusReview))
+	assert.Equal(t, []task.Status{task.StatusReview}, listener.changes)
+
+	require.NoError(t, f.store.UpdateTaskStatus(ctx, tsk.ID, task.StatusMerged))
+	assert.Equal(t, []task.Status{task.StatusReview, task.StatusMerged}, listener.changes)
+}
+
+func TestStore_StatusListener_Claim(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+	listener := &recordingStatusListener{}
+	f.store.SetStatusListener(listener)
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+
+	claimed, err := f.store.ClaimPendingTask(ctx, nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	assert.Equal(t, []task.Status{task.StatusRunning}, listener.changes)
+}
+
+func TestStore_StatusListener_Close(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+	first, second := &recordingStatusListener{}, &recordingStatusListener{}
+	f.store.SetStatusListener(task.StatusListeners{first, second})
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+
+	require.NoError(t, f.store.CloseTask(ctx, tsk.ID, "no longer needed"))
+	assert.Equal(t, []task.Status{task.StatusClosed}, first.changes)
+	assert.Equal(t, []task.Status{task.StatusClosed}, second.changes)
+}
+
+func TestStore_StatusListener_BudgetFailure(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+	listener := &recordingStatusListener{}
+	f.store.SetStatusListener(listener)
+
+	tsk := f.newTaskWithBudget("title", "desc", 1.0)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusReview))
+	require.NoError(t, f.store.AddCost(ctx, tsk.ID, 1.5))
+
+	require.NoError(t, f.store.RetryTask(ctx, tsk.ID, "ci_failure:tests", "tests failed"))
+	assert.Equal(t, []task.Status{task.StatusFailed}, listener.changes)
+}
+
 func TestStore_RemoveDependency_Success(t *testing.T) {
 	f := newTestTaskFixture(t)
 	ctx := context.Background()
@@ -721,6 +1718,89 @@ func TestStore_ScheduleRetry_Success(t *testing.T) {
 	assert.Equal(t, task.StatusPending, read.Status, "expected task to transition to pending for retry")
 }
 
+func TestStore_ScheduleRetry_RateLimitBackoff(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
+
+	before := time.Now()
+	require.NoError(t, f.store.ScheduleRetry(ctx, tsk.ID, "rate_limit: Claude max usage exceeded"))
+
+	read, err := f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusPending, read.Status)
+	require.NotNil(t, read.NotBefore, "expected rate-limited retry to be delayed")
+	assert.False(t, read.NotBefore.Before(before.Add(task.DefaultRateLimitBackoff).Truncate(time.Second)))
+	assert.False(t, read.NotBefore.After(time.Now().Add(task.DefaultRateLimitBackoff*6/5)))
+
+	claimed, err := f.store.ClaimPendingTask(ctx, nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, claimed, "expected task to be unclaimable during backoff")
+}
+
+func TestStore_ScheduleRetry_FallbackModel(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	tsk := f.newTask("title", "desc", true)
+	tsk.Model = "opus"
+	tsk.FallbackModels = []string{"sonnet"}
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+	claimed, err := f.store.ClaimPendingTask(ctx, nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+
+	// The first rate limit switches to the fallback model without backing off.
+	require.NoError(t, f.store.ScheduleRetry(ctx, tsk.ID, "rate_limit: overloaded_error"))
+	read, err := f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusPending, read.Status)
+	assert.Equal(t, "sonnet", read.ActiveModel)
+	assert.Equal(t, "sonnet", read.RunModel())
+	assert.Nil(t, read.NotBefore, "expected no backoff when falling back")
+
+	claimed, err = f.store.ClaimPendingTask(ctx, nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+
+	// With no fallback left the task backs off on the last model.
+	require.NoError(t, f.store.ScheduleRetry(ctx, tsk.ID, "rate_limit: overloaded_error"))
+	read, err = f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "sonnet", read.ActiveModel)
+	assert.NotNil(t, read.NotBefore)
+
+	attempts, err := f.store.ListAttempts(ctx, tsk.ID)
+	require.NoError(t, err)
+	require.Len(t, attempts, 2)
+	assert.Equal(t, "opus", attempts[0].Model)
+	assert.Equal(t, "sonnet", attempts[1].Model)
+}
+
+func TestStore_ScheduleRetry_NoBackoffForOtherCategories(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
+
+	require.NoError(t, f.store.ScheduleRetry(ctx, tsk.ID, "agent_error: connection reset"))
+
+	read, err := f.taskRepo.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusPending, read.Status)
+	assert.Nil(t, read.NotBefore)
+
+	claimed, err := f.store.ClaimPendingTask(ctx, nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	assert.Equal(t, tsk.ID, claimed.ID)
+}
+
 func TestStore_ScheduleRetry_MaxAttempts(t *testing.T) {
 	f := newTestTaskFixture(t)
 	ctx := context.Background()
@@ -729,7 +1809,7 @@ func TestStore_ScheduleRetry_MaxAttempts(t *testing.T) {
 	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
 	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
 	for i := 0; i < 4; i++ {
-		ok, err := f.taskRepo.ScheduleRetryFromRunning(ctx, tsk.ID, "rate_limit: max usage")
+		ok, err := f.taskRepo.ScheduleRetryFromRunning(ctx, tsk.ID, "rate_limit: max usage", nil)
 		require.NoError(t, err)
 		require.True(t, ok)
 		require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
@@ -741,6 +1821,7 @@ func TestStore_ScheduleRetry_MaxAttempts(t *testing.T) {
 	read, err := f.taskRepo.ReadTask(ctx, tsk.ID)
 	require.NoError(t, err)
 	assert.Equal(t, task.StatusFailed, read.Status, "expected task to fail when max attempts reached")
+	assert.Equal(t, task.FailureMaxAttempts, read.FailureCategory)
 }
 
 func TestStore_ScheduleRetry_BudgetExceeded(t *testing.T) {
@@ -758,6 +1839,7 @@ func TestStore_ScheduleRetry_BudgetExceeded(t *testing.T) {
 	read, err := f.taskRepo.ReadTask(ctx, tsk.ID)
 	require.NoError(t, err)
 	assert.Equal(t, task.StatusFailed, read.Status, "expected task to fail when budget exceeded")
+	assert.Equal(t, task.FailureBudgetExceeded, read.FailureCategory)
 }
 
 func TestStore_ScheduleRetry_CircuitBreaker(t *testing.T) {
@@ -768,7 +1850,7 @@ func TestStore_ScheduleRetry_CircuitBreaker(t *testing.T) {
 	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
 	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
 	require.NoError(t, f.taskRepo.SetConsecutiveFailures(ctx, tsk.ID, 2))
-	ok, err := f.taskRepo.ScheduleRetryFromRunning(ctx, tsk.ID, "rate_limit: Claude max usage exceeded")
+	ok, err := f.taskRepo.ScheduleRetryFromRunning(ctx, tsk.ID, "rate_limit: Claude max usage exceeded", nil)
 	require.NoError(t, err)
 	require.True(t, ok)
 	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
@@ -780,6 +1862,7 @@ func TestStore_ScheduleRetry_CircuitBreaker(t *testing.T) {
 	read, err := f.taskRepo.ReadTask(ctx, tsk.ID)
 	require.NoError(t, err)
 	assert.Equal(t, task.StatusFailed, read.Status, "expected task to fail due to circuit breaker")
+	assert.Equal(t, task.RetryCategoryRateLimit, read.FailureCategory)
 }
 
 func TestStore_DeleteTask_WithLogs(t *testing.T) {
@@ -817,6 +1900,129 @@ func TestStore_DeleteTask_RemovesDependencies(t *testing.T) {
 	assert.Empty(t, read.DependsOn, "expected dependency to be removed")
 }
 
+func TestStore_DeleteTask_Trash(t *testing.T) {
+	f := newTestTaskFixture(t)
+	f.store.SetTrashRetention(time.Hour)
+	ctx := context.Background()
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+	require.NoError(t, f.taskRepo.AppendTaskLogs(ctx, tsk.ID, 1, []string{"log line"}))
+	dependent := f.newTaskWithDeps("dependent", "desc", []string{tsk.ID.String()})
+	require.NoError(t, f.taskRepo.CreateTask(ctx, dependent))
+
+	require.NoError(t, f.store.DeleteTask(ctx, tsk.ID))
+
+	_, err := f.taskRepo.ReadTask(ctx, tsk.ID)
+	assert.Error(t, err, "expected trashed task to be hidden")
+	read, err := f.taskRepo.ReadTask(ctx, dependent.ID)
+	require.NoError(t, err)
+	assert.Empty(t, read.DependsOn, "expected dependency to be removed")
+	trashed, err := f.store.ListTrashedTasksByRepo(ctx, tsk.RepoID)
+	require.NoError(t, err)
+	require.Len(t, trashed, 1)
+
+	// Nothing has been in the trash for an hour yet.
+	purged, err := f.store.PurgeTrash(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+
+	restored, err := f.store.RestoreTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, tsk.ID, restored.ID)
+	logs, err := f.taskRepo.ReadTaskLogs(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"log line"}, logs)
+
+	_, err = f.store.RestoreTask(ctx, tsk.ID)
+	assert.True(t, errtag.HasTag[task.ErrTagTaskNotTrashed](err), "got %v", err)
+}
+
+func TestStore_Comments_PublishEvents(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+
+	ch := f.store.Subscribe()
+	defer f.store.Unsubscribe(ch)
+
+	comment, err := f.store.CreateComment(ctx, tsk.ID, &task.Comment{Author: "alice", Body: "first"})
+	require.NoError(t, err)
+	event := <-ch
+	assert.Equal(t, task.EventCommentCreated, event.Type)
+	assert.Equal(t, tsk.ID, event.TaskID)
+	require.NotNil(t, event.Comment)
+	assert.Equal(t, comment.ID, event.Comment.ID)
+
+	_, err = f.store.UpdateComment(ctx, tsk.ID, comment.ID, "edited")
+	require.NoError(t, err)
+	event = <-ch
+	assert.Equal(t, task.EventCommentUpdated, event.Type)
+	assert.Equal(t, "edited", event.Comment.Body)
+
+	require.NoError(t, f.store.DeleteComment(ctx, tsk.ID, comment.ID))
+	event = <-ch
+	assert.Equal(t, task.EventCommentDeleted, event.Type)
+	assert.Equal(t, comment.ID, event.Comment.ID)
+
+	_, err = f.store.UpdateComment(ctx, tsk.ID, comment.ID, "gone")
+	assert.True(t, errtag.HasTag[task.ErrTagCommentNotFound](err), "got %v", err)
+
+	// Deleting the task deletes its comments.
+	_, err = f.store.CreateComment(ctx, tsk.ID, &task.Comment{Author: "alice", Body: "second"})
+	require.NoError(t, err)
+	require.NoError(t, f.store.DeleteTask(ctx, tsk.ID))
+	comments, err := f.taskRepo.ListTaskComments(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Empty(t, comments)
+}
+
+func TestStore_ArchiveTasks(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	closed := f.newTask("closed", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, closed))
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, closed.ID, task.StatusClosed))
+	require.NoError(t, f.taskRepo.AppendTaskLogs(ctx, closed.ID, 1, []string{"one"}))
+	require.NoError(t, f.taskRepo.AppendTaskLogs(ctx, closed.ID, 2, []string{"two"}))
+	running := f.newTask("running", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, running))
+
+	n, err := f.store.ArchiveTasks(ctx, time.Hour)
+	require.NoError(t, err)
+	assert.Zero(t, n, "expected recently closed task to stay")
+
+	// A negative age also archives tasks closed within the current second.
+	n, err = f.store.ArchiveTasks(ctx, -time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	live, err := f.store.ListTasksByRepo(ctx, f.repoID)
+	require.NoError(t, err)
+	require.Len(t, live, 1)
+	assert.Equal(t, running.ID, live[0].ID)
+	archived, err := f.store.ListArchivedTasksByRepo(ctx, f.repoID)
+	require.NoError(t, err)
+	require.Len(t, archived, 1)
+	assert.Equal(t, closed.ID, archived[0].ID)
+
+	// Forcing the task out of closed unarchives it along with its logs.
+	_, err = f.store.ForceStatus(ctx, closed.ID, task.StatusPending, "")
+	require.NoError(t, err)
+	read, err := f.store.ReadTask(ctx, closed.ID)
+	require.NoError(t, err)
+	assert.Nil(t, read.ArchivedAt)
+	logs, err := f.taskRepo.ReadTaskLogs(ctx, closed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one", "two"}, logs)
+
+	_, err = f.store.UnarchiveTask(ctx, closed.ID)
+	assert.True(t, errtag.HasTag[task.ErrTagTaskConflict](err), "got %v", err)
+}
+
 func TestStore_SetAgentStatus_MergesFilesAcrossRetries(t *testing.T) {
 	f := newTestTaskFixture(t)
 	ctx := context.Background()
@@ -1083,3 +2289,190 @@ func TestMergeAgentStatusFiles(t *testing.T) {
 		})
 	}
 }
+
+func TestStore_Attempts_RecordLifecycle(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+
+	_, err := f.store.ClaimPendingTask(ctx, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, f.store.AddCost(ctx, tsk.ID, 1.5))
+	require.NoError(t, f.store.ScheduleRetry(ctx, tsk.ID, "ci_failure: tests failed"))
+
+	_, err = f.store.ClaimPendingTask(ctx, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, f.store.AddCost(ctx, tsk.ID, 0.5))
+	require.NoError(t, f.store.SetTaskPullRequest(ctx, tsk.ID, "https://github.com/owner/test-repo/pull/1", 1))
+
+	attempts, err := f.store.ListAttempts(ctx, tsk.ID)
+	require.NoError(t, err)
+	require.Len(t, attempts, 2)
+
+	assert.Equal(t, 1, attempts[0].Number)
+	assert.Equal(t, task.AttemptResultRetried, attempts[0].Result)
+	assert.InDelta(t, 1.5, attempts[0].CostUSD, 0.001)
+	assert.NotNil(t, attempts[0].EndedAt)
+
+	assert.Equal(t, 2, attempts[1].Number)
+	assert.Equal(t, "ci_failure: tests failed", attempts[1].RetryReason)
+	assert.Equal(t, string(task.StatusReview), attempts[1].Result)
+	assert.InDelta(t, 0.5, attempts[1].CostUSD, 0.001)
+	assert.NotNil(t, attempts[1].EndedAt)
+}
+
+func TestStore_Attempts_StopReopensOnReclaim(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+
+	_, err := f.store.ClaimPendingTask(ctx, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, f.store.StopTask(ctx, tsk.ID, "stopped by user"))
+
+	attempts, err := f.store.ListAttempts(ctx, tsk.ID)
+	require.NoError(t, err)
+	require.Len(t, attempts, 1)
+	assert.Equal(t, task.AttemptResultStopped, attempts[0].Result)
+
+	// A stopped task keeps its attempt number, so reclaiming it reopens the
+	// same attempt.
+	require.NoError(t, f.store.SetReady(ctx, tsk.ID, true))
+	_, err = f.store.ClaimPendingTask(ctx, nil, nil)
+	require.NoError(t, err)
+
+	attempts, err = f.store.ListAttempts(ctx, tsk.ID)
+	require.NoError(t, err)
+	require.Len(t, attempts, 1)
+	assert.Empty(t, attempts[0].Result)
+	assert.Nil(t, attempts[0].EndedAt)
+}
+
+func TestStore_StreamAttemptLogs(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.taskRepo.CreateTask(ctx, tsk))
+	require.NoError(t, f.store.AppendTaskLogs(ctx, tsk.ID, 1, []string{"a1"}))
+	require.NoError(t, f.store.AppendTaskLogs(ctx, tsk.ID, 2, []string{"b1", "b2"}))
+	require.NoError(t, f.store.AppendTaskLogs(ctx, tsk.ID, 2, []string{"b3"}))
+
+	var lines []string
+	err := f.store.StreamAttemptLogs(ctx, tsk.ID, 2, func(batch []string) error {
+		lines = append(lines, batch...)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b1", "b2", "b3"}, lines)
+}
+
+func TestStore_CompleteTask(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.store.CreateTask(ctx, tsk))
+
+	// Only a running task can be completed.
+	p := task.CompletionParams{Attempt: 1, IdempotencyKey: "complete", Success: true, CostUSD: 1.5}
+	_, err := f.store.CompleteTask(ctx, tsk.ID, p)
+	require.True(t, errtag.HasTag[task.ErrTagTaskNotRunning](err), "got %v", err)
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
+
+	// Nor can an attempt that has ended.
+	stale := p
+	stale.Attempt = 2
+	_, err = f.store.CompleteTask(ctx, tsk.ID, stale)
+	require.True(t, errtag.HasTag[task.ErrTagAttemptEnded](err), "got %v", err)
+
+	ch := f.store.Subscribe()
+	defer f.store.Unsubscribe(ch)
+
+	applied, err := f.store.CompleteTask(ctx, tsk.ID, p)
+	require.NoError(t, err)
+	assert.True(t, applied)
+	applied, err = f.store.CompleteTask(ctx, tsk.ID, p)
+	require.NoError(t, err)
+	assert.False(t, applied)
+
+	stored, err := f.store.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusClosed, stored.Status)
+	assert.InDelta(t, 1.5, stored.CostUSD, 0.001)
+
+	// The cost and status updates are published as one event.
+	select {
+	case event := <-ch:
+		assert.Equal(t, task.EventTaskUpdated, event.Type)
+		assert.Equal(t, task.StatusClosed, event.Task.Status)
+	default:
+		assert.Fail(t, "expected event to be published once committed")
+	}
+	select {
+	case event := <-ch:
+		assert.Failf(t, "unexpected event", "%s", event.Type)
+	default:
+	}
+}
+
+// failingStatusRepo fails every status update, including those made in a
+// transaction, to fail a store operation after its first writes.
+type failingStatusRepo struct {
+	task.Repository
+	err error
+}
+
+func (r failingStatusRepo) BeginTxFunc(ctx context.Context, fn func(ctx context.Context, txn tx.Tx, repo task.Repository) error) error {
+	return r.Repository.BeginTxFunc(ctx, func(ctx context.Context, txn tx.Tx, repo task.Repository) error {
+		return fn(ctx, txn, failingStatusRepo{Repository: repo, err: r.err})
+	})
+}
+
+func (r failingStatusRepo) UpdateTaskStatus(context.Context, task.TaskID, task.Status) error {
+	return r.err
+}
+
+func TestStore_CompleteTask_RollsBackOnFailure(t *testing.T) {
+	f := newTestTaskFixture(t)
+	ctx := context.Background()
+	tsk := f.newTask("title", "desc", true)
+	require.NoError(t, f.store.CreateTask(ctx, tsk))
+	require.NoError(t, f.taskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusRunning))
+
+	ch := f.store.Subscribe()
+	defer f.store.Unsubscribe(ch)
+
+	// The agent status and cost are written before the status update fails.
+	errBoom := errors.New("boom")
+	failing := task.NewStore(failingStatusRepo{Repository: f.taskRepo, err: errBoom}, task.NewBroker(nil))
+	p := task.CompletionParams{Attempt: 1, IdempotencyKey: "complete", Success: true, AgentStatus: "done", CostUSD: 1.5}
+	_, err := failing.CompleteTask(ctx, tsk.ID, p)
+	require.ErrorIs(t, err, errBoom)
+
+	stored, err := f.store.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusRunning, stored.Status)
+	assert.Empty(t, stored.AgentStatus)
+	assert.Zero(t, stored.CostUSD)
+	select {
+	case event := <-ch:
+		assert.Failf(t, "unexpected event", "%s", event.Type)
+	default:
+	}
+
+	// The key was rolled back with the writes, so the resent outcome is
+	// applied.
+	applied, err := f.store.CompleteTask(ctx, tsk.ID, p)
+	require.NoError(t, err)
+	assert.True(t, applied)
+
+	stored, err = f.store.ReadTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusClosed, stored.Status)
+	assert.Equal(t, "done", stored.AgentStatus)
+	assert.InDelta(t, 1.5, stored.CostUSD, 0.001)
+}
@@ -1,6 +1,9 @@
 package task
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 // Status represents the lifecycle state of a Task.
 type Status string
@@ -26,6 +29,7 @@ type Task struct {
 	ID                  TaskID    `json:"id"`
 	Number              int       `json:"number"`
 	RepoID              string    `json:"repo_id"`
+	AdditionalRepoIDs   []string  `json:"additional_repo_ids,omitempty"`
 	Type                string    `json:"type"`
 	Title               string    `json:"title"`
 	Description         string    `json:"description"`
@@ -33,8 +37,12 @@ type Task struct {
 	Logs                []string  `json:"logs"`
 	PullRequestURL      string    `json:"pull_request_url,omitempty"`
 	PRNumber            int       `json:"pr_number,omitempty"`
+	PullRequests        []PullRequest `json:"pull_requests,omitempty"`
 	DependsOn           []string  `json:"depends_on,omitempty"`
 	CloseReason         string    `json:"close_reason,omitempty"`
+	// FailureCategory classifies why a failed task failed (see the
+	// Failure* constants).
+	FailureCategory     string    `json:"failure_category,omitempty"`
 	Attempt             int       `json:"attempt"`
 	MaxAttempts         int       `json:"max_attempts"`
 	RetryReason         string    `json:"retry_reason,omitempty"`
@@ -44,18 +52,74 @@ type Task struct {
 	ConsecutiveFailures int       `json:"consecutive_failures"`
 	CostUSD             float64   `json:"cost_usd"`
 	MaxCostUSD          float64   `json:"max_cost_usd,omitempty"`
+	// MaxRuntimeSeconds caps how long an attempt may run before it is
+	// killed and the task fails. 0 uses the repo's default max runtime.
+	MaxRuntimeSeconds   int       `json:"max_runtime_seconds,omitempty"`
+	// RequiredLabels limits the task to workers that have all of these
+	// labels, on top of any labels its repo requires.
+	RequiredLabels      []string  `json:"required_labels,omitempty"`
 	SkipPR              bool      `json:"skip_pr"`
 	DraftPR             bool      `json:"draft_pr"`
+	// PlanOnly makes the agent propose a change plan for approval instead of
+	// implementing the task. It is cleared when the proposal is approved.
+	PlanOnly            bool      `json:"plan_only"`
 	Ready               bool      `json:"ready"`
 	EpicID              string     `json:"epic_id,omitempty"`
+	// StackParentID is the dependency the task is stacked on: its branch
+	// starts from the parent's branch and its PR targets it until the
+	// parent's PR is merged.
+	StackParentID       string     `json:"stack_parent_id,omitempty"`
 	Model               string     `json:"model,omitempty"`
+	// FallbackModels are the models the task falls back to, in order, when
+	// its agent is rate-limited or overloaded.
+	FallbackModels      []string   `json:"fallback_models,omitempty"`
+	// ActiveModel is the fallback model the task's next attempt runs with
+	// instead of Model. Empty while the task runs with Model.
+	ActiveModel         string     `json:"active_model,omitempty"`
 	BranchName          string     `json:"branch_name,omitempty"`
+	LastReviewID        int64      `json:"-"`
 	StartedAt           *time.Time `json:"started_at,omitempty"`
+	// NotBefore is when a pending task retried after a rate limit may be
+	// claimed again. Nil when the task can run right away.
+	NotBefore           *time.Time `json:"not_before,omitempty"`
+	// DeletedAt is when the task was moved to the trash. Nil for tasks that
+	// are not trashed.
+	DeletedAt           *time.Time `json:"deleted_at,omitempty"`
+	// ArchivedAt is when the merged or closed task was archived. Archived
+	// tasks are left out of task lists unless asked for, and their logs are
+	// only available again once they are unarchived.
+	ArchivedAt          *time.Time `json:"archived_at,omitempty"`
+	// CommentCount is the number of comments on the task. It is only set in
+	// task listings and when a single task is read.
+	CommentCount        int        `json:"comment_count,omitempty"`
 	DurationMs          *int64     `json:"duration_ms,omitempty"`
+	// Version is bumped on every change to the task. Edits can require it to
+	// match the version they were made from so they don't overwrite changes
+	// made since.
+	Version             int64      `json:"version"`
 	CreatedAt           time.Time  `json:"created_at"`
 	UpdatedAt           time.Time  `json:"updated_at"`
 }
 
+// PullRequest is a pull request the agent opened in one of a multi-repo
+// task's additional repos. The primary repo's pull request is tracked by
+// Task.PullRequestURL and Task.PRNumber.
+type PullRequest struct {
+	RepoID string `json:"repo_id"`
+	URL    string `json:"url"`
+	Number int    `json:"number"`
+	Merged bool   `json:"merged"`
+	// LastReviewID is the ID of the most recent review of this PR that has
+	// been turned into feedback.
+	LastReviewID int64 `json:"-"`
+}
+
+// HasPullRequest reports whether the agent opened a pull request for the task
+// in any of its repos.
+func (t *Task) HasPullRequest() bool {
+	return t.PRNumber > 0 || len(t.PullRequests) > 0
+}
+
 // ComputeDuration calculates the run duration from StartedAt to UpdatedAt
 // for tasks that have finished running (review, merged, closed, failed),
 // or from StartedAt to now for tasks that are currently running.
@@ -76,6 +140,21 @@ func (t *Task) ComputeDuration() {
 	t.DurationMs = &ms
 }
 
+// StackParent returns the ID of the dependency the task is stacked on, or ""
+// when it isn't stacked or that dependency has since been removed.
+func (t *Task) StackParent() string {
+	if t.StackParentID == "" || !slices.Contains(t.DependsOn, t.StackParentID) {
+		return ""
+	}
+	return t.StackParentID
+}
+
+// Stackable reports whether tasks stacked on t can start: t has an open pull
+// request whose branch they can branch off.
+func (t *Task) Stackable() bool {
+	return t.Status == StatusReview && t.PRNumber > 0 && t.BranchName != ""
+}
+
 // UpdatePendingTaskParams holds the fields that can be updated on a pending task.
 // All fields are required — the caller should merge with current values before calling.
 type UpdatePendingTaskParams struct {
@@ -84,10 +163,15 @@ type UpdatePendingTaskParams struct {
 	DependsOn          []string
 	AcceptanceCriteria []string
 	MaxCostUSD         float64
+	MaxRuntimeSeconds  int
+	RequiredLabels     []string
 	SkipPR             bool
 	DraftPR            bool
 	Model              string
 	Ready              bool
+	// ExpectedVersion is the version the update was made from. The update is
+	// rejected if the task has changed since. 0 updates any version.
+	ExpectedVersion int64
 }
 
 // StartOverTaskParams holds the fields that can be updated when starting a task over.
@@ -95,6 +179,24 @@ type StartOverTaskParams struct {
 	Title              string
 	Description        string
 	AcceptanceCriteria []string
+	// ExpectedVersion is the version the task was started over from, as in
+	// UpdatePendingTaskParams.
+	ExpectedVersion int64
+	// MaxAttempts is set by the Store from the repo's retry policy.
+	MaxAttempts int
+}
+
+// MoveTaskParams holds the fields of a pending task that change when it
+// moves to another repo.
+type MoveTaskParams struct {
+	RepoID            string
+	DependsOn         []string
+	AdditionalRepoIDs []string
+	// ExpectedVersion is the version the move was made from, as in
+	// UpdatePendingTaskParams.
+	ExpectedVersion int64
+	// MaxAttempts is set by the Store from the target repo's retry policy.
+	MaxAttempts int
 }
 
 // NewSetupTask creates a new internal setup scan task for a repo.
@@ -160,7 +262,7 @@ func NewTask(repoID, title, description string, dependsOn, acceptanceCriteria []
 		Status:             StatusPending,
 		DependsOn:          dependsOn,
 		Attempt:            1,
-		MaxAttempts:        5,
+		MaxAttempts:        DefaultMaxAttempts,
 		AcceptanceCriteria: acceptanceCriteria,
 		MaxCostUSD:         maxCostUSD,
 		SkipPR:             skipPR,
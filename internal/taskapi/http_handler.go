@@ -1,10 +1,18 @@
 package taskapi
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"path"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joshjon/kit/server"
 	"github.com/labstack/echo/v4"
@@ -13,9 +21,13 @@ import (
 	"github.com/vervesh/verve/internal/github"
 	"github.com/vervesh/verve/internal/githubtoken"
 	"github.com/vervesh/verve/internal/logkey"
+	"github.com/vervesh/verve/internal/msgcat"
+	"github.com/vervesh/verve/internal/provenance"
 	"github.com/vervesh/verve/internal/repo"
 	"github.com/vervesh/verve/internal/setting"
 	"github.com/vervesh/verve/internal/task"
+	"github.com/vervesh/verve/internal/user"
+	"github.com/vervesh/verve/internal/userapi"
 )
 
 // HTTPHandler handles task HTTP requests.
@@ -25,44 +37,165 @@ type HTTPHandler struct {
 	epicStore          *epic.Store
 	githubTokenService *githubtoken.Service
 	settingService     *setting.Service
+	version            int       // API version served: apiV1 or apiV2
+	v1Sunset           time.Time // When v1 is removed; zero when not scheduled
+	mergeMethod        string    // Default merge method for POST /tasks/:id/merge
 }
 
-// NewHTTPHandler creates a new HTTPHandler.
-func NewHTTPHandler(store *task.Store, repoStore *repo.Store, epicStore *epic.Store, githubTokenService *githubtoken.Service, settingService *setting.Service) *HTTPHandler {
-	return &HTTPHandler{store: store, repoStore: repoStore, epicStore: epicStore, githubTokenService: githubTokenService, settingService: settingService}
+// NewHTTPHandler creates a new HTTPHandler serving API v1. Use V2 for the
+// handler serving API v2.
+func NewHTTPHandler(store *task.Store, repoStore *repo.Store, epicStore *epic.Store, githubTokenService *githubtoken.Service, settingService *setting.Service, opts ...Option) *HTTPHandler {
+	h := &HTTPHandler{store: store, repoStore: repoStore, epicStore: epicStore, githubTokenService: githubTokenService, settingService: settingService, version: apiV1, mergeMethod: github.MergeMethodSquash}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// WithMergeMethod sets the merge method used by POST /tasks/:id/merge when
+// the request does not name one. The default is squash.
+func WithMergeMethod(method string) Option {
+	return func(h *HTTPHandler) {
+		if method != "" {
+			h.mergeMethod = method
+		}
+	}
 }
 
 // Register adds the endpoints to the provided Echo router group.
 func (h *HTTPHandler) Register(g *echo.Group) {
+	if h.version == apiV1 {
+		g.Use(h.deprecateV1)
+	}
+
 	// Repo-scoped task operations
 	g.GET("/repos/:repo_id/tasks", h.ListTasksByRepo)
 	g.GET("/repos/:repo_id/tasks/:number", h.GetTaskByNumber)
+	g.GET("/repos/:repo_id/tasks/failed", h.ListFailedTasks)
+	g.GET("/repos/:repo_id/tasks/trash", h.ListTrashedTasks)
 	g.POST("/repos/:repo_id/tasks", h.CreateTask)
+	g.POST("/repos/:repo_id/tasks/bulk-action", h.BulkTaskAction)
 	g.POST("/repos/:repo_id/tasks/sync", h.SyncRepoTasks)
+	g.GET("/repos/:repo_id/notes", h.ListNotes)
 
 	// Task operations (globally unique IDs)
 	g.GET("/tasks/:id", h.GetTask)
 	g.GET("/tasks/:id/logs", h.StreamLogs)
+	g.GET("/tasks/:id/attempts", h.ListAttempts)
+	g.GET("/tasks/:id/attempts/:attempt/logs", h.StreamAttemptLogs)
+	g.GET("/tasks/:id/events", h.ListEvents)
+	g.GET("/tasks/:id/progress", h.GetProgress)
 	g.POST("/tasks/:id/close", h.CloseTask)
+	g.POST("/tasks/:id/approve", h.ApproveTask)
+	g.POST("/tasks/:id/merge", h.MergeTask)
 	g.POST("/tasks/:id/stop", h.StopTask)
 	g.POST("/tasks/:id/retry", h.RetryTask)
 	g.POST("/tasks/:id/start-over", h.StartOverTask)
+	g.POST("/tasks/:id/move", h.MoveTask)
 	g.POST("/tasks/:id/feedback", h.FeedbackTask)
+	g.POST("/tasks/:id/nudge", h.NudgeTask)
+	g.GET("/tasks/:id/nudges", h.ListNudges)
+	g.GET("/tasks/:id/comments", h.ListComments)
+	g.POST("/tasks/:id/comments", h.CreateComment)
+	g.PATCH("/tasks/:id/comments/:comment_id", h.UpdateComment)
+	g.DELETE("/tasks/:id/comments/:comment_id", h.DeleteComment)
+	g.GET("/tasks/:id/attachments", h.ListAttachments)
+	g.POST("/tasks/:id/attachments", h.UploadAttachment)
+	g.GET("/tasks/:id/attachments/:attachment_id", h.DownloadAttachment)
+	g.DELETE("/tasks/:id/attachments/:attachment_id", h.DeleteAttachment)
+	g.GET("/tasks/:id/artifacts", h.ListArtifacts)
+	g.GET("/tasks/:id/artifacts/:artifact_id", h.DownloadArtifact)
 	g.POST("/tasks/:id/move-to-review", h.MoveToReview)
 	g.POST("/tasks/:id/sync", h.SyncTaskStatus)
 	g.GET("/tasks/:id/checks", h.GetTaskChecks)
+	g.POST("/tasks/:id/checks/rerun", h.RerunTaskChecks)
 	g.GET("/tasks/:id/diff", h.GetTaskDiff)
+	g.GET("/tasks/:id/shadow-diff", h.GetShadowDiff)
+	g.GET("/tasks/:id/proposal", h.GetProposal)
+	g.POST("/tasks/:id/proposal/approve", h.ApproveProposal)
+	g.GET("/tasks/:id/provenance", h.GetProvenanceReview)
+	g.POST("/tasks/:id/provenance/acknowledge", h.AcknowledgeProvenanceReview)
+	g.GET("/tasks/:id/self-review", h.GetSelfReview)
 	g.DELETE("/tasks/:id/dependency", h.RemoveDependency)
 	g.PUT("/tasks/:id/ready", h.SetReady)
 	g.PATCH("/tasks/:id", h.UpdateTask)
 	g.DELETE("/tasks/:id", h.DeleteTask)
+	g.POST("/tasks/:id/restore", h.RestoreTask)
+	g.POST("/tasks/:id/unarchive", h.UnarchiveTask)
 	g.POST("/tasks/bulk-delete", h.BulkDeleteTasks)
+	g.POST("/tasks/bulk-retry", h.BulkRetryTasks)
+
+	// Notes left by agents
+	g.POST("/notes/:id/convert", h.ConvertNote)
 }
 
 // --- Task Handlers ---
 
 // ListTasksByRepo handles GET /repos/:repo_id/tasks
 func (h *HTTPHandler) ListTasksByRepo(c echo.Context) error {
+	req, err := server.BindRequest[ListTasksRequest](c)
+	if err != nil {
+		return err
+	}
+	id := repo.MustParseRepoID(req.RepoID)
+	c.Set(logkey.RepoID, id.String())
+
+	ctx := c.Request().Context()
+	tasks, err := h.store.ListTasksByRepo(ctx, id.String())
+	if err != nil {
+		return err
+	}
+	if req.IncludeArchived {
+		archived, err := h.store.ListArchivedTasksByRepo(ctx, id.String())
+		if err != nil {
+			return err
+		}
+		tasks = append(tasks, archived...)
+		slices.SortStableFunc(tasks, func(a, b *task.Task) int {
+			return b.CreatedAt.Compare(a.CreatedAt)
+		})
+	}
+	if err := h.store.SetCommentCounts(ctx, id.String(), tasks); err != nil {
+		return err
+	}
+	return h.setTaskListResponse(c, tasks)
+}
+
+// ListFailedTasks handles GET /repos/:repo_id/tasks/failed
+func (h *HTTPHandler) ListFailedTasks(c echo.Context) error {
+	req, err := server.BindRequest[ListFailedTasksRequest](c)
+	if err != nil {
+		return err
+	}
+	id := repo.MustParseRepoID(req.RepoID)
+	c.Set(logkey.RepoID, id.String())
+
+	failed, err := h.store.ListFailedTasksByRepo(c.Request().Context(), id.String())
+	if err != nil {
+		return err
+	}
+
+	resp := FailedTasksResponse{
+		Tasks:      make([]*task.Task, 0, len(failed)),
+		Categories: task.CountFailureCategories(failed),
+	}
+	for _, t := range failed {
+		category := t.FailureCategory
+		if category == "" {
+			category = task.FailureUnknown
+		}
+		if req.Category == "" || req.Category == category {
+			resp.Tasks = append(resp.Tasks, t)
+		}
+	}
+	if h.version == apiV2 {
+		return server.SetResponse(c, http.StatusOK, FailedTasksResponseV2{Tasks: toTasksV2(resp.Tasks), Categories: resp.Categories})
+	}
+	return server.SetResponse(c, http.StatusOK, resp)
+}
+
+// ListTrashedTasks handles GET /repos/:repo_id/tasks/trash
+func (h *HTTPHandler) ListTrashedTasks(c echo.Context) error {
 	req, err := server.BindRequest[RepoIDRequest](c)
 	if err != nil {
 		return err
@@ -70,11 +203,15 @@ func (h *HTTPHandler) ListTasksByRepo(c echo.Context) error {
 	id := repo.MustParseRepoID(req.RepoID)
 	c.Set(logkey.RepoID, id.String())
 
-	tasks, err := h.store.ListTasksByRepo(c.Request().Context(), id.String())
+	ctx := c.Request().Context()
+	tasks, err := h.store.ListTrashedTasksByRepo(ctx, id.String())
 	if err != nil {
 		return err
 	}
-	return server.SetResponseList(c, http.StatusOK, tasks, "")
+	if err := h.store.SetCommentCounts(ctx, id.String(), tasks); err != nil {
+		return err
+	}
+	return h.setTaskListResponse(c, tasks)
 }
 
 // CreateTask handles POST /repos/:repo_id/tasks
@@ -92,22 +229,52 @@ func (h *HTTPHandler) CreateTask(c echo.Context) error {
 		return err
 	}
 	if r.SetupStatus != repo.SetupStatusReady {
-		return echo.NewHTTPError(http.StatusConflict, "repository setup is not complete — finish setup before adding tasks")
+		return echo.NewHTTPError(http.StatusConflict, msgcat.Text(msgcat.ErrRepoSetupIncompleteTasks))
+	}
+	for _, id := range req.AdditionalRepoIDs {
+		if _, err := h.repoStore.ReadRepo(c.Request().Context(), repo.MustParseRepoID(id)); err != nil {
+			return err
+		}
 	}
 
+	// Settings left unset are inherited from the repo's team.
+	defaults, err := h.repoStore.TeamDefaults(c.Request().Context(), r)
+	if err != nil {
+		return err
+	}
 	model := req.Model
+	if model == "" {
+		model = defaults.Model
+	}
 	if model == "" && h.settingService != nil {
 		model = h.settingService.Get(setting.KeyDefaultModel)
 	}
 	if model == "" {
 		model = "sonnet"
 	}
-	t := task.NewTask(repoID.String(), req.Title, req.Description, req.DependsOn, req.AcceptanceCriteria, req.MaxCostUSD, req.SkipPR, req.DraftPR, model, !req.NotReady)
+	maxCostUSD := req.MaxCostUSD
+	if maxCostUSD == 0 {
+		maxCostUSD = defaults.MaxCostUSD
+	}
+	if maxCostUSD == 0 && h.settingService != nil {
+		maxCostUSD = h.settingService.Float(setting.KeyDefaultMaxCostUSD)
+	}
+	fallbackModels := req.FallbackModels
+	if fallbackModels == nil {
+		fallbackModels = r.FallbackModels
+	}
+	t := task.NewTask(repoID.String(), req.Title, req.Description, req.DependsOn, req.AcceptanceCriteria, maxCostUSD, req.SkipPR, req.DraftPR, model, !req.NotReady)
+	t.AdditionalRepoIDs = req.AdditionalRepoIDs
+	t.MaxRuntimeSeconds = req.MaxRuntimeSeconds
+	t.RequiredLabels = req.RequiredLabels
+	t.StackParentID = req.StackOn
+	t.PlanOnly = req.PlanOnly
+	t.FallbackModels = fallbackModels
 	if err := h.store.CreateTask(c.Request().Context(), t); err != nil {
 		return err
 	}
 	c.Set(logkey.TaskID, t.ID.String())
-	return server.SetResponse(c, http.StatusCreated, t)
+	return h.setTaskResponse(c, http.StatusCreated, t)
 }
 
 // GetTask handles GET /tasks/:id
@@ -119,11 +286,15 @@ func (h *HTTPHandler) GetTask(c echo.Context) error {
 	id := task.MustParseTaskID(req.ID)
 	c.Set(logkey.TaskID, id.String())
 
-	t, err := h.store.ReadTask(c.Request().Context(), id)
+	ctx := c.Request().Context()
+	t, err := h.store.ReadTask(ctx, id)
 	if err != nil {
 		return err
 	}
-	return server.SetResponse(c, http.StatusOK, t)
+	if t.CommentCount, err = h.store.CountComments(ctx, id); err != nil {
+		return err
+	}
+	return h.setTaskResponse(c, http.StatusOK, t)
 }
 
 // GetTaskByNumber handles GET /repos/:repo_id/tasks/:number
@@ -137,12 +308,16 @@ func (h *HTTPHandler) GetTaskByNumber(c echo.Context) error {
 
 	number, _ := strconv.Atoi(req.Number) // safe after validation
 
-	t, err := h.store.ReadTaskByNumber(c.Request().Context(), repoID.String(), number)
+	ctx := c.Request().Context()
+	t, err := h.store.ReadTaskByNumber(ctx, repoID.String(), number)
 	if err != nil {
 		return err
 	}
 	c.Set(logkey.TaskID, t.ID.String())
-	return server.SetResponse(c, http.StatusOK, t)
+	if t.CommentCount, err = h.store.CountComments(ctx, t.ID); err != nil {
+		return err
+	}
+	return h.setTaskResponse(c, http.StatusOK, t)
 }
 
 // UpdateTask handles PATCH /tasks/:id
@@ -156,10 +331,19 @@ func (h *HTTPHandler) UpdateTask(c echo.Context) error {
 
 	ctx := c.Request().Context()
 
+	version, err := expectedVersion(c, req.ExpectedVersion)
+	if err != nil {
+		return err
+	}
 	existing, err := h.store.ReadTask(ctx, id)
 	if err != nil {
 		return err
 	}
+	if version == 0 {
+		// Fields left out of the request are merged from this read, so
+		// don't let the update overwrite changes made since.
+		version = existing.Version
+	}
 
 	params := task.UpdatePendingTaskParams{
 		Title:              existing.Title,
@@ -167,10 +351,13 @@ func (h *HTTPHandler) UpdateTask(c echo.Context) error {
 		DependsOn:          existing.DependsOn,
 		AcceptanceCriteria: existing.AcceptanceCriteria,
 		MaxCostUSD:         existing.MaxCostUSD,
+		MaxRuntimeSeconds:  existing.MaxRuntimeSeconds,
+		RequiredLabels:     existing.RequiredLabels,
 		SkipPR:             existing.SkipPR,
 		DraftPR:            existing.DraftPR,
 		Model:              existing.Model,
 		Ready:              existing.Ready,
+		ExpectedVersion:    version,
 	}
 
 	if req.Title != nil {
@@ -188,6 +375,12 @@ func (h *HTTPHandler) UpdateTask(c echo.Context) error {
 	if req.MaxCostUSD != nil {
 		params.MaxCostUSD = *req.MaxCostUSD
 	}
+	if req.MaxRuntimeSeconds != nil {
+		params.MaxRuntimeSeconds = *req.MaxRuntimeSeconds
+	}
+	if req.RequiredLabels != nil {
+		params.RequiredLabels = *req.RequiredLabels
+	}
 	if req.SkipPR != nil {
 		params.SkipPR = *req.SkipPR
 	}
@@ -202,7 +395,7 @@ func (h *HTTPHandler) UpdateTask(c echo.Context) error {
 	}
 
 	if params.SkipPR && params.DraftPR {
-		return echo.NewHTTPError(http.StatusBadRequest, "skip_pr and draft_pr are mutually exclusive")
+		return echo.NewHTTPError(http.StatusBadRequest, msgcat.Text(msgcat.ErrTaskSkipPRWithDraftPR))
 	}
 
 	if params.DependsOn == nil {
@@ -220,7 +413,25 @@ func (h *HTTPHandler) UpdateTask(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	return server.SetResponse(c, http.StatusOK, t)
+	return h.setTaskResponse(c, http.StatusOK, t)
+}
+
+// expectedVersion returns the task version an edit was made from, given as
+// expected_version in the request body or in the If-Match header, or 0 when
+// neither is given.
+func expectedVersion(c echo.Context, body *int64) (int64, error) {
+	ifMatch := strings.TrimSpace(c.Request().Header.Get("If-Match"))
+	if ifMatch == "" || ifMatch == "*" {
+		if body != nil {
+			return *body, nil
+		}
+		return 0, nil
+	}
+	version, err := strconv.ParseInt(strings.Trim(ifMatch, `"`), 10, 64)
+	if err != nil || version < 1 || (body != nil && *body != version) {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, msgcat.Text(msgcat.ErrTaskInvalidIfMatch))
+	}
+	return version, nil
 }
 
 // SyncTaskStatus handles POST /tasks/:id/sync
@@ -239,8 +450,10 @@ func (h *HTTPHandler) SyncTaskStatus(c echo.Context) error {
 		return err
 	}
 
+	// Multi-repo tasks are marked merged by the background sync once all of
+	// their PRs have merged.
 	gh := h.githubClient()
-	if t.Status == task.StatusReview && gh != nil && t.PRNumber > 0 {
+	if t.Status == task.StatusReview && gh != nil && t.PRNumber > 0 && len(t.PullRequests) == 0 {
 		repoID, parseErr := repo.ParseRepoID(t.RepoID)
 		if parseErr != nil {
 			return parseErr
@@ -265,7 +478,7 @@ func (h *HTTPHandler) SyncTaskStatus(c echo.Context) error {
 		}
 	}
 
-	return server.SetResponse(c, http.StatusOK, t)
+	return h.setTaskResponse(c, http.StatusOK, t)
 }
 
 // SyncRepoTasks handles POST /repos/:repo_id/tasks/sync
@@ -297,7 +510,9 @@ func (h *HTTPHandler) SyncRepoTasks(c echo.Context) error {
 	synced := 0
 	merged := 0
 	for _, t := range tasks {
-		if t.PRNumber > 0 {
+		// Multi-repo tasks are left to the background sync, which tracks
+		// each of their PRs.
+		if t.PRNumber > 0 && len(t.PullRequests) == 0 {
 			synced++
 			isMerged, err := gh.IsPRMerged(ctx, r.Owner, r.Name, t.PRNumber)
 			if err != nil {
@@ -333,27 +548,157 @@ func (h *HTTPHandler) CloseTask(c echo.Context) error {
 		return err
 	}
 
-	// Close the corresponding GitHub PR and delete its branch if unmerged.
-	if t.PRNumber > 0 && t.Status != task.StatusMerged {
-		if gh := h.githubClient(); gh != nil {
-			repoID, parseErr := repo.ParseRepoID(t.RepoID)
-			if parseErr == nil {
-				r, readErr := h.repoStore.ReadRepo(ctx, repoID)
-				if readErr == nil {
-					branch, closeErr := gh.ClosePR(ctx, r.Owner, r.Name, t.PRNumber)
-					if closeErr == nil && branch != "" {
-						_ = gh.DeleteBranch(ctx, r.Owner, r.Name, branch)
-					}
-				}
-			}
+	// Close the task's unmerged GitHub PRs and delete their branches.
+	h.closePullRequests(ctx, t)
+
+	t, err = h.store.ReadTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	return h.setTaskResponse(c, http.StatusOK, t)
+}
+
+// ApproveTask handles POST /tasks/:id/approve
+// It submits an approving review on each unmerged pull request of a task in
+// review.
+func (h *HTTPHandler) ApproveTask(c echo.Context) error {
+	req, err := server.BindRequest[ApproveTaskRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	ctx := c.Request().Context()
+
+	t, gh, err := h.reviewedTask(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = h.forEachPullRequest(ctx, t, func(r *repo.Repo, number int, _ string) error {
+		return gh.ApprovePR(ctx, r.Owner, r.Name, number, req.Body)
+	})
+	if err != nil {
+		return githubRejection(err)
+	}
+	return h.setTaskResponse(c, http.StatusOK, t)
+}
+
+// MergeTask handles POST /tasks/:id/merge
+// It merges each unmerged pull request of a task in review and marks the
+// task merged. Unacknowledged provenance findings block the merge.
+func (h *HTTPHandler) MergeTask(c echo.Context) error {
+	req, err := server.BindRequest[MergeTaskRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	ctx := c.Request().Context()
+
+	t, gh, err := h.reviewedTask(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	review, err := h.store.ReadProvenanceReview(ctx, id)
+	if err != nil {
+		return err
+	}
+	if review != nil && review.NeedsAcknowledgment() {
+		return echo.NewHTTPError(http.StatusConflict, msgcat.Text(msgcat.ErrProvenanceNotAcknowledged))
+	}
+
+	method := req.MergeMethod
+	if method == "" {
+		method = h.mergeMethod
+	}
+	err = h.forEachPullRequest(ctx, t, func(r *repo.Repo, number int, additionalRepoID string) error {
+		if _, err := gh.MergePR(ctx, r.Owner, r.Name, number, method); err != nil {
+			return err
 		}
+		// Record each additional PR as it merges so a failure part way
+		// through leaves the task showing which PRs remain.
+		if additionalRepoID != "" {
+			return h.store.MarkPullRequestMerged(ctx, id, additionalRepoID)
+		}
+		return nil
+	})
+	if err != nil {
+		return githubRejection(err)
+	}
+	if err := h.store.UpdateTaskStatus(ctx, id, task.StatusMerged); err != nil {
+		return err
 	}
 
 	t, err = h.store.ReadTask(ctx, id)
 	if err != nil {
 		return err
 	}
-	return server.SetResponse(c, http.StatusOK, t)
+	return h.setTaskResponse(c, http.StatusOK, t)
+}
+
+// reviewedTask reads a task whose pull requests are awaiting review, along
+// with the GitHub client used to act on them.
+func (h *HTTPHandler) reviewedTask(ctx context.Context, id task.TaskID) (*task.Task, *github.Client, error) {
+	t, err := h.store.ReadTask(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if t.Status != task.StatusReview {
+		return nil, nil, echo.NewHTTPError(http.StatusConflict, msgcat.Text(msgcat.ErrTaskNotInReview))
+	}
+	if !t.HasPullRequest() {
+		return nil, nil, task.ErrTaskNoPR
+	}
+	gh := h.githubClient()
+	if gh == nil {
+		return nil, nil, echo.NewHTTPError(http.StatusServiceUnavailable, msgcat.Text(msgcat.ErrGitHubTokenNotConfigured))
+	}
+	return t, gh, nil
+}
+
+// forEachPullRequest calls fn for the task's primary PR and then each of its
+// unmerged additional PRs, stopping at the first error. additionalRepoID is
+// empty for the primary PR.
+func (h *HTTPHandler) forEachPullRequest(ctx context.Context, t *task.Task, fn func(r *repo.Repo, number int, additionalRepoID string) error) error {
+	call := func(repoIDStr string, number int, additionalRepoID string) error {
+		repoID, err := repo.ParseRepoID(repoIDStr)
+		if err != nil {
+			return err
+		}
+		r, err := h.repoStore.ReadRepo(ctx, repoID)
+		if err != nil {
+			return err
+		}
+		return fn(r, number, additionalRepoID)
+	}
+	if t.PRNumber > 0 {
+		if err := call(t.RepoID, t.PRNumber, ""); err != nil {
+			return err
+		}
+	}
+	for _, pr := range t.PullRequests {
+		if pr.Merged {
+			continue
+		}
+		if err := call(pr.RepoID, pr.Number, pr.RepoID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// githubRejection turns GitHub refusing a review or merge, such as a PR
+// with failing required checks, into a conflict carrying GitHub's reason.
+func githubRejection(err error) error {
+	var rejected *github.RejectedError
+	if errors.As(err, &rejected) {
+		return echo.NewHTTPError(http.StatusConflict, rejected.Message)
+	}
+	return err
 }
 
 // StopTask handles POST /tasks/:id/stop
@@ -375,7 +720,7 @@ func (h *HTTPHandler) StopTask(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	return server.SetResponse(c, http.StatusOK, t)
+	return h.setTaskResponse(c, http.StatusOK, t)
 }
 
 // RetryTask handles POST /tasks/:id/retry
@@ -397,7 +742,7 @@ func (h *HTTPHandler) RetryTask(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	return server.SetResponse(c, http.StatusOK, t)
+	return h.setTaskResponse(c, http.StatusOK, t)
 }
 
 // MoveToReview handles POST /tasks/:id/move-to-review
@@ -419,7 +764,7 @@ func (h *HTTPHandler) MoveToReview(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	return server.SetResponse(c, http.StatusOK, t)
+	return h.setTaskResponse(c, http.StatusOK, t)
 }
 
 // StartOverTask handles POST /tasks/:id/start-over
@@ -438,10 +783,15 @@ func (h *HTTPHandler) StartOverTask(c echo.Context) error {
 		return err
 	}
 
+	version, err := expectedVersion(c, req.ExpectedVersion)
+	if err != nil {
+		return err
+	}
 	params := task.StartOverTaskParams{
 		Title:              existing.Title,
 		Description:        existing.Description,
 		AcceptanceCriteria: existing.AcceptanceCriteria,
+		ExpectedVersion:    version,
 	}
 	if req.Title != nil {
 		params.Title = *req.Title
@@ -461,20 +811,57 @@ func (h *HTTPHandler) StartOverTask(c echo.Context) error {
 		return err
 	}
 	if prev == nil {
-		return echo.NewHTTPError(http.StatusConflict, "task is not in review or failed status")
+		return echo.NewHTTPError(http.StatusConflict, msgcat.Text(msgcat.ErrTaskNotReviewOrFailed))
+	}
+
+	// Close the task's unmerged GitHub PRs and delete their branches.
+	h.closePullRequests(ctx, prev)
+
+	t, err := h.store.ReadTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	return h.setTaskResponse(c, http.StatusOK, t)
+}
+
+// MoveTask handles POST /tasks/:id/move
+func (h *HTTPHandler) MoveTask(c echo.Context) error {
+	req, err := server.BindRequest[MoveTaskRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	ctx := c.Request().Context()
+
+	// Tasks can only move to repos that could take new tasks.
+	r, err := h.repoStore.ReadRepo(ctx, repo.MustParseRepoID(req.RepoID))
+	if err != nil {
+		return err
+	}
+	if r.SetupStatus != repo.SetupStatusReady {
+		return echo.NewHTTPError(http.StatusConflict, msgcat.Text(msgcat.ErrRepoSetupIncompleteTasks))
+	}
+
+	version, err := expectedVersion(c, req.ExpectedVersion)
+	if err != nil {
+		return err
+	}
+	params := task.MoveTaskParams{RepoID: r.ID.String(), ExpectedVersion: version}
+	prev, err := h.store.MoveTask(ctx, id, params, req.DropDependencies)
+	if err != nil {
+		return err
 	}
 
-	// Close the corresponding GitHub PR and delete its branch if it had one.
-	if prev.PRNumber > 0 && prev.Status != task.StatusMerged {
-		if gh := h.githubClient(); gh != nil {
-			repoID, parseErr := repo.ParseRepoID(prev.RepoID)
+	if prev.RepoID != r.ID.String() {
+		// Close the PRs of earlier attempts, which stay in the old repo.
+		h.closePullRequests(ctx, prev)
+		if prev.EpicID != "" && h.epicStore != nil {
+			epicID, parseErr := epic.ParseEpicID(prev.EpicID)
 			if parseErr == nil {
-				r, readErr := h.repoStore.ReadRepo(ctx, repoID)
-				if readErr == nil {
-					branch, closeErr := gh.ClosePR(ctx, r.Owner, r.Name, prev.PRNumber)
-					if closeErr == nil && branch != "" {
-						_ = gh.DeleteBranch(ctx, r.Owner, r.Name, branch)
-					}
+				if err := h.epicStore.RemoveTaskAndCheck(ctx, epicID, id.String()); err != nil {
+					c.Logger().Errorf("failed to update epic after task move: %v", err)
 				}
 			}
 		}
@@ -484,7 +871,7 @@ func (h *HTTPHandler) StartOverTask(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	return server.SetResponse(c, http.StatusOK, t)
+	return h.setTaskResponse(c, http.StatusOK, t)
 }
 
 // FeedbackTask handles POST /tasks/:id/feedback
@@ -506,7 +893,7 @@ func (h *HTTPHandler) FeedbackTask(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	return server.SetResponse(c, http.StatusOK, t)
+	return h.setTaskResponse(c, http.StatusOK, t)
 }
 
 // RemoveDependency handles DELETE /tasks/:id/dependency
@@ -528,7 +915,7 @@ func (h *HTTPHandler) RemoveDependency(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	return server.SetResponse(c, http.StatusOK, t)
+	return h.setTaskResponse(c, http.StatusOK, t)
 }
 
 // SetReady handles PUT /tasks/:id/ready
@@ -550,7 +937,7 @@ func (h *HTTPHandler) SetReady(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	return server.SetResponse(c, http.StatusOK, t)
+	return h.setTaskResponse(c, http.StatusOK, t)
 }
 
 // DeleteTask handles DELETE /tasks/:id
@@ -569,21 +956,8 @@ func (h *HTTPHandler) DeleteTask(c echo.Context) error {
 		return err
 	}
 
-	// Close the corresponding GitHub PR and delete its branch if unmerged.
-	if t.PRNumber > 0 && t.Status != task.StatusMerged {
-		if gh := h.githubClient(); gh != nil {
-			repoID, parseErr := repo.ParseRepoID(t.RepoID)
-			if parseErr == nil {
-				r, readErr := h.repoStore.ReadRepo(ctx, repoID)
-				if readErr == nil {
-					branch, closeErr := gh.ClosePR(ctx, r.Owner, r.Name, t.PRNumber)
-					if closeErr == nil && branch != "" {
-						_ = gh.DeleteBranch(ctx, r.Owner, r.Name, branch)
-					}
-				}
-			}
-		}
-	}
+	// Close the task's unmerged GitHub PRs and delete their branches.
+	h.closePullRequests(ctx, t)
 
 	if err := h.store.DeleteTask(ctx, id); err != nil {
 		return err
@@ -601,6 +975,38 @@ func (h *HTTPHandler) DeleteTask(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// RestoreTask handles POST /tasks/:id/restore
+func (h *HTTPHandler) RestoreTask(c echo.Context) error {
+	req, err := server.BindRequest[TaskIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	t, err := h.store.RestoreTask(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return h.setTaskResponse(c, http.StatusOK, t)
+}
+
+// UnarchiveTask handles POST /tasks/:id/unarchive
+func (h *HTTPHandler) UnarchiveTask(c echo.Context) error {
+	req, err := server.BindRequest[TaskIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	t, err := h.store.UnarchiveTask(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return h.setTaskResponse(c, http.StatusOK, t)
+}
+
 // BulkDeleteTasks handles POST /tasks/bulk-delete
 func (h *HTTPHandler) BulkDeleteTasks(c echo.Context) error {
 	req, err := server.BindRequest[BulkDeleteTasksRequest](c)
@@ -675,6 +1081,86 @@ func (h *HTTPHandler) BulkDeleteTasks(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// BulkRetryTasks handles POST /tasks/bulk-retry
+func (h *HTTPHandler) BulkRetryTasks(c echo.Context) error {
+	req, err := server.BindRequest[BulkRetryTasksRequest](c)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]task.TaskID, len(req.TaskIDs))
+	for i, idStr := range req.TaskIDs {
+		ids[i] = task.MustParseTaskID(idStr)
+	}
+
+	retried, err := h.store.BulkRetryTasks(c.Request().Context(), ids, req.Instructions)
+	if err != nil {
+		return err
+	}
+
+	resp := BulkRetryTasksResponse{Retried: []string{}, Skipped: []string{}}
+	done := make(map[task.TaskID]bool, len(retried))
+	for _, id := range retried {
+		done[id] = true
+		resp.Retried = append(resp.Retried, id.String())
+	}
+	for _, id := range ids {
+		if !done[id] {
+			resp.Skipped = append(resp.Skipped, id.String())
+		}
+	}
+	return server.SetResponse(c, http.StatusOK, resp)
+}
+
+// BulkTaskAction handles POST /repos/:repo_id/tasks/bulk-action
+func (h *HTTPHandler) BulkTaskAction(c echo.Context) error {
+	req, err := server.BindRequest[BulkTaskActionRequest](c)
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+
+	p := task.BulkActionParams{
+		Action:       task.BulkAction(req.Action),
+		Status:       task.Status(req.Status),
+		Reason:       req.Reason,
+		Ready:        req.Ready == nil || *req.Ready,
+		Instructions: req.Instructions,
+	}
+	for _, idStr := range req.TaskIDs {
+		p.TaskIDs = append(p.TaskIDs, task.MustParseTaskID(idStr))
+	}
+
+	results, err := h.store.BulkTaskAction(ctx, req.RepoID, p)
+	if err != nil {
+		return err
+	}
+
+	resp := BulkTaskActionResponse{Results: make([]BulkTaskActionResult, len(results))}
+	for i, r := range results {
+		resp.Results[i] = BulkTaskActionResult{TaskID: r.TaskID.String(), Result: "applied"}
+		if !r.Applied {
+			resp.Results[i].Result, resp.Results[i].Reason = "skipped", r.Skipped
+			continue
+		}
+		if p.Action != task.BulkActionClose && p.Action != task.BulkActionDelete {
+			continue
+		}
+		// Close the task's unmerged GitHub PRs and delete their branches.
+		h.closePullRequests(ctx, r.Task)
+		if p.Action == task.BulkActionDelete && r.Task.EpicID != "" && h.epicStore != nil {
+			epicID, parseErr := epic.ParseEpicID(r.Task.EpicID)
+			if parseErr == nil {
+				if err := h.epicStore.RemoveTaskAndCheck(ctx, epicID, r.TaskID.String()); err != nil {
+					c.Logger().Errorf("failed to update epic after bulk task deletion: %v", err)
+				}
+			}
+		}
+	}
+	return server.SetResponse(c, http.StatusOK, resp)
+}
+
 // GetTaskChecks handles GET /tasks/:id/checks
 func (h *HTTPHandler) GetTaskChecks(c echo.Context) error {
 	req, err := server.BindRequest[TaskIDRequest](c)
@@ -697,7 +1183,7 @@ func (h *HTTPHandler) GetTaskChecks(c echo.Context) error {
 
 	gh := h.githubClient()
 	if gh == nil {
-		return server.SetResponse(c, http.StatusOK, CheckStatusResponse{Status: "error", Summary: "GitHub token not configured"})
+		return server.SetResponse(c, http.StatusOK, CheckStatusResponse{Status: "error", Summary: msgcat.Text(msgcat.ErrGitHubTokenNotConfigured)})
 	}
 
 	if h.githubTokenService.IsFineGrained() {
@@ -713,13 +1199,26 @@ func (h *HTTPHandler) GetTaskChecks(c echo.Context) error {
 		return readErr
 	}
 
-	result, err := gh.GetPRCheckStatus(ctx, r.Owner, r.Name, t.PRNumber)
+	result, err := gh.GetPRCheckStatus(ctx, r.Owner, r.Name, t.PRNumber, r.CheckPolicy())
 	if err != nil {
 		return server.SetResponse(c, http.StatusOK, CheckStatusResponse{Status: "error", Summary: "Failed to fetch check status"})
 	}
 
-	return server.SetResponse(c, http.StatusOK, CheckStatusResponse{
-		Status:           string(result.Status),
+	// Wait for the run of the repo's dispatched CI workflow, if any.
+	dispatch, err := h.store.ReadCIDispatch(ctx, id)
+	if err != nil {
+		return err
+	}
+	if dispatch != nil {
+		run, err := gh.FindDispatchedRun(ctx, r.Owner, r.Name, dispatch.WorkflowFile, dispatch.Ref, dispatch.DispatchedAt)
+		if err != nil {
+			return server.SetResponse(c, http.StatusOK, CheckStatusResponse{Status: "error", Summary: "Failed to fetch check status"})
+		}
+		github.ApplyDispatchedRun(result, dispatch.WorkflowFile, run)
+	}
+
+	return server.SetResponse(c, http.StatusOK, CheckStatusResponse{
+		Status:           string(result.Status),
 		Summary:          result.Summary,
 		FailedNames:      result.FailedNames,
 		CheckRunsSkipped: result.CheckRunsSkipped,
@@ -727,6 +1226,93 @@ func (h *HTTPHandler) GetTaskChecks(c echo.Context) error {
 	})
 }
 
+// RerunTaskChecks handles POST /tasks/:id/checks/rerun
+// It re-runs the failed jobs of every GitHub Actions workflow run with a
+// failed check on the task's PR, so a flaky check can pass without retrying
+// the task. The response reports the re-run checks as pending.
+func (h *HTTPHandler) RerunTaskChecks(c echo.Context) error {
+	req, err := server.BindRequest[TaskIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	ctx := c.Request().Context()
+
+	t, err := h.store.ReadTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	if t.Status != task.StatusReview && t.Status != task.StatusFailed {
+		return echo.NewHTTPError(http.StatusConflict, msgcat.Text(msgcat.ErrTaskNotReviewOrFailed))
+	}
+	if t.PRNumber <= 0 {
+		return task.ErrTaskNoPR
+	}
+
+	gh := h.githubClient()
+	if gh == nil {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, msgcat.Text(msgcat.ErrGitHubTokenNotConfigured))
+	}
+
+	repoID, parseErr := repo.ParseRepoID(t.RepoID)
+	if parseErr != nil {
+		return parseErr
+	}
+	r, readErr := h.repoStore.ReadRepo(ctx, repoID)
+	if readErr != nil {
+		return readErr
+	}
+
+	result, err := gh.GetPRCheckStatus(ctx, r.Owner, r.Name, t.PRNumber, r.CheckPolicy())
+	if err != nil {
+		return err
+	}
+
+	// Failed check runs from GitHub Actions are jobs; several can belong to
+	// the same workflow run, which is re-run once. FailedNames lists the
+	// failed check runs first, in the order of FailedRunIDs.
+	var runIDs []int64
+	var rerunNames []string
+	for i, jobID := range result.FailedRunIDs {
+		runID, err := gh.GetJobRunID(ctx, r.Owner, r.Name, jobID)
+		if err != nil {
+			return err
+		}
+		if runID == 0 {
+			continue
+		}
+		if !slices.Contains(runIDs, runID) {
+			runIDs = append(runIDs, runID)
+		}
+		if i < len(result.FailedNames) {
+			rerunNames = append(rerunNames, result.FailedNames[i])
+		}
+	}
+	if len(runIDs) == 0 {
+		return echo.NewHTTPError(http.StatusConflict, msgcat.Text(msgcat.ErrTaskNoFailedChecks))
+	}
+	for _, runID := range runIDs {
+		if err := gh.RerunFailedJobs(ctx, r.Owner, r.Name, runID); err != nil {
+			return githubRejection(err)
+		}
+	}
+
+	checks := slices.Clone(result.Checks)
+	for i, check := range checks {
+		if check.Conclusion == string(github.CheckStatusFailure) && slices.Contains(rerunNames, check.Name) {
+			checks[i].Status = "queued"
+			checks[i].Conclusion = ""
+		}
+	}
+	return server.SetResponse(c, http.StatusOK, CheckStatusResponse{
+		Status:  string(github.CheckStatusPending),
+		Summary: fmt.Sprintf("Re-running %d failed workflow run(s)", len(runIDs)),
+		Checks:  checks,
+	})
+}
+
 // GetTaskDiff handles GET /tasks/:id/diff
 func (h *HTTPHandler) GetTaskDiff(c echo.Context) error {
 	req, err := server.BindRequest[TaskIDRequest](c)
@@ -744,12 +1330,20 @@ func (h *HTTPHandler) GetTaskDiff(c echo.Context) error {
 	}
 
 	if t.PRNumber <= 0 {
+		// Shadow-mode runs record their diff instead of opening a PR.
+		shadow, err := h.store.ReadShadowDiff(ctx, id)
+		if err != nil {
+			return err
+		}
+		if shadow != nil {
+			return server.SetResponse(c, http.StatusOK, DiffResponse{Diff: shadow.Diff})
+		}
 		return server.SetResponse(c, http.StatusOK, DiffResponse{Diff: ""})
 	}
 
 	gh := h.githubClient()
 	if gh == nil {
-		return echo.NewHTTPError(http.StatusServiceUnavailable, "GitHub token not configured")
+		return echo.NewHTTPError(http.StatusServiceUnavailable, msgcat.Text(msgcat.ErrGitHubTokenNotConfigured))
 	}
 
 	repoID, parseErr := repo.ParseRepoID(t.RepoID)
@@ -769,6 +1363,168 @@ func (h *HTTPHandler) GetTaskDiff(c echo.Context) error {
 	return server.SetResponse(c, http.StatusOK, DiffResponse{Diff: diff})
 }
 
+// GetShadowDiff handles GET /tasks/:id/shadow-diff
+// It returns the diff recorded by the task's latest shadow-mode run, or null
+// if the task has not run in shadow mode.
+func (h *HTTPHandler) GetShadowDiff(c echo.Context) error {
+	req, err := server.BindRequest[TaskIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	ctx := c.Request().Context()
+
+	if _, err := h.store.ReadTask(ctx, id); err != nil {
+		return err
+	}
+	shadow, err := h.store.ReadShadowDiff(ctx, id)
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, shadow)
+}
+
+// GetProposal handles GET /tasks/:id/proposal
+// It returns the change plan recorded by the task's latest plan-only run, or
+// null if the task has not run in plan-only mode.
+func (h *HTTPHandler) GetProposal(c echo.Context) error {
+	req, err := server.BindRequest[TaskIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	ctx := c.Request().Context()
+
+	if _, err := h.store.ReadTask(ctx, id); err != nil {
+		return err
+	}
+	proposal, err := h.store.ReadProposal(ctx, id)
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, proposal)
+}
+
+// ApproveProposal handles POST /tasks/:id/proposal/approve
+// It approves the proposal of a plan-only task in review and sends the task
+// back to the agent to implement it.
+func (h *HTTPHandler) ApproveProposal(c echo.Context) error {
+	req, err := server.BindRequest[TaskIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	ctx := c.Request().Context()
+
+	if err := h.store.ApproveProposal(ctx, id); err != nil {
+		return err
+	}
+
+	t, err := h.store.ReadTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	return h.setTaskResponse(c, http.StatusOK, t)
+}
+
+// GetProvenanceReview handles GET /tasks/:id/provenance
+// It returns the license and provenance scan of the task's PR diff, or null
+// if the PR has not been scanned.
+func (h *HTTPHandler) GetProvenanceReview(c echo.Context) error {
+	req, err := server.BindRequest[TaskIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	ctx := c.Request().Context()
+
+	if _, err := h.store.ReadTask(ctx, id); err != nil {
+		return err
+	}
+	review, err := h.store.ReadProvenanceReview(ctx, id)
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, review)
+}
+
+// GetSelfReview handles GET /tasks/:id/self-review
+// It returns the automated self-review of the task's PR, or null if the PR
+// has not been reviewed.
+func (h *HTTPHandler) GetSelfReview(c echo.Context) error {
+	req, err := server.BindRequest[TaskIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	ctx := c.Request().Context()
+
+	if _, err := h.store.ReadTask(ctx, id); err != nil {
+		return err
+	}
+	review, err := h.store.ReadSelfReview(ctx, id)
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, review)
+}
+
+// AcknowledgeProvenanceReview handles POST /tasks/:id/provenance/acknowledge
+// It records that a human reviewed the task's provenance findings and marks
+// the PR's provenance commit status as passing so it can be merged.
+func (h *HTTPHandler) AcknowledgeProvenanceReview(c echo.Context) error {
+	req, err := server.BindRequest[TaskIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	ctx := c.Request().Context()
+
+	t, err := h.store.ReadTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	review, err := h.store.AcknowledgeProvenanceReview(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	gh := h.githubClient()
+	if gh == nil || t.PRNumber <= 0 {
+		return server.SetResponse(c, http.StatusOK, review)
+	}
+	repoID, err := repo.ParseRepoID(t.RepoID)
+	if err != nil {
+		return err
+	}
+	r, err := h.repoStore.ReadRepo(ctx, repoID)
+	if err != nil {
+		return err
+	}
+	// Acknowledging again is a no-op, so a failed status update can be
+	// retried by repeating the request.
+	if err := gh.SetCommitStatus(ctx, r.Owner, r.Name, review.HeadSHA, github.CommitStatus{
+		State:       "success",
+		Context:     provenance.StatusContext,
+		Description: msgcat.Text(msgcat.StatusProvenanceAcknowledged),
+	}); err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, review)
+}
+
 // StreamLogs handles GET /tasks/:id/logs as a Server-Sent Events stream.
 func (h *HTTPHandler) StreamLogs(c echo.Context) error {
 	req, err := server.BindRequest[TaskIDRequest](c)
@@ -778,6 +1534,516 @@ func (h *HTTPHandler) StreamLogs(c echo.Context) error {
 	id := task.MustParseTaskID(req.ID)
 	c.Set(logkey.TaskID, id.String())
 
+	return h.streamLogs(c, id, 0)
+}
+
+// ListAttempts handles GET /tasks/:id/attempts
+// It returns the task's attempts, oldest first, with their timing, cost,
+// result and the reason each retry was started.
+func (h *HTTPHandler) ListAttempts(c echo.Context) error {
+	req, err := server.BindRequest[TaskIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	ctx := c.Request().Context()
+
+	if _, err := h.store.ReadTask(ctx, id); err != nil {
+		return err
+	}
+	attempts, err := h.store.ListAttempts(ctx, id)
+	if err != nil {
+		return err
+	}
+	return server.SetResponseList(c, http.StatusOK, attempts, "")
+}
+
+// ListEvents handles GET /tasks/:id/events
+// It returns the structured events the agent reported (PRs, tool calls,
+// cost, status), oldest first. ?attempt=N limits them to a single attempt.
+func (h *HTTPHandler) ListEvents(c echo.Context) error {
+	req, err := server.BindRequest[ListTaskEventsRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	ctx := c.Request().Context()
+
+	if _, err := h.store.ReadTask(ctx, id); err != nil {
+		return err
+	}
+	events, err := h.store.ListAgentEvents(ctx, id, req.Attempt)
+	if err != nil {
+		return err
+	}
+	return server.SetResponseList(c, http.StatusOK, events, "")
+}
+
+// GetProgress handles GET /tasks/:id/progress
+// It returns the agent's latest reported plan and the status of each step.
+func (h *HTTPHandler) GetProgress(c echo.Context) error {
+	req, err := server.BindRequest[TaskIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	ctx := c.Request().Context()
+
+	if _, err := h.store.ReadTask(ctx, id); err != nil {
+		return err
+	}
+	progress, err := h.store.ReadProgress(ctx, id)
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, progress)
+}
+
+// NudgeTask handles POST /tasks/:id/nudge
+// It queues a message for the agent of a running task. The worker delivers it
+// into the agent container with its next heartbeat, so the agent can be
+// steered without stopping the run.
+func (h *HTTPHandler) NudgeTask(c echo.Context) error {
+	req, err := server.BindRequest[NudgeRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	nudge, err := h.store.NudgeTask(c.Request().Context(), id, req.Message)
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusCreated, nudge)
+}
+
+// ListNudges handles GET /tasks/:id/nudges
+// It returns the messages sent to the task's agent, oldest first.
+func (h *HTTPHandler) ListNudges(c echo.Context) error {
+	req, err := server.BindRequest[TaskIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	ctx := c.Request().Context()
+
+	if _, err := h.store.ReadTask(ctx, id); err != nil {
+		return err
+	}
+	nudges, err := h.store.ListNudges(ctx, id)
+	if err != nil {
+		return err
+	}
+	return server.SetResponseList(c, http.StatusOK, nudges, "")
+}
+
+// ListComments handles GET /tasks/:id/comments
+// It returns the task's comments, oldest first.
+func (h *HTTPHandler) ListComments(c echo.Context) error {
+	req, err := server.BindRequest[TaskIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	ctx := c.Request().Context()
+
+	if _, err := h.store.ReadTask(ctx, id); err != nil {
+		return err
+	}
+	comments, err := h.store.ListComments(ctx, id)
+	if err != nil {
+		return err
+	}
+	return server.SetResponseList(c, http.StatusOK, comments, "")
+}
+
+// CreateComment handles POST /tasks/:id/comments
+// The comment's author is the user calling with their token. Callers without
+// a user token may name the author, which defaults to anonymous.
+func (h *HTTPHandler) CreateComment(c echo.Context) error {
+	req, err := server.BindRequest[CreateCommentRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	comment := &task.Comment{
+		Attempt: req.Attempt,
+		Author:  strings.TrimSpace(req.Author),
+		Body:    req.Body,
+	}
+	if u := userapi.CurrentUser(c); u != nil {
+		comment.Author = u.Name
+		if u.ID != (user.UserID{}) {
+			comment.AuthorID = u.ID.String()
+		}
+	}
+	if comment.Author == "" {
+		comment.Author = task.AnonymousAuthor
+	}
+
+	created, err := h.store.CreateComment(c.Request().Context(), id, comment)
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusCreated, created)
+}
+
+// UpdateComment handles PATCH /tasks/:id/comments/:comment_id
+func (h *HTTPHandler) UpdateComment(c echo.Context) error {
+	req, err := server.BindRequest[UpdateCommentRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	commentID, _ := strconv.ParseInt(req.CommentID, 10, 64) // safe after validation
+
+	if err := h.checkCommentAuthor(c, id, commentID); err != nil {
+		return err
+	}
+	comment, err := h.store.UpdateComment(c.Request().Context(), id, commentID, req.Body)
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, comment)
+}
+
+// DeleteComment handles DELETE /tasks/:id/comments/:comment_id
+func (h *HTTPHandler) DeleteComment(c echo.Context) error {
+	req, err := server.BindRequest[CommentIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	commentID, _ := strconv.ParseInt(req.CommentID, 10, 64) // safe after validation
+
+	if err := h.checkCommentAuthor(c, id, commentID); err != nil {
+		return err
+	}
+	if err := h.store.DeleteComment(c.Request().Context(), id, commentID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// checkCommentAuthor rejects changes to a comment by a user other than its
+// author. Admins can change any comment, and comments left without a user
+// token can be changed by anyone.
+func (h *HTTPHandler) checkCommentAuthor(c echo.Context, id task.TaskID, commentID int64) error {
+	comment, err := h.store.ReadComment(c.Request().Context(), id, commentID)
+	if err != nil {
+		return err
+	}
+	u := userapi.CurrentUser(c)
+	if comment.AuthorID == "" || u == nil || u.Role.Allows(user.RoleAdmin) || u.ID.String() == comment.AuthorID {
+		return nil
+	}
+	return task.ErrCommentNotAuthor
+}
+
+// multipartOverhead is the room left for multipart headers and boundaries on
+// top of the attachment size limit when capping upload request bodies.
+const multipartOverhead = 64 << 10
+
+// ListAttachments handles GET /tasks/:id/attachments
+// It returns the task's attachments, oldest first.
+func (h *HTTPHandler) ListAttachments(c echo.Context) error {
+	req, err := server.BindRequest[TaskIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	ctx := c.Request().Context()
+
+	if _, err := h.store.ReadTask(ctx, id); err != nil {
+		return err
+	}
+	attachments, err := h.store.ListAttachments(ctx, id)
+	if err != nil {
+		return err
+	}
+	return server.SetResponseList(c, http.StatusOK, attachments, "")
+}
+
+// UploadAttachment handles POST /tasks/:id/attachments
+// The file is read from the multipart form field "file". Its extension must
+// be an allowed type matching its contents, and it must fit the store's size
+// limit. The uploader is the user calling with their token, if any.
+func (h *HTTPHandler) UploadAttachment(c echo.Context) error {
+	// The body is multipart, so only the path is validated up front.
+	req := TaskIDRequest{ID: c.Param("id")}
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	maxSize := h.store.MaxAttachmentSize()
+	if maxSize == 0 {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, msgcat.Text(msgcat.ErrAttachmentsNotConfigured))
+	}
+	tooLarge := echo.NewHTTPError(http.StatusRequestEntityTooLarge, msgcat.Text(msgcat.ErrAttachmentTooLarge, maxSize))
+
+	r := c.Request()
+	r.Body = http.MaxBytesReader(c.Response(), r.Body, maxSize+multipartOverhead)
+	fh, err := c.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return tooLarge
+		}
+		return echo.NewHTTPError(http.StatusBadRequest, "file is required")
+	}
+	if fh.Size > maxSize {
+		return tooLarge
+	}
+	filename, ok := task.CleanAttachmentFilename(fh.Filename)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, "file has an invalid name")
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	data, err := io.ReadAll(io.LimitReader(f, maxSize+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) > maxSize {
+		return tooLarge
+	}
+	contentType, ok := task.AttachmentContentType(filename, data)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnsupportedMediaType,
+			msgcat.Text(msgcat.ErrAttachmentType, strings.Join(task.AttachmentExtensions(), ", ")))
+	}
+
+	attachment := &task.Attachment{
+		Filename:    filename,
+		ContentType: contentType,
+		SizeBytes:   int64(len(data)),
+		UploadedBy:  task.AnonymousAuthor,
+	}
+	if u := userapi.CurrentUser(c); u != nil {
+		attachment.UploadedBy = u.Name
+	}
+
+	created, err := h.store.CreateAttachment(r.Context(), id, attachment, data)
+	if err != nil {
+		return attachmentError(err)
+	}
+	return server.SetResponse(c, http.StatusCreated, created)
+}
+
+// DownloadAttachment handles GET /tasks/:id/attachments/:attachment_id
+// It responds with the file's contents as a download.
+func (h *HTTPHandler) DownloadAttachment(c echo.Context) error {
+	req, err := server.BindRequest[AttachmentIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	attachmentID, _ := strconv.ParseInt(req.AttachmentID, 10, 64) // safe after validation
+
+	attachment, contents, err := h.store.OpenAttachment(c.Request().Context(), id, attachmentID)
+	if err != nil {
+		return attachmentError(err)
+	}
+	defer func() { _ = contents.Close() }()
+	writeDownloadHeaders(c, attachment.Filename, attachment.SizeBytes)
+	return c.Stream(http.StatusOK, attachment.ContentType, contents)
+}
+
+// DeleteAttachment handles DELETE /tasks/:id/attachments/:attachment_id
+func (h *HTTPHandler) DeleteAttachment(c echo.Context) error {
+	req, err := server.BindRequest[AttachmentIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	attachmentID, _ := strconv.ParseInt(req.AttachmentID, 10, 64) // safe after validation
+
+	if err := h.store.DeleteAttachment(c.Request().Context(), id, attachmentID); err != nil {
+		return attachmentError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// writeDownloadHeaders sets the headers for serving a stored file as a
+// download. Browsers are told not to sniff the content so an uploaded file is
+// never rendered as another type.
+func writeDownloadHeaders(c echo.Context, filename string, size int64) {
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Content-Length", strconv.FormatInt(size, 10))
+}
+
+// ListArtifacts handles GET /tasks/:id/artifacts
+// It returns the files the task's agent produced, ordered by attempt. The
+// optional ?attempt= filter limits the list to one attempt.
+func (h *HTTPHandler) ListArtifacts(c echo.Context) error {
+	req, err := server.BindRequest[ListArtifactsRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	ctx := c.Request().Context()
+
+	if _, err := h.store.ReadTask(ctx, id); err != nil {
+		return err
+	}
+	artifacts, err := h.store.ListArtifacts(ctx, id, req.Attempt)
+	if err != nil {
+		return err
+	}
+	return server.SetResponseList(c, http.StatusOK, artifacts, "")
+}
+
+// DownloadArtifact handles GET /tasks/:id/artifacts/:artifact_id
+// It responds with the artifact's contents as a download named after the
+// artifact's base name.
+func (h *HTTPHandler) DownloadArtifact(c echo.Context) error {
+	req, err := server.BindRequest[ArtifactIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	artifactID, _ := strconv.ParseInt(req.ArtifactID, 10, 64) // safe after validation
+
+	artifact, contents, err := h.store.OpenArtifact(c.Request().Context(), id, artifactID)
+	if err != nil {
+		return attachmentError(err)
+	}
+	defer func() { _ = contents.Close() }()
+	writeDownloadHeaders(c, path.Base(artifact.Name), artifact.SizeBytes)
+	return c.Stream(http.StatusOK, artifact.ContentType, contents)
+}
+
+// attachmentError maps the store's error for disabled attachment storage to
+// 503 Service Unavailable.
+func attachmentError(err error) error {
+	if errors.Is(err, task.ErrAttachmentsNotConfigured) {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, msgcat.Text(msgcat.ErrAttachmentsNotConfigured))
+	}
+	return err
+}
+
+// ListNotes handles GET /repos/:repo_id/notes
+// It returns the notes agents left on the repo's tasks, newest first.
+func (h *HTTPHandler) ListNotes(c echo.Context) error {
+	req, err := server.BindRequest[RepoIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := repo.MustParseRepoID(req.RepoID)
+	c.Set(logkey.RepoID, id.String())
+
+	notes, err := h.store.ListRepoNotes(c.Request().Context(), id.String())
+	if err != nil {
+		return err
+	}
+	return server.SetResponseList(c, http.StatusOK, notes, "")
+}
+
+// ConvertNote handles POST /notes/:id/convert
+// It creates a pending task for the follow-up work described by the note.
+func (h *HTTPHandler) ConvertNote(c echo.Context) error {
+	req, err := server.BindRequest[NoteIDRequest](c)
+	if err != nil {
+		return err
+	}
+	noteID, _ := strconv.ParseInt(req.ID, 10, 64) // safe after validation
+	ctx := c.Request().Context()
+
+	note, err := h.store.ReadNote(ctx, noteID)
+	if err != nil {
+		return err
+	}
+	c.Set(logkey.RepoID, note.RepoID)
+	r, err := h.repoStore.ReadRepo(ctx, repo.MustParseRepoID(note.RepoID))
+	if err != nil {
+		return err
+	}
+	if r.SetupStatus != repo.SetupStatusReady {
+		return echo.NewHTTPError(http.StatusConflict, msgcat.Text(msgcat.ErrRepoSetupIncompleteTasks))
+	}
+
+	defaults, err := h.repoStore.TeamDefaults(ctx, r)
+	if err != nil {
+		return err
+	}
+	model := defaults.Model
+	if model == "" && h.settingService != nil {
+		model = h.settingService.Get(setting.KeyDefaultModel)
+	}
+	if model == "" {
+		model = "sonnet"
+	}
+	t, err := h.store.ConvertNote(ctx, noteID, model)
+	if err != nil {
+		return err
+	}
+	c.Set(logkey.TaskID, t.ID.String())
+	return h.setTaskResponse(c, http.StatusCreated, t)
+}
+
+// StreamAttemptLogs handles GET /tasks/:id/attempts/:attempt/logs as a
+// Server-Sent Events stream. It behaves like GET /tasks/:id/logs but only
+// streams the logs of a single attempt.
+func (h *HTTPHandler) StreamAttemptLogs(c echo.Context) error {
+	req, err := server.BindRequest[TaskAttemptRequest](c)
+	if err != nil {
+		return err
+	}
+	id := task.MustParseTaskID(req.ID)
+	c.Set(logkey.TaskID, id.String())
+
+	attempt, _ := strconv.Atoi(req.Attempt) // safe after validation
+
+	t, err := h.store.ReadTask(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	if attempt > t.Attempt {
+		return echo.NewHTTPError(http.StatusNotFound, msgcat.Text(msgcat.ErrAttemptNotFound))
+	}
+
+	return h.streamLogs(c, id, attempt)
+}
+
+// streamLogs writes a task's stored logs followed by a logs_done event, then
+// streams new logs as they are appended. When attempt is non-zero only that
+// attempt's logs are streamed.
+func (h *HTTPHandler) streamLogs(c echo.Context, id task.TaskID, attempt int) error {
 	w := c.Response()
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
@@ -789,14 +2055,22 @@ func (h *HTTPHandler) StreamLogs(c echo.Context) error {
 	ch := h.store.Subscribe()
 	defer h.store.Unsubscribe(ch)
 
-	err = h.store.StreamTaskLogs(ctx, id, func(attempt int, lines []string) error {
+	writeBatch := func(attempt int, lines []string) error {
 		return writeSSE(w, task.EventLogsAppended, task.Event{
 			Type:    task.EventLogsAppended,
 			TaskID:  id,
 			Attempt: attempt,
 			Logs:    lines,
 		})
-	})
+	}
+	var err error
+	if attempt == 0 {
+		err = h.store.StreamTaskLogs(ctx, id, writeBatch)
+	} else {
+		err = h.store.StreamAttemptLogs(ctx, id, attempt, func(lines []string) error {
+			return writeBatch(attempt, lines)
+		})
+	}
 	if err != nil {
 		return nil
 	}
@@ -808,10 +2082,14 @@ func (h *HTTPHandler) StreamLogs(c echo.Context) error {
 	for {
 		select {
 		case event := <-ch:
-			if event.Type == task.EventLogsAppended && event.TaskID == id {
-				if err := writeSSE(w, event.Type, event); err != nil {
-					return nil
-				}
+			if event.Type != task.EventLogsAppended || event.TaskID != id {
+				continue
+			}
+			if attempt != 0 && event.Attempt != attempt {
+				continue
+			}
+			if err := writeSSE(w, event.Type, event); err != nil {
+				return nil
 			}
 		case <-ctx.Done():
 			return nil
@@ -827,6 +2105,37 @@ func (h *HTTPHandler) githubClient() *github.Client {
 	return h.githubTokenService.GetClient()
 }
 
+// closePullRequests closes the task's unmerged PRs, in its own repo and any
+// additional repos, and deletes their branches. Failures are ignored.
+func (h *HTTPHandler) closePullRequests(ctx context.Context, t *task.Task) {
+	gh := h.githubClient()
+	if gh == nil || t.Status == task.StatusMerged {
+		return
+	}
+	closePR := func(repoIDStr string, number int) {
+		repoID, err := repo.ParseRepoID(repoIDStr)
+		if err != nil {
+			return
+		}
+		r, err := h.repoStore.ReadRepo(ctx, repoID)
+		if err != nil {
+			return
+		}
+		branch, err := gh.ClosePR(ctx, r.Owner, r.Name, number)
+		if err == nil && branch != "" {
+			_ = gh.DeleteBranch(ctx, r.Owner, r.Name, branch)
+		}
+	}
+	if t.PRNumber > 0 {
+		closePR(t.RepoID, t.PRNumber)
+	}
+	for _, pr := range t.PullRequests {
+		if !pr.Merged {
+			closePR(pr.RepoID, pr.Number)
+		}
+	}
+}
+
 func writeSSE(w *echo.Response, event string, data any) error {
 	b, err := json.Marshal(data)
 	if err != nil {
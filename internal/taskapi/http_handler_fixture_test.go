@@ -14,15 +14,22 @@ import (
 	"github.com/joshjon/kit/testutil"
 	"github.com/stretchr/testify/require"
 
+	"github.com/vervesh/verve/internal/app"
+	"github.com/vervesh/verve/internal/attachment"
+	"github.com/vervesh/verve/internal/openapi"
 	"github.com/vervesh/verve/internal/repo"
 	"github.com/vervesh/verve/internal/sqlite"
 	"github.com/vervesh/verve/internal/task"
 	"github.com/vervesh/verve/internal/taskapi"
 )
 
+// v1Sunset is the v1 removal date the fixture's handler announces.
+var v1Sunset = time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+
 type fixture struct {
 	Server   *server.Server
 	TaskRepo task.Repository
+	TaskStore *task.Store
 	RepoStore *repo.Store
 	Repo     *repo.Repo
 	t        *testing.T
@@ -35,15 +42,24 @@ func newFixture(t *testing.T) *fixture {
 	taskRepo := sqlite.NewTaskRepository(db)
 	broker := task.NewBroker(nil)
 	taskStore := task.NewStore(taskRepo, broker)
+	attachments, err := attachment.NewDirStorage(t.TempDir())
+	require.NoError(t, err)
+	taskStore.SetAttachmentStorage(attachments, 0)
 
 	repoRepo := sqlite.NewRepoRepository(db)
 	repoStore := repo.NewStore(repoRepo)
 
-	handler := taskapi.NewHTTPHandler(taskStore, repoStore, nil, nil, nil)
+	handler := taskapi.NewHTTPHandler(taskStore, repoStore, nil, nil, nil, taskapi.WithV1Sunset(v1Sunset))
+
+	spec := openapi.New("Verve API", "test")
+	spec.Add("/api/v1", handler.Routes()...)
+	spec.Add("/api/v2", handler.V2().Routes()...)
 
-	srv, err := server.NewServer(testutil.GetFreePort(t))
+	srv, err := server.NewServer(testutil.GetFreePort(t), server.WithRequestTimeout(server.DefaultRequestTimeout, app.StreamingPaths...))
 	require.NoError(t, err)
-	srv.Register("/api/v1", handler)
+	srv.Register("/api/v1", handler, spec.ValidateRequests)
+	srv.Register("/api/v2", handler.V2(), spec.ValidateRequests)
+	srv.Register("/api/v1", openapi.NewHTTPHandler(spec))
 
 	go srv.Start()
 	err = srv.WaitHealthy(10, 100*time.Millisecond)
@@ -60,6 +76,7 @@ func newFixture(t *testing.T) *fixture {
 	return &fixture{
 		Server:   srv,
 		TaskRepo: taskRepo,
+		TaskStore: taskStore,
 		RepoStore: repoStore,
 		Repo:     r,
 		t:        t,
@@ -76,6 +93,22 @@ func (f *fixture) taskURL(id task.TaskID) string {
 	return fmt.Sprintf("%s/api/v1/tasks/%s", f.Server.Address(), id)
 }
 
+func (f *fixture) taskV2URL(id task.TaskID) string {
+	return fmt.Sprintf("%s/api/v2/tasks/%s", f.Server.Address(), id)
+}
+
+func (f *fixture) taskActionV2URL(id task.TaskID, action string) string {
+	return fmt.Sprintf("%s/%s", f.taskV2URL(id), action)
+}
+
+func (f *fixture) repoTasksV2URL() string {
+	return fmt.Sprintf("%s/api/v2/repos/%s/tasks", f.Server.Address(), f.Repo.ID)
+}
+
+func (f *fixture) openAPIURL() string {
+	return fmt.Sprintf("%s/api/v1/openapi.json", f.Server.Address())
+}
+
 func (f *fixture) taskActionURL(id task.TaskID, action string) string {
 	return fmt.Sprintf("%s/%s", f.taskURL(id), action)
 }
@@ -88,6 +121,14 @@ func (f *fixture) taskByNumberRawURL(repoID, number string) string {
 	return fmt.Sprintf("%s/api/v1/repos/%s/tasks/%s", f.Server.Address(), repoID, number)
 }
 
+func (f *fixture) repoNotesURL() string {
+	return fmt.Sprintf("%s/api/v1/repos/%s/notes", f.Server.Address(), f.Repo.ID)
+}
+
+func (f *fixture) convertNoteURL(id string) string {
+	return fmt.Sprintf("%s/api/v1/notes/%s/convert", f.Server.Address(), id)
+}
+
 // --- Seed helpers ---
 
 func (f *fixture) seedTask(title, description string) *task.Task {
@@ -106,6 +147,16 @@ func (f *fixture) seedRunningTask(title, description string) *task.Task {
 	return tsk
 }
 
+func (f *fixture) seedReadyRepo(fullName string) *repo.Repo {
+	f.t.Helper()
+	ctx := context.Background()
+	r, err := repo.NewRepo(fullName)
+	require.NoError(f.t, err)
+	require.NoError(f.t, f.RepoStore.CreateRepo(ctx, r))
+	require.NoError(f.t, f.RepoStore.UpdateRepoSetupStatus(ctx, r.ID, repo.SetupStatusReady))
+	return r
+}
+
 func (f *fixture) readTask(id task.TaskID) *task.Task {
 	f.t.Helper()
 	tsk, err := f.TaskRepo.ReadTask(context.Background(), id)
@@ -1,19 +1,31 @@
 package taskapi_test
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/joshjon/kit/server"
 	"github.com/joshjon/kit/testutil"
+	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/vervesh/verve/internal/openapi"
+	"github.com/vervesh/verve/internal/provenance"
 	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/selfreview"
 	"github.com/vervesh/verve/internal/task"
 	"github.com/vervesh/verve/internal/taskapi"
+	"github.com/vervesh/verve/pkg/verveclient"
 )
 
 // --- CreateTask ---
@@ -21,7 +33,7 @@ import (
 func TestCreateTask_Success(t *testing.T) {
 	f := newFixture(t)
 
-	req := taskapi.CreateTaskRequest{
+	req := verveclient.CreateTaskRequest{
 		Title:       "Fix bug",
 		Description: "Fix the login bug",
 	}
@@ -34,7 +46,7 @@ func TestCreateTask_Success(t *testing.T) {
 func TestCreateTask_EmptyTitle(t *testing.T) {
 	f := newFixture(t)
 
-	req := taskapi.CreateTaskRequest{
+	req := verveclient.CreateTaskRequest{
 		Title:       "",
 		Description: "some desc",
 	}
@@ -46,7 +58,7 @@ func TestCreateTask_EmptyTitle(t *testing.T) {
 func TestCreateTask_TitleTooLong(t *testing.T) {
 	f := newFixture(t)
 
-	req := taskapi.CreateTaskRequest{
+	req := verveclient.CreateTaskRequest{
 		Title:       strings.Repeat("a", 151),
 		Description: "desc",
 	}
@@ -58,7 +70,7 @@ func TestCreateTask_TitleTooLong(t *testing.T) {
 func TestCreateTask_InvalidRepoID(t *testing.T) {
 	f := newFixture(t)
 
-	req := taskapi.CreateTaskRequest{Title: "Fix bug"}
+	req := verveclient.CreateTaskRequest{Title: "Fix bug"}
 	// Post to an invalid repo ID URL.
 	url := f.Server.Address() + "/api/v1/repos/invalid/tasks"
 	httpRes := doJSON(t, http.MethodPost, url, req)
@@ -69,7 +81,7 @@ func TestCreateTask_InvalidRepoID(t *testing.T) {
 func TestCreateTask_WithModel(t *testing.T) {
 	f := newFixture(t)
 
-	req := taskapi.CreateTaskRequest{
+	req := verveclient.CreateTaskRequest{
 		Title:       "Fix bug",
 		Description: "desc",
 		Model:       "opus",
@@ -81,7 +93,7 @@ func TestCreateTask_WithModel(t *testing.T) {
 func TestCreateTask_WithDraftPR(t *testing.T) {
 	f := newFixture(t)
 
-	req := taskapi.CreateTaskRequest{
+	req := verveclient.CreateTaskRequest{
 		Title:       "Fix bug",
 		Description: "desc",
 		DraftPR:     true,
@@ -91,10 +103,54 @@ func TestCreateTask_WithDraftPR(t *testing.T) {
 	assert.Equal(t, false, res.Data.SkipPR)
 }
 
+func TestCreateTask_WithMaxRuntime(t *testing.T) {
+	f := newFixture(t)
+
+	req := verveclient.CreateTaskRequest{
+		Title:             "Migrate database",
+		Description:       "desc",
+		MaxRuntimeSeconds: 3 * 3600,
+	}
+	res := testutil.Post[server.Response[task.Task]](t, f.repoTasksURL(), req)
+	assert.Equal(t, 3*3600, res.Data.MaxRuntimeSeconds)
+	assert.Equal(t, 3*3600, f.readTask(res.Data.ID).MaxRuntimeSeconds)
+}
+
+func TestCreateTask_NegativeMaxRuntime(t *testing.T) {
+	f := newFixture(t)
+
+	req := verveclient.CreateTaskRequest{
+		Title:             "Fix bug",
+		Description:       "desc",
+		MaxRuntimeSeconds: -1,
+	}
+	httpRes := doJSON(t, http.MethodPost, f.repoTasksURL(), req)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+}
+
+func TestCreateTask_WithRequiredLabels(t *testing.T) {
+	f := newFixture(t)
+
+	req := verveclient.CreateTaskRequest{
+		Title:          "Train model",
+		Description:    "desc",
+		RequiredLabels: []string{"gpu"},
+	}
+	res := testutil.Post[server.Response[task.Task]](t, f.repoTasksURL(), req)
+	assert.Equal(t, []string{"gpu"}, res.Data.RequiredLabels)
+	assert.Equal(t, []string{"gpu"}, f.readTask(res.Data.ID).RequiredLabels)
+
+	req.RequiredLabels = []string{"Has Spaces"}
+	httpRes := doJSON(t, http.MethodPost, f.repoTasksURL(), req)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+}
+
 func TestCreateTask_SkipPRAndDraftPR_MutuallyExclusive(t *testing.T) {
 	f := newFixture(t)
 
-	req := taskapi.CreateTaskRequest{
+	req := verveclient.CreateTaskRequest{
 		Title:       "Fix bug",
 		Description: "desc",
 		SkipPR:      true,
@@ -108,7 +164,7 @@ func TestCreateTask_SkipPRAndDraftPR_MutuallyExclusive(t *testing.T) {
 func TestCreateTask_WithSkipPR(t *testing.T) {
 	f := newFixture(t)
 
-	req := taskapi.CreateTaskRequest{
+	req := verveclient.CreateTaskRequest{
 		Title:       "Fix bug",
 		Description: "desc",
 		SkipPR:      true,
@@ -118,6 +174,49 @@ func TestCreateTask_WithSkipPR(t *testing.T) {
 	assert.Equal(t, false, res.Data.DraftPR)
 }
 
+func TestCreateTask_WithAdditionalRepos(t *testing.T) {
+	f := newFixture(t)
+
+	other, err := repo.NewRepo("owner/frontend")
+	require.NoError(t, err)
+	require.NoError(t, f.RepoStore.CreateRepo(context.Background(), other))
+
+	req := verveclient.CreateTaskRequest{
+		Title:             "Add field",
+		Description:       "Add the field to the API and the UI",
+		AdditionalRepoIDs: []string{other.ID.String()},
+	}
+	res := testutil.Post[server.Response[task.Task]](t, f.repoTasksURL(), req)
+	assert.Equal(t, []string{other.ID.String()}, res.Data.AdditionalRepoIDs)
+	assert.Equal(t, []string{other.ID.String()}, f.readTask(res.Data.ID).AdditionalRepoIDs)
+}
+
+func TestCreateTask_AdditionalReposInvalid(t *testing.T) {
+	f := newFixture(t)
+
+	missing, err := repo.NewRepo("owner/missing")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		repoIDs []string
+		status  int
+	}{
+		{name: "task repo", repoIDs: []string{f.Repo.ID.String()}, status: http.StatusBadRequest},
+		{name: "duplicate", repoIDs: []string{missing.ID.String(), missing.ID.String()}, status: http.StatusBadRequest},
+		{name: "invalid id", repoIDs: []string{"invalid"}, status: http.StatusBadRequest},
+		{name: "not found", repoIDs: []string{missing.ID.String()}, status: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := verveclient.CreateTaskRequest{Title: "Fix bug", AdditionalRepoIDs: tt.repoIDs}
+			httpRes := doJSON(t, http.MethodPost, f.repoTasksURL(), req)
+			defer httpRes.Body.Close()
+			assert.Equal(t, tt.status, httpRes.StatusCode)
+		})
+	}
+}
+
 func TestCreateTask_BlockedWhenRepoNotReady(t *testing.T) {
 	f := newFixture(t)
 
@@ -125,7 +224,7 @@ func TestCreateTask_BlockedWhenRepoNotReady(t *testing.T) {
 	ctx := context.Background()
 	require.NoError(t, f.RepoStore.UpdateRepoSetupStatus(ctx, f.Repo.ID, repo.SetupStatusScanning))
 
-	req := taskapi.CreateTaskRequest{
+	req := verveclient.CreateTaskRequest{
 		Title:       "Fix bug",
 		Description: "desc",
 	}
@@ -141,7 +240,7 @@ func TestUpdateTask_SetDraftPR(t *testing.T) {
 	tsk := f.seedTask("title", "desc")
 
 	draftPR := true
-	req := taskapi.UpdateTaskRequest{DraftPR: &draftPR}
+	req := verveclient.UpdateTaskRequest{DraftPR: &draftPR}
 	httpRes := doJSON(t, http.MethodPatch, f.taskURL(tsk.ID), req)
 	defer httpRes.Body.Close()
 	assert.Equal(t, http.StatusOK, httpRes.StatusCode)
@@ -151,18 +250,169 @@ func TestUpdateTask_SetDraftPR(t *testing.T) {
 	assert.Equal(t, false, updated.SkipPR)
 }
 
+func TestUpdateTask_SetMaxRuntime(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedTask("title", "desc")
+
+	seconds := 7200
+	req := verveclient.UpdateTaskRequest{MaxRuntimeSeconds: &seconds}
+	httpRes := doJSON(t, http.MethodPatch, f.taskURL(tsk.ID), req)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusOK, httpRes.StatusCode)
+
+	assert.Equal(t, seconds, f.readTask(tsk.ID).MaxRuntimeSeconds)
+}
+
+func TestUpdateTask_SetRequiredLabels(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedTask("title", "desc")
+
+	labels := []string{"gpu", "linux"}
+	req := verveclient.UpdateTaskRequest{RequiredLabels: &labels}
+	httpRes := doJSON(t, http.MethodPatch, f.taskURL(tsk.ID), req)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusOK, httpRes.StatusCode)
+	assert.Equal(t, []string{"gpu", "linux"}, f.readTask(tsk.ID).RequiredLabels)
+
+	// An empty list removes the requirement
+	labels = []string{}
+	req = verveclient.UpdateTaskRequest{RequiredLabels: &labels}
+	httpRes = doJSON(t, http.MethodPatch, f.taskURL(tsk.ID), req)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusOK, httpRes.StatusCode)
+	assert.Empty(t, f.readTask(tsk.ID).RequiredLabels)
+}
+
 func TestUpdateTask_SkipPRAndDraftPR_MutuallyExclusive(t *testing.T) {
 	f := newFixture(t)
 	tsk := f.seedTask("title", "desc")
 
 	skipPR := true
 	draftPR := true
-	req := taskapi.UpdateTaskRequest{SkipPR: &skipPR, DraftPR: &draftPR}
+	req := verveclient.UpdateTaskRequest{SkipPR: &skipPR, DraftPR: &draftPR}
 	httpRes := doJSON(t, http.MethodPatch, f.taskURL(tsk.ID), req)
 	defer httpRes.Body.Close()
 	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode, "expected error for mutually exclusive skip_pr and draft_pr")
 }
 
+func TestUpdateTask_ExpectedVersion(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedTask("title", "desc")
+	read := f.readTask(tsk.ID).Version
+
+	title := "edited"
+	req := verveclient.UpdateTaskRequest{Title: &title, ExpectedVersion: &read}
+	httpRes := doJSON(t, http.MethodPatch, f.taskURL(tsk.ID), req)
+	defer httpRes.Body.Close()
+	require.Equal(t, http.StatusOK, httpRes.StatusCode)
+	updated := f.readTask(tsk.ID)
+	assert.Greater(t, updated.Version, read)
+	assert.Equal(t, strconv.Quote(strconv.FormatInt(updated.Version, 10)), httpRes.Header.Get("ETag"))
+
+	// An edit made from the earlier read would overwrite the title.
+	title = "stale"
+	httpRes = doJSON(t, http.MethodPatch, f.taskURL(tsk.ID), req)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusConflict, httpRes.StatusCode)
+	assert.Equal(t, "edited", f.readTask(tsk.ID).Title)
+
+	patch := func(ifMatch string) int {
+		t.Helper()
+		httpReq, err := http.NewRequest(http.MethodPatch, f.taskURL(tsk.ID), mustJSONReader(verveclient.UpdateTaskRequest{Title: &title}))
+		require.NoError(t, err)
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("If-Match", ifMatch)
+		res, err := testutil.DefaultClient.Do(httpReq)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		return res.StatusCode
+	}
+	assert.Equal(t, http.StatusConflict, patch(strconv.Quote(strconv.FormatInt(read, 10))))
+	assert.Equal(t, http.StatusBadRequest, patch("not-a-version"))
+	assert.Equal(t, http.StatusOK, patch(strconv.Quote(strconv.FormatInt(updated.Version, 10))))
+	assert.Equal(t, "stale", f.readTask(tsk.ID).Title)
+}
+
+func TestStartOverTask_ExpectedVersion(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	tsk := f.seedTask("title", "desc")
+	require.NoError(t, f.TaskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusFailed))
+	read := f.readTask(tsk.ID).Version
+	require.NoError(t, f.TaskRepo.SetAgentStatus(ctx, tsk.ID, "{}"))
+
+	req := verveclient.StartOverRequest{ExpectedVersion: &read}
+	httpRes := doJSON(t, http.MethodPost, f.taskActionURL(tsk.ID, "start-over"), req)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusConflict, httpRes.StatusCode)
+	assert.Equal(t, task.StatusFailed, f.readTask(tsk.ID).Status)
+
+	current := f.readTask(tsk.ID).Version
+	req.ExpectedVersion = &current
+	httpRes = doJSON(t, http.MethodPost, f.taskActionURL(tsk.ID, "start-over"), req)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusOK, httpRes.StatusCode)
+	assert.Equal(t, task.StatusPending, f.readTask(tsk.ID).Status)
+}
+
+// --- MoveTask ---
+
+func TestMoveTask(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	target := f.seedReadyRepo("owner/frontend")
+	existing := task.NewTask(target.ID.String(), "existing", "desc", nil, nil, 0, false, false, "sonnet", true)
+	require.NoError(t, f.TaskRepo.CreateTask(ctx, existing))
+
+	tsk := f.seedTask("title", "desc")
+	// A branch left by an earlier attempt stays in the old repo.
+	require.NoError(t, f.TaskRepo.SetBranchName(ctx, tsk.ID, "verve/old-branch"))
+	require.NoError(t, f.TaskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusPending))
+
+	req := verveclient.MoveTaskRequest{RepoID: target.ID.String()}
+	res := testutil.Post[server.Response[task.Task]](t, f.taskActionURL(tsk.ID, "move"), req)
+	assert.Equal(t, target.ID.String(), res.Data.RepoID)
+	assert.Equal(t, existing.Number+1, res.Data.Number, "expected the next number in the target repo")
+	assert.Empty(t, res.Data.BranchName)
+	assert.Equal(t, task.StatusPending, res.Data.Status)
+}
+
+func TestMoveTask_Dependencies(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	target := f.seedReadyRepo("owner/frontend")
+
+	dep := f.seedTask("dep", "desc")
+	tsk := task.NewTask(f.Repo.ID.String(), "title", "desc", []string{dep.ID.String()}, nil, 0, false, false, "sonnet", true)
+	require.NoError(t, f.TaskRepo.CreateTask(ctx, tsk))
+	dependent := task.NewTask(f.Repo.ID.String(), "dependent", "desc", []string{tsk.ID.String()}, nil, 0, false, false, "sonnet", true)
+	require.NoError(t, f.TaskRepo.CreateTask(ctx, dependent))
+
+	req := verveclient.MoveTaskRequest{RepoID: target.ID.String()}
+	httpRes := doJSON(t, http.MethodPost, f.taskActionURL(tsk.ID, "move"), req)
+	httpRes.Body.Close()
+	assert.Equal(t, http.StatusConflict, httpRes.StatusCode, "expected dependencies in the old repo to block the move")
+	assert.Equal(t, f.Repo.ID.String(), f.readTask(tsk.ID).RepoID)
+
+	req.DropDependencies = true
+	res := testutil.Post[server.Response[task.Task]](t, f.taskActionURL(tsk.ID, "move"), req)
+	assert.Equal(t, target.ID.String(), res.Data.RepoID)
+	assert.Empty(t, res.Data.DependsOn)
+	assert.Empty(t, f.readTask(dependent.ID).DependsOn, "expected the dependent to drop the moved task")
+}
+
+func TestMoveTask_NotPending(t *testing.T) {
+	f := newFixture(t)
+	target := f.seedReadyRepo("owner/frontend")
+	tsk := f.seedRunningTask("title", "desc")
+
+	req := verveclient.MoveTaskRequest{RepoID: target.ID.String()}
+	httpRes := doJSON(t, http.MethodPost, f.taskActionURL(tsk.ID, "move"), req)
+	httpRes.Body.Close()
+	assert.Equal(t, http.StatusConflict, httpRes.StatusCode)
+	assert.Equal(t, f.Repo.ID.String(), f.readTask(tsk.ID).RepoID)
+}
+
 // --- GetTask ---
 
 func TestGetTask_Success(t *testing.T) {
@@ -190,11 +440,69 @@ func TestCloseTask_Success(t *testing.T) {
 	f := newFixture(t)
 	tsk := f.seedRunningTask("title", "desc")
 
-	req := taskapi.CloseRequest{Reason: "no longer needed"}
+	req := verveclient.CloseRequest{Reason: "no longer needed"}
 	res := testutil.Post[server.Response[task.Task]](t, f.taskActionURL(tsk.ID, "close"), req)
 	assert.Equal(t, task.StatusClosed, res.Data.Status)
 }
 
+// --- ApproveTask / MergeTask ---
+
+func TestApproveTask_NotInReview(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedRunningTask("title", "desc")
+
+	httpRes := doJSON(t, http.MethodPost, f.taskActionURL(tsk.ID, "approve"), verveclient.ApproveTaskRequest{})
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusConflict, httpRes.StatusCode)
+}
+
+func TestMergeTask_GitHubNotConfigured(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	tsk := f.seedTask("title", "desc")
+	require.NoError(t, f.TaskRepo.SetTaskPullRequest(ctx, tsk.ID, "https://github.com/org/repo/pull/10", 10))
+	require.NoError(t, f.TaskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusReview))
+
+	httpRes := doJSON(t, http.MethodPost, f.taskActionURL(tsk.ID, "merge"), verveclient.MergeTaskRequest{})
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, httpRes.StatusCode)
+	assert.Equal(t, task.StatusReview, f.readTask(tsk.ID).Status)
+}
+
+func TestMergeTask_InvalidMergeMethod(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedTask("title", "desc")
+
+	httpRes := doJSON(t, http.MethodPost, f.taskActionURL(tsk.ID, "merge"), verveclient.MergeTaskRequest{MergeMethod: "fast-forward"})
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+}
+
+// --- RerunTaskChecks ---
+
+func TestRerunTaskChecks_NotInReview(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedRunningTask("title", "desc")
+
+	httpRes := doJSON(t, http.MethodPost, f.taskActionURL(tsk.ID, "checks/rerun"), nil)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusConflict, httpRes.StatusCode)
+}
+
+func TestRerunTaskChecks_GitHubNotConfigured(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	tsk := f.seedTask("title", "desc")
+	require.NoError(t, f.TaskRepo.SetTaskPullRequest(ctx, tsk.ID, "https://github.com/org/repo/pull/10", 10))
+	require.NoError(t, f.TaskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusReview))
+
+	httpRes := doJSON(t, http.MethodPost, f.taskActionURL(tsk.ID, "checks/rerun"), nil)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, httpRes.StatusCode)
+}
+
 // --- MoveToReview ---
 
 func TestMoveToReview_FailedWithPR(t *testing.T) {
@@ -280,7 +588,7 @@ func TestFeedbackTask_EmptyFeedback(t *testing.T) {
 	f := newFixture(t)
 	tsk := f.seedTask("title", "desc")
 
-	req := taskapi.FeedbackRequest{Feedback: ""}
+	req := verveclient.FeedbackRequest{Feedback: ""}
 	httpRes := doJSON(t, http.MethodPost, f.taskActionURL(tsk.ID, "feedback"), req)
 	defer httpRes.Body.Close()
 	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode, "expected validation error for empty feedback")
@@ -297,7 +605,7 @@ func TestRemoveDependency_Success(t *testing.T) {
 	tsk := task.NewTask(f.Repo.ID.String(), "title", "desc", []string{dep.ID.String()}, nil, 0, false, false, "sonnet", true)
 	require.NoError(t, f.TaskRepo.CreateTask(ctx, tsk))
 
-	req := taskapi.RemoveDependencyRequest{DependsOn: dep.ID.String()}
+	req := verveclient.RemoveDependencyRequest{DependsOn: dep.ID.String()}
 	// RemoveDependency uses DELETE with a JSON body, so use doJSON.
 	httpRes := doJSON(t, http.MethodDelete, f.taskActionURL(tsk.ID, "dependency"), req)
 	defer httpRes.Body.Close()
@@ -310,7 +618,7 @@ func TestRemoveDependency_Success(t *testing.T) {
 func TestRemoveDependency_InvalidTaskID(t *testing.T) {
 	f := newFixture(t)
 
-	req := taskapi.RemoveDependencyRequest{DependsOn: "tsk_abc"}
+	req := verveclient.RemoveDependencyRequest{DependsOn: "tsk_abc"}
 	url := f.Server.Address() + "/api/v1/tasks/invalid/dependency"
 	httpRes := doJSON(t, http.MethodDelete, url, req)
 	defer httpRes.Body.Close()
@@ -321,7 +629,7 @@ func TestRemoveDependency_EmptyDependsOn(t *testing.T) {
 	f := newFixture(t)
 	tsk := f.seedTask("title", "desc")
 
-	req := taskapi.RemoveDependencyRequest{DependsOn: ""}
+	req := verveclient.RemoveDependencyRequest{DependsOn: ""}
 	httpRes := doJSON(t, http.MethodDelete, f.taskActionURL(tsk.ID, "dependency"), req)
 	defer httpRes.Body.Close()
 	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode, "expected validation error for empty depends_on")
@@ -382,6 +690,74 @@ func TestDeleteTask_WithPullRequest(t *testing.T) {
 	assert.Error(t, readErr, "expected task to be deleted")
 }
 
+func TestDeleteTask_TrashAndRestore(t *testing.T) {
+	f := newFixture(t)
+	f.TaskStore.SetTrashRetention(time.Hour)
+	ctx := context.Background()
+
+	tsk := f.seedTask("title", "desc")
+	require.NoError(t, f.TaskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusFailed))
+	require.NoError(t, f.TaskRepo.AppendTaskLogs(ctx, tsk.ID, 1, []string{"error log line"}))
+
+	testutil.Delete(t, f.taskURL(tsk.ID))
+
+	httpRes := doJSON(t, http.MethodGet, f.taskURL(tsk.ID), nil)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusNotFound, httpRes.StatusCode, "expected trashed task to be hidden")
+	list := testutil.Get[server.ResponseList[task.Task]](t, f.repoTasksURL())
+	assert.Empty(t, list.Data)
+
+	trash := testutil.Get[server.ResponseList[task.Task]](t, f.repoTasksURL()+"/trash")
+	require.Len(t, trash.Data, 1)
+	assert.Equal(t, tsk.ID, trash.Data[0].ID)
+	assert.NotNil(t, trash.Data[0].DeletedAt)
+
+	res := testutil.Post[server.Response[task.Task]](t, f.taskActionURL(tsk.ID, "restore"), nil)
+	assert.Equal(t, task.StatusFailed, res.Data.Status)
+	assert.Nil(t, res.Data.DeletedAt)
+	logs, err := f.TaskRepo.ReadTaskLogs(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"error log line"}, logs, "expected logs to survive the trash")
+
+	httpRes = doJSON(t, http.MethodPost, f.taskActionURL(tsk.ID, "restore"), nil)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusNotFound, httpRes.StatusCode, "expected restoring a task outside the trash to fail")
+}
+
+func TestListTasks_ArchivedAndUnarchive(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	tsk := f.seedTask("title", "desc")
+	require.NoError(t, f.TaskRepo.UpdateTaskStatus(ctx, tsk.ID, task.StatusMerged))
+	require.NoError(t, f.TaskRepo.AppendTaskLogs(ctx, tsk.ID, 1, []string{"log line"}))
+	archived, err := f.TaskRepo.ArchiveTask(ctx, tsk.ID)
+	require.NoError(t, err)
+	require.True(t, archived)
+
+	list := testutil.Get[server.ResponseList[task.Task]](t, f.repoTasksURL())
+	assert.Empty(t, list.Data, "expected archived task to be hidden")
+	list = testutil.Get[server.ResponseList[task.Task]](t, f.repoTasksURL()+"?include_archived=true")
+	require.Len(t, list.Data, 1)
+	assert.NotNil(t, list.Data[0].ArchivedAt)
+	logs, err := f.TaskRepo.ReadTaskLogs(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Empty(t, logs, "expected logs to move to the archive")
+
+	res := testutil.Post[server.Response[task.Task]](t, f.taskActionURL(tsk.ID, "unarchive"), nil)
+	assert.Equal(t, task.StatusMerged, res.Data.Status)
+	assert.Nil(t, res.Data.ArchivedAt)
+	logs, err = f.TaskRepo.ReadTaskLogs(ctx, tsk.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"log line"}, logs, "expected logs to be restored")
+	list = testutil.Get[server.ResponseList[task.Task]](t, f.repoTasksURL())
+	assert.Len(t, list.Data, 1)
+
+	httpRes := doJSON(t, http.MethodPost, f.taskActionURL(tsk.ID, "unarchive"), nil)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusConflict, httpRes.StatusCode, "expected unarchiving a task outside the archive to fail")
+}
+
 func TestBulkDeleteTasks_WithPullRequests(t *testing.T) {
 	f := newFixture(t)
 	ctx := context.Background()
@@ -392,7 +768,7 @@ func TestBulkDeleteTasks_WithPullRequests(t *testing.T) {
 
 	tsk2 := f.seedTask("task2", "desc2")
 
-	req := taskapi.BulkDeleteTasksRequest{
+	req := verveclient.BulkDeleteTasksRequest{
 		TaskIDs: []string{tsk1.ID.String(), tsk2.ID.String()},
 	}
 	postNoContent(t, f.Server.Address()+"/api/v1/tasks/bulk-delete", req)
@@ -404,13 +780,135 @@ func TestBulkDeleteTasks_WithPullRequests(t *testing.T) {
 	assert.Error(t, err, "expected task2 to be deleted")
 }
 
+// --- Failed tasks ---
+
+func TestListFailedTasks(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	ciFailed := f.seedTask("ci", "desc")
+	require.NoError(t, f.TaskStore.FailTask(ctx, ciFailed.ID, "ci_failure"))
+	timedOut := f.seedTask("timeout", "desc")
+	require.NoError(t, f.TaskStore.FailTask(ctx, timedOut.ID, task.FailureWorkerTimeout))
+	f.seedTask("pending", "desc")
+
+	res := testutil.Get[server.Response[taskapi.FailedTasksResponse]](t, f.repoTasksURL()+"/failed")
+	assert.Len(t, res.Data.Tasks, 2)
+	assert.ElementsMatch(t, []task.FailureCategoryCount{
+		{Category: "ci_failure", Count: 1},
+		{Category: task.FailureWorkerTimeout, Count: 1},
+	}, res.Data.Categories)
+
+	res = testutil.Get[server.Response[taskapi.FailedTasksResponse]](t, f.repoTasksURL()+"/failed?category=ci_failure")
+	require.Len(t, res.Data.Tasks, 1)
+	assert.Equal(t, ciFailed.ID, res.Data.Tasks[0].ID)
+	assert.Equal(t, "ci_failure", res.Data.Tasks[0].FailureCategory)
+	assert.Len(t, res.Data.Categories, 2, "counts cover every failed task")
+}
+
+func TestBulkRetryTasks(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	failed := f.seedTask("failed", "desc")
+	require.NoError(t, f.TaskStore.FailTask(ctx, failed.ID, task.FailureAgentError))
+	pending := f.seedTask("pending", "desc")
+
+	req := verveclient.BulkRetryTasksRequest{
+		TaskIDs:      []string{failed.ID.String(), pending.ID.String()},
+		Instructions: "the API was down, try again",
+	}
+	res := testutil.Post[server.Response[taskapi.BulkRetryTasksResponse]](t, f.Server.Address()+"/api/v1/tasks/bulk-retry", req)
+	assert.Equal(t, []string{failed.ID.String()}, res.Data.Retried)
+	assert.Equal(t, []string{pending.ID.String()}, res.Data.Skipped)
+
+	read := f.readTask(failed.ID)
+	assert.Equal(t, task.StatusPending, read.Status)
+	assert.Equal(t, "the API was down, try again", read.RetryReason)
+}
+
+func TestBulkRetryTasks_InvalidIDs(t *testing.T) {
+	f := newFixture(t)
+
+	for _, ids := range [][]string{nil, {"not-a-task-id"}} {
+		httpRes := doJSON(t, http.MethodPost, f.Server.Address()+"/api/v1/tasks/bulk-retry", verveclient.BulkRetryTasksRequest{TaskIDs: ids})
+		httpRes.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+	}
+}
+
+func TestBulkTaskAction(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	failed := f.seedTask("failed", "desc")
+	require.NoError(t, f.TaskStore.FailTask(ctx, failed.ID, task.FailureAgentError))
+	pending := f.seedTask("pending", "desc")
+
+	req := verveclient.BulkTaskActionRequest{
+		Action:  verveclient.BulkActionClose,
+		TaskIDs: []string{failed.ID.String(), pending.ID.String(), task.NewTaskID().String()},
+		Reason:  "abandoned sprint",
+	}
+	res := testutil.Post[server.Response[taskapi.BulkTaskActionResponse]](t, f.repoTasksURL()+"/bulk-action", req)
+	require.Len(t, res.Data.Results, 3)
+	assert.Equal(t, "applied", res.Data.Results[0].Result)
+	assert.Equal(t, "applied", res.Data.Results[1].Result)
+	assert.Equal(t, "skipped", res.Data.Results[2].Result, "unknown tasks are skipped")
+	assert.NotEmpty(t, res.Data.Results[2].Reason)
+
+	read := f.readTask(pending.ID)
+	assert.Equal(t, task.StatusClosed, read.Status)
+	assert.Equal(t, "abandoned sprint", read.CloseReason)
+
+	// Closing again skips the now closed tasks.
+	res = testutil.Post[server.Response[taskapi.BulkTaskActionResponse]](t, f.repoTasksURL()+"/bulk-action", req)
+	assert.Equal(t, "skipped", res.Data.Results[0].Result)
+}
+
+func TestBulkTaskAction_StatusFilter(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	failed := f.seedTask("failed", "desc")
+	require.NoError(t, f.TaskStore.FailTask(ctx, failed.ID, task.FailureAgentError))
+	pending := f.seedTask("pending", "desc")
+
+	req := verveclient.BulkTaskActionRequest{Action: verveclient.BulkActionDelete, Status: string(task.StatusFailed)}
+	res := testutil.Post[server.Response[taskapi.BulkTaskActionResponse]](t, f.repoTasksURL()+"/bulk-action", req)
+	require.Len(t, res.Data.Results, 1)
+	assert.Equal(t, failed.ID.String(), res.Data.Results[0].TaskID)
+	assert.Equal(t, "applied", res.Data.Results[0].Result)
+
+	_, err := f.TaskRepo.ReadTask(ctx, failed.ID)
+	assert.Error(t, err, "expected the failed task to be deleted")
+	assert.Equal(t, task.StatusPending, f.readTask(pending.ID).Status)
+}
+
+func TestBulkTaskAction_Invalid(t *testing.T) {
+	f := newFixture(t)
+
+	id := task.NewTaskID().String()
+	for _, req := range []verveclient.BulkTaskActionRequest{
+		{Action: "archive", TaskIDs: []string{id}},
+		{Action: verveclient.BulkActionRetry},
+		{Action: verveclient.BulkActionRetry, TaskIDs: []string{id}, Status: string(task.StatusFailed)},
+		{Action: verveclient.BulkActionRetry, Status: "stuck"},
+		{Action: verveclient.BulkActionRetry, TaskIDs: []string{"not-a-task-id"}},
+	} {
+		httpRes := doJSON(t, http.MethodPost, f.repoTasksURL()+"/bulk-action", req)
+		httpRes.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode, "%+v", req)
+	}
+}
+
 // --- GetTaskByNumber ---
 
 func TestGetTaskByNumber_Success(t *testing.T) {
 	f := newFixture(t)
 
 	// Create a task via the API so it gets a number assigned.
-	createReq := taskapi.CreateTaskRequest{
+	createReq := verveclient.CreateTaskRequest{
 		Title:       "By number test",
 		Description: "desc",
 	}
@@ -471,7 +969,7 @@ func TestGetTaskByNumber_InvalidRepoID(t *testing.T) {
 func TestCreateTask_HasNumber(t *testing.T) {
 	f := newFixture(t)
 
-	req := taskapi.CreateTaskRequest{
+	req := verveclient.CreateTaskRequest{
 		Title:       "First task",
 		Description: "desc",
 	}
@@ -492,3 +990,659 @@ func TestListTasksByRepo_TasksHaveNumbers(t *testing.T) {
 	}
 }
 
+
+// --- Attempts ---
+
+// runAttempt claims the task, appends a log line and ends the attempt with a
+// retryable failure so the task is requeued for the next attempt.
+func (f *fixture) runAttempt(tsk *task.Task, attempt int, line, retryReason string) {
+	f.t.Helper()
+	ctx := context.Background()
+	claimed, err := f.TaskStore.ClaimPendingTask(ctx, nil, nil)
+	require.NoError(f.t, err)
+	require.NotNil(f.t, claimed)
+	require.NoError(f.t, f.TaskStore.AppendTaskLogs(ctx, tsk.ID, attempt, []string{line}))
+	require.NoError(f.t, f.TaskStore.AddCost(ctx, tsk.ID, 0.25))
+	if retryReason != "" {
+		require.NoError(f.t, f.TaskStore.ScheduleRetry(ctx, tsk.ID, retryReason))
+	}
+}
+
+func TestListAttempts(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedTask("Flaky task", "desc")
+
+	f.runAttempt(tsk, 1, "first", "ci_failure: tests failed")
+	f.runAttempt(tsk, 2, "second", "")
+
+	res := testutil.Get[server.ResponseList[task.Attempt]](t, f.taskActionURL(tsk.ID, "attempts"))
+	require.Len(t, res.Data, 2)
+
+	first := res.Data[0]
+	assert.Equal(t, 1, first.Number)
+	assert.Equal(t, task.AttemptResultRetried, first.Result)
+	assert.InDelta(t, 0.25, first.CostUSD, 0.001)
+	assert.NotNil(t, first.EndedAt)
+
+	second := res.Data[1]
+	assert.Equal(t, 2, second.Number)
+	assert.Equal(t, "ci_failure: tests failed", second.RetryReason)
+	assert.Empty(t, second.Result, "expected running attempt to have no result")
+	assert.Nil(t, second.EndedAt)
+}
+
+func TestListAttempts_NotFound(t *testing.T) {
+	f := newFixture(t)
+
+	httpRes := doJSON(t, http.MethodGet, f.taskActionURL(task.NewTaskID(), "attempts"), nil)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusNotFound, httpRes.StatusCode)
+}
+
+func TestListEvents(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedTask("Flaky task", "desc")
+	ctx := context.Background()
+
+	f.runAttempt(tsk, 1, "first", "ci_failure: tests failed")
+	require.NoError(t, f.TaskStore.AppendAgentEvents(ctx, tsk.ID, 1, []*task.AgentEvent{
+		{Type: task.AgentEventToolCall, Payload: json.RawMessage(`{"name":"Read"}`)},
+	}))
+	f.runAttempt(tsk, 2, "second", "")
+	require.NoError(t, f.TaskStore.AppendAgentEvents(ctx, tsk.ID, 2, []*task.AgentEvent{
+		{Type: task.AgentEventPRCreated, Payload: json.RawMessage(`{"url":"https://github.com/owner/repo/pull/7","number":7}`)},
+	}))
+
+	res := testutil.Get[server.ResponseList[task.AgentEvent]](t, f.taskActionURL(tsk.ID, "events"))
+	require.Len(t, res.Data, 2)
+	assert.Equal(t, task.AgentEventToolCall, res.Data[0].Type)
+	assert.Equal(t, 1, res.Data[0].Attempt)
+	assert.Equal(t, task.AgentEventPRCreated, res.Data[1].Type)
+	assert.JSONEq(t, `{"url":"https://github.com/owner/repo/pull/7","number":7}`, string(res.Data[1].Payload))
+
+	res = testutil.Get[server.ResponseList[task.AgentEvent]](t, f.taskActionURL(tsk.ID, "events?attempt=2"))
+	require.Len(t, res.Data, 1)
+	assert.Equal(t, 2, res.Data[0].Attempt)
+}
+
+func TestListEvents_NotFound(t *testing.T) {
+	f := newFixture(t)
+
+	httpRes := doJSON(t, http.MethodGet, f.taskActionURL(task.NewTaskID(), "events"), nil)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusNotFound, httpRes.StatusCode)
+}
+
+func TestGetProgress(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedTask("Task", "desc")
+	ctx := context.Background()
+
+	res := testutil.Get[server.Response[task.Progress]](t, f.taskActionURL(tsk.ID, "progress"))
+	assert.Empty(t, res.Data.Steps)
+
+	f.runAttempt(tsk, 1, "first", "")
+	require.NoError(t, f.TaskStore.AppendAgentEvents(ctx, tsk.ID, 1, []*task.AgentEvent{
+		{Type: task.AgentEventPlan, Payload: json.RawMessage(`{"steps":["Read code","Fix bug","Run tests"]}`)},
+		{Type: task.AgentEventStep, Payload: json.RawMessage(`{"index":0,"status":"started"}`)},
+		{Type: task.AgentEventStep, Payload: json.RawMessage(`{"index":0,"status":"completed"}`)},
+		{Type: task.AgentEventStep, Payload: json.RawMessage(`{"index":1,"status":"started"}`)},
+	}))
+
+	res = testutil.Get[server.Response[task.Progress]](t, f.taskActionURL(tsk.ID, "progress"))
+	assert.Equal(t, 1, res.Data.Attempt)
+	require.Len(t, res.Data.Steps, 3)
+	assert.Equal(t, "Read code", res.Data.Steps[0].Title)
+	assert.Equal(t, task.StepCompleted, res.Data.Steps[0].Status)
+	assert.Equal(t, task.StepInProgress, res.Data.Steps[1].Status)
+	assert.NotNil(t, res.Data.Steps[1].StartedAt)
+	assert.Equal(t, task.StepPending, res.Data.Steps[2].Status)
+}
+
+func TestGetProgress_NotFound(t *testing.T) {
+	f := newFixture(t)
+
+	httpRes := doJSON(t, http.MethodGet, f.taskActionURL(task.NewTaskID(), "progress"), nil)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusNotFound, httpRes.StatusCode)
+}
+
+func TestProvenanceReview(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedTask("Task", "desc")
+	ctx := context.Background()
+
+	res := testutil.Get[server.Response[*task.ProvenanceReview]](t, f.taskActionURL(tsk.ID, "provenance"))
+	assert.Nil(t, res.Data)
+
+	httpRes := doJSON(t, http.MethodPost, f.taskActionURL(tsk.ID, "provenance/acknowledge"), nil)
+	httpRes.Body.Close()
+	assert.Equal(t, http.StatusNotFound, httpRes.StatusCode)
+
+	require.NoError(t, f.TaskStore.RecordProvenanceReview(ctx, tsk.ID, &task.ProvenanceReview{
+		Findings:  []provenance.Finding{{Kind: provenance.KindLicenseHeader, Path: "util.go", Line: 1, Detail: "SPDX license identifier"}},
+		HeadSHA:   "abc123",
+		ScannedAt: time.Now(),
+	}))
+
+	res = testutil.Get[server.Response[*task.ProvenanceReview]](t, f.taskActionURL(tsk.ID, "provenance"))
+	require.NotNil(t, res.Data)
+	require.Len(t, res.Data.Findings, 1)
+	assert.Equal(t, "abc123", res.Data.HeadSHA)
+	assert.True(t, res.Data.NeedsAcknowledgment())
+
+	res = testutil.Post[server.Response[*task.ProvenanceReview]](t, f.taskActionURL(tsk.ID, "provenance/acknowledge"), nil)
+	require.NotNil(t, res.Data)
+	assert.NotNil(t, res.Data.AcknowledgedAt)
+	assert.False(t, res.Data.NeedsAcknowledgment())
+}
+
+func TestProvenanceReview_NotFound(t *testing.T) {
+	f := newFixture(t)
+
+	httpRes := doJSON(t, http.MethodGet, f.taskActionURL(task.NewTaskID(), "provenance"), nil)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusNotFound, httpRes.StatusCode)
+}
+
+func TestSelfReview(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedTask("Task", "desc")
+	ctx := context.Background()
+
+	res := testutil.Get[server.Response[*task.SelfReview]](t, f.taskActionURL(tsk.ID, "self-review"))
+	assert.Nil(t, res.Data)
+
+	require.NoError(t, f.TaskStore.RecordSelfReview(ctx, tsk.ID, &task.SelfReview{
+		Score:      80,
+		Checks:     []selfreview.Check{{Name: selfreview.CheckFileCount, Passed: true, Detail: "3 files changed"}},
+		HeadSHA:    "abc123",
+		CommentID:  42,
+		ReviewedAt: time.Now(),
+	}))
+
+	res = testutil.Get[server.Response[*task.SelfReview]](t, f.taskActionURL(tsk.ID, "self-review"))
+	require.NotNil(t, res.Data)
+	assert.Equal(t, 80, res.Data.Score)
+	require.Len(t, res.Data.Checks, 1)
+	assert.Equal(t, selfreview.CheckFileCount, res.Data.Checks[0].Name)
+	assert.Equal(t, "abc123", res.Data.HeadSHA)
+	assert.Zero(t, res.Data.CommentID)
+}
+
+func TestShadowDiff(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedRunningTask("Task", "desc")
+	ctx := context.Background()
+
+	res := testutil.Get[server.Response[*task.ShadowDiff]](t, f.taskActionURL(tsk.ID, "shadow-diff"))
+	assert.Nil(t, res.Data)
+
+	patch := "diff --git a/main.go b/main.go\n+package main\n"
+	require.NoError(t, f.TaskStore.RecordShadowDiff(ctx, tsk.ID, patch))
+
+	res = testutil.Get[server.Response[*task.ShadowDiff]](t, f.taskActionURL(tsk.ID, "shadow-diff"))
+	require.NotNil(t, res.Data)
+	assert.Equal(t, patch, res.Data.Diff)
+
+	// The task has no PR, so its diff is the shadow diff.
+	diff := testutil.Get[server.Response[taskapi.DiffResponse]](t, f.taskActionURL(tsk.ID, "diff"))
+	assert.Equal(t, patch, diff.Data.Diff)
+}
+
+func TestProposal(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedRunningTask("Task", "desc")
+	ctx := context.Background()
+	require.NoError(t, f.TaskRepo.SetTaskPlanOnly(ctx, tsk.ID, true))
+
+	res := testutil.Get[server.Response[*task.Proposal]](t, f.taskActionURL(tsk.ID, "proposal"))
+	assert.Nil(t, res.Data)
+
+	require.NoError(t, f.TaskStore.RecordProposal(ctx, tsk.ID, &task.Proposal{Summary: "Add caching", Approach: "Wrap the client"}))
+
+	res = testutil.Get[server.Response[*task.Proposal]](t, f.taskActionURL(tsk.ID, "proposal"))
+	require.NotNil(t, res.Data)
+	assert.Equal(t, "Add caching", res.Data.Summary)
+
+	approved := testutil.Post[server.Response[task.Task]](t, f.taskActionURL(tsk.ID, "proposal/approve"), nil)
+	assert.Equal(t, task.StatusPending, approved.Data.Status)
+	assert.False(t, approved.Data.PlanOnly)
+
+	// An approved proposal can't be approved again.
+	httpRes := doJSON(t, http.MethodPost, f.taskActionURL(tsk.ID, "proposal/approve"), nil)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusConflict, httpRes.StatusCode)
+}
+
+func TestNudgeTask(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedRunningTask("Task", "desc")
+
+	res := testutil.Post[server.Response[task.Nudge]](t, f.taskActionURL(tsk.ID, "nudge"), verveclient.NudgeRequest{Message: "Use the v2 API instead"})
+	assert.Equal(t, "Use the v2 API instead", res.Data.Message)
+	assert.Nil(t, res.Data.DeliveredAt)
+
+	list := testutil.Get[server.ResponseList[task.Nudge]](t, f.taskActionURL(tsk.ID, "nudges"))
+	require.Len(t, list.Data, 1)
+	assert.Equal(t, res.Data.ID, list.Data[0].ID)
+}
+
+func TestNudgeTask_NotRunning(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedTask("Task", "desc")
+
+	httpRes := doJSON(t, http.MethodPost, f.taskActionURL(tsk.ID, "nudge"), verveclient.NudgeRequest{Message: "hello"})
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusConflict, httpRes.StatusCode)
+}
+
+func TestNudgeTask_EmptyMessage(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedRunningTask("Task", "desc")
+
+	httpRes := doJSON(t, http.MethodPost, f.taskActionURL(tsk.ID, "nudge"), verveclient.NudgeRequest{Message: "  "})
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+}
+
+func TestTaskComments(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedTask("Task", "desc")
+	commentsURL := f.taskActionURL(tsk.ID, "comments")
+
+	attempt := 1
+	res := testutil.Post[server.Response[task.Comment]](t, commentsURL, verveclient.CreateCommentRequest{Body: "Needs a test", Attempt: &attempt, Author: "alice"})
+	assert.Equal(t, "alice", res.Data.Author)
+	assert.Equal(t, "Needs a test", res.Data.Body)
+	require.NotNil(t, res.Data.Attempt)
+	assert.Equal(t, 1, *res.Data.Attempt)
+	commentURL := commentsURL + "/" + strconv.FormatInt(res.Data.ID, 10)
+
+	got := testutil.Get[server.Response[task.Task]](t, f.taskURL(tsk.ID))
+	assert.Equal(t, 1, got.Data.CommentCount)
+	tasks := testutil.Get[server.ResponseList[task.Task]](t, f.repoTasksURL())
+	require.Len(t, tasks.Data, 1)
+	assert.Equal(t, 1, tasks.Data[0].CommentCount)
+
+	httpRes := doJSON(t, http.MethodPatch, commentURL, verveclient.UpdateCommentRequest{Body: "Needs two tests"})
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusOK, httpRes.StatusCode)
+	list := testutil.Get[server.ResponseList[task.Comment]](t, commentsURL)
+	require.Len(t, list.Data, 1)
+	assert.Equal(t, "Needs two tests", list.Data[0].Body)
+
+	testutil.Delete(t, commentURL)
+	list = testutil.Get[server.ResponseList[task.Comment]](t, commentsURL)
+	assert.Empty(t, list.Data)
+
+	httpRes = doJSON(t, http.MethodDelete, commentURL, nil)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusNotFound, httpRes.StatusCode)
+}
+
+func TestCreateComment_Invalid(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedTask("Task", "desc")
+	commentsURL := f.taskActionURL(tsk.ID, "comments")
+
+	httpRes := doJSON(t, http.MethodPost, commentsURL, verveclient.CreateCommentRequest{Body: "  "})
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+
+	attempt := 2
+	httpRes = doJSON(t, http.MethodPost, commentsURL, verveclient.CreateCommentRequest{Body: "hi", Attempt: &attempt})
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusNotFound, httpRes.StatusCode, "expected a comment on an attempt that hasn't started to fail")
+}
+
+// uploadAttachment posts a file to a task's attachments as multipart form
+// data.
+func uploadAttachment(t *testing.T, url, filename string, data []byte) *http.Response {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("file", filename)
+	require.NoError(t, err)
+	_, err = part.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	req, err := http.NewRequest(http.MethodPost, url, &body)
+	require.NoError(t, err)
+	req.Header.Set(echo.HeaderContentType, w.FormDataContentType())
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return res
+}
+
+func TestTaskAttachments(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedTask("Task", "desc")
+	attachmentsURL := f.taskActionURL(tsk.ID, "attachments")
+
+	httpRes := uploadAttachment(t, attachmentsURL, "error.log", []byte("panic: nil map\n"))
+	defer httpRes.Body.Close()
+	require.Equal(t, http.StatusCreated, httpRes.StatusCode)
+	var created server.Response[task.Attachment]
+	require.NoError(t, json.NewDecoder(httpRes.Body).Decode(&created))
+	assert.Equal(t, "error.log", created.Data.Filename)
+	assert.Equal(t, "text/plain", created.Data.ContentType)
+	assert.Equal(t, int64(15), created.Data.SizeBytes)
+	assert.Equal(t, task.AnonymousAuthor, created.Data.UploadedBy)
+	attachmentURL := attachmentsURL + "/" + strconv.FormatInt(created.Data.ID, 10)
+
+	list := testutil.Get[server.ResponseList[task.Attachment]](t, attachmentsURL)
+	require.Len(t, list.Data, 1)
+	assert.Equal(t, "error.log", list.Data[0].Filename)
+
+	httpRes = uploadAttachment(t, attachmentsURL, "error.log", []byte("again\n"))
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusConflict, httpRes.StatusCode, "expected a duplicate filename to conflict")
+
+	download, err := http.Get(attachmentURL)
+	require.NoError(t, err)
+	defer download.Body.Close()
+	require.Equal(t, http.StatusOK, download.StatusCode)
+	assert.Equal(t, `attachment; filename=error.log`, download.Header.Get(echo.HeaderContentDisposition))
+	assert.Equal(t, "nosniff", download.Header.Get("X-Content-Type-Options"))
+	b, err := io.ReadAll(download.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "panic: nil map\n", string(b))
+
+	testutil.Delete(t, attachmentURL)
+	list = testutil.Get[server.ResponseList[task.Attachment]](t, attachmentsURL)
+	assert.Empty(t, list.Data)
+
+	httpRes = doJSON(t, http.MethodGet, attachmentURL, nil)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusNotFound, httpRes.StatusCode)
+}
+
+func TestTaskArtifacts(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedRunningTask("Task", "desc")
+	png := []byte("\x89PNG\r\n\x1a\n")
+	created, err := f.TaskStore.CreateArtifact(context.Background(), tsk.ID, &task.Artifact{Attempt: 1, Name: "screenshots/home.png"}, png)
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", created.ContentType)
+	artifactsURL := f.taskActionURL(tsk.ID, "artifacts")
+
+	list := testutil.Get[server.ResponseList[task.Artifact]](t, artifactsURL)
+	require.Len(t, list.Data, 1)
+	assert.Equal(t, "screenshots/home.png", list.Data[0].Name)
+	assert.Equal(t, 1, list.Data[0].Attempt)
+
+	list = testutil.Get[server.ResponseList[task.Artifact]](t, artifactsURL+"?attempt=2")
+	assert.Empty(t, list.Data)
+
+	download, err := http.Get(artifactsURL + "/" + strconv.FormatInt(created.ID, 10))
+	require.NoError(t, err)
+	defer download.Body.Close()
+	require.Equal(t, http.StatusOK, download.StatusCode)
+	assert.Equal(t, "image/png", download.Header.Get(echo.HeaderContentType))
+	assert.Equal(t, `attachment; filename=home.png`, download.Header.Get(echo.HeaderContentDisposition))
+	b, err := io.ReadAll(download.Body)
+	require.NoError(t, err)
+	assert.Equal(t, png, b)
+
+	httpRes := doJSON(t, http.MethodGet, artifactsURL+"/999", nil)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusNotFound, httpRes.StatusCode)
+}
+
+func TestUploadAttachment_Invalid(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedTask("Task", "desc")
+	attachmentsURL := f.taskActionURL(tsk.ID, "attachments")
+
+	httpRes := uploadAttachment(t, attachmentsURL, "tool.exe", []byte("MZ"))
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusUnsupportedMediaType, httpRes.StatusCode)
+
+	httpRes = uploadAttachment(t, attachmentsURL, "screenshot.png", []byte("not a png"))
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusUnsupportedMediaType, httpRes.StatusCode, "expected contents that don't match the extension to be rejected")
+
+	httpRes = uploadAttachment(t, attachmentsURL, ".env", []byte("SECRET=1"))
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+
+	httpRes = uploadAttachment(t, attachmentsURL, "big.txt", bytes.Repeat([]byte("a"), task.DefaultMaxAttachmentSize+1))
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusRequestEntityTooLarge, httpRes.StatusCode)
+}
+
+func TestListAndConvertNotes(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	tsk := f.seedRunningTask("Fix login", "desc")
+
+	require.NoError(t, f.TaskStore.AppendAgentEvents(ctx, tsk.ID, 1, []*task.AgentEvent{
+		{Type: task.AgentEventNote, Payload: json.RawMessage(`{"text":"The session module needs refactoring"}`)},
+	}))
+
+	list := testutil.Get[server.ResponseList[task.Note]](t, f.repoNotesURL())
+	require.Len(t, list.Data, 1)
+	note := list.Data[0]
+	assert.Equal(t, tsk.ID.String(), note.TaskID)
+	assert.Equal(t, "The session module needs refactoring", note.Text)
+
+	convertURL := f.convertNoteURL(strconv.FormatInt(note.ID, 10))
+	res := testutil.Post[server.Response[task.Task]](t, convertURL, nil)
+	assert.Equal(t, "The session module needs refactoring", res.Data.Title)
+	assert.Equal(t, task.StatusPending, res.Data.Status)
+	assert.Equal(t, "sonnet", res.Data.Model)
+
+	list = testutil.Get[server.ResponseList[task.Note]](t, f.repoNotesURL())
+	require.Len(t, list.Data, 1)
+	assert.Equal(t, res.Data.ID.String(), list.Data[0].ConvertedTaskID)
+
+	httpRes := doJSON(t, http.MethodPost, convertURL, nil)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusConflict, httpRes.StatusCode, "a note can only be converted once")
+}
+
+func TestConvertNote_NotFound(t *testing.T) {
+	f := newFixture(t)
+
+	httpRes := doJSON(t, http.MethodPost, f.convertNoteURL("999"), nil)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusNotFound, httpRes.StatusCode)
+
+	httpRes = doJSON(t, http.MethodPost, f.convertNoteURL("abc"), nil)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+}
+
+func TestStreamAttemptLogs(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedTask("Flaky task", "desc")
+
+	f.runAttempt(tsk, 1, "first", "ci_failure: tests failed")
+	f.runAttempt(tsk, 2, "second", "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.taskActionURL(tsk.ID, "attempts/1/logs"), http.NoBody)
+	require.NoError(t, err)
+	httpRes, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer httpRes.Body.Close()
+	require.Equal(t, http.StatusOK, httpRes.StatusCode)
+
+	var data []string
+	scanner := bufio.NewScanner(httpRes.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "event: logs_done" {
+			break
+		}
+		if d, ok := strings.CutPrefix(line, "data: "); ok {
+			data = append(data, d)
+		}
+	}
+	require.Len(t, data, 1, "expected only the first attempt's logs")
+	assert.Contains(t, data[0], `"logs":["first"]`)
+	assert.Contains(t, data[0], `"attempt":1`)
+}
+
+func TestStreamAttemptLogs_InvalidAttempt(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedTask("Task", "desc")
+
+	tests := map[string]struct {
+		attempt string
+		want    int
+	}{
+		"zero":       {attempt: "0", want: http.StatusBadRequest},
+		"not number": {attempt: "abc", want: http.StatusBadRequest},
+		"future":     {attempt: "2", want: http.StatusNotFound},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			httpRes := doJSON(t, http.MethodGet, f.taskActionURL(tsk.ID, "attempts/"+tt.attempt+"/logs"), nil)
+			defer httpRes.Body.Close()
+			assert.Equal(t, tt.want, httpRes.StatusCode)
+		})
+	}
+}
+
+// --- API versions ---
+
+func TestV1_DeprecationHeaders(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedTask("Task", "desc")
+
+	httpRes := doJSON(t, http.MethodGet, f.taskURL(tsk.ID), nil)
+	defer httpRes.Body.Close()
+	require.Equal(t, http.StatusOK, httpRes.StatusCode)
+	assert.Equal(t, "true", httpRes.Header.Get("Deprecation"))
+	assert.Equal(t, "Fri, 01 Jan 2027 00:00:00 GMT", httpRes.Header.Get("Sunset"))
+	assert.Equal(t, `</api/v2>; rel="successor-version"`, httpRes.Header.Get("Link"))
+
+	v2Res := doJSON(t, http.MethodGet, f.taskV2URL(tsk.ID), nil)
+	defer v2Res.Body.Close()
+	require.Equal(t, http.StatusOK, v2Res.StatusCode)
+	assert.Empty(t, v2Res.Header.Get("Deprecation"))
+	assert.Empty(t, v2Res.Header.Get("Sunset"))
+}
+
+func TestV2_StreamLogs(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedTask("Flaky task", "desc")
+
+	f.runAttempt(tsk, 1, "first", "ci_failure: tests failed")
+	f.runAttempt(tsk, 2, "second", "")
+
+	tests := map[string]struct {
+		action string
+		want   []string
+	}{
+		"all attempts": {action: "logs", want: []string{`"logs":["first"]`, `"logs":["second"]`}},
+		"one attempt":  {action: "attempts/2/logs", want: []string{`"logs":["second"]`}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.taskActionV2URL(tsk.ID, tt.action), http.NoBody)
+			require.NoError(t, err)
+			httpRes, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer httpRes.Body.Close()
+			require.Equal(t, http.StatusOK, httpRes.StatusCode)
+			assert.Equal(t, "text/event-stream", httpRes.Header.Get("Content-Type"))
+
+			var data []string
+			scanner := bufio.NewScanner(httpRes.Body)
+			for scanner.Scan() {
+				line := scanner.Text()
+				if line == "event: logs_done" {
+					break
+				}
+				if d, ok := strings.CutPrefix(line, "data: "); ok {
+					data = append(data, d)
+				}
+			}
+			require.Len(t, data, len(tt.want))
+			for i, want := range tt.want {
+				assert.Contains(t, data[i], want)
+			}
+		})
+	}
+}
+
+func TestV2_GetTask(t *testing.T) {
+	f := newFixture(t)
+	tsk := f.seedTask("Task", "desc")
+	ctx := context.Background()
+	require.NoError(t, f.TaskRepo.SetTaskPullRequest(ctx, tsk.ID, "https://github.com/owner/test-repo/pull/7", 7))
+
+	res := testutil.Get[server.Response[taskapi.TaskV2]](t, f.taskV2URL(tsk.ID))
+	assert.Equal(t, tsk.ID, res.Data.ID)
+	assert.Equal(t, []taskapi.PullRequestV2{{RepoID: f.Repo.ID.String(), URL: "https://github.com/owner/test-repo/pull/7", Number: 7}}, res.Data.PullRequests)
+	assert.Equal(t, tsk.MaxAttempts, res.Data.Attempts.Max)
+	assert.NotNil(t, res.Data.DependsOn)
+}
+
+func TestV2_SharesHandlersWithV1(t *testing.T) {
+	f := newFixture(t)
+
+	req := verveclient.CreateTaskRequest{Title: "Fix bug", Description: "desc", MaxCostUSD: 2}
+	created := testutil.Post[server.Response[taskapi.TaskV2]](t, f.repoTasksV2URL(), req)
+	assert.Equal(t, "Fix bug", created.Data.Title)
+	assert.Equal(t, 2.0, created.Data.Cost.MaxUSD)
+
+	// Tasks created through v2 are visible through v1 and vice versa.
+	v1 := testutil.Get[server.Response[task.Task]](t, f.taskURL(created.Data.ID))
+	assert.Equal(t, 2.0, v1.Data.MaxCostUSD)
+
+	list := testutil.Get[server.ResponseList[taskapi.TaskV2]](t, f.repoTasksV2URL())
+	require.Len(t, list.Data, 1)
+	assert.Equal(t, created.Data.ID, list.Data[0].ID)
+}
+
+func TestRoutes_MatchRegister(t *testing.T) {
+	for _, h := range []*taskapi.HTTPHandler{taskapi.NewHTTPHandler(nil, nil, nil, nil, nil), taskapi.NewHTTPHandler(nil, nil, nil, nil, nil).V2()} {
+		e := echo.New()
+		h.Register(e.Group(""))
+
+		var registered, described []string
+		for _, r := range e.Routes() {
+			if r.Method != echo.RouteNotFound {
+				registered = append(registered, r.Method+" "+r.Path)
+			}
+		}
+		for _, r := range h.Routes() {
+			described = append(described, r.Method+" "+r.Path)
+		}
+		assert.ElementsMatch(t, registered, described)
+	}
+}
+
+func TestOpenAPI_Spec(t *testing.T) {
+	f := newFixture(t)
+
+	spec := testutil.Get[openapi.Document](t, f.openAPIURL())
+	assert.Equal(t, openapi.Version, spec.OpenAPI)
+	require.Contains(t, spec.Paths, "/api/v1/tasks/{id}")
+	assert.True(t, spec.Paths["/api/v1/tasks/{id}"]["get"].Deprecated)
+	require.Contains(t, spec.Paths, "/api/v2/tasks/{id}")
+	assert.False(t, spec.Paths["/api/v2/tasks/{id}"]["get"].Deprecated)
+	assert.Contains(t, spec.Components.Schemas, "TaskV2")
+}
+
+func TestCreateTask_MalformedBody(t *testing.T) {
+	f := newFixture(t)
+
+	for _, url := range []string{f.repoTasksURL(), f.repoTasksV2URL()} {
+		httpRes := doJSON(t, http.MethodPost, url, map[string]any{"title": 42, "acceptance_criteria": "tests pass"})
+		defer httpRes.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+
+		var res server.ResponseError
+		require.NoError(t, json.NewDecoder(httpRes.Body).Decode(&res))
+		assert.ElementsMatch(t, []string{"title: [must be a string]", "acceptance_criteria: [must be an array]"}, res.Error.Details)
+	}
+}
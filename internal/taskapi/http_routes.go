@@ -0,0 +1,85 @@
+package taskapi
+
+import (
+	"net/http"
+
+	"github.com/vervesh/verve/internal/openapi"
+	"github.com/vervesh/verve/internal/task"
+)
+
+// Routes describes the endpoints Register adds, for the OpenAPI document.
+// Keep it in step with Register.
+func (h *HTTPHandler) Routes() []openapi.Route {
+	var taskRes, failedRes any = task.Task{}, FailedTasksResponse{}
+	if h.version == apiV2 {
+		taskRes, failedRes = TaskV2{}, FailedTasksResponseV2{}
+	}
+	routes := []openapi.Route{
+		// Repo-scoped task operations
+		{Method: http.MethodGet, Path: "/repos/:repo_id/tasks", Summary: "List a repo's tasks", Request: ListTasksRequest{}, Response: taskRes, List: true},
+		{Method: http.MethodGet, Path: "/repos/:repo_id/tasks/:number", Summary: "Get a task by its number in the repo", Request: TaskByNumberRequest{}, Response: taskRes},
+		{Method: http.MethodGet, Path: "/repos/:repo_id/tasks/failed", Summary: "List a repo's failed tasks", Request: ListFailedTasksRequest{}, Response: failedRes},
+		{Method: http.MethodGet, Path: "/repos/:repo_id/tasks/trash", Summary: "List a repo's deleted tasks that can still be restored", Request: RepoIDRequest{}, Response: taskRes, List: true},
+		{Method: http.MethodPost, Path: "/repos/:repo_id/tasks", Summary: "Create a task", Request: CreateTaskRequest{}, Response: taskRes, Status: http.StatusCreated},
+		{Method: http.MethodPost, Path: "/repos/:repo_id/tasks/bulk-action", Summary: "Close, delete, set ready or retry several of a repo's tasks at once", Request: BulkTaskActionRequest{}, Response: BulkTaskActionResponse{}},
+		{Method: http.MethodPost, Path: "/repos/:repo_id/tasks/sync", Summary: "Sync the pull request status of a repo's tasks", Request: SyncRepoTasksRequest{}, Response: map[string]int{}},
+		{Method: http.MethodGet, Path: "/repos/:repo_id/notes", Summary: "List notes agents left in a repo", Request: RepoIDRequest{}, Response: task.Note{}, List: true, Tag: "notes"},
+
+		// Task operations (globally unique IDs)
+		{Method: http.MethodGet, Path: "/tasks/:id", Summary: "Get a task", Request: TaskIDRequest{}, Response: taskRes},
+		{Method: http.MethodGet, Path: "/tasks/:id/logs", Summary: "Stream a task's logs", Request: TaskIDRequest{}, Stream: true},
+		{Method: http.MethodGet, Path: "/tasks/:id/attempts", Summary: "List a task's attempts", Request: TaskIDRequest{}, Response: task.Attempt{}, List: true},
+		{Method: http.MethodGet, Path: "/tasks/:id/attempts/:attempt/logs", Summary: "Stream the logs of one attempt", Request: TaskAttemptRequest{}, Stream: true},
+		{Method: http.MethodGet, Path: "/tasks/:id/events", Summary: "List a task's agent events", Request: ListTaskEventsRequest{}, Response: task.AgentEvent{}, List: true},
+		{Method: http.MethodGet, Path: "/tasks/:id/progress", Summary: "Get a task's progress", Request: TaskIDRequest{}, Response: task.Progress{}},
+		{Method: http.MethodPost, Path: "/tasks/:id/close", Summary: "Close a task", Request: CloseRequest{}, Response: taskRes},
+		{Method: http.MethodPost, Path: "/tasks/:id/approve", Summary: "Approve a task's pull requests on GitHub", Request: ApproveTaskRequest{}, Response: taskRes},
+		{Method: http.MethodPost, Path: "/tasks/:id/merge", Summary: "Merge a task's pull requests on GitHub", Request: MergeTaskRequest{}, Response: taskRes},
+		{Method: http.MethodPost, Path: "/tasks/:id/stop", Summary: "Stop a running task", Request: TaskIDRequest{}, Response: taskRes},
+		{Method: http.MethodPost, Path: "/tasks/:id/retry", Summary: "Retry a failed task", Request: RetryTaskRequest{}, Response: taskRes},
+		{Method: http.MethodPost, Path: "/tasks/:id/start-over", Summary: "Start a task over from scratch", Request: StartOverRequest{}, Response: taskRes},
+		{Method: http.MethodPost, Path: "/tasks/:id/move", Summary: "Move a pending task to another repo", Request: MoveTaskRequest{}, Response: taskRes},
+		{Method: http.MethodPost, Path: "/tasks/:id/feedback", Summary: "Give feedback on a task in review", Request: FeedbackRequest{}, Response: taskRes},
+		{Method: http.MethodPost, Path: "/tasks/:id/nudge", Summary: "Send a message to a running task's agent", Request: NudgeRequest{}, Response: task.Nudge{}, Status: http.StatusCreated},
+		{Method: http.MethodGet, Path: "/tasks/:id/nudges", Summary: "List messages sent to a task's agent", Request: TaskIDRequest{}, Response: task.Nudge{}, List: true},
+		{Method: http.MethodGet, Path: "/tasks/:id/comments", Summary: "List a task's comments", Request: TaskIDRequest{}, Response: task.Comment{}, List: true, Tag: "comments"},
+		{Method: http.MethodPost, Path: "/tasks/:id/comments", Summary: "Comment on a task", Request: CreateCommentRequest{}, Response: task.Comment{}, Status: http.StatusCreated, Tag: "comments"},
+		{Method: http.MethodPatch, Path: "/tasks/:id/comments/:comment_id", Summary: "Edit a comment on a task", Request: UpdateCommentRequest{}, Response: task.Comment{}, Tag: "comments"},
+		{Method: http.MethodDelete, Path: "/tasks/:id/comments/:comment_id", Summary: "Delete a comment on a task", Request: CommentIDRequest{}, Tag: "comments"},
+		{Method: http.MethodGet, Path: "/tasks/:id/attachments", Summary: "List a task's attachments", Request: TaskIDRequest{}, Response: task.Attachment{}, List: true, Tag: "attachments"},
+		{Method: http.MethodPost, Path: "/tasks/:id/attachments", Summary: "Attach a file to a task", Request: TaskIDRequest{}, Upload: "file", Response: task.Attachment{}, Status: http.StatusCreated, Tag: "attachments"},
+		{Method: http.MethodGet, Path: "/tasks/:id/attachments/:attachment_id", Summary: "Download a task attachment", Request: AttachmentIDRequest{}, Download: true, Tag: "attachments"},
+		{Method: http.MethodDelete, Path: "/tasks/:id/attachments/:attachment_id", Summary: "Delete a task attachment", Request: AttachmentIDRequest{}, Tag: "attachments"},
+		{Method: http.MethodGet, Path: "/tasks/:id/artifacts", Summary: "List the artifacts a task's agent produced", Request: ListArtifactsRequest{}, Response: task.Artifact{}, List: true, Tag: "artifacts"},
+		{Method: http.MethodGet, Path: "/tasks/:id/artifacts/:artifact_id", Summary: "Download a task artifact", Request: ArtifactIDRequest{}, Download: true, Tag: "artifacts"},
+		{Method: http.MethodPost, Path: "/tasks/:id/move-to-review", Summary: "Move a task to review", Request: TaskIDRequest{}, Response: taskRes},
+		{Method: http.MethodPost, Path: "/tasks/:id/sync", Summary: "Sync a task's pull request status", Request: TaskIDRequest{}, Response: taskRes},
+		{Method: http.MethodGet, Path: "/tasks/:id/checks", Summary: "Get the CI status of a task's pull request", Request: TaskIDRequest{}, Response: CheckStatusResponse{}},
+		{Method: http.MethodPost, Path: "/tasks/:id/checks/rerun", Summary: "Re-run the failed GitHub Actions jobs of a task's pull request", Request: TaskIDRequest{}, Response: CheckStatusResponse{}},
+		{Method: http.MethodGet, Path: "/tasks/:id/diff", Summary: "Get a task's diff", Request: TaskIDRequest{}, Response: DiffResponse{}},
+		{Method: http.MethodGet, Path: "/tasks/:id/shadow-diff", Summary: "Get the diff of a shadow-mode task", Request: TaskIDRequest{}, Response: task.ShadowDiff{}},
+		{Method: http.MethodGet, Path: "/tasks/:id/proposal", Summary: "Get the change proposal of a plan-only task", Request: TaskIDRequest{}, Response: task.Proposal{}},
+		{Method: http.MethodPost, Path: "/tasks/:id/proposal/approve", Summary: "Approve a task's change proposal for implementation", Request: TaskIDRequest{}, Response: taskRes},
+		{Method: http.MethodGet, Path: "/tasks/:id/provenance", Summary: "Get a task's provenance review", Request: TaskIDRequest{}, Response: task.ProvenanceReview{}},
+		{Method: http.MethodPost, Path: "/tasks/:id/provenance/acknowledge", Summary: "Acknowledge a task's provenance review", Request: TaskIDRequest{}, Response: task.ProvenanceReview{}},
+		{Method: http.MethodGet, Path: "/tasks/:id/self-review", Summary: "Get a task's self-review", Request: TaskIDRequest{}, Response: task.SelfReview{}},
+		{Method: http.MethodDelete, Path: "/tasks/:id/dependency", Summary: "Remove a dependency from a task", Request: RemoveDependencyRequest{}, Response: taskRes},
+		{Method: http.MethodPut, Path: "/tasks/:id/ready", Summary: "Set whether a task is ready to run", Request: SetReadyRequest{}, Response: taskRes},
+		{Method: http.MethodPatch, Path: "/tasks/:id", Summary: "Update a pending task", Request: UpdateTaskRequest{}, Response: taskRes},
+		{Method: http.MethodDelete, Path: "/tasks/:id", Summary: "Delete a task", Request: TaskIDRequest{}},
+		{Method: http.MethodPost, Path: "/tasks/:id/restore", Summary: "Restore a deleted task from the trash", Request: TaskIDRequest{}, Response: taskRes},
+		{Method: http.MethodPost, Path: "/tasks/:id/unarchive", Summary: "Bring an archived task back into task lists with its logs", Request: TaskIDRequest{}, Response: taskRes},
+		{Method: http.MethodPost, Path: "/tasks/bulk-delete", Summary: "Delete several tasks", Request: BulkDeleteTasksRequest{}},
+		{Method: http.MethodPost, Path: "/tasks/bulk-retry", Summary: "Retry several failed tasks", Request: BulkRetryTasksRequest{}, Response: BulkRetryTasksResponse{}},
+
+		// Notes left by agents
+		{Method: http.MethodPost, Path: "/notes/:id/convert", Summary: "Turn a note into a task", Request: NoteIDRequest{}, Response: taskRes, Status: http.StatusCreated, Tag: "notes"},
+	}
+	for i := range routes {
+		if routes[i].Tag == "" {
+			routes[i].Tag = "tasks"
+		}
+		routes[i].Deprecated = h.version == apiV1
+	}
+	return routes
+}
@@ -1,13 +1,17 @@
 package taskapi
 
 import (
+	"fmt"
+	"slices"
 	"strconv"
 
 	"github.com/cohesivestack/valgo"
 
 	"github.com/vervesh/verve/internal/github"
+	"github.com/vervesh/verve/internal/msgcat"
 	"github.com/vervesh/verve/internal/repo"
 	"github.com/vervesh/verve/internal/task"
+	"github.com/vervesh/verve/pkg/verveclient"
 )
 
 // --- Param-only request types ---
@@ -30,6 +34,28 @@ func (r RepoIDRequest) Validate() error {
 	return valgo.In("params", valgo.Is(repo.RepoIDValidator(r.RepoID, "repo_id"))).ToError()
 }
 
+// ListTasksRequest captures the :repo_id path parameter and optional
+// ?include_archived= flag for listing a repo's tasks.
+type ListTasksRequest struct {
+	RepoID          string `param:"repo_id" json:"-"`
+	IncludeArchived bool   `query:"include_archived" json:"-"`
+}
+
+func (r ListTasksRequest) Validate() error {
+	return valgo.In("params", valgo.Is(repo.RepoIDValidator(r.RepoID, "repo_id"))).ToError()
+}
+
+// ListFailedTasksRequest captures the :repo_id path parameter and optional
+// ?category= filter for listing a repo's failed tasks.
+type ListFailedTasksRequest struct {
+	RepoID   string `param:"repo_id" json:"-"`
+	Category string `query:"category" json:"-"`
+}
+
+func (r ListFailedTasksRequest) Validate() error {
+	return valgo.In("params", valgo.Is(repo.RepoIDValidator(r.RepoID, "repo_id"))).ToError()
+}
+
 // TaskByNumberRequest captures the :repo_id and :number path parameters for looking up a task by number.
 type TaskByNumberRequest struct {
 	RepoID string `param:"repo_id" json:"-"`
@@ -45,20 +71,117 @@ func (r TaskByNumberRequest) Validate() error {
 	return v.ToError()
 }
 
+// TaskAttemptRequest captures the :id and :attempt path parameters for
+// attempt-scoped endpoints.
+type TaskAttemptRequest struct {
+	ID      string `param:"id" json:"-"`
+	Attempt string `param:"attempt" json:"-"`
+}
+
+func (r TaskAttemptRequest) Validate() error {
+	v := valgo.In("params", valgo.Is(task.TaskIDValidator(r.ID, "id")))
+	n, err := strconv.Atoi(r.Attempt)
+	if err != nil || n <= 0 {
+		v = v.AddErrorMessage("attempt", "must be a positive integer")
+	}
+	return v.ToError()
+}
+
+// NoteIDRequest captures the :id path parameter for note endpoints.
+type NoteIDRequest struct {
+	ID string `param:"id" json:"-"`
+}
+
+func (r NoteIDRequest) Validate() error {
+	n, err := strconv.ParseInt(r.ID, 10, 64)
+	if err != nil || n <= 0 {
+		return valgo.AddErrorMessage("id", "must be a positive integer").ToError()
+	}
+	return nil
+}
+
+// CommentIDRequest captures the :id and :comment_id path parameters for
+// comment endpoints.
+type CommentIDRequest struct {
+	ID        string `param:"id" json:"-"`
+	CommentID string `param:"comment_id" json:"-"`
+}
+
+func (r CommentIDRequest) Validate() error {
+	v := valgo.In("params", valgo.Is(task.TaskIDValidator(r.ID, "id")))
+	n, err := strconv.ParseInt(r.CommentID, 10, 64)
+	if err != nil || n <= 0 {
+		v = v.AddErrorMessage("comment_id", "must be a positive integer")
+	}
+	return v.ToError()
+}
+
+// AttachmentIDRequest captures the :id and :attachment_id path parameters
+// for attachment endpoints.
+type AttachmentIDRequest struct {
+	ID           string `param:"id" json:"-"`
+	AttachmentID string `param:"attachment_id" json:"-"`
+}
+
+func (r AttachmentIDRequest) Validate() error {
+	v := valgo.In("params", valgo.Is(task.TaskIDValidator(r.ID, "id")))
+	n, err := strconv.ParseInt(r.AttachmentID, 10, 64)
+	if err != nil || n <= 0 {
+		v = v.AddErrorMessage("attachment_id", "must be a positive integer")
+	}
+	return v.ToError()
+}
+
+// ListArtifactsRequest captures the :id path parameter and optional
+// ?attempt= filter for listing artifacts.
+type ListArtifactsRequest struct {
+	ID      string `param:"id" json:"-"`
+	Attempt int    `query:"attempt" json:"-"`
+}
+
+func (r ListArtifactsRequest) Validate() error {
+	return valgo.In("params", valgo.Is(
+		task.TaskIDValidator(r.ID, "id"),
+		valgo.Int(r.Attempt, "attempt").GreaterOrEqualTo(0),
+	)).ToError()
+}
+
+// ArtifactIDRequest captures the :id and :artifact_id path parameters for
+// downloading an artifact.
+type ArtifactIDRequest struct {
+	ID         string `param:"id" json:"-"`
+	ArtifactID string `param:"artifact_id" json:"-"`
+}
+
+func (r ArtifactIDRequest) Validate() error {
+	v := valgo.In("params", valgo.Is(task.TaskIDValidator(r.ID, "id")))
+	n, err := strconv.ParseInt(r.ArtifactID, 10, 64)
+	if err != nil || n <= 0 {
+		v = v.AddErrorMessage("artifact_id", "must be a positive integer")
+	}
+	return v.ToError()
+}
+
+// ListTaskEventsRequest captures the :id path parameter and optional
+// ?attempt= filter for listing agent events.
+type ListTaskEventsRequest struct {
+	ID      string `param:"id" json:"-"`
+	Attempt int    `query:"attempt" json:"-"`
+}
+
+func (r ListTaskEventsRequest) Validate() error {
+	return valgo.In("params", valgo.Is(
+		task.TaskIDValidator(r.ID, "id"),
+		valgo.Int(r.Attempt, "attempt").GreaterOrEqualTo(0),
+	)).ToError()
+}
+
 // --- Param+body request types ---
 
 // CreateTaskRequest is the request body for creating a task.
 type CreateTaskRequest struct {
-	RepoID             string   `param:"repo_id" json:"-"`
-	Title              string   `json:"title"`
-	Description        string   `json:"description"`
-	DependsOn          []string `json:"depends_on,omitempty"`
-	AcceptanceCriteria []string `json:"acceptance_criteria,omitempty"`
-	MaxCostUSD         float64  `json:"max_cost_usd,omitempty"`
-	SkipPR             bool     `json:"skip_pr,omitempty"`
-	DraftPR            bool     `json:"draft_pr,omitempty"`
-	Model              string   `json:"model,omitempty"`
-	NotReady           bool     `json:"not_ready,omitempty"`
+	RepoID string `param:"repo_id" json:"-"`
+	verveclient.CreateTaskRequest
 }
 
 func (r CreateTaskRequest) Validate() error {
@@ -66,9 +189,29 @@ func (r CreateTaskRequest) Validate() error {
 		In("params", valgo.Is(repo.RepoIDValidator(r.RepoID, "repo_id"))).
 		Is(
 			valgo.String(r.Title, "title").Not().Blank().MaxLength(150),
+			valgo.Int(r.MaxRuntimeSeconds, "max_runtime_seconds").GreaterOrEqualTo(0),
 		)
 	if r.SkipPR && r.DraftPR {
-		v = v.AddErrorMessage("skip_pr", "skip_pr and draft_pr are mutually exclusive")
+		v = v.AddErrorMessage("skip_pr", msgcat.Text(msgcat.ErrTaskSkipPRWithDraftPR))
+	}
+	distinct := true
+	for i, id := range r.AdditionalRepoIDs {
+		v = v.Is(repo.RepoIDValidator(id, fmt.Sprintf("additional_repo_ids[%d]", i)))
+		if id == r.RepoID || slices.Index(r.AdditionalRepoIDs, id) != i {
+			distinct = false
+		}
+	}
+	if !distinct {
+		v = v.AddErrorMessage("additional_repo_ids", msgcat.Text(msgcat.ErrTaskAdditionalRepos))
+	}
+	if err := task.ValidateLabels(r.RequiredLabels); err != nil {
+		v = v.AddErrorMessage("required_labels", err.Error())
+	}
+	if err := task.ValidateFallbackModels(r.FallbackModels); err != nil {
+		v = v.AddErrorMessage("fallback_models", err.Error())
+	}
+	if r.StackOn != "" && !slices.Contains(r.DependsOn, r.StackOn) {
+		v = v.AddErrorMessage("stack_on", msgcat.Text(msgcat.ErrTaskStackOnNotDependency))
 	}
 	return v.ToError()
 }
@@ -76,16 +219,8 @@ func (r CreateTaskRequest) Validate() error {
 // UpdateTaskRequest is the request body for updating a pending task.
 // All fields are optional — only provided fields are updated.
 type UpdateTaskRequest struct {
-	ID                 string   `param:"id" json:"-"`
-	Title              *string  `json:"title,omitempty"`
-	Description        *string  `json:"description,omitempty"`
-	DependsOn          []string `json:"depends_on,omitempty"`
-	AcceptanceCriteria []string `json:"acceptance_criteria,omitempty"`
-	MaxCostUSD         *float64 `json:"max_cost_usd,omitempty"`
-	SkipPR             *bool    `json:"skip_pr,omitempty"`
-	DraftPR            *bool    `json:"draft_pr,omitempty"`
-	Model              *string  `json:"model,omitempty"`
-	NotReady           *bool    `json:"not_ready,omitempty"`
+	ID string `param:"id" json:"-"`
+	verveclient.UpdateTaskRequest
 }
 
 func (r UpdateTaskRequest) Validate() error {
@@ -93,23 +228,59 @@ func (r UpdateTaskRequest) Validate() error {
 	if r.Title != nil {
 		v = v.Is(valgo.String(*r.Title, "title").Not().Blank().MaxLength(150))
 	}
+	if r.MaxRuntimeSeconds != nil {
+		v = v.Is(valgo.Int(*r.MaxRuntimeSeconds, "max_runtime_seconds").GreaterOrEqualTo(0))
+	}
+	if r.RequiredLabels != nil {
+		if err := task.ValidateLabels(*r.RequiredLabels); err != nil {
+			v = v.AddErrorMessage("required_labels", err.Error())
+		}
+	}
+	if r.ExpectedVersion != nil {
+		v = v.Is(valgo.Int64(*r.ExpectedVersion, "expected_version").GreaterOrEqualTo(1))
+	}
 	return v.ToError()
 }
 
 // CloseRequest is the request body for closing a task.
 type CloseRequest struct {
-	ID     string `param:"id" json:"-"`
-	Reason string `json:"reason,omitempty"`
+	ID string `param:"id" json:"-"`
+	verveclient.CloseRequest
 }
 
 func (r CloseRequest) Validate() error {
 	return valgo.In("params", valgo.Is(task.TaskIDValidator(r.ID, "id"))).ToError()
 }
 
+// ApproveTaskRequest is the request body for approving a task's pull
+// request.
+type ApproveTaskRequest struct {
+	ID string `param:"id" json:"-"`
+	verveclient.ApproveTaskRequest
+}
+
+func (r ApproveTaskRequest) Validate() error {
+	return valgo.In("params", valgo.Is(task.TaskIDValidator(r.ID, "id"))).ToError()
+}
+
+// MergeTaskRequest is the request body for merging a task's pull request.
+type MergeTaskRequest struct {
+	ID string `param:"id" json:"-"`
+	verveclient.MergeTaskRequest
+}
+
+func (r MergeTaskRequest) Validate() error {
+	v := valgo.In("params", valgo.Is(task.TaskIDValidator(r.ID, "id")))
+	if r.MergeMethod != "" && !slices.Contains(github.MergeMethods, r.MergeMethod) {
+		v = v.AddErrorMessage("merge_method", fmt.Sprintf("must be one of %v", github.MergeMethods))
+	}
+	return v.ToError()
+}
+
 // RetryTaskRequest is the request body for retrying a failed task.
 type RetryTaskRequest struct {
-	ID           string `param:"id" json:"-"`
-	Instructions string `json:"instructions,omitempty"`
+	ID string `param:"id" json:"-"`
+	verveclient.RetryTaskRequest
 }
 
 func (r RetryTaskRequest) Validate() error {
@@ -118,8 +289,8 @@ func (r RetryTaskRequest) Validate() error {
 
 // FeedbackRequest is the request body for providing feedback on a task in review.
 type FeedbackRequest struct {
-	ID       string `param:"id" json:"-"`
-	Feedback string `json:"feedback"`
+	ID string `param:"id" json:"-"`
+	verveclient.FeedbackRequest
 }
 
 func (r FeedbackRequest) Validate() error {
@@ -128,12 +299,57 @@ func (r FeedbackRequest) Validate() error {
 		ToError()
 }
 
+// NudgeRequest is the request body for sending a message to the agent of a
+// running task.
+type NudgeRequest struct {
+	ID string `param:"id" json:"-"`
+	verveclient.NudgeRequest
+}
+
+func (r NudgeRequest) Validate() error {
+	return valgo.In("params", valgo.Is(task.TaskIDValidator(r.ID, "id"))).
+		Is(valgo.String(r.Message, "message").Not().Blank().MaxLength(task.MaxNudgeLength)).
+		ToError()
+}
+
+// maxCommentAuthorLength caps the author name given by callers without a
+// user token.
+const maxCommentAuthorLength = 100
+
+// CreateCommentRequest is the request body for commenting on a task.
+type CreateCommentRequest struct {
+	ID string `param:"id" json:"-"`
+	verveclient.CreateCommentRequest
+}
+
+func (r CreateCommentRequest) Validate() error {
+	v := valgo.In("params", valgo.Is(task.TaskIDValidator(r.ID, "id"))).
+		Is(valgo.String(r.Body, "body").Not().Blank().MaxLength(task.MaxCommentLength)).
+		Is(valgo.String(r.Author, "author").MaxLength(maxCommentAuthorLength))
+	if r.Attempt != nil && *r.Attempt <= 0 {
+		v = v.AddErrorMessage("attempt", "must be a positive integer")
+	}
+	return v.ToError()
+}
+
+// UpdateCommentRequest is the request body for editing a comment on a task.
+type UpdateCommentRequest struct {
+	ID        string `param:"id" json:"-"`
+	CommentID string `param:"comment_id" json:"-"`
+	verveclient.UpdateCommentRequest
+}
+
+func (r UpdateCommentRequest) Validate() error {
+	if err := (CommentIDRequest{ID: r.ID, CommentID: r.CommentID}).Validate(); err != nil {
+		return err
+	}
+	return valgo.Is(valgo.String(r.Body, "body").Not().Blank().MaxLength(task.MaxCommentLength)).ToError()
+}
+
 // StartOverRequest is the request body for starting a task over from scratch.
 type StartOverRequest struct {
-	ID                 string   `param:"id" json:"-"`
-	Title              *string  `json:"title,omitempty"`
-	Description        *string  `json:"description,omitempty"`
-	AcceptanceCriteria []string `json:"acceptance_criteria,omitempty"`
+	ID string `param:"id" json:"-"`
+	verveclient.StartOverRequest
 }
 
 func (r StartOverRequest) Validate() error {
@@ -141,13 +357,32 @@ func (r StartOverRequest) Validate() error {
 	if r.Title != nil {
 		v = v.Is(valgo.String(*r.Title, "title").Not().Blank().MaxLength(150))
 	}
+	if r.ExpectedVersion != nil {
+		v = v.Is(valgo.Int64(*r.ExpectedVersion, "expected_version").GreaterOrEqualTo(1))
+	}
+	return v.ToError()
+}
+
+// MoveTaskRequest is the request body for moving a pending task to another
+// repo.
+type MoveTaskRequest struct {
+	ID string `param:"id" json:"-"`
+	verveclient.MoveTaskRequest
+}
+
+func (r MoveTaskRequest) Validate() error {
+	v := valgo.In("params", valgo.Is(task.TaskIDValidator(r.ID, "id"))).
+		Is(repo.RepoIDValidator(r.RepoID, "repo_id"))
+	if r.ExpectedVersion != nil {
+		v = v.Is(valgo.Int64(*r.ExpectedVersion, "expected_version").GreaterOrEqualTo(1))
+	}
 	return v.ToError()
 }
 
 // RemoveDependencyRequest is the request body for removing a dependency from a task.
 type RemoveDependencyRequest struct {
-	ID        string `param:"id" json:"-"`
-	DependsOn string `json:"depends_on"`
+	ID string `param:"id" json:"-"`
+	verveclient.RemoveDependencyRequest
 }
 
 func (r RemoveDependencyRequest) Validate() error {
@@ -158,8 +393,8 @@ func (r RemoveDependencyRequest) Validate() error {
 
 // SetReadyRequest is the request body for toggling a task's ready state.
 type SetReadyRequest struct {
-	ID    string `param:"id" json:"-"`
-	Ready bool   `json:"ready"`
+	ID string `param:"id" json:"-"`
+	verveclient.SetReadyRequest
 }
 
 func (r SetReadyRequest) Validate() error {
@@ -168,7 +403,7 @@ func (r SetReadyRequest) Validate() error {
 
 // BulkDeleteTasksRequest is the request body for bulk-deleting tasks.
 type BulkDeleteTasksRequest struct {
-	TaskIDs []string `json:"task_ids"`
+	verveclient.BulkDeleteTasksRequest
 }
 
 func (r BulkDeleteTasksRequest) Validate() error {
@@ -178,6 +413,59 @@ func (r BulkDeleteTasksRequest) Validate() error {
 	return nil
 }
 
+// maxBulkRetryTasks caps the number of tasks retried by one bulk retry.
+const maxBulkRetryTasks = 200
+
+// BulkRetryTasksRequest is the request body for retrying several failed tasks.
+type BulkRetryTasksRequest struct {
+	verveclient.BulkRetryTasksRequest
+}
+
+func (r BulkRetryTasksRequest) Validate() error {
+	if len(r.TaskIDs) == 0 {
+		return valgo.AddErrorMessage("task_ids", "task_ids required").ToError()
+	}
+	if len(r.TaskIDs) > maxBulkRetryTasks {
+		return valgo.AddErrorMessage("task_ids", fmt.Sprintf("at most %d tasks can be retried at once", maxBulkRetryTasks)).ToError()
+	}
+	validators := make([]valgo.Validator, len(r.TaskIDs))
+	for i, id := range r.TaskIDs {
+		validators[i] = task.TaskIDValidator(id, fmt.Sprintf("task_ids[%d]", i))
+	}
+	return valgo.Is(validators...).ToError()
+}
+
+// maxBulkActionTasks caps the number of task IDs one bulk action lists.
+const maxBulkActionTasks = 200
+
+// BulkTaskActionRequest captures the :repo_id path parameter and the
+// request body for applying one action to several of a repo's tasks.
+type BulkTaskActionRequest struct {
+	RepoID string `param:"repo_id" json:"-"`
+	verveclient.BulkTaskActionRequest
+}
+
+func (r BulkTaskActionRequest) Validate() error {
+	v := valgo.In("params", valgo.Is(repo.RepoIDValidator(r.RepoID, "repo_id")))
+	if !task.BulkAction(r.Action).Valid() {
+		v = v.AddErrorMessage("action", "must be one of close, delete, set-ready or retry")
+	}
+	switch {
+	case len(r.TaskIDs) == 0 && r.Status == "":
+		v = v.AddErrorMessage("task_ids", "task_ids or status required")
+	case len(r.TaskIDs) > 0 && r.Status != "":
+		v = v.AddErrorMessage("status", "must not be set with task_ids")
+	case r.Status != "" && !task.ValidStatus(task.Status(r.Status)):
+		v = v.AddErrorMessage("status", "must be a valid task status")
+	case len(r.TaskIDs) > maxBulkActionTasks:
+		v = v.AddErrorMessage("task_ids", fmt.Sprintf("at most %d tasks can be acted on at once", maxBulkActionTasks))
+	}
+	for i, id := range r.TaskIDs {
+		v = v.Is(task.TaskIDValidator(id, fmt.Sprintf("task_ids[%d]", i)))
+	}
+	return v.ToError()
+}
+
 // SyncRepoTasksRequest captures the :repo_id path parameter.
 type SyncRepoTasksRequest struct {
 	RepoID string `param:"repo_id" json:"-"`
@@ -198,8 +486,23 @@ type CheckStatusResponse struct {
 	Checks           []github.IndividualCheck `json:"checks,omitempty"`
 }
 
-// DiffResponse is the response body for the task diff endpoint.
-type DiffResponse struct {
-	Diff string `json:"diff"`
+// FailedTasksResponse is the response body for the failed tasks endpoint.
+type FailedTasksResponse struct {
+	Tasks []*task.Task `json:"tasks"`
+	// Categories counts every failed task in the repo per failure category,
+	// regardless of the category filter.
+	Categories []task.FailureCategoryCount `json:"categories"`
 }
 
+// BulkRetryTasksResponse is the response body for the bulk retry endpoint.
+type BulkRetryTasksResponse = verveclient.BulkRetryTasksResponse
+
+// BulkTaskActionResponse is the response body for the bulk action endpoint.
+type BulkTaskActionResponse = verveclient.BulkTaskActionResponse
+
+// BulkTaskActionResult is the outcome of a bulk action for one task.
+type BulkTaskActionResult = verveclient.BulkTaskActionResult
+
+// DiffResponse is the response body for the task diff endpoint.
+type DiffResponse = verveclient.DiffResponse
+
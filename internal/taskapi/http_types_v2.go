@@ -0,0 +1,156 @@
+package taskapi
+
+import (
+	"time"
+
+	"github.com/vervesh/verve/internal/task"
+)
+
+// TaskV2 is a task as represented by API v2. Compared to v1 it:
+//   - omits logs, which are streamed from /tasks/:id/logs instead
+//   - lists the primary repo's pull request in PullRequests alongside the
+//     additional repos' pull requests, instead of in separate fields
+//   - groups attempt and cost fields
+type TaskV2 struct {
+	ID                 task.TaskID     `json:"id"`
+	Number             int             `json:"number"`
+	RepoID             string          `json:"repo_id"`
+	AdditionalRepoIDs  []string        `json:"additional_repo_ids"`
+	Type               string          `json:"type"`
+	Title              string          `json:"title"`
+	Description        string          `json:"description"`
+	Status             task.Status     `json:"status"`
+	PullRequests       []PullRequestV2 `json:"pull_requests"`
+	DependsOn          []string        `json:"depends_on"`
+	CloseReason        string          `json:"close_reason,omitempty"`
+	FailureCategory    string          `json:"failure_category,omitempty"`
+	Attempts           AttemptsV2      `json:"attempts"`
+	AcceptanceCriteria []string        `json:"acceptance_criteria"`
+	AgentStatus        string          `json:"agent_status,omitempty"`
+	Cost               CostV2          `json:"cost"`
+	MaxRuntimeSeconds  int             `json:"max_runtime_seconds,omitempty"`
+	RequiredLabels     []string        `json:"required_labels"`
+	SkipPR             bool            `json:"skip_pr"`
+	DraftPR            bool            `json:"draft_pr"`
+	PlanOnly           bool            `json:"plan_only"`
+	Ready              bool            `json:"ready"`
+	EpicID             string          `json:"epic_id,omitempty"`
+	Model              string          `json:"model,omitempty"`
+	FallbackModels     []string        `json:"fallback_models"`
+	ActiveModel        string          `json:"active_model,omitempty"`
+	BranchName         string          `json:"branch_name,omitempty"`
+	StartedAt          *time.Time      `json:"started_at,omitempty"`
+	NotBefore          *time.Time      `json:"not_before,omitempty"`
+	DeletedAt          *time.Time      `json:"deleted_at,omitempty"`
+	ArchivedAt         *time.Time      `json:"archived_at,omitempty"`
+	DurationMs         *int64          `json:"duration_ms,omitempty"`
+	Version            int64           `json:"version"`
+	CreatedAt          time.Time       `json:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+}
+
+// PullRequestV2 is a pull request the agent opened for a task in one of its
+// repos.
+type PullRequestV2 struct {
+	RepoID string `json:"repo_id"`
+	URL    string `json:"url"`
+	Number int    `json:"number"`
+	Merged bool   `json:"merged"`
+}
+
+// AttemptsV2 describes a task's attempts so far.
+type AttemptsV2 struct {
+	Current             int    `json:"current"`
+	Max                 int    `json:"max"`
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	RetryReason         string `json:"retry_reason,omitempty"`
+	RetryContext        string `json:"retry_context,omitempty"`
+}
+
+// CostV2 describes a task's spend and budget.
+type CostV2 struct {
+	USD    float64 `json:"usd"`
+	MaxUSD float64 `json:"max_usd,omitempty"`
+}
+
+// FailedTasksResponseV2 is the API v2 response body for the failed tasks
+// endpoint.
+type FailedTasksResponseV2 struct {
+	Tasks      []TaskV2                    `json:"tasks"`
+	Categories []task.FailureCategoryCount `json:"categories"`
+}
+
+func toTaskV2(t *task.Task) TaskV2 {
+	prs := make([]PullRequestV2, 0, len(t.PullRequests)+1)
+	if t.PRNumber > 0 {
+		prs = append(prs, PullRequestV2{
+			RepoID: t.RepoID,
+			URL:    t.PullRequestURL,
+			Number: t.PRNumber,
+			Merged: t.Status == task.StatusMerged,
+		})
+	}
+	for _, pr := range t.PullRequests {
+		prs = append(prs, PullRequestV2{RepoID: pr.RepoID, URL: pr.URL, Number: pr.Number, Merged: pr.Merged})
+	}
+	return TaskV2{
+		ID:                t.ID,
+		Number:            t.Number,
+		RepoID:            t.RepoID,
+		AdditionalRepoIDs: nonNil(t.AdditionalRepoIDs),
+		Type:              t.Type,
+		Title:             t.Title,
+		Description:       t.Description,
+		Status:            t.Status,
+		PullRequests:      prs,
+		DependsOn:         nonNil(t.DependsOn),
+		CloseReason:       t.CloseReason,
+		FailureCategory:   t.FailureCategory,
+		Attempts: AttemptsV2{
+			Current:             t.Attempt,
+			Max:                 t.MaxAttempts,
+			ConsecutiveFailures: t.ConsecutiveFailures,
+			RetryReason:         t.RetryReason,
+			RetryContext:        t.RetryContext,
+		},
+		AcceptanceCriteria: nonNil(t.AcceptanceCriteria),
+		AgentStatus:        t.AgentStatus,
+		Cost:               CostV2{USD: t.CostUSD, MaxUSD: t.MaxCostUSD},
+		MaxRuntimeSeconds:  t.MaxRuntimeSeconds,
+		RequiredLabels:     nonNil(t.RequiredLabels),
+		SkipPR:             t.SkipPR,
+		DraftPR:            t.DraftPR,
+		PlanOnly:           t.PlanOnly,
+		Ready:              t.Ready,
+		EpicID:             t.EpicID,
+		Model:              t.Model,
+		FallbackModels:     nonNil(t.FallbackModels),
+		ActiveModel:        t.ActiveModel,
+		BranchName:         t.BranchName,
+		StartedAt:          t.StartedAt,
+		NotBefore:          t.NotBefore,
+		DeletedAt:          t.DeletedAt,
+		ArchivedAt:         t.ArchivedAt,
+		DurationMs:         t.DurationMs,
+		Version:            t.Version,
+		CreatedAt:          t.CreatedAt,
+		UpdatedAt:          t.UpdatedAt,
+	}
+}
+
+func toTasksV2(tasks []*task.Task) []TaskV2 {
+	out := make([]TaskV2, len(tasks))
+	for i, t := range tasks {
+		out[i] = toTaskV2(t)
+	}
+	return out
+}
+
+// nonNil returns s, or an empty slice when s is nil, so v2 lists are always
+// JSON arrays.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,74 @@
+package taskapi
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/joshjon/kit/server"
+	"github.com/labstack/echo/v4"
+
+	"github.com/vervesh/verve/internal/task"
+)
+
+// API versions served by HTTPHandler. Every version shares the same handlers
+// and differs only in how tasks are mapped to response bodies, so clients
+// can move to a newer version one endpoint at a time.
+const (
+	apiV1 = 1
+	apiV2 = 2
+)
+
+// v1SuccessorLink points v1 clients at the version replacing it.
+const v1SuccessorLink = `</api/v2>; rel="successor-version"`
+
+// Option configures an HTTPHandler.
+type Option func(*HTTPHandler)
+
+// WithV1Sunset announces when API v1 will be removed in the Sunset header
+// of every v1 response.
+func WithV1Sunset(t time.Time) Option {
+	return func(h *HTTPHandler) {
+		h.v1Sunset = t
+	}
+}
+
+// V2 returns a handler serving API v2 with the same stores and options.
+func (h *HTTPHandler) V2() *HTTPHandler {
+	v2 := *h
+	v2.version = apiV2
+	return &v2
+}
+
+// deprecateV1 marks v1 responses as deprecated in favor of v2 and, when
+// scheduled, announces when v1 will be removed.
+func (h *HTTPHandler) deprecateV1(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		header := c.Response().Header()
+		header.Set("Deprecation", "true")
+		header.Add("Link", v1SuccessorLink)
+		if !h.v1Sunset.IsZero() {
+			header.Set("Sunset", h.v1Sunset.UTC().Format(http.TimeFormat))
+		}
+		return next(c)
+	}
+}
+
+// setTaskResponse writes t in the representation of the handler's API
+// version, with its version as the ETag for If-Match preconditions.
+func (h *HTTPHandler) setTaskResponse(c echo.Context, code int, t *task.Task) error {
+	c.Response().Header().Set("ETag", strconv.Quote(strconv.FormatInt(t.Version, 10)))
+	if h.version == apiV2 {
+		return server.SetResponse(c, code, toTaskV2(t))
+	}
+	return server.SetResponse(c, code, t)
+}
+
+// setTaskListResponse writes tasks in the representation of the handler's
+// API version.
+func (h *HTTPHandler) setTaskListResponse(c echo.Context, tasks []*task.Task) error {
+	if h.version == apiV2 {
+		return server.SetResponseList(c, http.StatusOK, toTasksV2(tasks), "")
+	}
+	return server.SetResponseList(c, http.StatusOK, tasks, "")
+}
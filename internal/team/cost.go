@@ -0,0 +1,58 @@
+package team
+
+import (
+	"cmp"
+	"slices"
+)
+
+// RepoCost is a repo's spend over a reporting window.
+type RepoCost struct {
+	RepoID   string  `json:"repo_id"`
+	FullName string  `json:"full_name"`
+	TeamID   string  `json:"-"`
+	CostUSD  float64 `json:"cost_usd"`
+}
+
+// TeamCost is a team's spend over a reporting window, rolled up from its
+// repos.
+type TeamCost struct {
+	// TeamID is empty for the repos that don't belong to a team.
+	TeamID   string     `json:"team_id,omitempty"`
+	TeamName string     `json:"team_name"`
+	CostUSD  float64    `json:"cost_usd"`
+	Repos    []RepoCost `json:"repos"`
+}
+
+// RollupCosts groups repo spend by team. Every team is listed, including
+// those that spent nothing, most expensive first; repos without a team are
+// grouped last. Each team's repos are listed most expensive first.
+func RollupCosts(teams []*Team, repos []RepoCost) []TeamCost {
+	byTeam := make(map[string]*TeamCost, len(teams))
+	out := make([]TeamCost, 0, len(teams)+1)
+	for _, t := range teams {
+		out = append(out, TeamCost{TeamID: t.ID.String(), TeamName: t.Name, Repos: []RepoCost{}})
+	}
+	for i := range out {
+		byTeam[out[i].TeamID] = &out[i]
+	}
+	unassigned := TeamCost{TeamName: "No team", Repos: []RepoCost{}}
+	for _, r := range repos {
+		tc, ok := byTeam[r.TeamID]
+		if !ok {
+			tc = &unassigned
+		}
+		tc.CostUSD += r.CostUSD
+		tc.Repos = append(tc.Repos, r)
+	}
+
+	byCost := func(a, b RepoCost) int { return cmp.Compare(b.CostUSD, a.CostUSD) }
+	for i := range out {
+		slices.SortStableFunc(out[i].Repos, byCost)
+	}
+	slices.SortStableFunc(out, func(a, b TeamCost) int { return cmp.Compare(b.CostUSD, a.CostUSD) })
+	if len(unassigned.Repos) > 0 {
+		slices.SortStableFunc(unassigned.Repos, byCost)
+		out = append(out, unassigned)
+	}
+	return out
+}
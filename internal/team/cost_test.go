@@ -0,0 +1,46 @@
+package team_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/team"
+)
+
+func TestRollupCosts(t *testing.T) {
+	payments := team.NewTeam("Payments")
+	growth := team.NewTeam("Growth")
+	idle := team.NewTeam("Idle")
+
+	got := team.RollupCosts([]*team.Team{payments, growth, idle}, []team.RepoCost{
+		{RepoID: "repo_a", FullName: "acme/billing", TeamID: payments.ID.String(), CostUSD: 2},
+		{RepoID: "repo_b", FullName: "acme/ledger", TeamID: payments.ID.String(), CostUSD: 5},
+		{RepoID: "repo_c", FullName: "acme/web", TeamID: growth.ID.String(), CostUSD: 10},
+		{RepoID: "repo_d", FullName: "acme/scratch", CostUSD: 1.5},
+	})
+
+	require.Len(t, got, 4)
+	assert.Equal(t, "Growth", got[0].TeamName)
+	assert.Equal(t, 10.0, got[0].CostUSD)
+
+	assert.Equal(t, "Payments", got[1].TeamName)
+	assert.Equal(t, 7.0, got[1].CostUSD)
+	require.Len(t, got[1].Repos, 2)
+	assert.Equal(t, "acme/ledger", got[1].Repos[0].FullName, "most expensive repo first")
+
+	assert.Equal(t, idle.ID.String(), got[2].TeamID)
+	assert.Zero(t, got[2].CostUSD)
+	assert.Empty(t, got[2].Repos)
+
+	assert.Empty(t, got[3].TeamID, "repos without a team are grouped last")
+	assert.Equal(t, 1.5, got[3].CostUSD)
+}
+
+func TestNewTeam(t *testing.T) {
+	tm := team.NewTeam("  Payments ")
+	assert.Equal(t, "Payments", tm.Name)
+	assert.Contains(t, tm.ID.String(), "team_")
+	assert.False(t, tm.CreatedAt.IsZero())
+}
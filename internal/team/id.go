@@ -0,0 +1,44 @@
+package team
+
+import (
+	"github.com/cohesivestack/valgo"
+	"github.com/joshjon/kit/id"
+	"go.jetify.com/typeid"
+
+	"github.com/vervesh/verve/internal/msgcat"
+)
+
+type teamPrefix struct{}
+
+func (teamPrefix) Prefix() string { return "team" }
+
+// TeamID is the unique identifier for a Team.
+type TeamID struct {
+	typeid.TypeID[teamPrefix]
+}
+
+// NewTeamID generates a new unique TeamID.
+func NewTeamID() TeamID {
+	return id.New[TeamID]()
+}
+
+// ParseTeamID parses a string into a TeamID.
+func ParseTeamID(s string) (TeamID, error) {
+	return id.Parse[TeamID](s)
+}
+
+// MustParseTeamID parses a string into a TeamID, panicking on failure.
+func MustParseTeamID(s string) TeamID {
+	return id.MustParse[TeamID](s)
+}
+
+// TeamIDValidator returns a valgo Validator that checks whether the given
+// string is a valid TeamID.
+func TeamIDValidator(identifier string, nameAndTitle ...string) *valgo.ValidatorString[string] {
+	return valgo.String(identifier, nameAndTitle...).
+		Not().Blank().
+		Passing(func(_ string) bool {
+			_, err := ParseTeamID(identifier)
+			return err == nil
+		}, msgcat.Text(msgcat.ErrInvalidID, "team"))
+}
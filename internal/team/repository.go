@@ -0,0 +1,12 @@
+package team
+
+import "context"
+
+// Repository is the interface for performing CRUD operations on Teams.
+type Repository interface {
+	CreateTeam(ctx context.Context, team *Team) error
+	ReadTeam(ctx context.Context, id TeamID) (*Team, error)
+	ListTeams(ctx context.Context) ([]*Team, error)
+	UpdateTeam(ctx context.Context, team *Team) error
+	DeleteTeam(ctx context.Context, id TeamID) error
+}
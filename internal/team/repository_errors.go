@@ -0,0 +1,10 @@
+package team
+
+import "github.com/joshjon/kit/errtag"
+
+// ErrTagTeamNotFound tags errors for team-not-found cases (HTTP 404).
+type ErrTagTeamNotFound = errtag.NotFound
+
+// ErrTagTeamConflict tags errors for team-conflict cases, such as a
+// duplicate name (HTTP 409).
+type ErrTagTeamConflict = errtag.Conflict
@@ -0,0 +1,39 @@
+package team
+
+import "context"
+
+// Store wraps a Repository and adds application-level concerns.
+type Store struct {
+	repo Repository
+}
+
+// NewStore creates a new Store backed by the given Repository.
+func NewStore(repo Repository) *Store {
+	return &Store{repo: repo}
+}
+
+// CreateTeam creates a new team.
+func (s *Store) CreateTeam(ctx context.Context, team *Team) error {
+	return s.repo.CreateTeam(ctx, team)
+}
+
+// ReadTeam reads a team by ID.
+func (s *Store) ReadTeam(ctx context.Context, id TeamID) (*Team, error) {
+	return s.repo.ReadTeam(ctx, id)
+}
+
+// ListTeams returns all teams ordered by name.
+func (s *Store) ListTeams(ctx context.Context) ([]*Team, error) {
+	return s.repo.ListTeams(ctx)
+}
+
+// UpdateTeam updates a team's name and settings.
+func (s *Store) UpdateTeam(ctx context.Context, team *Team) error {
+	return s.repo.UpdateTeam(ctx, team)
+}
+
+// DeleteTeam deletes a team and its notification sinks. Its repos are kept
+// and no longer belong to a team.
+func (s *Store) DeleteTeam(ctx context.Context, id TeamID) error {
+	return s.repo.DeleteTeam(ctx, id)
+}
@@ -0,0 +1,32 @@
+package team
+
+import (
+	"strings"
+	"time"
+)
+
+// Team groups repos owned by the same project or team. Repos in a team
+// inherit its settings: work created in them runs with the team's default
+// model and budget unless the request sets its own, and the team's
+// notification sinks receive the events of every repo in it.
+type Team struct {
+	ID   TeamID `json:"id"`
+	Name string `json:"name"`
+	// DefaultModel is the model new tasks, epics and conversations in the
+	// team's repos run with when none is requested. Empty falls back to the
+	// instance-wide default model.
+	DefaultModel string `json:"default_model"`
+	// DefaultMaxCostUSD is the budget of new tasks in the team's repos that
+	// don't set their own. 0 leaves them without a budget.
+	DefaultMaxCostUSD float64   `json:"default_max_cost_usd"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// NewTeam creates a new Team with no settings.
+func NewTeam(name string) *Team {
+	return &Team{
+		ID:        NewTeamID(),
+		Name:      strings.TrimSpace(name),
+		CreatedAt: time.Now(),
+	}
+}
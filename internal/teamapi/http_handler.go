@@ -0,0 +1,249 @@
+package teamapi
+
+import (
+	"cmp"
+	"context"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/joshjon/kit/server"
+	"github.com/labstack/echo/v4"
+
+	"github.com/vervesh/verve/internal/costguard"
+	"github.com/vervesh/verve/internal/logkey"
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/task"
+	"github.com/vervesh/verve/internal/team"
+)
+
+// SpendReader reads each repo's spend over a time window.
+type SpendReader interface {
+	ListRepoSpendSince(ctx context.Context, since time.Time) ([]costguard.RepoSpend, error)
+}
+
+// HTTPHandler handles team HTTP requests.
+type HTTPHandler struct {
+	teamStore *team.Store
+	repoStore *repo.Store
+	taskStore *task.Store
+	spend     SpendReader
+}
+
+// NewHTTPHandler creates a new HTTPHandler.
+func NewHTTPHandler(teamStore *team.Store, repoStore *repo.Store, taskStore *task.Store, spend SpendReader) *HTTPHandler {
+	return &HTTPHandler{
+		teamStore: teamStore,
+		repoStore: repoStore,
+		taskStore: taskStore,
+		spend:     spend,
+	}
+}
+
+// Register adds the team endpoints to the provided Echo router group.
+func (h *HTTPHandler) Register(g *echo.Group) {
+	g.GET("/teams", h.ListTeams)
+	g.POST("/teams", h.CreateTeam)
+	g.GET("/teams/costs", h.GetTeamCosts)
+	g.GET("/teams/:team_id", h.GetTeam)
+	g.PATCH("/teams/:team_id", h.UpdateTeam)
+	g.DELETE("/teams/:team_id", h.DeleteTeam)
+	g.GET("/teams/:team_id/repos", h.ListTeamRepos)
+	g.GET("/teams/:team_id/tasks", h.ListTeamTasks)
+}
+
+// ListTeams handles GET /teams
+func (h *HTTPHandler) ListTeams(c echo.Context) error {
+	teams, err := h.teamStore.ListTeams(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return server.SetResponseList(c, http.StatusOK, teams, "")
+}
+
+// CreateTeam handles POST /teams
+func (h *HTTPHandler) CreateTeam(c echo.Context) error {
+	req, err := server.BindRequest[CreateTeamRequest](c)
+	if err != nil {
+		return err
+	}
+
+	t := team.NewTeam(req.Name)
+	t.DefaultModel = req.DefaultModel
+	t.DefaultMaxCostUSD = req.DefaultMaxCostUSD
+	if err := h.teamStore.CreateTeam(c.Request().Context(), t); err != nil {
+		return err
+	}
+	c.Set(logkey.TeamID, t.ID.String())
+
+	return server.SetResponse(c, http.StatusCreated, t)
+}
+
+// GetTeam handles GET /teams/:team_id
+func (h *HTTPHandler) GetTeam(c echo.Context) error {
+	req, err := server.BindRequest[TeamIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := team.MustParseTeamID(req.TeamID)
+	c.Set(logkey.TeamID, id.String())
+
+	t, err := h.teamStore.ReadTeam(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, t)
+}
+
+// UpdateTeam handles PATCH /teams/:team_id
+func (h *HTTPHandler) UpdateTeam(c echo.Context) error {
+	req, err := server.BindRequest[UpdateTeamRequest](c)
+	if err != nil {
+		return err
+	}
+	id := team.MustParseTeamID(req.TeamID)
+	c.Set(logkey.TeamID, id.String())
+
+	ctx := c.Request().Context()
+
+	t, err := h.teamStore.ReadTeam(ctx, id)
+	if err != nil {
+		return err
+	}
+	if req.Name != nil {
+		t.Name = *req.Name
+	}
+	if req.DefaultModel != nil {
+		t.DefaultModel = *req.DefaultModel
+	}
+	if req.DefaultMaxCostUSD != nil {
+		t.DefaultMaxCostUSD = *req.DefaultMaxCostUSD
+	}
+	if err := h.teamStore.UpdateTeam(ctx, t); err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, t)
+}
+
+// DeleteTeam handles DELETE /teams/:team_id
+// The team's repos are kept and no longer belong to a team.
+func (h *HTTPHandler) DeleteTeam(c echo.Context) error {
+	req, err := server.BindRequest[TeamIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := team.MustParseTeamID(req.TeamID)
+	c.Set(logkey.TeamID, id.String())
+
+	ctx := c.Request().Context()
+
+	if _, err := h.teamStore.ReadTeam(ctx, id); err != nil {
+		return err
+	}
+	if err := h.teamStore.DeleteTeam(ctx, id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListTeamRepos handles GET /teams/:team_id/repos
+func (h *HTTPHandler) ListTeamRepos(c echo.Context) error {
+	req, err := server.BindRequest[TeamIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := team.MustParseTeamID(req.TeamID)
+	c.Set(logkey.TeamID, id.String())
+
+	ctx := c.Request().Context()
+
+	if _, err := h.teamStore.ReadTeam(ctx, id); err != nil {
+		return err
+	}
+	repos, err := h.repoStore.ListReposByTeam(ctx, id.String())
+	if err != nil {
+		return err
+	}
+	return server.SetResponseList(c, http.StatusOK, repos, "")
+}
+
+// ListTeamTasks handles GET /teams/:team_id/tasks?status=<status>
+// Returns the tasks of every repo in the team, newest first.
+func (h *HTTPHandler) ListTeamTasks(c echo.Context) error {
+	req, err := server.BindRequest[ListTeamTasksRequest](c)
+	if err != nil {
+		return err
+	}
+	id := team.MustParseTeamID(req.TeamID)
+	c.Set(logkey.TeamID, id.String())
+
+	ctx := c.Request().Context()
+
+	if _, err := h.teamStore.ReadTeam(ctx, id); err != nil {
+		return err
+	}
+	repos, err := h.repoStore.ListReposByTeam(ctx, id.String())
+	if err != nil {
+		return err
+	}
+
+	tasks := []*task.Task{}
+	for _, r := range repos {
+		repoTasks, err := h.taskStore.ListTasksByRepo(ctx, r.ID.String())
+		if err != nil {
+			return err
+		}
+		for _, t := range repoTasks {
+			if req.Status == "" || t.Status == req.Status {
+				tasks = append(tasks, t)
+			}
+		}
+	}
+	slices.SortStableFunc(tasks, func(a, b *task.Task) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	return server.SetResponseList(c, http.StatusOK, tasks, "")
+}
+
+// GetTeamCosts handles GET /teams/costs?days=<n>
+// Returns the spend of every team over the last n days (30 by default),
+// rolled up from the spend of its repos.
+func (h *HTTPHandler) GetTeamCosts(c echo.Context) error {
+	req, err := server.BindRequest[TeamCostsRequest](c)
+	if err != nil {
+		return err
+	}
+	days := cmp.Or(req.Days, defaultCostDays)
+
+	ctx := c.Request().Context()
+
+	teams, err := h.teamStore.ListTeams(ctx)
+	if err != nil {
+		return err
+	}
+	repos, err := h.repoStore.ListRepos(ctx)
+	if err != nil {
+		return err
+	}
+	spend, err := h.spend.ListRepoSpendSince(ctx, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return err
+	}
+
+	costByRepo := make(map[string]float64, len(spend))
+	for _, s := range spend {
+		costByRepo[s.RepoID] += s.CostUSD
+	}
+	repoCosts := make([]team.RepoCost, len(repos))
+	for i, r := range repos {
+		repoCosts[i] = team.RepoCost{
+			RepoID:   r.ID.String(),
+			FullName: r.FullName,
+			TeamID:   r.TeamID,
+			CostUSD:  costByRepo[r.ID.String()],
+		}
+	}
+
+	return server.SetResponse(c, http.StatusOK, TeamCostsResponse{
+		Days:  days,
+		Teams: team.RollupCosts(teams, repoCosts),
+	})
+}
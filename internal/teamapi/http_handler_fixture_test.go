@@ -0,0 +1,142 @@
+package teamapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/joshjon/kit/server"
+	"github.com/joshjon/kit/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/costguard"
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/sqlite"
+	"github.com/vervesh/verve/internal/task"
+	"github.com/vervesh/verve/internal/team"
+	"github.com/vervesh/verve/internal/teamapi"
+)
+
+type fixture struct {
+	Server    *server.Server
+	TeamStore *team.Store
+	RepoRepo  repo.Repository
+	TaskRepo  task.Repository
+	Spend     *stubSpend
+	Team      *team.Team
+	t         *testing.T
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	db := sqlite.NewTestDB(t)
+	broker := task.NewBroker(nil)
+	taskRepo := sqlite.NewTaskRepository(db)
+	taskStore := task.NewStore(taskRepo, broker)
+
+	repoRepo := sqlite.NewRepoRepository(db)
+	repoStore := repo.NewStore(repoRepo)
+
+	teamStore := team.NewStore(sqlite.NewTeamRepository(db))
+	spend := &stubSpend{}
+
+	handler := teamapi.NewHTTPHandler(teamStore, repoStore, taskStore, spend)
+
+	srv, err := server.NewServer(testutil.GetFreePort(t))
+	require.NoError(t, err)
+	srv.Register("/api/v1", handler)
+
+	go srv.Start()
+	err = srv.WaitHealthy(10, 100*time.Millisecond)
+	require.NoError(t, err)
+
+	// Pre-create a team for use in tests.
+	tm := team.NewTeam("Platform")
+	require.NoError(t, teamStore.CreateTeam(context.Background(), tm))
+
+	t.Cleanup(func() { srv.Stop(context.Background()) })
+
+	return &fixture{
+		Server:    srv,
+		TeamStore: teamStore,
+		RepoRepo:  repoRepo,
+		TaskRepo:  taskRepo,
+		Spend:     spend,
+		Team:      tm,
+		t:         t,
+	}
+}
+
+// stubSpend returns fixed repo spend.
+type stubSpend struct {
+	spend []costguard.RepoSpend
+	since time.Time
+}
+
+func (s *stubSpend) ListRepoSpendSince(_ context.Context, since time.Time) ([]costguard.RepoSpend, error) {
+	s.since = since
+	return s.spend, nil
+}
+
+// --- URL helpers ---
+
+func (f *fixture) teamsURL() string {
+	return fmt.Sprintf("%s/api/v1/teams", f.Server.Address())
+}
+
+func (f *fixture) teamURL(id string) string {
+	return fmt.Sprintf("%s/api/v1/teams/%s", f.Server.Address(), id)
+}
+
+// --- Seed helpers ---
+
+// seedRepo creates a repo, adding it to the team when teamID is set.
+func (f *fixture) seedRepo(fullName, teamID string) *repo.Repo {
+	f.t.Helper()
+	ctx := context.Background()
+	r, err := repo.NewRepo(fullName)
+	require.NoError(f.t, err)
+	require.NoError(f.t, f.RepoRepo.CreateRepo(ctx, r))
+	if teamID != "" {
+		require.NoError(f.t, f.RepoRepo.UpdateRepoTeam(ctx, r.ID, teamID))
+		r.TeamID = teamID
+	}
+	return r
+}
+
+func (f *fixture) seedTask(r *repo.Repo, title string, status task.Status) *task.Task {
+	f.t.Helper()
+	ctx := context.Background()
+	tsk := task.NewTask(r.ID.String(), title, "desc", nil, nil, 0, false, false, "sonnet", true)
+	require.NoError(f.t, f.TaskRepo.CreateTask(ctx, tsk))
+	if status != task.StatusPending {
+		require.NoError(f.t, f.TaskRepo.UpdateTaskStatus(ctx, tsk.ID, status))
+	}
+	return tsk
+}
+
+// --- HTTP helpers ---
+
+func doJSON(t *testing.T, method, url string, body any) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, mustJSONReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	httpRes, err := testutil.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return httpRes
+}
+
+func mustJSONReader(v any) io.Reader {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return bytes.NewReader(b)
+}
@@ -0,0 +1,176 @@
+package teamapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/joshjon/kit/server"
+	"github.com/joshjon/kit/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/costguard"
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/task"
+	"github.com/vervesh/verve/internal/team"
+	"github.com/vervesh/verve/internal/teamapi"
+)
+
+// --- Create Team ---
+
+func TestCreateTeam_Success(t *testing.T) {
+	f := newFixture(t)
+
+	req := teamapi.CreateTeamRequest{Name: " Payments ", DefaultModel: "opus", DefaultMaxCostUSD: 5}
+	res := testutil.Post[server.Response[team.Team]](t, f.teamsURL(), req)
+	assert.Equal(t, "Payments", res.Data.Name)
+	assert.Equal(t, "opus", res.Data.DefaultModel)
+	assert.InDelta(t, 5.0, res.Data.DefaultMaxCostUSD, 0.001)
+
+	list := testutil.Get[server.ResponseList[team.Team]](t, f.teamsURL())
+	require.Len(t, list.Data, 2)
+	assert.Equal(t, "Payments", list.Data[0].Name, "teams are listed by name")
+}
+
+func TestCreateTeam_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		req  teamapi.CreateTeamRequest
+	}{
+		{"blank name", teamapi.CreateTeamRequest{Name: "  "}},
+		{"negative budget", teamapi.CreateTeamRequest{Name: "Payments", DefaultMaxCostUSD: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			httpRes := doJSON(t, http.MethodPost, f.teamsURL(), tt.req)
+			defer httpRes.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+		})
+	}
+}
+
+func TestCreateTeam_DuplicateName(t *testing.T) {
+	f := newFixture(t)
+
+	httpRes := doJSON(t, http.MethodPost, f.teamsURL(), teamapi.CreateTeamRequest{Name: f.Team.Name})
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusConflict, httpRes.StatusCode)
+}
+
+// --- Get / Update / Delete Team ---
+
+func TestGetTeam_NotFound(t *testing.T) {
+	f := newFixture(t)
+
+	httpRes, err := http.Get(f.teamURL(team.NewTeamID().String()))
+	require.NoError(t, err)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusNotFound, httpRes.StatusCode)
+}
+
+func TestUpdateTeam(t *testing.T) {
+	f := newFixture(t)
+
+	budget := 2.5
+	httpRes := doJSON(t, http.MethodPatch, f.teamURL(f.Team.ID.String()), teamapi.UpdateTeamRequest{DefaultMaxCostUSD: &budget})
+	defer httpRes.Body.Close()
+	require.Equal(t, http.StatusOK, httpRes.StatusCode)
+
+	res := testutil.Get[server.Response[team.Team]](t, f.teamURL(f.Team.ID.String()))
+	assert.Equal(t, "Platform", res.Data.Name, "omitted fields are unchanged")
+	assert.InDelta(t, 2.5, res.Data.DefaultMaxCostUSD, 0.001)
+}
+
+func TestDeleteTeam_KeepsRepos(t *testing.T) {
+	f := newFixture(t)
+	r := f.seedRepo("owner/api", f.Team.ID.String())
+
+	testutil.Delete(t, f.teamURL(f.Team.ID.String()))
+
+	httpRes, err := http.Get(f.teamURL(f.Team.ID.String()))
+	require.NoError(t, err)
+	httpRes.Body.Close()
+	assert.Equal(t, http.StatusNotFound, httpRes.StatusCode)
+
+	read, err := f.RepoRepo.ReadRepo(context.Background(), r.ID)
+	require.NoError(t, err)
+	assert.Empty(t, read.TeamID)
+}
+
+// --- Team Repos and Tasks ---
+
+func TestListTeamRepos(t *testing.T) {
+	f := newFixture(t)
+	f.seedRepo("owner/web", f.Team.ID.String())
+	f.seedRepo("owner/api", f.Team.ID.String())
+	f.seedRepo("owner/other", "")
+
+	res := testutil.Get[server.ResponseList[repo.Repo]](t, f.teamURL(f.Team.ID.String())+"/repos")
+	require.Len(t, res.Data, 2)
+	assert.Equal(t, "owner/api", res.Data[0].FullName)
+	assert.Equal(t, "owner/web", res.Data[1].FullName)
+}
+
+func TestListTeamTasks(t *testing.T) {
+	f := newFixture(t)
+	api := f.seedRepo("owner/api", f.Team.ID.String())
+	web := f.seedRepo("owner/web", f.Team.ID.String())
+	other := f.seedRepo("owner/other", "")
+	f.seedTask(api, "API task", task.StatusPending)
+	failed := f.seedTask(web, "Web task", task.StatusFailed)
+	f.seedTask(other, "Other task", task.StatusFailed)
+
+	res := testutil.Get[server.ResponseList[task.Task]](t, f.teamURL(f.Team.ID.String())+"/tasks")
+	assert.Len(t, res.Data, 2, "only tasks of the team's repos are listed")
+
+	res = testutil.Get[server.ResponseList[task.Task]](t, f.teamURL(f.Team.ID.String())+"/tasks?status=failed")
+	require.Len(t, res.Data, 1)
+	assert.Equal(t, failed.ID, res.Data[0].ID)
+
+	httpRes, err := http.Get(f.teamURL(f.Team.ID.String()) + "/tasks?status=bogus")
+	require.NoError(t, err)
+	httpRes.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+}
+
+// --- Team Costs ---
+
+func TestGetTeamCosts(t *testing.T) {
+	f := newFixture(t)
+	api := f.seedRepo("owner/api", f.Team.ID.String())
+	web := f.seedRepo("owner/web", f.Team.ID.String())
+	other := f.seedRepo("owner/other", "")
+	f.Spend.spend = []costguard.RepoSpend{
+		{RepoID: api.ID.String(), CostUSD: 1.5},
+		{RepoID: web.ID.String(), CostUSD: 2},
+		{RepoID: other.ID.String(), CostUSD: 4},
+	}
+
+	res := testutil.Get[server.Response[teamapi.TeamCostsResponse]](t, f.teamsURL()+"/costs?days=7")
+	assert.Equal(t, 7, res.Data.Days)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, -7), f.Spend.since, time.Minute)
+	require.Len(t, res.Data.Teams, 2)
+	assert.Equal(t, f.Team.ID.String(), res.Data.Teams[0].TeamID)
+	assert.InDelta(t, 3.5, res.Data.Teams[0].CostUSD, 0.001)
+	require.Len(t, res.Data.Teams[0].Repos, 2)
+	assert.Equal(t, "owner/web", res.Data.Teams[0].Repos[0].FullName)
+	assert.Empty(t, res.Data.Teams[1].TeamID, "repos without a team are grouped last")
+	assert.InDelta(t, 4.0, res.Data.Teams[1].CostUSD, 0.001)
+}
+
+func TestGetTeamCosts_DefaultWindow(t *testing.T) {
+	f := newFixture(t)
+
+	res := testutil.Get[server.Response[teamapi.TeamCostsResponse]](t, f.teamsURL()+"/costs")
+	assert.Equal(t, 30, res.Data.Days)
+	require.Len(t, res.Data.Teams, 1)
+	assert.Zero(t, res.Data.Teams[0].CostUSD)
+
+	httpRes, err := http.Get(f.teamsURL() + "/costs?days=400")
+	require.NoError(t, err)
+	httpRes.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+}
@@ -0,0 +1,88 @@
+package teamapi
+
+import (
+	"github.com/cohesivestack/valgo"
+
+	"github.com/vervesh/verve/internal/task"
+	"github.com/vervesh/verve/internal/team"
+)
+
+const (
+	defaultCostDays = 30  // Reporting window of GET /teams/costs
+	maxCostDays     = 365 // Longest reporting window of GET /teams/costs
+)
+
+// TeamIDRequest captures the :team_id path parameter.
+type TeamIDRequest struct {
+	TeamID string `param:"team_id" json:"-"`
+}
+
+func (r TeamIDRequest) Validate() error {
+	return valgo.In("params", valgo.Is(team.TeamIDValidator(r.TeamID, "team_id"))).ToError()
+}
+
+// CreateTeamRequest is the request body for creating a team.
+type CreateTeamRequest struct {
+	Name              string  `json:"name"`
+	DefaultModel      string  `json:"default_model,omitempty"`
+	DefaultMaxCostUSD float64 `json:"default_max_cost_usd,omitempty"`
+}
+
+func (r CreateTeamRequest) Validate() error {
+	v := valgo.Is(valgo.String(r.Name, "name").Not().Blank().MaxLength(100))
+	if r.DefaultMaxCostUSD < 0 {
+		v = v.AddErrorMessage("default_max_cost_usd", "must not be negative")
+	}
+	return v.ToError()
+}
+
+// UpdateTeamRequest is the request body for updating a team. Only the fields
+// that are set are changed.
+type UpdateTeamRequest struct {
+	TeamID            string   `param:"team_id" json:"-"`
+	Name              *string  `json:"name,omitempty"`
+	DefaultModel      *string  `json:"default_model,omitempty"`
+	DefaultMaxCostUSD *float64 `json:"default_max_cost_usd,omitempty"`
+}
+
+func (r UpdateTeamRequest) Validate() error {
+	v := valgo.In("params", valgo.Is(team.TeamIDValidator(r.TeamID, "team_id")))
+	if r.Name != nil {
+		v = v.Is(valgo.String(*r.Name, "name").Not().Blank().MaxLength(100))
+	}
+	if r.DefaultMaxCostUSD != nil && *r.DefaultMaxCostUSD < 0 {
+		v = v.AddErrorMessage("default_max_cost_usd", "must not be negative")
+	}
+	return v.ToError()
+}
+
+// ListTeamTasksRequest captures the :team_id path parameter and the
+// optional ?status= filter.
+type ListTeamTasksRequest struct {
+	TeamID string      `param:"team_id" json:"-"`
+	Status task.Status `query:"status" json:"-"`
+}
+
+func (r ListTeamTasksRequest) Validate() error {
+	v := valgo.In("params", valgo.Is(team.TeamIDValidator(r.TeamID, "team_id")))
+	if r.Status != "" && !task.ValidStatus(r.Status) {
+		v = v.AddErrorMessage("status", "must be a valid task status")
+	}
+	return v.ToError()
+}
+
+// TeamCostsRequest captures the optional ?days= reporting window. 0 uses
+// the default of 30 days.
+type TeamCostsRequest struct {
+	Days int `query:"days" json:"-"`
+}
+
+func (r TeamCostsRequest) Validate() error {
+	return valgo.Is(valgo.Int(r.Days, "days").Between(0, maxCostDays)).ToError()
+}
+
+// TeamCostsResponse reports spend by team over the reporting window.
+type TeamCostsResponse struct {
+	Days  int             `json:"days"`
+	Teams []team.TeamCost `json:"teams"`
+}
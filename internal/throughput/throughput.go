@@ -0,0 +1,211 @@
+// Package throughput reports how many tasks are created, merged and failed
+// each day and how long tasks take to move through the task lifecycle,
+// computed from the task status changes recorded in the audit log.
+package throughput
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/vervesh/verve/internal/costguard"
+	"github.com/vervesh/verve/internal/task"
+)
+
+// dateLayout is the layout of the days a report is bucketed by, in UTC.
+const dateLayout = "2006-01-02"
+
+// Creation is a task created in a repo.
+type Creation struct {
+	RepoID    string
+	CreatedAt time.Time
+}
+
+// Transition is a task moving to a new status, as recorded in the audit log.
+type Transition struct {
+	TaskID string
+	RepoID string
+	// TaskCreatedAt is when the task was created, and so entered pending
+	// before its first transition.
+	TaskCreatedAt time.Time
+	To            task.Status
+	At            time.Time
+}
+
+// Repository reads the task history a report is computed from.
+type Repository interface {
+	// ListCreationsSince returns the tasks created at or after since, oldest
+	// first.
+	ListCreationsSince(ctx context.Context, since time.Time) ([]Creation, error)
+	// ListTransitionsSince returns every recorded transition of the tasks
+	// that changed status at or after since, grouped by task and oldest
+	// first within each task.
+	ListTransitionsSince(ctx context.Context, since time.Time) ([]Transition, error)
+}
+
+// Report is the throughput of each repo over a reporting window, and the
+// cycle times of every repo together.
+type Report struct {
+	Since      time.Time  `json:"since"`
+	Days       int        `json:"days"`
+	Repos      []Repo     `json:"repos"`
+	CycleTimes CycleTimes `json:"cycle_times"`
+}
+
+// Repo is the throughput of one repo over the reporting window.
+type Repo struct {
+	RepoID  string `json:"repo_id"`
+	Created int    `json:"created"`
+	Merged  int    `json:"merged"`
+	Failed  int    `json:"failed"`
+	// PerDay has one entry for every day of the window, oldest first.
+	PerDay     []Day      `json:"per_day"`
+	CycleTimes CycleTimes `json:"cycle_times"`
+}
+
+// Day counts the tasks of a repo created, merged and failed on one UTC day.
+type Day struct {
+	Date    string `json:"date"`
+	Created int    `json:"created"`
+	Merged  int    `json:"merged"`
+	Failed  int    `json:"failed"`
+}
+
+// CycleTimes are the percentiles of how long tasks took to move between
+// statuses, for moves made in the reporting window.
+type CycleTimes struct {
+	// From pending, when created or requeued, until an agent claimed it
+	PendingToRunning Percentiles `json:"pending_to_running"`
+	// From claimed until the agent's PR was ready for review
+	RunningToReview Percentiles `json:"running_to_review"`
+	// From ready for review until merged
+	ReviewToMerged Percentiles `json:"review_to_merged"`
+}
+
+// Percentiles summarizes durations in seconds.
+type Percentiles struct {
+	Count      int     `json:"count"`
+	P50Seconds float64 `json:"p50_seconds"`
+	P90Seconds float64 `json:"p90_seconds"`
+	P95Seconds float64 `json:"p95_seconds"`
+}
+
+func newPercentiles(seconds []float64) Percentiles {
+	return Percentiles{
+		Count:      len(seconds),
+		P50Seconds: costguard.Percentile(seconds, 50),
+		P90Seconds: costguard.Percentile(seconds, 90),
+		P95Seconds: costguard.Percentile(seconds, 95),
+	}
+}
+
+// durations collects the cycle times making up CycleTimes.
+type durations struct {
+	pendingToRunning []float64
+	runningToReview  []float64
+	reviewToMerged   []float64
+}
+
+func (d *durations) add(from, to task.Status, seconds float64) {
+	switch {
+	case from == task.StatusPending && to == task.StatusRunning:
+		d.pendingToRunning = append(d.pendingToRunning, seconds)
+	case from == task.StatusRunning && to == task.StatusReview:
+		d.runningToReview = append(d.runningToReview, seconds)
+	case from == task.StatusReview && to == task.StatusMerged:
+		d.reviewToMerged = append(d.reviewToMerged, seconds)
+	}
+}
+
+func (d *durations) cycleTimes() CycleTimes {
+	return CycleTimes{
+		PendingToRunning: newPercentiles(d.pendingToRunning),
+		RunningToReview:  newPercentiles(d.runningToReview),
+		ReviewToMerged:   newPercentiles(d.reviewToMerged),
+	}
+}
+
+// Since returns the start of the days-long reporting window ending at now:
+// midnight UTC days-1 days before now.
+func Since(days int, now time.Time) time.Time {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1-days)
+}
+
+// Compute builds the report of the days-long window ending at now. When
+// repoID is non-empty only that repo is reported on.
+//
+// A task's first transition is taken to be from pending, which it entered
+// when created. The audit log only recorded merges and closes before it
+// recorded every transition, so a task whose history starts with one of
+// those contributes no cycle times.
+func Compute(creations []Creation, transitions []Transition, repoID string, days int, now time.Time) *Report {
+	start := Since(days, now)
+	report := &Report{Since: start, Days: days, Repos: []Repo{}}
+
+	repos := make(map[string]*Repo)
+	repoDurations := make(map[string]*durations)
+	dayIndex := func(at time.Time) int {
+		return int(at.UTC().Sub(start).Hours() / 24)
+	}
+	repoFor := func(id string) *Repo {
+		r, ok := repos[id]
+		if !ok {
+			r = &Repo{RepoID: id, PerDay: make([]Day, days)}
+			for i := range r.PerDay {
+				r.PerDay[i].Date = start.AddDate(0, 0, i).Format(dateLayout)
+			}
+			repos[id] = r
+			repoDurations[id] = &durations{}
+		}
+		return r
+	}
+	include := func(id string, at time.Time) bool {
+		return (repoID == "" || id == repoID) && !at.Before(start) && !at.After(now)
+	}
+
+	for _, c := range creations {
+		if !include(c.RepoID, c.CreatedAt) {
+			continue
+		}
+		r := repoFor(c.RepoID)
+		r.Created++
+		r.PerDay[dayIndex(c.CreatedAt)].Created++
+	}
+
+	var all durations
+	status, enteredAt, taskID := task.StatusPending, time.Time{}, ""
+	for _, tr := range transitions {
+		if tr.TaskID != taskID {
+			status, enteredAt, taskID = task.StatusPending, tr.TaskCreatedAt, tr.TaskID
+		}
+		from, fromAt := status, enteredAt
+		status, enteredAt = tr.To, tr.At
+		if !include(tr.RepoID, tr.At) {
+			continue
+		}
+		r := repoFor(tr.RepoID)
+		switch tr.To {
+		case task.StatusMerged:
+			r.Merged++
+			r.PerDay[dayIndex(tr.At)].Merged++
+		case task.StatusFailed:
+			r.Failed++
+			r.PerDay[dayIndex(tr.At)].Failed++
+		}
+		seconds := tr.At.Sub(fromAt).Seconds()
+		repoDurations[tr.RepoID].add(from, tr.To, seconds)
+		all.add(from, tr.To, seconds)
+	}
+
+	for id, r := range repos {
+		r.CycleTimes = repoDurations[id].cycleTimes()
+		report.Repos = append(report.Repos, *r)
+	}
+	slices.SortFunc(report.Repos, func(a, b Repo) int {
+		return cmp.Compare(a.RepoID, b.RepoID)
+	})
+	report.CycleTimes = all.cycleTimes()
+	return report
+}
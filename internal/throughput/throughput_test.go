@@ -0,0 +1,95 @@
+package throughput
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/task"
+)
+
+func TestSince(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), Since(1, now))
+	assert.Equal(t, time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC), Since(30, now))
+}
+
+func TestCompute(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	day := func(d int, hour int) time.Time {
+		return time.Date(2026, 3, d, hour, 0, 0, 0, time.UTC)
+	}
+
+	creations := []Creation{
+		{RepoID: "repo_a", CreatedAt: day(1, 9)},
+		{RepoID: "repo_a", CreatedAt: day(8, 9)},
+		{RepoID: "repo_a", CreatedAt: day(8, 10)},
+		{RepoID: "repo_b", CreatedAt: day(9, 9)},
+	}
+	transitions := []Transition{
+		// Created before the window; merged in it.
+		{TaskID: "tsk_1", RepoID: "repo_a", TaskCreatedAt: day(1, 9), To: task.StatusRunning, At: day(1, 10)},
+		{TaskID: "tsk_1", RepoID: "repo_a", TaskCreatedAt: day(1, 9), To: task.StatusReview, At: day(1, 12)},
+		{TaskID: "tsk_1", RepoID: "repo_a", TaskCreatedAt: day(1, 9), To: task.StatusMerged, At: day(8, 12)},
+		{TaskID: "tsk_2", RepoID: "repo_a", TaskCreatedAt: day(8, 9), To: task.StatusRunning, At: day(8, 10)},
+		{TaskID: "tsk_2", RepoID: "repo_a", TaskCreatedAt: day(8, 9), To: task.StatusReview, At: day(8, 14)},
+		{TaskID: "tsk_2", RepoID: "repo_a", TaskCreatedAt: day(8, 9), To: task.StatusMerged, At: day(9, 14)},
+		// Retried once before failing.
+		{TaskID: "tsk_3", RepoID: "repo_b", TaskCreatedAt: day(9, 9), To: task.StatusRunning, At: day(9, 12)},
+		{TaskID: "tsk_3", RepoID: "repo_b", TaskCreatedAt: day(9, 9), To: task.StatusPending, At: day(9, 13)},
+		{TaskID: "tsk_3", RepoID: "repo_b", TaskCreatedAt: day(9, 9), To: task.StatusRunning, At: day(9, 14)},
+		{TaskID: "tsk_3", RepoID: "repo_b", TaskCreatedAt: day(9, 9), To: task.StatusFailed, At: day(10, 9)},
+		// Only its merge was recorded, so it has no cycle times.
+		{TaskID: "tsk_4", RepoID: "repo_b", TaskCreatedAt: day(2, 9), To: task.StatusMerged, At: day(10, 10)},
+	}
+
+	report := Compute(creations, transitions, "", 3, now)
+	assert.Equal(t, day(8, 0), report.Since)
+	assert.Equal(t, 3, report.Days)
+	require.Len(t, report.Repos, 2)
+
+	a := report.Repos[0]
+	assert.Equal(t, "repo_a", a.RepoID)
+	assert.Equal(t, 2, a.Created)
+	assert.Equal(t, 2, a.Merged)
+	assert.Zero(t, a.Failed)
+	assert.Equal(t, []Day{
+		{Date: "2026-03-08", Created: 2, Merged: 1},
+		{Date: "2026-03-09", Merged: 1},
+		{Date: "2026-03-10"},
+	}, a.PerDay)
+	assert.Equal(t, 1, a.CycleTimes.PendingToRunning.Count)
+	assert.Equal(t, time.Hour.Seconds(), a.CycleTimes.PendingToRunning.P50Seconds)
+	assert.Equal(t, 1, a.CycleTimes.RunningToReview.Count)
+	assert.Equal(t, 4*time.Hour.Seconds(), a.CycleTimes.RunningToReview.P50Seconds)
+	assert.Equal(t, 2, a.CycleTimes.ReviewToMerged.Count)
+	assert.Equal(t, 24*time.Hour.Seconds(), a.CycleTimes.ReviewToMerged.P50Seconds)
+	assert.Equal(t, 168*time.Hour.Seconds(), a.CycleTimes.ReviewToMerged.P95Seconds)
+
+	b := report.Repos[1]
+	assert.Equal(t, "repo_b", b.RepoID)
+	assert.Equal(t, 1, b.Created)
+	assert.Equal(t, 1, b.Merged)
+	assert.Equal(t, 1, b.Failed)
+	assert.Equal(t, 2, b.CycleTimes.PendingToRunning.Count)
+	assert.Equal(t, time.Hour.Seconds(), b.CycleTimes.PendingToRunning.P50Seconds)
+	assert.Equal(t, 3*time.Hour.Seconds(), b.CycleTimes.PendingToRunning.P95Seconds)
+	assert.Zero(t, b.CycleTimes.ReviewToMerged.Count)
+
+	assert.Equal(t, 3, report.CycleTimes.PendingToRunning.Count)
+	assert.Equal(t, 2, report.CycleTimes.ReviewToMerged.Count)
+
+	report = Compute(creations, transitions, "repo_b", 3, now)
+	require.Len(t, report.Repos, 1)
+	assert.Equal(t, "repo_b", report.Repos[0].RepoID)
+	assert.Equal(t, 2, report.CycleTimes.PendingToRunning.Count)
+}
+
+func TestCompute_Empty(t *testing.T) {
+	report := Compute(nil, nil, "", 30, time.Now())
+	assert.NotNil(t, report.Repos)
+	assert.Empty(t, report.Repos)
+	assert.Zero(t, report.CycleTimes.ReviewToMerged.Count)
+}
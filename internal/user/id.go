@@ -0,0 +1,63 @@
+package user
+
+import (
+	"github.com/cohesivestack/valgo"
+	"github.com/joshjon/kit/id"
+	"go.jetify.com/typeid"
+
+	"github.com/vervesh/verve/internal/msgcat"
+)
+
+type userPrefix struct{}
+
+func (userPrefix) Prefix() string { return "usr" }
+
+// UserID is the unique identifier for a User.
+type UserID struct {
+	typeid.TypeID[userPrefix]
+}
+
+// NewUserID generates a new unique UserID.
+func NewUserID() UserID {
+	return id.New[UserID]()
+}
+
+// ParseUserID parses a string into a UserID.
+func ParseUserID(s string) (UserID, error) {
+	return id.Parse[UserID](s)
+}
+
+// MustParseUserID parses a string into a UserID, panicking on failure.
+func MustParseUserID(s string) UserID {
+	return id.MustParse[UserID](s)
+}
+
+// UserIDValidator returns a valgo Validator that checks whether the given
+// string is a valid UserID.
+func UserIDValidator(identifier string, nameAndTitle ...string) *valgo.ValidatorString[string] {
+	return valgo.String(identifier, nameAndTitle...).
+		Not().Blank().
+		Passing(func(_ string) bool {
+			_, err := ParseUserID(identifier)
+			return err == nil
+		}, msgcat.Text(msgcat.ErrInvalidID, "user"))
+}
+
+type sessionPrefix struct{}
+
+func (sessionPrefix) Prefix() string { return "sess" }
+
+// SessionID is the unique identifier for a Session.
+type SessionID struct {
+	typeid.TypeID[sessionPrefix]
+}
+
+// NewSessionID generates a new unique SessionID.
+func NewSessionID() SessionID {
+	return id.New[SessionID]()
+}
+
+// MustParseSessionID parses a string into a SessionID, panicking on failure.
+func MustParseSessionID(s string) SessionID {
+	return id.MustParse[SessionID](s)
+}
@@ -0,0 +1,22 @@
+package user
+
+import (
+	"context"
+	"time"
+)
+
+// Repository is the interface for performing CRUD operations on Users.
+type Repository interface {
+	CreateUser(ctx context.Context, user *User) error
+	ReadUser(ctx context.Context, id UserID) (*User, error)
+	ReadUserByTokenHash(ctx context.Context, tokenHash string) (*User, error)
+	ListUsers(ctx context.Context) ([]*User, error)
+	UpdateUserRole(ctx context.Context, id UserID, role Role) error
+	UpdateUserTokenHash(ctx context.Context, id UserID, tokenHash string) error
+	DeleteUser(ctx context.Context, id UserID) error
+
+	CreateSession(ctx context.Context, session *Session) error
+	ReadSessionByTokenHash(ctx context.Context, tokenHash string) (*Session, error)
+	DeleteSessionByTokenHash(ctx context.Context, tokenHash string) error
+	DeleteExpiredSessions(ctx context.Context, now time.Time) error
+}
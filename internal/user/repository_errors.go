@@ -0,0 +1,10 @@
+package user
+
+import "github.com/joshjon/kit/errtag"
+
+// ErrTagUserNotFound tags errors for user-not-found cases (HTTP 404).
+type ErrTagUserNotFound = errtag.NotFound
+
+// ErrTagUserConflict tags errors for user-conflict cases, such as a
+// duplicate name (HTTP 409).
+type ErrTagUserConflict = errtag.Conflict
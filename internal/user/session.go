@@ -0,0 +1,54 @@
+package user
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// sessionTokenPrefix marks session tokens, telling them apart from the API
+// tokens of users.
+const sessionTokenPrefix = "vs_"
+
+// Session is a short-lived sign-in through single sign-on. It carries the
+// role the identity provider's groups mapped to when it was issued, so
+// people signing in with SSO don't need a user of their own.
+type Session struct {
+	ID        SessionID `json:"id"`
+	Subject   string    `json:"subject"` // The identity provider's ID for the person
+	Name      string    `json:"name"`
+	Role      Role      `json:"role"`
+	TokenHash string    `json:"-"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewSession creates a new Session lasting ttl with a freshly generated
+// token, which is returned alongside it.
+func NewSession(subject, name string, role Role, ttl time.Duration) (*Session, string) {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	token := sessionTokenPrefix + hex.EncodeToString(b)
+	now := time.Now()
+	return &Session{
+		ID:        NewSessionID(),
+		Subject:   subject,
+		Name:      strings.TrimSpace(name),
+		Role:      role,
+		TokenHash: HashToken(token),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}, token
+}
+
+// Expired reports whether the session has ended.
+func (s *Session) Expired() bool {
+	return !time.Now().Before(s.ExpiresAt)
+}
+
+// IsSessionToken reports whether token is a session token rather than a
+// user's API token.
+func IsSessionToken(token string) bool {
+	return strings.HasPrefix(token, sessionTokenPrefix)
+}
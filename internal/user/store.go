@@ -0,0 +1,98 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joshjon/kit/errtag"
+)
+
+// Store wraps a Repository and adds application-level concerns.
+type Store struct {
+	repo Repository
+}
+
+// NewStore creates a new Store backed by the given Repository.
+func NewStore(repo Repository) *Store {
+	return &Store{repo: repo}
+}
+
+// CreateUser creates a new user with the given role and returns the user's
+// API token. The token can't be recovered later, only rotated.
+func (s *Store) CreateUser(ctx context.Context, name string, role Role) (*User, string, error) {
+	u, token := NewUser(name, role)
+	if err := s.repo.CreateUser(ctx, u); err != nil {
+		return nil, "", err
+	}
+	return u, token, nil
+}
+
+// ReadUser reads a user by ID.
+func (s *Store) ReadUser(ctx context.Context, id UserID) (*User, error) {
+	return s.repo.ReadUser(ctx, id)
+}
+
+// Authenticate returns the user holding token: a user's API token or an
+// unexpired session token. Sessions authenticate as a user with an empty ID
+// and the session's name and role.
+func (s *Store) Authenticate(ctx context.Context, token string) (*User, error) {
+	if !IsSessionToken(token) {
+		return s.repo.ReadUserByTokenHash(ctx, HashToken(token))
+	}
+	session, err := s.repo.ReadSessionByTokenHash(ctx, HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if session.Expired() {
+		return nil, errtag.Tag[ErrTagUserNotFound](errors.New("session expired"))
+	}
+	return &User{Name: session.Name, Role: session.Role, CreatedAt: session.CreatedAt}, nil
+}
+
+// ListUsers returns all users ordered by name.
+func (s *Store) ListUsers(ctx context.Context) ([]*User, error) {
+	return s.repo.ListUsers(ctx)
+}
+
+// AssignRole changes a user's role. It takes effect on the user's next
+// request.
+func (s *Store) AssignRole(ctx context.Context, id UserID, role Role) (*User, error) {
+	if err := s.repo.UpdateUserRole(ctx, id, role); err != nil {
+		return nil, err
+	}
+	return s.repo.ReadUser(ctx, id)
+}
+
+// RotateToken replaces a user's API token and returns the new one. The old
+// token stops working immediately.
+func (s *Store) RotateToken(ctx context.Context, id UserID) (string, error) {
+	token := GenerateToken()
+	if err := s.repo.UpdateUserTokenHash(ctx, id, HashToken(token)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// DeleteUser deletes a user, revoking their token.
+func (s *Store) DeleteUser(ctx context.Context, id UserID) error {
+	return s.repo.DeleteUser(ctx, id)
+}
+
+// CreateSession signs in a person authenticated by the identity provider for
+// ttl and returns the session token. Expired sessions are removed first.
+func (s *Store) CreateSession(ctx context.Context, subject, name string, role Role, ttl time.Duration) (*Session, string, error) {
+	if err := s.repo.DeleteExpiredSessions(ctx, time.Now()); err != nil {
+		return nil, "", err
+	}
+	session, token := NewSession(subject, name, role, ttl)
+	if err := s.repo.CreateSession(ctx, session); err != nil {
+		return nil, "", err
+	}
+	return session, token, nil
+}
+
+// EndSession signs out the holder of a session token.
+func (s *Store) EndSession(ctx context.Context, token string) error {
+	return s.repo.DeleteSessionByTokenHash(ctx, HashToken(token))
+}
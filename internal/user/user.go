@@ -0,0 +1,86 @@
+package user
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// Role grants a user access to the API. Each role can do everything the
+// roles below it can.
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"   // Read tasks, epics and their logs
+	RoleOperator Role = "operator" // Create, retry, close and otherwise act on tasks and epics
+	RoleAdmin    Role = "admin"    // Manage repos, settings, teams and users
+)
+
+// AllRoles lists every role from least to most privileged.
+var AllRoles = []Role{RoleViewer, RoleOperator, RoleAdmin}
+
+// ValidRole reports whether r is a known role.
+func ValidRole(r string) bool {
+	return Role(r).rank() > 0
+}
+
+// Allows reports whether the role grants at least the access of required.
+func (r Role) Allows(required Role) bool {
+	return r.rank() > 0 && r.rank() >= required.rank()
+}
+
+func (r Role) rank() int {
+	switch r {
+	case RoleViewer:
+		return 1
+	case RoleOperator:
+		return 2
+	case RoleAdmin:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// tokenPrefix marks Verve user tokens so they are recognizable in config
+// files and secret scanners.
+const tokenPrefix = "vu_"
+
+// User is a person or integration calling the API with their own token.
+type User struct {
+	ID        UserID    `json:"id"`
+	Name      string    `json:"name"`
+	Role      Role      `json:"role"`
+	TokenHash string    `json:"-"` // SHA-256 of the token; the token itself is only returned once, when the user is created
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewUser creates a new User with a freshly generated API token, which is
+// returned alongside it. Only the token's hash is kept on the user.
+func NewUser(name string, role Role) (*User, string) {
+	token := GenerateToken()
+	return &User{
+		ID:        NewUserID(),
+		Name:      strings.TrimSpace(name),
+		Role:      role,
+		TokenHash: HashToken(token),
+		CreatedAt: time.Now(),
+	}, token
+}
+
+// GenerateToken returns a random API token.
+func GenerateToken() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return tokenPrefix + hex.EncodeToString(b)
+}
+
+// HashToken returns the hex-encoded SHA-256 of token, as stored on a User.
+// Tokens are random and long, so an unsalted hash is enough to keep a
+// database leak from exposing them.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
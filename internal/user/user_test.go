@@ -0,0 +1,42 @@
+package user_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vervesh/verve/internal/user"
+)
+
+func TestRole_Allows(t *testing.T) {
+	tests := []struct {
+		role     user.Role
+		required user.Role
+		want     bool
+	}{
+		{user.RoleViewer, user.RoleViewer, true},
+		{user.RoleViewer, user.RoleOperator, false},
+		{user.RoleOperator, user.RoleViewer, true},
+		{user.RoleOperator, user.RoleAdmin, false},
+		{user.RoleAdmin, user.RoleOperator, true},
+		{user.Role("owner"), user.RoleViewer, false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.role)+"/"+string(tt.required), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.role.Allows(tt.required))
+		})
+	}
+}
+
+func TestNewUser_Token(t *testing.T) {
+	u, token := user.NewUser(" Ada ", user.RoleOperator)
+
+	assert.Equal(t, "Ada", u.Name)
+	assert.True(t, strings.HasPrefix(token, "vu_"))
+	assert.Equal(t, user.HashToken(token), u.TokenHash)
+	assert.NotContains(t, u.TokenHash, token)
+
+	_, other := user.NewUser("Grace", user.RoleViewer)
+	assert.NotEqual(t, token, other)
+}
@@ -0,0 +1,178 @@
+package userapi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/joshjon/kit/server"
+	"github.com/labstack/echo/v4"
+
+	"github.com/vervesh/verve/internal/logkey"
+	"github.com/vervesh/verve/internal/msgcat"
+	"github.com/vervesh/verve/internal/user"
+)
+
+// HTTPHandler handles user HTTP requests. Every endpoint but GET /users/me
+// is for admins.
+type HTTPHandler struct {
+	store *user.Store
+	auth  *Authenticator
+}
+
+// NewHTTPHandler creates a new HTTPHandler.
+func NewHTTPHandler(store *user.Store, auth *Authenticator) *HTTPHandler {
+	return &HTTPHandler{store: store, auth: auth}
+}
+
+// Register adds the user endpoints to the provided Echo router group.
+func (h *HTTPHandler) Register(g *echo.Group) {
+	admin := h.auth.Require(user.RoleAdmin)
+
+	g.GET("/users/me", h.GetCurrentUser, h.auth.Require(user.RoleViewer))
+	g.GET("/users", h.ListUsers, admin)
+	g.POST("/users", h.CreateUser, admin)
+	g.GET("/users/:user_id", h.GetUser, admin)
+	g.PUT("/users/:user_id/role", h.AssignRole, admin)
+	g.POST("/users/:user_id/rotate-token", h.RotateToken, admin)
+	g.DELETE("/users/:user_id", h.DeleteUser, admin)
+}
+
+// GetCurrentUser handles GET /users/me
+func (h *HTTPHandler) GetCurrentUser(c echo.Context) error {
+	return server.SetResponse(c, http.StatusOK, CurrentUser(c))
+}
+
+// ListUsers handles GET /users
+func (h *HTTPHandler) ListUsers(c echo.Context) error {
+	users, err := h.store.ListUsers(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return server.SetResponseList(c, http.StatusOK, users, "")
+}
+
+// CreateUser handles POST /users
+// The response holds the user's API token, which can't be read again.
+func (h *HTTPHandler) CreateUser(c echo.Context) error {
+	req, err := server.BindRequest[CreateUserRequest](c)
+	if err != nil {
+		return err
+	}
+
+	u, token, err := h.store.CreateUser(c.Request().Context(), req.Name, req.Role)
+	if err != nil {
+		return err
+	}
+	c.Set(logkey.UserID, u.ID.String())
+
+	return server.SetResponse(c, http.StatusCreated, UserTokenResponse{User: u, Token: token})
+}
+
+// GetUser handles GET /users/:user_id
+func (h *HTTPHandler) GetUser(c echo.Context) error {
+	req, err := server.BindRequest[UserIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := user.MustParseUserID(req.UserID)
+	c.Set(logkey.UserID, id.String())
+
+	u, err := h.store.ReadUser(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, u)
+}
+
+// AssignRole handles PUT /users/:user_id/role
+func (h *HTTPHandler) AssignRole(c echo.Context) error {
+	req, err := server.BindRequest[AssignRoleRequest](c)
+	if err != nil {
+		return err
+	}
+	id := user.MustParseUserID(req.UserID)
+	c.Set(logkey.UserID, id.String())
+
+	ctx := c.Request().Context()
+
+	if req.Role != user.RoleAdmin {
+		if err := h.checkNotLastAdmin(ctx, id); err != nil {
+			return err
+		}
+	}
+	u, err := h.store.AssignRole(ctx, id, req.Role)
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, u)
+}
+
+// RotateToken handles POST /users/:user_id/rotate-token
+// The user's old token stops working and the new one is returned.
+func (h *HTTPHandler) RotateToken(c echo.Context) error {
+	req, err := server.BindRequest[UserIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := user.MustParseUserID(req.UserID)
+	c.Set(logkey.UserID, id.String())
+
+	ctx := c.Request().Context()
+
+	token, err := h.store.RotateToken(ctx, id)
+	if err != nil {
+		return err
+	}
+	u, err := h.store.ReadUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, UserTokenResponse{User: u, Token: token})
+}
+
+// DeleteUser handles DELETE /users/:user_id
+func (h *HTTPHandler) DeleteUser(c echo.Context) error {
+	req, err := server.BindRequest[UserIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := user.MustParseUserID(req.UserID)
+	c.Set(logkey.UserID, id.String())
+
+	ctx := c.Request().Context()
+
+	if _, err := h.store.ReadUser(ctx, id); err != nil {
+		return err
+	}
+	if err := h.checkNotLastAdmin(ctx, id); err != nil {
+		return err
+	}
+	if err := h.store.DeleteUser(ctx, id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// checkNotLastAdmin refuses to take away the admin role of the only admin
+// user when there is no admin token to fall back on, which would leave
+// nobody able to manage users.
+func (h *HTTPHandler) checkNotLastAdmin(ctx context.Context, id user.UserID) error {
+	if h.auth.adminToken != "" {
+		return nil
+	}
+	users, err := h.store.ListUsers(ctx)
+	if err != nil {
+		return err
+	}
+	admins, target := 0, false
+	for _, u := range users {
+		if u.Role == user.RoleAdmin {
+			admins++
+			target = target || u.ID == id
+		}
+	}
+	if target && admins == 1 {
+		return echo.NewHTTPError(http.StatusConflict, msgcat.Text(msgcat.ErrLastAdmin))
+	}
+	return nil
+}
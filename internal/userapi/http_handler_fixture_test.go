@@ -0,0 +1,122 @@
+package userapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/joshjon/kit/server"
+	"github.com/joshjon/kit/testutil"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/sqlite"
+	"github.com/vervesh/verve/internal/user"
+	"github.com/vervesh/verve/internal/userapi"
+)
+
+const testAdminToken = "test-admin-token"
+
+type fixture struct {
+	Server    *server.Server
+	UserStore *user.Store
+	t         *testing.T
+}
+
+// newFixture serves the user endpoints and a /things resource that viewers
+// can read and operators can write, standing in for the task endpoints.
+// adminToken may be empty.
+func newFixture(t *testing.T, adminToken string) *fixture {
+	t.Helper()
+
+	db := sqlite.NewTestDB(t)
+	userStore := user.NewStore(sqlite.NewUserRepository(db))
+	auth := userapi.NewAuthenticator(userStore, adminToken)
+
+	srv, err := server.NewServer(testutil.GetFreePort(t))
+	require.NoError(t, err)
+	srv.Register("/api/v1", userapi.NewHTTPHandler(userStore, auth))
+	srv.Register("/api/v1", thingsHandler{}, auth.RequireByMethod(user.RoleViewer, user.RoleOperator))
+
+	go srv.Start()
+	err = srv.WaitHealthy(10, 100*time.Millisecond)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { srv.Stop(context.Background()) })
+
+	return &fixture{
+		Server:    srv,
+		UserStore: userStore,
+		t:         t,
+	}
+}
+
+type thingsHandler struct{}
+
+func (thingsHandler) Register(g *echo.Group) {
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusNoContent) }
+	g.GET("/things", ok)
+	g.POST("/things", ok)
+}
+
+// --- URL helpers ---
+
+func (f *fixture) usersURL() string {
+	return fmt.Sprintf("%s/api/v1/users", f.Server.Address())
+}
+
+func (f *fixture) userURL(id string) string {
+	return fmt.Sprintf("%s/api/v1/users/%s", f.Server.Address(), id)
+}
+
+func (f *fixture) thingsURL() string {
+	return fmt.Sprintf("%s/api/v1/things", f.Server.Address())
+}
+
+// --- Seed helpers ---
+
+func (f *fixture) seedUser(name string, role user.Role) (*user.User, string) {
+	f.t.Helper()
+	u, token, err := f.UserStore.CreateUser(context.Background(), name, role)
+	require.NoError(f.t, err)
+	return u, token
+}
+
+// --- HTTP helpers ---
+
+func doJSON(t *testing.T, method, url, token string, body any) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, mustJSONReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	httpRes, err := testutil.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return httpRes
+}
+
+func decode[T any](t *testing.T, httpRes *http.Response) T {
+	t.Helper()
+	defer httpRes.Body.Close()
+	var out T
+	require.NoError(t, json.NewDecoder(httpRes.Body).Decode(&out))
+	return out
+}
+
+func mustJSONReader(v any) io.Reader {
+	if v == nil {
+		return nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return bytes.NewReader(b)
+}
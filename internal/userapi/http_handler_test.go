@@ -0,0 +1,164 @@
+package userapi_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/joshjon/kit/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/user"
+	"github.com/vervesh/verve/internal/userapi"
+)
+
+// --- Create User ---
+
+func TestCreateUser_Success(t *testing.T) {
+	f := newFixture(t, testAdminToken)
+
+	httpRes := doJSON(t, http.MethodPost, f.usersURL(), testAdminToken, userapi.CreateUserRequest{Name: "Ada", Role: user.RoleOperator})
+	require.Equal(t, http.StatusCreated, httpRes.StatusCode)
+	res := decode[server.Response[userapi.UserTokenResponse]](t, httpRes)
+	assert.Equal(t, "Ada", res.Data.User.Name)
+	assert.Equal(t, user.RoleOperator, res.Data.User.Role)
+	require.NotEmpty(t, res.Data.Token)
+
+	httpRes = doJSON(t, http.MethodGet, f.usersURL()+"/me", res.Data.Token, nil)
+	require.Equal(t, http.StatusOK, httpRes.StatusCode)
+	me := decode[server.Response[user.User]](t, httpRes)
+	assert.Equal(t, "Ada", me.Data.Name)
+}
+
+func TestCreateUser_InvalidRole(t *testing.T) {
+	f := newFixture(t, testAdminToken)
+
+	httpRes := doJSON(t, http.MethodPost, f.usersURL(), testAdminToken, userapi.CreateUserRequest{Name: "Ada", Role: "owner"})
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+}
+
+func TestCreateUser_RequiresAdmin(t *testing.T) {
+	f := newFixture(t, testAdminToken)
+	_, token := f.seedUser("Ada", user.RoleOperator)
+
+	httpRes := doJSON(t, http.MethodPost, f.usersURL(), token, userapi.CreateUserRequest{Name: "Grace", Role: user.RoleAdmin})
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusForbidden, httpRes.StatusCode)
+}
+
+// --- Authentication ---
+
+func TestRequireByMethod(t *testing.T) {
+	f := newFixture(t, testAdminToken)
+	_, viewer := f.seedUser("Viewer", user.RoleViewer)
+	_, operator := f.seedUser("Operator", user.RoleOperator)
+
+	tests := []struct {
+		name   string
+		method string
+		token  string
+		want   int
+	}{
+		{"no token", http.MethodGet, "", http.StatusUnauthorized},
+		{"unknown token", http.MethodGet, "vu_nope", http.StatusUnauthorized},
+		{"viewer reads", http.MethodGet, viewer, http.StatusNoContent},
+		{"viewer writes", http.MethodPost, viewer, http.StatusForbidden},
+		{"operator writes", http.MethodPost, operator, http.StatusNoContent},
+		{"admin token writes", http.MethodPost, testAdminToken, http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpRes := doJSON(t, tt.method, f.thingsURL(), tt.token, nil)
+			defer httpRes.Body.Close()
+			assert.Equal(t, tt.want, httpRes.StatusCode)
+		})
+	}
+}
+
+func TestRequireByMethod_TokenQueryParam(t *testing.T) {
+	f := newFixture(t, testAdminToken)
+	_, viewer := f.seedUser("Viewer", user.RoleViewer)
+
+	httpRes := doJSON(t, http.MethodGet, f.thingsURL()+"?token="+viewer, "", nil)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusNoContent, httpRes.StatusCode)
+}
+
+// --- Assign Role ---
+
+func TestAssignRole_Success(t *testing.T) {
+	f := newFixture(t, testAdminToken)
+	u, token := f.seedUser("Ada", user.RoleViewer)
+
+	httpRes := doJSON(t, http.MethodPut, f.userURL(u.ID.String())+"/role", testAdminToken, userapi.AssignRoleRequest{Role: user.RoleOperator})
+	require.Equal(t, http.StatusOK, httpRes.StatusCode)
+	res := decode[server.Response[user.User]](t, httpRes)
+	assert.Equal(t, user.RoleOperator, res.Data.Role)
+
+	httpRes = doJSON(t, http.MethodPost, f.thingsURL(), token, nil)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusNoContent, httpRes.StatusCode, "the new role applies to the next request")
+}
+
+func TestAssignRole_NotFound(t *testing.T) {
+	f := newFixture(t, testAdminToken)
+
+	httpRes := doJSON(t, http.MethodPut, f.userURL(user.NewUserID().String())+"/role", testAdminToken, userapi.AssignRoleRequest{Role: user.RoleAdmin})
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusNotFound, httpRes.StatusCode)
+}
+
+func TestAssignRole_LastAdmin(t *testing.T) {
+	f := newFixture(t, "")
+	u, token := f.seedUser("Ada", user.RoleAdmin)
+
+	httpRes := doJSON(t, http.MethodPut, f.userURL(u.ID.String())+"/role", token, userapi.AssignRoleRequest{Role: user.RoleViewer})
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusConflict, httpRes.StatusCode)
+}
+
+// --- Rotate Token ---
+
+func TestRotateToken_RevokesOldToken(t *testing.T) {
+	f := newFixture(t, testAdminToken)
+	u, oldToken := f.seedUser("Ada", user.RoleViewer)
+
+	httpRes := doJSON(t, http.MethodPost, f.userURL(u.ID.String())+"/rotate-token", testAdminToken, nil)
+	require.Equal(t, http.StatusOK, httpRes.StatusCode)
+	res := decode[server.Response[userapi.UserTokenResponse]](t, httpRes)
+	assert.NotEqual(t, oldToken, res.Data.Token)
+
+	httpRes = doJSON(t, http.MethodGet, f.thingsURL(), oldToken, nil)
+	httpRes.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, httpRes.StatusCode)
+
+	httpRes = doJSON(t, http.MethodGet, f.thingsURL(), res.Data.Token, nil)
+	httpRes.Body.Close()
+	assert.Equal(t, http.StatusNoContent, httpRes.StatusCode)
+}
+
+// --- Delete User ---
+
+func TestDeleteUser_RevokesToken(t *testing.T) {
+	f := newFixture(t, testAdminToken)
+	u, token := f.seedUser("Ada", user.RoleViewer)
+
+	httpRes := doJSON(t, http.MethodDelete, f.userURL(u.ID.String()), testAdminToken, nil)
+	httpRes.Body.Close()
+	require.Equal(t, http.StatusNoContent, httpRes.StatusCode)
+
+	httpRes = doJSON(t, http.MethodGet, f.thingsURL(), token, nil)
+	httpRes.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, httpRes.StatusCode)
+}
+
+func TestDeleteUser_LastAdmin(t *testing.T) {
+	f := newFixture(t, "")
+	u, token := f.seedUser("Ada", user.RoleAdmin)
+	f.seedUser("Grace", user.RoleViewer)
+
+	httpRes := doJSON(t, http.MethodDelete, f.userURL(u.ID.String()), token, nil)
+	defer httpRes.Body.Close()
+	assert.Equal(t, http.StatusConflict, httpRes.StatusCode)
+}
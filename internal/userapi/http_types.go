@@ -0,0 +1,51 @@
+package userapi
+
+import (
+	"github.com/cohesivestack/valgo"
+
+	"github.com/vervesh/verve/internal/user"
+)
+
+// UserIDRequest captures the :user_id path parameter.
+type UserIDRequest struct {
+	UserID string `param:"user_id" json:"-"`
+}
+
+func (r UserIDRequest) Validate() error {
+	return valgo.In("params", valgo.Is(user.UserIDValidator(r.UserID, "user_id"))).ToError()
+}
+
+// CreateUserRequest is the request body for creating a user.
+type CreateUserRequest struct {
+	Name string    `json:"name"`
+	Role user.Role `json:"role"`
+}
+
+func (r CreateUserRequest) Validate() error {
+	v := valgo.Is(valgo.String(r.Name, "name").Not().Blank().MaxLength(100))
+	if !user.ValidRole(string(r.Role)) {
+		v = v.AddErrorMessage("role", "must be one of viewer, operator or admin")
+	}
+	return v.ToError()
+}
+
+// AssignRoleRequest is the request body for changing a user's role.
+type AssignRoleRequest struct {
+	UserID string    `param:"user_id" json:"-"`
+	Role   user.Role `json:"role"`
+}
+
+func (r AssignRoleRequest) Validate() error {
+	v := valgo.In("params", valgo.Is(user.UserIDValidator(r.UserID, "user_id")))
+	if !user.ValidRole(string(r.Role)) {
+		v = v.AddErrorMessage("role", "must be one of viewer, operator or admin")
+	}
+	return v.ToError()
+}
+
+// UserTokenResponse returns a user with their API token. It is only sent
+// when the token is created or rotated; the token can't be read again.
+type UserTokenResponse struct {
+	User  *user.User `json:"user"`
+	Token string     `json:"token"`
+}
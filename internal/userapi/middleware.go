@@ -0,0 +1,93 @@
+package userapi
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/joshjon/kit/errtag"
+	"github.com/labstack/echo/v4"
+
+	"github.com/vervesh/verve/internal/logkey"
+	"github.com/vervesh/verve/internal/msgcat"
+	"github.com/vervesh/verve/internal/user"
+)
+
+// contextKey is the echo context key holding the authenticated user.
+const contextKey = "verve.user"
+
+// tokenQueryParam carries the token of browser clients that can't set
+// headers, such as EventSource and WebSocket connections.
+const tokenQueryParam = "token"
+
+// Authenticator identifies the caller of a request by their bearer token and
+// checks their role. The admin token, when configured, authenticates as an
+// admin that isn't backed by a user, so the first users can be created with
+// it.
+type Authenticator struct {
+	store      *user.Store
+	adminToken string
+}
+
+// NewAuthenticator creates a new Authenticator. adminToken may be empty.
+func NewAuthenticator(store *user.Store, adminToken string) *Authenticator {
+	return &Authenticator{store: store, adminToken: adminToken}
+}
+
+// Require returns middleware that rejects callers without a valid token
+// (401) or whose role doesn't allow role (403).
+func (a *Authenticator) Require(role user.Role) echo.MiddlewareFunc {
+	return a.RequireByMethod(role, role)
+}
+
+// RequireByMethod returns middleware like Require that requires read for
+// GET and HEAD requests and write for every other method.
+func (a *Authenticator) RequireByMethod(read, write user.Role) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			u, err := a.authenticate(c)
+			if err != nil {
+				return err
+			}
+			if u.ID != (user.UserID{}) {
+				c.Set(logkey.UserID, u.ID.String())
+			}
+
+			required := write
+			if m := c.Request().Method; m == http.MethodGet || m == http.MethodHead {
+				required = read
+			}
+			if !u.Role.Allows(required) {
+				return echo.NewHTTPError(http.StatusForbidden, msgcat.Text(msgcat.ErrRoleRequired, required))
+			}
+			c.Set(contextKey, u)
+			return next(c)
+		}
+	}
+}
+
+func (a *Authenticator) authenticate(c echo.Context) (*user.User, error) {
+	token, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+	if !ok {
+		token = c.QueryParam(tokenQueryParam)
+	}
+	if token == "" {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, msgcat.Text(msgcat.ErrInvalidUserToken))
+	}
+	if a.adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(a.adminToken)) == 1 {
+		return &user.User{Name: "admin", Role: user.RoleAdmin}, nil
+	}
+	u, err := a.store.Authenticate(c.Request().Context(), token)
+	if errtag.HasTag[user.ErrTagUserNotFound](err) {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, msgcat.Text(msgcat.ErrInvalidUserToken))
+	}
+	return u, err
+}
+
+// CurrentUser returns the user authenticated by the Authenticator's
+// middleware, or nil when the route isn't behind it. The admin token
+// authenticates as an admin with an empty ID.
+func CurrentUser(c echo.Context) *user.User {
+	u, _ := c.Get(contextKey).(*user.User)
+	return u
+}
@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"github.com/cohesivestack/valgo"
+	"github.com/joshjon/kit/id"
+	"go.jetify.com/typeid"
+
+	"github.com/vervesh/verve/internal/msgcat"
+)
+
+type subscriptionPrefix struct{}
+
+func (subscriptionPrefix) Prefix() string { return "whk" }
+
+// SubscriptionID is the unique identifier for a webhook Subscription.
+type SubscriptionID struct {
+	typeid.TypeID[subscriptionPrefix]
+}
+
+// NewSubscriptionID generates a new unique SubscriptionID.
+func NewSubscriptionID() SubscriptionID {
+	return id.New[SubscriptionID]()
+}
+
+// ParseSubscriptionID parses a string into a SubscriptionID.
+func ParseSubscriptionID(s string) (SubscriptionID, error) {
+	return id.Parse[SubscriptionID](s)
+}
+
+// MustParseSubscriptionID parses a string into a SubscriptionID, panicking on failure.
+func MustParseSubscriptionID(s string) SubscriptionID {
+	return id.MustParse[SubscriptionID](s)
+}
+
+// SubscriptionIDValidator returns a valgo Validator that checks whether the
+// given string is a valid SubscriptionID.
+func SubscriptionIDValidator(identifier string, nameAndTitle ...string) *valgo.ValidatorString[string] {
+	return valgo.String(identifier, nameAndTitle...).
+		Not().Blank().
+		Passing(func(_ string) bool {
+			_, err := ParseSubscriptionID(identifier)
+			return err == nil
+		}, msgcat.Text(msgcat.ErrInvalidID, "webhook"))
+}
+
+type deliveryPrefix struct{}
+
+func (deliveryPrefix) Prefix() string { return "whd" }
+
+// DeliveryID is the unique identifier for a webhook Delivery attempt.
+type DeliveryID struct {
+	typeid.TypeID[deliveryPrefix]
+}
+
+// NewDeliveryID generates a new unique DeliveryID.
+func NewDeliveryID() DeliveryID {
+	return id.New[DeliveryID]()
+}
+
+// MustParseDeliveryID parses a string into a DeliveryID, panicking on failure.
+func MustParseDeliveryID(s string) DeliveryID {
+	return id.MustParse[DeliveryID](s)
+}
+
+type eventPrefix struct{}
+
+func (eventPrefix) Prefix() string { return "evt" }
+
+// EventID is the unique identifier for a webhook Event. It is shared by every
+// delivery attempt of the event so receivers can deduplicate retries.
+type EventID struct {
+	typeid.TypeID[eventPrefix]
+}
+
+// NewEventID generates a new unique EventID.
+func NewEventID() EventID {
+	return id.New[EventID]()
+}
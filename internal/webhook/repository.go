@@ -0,0 +1,16 @@
+package webhook
+
+import "context"
+
+// Repository is the interface for persisting webhook Subscriptions and their
+// Delivery log.
+type Repository interface {
+	CreateSubscription(ctx context.Context, sub *Subscription) error
+	ReadSubscription(ctx context.Context, id SubscriptionID) (*Subscription, error)
+	ListSubscriptions(ctx context.Context) ([]*Subscription, error)
+	DeleteSubscription(ctx context.Context, id SubscriptionID) error
+	CreateDelivery(ctx context.Context, d *Delivery) error
+	ListDeliveries(ctx context.Context, subID SubscriptionID, limit int) ([]*Delivery, error)
+	// PruneDeliveries deletes all but the newest keep deliveries of a subscription.
+	PruneDeliveries(ctx context.Context, subID SubscriptionID, keep int) error
+}
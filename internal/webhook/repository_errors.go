@@ -0,0 +1,25 @@
+package webhook
+
+import (
+	"github.com/joshjon/kit/errtag"
+
+	"github.com/vervesh/verve/internal/msgcat"
+)
+
+// ErrTagSubscriptionNotFound indicates a webhook subscription was not found.
+type ErrTagSubscriptionNotFound struct{ errtag.NotFound }
+
+func (ErrTagSubscriptionNotFound) Msg() string { return msgcat.Text(msgcat.ErrWebhookNotFound) }
+
+func (e ErrTagSubscriptionNotFound) Unwrap() error {
+	return errtag.Tag[errtag.NotFound](e.Cause())
+}
+
+// ErrTagSubscriptionConflict indicates a webhook subscription conflict (e.g. duplicate ID).
+type ErrTagSubscriptionConflict struct{ errtag.Conflict }
+
+func (ErrTagSubscriptionConflict) Msg() string { return msgcat.Text(msgcat.ErrWebhookConflict) }
+
+func (e ErrTagSubscriptionConflict) Unwrap() error {
+	return errtag.Tag[errtag.Conflict](e.Cause())
+}
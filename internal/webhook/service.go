@@ -0,0 +1,271 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/joshjon/kit/log"
+
+	"github.com/vervesh/verve/internal/epic"
+	"github.com/vervesh/verve/internal/logkey"
+	"github.com/vervesh/verve/internal/task"
+)
+
+const (
+	// maxDeliveriesPerSubscription caps the delivery log kept per subscription.
+	maxDeliveriesPerSubscription = 100
+	// maxResponseBodyLen caps the receiver response body stored for debugging.
+	maxResponseBodyLen = 1024
+)
+
+// defaultRetryDelays are the waits before each retry of a failed delivery.
+var defaultRetryDelays = []time.Duration{5 * time.Second, 30 * time.Second, 2 * time.Minute}
+
+// Option configures a Service.
+type Option func(*Service)
+
+// WithHTTPClient sets the HTTP client used to deliver webhooks.
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Service) {
+		s.client = client
+	}
+}
+
+// WithRetryDelays sets the waits before each retry of a failed delivery. The
+// number of delays is the maximum number of retries.
+func WithRetryDelays(delays ...time.Duration) Option {
+	return func(s *Service) {
+		s.retryDelays = delays
+	}
+}
+
+// Service manages webhook subscriptions and delivers task and epic lifecycle
+// events to them as signed JSON POST requests. Task events are consumed from
+// the task Broker; epic events arrive through epic.EventListener.
+type Service struct {
+	repo        Repository
+	client      *http.Client
+	retryDelays []time.Duration
+	logger      log.Logger
+
+	// Tracks in-flight deliveries so callers can wait for them to finish.
+	wg sync.WaitGroup
+}
+
+var _ epic.EventListener = (*Service)(nil)
+
+// NewService creates a new webhook Service.
+func NewService(repo Repository, logger log.Logger, opts ...Option) *Service {
+	s := &Service{
+		repo:        repo,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		retryDelays: defaultRetryDelays,
+		logger:      logger.With("component", "webhook_service"),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// CreateSubscription creates a new webhook subscription.
+func (s *Service) CreateSubscription(ctx context.Context, sub *Subscription) error {
+	return s.repo.CreateSubscription(ctx, sub)
+}
+
+// ReadSubscription reads a webhook subscription by ID.
+func (s *Service) ReadSubscription(ctx context.Context, id SubscriptionID) (*Subscription, error) {
+	return s.repo.ReadSubscription(ctx, id)
+}
+
+// ListSubscriptions returns all webhook subscriptions.
+func (s *Service) ListSubscriptions(ctx context.Context) ([]*Subscription, error) {
+	return s.repo.ListSubscriptions(ctx)
+}
+
+// DeleteSubscription deletes a webhook subscription and its delivery log.
+func (s *Service) DeleteSubscription(ctx context.Context, id SubscriptionID) error {
+	return s.repo.DeleteSubscription(ctx, id)
+}
+
+// ListDeliveries returns the most recent delivery attempts for a
+// subscription, newest first.
+func (s *Service) ListDeliveries(ctx context.Context, id SubscriptionID, limit int) ([]*Delivery, error) {
+	return s.repo.ListDeliveries(ctx, id, limit)
+}
+
+// Run consumes task events from the broker and delivers them to matching
+// subscriptions until ctx is cancelled. Events received from other replicas
+// through a shared event bus are left to the replica that published them.
+func (s *Service) Run(ctx context.Context, broker *task.Broker) {
+	ch := broker.Subscribe()
+	defer broker.Unsubscribe(ch)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-ch:
+			s.handleTaskEvent(ctx, ev)
+		}
+	}
+}
+
+func (s *Service) handleTaskEvent(ctx context.Context, ev task.Event) {
+	if ev.Remote {
+		// The replica that published the event delivers it.
+		return
+	}
+	out := Event{Type: ev.Type, RepoID: ev.RepoID}
+	switch ev.Type {
+	case task.EventTaskCreated, task.EventTaskUpdated, task.EventReviewOverdue:
+		if ev.Task == nil {
+			return
+		}
+		out.Task = ev.Task
+		out.TaskID = ev.Task.ID.String()
+	case task.EventTaskDeleted:
+		out.TaskID = ev.TaskID.String()
+	default:
+		return
+	}
+	s.Publish(ctx, out)
+}
+
+// EpicCompleted delivers an epic_completed event.
+func (s *Service) EpicCompleted(ctx context.Context, e *epic.Epic) {
+	s.Publish(ctx, Event{Type: EventEpicCompleted, RepoID: e.RepoID, Epic: e})
+}
+
+// EpicBudgetExceeded delivers an epic_budget_exceeded event.
+func (s *Service) EpicBudgetExceeded(ctx context.Context, e *epic.Epic) {
+	s.Publish(ctx, Event{Type: EventEpicBudgetExceeded, RepoID: e.RepoID, Epic: e})
+}
+
+// Publish delivers the event to every matching subscription. Delivery is
+// asynchronous and failed attempts are retried; every attempt is recorded in
+// the subscription's delivery log.
+func (s *Service) Publish(ctx context.Context, ev Event) {
+	subs, err := s.repo.ListSubscriptions(ctx)
+	if err != nil {
+		s.logger.Error("failed to list webhook subscriptions", "error", err)
+		return
+	}
+
+	var targets []*Subscription
+	for _, sub := range subs {
+		if sub.Matches(ev.Type, ev.RepoID) {
+			targets = append(targets, sub)
+		}
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	ev.ID = NewEventID()
+	if ev.Timestamp.IsZero() {
+		ev.Timestamp = time.Now()
+	}
+	body, err := json.Marshal(ev)
+	if err != nil {
+		s.logger.Error("failed to marshal webhook event", "webhook.event", ev.Type, "error", err)
+		return
+	}
+
+	// Deliveries and their retries outlive the triggering request.
+	ctx = context.WithoutCancel(ctx)
+	for _, sub := range targets {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.deliver(ctx, sub, ev, body)
+		}()
+	}
+}
+
+// Wait blocks until all in-flight deliveries, including retries, have finished.
+func (s *Service) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Service) deliver(ctx context.Context, sub *Subscription, ev Event, body []byte) {
+	for attempt := 1; ; attempt++ {
+		d, retryable := s.send(ctx, sub, ev, body, attempt)
+		if err := s.repo.CreateDelivery(ctx, d); err != nil {
+			s.logger.Error("failed to record webhook delivery", logkey.WebhookID, sub.ID.String(), "error", err)
+		} else if err := s.repo.PruneDeliveries(ctx, sub.ID, maxDeliveriesPerSubscription); err != nil {
+			s.logger.Warn("failed to prune webhook deliveries", logkey.WebhookID, sub.ID.String(), "error", err)
+		}
+
+		if d.Success {
+			return
+		}
+		if !retryable || attempt > len(s.retryDelays) {
+			s.logger.Warn("webhook delivery failed",
+				logkey.WebhookID, sub.ID.String(),
+				"webhook.event", ev.Type,
+				"webhook.attempt", attempt,
+				"webhook.status_code", d.StatusCode,
+				"error", d.Error,
+			)
+			return
+		}
+		time.Sleep(s.retryDelays[attempt-1])
+	}
+}
+
+// send makes a single delivery attempt and reports whether a failure is worth
+// retrying.
+func (s *Service) send(ctx context.Context, sub *Subscription, ev Event, body []byte, attempt int) (*Delivery, bool) {
+	d := &Delivery{
+		ID:             NewDeliveryID(),
+		SubscriptionID: sub.ID,
+		EventID:        ev.ID.String(),
+		EventType:      ev.Type,
+		Attempt:        attempt,
+		CreatedAt:      time.Now(),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		d.Error = fmt.Sprintf("create request: %v", err)
+		return d, false
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Verve-Webhook")
+	req.Header.Set(HeaderEvent, ev.Type)
+	req.Header.Set(HeaderEventID, ev.ID.String())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, timestamp, body))
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	d.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		d.Error = err.Error()
+		return d, true
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodyLen))
+	d.StatusCode = resp.StatusCode
+	d.ResponseBody = string(respBody)
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		d.Success = true
+		return d, false
+	}
+	d.Error = fmt.Sprintf("unexpected status %d", resp.StatusCode)
+	return d, isRetryableStatus(resp.StatusCode)
+}
+
+func isRetryableStatus(code int) bool {
+	return code >= 500 || code == http.StatusRequestTimeout || code == http.StatusTooManyRequests
+}
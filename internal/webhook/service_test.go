@@ -0,0 +1,280 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joshjon/kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/epic"
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/sqlite"
+	"github.com/vervesh/verve/internal/task"
+	"github.com/vervesh/verve/internal/webhook"
+)
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// receiver is a test HTTP server that records webhook requests and responds
+// with a queue of status codes, repeating the last one.
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []receivedRequest
+	statuses []int
+}
+
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	t.Helper()
+	if len(statuses) == 0 {
+		statuses = []int{http.StatusOK}
+	}
+	rc := &receiver{statuses: statuses}
+	rc.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		rc.mu.Lock()
+		rc.requests = append(rc.requests, receivedRequest{header: r.Header.Clone(), body: body})
+		status := rc.statuses[0]
+		if len(rc.statuses) > 1 {
+			rc.statuses = rc.statuses[1:]
+		}
+		rc.mu.Unlock()
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("ack"))
+	}))
+	t.Cleanup(rc.Close)
+	return rc
+}
+
+func (rc *receiver) received() []receivedRequest {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return append([]receivedRequest(nil), rc.requests...)
+}
+
+type serviceFixture struct {
+	service   *webhook.Service
+	repoStore *repo.Store
+	repoID    string
+}
+
+func newServiceFixture(t *testing.T) *serviceFixture {
+	t.Helper()
+	db := sqlite.NewTestDB(t)
+	repoStore := repo.NewStore(sqlite.NewRepoRepository(db))
+	r, err := repo.NewRepo("owner/test-repo")
+	require.NoError(t, err)
+	require.NoError(t, repoStore.CreateRepo(context.Background(), r))
+
+	service := webhook.NewService(
+		sqlite.NewWebhookRepository(db),
+		log.NewLogger(log.WithNop()),
+		webhook.WithRetryDelays(time.Millisecond, time.Millisecond),
+	)
+	return &serviceFixture{service: service, repoStore: repoStore, repoID: r.ID.String()}
+}
+
+func (f *serviceFixture) subscribe(t *testing.T, url string, repoID string, eventTypes ...string) *webhook.Subscription {
+	t.Helper()
+	sub := webhook.NewSubscription(url, "", eventTypes, repoID)
+	require.NoError(t, f.service.CreateSubscription(context.Background(), sub))
+	return sub
+}
+
+func TestService_SubscriptionCRUD(t *testing.T) {
+	f := newServiceFixture(t)
+	ctx := context.Background()
+
+	sub := f.subscribe(t, "https://example.com/hook", f.repoID, webhook.EventTaskCreated)
+
+	got, err := f.service.ReadSubscription(ctx, sub.ID)
+	require.NoError(t, err)
+	assert.Equal(t, sub.URL, got.URL)
+	assert.Equal(t, sub.Secret, got.Secret)
+	assert.Equal(t, f.repoID, got.RepoID)
+	assert.Equal(t, []string{webhook.EventTaskCreated}, got.EventTypes)
+
+	subs, err := f.service.ListSubscriptions(ctx)
+	require.NoError(t, err)
+	require.Len(t, subs, 1)
+
+	require.NoError(t, f.service.DeleteSubscription(ctx, sub.ID))
+	_, err = f.service.ReadSubscription(ctx, sub.ID)
+	require.Error(t, err)
+}
+
+func TestService_Publish_SignedDelivery(t *testing.T) {
+	f := newServiceFixture(t)
+	rc := newReceiver(t)
+	sub := f.subscribe(t, rc.URL, "")
+
+	tsk := task.NewTask(f.repoID, "Add login", "desc", nil, nil, 0, false, false, "", true)
+	f.service.Publish(context.Background(), webhook.Event{Type: webhook.EventTaskCreated, RepoID: f.repoID, Task: tsk, TaskID: tsk.ID.String()})
+	f.service.Wait()
+
+	reqs := rc.received()
+	require.Len(t, reqs, 1)
+	req := reqs[0]
+
+	assert.Equal(t, webhook.EventTaskCreated, req.header.Get(webhook.HeaderEvent))
+	ts, err := strconv.ParseInt(req.header.Get(webhook.HeaderTimestamp), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, webhook.Sign(sub.Secret, ts, req.body), req.header.Get(webhook.HeaderSignature))
+
+	var ev webhook.Event
+	require.NoError(t, json.Unmarshal(req.body, &ev))
+	assert.Equal(t, req.header.Get(webhook.HeaderEventID), ev.ID.String())
+	assert.Equal(t, webhook.EventTaskCreated, ev.Type)
+	assert.Equal(t, f.repoID, ev.RepoID)
+	require.NotNil(t, ev.Task)
+	assert.Equal(t, "Add login", ev.Task.Title)
+
+	deliveries, err := f.service.ListDeliveries(context.Background(), sub.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.True(t, deliveries[0].Success)
+	assert.Equal(t, http.StatusOK, deliveries[0].StatusCode)
+	assert.Equal(t, 1, deliveries[0].Attempt)
+	assert.Equal(t, ev.ID.String(), deliveries[0].EventID)
+	assert.Equal(t, "ack", deliveries[0].ResponseBody)
+}
+
+func TestService_Publish_Filters(t *testing.T) {
+	f := newServiceFixture(t)
+	all := newReceiver(t)
+	createdOnly := newReceiver(t)
+	otherRepo := newReceiver(t)
+	f.subscribe(t, all.URL, "")
+	f.subscribe(t, createdOnly.URL, "", webhook.EventTaskCreated)
+	other, err := repo.NewRepo("owner/other-repo")
+	require.NoError(t, err)
+	require.NoError(t, f.repoStore.CreateRepo(context.Background(), other))
+	f.subscribe(t, otherRepo.URL, other.ID.String())
+
+	f.service.Publish(context.Background(), webhook.Event{Type: webhook.EventTaskDeleted, RepoID: f.repoID, TaskID: task.NewTaskID().String()})
+	f.service.Wait()
+
+	assert.Len(t, all.received(), 1)
+	assert.Empty(t, createdOnly.received())
+	assert.Empty(t, otherRepo.received())
+}
+
+func TestService_Publish_RetriesFailures(t *testing.T) {
+	f := newServiceFixture(t)
+	rc := newReceiver(t, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK)
+	sub := f.subscribe(t, rc.URL, "")
+
+	f.service.Publish(context.Background(), webhook.Event{Type: webhook.EventTaskDeleted, RepoID: f.repoID})
+	f.service.Wait()
+
+	reqs := rc.received()
+	require.Len(t, reqs, 3)
+	eventID := reqs[0].header.Get(webhook.HeaderEventID)
+	for _, r := range reqs {
+		assert.Equal(t, eventID, r.header.Get(webhook.HeaderEventID), "retries must reuse the event ID")
+	}
+
+	deliveries, err := f.service.ListDeliveries(context.Background(), sub.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 3)
+	// Newest first.
+	assert.Equal(t, 3, deliveries[0].Attempt)
+	assert.True(t, deliveries[0].Success)
+	assert.Equal(t, 1, deliveries[2].Attempt)
+	assert.False(t, deliveries[2].Success)
+	assert.Equal(t, http.StatusServiceUnavailable, deliveries[2].StatusCode)
+	assert.Contains(t, deliveries[2].Error, "unexpected status 503")
+}
+
+func TestService_Publish_GivesUp(t *testing.T) {
+	f := newServiceFixture(t)
+
+	t.Run("after max retries", func(t *testing.T) {
+		rc := newReceiver(t, http.StatusInternalServerError)
+		sub := f.subscribe(t, rc.URL, "")
+		f.service.Publish(context.Background(), webhook.Event{Type: webhook.EventTaskDeleted, RepoID: f.repoID})
+		f.service.Wait()
+
+		// One attempt plus two retries.
+		assert.Len(t, rc.received(), 3)
+		deliveries, err := f.service.ListDeliveries(context.Background(), sub.ID, 10)
+		require.NoError(t, err)
+		assert.Len(t, deliveries, 3)
+		require.NoError(t, f.service.DeleteSubscription(context.Background(), sub.ID))
+	})
+
+	t.Run("client errors are not retried", func(t *testing.T) {
+		rc := newReceiver(t, http.StatusBadRequest)
+		f.subscribe(t, rc.URL, "")
+		f.service.Publish(context.Background(), webhook.Event{Type: webhook.EventTaskDeleted, RepoID: f.repoID})
+		f.service.Wait()
+
+		assert.Len(t, rc.received(), 1)
+	})
+}
+
+func TestService_Run_DeliversBrokerEvents(t *testing.T) {
+	f := newServiceFixture(t)
+	rc := newReceiver(t)
+	f.subscribe(t, rc.URL, "")
+
+	broker := task.NewBroker(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f.service.Run(ctx, broker)
+	}()
+
+	// Wait for the service to subscribe before publishing.
+	tsk := task.NewTask(f.repoID, "Add login", "desc", nil, nil, 0, false, false, "", true)
+	require.Eventually(t, func() bool {
+		broker.Publish(ctx, task.Event{Type: task.EventLogsAppended, TaskID: tsk.ID, Logs: []string{"ignored"}})
+		broker.Publish(ctx, task.Event{Type: task.EventTaskUpdated, RepoID: f.repoID, Task: tsk})
+		return len(rc.received()) > 0
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-done
+	f.service.Wait()
+
+	for _, r := range rc.received() {
+		assert.Equal(t, webhook.EventTaskUpdated, r.header.Get(webhook.HeaderEvent), "log events must not be delivered")
+	}
+}
+
+func TestService_EpicEvents(t *testing.T) {
+	f := newServiceFixture(t)
+	rc := newReceiver(t)
+	f.subscribe(t, rc.URL, "", webhook.EventEpicCompleted, webhook.EventEpicBudgetExceeded)
+
+	e := epic.NewEpic(f.repoID, "Auth", "desc")
+	f.service.EpicCompleted(context.Background(), e)
+	f.service.EpicBudgetExceeded(context.Background(), e)
+	f.service.Wait()
+
+	reqs := rc.received()
+	require.Len(t, reqs, 2)
+	var types []string
+	for _, r := range reqs {
+		var ev webhook.Event
+		require.NoError(t, json.Unmarshal(r.body, &ev))
+		require.NotNil(t, ev.Epic)
+		assert.Equal(t, e.ID, ev.Epic.ID)
+		types = append(types, ev.Type)
+	}
+	assert.ElementsMatch(t, []string{webhook.EventEpicCompleted, webhook.EventEpicBudgetExceeded}, types)
+}
@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/vervesh/verve/internal/epic"
+	"github.com/vervesh/verve/internal/task"
+)
+
+// Event types delivered to webhook subscriptions.
+const (
+	EventTaskCreated        = task.EventTaskCreated
+	EventTaskUpdated        = task.EventTaskUpdated
+	EventTaskDeleted        = task.EventTaskDeleted
+	EventEpicCompleted      = "epic_completed"
+	EventEpicBudgetExceeded = "epic_budget_exceeded"
+	EventReviewOverdue      = task.EventReviewOverdue
+)
+
+// AllEventTypes lists every event type a subscription can filter on.
+var AllEventTypes = []string{
+	EventTaskCreated,
+	EventTaskUpdated,
+	EventTaskDeleted,
+	EventEpicCompleted,
+	EventEpicBudgetExceeded,
+	EventReviewOverdue,
+}
+
+// ValidEventType reports whether t is a known event type.
+func ValidEventType(t string) bool {
+	return slices.Contains(AllEventTypes, t)
+}
+
+// Headers set on every webhook delivery.
+const (
+	HeaderEvent     = "X-Verve-Event"
+	HeaderEventID   = "X-Verve-Event-Id"
+	HeaderTimestamp = "X-Verve-Timestamp"
+	HeaderSignature = "X-Verve-Signature"
+)
+
+// Subscription is an externally registered webhook that receives task and
+// epic lifecycle events.
+type Subscription struct {
+	ID         SubscriptionID `json:"id"`
+	URL        string         `json:"url"`
+	Secret     string         `json:"-"` // Only returned once, when the subscription is created
+	EventTypes []string       `json:"event_types"`
+	RepoID     string         `json:"repo_id,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+// NewSubscription creates a new Subscription. An empty eventTypes list
+// subscribes to all events and an empty repoID to events from all repos. If
+// secret is empty a random one is generated.
+func NewSubscription(url, secret string, eventTypes []string, repoID string) *Subscription {
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	if secret == "" {
+		secret = GenerateSecret()
+	}
+	return &Subscription{
+		ID:         NewSubscriptionID(),
+		URL:        url,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		RepoID:     repoID,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// Matches reports whether the subscription should receive an event of the
+// given type from the given repo.
+func (s *Subscription) Matches(eventType, repoID string) bool {
+	if s.RepoID != "" && s.RepoID != repoID {
+		return false
+	}
+	return len(s.EventTypes) == 0 || slices.Contains(s.EventTypes, eventType)
+}
+
+// Event is the JSON body delivered to webhook subscriptions.
+type Event struct {
+	ID        EventID    `json:"id"`
+	Type      string     `json:"type"`
+	RepoID    string     `json:"repo_id,omitempty"`
+	Timestamp time.Time  `json:"timestamp"`
+	Task      *task.Task `json:"task,omitempty"`
+	TaskID    string     `json:"task_id,omitempty"`
+	Epic      *epic.Epic `json:"epic,omitempty"`
+}
+
+// Delivery records a single attempt to deliver an event to a subscription.
+type Delivery struct {
+	ID             DeliveryID     `json:"id"`
+	SubscriptionID SubscriptionID `json:"subscription_id"`
+	EventID        string         `json:"event_id"`
+	EventType      string         `json:"event_type"`
+	Attempt        int            `json:"attempt"`
+	Success        bool           `json:"success"`
+	StatusCode     int            `json:"status_code,omitempty"`
+	Error          string         `json:"error,omitempty"`
+	ResponseBody   string         `json:"response_body,omitempty"`
+	DurationMs     int64          `json:"duration_ms"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+// GenerateSecret returns a random hex-encoded signing secret.
+func GenerateSecret() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Sign returns the signature sent in the X-Verve-Signature header:
+// "sha256=" followed by the hex-encoded HMAC-SHA256 of "<timestamp>.<body>"
+// keyed with the subscription secret. Receivers should recompute it and
+// reject stale timestamps to guard against replays.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscription_Matches(t *testing.T) {
+	tests := []struct {
+		name       string
+		eventTypes []string
+		repoID     string
+		event      string
+		eventRepo  string
+		want       bool
+	}{
+		{"all events all repos", nil, "", EventTaskUpdated, "repo_a", true},
+		{"filtered event match", []string{EventTaskCreated}, "", EventTaskCreated, "repo_a", true},
+		{"filtered event mismatch", []string{EventTaskCreated}, "", EventTaskUpdated, "repo_a", false},
+		{"repo match", nil, "repo_a", EventTaskDeleted, "repo_a", true},
+		{"repo mismatch", nil, "repo_a", EventTaskDeleted, "repo_b", false},
+		{"event and repo match", []string{EventEpicCompleted}, "repo_a", EventEpicCompleted, "repo_a", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := NewSubscription("https://example.com/hook", "", tt.eventTypes, tt.repoID)
+			assert.Equal(t, tt.want, sub.Matches(tt.event, tt.eventRepo))
+		})
+	}
+}
+
+func TestNewSubscription_GeneratesSecret(t *testing.T) {
+	a := NewSubscription("https://example.com/hook", "", nil, "")
+	b := NewSubscription("https://example.com/hook", "", nil, "")
+	assert.Len(t, a.Secret, 64)
+	assert.NotEqual(t, a.Secret, b.Secret)
+	assert.Equal(t, []string{}, a.EventTypes)
+
+	c := NewSubscription("https://example.com/hook", "my-secret-value!", nil, "")
+	assert.Equal(t, "my-secret-value!", c.Secret)
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"type":"task_created"}`)
+	sig := Sign("secret", 1700000000, body)
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, sig)
+	assert.Equal(t, sig, Sign("secret", 1700000000, body), "signature must be deterministic")
+	assert.NotEqual(t, sig, Sign("other", 1700000000, body), "signature must depend on the secret")
+	assert.NotEqual(t, sig, Sign("secret", 1700000001, body), "signature must depend on the timestamp")
+	assert.NotEqual(t, sig, Sign("secret", 1700000000, []byte(`{}`)), "signature must depend on the body")
+}
+
+func TestIsRetryableStatus(t *testing.T) {
+	assert.True(t, isRetryableStatus(500))
+	assert.True(t, isRetryableStatus(503))
+	assert.True(t, isRetryableStatus(408))
+	assert.True(t, isRetryableStatus(429))
+	assert.False(t, isRetryableStatus(400))
+	assert.False(t, isRetryableStatus(404))
+}
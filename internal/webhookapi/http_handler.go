@@ -0,0 +1,136 @@
+package webhookapi
+
+import (
+	"net/http"
+
+	"github.com/joshjon/kit/server"
+	"github.com/labstack/echo/v4"
+
+	"github.com/vervesh/verve/internal/adminapi"
+	"github.com/vervesh/verve/internal/logkey"
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/webhook"
+)
+
+// HTTPHandler handles webhook subscription HTTP requests. All endpoints
+// require the admin token as a bearer token.
+type HTTPHandler struct {
+	webhookService *webhook.Service
+	repoStore      *repo.Store
+	adminToken     string
+}
+
+// NewHTTPHandler creates a new HTTPHandler.
+func NewHTTPHandler(webhookService *webhook.Service, repoStore *repo.Store, adminToken string) *HTTPHandler {
+	return &HTTPHandler{
+		webhookService: webhookService,
+		repoStore:      repoStore,
+		adminToken:     adminToken,
+	}
+}
+
+// Register adds the webhook endpoints to the provided Echo router group.
+func (h *HTTPHandler) Register(g *echo.Group) {
+	webhooks := g.Group("/webhooks", adminapi.RequireToken(h.adminToken))
+	webhooks.GET("", h.ListWebhooks)
+	webhooks.POST("", h.CreateWebhook)
+	webhooks.GET("/:id", h.GetWebhook)
+	webhooks.DELETE("/:id", h.DeleteWebhook)
+	webhooks.GET("/:id/deliveries", h.ListDeliveries)
+}
+
+// ListWebhooks handles GET /webhooks
+func (h *HTTPHandler) ListWebhooks(c echo.Context) error {
+	subs, err := h.webhookService.ListSubscriptions(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return server.SetResponseList(c, http.StatusOK, subs, "")
+}
+
+// CreateWebhook handles POST /webhooks
+func (h *HTTPHandler) CreateWebhook(c echo.Context) error {
+	req, err := server.BindRequest[CreateWebhookRequest](c)
+	if err != nil {
+		return err
+	}
+	ctx := c.Request().Context()
+
+	if req.RepoID != "" {
+		c.Set(logkey.RepoID, req.RepoID)
+		if _, err := h.repoStore.ReadRepo(ctx, repo.MustParseRepoID(req.RepoID)); err != nil {
+			return err
+		}
+	}
+
+	sub := webhook.NewSubscription(req.URL, req.Secret, req.EventTypes, req.RepoID)
+	if err := h.webhookService.CreateSubscription(ctx, sub); err != nil {
+		return err
+	}
+	c.Set(logkey.WebhookID, sub.ID.String())
+
+	return server.SetResponse(c, http.StatusCreated, CreateWebhookResponse{Subscription: sub, Secret: sub.Secret})
+}
+
+// GetWebhook handles GET /webhooks/:id
+func (h *HTTPHandler) GetWebhook(c echo.Context) error {
+	req, err := server.BindRequest[WebhookIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := webhook.MustParseSubscriptionID(req.ID)
+	c.Set(logkey.WebhookID, id.String())
+
+	sub, err := h.webhookService.ReadSubscription(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return server.SetResponse(c, http.StatusOK, sub)
+}
+
+// DeleteWebhook handles DELETE /webhooks/:id
+func (h *HTTPHandler) DeleteWebhook(c echo.Context) error {
+	req, err := server.BindRequest[WebhookIDRequest](c)
+	if err != nil {
+		return err
+	}
+	id := webhook.MustParseSubscriptionID(req.ID)
+	c.Set(logkey.WebhookID, id.String())
+
+	ctx := c.Request().Context()
+
+	if _, err := h.webhookService.ReadSubscription(ctx, id); err != nil {
+		return err
+	}
+	if err := h.webhookService.DeleteSubscription(ctx, id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListDeliveries handles GET /webhooks/:id/deliveries
+// It returns the most recent delivery attempts, newest first.
+func (h *HTTPHandler) ListDeliveries(c echo.Context) error {
+	req, err := server.BindRequest[ListDeliveriesRequest](c)
+	if err != nil {
+		return err
+	}
+	id := webhook.MustParseSubscriptionID(req.ID)
+	c.Set(logkey.WebhookID, id.String())
+
+	ctx := c.Request().Context()
+
+	if _, err := h.webhookService.ReadSubscription(ctx, id); err != nil {
+		return err
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultDeliveryLimit
+	}
+	deliveries, err := h.webhookService.ListDeliveries(ctx, id, limit)
+	if err != nil {
+		return err
+	}
+	return server.SetResponseList(c, http.StatusOK, deliveries, "")
+}
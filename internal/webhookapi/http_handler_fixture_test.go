@@ -0,0 +1,114 @@
+package webhookapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/joshjon/kit/log"
+	"github.com/joshjon/kit/server"
+	"github.com/joshjon/kit/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/sqlite"
+	"github.com/vervesh/verve/internal/webhook"
+	"github.com/vervesh/verve/internal/webhookapi"
+)
+
+const adminToken = "test-admin-token"
+
+type fixture struct {
+	Server         *server.Server
+	WebhookService *webhook.Service
+	Repo           *repo.Repo
+	t              *testing.T
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	db := sqlite.NewTestDB(t)
+	logger := log.NewLogger(log.WithNop())
+
+	repoRepo := sqlite.NewRepoRepository(db)
+	repoStore := repo.NewStore(repoRepo)
+
+	webhookService := webhook.NewService(sqlite.NewWebhookRepository(db), logger, webhook.WithRetryDelays())
+
+	handler := webhookapi.NewHTTPHandler(webhookService, repoStore, adminToken)
+
+	srv, err := server.NewServer(testutil.GetFreePort(t))
+	require.NoError(t, err)
+	srv.Register("/api/v1", handler)
+
+	go srv.Start()
+	err = srv.WaitHealthy(10, 100*time.Millisecond)
+	require.NoError(t, err)
+
+	// Pre-create a repo for use in tests.
+	r, _ := repo.NewRepo("owner/test-repo")
+	require.NoError(t, repoStore.CreateRepo(context.Background(), r))
+
+	t.Cleanup(func() { srv.Stop(context.Background()) })
+
+	return &fixture{
+		Server:         srv,
+		WebhookService: webhookService,
+		Repo:           r,
+		t:              t,
+	}
+}
+
+// --- URL helpers ---
+
+func (f *fixture) webhooksURL() string {
+	return fmt.Sprintf("%s/api/v1/webhooks", f.Server.Address())
+}
+
+func (f *fixture) webhookURL(id string) string {
+	return fmt.Sprintf("%s/api/v1/webhooks/%s", f.Server.Address(), id)
+}
+
+// --- HTTP helpers ---
+
+// do sends an authenticated JSON request and decodes the response into out
+// when it is non-nil.
+func (f *fixture) do(method, url string, body any, out any) *http.Response {
+	f.t.Helper()
+	return doWithToken(f.t, method, url, adminToken, body, out)
+}
+
+func doWithToken(t *testing.T, method, url, token string, body any, out any) *http.Response {
+	t.Helper()
+	var r io.Reader
+	if body != nil {
+		r = mustJSONReader(body)
+	}
+	req, err := http.NewRequest(method, url, r)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	httpRes, err := testutil.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer httpRes.Body.Close()
+	if out != nil {
+		require.NoError(t, json.NewDecoder(httpRes.Body).Decode(out))
+	}
+	return httpRes
+}
+
+func mustJSONReader(v any) io.Reader {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return bytes.NewReader(b)
+}
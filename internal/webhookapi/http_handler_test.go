@@ -0,0 +1,167 @@
+package webhookapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/joshjon/kit/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/webhook"
+	"github.com/vervesh/verve/internal/webhookapi"
+)
+
+// --- Auth ---
+
+func TestWebhooks_RequireAdminToken(t *testing.T) {
+	f := newFixture(t)
+
+	httpRes := doWithToken(t, http.MethodGet, f.webhooksURL(), "", nil, nil)
+	assert.Equal(t, http.StatusUnauthorized, httpRes.StatusCode)
+
+	httpRes = doWithToken(t, http.MethodPost, f.webhooksURL(), "wrong", webhookapi.CreateWebhookRequest{URL: "https://example.com/hook"}, nil)
+	assert.Equal(t, http.StatusUnauthorized, httpRes.StatusCode)
+}
+
+// --- Create ---
+
+func TestCreateWebhook_Success(t *testing.T) {
+	f := newFixture(t)
+
+	req := webhookapi.CreateWebhookRequest{
+		URL:        "https://example.com/hook",
+		EventTypes: []string{webhook.EventTaskCreated, webhook.EventEpicCompleted},
+		RepoID:     f.Repo.ID.String(),
+	}
+	var res server.Response[webhookapi.CreateWebhookResponse]
+	httpRes := f.do(http.MethodPost, f.webhooksURL(), req, &res)
+	require.Equal(t, http.StatusCreated, httpRes.StatusCode)
+
+	assert.NotEmpty(t, res.Data.ID.String())
+	assert.Equal(t, "https://example.com/hook", res.Data.URL)
+	assert.Equal(t, f.Repo.ID.String(), res.Data.RepoID)
+	assert.Equal(t, req.EventTypes, res.Data.EventTypes)
+	assert.Len(t, res.Data.Secret, 64, "expected a generated secret to be returned once")
+}
+
+func TestCreateWebhook_CustomSecret(t *testing.T) {
+	f := newFixture(t)
+
+	req := webhookapi.CreateWebhookRequest{URL: "https://example.com/hook", Secret: "0123456789abcdef"}
+	var res server.Response[webhookapi.CreateWebhookResponse]
+	f.do(http.MethodPost, f.webhooksURL(), req, &res)
+	assert.Equal(t, "0123456789abcdef", res.Data.Secret)
+
+	sub, err := f.WebhookService.ReadSubscription(context.Background(), res.Data.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789abcdef", sub.Secret)
+}
+
+func TestCreateWebhook_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		req  webhookapi.CreateWebhookRequest
+	}{
+		{"empty url", webhookapi.CreateWebhookRequest{}},
+		{"invalid url", webhookapi.CreateWebhookRequest{URL: "ftp://example.com"}},
+		{"short secret", webhookapi.CreateWebhookRequest{URL: "https://example.com/hook", Secret: "short"}},
+		{"unknown event", webhookapi.CreateWebhookRequest{URL: "https://example.com/hook", EventTypes: []string{"logs_appended"}}},
+		{"invalid repo id", webhookapi.CreateWebhookRequest{URL: "https://example.com/hook", RepoID: "nope"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			httpRes := f.do(http.MethodPost, f.webhooksURL(), tt.req, nil)
+			assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+		})
+	}
+}
+
+func TestCreateWebhook_RepoNotFound(t *testing.T) {
+	f := newFixture(t)
+
+	req := webhookapi.CreateWebhookRequest{URL: "https://example.com/hook", RepoID: repo.NewRepoID().String()}
+	httpRes := f.do(http.MethodPost, f.webhooksURL(), req, nil)
+	assert.Equal(t, http.StatusNotFound, httpRes.StatusCode)
+}
+
+// --- List / Get ---
+
+func TestListWebhooks_OmitsSecret(t *testing.T) {
+	f := newFixture(t)
+	f.do(http.MethodPost, f.webhooksURL(), webhookapi.CreateWebhookRequest{URL: "https://example.com/a"}, nil)
+	f.do(http.MethodPost, f.webhooksURL(), webhookapi.CreateWebhookRequest{URL: "https://example.com/b"}, nil)
+
+	var res server.ResponseList[map[string]any]
+	f.do(http.MethodGet, f.webhooksURL(), nil, &res)
+	require.Len(t, res.Data, 2)
+	for _, w := range res.Data {
+		assert.NotContains(t, w, "secret")
+	}
+}
+
+func TestGetWebhook(t *testing.T) {
+	f := newFixture(t)
+	var created server.Response[webhookapi.CreateWebhookResponse]
+	f.do(http.MethodPost, f.webhooksURL(), webhookapi.CreateWebhookRequest{URL: "https://example.com/a"}, &created)
+
+	var res server.Response[webhook.Subscription]
+	httpRes := f.do(http.MethodGet, f.webhookURL(created.Data.ID.String()), nil, &res)
+	require.Equal(t, http.StatusOK, httpRes.StatusCode)
+	assert.Equal(t, created.Data.ID, res.Data.ID)
+
+	httpRes = f.do(http.MethodGet, f.webhookURL(webhook.NewSubscriptionID().String()), nil, nil)
+	assert.Equal(t, http.StatusNotFound, httpRes.StatusCode)
+}
+
+// --- Delete ---
+
+func TestDeleteWebhook(t *testing.T) {
+	f := newFixture(t)
+	var created server.Response[webhookapi.CreateWebhookResponse]
+	f.do(http.MethodPost, f.webhooksURL(), webhookapi.CreateWebhookRequest{URL: "https://example.com/a"}, &created)
+
+	httpRes := f.do(http.MethodDelete, f.webhookURL(created.Data.ID.String()), nil, nil)
+	assert.Equal(t, http.StatusNoContent, httpRes.StatusCode)
+
+	httpRes = f.do(http.MethodDelete, f.webhookURL(created.Data.ID.String()), nil, nil)
+	assert.Equal(t, http.StatusNotFound, httpRes.StatusCode)
+}
+
+// --- Deliveries ---
+
+func TestListDeliveries(t *testing.T) {
+	f := newFixture(t)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer receiver.Close()
+
+	var created server.Response[webhookapi.CreateWebhookResponse]
+	f.do(http.MethodPost, f.webhooksURL(), webhookapi.CreateWebhookRequest{URL: receiver.URL}, &created)
+
+	f.WebhookService.Publish(context.Background(), webhook.Event{Type: webhook.EventTaskDeleted, RepoID: f.Repo.ID.String()})
+	f.WebhookService.Publish(context.Background(), webhook.Event{Type: webhook.EventTaskDeleted, RepoID: f.Repo.ID.String()})
+	f.WebhookService.Wait()
+
+	var res server.ResponseList[webhook.Delivery]
+	httpRes := f.do(http.MethodGet, f.webhookURL(created.Data.ID.String())+"/deliveries", nil, &res)
+	require.Equal(t, http.StatusOK, httpRes.StatusCode)
+	require.Len(t, res.Data, 2)
+	for _, d := range res.Data {
+		assert.False(t, d.Success)
+		assert.Equal(t, http.StatusBadRequest, d.StatusCode)
+		assert.Equal(t, webhook.EventTaskDeleted, d.EventType)
+	}
+
+	httpRes = f.do(http.MethodGet, f.webhookURL(created.Data.ID.String())+"/deliveries?limit=1", nil, &res)
+	require.Equal(t, http.StatusOK, httpRes.StatusCode)
+	assert.Len(t, res.Data, 1)
+
+	httpRes = f.do(http.MethodGet, f.webhookURL(created.Data.ID.String())+"/deliveries?limit=500", nil, nil)
+	assert.Equal(t, http.StatusBadRequest, httpRes.StatusCode)
+}
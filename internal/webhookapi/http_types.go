@@ -0,0 +1,79 @@
+package webhookapi
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/cohesivestack/valgo"
+
+	"github.com/vervesh/verve/internal/repo"
+	"github.com/vervesh/verve/internal/webhook"
+)
+
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 100
+	minSecretLen         = 16
+)
+
+// WebhookIDRequest captures the :id path parameter.
+type WebhookIDRequest struct {
+	ID string `param:"id" json:"-"`
+}
+
+func (r WebhookIDRequest) Validate() error {
+	return valgo.In("params", valgo.Is(webhook.SubscriptionIDValidator(r.ID, "id"))).ToError()
+}
+
+// CreateWebhookRequest is the request body for registering a webhook. An
+// empty event_types list subscribes to all events and an empty repo_id to
+// events from all repos. If secret is omitted one is generated.
+type CreateWebhookRequest struct {
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret,omitempty"`
+	EventTypes []string `json:"event_types,omitempty"`
+	RepoID     string   `json:"repo_id,omitempty"`
+}
+
+func (r CreateWebhookRequest) Validate() error {
+	v := valgo.Is(valgo.String(r.URL, "url").Not().Blank())
+	u, err := url.Parse(r.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		v = v.AddErrorMessage("url", "must be a valid http or https URL")
+	}
+	if r.Secret != "" && len(r.Secret) < minSecretLen {
+		v = v.AddErrorMessage("secret", "must be at least 16 characters")
+	}
+	for _, t := range r.EventTypes {
+		if !webhook.ValidEventType(t) {
+			v = v.AddErrorMessage("event_types", "must only contain "+strings.Join(webhook.AllEventTypes, ", "))
+			break
+		}
+	}
+	if r.RepoID != "" {
+		v = v.Is(repo.RepoIDValidator(r.RepoID, "repo_id"))
+	}
+	return v.ToError()
+}
+
+// ListDeliveriesRequest captures the :id path parameter and optional limit
+// query parameter for the delivery log.
+type ListDeliveriesRequest struct {
+	ID    string `param:"id" json:"-"`
+	Limit int    `query:"limit" json:"-"`
+}
+
+func (r ListDeliveriesRequest) Validate() error {
+	v := valgo.In("params", valgo.Is(webhook.SubscriptionIDValidator(r.ID, "id")))
+	if r.Limit < 0 || r.Limit > maxDeliveryLimit {
+		v = v.AddErrorMessage("limit", "must be between 1 and 100")
+	}
+	return v.ToError()
+}
+
+// CreateWebhookResponse is returned when a webhook is registered. It is the
+// only response that includes the signing secret.
+type CreateWebhookResponse struct {
+	*webhook.Subscription
+	Secret string `json:"secret"`
+}
@@ -0,0 +1,175 @@
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// agentEventSeparator starts every structured event record the agent writes
+// to its event stream. Records are RFC 7464 JSON text sequences: an ASCII
+// record separator followed by a JSON object, which ordinary log output
+// never starts with.
+const agentEventSeparator = "\x1e"
+
+// Agent event types. They mirror task.AgentEventType on the server.
+const (
+	agentEventPRCreated    = "pr_created"
+	agentEventPRUpdated    = "pr_updated"
+	agentEventBranchPushed = "branch_pushed"
+	agentEventStatus       = "status"
+	agentEventNoChanges    = "no_changes"
+	agentEventCost         = "cost"
+	agentEventToolCall     = "tool_call"
+	agentEventPlan         = "plan"
+	agentEventStep         = "step"
+	agentEventNote         = "note"
+
+	agentEventBudgetExceeded = "budget_exceeded"
+)
+
+// agentEvent is a structured event emitted by the agent.
+type agentEvent struct {
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+type prEventPayload struct {
+	URL    string `json:"url"`
+	Number int    `json:"number"`
+	Repo   string `json:"repo,omitempty"` // owner/name; empty for the primary repo
+}
+
+type branchEventPayload struct {
+	Branch string `json:"branch"`
+}
+
+type costEventPayload struct {
+	CostUSD float64 `json:"cost_usd"`
+}
+
+// budgetExceededEventPayload is emitted by the agent's API proxy once the
+// metered cost of the attempt reaches the task's remaining budget.
+type budgetExceededEventPayload struct {
+	CostUSD   float64 `json:"cost_usd"`
+	BudgetUSD float64 `json:"budget_usd"`
+}
+
+type toolCallEventPayload struct {
+	Name   string `json:"name"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type planEventPayload struct {
+	Steps []string `json:"steps"`
+}
+
+type stepEventPayload struct {
+	Index  *int   `json:"index"`
+	Status string `json:"status"`
+}
+
+type noteEventPayload struct {
+	Text string `json:"text"`
+}
+
+// legacyMarkers maps the VERVE_* text markers printed by older agent images,
+// and by Claude itself for VERVE_STATUS and VERVE_NOTE, to event types.
+var legacyMarkers = []struct {
+	prefix    string
+	eventType string
+}{
+	{"VERVE_PR_CREATED:", agentEventPRCreated},
+	{"VERVE_PR_UPDATED:", agentEventPRUpdated},
+	{"VERVE_BRANCH_PUSHED:", agentEventBranchPushed},
+	{"VERVE_STATUS:", agentEventStatus},
+	{"VERVE_NO_CHANGES:", agentEventNoChanges},
+	{"VERVE_COST:", agentEventCost},
+	{"VERVE_NOTE:", agentEventNote},
+}
+
+// isAgentEventRecord reports whether line is a structured event record rather
+// than plain log output. Records are kept out of the task's plain logs.
+func isAgentEventRecord(line string) bool {
+	return strings.HasPrefix(line, agentEventSeparator)
+}
+
+// parseAgentEvent parses a structured event record or a legacy VERVE_*
+// marker line into an agent event. It returns false for plain log lines,
+// unknown event types and events whose payload doesn't match their type.
+func parseAgentEvent(line string) (agentEvent, bool) {
+	if isAgentEventRecord(line) {
+		var ev agentEvent
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, agentEventSeparator)), &ev); err != nil {
+			return agentEvent{}, false
+		}
+		if len(ev.Payload) == 0 || string(ev.Payload) == "null" {
+			ev.Payload = json.RawMessage("{}")
+		}
+		return ev, validAgentEvent(ev)
+	}
+
+	// Strip markdown formatting (e.g. **bold**) that the agent may wrap
+	// around marker lines.
+	cleanLine := strings.TrimRight(strings.TrimLeft(line, "*"), "*")
+	for _, m := range legacyMarkers {
+		if !strings.HasPrefix(cleanLine, m.prefix) {
+			continue
+		}
+		value := strings.TrimPrefix(cleanLine, m.prefix)
+		ev := agentEvent{Type: m.eventType, Payload: json.RawMessage(value)}
+		switch m.eventType {
+		case agentEventNoChanges:
+			ev.Payload = json.RawMessage("{}")
+		case agentEventCost:
+			var cost float64
+			if _, err := fmt.Sscanf(value, "%f", &cost); err != nil {
+				return agentEvent{}, false
+			}
+			ev.Payload, _ = json.Marshal(costEventPayload{CostUSD: cost})
+		case agentEventNote:
+			ev.Payload, _ = json.Marshal(noteEventPayload{Text: strings.TrimSpace(value)})
+		}
+		return ev, validAgentEvent(ev)
+	}
+	return agentEvent{}, false
+}
+
+// validAgentEvent reports whether ev has a known type and a JSON object
+// payload of the expected shape.
+func validAgentEvent(ev agentEvent) bool {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(ev.Payload, &obj); err != nil || obj == nil {
+		return false
+	}
+	switch ev.Type {
+	case agentEventPRCreated, agentEventPRUpdated:
+		var p prEventPayload
+		return json.Unmarshal(ev.Payload, &p) == nil && p.URL != ""
+	case agentEventBranchPushed:
+		var p branchEventPayload
+		return json.Unmarshal(ev.Payload, &p) == nil && p.Branch != ""
+	case agentEventCost:
+		var p costEventPayload
+		return json.Unmarshal(ev.Payload, &p) == nil
+	case agentEventBudgetExceeded:
+		var p budgetExceededEventPayload
+		return json.Unmarshal(ev.Payload, &p) == nil && p.BudgetUSD > 0
+	case agentEventToolCall:
+		var p toolCallEventPayload
+		return json.Unmarshal(ev.Payload, &p) == nil && p.Name != ""
+	case agentEventPlan:
+		var p planEventPayload
+		return json.Unmarshal(ev.Payload, &p) == nil && len(p.Steps) > 0
+	case agentEventStep:
+		var p stepEventPayload
+		return json.Unmarshal(ev.Payload, &p) == nil && p.Index != nil && *p.Index >= 0 &&
+			(p.Status == "started" || p.Status == "completed")
+	case agentEventNote:
+		var p noteEventPayload
+		return json.Unmarshal(ev.Payload, &p) == nil && strings.TrimSpace(p.Text) != ""
+	case agentEventStatus, agentEventNoChanges:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAgentEvent(t *testing.T) {
+	tests := []struct {
+		name        string
+		line        string
+		wantOK      bool
+		wantType    string
+		wantPayload string
+	}{
+		{
+			name:        "structured pr created",
+			line:        "\x1e" + `{"type":"pr_created","payload":{"url":"https://github.com/o/r/pull/3","number":3}}`,
+			wantOK:      true,
+			wantType:    agentEventPRCreated,
+			wantPayload: `{"url":"https://github.com/o/r/pull/3","number":3}`,
+		},
+		{
+			name:        "structured pr created in additional repo",
+			line:        "\x1e" + `{"type":"pr_created","payload":{"url":"https://github.com/o/other/pull/4","number":4,"repo":"o/other"}}`,
+			wantOK:      true,
+			wantType:    agentEventPRCreated,
+			wantPayload: `{"url":"https://github.com/o/other/pull/4","number":4,"repo":"o/other"}`,
+		},
+		{
+			name:        "structured tool call",
+			line:        "\x1e" + `{"type":"tool_call","payload":{"name":"Bash","detail":"go test ./..."}}`,
+			wantOK:      true,
+			wantType:    agentEventToolCall,
+			wantPayload: `{"name":"Bash","detail":"go test ./..."}`,
+		},
+		{
+			name:        "structured event without payload",
+			line:        "\x1e" + `{"type":"no_changes"}`,
+			wantOK:      true,
+			wantType:    agentEventNoChanges,
+			wantPayload: `{}`,
+		},
+		{
+			name:        "structured plan",
+			line:        "\x1e" + `{"type":"plan","payload":{"steps":["Read code","Fix bug"]}}`,
+			wantOK:      true,
+			wantType:    agentEventPlan,
+			wantPayload: `{"steps":["Read code","Fix bug"]}`,
+		},
+		{
+			name:        "structured step",
+			line:        "\x1e" + `{"type":"step","payload":{"index":0,"status":"started"}}`,
+			wantOK:      true,
+			wantType:    agentEventStep,
+			wantPayload: `{"index":0,"status":"started"}`,
+		},
+		{
+			name:        "structured budget exceeded",
+			line:        "\x1e" + `{"type":"budget_exceeded","payload":{"cost_usd":1.07,"budget_usd":1}}`,
+			wantOK:      true,
+			wantType:    agentEventBudgetExceeded,
+			wantPayload: `{"cost_usd":1.07,"budget_usd":1}`,
+		},
+		{
+			name:   "structured budget exceeded without budget",
+			line:   "\x1e" + `{"type":"budget_exceeded","payload":{"cost_usd":1.07}}`,
+			wantOK: false,
+		},
+		{
+			name:   "structured empty plan",
+			line:   "\x1e" + `{"type":"plan","payload":{"steps":[]}}`,
+			wantOK: false,
+		},
+		{
+			name:   "structured step with unknown status",
+			line:   "\x1e" + `{"type":"step","payload":{"index":0,"status":"skipped"}}`,
+			wantOK: false,
+		},
+		{
+			name:   "structured unknown type",
+			line:   "\x1e" + `{"type":"dance","payload":{}}`,
+			wantOK: false,
+		},
+		{
+			name:   "structured malformed",
+			line:   "\x1e" + `{"type":`,
+			wantOK: false,
+		},
+		{
+			name:   "structured payload of wrong shape",
+			line:   "\x1e" + `{"type":"cost","payload":{"cost_usd":"lots"}}`,
+			wantOK: false,
+		},
+		{
+			name:        "legacy pr created",
+			line:        `VERVE_PR_CREATED:{"url":"https://github.com/o/r/pull/1","number":1}`,
+			wantOK:      true,
+			wantType:    agentEventPRCreated,
+			wantPayload: `{"url":"https://github.com/o/r/pull/1","number":1}`,
+		},
+		{
+			name:        "legacy bold status",
+			line:        `**VERVE_STATUS:{"tests_status":"pass"}**`,
+			wantOK:      true,
+			wantType:    agentEventStatus,
+			wantPayload: `{"tests_status":"pass"}`,
+		},
+		{
+			name:        "legacy cost",
+			line:        "VERVE_COST:1.25",
+			wantOK:      true,
+			wantType:    agentEventCost,
+			wantPayload: `{"cost_usd":1.25}`,
+		},
+		{
+			name:        "legacy no changes",
+			line:        "VERVE_NO_CHANGES:true",
+			wantOK:      true,
+			wantType:    agentEventNoChanges,
+			wantPayload: `{}`,
+		},
+		{
+			name:        "legacy note",
+			line:        "**VERVE_NOTE: the retry module needs refactoring**",
+			wantOK:      true,
+			wantType:    agentEventNote,
+			wantPayload: `{"text":"the retry module needs refactoring"}`,
+		},
+		{
+			name:   "legacy empty note",
+			line:   "VERVE_NOTE:  ",
+			wantOK: false,
+		},
+		{
+			name:   "legacy malformed pr",
+			line:   "VERVE_PR_CREATED:not json",
+			wantOK: false,
+		},
+		{
+			name:   "plain log line",
+			line:   "[agent] Pull request created",
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev, ok := parseAgentEvent(tt.line)
+			assert.Equal(t, tt.wantOK, ok)
+			if !tt.wantOK {
+				return
+			}
+			assert.Equal(t, tt.wantType, ev.Type)
+			assert.JSONEq(t, tt.wantPayload, string(ev.Payload))
+		})
+	}
+}
+
+func TestIsAgentEventRecord(t *testing.T) {
+	assert.True(t, isAgentEventRecord("\x1e{}"))
+	assert.False(t, isAgentEventRecord(`VERVE_COST:1.0`))
+	assert.False(t, isAgentEventRecord("[tool] Bash: ls"))
+}
@@ -0,0 +1,120 @@
+package worker
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	defaultPollInterval    = 5 * time.Second
+	defaultPollMaxInterval = 30 * time.Second
+
+	// idlePollsBeforeBackoff is the number of consecutive empty long-polls
+	// (each lasting up to the server's long-poll timeout) before the worker
+	// starts backing off between polls.
+	idlePollsBeforeBackoff = 3
+)
+
+// pollBackoff computes the delay before the next poll for work. Work is polled
+// again immediately after the long-poll returns work or a running task
+// finishes, since finished tasks often unblock more. Errors back off
+// exponentially from the base interval, and once no work has been available
+// for several consecutive long-polls the delay between polls grows the same
+// way. Every delay is spread with random jitter so workers don't poll in
+// lockstep.
+type pollBackoff struct {
+	interval    time.Duration
+	maxInterval time.Duration
+	jitter      time.Duration
+
+	emptyPolls int
+	errors     int
+
+	// randInt64N returns a random value in [0, n). Overridable in tests.
+	randInt64N func(n int64) int64
+}
+
+func newPollBackoff(interval, maxInterval, jitter time.Duration) *pollBackoff {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	if maxInterval <= 0 {
+		maxInterval = defaultPollMaxInterval
+	}
+	if maxInterval < interval {
+		maxInterval = interval
+	}
+	if jitter < 0 {
+		jitter = 0
+	}
+	return &pollBackoff{
+		interval:    interval,
+		maxInterval: maxInterval,
+		jitter:      jitter,
+		randInt64N:  rand.Int64N,
+	}
+}
+
+// workReceived resets the backoff. The next poll happens immediately.
+func (b *pollBackoff) workReceived() time.Duration {
+	b.emptyPolls = 0
+	b.errors = 0
+	return 0
+}
+
+// noWork records an empty long-poll and returns the delay before the next
+// poll. Until the worker has been idle for idlePollsBeforeBackoff polls only
+// jitter is applied, keeping pickup latency low while work is flowing.
+func (b *pollBackoff) noWork() time.Duration {
+	b.errors = 0
+	b.emptyPolls++
+	if b.emptyPolls < idlePollsBeforeBackoff {
+		return b.addJitter(0)
+	}
+	return b.addJitter(b.exponential(b.emptyPolls - idlePollsBeforeBackoff))
+}
+
+// failed records a poll error and returns the delay before the next poll.
+func (b *pollBackoff) failed() time.Duration {
+	b.errors++
+	return b.addJitter(b.exponential(b.errors - 1))
+}
+
+// exponential returns interval * 2^n, capped at maxInterval.
+func (b *pollBackoff) exponential(n int) time.Duration {
+	d := b.interval
+	for i := 0; i < n && d < b.maxInterval; i++ {
+		d *= 2
+	}
+	return min(d, b.maxInterval)
+}
+
+func (b *pollBackoff) addJitter(d time.Duration) time.Duration {
+	if b.jitter <= 0 {
+		return d
+	}
+	return d + time.Duration(b.randInt64N(int64(b.jitter)))
+}
+
+// sleepCtx sleeps for d or until ctx is cancelled, whichever comes first.
+func sleepCtx(ctx context.Context, d time.Duration) {
+	sleepWake(ctx, d, nil)
+}
+
+// sleepWake sleeps like sleepCtx but also stops when wake receives, reporting
+// whether it did.
+func sleepWake(ctx context.Context, d time.Duration, wake <-chan struct{}) bool {
+	if d <= 0 {
+		return false
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	case <-wake:
+		return true
+	}
+	return false
+}
@@ -1,7 +1,10 @@
 package worker
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -10,12 +13,15 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/containerd/errdefs"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/joshjon/kit/log"
+
+	"github.com/vervesh/verve/pkg/verveclient"
 )
 
 const DefaultAgentImage = "verve:base"
@@ -23,15 +29,59 @@ const DefaultAgentImage = "verve:base"
 // containerCacheDir is the path inside the agent container where the cache volume is mounted.
 const containerCacheDir = "/cache"
 
+// containerNudgeDir is the directory inside the agent container that user
+// nudges are dropped into. The agent's Claude Code hooks read new files from
+// it between tool calls.
+const (
+	containerNudgeParent = "/tmp"
+	containerNudgeDir    = "verve-nudges"
+)
+
+// containerAttachmentDir is the directory inside the agent container that a
+// task's attachments are copied into before the agent starts.
+const (
+	containerAttachmentParent = "/tmp"
+	containerAttachmentDir    = "verve-attachments"
+)
+
+// containerArtifactDir is where a task's agent saves files for reviewers,
+// such as test reports and screenshots. The worker copies them out after the
+// container exits, keeping at most maxArtifactFiles files of up to
+// maxArtifactSize bytes each and maxArtifactTotalSize bytes in all.
+const (
+	containerArtifactDir = "/tmp/verve-artifacts"
+	maxArtifactFiles     = 50
+	maxArtifactSize      = 25 << 20
+	maxArtifactTotalSize = 100 << 20
+)
+
+// containerShadowDiffPath is where a shadow-mode agent writes its diff.
+// maxShadowDiffSize caps how much of it the worker reads back.
+const (
+	containerShadowDiffPath = "/tmp/verve-shadow.diff"
+	maxShadowDiffSize       = 2 * 1024 * 1024
+)
+
+// containerProposalPath is where a plan-only agent writes its proposal.
+// maxProposalSize caps how much of it the worker reads back.
+const (
+	containerProposalPath = "/tmp/verve-proposal.json"
+	maxProposalSize       = 256 * 1024
+)
+
+// agentUID is the uid/gid of the non-root agent user in the agent image.
+const agentUID = 1000
+
 type DockerRunner struct {
 	client       *client.Client
 	agentImage   string
 	cacheEnabled bool
 	cacheDir     string
+	workspace    *workspaceCache // nil when workspace caching is disabled
 	logger       log.Logger
 }
 
-func NewDockerRunner(agentImage string, cacheEnabled bool, cacheDir string, logger log.Logger) (*DockerRunner, error) {
+func NewDockerRunner(agentImage string, cacheEnabled bool, cacheDir string, workspaceCache WorkspaceCacheConfig, logger log.Logger) (*DockerRunner, error) {
 	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create docker client: %w", err)
@@ -42,7 +92,11 @@ func NewDockerRunner(agentImage string, cacheEnabled bool, cacheDir string, logg
 	if cacheDir == "" {
 		cacheDir = DefaultCacheDir()
 	}
-	return &DockerRunner{client: cli, agentImage: agentImage, cacheEnabled: cacheEnabled, cacheDir: cacheDir, logger: logger}, nil
+	d := &DockerRunner{client: cli, agentImage: agentImage, cacheEnabled: cacheEnabled, cacheDir: cacheDir, logger: logger}
+	if workspaceCache.Enabled {
+		d.workspace = newWorkspaceCache(cli, workspaceCache, logger)
+	}
+	return d, nil
 }
 
 func (d *DockerRunner) Close() error {
@@ -67,6 +121,15 @@ func (d *DockerRunner) EnsureImage(ctx context.Context) error {
 	return fmt.Errorf("agent image %s not found", d.agentImage)
 }
 
+// PruneWorkspaceCaches evicts per-repo workspace cache volumes that exceed
+// the configured TTL or size cap. It is a no-op when workspace caching is
+// disabled.
+func (d *DockerRunner) PruneWorkspaceCaches(ctx context.Context) {
+	if d.workspace != nil {
+		d.workspace.prune(ctx)
+	}
+}
+
 // AgentImage returns the configured agent image name
 func (d *DockerRunner) AgentImage() string {
 	return d.agentImage
@@ -76,6 +139,26 @@ type RunResult struct {
 	Success  bool
 	ExitCode int
 	Error    error
+	// ShadowDiff is the diff a successful shadow-mode run recorded.
+	ShadowDiff string
+	// Proposal is the change plan a successful plan-only run recorded.
+	Proposal *verveclient.Proposal
+	// Artifacts are the files a task's agent saved to its artifacts
+	// directory.
+	Artifacts []ArtifactFile
+}
+
+// AttachmentFile is a file attached to a task, as delivered to its agent.
+type AttachmentFile struct {
+	Name string
+	Data []byte
+}
+
+// ArtifactFile is a file a task's agent produced. Name is its path relative
+// to the artifacts directory.
+type ArtifactFile struct {
+	Name string
+	Data []byte
 }
 
 // AgentConfig holds the configuration for running an agent
@@ -83,17 +166,26 @@ type AgentConfig struct {
 	WorkType string // "task", "epic", or "setup"
 
 	// Task fields
-	TaskID               string
-	TaskNumber           int
-	TaskTitle            string
-	TaskDescription      string
-	SkipPR               bool
-	DraftPR              bool
-	Attempt              int
-	RetryReason          string
-	AcceptanceCriteria   []string
-	RetryContext         string
-	PreviousStatus       string
+	TaskID                   string
+	TaskNumber               int
+	TaskTitle                string
+	TaskDescription          string
+	SkipPR                   bool
+	DraftPR                  bool
+	Attempt                  int
+	RetryReason              string
+	AcceptanceCriteria       []string
+	RetryContext             string
+	PreviousStatus           string
+	EpicContext              string           // Planning summary excerpt for tasks created from an epic
+	WorkspaceCacheGeneration int              // Repo workspace cache generation; older cached volumes are discarded
+	AdditionalRepos          []string         // Full names of other repos a multi-repo task changes
+	ShadowMode               bool             // Record the diff instead of pushing or opening pull requests
+	PlanOnly                 bool             // Propose a change plan for approval instead of implementing the task
+	Proposal                 string           // Previous proposal to revise (plan-only) or approved proposal to implement
+	BaseBranch               string           // Branch to work from and open the PR against; empty uses the repo's default branch
+	Attachments              []AttachmentFile // Files attached to the task, placed in the container for the agent to read
+	CostBudgetUSD            float64          // Spend left in the task's budget; the agent's API proxy stops the run once it is used up. 0 for no limit
 
 	// Epic fields
 	EpicID             string
@@ -101,7 +193,9 @@ type AgentConfig struct {
 	EpicDescription    string
 	EpicPlanningPrompt string
 	EpicFeedback       string // User feedback for re-planning
+	EpicFeedbackType   string // "replan" when EpicFeedback describes an active epic's progress
 	EpicPreviousPlan   string // JSON of previous proposed tasks for re-planning context
+	EpicTranscript     string // Planning transcript so far, to continue from
 	APIURL             string // For epic/setup/conversation agent to call back to server
 
 	// Conversation fields
@@ -128,6 +222,7 @@ type AgentConfig struct {
 	RepoSummary      string
 	RepoExpectations string
 	RepoTechStack    string
+	PRConventions    string // Commit and PR conventions the repo enforces, one per line
 }
 
 // LogCallback is called for each log line from the container
@@ -207,9 +302,15 @@ func (d *DockerRunner) RunAgent(ctx context.Context, cfg AgentConfig, onLog LogC
 		if cfg.EpicFeedback != "" {
 			env = append(env, "EPIC_FEEDBACK="+cfg.EpicFeedback)
 		}
+		if cfg.EpicFeedbackType != "" {
+			env = append(env, "EPIC_FEEDBACK_TYPE="+cfg.EpicFeedbackType)
+		}
 		if cfg.EpicPreviousPlan != "" {
 			env = append(env, "EPIC_PREVIOUS_PLAN="+cfg.EpicPreviousPlan)
 		}
+		if cfg.EpicTranscript != "" {
+			env = append(env, "EPIC_TRANSCRIPT="+cfg.EpicTranscript)
+		}
 	case workTypeConversation:
 		// Conversation-specific env vars
 		env = append(env,
@@ -252,6 +353,34 @@ func (d *DockerRunner) RunAgent(ctx context.Context, cfg AgentConfig, onLog LogC
 				env = append(env, "PREVIOUS_STATUS="+cfg.PreviousStatus)
 			}
 		}
+		if cfg.EpicContext != "" {
+			env = append(env, "EPIC_CONTEXT="+cfg.EpicContext)
+		}
+		if len(cfg.AdditionalRepos) > 0 {
+			env = append(env, "ADDITIONAL_REPOS="+strings.Join(cfg.AdditionalRepos, " "))
+		}
+		if cfg.ShadowMode {
+			env = append(env, "SHADOW_MODE=true")
+		}
+		if cfg.PlanOnly {
+			env = append(env, "PLAN_ONLY=true")
+		}
+		if cfg.Proposal != "" {
+			env = append(env, "PROPOSAL="+cfg.Proposal)
+		}
+		if cfg.BaseBranch != "" {
+			env = append(env, "BASE_BRANCH="+cfg.BaseBranch)
+		}
+		if cfg.PRConventions != "" {
+			env = append(env, "PR_CONVENTIONS="+cfg.PRConventions)
+		}
+		if cfg.CostBudgetUSD > 0 {
+			env = append(env, fmt.Sprintf("COST_BUDGET_USD=%.4f", cfg.CostBudgetUSD))
+		}
+		if len(cfg.Attachments) > 0 {
+			env = append(env, "ATTACHMENTS_DIR="+containerAttachmentParent+"/"+containerAttachmentDir)
+		}
+		env = append(env, "ARTIFACTS_DIR="+containerArtifactDir)
 		if len(cfg.AcceptanceCriteria) > 0 {
 			var ac string
 			for i, c := range cfg.AcceptanceCriteria {
@@ -287,6 +416,29 @@ func (d *DockerRunner) RunAgent(ctx context.Context, cfg AgentConfig, onLog LogC
 		d.logger.Debug("cache volume mounted", "cache.host_dir", d.cacheDir, "cache.container_dir", containerCacheDir)
 	}
 
+	// Mount the repo's workspace cache volume so retries reuse the clone
+	// (and dependency caches, when the shared cache is off) from earlier
+	// attempts. Caching is best effort: the run proceeds without it on error.
+	if d.workspace != nil && workType == "task" && cfg.GitHubRepo != "" {
+		volumeName, release, err := d.workspace.acquire(ctx, cfg.GitHubRepo, cfg.WorkspaceCacheGeneration)
+		if err != nil {
+			d.logger.Warn("workspace cache unavailable, running without it", "repo.full_name", cfg.GitHubRepo, "error", err)
+		} else {
+			// Registered before the container removal defer, so it runs
+			// after the container no longer holds the volume.
+			defer func() {
+				release()
+				d.workspace.prune(context.Background())
+			}()
+			hostConfig.Binds = append(hostConfig.Binds, volumeName+":"+containerWorkspaceCacheDir)
+			env = append(env, "WORKSPACE_CACHE_DIR="+containerWorkspaceCacheDir)
+			if !d.cacheEnabled {
+				env = append(env, "WORKSPACE_CACHE_DEPS=true")
+			}
+			d.logger.Debug("workspace cache mounted", "workspace.volume", volumeName, "workspace.container_dir", containerWorkspaceCacheDir)
+		}
+	}
+
 	// Epic planning and setup scan containers need to call back to the API server.
 	// Three deployment scenarios are handled:
 	// 1. Docker Compose: worker is in Docker, attach container to
@@ -348,6 +500,14 @@ func (d *DockerRunner) RunAgent(ctx context.Context, cfg AgentConfig, onLog LogC
 		}
 	}()
 
+	// Copy the task's attachments in before the agent starts so its prompt
+	// can list them.
+	if len(cfg.Attachments) > 0 {
+		if err := d.copyAttachments(ctx, containerID, cfg.Attachments); err != nil {
+			return RunResult{Error: fmt.Errorf("failed to copy attachments into container %s: %w", containerName, err)}
+		}
+	}
+
 	// Start container
 	if err := d.client.ContainerStart(ctx, containerID, container.StartOptions{}); err != nil {
 		return RunResult{Error: fmt.Errorf("failed to start container %s: %w", containerName, err)}
@@ -401,13 +561,232 @@ func (d *DockerRunner) RunAgent(ctx context.Context, cfg AgentConfig, onLog LogC
 	// Wait for log streaming to complete
 	wg.Wait()
 
-	return RunResult{
+	result := RunResult{
 		Success:  exitCode == 0,
 		ExitCode: int(exitCode),
 	}
+	// The diff and proposal only exist inside the container, so read them
+	// back before the deferred removal. A plan-only run changes nothing, so
+	// it has no diff even in shadow mode.
+	switch {
+	case cfg.PlanOnly && result.Success:
+		proposal, err := d.readProposal(ctx, containerID)
+		if err != nil {
+			return RunResult{ExitCode: result.ExitCode, Error: fmt.Errorf("read proposal: %w", err)}
+		}
+		result.Proposal = proposal
+	case cfg.ShadowMode && result.Success:
+		diff, err := d.readShadowDiff(ctx, containerID)
+		if err != nil {
+			return RunResult{ExitCode: result.ExitCode, Error: fmt.Errorf("read shadow diff: %w", err)}
+		}
+		result.ShadowDiff = diff
+	}
+	if workType == "task" {
+		// Artifacts are kept whatever the outcome; a failing test report is
+		// as useful to reviewers as a passing one.
+		artifacts, err := d.readArtifacts(ctx, containerID)
+		if err != nil {
+			d.logger.Warn("failed to read artifacts", "container.name", containerName, "error", err)
+		}
+		result.Artifacts = artifacts
+	}
+	return result
+}
+
+// readArtifacts copies the files a task's agent saved to its artifacts
+// directory out of its container. Files beyond the artifact limits are
+// skipped. It returns nil if the agent saved none.
+func (d *DockerRunner) readArtifacts(ctx context.Context, containerID string) ([]ArtifactFile, error) {
+	rc, _, err := d.client.CopyFromContainer(ctx, containerID, containerArtifactDir)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = rc.Close() }()
+	return readTarArtifacts(rc)
+}
+
+// readTarArtifacts returns the regular files in a tar stream of the
+// artifacts directory, named relative to it. It stops at maxArtifactFiles
+// files and skips files that are too large or would exceed
+// maxArtifactTotalSize. Anything read before an error is returned with it.
+func readTarArtifacts(r io.Reader) ([]ArtifactFile, error) {
+	var files []ArtifactFile
+	var total int64
+	tr := tar.NewReader(r)
+	for len(files) < maxArtifactFiles {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return files, err
+		}
+		if hdr.Typeflag != tar.TypeReg || hdr.Size > maxArtifactSize || total+hdr.Size > maxArtifactTotalSize {
+			continue
+		}
+		// Entries are named after the copied directory, e.g.
+		// "verve-artifacts/coverage/summary.txt".
+		_, name, ok := strings.Cut(hdr.Name, "/")
+		if !ok || name == "" {
+			continue
+		}
+		b, err := io.ReadAll(io.LimitReader(tr, maxArtifactSize))
+		if err != nil {
+			return files, err
+		}
+		total += int64(len(b))
+		files = append(files, ArtifactFile{Name: name, Data: b})
+	}
+	return files, nil
+}
+
+// readShadowDiff copies the diff a shadow-mode agent recorded out of its
+// container. It returns an empty string if the agent made no changes.
+func (d *DockerRunner) readShadowDiff(ctx context.Context, containerID string) (string, error) {
+	rc, _, err := d.client.CopyFromContainer(ctx, containerID, containerShadowDiffPath)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	defer func() { _ = rc.Close() }()
+	return readTarFile(rc, maxShadowDiffSize)
+}
+
+// readProposal copies the proposal a plan-only agent recorded out of its
+// container. Unlike a shadow diff the proposal is required: a plan-only run
+// that recorded none failed to do its job.
+func (d *DockerRunner) readProposal(ctx context.Context, containerID string) (*verveclient.Proposal, error) {
+	rc, _, err := d.client.CopyFromContainer(ctx, containerID, containerProposalPath)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, errors.New("agent recorded no proposal")
+		}
+		return nil, err
+	}
+	defer func() { _ = rc.Close() }()
+	data, err := readTarFile(rc, maxProposalSize)
+	if err != nil {
+		return nil, err
+	}
+	return parseProposal(data)
+}
+
+// parseProposal decodes the JSON proposal a plan-only agent wrote. The
+// summary is required; files and risks may be empty.
+func parseProposal(data string) (*verveclient.Proposal, error) {
+	var p verveclient.Proposal
+	if err := json.Unmarshal([]byte(data), &p); err != nil {
+		return nil, fmt.Errorf("decode proposal: %w", err)
+	}
+	if strings.TrimSpace(p.Summary) == "" {
+		return nil, errors.New("proposal has no summary")
+	}
+	return &p, nil
+}
+
+// readTarFile returns the contents of the first regular file in a tar
+// stream, failing if it is larger than limit bytes.
+func readTarFile(r io.Reader, limit int64) (string, error) {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if hdr.Size > limit {
+			return "", fmt.Errorf("%s is %d bytes, larger than the %d byte limit", hdr.Name, hdr.Size, limit)
+		}
+		b, err := io.ReadAll(io.LimitReader(tr, limit))
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
 }
 
 // streamLogs reads from the Docker multiplexed log stream and calls the callback for each line
+// copyAttachments copies a task's attachments into a created container's
+// attachment directory, owned by the agent user.
+func (d *DockerRunner) copyAttachments(ctx context.Context, containerID string, files []AttachmentFile) error {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     containerAttachmentDir + "/",
+		Mode:     0o755,
+		Uid:      agentUID,
+		Gid:      agentUID,
+	}); err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     containerAttachmentDir + "/" + f.Name,
+			Mode:     0o644,
+			Size:     int64(len(f.Data)),
+			Uid:      agentUID,
+			Gid:      agentUID,
+		}); err != nil {
+			return err
+		}
+		if _, err := tw.Write(f.Data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return d.client.CopyToContainer(ctx, containerID, containerAttachmentParent, &buf, container.CopyToContainerOptions{CopyUIDGID: true})
+}
+
+// DeliverNudges drops user nudges into a running task container as one file
+// per nudge. It fails if the task's container does not exist yet.
+func (d *DockerRunner) DeliverNudges(ctx context.Context, taskID string, nudges []Nudge) error {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     containerNudgeDir + "/",
+		Mode:     0o755,
+		Uid:      agentUID,
+		Gid:      agentUID,
+	}); err != nil {
+		return err
+	}
+	for _, n := range nudges {
+		if err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     fmt.Sprintf("%s/%020d.txt", containerNudgeDir, n.ID),
+			Mode:     0o644,
+			Size:     int64(len(n.Message)),
+			Uid:      agentUID,
+			Gid:      agentUID,
+		}); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(tw, n.Message); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return d.client.CopyToContainer(ctx, "verve-task-"+taskID, containerNudgeParent, &buf, container.CopyToContainerOptions{CopyUIDGID: true})
+}
+
 func (d *DockerRunner) streamLogs(reader io.Reader, onLog LogCallback) {
 	// Create a pipe to demultiplex Docker's stream format
 	stdoutPipeR, stdoutPipeW := io.Pipe()
@@ -1,9 +1,12 @@
 package worker
 
 import (
+	"archive/tar"
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAgentConfig_ConversationFields(t *testing.T) {
@@ -199,3 +202,70 @@ func TestRewriteLocalhostURL(t *testing.T) {
 		})
 	}
 }
+
+func TestReadTarFile(t *testing.T) {
+	diff := "diff --git a/main.go b/main.go\n+package main\n"
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "verve-shadow.diff", Mode: 0o644, Size: int64(len(diff))}))
+	_, err := tw.Write([]byte(diff))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	got, err := readTarFile(bytes.NewReader(buf.Bytes()), 1024)
+	require.NoError(t, err)
+	assert.Equal(t, diff, got)
+
+	_, err = readTarFile(bytes.NewReader(buf.Bytes()), 8)
+	assert.Error(t, err)
+
+	var empty bytes.Buffer
+	require.NoError(t, tar.NewWriter(&empty).Close())
+	got, err = readTarFile(&empty, 1024)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestParseProposal(t *testing.T) {
+	p, err := parseProposal(`{"summary":"Add caching","approach":"Wrap the client","files":[{"path":"client.go","change":"add cache"}],"risks":["stale reads"]}`)
+	require.NoError(t, err)
+	assert.Equal(t, "Add caching", p.Summary)
+	assert.Equal(t, "Wrap the client", p.Approach)
+	require.Len(t, p.Files, 1)
+	assert.Equal(t, "client.go", p.Files[0].Path)
+	assert.Equal(t, []string{"stale reads"}, p.Risks)
+
+	_, err = parseProposal(`{"summary":"  ","approach":"x"}`)
+	assert.Error(t, err, "summary is required")
+
+	_, err = parseProposal("not json")
+	assert.Error(t, err)
+}
+
+func TestReadTarArtifacts(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: "verve-artifacts/", Mode: 0o755}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: "verve-artifacts/coverage/", Mode: 0o755}))
+	for name, body := range map[string]string{
+		"verve-artifacts/report.xml":           "<testsuites/>",
+		"verve-artifacts/coverage/summary.txt": "total: 87%",
+	} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0o644, Size: int64(len(body))}))
+		_, err := tw.Write([]byte(body))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeSymlink, Name: "verve-artifacts/link", Linkname: "/etc/passwd"}))
+	require.NoError(t, tw.Close())
+
+	files, err := readTarArtifacts(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	got := map[string]string{}
+	for _, f := range files {
+		got[f.Name] = string(f.Data)
+	}
+	assert.Equal(t, map[string]string{
+		"report.xml":           "<testsuites/>",
+		"coverage/summary.txt": "total: 87%",
+	}, got)
+}
@@ -0,0 +1,97 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vervesh/verve/pkg/verveclient"
+)
+
+// permitRetryInterval is how long the worker waits before asking for a
+// permit again after the request failed.
+const permitRetryInterval = 5 * time.Second
+
+// minPermitWait keeps the worker from asking for a permit again right away
+// when the server asks it to wait less than this.
+const minPermitWait = 500 * time.Millisecond
+
+// acquirePermit waits until the server grants a permit to run the task's
+// agent, keeping agents across all workers within the server's limits. The
+// permit is renewed until the returned release func is called. It only
+// fails when ctx ends first.
+func (w *Worker) acquirePermit(ctx context.Context, task *Task, streamer *logStreamer) (func(), error) {
+	req := verveclient.PermitRequest{WorkerID: w.workerID, TaskID: task.ID, Model: task.Model}
+	waiting := false
+	for {
+		var wait time.Duration
+		res, err := w.api.AcquirePermit(ctx, req)
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			w.logger.Warn("failed to acquire agent permit", "task.id", task.ID, "error", err)
+			wait = permitRetryInterval
+		case res.Granted:
+			if waiting {
+				streamer.AddLine("[verve] Agent permit granted")
+			}
+			if res.Permit == nil {
+				return func() {}, nil
+			}
+			return w.holdPermit(ctx, task.ID, *res.Permit), nil
+		default:
+			if !waiting {
+				w.logger.Info("waiting for agent permit", "task.id", task.ID, "permit.reason", res.Reason)
+				streamer.AddLine(fmt.Sprintf("[verve] Waiting for an agent permit: %s", res.Reason))
+				waiting = true
+			}
+			wait = time.Duration(res.RetryAfterMs) * time.Millisecond
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(max(wait, minPermitWait)):
+		}
+	}
+}
+
+// holdPermit renews a granted permit in the background until the returned
+// func releases it.
+func (w *Worker) holdPermit(ctx context.Context, taskID string, p verveclient.Permit) func() {
+	renewCtx, stopRenewing := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		interval := max(p.ExpiresAt.Sub(p.GrantedAt)/3, time.Second)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-renewCtx.Done():
+				return
+			case <-ticker.C:
+				if _, err := w.api.RenewPermit(renewCtx, p.ID); err != nil && renewCtx.Err() == nil {
+					w.logger.Warn("failed to renew agent permit", "task.id", taskID, "permit.id", p.ID, "error", err)
+					if verveclient.IsNotFound(err) {
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return func() {
+		stopRenewing()
+		<-done
+		// Release even when the task was stopped so the slot frees up right
+		// away instead of when the permit expires.
+		releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+		defer cancel()
+		if err := w.api.ReleasePermit(releaseCtx, p.ID); err != nil {
+			w.logger.Warn("failed to release agent permit", "task.id", taskID, "permit.id", p.ID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joshjon/kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/pkg/verveclient"
+)
+
+// permitServer is a fake agent API that denies the first permit request and
+// grants the next one.
+type permitServer struct {
+	mu       sync.Mutex
+	requests int
+	released []string
+}
+
+func (s *permitServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == verveclient.APIPrefix+"/agent/permits":
+		s.requests++
+		res := verveclient.PermitResponse{Reason: "max concurrent agents reached", RetryAfterMs: 1}
+		if s.requests > 1 {
+			now := time.Now()
+			res = verveclient.PermitResponse{Granted: true, Permit: &verveclient.Permit{ID: "pmt_1", GrantedAt: now, ExpiresAt: now.Add(time.Minute)}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": res})
+	case r.Method == http.MethodDelete:
+		s.released = append(s.released, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func newPermitTestWorker(t *testing.T, handler http.Handler) *Worker {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	api := verveclient.NewClient(srv.URL)
+	return &Worker{
+		api:      api,
+		agent:    api,
+		logger:   log.NewLogger(log.WithNop()),
+		workerID: "worker-1",
+	}
+}
+
+func TestAcquirePermit_WaitsUntilGranted(t *testing.T) {
+	fake := &permitServer{}
+	w := newPermitTestWorker(t, fake)
+	ctx := context.Background()
+	streamer := newLogStreamer(ctx, w, "tsk_1", 1)
+	defer streamer.Stop()
+
+	release, err := w.acquirePermit(ctx, &Task{ID: "tsk_1", Model: "sonnet"}, streamer)
+	require.NoError(t, err)
+	release()
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	assert.Equal(t, 2, fake.requests)
+	assert.Equal(t, []string{verveclient.APIPrefix + "/agent/permits/pmt_1"}, fake.released)
+}
+
+func TestAcquirePermit_StopsWithContext(t *testing.T) {
+	w := newPermitTestWorker(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": verveclient.PermitResponse{Reason: "tokens per minute limit reached", RetryAfterMs: 60_000}})
+	}))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	streamer := newLogStreamer(context.Background(), w, "tsk_1", 1)
+	defer streamer.Stop()
+
+	_, err := w.acquirePermit(ctx, &Task{ID: "tsk_1"}, streamer)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,213 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joshjon/kit/log"
+)
+
+// Worker modes select how work items are run.
+const (
+	ModeDocker = "docker" // Run agents in Docker containers
+	ModeSim    = "sim"    // Emulate agents from a scenario file, without Docker
+)
+
+// simImage is reported as the agent image in sim mode.
+const simImage = "sim"
+
+// SimScenario scripts what an emulated agent does for each task it runs.
+// Steps run in order; the run succeeds if it gets past the last step.
+type SimScenario struct {
+	Steps []SimStep `json:"steps"`
+}
+
+// SimStep is one action of an emulated agent. Exactly one of Log, Event,
+// Sleep, Exit or Error should be set.
+//
+// Log lines and event payloads may use the placeholders {{task_id}},
+// {{task_number}}, {{task_title}}, {{repo}} and {{attempt}}.
+type SimStep struct {
+	// Attempt limits the step to one task attempt, e.g. to fail the first
+	// attempt and succeed on retry. Zero runs the step on every attempt.
+	Attempt int `json:"attempt,omitempty"`
+
+	Log   string      `json:"log,omitempty"`   // Print a log line; VERVE_* markers are recognised as usual
+	Event *agentEvent `json:"event,omitempty"` // Emit a structured agent event
+	Sleep simDuration `json:"sleep,omitempty"` // Pause, e.g. "2s"
+	Exit  *int        `json:"exit,omitempty"`  // End the run with this exit code
+	Error string      `json:"error,omitempty"` // End the run as if the container failed to run
+}
+
+// simDuration is a time.Duration written as a string such as "1.5s".
+type simDuration time.Duration
+
+func (d *simDuration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"2s\": %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = simDuration(v)
+	return nil
+}
+
+// defaultSimScenario walks through a short plan, reports cost and finishes
+// without changes, so tasks close without needing GitHub.
+var defaultSimScenario = SimScenario{
+	Steps: []SimStep{
+		{Log: "Simulated agent starting task {{task_number}}: {{task_title}}"},
+		{Event: simEvent(agentEventPlan, planEventPayload{Steps: []string{"Read the code", "Make the change", "Run the tests"}})},
+		{Event: simEvent(agentEventStep, stepEventPayload{Index: simIndex(0), Status: "started"})},
+		{Event: simEvent(agentEventToolCall, toolCallEventPayload{Name: "Read", Detail: "README.md"})},
+		{Sleep: simDuration(2 * time.Second)},
+		{Event: simEvent(agentEventStep, stepEventPayload{Index: simIndex(0), Status: "completed"})},
+		{Event: simEvent(agentEventStep, stepEventPayload{Index: simIndex(1), Status: "started"})},
+		{Event: simEvent(agentEventToolCall, toolCallEventPayload{Name: "Edit", Detail: "main.go"})},
+		{Sleep: simDuration(2 * time.Second)},
+		{Event: simEvent(agentEventStep, stepEventPayload{Index: simIndex(1), Status: "completed"})},
+		{Event: simEvent(agentEventStep, stepEventPayload{Index: simIndex(2), Status: "started"})},
+		{Event: simEvent(agentEventToolCall, toolCallEventPayload{Name: "Bash", Detail: "go test ./..."})},
+		{Sleep: simDuration(time.Second)},
+		{Event: simEvent(agentEventStep, stepEventPayload{Index: simIndex(2), Status: "completed"})},
+		{Event: simEvent(agentEventCost, costEventPayload{CostUSD: 0.05})},
+		{Event: simEvent(agentEventNoChanges, struct{}{})},
+		{Log: "Simulated agent finished"},
+	},
+}
+
+func simEvent(eventType string, payload any) *agentEvent {
+	b, _ := json.Marshal(payload)
+	return &agentEvent{Type: eventType, Payload: b}
+}
+
+func simIndex(i int) *int { return &i }
+
+// LoadSimScenario reads a scenario file. An empty path returns the built-in
+// scenario.
+func LoadSimScenario(path string) (SimScenario, error) {
+	if path == "" {
+		return defaultSimScenario, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return SimScenario{}, fmt.Errorf("read sim scenario: %w", err)
+	}
+	var scenario SimScenario
+	if err := json.Unmarshal(b, &scenario); err != nil {
+		return SimScenario{}, fmt.Errorf("parse sim scenario %s: %w", path, err)
+	}
+	for i, step := range scenario.Steps {
+		if step.Event == nil {
+			continue
+		}
+		if len(step.Event.Payload) == 0 || string(step.Event.Payload) == "null" {
+			step.Event.Payload = json.RawMessage("{}")
+		}
+		if !validAgentEvent(*step.Event) {
+			return SimScenario{}, fmt.Errorf("sim scenario step %d: invalid %q event", i, step.Event.Type)
+		}
+	}
+	return scenario, nil
+}
+
+// SimRunner emulates agent runs from a scenario instead of starting
+// containers, so the server can be developed and demoed without Docker or
+// credentials. Only tasks are emulated; other work items fail.
+type SimRunner struct {
+	scenario SimScenario
+	logger   log.Logger
+}
+
+// NewSimRunner creates a SimRunner for the scenario file at path, or the
+// built-in scenario when path is empty.
+func NewSimRunner(path string, logger log.Logger) (*SimRunner, error) {
+	scenario, err := LoadSimScenario(path)
+	if err != nil {
+		return nil, err
+	}
+	return &SimRunner{scenario: scenario, logger: logger}, nil
+}
+
+func (s *SimRunner) Close() error { return nil }
+
+func (s *SimRunner) EnsureImage(context.Context) error { return nil }
+
+func (s *SimRunner) PruneWorkspaceCaches(context.Context) {}
+
+func (s *SimRunner) AgentImage() string { return simImage }
+
+// DeliverNudges logs nudges, since there is no agent to receive them.
+func (s *SimRunner) DeliverNudges(_ context.Context, taskID string, nudges []Nudge) error {
+	for _, n := range nudges {
+		s.logger.Info("sim agent received nudge", "task.id", taskID, "nudge.id", n.ID)
+	}
+	return nil
+}
+
+// RunAgent plays the scenario for a task, passing its log lines and events to
+// onLog the way a container's output would be.
+func (s *SimRunner) RunAgent(ctx context.Context, cfg AgentConfig, onLog LogCallback) RunResult {
+	workType := cfg.WorkType
+	if workType == "" {
+		workType = "task"
+	}
+	if workType != "task" {
+		return RunResult{Error: fmt.Errorf("%s work is not supported in sim mode", workType)}
+	}
+
+	logReplacer := simReplacer(cfg, func(s string) string { return s })
+	payloadReplacer := simReplacer(cfg, func(s string) string {
+		b, _ := json.Marshal(s)
+		return strings.Trim(string(b), `"`)
+	})
+
+	for _, step := range s.scenario.Steps {
+		if ctx.Err() != nil {
+			return RunResult{Error: ctx.Err()}
+		}
+		if step.Attempt != 0 && step.Attempt != cfg.Attempt {
+			continue
+		}
+		switch {
+		case step.Log != "":
+			onLog(logReplacer.Replace(step.Log))
+		case step.Event != nil:
+			ev := agentEvent{Type: step.Event.Type, Payload: json.RawMessage(payloadReplacer.Replace(string(step.Event.Payload)))}
+			b, _ := json.Marshal(ev)
+			onLog(agentEventSeparator + string(b))
+		case step.Sleep > 0:
+			select {
+			case <-time.After(time.Duration(step.Sleep)):
+			case <-ctx.Done():
+				return RunResult{Error: ctx.Err()}
+			}
+		case step.Exit != nil:
+			return RunResult{Success: *step.Exit == 0, ExitCode: *step.Exit}
+		case step.Error != "":
+			return RunResult{Error: errors.New(step.Error)}
+		}
+	}
+	return RunResult{Success: true}
+}
+
+// simReplacer expands scenario placeholders, passing each value through
+// escape first.
+func simReplacer(cfg AgentConfig, escape func(string) string) *strings.Replacer {
+	return strings.NewReplacer(
+		"{{task_id}}", escape(cfg.TaskID),
+		"{{task_number}}", strconv.Itoa(cfg.TaskNumber),
+		"{{task_title}}", escape(cfg.TaskTitle),
+		"{{repo}}", escape(cfg.GitHubRepo),
+		"{{attempt}}", strconv.Itoa(cfg.Attempt),
+	)
+}
@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSimScenario(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "scenario.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestSimRunner_RunAgent(t *testing.T) {
+	path := writeSimScenario(t, `{"steps": [
+		{"log": "working on {{task_number}} in {{repo}}"},
+		{"event": {"type": "branch_pushed", "payload": {"branch": "verve/{{task_id}}"}}},
+		{"log": "VERVE_COST:0.25"},
+		{"sleep": "1ms"},
+		{"attempt": 1, "log": "rate_limit_error"},
+		{"attempt": 1, "exit": 1},
+		{"event": {"type": "no_changes"}}
+	]}`)
+	runner, err := NewSimRunner(path, nil)
+	require.NoError(t, err)
+
+	cfg := AgentConfig{TaskID: "tsk_1", TaskNumber: 7, GitHubRepo: "owner/repo", Attempt: 1}
+	var lines []string
+	onLog := func(line string) { lines = append(lines, line) }
+
+	res := runner.RunAgent(context.Background(), cfg, onLog)
+	assert.False(t, res.Success)
+	assert.Equal(t, 1, res.ExitCode)
+	require.Len(t, lines, 4)
+	assert.Equal(t, "working on 7 in owner/repo", lines[0])
+	ev, ok := parseAgentEvent(lines[1])
+	require.True(t, ok)
+	assert.Equal(t, agentEventBranchPushed, ev.Type)
+	assert.JSONEq(t, `{"branch":"verve/tsk_1"}`, string(ev.Payload))
+	ev, ok = parseAgentEvent(lines[2])
+	require.True(t, ok)
+	assert.Equal(t, agentEventCost, ev.Type)
+
+	// Steps limited to the first attempt are skipped on retry.
+	lines = nil
+	cfg.Attempt = 2
+	res = runner.RunAgent(context.Background(), cfg, onLog)
+	assert.True(t, res.Success)
+	require.Len(t, lines, 4)
+	ev, ok = parseAgentEvent(lines[3])
+	require.True(t, ok)
+	assert.Equal(t, agentEventNoChanges, ev.Type)
+}
+
+func TestSimRunner_RunAgent_Cancelled(t *testing.T) {
+	runner, err := NewSimRunner(writeSimScenario(t, `{"steps": [{"sleep": "1m"}]}`), nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	res := runner.RunAgent(ctx, AgentConfig{TaskID: "tsk_1"}, func(string) {})
+	assert.ErrorIs(t, res.Error, context.DeadlineExceeded)
+}
+
+func TestSimRunner_RunAgent_UnsupportedWorkType(t *testing.T) {
+	runner, err := NewSimRunner("", nil)
+	require.NoError(t, err)
+
+	res := runner.RunAgent(context.Background(), AgentConfig{WorkType: workTypeEpic}, func(string) {})
+	assert.EqualError(t, res.Error, "epic work is not supported in sim mode")
+}
+
+func TestLoadSimScenario(t *testing.T) {
+	scenario, err := LoadSimScenario("")
+	require.NoError(t, err)
+	for _, step := range scenario.Steps {
+		if step.Event != nil {
+			assert.True(t, validAgentEvent(*step.Event), step.Event.Type)
+		}
+	}
+
+	_, err = LoadSimScenario(writeSimScenario(t, `{"steps": [{"event": {"type": "plan", "payload": {"steps": []}}}]}`))
+	assert.EqualError(t, err, `sim scenario step 0: invalid "plan" event`)
+
+	_, err = LoadSimScenario(writeSimScenario(t, `{"steps": [{"sleep": "soon"}]}`))
+	assert.Error(t, err)
+}
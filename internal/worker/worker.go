@@ -1,11 +1,12 @@
 package worker
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -15,9 +16,16 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/joshjon/kit/log"
+	"google.golang.org/grpc"
+
 	"github.com/vervesh/verve/internal/redact"
+	"github.com/vervesh/verve/pkg/verveclient"
 )
 
+// workerHeartbeatInterval is how often the worker reports the tasks it is
+// running. The server releases them after two minutes without contact.
+const workerHeartbeatInterval = 30 * time.Second
+
 const (
 	workTypeEpic         = "epic"
 	workTypeSetup        = "setup"
@@ -37,93 +45,72 @@ func DefaultCacheDir() string {
 // Config holds the worker configuration
 type Config struct {
 	APIURL                    string
-	AnthropicAPIKey           string // API key auth (pay-per-use)
-	AnthropicBaseURL          string // Custom base URL for Anthropic API (e.g. for proxies or self-hosted endpoints)
-	ClaudeCodeOAuthToken      string // OAuth token auth (subscription-based, alternative to API key)
-	AgentImage                string // Docker image for agent — defaults to verve:base
-	MaxConcurrentTasks        int    // Maximum concurrent tasks (default: 1)
-	DryRun                    bool   // Skip Claude and make a dummy change instead
-	GitHubInsecureSkipVerify  bool   // Disable TLS certificate verification for GitHub operations in agent containers
-	StripAnthropicBetaHeaders bool   // Strip anthropic-beta headers via reverse proxy inside agent containers (for Bedrock proxy compatibility)
-	CacheEnabled              bool   // Mount a host volume for dependency caching between agent runs (default: true)
-	CacheDir                  string // Host directory for cache volume (default: ~/.cache/verve)
-}
-
-type Task struct {
-	ID                 string   `json:"id"`
-	Number             int      `json:"number"`
-	RepoID             string   `json:"repo_id"`
-	Title              string   `json:"title"`
-	Description        string   `json:"description"`
-	Status             string   `json:"status"`
-	Attempt            int      `json:"attempt"`
-	MaxAttempts        int      `json:"max_attempts"`
-	RetryReason        string   `json:"retry_reason,omitempty"`
-	AcceptanceCriteria []string `json:"acceptance_criteria"`
-	RetryContext       string   `json:"retry_context,omitempty"`
-	AgentStatus        string   `json:"agent_status,omitempty"`
-	CostUSD            float64  `json:"cost_usd"`
-	MaxCostUSD         float64  `json:"max_cost_usd,omitempty"`
-	SkipPR             bool     `json:"skip_pr"`
-	DraftPR            bool     `json:"draft_pr"`
-	Model              string   `json:"model,omitempty"`
-}
-
-type Epic struct {
-	ID             string          `json:"id"`
-	RepoID         string          `json:"repo_id"`
-	Title          string          `json:"title"`
-	Description    string          `json:"description"`
-	PlanningPrompt string          `json:"planning_prompt,omitempty"`
-	Model          string          `json:"model,omitempty"`
-	Feedback       *string         `json:"feedback,omitempty"`
-	ProposedTasks  json.RawMessage `json:"proposed_tasks,omitempty"`
-}
-
-type Setup struct {
-	TaskID   string `json:"task_id"`
-	RepoID   string `json:"repo_id"`
-	FullName string `json:"full_name"`
-}
-
-type Conversation struct {
-	ID             string          `json:"id"`
-	RepoID         string          `json:"repo_id"`
-	Title          string          `json:"title"`
-	Messages       json.RawMessage `json:"messages"`
-	PendingMessage string          `json:"pending_message"`
-	Model          string          `json:"model,omitempty"`
-}
-
-// PollResponse is a discriminated union returned by the unified poll endpoint.
-type PollResponse struct {
-	Type         string        `json:"type"` // "task", "epic", "setup", "conversation", or "stop"
-	Task         *Task         `json:"task,omitempty"`
-	Epic         *Epic         `json:"epic,omitempty"`
-	Setup        *Setup        `json:"setup,omitempty"`
-	Conversation *Conversation `json:"conversation,omitempty"`
-	Stops        []StopSignal  `json:"stops,omitempty"`
-	GitHubToken  string        `json:"github_token"`
-	RepoFullName string        `json:"repo_full_name"`
-
-	// Repo setup data (injected into agent prompts)
-	RepoSummary      string `json:"repo_summary,omitempty"`
-	RepoExpectations string `json:"repo_expectations,omitempty"`
-	RepoTechStack    string `json:"repo_tech_stack,omitempty"`
-}
-
-// StopSignal identifies an entity that should be stopped (mirrors agentapi.StopSignal).
-type StopSignal struct {
-	EntityType string `json:"entity_type"`
-	EntityID   string `json:"entity_id"`
+	GRPCAddr                  string        // host:port of the server's agent gRPC service; when set, polls, task logs, heartbeats and completions use it instead of REST
+	GRPCTLS                   bool          // Connect to GRPCAddr over TLS, verifying the server against the system roots
+	GRPCCACert                string        // PEM CA bundle to verify GRPCAddr's certificate against instead of the system roots; implies GRPCTLS
+	GRPCInsecure              bool          // Allow plaintext gRPC to a non-loopback GRPCAddr, sending GitHub tokens unencrypted
+	AnthropicAPIKey           string        // API key auth (pay-per-use)
+	AnthropicBaseURL          string        // Custom base URL for Anthropic API (e.g. for proxies or self-hosted endpoints)
+	ClaudeCodeOAuthToken      string        // OAuth token auth (subscription-based, alternative to API key)
+	AgentImage                string        // Docker image for agent — defaults to verve:base
+	MaxConcurrentTasks        int           // Maximum concurrent tasks (default: 1)
+	DryRun                    bool          // Skip Claude and make a dummy change instead
+	GitHubInsecureSkipVerify  bool          // Disable TLS certificate verification for GitHub operations in agent containers
+	StripAnthropicBetaHeaders bool          // Strip anthropic-beta headers via reverse proxy inside agent containers (for Bedrock proxy compatibility)
+	CacheEnabled              bool          // Mount a host volume for dependency caching between agent runs (default: true)
+	CacheDir                  string        // Host directory for cache volume (default: ~/.cache/verve)
+	WorkspaceCacheEnabled     bool          // Keep a Docker volume per repo with the clone and dependency caches between task attempts
+	WorkspaceCacheTTL         time.Duration // Remove workspace cache volumes unused for this long (default: 72h, 0 disables)
+	WorkspaceCacheMaxBytes    int64         // Evict least recently used workspace caches above this total size (default: 20 GiB, 0 disables)
+	PollInterval              time.Duration // Base delay between polls when backing off (default: 5s)
+	PollMaxInterval           time.Duration // Maximum delay between polls when idle or erroring (default: 30s)
+	PollJitter                time.Duration // Maximum random delay added before each poll to spread load (default: 2s)
+	Mode                      string        // How agents run: ModeDocker (default) or ModeSim
+	SimScenario               string        // Scenario file emulated agents follow in sim mode (default: built-in scenario)
+	Version                   string        // Build version reported when registering with the server
+	Labels                    []string      // Labels reported to the server; tasks that require labels only go to workers that have them all
+}
+
+// Work item types received from the poll endpoint.
+type (
+	PollResponse = verveclient.PollResponse
+	Task         = verveclient.PollTask
+	Epic         = verveclient.PollEpic
+	Setup        = verveclient.Setup
+	Conversation = verveclient.PollConversation
+	StopSignal   = verveclient.StopSignal
+	Nudge        = verveclient.Nudge
+)
+
+// agentRunner runs agents for claimed work items. DockerRunner runs them in
+// containers; SimRunner emulates them for development.
+type agentRunner interface {
+	EnsureImage(ctx context.Context) error
+	PruneWorkspaceCaches(ctx context.Context)
+	AgentImage() string
+	RunAgent(ctx context.Context, cfg AgentConfig, onLog LogCallback) RunResult
+	DeliverNudges(ctx context.Context, taskID string, nudges []Nudge) error
+	Close() error
+}
+
+// agentPlane is the hot path of the agent API: polls, task logs, task
+// heartbeats and completions. The REST client serves it unless the worker
+// is configured with the server's gRPC address.
+type agentPlane interface {
+	Poll(ctx context.Context, opts verveclient.PollOptions) (*PollResponse, error)
+	PollBatch(ctx context.Context, opts verveclient.PollOptions, max int) ([]*PollResponse, error)
+	OpenTaskLogs(ctx context.Context, taskID string, attempt int) verveclient.TaskLogWriter
+	TaskHeartbeat(ctx context.Context, taskID string) (verveclient.HeartbeatResponse, error)
+	CompleteTask(ctx context.Context, taskID string, req verveclient.TaskCompleteRequest) error
 }
 
 type Worker struct {
-	config       Config
-	docker       *DockerRunner
-	client       *http.Client
-	logger       log.Logger
-	pollInterval time.Duration
+	config      Config
+	runner      agentRunner
+	api         *verveclient.Client
+	agent       agentPlane
+	logger      log.Logger
+	pollBackoff *pollBackoff
 
 	// Unique identifier for this worker instance
 	workerID string
@@ -131,6 +118,7 @@ type Worker struct {
 	// Concurrency control
 	maxConcurrent int
 	semaphore     chan struct{}
+	taskDone      chan struct{} // Signalled when a slot frees up, waking an idle poll loop
 	wg            sync.WaitGroup
 	activeTasks   int
 	activeMu      sync.Mutex
@@ -138,12 +126,32 @@ type Worker struct {
 	// Running execution contexts for stop-signal cancellation
 	runningCtxsMu sync.Mutex
 	runningCtxs   map[string]context.CancelFunc // entityID → cancel
+	runningTasks  map[string]struct{}           // task IDs reported in worker heartbeats
+
+	// Tasks cut short by shutdown, handed back to the server on drain
+	abandonedMu sync.Mutex
+	abandoned   []string
 }
 
 func New(cfg Config, logger log.Logger) (*Worker, error) {
-	docker, err := NewDockerRunner(cfg.AgentImage, cfg.CacheEnabled, cfg.CacheDir, logger)
-	if err != nil {
-		return nil, err
+	var runner agentRunner
+	if cfg.Mode == ModeSim {
+		sim, err := NewSimRunner(cfg.SimScenario, logger)
+		if err != nil {
+			return nil, err
+		}
+		runner = sim
+	} else {
+		workspaceCache := WorkspaceCacheConfig{
+			Enabled:  cfg.WorkspaceCacheEnabled,
+			TTL:      cfg.WorkspaceCacheTTL,
+			MaxBytes: cfg.WorkspaceCacheMaxBytes,
+		}
+		docker, err := NewDockerRunner(cfg.AgentImage, cfg.CacheEnabled, cfg.CacheDir, workspaceCache, logger)
+		if err != nil {
+			return nil, err
+		}
+		runner = docker
 	}
 
 	// Default to 1 concurrent task if not specified
@@ -152,21 +160,74 @@ func New(cfg Config, logger log.Logger) (*Worker, error) {
 		maxConcurrent = 1
 	}
 
+	api := verveclient.NewClient(cfg.APIURL, verveclient.WithHTTPClient(&http.Client{Timeout: 60 * time.Second}))
+	var agent agentPlane = api
+	if cfg.GRPCAddr != "" {
+		opts, err := grpcDialOptions(cfg)
+		if err != nil {
+			_ = runner.Close()
+			return nil, err
+		}
+		grpcClient, err := verveclient.NewAgentGRPCClient(cfg.GRPCAddr, opts...)
+		if err != nil {
+			_ = runner.Close()
+			return nil, err
+		}
+		agent = grpcClient
+	}
+
 	return &Worker{
 		config:        cfg,
-		docker:        docker,
-		client:        &http.Client{Timeout: 60 * time.Second},
+		runner:        runner,
+		api:           api,
+		agent:         agent,
 		logger:        logger,
-		pollInterval:  5 * time.Second,
+		pollBackoff:   newPollBackoff(cfg.PollInterval, cfg.PollMaxInterval, cfg.PollJitter),
 		workerID:      uuid.New().String(),
 		maxConcurrent: maxConcurrent,
 		semaphore:     make(chan struct{}, maxConcurrent),
+		taskDone:      make(chan struct{}, 1),
 		runningCtxs:   make(map[string]context.CancelFunc),
+		runningTasks:  make(map[string]struct{}),
 	}, nil
 }
 
+// grpcDialOptions returns the options for dialing cfg.GRPCAddr. Polls hand
+// out GitHub tokens, so plaintext is refused unless the address is loopback
+// or cfg.GRPCInsecure opts in.
+func grpcDialOptions(cfg Config) ([]grpc.DialOption, error) {
+	if cfg.GRPCTLS || cfg.GRPCCACert != "" {
+		creds, err := verveclient.AgentGRPCTLS(cfg.GRPCCACert)
+		if err != nil {
+			return nil, err
+		}
+		return []grpc.DialOption{creds}, nil
+	}
+	if !cfg.GRPCInsecure && !isLoopbackAddr(cfg.GRPCAddr) {
+		return nil, fmt.Errorf("agent grpc address %s is not loopback: enable tls or explicitly allow insecure transport", cfg.GRPCAddr)
+	}
+	return nil, nil
+}
+
+// isLoopbackAddr reports whether the host of addr (host:port) is localhost
+// or a loopback IP.
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
 func (w *Worker) Close() error {
-	return w.docker.Close()
+	if c, ok := w.agent.(io.Closer); ok {
+		_ = c.Close()
+	}
+	return w.runner.Close()
 }
 
 func (w *Worker) trackRunning(id string, cancel context.CancelFunc) {
@@ -181,6 +242,28 @@ func (w *Worker) untrackRunning(id string) {
 	delete(w.runningCtxs, id)
 }
 
+func (w *Worker) trackTask(id string) {
+	w.runningCtxsMu.Lock()
+	defer w.runningCtxsMu.Unlock()
+	w.runningTasks[id] = struct{}{}
+}
+
+func (w *Worker) untrackTask(id string) {
+	w.runningCtxsMu.Lock()
+	defer w.runningCtxsMu.Unlock()
+	delete(w.runningTasks, id)
+}
+
+func (w *Worker) runningTaskIDs() []string {
+	w.runningCtxsMu.Lock()
+	defer w.runningCtxsMu.Unlock()
+	ids := make([]string, 0, len(w.runningTasks))
+	for id := range w.runningTasks {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
 func (w *Worker) cancelRunning(id, entityType string) {
 	w.runningCtxsMu.Lock()
 	cancel, ok := w.runningCtxs[id]
@@ -198,12 +281,23 @@ func (w *Worker) Run(ctx context.Context) error {
 	if !strings.HasPrefix(w.config.APIURL, "https://") {
 		w.logger.Warn("api url is not https, github tokens will be sent in plaintext", "worker.api_url", w.config.APIURL)
 	}
+	if w.config.GRPCAddr != "" && !w.config.GRPCTLS && w.config.GRPCCACert == "" {
+		w.logger.Warn("api grpc addr is not tls, github tokens will be sent in plaintext", "worker.api_grpc_addr", w.config.GRPCAddr)
+	}
 
 	// Ensure agent image exists
-	if err := w.docker.EnsureImage(ctx); err != nil {
+	if err := w.runner.EnsureImage(ctx); err != nil {
 		return err
 	}
-	w.logger.Info("agent image verified", "agent.image", w.docker.AgentImage())
+	w.logger.Info("agent image verified", "agent.image", w.runner.AgentImage())
+
+	// Evict workspace caches that went stale while the worker was down.
+	w.runner.PruneWorkspaceCaches(ctx)
+
+	// Register with the server and keep it informed of the tasks this worker
+	// is running, so they are released if the worker disappears.
+	w.register(ctx)
+	go w.heartbeatLoop(ctx)
 
 	// Start stop-poll goroutine to receive stop signals via dedicated poll channel.
 	go w.stopPollLoop(ctx)
@@ -213,149 +307,244 @@ func (w *Worker) Run(ctx context.Context) error {
 		case <-ctx.Done():
 			w.logger.Info("worker shutting down, waiting for active tasks")
 			w.wg.Wait()
+			w.drain()
 			w.logger.Info("all tasks completed, worker stopped")
 			return ctx.Err()
 		default:
 		}
 
-		// Try to acquire a semaphore slot (non-blocking check first)
+		// Wait for a free slot. One frees up as soon as a running task
+		// finishes, so polling resumes right away.
 		select {
 		case w.semaphore <- struct{}{}:
-			// Got a slot, proceed to poll
-		default:
-			// All slots full, wait a bit before checking again
-			time.Sleep(100 * time.Millisecond)
+		case <-ctx.Done():
 			continue
 		}
 
-		poll, err := w.poll(ctx)
-		if err != nil {
-			// Release slot on error
-			<-w.semaphore
-			w.logger.Error("error polling for work", "error", err)
-			time.Sleep(w.pollInterval)
-			continue
+		// Take every other free slot too, so one poll fills them all.
+		slots := 1 + w.acquireFreeSlots()
+
+		// Finishes signalled before this poll need no wake-up.
+		select {
+		case <-w.taskDone:
+		default:
 		}
 
-		if poll == nil {
-			// No work available, release slot and continue polling
+		work, err := w.poll(ctx, slots)
+		// Release the slots no work was handed out for
+		for range slots - len(work) {
 			<-w.semaphore
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			delay := w.pollBackoff.failed()
+			w.logger.Error("error polling for work", "error", err, "worker.poll_delay", delay)
+			sleepCtx(ctx, delay)
 			continue
 		}
 
-		// Track active count for logging
-		w.activeMu.Lock()
-		w.activeTasks++
-		activeCount := w.activeTasks
-		w.activeMu.Unlock()
-
-		// Dispatch based on work type
-		executeFunc := func(p *PollResponse) {
-			switch p.Type {
-			case workTypeEpic:
-				w.logger.Info("claimed epic",
-					"epic.id", p.Epic.ID,
-					"repo.full_name", p.RepoFullName,
-					"worker.active_tasks", activeCount,
-					"epic.title", p.Epic.Title,
-				)
-				w.executeEpicPlanning(ctx, p)
-			case workTypeConversation:
-				w.logger.Info("claimed conversation",
-					"conversation.id", p.Conversation.ID,
-					"repo.full_name", p.RepoFullName,
-					"worker.active_tasks", activeCount,
-					"conversation.title", p.Conversation.Title,
-				)
-				w.executeConversation(ctx, p)
-			case workTypeSetup, workTypeSetupReview:
-				w.logger.Info("claimed setup work",
-					"setup.task_id", p.Setup.TaskID,
-					"setup.repo_id", p.Setup.RepoID,
-					"setup.type", p.Type,
-					"repo.full_name", p.RepoFullName,
-					"worker.active_tasks", activeCount,
-				)
-				w.executeSetup(ctx, p)
-			default:
-				w.logger.Info("claimed task",
-					"task.id", p.Task.ID,
-					"repo.full_name", p.RepoFullName,
-					"worker.active_tasks", activeCount,
-					"worker.max_concurrent", w.maxConcurrent,
-					"task.description", p.Task.Description,
-				)
-				w.executeTask(ctx, p)
+		if len(work) == 0 {
+			// No work available, wait before polling again, or until a
+			// running task finishes.
+			if sleepWake(ctx, w.pollBackoff.noWork(), w.taskDone) {
+				w.pollBackoff.workReceived()
 			}
+			continue
 		}
 
-		if w.maxConcurrent > 1 {
-			w.wg.Add(1)
-			go func(p *PollResponse) {
-				defer w.wg.Done()
-				defer func() {
-					<-w.semaphore
-					w.activeMu.Lock()
-					w.activeTasks--
-					w.activeMu.Unlock()
-				}()
-				executeFunc(p)
-			}(poll)
-		} else {
-			executeFunc(poll)
-			<-w.semaphore
-			w.activeMu.Lock()
-			w.activeTasks--
-			w.activeMu.Unlock()
+		// Work received: poll again immediately once a slot frees up
+		w.pollBackoff.workReceived()
+		for _, p := range work {
+			w.dispatch(ctx, p)
 		}
 	}
 }
 
-func (w *Worker) poll(ctx context.Context) (*PollResponse, error) {
-	pollURL := w.config.APIURL + "/api/v1/agent/poll"
+// dispatch runs work claimed by a poll in the slot reserved for it, in the
+// background when the worker runs several tasks at once.
+func (w *Worker) dispatch(ctx context.Context, p *PollResponse) {
+	// Track active count for logging
+	w.activeMu.Lock()
+	w.activeTasks++
+	activeCount := w.activeTasks
+	w.activeMu.Unlock()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pollURL, http.NoBody)
-	if err != nil {
-		return nil, err
+	if w.maxConcurrent > 1 {
+		w.wg.Add(1)
+		go func() {
+			defer w.wg.Done()
+			defer w.releaseSlot()
+			w.execute(ctx, p, activeCount)
+		}()
+		return
 	}
+	w.execute(ctx, p, activeCount)
+	w.releaseSlot()
+}
 
-	// Send worker metadata as query parameters for server-side tracking
+// execute runs claimed work based on its type.
+func (w *Worker) execute(ctx context.Context, p *PollResponse, activeCount int) {
+	switch p.Type {
+	case workTypeEpic:
+		w.logger.Info("claimed epic",
+			"epic.id", p.Epic.ID,
+			"repo.full_name", p.RepoFullName,
+			"worker.active_tasks", activeCount,
+			"epic.title", p.Epic.Title,
+		)
+		w.executeEpicPlanning(ctx, p)
+	case workTypeConversation:
+		w.logger.Info("claimed conversation",
+			"conversation.id", p.Conversation.ID,
+			"repo.full_name", p.RepoFullName,
+			"worker.active_tasks", activeCount,
+			"conversation.title", p.Conversation.Title,
+		)
+		w.executeConversation(ctx, p)
+	case workTypeSetup, workTypeSetupReview:
+		w.logger.Info("claimed setup work",
+			"setup.task_id", p.Setup.TaskID,
+			"setup.repo_id", p.Setup.RepoID,
+			"setup.type", p.Type,
+			"repo.full_name", p.RepoFullName,
+			"worker.active_tasks", activeCount,
+		)
+		w.executeSetup(ctx, p)
+	default:
+		w.logger.Info("claimed task",
+			"task.id", p.Task.ID,
+			"repo.full_name", p.RepoFullName,
+			"worker.active_tasks", activeCount,
+			"worker.max_concurrent", w.maxConcurrent,
+			"task.description", p.Task.Description,
+		)
+		w.executeTask(ctx, p)
+	}
+}
+
+// releaseSlot frees the concurrency slot of finished work and wakes the poll
+// loop if it is idling.
+func (w *Worker) releaseSlot() {
+	<-w.semaphore
 	w.activeMu.Lock()
-	activeTasks := w.activeTasks
+	w.activeTasks--
 	w.activeMu.Unlock()
+	select {
+	case w.taskDone <- struct{}{}:
+	default:
+	}
+}
 
-	q := req.URL.Query()
-	q.Set("worker_id", w.workerID)
-	q.Set("max_concurrent", fmt.Sprintf("%d", w.maxConcurrent))
-	q.Set("active_tasks", fmt.Sprintf("%d", activeTasks))
-	req.URL.RawQuery = q.Encode()
+// abandon records a task cut short by shutdown so drain can hand it back.
+func (w *Worker) abandon(taskID string) {
+	w.abandonedMu.Lock()
+	defer w.abandonedMu.Unlock()
+	w.abandoned = append(w.abandoned, taskID)
+}
 
-	resp, err := w.client.Do(req)
+// drain tells the server the worker is shutting down and hands back the tasks
+// it abandoned, so they are rescheduled without waiting for the heartbeat
+// timeout. The run context is already cancelled, so drain uses its own.
+func (w *Worker) drain() {
+	w.abandonedMu.Lock()
+	taskIDs := w.abandoned
+	w.abandoned = nil
+	w.abandonedMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := w.api.Drain(ctx, verveclient.DrainRequest{WorkerID: w.workerID, TaskIDs: taskIDs})
 	if err != nil {
-		return nil, err
+		w.logger.Error("failed to drain worker", "error", err, "worker.abandoned_tasks", len(taskIDs))
+		return
 	}
-	defer func() { _ = resp.Body.Close() }()
+	w.logger.Info("worker drained", "worker.abandoned_tasks", len(taskIDs), "worker.rescheduled_tasks", len(res.Rescheduled))
+}
 
-	if resp.StatusCode == http.StatusNoContent {
-		return nil, nil
+// register announces the worker to the server. Failing to register isn't
+// fatal: the worker still polls for work, it just isn't tracked until a
+// heartbeat re-registers it.
+func (w *Worker) register(ctx context.Context) {
+	hostname, _ := os.Hostname()
+	err := w.api.RegisterWorker(ctx, verveclient.RegisterWorkerRequest{
+		WorkerID:           w.workerID,
+		Hostname:           hostname,
+		Version:            w.config.Version,
+		MaxConcurrentTasks: w.maxConcurrent,
+		Labels:             w.config.Labels,
+	})
+	if err != nil {
+		w.logger.Warn("failed to register worker", "error", err)
+		return
 	}
+	w.logger.Info("worker registered", "worker.id", w.workerID)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
+// heartbeatLoop reports the tasks the worker is running until ctx is done.
+// If the server no longer knows the worker, e.g. after a restart, the worker
+// registers again.
+func (w *Worker) heartbeatLoop(ctx context.Context) {
+	ticker := time.NewTicker(workerHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := w.api.WorkerHeartbeat(ctx, w.workerID, verveclient.WorkerHeartbeatRequest{ActiveTaskIDs: w.runningTaskIDs()})
+			switch {
+			case verveclient.IsNotFound(err):
+				w.register(ctx)
+			case err != nil && ctx.Err() == nil:
+				w.logger.Warn("failed to send worker heartbeat", "error", err)
+			}
+		}
+	}
+}
+
+// acquireFreeSlots takes every free concurrency slot without waiting and
+// returns how many it took.
+func (w *Worker) acquireFreeSlots() int {
+	n := 0
+	for {
+		select {
+		case w.semaphore <- struct{}{}:
+			n++
+		default:
+			return n
+		}
 	}
+}
 
-	var envelope struct {
-		Data PollResponse `json:"data"`
+// poll long-polls for work to fill slots. With more than one slot to fill the
+// server claims the work in one batch.
+func (w *Worker) poll(ctx context.Context, slots int) ([]*PollResponse, error) {
+	// Send worker metadata for server-side tracking
+	w.activeMu.Lock()
+	activeTasks := w.activeTasks
+	w.activeMu.Unlock()
+
+	opts := verveclient.PollOptions{
+		WorkerID:      w.workerID,
+		MaxConcurrent: w.maxConcurrent,
+		ActiveTasks:   activeTasks,
+		Labels:        w.config.Labels,
+	}
+	if slots > 1 {
+		return w.agent.PollBatch(ctx, opts, slots)
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+	p, err := w.agent.Poll(ctx, opts)
+	if err != nil || p == nil {
 		return nil, err
 	}
-
-	return &envelope.Data, nil
+	return []*PollResponse{p}, nil
 }
 
 func (w *Worker) stopPollLoop(ctx context.Context) {
+	backoff := newPollBackoff(w.config.PollInterval, w.config.PollMaxInterval, w.config.PollJitter)
 	for {
 		select {
 		case <-ctx.Done():
@@ -368,10 +557,12 @@ func (w *Worker) stopPollLoop(ctx context.Context) {
 			if ctx.Err() != nil {
 				return
 			}
-			w.logger.Error("error polling for stops", "error", err)
-			time.Sleep(5 * time.Second)
+			delay := backoff.failed()
+			w.logger.Error("error polling for stops", "error", err, "worker.poll_delay", delay)
+			sleepCtx(ctx, delay)
 			continue
 		}
+		backoff.workReceived()
 
 		for _, s := range stops {
 			w.cancelRunning(s.EntityID, s.EntityType)
@@ -380,49 +571,21 @@ func (w *Worker) stopPollLoop(ctx context.Context) {
 }
 
 func (w *Worker) pollForStops(ctx context.Context) ([]StopSignal, error) {
-	pollURL := w.config.APIURL + "/api/v1/agent/poll?accept=stop"
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pollURL, http.NoBody)
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := w.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode == http.StatusNoContent {
-		return nil, nil
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
-	}
-
-	var envelope struct {
-		Data struct {
-			Stops []StopSignal `json:"stops"`
-		} `json:"data"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
-		return nil, err
-	}
-
-	return envelope.Data.Stops, nil
+	return w.api.PollStops(ctx)
 }
 
-// logStreamer buffers log lines and periodically sends them to the API server
+// logStreamer buffers log lines and periodically sends them to the API server.
+// For tasks it also buffers the agent's structured events.
 type logStreamer struct {
 	worker         *Worker
 	taskID         string
 	epicID         string
 	conversationID string
 	attempt        int
+	taskLogs       verveclient.TaskLogWriter
 	ctx            context.Context
 	buffer         []string
+	events         []agentEvent
 	mu             sync.Mutex
 	done           chan struct{}
 	flushed        chan struct{}
@@ -435,6 +598,7 @@ func newLogStreamer(ctx context.Context, w *Worker, taskID string, attempt int)
 		worker:    w,
 		taskID:    taskID,
 		attempt:   attempt,
+		taskLogs:  w.agent.OpenTaskLogs(ctx, taskID, attempt),
 		ctx:       ctx,
 		buffer:    make([]string, 0, 100),
 		done:      make(chan struct{}),
@@ -491,6 +655,19 @@ func (ls *logStreamer) AddLine(line string) {
 	}
 }
 
+// AddEvent buffers a structured agent event (thread-safe). Events are only
+// recorded for tasks; they are ignored for epics and conversations. Like log
+// lines, sensitive data in the payload is redacted.
+func (ls *logStreamer) AddEvent(ev agentEvent) {
+	if ls.taskID == "" {
+		return
+	}
+	ev.Payload = redact.JSON(ev.Payload)
+	ls.mu.Lock()
+	ls.events = append(ls.events, ev)
+	ls.mu.Unlock()
+}
+
 // Stop signals the streamer to stop and waits for final flush
 func (ls *logStreamer) Stop() {
 	close(ls.done)
@@ -510,6 +687,11 @@ func (ls *logStreamer) flushLoop() {
 		case <-ls.done:
 			// Final flush
 			ls.flush()
+			if ls.taskLogs != nil {
+				if err := ls.taskLogs.Close(); err != nil {
+					ls.worker.logger.Error("failed to close task log stream", "task.id", ls.taskID, "error", err)
+				}
+			}
 			return
 		}
 	}
@@ -517,19 +699,23 @@ func (ls *logStreamer) flushLoop() {
 
 func (ls *logStreamer) flush() {
 	ls.mu.Lock()
+	events := ls.events
+	ls.events = nil
 	if len(ls.buffer) == 0 {
 		ls.mu.Unlock()
+		ls.flushEvents(events)
 		return
 	}
 	// Take ownership of the buffer
 	toSend := ls.buffer
 	ls.buffer = make([]string, 0, 100)
 	ls.mu.Unlock()
+	defer ls.flushEvents(events)
 
 	// Send to API server
 	switch {
 	case ls.taskID != "":
-		if err := ls.worker.sendLogs(ls.ctx, ls.taskID, ls.attempt, toSend); err != nil {
+		if err := ls.taskLogs.Write(toSend); err != nil {
 			ls.worker.logger.Error("failed to send logs", "task.id", ls.taskID, "error", err)
 		}
 	case ls.epicID != "":
@@ -543,6 +729,16 @@ func (ls *logStreamer) flush() {
 	}
 }
 
+// flushEvents sends buffered agent events after the logs flushed with them.
+func (ls *logStreamer) flushEvents(events []agentEvent) {
+	if len(events) == 0 {
+		return
+	}
+	if err := ls.worker.sendEvents(ls.ctx, ls.taskID, ls.attempt, events); err != nil {
+		ls.worker.logger.Error("failed to send agent events", "task.id", ls.taskID, "error", err)
+	}
+}
+
 func (w *Worker) executeTask(ctx context.Context, poll *PollResponse) {
 	task := poll.Task
 	githubToken := poll.GitHubToken
@@ -555,6 +751,7 @@ func (w *Worker) executeTask(ctx context.Context, poll *PollResponse) {
 	// Track PR info, branch info, and agent markers
 	var prURL string
 	var prNumber int
+	var additionalPRs []verveclient.CompletedPullRequest
 	var branchName string
 	var agentStatus string
 	var costUSD float64
@@ -562,91 +759,65 @@ func (w *Worker) executeTask(ctx context.Context, poll *PollResponse) {
 	var rateLimited bool
 	var transientError bool
 	var authError bool
+	var budgetExceeded bool
+	var meteredCostUSD float64
+	var stopOverBudget context.CancelFunc
 	var markerMu sync.Mutex
 
 	// Log callback - called from Docker log streaming goroutine
 	onLog := func(line string) {
 		taskLogger.Debug("agent output", "agent.line", line)
-		streamer.AddLine(line)
-
-		// Strip markdown formatting (e.g. **bold**) that the agent
-		// may wrap around marker lines.
-		cleanLine := strings.TrimRight(strings.TrimLeft(line, "*"), "*")
 
-		// Parse PR marker
-		if strings.HasPrefix(cleanLine, "VERVE_PR_CREATED:") {
-			jsonStr := strings.TrimPrefix(cleanLine, "VERVE_PR_CREATED:")
-			var prInfo struct {
-				URL    string `json:"url"`
-				Number int    `json:"number"`
-			}
-			if err := json.Unmarshal([]byte(jsonStr), &prInfo); err == nil {
-				markerMu.Lock()
-				prURL = prInfo.URL
-				prNumber = prInfo.Number
-				markerMu.Unlock()
-				taskLogger.Info("captured pr", "pr.url", prURL, "pr.number", prNumber)
-			}
-		}
-
-		// Parse PR updated marker (retry with existing PR)
-		if strings.HasPrefix(cleanLine, "VERVE_PR_UPDATED:") {
-			jsonStr := strings.TrimPrefix(cleanLine, "VERVE_PR_UPDATED:")
-			var prInfo struct {
-				URL    string `json:"url"`
-				Number int    `json:"number"`
-			}
-			if err := json.Unmarshal([]byte(jsonStr), &prInfo); err == nil {
-				markerMu.Lock()
-				prURL = prInfo.URL
-				prNumber = prInfo.Number
-				markerMu.Unlock()
-				taskLogger.Info("captured pr update", "pr.url", prURL, "pr.number", prNumber)
-			}
-		}
-
-		// Parse branch pushed marker (skip-PR mode)
-		if strings.HasPrefix(cleanLine, "VERVE_BRANCH_PUSHED:") {
-			jsonStr := strings.TrimPrefix(cleanLine, "VERVE_BRANCH_PUSHED:")
-			var branchInfo struct {
-				Branch string `json:"branch"`
-			}
-			if err := json.Unmarshal([]byte(jsonStr), &branchInfo); err == nil {
-				markerMu.Lock()
-				branchName = branchInfo.Branch
-				markerMu.Unlock()
+		ev, ok := parseAgentEvent(line)
+		if ok {
+			streamer.AddEvent(ev)
+			markerMu.Lock()
+			switch ev.Type {
+			case agentEventPRCreated, agentEventPRUpdated:
+				var pr prEventPayload
+				_ = json.Unmarshal(ev.Payload, &pr) // validated by parseAgentEvent
+				if pr.Repo != "" && !strings.EqualFold(pr.Repo, repoFullName) {
+					additionalPRs = append(additionalPRs, verveclient.CompletedPullRequest{Repo: pr.Repo, URL: pr.URL, Number: pr.Number})
+				} else {
+					prURL = pr.URL
+					prNumber = pr.Number
+				}
+				taskLogger.Info("captured pr", "pr.url", pr.URL, "pr.number", pr.Number, "repo.full_name", pr.Repo, "agent.event", ev.Type)
+			case agentEventBranchPushed:
+				var branch branchEventPayload
+				_ = json.Unmarshal(ev.Payload, &branch)
+				branchName = branch.Branch
 				taskLogger.Info("captured branch", "task.branch", branchName)
+			case agentEventStatus:
+				agentStatus = string(ev.Payload)
+				taskLogger.Info("captured agent status")
+			case agentEventNoChanges:
+				noChanges = true
+				taskLogger.Info("agent reported no changes needed")
+			case agentEventCost:
+				var cost costEventPayload
+				_ = json.Unmarshal(ev.Payload, &cost)
+				costUSD = cost.CostUSD
+				taskLogger.Info("captured cost", "task.cost_usd", cost.CostUSD)
+			case agentEventBudgetExceeded:
+				var budget budgetExceededEventPayload
+				_ = json.Unmarshal(ev.Payload, &budget)
+				meteredCostUSD = budget.CostUSD
+				if !budgetExceeded {
+					budgetExceeded = true
+					taskLogger.Warn("agent reached task budget, stopping", "task.cost_usd", budget.CostUSD, "task.budget_usd", budget.BudgetUSD)
+					stopOverBudget()
+				}
 			}
-		}
-
-		// Parse agent status marker
-		if strings.HasPrefix(cleanLine, "VERVE_STATUS:") {
-			statusJSON := strings.TrimPrefix(cleanLine, "VERVE_STATUS:")
-			markerMu.Lock()
-			agentStatus = statusJSON
 			markerMu.Unlock()
-			taskLogger.Info("captured agent status")
 		}
 
-		// Parse no-changes marker
-		if strings.HasPrefix(cleanLine, "VERVE_NO_CHANGES:") {
-			markerMu.Lock()
-			noChanges = true
-			markerMu.Unlock()
-			taskLogger.Info("agent reported no changes needed")
-		}
-
-		// Parse cost marker
-		if strings.HasPrefix(cleanLine, "VERVE_COST:") {
-			costStr := strings.TrimPrefix(cleanLine, "VERVE_COST:")
-			var cost float64
-			if _, err := fmt.Sscanf(costStr, "%f", &cost); err == nil {
-				markerMu.Lock()
-				costUSD = cost
-				markerMu.Unlock()
-				taskLogger.Info("captured cost", "task.cost_usd", cost)
-			}
+		// Structured event records are reported separately from the plain
+		// logs. Legacy marker lines stay in the logs as before.
+		if isAgentEventRecord(line) {
+			return
 		}
+		streamer.AddLine(line)
 
 		// Detect Claude rate limit or session max usage errors
 		if isRateLimitError(line) {
@@ -696,9 +867,19 @@ func (w *Worker) executeTask(ctx context.Context, poll *PollResponse) {
 		AcceptanceCriteria:        task.AcceptanceCriteria,
 		RetryContext:              task.RetryContext,
 		PreviousStatus:            task.AgentStatus,
+		EpicContext:               poll.EpicContext,
+		WorkspaceCacheGeneration:  poll.WorkspaceCacheGeneration,
 		RepoSummary:               poll.RepoSummary,
 		RepoExpectations:          poll.RepoExpectations,
 		RepoTechStack:             poll.RepoTechStack,
+		PRConventions:             poll.PRConventions,
+		AdditionalRepos:           poll.AdditionalRepos,
+		ShadowMode:                poll.ShadowMode,
+		PlanOnly:                  task.PlanOnly,
+		Proposal:                  poll.Proposal,
+		BaseBranch:                poll.BaseBranch,
+		Attachments:               w.downloadAttachments(ctx, task.ID, poll.Attachments, streamer),
+		CostBudgetUSD:             costBudget(task),
 	}
 
 	// Create a cancellable context for the agent execution.
@@ -707,14 +888,49 @@ func (w *Worker) executeTask(ctx context.Context, poll *PollResponse) {
 	defer cancelExec()
 	w.trackRunning(task.ID, cancelExec)
 	defer w.untrackRunning(task.ID)
+	w.trackTask(task.ID)
+	defer w.untrackTask(task.ID)
 
 	// Start heartbeat goroutine
 	heartbeatCtx, cancelHeartbeat := context.WithCancel(ctx)
 	defer cancelHeartbeat()
 	go w.taskHeartbeatLoop(heartbeatCtx, task.ID, cancelExec)
 
+	// Wait for a permit when the server limits agents across all workers.
+	// The wait doesn't count towards the task's max runtime.
+	if poll.PermitRequired {
+		releasePermit, err := w.acquirePermit(execCtx, task, streamer)
+		if err != nil {
+			streamer.Stop()
+			if ctx.Err() != nil {
+				taskLogger.Info("task abandoned while waiting for a permit, worker shutting down")
+				w.abandon(task.ID)
+				return
+			}
+			taskLogger.Info("task execution cancelled while waiting for a permit (stopped by user)")
+			return
+		}
+		defer releasePermit()
+	}
+
+	// Enforce the task's max runtime. Running out of time kills the agent
+	// container like a stop does, but the task is failed rather than
+	// returned to pending.
+	runCtx := execCtx
+	if poll.MaxRuntimeSeconds > 0 {
+		var cancelRun context.CancelFunc
+		runCtx, cancelRun = context.WithTimeout(execCtx, time.Duration(poll.MaxRuntimeSeconds)*time.Second)
+		defer cancelRun()
+	}
+
+	// The agent's API proxy reports when the attempt has spent the task's
+	// remaining budget. Stopping for it kills the container without looking
+	// like a user stop to the checks below.
+	runCtx, stopOverBudget = context.WithCancel(runCtx)
+	defer stopOverBudget()
+
 	// Run the agent with streaming logs
-	result := w.docker.RunAgent(execCtx, agentCfg, onLog)
+	result := w.runner.RunAgent(runCtx, agentCfg, onLog)
 
 	// Stop heartbeat before completing the task
 	cancelHeartbeat()
@@ -727,6 +943,17 @@ func (w *Worker) executeTask(ctx context.Context, poll *PollResponse) {
 		return
 	}
 
+	// If the worker is shutting down, hand the task back on drain instead
+	// of reporting a failure caused by the killed container.
+	if ctx.Err() != nil {
+		taskLogger.Info("task abandoned, worker shutting down")
+		streamer.Stop()
+		w.abandon(task.ID)
+		return
+	}
+
+	w.uploadArtifacts(ctx, task.ID, task.Attempt, result.Artifacts, streamer)
+
 	// Stop the streamer and flush remaining logs
 	streamer.Stop()
 
@@ -734,6 +961,7 @@ func (w *Worker) executeTask(ctx context.Context, poll *PollResponse) {
 	markerMu.Lock()
 	capturedPRURL := prURL
 	capturedPRNumber := prNumber
+	capturedAdditionalPRs := additionalPRs
 	capturedBranchName := branchName
 	capturedAgentStatus := agentStatus
 	capturedCostUSD := costUSD
@@ -741,14 +969,34 @@ func (w *Worker) executeTask(ctx context.Context, poll *PollResponse) {
 	capturedRateLimited := rateLimited
 	capturedTransientError := transientError
 	capturedAuthError := authError
+	capturedBudgetExceeded := budgetExceeded
+	capturedMeteredCostUSD := meteredCostUSD
 	markerMu.Unlock()
 
 	// Report completion with PR info, agent status, and cost
 	switch {
+	case capturedBudgetExceeded:
+		taskLogger.Error("task exceeded max cost", "task.max_cost_usd", task.MaxCostUSD)
+		_ = w.agent.CompleteTask(ctx, task.ID, verveclient.TaskCompleteRequest{
+			Attempt:        task.Attempt,
+			Error:          fmt.Sprintf("max cost of $%.2f reached", task.MaxCostUSD),
+			AgentStatus:    capturedAgentStatus,
+			CostUSD:        max(capturedCostUSD, capturedMeteredCostUSD),
+			BudgetExceeded: true,
+		})
+	case errors.Is(runCtx.Err(), context.DeadlineExceeded):
+		taskLogger.Error("task exceeded max runtime", "task.max_runtime_seconds", poll.MaxRuntimeSeconds)
+		_ = w.agent.CompleteTask(ctx, task.ID, verveclient.TaskCompleteRequest{
+			Attempt:            task.Attempt,
+			Error:              fmt.Sprintf("max runtime of %s exceeded", time.Duration(poll.MaxRuntimeSeconds)*time.Second),
+			AgentStatus:        capturedAgentStatus,
+			CostUSD:            capturedCostUSD,
+			MaxRuntimeExceeded: true,
+		})
 	case result.Error != nil:
 		retryable := capturedRateLimited || capturedTransientError || isDockerInfraError(result.Error)
 		taskLogger.Error("task failed", "error", result.Error, "task.retryable", retryable)
-		_ = w.completeTask(ctx, task.ID, false, result.Error.Error(), "", 0, "", capturedAgentStatus, capturedCostUSD, false, retryable)
+		_ = w.completeTask(ctx, task.ID, task.Attempt, false, result.Error.Error(), "", 0, nil, "", "", nil, capturedAgentStatus, capturedCostUSD, false, retryable)
 	case result.Success:
 		// Defense-in-depth: if the agent exited successfully but we detected
 		// authentication or rate-limit errors in the logs and no actual work
@@ -762,19 +1010,65 @@ func (w *Worker) executeTask(ctx context.Context, poll *PollResponse) {
 				errMsg = "agent completed with no changes due to authentication error (check API key)"
 			}
 			taskLogger.Error("task failed, no changes due to api error", "task.auth_error", capturedAuthError, "task.rate_limited", capturedRateLimited)
-			_ = w.completeTask(ctx, task.ID, false, errMsg, "", 0, "", capturedAgentStatus, capturedCostUSD, false, capturedRateLimited)
+			_ = w.completeTask(ctx, task.ID, task.Attempt, false, errMsg, "", 0, nil, "", "", nil, capturedAgentStatus, capturedCostUSD, false, capturedRateLimited)
 		case capturedNoChanges:
 			taskLogger.Info("task completed, no changes needed")
-			_ = w.completeTask(ctx, task.ID, true, "", capturedPRURL, capturedPRNumber, capturedBranchName, capturedAgentStatus, capturedCostUSD, capturedNoChanges, false)
+			_ = w.completeTask(ctx, task.ID, task.Attempt, true, "", capturedPRURL, capturedPRNumber, capturedAdditionalPRs, capturedBranchName, result.ShadowDiff, result.Proposal, capturedAgentStatus, capturedCostUSD, capturedNoChanges, false)
 		default:
 			taskLogger.Info("task completed successfully")
-			_ = w.completeTask(ctx, task.ID, true, "", capturedPRURL, capturedPRNumber, capturedBranchName, capturedAgentStatus, capturedCostUSD, capturedNoChanges, false)
+			_ = w.completeTask(ctx, task.ID, task.Attempt, true, "", capturedPRURL, capturedPRNumber, capturedAdditionalPRs, capturedBranchName, result.ShadowDiff, result.Proposal, capturedAgentStatus, capturedCostUSD, capturedNoChanges, false)
 		}
 	default:
 		errMsg := fmt.Sprintf("exit code %d", result.ExitCode)
 		retryable := capturedRateLimited || capturedTransientError
 		taskLogger.Error("task failed", "container.exit_code", result.ExitCode, "task.retryable", retryable)
-		_ = w.completeTask(ctx, task.ID, false, errMsg, "", 0, "", capturedAgentStatus, capturedCostUSD, false, retryable)
+		_ = w.completeTask(ctx, task.ID, task.Attempt, false, errMsg, "", 0, nil, "", "", nil, capturedAgentStatus, capturedCostUSD, false, retryable)
+	}
+}
+
+// costBudget returns the spend left in a task's budget for its next attempt,
+// or 0 when the task has no budget. An exhausted budget still leaves a cent
+// so the agent's proxy stops it at its first API call.
+func costBudget(t *Task) float64 {
+	if t.MaxCostUSD <= 0 {
+		return 0
+	}
+	return max(t.MaxCostUSD-t.CostUSD, 0.01)
+}
+
+// downloadAttachments fetches the contents of a task's attachments for the
+// agent's workspace. An attachment that can't be downloaded is skipped and
+// noted in the task's logs rather than failing the run.
+func (w *Worker) downloadAttachments(ctx context.Context, taskID string, attachments []verveclient.Attachment, streamer *logStreamer) []AttachmentFile {
+	files := make([]AttachmentFile, 0, len(attachments))
+	for _, a := range attachments {
+		data, err := w.api.DownloadAgentTaskAttachment(ctx, taskID, a.ID)
+		if err != nil {
+			w.logger.Warn("failed to download task attachment", "task.id", taskID, "attachment.id", a.ID, "error", err)
+			streamer.AddLine(fmt.Sprintf("[verve] Could not download attachment %s; continuing without it", a.Filename))
+			continue
+		}
+		files = append(files, AttachmentFile{Name: a.Filename, Data: data})
+	}
+	return files
+}
+
+// uploadArtifacts records the files an agent saved to its artifacts
+// directory against the attempt that produced them. An artifact that can't
+// be uploaded is skipped and noted in the task's logs rather than failing
+// the run.
+func (w *Worker) uploadArtifacts(ctx context.Context, taskID string, attempt int, files []ArtifactFile, streamer *logStreamer) {
+	saved := 0
+	for _, f := range files {
+		if _, err := w.api.UploadTaskArtifact(ctx, taskID, attempt, f.Name, f.Data); err != nil {
+			w.logger.Warn("failed to upload task artifact", "task.id", taskID, "artifact.name", f.Name, "error", err)
+			streamer.AddLine(fmt.Sprintf("[verve] Could not save artifact %s", f.Name))
+			continue
+		}
+		saved++
+	}
+	if saved > 0 {
+		streamer.AddLine(fmt.Sprintf("[verve] Saved %d artifact(s) for review", saved))
 	}
 }
 
@@ -788,10 +1082,13 @@ func (w *Worker) executeEpicPlanning(ctx context.Context, poll *PollResponse) {
 	// Create log streamer for real-time log streaming
 	streamer := newEpicLogStreamer(ctx, w, ep.ID)
 
-	var feedback string
+	var feedback, feedbackType string
 	if ep.Feedback != nil {
 		feedback = *ep.Feedback
 	}
+	if ep.FeedbackType != nil {
+		feedbackType = *ep.FeedbackType
+	}
 	var previousPlan string
 	if len(ep.ProposedTasks) > 0 {
 		previousPlan = string(ep.ProposedTasks)
@@ -804,7 +1101,9 @@ func (w *Worker) executeEpicPlanning(ctx context.Context, poll *PollResponse) {
 		EpicDescription:           ep.Description,
 		EpicPlanningPrompt:        ep.PlanningPrompt,
 		EpicFeedback:              feedback,
+		EpicFeedbackType:          feedbackType,
 		EpicPreviousPlan:          previousPlan,
+		EpicTranscript:            poll.EpicTranscript,
 		APIURL:                    w.config.APIURL,
 		GitHubToken:               githubToken,
 		GitHubRepo:                repoFullName,
@@ -830,13 +1129,28 @@ func (w *Worker) executeEpicPlanning(ctx context.Context, poll *PollResponse) {
 	defer cancelHeartbeat()
 	go w.epicHeartbeatLoop(heartbeatCtx, ep.ID, cancelExec)
 
-	// Log callback for epic planning
+	// Log callback for epic planning. Cost markers are reported as soon as
+	// they are seen so the server can enforce the epic's planning budget.
 	onLog := func(line string) {
 		epicLogger.Info("epic agent", "agent.line", line)
-		streamer.AddLine(line)
+		if !isAgentEventRecord(line) {
+			streamer.AddLine(line)
+		}
+
+		if ev, ok := parseAgentEvent(line); ok && ev.Type == agentEventCost {
+			var p costEventPayload
+			_ = json.Unmarshal(ev.Payload, &p) // validated by parseAgentEvent
+			if cost := p.CostUSD; cost > 0 {
+				epicLogger.Info("captured cost", "epic.cost_usd", cost)
+				if stopped := w.reportEpicCost(ctx, ep.ID, cost); stopped {
+					epicLogger.Info("epic planning budget exceeded, cancelling execution")
+					cancelExec()
+				}
+			}
+		}
 	}
 
-	result := w.docker.RunAgent(execCtx, agentCfg, onLog)
+	result := w.runner.RunAgent(execCtx, agentCfg, onLog)
 
 	// Stop heartbeat before completing
 	cancelHeartbeat()
@@ -875,7 +1189,9 @@ func (w *Worker) executeSetup(ctx context.Context, poll *PollResponse) {
 	// Log callback
 	onLog := func(line string) {
 		setupLogger.Debug("setup agent output", "agent.line", line)
-		streamer.AddLine(line)
+		if !isAgentEventRecord(line) {
+			streamer.AddLine(line)
+		}
 	}
 
 	// Determine work type: "setup" for initial scan, "setup-review" for AI review of user config
@@ -897,9 +1213,9 @@ func (w *Worker) executeSetup(ctx context.Context, poll *PollResponse) {
 		ClaudeModel:               "sonnet",
 		GitHubInsecureSkipVerify:  w.config.GitHubInsecureSkipVerify,
 		StripAnthropicBetaHeaders: w.config.StripAnthropicBetaHeaders,
-		RepoSummary:              poll.RepoSummary,
-		RepoExpectations:         poll.RepoExpectations,
-		RepoTechStack:            poll.RepoTechStack,
+		RepoSummary:               poll.RepoSummary,
+		RepoExpectations:          poll.RepoExpectations,
+		RepoTechStack:             poll.RepoTechStack,
 	}
 
 	// Start heartbeat goroutine using the setup heartbeat endpoint
@@ -907,7 +1223,7 @@ func (w *Worker) executeSetup(ctx context.Context, poll *PollResponse) {
 	defer cancelHeartbeat()
 	go w.setupHeartbeatLoop(heartbeatCtx, setup.RepoID)
 
-	result := w.docker.RunAgent(ctx, agentCfg, onLog)
+	result := w.runner.RunAgent(ctx, agentCfg, onLog)
 
 	// Stop heartbeat before completing
 	cancelHeartbeat()
@@ -918,16 +1234,16 @@ func (w *Worker) executeSetup(ctx context.Context, poll *PollResponse) {
 	switch {
 	case result.Error != nil:
 		setupLogger.Error("setup scan failed", "error", result.Error)
-		_ = w.completeTask(ctx, setup.TaskID, false, result.Error.Error(), "", 0, "", "", 0, false, false)
+		_ = w.completeTask(ctx, setup.TaskID, 0, false, result.Error.Error(), "", 0, nil, "", "", nil, "", 0, false, false)
 	case result.Success:
 		setupLogger.Info("setup scan completed successfully")
 		// The agent script calls POST /repos/:repo_id/setup-complete directly.
 		// Mark the underlying task as closed.
-		_ = w.completeTask(ctx, setup.TaskID, true, "", "", 0, "", "", 0, true, false)
+		_ = w.completeTask(ctx, setup.TaskID, 0, true, "", "", 0, nil, "", "", nil, "", 0, true, false)
 	default:
 		errMsg := fmt.Sprintf("exit code %d", result.ExitCode)
 		setupLogger.Error("setup scan failed", "container.exit_code", result.ExitCode)
-		_ = w.completeTask(ctx, setup.TaskID, false, errMsg, "", 0, "", "", 0, false, false)
+		_ = w.completeTask(ctx, setup.TaskID, 0, false, errMsg, "", 0, nil, "", "", nil, "", 0, false, false)
 	}
 }
 
@@ -975,10 +1291,12 @@ func (w *Worker) executeConversation(ctx context.Context, poll *PollResponse) {
 	// Log callback for conversation
 	onLog := func(line string) {
 		convLogger.Debug("conversation agent", "agent.line", line)
-		streamer.AddLine(line)
+		if !isAgentEventRecord(line) {
+			streamer.AddLine(line)
+		}
 	}
 
-	result := w.docker.RunAgent(ctx, agentCfg, onLog)
+	result := w.runner.RunAgent(ctx, agentCfg, onLog)
 
 	// Stop heartbeat before completing
 	cancelHeartbeat()
@@ -1018,70 +1336,19 @@ func (w *Worker) conversationHeartbeatLoop(ctx context.Context, conversationID s
 }
 
 func (w *Worker) sendConversationHeartbeat(ctx context.Context, conversationID string) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
-		w.config.APIURL+"/api/v1/agent/conversations/"+conversationID+"/heartbeat", http.NoBody)
-	if err != nil {
-		return err
-	}
-
-	resp, err := w.client.Do(req)
-	if err != nil {
-		return err
-	}
-	_ = resp.Body.Close()
-	return nil
+	return w.api.ConversationHeartbeat(ctx, conversationID)
 }
 
 func (w *Worker) completeConversation(ctx context.Context, conversationID string, success bool, response, errMsg string) error {
-	payload := map[string]interface{}{"success": success}
-	if response != "" {
-		payload["response"] = response
-	}
-	if errMsg != "" {
-		payload["error"] = errMsg
-	}
-	body, _ := json.Marshal(payload)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
-		w.config.APIURL+"/api/v1/agent/conversations/"+conversationID+"/complete", bytes.NewReader(body))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := w.client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
-	}
-	return nil
+	return w.api.CompleteConversation(ctx, conversationID, verveclient.ConversationCompleteRequest{
+		Success:  success,
+		Response: response,
+		Error:    errMsg,
+	})
 }
 
 func (w *Worker) sendConversationLogs(ctx context.Context, conversationID string, logs []string) error {
-	body, _ := json.Marshal(map[string]any{"lines": logs})
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
-		w.config.APIURL+"/api/v1/agent/conversations/"+conversationID+"/logs", bytes.NewReader(body))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := w.client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
-	}
-	return nil
+	return w.api.AppendConversationLogs(ctx, conversationID, logs)
 }
 
 func (w *Worker) setupHeartbeatLoop(ctx context.Context, repoID string) {
@@ -1101,68 +1368,49 @@ func (w *Worker) setupHeartbeatLoop(ctx context.Context, repoID string) {
 }
 
 func (w *Worker) sendSetupHeartbeat(ctx context.Context, repoID string) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
-		w.config.APIURL+"/api/v1/agent/repos/"+repoID+"/setup-heartbeat", http.NoBody)
-	if err != nil {
-		return err
-	}
-
-	resp, err := w.client.Do(req)
-	if err != nil {
-		return err
-	}
-	_ = resp.Body.Close()
-	return nil
+	return w.api.SetupHeartbeat(ctx, repoID)
 }
 
-func (w *Worker) sendLogs(ctx context.Context, taskID string, attempt int, logs []string) error {
-	body, _ := json.Marshal(map[string]any{"logs": logs, "attempt": attempt})
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.config.APIURL+"/api/v1/agent/tasks/"+taskID+"/logs", bytes.NewReader(body))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := w.client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
+// sendEvents reports structured agent events for a task attempt.
+func (w *Worker) sendEvents(ctx context.Context, taskID string, attempt int, events []agentEvent) error {
+	inputs := make([]verveclient.AgentEventInput, len(events))
+	for i, e := range events {
+		inputs[i] = verveclient.AgentEventInput(e)
 	}
-	return nil
+	return w.api.AppendTaskEvents(ctx, taskID, verveclient.TaskEventsRequest{Attempt: attempt, Events: inputs})
 }
 
 func (w *Worker) sendEpicLogs(ctx context.Context, epicID string, logs []string) error {
-	body, _ := json.Marshal(map[string]any{"lines": logs})
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.config.APIURL+"/api/v1/agent/epics/"+epicID+"/logs", bytes.NewReader(body))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := w.client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
-	}
-	return nil
+	return w.api.AppendEpicLogs(ctx, epicID, logs)
 }
 
 func (w *Worker) taskHeartbeatLoop(ctx context.Context, taskID string, cancelExecution context.CancelFunc) {
 	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
 
+	// Nudges are handed out by the server once, so keep the ones that could
+	// not be delivered yet (e.g. the container is still being created) and
+	// retry them on the next tick.
+	var pending []Nudge
+	beat := func() (stopped bool) {
+		stopped, nudges := w.sendTaskHeartbeat(ctx, taskID)
+		if stopped {
+			return true
+		}
+		pending = append(pending, nudges...)
+		if len(pending) > 0 {
+			if err := w.runner.DeliverNudges(ctx, taskID, pending); err != nil {
+				w.logger.Warn("failed to deliver nudges, will retry", "task.id", taskID, "error", err)
+				return false
+			}
+			w.logger.Info("delivered nudges", "task.id", taskID, "nudge.count", len(pending))
+			pending = nil
+		}
+		return false
+	}
+
 	// Send initial heartbeat immediately
-	if stopped := w.sendTaskHeartbeat(ctx, taskID); stopped {
+	if stopped := beat(); stopped {
 		w.logger.Info("task was stopped, cancelling execution", "task.id", taskID)
 		cancelExecution()
 		return
@@ -1173,7 +1421,7 @@ func (w *Worker) taskHeartbeatLoop(ctx context.Context, taskID string, cancelExe
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			if stopped := w.sendTaskHeartbeat(ctx, taskID); stopped {
+			if stopped := beat(); stopped {
 				w.logger.Info("task was stopped, cancelling execution", "task.id", taskID)
 				cancelExecution()
 				return
@@ -1182,30 +1430,12 @@ func (w *Worker) taskHeartbeatLoop(ctx context.Context, taskID string, cancelExe
 	}
 }
 
-func (w *Worker) sendTaskHeartbeat(ctx context.Context, taskID string) (stopped bool) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
-		w.config.APIURL+"/api/v1/agent/tasks/"+taskID+"/heartbeat", http.NoBody)
-	if err != nil {
-		return false
-	}
-
-	resp, err := w.client.Do(req)
+func (w *Worker) sendTaskHeartbeat(ctx context.Context, taskID string) (stopped bool, nudges []Nudge) {
+	res, err := w.agent.TaskHeartbeat(ctx, taskID)
 	if err != nil {
-		return false
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	// The server wraps responses in a {"data": ...} envelope.
-	// Parse the envelope to check if the task was stopped.
-	var result struct {
-		Data struct {
-			Stopped bool `json:"stopped"`
-		} `json:"data"`
+		return false, nil
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return false
-	}
-	return result.Data.Stopped
+	return res.Stopped, res.Nudges
 }
 
 func (w *Worker) epicHeartbeatLoop(ctx context.Context, epicID string, cancelExecution context.CancelFunc) {
@@ -1233,72 +1463,43 @@ func (w *Worker) epicHeartbeatLoop(ctx context.Context, epicID string, cancelExe
 }
 
 func (w *Worker) sendEpicHeartbeat(ctx context.Context, epicID string) (stopped bool) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
-		w.config.APIURL+"/api/v1/agent/epics/"+epicID+"/heartbeat", http.NoBody)
+	res, err := w.api.EpicHeartbeat(ctx, epicID)
 	if err != nil {
 		return false
 	}
+	return res.Stopped
+}
 
-	resp, err := w.client.Do(req)
+// reportEpicCost reports planning cost for an epic. Returns true if the
+// server stopped the planning session because the epic's budget was exceeded.
+func (w *Worker) reportEpicCost(ctx context.Context, epicID string, costUSD float64) (stopped bool) {
+	res, err := w.api.ReportEpicCost(ctx, epicID, costUSD)
 	if err != nil {
+		w.logger.Error("failed to report epic cost", "epic.id", epicID, "error", err)
 		return false
 	}
-	defer func() { _ = resp.Body.Close() }()
-
-	var result struct {
-		Data struct {
-			Stopped bool `json:"stopped"`
-		} `json:"data"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return false
-	}
-	return result.Data.Stopped
+	return res.Stopped
 }
 
-func (w *Worker) completeTask(ctx context.Context, taskID string, success bool, errMsg, prURL string, prNumber int, branchName, agentStatus string, costUSD float64, noChanges, retryable bool) error {
-	payload := map[string]interface{}{"success": success}
-	if errMsg != "" {
-		payload["error"] = errMsg
+func (w *Worker) completeTask(ctx context.Context, taskID string, attempt int, success bool, errMsg, prURL string, prNumber int, additionalPRs []verveclient.CompletedPullRequest, branchName, shadowDiff string, proposal *verveclient.Proposal, agentStatus string, costUSD float64, noChanges, retryable bool) error {
+	req := verveclient.TaskCompleteRequest{
+		Attempt:      attempt,
+		Success:      success,
+		Error:        errMsg,
+		BranchName:   branchName,
+		AgentStatus:  agentStatus,
+		CostUSD:      costUSD,
+		NoChanges:    noChanges,
+		Retryable:    retryable,
+		PullRequests: additionalPRs,
+		ShadowDiff:   shadowDiff,
+		Proposal:     proposal,
 	}
 	if prURL != "" {
-		payload["pull_request_url"] = prURL
-		payload["pr_number"] = prNumber
-	}
-	if branchName != "" {
-		payload["branch_name"] = branchName
-	}
-	if agentStatus != "" {
-		payload["agent_status"] = agentStatus
-	}
-	if costUSD > 0 {
-		payload["cost_usd"] = costUSD
-	}
-	if noChanges {
-		payload["no_changes"] = true
-	}
-	if retryable {
-		payload["retryable"] = true
-	}
-	body, _ := json.Marshal(payload)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.config.APIURL+"/api/v1/agent/tasks/"+taskID+"/complete", bytes.NewReader(body))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := w.client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
+		req.PullRequestURL = prURL
+		req.PRNumber = prNumber
 	}
-	return nil
+	return w.agent.CompleteTask(ctx, taskID, req)
 }
 
 // rateLimitPatterns are substrings in agent output that indicate Claude rate
@@ -1,6 +1,7 @@
 package worker
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,8 +10,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/joshjon/kit/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/pkg/verveclient"
 )
 
 func TestReadLines(t *testing.T) {
@@ -161,6 +165,28 @@ func TestConfig_CacheDefaultDir(t *testing.T) {
 	assert.NotContains(t, dir, "/tmp")
 }
 
+func TestGRPCDialOptions(t *testing.T) {
+	for _, addr := range []string{"localhost:7401", "127.0.0.1:7401", "[::1]:7401"} {
+		opts, err := grpcDialOptions(Config{GRPCAddr: addr})
+		require.NoError(t, err, addr)
+		assert.Empty(t, opts, "loopback %s stays plaintext", addr)
+	}
+
+	_, err := grpcDialOptions(Config{GRPCAddr: "server:7401"})
+	require.Error(t, err, "plaintext to a remote host must be refused")
+
+	opts, err := grpcDialOptions(Config{GRPCAddr: "server:7401", GRPCInsecure: true})
+	require.NoError(t, err)
+	assert.Empty(t, opts)
+
+	opts, err = grpcDialOptions(Config{GRPCAddr: "server:7401", GRPCTLS: true})
+	require.NoError(t, err)
+	assert.Len(t, opts, 1)
+
+	_, err = grpcDialOptions(Config{GRPCAddr: "server:7401", GRPCCACert: "/does/not/exist.pem"})
+	require.Error(t, err, "a missing CA bundle must fail")
+}
+
 func TestPollResponse(t *testing.T) {
 	resp := PollResponse{
 		Task: &Task{
@@ -318,6 +344,48 @@ func TestLogStreamer_BufferManagement(t *testing.T) {
 	ls.mu.Unlock()
 }
 
+// recordingAgent is an agentPlane that records the task log lines written
+// through it.
+type recordingAgent struct {
+	agentPlane
+	mu     sync.Mutex
+	lines  []string
+	closed bool
+}
+
+func (a *recordingAgent) OpenTaskLogs(context.Context, string, int) verveclient.TaskLogWriter {
+	return a
+}
+
+func (a *recordingAgent) Write(lines []string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.lines = append(a.lines, lines...)
+	return nil
+}
+
+func (a *recordingAgent) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.closed = true
+	return nil
+}
+
+func TestLogStreamer_WritesTaskLogsThroughAgentPlane(t *testing.T) {
+	agent := &recordingAgent{}
+	w := &Worker{agent: agent, logger: log.NewLogger(log.WithNop())}
+
+	ls := newLogStreamer(context.Background(), w, "tsk_1", 1)
+	ls.AddLine("line 1")
+	ls.AddLine("line 2")
+	ls.Stop()
+
+	agent.mu.Lock()
+	defer agent.mu.Unlock()
+	assert.Equal(t, []string{"line 1", "line 2"}, agent.lines)
+	assert.True(t, agent.closed, "expected the log stream to be closed on stop")
+}
+
 func TestRunResult(t *testing.T) {
 	success := RunResult{Success: true, ExitCode: 0}
 	assert.True(t, success.Success, "expected success")
@@ -473,3 +541,92 @@ func TestIsDockerInfraError(t *testing.T) {
 		})
 	}
 }
+
+func TestPollBackoff_Defaults(t *testing.T) {
+	b := newPollBackoff(0, 0, -time.Second)
+	assert.Equal(t, defaultPollInterval, b.interval)
+	assert.Equal(t, defaultPollMaxInterval, b.maxInterval)
+	assert.Equal(t, time.Duration(0), b.jitter)
+
+	b = newPollBackoff(time.Minute, time.Second, 0)
+	assert.Equal(t, time.Minute, b.maxInterval, "expected max interval to be at least the base interval")
+}
+
+func TestPollBackoff_ErrorsBackOffExponentially(t *testing.T) {
+	b := newPollBackoff(time.Second, 5*time.Second, 0)
+
+	assert.Equal(t, 1*time.Second, b.failed())
+	assert.Equal(t, 2*time.Second, b.failed())
+	assert.Equal(t, 4*time.Second, b.failed())
+	assert.Equal(t, 5*time.Second, b.failed(), "expected delay capped at max interval")
+	assert.Equal(t, 5*time.Second, b.failed())
+
+	assert.Equal(t, time.Duration(0), b.workReceived())
+	assert.Equal(t, 1*time.Second, b.failed(), "expected backoff reset after work received")
+}
+
+func TestPollBackoff_IdleBackoff(t *testing.T) {
+	b := newPollBackoff(time.Second, 4*time.Second, 0)
+
+	for i := 1; i < idlePollsBeforeBackoff; i++ {
+		assert.Equal(t, time.Duration(0), b.noWork(), "expected no delay before idle threshold (poll %d)", i)
+	}
+	assert.Equal(t, 1*time.Second, b.noWork())
+	assert.Equal(t, 2*time.Second, b.noWork())
+	assert.Equal(t, 4*time.Second, b.noWork())
+	assert.Equal(t, 4*time.Second, b.noWork(), "expected delay capped at max interval")
+
+	b.workReceived()
+	assert.Equal(t, time.Duration(0), b.noWork(), "expected idle backoff reset after work received")
+}
+
+func TestPollBackoff_NoWorkResetsErrors(t *testing.T) {
+	b := newPollBackoff(time.Second, time.Minute, 0)
+	b.failed()
+	b.failed()
+	b.noWork()
+	assert.Equal(t, 1*time.Second, b.failed(), "expected error backoff reset after successful poll")
+}
+
+func TestPollBackoff_Jitter(t *testing.T) {
+	b := newPollBackoff(time.Second, time.Minute, 500*time.Millisecond)
+
+	var gotN int64
+	b.randInt64N = func(n int64) int64 {
+		gotN = n
+		return n - 1
+	}
+	assert.Equal(t, time.Second+500*time.Millisecond-1, b.failed())
+	assert.Equal(t, int64(500*time.Millisecond), gotN)
+	assert.Equal(t, 500*time.Millisecond-1, b.noWork(), "expected jitter even before idle threshold")
+
+	// Real randomness stays within [d, d+jitter).
+	b = newPollBackoff(time.Second, time.Minute, 500*time.Millisecond)
+	for range 100 {
+		d := b.failed()
+		b.workReceived()
+		assert.GreaterOrEqual(t, d, time.Second)
+		assert.Less(t, d, time.Second+500*time.Millisecond)
+	}
+}
+
+func TestSleepCtx_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	sleepCtx(ctx, time.Minute)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestSleepWake(t *testing.T) {
+	wake := make(chan struct{}, 1)
+	wake <- struct{}{}
+
+	start := time.Now()
+	assert.True(t, sleepWake(context.Background(), time.Minute, wake))
+	assert.Less(t, time.Since(start), time.Second)
+
+	assert.False(t, sleepWake(context.Background(), time.Millisecond, wake), "expected timeout without a wake-up")
+	assert.False(t, sleepWake(context.Background(), 0, wake))
+}
@@ -0,0 +1,246 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	"github.com/joshjon/kit/log"
+)
+
+// containerWorkspaceCacheDir is the path inside the agent container where the
+// repo's workspace cache volume is mounted.
+const containerWorkspaceCacheDir = "/workspace-cache"
+
+// Docker volume naming and labels for per-repo workspace caches.
+const (
+	workspaceVolumePrefix    = "verve-workspace-"
+	workspaceLabel           = "verve.workspace"
+	workspaceRepoLabel       = "verve.workspace.repo"
+	workspaceGenerationLabel = "verve.workspace.generation"
+)
+
+// Defaults for workspace cache eviction.
+const (
+	DefaultWorkspaceCacheTTL      = 72 * time.Hour
+	DefaultWorkspaceCacheMaxBytes = 20 << 30 // 20 GiB
+)
+
+// WorkspaceCacheConfig controls the per-repo workspace cache volumes that keep
+// a repo's clone and dependency caches between task attempts.
+type WorkspaceCacheConfig struct {
+	Enabled  bool
+	TTL      time.Duration // Remove volumes not used for this long (0 disables)
+	MaxBytes int64         // Evict least recently used volumes above this total size (0 disables)
+}
+
+// workspaceCache manages one Docker volume per repo. Volumes are labelled
+// with the repo's workspace cache generation; a volume from an older
+// generation is discarded before it is reused, which is how invalidation
+// requests from the server take effect.
+type workspaceCache struct {
+	client *client.Client
+	cfg    WorkspaceCacheConfig
+	logger log.Logger
+
+	mu       sync.Mutex
+	lastUsed map[string]time.Time // volume name → last time a run released it
+	inUse    map[string]int       // volume name → number of runs mounting it
+}
+
+func newWorkspaceCache(cli *client.Client, cfg WorkspaceCacheConfig, logger log.Logger) *workspaceCache {
+	return &workspaceCache{
+		client:   cli,
+		cfg:      cfg,
+		logger:   logger,
+		lastUsed: make(map[string]time.Time),
+		inUse:    make(map[string]int),
+	}
+}
+
+// workspaceVolumeName returns the Docker volume name for a repo. GitHub owner
+// names cannot contain underscores, so the first one separates owner and repo.
+func workspaceVolumeName(repoFullName string) string {
+	var b strings.Builder
+	b.WriteString(workspaceVolumePrefix)
+	for _, r := range strings.ToLower(repoFullName) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			b.WriteRune(r)
+		case r == '/':
+			b.WriteRune('_')
+		default:
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
+
+// acquire returns the name of the repo's workspace volume, creating it if
+// needed, and marks it in use until release is called. A volume from an older
+// generation than requested is removed and recreated empty.
+func (w *workspaceCache) acquire(ctx context.Context, repoFullName string, generation int) (string, func(), error) {
+	name := workspaceVolumeName(repoFullName)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	existing, err := w.findVolume(ctx, name)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if existing != nil && volumeGeneration(existing) < generation {
+		if w.inUse[name] > 0 {
+			return "", nil, fmt.Errorf("workspace cache %s is invalidated but still in use", name)
+		}
+		if err := w.client.VolumeRemove(ctx, name, false); err != nil {
+			return "", nil, fmt.Errorf("remove invalidated workspace cache %s: %w", name, err)
+		}
+		w.logger.Info("workspace cache invalidated", "workspace.volume", name, "workspace.generation", generation)
+		delete(w.lastUsed, name)
+		existing = nil
+	}
+
+	if existing == nil {
+		if _, err := w.client.VolumeCreate(ctx, volume.CreateOptions{
+			Name: name,
+			Labels: map[string]string{
+				workspaceLabel:           "true",
+				workspaceRepoLabel:       repoFullName,
+				workspaceGenerationLabel: strconv.Itoa(generation),
+			},
+		}); err != nil {
+			return "", nil, fmt.Errorf("create workspace cache %s: %w", name, err)
+		}
+		w.logger.Info("workspace cache created", "workspace.volume", name)
+	}
+
+	w.inUse[name]++
+	release := func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		w.inUse[name]--
+		if w.inUse[name] <= 0 {
+			delete(w.inUse, name)
+		}
+		w.lastUsed[name] = time.Now()
+	}
+	return name, release, nil
+}
+
+func (w *workspaceCache) findVolume(ctx context.Context, name string) (*volume.Volume, error) {
+	res, err := w.client.VolumeList(ctx, volume.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", workspaceLabel), filters.Arg("name", name)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list workspace caches: %w", err)
+	}
+	// The name filter matches substrings, so look for the exact name.
+	for _, v := range res.Volumes {
+		if v.Name == name {
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+// prune removes workspace volumes that have not been used within the TTL,
+// then evicts the least recently used volumes until the total size is under
+// the configured cap. Volumes mounted by a running agent are never removed.
+func (w *workspaceCache) prune(ctx context.Context) {
+	if w.cfg.TTL <= 0 && w.cfg.MaxBytes <= 0 {
+		return
+	}
+
+	usage, err := w.client.DiskUsage(ctx, types.DiskUsageOptions{Types: []types.DiskUsageObject{types.VolumeObject}})
+	if err != nil {
+		w.logger.Warn("failed to read workspace cache disk usage", "error", err)
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var entries []workspaceCacheEntry
+	for _, v := range usage.Volumes {
+		if v.Labels[workspaceLabel] == "" {
+			continue
+		}
+		e := workspaceCacheEntry{name: v.Name, lastUsed: w.lastUsed[v.Name], inUse: w.inUse[v.Name] > 0}
+		if e.lastUsed.IsZero() {
+			// Not used since this worker started; fall back to creation time.
+			e.lastUsed, _ = time.Parse(time.RFC3339, v.CreatedAt)
+		}
+		if v.UsageData != nil && v.UsageData.Size > 0 {
+			e.size = v.UsageData.Size
+		}
+		entries = append(entries, e)
+	}
+
+	for _, name := range selectWorkspaceEvictions(entries, time.Now(), w.cfg.TTL, w.cfg.MaxBytes) {
+		if err := w.client.VolumeRemove(ctx, name, false); err != nil {
+			// Typically the volume is mounted by a container this worker
+			// does not track (e.g. another worker on the same host).
+			w.logger.Warn("failed to evict workspace cache", "workspace.volume", name, "error", err)
+			continue
+		}
+		delete(w.lastUsed, name)
+		w.logger.Info("workspace cache evicted", "workspace.volume", name)
+	}
+}
+
+type workspaceCacheEntry struct {
+	name     string
+	lastUsed time.Time
+	size     int64
+	inUse    bool
+}
+
+// selectWorkspaceEvictions returns the names of the volumes to remove: those
+// idle for longer than ttl, then the least recently used ones until the total
+// size is at most maxBytes. Volumes in use are skipped but count towards the
+// total.
+func selectWorkspaceEvictions(entries []workspaceCacheEntry, now time.Time, ttl time.Duration, maxBytes int64) []string {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].lastUsed.Before(entries[j].lastUsed) })
+
+	var evict []string
+	var kept []workspaceCacheEntry
+	var total int64
+	for _, e := range entries {
+		if !e.inUse && ttl > 0 && now.Sub(e.lastUsed) > ttl {
+			evict = append(evict, e.name)
+			continue
+		}
+		kept = append(kept, e)
+		total += e.size
+	}
+
+	if maxBytes <= 0 {
+		return evict
+	}
+	for _, e := range kept {
+		if total <= maxBytes {
+			break
+		}
+		if e.inUse {
+			continue
+		}
+		evict = append(evict, e.name)
+		total -= e.size
+	}
+	return evict
+}
+
+func volumeGeneration(v *volume.Volume) int {
+	n, _ := strconv.Atoi(v.Labels[workspaceGenerationLabel])
+	return n
+}
@@ -0,0 +1,58 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkspaceVolumeName(t *testing.T) {
+	tests := []struct {
+		repo string
+		want string
+	}{
+		{"owner/repo", "verve-workspace-owner_repo"},
+		{"Owner/My.Repo", "verve-workspace-owner_my.repo"},
+		{"owner/my_repo-2", "verve-workspace-owner_my_repo-2"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, workspaceVolumeName(tt.repo), tt.repo)
+	}
+}
+
+func TestSelectWorkspaceEvictions_TTL(t *testing.T) {
+	now := time.Now()
+	entries := []workspaceCacheEntry{
+		{name: "fresh", lastUsed: now.Add(-time.Hour)},
+		{name: "stale", lastUsed: now.Add(-48 * time.Hour)},
+		{name: "stale-in-use", lastUsed: now.Add(-48 * time.Hour), inUse: true},
+	}
+
+	evict := selectWorkspaceEvictions(entries, now, 24*time.Hour, 0)
+	assert.Equal(t, []string{"stale"}, evict)
+}
+
+func TestSelectWorkspaceEvictions_SizeCap(t *testing.T) {
+	now := time.Now()
+	entries := []workspaceCacheEntry{
+		{name: "newest", lastUsed: now.Add(-time.Minute), size: 400},
+		{name: "oldest", lastUsed: now.Add(-3 * time.Hour), size: 300, inUse: true},
+		{name: "older", lastUsed: now.Add(-2 * time.Hour), size: 300},
+		{name: "recent", lastUsed: now.Add(-time.Hour), size: 300},
+	}
+
+	// Total is 1300; the oldest volume is in use, so the next least recently
+	// used ones are evicted until the total fits.
+	evict := selectWorkspaceEvictions(entries, now, 0, 800)
+	assert.Equal(t, []string{"older", "recent"}, evict)
+}
+
+func TestSelectWorkspaceEvictions_Disabled(t *testing.T) {
+	now := time.Now()
+	entries := []workspaceCacheEntry{
+		{name: "a", lastUsed: now.Add(-1000 * time.Hour), size: 1 << 40},
+	}
+
+	assert.Empty(t, selectWorkspaceEvictions(entries, now, 0, 0))
+}
@@ -9,14 +9,27 @@ import (
 type WorkerInfo struct {
 	// WorkerID is a unique identifier for this worker instance.
 	WorkerID string `json:"worker_id"`
+	// Hostname is the host the worker runs on, as reported at registration.
+	Hostname string `json:"hostname,omitempty"`
+	// Version is the worker's build version, as reported at registration.
+	Version string `json:"version,omitempty"`
+	// Labels route tasks to the worker, as reported at registration. Tasks
+	// that require labels only go to workers that have all of them.
+	Labels []string `json:"labels,omitempty"`
 	// MaxConcurrentTasks is the maximum number of tasks this worker can run simultaneously.
 	MaxConcurrentTasks int `json:"max_concurrent_tasks"`
 	// ActiveTasks is the number of tasks currently being executed by this worker.
 	ActiveTasks int `json:"active_tasks"`
+	// ActiveTaskIDs are the tasks the worker reported running in its last
+	// heartbeat. Only registered workers report them.
+	ActiveTaskIDs []string `json:"active_task_ids,omitempty"`
 	// ConnectedAt is when this worker first connected.
 	ConnectedAt time.Time `json:"connected_at"`
 	// LastPollAt is when this worker last polled for work.
 	LastPollAt time.Time `json:"last_poll_at"`
+	// LastHeartbeatAt is when this worker last registered or sent a
+	// heartbeat. Nil for workers that only poll.
+	LastHeartbeatAt *time.Time `json:"last_heartbeat_at,omitempty"`
 	// UptimeMs is total uptime since the worker first connected.
 	UptimeMs int64 `json:"uptime_ms"`
 	// Polling indicates whether the worker is currently in a long-poll request.
@@ -65,6 +78,58 @@ func (r *Registry) RecordPollStart(workerID string, maxConcurrent, activeTasks i
 	entry.polling = true
 }
 
+// Registration is what a worker reports about itself when it registers.
+type Registration struct {
+	WorkerID           string
+	Hostname           string
+	Version            string
+	MaxConcurrentTasks int
+	Labels             []string
+}
+
+// Register adds a worker or refreshes its metadata. Registering counts as a
+// heartbeat.
+func (r *Registry) Register(reg Registration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	entry, exists := r.workers[reg.WorkerID]
+	if !exists {
+		entry = &workerEntry{
+			info: WorkerInfo{
+				WorkerID:    reg.WorkerID,
+				ConnectedAt: now,
+			},
+		}
+		r.workers[reg.WorkerID] = entry
+	}
+
+	entry.info.Hostname = reg.Hostname
+	entry.info.Version = reg.Version
+	entry.info.MaxConcurrentTasks = reg.MaxConcurrentTasks
+	entry.info.Labels = reg.Labels
+	entry.info.LastHeartbeatAt = &now
+}
+
+// Heartbeat records that a registered worker is alive and running the given
+// tasks. It returns false if the worker is not registered, e.g. because the
+// server restarted, in which case the worker should register again.
+func (r *Registry) Heartbeat(workerID string, activeTaskIDs []string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, exists := r.workers[workerID]
+	if !exists {
+		return false
+	}
+	now := time.Now()
+	entry.info.ActiveTaskIDs = activeTaskIDs
+	entry.info.ActiveTasks = len(activeTaskIDs)
+	entry.info.LastHeartbeatAt = &now
+	return true
+}
+
 // RecordPollEnd is called when a worker's long-poll request completes.
 func (r *Registry) RecordPollEnd(workerID string) {
 	r.mu.Lock()
@@ -75,8 +140,18 @@ func (r *Registry) RecordPollEnd(workerID string) {
 	}
 }
 
-// ListWorkers returns info about all workers that have polled recently.
-// Workers that haven't polled in the given staleness duration are pruned.
+// Remove drops a worker from the registry, e.g. when it drains on shutdown.
+func (r *Registry) Remove(workerID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.workers, workerID)
+}
+
+// ListWorkers returns info about all workers that have polled or sent a
+// heartbeat recently. Workers that haven't been seen in the given staleness
+// duration are pruned, except those still holding tasks, which are left for
+// RemoveStale so their tasks can be released.
 func (r *Registry) ListWorkers(staleness time.Duration) []WorkerInfo {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -86,8 +161,10 @@ func (r *Registry) ListWorkers(staleness time.Duration) []WorkerInfo {
 
 	result := make([]WorkerInfo, 0, len(r.workers))
 	for id, entry := range r.workers {
-		if entry.info.LastPollAt.Before(cutoff) {
-			delete(r.workers, id)
+		if entry.lastSeen().Before(cutoff) {
+			if len(entry.info.ActiveTaskIDs) == 0 {
+				delete(r.workers, id)
+			}
 			continue
 		}
 		info := entry.info
@@ -98,3 +175,29 @@ func (r *Registry) ListWorkers(staleness time.Duration) []WorkerInfo {
 
 	return result
 }
+
+// RemoveStale drops workers that haven't polled or sent a heartbeat in the
+// given staleness duration and returns them, so the tasks they reported as
+// running can be released.
+func (r *Registry) RemoveStale(staleness time.Duration) []WorkerInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cutoff := time.Now().Add(-staleness)
+	var removed []WorkerInfo
+	for id, entry := range r.workers {
+		if entry.lastSeen().Before(cutoff) {
+			delete(r.workers, id)
+			removed = append(removed, entry.info)
+		}
+	}
+	return removed
+}
+
+// lastSeen is the later of the worker's last poll and last heartbeat.
+func (e *workerEntry) lastSeen() time.Time {
+	if e.info.LastHeartbeatAt != nil && e.info.LastHeartbeatAt.After(e.info.LastPollAt) {
+		return *e.info.LastHeartbeatAt
+	}
+	return e.info.LastPollAt
+}
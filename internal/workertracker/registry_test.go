@@ -73,6 +73,19 @@ func TestRecordPollEnd(t *testing.T) {
 	})
 }
 
+func TestRemove(t *testing.T) {
+	r := New()
+	r.RecordPollStart("worker-1", 4, 0)
+	r.RecordPollStart("worker-2", 2, 0)
+
+	r.Remove("worker-1")
+	r.Remove("nonexistent")
+
+	workers := r.ListWorkers(time.Minute)
+	require.Len(t, workers, 1)
+	assert.Equal(t, "worker-2", workers[0].WorkerID)
+}
+
 func TestListWorkers(t *testing.T) {
 	t.Run("returns active workers", func(t *testing.T) {
 		r := New()
@@ -116,3 +129,76 @@ func TestListWorkers(t *testing.T) {
 		assert.Empty(t, workers)
 	})
 }
+
+func TestRegister(t *testing.T) {
+	r := New()
+	r.RecordPollStart("worker-1", 2, 0)
+	r.Register(Registration{WorkerID: "worker-1", Hostname: "host-a", Version: "v1.2.3", MaxConcurrentTasks: 4, Labels: []string{"gpu"}})
+
+	workers := r.ListWorkers(time.Minute)
+	require.Len(t, workers, 1)
+	assert.Equal(t, "host-a", workers[0].Hostname)
+	assert.Equal(t, "v1.2.3", workers[0].Version)
+	assert.Equal(t, 4, workers[0].MaxConcurrentTasks)
+	assert.Equal(t, []string{"gpu"}, workers[0].Labels)
+	assert.NotNil(t, workers[0].LastHeartbeatAt)
+	assert.True(t, workers[0].Polling, "registering should not reset poll state")
+}
+
+func TestHeartbeat(t *testing.T) {
+	t.Run("records active tasks", func(t *testing.T) {
+		r := New()
+		r.Register(Registration{WorkerID: "worker-1", MaxConcurrentTasks: 4})
+
+		ok := r.Heartbeat("worker-1", []string{"tsk_a", "tsk_b"})
+		require.True(t, ok)
+
+		workers := r.ListWorkers(time.Minute)
+		require.Len(t, workers, 1)
+		assert.Equal(t, []string{"tsk_a", "tsk_b"}, workers[0].ActiveTaskIDs)
+		assert.Equal(t, 2, workers[0].ActiveTasks)
+	})
+
+	t.Run("unregistered worker", func(t *testing.T) {
+		r := New()
+		assert.False(t, r.Heartbeat("nonexistent", nil))
+		assert.Empty(t, r.ListWorkers(time.Minute))
+	})
+
+	t.Run("keeps a worker that stopped polling alive", func(t *testing.T) {
+		r := New()
+		r.RecordPollStart("worker-1", 1, 0)
+		r.Register(Registration{WorkerID: "worker-1", MaxConcurrentTasks: 1})
+
+		r.mu.Lock()
+		r.workers["worker-1"].info.LastPollAt = time.Now().Add(-5 * time.Minute)
+		r.mu.Unlock()
+
+		assert.Len(t, r.ListWorkers(time.Minute), 1)
+	})
+}
+
+func TestRemoveStale(t *testing.T) {
+	r := New()
+	r.Register(Registration{WorkerID: "stale-worker", MaxConcurrentTasks: 2})
+	r.Heartbeat("stale-worker", []string{"tsk_a"})
+	r.Register(Registration{WorkerID: "live-worker", MaxConcurrentTasks: 2})
+
+	past := time.Now().Add(-5 * time.Minute)
+	r.mu.Lock()
+	r.workers["stale-worker"].info.LastHeartbeatAt = &past
+	r.mu.Unlock()
+
+	// Listing hides the stale worker but keeps it while it holds tasks.
+	workers := r.ListWorkers(time.Minute)
+	require.Len(t, workers, 1)
+	assert.Equal(t, "live-worker", workers[0].WorkerID)
+
+	removed := r.RemoveStale(time.Minute)
+	require.Len(t, removed, 1)
+	assert.Equal(t, "stale-worker", removed[0].WorkerID)
+	assert.Equal(t, []string{"tsk_a"}, removed[0].ActiveTaskIDs)
+
+	assert.Empty(t, r.RemoveStale(time.Minute))
+	assert.Len(t, r.ListWorkers(time.Minute), 1)
+}
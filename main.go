@@ -16,8 +16,15 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/vervesh/verve/internal/app"
+	"github.com/vervesh/verve/internal/cliconfig"
+	"github.com/vervesh/verve/internal/costguard"
+	"github.com/vervesh/verve/internal/github"
 	"github.com/vervesh/verve/internal/keymanager"
+	"github.com/vervesh/verve/internal/permit"
+	"github.com/vervesh/verve/internal/provenance"
+	"github.com/vervesh/verve/internal/selfreview"
 	"github.com/vervesh/verve/internal/setting"
+	"github.com/vervesh/verve/internal/task"
 	"github.com/vervesh/verve/internal/worker"
 )
 
@@ -41,6 +48,26 @@ func main() {
 		},
 	}
 
+	sqliteDirFlag := &cli.StringFlag{
+		Name:    "sqlite-dir",
+		EnvVars: []string{"SQLITE_DIR"},
+	}
+
+	databaseFlags := []cli.Flag{
+		sqliteDirFlag,
+		&cli.StringFlag{
+			Name:    "turso-dsn",
+			EnvVars: []string{"TURSO_DSN"},
+			Usage:   "Turso/libSQL database URL (e.g. libsql://db-name.turso.io?authToken=...)",
+		},
+		&cli.DurationFlag{
+			Name:    "migrate-lock-timeout",
+			EnvVars: []string{"MIGRATE_LOCK_TIMEOUT"},
+			Usage:   "How long a migration waits for the database write lock before failing (file-backed SQLite only)",
+			Value:   10 * time.Second,
+		},
+	}
+
 	apiFlags := []cli.Flag{
 		&cli.IntFlag{
 			Name:    "port",
@@ -53,19 +80,86 @@ func main() {
 			EnvVars: []string{"ENCRYPTION_KEY"},
 		},
 		&cli.StringFlag{
-			Name:    "ui",
-			EnvVars: []string{"UI"},
-			Usage:   "Enable embedded UI (true/false/auto). Auto enables UI in combined mode.",
-			Value:   "auto",
+			Name:    "previous-encryption-keys",
+			EnvVars: []string{"PREVIOUS_ENCRYPTION_KEYS"},
+			Usage:   "Comma-separated hex keys ENCRYPTION_KEY was rotated from, used to read secrets written under them",
 		},
 		&cli.StringFlag{
-			Name:    "sqlite-dir",
-			EnvVars: []string{"SQLITE_DIR"},
+			Name:    "key-provider",
+			EnvVars: []string{"KEY_PROVIDER"},
+			Usage:   "Provider wrapping the keys secrets are encrypted with (env, awskms, gcpkms or vault)",
+			Value:   "env",
 		},
 		&cli.StringFlag{
-			Name:    "turso-dsn",
-			EnvVars: []string{"TURSO_DSN"},
-			Usage:   "Turso/libSQL database URL (e.g. libsql://db-name.turso.io?authToken=...)",
+			Name:    "key-provider-key-id",
+			EnvVars: []string{"KEY_PROVIDER_KEY_ID"},
+			Usage:   "AWS KMS key ID or ARN, GCP KMS CryptoKey resource name, or Vault transit key name",
+		},
+		&cli.StringFlag{
+			Name:    "aws-region",
+			EnvVars: []string{"AWS_REGION"},
+		},
+		&cli.StringFlag{
+			Name:    "aws-access-key-id",
+			EnvVars: []string{"AWS_ACCESS_KEY_ID"},
+		},
+		&cli.StringFlag{
+			Name:    "aws-secret-access-key",
+			EnvVars: []string{"AWS_SECRET_ACCESS_KEY"},
+		},
+		&cli.StringFlag{
+			Name:    "aws-session-token",
+			EnvVars: []string{"AWS_SESSION_TOKEN"},
+		},
+		&cli.StringFlag{
+			Name:    "gcp-access-token",
+			EnvVars: []string{"GCP_ACCESS_TOKEN"},
+			Usage:   "OAuth access token for GCP KMS; if empty, fetched from the metadata server",
+		},
+		&cli.StringFlag{
+			Name:    "vault-addr",
+			EnvVars: []string{"VAULT_ADDR"},
+		},
+		&cli.StringFlag{
+			Name:    "vault-token",
+			EnvVars: []string{"VAULT_TOKEN"},
+		},
+		&cli.StringFlag{
+			Name:    "vault-transit-mount",
+			EnvVars: []string{"VAULT_TRANSIT_MOUNT"},
+			Value:   "transit",
+		},
+		&cli.StringFlag{
+			Name:    "secrets-backend",
+			EnvVars: []string{"SECRETS_BACKEND"},
+			Usage:   "Secrets manager the encryption key and GitHub token can be read from (vault, awssecretsmanager or gcpsecretmanager)",
+		},
+		&cli.StringFlag{
+			Name:    "vault-kv-mount",
+			EnvVars: []string{"VAULT_KV_MOUNT"},
+			Value:   "secret",
+		},
+		&cli.StringFlag{
+			Name:    "encryption-key-secret",
+			EnvVars: []string{"ENCRYPTION_KEY_SECRET"},
+			Usage:   "Secret holding the hex encryption key, followed by any keys it was rotated from (comma-separated)",
+		},
+		&cli.StringFlag{
+			Name:    "github-token-secret",
+			EnvVars: []string{"GITHUB_TOKEN_SECRET"},
+			Usage:   "Secret holding the GitHub token, read instead of the token saved in settings",
+		},
+		&cli.DurationFlag{
+			Name:    "github-token-secret-ttl",
+			EnvVars: []string{"GITHUB_TOKEN_SECRET_TTL"},
+			Usage:   "How long the GitHub token read from the secrets manager is cached before it is read again",
+			Value:   5 * time.Minute,
+		},
+		&cli.StringFlag{
+			Name:    "ui",
+			EnvVars: []string{"UI"},
+			Usage:   "Enable embedded UI (true/false/auto). Auto enables UI in combined mode.",
+			Value:   "auto",
 		},
 		&cli.StringFlag{
 			Name:    "cors-origins",
@@ -81,10 +175,251 @@ func main() {
 			Name:    "log-retention",
 			EnvVars: []string{"LOG_RETENTION"},
 		},
+		&cli.DurationFlag{
+			Name:    "log-batch-interval",
+			EnvVars: []string{"LOG_BATCH_INTERVAL"},
+			Usage:   "How long task log appends wait to be combined into one database insert (0 writes each on its own)",
+			Value:   50 * time.Millisecond,
+		},
+		&cli.DurationFlag{
+			Name:    "task-trash-retention",
+			EnvVars: []string{"TASK_TRASH_RETENTION"},
+			Usage:   "How long deleted tasks can be restored from the trash before they are purged (0 deletes them right away)",
+		},
+		&cli.DurationFlag{
+			Name:    "task-archive-after",
+			EnvVars: []string{"TASK_ARCHIVE_AFTER"},
+			Usage:   "How long tasks stay merged or closed before they and their logs are archived (0 never archives)",
+		},
+		&cli.DurationFlag{
+			Name:    "sync-interval",
+			EnvVars: []string{"SYNC_INTERVAL"},
+			Usage:   "How often PR status is synced from GitHub",
+			Value:   30 * time.Second,
+		},
+		&cli.StringFlag{
+			Name:    "merge-method",
+			EnvVars: []string{"MERGE_METHOD"},
+			Usage:   "How task pull requests merged from Verve are merged on GitHub: merge, squash or rebase",
+			Value:   github.MergeMethodSquash,
+		},
+		&cli.DurationFlag{
+			Name:    "reap-interval",
+			EnvVars: []string{"REAP_INTERVAL"},
+			Usage:   "How often stale tasks, epics and conversations are timed out",
+			Value:   1 * time.Minute,
+		},
+		&cli.StringFlag{
+			Name:    "admin-token",
+			EnvVars: []string{"ADMIN_TOKEN"},
+			Usage:   "Bearer token for the /api/v1/admin endpoints (disabled when empty)",
+		},
+		&cli.BoolFlag{
+			Name:    "require-auth",
+			EnvVars: []string{"REQUIRE_AUTH"},
+			Usage:   "Require a user API token with a sufficient role on the task, epic, repo, settings and other UI endpoints",
+		},
+		&cli.DurationFlag{
+			Name:    "session-ttl",
+			EnvVars: []string{"SESSION_TTL"},
+			Usage:   "How long a single sign-on session lasts before the user signs in again",
+			Value:   12 * time.Hour,
+		},
+		&cli.StringFlag{
+			Name:    "mcp-token",
+			EnvVars: []string{"MCP_TOKEN"},
+			Usage:   "Bearer token for the /api/v1/mcp MCP server endpoints (disabled when empty)",
+		},
+		&cli.StringFlag{
+			Name:    "mcp-repos",
+			EnvVars: []string{"MCP_REPOS"},
+			Usage:   "Comma-separated repo full names (owner/name) MCP clients may access (default: all repos)",
+		},
+		&cli.TimestampFlag{
+			Name:    "api-v1-sunset",
+			EnvVars: []string{"API_V1_SUNSET"},
+			Usage:   "Date (YYYY-MM-DD) announced in the Sunset header of deprecated /api/v1 task endpoints",
+			Layout:  "2006-01-02",
+		},
+		&cli.BoolFlag{
+			Name:    "auto-migrate",
+			EnvVars: []string{"AUTO_MIGRATE"},
+			Usage:   "Apply pending database migrations on startup; when disabled, startup fails if migrations are pending",
+			Value:   true,
+		},
+		&cli.DurationFlag{
+			Name:    "sqlite-checkpoint-interval",
+			EnvVars: []string{"SQLITE_CHECKPOINT_INTERVAL"},
+			Usage:   "How often the SQLite write-ahead log is checkpointed and truncated (file-backed SQLite only, 0 disables)",
+			Value:   5 * time.Minute,
+		},
+		&cli.StringFlag{
+			Name:    "event-bus",
+			EnvVars: []string{"EVENT_BUS"},
+			Usage:   "How task events reach the other API replicas: memory (they don't) or database (through the shared database)",
+			Value:   "memory",
+		},
+		&cli.StringFlag{
+			Name:    "backup-dir",
+			EnvVars: []string{"BACKUP_DIR"},
+			Usage:   "Directory scheduled database backups are written to (file-backed SQLite only, empty disables)",
+		},
+		&cli.DurationFlag{
+			Name:    "backup-interval",
+			EnvVars: []string{"BACKUP_INTERVAL"},
+			Usage:   "How often a scheduled database backup is taken",
+			Value:   24 * time.Hour,
+		},
+		&cli.IntFlag{
+			Name:    "backup-keep",
+			EnvVars: []string{"BACKUP_KEEP"},
+			Usage:   "Scheduled backups kept before the oldest are removed (negative keeps all)",
+			Value:   7,
+		},
+		&cli.StringFlag{
+			Name:    "attachment-storage",
+			EnvVars: []string{"ATTACHMENT_STORAGE"},
+			Usage:   "Where task attachment contents are stored: dir or s3",
+			Value:   "dir",
+		},
+		&cli.StringFlag{
+			Name:    "attachment-dir",
+			EnvVars: []string{"ATTACHMENT_DIR"},
+			Usage:   "Directory for dir attachment storage (default: <sqlite-dir>/attachments; attachments are disabled without either)",
+		},
+		&cli.StringFlag{
+			Name:    "attachment-s3-bucket",
+			EnvVars: []string{"ATTACHMENT_S3_BUCKET"},
+			Usage:   "Bucket for s3 attachment storage; uses the AWS_* region and credentials",
+		},
+		&cli.StringFlag{
+			Name:    "attachment-s3-prefix",
+			EnvVars: []string{"ATTACHMENT_S3_PREFIX"},
+			Usage:   "Key prefix for attachments in the S3 bucket",
+		},
+		&cli.StringFlag{
+			Name:    "attachment-s3-endpoint",
+			EnvVars: []string{"ATTACHMENT_S3_ENDPOINT"},
+			Usage:   "Endpoint of an S3-compatible service such as MinIO (empty uses AWS S3)",
+		},
+		&cli.Int64Flag{
+			Name:    "attachment-max-size",
+			EnvVars: []string{"ATTACHMENT_MAX_SIZE"},
+			Usage:   "Largest task attachment accepted, in bytes",
+			Value:   10 << 20,
+		},
+		&cli.DurationFlag{
+			Name:    "artifact-retention",
+			EnvVars: []string{"ARTIFACT_RETENTION"},
+			Usage:   "How long to keep files agents save for review before deleting them (0 keeps them until their task's logs are deleted)",
+		},
+		&cli.BoolFlag{
+			Name:    "provenance-scan",
+			EnvVars: []string{"PROVENANCE_SCAN"},
+			Usage:   "Scan agent PR diffs for license headers and large verbatim code blocks; flagged PRs need acknowledgment before merge",
+		},
+		&cli.StringFlag{
+			Name:    "provenance-scanner",
+			EnvVars: []string{"PROVENANCE_SCANNER"},
+			Usage:   "External scanner command run with the PR diff on stdin; it must print a JSON array of findings",
+		},
+		&cli.IntFlag{
+			Name:    "provenance-block-lines",
+			EnvVars: []string{"PROVENANCE_BLOCK_LINES"},
+			Usage:   "Contiguous added lines in a PR flagged as a large verbatim block (negative disables)",
+			Value:   provenance.DefaultLargeBlockLines,
+		},
+		&cli.BoolFlag{
+			Name:    "self-review",
+			EnvVars: []string{"SELF_REVIEW"},
+			Usage:   "Review agent PRs for acceptance criteria coverage, TODO and debugging leftovers and diff size, posting the report as a PR comment",
+		},
+		&cli.IntFlag{
+			Name:    "self-review-max-files",
+			EnvVars: []string{"SELF_REVIEW_MAX_FILES"},
+			Usage:   "Files a PR may change before self-review flags it (negative disables)",
+			Value:   selfreview.DefaultMaxFiles,
+		},
+		&cli.IntFlag{
+			Name:    "self-review-max-file-lines",
+			EnvVars: []string{"SELF_REVIEW_MAX_FILE_LINES"},
+			Usage:   "Changed lines in a single file before self-review flags it (negative disables)",
+			Value:   selfreview.DefaultMaxFileLines,
+		},
+		&cli.IntFlag{
+			Name:    "self-review-max-lines",
+			EnvVars: []string{"SELF_REVIEW_MAX_LINES"},
+			Usage:   "Changed lines in a PR before self-review flags it (negative disables)",
+			Value:   selfreview.DefaultMaxLines,
+		},
+		&cli.BoolFlag{
+			Name:    "cost-anomaly-detection",
+			EnvVars: []string{"COST_ANOMALY_DETECTION"},
+			Usage:   "Watch for tasks costing far more than their repo's p95 and instance-wide hourly spend spikes, alerting notification sinks",
+		},
+		&cli.Float64Flag{
+			Name:    "cost-anomaly-task-factor",
+			EnvVars: []string{"COST_ANOMALY_TASK_FACTOR"},
+			Usage:   "Multiple of a repo's p95 task cost that flags an unfinished task",
+			Value:   costguard.DefaultTaskFactor,
+		},
+		&cli.Float64Flag{
+			Name:    "cost-anomaly-spike-factor",
+			EnvVars: []string{"COST_ANOMALY_SPIKE_FACTOR"},
+			Usage:   "Multiple of the trailing 7-day average hourly spend that flags the last hour's spend",
+			Value:   costguard.DefaultSpikeFactor,
+		},
+		&cli.Float64Flag{
+			Name:    "cost-anomaly-min-hourly-usd",
+			EnvVars: []string{"COST_ANOMALY_MIN_HOURLY_USD"},
+			Usage:   "Hourly spend in USD below which spikes are ignored",
+			Value:   costguard.DefaultMinHourlySpendUSD,
+		},
+		&cli.BoolFlag{
+			Name:    "cost-anomaly-auto-pause",
+			EnvVars: []string{"COST_ANOMALY_AUTO_PAUSE"},
+			Usage:   "Pause dispatching new work when a cost anomaly is detected, until acknowledged via the admin API (requires ADMIN_TOKEN)",
+		},
+		&cli.IntFlag{
+			Name:    "agent-max-concurrent",
+			EnvVars: []string{"AGENT_MAX_CONCURRENT"},
+			Usage:   "Task agents allowed to run at once across all workers; workers wait for a permit before starting one (0 = unlimited)",
+		},
+		&cli.IntFlag{
+			Name:    "agent-tokens-per-minute",
+			EnvVars: []string{"AGENT_TOKENS_PER_MINUTE"},
+			Usage:   "Tokens task agents may be granted per minute across all workers; each run draws AGENT_RUN_TOKENS (0 = unlimited)",
+		},
+		&cli.IntFlag{
+			Name:    "agent-run-tokens",
+			EnvVars: []string{"AGENT_RUN_TOKENS"},
+			Usage:   "Tokens a task agent run is expected to use, drawn from the tokens-per-minute limits when it starts",
+			Value:   permit.DefaultRunTokens,
+		},
+		&cli.StringFlag{
+			Name:    "agent-model-limits",
+			EnvVars: []string{"AGENT_MODEL_LIMITS"},
+			Usage:   "Per-model task agent limits as comma-separated MODEL:MAX_CONCURRENT[:TOKENS_PER_MINUTE] entries (e.g. opus:2:400000,sonnet:8)",
+		},
 		&cli.StringFlag{
 			Name:    "claude-models",
 			EnvVars: []string{"CLAUDE_MODELS"},
 		},
+		&cli.IntFlag{
+			Name:    "agent-grpc-port",
+			EnvVars: []string{"AGENT_GRPC_PORT"},
+			Usage:   "Port to serve the agent plane's gRPC service on, for workers started with API_GRPC_ADDR (0 disables)",
+		},
+		&cli.StringFlag{
+			Name:    "agent-grpc-tls-cert",
+			EnvVars: []string{"AGENT_GRPC_TLS_CERT"},
+			Usage:   "PEM certificate file the agent gRPC service presents; with AGENT_GRPC_TLS_KEY serves it over TLS",
+		},
+		&cli.StringFlag{
+			Name:    "agent-grpc-tls-key",
+			EnvVars: []string{"AGENT_GRPC_TLS_KEY"},
+			Usage:   "PEM private key file of AGENT_GRPC_TLS_CERT",
+		},
 	}
 
 	workerFlags := []cli.Flag{
@@ -93,6 +428,26 @@ func main() {
 			EnvVars: []string{"API_URL"},
 			Value:   "http://localhost:7400",
 		},
+		&cli.StringFlag{
+			Name:    "api-grpc-addr",
+			EnvVars: []string{"API_GRPC_ADDR"},
+			Usage:   "host:port of the server's agent gRPC service; when set, polls, task logs, heartbeats and completions use gRPC instead of REST",
+		},
+		&cli.BoolFlag{
+			Name:    "api-grpc-tls",
+			EnvVars: []string{"API_GRPC_TLS"},
+			Usage:   "Connect to API_GRPC_ADDR over TLS, verifying the server against the system roots",
+		},
+		&cli.StringFlag{
+			Name:    "api-grpc-ca-cert",
+			EnvVars: []string{"API_GRPC_CA_CERT"},
+			Usage:   "PEM CA bundle to verify API_GRPC_ADDR's certificate against instead of the system roots; implies API_GRPC_TLS",
+		},
+		&cli.BoolFlag{
+			Name:    "api-grpc-insecure",
+			EnvVars: []string{"API_GRPC_INSECURE"},
+			Usage:   "Allow plaintext gRPC to a non-loopback API_GRPC_ADDR, sending GitHub tokens unencrypted",
+		},
 		&cli.StringFlag{
 			Name:    "anthropic-api-key",
 			EnvVars: []string{"ANTHROPIC_API_KEY"},
@@ -135,35 +490,176 @@ func main() {
 			Usage:   "Host directory for dependency cache volume",
 			Value:   worker.DefaultCacheDir(),
 		},
+		&cli.BoolFlag{
+			Name:    "workspace-cache",
+			EnvVars: []string{"WORKSPACE_CACHE"},
+			Usage:   "Keep a Docker volume per repo with the clone and dependency caches between task attempts",
+			Value:   true,
+		},
+		&cli.DurationFlag{
+			Name:    "workspace-cache-ttl",
+			EnvVars: []string{"WORKSPACE_CACHE_TTL"},
+			Usage:   "Remove workspace cache volumes not used for this long (0 disables)",
+			Value:   worker.DefaultWorkspaceCacheTTL,
+		},
+		&cli.Int64Flag{
+			Name:    "workspace-cache-max-size",
+			EnvVars: []string{"WORKSPACE_CACHE_MAX_SIZE"},
+			Usage:   "Total size in bytes of workspace cache volumes to keep before evicting the least recently used (0 disables)",
+			Value:   worker.DefaultWorkspaceCacheMaxBytes,
+		},
+		&cli.DurationFlag{
+			Name:    "poll-interval",
+			EnvVars: []string{"POLL_INTERVAL"},
+			Usage:   "Base delay between polls for work when backing off after errors or idle periods",
+			Value:   5 * time.Second,
+		},
+		&cli.DurationFlag{
+			Name:    "poll-max-interval",
+			EnvVars: []string{"POLL_MAX_INTERVAL"},
+			Usage:   "Maximum delay between polls for work when backing off",
+			Value:   30 * time.Second,
+		},
+		&cli.DurationFlag{
+			Name:    "poll-jitter",
+			EnvVars: []string{"POLL_JITTER"},
+			Usage:   "Maximum random delay added before each poll to spread load across workers",
+			Value:   2 * time.Second,
+		},
+		&cli.StringFlag{
+			Name:    "worker-mode",
+			EnvVars: []string{"WORKER_MODE"},
+			Usage:   "How agents run: docker, or sim to emulate them from a scenario file without Docker or credentials",
+			Value:   worker.ModeDocker,
+		},
+		&cli.StringFlag{
+			Name:    "sim-scenario",
+			EnvVars: []string{"SIM_SCENARIO"},
+			Usage:   "JSON scenario file emulated agents follow in sim mode (default: built-in scenario)",
+		},
+		&cli.StringFlag{
+			Name:    "worker-labels",
+			EnvVars: []string{"WORKER_LABELS"},
+			Usage:   "Comma-separated labels for this worker (e.g. gpu,region.eu). Tasks that require labels only go to workers that have all of them",
+		},
+	}
+
+	configFlags := []cli.Flag{
+		&cli.StringFlag{
+			Name:    "config",
+			EnvVars: []string{"VERVE_CONFIG"},
+			Usage:   "YAML file of flag names to values; flags set on the command line or through environment variables take precedence",
+		},
+		&cli.BoolFlag{
+			Name:  "print-config",
+			Usage: "Print the effective configuration and where each value came from, then exit",
+		},
 	}
+	// Settings a config file may hold; commands skip those they don't use.
+	configurable := concat(sharedFlags, databaseFlags, apiFlags, workerFlags)
 
 	cliApp := &cli.App{
 		Name:    "verve",
 		Usage:   "AI agent orchestrator — runs API server and worker",
 		Version: fmt.Sprintf("%s (commit: %s, built: %s)", version, commit, date),
-		Flags: concat(sharedFlags, apiFlags, workerFlags),
-		Action: func(c *cli.Context) error {
+		Flags: concat(configFlags, sharedFlags, databaseFlags, apiFlags, workerFlags),
+		Action: withConfig(configurable, func(c *cli.Context) error {
 			return runCombined(ctx, c, logger)
-		},
+		}),
 		Commands: []*cli.Command{
 			{
 				Name:  "api",
 				Usage: "Run the API server only",
-				Flags: concat(sharedFlags, apiFlags),
-				Action: func(c *cli.Context) error {
+				Flags: concat(configFlags, sharedFlags, databaseFlags, apiFlags),
+				Action: withConfig(configurable, func(c *cli.Context) error {
 					return runAPI(ctx, c, logger)
+				}),
+			},
+			{
+				Name:  "migrate",
+				Usage: "Apply pending database migrations and exit",
+				Flags: concat(configFlags, databaseFlags, []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Report pending migrations without applying them",
+					},
+				}),
+				Action: withConfig(configurable, func(c *cli.Context) error {
+					return runMigrate(ctx, c, logger)
+				}),
+			},
+			{
+				Name:  "backup",
+				Usage: "Write a consistent snapshot of the SQLite database; safe while the server runs",
+				Flags: []cli.Flag{
+					sqliteDirFlag,
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Path to write the snapshot to (default: verve-<timestamp>.db in the current directory)",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					return runBackup(ctx, c, logger)
+				},
+			},
+			{
+				Name:  "restore",
+				Usage: "Replace the SQLite database with a snapshot; stop every server using it first",
+				Flags: []cli.Flag{
+					sqliteDirFlag,
+					&cli.StringFlag{
+						Name:     "input",
+						Aliases:  []string{"i"},
+						Usage:    "Snapshot to restore",
+						Required: true,
+					},
+				},
+				Action: func(c *cli.Context) error {
+					return runRestore(ctx, c, logger)
+				},
+			},
+			{
+				Name:  "export",
+				Usage: "Export repos, teams, epics, tasks with their logs, and settings as JSON lines for import into another database",
+				Flags: concat(databaseFlags, []cli.Flag{
+					&cli.StringFlag{
+						Name:     "output",
+						Aliases:  []string{"o"},
+						Usage:    "Directory to write the export to",
+						Required: true,
+					},
+				}),
+				Action: func(c *cli.Context) error {
+					return runExport(ctx, c, logger)
+				},
+			},
+			{
+				Name:  "import",
+				Usage: "Import an export into an empty database, migrating it first",
+				Flags: concat(databaseFlags, []cli.Flag{
+					&cli.StringFlag{
+						Name:     "input",
+						Aliases:  []string{"i"},
+						Usage:    "Directory holding the export",
+						Required: true,
+					},
+				}),
+				Action: func(c *cli.Context) error {
+					return runImport(ctx, c, logger)
 				},
 			},
 			{
 				Name:  "worker",
 				Usage: "Run the worker only",
-				Flags: concat(sharedFlags, workerFlags),
-				Action: func(c *cli.Context) error {
+				Flags: concat(configFlags, sharedFlags, workerFlags),
+				Action: withConfig(configurable, func(c *cli.Context) error {
 					return runWorker(ctx, c, logger)
-				},
+				}),
 			},
 		},
 	}
+	cliApp.Commands = append(cliApp.Commands, clientCommands(ctx)...)
 
 	if err := cliApp.RunContext(ctx, os.Args); err != nil {
 		logger.Error("fatal error", "error", err)
@@ -174,22 +670,32 @@ func main() {
 // runCombined starts both the API server and worker in the same process.
 func runCombined(ctx context.Context, c *cli.Context, logger log.Logger) error {
 	// Validate worker auth.
-	if !c.Bool("dry-run") && c.String("anthropic-api-key") == "" && c.String("claude-code-oauth-token") == "" {
-		return fmt.Errorf("ANTHROPIC_API_KEY or CLAUDE_CODE_OAUTH_TOKEN is required (or set DRY_RUN=true)")
+	workerCfg := buildWorkerConfig(c)
+	if err := validateWorkerAuth(workerCfg); err != nil {
+		return err
 	}
 
-	// Resolve encryption key (auto-generate for local dev).
-	encryptionKey, err := keymanager.ResolveEncryptionKey(c.String("encryption-key"), logger)
-	if err != nil {
-		return err
+	// Resolve encryption key (auto-generate for local dev) unless it is read
+	// from a secrets manager.
+	var encryptionKey string
+	if c.String("encryption-key-secret") == "" {
+		var err error
+		encryptionKey, err = keymanager.ResolveEncryptionKey(c.String("encryption-key"), logger)
+		if err != nil {
+			return err
+		}
 	}
 
 	apiCfg := buildAPIConfig(c, encryptionKey, true)
-	workerCfg := buildWorkerConfig(c)
 
 	// In combined mode, worker always talks to the co-located API.
 	port := c.Int("port")
 	workerCfg.APIURL = fmt.Sprintf("http://localhost:%d", port)
+	workerCfg.GRPCAddr = ""
+	if apiCfg.AgentGRPCPort > 0 {
+		workerCfg.GRPCAddr = fmt.Sprintf("localhost:%d", apiCfg.AgentGRPCPort)
+		workerCfg.GRPCCACert = apiCfg.AgentGRPCTLSCert
+	}
 
 	logWorkerConfig(logger, workerCfg)
 
@@ -237,7 +743,8 @@ func runCombined(ctx context.Context, c *cli.Context, logger log.Logger) error {
 // runAPI starts only the API server.
 func runAPI(ctx context.Context, c *cli.Context, logger log.Logger) error {
 	encryptionKey := c.String("encryption-key")
-	if encryptionKey == "" {
+	keyProvider := c.String("key-provider")
+	if encryptionKey == "" && (keyProvider == "" || keyProvider == "env") {
 		logger.Warn("encryption key not set, github token storage will be unavailable")
 	}
 
@@ -250,12 +757,103 @@ func runAPI(ctx context.Context, c *cli.Context, logger log.Logger) error {
 	return app.Run(ctx, logger, cfg)
 }
 
+// runMigrate applies pending database migrations, or reports them in dry-run
+// mode. Without SQLITE_DIR or TURSO_DSN it targets the default data directory
+// used by combined mode.
+func runMigrate(ctx context.Context, c *cli.Context, logger log.Logger) error {
+	cfg := app.MigrateConfig{
+		SQLiteDir:   c.String("sqlite-dir"),
+		TursoDSN:    c.String("turso-dsn"),
+		DryRun:      c.Bool("dry-run"),
+		LockTimeout: c.Duration("migrate-lock-timeout"),
+	}
+	if cfg.SQLiteDir == "" && cfg.TursoDSN == "" {
+		dataDir, err := dataHome()
+		if err != nil {
+			return fmt.Errorf("resolve data directory: %w", err)
+		}
+		cfg.SQLiteDir = dataDir
+	}
+	return app.Migrate(ctx, logger, cfg)
+}
+
+// runBackup writes a snapshot of the SQLite database. Without SQLITE_DIR it
+// targets the default data directory used by combined mode.
+func runBackup(ctx context.Context, c *cli.Context, logger log.Logger) error {
+	cfg := app.BackupConfig{
+		SQLiteDir: c.String("sqlite-dir"),
+		Output:    c.String("output"),
+	}
+	if cfg.SQLiteDir == "" {
+		dataDir, err := dataHome()
+		if err != nil {
+			return fmt.Errorf("resolve data directory: %w", err)
+		}
+		cfg.SQLiteDir = dataDir
+	}
+	return app.Backup(ctx, logger, cfg)
+}
+
+// runRestore replaces the SQLite database with a snapshot. Without
+// SQLITE_DIR it targets the default data directory used by combined mode.
+func runRestore(ctx context.Context, c *cli.Context, logger log.Logger) error {
+	cfg := app.RestoreConfig{
+		SQLiteDir: c.String("sqlite-dir"),
+		Input:     c.String("input"),
+	}
+	if cfg.SQLiteDir == "" {
+		dataDir, err := dataHome()
+		if err != nil {
+			return fmt.Errorf("resolve data directory: %w", err)
+		}
+		cfg.SQLiteDir = dataDir
+	}
+	return app.Restore(ctx, logger, cfg)
+}
+
+// runExport exports the database. Without SQLITE_DIR or TURSO_DSN it
+// targets the default data directory used by combined mode.
+func runExport(ctx context.Context, c *cli.Context, logger log.Logger) error {
+	cfg := app.ExportConfig{
+		SQLiteDir: c.String("sqlite-dir"),
+		TursoDSN:  c.String("turso-dsn"),
+		Output:    c.String("output"),
+	}
+	if cfg.SQLiteDir == "" && cfg.TursoDSN == "" {
+		dataDir, err := dataHome()
+		if err != nil {
+			return fmt.Errorf("resolve data directory: %w", err)
+		}
+		cfg.SQLiteDir = dataDir
+	}
+	return app.Export(ctx, logger, cfg)
+}
+
+// runImport imports an export into the database. Without SQLITE_DIR or
+// TURSO_DSN it targets the default data directory used by combined mode.
+func runImport(ctx context.Context, c *cli.Context, logger log.Logger) error {
+	cfg := app.ImportConfig{
+		SQLiteDir:   c.String("sqlite-dir"),
+		TursoDSN:    c.String("turso-dsn"),
+		Input:       c.String("input"),
+		LockTimeout: c.Duration("migrate-lock-timeout"),
+	}
+	if cfg.SQLiteDir == "" && cfg.TursoDSN == "" {
+		dataDir, err := dataHome()
+		if err != nil {
+			return fmt.Errorf("resolve data directory: %w", err)
+		}
+		cfg.SQLiteDir = dataDir
+	}
+	return app.Import(ctx, logger, cfg)
+}
+
 // runWorker starts only the worker.
 func runWorker(ctx context.Context, c *cli.Context, logger log.Logger) error {
 	cfg := buildWorkerConfig(c)
 
-	if !cfg.DryRun && cfg.AnthropicAPIKey == "" && cfg.ClaudeCodeOAuthToken == "" {
-		return fmt.Errorf("ANTHROPIC_API_KEY or CLAUDE_CODE_OAUTH_TOKEN is required (or set DRY_RUN=true)")
+	if err := validateWorkerAuth(cfg); err != nil {
+		return err
 	}
 
 	if cfg.GitHubInsecureSkipVerify {
@@ -304,15 +902,80 @@ func buildAPIConfig(c *cli.Context, encryptionKey string, combined bool) app.Con
 	}
 
 	cfg := app.Config{
+		Version:                  version,
 		Port:                     c.Int("port"),
+		AgentGRPCPort:            c.Int("agent-grpc-port"),
+		AgentGRPCTLSCert:         c.String("agent-grpc-tls-cert"),
+		AgentGRPCTLSKey:          c.String("agent-grpc-tls-key"),
 		UI:                       ui,
 		EncryptionKey:            encryptionKey,
+		PreviousEncryptionKeys:   parseList(c.String("previous-encryption-keys")),
+		KeyProvider:              c.String("key-provider"),
+		KeyProviderKeyID:         c.String("key-provider-key-id"),
+		AWSRegion:                c.String("aws-region"),
+		AWSAccessKeyID:           c.String("aws-access-key-id"),
+		AWSSecretAccessKey:       c.String("aws-secret-access-key"),
+		AWSSessionToken:          c.String("aws-session-token"),
+		GCPAccessToken:           c.String("gcp-access-token"),
+		VaultAddress:             c.String("vault-addr"),
+		VaultToken:               c.String("vault-token"),
+		VaultTransitMount:        c.String("vault-transit-mount"),
+		SecretsBackend:           c.String("secrets-backend"),
+		VaultKVMount:             c.String("vault-kv-mount"),
+		EncryptionKeySecret:      c.String("encryption-key-secret"),
+		GitHubTokenSecret:        c.String("github-token-secret"),
+		GitHubTokenSecretTTL:     c.Duration("github-token-secret-ttl"),
 		GitHubInsecureSkipVerify: c.Bool("github-insecure-skip-verify"),
 		SQLiteDir:                sqliteDir,
 		TursoDSN:                 c.String("turso-dsn"),
-		CorsOrigins:              parseCorsOrigins(c.String("cors-origins")),
+		CorsOrigins:              parseList(c.String("cors-origins")),
 		TaskTimeout:              c.Duration("task-timeout"),
 		LogRetention:             c.Duration("log-retention"),
+		LogBatchInterval:         c.Duration("log-batch-interval"),
+		TaskTrashRetention:       c.Duration("task-trash-retention"),
+		TaskArchiveAfter:         c.Duration("task-archive-after"),
+		SyncInterval:             c.Duration("sync-interval"),
+		MergeMethod:              c.String("merge-method"),
+		ReapInterval:             c.Duration("reap-interval"),
+		AdminToken:               c.String("admin-token"),
+		RequireAuth:              c.Bool("require-auth"),
+		SessionTTL:               c.Duration("session-ttl"),
+		MCPToken:                 c.String("mcp-token"),
+		MCPRepos:                 parseList(c.String("mcp-repos")),
+		AutoMigrate:              c.Bool("auto-migrate"),
+		MigrateLockTimeout:       c.Duration("migrate-lock-timeout"),
+		CheckpointInterval:       c.Duration("sqlite-checkpoint-interval"),
+		EventBus:                 c.String("event-bus"),
+		BackupDir:                c.String("backup-dir"),
+		BackupInterval:           c.Duration("backup-interval"),
+		BackupKeep:               c.Int("backup-keep"),
+		AttachmentStorage:        c.String("attachment-storage"),
+		AttachmentDir:            c.String("attachment-dir"),
+		AttachmentS3Bucket:       c.String("attachment-s3-bucket"),
+		AttachmentS3Prefix:       c.String("attachment-s3-prefix"),
+		AttachmentS3Endpoint:     c.String("attachment-s3-endpoint"),
+		AttachmentMaxSize:        c.Int64("attachment-max-size"),
+		ArtifactRetention:        c.Duration("artifact-retention"),
+		ProvenanceScan:           c.Bool("provenance-scan"),
+		ProvenanceScanner:        c.String("provenance-scanner"),
+		ProvenanceBlockLines:     c.Int("provenance-block-lines"),
+		SelfReview:               c.Bool("self-review"),
+		SelfReviewMaxFiles:       c.Int("self-review-max-files"),
+		SelfReviewMaxFileLines:   c.Int("self-review-max-file-lines"),
+		SelfReviewMaxLines:       c.Int("self-review-max-lines"),
+		CostAnomalyDetection:     c.Bool("cost-anomaly-detection"),
+		CostAnomalyTaskFactor:    c.Float64("cost-anomaly-task-factor"),
+		CostAnomalySpikeFactor:   c.Float64("cost-anomaly-spike-factor"),
+		CostAnomalyMinHourlyUSD:  c.Float64("cost-anomaly-min-hourly-usd"),
+		CostAnomalyAutoPause:     c.Bool("cost-anomaly-auto-pause"),
+		AgentMaxConcurrent:       c.Int("agent-max-concurrent"),
+		AgentTokensPerMinute:     c.Int("agent-tokens-per-minute"),
+		AgentRunTokens:           c.Int("agent-run-tokens"),
+		AgentModelLimits:         c.String("agent-model-limits"),
+	}
+
+	if sunset := c.Timestamp("api-v1-sunset"); sunset != nil {
+		cfg.APIV1Sunset = *sunset
 	}
 
 	if models := c.String("claude-models"); models != "" {
@@ -325,6 +988,10 @@ func buildAPIConfig(c *cli.Context, encryptionKey string, combined bool) app.Con
 func buildWorkerConfig(c *cli.Context) worker.Config {
 	return worker.Config{
 		APIURL:                    c.String("api-url"),
+		GRPCAddr:                  c.String("api-grpc-addr"),
+		GRPCTLS:                   c.Bool("api-grpc-tls"),
+		GRPCCACert:                c.String("api-grpc-ca-cert"),
+		GRPCInsecure:              c.Bool("api-grpc-insecure"),
 		AnthropicAPIKey:           c.String("anthropic-api-key"),
 		AnthropicBaseURL:          c.String("anthropic-base-url"),
 		ClaudeCodeOAuthToken:      c.String("claude-code-oauth-token"),
@@ -335,26 +1002,65 @@ func buildWorkerConfig(c *cli.Context) worker.Config {
 		StripAnthropicBetaHeaders: c.Bool("strip-anthropic-beta-headers"),
 		CacheEnabled:              c.Bool("cache"),
 		CacheDir:                  c.String("cache-dir"),
+		WorkspaceCacheEnabled:     c.Bool("workspace-cache"),
+		WorkspaceCacheTTL:         c.Duration("workspace-cache-ttl"),
+		WorkspaceCacheMaxBytes:    c.Int64("workspace-cache-max-size"),
+		PollInterval:              c.Duration("poll-interval"),
+		PollMaxInterval:           c.Duration("poll-max-interval"),
+		PollJitter:                c.Duration("poll-jitter"),
+		Mode:                      c.String("worker-mode"),
+		SimScenario:               c.String("sim-scenario"),
+		Version:                   version,
+		Labels:                    parseList(c.String("worker-labels")),
 	}
 }
 
+// validateWorkerAuth checks the worker mode and labels, and that a real worker
+// has Claude credentials. Sim mode and dry runs need none.
+func validateWorkerAuth(cfg worker.Config) error {
+	if err := task.ValidateLabels(cfg.Labels); err != nil {
+		return fmt.Errorf("WORKER_LABELS: %w", err)
+	}
+	switch cfg.Mode {
+	case worker.ModeSim:
+		return nil
+	case worker.ModeDocker:
+	default:
+		return fmt.Errorf("WORKER_MODE must be %s or %s", worker.ModeDocker, worker.ModeSim)
+	}
+	if !cfg.DryRun && cfg.AnthropicAPIKey == "" && cfg.ClaudeCodeOAuthToken == "" {
+		return fmt.Errorf("ANTHROPIC_API_KEY or CLAUDE_CODE_OAUTH_TOKEN is required (or set DRY_RUN=true)")
+	}
+	return nil
+}
+
 func logWorkerConfig(logger log.Logger, cfg worker.Config) {
 	authMethod := "api_key"
 	if cfg.ClaudeCodeOAuthToken != "" {
 		authMethod = "oauth"
 	}
 	logger.Info("worker configured",
+		"worker.mode", cfg.Mode,
 		"worker.api_url", cfg.APIURL,
+		"worker.api_grpc_addr", cfg.GRPCAddr,
+		"worker.api_grpc_tls", cfg.GRPCTLS || cfg.GRPCCACert != "",
 		"worker.auth_method", authMethod,
 		"worker.agent_image", cfg.AgentImage,
 		"worker.max_concurrent", cfg.MaxConcurrentTasks,
 		"worker.dry_run", cfg.DryRun,
 		"worker.cache_enabled", cfg.CacheEnabled,
 		"worker.cache_dir", cfg.CacheDir,
+		"worker.workspace_cache_enabled", cfg.WorkspaceCacheEnabled,
+		"worker.workspace_cache_ttl", cfg.WorkspaceCacheTTL,
+		"worker.workspace_cache_max_bytes", cfg.WorkspaceCacheMaxBytes,
+		"worker.poll_interval", cfg.PollInterval,
+		"worker.poll_max_interval", cfg.PollMaxInterval,
+		"worker.poll_jitter", cfg.PollJitter,
+		"worker.labels", cfg.Labels,
 	)
 }
 
-func parseCorsOrigins(s string) []string {
+func parseList(s string) []string {
 	if s == "" {
 		return nil
 	}
@@ -402,6 +1108,28 @@ func waitHealthy(ctx context.Context, url string, attempts int, interval time.Du
 	return fmt.Errorf("health check at %s failed after %d attempts", url, attempts)
 }
 
+// withConfig applies the --config file to c before running action, or prints
+// the effective configuration instead of running it when --print-config is
+// set.
+func withConfig(configurable []cli.Flag, action cli.ActionFunc) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		var file *cliconfig.File
+		if path := c.String("config"); path != "" {
+			var err error
+			if file, err = cliconfig.Load(path, configurable); err != nil {
+				return err
+			}
+			if err := file.Apply(c); err != nil {
+				return err
+			}
+		}
+		if c.Bool("print-config") {
+			return cliconfig.Print(c.App.Writer, c, file, "config", "print-config")
+		}
+		return action(c)
+	}
+}
+
 func concat(slices ...[]cli.Flag) []cli.Flag {
 	var out []cli.Flag
 	for _, s := range slices {
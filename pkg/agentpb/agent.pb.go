@@ -0,0 +1,1798 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: verve/agent/v1/agent.proto
+
+package agentpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PollRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Identifies the polling worker so the server can track its capacity.
+	WorkerId      string `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	MaxConcurrent int32  `protobuf:"varint,2,opt,name=max_concurrent,json=maxConcurrent,proto3" json:"max_concurrent,omitempty"`
+	ActiveTasks   int32  `protobuf:"varint,3,opt,name=active_tasks,json=activeTasks,proto3" json:"active_tasks,omitempty"`
+	// Only tasks whose required labels are all in labels are handed out.
+	Labels []string `protobuf:"bytes,4,rep,name=labels,proto3" json:"labels,omitempty"`
+	// Most work items to claim at once, between 1 and 32. Zero claims one.
+	Max           int32 `protobuf:"varint,5,opt,name=max,proto3" json:"max,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PollRequest) Reset() {
+	*x = PollRequest{}
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PollRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PollRequest) ProtoMessage() {}
+
+func (x *PollRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PollRequest.ProtoReflect.Descriptor instead.
+func (*PollRequest) Descriptor() ([]byte, []int) {
+	return file_verve_agent_v1_agent_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PollRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *PollRequest) GetMaxConcurrent() int32 {
+	if x != nil {
+		return x.MaxConcurrent
+	}
+	return 0
+}
+
+func (x *PollRequest) GetActiveTasks() int32 {
+	if x != nil {
+		return x.ActiveTasks
+	}
+	return 0
+}
+
+func (x *PollRequest) GetLabels() []string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *PollRequest) GetMax() int32 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+type PollResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*WorkItem            `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PollResponse) Reset() {
+	*x = PollResponse{}
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PollResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PollResponse) ProtoMessage() {}
+
+func (x *PollResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PollResponse.ProtoReflect.Descriptor instead.
+func (*PollResponse) Descriptor() ([]byte, []int) {
+	return file_verve_agent_v1_agent_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PollResponse) GetItems() []*WorkItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// WorkItem is a claimed unit of work. Type says which of task, epic, setup
+// and conversation is set.
+type WorkItem struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "task", "epic", "setup", "setup-review" or "conversation".
+	Type             string            `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Task             *PollTask         `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`
+	Epic             *PollEpic         `protobuf:"bytes,3,opt,name=epic,proto3" json:"epic,omitempty"`
+	Setup            *Setup            `protobuf:"bytes,4,opt,name=setup,proto3" json:"setup,omitempty"`
+	Conversation     *PollConversation `protobuf:"bytes,5,opt,name=conversation,proto3" json:"conversation,omitempty"`
+	GithubToken      string            `protobuf:"bytes,6,opt,name=github_token,json=githubToken,proto3" json:"github_token,omitempty"`
+	RepoFullName     string            `protobuf:"bytes,7,opt,name=repo_full_name,json=repoFullName,proto3" json:"repo_full_name,omitempty"`
+	RepoSummary      string            `protobuf:"bytes,8,opt,name=repo_summary,json=repoSummary,proto3" json:"repo_summary,omitempty"`
+	RepoExpectations string            `protobuf:"bytes,9,opt,name=repo_expectations,json=repoExpectations,proto3" json:"repo_expectations,omitempty"`
+	RepoTechStack    string            `protobuf:"bytes,10,opt,name=repo_tech_stack,json=repoTechStack,proto3" json:"repo_tech_stack,omitempty"`
+	// The remaining fields are only set for tasks.
+	PrConventions string `protobuf:"bytes,11,opt,name=pr_conventions,json=prConventions,proto3" json:"pr_conventions,omitempty"`
+	EpicContext   string `protobuf:"bytes,12,opt,name=epic_context,json=epicContext,proto3" json:"epic_context,omitempty"`
+	// Set for epics: the planning transcript so far.
+	EpicTranscript           string        `protobuf:"bytes,13,opt,name=epic_transcript,json=epicTranscript,proto3" json:"epic_transcript,omitempty"`
+	WorkspaceCacheGeneration int64         `protobuf:"varint,14,opt,name=workspace_cache_generation,json=workspaceCacheGeneration,proto3" json:"workspace_cache_generation,omitempty"`
+	AdditionalRepos          []string      `protobuf:"bytes,15,rep,name=additional_repos,json=additionalRepos,proto3" json:"additional_repos,omitempty"`
+	ShadowMode               bool          `protobuf:"varint,16,opt,name=shadow_mode,json=shadowMode,proto3" json:"shadow_mode,omitempty"`
+	Proposal                 string        `protobuf:"bytes,17,opt,name=proposal,proto3" json:"proposal,omitempty"`
+	BaseBranch               string        `protobuf:"bytes,18,opt,name=base_branch,json=baseBranch,proto3" json:"base_branch,omitempty"`
+	MaxRuntimeSeconds        int64         `protobuf:"varint,19,opt,name=max_runtime_seconds,json=maxRuntimeSeconds,proto3" json:"max_runtime_seconds,omitempty"`
+	Attachments              []*Attachment `protobuf:"bytes,20,rep,name=attachments,proto3" json:"attachments,omitempty"`
+	PermitRequired           bool          `protobuf:"varint,21,opt,name=permit_required,json=permitRequired,proto3" json:"permit_required,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *WorkItem) Reset() {
+	*x = WorkItem{}
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkItem) ProtoMessage() {}
+
+func (x *WorkItem) ProtoReflect() protoreflect.Message {
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkItem.ProtoReflect.Descriptor instead.
+func (*WorkItem) Descriptor() ([]byte, []int) {
+	return file_verve_agent_v1_agent_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *WorkItem) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *WorkItem) GetTask() *PollTask {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *WorkItem) GetEpic() *PollEpic {
+	if x != nil {
+		return x.Epic
+	}
+	return nil
+}
+
+func (x *WorkItem) GetSetup() *Setup {
+	if x != nil {
+		return x.Setup
+	}
+	return nil
+}
+
+func (x *WorkItem) GetConversation() *PollConversation {
+	if x != nil {
+		return x.Conversation
+	}
+	return nil
+}
+
+func (x *WorkItem) GetGithubToken() string {
+	if x != nil {
+		return x.GithubToken
+	}
+	return ""
+}
+
+func (x *WorkItem) GetRepoFullName() string {
+	if x != nil {
+		return x.RepoFullName
+	}
+	return ""
+}
+
+func (x *WorkItem) GetRepoSummary() string {
+	if x != nil {
+		return x.RepoSummary
+	}
+	return ""
+}
+
+func (x *WorkItem) GetRepoExpectations() string {
+	if x != nil {
+		return x.RepoExpectations
+	}
+	return ""
+}
+
+func (x *WorkItem) GetRepoTechStack() string {
+	if x != nil {
+		return x.RepoTechStack
+	}
+	return ""
+}
+
+func (x *WorkItem) GetPrConventions() string {
+	if x != nil {
+		return x.PrConventions
+	}
+	return ""
+}
+
+func (x *WorkItem) GetEpicContext() string {
+	if x != nil {
+		return x.EpicContext
+	}
+	return ""
+}
+
+func (x *WorkItem) GetEpicTranscript() string {
+	if x != nil {
+		return x.EpicTranscript
+	}
+	return ""
+}
+
+func (x *WorkItem) GetWorkspaceCacheGeneration() int64 {
+	if x != nil {
+		return x.WorkspaceCacheGeneration
+	}
+	return 0
+}
+
+func (x *WorkItem) GetAdditionalRepos() []string {
+	if x != nil {
+		return x.AdditionalRepos
+	}
+	return nil
+}
+
+func (x *WorkItem) GetShadowMode() bool {
+	if x != nil {
+		return x.ShadowMode
+	}
+	return false
+}
+
+func (x *WorkItem) GetProposal() string {
+	if x != nil {
+		return x.Proposal
+	}
+	return ""
+}
+
+func (x *WorkItem) GetBaseBranch() string {
+	if x != nil {
+		return x.BaseBranch
+	}
+	return ""
+}
+
+func (x *WorkItem) GetMaxRuntimeSeconds() int64 {
+	if x != nil {
+		return x.MaxRuntimeSeconds
+	}
+	return 0
+}
+
+func (x *WorkItem) GetAttachments() []*Attachment {
+	if x != nil {
+		return x.Attachments
+	}
+	return nil
+}
+
+func (x *WorkItem) GetPermitRequired() bool {
+	if x != nil {
+		return x.PermitRequired
+	}
+	return false
+}
+
+type PollTask struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Id                 string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Number             int64                  `protobuf:"varint,2,opt,name=number,proto3" json:"number,omitempty"`
+	RepoId             string                 `protobuf:"bytes,3,opt,name=repo_id,json=repoId,proto3" json:"repo_id,omitempty"`
+	Title              string                 `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	Description        string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	Status             string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	Attempt            int32                  `protobuf:"varint,7,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	MaxAttempts        int32                  `protobuf:"varint,8,opt,name=max_attempts,json=maxAttempts,proto3" json:"max_attempts,omitempty"`
+	RetryReason        string                 `protobuf:"bytes,9,opt,name=retry_reason,json=retryReason,proto3" json:"retry_reason,omitempty"`
+	AcceptanceCriteria []string               `protobuf:"bytes,10,rep,name=acceptance_criteria,json=acceptanceCriteria,proto3" json:"acceptance_criteria,omitempty"`
+	RetryContext       string                 `protobuf:"bytes,11,opt,name=retry_context,json=retryContext,proto3" json:"retry_context,omitempty"`
+	AgentStatus        string                 `protobuf:"bytes,12,opt,name=agent_status,json=agentStatus,proto3" json:"agent_status,omitempty"`
+	CostUsd            float64                `protobuf:"fixed64,13,opt,name=cost_usd,json=costUsd,proto3" json:"cost_usd,omitempty"`
+	MaxCostUsd         float64                `protobuf:"fixed64,14,opt,name=max_cost_usd,json=maxCostUsd,proto3" json:"max_cost_usd,omitempty"`
+	SkipPr             bool                   `protobuf:"varint,15,opt,name=skip_pr,json=skipPr,proto3" json:"skip_pr,omitempty"`
+	DraftPr            bool                   `protobuf:"varint,16,opt,name=draft_pr,json=draftPr,proto3" json:"draft_pr,omitempty"`
+	PlanOnly           bool                   `protobuf:"varint,17,opt,name=plan_only,json=planOnly,proto3" json:"plan_only,omitempty"`
+	Model              string                 `protobuf:"bytes,18,opt,name=model,proto3" json:"model,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *PollTask) Reset() {
+	*x = PollTask{}
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PollTask) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PollTask) ProtoMessage() {}
+
+func (x *PollTask) ProtoReflect() protoreflect.Message {
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PollTask.ProtoReflect.Descriptor instead.
+func (*PollTask) Descriptor() ([]byte, []int) {
+	return file_verve_agent_v1_agent_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PollTask) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PollTask) GetNumber() int64 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *PollTask) GetRepoId() string {
+	if x != nil {
+		return x.RepoId
+	}
+	return ""
+}
+
+func (x *PollTask) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *PollTask) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *PollTask) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PollTask) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *PollTask) GetMaxAttempts() int32 {
+	if x != nil {
+		return x.MaxAttempts
+	}
+	return 0
+}
+
+func (x *PollTask) GetRetryReason() string {
+	if x != nil {
+		return x.RetryReason
+	}
+	return ""
+}
+
+func (x *PollTask) GetAcceptanceCriteria() []string {
+	if x != nil {
+		return x.AcceptanceCriteria
+	}
+	return nil
+}
+
+func (x *PollTask) GetRetryContext() string {
+	if x != nil {
+		return x.RetryContext
+	}
+	return ""
+}
+
+func (x *PollTask) GetAgentStatus() string {
+	if x != nil {
+		return x.AgentStatus
+	}
+	return ""
+}
+
+func (x *PollTask) GetCostUsd() float64 {
+	if x != nil {
+		return x.CostUsd
+	}
+	return 0
+}
+
+func (x *PollTask) GetMaxCostUsd() float64 {
+	if x != nil {
+		return x.MaxCostUsd
+	}
+	return 0
+}
+
+func (x *PollTask) GetSkipPr() bool {
+	if x != nil {
+		return x.SkipPr
+	}
+	return false
+}
+
+func (x *PollTask) GetDraftPr() bool {
+	if x != nil {
+		return x.DraftPr
+	}
+	return false
+}
+
+func (x *PollTask) GetPlanOnly() bool {
+	if x != nil {
+		return x.PlanOnly
+	}
+	return false
+}
+
+func (x *PollTask) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+type PollEpic struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RepoId         string                 `protobuf:"bytes,2,opt,name=repo_id,json=repoId,proto3" json:"repo_id,omitempty"`
+	Title          string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Description    string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	PlanningPrompt string                 `protobuf:"bytes,5,opt,name=planning_prompt,json=planningPrompt,proto3" json:"planning_prompt,omitempty"`
+	Model          string                 `protobuf:"bytes,6,opt,name=model,proto3" json:"model,omitempty"`
+	Feedback       *string                `protobuf:"bytes,7,opt,name=feedback,proto3,oneof" json:"feedback,omitempty"`
+	// "replan" when an active epic's remaining work is being re-planned.
+	FeedbackType *string `protobuf:"bytes,8,opt,name=feedback_type,json=feedbackType,proto3,oneof" json:"feedback_type,omitempty"`
+	// JSON array of the tasks proposed so far, as the agent writes them.
+	ProposedTasksJson []byte `protobuf:"bytes,9,opt,name=proposed_tasks_json,json=proposedTasksJson,proto3" json:"proposed_tasks_json,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *PollEpic) Reset() {
+	*x = PollEpic{}
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PollEpic) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PollEpic) ProtoMessage() {}
+
+func (x *PollEpic) ProtoReflect() protoreflect.Message {
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PollEpic.ProtoReflect.Descriptor instead.
+func (*PollEpic) Descriptor() ([]byte, []int) {
+	return file_verve_agent_v1_agent_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PollEpic) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PollEpic) GetRepoId() string {
+	if x != nil {
+		return x.RepoId
+	}
+	return ""
+}
+
+func (x *PollEpic) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *PollEpic) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *PollEpic) GetPlanningPrompt() string {
+	if x != nil {
+		return x.PlanningPrompt
+	}
+	return ""
+}
+
+func (x *PollEpic) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *PollEpic) GetFeedback() string {
+	if x != nil && x.Feedback != nil {
+		return *x.Feedback
+	}
+	return ""
+}
+
+func (x *PollEpic) GetFeedbackType() string {
+	if x != nil && x.FeedbackType != nil {
+		return *x.FeedbackType
+	}
+	return ""
+}
+
+func (x *PollEpic) GetProposedTasksJson() []byte {
+	if x != nil {
+		return x.ProposedTasksJson
+	}
+	return nil
+}
+
+type Setup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	RepoId        string                 `protobuf:"bytes,2,opt,name=repo_id,json=repoId,proto3" json:"repo_id,omitempty"`
+	FullName      string                 `protobuf:"bytes,3,opt,name=full_name,json=fullName,proto3" json:"full_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Setup) Reset() {
+	*x = Setup{}
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Setup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Setup) ProtoMessage() {}
+
+func (x *Setup) ProtoReflect() protoreflect.Message {
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Setup.ProtoReflect.Descriptor instead.
+func (*Setup) Descriptor() ([]byte, []int) {
+	return file_verve_agent_v1_agent_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Setup) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *Setup) GetRepoId() string {
+	if x != nil {
+		return x.RepoId
+	}
+	return ""
+}
+
+func (x *Setup) GetFullName() string {
+	if x != nil {
+		return x.FullName
+	}
+	return ""
+}
+
+type PollConversation struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RepoId string                 `protobuf:"bytes,2,opt,name=repo_id,json=repoId,proto3" json:"repo_id,omitempty"`
+	Title  string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	// JSON array of the conversation's messages so far.
+	MessagesJson   []byte `protobuf:"bytes,4,opt,name=messages_json,json=messagesJson,proto3" json:"messages_json,omitempty"`
+	PendingMessage string `protobuf:"bytes,5,opt,name=pending_message,json=pendingMessage,proto3" json:"pending_message,omitempty"`
+	Model          string `protobuf:"bytes,6,opt,name=model,proto3" json:"model,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PollConversation) Reset() {
+	*x = PollConversation{}
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PollConversation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PollConversation) ProtoMessage() {}
+
+func (x *PollConversation) ProtoReflect() protoreflect.Message {
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PollConversation.ProtoReflect.Descriptor instead.
+func (*PollConversation) Descriptor() ([]byte, []int) {
+	return file_verve_agent_v1_agent_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PollConversation) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PollConversation) GetRepoId() string {
+	if x != nil {
+		return x.RepoId
+	}
+	return ""
+}
+
+func (x *PollConversation) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *PollConversation) GetMessagesJson() []byte {
+	if x != nil {
+		return x.MessagesJson
+	}
+	return nil
+}
+
+func (x *PollConversation) GetPendingMessage() string {
+	if x != nil {
+		return x.PendingMessage
+	}
+	return ""
+}
+
+func (x *PollConversation) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+type Attachment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	TaskId        string                 `protobuf:"bytes,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Filename      string                 `protobuf:"bytes,3,opt,name=filename,proto3" json:"filename,omitempty"`
+	ContentType   string                 `protobuf:"bytes,4,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	SizeBytes     int64                  `protobuf:"varint,5,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	UploadedBy    string                 `protobuf:"bytes,6,opt,name=uploaded_by,json=uploadedBy,proto3" json:"uploaded_by,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Attachment) Reset() {
+	*x = Attachment{}
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Attachment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attachment) ProtoMessage() {}
+
+func (x *Attachment) ProtoReflect() protoreflect.Message {
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attachment.ProtoReflect.Descriptor instead.
+func (*Attachment) Descriptor() ([]byte, []int) {
+	return file_verve_agent_v1_agent_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Attachment) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Attachment) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *Attachment) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *Attachment) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *Attachment) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *Attachment) GetUploadedBy() string {
+	if x != nil {
+		return x.UploadedBy
+	}
+	return ""
+}
+
+func (x *Attachment) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type StreamLogsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The task attempt the lines belong to. Every message names it.
+	TaskId  string   `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Attempt int32    `protobuf:"varint,2,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	Lines   []string `protobuf:"bytes,3,rep,name=lines,proto3" json:"lines,omitempty"`
+	// A message resent with the same key is acknowledged without its lines
+	// being appended again.
+	IdempotencyKey string `protobuf:"bytes,4,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *StreamLogsRequest) Reset() {
+	*x = StreamLogsRequest{}
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamLogsRequest) ProtoMessage() {}
+
+func (x *StreamLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamLogsRequest.ProtoReflect.Descriptor instead.
+func (*StreamLogsRequest) Descriptor() ([]byte, []int) {
+	return file_verve_agent_v1_agent_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *StreamLogsRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *StreamLogsRequest) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *StreamLogsRequest) GetLines() []string {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *StreamLogsRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type StreamLogsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of lines stored, not counting resent messages.
+	Lines         int64 `protobuf:"varint,1,opt,name=lines,proto3" json:"lines,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamLogsResponse) Reset() {
+	*x = StreamLogsResponse{}
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamLogsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamLogsResponse) ProtoMessage() {}
+
+func (x *StreamLogsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamLogsResponse.ProtoReflect.Descriptor instead.
+func (*StreamLogsResponse) Descriptor() ([]byte, []int) {
+	return file_verve_agent_v1_agent_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StreamLogsResponse) GetLines() int64 {
+	if x != nil {
+		return x.Lines
+	}
+	return 0
+}
+
+type HeartbeatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeartbeatRequest) Reset() {
+	*x = HeartbeatRequest{}
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatRequest) ProtoMessage() {}
+
+func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatRequest.ProtoReflect.Descriptor instead.
+func (*HeartbeatRequest) Descriptor() ([]byte, []int) {
+	return file_verve_agent_v1_agent_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *HeartbeatRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+type HeartbeatResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Stopped bool                   `protobuf:"varint,1,opt,name=stopped,proto3" json:"stopped,omitempty"`
+	// User messages to deliver to the task's agent. Each nudge is returned by
+	// exactly one heartbeat.
+	Nudges        []*Nudge `protobuf:"bytes,2,rep,name=nudges,proto3" json:"nudges,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeartbeatResponse) Reset() {
+	*x = HeartbeatResponse{}
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatResponse) ProtoMessage() {}
+
+func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatResponse.ProtoReflect.Descriptor instead.
+func (*HeartbeatResponse) Descriptor() ([]byte, []int) {
+	return file_verve_agent_v1_agent_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *HeartbeatResponse) GetStopped() bool {
+	if x != nil {
+		return x.Stopped
+	}
+	return false
+}
+
+func (x *HeartbeatResponse) GetNudges() []*Nudge {
+	if x != nil {
+		return x.Nudges
+	}
+	return nil
+}
+
+type Nudge struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Attempt       int32                  `protobuf:"varint,2,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Nudge) Reset() {
+	*x = Nudge{}
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Nudge) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Nudge) ProtoMessage() {}
+
+func (x *Nudge) ProtoReflect() protoreflect.Message {
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Nudge.ProtoReflect.Descriptor instead.
+func (*Nudge) Descriptor() ([]byte, []int) {
+	return file_verve_agent_v1_agent_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Nudge) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Nudge) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *Nudge) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Nudge) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type CompleteRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TaskId string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// A completion resent with the same key is acknowledged without being
+	// applied again.
+	IdempotencyKey string `protobuf:"bytes,2,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	// The attempt that completed. Zero means the task's current attempt.
+	Attempt        int32   `protobuf:"varint,3,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	Success        bool    `protobuf:"varint,4,opt,name=success,proto3" json:"success,omitempty"`
+	PullRequestUrl string  `protobuf:"bytes,5,opt,name=pull_request_url,json=pullRequestUrl,proto3" json:"pull_request_url,omitempty"`
+	PrNumber       int64   `protobuf:"varint,6,opt,name=pr_number,json=prNumber,proto3" json:"pr_number,omitempty"`
+	BranchName     string  `protobuf:"bytes,7,opt,name=branch_name,json=branchName,proto3" json:"branch_name,omitempty"`
+	Error          string  `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	AgentStatus    string  `protobuf:"bytes,9,opt,name=agent_status,json=agentStatus,proto3" json:"agent_status,omitempty"`
+	CostUsd        float64 `protobuf:"fixed64,10,opt,name=cost_usd,json=costUsd,proto3" json:"cost_usd,omitempty"`
+	NoChanges      bool    `protobuf:"varint,11,opt,name=no_changes,json=noChanges,proto3" json:"no_changes,omitempty"`
+	Retryable      bool    `protobuf:"varint,12,opt,name=retryable,proto3" json:"retryable,omitempty"`
+	// Pull requests opened in a multi-repo task's additional repos.
+	PullRequests       []*CompletedPullRequest `protobuf:"bytes,13,rep,name=pull_requests,json=pullRequests,proto3" json:"pull_requests,omitempty"`
+	ShadowDiff         string                  `protobuf:"bytes,14,opt,name=shadow_diff,json=shadowDiff,proto3" json:"shadow_diff,omitempty"`
+	Proposal           *Proposal               `protobuf:"bytes,15,opt,name=proposal,proto3" json:"proposal,omitempty"`
+	MaxRuntimeExceeded bool                    `protobuf:"varint,16,opt,name=max_runtime_exceeded,json=maxRuntimeExceeded,proto3" json:"max_runtime_exceeded,omitempty"`
+	BudgetExceeded     bool                    `protobuf:"varint,17,opt,name=budget_exceeded,json=budgetExceeded,proto3" json:"budget_exceeded,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CompleteRequest) Reset() {
+	*x = CompleteRequest{}
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteRequest) ProtoMessage() {}
+
+func (x *CompleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteRequest.ProtoReflect.Descriptor instead.
+func (*CompleteRequest) Descriptor() ([]byte, []int) {
+	return file_verve_agent_v1_agent_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CompleteRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *CompleteRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *CompleteRequest) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *CompleteRequest) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CompleteRequest) GetPullRequestUrl() string {
+	if x != nil {
+		return x.PullRequestUrl
+	}
+	return ""
+}
+
+func (x *CompleteRequest) GetPrNumber() int64 {
+	if x != nil {
+		return x.PrNumber
+	}
+	return 0
+}
+
+func (x *CompleteRequest) GetBranchName() string {
+	if x != nil {
+		return x.BranchName
+	}
+	return ""
+}
+
+func (x *CompleteRequest) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *CompleteRequest) GetAgentStatus() string {
+	if x != nil {
+		return x.AgentStatus
+	}
+	return ""
+}
+
+func (x *CompleteRequest) GetCostUsd() float64 {
+	if x != nil {
+		return x.CostUsd
+	}
+	return 0
+}
+
+func (x *CompleteRequest) GetNoChanges() bool {
+	if x != nil {
+		return x.NoChanges
+	}
+	return false
+}
+
+func (x *CompleteRequest) GetRetryable() bool {
+	if x != nil {
+		return x.Retryable
+	}
+	return false
+}
+
+func (x *CompleteRequest) GetPullRequests() []*CompletedPullRequest {
+	if x != nil {
+		return x.PullRequests
+	}
+	return nil
+}
+
+func (x *CompleteRequest) GetShadowDiff() string {
+	if x != nil {
+		return x.ShadowDiff
+	}
+	return ""
+}
+
+func (x *CompleteRequest) GetProposal() *Proposal {
+	if x != nil {
+		return x.Proposal
+	}
+	return nil
+}
+
+func (x *CompleteRequest) GetMaxRuntimeExceeded() bool {
+	if x != nil {
+		return x.MaxRuntimeExceeded
+	}
+	return false
+}
+
+func (x *CompleteRequest) GetBudgetExceeded() bool {
+	if x != nil {
+		return x.BudgetExceeded
+	}
+	return false
+}
+
+type CompletedPullRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// owner/name
+	Repo          string `protobuf:"bytes,1,opt,name=repo,proto3" json:"repo,omitempty"`
+	Url           string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Number        int64  `protobuf:"varint,3,opt,name=number,proto3" json:"number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompletedPullRequest) Reset() {
+	*x = CompletedPullRequest{}
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompletedPullRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompletedPullRequest) ProtoMessage() {}
+
+func (x *CompletedPullRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompletedPullRequest.ProtoReflect.Descriptor instead.
+func (*CompletedPullRequest) Descriptor() ([]byte, []int) {
+	return file_verve_agent_v1_agent_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CompletedPullRequest) GetRepo() string {
+	if x != nil {
+		return x.Repo
+	}
+	return ""
+}
+
+func (x *CompletedPullRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CompletedPullRequest) GetNumber() int64 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+type Proposal struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Summary       string                 `protobuf:"bytes,1,opt,name=summary,proto3" json:"summary,omitempty"`
+	Approach      string                 `protobuf:"bytes,2,opt,name=approach,proto3" json:"approach,omitempty"`
+	Files         []*ProposalFile        `protobuf:"bytes,3,rep,name=files,proto3" json:"files,omitempty"`
+	Risks         []string               `protobuf:"bytes,4,rep,name=risks,proto3" json:"risks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Proposal) Reset() {
+	*x = Proposal{}
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Proposal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Proposal) ProtoMessage() {}
+
+func (x *Proposal) ProtoReflect() protoreflect.Message {
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Proposal.ProtoReflect.Descriptor instead.
+func (*Proposal) Descriptor() ([]byte, []int) {
+	return file_verve_agent_v1_agent_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Proposal) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *Proposal) GetApproach() string {
+	if x != nil {
+		return x.Approach
+	}
+	return ""
+}
+
+func (x *Proposal) GetFiles() []*ProposalFile {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *Proposal) GetRisks() []string {
+	if x != nil {
+		return x.Risks
+	}
+	return nil
+}
+
+type ProposalFile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Change        string                 `protobuf:"bytes,2,opt,name=change,proto3" json:"change,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProposalFile) Reset() {
+	*x = ProposalFile{}
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProposalFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProposalFile) ProtoMessage() {}
+
+func (x *ProposalFile) ProtoReflect() protoreflect.Message {
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProposalFile.ProtoReflect.Descriptor instead.
+func (*ProposalFile) Descriptor() ([]byte, []int) {
+	return file_verve_agent_v1_agent_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ProposalFile) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ProposalFile) GetChange() string {
+	if x != nil {
+		return x.Change
+	}
+	return ""
+}
+
+type CompleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteResponse) Reset() {
+	*x = CompleteResponse{}
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteResponse) ProtoMessage() {}
+
+func (x *CompleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_verve_agent_v1_agent_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteResponse.ProtoReflect.Descriptor instead.
+func (*CompleteResponse) Descriptor() ([]byte, []int) {
+	return file_verve_agent_v1_agent_proto_rawDescGZIP(), []int{17}
+}
+
+var File_verve_agent_v1_agent_proto protoreflect.FileDescriptor
+
+const file_verve_agent_v1_agent_proto_rawDesc = "" +
+	"\n" +
+	"\x1averve/agent/v1/agent.proto\x12\x0everve.agent.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x9e\x01\n" +
+	"\vPollRequest\x12\x1b\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12%\n" +
+	"\x0emax_concurrent\x18\x02 \x01(\x05R\rmaxConcurrent\x12!\n" +
+	"\factive_tasks\x18\x03 \x01(\x05R\vactiveTasks\x12\x16\n" +
+	"\x06labels\x18\x04 \x03(\tR\x06labels\x12\x10\n" +
+	"\x03max\x18\x05 \x01(\x05R\x03max\">\n" +
+	"\fPollResponse\x12.\n" +
+	"\x05items\x18\x01 \x03(\v2\x18.verve.agent.v1.WorkItemR\x05items\"\xff\x06\n" +
+	"\bWorkItem\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12,\n" +
+	"\x04task\x18\x02 \x01(\v2\x18.verve.agent.v1.PollTaskR\x04task\x12,\n" +
+	"\x04epic\x18\x03 \x01(\v2\x18.verve.agent.v1.PollEpicR\x04epic\x12+\n" +
+	"\x05setup\x18\x04 \x01(\v2\x15.verve.agent.v1.SetupR\x05setup\x12D\n" +
+	"\fconversation\x18\x05 \x01(\v2 .verve.agent.v1.PollConversationR\fconversation\x12!\n" +
+	"\fgithub_token\x18\x06 \x01(\tR\vgithubToken\x12$\n" +
+	"\x0erepo_full_name\x18\a \x01(\tR\frepoFullName\x12!\n" +
+	"\frepo_summary\x18\b \x01(\tR\vrepoSummary\x12+\n" +
+	"\x11repo_expectations\x18\t \x01(\tR\x10repoExpectations\x12&\n" +
+	"\x0frepo_tech_stack\x18\n" +
+	" \x01(\tR\rrepoTechStack\x12%\n" +
+	"\x0epr_conventions\x18\v \x01(\tR\rprConventions\x12!\n" +
+	"\fepic_context\x18\f \x01(\tR\vepicContext\x12'\n" +
+	"\x0fepic_transcript\x18\r \x01(\tR\x0eepicTranscript\x12<\n" +
+	"\x1aworkspace_cache_generation\x18\x0e \x01(\x03R\x18workspaceCacheGeneration\x12)\n" +
+	"\x10additional_repos\x18\x0f \x03(\tR\x0fadditionalRepos\x12\x1f\n" +
+	"\vshadow_mode\x18\x10 \x01(\bR\n" +
+	"shadowMode\x12\x1a\n" +
+	"\bproposal\x18\x11 \x01(\tR\bproposal\x12\x1f\n" +
+	"\vbase_branch\x18\x12 \x01(\tR\n" +
+	"baseBranch\x12.\n" +
+	"\x13max_runtime_seconds\x18\x13 \x01(\x03R\x11maxRuntimeSeconds\x12<\n" +
+	"\vattachments\x18\x14 \x03(\v2\x1a.verve.agent.v1.AttachmentR\vattachments\x12'\n" +
+	"\x0fpermit_required\x18\x15 \x01(\bR\x0epermitRequired\"\x98\x04\n" +
+	"\bPollTask\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06number\x18\x02 \x01(\x03R\x06number\x12\x17\n" +
+	"\arepo_id\x18\x03 \x01(\tR\x06repoId\x12\x14\n" +
+	"\x05title\x18\x04 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x18\n" +
+	"\aattempt\x18\a \x01(\x05R\aattempt\x12!\n" +
+	"\fmax_attempts\x18\b \x01(\x05R\vmaxAttempts\x12!\n" +
+	"\fretry_reason\x18\t \x01(\tR\vretryReason\x12/\n" +
+	"\x13acceptance_criteria\x18\n" +
+	" \x03(\tR\x12acceptanceCriteria\x12#\n" +
+	"\rretry_context\x18\v \x01(\tR\fretryContext\x12!\n" +
+	"\fagent_status\x18\f \x01(\tR\vagentStatus\x12\x19\n" +
+	"\bcost_usd\x18\r \x01(\x01R\acostUsd\x12 \n" +
+	"\fmax_cost_usd\x18\x0e \x01(\x01R\n" +
+	"maxCostUsd\x12\x17\n" +
+	"\askip_pr\x18\x0f \x01(\bR\x06skipPr\x12\x19\n" +
+	"\bdraft_pr\x18\x10 \x01(\bR\adraftPr\x12\x1b\n" +
+	"\tplan_only\x18\x11 \x01(\bR\bplanOnly\x12\x14\n" +
+	"\x05model\x18\x12 \x01(\tR\x05model\"\xc4\x02\n" +
+	"\bPollEpic\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\arepo_id\x18\x02 \x01(\tR\x06repoId\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12'\n" +
+	"\x0fplanning_prompt\x18\x05 \x01(\tR\x0eplanningPrompt\x12\x14\n" +
+	"\x05model\x18\x06 \x01(\tR\x05model\x12\x1f\n" +
+	"\bfeedback\x18\a \x01(\tH\x00R\bfeedback\x88\x01\x01\x12(\n" +
+	"\rfeedback_type\x18\b \x01(\tH\x01R\ffeedbackType\x88\x01\x01\x12.\n" +
+	"\x13proposed_tasks_json\x18\t \x01(\fR\x11proposedTasksJsonB\v\n" +
+	"\t_feedbackB\x10\n" +
+	"\x0e_feedback_type\"V\n" +
+	"\x05Setup\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x17\n" +
+	"\arepo_id\x18\x02 \x01(\tR\x06repoId\x12\x1b\n" +
+	"\tfull_name\x18\x03 \x01(\tR\bfullName\"\xb5\x01\n" +
+	"\x10PollConversation\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\arepo_id\x18\x02 \x01(\tR\x06repoId\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12#\n" +
+	"\rmessages_json\x18\x04 \x01(\fR\fmessagesJson\x12'\n" +
+	"\x0fpending_message\x18\x05 \x01(\tR\x0ependingMessage\x12\x14\n" +
+	"\x05model\x18\x06 \x01(\tR\x05model\"\xef\x01\n" +
+	"\n" +
+	"Attachment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\atask_id\x18\x02 \x01(\tR\x06taskId\x12\x1a\n" +
+	"\bfilename\x18\x03 \x01(\tR\bfilename\x12!\n" +
+	"\fcontent_type\x18\x04 \x01(\tR\vcontentType\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x05 \x01(\x03R\tsizeBytes\x12\x1f\n" +
+	"\vuploaded_by\x18\x06 \x01(\tR\n" +
+	"uploadedBy\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x85\x01\n" +
+	"\x11StreamLogsRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x18\n" +
+	"\aattempt\x18\x02 \x01(\x05R\aattempt\x12\x14\n" +
+	"\x05lines\x18\x03 \x03(\tR\x05lines\x12'\n" +
+	"\x0fidempotency_key\x18\x04 \x01(\tR\x0eidempotencyKey\"*\n" +
+	"\x12StreamLogsResponse\x12\x14\n" +
+	"\x05lines\x18\x01 \x01(\x03R\x05lines\"+\n" +
+	"\x10HeartbeatRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\"\\\n" +
+	"\x11HeartbeatResponse\x12\x18\n" +
+	"\astopped\x18\x01 \x01(\bR\astopped\x12-\n" +
+	"\x06nudges\x18\x02 \x03(\v2\x15.verve.agent.v1.NudgeR\x06nudges\"\x86\x01\n" +
+	"\x05Nudge\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x18\n" +
+	"\aattempt\x18\x02 \x01(\x05R\aattempt\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xfd\x04\n" +
+	"\x0fCompleteRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12'\n" +
+	"\x0fidempotency_key\x18\x02 \x01(\tR\x0eidempotencyKey\x12\x18\n" +
+	"\aattempt\x18\x03 \x01(\x05R\aattempt\x12\x18\n" +
+	"\asuccess\x18\x04 \x01(\bR\asuccess\x12(\n" +
+	"\x10pull_request_url\x18\x05 \x01(\tR\x0epullRequestUrl\x12\x1b\n" +
+	"\tpr_number\x18\x06 \x01(\x03R\bprNumber\x12\x1f\n" +
+	"\vbranch_name\x18\a \x01(\tR\n" +
+	"branchName\x12\x14\n" +
+	"\x05error\x18\b \x01(\tR\x05error\x12!\n" +
+	"\fagent_status\x18\t \x01(\tR\vagentStatus\x12\x19\n" +
+	"\bcost_usd\x18\n" +
+	" \x01(\x01R\acostUsd\x12\x1d\n" +
+	"\n" +
+	"no_changes\x18\v \x01(\bR\tnoChanges\x12\x1c\n" +
+	"\tretryable\x18\f \x01(\bR\tretryable\x12I\n" +
+	"\rpull_requests\x18\r \x03(\v2$.verve.agent.v1.CompletedPullRequestR\fpullRequests\x12\x1f\n" +
+	"\vshadow_diff\x18\x0e \x01(\tR\n" +
+	"shadowDiff\x124\n" +
+	"\bproposal\x18\x0f \x01(\v2\x18.verve.agent.v1.ProposalR\bproposal\x120\n" +
+	"\x14max_runtime_exceeded\x18\x10 \x01(\bR\x12maxRuntimeExceeded\x12'\n" +
+	"\x0fbudget_exceeded\x18\x11 \x01(\bR\x0ebudgetExceeded\"T\n" +
+	"\x14CompletedPullRequest\x12\x12\n" +
+	"\x04repo\x18\x01 \x01(\tR\x04repo\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x16\n" +
+	"\x06number\x18\x03 \x01(\x03R\x06number\"\x8a\x01\n" +
+	"\bProposal\x12\x18\n" +
+	"\asummary\x18\x01 \x01(\tR\asummary\x12\x1a\n" +
+	"\bapproach\x18\x02 \x01(\tR\bapproach\x122\n" +
+	"\x05files\x18\x03 \x03(\v2\x1c.verve.agent.v1.ProposalFileR\x05files\x12\x14\n" +
+	"\x05risks\x18\x04 \x03(\tR\x05risks\":\n" +
+	"\fProposalFile\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x16\n" +
+	"\x06change\x18\x02 \x01(\tR\x06change\"\x12\n" +
+	"\x10CompleteResponse2\xc9\x02\n" +
+	"\fAgentService\x12A\n" +
+	"\x04Poll\x12\x1b.verve.agent.v1.PollRequest\x1a\x1c.verve.agent.v1.PollResponse\x12U\n" +
+	"\n" +
+	"StreamLogs\x12!.verve.agent.v1.StreamLogsRequest\x1a\".verve.agent.v1.StreamLogsResponse(\x01\x12P\n" +
+	"\tHeartbeat\x12 .verve.agent.v1.HeartbeatRequest\x1a!.verve.agent.v1.HeartbeatResponse\x12M\n" +
+	"\bComplete\x12\x1f.verve.agent.v1.CompleteRequest\x1a .verve.agent.v1.CompleteResponseB&Z$github.com/vervesh/verve/pkg/agentpbb\x06proto3"
+
+var (
+	file_verve_agent_v1_agent_proto_rawDescOnce sync.Once
+	file_verve_agent_v1_agent_proto_rawDescData []byte
+)
+
+func file_verve_agent_v1_agent_proto_rawDescGZIP() []byte {
+	file_verve_agent_v1_agent_proto_rawDescOnce.Do(func() {
+		file_verve_agent_v1_agent_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_verve_agent_v1_agent_proto_rawDesc), len(file_verve_agent_v1_agent_proto_rawDesc)))
+	})
+	return file_verve_agent_v1_agent_proto_rawDescData
+}
+
+var file_verve_agent_v1_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_verve_agent_v1_agent_proto_goTypes = []any{
+	(*PollRequest)(nil),           // 0: verve.agent.v1.PollRequest
+	(*PollResponse)(nil),          // 1: verve.agent.v1.PollResponse
+	(*WorkItem)(nil),              // 2: verve.agent.v1.WorkItem
+	(*PollTask)(nil),              // 3: verve.agent.v1.PollTask
+	(*PollEpic)(nil),              // 4: verve.agent.v1.PollEpic
+	(*Setup)(nil),                 // 5: verve.agent.v1.Setup
+	(*PollConversation)(nil),      // 6: verve.agent.v1.PollConversation
+	(*Attachment)(nil),            // 7: verve.agent.v1.Attachment
+	(*StreamLogsRequest)(nil),     // 8: verve.agent.v1.StreamLogsRequest
+	(*StreamLogsResponse)(nil),    // 9: verve.agent.v1.StreamLogsResponse
+	(*HeartbeatRequest)(nil),      // 10: verve.agent.v1.HeartbeatRequest
+	(*HeartbeatResponse)(nil),     // 11: verve.agent.v1.HeartbeatResponse
+	(*Nudge)(nil),                 // 12: verve.agent.v1.Nudge
+	(*CompleteRequest)(nil),       // 13: verve.agent.v1.CompleteRequest
+	(*CompletedPullRequest)(nil),  // 14: verve.agent.v1.CompletedPullRequest
+	(*Proposal)(nil),              // 15: verve.agent.v1.Proposal
+	(*ProposalFile)(nil),          // 16: verve.agent.v1.ProposalFile
+	(*CompleteResponse)(nil),      // 17: verve.agent.v1.CompleteResponse
+	(*timestamppb.Timestamp)(nil), // 18: google.protobuf.Timestamp
+}
+var file_verve_agent_v1_agent_proto_depIdxs = []int32{
+	2,  // 0: verve.agent.v1.PollResponse.items:type_name -> verve.agent.v1.WorkItem
+	3,  // 1: verve.agent.v1.WorkItem.task:type_name -> verve.agent.v1.PollTask
+	4,  // 2: verve.agent.v1.WorkItem.epic:type_name -> verve.agent.v1.PollEpic
+	5,  // 3: verve.agent.v1.WorkItem.setup:type_name -> verve.agent.v1.Setup
+	6,  // 4: verve.agent.v1.WorkItem.conversation:type_name -> verve.agent.v1.PollConversation
+	7,  // 5: verve.agent.v1.WorkItem.attachments:type_name -> verve.agent.v1.Attachment
+	18, // 6: verve.agent.v1.Attachment.created_at:type_name -> google.protobuf.Timestamp
+	12, // 7: verve.agent.v1.HeartbeatResponse.nudges:type_name -> verve.agent.v1.Nudge
+	18, // 8: verve.agent.v1.Nudge.created_at:type_name -> google.protobuf.Timestamp
+	14, // 9: verve.agent.v1.CompleteRequest.pull_requests:type_name -> verve.agent.v1.CompletedPullRequest
+	15, // 10: verve.agent.v1.CompleteRequest.proposal:type_name -> verve.agent.v1.Proposal
+	16, // 11: verve.agent.v1.Proposal.files:type_name -> verve.agent.v1.ProposalFile
+	0,  // 12: verve.agent.v1.AgentService.Poll:input_type -> verve.agent.v1.PollRequest
+	8,  // 13: verve.agent.v1.AgentService.StreamLogs:input_type -> verve.agent.v1.StreamLogsRequest
+	10, // 14: verve.agent.v1.AgentService.Heartbeat:input_type -> verve.agent.v1.HeartbeatRequest
+	13, // 15: verve.agent.v1.AgentService.Complete:input_type -> verve.agent.v1.CompleteRequest
+	1,  // 16: verve.agent.v1.AgentService.Poll:output_type -> verve.agent.v1.PollResponse
+	9,  // 17: verve.agent.v1.AgentService.StreamLogs:output_type -> verve.agent.v1.StreamLogsResponse
+	11, // 18: verve.agent.v1.AgentService.Heartbeat:output_type -> verve.agent.v1.HeartbeatResponse
+	17, // 19: verve.agent.v1.AgentService.Complete:output_type -> verve.agent.v1.CompleteResponse
+	16, // [16:20] is the sub-list for method output_type
+	12, // [12:16] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_verve_agent_v1_agent_proto_init() }
+func file_verve_agent_v1_agent_proto_init() {
+	if File_verve_agent_v1_agent_proto != nil {
+		return
+	}
+	file_verve_agent_v1_agent_proto_msgTypes[4].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_verve_agent_v1_agent_proto_rawDesc), len(file_verve_agent_v1_agent_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_verve_agent_v1_agent_proto_goTypes,
+		DependencyIndexes: file_verve_agent_v1_agent_proto_depIdxs,
+		MessageInfos:      file_verve_agent_v1_agent_proto_msgTypes,
+	}.Build()
+	File_verve_agent_v1_agent_proto = out.File
+	file_verve_agent_v1_agent_proto_goTypes = nil
+	file_verve_agent_v1_agent_proto_depIdxs = nil
+}
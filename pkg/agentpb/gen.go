@@ -0,0 +1,7 @@
+// Package agentpb holds the generated protobuf messages and gRPC client and
+// server for the agent plane's AgentService, defined in
+// proto/verve/agent/v1/agent.proto. Regenerating requires protoc; the Go
+// plugins are module tools.
+package agentpb
+
+//go:generate sh -c "protoc -I ../../proto --plugin=protoc-gen-go=$(go tool -n protoc-gen-go) --plugin=protoc-gen-go-grpc=$(go tool -n protoc-gen-go-grpc) --go_out=../.. --go_opt=module=github.com/vervesh/verve --go-grpc_out=../.. --go-grpc_opt=module=github.com/vervesh/verve verve/agent/v1/agent.proto"
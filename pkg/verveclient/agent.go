@@ -0,0 +1,546 @@
+package verveclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Poll response types.
+const (
+	WorkTypeTask         = "task"
+	WorkTypeEpic         = "epic"
+	WorkTypeSetup        = "setup"
+	WorkTypeSetupReview  = "setup-review"
+	WorkTypeConversation = "conversation"
+	WorkTypeStop         = "stop"
+)
+
+// PollResponse is the discriminated union returned by the agent poll
+// endpoint. Type says which of the work item fields is set.
+type PollResponse struct {
+	Type         string            `json:"type"` // "task", "epic", "setup", "setup-review", "conversation", or "stop"
+	Task         *PollTask         `json:"task,omitempty"`
+	Epic         *PollEpic         `json:"epic,omitempty"`
+	Setup        *Setup            `json:"setup,omitempty"`
+	Conversation *PollConversation `json:"conversation,omitempty"`
+	Stops        []StopSignal      `json:"stops,omitempty"`
+	GitHubToken  string            `json:"github_token,omitempty"`
+	RepoFullName string            `json:"repo_full_name"`
+
+	// Repo setup data (injected into agent prompts)
+	RepoSummary      string `json:"repo_summary,omitempty"`
+	RepoExpectations string `json:"repo_expectations,omitempty"`
+	RepoTechStack    string `json:"repo_tech_stack,omitempty"`
+
+	// Commit and PR conventions the repo's completion validations enforce,
+	// one per line (present when Type == "task" and the repo has any)
+	PRConventions string `json:"pr_conventions,omitempty"`
+
+	// Epic planning context relevant to the task (present when the task
+	// belongs to an epic with a planning summary)
+	EpicContext string `json:"epic_context,omitempty"`
+
+	// Planning transcript so far, for the agent to continue from (present
+	// when Type == "epic" and earlier sessions logged anything)
+	EpicTranscript string `json:"epic_transcript,omitempty"`
+
+	// Generation of the repo's cached agent workspace (present when Type ==
+	// "task"). Workers discard cached workspaces from older generations.
+	WorkspaceCacheGeneration int `json:"workspace_cache_generation,omitempty"`
+
+	// Full names of the other repos a multi-repo task changes (present when
+	// Type == "task")
+	AdditionalRepos []string `json:"additional_repos,omitempty"`
+
+	// Whether the agent runs in shadow mode (present when Type == "task"):
+	// it records its diff instead of pushing or opening pull requests.
+	ShadowMode bool `json:"shadow_mode,omitempty"`
+
+	// Change plan for the agent (present when Type == "task" and the task
+	// has a proposal): for a plan-only task the previous proposal to revise,
+	// otherwise the approved proposal to implement.
+	Proposal string `json:"proposal,omitempty"`
+
+	// Branch the task branches from and opens its PR against instead of the
+	// repo's default branch (present when the task belongs to an epic with a
+	// shared integration branch)
+	BaseBranch string `json:"base_branch,omitempty"`
+
+	// Longest the agent may run, in seconds, before the worker kills it
+	// (present when Type == "task" and the task or its repo sets a max
+	// runtime)
+	MaxRuntimeSeconds int `json:"max_runtime_seconds,omitempty"`
+
+	// Files attached to the task (present when Type == "task" and the task
+	// has attachments). Workers download them with
+	// DownloadAgentTaskAttachment and place them in the agent's workspace.
+	Attachments []Attachment `json:"attachments,omitempty"`
+
+	// Whether the worker must hold a permit from AcquirePermit while the
+	// agent runs (present when Type == "task" and the server limits agent
+	// concurrency or tokens per minute)
+	PermitRequired bool `json:"permit_required,omitempty"`
+}
+
+// PollTask holds the task fields an agent needs to run a claimed task.
+type PollTask struct {
+	ID                 string   `json:"id"`
+	Number             int      `json:"number"`
+	RepoID             string   `json:"repo_id"`
+	Title              string   `json:"title"`
+	Description        string   `json:"description"`
+	Status             string   `json:"status"`
+	Attempt            int      `json:"attempt"`
+	MaxAttempts        int      `json:"max_attempts"`
+	RetryReason        string   `json:"retry_reason,omitempty"`
+	AcceptanceCriteria []string `json:"acceptance_criteria"`
+	RetryContext       string   `json:"retry_context,omitempty"`
+	AgentStatus        string   `json:"agent_status,omitempty"`
+	CostUSD            float64  `json:"cost_usd"`
+	MaxCostUSD         float64  `json:"max_cost_usd,omitempty"`
+	SkipPR             bool     `json:"skip_pr"`
+	DraftPR            bool     `json:"draft_pr"`
+	PlanOnly           bool     `json:"plan_only"`
+	Model              string   `json:"model,omitempty"`
+}
+
+// PollEpic holds the epic fields an agent needs to run a planning session.
+type PollEpic struct {
+	ID             string          `json:"id"`
+	RepoID         string          `json:"repo_id"`
+	Title          string          `json:"title"`
+	Description    string          `json:"description"`
+	PlanningPrompt string          `json:"planning_prompt,omitempty"`
+	Model          string          `json:"model,omitempty"`
+	Feedback       *string         `json:"feedback,omitempty"`
+	FeedbackType   *string         `json:"feedback_type,omitempty"` // "replan" when an active epic's remaining work is being re-planned
+	ProposedTasks  json.RawMessage `json:"proposed_tasks,omitempty"`
+}
+
+// Setup holds the fields for a repository setup scan work item.
+type Setup struct {
+	TaskID   string `json:"task_id"`
+	RepoID   string `json:"repo_id"`
+	FullName string `json:"full_name"`
+}
+
+// PollConversation holds the conversation fields an agent needs to answer
+// the pending message.
+type PollConversation struct {
+	ID             string          `json:"id"`
+	RepoID         string          `json:"repo_id"`
+	Title          string          `json:"title"`
+	Messages       json.RawMessage `json:"messages"`
+	PendingMessage string          `json:"pending_message"`
+	Model          string          `json:"model,omitempty"`
+}
+
+// StopSignal identifies an entity that should be stopped.
+type StopSignal struct {
+	EntityType string `json:"entity_type"` // "task" or "epic"
+	EntityID   string `json:"entity_id"`
+}
+
+// PollOptions identifies the polling worker so the server can track its
+// capacity.
+type PollOptions struct {
+	WorkerID      string
+	MaxConcurrent int
+	ActiveTasks   int
+	// Labels describe the worker. Only tasks whose required labels are all
+	// in Labels are handed to it.
+	Labels []string
+}
+
+// HeartbeatResponse is the response body of the task and epic heartbeat and
+// epic cost endpoints.
+type HeartbeatResponse struct {
+	Status  string `json:"status"`
+	Stopped bool   `json:"stopped"`
+	// Nudges are user messages to deliver to the task's agent. Each nudge is
+	// returned by exactly one task heartbeat.
+	Nudges []Nudge `json:"nudges,omitempty"`
+}
+
+// TaskLogsRequest is the request body for appending task logs.
+type TaskLogsRequest struct {
+	Logs    []string `json:"logs"`
+	Attempt int      `json:"attempt"`
+}
+
+// AgentEventInput is a structured event reported by the agent.
+type AgentEventInput struct {
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// TaskEventsRequest is the request body for appending structured agent events.
+type TaskEventsRequest struct {
+	Attempt int               `json:"attempt"`
+	Events  []AgentEventInput `json:"events"`
+}
+
+// TaskCompleteRequest is the request body for completing a task.
+type TaskCompleteRequest struct {
+	// Attempt is the attempt that completed. Zero means the task's current
+	// attempt.
+	Attempt        int     `json:"attempt,omitempty"`
+	Success        bool    `json:"success"`
+	PullRequestURL string  `json:"pull_request_url,omitempty"`
+	PRNumber       int     `json:"pr_number,omitempty"`
+	BranchName     string  `json:"branch_name,omitempty"`
+	Error          string  `json:"error,omitempty"`
+	AgentStatus    string  `json:"agent_status,omitempty"`
+	CostUSD        float64 `json:"cost_usd,omitempty"`
+	NoChanges      bool    `json:"no_changes,omitempty"`
+	Retryable      bool    `json:"retryable,omitempty"`
+	// PullRequests are the pull requests opened in a multi-repo task's
+	// additional repos. The primary repo's PR is PullRequestURL/PRNumber.
+	PullRequests []CompletedPullRequest `json:"pull_requests,omitempty"`
+	// ShadowDiff is the diff a shadow-mode run produced. Nothing was pushed,
+	// so the server keeps it for review in place of a pull request.
+	ShadowDiff string `json:"shadow_diff,omitempty"`
+	// Proposal is the change plan a plan-only run produced. The server sets
+	// its attempt and timestamps.
+	Proposal *Proposal `json:"proposal,omitempty"`
+	// MaxRuntimeExceeded reports that the worker killed the agent because
+	// it ran past the task's max runtime.
+	MaxRuntimeExceeded bool `json:"max_runtime_exceeded,omitempty"`
+	// BudgetExceeded reports that the worker stopped the agent because its
+	// metered API cost reached the task's max cost.
+	BudgetExceeded bool `json:"budget_exceeded,omitempty"`
+}
+
+// CompletedPullRequest is a pull request the agent opened in one of a
+// multi-repo task's additional repos.
+type CompletedPullRequest struct {
+	Repo   string `json:"repo"` // owner/name
+	URL    string `json:"url"`
+	Number int    `json:"number"`
+}
+
+// EpicCostRequest is the request body for reporting epic planning cost.
+type EpicCostRequest struct {
+	CostUSD float64 `json:"cost_usd"`
+}
+
+// SessionLogRequest is the request body for appending epic session log entries.
+type SessionLogRequest struct {
+	Lines []string `json:"lines"`
+}
+
+// ConversationCompleteRequest is the request body for completing a
+// conversation response.
+type ConversationCompleteRequest struct {
+	Success  bool   `json:"success"`
+	Response string `json:"response,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ConversationLogsRequest is the request body for appending conversation logs.
+type ConversationLogsRequest struct {
+	Lines []string `json:"lines"`
+}
+
+// DrainRequest is the request body for draining a worker on shutdown.
+type DrainRequest struct {
+	WorkerID string `json:"worker_id"`
+	// TaskIDs are the in-flight tasks the worker abandoned.
+	TaskIDs []string `json:"task_ids"`
+}
+
+// DrainResponse is the response body for the drain endpoint.
+type DrainResponse struct {
+	// Rescheduled are the abandoned tasks returned to the queue. Tasks that
+	// were no longer running are left out.
+	Rescheduled []string `json:"rescheduled"`
+}
+
+// RegisterWorkerRequest is the request body for registering a worker.
+type RegisterWorkerRequest struct {
+	WorkerID           string   `json:"worker_id"`
+	Hostname           string   `json:"hostname,omitempty"`
+	Version            string   `json:"version,omitempty"`
+	MaxConcurrentTasks int      `json:"max_concurrent_tasks"`
+	Labels             []string `json:"labels,omitempty"`
+}
+
+// WorkerHeartbeatRequest is the request body for a worker heartbeat.
+type WorkerHeartbeatRequest struct {
+	// ActiveTaskIDs are the tasks the worker is running. If the worker stops
+	// sending heartbeats, the server releases them for other workers.
+	ActiveTaskIDs []string `json:"active_task_ids"`
+}
+
+// PermitRequest is the request body for acquiring an agent permit.
+type PermitRequest struct {
+	WorkerID string `json:"worker_id"`
+	TaskID   string `json:"task_id,omitempty"`
+	Model    string `json:"model,omitempty"`
+}
+
+// Permit allows a worker to run one agent. The worker renews it with
+// RenewPermit before ExpiresAt and releases it with ReleasePermit when the
+// agent finishes.
+type Permit struct {
+	ID        string    `json:"id"`
+	WorkerID  string    `json:"worker_id,omitempty"`
+	TaskID    string    `json:"task_id,omitempty"`
+	Model     string    `json:"model,omitempty"`
+	GrantedAt time.Time `json:"granted_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// PermitResponse is the response body for acquiring an agent permit. When
+// it isn't granted, Reason names the limit that was reached and the worker
+// asks again after RetryAfterMs. A grant without a Permit means the server
+// doesn't enforce limits and there is nothing to hold.
+type PermitResponse struct {
+	Granted      bool    `json:"granted"`
+	Permit       *Permit `json:"permit,omitempty"`
+	Reason       string  `json:"reason,omitempty"`
+	RetryAfterMs int64   `json:"retry_after_ms,omitempty"`
+}
+
+// Poll long-polls for the next work item. It returns nil when no work
+// became available before the server's poll timeout.
+func (c *Client) Poll(ctx context.Context, opts PollOptions) (*PollResponse, error) {
+	var res PollResponse
+	status, err := c.do(ctx, http.MethodGet, "/agent/poll", opts.query(), nil, &res)
+	if err != nil || status == http.StatusNoContent {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// PollBatch long-polls for up to max work items, which the server claims
+// together. It returns nil when no work became available before the server's
+// poll timeout.
+func (c *Client) PollBatch(ctx context.Context, opts PollOptions, max int) ([]*PollResponse, error) {
+	query := opts.query()
+	query.Set("max", strconv.Itoa(max))
+	var res []*PollResponse
+	status, err := c.do(ctx, http.MethodGet, "/agent/poll", query, nil, &res)
+	if err != nil || status == http.StatusNoContent {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (o PollOptions) query() url.Values {
+	query := url.Values{
+		"worker_id":      {o.WorkerID},
+		"max_concurrent": {strconv.Itoa(o.MaxConcurrent)},
+		"active_tasks":   {strconv.Itoa(o.ActiveTasks)},
+	}
+	if len(o.Labels) > 0 {
+		query.Set("labels", strings.Join(o.Labels, ","))
+	}
+	return query
+}
+
+// PollStops long-polls for stop signals only. It returns nil when no stop
+// signal arrived before the server's poll timeout.
+func (c *Client) PollStops(ctx context.Context) ([]StopSignal, error) {
+	var res PollResponse
+	status, err := c.do(ctx, http.MethodGet, "/agent/poll", url.Values{"accept": {WorkTypeStop}}, nil, &res)
+	if err != nil || status == http.StatusNoContent {
+		return nil, err
+	}
+	return res.Stops, nil
+}
+
+// completeIdempotencyKey is the idempotency key of every task completion, so
+// an attempt's outcome is applied once.
+const completeIdempotencyKey = "complete"
+
+// AppendTaskLogs appends log lines to a task attempt. Each call is sent with
+// its own idempotency key, so a retried call appends its lines once.
+func (c *Client) AppendTaskLogs(ctx context.Context, taskID string, req TaskLogsRequest) error {
+	key, err := newIdempotencyKey()
+	if err != nil {
+		return err
+	}
+	return c.sendNoContentOnce(ctx, http.MethodPost, "/agent/tasks/"+pathEscape(taskID)+"/logs", key, req)
+}
+
+// TaskLogWriter sends a task attempt's log lines to the server as the agent
+// writes them. It is safe for concurrent use.
+type TaskLogWriter interface {
+	// Write appends lines to the attempt's logs.
+	Write(lines []string) error
+	// Close flushes anything still in flight and releases the writer.
+	Close() error
+}
+
+// OpenTaskLogs returns a writer for a task attempt's log lines. Each Write
+// is one AppendTaskLogs call.
+func (c *Client) OpenTaskLogs(ctx context.Context, taskID string, attempt int) TaskLogWriter {
+	return &restTaskLogs{ctx: ctx, c: c, taskID: taskID, attempt: attempt}
+}
+
+// restTaskLogs is the TaskLogWriter of Client.
+type restTaskLogs struct {
+	ctx     context.Context
+	c       *Client
+	taskID  string
+	attempt int
+}
+
+func (l *restTaskLogs) Write(lines []string) error {
+	return l.c.AppendTaskLogs(l.ctx, l.taskID, TaskLogsRequest{Logs: lines, Attempt: l.attempt})
+}
+
+func (l *restTaskLogs) Close() error { return nil }
+
+// AppendTaskEvents appends structured agent events to a task attempt.
+func (c *Client) AppendTaskEvents(ctx context.Context, taskID string, req TaskEventsRequest) error {
+	return c.sendNoContent(ctx, http.MethodPost, "/agent/tasks/"+pathEscape(taskID)+"/events", req)
+}
+
+// TaskHeartbeat keeps a claimed task alive. The response reports whether the
+// task was stopped and the agent should abort.
+func (c *Client) TaskHeartbeat(ctx context.Context, taskID string) (HeartbeatResponse, error) {
+	return send[HeartbeatResponse](ctx, c, http.MethodPost, "/agent/tasks/"+pathEscape(taskID)+"/heartbeat", nil)
+}
+
+// DownloadAgentTaskAttachment returns the contents of one of a claimed
+// task's attachments.
+func (c *Client) DownloadAgentTaskAttachment(ctx context.Context, taskID string, attachmentID int64) ([]byte, error) {
+	return get[[]byte](ctx, c, "/agent/tasks/"+pathEscape(taskID)+"/attachments/"+strconv.FormatInt(attachmentID, 10), nil)
+}
+
+// UploadTaskArtifact records a file the agent produced during an attempt of
+// a claimed task. The name is the file's path relative to the agent's
+// artifacts directory.
+func (c *Client) UploadTaskArtifact(ctx context.Context, taskID string, attempt int, name string, data []byte) (*Artifact, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	if err := w.WriteField("attempt", strconv.Itoa(attempt)); err != nil {
+		return nil, err
+	}
+	if err := w.WriteField("name", name); err != nil {
+		return nil, err
+	}
+	part, err := w.CreateFormFile("file", path.Base(name))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, "/agent/tasks/"+pathEscape(taskID)+"/artifacts", nil, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, decodeError(resp)
+	}
+	var artifact Artifact
+	if err := json.NewDecoder(resp.Body).Decode(&envelope{Data: &artifact}); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &artifact, nil
+}
+
+// CompleteTask reports the outcome of a task attempt. The server applies the
+// first outcome reported for req.Attempt and ignores any sent after it.
+func (c *Client) CompleteTask(ctx context.Context, taskID string, req TaskCompleteRequest) error {
+	return c.sendNoContentOnce(ctx, http.MethodPost, "/agent/tasks/"+pathEscape(taskID)+"/complete", completeIdempotencyKey, req)
+}
+
+// EpicHeartbeat keeps a claimed epic planning session alive. The response
+// reports whether planning was stopped and the agent should abort.
+func (c *Client) EpicHeartbeat(ctx context.Context, epicID string) (HeartbeatResponse, error) {
+	return send[HeartbeatResponse](ctx, c, http.MethodPost, "/agent/epics/"+pathEscape(epicID)+"/heartbeat", nil)
+}
+
+// AppendEpicLogs appends lines to an epic's planning session log.
+func (c *Client) AppendEpicLogs(ctx context.Context, epicID string, lines []string) error {
+	return c.sendNoContent(ctx, http.MethodPost, "/agent/epics/"+pathEscape(epicID)+"/logs", SessionLogRequest{Lines: lines})
+}
+
+// ReportEpicCost adds planning cost to an epic. The response reports whether
+// the cost exceeded the epic's budget and planning was stopped.
+func (c *Client) ReportEpicCost(ctx context.Context, epicID string, costUSD float64) (HeartbeatResponse, error) {
+	return send[HeartbeatResponse](ctx, c, http.MethodPost, "/agent/epics/"+pathEscape(epicID)+"/cost", EpicCostRequest{CostUSD: costUSD})
+}
+
+// ConversationHeartbeat keeps a claimed conversation alive.
+func (c *Client) ConversationHeartbeat(ctx context.Context, conversationID string) error {
+	return c.sendNoContent(ctx, http.MethodPost, "/agent/conversations/"+pathEscape(conversationID)+"/heartbeat", nil)
+}
+
+// CompleteConversation reports the agent's response to a conversation's
+// pending message.
+func (c *Client) CompleteConversation(ctx context.Context, conversationID string, req ConversationCompleteRequest) error {
+	return c.sendNoContent(ctx, http.MethodPost, "/agent/conversations/"+pathEscape(conversationID)+"/complete", req)
+}
+
+// AppendConversationLogs appends log lines to a conversation response.
+func (c *Client) AppendConversationLogs(ctx context.Context, conversationID string, lines []string) error {
+	return c.sendNoContent(ctx, http.MethodPost, "/agent/conversations/"+pathEscape(conversationID)+"/logs", ConversationLogsRequest{Lines: lines})
+}
+
+// SetupHeartbeat keeps a claimed repo setup scan alive.
+func (c *Client) SetupHeartbeat(ctx context.Context, repoID string) error {
+	return c.sendNoContent(ctx, http.MethodPost, "/agent/repos/"+pathEscape(repoID)+"/setup-heartbeat", nil)
+}
+
+// RegisterWorker registers a worker with the server so it shows up in the
+// fleet and its tasks can be released if it disappears.
+func (c *Client) RegisterWorker(ctx context.Context, req RegisterWorkerRequest) error {
+	return c.sendNoContent(ctx, http.MethodPost, "/agent/workers", req)
+}
+
+// WorkerHeartbeat reports that a registered worker is alive and which tasks
+// it is running. It returns a not found error (see IsNotFound) when the
+// server doesn't know the worker, which should then register again.
+func (c *Client) WorkerHeartbeat(ctx context.Context, workerID string, req WorkerHeartbeatRequest) error {
+	return c.sendNoContent(ctx, http.MethodPost, "/agent/workers/"+pathEscape(workerID)+"/heartbeat", req)
+}
+
+// AcquirePermit asks for a permit to run an agent within the server's
+// global and per-model limits.
+func (c *Client) AcquirePermit(ctx context.Context, req PermitRequest) (PermitResponse, error) {
+	return send[PermitResponse](ctx, c, http.MethodPost, "/agent/permits", req)
+}
+
+// RenewPermit extends a held permit's expiry. It returns a not found error
+// (see IsNotFound) when the permit already expired.
+func (c *Client) RenewPermit(ctx context.Context, permitID string) (Permit, error) {
+	return send[Permit](ctx, c, http.MethodPut, "/agent/permits/"+pathEscape(permitID), nil)
+}
+
+// ReleasePermit returns a permit once its agent finished.
+func (c *Client) ReleasePermit(ctx context.Context, permitID string) error {
+	return c.sendNoContent(ctx, http.MethodDelete, "/agent/permits/"+pathEscape(permitID), nil)
+}
+
+// Drain reports that a worker is shutting down and hands back the tasks it
+// abandoned, so the server can reschedule them without waiting for their
+// heartbeats to time out.
+func (c *Client) Drain(ctx context.Context, req DrainRequest) (DrainResponse, error) {
+	return send[DrainResponse](ctx, c, http.MethodPost, "/agent/drain", req)
+}
@@ -0,0 +1,371 @@
+package verveclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/vervesh/verve/pkg/agentpb"
+)
+
+// agentServiceConfig retries completions the server could not be reached
+// for. The server applies a completion once per idempotency key, so a retry
+// can't apply it twice.
+const agentServiceConfig = `{
+	"methodConfig": [{
+		"name": [{"service": "verve.agent.v1.AgentService", "method": "Complete"}],
+		"retryPolicy": {
+			"maxAttempts": 4,
+			"initialBackoff": "0.5s",
+			"maxBackoff": "30s",
+			"backoffMultiplier": 2,
+			"retryableStatusCodes": ["UNAVAILABLE"]
+		}
+	}]
+}`
+
+// AgentGRPCClient is a client for the agent plane's gRPC service. It covers
+// the worker's hot path (polls, task logs, task heartbeats and completions)
+// with the same types as the REST methods of Client; everything else a
+// worker does stays on Client. It is safe for concurrent use.
+type AgentGRPCClient struct {
+	conn   *grpc.ClientConn
+	client agentpb.AgentServiceClient
+}
+
+// NewAgentGRPCClient creates a client for the agent gRPC service at addr
+// (host:port). The connection is made lazily. It is not encrypted unless
+// opts carry transport credentials such as those of AgentGRPCTLS; without
+// them GitHub tokens handed out by polls cross the network in plaintext.
+func NewAgentGRPCClient(addr string, opts ...grpc.DialOption) (*AgentGRPCClient, error) {
+	opts = append([]grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(agentServiceConfig),
+	}, opts...)
+	conn, err := grpc.NewClient(addr, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("create agent gRPC client: %w", err)
+	}
+	return &AgentGRPCClient{conn: conn, client: agentpb.NewAgentServiceClient(conn)}, nil
+}
+
+// AgentGRPCTLS returns the dial option connecting NewAgentGRPCClient over
+// TLS. The server's certificate is verified against the PEM CA bundle in
+// caFile, or the system roots when caFile is empty.
+func AgentGRPCTLS(caFile string) (grpc.DialOption, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("read agent gRPC CA bundle: %w", err)
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("agent gRPC CA bundle %s has no PEM certificates", caFile)
+		}
+	}
+	return grpc.WithTransportCredentials(credentials.NewTLS(cfg)), nil
+}
+
+// Close closes the client's connection.
+func (c *AgentGRPCClient) Close() error {
+	return c.conn.Close()
+}
+
+// Poll long-polls for the next work item. It returns nil when no work
+// became available before the server's poll timeout.
+func (c *AgentGRPCClient) Poll(ctx context.Context, opts PollOptions) (*PollResponse, error) {
+	work, err := c.PollBatch(ctx, opts, 1)
+	if err != nil || len(work) == 0 {
+		return nil, err
+	}
+	return work[0], nil
+}
+
+// PollBatch long-polls for up to max work items, which the server claims
+// together. It returns nil when no work became available before the server's
+// poll timeout.
+func (c *AgentGRPCClient) PollBatch(ctx context.Context, opts PollOptions, max int) ([]*PollResponse, error) {
+	res, err := c.client.Poll(ctx, &agentpb.PollRequest{
+		WorkerId:      opts.WorkerID,
+		MaxConcurrent: int32(opts.MaxConcurrent),
+		ActiveTasks:   int32(opts.ActiveTasks),
+		Labels:        opts.Labels,
+		Max:           int32(max),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Items) == 0 {
+		return nil, nil
+	}
+	work := make([]*PollResponse, len(res.Items))
+	for i, item := range res.Items {
+		work[i] = pollResponseFromProto(item)
+	}
+	return work, nil
+}
+
+// OpenTaskLogs opens a stream of a task attempt's log lines. Each Write
+// sends its lines on the stream, and the server stores them as they arrive.
+// A broken stream is reopened by the next Write or Close, which resends the
+// lines the server may not have stored.
+func (c *AgentGRPCClient) OpenTaskLogs(ctx context.Context, taskID string, attempt int) TaskLogWriter {
+	return &grpcTaskLogs{ctx: ctx, client: c.client, taskID: taskID, attempt: attempt}
+}
+
+// TaskHeartbeat keeps a claimed task alive. The response reports whether the
+// task was stopped and the agent should abort.
+func (c *AgentGRPCClient) TaskHeartbeat(ctx context.Context, taskID string) (HeartbeatResponse, error) {
+	res, err := c.client.Heartbeat(ctx, &agentpb.HeartbeatRequest{TaskId: taskID})
+	if err != nil {
+		return HeartbeatResponse{}, err
+	}
+	out := HeartbeatResponse{Status: "ok", Stopped: res.Stopped}
+	for _, n := range res.Nudges {
+		out.Nudges = append(out.Nudges, Nudge{
+			ID:        n.Id,
+			Attempt:   int(n.Attempt),
+			Message:   n.Message,
+			CreatedAt: n.CreatedAt.AsTime(),
+		})
+	}
+	return out, nil
+}
+
+// CompleteTask reports the outcome of a task attempt. The server applies the
+// first outcome reported for req.Attempt and ignores any sent after it.
+func (c *AgentGRPCClient) CompleteTask(ctx context.Context, taskID string, req TaskCompleteRequest) error {
+	_, err := c.client.Complete(ctx, completeRequestProto(taskID, req))
+	return err
+}
+
+// maxUnackedLogBatches is how many batches a log stream carries before it is
+// closed and a new one opened. The server only confirms a stream's batches
+// were stored when the stream closes, so they are held until then.
+const maxUnackedLogBatches = 100
+
+// grpcTaskLogs is the TaskLogWriter of AgentGRPCClient.
+type grpcTaskLogs struct {
+	ctx     context.Context
+	client  agentpb.AgentServiceClient
+	taskID  string
+	attempt int
+
+	mu     sync.Mutex // Serializes sends on stream
+	stream grpc.ClientStreamingClient[agentpb.StreamLogsRequest, agentpb.StreamLogsResponse]
+	// Batches sent on stream that the server hasn't confirmed storing. Empty
+	// whenever stream is nil.
+	unacked []*agentpb.StreamLogsRequest
+}
+
+// Write sends lines on the open stream. When the stream has broken, the
+// server may not have stored the batches sent on it, so they are all resent
+// on a new one; their idempotency keys keep those it did store from being
+// appended twice. Batches that still can't be sent are dropped and reported
+// by the returned error.
+func (l *grpcTaskLogs) Write(lines []string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	key, err := newIdempotencyKey()
+	if err != nil {
+		return err
+	}
+	msg := &agentpb.StreamLogsRequest{TaskId: l.taskID, Attempt: int32(l.attempt), Lines: lines, IdempotencyKey: key}
+	l.unacked = append(l.unacked, msg)
+	if l.stream == nil {
+		err = l.open()
+	} else if err = l.stream.Send(msg); err != nil {
+		err = l.broken()
+	}
+	if err == nil && len(l.unacked) >= maxUnackedLogBatches {
+		err = l.ack()
+	}
+	if err != nil && l.ctx.Err() == nil {
+		if err = l.open(); err == nil && len(l.unacked) >= maxUnackedLogBatches {
+			err = l.ack()
+		}
+	}
+	if err != nil {
+		l.unacked = nil
+	}
+	return err
+}
+
+// open opens a stream and sends every unconfirmed batch on it. When sending
+// fails the stream is closed and its error returned.
+func (l *grpcTaskLogs) open() error {
+	stream, err := l.client.StreamLogs(l.ctx)
+	if err != nil {
+		return err
+	}
+	l.stream = stream
+	for _, msg := range l.unacked {
+		if err := stream.Send(msg); err != nil {
+			return l.broken()
+		}
+	}
+	return nil
+}
+
+// ack closes the open stream, confirming its batches were stored.
+func (l *grpcTaskLogs) ack() error {
+	_, err := l.stream.CloseAndRecv()
+	l.stream = nil
+	if err == nil {
+		l.unacked = nil
+	}
+	return err
+}
+
+// broken closes the open stream after a send on it failed. Send only reports
+// that the stream is gone; the status the server ended it with comes from
+// CloseAndRecv.
+func (l *grpcTaskLogs) broken() error {
+	_, err := l.stream.CloseAndRecv()
+	l.stream = nil
+	if err == nil {
+		err = fmt.Errorf("log stream of task %s closed by server", l.taskID)
+	}
+	return err
+}
+
+// Close confirms the open stream's batches were stored, resending them on a
+// new stream when it broke.
+func (l *grpcTaskLogs) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.stream == nil {
+		return nil
+	}
+	err := l.ack()
+	if err != nil && l.ctx.Err() == nil {
+		if err = l.open(); err == nil {
+			err = l.ack()
+		}
+	}
+	l.unacked = nil
+	return err
+}
+
+// pollResponseFromProto converts a claimed work item from its protobuf form.
+func pollResponseFromProto(item *agentpb.WorkItem) *PollResponse {
+	res := &PollResponse{
+		Type:                     item.Type,
+		GitHubToken:              item.GithubToken,
+		RepoFullName:             item.RepoFullName,
+		RepoSummary:              item.RepoSummary,
+		RepoExpectations:         item.RepoExpectations,
+		RepoTechStack:            item.RepoTechStack,
+		PRConventions:            item.PrConventions,
+		EpicContext:              item.EpicContext,
+		EpicTranscript:           item.EpicTranscript,
+		WorkspaceCacheGeneration: int(item.WorkspaceCacheGeneration),
+		AdditionalRepos:          item.AdditionalRepos,
+		ShadowMode:               item.ShadowMode,
+		Proposal:                 item.Proposal,
+		BaseBranch:               item.BaseBranch,
+		MaxRuntimeSeconds:        int(item.MaxRuntimeSeconds),
+		PermitRequired:           item.PermitRequired,
+	}
+	if t := item.Task; t != nil {
+		res.Task = &PollTask{
+			ID:                 t.Id,
+			Number:             int(t.Number),
+			RepoID:             t.RepoId,
+			Title:              t.Title,
+			Description:        t.Description,
+			Status:             t.Status,
+			Attempt:            int(t.Attempt),
+			MaxAttempts:        int(t.MaxAttempts),
+			RetryReason:        t.RetryReason,
+			AcceptanceCriteria: t.AcceptanceCriteria,
+			RetryContext:       t.RetryContext,
+			AgentStatus:        t.AgentStatus,
+			CostUSD:            t.CostUsd,
+			MaxCostUSD:         t.MaxCostUsd,
+			SkipPR:             t.SkipPr,
+			DraftPR:            t.DraftPr,
+			PlanOnly:           t.PlanOnly,
+			Model:              t.Model,
+		}
+	}
+	if e := item.Epic; e != nil {
+		res.Epic = &PollEpic{
+			ID:             e.Id,
+			RepoID:         e.RepoId,
+			Title:          e.Title,
+			Description:    e.Description,
+			PlanningPrompt: e.PlanningPrompt,
+			Model:          e.Model,
+			Feedback:       e.Feedback,
+			FeedbackType:   e.FeedbackType,
+			ProposedTasks:  json.RawMessage(e.ProposedTasksJson),
+		}
+	}
+	if s := item.Setup; s != nil {
+		res.Setup = &Setup{TaskID: s.TaskId, RepoID: s.RepoId, FullName: s.FullName}
+	}
+	if conv := item.Conversation; conv != nil {
+		res.Conversation = &PollConversation{
+			ID:             conv.Id,
+			RepoID:         conv.RepoId,
+			Title:          conv.Title,
+			Messages:       json.RawMessage(conv.MessagesJson),
+			PendingMessage: conv.PendingMessage,
+			Model:          conv.Model,
+		}
+	}
+	for _, a := range item.Attachments {
+		res.Attachments = append(res.Attachments, Attachment{
+			ID:          a.Id,
+			TaskID:      a.TaskId,
+			Filename:    a.Filename,
+			ContentType: a.ContentType,
+			SizeBytes:   a.SizeBytes,
+			UploadedBy:  a.UploadedBy,
+			CreatedAt:   a.CreatedAt.AsTime(),
+		})
+	}
+	return res
+}
+
+// completeRequestProto converts a task attempt's outcome to its protobuf
+// form.
+func completeRequestProto(taskID string, req TaskCompleteRequest) *agentpb.CompleteRequest {
+	out := &agentpb.CompleteRequest{
+		TaskId:             taskID,
+		IdempotencyKey:     completeIdempotencyKey,
+		Attempt:            int32(req.Attempt),
+		Success:            req.Success,
+		PullRequestUrl:     req.PullRequestURL,
+		PrNumber:           int64(req.PRNumber),
+		BranchName:         req.BranchName,
+		Error:              req.Error,
+		AgentStatus:        req.AgentStatus,
+		CostUsd:            req.CostUSD,
+		NoChanges:          req.NoChanges,
+		Retryable:          req.Retryable,
+		ShadowDiff:         req.ShadowDiff,
+		MaxRuntimeExceeded: req.MaxRuntimeExceeded,
+		BudgetExceeded:     req.BudgetExceeded,
+	}
+	for _, pr := range req.PullRequests {
+		out.PullRequests = append(out.PullRequests, &agentpb.CompletedPullRequest{Repo: pr.Repo, Url: pr.URL, Number: int64(pr.Number)})
+	}
+	if p := req.Proposal; p != nil {
+		out.Proposal = &agentpb.Proposal{Summary: p.Summary, Approach: p.Approach, Risks: p.Risks}
+		for _, f := range p.Files {
+			out.Proposal.Files = append(out.Proposal.Files, &agentpb.ProposalFile{Path: f.Path, Change: f.Change})
+		}
+	}
+	return out
+}
@@ -0,0 +1,376 @@
+// Package verveclient is a typed Go client for the Verve API.
+//
+// It covers the API used by people and integrations (create, update and act
+// on tasks and epics, manage repos and settings), the agent API used by
+// workers (poll for work, stream logs, heartbeat, complete), and the
+// Server-Sent Events streams for live updates.
+// The request and response types are the wire types the API server binds, so
+// the server, the worker and external integrators share one definition.
+package verveclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIPrefix is the path prefix of every API endpoint.
+const APIPrefix = "/api/v1"
+
+// IdempotencyKeyHeader is the header carrying the key the server
+// deduplicates an agent callback by. Keys are scoped to the task attempt the
+// callback reports on.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+const (
+	defaultTimeout      = 60 * time.Second
+	defaultRetryBackoff = 500 * time.Millisecond
+	maxRetryBackoff     = 30 * time.Second
+)
+
+// Client is a Verve API client. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	// streamClient is used for SSE streams, which stay open indefinitely and
+	// so must not be subject to the request timeout.
+	streamClient *http.Client
+	header       http.Header
+	retry        retryPolicy
+}
+
+// retryPolicy controls how idempotent requests are retried.
+type retryPolicy struct {
+	maxAttempts int           // Total attempts per request; 1 disables retries
+	minBackoff  time.Duration // Wait before the first retry, doubled for each further retry
+	maxBackoff  time.Duration
+}
+
+// Option configures a Client.
+type Option func(c *Client)
+
+// WithHTTPClient sets the HTTP client used for regular requests. Its timeout
+// must exceed the server's 30 second long-poll when the agent API is used.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+		streamClient := *hc
+		streamClient.Timeout = 0
+		c.streamClient = &streamClient
+	}
+}
+
+// WithBearerToken sends the token in the Authorization header of every
+// request, as required by the admin endpoints.
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		c.header.Set("Authorization", "Bearer "+token)
+	}
+}
+
+// WithHeader sets a header sent with every request.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.header.Set(key, value)
+	}
+}
+
+// WithRetries retries idempotent requests (GET, PUT and DELETE) up to
+// maxAttempts times in total when the server can't be reached or responds
+// with 429, 502, 503 or 504. Retries back off exponentially from minBackoff
+// (500ms when zero), or wait as long as the server's Retry-After header asks. POST requests are
+// never retried since they may not be safe to repeat, except the agent
+// callbacks sent with an idempotency key.
+func WithRetries(maxAttempts int, minBackoff time.Duration) Option {
+	if minBackoff <= 0 {
+		minBackoff = defaultRetryBackoff
+	}
+	return func(c *Client) {
+		c.retry = retryPolicy{maxAttempts: max(maxAttempts, 1), minBackoff: minBackoff, maxBackoff: maxRetryBackoff}
+	}
+}
+
+// NewClient creates a client for the Verve API server at baseURL
+// (e.g. "http://localhost:7400").
+func NewClient(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		httpClient:   &http.Client{Timeout: defaultTimeout},
+		streamClient: &http.Client{},
+		header:       make(http.Header),
+		retry:        retryPolicy{maxAttempts: 1},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// BaseURL returns the API server URL the client was created with.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// APIError is returned when the server responds with an unexpected status.
+type APIError struct {
+	StatusCode int
+	Message    string
+	Details    []string
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	if len(e.Details) > 0 {
+		msg += ": " + strings.Join(e.Details, "; ")
+	}
+	return fmt.Sprintf("verve api: %d %s", e.StatusCode, msg)
+}
+
+// IsNotFound reports whether err is an APIError with status 404.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict reports whether err is an APIError with status 409.
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+func hasStatus(err error, code int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == code
+}
+
+// envelope is the {"data": ...} wrapper of every JSON response. Data holds
+// a pointer to the value to decode into.
+type envelope struct {
+	Data any `json:"data"`
+}
+
+// errorEnvelope is the {"error": ...} body of an error response.
+type errorEnvelope struct {
+	Error struct {
+		Message string   `json:"message"`
+		Details []string `json:"details"`
+	} `json:"error"`
+}
+
+// get sends a GET request and decodes the response data into out.
+func get[T any](ctx context.Context, c *Client, path string, query url.Values) (T, error) {
+	var out T
+	_, err := c.do(ctx, http.MethodGet, path, query, nil, &out)
+	return out, err
+}
+
+// send sends a request with a JSON body and decodes the response data into
+// out.
+func send[T any](ctx context.Context, c *Client, method, path string, body any) (T, error) {
+	var out T
+	_, err := c.do(ctx, method, path, nil, body, &out)
+	return out, err
+}
+
+// sendNoContent sends a request with a JSON body and discards the response.
+func (c *Client) sendNoContent(ctx context.Context, method, path string, body any) error {
+	_, err := c.do(ctx, method, path, nil, body, nil)
+	return err
+}
+
+// sendNoContentOnce is sendNoContent for a request the server applies at
+// most once per idempotency key.
+func (c *Client) sendNoContentOnce(ctx context.Context, method, path, key string, body any) error {
+	_, err := c.doKeyed(ctx, method, path, nil, key, body, nil)
+	return err
+}
+
+// do sends a request to path (relative to APIPrefix). A non-nil body is sent
+// as JSON. When out is non-nil the response's data envelope is decoded into
+// it, or when out is a *[]byte the raw response body is read into it. It returns the response status, which callers use to tell 200 from 204.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) (int, error) {
+	return c.doKeyed(ctx, method, path, query, "", body, out)
+}
+
+// doKeyed is do for a request sent with an idempotency key, unless key is
+// empty. The server applies a keyed request at most once, so it is retried
+// like an idempotent one.
+func (c *Client) doKeyed(ctx context.Context, method, path string, query url.Values, key string, body, out any) (int, error) {
+	var payload []byte
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return 0, fmt.Errorf("encode request: %w", err)
+		}
+		payload = b
+	}
+
+	attempts := 1
+	if isIdempotent(method) || key != "" {
+		attempts = c.retry.maxAttempts
+	}
+	for attempt := 1; ; attempt++ {
+		status, retryAfter, err := c.doOnce(ctx, method, path, query, key, payload, out)
+		if err == nil || attempt >= attempts || !isRetryable(status, err) {
+			return status, err
+		}
+		if err := sleep(ctx, c.retry.backoff(attempt, retryAfter)); err != nil {
+			return status, err
+		}
+	}
+}
+
+// doOnce sends a single request. It returns the Retry-After delay the server
+// asked for, if any, alongside the status.
+func (c *Client) doOnce(ctx context.Context, method, path string, query url.Values, key string, payload []byte, out any) (int, time.Duration, error) {
+	var reader io.Reader = http.NoBody
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+	req, err := c.newRequest(ctx, method, path, query, reader)
+	if err != nil {
+		return 0, 0, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, parseRetryAfter(resp.Header.Get("Retry-After")), decodeError(resp)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return resp.StatusCode, 0, nil
+	}
+	if raw, ok := out.(*[]byte); ok {
+		// Non-JSON responses such as exports are returned as they are.
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return resp.StatusCode, 0, fmt.Errorf("read response: %w", err)
+		}
+		*raw = b
+		return resp.StatusCode, 0, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope{Data: out}); err != nil {
+		return resp.StatusCode, 0, fmt.Errorf("decode response: %w", err)
+	}
+	return resp.StatusCode, 0, nil
+}
+
+// newIdempotencyKey returns a random idempotency key.
+func newIdempotencyKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate idempotency key: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRetryable reports whether a failed request may succeed if sent again.
+// A request that failed without a status never reached the server or lost
+// its response; one canceled by the caller is not retried.
+func isRetryable(status int, err error) bool {
+	if status == 0 {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff returns how long to wait before retrying after the given attempt.
+func (p retryPolicy) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return min(retryAfter, p.maxBackoff)
+	}
+	d := p.minBackoff << (attempt - 1)
+	if d <= 0 || d > p.maxBackoff {
+		return p.maxBackoff
+	}
+	return d
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds. HTTP dates
+// are not used by the API server and are ignored.
+func parseRetryAfter(v string) time.Duration {
+	secs, err := strconv.Atoi(v)
+	if err != nil || secs < 0 {
+		return 0
+	}
+	return time.Duration(secs) * time.Second
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, query url.Values, body io.Reader) (*http.Request, error) {
+	u := c.baseURL + APIPrefix + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range c.header {
+		req.Header[k] = v
+	}
+	return req, nil
+}
+
+func decodeError(resp *http.Response) error {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var e errorEnvelope
+	if json.Unmarshal(b, &e) == nil && e.Error.Message != "" {
+		apiErr.Message = e.Error.Message
+		apiErr.Details = e.Error.Details
+	} else {
+		apiErr.Message = strings.TrimSpace(string(b))
+	}
+	return apiErr
+}
+
+// pathEscape escapes an ID for use as a path segment.
+func pathEscape(id string) string {
+	return url.PathEscape(id)
+}
@@ -0,0 +1,286 @@
+package verveclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vervesh/verve/pkg/verveclient"
+)
+
+func newTestServer(t *testing.T, handler http.HandlerFunc) *verveclient.Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return verveclient.NewClient(srv.URL, verveclient.WithBearerToken("secret"))
+}
+
+func TestClient_CreateTask(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/repos/repo_1/tasks", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]any{"title": "Fix bug", "description": "details"}, body)
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"data":{"id":"tsk_1","number":3,"title":"Fix bug","status":"pending"}}`)
+	})
+
+	tsk, err := client.CreateTask(context.Background(), "repo_1", verveclient.CreateTaskRequest{Title: "Fix bug", Description: "details"})
+	require.NoError(t, err)
+	assert.Equal(t, "tsk_1", tsk.ID)
+	assert.Equal(t, 3, tsk.Number)
+	assert.Equal(t, verveclient.TaskStatusPending, tsk.Status)
+}
+
+func TestClient_APIError(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"error":{"message":"task not found"}}`)
+	})
+
+	_, err := client.GetTask(context.Background(), "tsk_missing")
+	require.Error(t, err)
+	assert.True(t, verveclient.IsNotFound(err))
+	assert.False(t, verveclient.IsConflict(err))
+	assert.EqualError(t, err, "verve api: 404 task not found")
+}
+
+func TestClient_Retries(t *testing.T) {
+	t.Run("idempotent request", func(t *testing.T) {
+		calls := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls++
+			if calls < 3 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = io.WriteString(w, `{"data":[{"id":"repo_1","full_name":"owner/repo"}]}`)
+		}))
+		t.Cleanup(srv.Close)
+		client := verveclient.NewClient(srv.URL, verveclient.WithRetries(3, time.Millisecond))
+
+		repos, err := client.ListRepos(context.Background())
+		require.NoError(t, err)
+		require.Len(t, repos, 1)
+		assert.Equal(t, "owner/repo", repos[0].FullName)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("post is not retried", func(t *testing.T) {
+		calls := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		t.Cleanup(srv.Close)
+		client := verveclient.NewClient(srv.URL, verveclient.WithRetries(3, time.Millisecond))
+
+		_, err := client.CreateEpic(context.Background(), "repo_1", verveclient.CreateEpicRequest{Title: "Epic"})
+		require.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("keyed post is retried with the same key", func(t *testing.T) {
+		var keys []string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			keys = append(keys, r.Header.Get(verveclient.IdempotencyKeyHeader))
+			if len(keys) < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(srv.Close)
+		client := verveclient.NewClient(srv.URL, verveclient.WithRetries(3, time.Millisecond))
+
+		err := client.AppendTaskLogs(context.Background(), "tsk_1", verveclient.TaskLogsRequest{Logs: []string{"line"}, Attempt: 1})
+		require.NoError(t, err)
+		require.Len(t, keys, 3)
+		assert.NotEmpty(t, keys[0])
+		assert.Equal(t, keys[0], keys[1])
+		assert.Equal(t, keys[0], keys[2])
+	})
+
+	t.Run("client error is not retried", func(t *testing.T) {
+		calls := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		t.Cleanup(srv.Close)
+		client := verveclient.NewClient(srv.URL, verveclient.WithRetries(3, time.Millisecond))
+
+		_, err := client.GetEpic(context.Background(), "epc_missing")
+		assert.True(t, verveclient.IsNotFound(err))
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestClient_SaveDefaultModel(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/api/v1/settings/default-model", r.URL.Path)
+		b, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"model":"opus"}`, string(b))
+		_, _ = io.WriteString(w, `{"data":{"model":"opus","configured":true}}`)
+	})
+
+	res, err := client.SaveDefaultModel(context.Background(), verveclient.DefaultModelRequest{Model: "opus"})
+	require.NoError(t, err)
+	assert.Equal(t, "opus", res.Model)
+	assert.True(t, res.Configured)
+}
+
+func TestClient_Poll(t *testing.T) {
+	t.Run("work", func(t *testing.T) {
+		client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v1/agent/poll", r.URL.Path)
+			assert.Equal(t, "worker-1", r.URL.Query().Get("worker_id"))
+			assert.Equal(t, "2", r.URL.Query().Get("max_concurrent"))
+			assert.Equal(t, "1", r.URL.Query().Get("active_tasks"))
+			assert.Equal(t, "gpu,linux", r.URL.Query().Get("labels"))
+			_, _ = io.WriteString(w, `{"data":{"type":"task","task":{"id":"tsk_1","attempt":2},"repo_full_name":"owner/repo"}}`)
+		})
+
+		res, err := client.Poll(context.Background(), verveclient.PollOptions{WorkerID: "worker-1", MaxConcurrent: 2, ActiveTasks: 1, Labels: []string{"gpu", "linux"}})
+		require.NoError(t, err)
+		require.NotNil(t, res)
+		assert.Equal(t, verveclient.WorkTypeTask, res.Type)
+		require.NotNil(t, res.Task)
+		assert.Equal(t, "tsk_1", res.Task.ID)
+		assert.Equal(t, 2, res.Task.Attempt)
+		assert.Equal(t, "owner/repo", res.RepoFullName)
+	})
+
+	t.Run("no work", func(t *testing.T) {
+		client := newTestServer(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		res, err := client.Poll(context.Background(), verveclient.PollOptions{WorkerID: "worker-1"})
+		require.NoError(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestClient_PollBatch(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/agent/poll", r.URL.Path)
+		assert.Equal(t, "3", r.URL.Query().Get("max"))
+		assert.Equal(t, "worker-1", r.URL.Query().Get("worker_id"))
+		_, _ = io.WriteString(w, `{"data":[{"type":"task","task":{"id":"tsk_1"}},{"type":"epic","epic":{"id":"epc_1"}}]}`)
+	})
+
+	res, err := client.PollBatch(context.Background(), verveclient.PollOptions{WorkerID: "worker-1"}, 3)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	assert.Equal(t, "tsk_1", res[0].Task.ID)
+	assert.Equal(t, verveclient.WorkTypeEpic, res[1].Type)
+}
+
+func TestClient_TaskHeartbeat(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/agent/tasks/tsk_1/heartbeat", r.URL.Path)
+		_, _ = io.WriteString(w, `{"data":{"status":"ok","stopped":false,"nudges":[{"id":7,"attempt":1,"message":"use the v2 API"}]}}`)
+	})
+
+	res, err := client.TaskHeartbeat(context.Background(), "tsk_1")
+	require.NoError(t, err)
+	assert.False(t, res.Stopped)
+	require.Len(t, res.Nudges, 1)
+	assert.Equal(t, int64(7), res.Nudges[0].ID)
+	assert.Equal(t, "use the v2 API", res.Nudges[0].Message)
+}
+
+func TestClient_CompleteTask_OmitsZeroFields(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"success":false,"error":"exit code 1","retryable":true}`, string(b))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	err := client.CompleteTask(context.Background(), "tsk_1", verveclient.TaskCompleteRequest{Error: "exit code 1", Retryable: true})
+	require.NoError(t, err)
+}
+
+func TestClient_Events(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/events", r.URL.Path)
+		assert.Equal(t, "repo_1", r.URL.Query().Get("repo_id"))
+		assert.Equal(t, "41", r.Header.Get("Last-Event-ID"))
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "retry: 3000\n\n")
+		_, _ = io.WriteString(w, ": keepalive\n\n")
+		_, _ = fmt.Fprintf(w, "id: 42\nevent: task_updated\ndata: %s\n\n", `{"id":42,"type":"task_updated","repo_id":"repo_1","task":{"id":"tsk_1","status":"running"}}`)
+		_, _ = io.WriteString(w, "event: logs_done\ndata: {}\n\n")
+	})
+
+	stream, err := client.Events(context.Background(), verveclient.EventsOptions{RepoID: "repo_1", LastEventID: "41"})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	ev, err := stream.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "42", ev.ID)
+	assert.Equal(t, verveclient.EventTaskUpdated, ev.Type)
+	var event verveclient.Event
+	require.NoError(t, ev.Decode(&event))
+	require.NotNil(t, event.Task)
+	assert.Equal(t, "tsk_1", event.Task.ID)
+	assert.Equal(t, verveclient.TaskStatusRunning, event.Task.Status)
+
+	ev, err = stream.Next()
+	require.NoError(t, err)
+	assert.Equal(t, verveclient.EventLogsDone, ev.Type)
+	assert.Empty(t, ev.ID)
+	assert.Equal(t, "42", stream.LastEventID())
+
+	_, err = stream.Next()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestClient_TaskLogs_Attempt(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/tasks/tsk_1/attempts/2/logs", r.URL.Path)
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "event: logs_appended\ndata: {\"type\":\"logs_appended\",\"task_id\":\"tsk_1\",\"attempt\":2,\"logs\":[\"a\",\"b\"]}\n\n")
+	})
+
+	stream, err := client.TaskLogs(context.Background(), "tsk_1", 2)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	ev, err := stream.Next()
+	require.NoError(t, err)
+	var event verveclient.Event
+	require.NoError(t, ev.Decode(&event))
+	assert.Equal(t, 2, event.Attempt)
+	assert.Equal(t, []string{"a", "b"}, event.Logs)
+}
+
+func TestClient_ExportEpicTranscript(t *testing.T) {
+	client := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/epics/epc_1/transcript", r.URL.Path)
+		assert.Equal(t, "markdown", r.URL.Query().Get("format"))
+		w.Header().Set("Content-Type", "text/markdown")
+		_, _ = io.WriteString(w, "# Planning transcript: Billing\n")
+	})
+
+	md, err := client.ExportEpicTranscript(context.Background(), "epc_1")
+	require.NoError(t, err)
+	assert.Equal(t, "# Planning transcript: Billing\n", md)
+}
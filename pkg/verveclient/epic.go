@@ -0,0 +1,247 @@
+package verveclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Epic statuses.
+const (
+	EpicStatusDraft     = "draft"
+	EpicStatusPlanning  = "planning"
+	EpicStatusReady     = "ready"
+	EpicStatusActive    = "active"
+	EpicStatusCompleted = "completed"
+	EpicStatusClosed    = "closed"
+)
+
+// Epic is a large deliverable an agent plans into related tasks.
+type Epic struct {
+	ID              string         `json:"id"`
+	Number          int            `json:"number"`
+	RepoID          string         `json:"repo_id"`
+	Title           string         `json:"title"`
+	Description     string         `json:"description"`
+	Status          string         `json:"status"`
+	ProposedTasks   []ProposedTask `json:"proposed_tasks"`
+	TaskIDs         []string       `json:"task_ids"`
+	PlanningPrompt  string         `json:"planning_prompt,omitempty"`
+	PlanningSummary string         `json:"planning_summary,omitempty"`
+	SessionLog      []string       `json:"session_log"`
+	NotReady        bool           `json:"not_ready"`
+	Model           string         `json:"model,omitempty"`
+	CostUSD         float64        `json:"cost_usd"`
+	MaxCostUSD      float64        `json:"max_cost_usd,omitempty"`
+	SharedBranch    bool           `json:"shared_branch"`
+	PullRequestURL  string         `json:"pull_request_url,omitempty"`
+	PRNumber        int            `json:"pr_number,omitempty"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+}
+
+// ProposedTask is a task the planning agent proposed for an epic, created
+// when the epic is confirmed.
+type ProposedTask struct {
+	TempID             string        `json:"temp_id"`
+	Title              string        `json:"title"`
+	Description        string        `json:"description"`
+	DependsOnTempIDs   []string      `json:"depends_on_temp_ids,omitempty"`
+	AcceptanceCriteria []string      `json:"acceptance_criteria,omitempty"`
+	Estimate           *TaskEstimate `json:"estimate,omitempty"`
+}
+
+// TaskEstimate is the planning agent's estimate for a proposed task. The
+// upper end of the cost range becomes the task's budget when it is created.
+type TaskEstimate struct {
+	Model      string  `json:"model,omitempty"`
+	MinCostUSD float64 `json:"min_cost_usd,omitempty"`
+	MaxCostUSD float64 `json:"max_cost_usd,omitempty"`
+	Complexity string  `json:"complexity,omitempty"` // low, medium or high
+}
+
+// EpicTask summarizes a task created from an epic.
+type EpicTask struct {
+	ID     string `json:"id"`
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+}
+
+// EpicProgress reports how far an epic's tasks have got, for progress views
+// and burndown charts.
+type EpicProgress struct {
+	EpicID string `json:"epic_id"`
+	Status string `json:"status"`
+	// StatusCounts counts the epic's tasks by status. Every task status is
+	// present, with zero for statuses no task is in.
+	StatusCounts   map[string]int `json:"status_counts"`
+	TotalTasks     int            `json:"total_tasks"`
+	CompletedTasks int            `json:"completed_tasks"` // Merged or closed
+	// EstimatedRemainingTasks is the number of tasks left before the epic
+	// completes. Before the epic is confirmed it is the number of proposed
+	// tasks, which may still change.
+	EstimatedRemainingTasks int              `json:"estimated_remaining_tasks"`
+	Cost                    EpicProgressCost `json:"cost"`
+	// EstimatedCompletionAt projects the pace of completions so far over the
+	// remaining tasks. It is omitted until a task has completed and once
+	// none remain.
+	EstimatedCompletionAt *time.Time `json:"estimated_completion_at,omitempty"`
+	// Burndown has a point when the first task was created and one for each
+	// completion since, oldest first.
+	Burndown []BurndownPoint `json:"burndown"`
+}
+
+// EpicProgressCost breaks down what an epic has cost so far.
+type EpicProgressCost struct {
+	PlanningUSD float64 `json:"planning_usd"` // Spent by the planning agent
+	TasksUSD    float64 `json:"tasks_usd"`    // Spent by agents working the epic's tasks
+	SpentUSD    float64 `json:"spent_usd"`    // Planning plus tasks
+	// EstimatedTotalUSD adds to SpentUSD what it would take to bring each
+	// remaining task up to the average cost of a merged task. It equals
+	// SpentUSD until a task merges.
+	EstimatedTotalUSD float64 `json:"estimated_total_usd"`
+}
+
+// BurndownPoint is the state of an epic's tasks at a point in time.
+type BurndownPoint struct {
+	Time      time.Time `json:"time"`
+	Completed int       `json:"completed"`
+	Remaining int       `json:"remaining"`
+}
+
+// TranscriptEntry is one entry of an epic's planning transcript.
+type TranscriptEntry struct {
+	Role      string `json:"role"` // "user", "agent" or "system"
+	Content   string `json:"content"`
+	Timestamp int64  `json:"timestamp"` // Unix epoch
+}
+
+// CreateEpicRequest is the request body for creating an epic.
+type CreateEpicRequest struct {
+	Title          string  `json:"title"`
+	Description    string  `json:"description"`
+	PlanningPrompt string  `json:"planning_prompt,omitempty"`
+	Model          string  `json:"model,omitempty"`
+	MaxCostUSD     float64 `json:"max_cost_usd,omitempty"`
+}
+
+// StartPlanningRequest is the request body for starting a planning session.
+type StartPlanningRequest struct {
+	Prompt string `json:"prompt"`
+}
+
+// ResumePlanningRequest is the request body for resuming an epic's planning
+// session.
+type ResumePlanningRequest struct {
+	Message string `json:"message,omitempty"` // The user's next turn in the conversation
+}
+
+// ReplanEpicRequest is the request body for re-planning an active epic.
+type ReplanEpicRequest struct {
+	Prompt string `json:"prompt,omitempty"` // What to change about the remaining work
+}
+
+// SessionMessageRequest is the request body for sending a message in a
+// planning session.
+type SessionMessageRequest struct {
+	Message string `json:"message"`
+}
+
+// ConfirmEpicRequest is the request body for confirming an epic.
+type ConfirmEpicRequest struct {
+	NotReady     bool     `json:"not_ready,omitempty"`
+	SharedBranch bool     `json:"shared_branch,omitempty"` // Tasks branch from and merge into the epic's integration branch
+	TempIDs      []string `json:"temp_ids,omitempty"`      // Proposed tasks to confirm now; all of them when empty
+}
+
+// ListEpics lists a repo's epics.
+func (c *Client) ListEpics(ctx context.Context, repoID string) ([]Epic, error) {
+	return get[[]Epic](ctx, c, "/repos/"+pathEscape(repoID)+"/epics", nil)
+}
+
+// CreateEpic creates an epic in a repo and queues it for planning.
+func (c *Client) CreateEpic(ctx context.Context, repoID string, req CreateEpicRequest) (*Epic, error) {
+	return send[*Epic](ctx, c, http.MethodPost, "/repos/"+pathEscape(repoID)+"/epics", req)
+}
+
+// GetEpic reads an epic by ID.
+func (c *Client) GetEpic(ctx context.Context, id string) (*Epic, error) {
+	return get[*Epic](ctx, c, "/epics/"+pathEscape(id), nil)
+}
+
+// GetEpicByNumber reads an epic by its repo-scoped number.
+func (c *Client) GetEpicByNumber(ctx context.Context, repoID string, number int) (*Epic, error) {
+	return get[*Epic](ctx, c, "/repos/"+pathEscape(repoID)+"/epics/"+strconv.Itoa(number), nil)
+}
+
+// ListEpicTasks lists the tasks created from an epic.
+func (c *Client) ListEpicTasks(ctx context.Context, id string) ([]EpicTask, error) {
+	return get[[]EpicTask](ctx, c, "/epics/"+pathEscape(id)+"/tasks", nil)
+}
+
+// GetEpicProgress reports an epic's task counts, cost and burndown.
+func (c *Client) GetEpicProgress(ctx context.Context, id string) (*EpicProgress, error) {
+	return get[*EpicProgress](ctx, c, "/epics/"+pathEscape(id)+"/progress", nil)
+}
+
+// GetEpicTranscript returns an epic's planning transcript.
+func (c *Client) GetEpicTranscript(ctx context.Context, id string) ([]TranscriptEntry, error) {
+	return get[[]TranscriptEntry](ctx, c, "/epics/"+pathEscape(id)+"/transcript", nil)
+}
+
+// ExportEpicTranscript returns an epic's planning transcript as a markdown
+// document.
+func (c *Client) ExportEpicTranscript(ctx context.Context, id string) (string, error) {
+	b, err := get[[]byte](ctx, c, "/epics/"+pathEscape(id)+"/transcript", url.Values{"format": {"markdown"}})
+	return string(b), err
+}
+
+// DeleteEpic deletes an epic.
+func (c *Client) DeleteEpic(ctx context.Context, id string) error {
+	return c.sendNoContent(ctx, http.MethodDelete, "/epics/"+pathEscape(id), nil)
+}
+
+// StartEpicPlanning starts a planning session for an epic.
+func (c *Client) StartEpicPlanning(ctx context.Context, id string, req StartPlanningRequest) (*Epic, error) {
+	return c.epicAction(ctx, id, "plan", req)
+}
+
+// ResumeEpicPlanning starts a new planning session for a draft epic that
+// continues from its transcript.
+func (c *Client) ResumeEpicPlanning(ctx context.Context, id string, req ResumePlanningRequest) (*Epic, error) {
+	return c.epicAction(ctx, id, "resume", req)
+}
+
+// SendEpicMessage sends a message to the agent planning an epic.
+func (c *Client) SendEpicMessage(ctx context.Context, id string, req SessionMessageRequest) (*Epic, error) {
+	return c.epicAction(ctx, id, "session-message", req)
+}
+
+// ReplanEpic sends an active epic back to planning to adjust the work that
+// remains. Confirming the new plan amends the epic's tasks.
+func (c *Client) ReplanEpic(ctx context.Context, id string, req ReplanEpicRequest) (*Epic, error) {
+	return c.epicAction(ctx, id, "replan", req)
+}
+
+// ConfirmEpic confirms an epic's proposed tasks, or those named by
+// req.TempIDs, creating them.
+func (c *Client) ConfirmEpic(ctx context.Context, id string, req ConfirmEpicRequest) (*Epic, error) {
+	return c.epicAction(ctx, id, "confirm", req)
+}
+
+// CloseEpic closes an epic and its open tasks.
+func (c *Client) CloseEpic(ctx context.Context, id string) (*Epic, error) {
+	return c.epicAction(ctx, id, "close", nil)
+}
+
+// StopEpic stops the agent planning an epic.
+func (c *Client) StopEpic(ctx context.Context, id string) (*Epic, error) {
+	return c.epicAction(ctx, id, "stop", nil)
+}
+
+func (c *Client) epicAction(ctx context.Context, id, action string, body any) (*Epic, error) {
+	return send[*Epic](ctx, c, http.MethodPost, "/epics/"+pathEscape(id)+"/"+action, body)
+}
@@ -0,0 +1,198 @@
+package verveclient
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// SSE event types.
+const (
+	EventInit                = "init"
+	EventTaskCreated         = "task_created"
+	EventTaskUpdated         = "task_updated"
+	EventTaskDeleted         = "task_deleted"
+	EventLogsAppended        = "logs_appended"
+	EventLogsDone            = "logs_done"
+	EventRepoUpdated         = "repo_updated"
+	EventAgentEventsAppended = "agent_events_appended"
+	EventTaskProgress        = "task_progress"
+	EventTaskNudges          = "task_nudges"
+	EventTaskProvenance      = "task_provenance"
+	EventCommentCreated      = "comment_created"
+	EventCommentUpdated      = "comment_updated"
+	EventCommentDeleted      = "comment_deleted"
+)
+
+// Event is the data of a task or repo mutation event. Which fields are set
+// depends on Type.
+type Event struct {
+	ID      uint64          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	RepoID  string          `json:"repo_id,omitempty"`
+	Task    *Task           `json:"task,omitempty"`
+	TaskID  string          `json:"task_id,omitempty"`
+	Logs    []string        `json:"logs,omitempty"`
+	Attempt int             `json:"attempt,omitempty"`
+	Repo    json.RawMessage `json:"repo,omitempty"`
+
+	AgentEvents []AgentEvent      `json:"agent_events,omitempty"`
+	Progress    *TaskProgress     `json:"progress,omitempty"`
+	Nudges      []Nudge           `json:"nudges,omitempty"`
+	Provenance  *ProvenanceReview `json:"provenance,omitempty"`
+	Comment     *Comment          `json:"comment,omitempty"`
+}
+
+// SSEEvent is a single Server-Sent Event.
+type SSEEvent struct {
+	ID   string
+	Type string
+	Data []byte
+}
+
+// Decode unmarshals the event data into v. The data of an init event is a
+// []Task; the data of a logs_done event is empty; all other events decode
+// into an Event.
+func (e SSEEvent) Decode(v any) error {
+	return json.Unmarshal(e.Data, v)
+}
+
+// EventStream reads Server-Sent Events from an open stream. It is not safe
+// for concurrent use.
+type EventStream struct {
+	body   io.ReadCloser
+	reader *bufio.Reader
+	lastID string
+}
+
+// Next blocks until the next event arrives. It returns io.EOF when the server
+// closes the stream, and an error once the stream's context is done or the
+// stream is closed.
+func (s *EventStream) Next() (SSEEvent, error) {
+	var (
+		ev   SSEEvent
+		data strings.Builder
+	)
+	hasData := false
+	for {
+		line, err := s.reader.ReadString('\n')
+		if err != nil {
+			return SSEEvent{}, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" {
+			// A blank line dispatches the event; events without data (such
+			// as the retry hint) are skipped.
+			if !hasData {
+				ev = SSEEvent{}
+				continue
+			}
+			if ev.Type == "" {
+				ev.Type = "message"
+			}
+			ev.Data = []byte(data.String())
+			if ev.ID != "" {
+				s.lastID = ev.ID
+			}
+			return ev, nil
+		}
+		if strings.HasPrefix(line, ":") {
+			continue // comment
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			ev.Type = value
+		case "data":
+			if hasData {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+			hasData = true
+		case "id":
+			ev.ID = value
+		}
+	}
+}
+
+// LastEventID returns the ID of the last event read that carried one. Pass
+// it as EventsOptions.LastEventID when reconnecting to resume the stream.
+func (s *EventStream) LastEventID() string {
+	return s.lastID
+}
+
+// Close closes the stream.
+func (s *EventStream) Close() error {
+	return s.body.Close()
+}
+
+// EventsOptions configures the event stream.
+type EventsOptions struct {
+	// RepoID limits events to a single repo.
+	RepoID string
+	// LastEventID resumes a previous stream after the given event. Missed
+	// events are replayed when the server still has them; otherwise the
+	// stream starts with a fresh init event.
+	LastEventID string
+}
+
+// Events opens the task and repo event stream. The first event is init
+// (the current task list) unless a resumed stream replays missed events.
+// The stream stays open until ctx is done or Close is called.
+func (c *Client) Events(ctx context.Context, opts EventsOptions) (*EventStream, error) {
+	var query url.Values
+	if opts.RepoID != "" {
+		query = url.Values{"repo_id": {opts.RepoID}}
+	}
+	header := http.Header{}
+	if opts.LastEventID != "" {
+		header.Set("Last-Event-ID", opts.LastEventID)
+	}
+	return c.stream(ctx, "/events", query, header)
+}
+
+// TaskLogs opens a task's log stream. The stored logs arrive first as
+// logs_appended events, followed by logs_done; new logs are streamed as they
+// are appended. When attempt is non-zero only that attempt's logs are
+// streamed.
+func (c *Client) TaskLogs(ctx context.Context, taskID string, attempt int) (*EventStream, error) {
+	path := "/tasks/" + pathEscape(taskID) + "/logs"
+	if attempt > 0 {
+		path = "/tasks/" + pathEscape(taskID) + "/attempts/" + strconv.Itoa(attempt) + "/logs"
+	}
+	return c.stream(ctx, path, nil, nil)
+}
+
+func (c *Client) stream(ctx context.Context, path string, query url.Values, header http.Header) (*EventStream, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, path, query, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	resp, err := c.streamClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer func() { _ = resp.Body.Close() }()
+		return nil, decodeError(resp)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("unexpected content type %q", ct)
+	}
+	return &EventStream{body: resp.Body, reader: bufio.NewReader(resp.Body)}, nil
+}
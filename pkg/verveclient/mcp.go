@@ -0,0 +1,34 @@
+package verveclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+)
+
+// MCP sends a JSON-RPC message to the server's MCP endpoint and returns the
+// response, or nil when the message gets none (notifications). The client
+// must be created WithBearerToken and the server's MCP token.
+func (c *Client) MCP(ctx context.Context, msg []byte) ([]byte, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/mcp", nil, bytes.NewReader(msg))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, decodeError(resp)
+	}
+	if resp.StatusCode == http.StatusAccepted {
+		return nil, nil
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,83 @@
+package verveclient
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Repo setup statuses.
+const (
+	RepoSetupPending     = "pending"
+	RepoSetupScanning    = "scanning"
+	RepoSetupNeedsSetup  = "needs_setup"
+	RepoSetupConfiguring = "configuring"
+	RepoSetupReady       = "ready"
+)
+
+// Repo is a GitHub repository tasks and epics are run against.
+type Repo struct {
+	ID                       string     `json:"id"`
+	Owner                    string     `json:"owner"`
+	Name                     string     `json:"name"`
+	FullName                 string     `json:"full_name"`
+	Summary                  string     `json:"summary"`
+	TechStack                []string   `json:"tech_stack"`
+	SetupStatus              string     `json:"setup_status"`
+	Expectations             string     `json:"expectations"`
+	SetupCompletedAt         *time.Time `json:"setup_completed_at,omitempty"`
+	ProtectedPaths           []string   `json:"protected_paths"`
+	ShadowMode               bool       `json:"shadow_mode"`
+	MaxRuntimeSeconds        int        `json:"max_runtime_seconds"`
+	RequiredLabels           []string   `json:"required_labels"`
+	IgnoredChecks            []string   `json:"ignored_checks"`
+	FallbackModels           []string   `json:"fallback_models"`
+	Paused                   bool       `json:"paused"`
+	SyncDisabled             bool       `json:"sync_disabled"`
+	SyncIntervalSeconds      int        `json:"sync_interval_seconds"`
+	ReviewSLASeconds         int        `json:"review_sla_seconds"`
+	WorkspaceCacheGeneration int        `json:"workspace_cache_generation"`
+	CreatedAt                time.Time  `json:"created_at"`
+}
+
+// AddRepoRequest is the request body for adding a repo.
+type AddRepoRequest struct {
+	FullName string `json:"full_name"` // owner/name
+}
+
+// ListRepos lists the repos added to the server.
+func (c *Client) ListRepos(ctx context.Context) ([]Repo, error) {
+	return get[[]Repo](ctx, c, "/repos", nil)
+}
+
+// AddRepo adds a GitHub repo by its full name (owner/name). The repo must be
+// set up before tasks can be created in it.
+func (c *Client) AddRepo(ctx context.Context, req AddRepoRequest) (*Repo, error) {
+	return send[*Repo](ctx, c, http.MethodPost, "/repos", req)
+}
+
+// RemoveRepo removes a repo from the server.
+func (c *Client) RemoveRepo(ctx context.Context, id string) error {
+	return c.sendNoContent(ctx, http.MethodDelete, "/repos/"+pathEscape(id), nil)
+}
+
+// GetRepoSetup reads a repo with its setup configuration.
+func (c *Client) GetRepoSetup(ctx context.Context, id string) (*Repo, error) {
+	return get[*Repo](ctx, c, "/repos/"+pathEscape(id)+"/setup", nil)
+}
+
+// SkipRepoSetup marks a repo ready without scanning it.
+func (c *Client) SkipRepoSetup(ctx context.Context, id string) (*Repo, error) {
+	return send[*Repo](ctx, c, http.MethodPost, "/repos/"+pathEscape(id)+"/setup/skip", nil)
+}
+
+// SyncRepo queues a sync of a repo's pull requests to run right away.
+func (c *Client) SyncRepo(ctx context.Context, id string) error {
+	return c.sendNoContent(ctx, http.MethodPost, "/repos/"+pathEscape(id)+"/sync", nil)
+}
+
+// InvalidateWorkspaceCache makes workers discard their cached checkout of a
+// repo before its next task.
+func (c *Client) InvalidateWorkspaceCache(ctx context.Context, id string) (*Repo, error) {
+	return send[*Repo](ctx, c, http.MethodDelete, "/repos/"+pathEscape(id)+"/workspace-cache", nil)
+}
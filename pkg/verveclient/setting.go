@@ -0,0 +1,69 @@
+package verveclient
+
+import (
+	"context"
+	"net/http"
+)
+
+// GitHubTokenStatus reports whether the server has a GitHub token.
+type GitHubTokenStatus struct {
+	Configured  bool `json:"configured"`
+	FineGrained bool `json:"fine_grained,omitempty"`
+}
+
+// SaveGitHubTokenRequest is the request body for saving the GitHub token.
+type SaveGitHubTokenRequest struct {
+	Token string `json:"token"`
+}
+
+// DefaultModel is the model used for tasks and epics that don't set one.
+type DefaultModel struct {
+	Model      string `json:"model"`
+	Configured bool   `json:"configured"`
+}
+
+// DefaultModelRequest is the request body for setting the default model.
+type DefaultModelRequest struct {
+	Model string `json:"model"`
+}
+
+// ModelOption is a model tasks and epics can run with.
+type ModelOption struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+}
+
+// GetGitHubTokenStatus reports whether a GitHub token is configured.
+func (c *Client) GetGitHubTokenStatus(ctx context.Context) (*GitHubTokenStatus, error) {
+	return get[*GitHubTokenStatus](ctx, c, "/settings/github-token", nil)
+}
+
+// SaveGitHubToken saves the GitHub token used for every repo.
+func (c *Client) SaveGitHubToken(ctx context.Context, req SaveGitHubTokenRequest) error {
+	return c.sendNoContent(ctx, http.MethodPut, "/settings/github-token", req)
+}
+
+// DeleteGitHubToken removes the GitHub token.
+func (c *Client) DeleteGitHubToken(ctx context.Context) error {
+	return c.sendNoContent(ctx, http.MethodDelete, "/settings/github-token", nil)
+}
+
+// GetDefaultModel reads the default model.
+func (c *Client) GetDefaultModel(ctx context.Context) (*DefaultModel, error) {
+	return get[*DefaultModel](ctx, c, "/settings/default-model", nil)
+}
+
+// SaveDefaultModel sets the default model.
+func (c *Client) SaveDefaultModel(ctx context.Context, req DefaultModelRequest) (*DefaultModel, error) {
+	return send[*DefaultModel](ctx, c, http.MethodPut, "/settings/default-model", req)
+}
+
+// DeleteDefaultModel clears the default model.
+func (c *Client) DeleteDefaultModel(ctx context.Context) error {
+	return c.sendNoContent(ctx, http.MethodDelete, "/settings/default-model", nil)
+}
+
+// ListModels lists the models the server offers.
+func (c *Client) ListModels(ctx context.Context) ([]ModelOption, error) {
+	return get[[]ModelOption](ctx, c, "/settings/models", nil)
+}
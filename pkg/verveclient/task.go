@@ -0,0 +1,699 @@
+package verveclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Task statuses.
+const (
+	TaskStatusPending = "pending"
+	TaskStatusRunning = "running"
+	TaskStatusReview  = "review"
+	TaskStatusMerged  = "merged"
+	TaskStatusClosed  = "closed"
+	TaskStatusFailed  = "failed"
+)
+
+// Task is a unit of work dispatched to an agent.
+type Task struct {
+	ID                  string        `json:"id"`
+	Number              int           `json:"number"`
+	RepoID              string        `json:"repo_id"`
+	AdditionalRepoIDs   []string      `json:"additional_repo_ids,omitempty"`
+	Type                string        `json:"type"`
+	Title               string        `json:"title"`
+	Description         string        `json:"description"`
+	Status              string        `json:"status"`
+	Logs                []string      `json:"logs"`
+	PullRequestURL      string        `json:"pull_request_url,omitempty"`
+	PRNumber            int           `json:"pr_number,omitempty"`
+	PullRequests        []PullRequest `json:"pull_requests,omitempty"`
+	DependsOn           []string      `json:"depends_on,omitempty"`
+	CloseReason         string        `json:"close_reason,omitempty"`
+	FailureCategory     string        `json:"failure_category,omitempty"`
+	Attempt             int           `json:"attempt"`
+	MaxAttempts         int           `json:"max_attempts"`
+	RetryReason         string        `json:"retry_reason,omitempty"`
+	AcceptanceCriteria  []string      `json:"acceptance_criteria"`
+	AgentStatus         string        `json:"agent_status,omitempty"`
+	RetryContext        string        `json:"retry_context,omitempty"`
+	ConsecutiveFailures int           `json:"consecutive_failures"`
+	CostUSD             float64       `json:"cost_usd"`
+	MaxCostUSD          float64       `json:"max_cost_usd,omitempty"`
+	SkipPR              bool          `json:"skip_pr"`
+	DraftPR             bool          `json:"draft_pr"`
+	PlanOnly            bool          `json:"plan_only"`
+	Ready               bool          `json:"ready"`
+	EpicID              string        `json:"epic_id,omitempty"`
+	StackParentID       string        `json:"stack_parent_id,omitempty"`
+	Model               string        `json:"model,omitempty"`
+	FallbackModels      []string      `json:"fallback_models,omitempty"`
+	ActiveModel         string        `json:"active_model,omitempty"`
+	BranchName          string        `json:"branch_name,omitempty"`
+	StartedAt           *time.Time    `json:"started_at,omitempty"`
+	DurationMs          *int64        `json:"duration_ms,omitempty"`
+	CommentCount        int           `json:"comment_count,omitempty"`
+	Version             int64         `json:"version"`
+	CreatedAt           time.Time     `json:"created_at"`
+	UpdatedAt           time.Time     `json:"updated_at"`
+}
+
+// PullRequest is a pull request opened in one of a multi-repo task's
+// additional repos.
+type PullRequest struct {
+	RepoID string `json:"repo_id"`
+	URL    string `json:"url"`
+	Number int    `json:"number"`
+	Merged bool   `json:"merged"`
+}
+
+// TaskAttempt is a single run of a task by an agent.
+type TaskAttempt struct {
+	Number      int        `json:"number"`
+	RetryReason string     `json:"retry_reason,omitempty"`
+	Result      string     `json:"result,omitempty"`
+	CostUSD     float64    `json:"cost_usd"`
+	StartedAt   time.Time  `json:"started_at"`
+	EndedAt     *time.Time `json:"ended_at,omitempty"`
+	Model       string     `json:"model,omitempty"`
+}
+
+// AgentEvent is a structured event an agent reported during an attempt.
+// Payload is a JSON object whose shape depends on Type.
+type AgentEvent struct {
+	ID        int64           `json:"id"`
+	Attempt   int             `json:"attempt"`
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// ProgressStep is a single step of an agent's plan. Status is "pending",
+// "in_progress" or "completed".
+type ProgressStep struct {
+	Title       string     `json:"title"`
+	Status      string     `json:"status"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// TaskProgress is the agent's latest reported plan for a task.
+type TaskProgress struct {
+	Attempt   int            `json:"attempt"`
+	Steps     []ProgressStep `json:"steps"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// Nudge is a message a user sent to the agent of a running task.
+type Nudge struct {
+	ID          int64      `json:"id"`
+	Attempt     int        `json:"attempt"`
+	Message     string     `json:"message"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+}
+
+// Note is a message an agent left for humans while working on a task, such
+// as follow-up work it discovered.
+type Note struct {
+	ID              int64     `json:"id"`
+	TaskID          string    `json:"task_id"`
+	RepoID          string    `json:"repo_id"`
+	Attempt         int       `json:"attempt"`
+	Text            string    `json:"text"`
+	CreatedAt       time.Time `json:"created_at"`
+	ConvertedTaskID string    `json:"converted_task_id,omitempty"`
+}
+
+// Comment is a message a person left on a task, optionally about one of its
+// attempts.
+type Comment struct {
+	ID        int64     `json:"id"`
+	TaskID    string    `json:"task_id"`
+	Attempt   *int      `json:"attempt,omitempty"`
+	Author    string    `json:"author"`
+	AuthorID  string    `json:"author_id,omitempty"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Attachment is a file attached to a task for its agent to read.
+type Attachment struct {
+	ID          int64     `json:"id"`
+	TaskID      string    `json:"task_id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	UploadedBy  string    `json:"uploaded_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Artifact is a file an agent produced during an attempt of a task, such as
+// a test report or screenshot.
+type Artifact struct {
+	ID          int64     `json:"id"`
+	TaskID      string    `json:"task_id"`
+	Attempt     int       `json:"attempt"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ProvenanceFinding is an addition in a task's PR that may have been copied
+// from elsewhere: a license header, a large verbatim block, or a match
+// reported by an external scanner.
+type ProvenanceFinding struct {
+	Kind   string `json:"kind"` // "license_header", "large_block" or "external"
+	Path   string `json:"path"`
+	Line   int    `json:"line,omitempty"`
+	Lines  int    `json:"lines,omitempty"`
+	Detail string `json:"detail"`
+}
+
+// ProvenanceReview is the latest license and provenance scan of a task's PR.
+// Findings must be acknowledged before the PR is cleared for merging.
+type ProvenanceReview struct {
+	Findings       []ProvenanceFinding `json:"findings"`
+	HeadSHA        string              `json:"head_sha"`
+	ScannedAt      time.Time           `json:"scanned_at"`
+	AcknowledgedAt *time.Time          `json:"acknowledged_at,omitempty"`
+}
+
+// ShadowDiff is the diff from a task's latest shadow-mode run, where the
+// agent records its changes instead of pushing or opening a pull request.
+type ShadowDiff struct {
+	Attempt   int       `json:"attempt"`
+	Diff      string    `json:"diff"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Proposal is the change plan from a task's latest plan-only run: the files
+// the agent would touch, its approach and the risks it sees.
+type Proposal struct {
+	Attempt    int            `json:"attempt"`
+	Summary    string         `json:"summary"`
+	Approach   string         `json:"approach"`
+	Files      []ProposalFile `json:"files"`
+	Risks      []string       `json:"risks"`
+	CreatedAt  time.Time      `json:"created_at"`
+	ApprovedAt *time.Time     `json:"approved_at,omitempty"`
+}
+
+// ProposalFile is a file a proposal expects to add, change or remove.
+type ProposalFile struct {
+	Path   string `json:"path"`
+	Change string `json:"change"`
+}
+
+// SelfReviewCheck is one check of a PR self-review.
+type SelfReviewCheck struct {
+	Name     string   `json:"name"` // "acceptance_criteria", "leftovers", "file_count" or "diff_size"
+	Passed   bool     `json:"passed"`
+	Detail   string   `json:"detail"`
+	Findings []string `json:"findings,omitempty"`
+}
+
+// SelfReview is the latest automated self-review of a task's PR, scored out
+// of 100.
+type SelfReview struct {
+	Score      int               `json:"score"`
+	Checks     []SelfReviewCheck `json:"checks"`
+	HeadSHA    string            `json:"head_sha"`
+	ReviewedAt time.Time         `json:"reviewed_at"`
+}
+
+// CreateTaskRequest is the request body for creating a task.
+type CreateTaskRequest struct {
+	Title              string   `json:"title"`
+	Description        string   `json:"description"`
+	DependsOn          []string `json:"depends_on,omitempty"`
+	AcceptanceCriteria []string `json:"acceptance_criteria,omitempty"`
+	MaxCostUSD         float64  `json:"max_cost_usd,omitempty"`
+	SkipPR             bool     `json:"skip_pr,omitempty"`
+	DraftPR            bool     `json:"draft_pr,omitempty"`
+	Model              string   `json:"model,omitempty"`
+	NotReady           bool     `json:"not_ready,omitempty"`
+	// MaxRuntimeSeconds caps how long an attempt may run before the worker
+	// kills it and the task fails. 0 uses the repo's default max runtime.
+	MaxRuntimeSeconds int `json:"max_runtime_seconds,omitempty"`
+	// RequiredLabels limits the task to workers that have all of these
+	// labels, on top of any labels its repo requires.
+	RequiredLabels []string `json:"required_labels,omitempty"`
+	// AdditionalRepoIDs lists other repos the task changes. The agent works
+	// on all of them and opens a pull request in each one it changes.
+	AdditionalRepoIDs []string `json:"additional_repo_ids,omitempty"`
+	// StackOn stacks the task on one of its dependencies: the task starts
+	// once the dependency has an open PR, branches off its branch, and its
+	// own PR is retargeted when the dependency's PR is merged.
+	StackOn string `json:"stack_on,omitempty"`
+	// PlanOnly makes the agent propose a change plan for approval instead of
+	// implementing the task. Approving the proposal starts the implementation.
+	PlanOnly bool `json:"plan_only,omitempty"`
+	// FallbackModels are the models the task falls back to, in order, when
+	// its agent is rate-limited or overloaded. Nil uses the repo's fallback
+	// models; an empty list disables falling back.
+	FallbackModels []string `json:"fallback_models,omitzero"`
+}
+
+// UpdateTaskRequest is the request body for updating a pending task.
+// All fields are optional — only provided fields are updated.
+type UpdateTaskRequest struct {
+	Title              *string  `json:"title,omitempty"`
+	Description        *string  `json:"description,omitempty"`
+	DependsOn          []string `json:"depends_on,omitempty"`
+	AcceptanceCriteria []string `json:"acceptance_criteria,omitempty"`
+	MaxCostUSD         *float64 `json:"max_cost_usd,omitempty"`
+	SkipPR             *bool    `json:"skip_pr,omitempty"`
+	DraftPR            *bool    `json:"draft_pr,omitempty"`
+	Model              *string  `json:"model,omitempty"`
+	NotReady           *bool    `json:"not_ready,omitempty"`
+	MaxRuntimeSeconds  *int     `json:"max_runtime_seconds,omitempty"`
+	// RequiredLabels replaces the task's required worker labels. An empty
+	// list removes the requirement.
+	RequiredLabels *[]string `json:"required_labels,omitempty"`
+	// ExpectedVersion is the version of the task the update was made from.
+	// The update fails with 409 Conflict if the task has changed since.
+	ExpectedVersion *int64 `json:"expected_version,omitempty"`
+}
+
+// CloseRequest is the request body for closing a task.
+type CloseRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// ApproveTaskRequest is the request body for approving a task's pull
+// request.
+type ApproveTaskRequest struct {
+	Body string `json:"body,omitempty"`
+}
+
+// MergeTaskRequest is the request body for merging a task's pull request.
+// MergeMethod is "merge", "squash" or "rebase"; empty uses the server's
+// default.
+type MergeTaskRequest struct {
+	MergeMethod string `json:"merge_method,omitempty"`
+}
+
+// RetryTaskRequest is the request body for retrying a failed task.
+type RetryTaskRequest struct {
+	Instructions string `json:"instructions,omitempty"`
+}
+
+// FeedbackRequest is the request body for providing feedback on a task in review.
+type FeedbackRequest struct {
+	Feedback string `json:"feedback"`
+}
+
+// NudgeRequest is the request body for sending a message to the agent of a
+// running task.
+type NudgeRequest struct {
+	Message string `json:"message"`
+}
+
+// CreateCommentRequest is the request body for commenting on a task. Author
+// is ignored when the client authenticates with a user token, whose user is
+// the author instead.
+type CreateCommentRequest struct {
+	Body    string `json:"body"`
+	Attempt *int   `json:"attempt,omitempty"`
+	Author  string `json:"author,omitempty"`
+}
+
+// UpdateCommentRequest is the request body for editing a comment on a task.
+type UpdateCommentRequest struct {
+	Body string `json:"body"`
+}
+
+// StartOverRequest is the request body for starting a task over from scratch.
+type StartOverRequest struct {
+	Title              *string  `json:"title,omitempty"`
+	Description        *string  `json:"description,omitempty"`
+	AcceptanceCriteria []string `json:"acceptance_criteria,omitempty"`
+	// ExpectedVersion is as in UpdateTaskRequest.
+	ExpectedVersion *int64 `json:"expected_version,omitempty"`
+}
+
+// MoveTaskRequest is the request body for moving a pending task to another
+// repo.
+type MoveTaskRequest struct {
+	RepoID string `json:"repo_id"`
+	// DropDependencies drops the task's dependencies on tasks of other repos
+	// and other repos' dependencies on it. Without it, a move that would
+	// leave such dependencies is rejected.
+	DropDependencies bool `json:"drop_dependencies,omitempty"`
+	// ExpectedVersion is as in UpdateTaskRequest.
+	ExpectedVersion *int64 `json:"expected_version,omitempty"`
+}
+
+// RemoveDependencyRequest is the request body for removing a dependency from a task.
+type RemoveDependencyRequest struct {
+	DependsOn string `json:"depends_on"`
+}
+
+// SetReadyRequest is the request body for toggling a task's ready state.
+type SetReadyRequest struct {
+	Ready bool `json:"ready"`
+}
+
+// BulkDeleteTasksRequest is the request body for bulk-deleting tasks.
+type BulkDeleteTasksRequest struct {
+	TaskIDs []string `json:"task_ids"`
+}
+
+// BulkRetryTasksRequest is the request body for retrying several failed tasks
+// at once.
+type BulkRetryTasksRequest struct {
+	TaskIDs []string `json:"task_ids"`
+	// Instructions are optional guidance given to the agent of every task.
+	Instructions string `json:"instructions,omitempty"`
+}
+
+// BulkRetryTasksResponse is the response body for the bulk retry endpoint.
+type BulkRetryTasksResponse struct {
+	Retried []string `json:"retried"`
+	// Skipped are the requested tasks that were not failed, so not retried.
+	Skipped []string `json:"skipped"`
+}
+
+// Bulk actions of BulkTaskAction.
+const (
+	BulkActionClose    = "close"
+	BulkActionDelete   = "delete"
+	BulkActionSetReady = "set-ready"
+	BulkActionRetry    = "retry"
+)
+
+// BulkTaskActionRequest is the request body for applying one action to
+// several of a repo's tasks at once. Exactly one of TaskIDs and Status
+// selects the tasks.
+type BulkTaskActionRequest struct {
+	// Action is one of "close", "delete", "set-ready" and "retry".
+	Action  string   `json:"action"`
+	TaskIDs []string `json:"task_ids,omitempty"`
+	// Status selects every unarchived task of the repo in the status.
+	Status string `json:"status,omitempty"`
+	// Reason is the close reason of a close.
+	Reason string `json:"reason,omitempty"`
+	// Ready is the ready flag a set-ready sets. Defaults to true.
+	Ready *bool `json:"ready,omitempty"`
+	// Instructions are optional guidance given to the agent of every task
+	// a retry retries.
+	Instructions string `json:"instructions,omitempty"`
+}
+
+// BulkTaskActionResult is the outcome of a bulk action for one task.
+type BulkTaskActionResult struct {
+	TaskID string `json:"task_id"`
+	// Result is "applied" or "skipped".
+	Result string `json:"result"`
+	// Reason is why the task was skipped.
+	Reason string `json:"reason,omitempty"`
+}
+
+// BulkTaskActionResponse is the response body for the bulk action endpoint.
+type BulkTaskActionResponse struct {
+	Results []BulkTaskActionResult `json:"results"`
+}
+
+// FailureCategoryCount is the number of failed tasks in a failure category.
+type FailureCategoryCount struct {
+	Category string `json:"category"`
+	Count    int    `json:"count"`
+}
+
+// FailedTasks is a repo's failed tasks together with how many failed in
+// each failure category.
+type FailedTasks struct {
+	Tasks      []Task                 `json:"tasks"`
+	Categories []FailureCategoryCount `json:"categories"`
+}
+
+// DiffResponse is the response body for the task diff endpoint.
+type DiffResponse struct {
+	Diff string `json:"diff"`
+}
+
+// ListTasks lists a repo's tasks.
+func (c *Client) ListTasks(ctx context.Context, repoID string) ([]Task, error) {
+	return get[[]Task](ctx, c, "/repos/"+pathEscape(repoID)+"/tasks", nil)
+}
+
+// CreateTask creates a task in a repo.
+func (c *Client) CreateTask(ctx context.Context, repoID string, req CreateTaskRequest) (*Task, error) {
+	return send[*Task](ctx, c, http.MethodPost, "/repos/"+pathEscape(repoID)+"/tasks", req)
+}
+
+// GetTask reads a task by ID.
+func (c *Client) GetTask(ctx context.Context, id string) (*Task, error) {
+	return get[*Task](ctx, c, "/tasks/"+pathEscape(id), nil)
+}
+
+// GetTaskByNumber reads a task by its repo-scoped number.
+func (c *Client) GetTaskByNumber(ctx context.Context, repoID string, number int) (*Task, error) {
+	return get[*Task](ctx, c, "/repos/"+pathEscape(repoID)+"/tasks/"+strconv.Itoa(number), nil)
+}
+
+// UpdateTask updates a pending task.
+func (c *Client) UpdateTask(ctx context.Context, id string, req UpdateTaskRequest) (*Task, error) {
+	return send[*Task](ctx, c, http.MethodPatch, "/tasks/"+pathEscape(id), req)
+}
+
+// DeleteTask deletes a task.
+func (c *Client) DeleteTask(ctx context.Context, id string) error {
+	return c.sendNoContent(ctx, http.MethodDelete, "/tasks/"+pathEscape(id), nil)
+}
+
+// BulkDeleteTasks deletes several tasks at once.
+func (c *Client) BulkDeleteTasks(ctx context.Context, ids []string) error {
+	return c.sendNoContent(ctx, http.MethodPost, "/tasks/bulk-delete", BulkDeleteTasksRequest{TaskIDs: ids})
+}
+
+// ListFailedTasks lists a repo's failed tasks, most recently failed first,
+// with counts per failure category. A non-empty category only returns the
+// tasks in that category; the counts always cover every failed task.
+func (c *Client) ListFailedTasks(ctx context.Context, repoID, category string) (*FailedTasks, error) {
+	var query url.Values
+	if category != "" {
+		query = url.Values{"category": {category}}
+	}
+	return get[*FailedTasks](ctx, c, "/repos/"+pathEscape(repoID)+"/tasks/failed", query)
+}
+
+// BulkRetryTasks retries several failed tasks at once.
+func (c *Client) BulkRetryTasks(ctx context.Context, req BulkRetryTasksRequest) (*BulkRetryTasksResponse, error) {
+	return send[*BulkRetryTasksResponse](ctx, c, http.MethodPost, "/tasks/bulk-retry", req)
+}
+
+// BulkTaskAction closes, deletes, sets ready or retries several of a repo's
+// tasks in one transaction, reporting the outcome for each.
+func (c *Client) BulkTaskAction(ctx context.Context, repoID string, req BulkTaskActionRequest) (*BulkTaskActionResponse, error) {
+	return send[*BulkTaskActionResponse](ctx, c, http.MethodPost, "/repos/"+pathEscape(repoID)+"/tasks/bulk-action", req)
+}
+
+// CloseTask closes a task with an optional reason.
+func (c *Client) CloseTask(ctx context.Context, id string, req CloseRequest) (*Task, error) {
+	return c.taskAction(ctx, id, "close", req)
+}
+
+// ApproveTask submits an approving GitHub review on the pull requests of a
+// task in review.
+func (c *Client) ApproveTask(ctx context.Context, id string, req ApproveTaskRequest) (*Task, error) {
+	return c.taskAction(ctx, id, "approve", req)
+}
+
+// MergeTask merges the pull requests of a task in review and marks the task
+// merged.
+func (c *Client) MergeTask(ctx context.Context, id string, req MergeTaskRequest) (*Task, error) {
+	return c.taskAction(ctx, id, "merge", req)
+}
+
+// StopTask stops a running task.
+func (c *Client) StopTask(ctx context.Context, id string, req CloseRequest) (*Task, error) {
+	return c.taskAction(ctx, id, "stop", req)
+}
+
+// RetryTask retries a failed task with optional instructions for the agent.
+func (c *Client) RetryTask(ctx context.Context, id string, req RetryTaskRequest) (*Task, error) {
+	return c.taskAction(ctx, id, "retry", req)
+}
+
+// StartOverTask restarts a task from scratch, optionally changing its
+// title, description and acceptance criteria.
+func (c *Client) StartOverTask(ctx context.Context, id string, req StartOverRequest) (*Task, error) {
+	return c.taskAction(ctx, id, "start-over", req)
+}
+
+// MoveTask moves a pending task filed against the wrong repo to another
+// repo.
+func (c *Client) MoveTask(ctx context.Context, id string, req MoveTaskRequest) (*Task, error) {
+	return c.taskAction(ctx, id, "move", req)
+}
+
+// FeedbackTask sends review feedback to the agent of a task in review.
+func (c *Client) FeedbackTask(ctx context.Context, id string, req FeedbackRequest) (*Task, error) {
+	return c.taskAction(ctx, id, "feedback", req)
+}
+
+// NudgeTask queues a message for the agent of a running task. The agent
+// receives it between tool calls without the run being restarted.
+func (c *Client) NudgeTask(ctx context.Context, id string, req NudgeRequest) (*Nudge, error) {
+	return send[*Nudge](ctx, c, http.MethodPost, "/tasks/"+pathEscape(id)+"/nudge", req)
+}
+
+// ListTaskNudges lists the messages sent to a task's agent, oldest first.
+func (c *Client) ListTaskNudges(ctx context.Context, id string) ([]Nudge, error) {
+	return get[[]Nudge](ctx, c, "/tasks/"+pathEscape(id)+"/nudges", nil)
+}
+
+// ListTaskComments lists a task's comments, oldest first.
+func (c *Client) ListTaskComments(ctx context.Context, id string) ([]Comment, error) {
+	return get[[]Comment](ctx, c, "/tasks/"+pathEscape(id)+"/comments", nil)
+}
+
+// CreateTaskComment comments on a task.
+func (c *Client) CreateTaskComment(ctx context.Context, id string, req CreateCommentRequest) (*Comment, error) {
+	return send[*Comment](ctx, c, http.MethodPost, "/tasks/"+pathEscape(id)+"/comments", req)
+}
+
+// UpdateTaskComment edits a comment on a task. Only the comment's author or
+// an admin can edit it.
+func (c *Client) UpdateTaskComment(ctx context.Context, id string, commentID int64, req UpdateCommentRequest) (*Comment, error) {
+	return send[*Comment](ctx, c, http.MethodPatch, "/tasks/"+pathEscape(id)+"/comments/"+strconv.FormatInt(commentID, 10), req)
+}
+
+// DeleteTaskComment deletes a comment on a task. Only the comment's author or
+// an admin can delete it.
+func (c *Client) DeleteTaskComment(ctx context.Context, id string, commentID int64) error {
+	return c.sendNoContent(ctx, http.MethodDelete, "/tasks/"+pathEscape(id)+"/comments/"+strconv.FormatInt(commentID, 10), nil)
+}
+
+// ListTaskAttachments lists a task's attachments, oldest first.
+func (c *Client) ListTaskAttachments(ctx context.Context, id string) ([]Attachment, error) {
+	return get[[]Attachment](ctx, c, "/tasks/"+pathEscape(id)+"/attachments", nil)
+}
+
+// DownloadTaskAttachment returns the contents of one of a task's
+// attachments.
+func (c *Client) DownloadTaskAttachment(ctx context.Context, id string, attachmentID int64) ([]byte, error) {
+	return get[[]byte](ctx, c, "/tasks/"+pathEscape(id)+"/attachments/"+strconv.FormatInt(attachmentID, 10), nil)
+}
+
+// DeleteTaskAttachment removes an attachment from a task.
+func (c *Client) DeleteTaskAttachment(ctx context.Context, id string, attachmentID int64) error {
+	return c.sendNoContent(ctx, http.MethodDelete, "/tasks/"+pathEscape(id)+"/attachments/"+strconv.FormatInt(attachmentID, 10), nil)
+}
+
+// ListTaskArtifacts lists the artifacts a task's agent produced, ordered by
+// attempt. When attempt is greater than zero only that attempt's artifacts
+// are listed.
+func (c *Client) ListTaskArtifacts(ctx context.Context, id string, attempt int) ([]Artifact, error) {
+	var query url.Values
+	if attempt > 0 {
+		query = url.Values{"attempt": {strconv.Itoa(attempt)}}
+	}
+	return get[[]Artifact](ctx, c, "/tasks/"+pathEscape(id)+"/artifacts", query)
+}
+
+// DownloadTaskArtifact returns the contents of one of a task's artifacts.
+func (c *Client) DownloadTaskArtifact(ctx context.Context, id string, artifactID int64) ([]byte, error) {
+	return get[[]byte](ctx, c, "/tasks/"+pathEscape(id)+"/artifacts/"+strconv.FormatInt(artifactID, 10), nil)
+}
+
+// ListNotes lists the notes agents left on a repo's tasks, newest first.
+func (c *Client) ListNotes(ctx context.Context, repoID string) ([]Note, error) {
+	return get[[]Note](ctx, c, "/repos/"+pathEscape(repoID)+"/notes", nil)
+}
+
+// ConvertNote creates a pending task for the follow-up work described by a
+// note. A note can only be converted once.
+func (c *Client) ConvertNote(ctx context.Context, noteID int64) (*Task, error) {
+	return send[*Task](ctx, c, http.MethodPost, "/notes/"+strconv.FormatInt(noteID, 10)+"/convert", nil)
+}
+
+// MoveToReview moves a failed task that has a pull request to review.
+func (c *Client) MoveToReview(ctx context.Context, id string) (*Task, error) {
+	return c.taskAction(ctx, id, "move-to-review", nil)
+}
+
+// SyncTask refreshes a task's status from its pull request.
+func (c *Client) SyncTask(ctx context.Context, id string) (*Task, error) {
+	return c.taskAction(ctx, id, "sync", nil)
+}
+
+// SetTaskReady marks a task ready or not ready to be picked up.
+func (c *Client) SetTaskReady(ctx context.Context, id string, ready bool) (*Task, error) {
+	return send[*Task](ctx, c, http.MethodPut, "/tasks/"+pathEscape(id)+"/ready", SetReadyRequest{Ready: ready})
+}
+
+// RemoveDependency removes a dependency from a task.
+func (c *Client) RemoveDependency(ctx context.Context, id, dependsOn string) (*Task, error) {
+	return send[*Task](ctx, c, http.MethodDelete, "/tasks/"+pathEscape(id)+"/dependency", RemoveDependencyRequest{DependsOn: dependsOn})
+}
+
+// ListTaskAttempts lists a task's attempts, oldest first.
+func (c *Client) ListTaskAttempts(ctx context.Context, id string) ([]TaskAttempt, error) {
+	return get[[]TaskAttempt](ctx, c, "/tasks/"+pathEscape(id)+"/attempts", nil)
+}
+
+// ListTaskEvents lists the structured events the agent reported for a task,
+// oldest first. When attempt is non-zero only that attempt's events are
+// returned.
+func (c *Client) ListTaskEvents(ctx context.Context, id string, attempt int) ([]AgentEvent, error) {
+	var query url.Values
+	if attempt > 0 {
+		query = url.Values{"attempt": {strconv.Itoa(attempt)}}
+	}
+	return get[[]AgentEvent](ctx, c, "/tasks/"+pathEscape(id)+"/events", query)
+}
+
+// GetTaskProgress reads the agent's latest reported plan for a task.
+func (c *Client) GetTaskProgress(ctx context.Context, id string) (*TaskProgress, error) {
+	return get[*TaskProgress](ctx, c, "/tasks/"+pathEscape(id)+"/progress", nil)
+}
+
+// GetTaskProvenance reads the provenance review of a task's PR. It returns
+// nil if the PR has not been scanned.
+func (c *Client) GetTaskProvenance(ctx context.Context, id string) (*ProvenanceReview, error) {
+	return get[*ProvenanceReview](ctx, c, "/tasks/"+pathEscape(id)+"/provenance", nil)
+}
+
+// AcknowledgeTaskProvenance records that the provenance findings of a task's
+// PR were reviewed, clearing the PR for merging.
+func (c *Client) AcknowledgeTaskProvenance(ctx context.Context, id string) (*ProvenanceReview, error) {
+	return send[*ProvenanceReview](ctx, c, http.MethodPost, "/tasks/"+pathEscape(id)+"/provenance/acknowledge", nil)
+}
+
+// GetTaskSelfReview reads the self-review of a task's PR. It returns nil if
+// the PR has not been reviewed.
+func (c *Client) GetTaskSelfReview(ctx context.Context, id string) (*SelfReview, error) {
+	return get[*SelfReview](ctx, c, "/tasks/"+pathEscape(id)+"/self-review", nil)
+}
+
+// GetTaskProposal reads the change proposal of a plan-only task. It returns
+// nil if the task has not run in plan-only mode.
+func (c *Client) GetTaskProposal(ctx context.Context, id string) (*Proposal, error) {
+	return get[*Proposal](ctx, c, "/tasks/"+pathEscape(id)+"/proposal", nil)
+}
+
+// ApproveTaskProposal approves a plan-only task's proposal and sends the task
+// back to the agent to implement it.
+func (c *Client) ApproveTaskProposal(ctx context.Context, id string) (*Task, error) {
+	return c.taskAction(ctx, id, "proposal/approve", nil)
+}
+
+// GetTaskDiff reads the diff of a task's branch against its base.
+func (c *Client) GetTaskDiff(ctx context.Context, id string) (string, error) {
+	res, err := get[DiffResponse](ctx, c, "/tasks/"+pathEscape(id)+"/diff", nil)
+	return res.Diff, err
+}
+
+func (c *Client) taskAction(ctx context.Context, id, action string, body any) (*Task, error) {
+	return send[*Task](ctx, c, http.MethodPost, "/tasks/"+pathEscape(id)+"/"+action, body)
+}
@@ -14,6 +14,9 @@ const MOCK_REPO = {
 	has_readme: true,
 	expectations: '',
 	setup_completed_at: '2025-01-15T10:05:00Z',
+	protected_paths: [] as string[],
+	shadow_mode: false,
+	workspace_cache_generation: 0,
 	created_at: '2025-01-15T10:00:00Z'
 };
 
@@ -56,6 +59,59 @@ const MOCK_REPO_NEEDS_SETUP = {
 	setup_completed_at: undefined
 };
 
+// Repo variant: ready with a CI workflow dispatched for agent PRs
+const MOCK_REPO_WITH_CI_WORKFLOW = {
+	...MOCK_REPO_NEEDS_SETUP,
+	setup_status: 'ready',
+	setup_completed_at: '2025-01-15T10:05:00Z',
+	ci_workflow: {
+		file: 'full-tests.yml',
+		inputs: { suite: 'all', browsers: 'chromium,firefox' }
+	}
+};
+
+// Repo variant: ready with paths agents must not change
+const MOCK_REPO_WITH_PROTECTED_PATHS = {
+	...MOCK_REPO_NEEDS_SETUP,
+	setup_status: 'ready',
+	setup_completed_at: '2025-01-15T10:05:00Z',
+	protected_paths: ['.github/workflows', 'deploy/**', '.env*']
+};
+
+// Repo variant: ready with agents running in shadow mode
+const MOCK_REPO_WITH_SHADOW_MODE = {
+	...MOCK_REPO_NEEDS_SETUP,
+	setup_status: 'ready',
+	setup_completed_at: '2025-01-15T10:05:00Z',
+	shadow_mode: true
+};
+
+// Repo variant: ready with PR conventions enforced on agent completion
+const MOCK_REPO_WITH_COMPLETION_VALIDATIONS = {
+	...MOCK_REPO_NEEDS_SETUP,
+	setup_status: 'ready',
+	setup_completed_at: '2025-01-15T10:05:00Z',
+	completion_validations: {
+		title_pattern: '^(feat|fix|chore): ',
+		require_task_id: true,
+		branch_pattern: '^verve/task-\\d+$',
+		max_commits: 3,
+		conventional_commits: true
+	}
+};
+
+// Repo variant: ready with a retry policy overriding the instance-wide one
+const MOCK_REPO_WITH_RETRY_POLICY = {
+	...MOCK_REPO_NEEDS_SETUP,
+	setup_status: 'ready',
+	setup_completed_at: '2025-01-15T10:05:00Z',
+	retry_policy: {
+		max_attempts: 8,
+		category_limits: { ci_failure: 4, 'ci_failure:lint': 2 },
+		rate_limit_backoff_seconds: [60, 300, 900]
+	}
+};
+
 // Repo variant: scan complete but no tech stack detected (empty repo scenario)
 const MOCK_REPO_NEEDS_SETUP_EMPTY = {
 	...MOCK_REPO,
@@ -88,6 +144,14 @@ const MOCK_REPO_WITH_EXPECTATIONS = {
 		'## Code Quality\n\n- Follow **ESLint** rules strictly\n- Use `Prettier` for formatting\n- Prefer `const` over `let` where possible\n\n## Testing\n\n- Write **unit tests** for all new functions\n- Use [Vitest](https://vitest.dev) as the test runner\n- Aim for **80% code coverage**\n\n## Architecture\n\nFollow a layered architecture:\n\n1. **Presentation layer** — Svelte components\n2. **Business logic** — TypeScript services\n3. **Data access** — Drizzle ORM repositories'
 };
 
+// Second repo a multi-repo task can also change.
+const MOCK_REPO_API = {
+	...MOCK_REPO,
+	id: 'repo_mock02',
+	name: 'api',
+	full_name: 'acme/api'
+};
+
 // Sample agent logs that showcase all the different log types and syntax highlighting.
 // These are used by the running/review task detail screenshots so we can preview how
 // the terminal rendering looks for each prefix and inline formatting rule.
@@ -339,6 +403,7 @@ const MOCK_TASKS = [
 		skip_pr: false,
 		started_at: '2025-06-01T08:00:00Z',
 		duration_ms: 180000,
+		comment_count: 2,
 		created_at: '2025-06-01T07:00:00Z',
 		updated_at: '2025-06-01T08:03:00Z'
 	},
@@ -428,9 +493,95 @@ const MOCK_TASKS = [
 		skip_pr: false,
 		created_at: '2025-05-31T14:00:00Z',
 		updated_at: '2025-06-01T12:00:00Z'
+	},
+	// Multi-repo task: one PR in the task's repo and one per changed additional repo
+	{
+		id: 'tsk_multirepo01',
+		number: 9,
+		repo_id: 'repo_mock01',
+		additional_repo_ids: ['repo_mock02'],
+		title: 'Add user avatar uploads',
+		description: 'Add an avatar upload endpoint to the API and the upload form to the web app',
+		status: 'review',
+		logs: [],
+		pull_request_url: 'https://github.com/acme/webapp/pull/51',
+		pr_number: 51,
+		pull_requests: [
+			{
+				repo_id: 'repo_mock02',
+				url: 'https://github.com/acme/api/pull/12',
+				number: 12,
+				merged: true
+			}
+		],
+		branch_name: 'verve/task-9',
+		attempt: 1,
+		max_attempts: 3,
+		acceptance_criteria: ['Avatars can be uploaded from the profile page'],
+		consecutive_failures: 0,
+		cost_usd: 0.88,
+		skip_pr: false,
+		started_at: '2025-06-01T09:00:00Z',
+		duration_ms: 420000,
+		created_at: '2025-06-01T08:30:00Z',
+		updated_at: '2025-06-01T09:07:00Z'
+	},
+	// Shadow-mode task: the agent's diff was recorded instead of pushed
+	{
+		id: 'tsk_shadow01',
+		number: 10,
+		repo_id: 'repo_mock01',
+		title: 'Debounce search input',
+		description: 'Debounce the search box so typing does not send a request per keystroke',
+		status: 'review',
+		logs: [],
+		attempt: 1,
+		max_attempts: 3,
+		acceptance_criteria: ['Search requests are sent at most every 300ms while typing'],
+		consecutive_failures: 0,
+		cost_usd: 0.41,
+		skip_pr: false,
+		started_at: '2025-06-01T11:00:00Z',
+		duration_ms: 180000,
+		created_at: '2025-06-01T10:50:00Z',
+		updated_at: '2025-06-01T11:03:00Z'
 	}
 ];
 
+// Plan-only task in review: its agent proposed a plan instead of changing code.
+const MOCK_TASK_PLAN_ONLY = {
+	...MOCK_TASKS[3],
+	id: 'tsk_planonly01',
+	number: 15,
+	title: 'Move session storage to Redis',
+	description: 'Sessions are lost on every deploy; keep them in Redis instead of memory',
+	plan_only: true,
+	pull_request_url: undefined,
+	pr_number: undefined,
+	branch_name: undefined,
+	comment_count: 0,
+	cost_usd: 0.16
+};
+
+// Plan the plan-only task's agent proposed, awaiting approval.
+const MOCK_TASK_PROPOSAL = {
+	attempt: 1,
+	summary: 'Replace the in-memory session store with a Redis-backed store behind the existing interface.',
+	approach:
+		'Add a RedisStore implementing SessionStore, choose it from REDIS_URL at startup and fall back to the memory store when unset so local development is unchanged.',
+	files: [
+		{ path: 'src/session/redis-store.ts', change: 'New Redis implementation of SessionStore with TTL per session' },
+		{ path: 'src/session/index.ts', change: 'Select the store from REDIS_URL' },
+		{ path: 'src/config.ts', change: 'Read REDIS_URL' },
+		{ path: 'docker-compose.yml', change: 'Add a redis service for local runs' }
+	],
+	risks: [
+		'Existing sessions are dropped once when the new store rolls out',
+		'Redis becomes a hard dependency in production'
+	],
+	created_at: '2025-06-01T08:02:00Z'
+};
+
 // Map of task ID to per-attempt logs for the SSE mock.
 const MOCK_TASK_LOGS: Record<string, Record<number, string[]>> = {
 	tsk_running01: SAMPLE_LOGS_RUNNING,
@@ -439,6 +590,207 @@ const MOCK_TASK_LOGS: Record<string, Record<number, string[]>> = {
 	tsk_failed01: SAMPLE_LOGS_FAILED
 };
 
+// Map of task ID to the agent's reported plan for the progress mock.
+const MOCK_TASK_PROGRESS: Record<string, unknown> = {
+	tsk_running01: {
+		attempt: 1,
+		steps: [
+			{
+				title: 'Reproduce pool exhaustion with a load test',
+				status: 'completed',
+				started_at: '2025-06-01T10:30:10Z',
+				completed_at: '2025-06-01T10:32:40Z'
+			},
+			{
+				title: 'Configure max open and idle connections',
+				status: 'completed',
+				started_at: '2025-06-01T10:32:41Z',
+				completed_at: '2025-06-01T10:34:05Z'
+			},
+			{
+				title: 'Release connections on request cancellation',
+				status: 'in_progress',
+				started_at: '2025-06-01T10:34:06Z'
+			},
+			{ title: 'Add regression test for pool limits', status: 'pending' },
+			{ title: 'Run the full test suite', status: 'pending' }
+		],
+		updated_at: '2025-06-01T10:34:06Z'
+	}
+};
+
+// Map of task ID to the messages the user sent to the running agent.
+const MOCK_TASK_NUDGES: Record<string, unknown[]> = {
+	tsk_running01: [
+		{
+			id: 1,
+			attempt: 1,
+			message: 'Keep the pool size configurable via DB_MAX_OPEN_CONNS rather than hard-coding it.',
+			created_at: '2025-06-01T10:33:00Z',
+			delivered_at: '2025-06-01T10:33:04Z'
+		},
+		{
+			id: 2,
+			attempt: 1,
+			message: 'Skip the load test in CI, it is too slow.',
+			created_at: '2025-06-01T10:34:20Z'
+		}
+	]
+};
+
+// Map of task ID to the comments left on it.
+const MOCK_TASK_COMMENTS: Record<string, unknown[]> = {
+	tsk_review01: [
+		{
+			id: 1,
+			task_id: 'tsk_review01',
+			author: 'jordan',
+			body: 'The toggle flashes the light theme on first load. Can we read the preference before hydration?',
+			created_at: '2025-06-01T08:20:00Z',
+			updated_at: '2025-06-01T08:20:00Z'
+		},
+		{
+			id: 2,
+			task_id: 'tsk_review01',
+			attempt: 1,
+			author: 'sam',
+			body: 'Contrast checks look good on this attempt. Approving once the flash is fixed.',
+			created_at: '2025-06-01T08:41:00Z',
+			updated_at: '2025-06-01T08:45:00Z'
+		}
+	]
+};
+
+// Map of task ID to the files attached to it for its agent.
+const MOCK_TASK_ATTACHMENTS: Record<string, unknown[]> = {
+	tsk_running01: [
+		{
+			id: 1,
+			task_id: 'tsk_running01',
+			filename: 'pool-exhaustion.log',
+			content_type: 'text/plain',
+			size_bytes: 18432,
+			uploaded_by: 'jordan',
+			created_at: '2025-06-01T10:20:00Z'
+		},
+		{
+			id: 2,
+			task_id: 'tsk_running01',
+			filename: 'grafana-connections.png',
+			content_type: 'image/png',
+			size_bytes: 248310,
+			uploaded_by: 'jordan',
+			created_at: '2025-06-01T10:21:00Z'
+		}
+	]
+};
+
+// Map of task ID to the files its agent saved as artifacts, per attempt.
+const MOCK_TASK_ARTIFACTS: Record<string, unknown[]> = {
+	tsk_failed01: [
+		{
+			id: 1,
+			task_id: 'tsk_failed01',
+			attempt: 1,
+			name: 'junit.xml',
+			content_type: 'application/xml',
+			size_bytes: 6144,
+			created_at: '2025-05-29T15:40:00Z'
+		},
+		{
+			id: 2,
+			task_id: 'tsk_failed01',
+			attempt: 2,
+			name: 'junit.xml',
+			content_type: 'application/xml',
+			size_bytes: 7312,
+			created_at: '2025-05-29T16:04:00Z'
+		},
+		{
+			id: 3,
+			task_id: 'tsk_failed01',
+			attempt: 2,
+			name: 'coverage/index.html',
+			content_type: 'text/html',
+			size_bytes: 1835008,
+			created_at: '2025-05-29T16:04:00Z'
+		}
+	]
+};
+
+// Map of repo ID to the follow-up notes its agents left.
+const MOCK_REPO_NOTES: Record<string, unknown[]> = {
+	repo_mock01: [
+		{
+			id: 1,
+			task_id: 'tsk_review01',
+			repo_id: 'repo_mock01',
+			attempt: 1,
+			text: 'The settings page still hardcodes light-theme colors in its inline styles.',
+			created_at: '2025-06-01T08:02:00Z'
+		},
+		{
+			id: 2,
+			task_id: 'tsk_review01',
+			repo_id: 'repo_mock01',
+			attempt: 1,
+			text: 'Chart colors come from a separate palette that ignores the theme.',
+			created_at: '2025-06-01T08:02:30Z',
+			converted_task_id: 'tsk_pending01'
+		}
+	]
+};
+
+// Map of task ID to the license and provenance scan of its PR.
+const MOCK_TASK_PROVENANCE: Record<string, unknown> = {
+	tsk_review01: {
+		findings: [
+			{
+				kind: 'license_header',
+				path: 'src/lib/theme/contrast.ts',
+				line: 1,
+				detail: 'GPL license text: // This program is free software under the GNU General Public License v3'
+			},
+			{
+				kind: 'large_block',
+				path: 'src/lib/theme/palette.ts',
+				line: 12,
+				lines: 248,
+				detail: '248 contiguous added lines'
+			}
+		],
+		head_sha: '4f2c9e1a7b3d5c8e0f1a2b3c4d5e6f7a8b9c0d1e',
+		scanned_at: '2025-06-01T08:03:00Z'
+	}
+};
+
+// Map of task ID to the automated self-review of its PR.
+const MOCK_TASK_SELF_REVIEWS: Record<string, unknown> = {
+	tsk_review01: {
+		score: 80,
+		checks: [
+			{
+				name: 'acceptance_criteria',
+				passed: true,
+				detail: '2 of 2 acceptance criteria appear to be addressed by the diff'
+			},
+			{
+				name: 'leftovers',
+				passed: false,
+				detail: '2 added lines look like TODOs or debugging code',
+				findings: [
+					'src/lib/theme/store.ts:14: TODO comment',
+					'src/lib/components/ThemeToggle.svelte:22: console.log call'
+				]
+			},
+			{ name: 'file_count', passed: true, detail: '6 files changed (limit 30)' },
+			{ name: 'diff_size', passed: true, detail: '412 changed lines' }
+		],
+		head_sha: '4f2c9e1a7b3d5c8e0f1a2b3c4d5e6f7a8b9c0d1e',
+		reviewed_at: '2025-06-01T08:03:00Z'
+	}
+};
+
 // --- Mock Epic Data ---
 
 // Epic in draft state — no planning session started yet.
@@ -643,6 +995,32 @@ const MOCK_EPIC_PLANNING_CLAIMED = {
 	last_heartbeat_at: '2025-06-01T10:15:00Z'
 };
 
+// Completed epic whose tasks shared an integration branch — the final PR is open.
+const MOCK_EPIC_SHARED_BRANCH = {
+	...MOCK_EPIC_ACTIVE,
+	id: 'epc_shared01',
+	number: 6,
+	status: 'completed',
+	task_ids: ['tsk_merged01'],
+	shared_branch: true,
+	pull_request_url: 'https://github.com/acme/webapp/pull/57',
+	pr_number: 57
+};
+
+// Active epic that has worked through half its tasks, for the burndown chart.
+const MOCK_EPIC_BURNDOWN = {
+	...MOCK_EPIC_ACTIVE,
+	id: 'epc_burndown01',
+	number: 7,
+	title: 'Trim the frontend bundle',
+	description: 'Cut the production bundle below 200 KB by dropping heavy dependencies and lazy-loading routes.',
+	proposed_tasks: [],
+	task_ids: ['tsk_archived02', 'tsk_archived01', 'tsk_merged01', 'tsk_review01', 'tsk_running01', 'tsk_pending01'],
+	planning_prompt: 'Audit the bundle and split the work into small tasks.',
+	created_at: '2025-03-24T10:00:00Z',
+	updated_at: '2025-05-30T14:02:00Z'
+};
+
 // All mock epics, used by the dashboard's epic list.
 const MOCK_EPICS = [MOCK_EPIC_DRAFT, MOCK_EPIC_PLANNING, MOCK_EPIC_READY, MOCK_EPIC_ACTIVE];
 
@@ -652,7 +1030,9 @@ const MOCK_EPIC_MAP: Record<string, typeof MOCK_EPIC_DRAFT> = {
 	epc_planning01: MOCK_EPIC_PLANNING,
 	epc_planningclaimed01: MOCK_EPIC_PLANNING_CLAIMED,
 	epc_ready01: MOCK_EPIC_READY,
-	epc_active01: MOCK_EPIC_ACTIVE
+	epc_active01: MOCK_EPIC_ACTIVE,
+	epc_shared01: MOCK_EPIC_SHARED_BRANCH,
+	epc_burndown01: MOCK_EPIC_BURNDOWN
 };
 
 // Map of epic number to full epic object for number lookups.
@@ -661,7 +1041,53 @@ const MOCK_EPIC_BY_NUMBER: Record<number, typeof MOCK_EPIC_DRAFT> = {
 	2: MOCK_EPIC_PLANNING,
 	3: MOCK_EPIC_READY,
 	4: MOCK_EPIC_ACTIVE,
-	5: MOCK_EPIC_PLANNING_CLAIMED
+	5: MOCK_EPIC_PLANNING_CLAIMED,
+	6: MOCK_EPIC_SHARED_BRANCH,
+	7: MOCK_EPIC_BURNDOWN
+};
+
+// Progress for each epic that has tasks, keyed by epic ID.
+const MOCK_EPIC_PROGRESS: Record<string, unknown> = {
+	// Nothing completed yet, so the burndown has only its starting point.
+	epc_active01: {
+		epic_id: 'epc_active01',
+		status: 'active',
+		status_counts: { pending: 1, running: 1, review: 1, merged: 0, closed: 0, failed: 0 },
+		total_tasks: 3,
+		completed_tasks: 0,
+		estimated_remaining_tasks: 3,
+		cost: { planning_usd: 0.24, tasks_usd: 0.57, spent_usd: 0.81, estimated_total_usd: 0.81 },
+		burndown: [{ time: '2025-06-01T08:00:00Z', completed: 0, remaining: 3 }]
+	},
+	epc_shared01: {
+		epic_id: 'epc_shared01',
+		status: 'completed',
+		status_counts: { pending: 0, running: 0, review: 0, merged: 1, closed: 0, failed: 0 },
+		total_tasks: 1,
+		completed_tasks: 1,
+		estimated_remaining_tasks: 0,
+		cost: { planning_usd: 0.24, tasks_usd: 0.3, spent_usd: 0.54, estimated_total_usd: 0.54 },
+		burndown: [
+			{ time: '2025-05-30T13:00:00Z', completed: 0, remaining: 1 },
+			{ time: '2025-05-30T14:02:00Z', completed: 1, remaining: 0 }
+		]
+	},
+	epc_burndown01: {
+		epic_id: 'epc_burndown01',
+		status: 'active',
+		status_counts: { pending: 1, running: 1, review: 1, merged: 2, closed: 1, failed: 0 },
+		total_tasks: 6,
+		completed_tasks: 3,
+		estimated_remaining_tasks: 3,
+		cost: { planning_usd: 0.31, tasks_usd: 1.96, spent_usd: 2.27, estimated_total_usd: 2.6 },
+		estimated_completion_at: '2025-08-04T16:44:00Z',
+		burndown: [
+			{ time: '2025-03-25T11:20:00Z', completed: 0, remaining: 6 },
+			{ time: '2025-04-01T16:00:00Z', completed: 1, remaining: 5 },
+			{ time: '2025-04-02T09:04:00Z', completed: 2, remaining: 4 },
+			{ time: '2025-05-30T14:02:00Z', completed: 3, remaining: 3 }
+		]
+	}
 };
 
 // --- Mock Conversation Data ---
@@ -1018,100 +1444,591 @@ index 8e2f1a0..b3c4d72 100644
    </div>
 `;
 
-// Intercept all API calls so the UI renders with mock data instead of hitting a real server.
-// Routes are registered most-specific first because Playwright matches in FIFO order.
-// The `repoOverride` parameter allows individual tests to swap the mock repo (e.g. to
-// simulate scanning or needs_setup states) without affecting other tests.
-async function setupMockAPI(
-	page: import('@playwright/test').Page,
-	repoOverride?: typeof MOCK_REPO
-) {
-	const activeRepo = repoOverride ?? MOCK_REPO;
-	// GitHub token status - report as configured so the UI shows the dashboard.
-	await page.route('**/api/v1/settings/github-token', (route) =>
-		route.fulfill({ json: { data: { configured: true, fine_grained: true } } })
-	);
+// Map of task ID to the diff recorded by its latest shadow-mode run.
+const MOCK_TASK_SHADOW_DIFFS: Record<string, unknown> = {
+	tsk_shadow01: {
+		attempt: 1,
+		diff: MOCK_DIFF,
+		created_at: '2025-06-01T11:03:00Z'
+	}
+};
 
-	// Default model
-	await page.route('**/api/v1/settings/default-model', (route) =>
-		route.fulfill({ json: { data: { model: 'claude-sonnet-4-20250514', configured: true } } })
-	);
+// --- Mock Team Data ---
 
-	// Available models list
-	await page.route('**/api/v1/settings/models', (route) =>
-		route.fulfill({ json: { data: [
-			{ value: 'haiku', label: 'Haiku' },
-			{ value: 'sonnet', label: 'Sonnet' },
-			{ value: 'opus', label: 'Opus' }
-		] } })
-	);
+// Repo variant: ready and in the Platform team
+const MOCK_REPO_WITH_TEAM = {
+	...MOCK_REPO_NEEDS_SETUP,
+	setup_status: 'ready',
+	setup_completed_at: '2025-01-15T10:05:00Z',
+	team_id: 'team_mock01'
+};
 
-	// Agent metrics
-	await page.route('**/api/v1/metrics', (route) =>
-		route.fulfill({ json: { data: MOCK_METRICS } })
-	);
+const MOCK_TEAMS = [
+	{
+		id: 'team_mock01',
+		name: 'Platform',
+		default_model: 'opus',
+		default_max_cost_usd: 10,
+		created_at: '2025-01-10T09:00:00Z'
+	},
+	{
+		id: 'team_mock02',
+		name: 'Growth',
+		default_model: '',
+		default_max_cost_usd: 0,
+		created_at: '2025-02-03T14:30:00Z'
+	}
+];
 
-	// Repo setup endpoints (must be before generic /repos/* routes)
-	await page.route('**/api/v1/repos/*/setup/rescan', (route) =>
-		route.fulfill({ json: { data: { ...activeRepo, setup_status: 'scanning' } } })
-	);
-	await page.route('**/api/v1/repos/*/setup/skip', (route) =>
-		route.fulfill({ json: { data: { ...activeRepo, setup_status: 'ready', setup_completed_at: new Date().toISOString() } } })
-	);
-	await page.route('**/api/v1/repos/*/setup/submit', (route) =>
-		route.fulfill({ json: { data: { ...activeRepo, setup_status: 'configuring' } } })
-	);
-	await page.route('**/api/v1/repos/*/setup/confirm', (route) =>
-		route.fulfill({ json: { data: { ...activeRepo, setup_status: 'ready', setup_completed_at: new Date().toISOString() } } })
-	);
-	await page.route('**/api/v1/repos/*/setup', (route) => {
-		if (route.request().method() === 'PATCH') {
-			return route.fulfill({ json: { data: { ...activeRepo, setup_status: 'ready', setup_completed_at: new Date().toISOString() } } });
+// Spend over the last 30 days, with the repos outside any team grouped last.
+// Mock throughput report for the metrics page: the 30 days up to
+// 2025-06-01, one series of [created, merged, failed] counts per repo.
+function mockThroughputDays(counts: [number, number, number][]) {
+	return counts.map(([created, merged, failed], i) => ({
+		date: new Date(Date.UTC(2025, 4, 3 + i)).toISOString().slice(0, 10),
+		created,
+		merged,
+		failed
+	}));
+}
+
+const MOCK_THROUGHPUT_WEBAPP_DAYS = mockThroughputDays([
+	[2, 1, 0], [1, 1, 0], [0, 0, 0], [0, 0, 0], [3, 1, 0], [2, 2, 1], [1, 2, 0], [2, 1, 0],
+	[1, 1, 0], [0, 0, 0], [0, 0, 0], [4, 2, 0], [2, 3, 0], [1, 1, 1], [3, 2, 0], [2, 2, 0],
+	[0, 0, 0], [0, 0, 0], [2, 1, 0], [3, 2, 0], [1, 2, 0], [2, 1, 1], [1, 2, 0], [0, 0, 0],
+	[0, 0, 0], [3, 1, 0], [2, 2, 0], [1, 1, 0], [2, 1, 0], [1, 0, 0]
+]);
+
+const MOCK_THROUGHPUT_API_DAYS = mockThroughputDays([
+	[0, 0, 0], [1, 0, 0], [0, 1, 0], [0, 0, 0], [0, 0, 0], [1, 0, 0], [0, 0, 1], [1, 1, 0],
+	[0, 0, 0], [0, 0, 0], [0, 0, 0], [2, 0, 0], [0, 1, 0], [0, 1, 0], [1, 0, 0], [0, 0, 0],
+	[0, 0, 0], [0, 0, 0], [1, 1, 0], [0, 0, 0], [1, 0, 0], [0, 1, 0], [0, 0, 0], [0, 0, 0],
+	[0, 0, 0], [1, 0, 0], [0, 1, 0], [0, 0, 0], [1, 0, 0], [0, 0, 0]
+]);
+
+const MOCK_THROUGHPUT_REPORT = {
+	since: '2025-05-03T00:00:00Z',
+	days: 30,
+	repos: [
+		{
+			repo_id: 'repo_mock01',
+			created: 42,
+			merged: 35,
+			failed: 3,
+			per_day: MOCK_THROUGHPUT_WEBAPP_DAYS,
+			cycle_times: {
+				pending_to_running: { count: 41, p50_seconds: 95, p90_seconds: 840, p95_seconds: 1820 },
+				running_to_review: { count: 38, p50_seconds: 1260, p90_seconds: 3900, p95_seconds: 5400 },
+				review_to_merged: { count: 35, p50_seconds: 7200, p90_seconds: 64800, p95_seconds: 118800 }
+			}
+		},
+		{
+			repo_id: 'repo_mock02',
+			created: 10,
+			merged: 7,
+			failed: 1,
+			per_day: MOCK_THROUGHPUT_API_DAYS,
+			cycle_times: {
+				pending_to_running: { count: 10, p50_seconds: 40, p90_seconds: 300, p95_seconds: 420 },
+				running_to_review: { count: 8, p50_seconds: 900, p90_seconds: 2400, p95_seconds: 2700 },
+				review_to_merged: { count: 7, p50_seconds: 14400, p90_seconds: 86400, p95_seconds: 93600 }
+			}
 		}
-		return route.fulfill({ json: { data: activeRepo } });
-	});
+	],
+	cycle_times: {
+		pending_to_running: { count: 51, p50_seconds: 80, p90_seconds: 780, p95_seconds: 1500 },
+		running_to_review: { count: 46, p50_seconds: 1140, p90_seconds: 3600, p95_seconds: 5100 },
+		review_to_merged: { count: 42, p50_seconds: 9000, p90_seconds: 72000, p95_seconds: 104400 }
+	}
+};
 
-	// Repos list
-	await page.route('**/api/v1/repos', (route) => {
-		if (route.request().method() === 'GET') {
-			return route.fulfill({ json: { data: [activeRepo] } });
+const MOCK_TEAM_COSTS = {
+	days: 30,
+	teams: [
+		{
+			team_id: 'team_mock01',
+			team_name: 'Platform',
+			cost_usd: 42.37,
+			repos: [{ repo_id: 'repo_mock01', full_name: 'acme/webapp', cost_usd: 42.37 }]
+		},
+		{ team_id: 'team_mock02', team_name: 'Growth', cost_usd: 0, repos: [] },
+		{
+			team_name: 'No team',
+			cost_usd: 6.12,
+			repos: [{ repo_id: 'repo_mock02', full_name: 'acme/api', cost_usd: 6.12 }]
 		}
-		return route.fulfill({ json: { data: activeRepo } });
-	});
+	]
+};
 
-	// SSE events endpoint - send an init event with mock tasks then keep connection open.
-	await page.route('**/api/v1/events**', (route) => {
-		const body = `event: init\ndata: ${JSON.stringify(MOCK_TASKS)}\n\n`;
-		return route.fulfill({
-			status: 200,
-			headers: {
-				'Content-Type': 'text/event-stream',
-				'Cache-Control': 'no-cache',
-				Connection: 'keep-alive'
-			},
-			body
-		});
-	});
+// --- Mock Auth Data ---
+
+// Capabilities of a server that requires sign-in.
+const MOCK_CAPABILITIES_AUTH = {
+	version: 'v1.4.0',
+	features: {
+		admin: true,
+		auth: true,
+		mcp: false,
+		github_token_storage: true,
+		provenance_scan: false,
+		self_review: false,
+		cost_anomaly_detection: false,
+		cost_anomaly_auto_pause: false
+	},
+	limits: {
+		max_labels: 16,
+		task_timeout_seconds: 300,
+		log_retention_seconds: 0,
+		trash_retention_seconds: 0,
+		archive_after_seconds: 0
+	}
+};
 
-	// Task by number lookup (must be before generic /repos/* catch-all).
-	// Note: Playwright glob does NOT support [0-9] character classes — use a
-	// URL predicate instead so that numeric task-number segments match correctly.
-	await page.route(
-		(url) => /\/api\/v1\/repos\/[^/]+\/tasks\/\d+(\/|\?|$)/.test(url.pathname),
-		(route) => {
-			const url = route.request().url();
-			const numberStr = url.split('/tasks/')[1]?.split(/[/?]/)[0];
-			const number = Number(numberStr);
-			const task = MOCK_TASKS.find((t) => t.number === number);
-			if (task) {
-				return route.fulfill({ json: { data: task } });
-			}
-			return route.fulfill({ status: 404, json: { error: { message: 'not found' } } });
-		}
-	);
+// Single sign-on configured with an identity provider's groups mapped to roles.
+const MOCK_OIDC_SETTINGS = {
+	issuer_url: 'https://login.acme.dev',
+	client_id: 'verve',
+	redirect_url: 'https://verve.acme.dev/api/v1/auth/oidc/callback',
+	groups_claim: 'groups',
+	group_roles: { platform: 'admin', engineering: 'operator' },
+	default_role: 'viewer',
+	has_client_secret: true,
+	configured: true
+};
 
-	// Task diff (must be before generic /tasks/* route).
-	await page.route('**/api/v1/tasks/*/diff', (route) =>
+// Users of a server that requires auth; the first is the signed in admin.
+const MOCK_USERS = [
+	{ id: 'usr_admin01', name: 'Dana Reyes', role: 'admin', created_at: '2025-01-10T09:00:00Z' },
+	{ id: 'usr_operator01', name: 'Sam Okafor', role: 'operator', created_at: '2025-02-03T14:30:00Z' },
+	{ id: 'usr_viewer01', name: 'release-dashboard', role: 'viewer', created_at: '2025-04-18T08:15:00Z' }
+];
+
+// Token returned once when a user is created or their token is rotated.
+const MOCK_USER_TOKEN = 'vu_3f9c1a7e52b84d06a1e2c9f4b7d05e83';
+
+// --- Mock GitHub Credential Data ---
+
+// A token for one repo in another org and a GitHub App installation covering a
+// whole org.
+const MOCK_GITHUB_CREDENTIALS = [
+	{
+		id: 'ghc_mock01',
+		name: 'contractor',
+		kind: 'token',
+		repos: ['partner-co/integrations'],
+		created_at: '2025-05-28T09:00:00Z',
+		updated_at: '2025-05-28T09:00:00Z'
+	},
+	{
+		id: 'ghc_mock02',
+		name: 'acme-labs',
+		kind: 'app',
+		repos: ['acme-labs/*'],
+		app_id: 418203,
+		installation_id: 51938274,
+		created_at: '2025-05-20T14:00:00Z',
+		updated_at: '2025-05-31T11:00:00Z'
+	}
+];
+
+// --- Mock Queue Pause Data ---
+
+// Repo variant: ready and paused while its CI is broken
+const MOCK_REPO_PAUSED = {
+	...MOCK_REPO_NEEDS_SETUP,
+	setup_status: 'ready',
+	setup_completed_at: '2025-01-15T10:05:00Z',
+	paused: true
+};
+
+// The instance-wide queue paused to drain workers before an upgrade.
+const MOCK_QUEUE_PAUSED = {
+	paused: true,
+	reason: 'Upgrading to v1.5',
+	paused_at: '2025-06-01T10:30:00Z'
+};
+
+// --- Mock Runtime Settings Data ---
+
+// Settings that apply without a restart. The PR sync interval is overridden;
+// the rest use the server's configured defaults.
+const MOCK_RUNTIME_SETTINGS = [
+	{
+		key: 'default_model',
+		type: 'string',
+		description: 'Model tasks, epics and conversations use when none is chosen',
+		value: 'sonnet'
+	},
+	{
+		key: 'default_max_cost_usd',
+		type: 'float',
+		description:
+			'Budget in USD of tasks created without one when their repo and team set none (0 = no budget)',
+		value: '5'
+	},
+	{
+		key: 'sync_interval',
+		type: 'duration',
+		description: 'How often PR status is synced from GitHub (e.g. 30s)',
+		value: '2m'
+	},
+	{
+		key: 'reap_interval',
+		type: 'duration',
+		description: 'How often stale tasks, epics and conversations are timed out (e.g. 1m)',
+		value: ''
+	}
+];
+
+// --- Mock PR Sync Data ---
+
+// Repo variant: ready with its PRs synced every two minutes
+const MOCK_REPO_WITH_SYNC_INTERVAL = {
+	...MOCK_REPO_NEEDS_SETUP,
+	setup_status: 'ready',
+	setup_completed_at: '2025-01-15T10:05:00Z',
+	sync_interval_seconds: 120
+};
+
+// Repo variant: ready with PR sync turned off
+const MOCK_REPO_SYNC_DISABLED = {
+	...MOCK_REPO_NEEDS_SETUP,
+	setup_status: 'ready',
+	setup_completed_at: '2025-01-15T10:05:00Z',
+	sync_disabled: true
+};
+
+// --- Mock Review SLA Data ---
+
+// Repo variant: ready with tasks reported overdue after a day in review
+const MOCK_REPO_WITH_REVIEW_SLA = {
+	...MOCK_REPO_NEEDS_SETUP,
+	setup_status: 'ready',
+	setup_completed_at: '2025-01-15T10:05:00Z',
+	review_sla_seconds: 86400
+};
+
+// --- Mock Trash Data ---
+
+// Capabilities of a server that keeps deleted tasks in the trash for a week.
+const MOCK_CAPABILITIES_TRASH = {
+	version: 'v1.4.0',
+	features: {
+		admin: true,
+		auth: false,
+		mcp: false,
+		github_token_storage: true,
+		provenance_scan: false,
+		self_review: false,
+		cost_anomaly_detection: false,
+		cost_anomaly_auto_pause: false
+	},
+	limits: {
+		max_labels: 16,
+		task_timeout_seconds: 300,
+		log_retention_seconds: 0,
+		trash_retention_seconds: 604800,
+		archive_after_seconds: 0
+	}
+};
+
+// Deleted tasks that can still be restored, most recently deleted first.
+const MOCK_TRASHED_TASKS = [
+	{
+		id: 'tsk_trashed01',
+		number: 12,
+		repo_id: 'repo_mock01',
+		title: 'Migrate session store to Redis',
+		description: 'Replace the in-memory session store so sessions survive deploys',
+		status: 'failed',
+		logs: [],
+		attempt: 3,
+		max_attempts: 3,
+		acceptance_criteria: [],
+		consecutive_failures: 3,
+		cost_usd: 1.92,
+		skip_pr: false,
+		deleted_at: '2025-06-01T09:40:00Z',
+		created_at: '2025-05-30T14:00:00Z',
+		updated_at: '2025-06-01T09:40:00Z'
+	},
+	{
+		id: 'tsk_trashed02',
+		number: 11,
+		repo_id: 'repo_mock01',
+		title: 'Add holiday banner',
+		description: 'Seasonal banner on the landing page',
+		status: 'pending',
+		logs: [],
+		attempt: 0,
+		max_attempts: 3,
+		acceptance_criteria: [],
+		consecutive_failures: 0,
+		cost_usd: 0,
+		skip_pr: false,
+		deleted_at: '2025-05-29T16:05:00Z',
+		created_at: '2025-05-29T15:50:00Z',
+		updated_at: '2025-05-29T16:05:00Z'
+	}
+];
+
+// --- Mock Archive Data ---
+
+// Capabilities of a server that archives tasks 30 days after they are merged
+// or closed.
+const MOCK_CAPABILITIES_ARCHIVE = {
+	version: 'v1.4.0',
+	features: {
+		admin: true,
+		auth: false,
+		mcp: false,
+		github_token_storage: true,
+		provenance_scan: false,
+		self_review: false,
+		cost_anomaly_detection: false,
+		cost_anomaly_auto_pause: false
+	},
+	limits: {
+		max_labels: 16,
+		task_timeout_seconds: 300,
+		log_retention_seconds: 0,
+		trash_retention_seconds: 0,
+		archive_after_seconds: 2592000
+	}
+};
+
+// Tasks archived a month after they were merged or closed.
+const MOCK_ARCHIVED_TASKS = [
+	{
+		id: 'tsk_archived01',
+		number: 13,
+		repo_id: 'repo_mock01',
+		title: 'Add request ID middleware',
+		description: 'Tag every request with an ID and include it in logs',
+		status: 'merged',
+		logs: [],
+		pull_request_url: 'https://github.com/acme/webapp/pull/21',
+		pr_number: 21,
+		branch_name: 'verve/request-id-middleware',
+		attempt: 1,
+		max_attempts: 3,
+		acceptance_criteria: ['Request ID header on every response'],
+		consecutive_failures: 0,
+		cost_usd: 0.38,
+		skip_pr: false,
+		ready: true,
+		started_at: '2025-04-02T09:00:00Z',
+		duration_ms: 240000,
+		archived_at: '2025-05-03T10:00:00Z',
+		created_at: '2025-04-02T08:55:00Z',
+		updated_at: '2025-05-03T10:00:00Z'
+	},
+	{
+		id: 'tsk_archived02',
+		number: 14,
+		repo_id: 'repo_mock01',
+		title: 'Replace moment with date-fns',
+		description: 'Drop moment to shrink the bundle',
+		status: 'closed',
+		logs: [],
+		attempt: 2,
+		max_attempts: 3,
+		acceptance_criteria: [],
+		consecutive_failures: 0,
+		cost_usd: 0.71,
+		skip_pr: false,
+		ready: true,
+		close_reason: 'Superseded by the bundle audit epic',
+		archived_at: '2025-04-28T10:00:00Z',
+		created_at: '2025-03-25T11:20:00Z',
+		updated_at: '2025-04-28T10:00:00Z'
+	}
+];
+
+// Intercept all API calls so the UI renders with mock data instead of hitting a real server.
+// Routes are registered most-specific first because Playwright matches in FIFO order.
+// The `repoOverride` parameter allows individual tests to swap the mock repo (e.g. to
+// simulate scanning or needs_setup states) without affecting other tests.
+async function setupMockAPI(
+	page: import('@playwright/test').Page,
+	repoOverride?: typeof MOCK_REPO,
+	extraRepos: (typeof MOCK_REPO)[] = []
+) {
+	const activeRepo = repoOverride ?? MOCK_REPO;
+	// GitHub token status - report as configured so the UI shows the dashboard.
+	await page.route('**/api/v1/settings/github-token', (route) =>
+		route.fulfill({ json: { data: { configured: true, fine_grained: true } } })
+	);
+
+	// Default model
+	await page.route('**/api/v1/settings/default-model', (route) =>
+		route.fulfill({ json: { data: { model: 'claude-sonnet-4-20250514', configured: true } } })
+	);
+
+	// Instance-wide retry policy
+	await page.route('**/api/v1/settings/retry-policy', (route) =>
+		route.fulfill({ json: { data: {
+			policy: {},
+			defaults: { max_attempts: 5, circuit_breaker_threshold: 3 },
+			configured: false
+		} } })
+	);
+
+	// Available models list
+	await page.route('**/api/v1/settings/models', (route) =>
+		route.fulfill({ json: { data: [
+			{ value: 'haiku', label: 'Haiku' },
+			{ value: 'sonnet', label: 'Sonnet' },
+			{ value: 'opus', label: 'Opus' }
+		] } })
+	);
+
+	// Agent metrics
+	await page.route('**/api/v1/metrics', (route) =>
+		route.fulfill({ json: { data: MOCK_METRICS } })
+	);
+	await page.route('**/api/v1/reports/throughput**', (route) =>
+		route.fulfill({ json: { data: MOCK_THROUGHPUT_REPORT } })
+	);
+
+	// Repo setup endpoints (must be before generic /repos/* routes)
+	await page.route('**/api/v1/repos/*/setup/rescan', (route) =>
+		route.fulfill({ json: { data: { ...activeRepo, setup_status: 'scanning' } } })
+	);
+	await page.route('**/api/v1/repos/*/setup/skip', (route) =>
+		route.fulfill({ json: { data: { ...activeRepo, setup_status: 'ready', setup_completed_at: new Date().toISOString() } } })
+	);
+	await page.route('**/api/v1/repos/*/setup/submit', (route) =>
+		route.fulfill({ json: { data: { ...activeRepo, setup_status: 'configuring' } } })
+	);
+	await page.route('**/api/v1/repos/*/setup/confirm', (route) =>
+		route.fulfill({ json: { data: { ...activeRepo, setup_status: 'ready', setup_completed_at: new Date().toISOString() } } })
+	);
+	await page.route('**/api/v1/repos/*/workspace-cache', (route) =>
+		route.fulfill({ json: { data: { ...activeRepo, workspace_cache_generation: activeRepo.workspace_cache_generation + 1 } } })
+	);
+	await page.route('**/api/v1/repos/*/setup', (route) => {
+		if (route.request().method() === 'PATCH') {
+			return route.fulfill({ json: { data: { ...activeRepo, setup_status: 'ready', setup_completed_at: new Date().toISOString() } } });
+		}
+		return route.fulfill({ json: { data: activeRepo } });
+	});
+
+	// Teams and their spend
+	await page.route('**/api/v1/teams/costs**', (route) =>
+		route.fulfill({ json: { data: MOCK_TEAM_COSTS } })
+	);
+	await page.route('**/api/v1/teams', (route) => {
+		if (route.request().method() === 'GET') {
+			return route.fulfill({ json: { data: MOCK_TEAMS } });
+		}
+		return route.fulfill({ json: { data: MOCK_TEAMS[0] } });
+	});
+
+	// Single sign-on settings and status
+	await page.route('**/api/v1/settings/oidc', (route) =>
+		route.fulfill({ json: { data: MOCK_OIDC_SETTINGS } })
+	);
+	await page.route('**/api/v1/auth/oidc', (route) =>
+		route.fulfill({ json: { data: { enabled: true } } })
+	);
+
+	// Users and their API tokens
+	await page.route('**/api/v1/users/me', (route) =>
+		route.fulfill({ json: { data: MOCK_USERS[0] } })
+	);
+	await page.route('**/api/v1/users/*/rotate-token', (route) => {
+		const userId = route.request().url().split('/users/')[1]?.split('/')[0];
+		const user = MOCK_USERS.find((u) => u.id === userId) ?? MOCK_USERS[0];
+		return route.fulfill({ json: { data: { user, token: MOCK_USER_TOKEN } } });
+	});
+	await page.route('**/api/v1/users', (route) => {
+		if (route.request().method() === 'POST') {
+			const body = route.request().postDataJSON() as { name: string; role: string };
+			const user = { id: 'usr_new01', ...body, created_at: '2025-06-01T12:00:00Z' };
+			return route.fulfill({ status: 201, json: { data: { user, token: MOCK_USER_TOKEN } } });
+		}
+		return route.fulfill({ json: { data: MOCK_USERS } });
+	});
+
+	// Repo & org GitHub credentials
+	await page.route('**/api/v1/settings/github-credentials', (route) =>
+		route.fulfill({ json: { data: MOCK_GITHUB_CREDENTIALS } })
+	);
+
+	// Instance-wide queue pause
+	await page.route('**/api/v1/settings/queue-paused', (route) =>
+		route.fulfill({ json: { data: { paused: false } } })
+	);
+
+	// Runtime settings
+	await page.route('**/api/v1/settings/runtime', (route) =>
+		route.fulfill({ json: { data: MOCK_RUNTIME_SETTINGS } })
+	);
+
+	// Trashed tasks
+	await page.route('**/api/v1/repos/*/tasks/trash', (route) =>
+		route.fulfill({ json: { data: MOCK_TRASHED_TASKS } })
+	);
+
+	// Bulk task actions apply to every listed task.
+	await page.route('**/api/v1/repos/*/tasks/bulk-action', (route) => {
+		const body = route.request().postDataJSON() as { task_ids: string[] };
+		const results = body.task_ids.map((id) => ({ task_id: id, result: 'applied' }));
+		return route.fulfill({ json: { data: { results } } });
+	});
+
+	// Repo task list. Archived tasks are only included when asked for.
+	await page.route(
+		(url) => /\/api\/v1\/repos\/[^/]+\/tasks$/.test(url.pathname),
+		(route) => {
+			const url = new URL(route.request().url());
+			const archived = url.searchParams.get('include_archived') === 'true' ? MOCK_ARCHIVED_TASKS : [];
+			return route.fulfill({ json: { data: [...MOCK_TASKS, ...archived] } });
+		}
+	);
+
+	// Repos list
+	await page.route('**/api/v1/repos', (route) => {
+		if (route.request().method() === 'GET') {
+			return route.fulfill({ json: { data: [activeRepo, ...extraRepos] } });
+		}
+		return route.fulfill({ json: { data: activeRepo } });
+	});
+
+	// SSE events endpoint - send an init event with mock tasks then keep connection open.
+	await page.route('**/api/v1/events**', (route) => {
+		const body = `event: init\ndata: ${JSON.stringify(MOCK_TASKS)}\n\n`;
+		return route.fulfill({
+			status: 200,
+			headers: {
+				'Content-Type': 'text/event-stream',
+				'Cache-Control': 'no-cache',
+				Connection: 'keep-alive'
+			},
+			body
+		});
+	});
+
+	// Task by number lookup (must be before generic /repos/* catch-all).
+	// Note: Playwright glob does NOT support [0-9] character classes — use a
+	// URL predicate instead so that numeric task-number segments match correctly.
+	await page.route(
+		(url) => /\/api\/v1\/repos\/[^/]+\/tasks\/\d+(\/|\?|$)/.test(url.pathname),
+		(route) => {
+			const url = route.request().url();
+			const numberStr = url.split('/tasks/')[1]?.split(/[/?]/)[0];
+			const number = Number(numberStr);
+			const task = MOCK_TASKS.find((t) => t.number === number);
+			if (task) {
+				return route.fulfill({ json: { data: task } });
+			}
+			return route.fulfill({ status: 404, json: { error: { message: 'not found' } } });
+		}
+	);
+
+	// Task diff (must be before generic /tasks/* route).
+	await page.route('**/api/v1/tasks/*/diff', (route) =>
 		route.fulfill({ json: { data: { diff: MOCK_DIFF } } })
 	);
 
@@ -1145,42 +2062,123 @@ async function setupMockAPI(
 		})
 	);
 
-	// Task logs SSE (must be before generic /tasks/* route).
-	// Sends per-attempt logs_appended events followed by logs_done so the UI
-	// renders them in the terminal with full syntax highlighting.
-	await page.route('**/api/v1/tasks/*/logs', (route) => {
+	// Task progress (must be before generic /tasks/* route).
+	await page.route('**/api/v1/tasks/*/progress', (route) => {
 		const url = route.request().url();
 		const taskId = url.split('/tasks/')[1]?.split('/')[0];
-		const logsByAttempt = taskId ? MOCK_TASK_LOGS[taskId] : undefined;
-
-		let body = '';
-		if (logsByAttempt) {
-			for (const [attempt, lines] of Object.entries(logsByAttempt)) {
-				body += `event: logs_appended\ndata: ${JSON.stringify({ attempt: Number(attempt), logs: lines })}\n\n`;
-			}
-		}
-		body += 'event: logs_done\ndata: {}\n\n';
-
-		return route.fulfill({
-			status: 200,
-			headers: {
-				'Content-Type': 'text/event-stream',
-				'Cache-Control': 'no-cache',
-				Connection: 'keep-alive'
-			},
-			body
-		});
+		const progress = (taskId && MOCK_TASK_PROGRESS[taskId]) ?? {
+			attempt: 0,
+			steps: [],
+			updated_at: '2025-06-01T10:00:00Z'
+		};
+		return route.fulfill({ json: { data: progress } });
 	});
 
-	// Individual task detail (generic catch-all for /tasks/*).
-	await page.route('**/api/v1/tasks/*', (route) => {
+	// Task comments (must be before generic /tasks/* route).
+	await page.route('**/api/v1/tasks/*/comments', (route) => {
 		const url = route.request().url();
-		const taskId = url.split('/tasks/')[1]?.split('/')[0]?.split('?')[0];
-		const task = MOCK_TASKS.find((t) => t.id === taskId);
-		if (task) {
-			return route.fulfill({ json: { data: task } });
-		}
-		return route.fulfill({ status: 404, json: { error: { message: 'not found' } } });
+		const taskId = url.split('/tasks/')[1]?.split('/')[0];
+		const comments = (taskId && MOCK_TASK_COMMENTS[taskId]) ?? [];
+		return route.fulfill({ json: { data: comments } });
+	});
+
+	// Task attachments (must be before generic /tasks/* route).
+	await page.route('**/api/v1/tasks/*/attachments', (route) => {
+		const url = route.request().url();
+		const taskId = url.split('/tasks/')[1]?.split('/')[0];
+		const attachments = (taskId && MOCK_TASK_ATTACHMENTS[taskId]) ?? [];
+		return route.fulfill({ json: { data: attachments } });
+	});
+
+	// Plan-only task proposals (must be before generic /tasks/* route).
+	await page.route('**/api/v1/tasks/*/proposal', (route) =>
+		route.fulfill({ status: 404, json: { error: { message: 'not found' } } })
+	);
+
+	// Task artifacts (must be before generic /tasks/* route).
+	await page.route('**/api/v1/tasks/*/artifacts', (route) => {
+		const url = route.request().url();
+		const taskId = url.split('/tasks/')[1]?.split('/')[0];
+		const artifacts = (taskId && MOCK_TASK_ARTIFACTS[taskId]) ?? [];
+		return route.fulfill({ json: { data: artifacts } });
+	});
+
+	// Repo notes left by agents.
+	await page.route('**/api/v1/repos/*/notes', (route) => {
+		const url = route.request().url();
+		const repoId = url.split('/repos/')[1]?.split('/')[0];
+		const notes = (repoId && MOCK_REPO_NOTES[repoId]) ?? [];
+		return route.fulfill({ json: { data: notes } });
+	});
+
+	// Task nudges (must be before generic /tasks/* route).
+	await page.route('**/api/v1/tasks/*/nudges', (route) => {
+		const url = route.request().url();
+		const taskId = url.split('/tasks/')[1]?.split('/')[0];
+		const nudges = (taskId && MOCK_TASK_NUDGES[taskId]) ?? [];
+		return route.fulfill({ json: { data: nudges } });
+	});
+
+	// Task provenance review (must be before generic /tasks/* route).
+	await page.route('**/api/v1/tasks/*/provenance', (route) => {
+		const url = route.request().url();
+		const taskId = url.split('/tasks/')[1]?.split('/')[0];
+		const review = (taskId && MOCK_TASK_PROVENANCE[taskId]) ?? null;
+		return route.fulfill({ json: { data: review } });
+	});
+
+	// Task self-review (must be before generic /tasks/* route).
+	await page.route('**/api/v1/tasks/*/self-review', (route) => {
+		const url = route.request().url();
+		const taskId = url.split('/tasks/')[1]?.split('/')[0];
+		const review = (taskId && MOCK_TASK_SELF_REVIEWS[taskId]) ?? null;
+		return route.fulfill({ json: { data: review } });
+	});
+
+	// Task shadow diff (must be before generic /tasks/* route).
+	await page.route('**/api/v1/tasks/*/shadow-diff', (route) => {
+		const url = route.request().url();
+		const taskId = url.split('/tasks/')[1]?.split('/')[0];
+		const shadow = (taskId && MOCK_TASK_SHADOW_DIFFS[taskId]) ?? null;
+		return route.fulfill({ json: { data: shadow } });
+	});
+
+	// Task logs SSE (must be before generic /tasks/* route).
+	// Sends per-attempt logs_appended events followed by logs_done so the UI
+	// renders them in the terminal with full syntax highlighting.
+	await page.route('**/api/v1/tasks/*/logs', (route) => {
+		const url = route.request().url();
+		const taskId = url.split('/tasks/')[1]?.split('/')[0];
+		const logsByAttempt = taskId ? MOCK_TASK_LOGS[taskId] : undefined;
+
+		let body = '';
+		if (logsByAttempt) {
+			for (const [attempt, lines] of Object.entries(logsByAttempt)) {
+				body += `event: logs_appended\ndata: ${JSON.stringify({ attempt: Number(attempt), logs: lines })}\n\n`;
+			}
+		}
+		body += 'event: logs_done\ndata: {}\n\n';
+
+		return route.fulfill({
+			status: 200,
+			headers: {
+				'Content-Type': 'text/event-stream',
+				'Cache-Control': 'no-cache',
+				Connection: 'keep-alive'
+			},
+			body
+		});
+	});
+
+	// Individual task detail (generic catch-all for /tasks/*).
+	await page.route('**/api/v1/tasks/*', (route) => {
+		const url = route.request().url();
+		const taskId = url.split('/tasks/')[1]?.split('/')[0]?.split('?')[0];
+		const task = MOCK_TASKS.find((t) => t.id === taskId);
+		if (task) {
+			return route.fulfill({ json: { data: task } });
+		}
+		return route.fulfill({ status: 404, json: { error: { message: 'not found' } } });
 	});
 
 	// --- Conversation API mocks ---
@@ -1249,7 +2247,7 @@ async function setupMockAPI(
 		const epic = epicId ? MOCK_EPIC_MAP[epicId] : undefined;
 		if (epic && epic.task_ids.length > 0) {
 			const tasks = epic.task_ids.map((tid: string) => {
-				const t = MOCK_TASKS.find((mt) => mt.id === tid);
+				const t = [...MOCK_TASKS, ...MOCK_ARCHIVED_TASKS].find((mt) => mt.id === tid);
 				return t ? { id: t.id, number: t.number, title: t.title, status: t.status } : { id: tid, number: 0, title: tid, status: 'pending' };
 			});
 			return route.fulfill({ json: { data: tasks } });
@@ -1257,6 +2255,17 @@ async function setupMockAPI(
 		return route.fulfill({ json: { data: [] } });
 	});
 
+	// Epic progress (must be before the generic /epics/* catch-all).
+	await page.route('**/api/v1/epics/*/progress', (route) => {
+		const url = route.request().url();
+		const epicId = url.split('/epics/')[1]?.split('/')[0];
+		const progress = epicId ? MOCK_EPIC_PROGRESS[epicId] : undefined;
+		if (progress) {
+			return route.fulfill({ json: { data: progress } });
+		}
+		return route.fulfill({ status: 404, json: { error: { message: 'not found' } } });
+	});
+
 	// Epic sub-resource routes (must be before the generic /epics/* catch-all).
 	await page.route('**/api/v1/epics/*/plan', (route) =>
 		route.fulfill({ json: { data: MOCK_EPIC_PLANNING } })
@@ -1320,284 +2329,1168 @@ test.describe('UI Screenshots', () => {
 		await page.waitForSelector('text=Scanning repository', { timeout: 5000 });
 		await page.waitForTimeout(1500);
 
-		await page.screenshot({
-			path: `screenshots/repo-setup-scanning-${testInfo.project.name}.png`,
-			fullPage: true
+		await page.screenshot({
+			path: `screenshots/repo-setup-scanning-${testInfo.project.name}.png`,
+			fullPage: true
+		});
+	});
+
+	test('dashboard - repo needs setup banner', async ({ page }, testInfo) => {
+		await setupMockAPI(page, MOCK_REPO_NEEDS_SETUP);
+		await page.goto('/');
+
+		// Wait for the needs-setup banner to render (no kanban columns visible).
+		await page.waitForSelector('text=Repository needs configuration', { timeout: 5000 });
+		await page.waitForTimeout(1500);
+
+		// Expand the "Scan Results" section to show the RepoSummary
+		const scanResultsBtn = page.getByText('Scan Results');
+		if (await scanResultsBtn.isVisible()) {
+			await scanResultsBtn.click();
+			await page.waitForTimeout(500);
+		}
+
+		await page.screenshot({
+			path: `screenshots/repo-setup-needs-setup-${testInfo.project.name}.png`,
+			fullPage: true
+		});
+	});
+
+	test('repo setup wizard dialog', async ({ page }, testInfo) => {
+		await page.setViewportSize({ width: 1280, height: 1600 });
+		await setupMockAPI(page, MOCK_REPO_NEEDS_SETUP);
+		await page.goto('/');
+
+		// Wait for the needs-setup banner to render.
+		await page.waitForSelector('text=Repository needs configuration', { timeout: 5000 });
+		await page.waitForTimeout(1500);
+
+		// Click the "Configure" button in the needs_setup banner to open the wizard
+		const configureBtn = page.getByRole('button', { name: /configure/i });
+		await configureBtn.click();
+
+		// Wait for dialog to appear and settle.
+		await page.waitForTimeout(1000);
+
+		// Screenshot the dialog element directly to capture its full content.
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.screenshot({
+			path: `screenshots/repo-setup-wizard-${testInfo.project.name}.png`
+		});
+	});
+
+	test('repo setup wizard dialog - empty tech stack', async ({ page }, testInfo) => {
+		await page.setViewportSize({ width: 1280, height: 1600 });
+		await setupMockAPI(page, MOCK_REPO_NEEDS_SETUP_EMPTY);
+		await page.goto('/');
+
+		// Wait for the needs-setup banner to render.
+		await page.waitForSelector('text=Repository needs configuration', { timeout: 5000 });
+		await page.waitForTimeout(1500);
+
+		// Click the "Configure" button in the needs_setup banner to open the wizard
+		const configureBtn = page.getByRole('button', { name: /configure/i });
+		await configureBtn.click();
+
+		// Wait for dialog to appear and settle.
+		await page.waitForTimeout(1000);
+
+		// Screenshot the dialog element directly to capture its full content.
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.screenshot({
+			path: `screenshots/repo-setup-wizard-empty-stack-${testInfo.project.name}.png`
+		});
+	});
+
+	test('dashboard - repo configuring banner', async ({ page }, testInfo) => {
+		await setupMockAPI(page, MOCK_REPO_CONFIGURING);
+		await page.goto('/');
+
+		// Wait for the configuring banner to render.
+		await page.waitForSelector('text=AI reviewing configuration', { timeout: 5000 });
+		await page.waitForTimeout(1500);
+
+		await page.screenshot({
+			path: `screenshots/repo-setup-configuring-${testInfo.project.name}.png`,
+			fullPage: true
+		});
+	});
+
+	test('dashboard - repo pending setup banner', async ({ page }, testInfo) => {
+		await setupMockAPI(page, MOCK_REPO_PENDING);
+		await page.goto('/');
+
+		// Wait for the pending setup banner to render (no kanban columns visible).
+		await page.waitForSelector('text=Repository setup required', { timeout: 5000 });
+		await page.waitForTimeout(1500);
+
+		await page.screenshot({
+			path: `screenshots/repo-setup-pending-${testInfo.project.name}.png`,
+			fullPage: true
+		});
+	});
+
+	test('repo settings dialog', async ({ page }, testInfo) => {
+		await page.setViewportSize({ width: 1280, height: 900 });
+		await setupMockAPI(page, MOCK_REPO_NEEDS_SETUP);
+		await page.goto('/');
+
+		await page.waitForTimeout(1500);
+
+		// Click the "Repo Settings" sidebar link
+		const repoSettingsBtn = page.getByRole('button', { name: /repo settings/i });
+		await repoSettingsBtn.click();
+
+		await page.waitForTimeout(1000);
+
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.screenshot({
+			path: `screenshots/repo-settings-dialog-${testInfo.project.name}.png`
+		});
+	});
+
+	test('repo settings dialog with CI workflow', async ({ page }, testInfo) => {
+		await page.setViewportSize({ width: 1280, height: 900 });
+		await setupMockAPI(page, MOCK_REPO_WITH_CI_WORKFLOW);
+		await page.goto('/');
+
+		await page.waitForTimeout(1500);
+
+		const repoSettingsBtn = page.getByRole('button', { name: /repo settings/i });
+		await repoSettingsBtn.click();
+
+		await page.waitForTimeout(1000);
+
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.screenshot({
+			path: `screenshots/repo-settings-dialog-ci-workflow-${testInfo.project.name}.png`
+		});
+	});
+
+	test('repo settings dialog with protected paths', async ({ page }, testInfo) => {
+		await page.setViewportSize({ width: 1280, height: 900 });
+		await setupMockAPI(page, MOCK_REPO_WITH_PROTECTED_PATHS);
+		await page.goto('/');
+
+		await page.waitForTimeout(1500);
+
+		const repoSettingsBtn = page.getByRole('button', { name: /repo settings/i });
+		await repoSettingsBtn.click();
+
+		await page.waitForTimeout(1000);
+
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.screenshot({
+			path: `screenshots/repo-settings-dialog-protected-paths-${testInfo.project.name}.png`
+		});
+	});
+
+	test('repo settings dialog with shadow mode', async ({ page }, testInfo) => {
+		await page.setViewportSize({ width: 1280, height: 900 });
+		await setupMockAPI(page, MOCK_REPO_WITH_SHADOW_MODE);
+		await page.goto('/');
+
+		await page.waitForTimeout(1500);
+
+		const repoSettingsBtn = page.getByRole('button', { name: /repo settings/i });
+		await repoSettingsBtn.click();
+
+		await page.waitForTimeout(1000);
+
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.screenshot({
+			path: `screenshots/repo-settings-dialog-shadow-mode-${testInfo.project.name}.png`
+		});
+	});
+
+	test('repo settings dialog with completion validations', async ({ page }, testInfo) => {
+		await page.setViewportSize({ width: 1280, height: 900 });
+		await setupMockAPI(page, MOCK_REPO_WITH_COMPLETION_VALIDATIONS);
+		await page.goto('/');
+
+		await page.waitForTimeout(1500);
+
+		const repoSettingsBtn = page.getByRole('button', { name: /repo settings/i });
+		await repoSettingsBtn.click();
+
+		await page.waitForTimeout(1000);
+
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.screenshot({
+			path: `screenshots/repo-settings-dialog-completion-validations-${testInfo.project.name}.png`
+		});
+	});
+
+	test('repo settings dialog with retry policy', async ({ page }, testInfo) => {
+		await page.setViewportSize({ width: 1280, height: 900 });
+		await setupMockAPI(page, MOCK_REPO_WITH_RETRY_POLICY);
+		await page.goto('/');
+
+		await page.waitForTimeout(1500);
+
+		const repoSettingsBtn = page.getByRole('button', { name: /repo settings/i });
+		await repoSettingsBtn.click();
+
+		await page.waitForTimeout(1000);
+
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.screenshot({
+			path: `screenshots/repo-settings-dialog-retry-policy-${testInfo.project.name}.png`
+		});
+	});
+
+	test('repo settings dialog with workspace cache cleared', async ({ page }, testInfo) => {
+		await page.setViewportSize({ width: 1280, height: 900 });
+		await setupMockAPI(page, MOCK_REPO_WITH_CI_WORKFLOW);
+		await page.goto('/');
+
+		await page.waitForTimeout(1500);
+
+		const repoSettingsBtn = page.getByRole('button', { name: /repo settings/i });
+		await repoSettingsBtn.click();
+
+		await page.waitForTimeout(1000);
+
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.getByRole('button', { name: /^clear$/i }).click();
+
+		await page.waitForTimeout(500);
+
+		await dialog.screenshot({
+			path: `screenshots/repo-settings-dialog-workspace-cache-cleared-${testInfo.project.name}.png`
+		});
+	});
+
+	test('repo settings dialog with markdown expectations', async ({ page }, testInfo) => {
+		await page.setViewportSize({ width: 1280, height: 900 });
+		await setupMockAPI(page, MOCK_REPO_WITH_EXPECTATIONS);
+		await page.goto('/');
+
+		await page.waitForTimeout(1500);
+
+		// Click the "Repo Settings" sidebar link
+		const repoSettingsBtn = page.getByRole('button', { name: /repo settings/i });
+		await repoSettingsBtn.click();
+
+		await page.waitForTimeout(1000);
+
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.screenshot({
+			path: `screenshots/repo-settings-markdown-${testInfo.project.name}.png`
+		});
+	});
+
+	test('repo settings dialog with team', async ({ page }, testInfo) => {
+		await page.setViewportSize({ width: 1280, height: 900 });
+		await setupMockAPI(page, MOCK_REPO_WITH_TEAM);
+		await page.goto('/');
+
+		await page.waitForTimeout(1500);
+
+		const repoSettingsBtn = page.getByRole('button', { name: /repo settings/i });
+		await repoSettingsBtn.click();
+
+		await page.waitForTimeout(1000);
+
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.screenshot({
+			path: `screenshots/repo-settings-dialog-team-${testInfo.project.name}.png`
+		});
+	});
+
+	test('task detail - review', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
+		await page.goto(`/acme/webapp/tasks/4`);
+
+		await page.waitForTimeout(2000);
+
+		await page.screenshot({
+			path: `screenshots/task-detail-${testInfo.project.name}.png`,
+			fullPage: true
+		});
+	});
+
+	test('task detail - move to another repo', async ({ page }, testInfo) => {
+		await setupMockAPI(page, undefined, [MOCK_REPO_API]);
+		await page.goto('/acme/webapp/tasks/1');
+
+		await page.waitForTimeout(2000);
+
+		await page.getByRole('button', { name: 'Move', exact: true }).click();
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.locator('#move-repo').selectOption('repo_mock02');
+		await page.waitForTimeout(300);
+
+		await dialog.screenshot({
+			path: `screenshots/task-move-dialog-${testInfo.project.name}.png`
+		});
+	});
+
+	test('task detail - move blocked by dependencies', async ({ page }, testInfo) => {
+		await setupMockAPI(page, undefined, [MOCK_REPO_API]);
+		await page.route('**/api/v1/tasks/*/move', (route) =>
+			route.fulfill({
+				status: 409,
+				json: { error: { message: 'task has dependencies outside the target repo' } }
+			})
+		);
+		await page.goto('/acme/webapp/tasks/1');
+
+		await page.waitForTimeout(2000);
+
+		await page.getByRole('button', { name: 'Move', exact: true }).click();
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.locator('#move-repo').selectOption('repo_mock02');
+		await dialog.getByRole('button', { name: 'Move', exact: true }).click();
+		await page.waitForSelector('text=task has dependencies outside the target repo', { timeout: 5000 });
+		await page.waitForTimeout(300);
+
+		await dialog.screenshot({
+			path: `screenshots/task-move-blocked-${testInfo.project.name}.png`
+		});
+	});
+
+	test('task detail - review notes from agent', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
+		await page.goto(`/acme/webapp/tasks/4`);
+
+		await page.waitForTimeout(2000);
+
+		await page.getByText('Notes from agent', { exact: true }).locator('xpath=../../..').screenshot({
+			path: `screenshots/task-review-notes-${testInfo.project.name}.png`
+		});
+	});
+
+	test('task detail - review provenance findings', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
+		await page.goto(`/acme/webapp/tasks/4`);
+
+		await page.waitForTimeout(2000);
+
+		await page.getByText('Provenance review required', { exact: true }).locator('xpath=../..').screenshot({
+			path: `screenshots/task-review-provenance-${testInfo.project.name}.png`
+		});
+	});
+
+	test('task detail - pr self-review', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
+		await page.goto(`/acme/webapp/tasks/4`);
+
+		await page.waitForTimeout(2000);
+
+		await page.getByText('Self-review', { exact: true }).locator('xpath=../..').screenshot({
+			path: `screenshots/task-self-review-${testInfo.project.name}.png`
+		});
+	});
+
+	test('task detail - review comments', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
+		await page.goto(`/acme/webapp/tasks/4`);
+
+		await page.waitForTimeout(2000);
+
+		await page.getByText('Comments', { exact: true }).locator('xpath=../..').screenshot({
+			path: `screenshots/task-review-comments-${testInfo.project.name}.png`
+		});
+	});
+
+	test('task detail - additional pull requests', async ({ page }, testInfo) => {
+		await setupMockAPI(page, undefined, [MOCK_REPO_API]);
+		await page.goto(`/acme/webapp/tasks/9`);
+
+		await page.waitForTimeout(2000);
+
+		await page.getByText('Additional Pull Requests', { exact: true }).locator('xpath=../..').screenshot({
+			path: `screenshots/task-additional-prs-${testInfo.project.name}.png`
+		});
+	});
+
+	test('task detail - shadow run', async ({ page }, testInfo) => {
+		await setupMockAPI(page, MOCK_REPO_WITH_SHADOW_MODE);
+		await page.goto(`/acme/webapp/tasks/10`);
+
+		await page.waitForTimeout(2000);
+
+		await page.getByText('View Changes').click();
+
+		await page.waitForTimeout(1000);
+
+		await page.getByText('Shadow Run', { exact: true }).locator('xpath=../..').screenshot({
+			path: `screenshots/task-shadow-run-${testInfo.project.name}.png`
+		});
+	});
+
+	test('task detail - pr view', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
+		await page.goto(`/acme/webapp/tasks/4/pr`);
+
+		// Wait for the diff to auto-expand and render (file headers appear once loaded).
+		await page.waitForSelector('table', { timeout: 15000 });
+		await page.waitForTimeout(500);
+
+		await page.screenshot({
+			path: `screenshots/task-pr-view-${testInfo.project.name}.png`,
+			fullPage: true
+		});
+	});
+
+	test('task detail - running', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
+		await page.goto(`/acme/webapp/tasks/3`);
+
+		await page.waitForTimeout(2000);
+
+		await page.screenshot({
+			path: `screenshots/task-running-${testInfo.project.name}.png`,
+			fullPage: true
+		});
+	});
+
+	test('task detail - running plan checklist', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
+		await page.goto(`/acme/webapp/tasks/3`);
+
+		await page.waitForTimeout(2000);
+
+		await page.getByText('Plan', { exact: true }).locator('xpath=../..').screenshot({
+			path: `screenshots/task-running-plan-${testInfo.project.name}.png`
+		});
+	});
+
+	test('task detail - running messages to agent', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
+		await page.goto(`/acme/webapp/tasks/3`);
+
+		await page.waitForTimeout(2000);
+
+		await page.getByText('Messages to agent', { exact: true }).locator('xpath=../..').screenshot({
+			path: `screenshots/task-running-nudges-${testInfo.project.name}.png`
+		});
+	});
+
+	test('task detail - plan-only proposal awaiting approval', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
+		await page.route(
+			(url) => /\/api\/v1\/repos\/[^/]+\/tasks\/15(\/|\?|$)/.test(url.pathname),
+			(route) => route.fulfill({ json: { data: MOCK_TASK_PLAN_ONLY } })
+		);
+		await page.route('**/api/v1/tasks/tsk_planonly01/proposal', (route) =>
+			route.fulfill({ json: { data: MOCK_TASK_PROPOSAL } })
+		);
+		await page.goto(`/acme/webapp/tasks/15`);
+
+		await page.waitForTimeout(2000);
+
+		await page.getByText('Proposal', { exact: true }).locator('xpath=../..').screenshot({
+			path: `screenshots/task-proposal-${testInfo.project.name}.png`
+		});
+	});
+
+	test('task detail - plan-only proposal approved', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
+		await page.route(
+			(url) => /\/api\/v1\/repos\/[^/]+\/tasks\/15(\/|\?|$)/.test(url.pathname),
+			(route) => route.fulfill({ json: { data: { ...MOCK_TASK_PLAN_ONLY, plan_only: false, status: 'pending', attempt: 2 } } })
+		);
+		await page.route('**/api/v1/tasks/tsk_planonly01/proposal', (route) =>
+			route.fulfill({ json: { data: { ...MOCK_TASK_PROPOSAL, approved_at: '2025-06-01T09:15:00Z' } } })
+		);
+		await page.goto(`/acme/webapp/tasks/15`);
+
+		await page.waitForTimeout(2000);
+
+		await page.getByText('Proposal', { exact: true }).locator('xpath=../..').screenshot({
+			path: `screenshots/task-proposal-approved-${testInfo.project.name}.png`
+		});
+	});
+
+	test('task detail - failed artifacts', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
+		await page.goto(`/acme/webapp/tasks/6`);
+
+		await page.waitForTimeout(2000);
+
+		await page.getByText('Artifacts', { exact: true }).locator('xpath=../..').screenshot({
+			path: `screenshots/task-failed-artifacts-${testInfo.project.name}.png`
+		});
+	});
+
+	test('task detail - running attachments', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
+		await page.goto(`/acme/webapp/tasks/3`);
+
+		await page.waitForTimeout(2000);
+
+		await page.getByText('Attachments', { exact: true }).locator('xpath=../..').screenshot({
+			path: `screenshots/task-running-attachments-${testInfo.project.name}.png`
+		});
+	});
+
+	test('task detail - retry running', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
+		await page.goto(`/acme/webapp/tasks/7`);
+
+		await page.waitForTimeout(2000);
+
+		await page.screenshot({
+			path: `screenshots/task-retry-running-${testInfo.project.name}.png`,
+			fullPage: true
+		});
+	});
+
+	test('task detail - retry pending', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
+		await page.goto(`/acme/webapp/tasks/8`);
+
+		await page.waitForTimeout(2000);
+
+		await page.screenshot({
+			path: `screenshots/task-retry-pending-${testInfo.project.name}.png`,
+			fullPage: true
+		});
+	});
+
+	test('task detail - not ready', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
+		await page.goto(`/acme/webapp/tasks/2`);
+
+		await page.waitForTimeout(2000);
+
+		await page.screenshot({
+			path: `screenshots/task-not-ready-${testInfo.project.name}.png`,
+			fullPage: true
+		});
+	});
+
+	test('edit task dialog', async ({ page }, testInfo) => {
+		// Use a tall viewport so the dialog's max-h-[90vh] doesn't clip content.
+		await page.setViewportSize({ width: 1280, height: 1600 });
+		await setupMockAPI(page);
+		await page.goto('/acme/webapp/tasks/1');
+
+		// Wait for the Edit button to be visible, confirming the task has loaded.
+		const editButton = page.getByRole('button', { name: /edit/i });
+		await editButton.waitFor({ timeout: 15000 });
+		await editButton.click();
+
+		// Wait for dialog to appear and settle.
+		await page.waitForTimeout(1000);
+
+		// Screenshot the dialog element directly to capture its full content.
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.screenshot({
+			path: `screenshots/edit-task-dialog-${testInfo.project.name}.png`
+		});
+	});
+
+	test('create task dialog', async ({ page }, testInfo) => {
+		// Use a tall viewport so the dialog's max-h-[90vh] doesn't clip content.
+		await page.setViewportSize({ width: 1280, height: 1600 });
+		await setupMockAPI(page);
+		await page.goto('/');
+
+		// Wait for dashboard to load.
+		await page.waitForSelector('[data-testid="task-card"], .task-card, [class*="Card"]', {
+			timeout: 5000
+		}).catch(() => {});
+		await page.waitForTimeout(1000);
+
+		// Click the "New Task" button to open the dialog.
+		const createButton = page.getByRole('button', { name: /new task/i });
+		await createButton.click();
+
+		// Wait for dialog to appear and settle.
+		await page.waitForTimeout(1000);
+
+		// Screenshot the dialog element directly to capture its full content.
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.screenshot({
+			path: `screenshots/create-task-dialog-${testInfo.project.name}.png`
+		});
+	});
+
+	test('create task dialog - additional repos', async ({ page }, testInfo) => {
+		await page.setViewportSize({ width: 1280, height: 1600 });
+		await setupMockAPI(page, undefined, [MOCK_REPO_API]);
+		await page.goto('/');
+		await page.waitForTimeout(1000);
+
+		await page.getByRole('button', { name: /new task/i }).click();
+		await page.waitForTimeout(500);
+		await page.getByRole('button', { name: /advanced options/i }).click();
+		await page.getByText('acme/api', { exact: true }).click();
+		await page.waitForTimeout(500);
+
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.screenshot({
+			path: `screenshots/create-task-dialog-additional-repos-${testInfo.project.name}.png`
+		});
+	});
+
+	// --- Metrics Screenshots ---
+
+	test('metrics dashboard', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
+		await page.goto('/agents');
+
+		// Wait for metrics to load - ensure we see actual data, not loading state.
+		// Wait for the Tasks heading to appear
+		await page.waitForSelector('h2:has-text("Tasks")', { timeout: 5000 });
+		// Wait for specific numeric values to be rendered in the metric cards
+		await page.waitForSelector('.text-2xl.font-bold', { timeout: 5000 });
+		// Wait for Connected Workers section to ensure full page load
+		await page.waitForSelector('h2:has-text("Connected Workers")', { timeout: 5000 });
+		// Additional wait to ensure all components have rendered
+		await page.waitForTimeout(1500);
+
+		await page.screenshot({
+			path: `screenshots/metrics-dashboard-${testInfo.project.name}.png`,
+			fullPage: true
+		});
+	});
+
+	test('metrics - throughput report', async ({ page }, testInfo) => {
+		await setupMockAPI(page, undefined, [MOCK_REPO_API]);
+		await page.goto('/agents');
+		await page.waitForTimeout(2000);
+
+		const section = page.getByText('Throughput', { exact: true }).locator('xpath=../..');
+		await section.screenshot({
+			path: `screenshots/metrics-throughput-${testInfo.project.name}.png`
+		});
+	});
+
+	// --- Team Screenshots ---
+
+	test('teams page', async ({ page }, testInfo) => {
+		await setupMockAPI(page, MOCK_REPO_WITH_TEAM, [MOCK_REPO_API]);
+		await page.goto('/teams');
+
+		// Wait for the team cards and the spend rollup to render.
+		await page.waitForSelector('h3:has-text("Platform")', { timeout: 5000 });
+		await page.waitForTimeout(1500);
+
+		await page.screenshot({
+			path: `screenshots/teams-${testInfo.project.name}.png`,
+			fullPage: true
+		});
+	});
+
+	test('teams page - empty state', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
+
+		// Override teams routes to return no teams and no spend.
+		await page.route('**/api/v1/teams', (route) => route.fulfill({ json: { data: [] } }));
+		await page.route('**/api/v1/teams/costs**', (route) =>
+			route.fulfill({ json: { data: { days: 30, teams: [] } } })
+		);
+
+		await page.goto('/teams');
+		await page.waitForSelector('text=No teams yet', { timeout: 5000 });
+		await page.waitForTimeout(1000);
+
+		await page.screenshot({
+			path: `screenshots/teams-empty-${testInfo.project.name}.png`,
+			fullPage: true
+		});
+	});
+
+	// --- GitHub Credential Screenshots ---
+
+	test('settings dialog - github credentials', async ({ page }, testInfo) => {
+		await page.setViewportSize({ width: 1280, height: 1600 });
+		await setupMockAPI(page);
+		await page.goto('/');
+
+		await page.waitForTimeout(1500);
+
+		await page.getByTitle('Settings').click();
+		await page.waitForTimeout(1000);
+
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.getByText('Repo & Org Credentials', { exact: true }).locator('xpath=../../..').screenshot({
+			path: `screenshots/settings-github-credentials-${testInfo.project.name}.png`
+		});
+	});
+
+	test('settings dialog - github credentials editing app', async ({ page }, testInfo) => {
+		await page.setViewportSize({ width: 1280, height: 1600 });
+		await setupMockAPI(page);
+		await page.goto('/');
+
+		await page.waitForTimeout(1500);
+
+		await page.getByTitle('Settings').click();
+		await page.waitForTimeout(1000);
+
+		const dialog = page.locator('[role="dialog"]');
+		const section = dialog.getByText('Repo & Org Credentials', { exact: true }).locator('xpath=../../..');
+		await section.getByRole('button', { name: 'Edit' }).nth(1).click();
+		await page.waitForSelector('#ghc-name', { timeout: 5000 });
+		await page.waitForTimeout(500);
+
+		await section.screenshot({
+			path: `screenshots/settings-github-credentials-editing-${testInfo.project.name}.png`
+		});
+	});
+
+	// --- Queue Pause Screenshots ---
+
+	test('settings dialog - queue paused', async ({ page }, testInfo) => {
+		await page.setViewportSize({ width: 1280, height: 1600 });
+		await setupMockAPI(page);
+		await page.route('**/api/v1/settings/queue-paused', (route) =>
+			route.fulfill({ json: { data: MOCK_QUEUE_PAUSED } })
+		);
+		await page.goto('/');
+
+		await page.waitForTimeout(1500);
+
+		await page.getByTitle('Settings').click();
+		await page.waitForTimeout(1000);
+
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.getByText('Queue', { exact: true }).locator('xpath=../../..').screenshot({
+			path: `screenshots/settings-queue-paused-${testInfo.project.name}.png`
+		});
+	});
+
+	test('repo settings dialog - queue paused', async ({ page }, testInfo) => {
+		await page.setViewportSize({ width: 1280, height: 900 });
+		await setupMockAPI(page, MOCK_REPO_PAUSED);
+		await page.goto('/');
+
+		await page.waitForTimeout(1500);
+
+		const repoSettingsBtn = page.getByRole('button', { name: /repo settings/i });
+		await repoSettingsBtn.click();
+
+		await page.waitForTimeout(1000);
+
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.locator('h3', { hasText: /^\s*Queue/ }).locator('xpath=../../..').screenshot({
+			path: `screenshots/repo-settings-queue-paused-${testInfo.project.name}.png`
+		});
+	});
+
+	// --- Runtime Settings Screenshots ---
+
+	test('settings dialog - runtime settings', async ({ page }, testInfo) => {
+		await page.setViewportSize({ width: 1280, height: 1600 });
+		await setupMockAPI(page);
+		await page.goto('/');
+
+		await page.waitForTimeout(1500);
+
+		await page.getByTitle('Settings').click();
+		await page.waitForTimeout(1000);
+
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.getByText('Runtime', { exact: true }).locator('xpath=../..').screenshot({
+			path: `screenshots/settings-runtime-${testInfo.project.name}.png`
+		});
+	});
+
+	test('settings dialog - runtime settings editing', async ({ page }, testInfo) => {
+		await page.setViewportSize({ width: 1280, height: 1600 });
+		await setupMockAPI(page);
+		await page.goto('/');
+
+		await page.waitForTimeout(1500);
+
+		await page.getByTitle('Settings').click();
+		await page.waitForTimeout(1000);
+
+		await page.locator('#runtime-reap_interval').fill('30s');
+		await page.waitForTimeout(300);
+
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.getByText('Runtime', { exact: true }).locator('xpath=../..').screenshot({
+			path: `screenshots/settings-runtime-editing-${testInfo.project.name}.png`
+		});
+	});
+
+	// --- PR Sync Screenshots ---
+
+	test('repo settings dialog - pr sync interval', async ({ page }, testInfo) => {
+		await page.setViewportSize({ width: 1280, height: 900 });
+		await setupMockAPI(page, MOCK_REPO_WITH_SYNC_INTERVAL);
+		await page.goto('/');
+
+		await page.waitForTimeout(1500);
+
+		const repoSettingsBtn = page.getByRole('button', { name: /repo settings/i });
+		await repoSettingsBtn.click();
+
+		await page.waitForTimeout(1000);
+
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.locator('h3', { hasText: /^\s*PR Sync/ }).locator('xpath=../../..').screenshot({
+			path: `screenshots/repo-settings-pr-sync-interval-${testInfo.project.name}.png`
 		});
 	});
 
-	test('dashboard - repo needs setup banner', async ({ page }, testInfo) => {
-		await setupMockAPI(page, MOCK_REPO_NEEDS_SETUP);
+	test('repo settings dialog - pr sync off', async ({ page }, testInfo) => {
+		await page.setViewportSize({ width: 1280, height: 900 });
+		await setupMockAPI(page, MOCK_REPO_SYNC_DISABLED);
 		await page.goto('/');
 
-		// Wait for the needs-setup banner to render (no kanban columns visible).
-		await page.waitForSelector('text=Repository needs configuration', { timeout: 5000 });
 		await page.waitForTimeout(1500);
 
-		// Expand the "Scan Results" section to show the RepoSummary
-		const scanResultsBtn = page.getByText('Scan Results');
-		if (await scanResultsBtn.isVisible()) {
-			await scanResultsBtn.click();
-			await page.waitForTimeout(500);
-		}
+		const repoSettingsBtn = page.getByRole('button', { name: /repo settings/i });
+		await repoSettingsBtn.click();
 
-		await page.screenshot({
-			path: `screenshots/repo-setup-needs-setup-${testInfo.project.name}.png`,
-			fullPage: true
+		await page.waitForTimeout(1000);
+
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.locator('h3', { hasText: /^\s*PR Sync/ }).locator('xpath=../../..').screenshot({
+			path: `screenshots/repo-settings-pr-sync-off-${testInfo.project.name}.png`
 		});
 	});
 
-	test('repo setup wizard dialog', async ({ page }, testInfo) => {
-		await page.setViewportSize({ width: 1280, height: 1600 });
-		await setupMockAPI(page, MOCK_REPO_NEEDS_SETUP);
+	// --- Review SLA Screenshots ---
+
+	test('repo settings dialog - review sla', async ({ page }, testInfo) => {
+		await page.setViewportSize({ width: 1280, height: 900 });
+		await setupMockAPI(page, MOCK_REPO_WITH_REVIEW_SLA);
 		await page.goto('/');
 
-		// Wait for the needs-setup banner to render.
-		await page.waitForSelector('text=Repository needs configuration', { timeout: 5000 });
 		await page.waitForTimeout(1500);
 
-		// Click the "Configure" button in the needs_setup banner to open the wizard
-		const configureBtn = page.getByRole('button', { name: /configure/i });
-		await configureBtn.click();
+		const repoSettingsBtn = page.getByRole('button', { name: /repo settings/i });
+		await repoSettingsBtn.click();
 
-		// Wait for dialog to appear and settle.
 		await page.waitForTimeout(1000);
 
-		// Screenshot the dialog element directly to capture its full content.
 		const dialog = page.locator('[role="dialog"]');
-		await dialog.screenshot({
-			path: `screenshots/repo-setup-wizard-${testInfo.project.name}.png`
+		await dialog.locator('h3', { hasText: /^\s*Review SLA/ }).locator('xpath=../../..').screenshot({
+			path: `screenshots/repo-settings-review-sla-${testInfo.project.name}.png`
 		});
 	});
 
-	test('repo setup wizard dialog - empty tech stack', async ({ page }, testInfo) => {
-		await page.setViewportSize({ width: 1280, height: 1600 });
-		await setupMockAPI(page, MOCK_REPO_NEEDS_SETUP_EMPTY);
+	test('repo settings dialog - review sla editing', async ({ page }, testInfo) => {
+		await page.setViewportSize({ width: 1280, height: 900 });
+		await setupMockAPI(page, MOCK_REPO_WITH_REVIEW_SLA);
 		await page.goto('/');
 
-		// Wait for the needs-setup banner to render.
-		await page.waitForSelector('text=Repository needs configuration', { timeout: 5000 });
 		await page.waitForTimeout(1500);
 
-		// Click the "Configure" button in the needs_setup banner to open the wizard
-		const configureBtn = page.getByRole('button', { name: /configure/i });
-		await configureBtn.click();
+		const repoSettingsBtn = page.getByRole('button', { name: /repo settings/i });
+		await repoSettingsBtn.click();
 
-		// Wait for dialog to appear and settle.
 		await page.waitForTimeout(1000);
 
-		// Screenshot the dialog element directly to capture its full content.
 		const dialog = page.locator('[role="dialog"]');
-		await dialog.screenshot({
-			path: `screenshots/repo-setup-wizard-empty-stack-${testInfo.project.name}.png`
+		await dialog
+			.locator('h3', { hasText: /^\s*Review SLA/ })
+			.locator('xpath=../..')
+			.getByRole('button', { name: /edit/i })
+			.click();
+		await page.waitForSelector('#review-sla-hours', { timeout: 5000 });
+		await page.waitForTimeout(300);
+
+		await dialog.locator('#review-sla-hours').locator('xpath=../..').screenshot({
+			path: `screenshots/repo-settings-review-sla-editing-${testInfo.project.name}.png`
 		});
 	});
 
-	test('dashboard - repo configuring banner', async ({ page }, testInfo) => {
-		await setupMockAPI(page, MOCK_REPO_CONFIGURING);
+	// --- Trash Screenshots ---
+
+	test('dashboard - bulk actions', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
 		await page.goto('/');
 
-		// Wait for the configuring banner to render.
-		await page.waitForSelector('text=AI reviewing configuration', { timeout: 5000 });
 		await page.waitForTimeout(1500);
 
+		await page.getByRole('button', { name: 'Select', exact: true }).click();
+		for (const title of ['Refactor payment processing module', 'Add dark mode support', 'Migrate to new ORM']) {
+			await page.getByText(title, { exact: true }).click();
+		}
+		await page.waitForTimeout(500);
+
 		await page.screenshot({
-			path: `screenshots/repo-setup-configuring-${testInfo.project.name}.png`,
+			path: `screenshots/dashboard-bulk-actions-${testInfo.project.name}.png`,
 			fullPage: true
 		});
 	});
 
-	test('dashboard - repo pending setup banner', async ({ page }, testInfo) => {
-		await setupMockAPI(page, MOCK_REPO_PENDING);
+	test('dashboard - bulk close with skipped tasks', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
+		// The review task was merged after it was selected.
+		await page.route('**/api/v1/repos/*/tasks/bulk-action', (route) =>
+			route.fulfill({
+				json: {
+					data: {
+						results: [
+							{ task_id: 'tsk_notready01', result: 'applied' },
+							{ task_id: 'tsk_review01', result: 'skipped', reason: 'task is already closed or merged' }
+						]
+					}
+				}
+			})
+		);
 		await page.goto('/');
 
-		// Wait for the pending setup banner to render (no kanban columns visible).
-		await page.waitForSelector('text=Repository setup required', { timeout: 5000 });
 		await page.waitForTimeout(1500);
 
+		await page.getByRole('button', { name: 'Select', exact: true }).click();
+		for (const title of ['Refactor payment processing module', 'Add dark mode support']) {
+			await page.getByText(title, { exact: true }).click();
+		}
+		await page.getByRole('button', { name: /close \(2\)/i }).click();
+		await page.locator('[role="dialog"] textarea').fill('Superseded by the checkout rewrite');
+		await page.locator('[role="dialog"]').screenshot({
+			path: `screenshots/dashboard-bulk-close-dialog-${testInfo.project.name}.png`
+		});
+
+		await page.locator('[role="dialog"]').getByRole('button', { name: 'Close Tasks' }).click();
+		await page.waitForSelector('text=1 task was skipped', { timeout: 5000 });
+		await page.waitForTimeout(500);
+
 		await page.screenshot({
-			path: `screenshots/repo-setup-pending-${testInfo.project.name}.png`,
+			path: `screenshots/dashboard-bulk-skipped-${testInfo.project.name}.png`,
 			fullPage: true
 		});
 	});
 
-	test('repo settings dialog', async ({ page }, testInfo) => {
-		await page.setViewportSize({ width: 1280, height: 900 });
-		await setupMockAPI(page, MOCK_REPO_NEEDS_SETUP);
+	test('dashboard - trash', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
+		await page.route('**/api/v1/capabilities', (route) =>
+			route.fulfill({ json: { data: MOCK_CAPABILITIES_TRASH } })
+		);
 		await page.goto('/');
 
 		await page.waitForTimeout(1500);
 
-		// Click the "Repo Settings" sidebar link
-		const repoSettingsBtn = page.getByRole('button', { name: /repo settings/i });
-		await repoSettingsBtn.click();
-
+		await page.getByRole('button', { name: /trash/i }).click();
 		await page.waitForTimeout(1000);
 
 		const dialog = page.locator('[role="dialog"]');
 		await dialog.screenshot({
-			path: `screenshots/repo-settings-dialog-${testInfo.project.name}.png`
+			path: `screenshots/dashboard-trash-${testInfo.project.name}.png`
 		});
 	});
 
-	test('repo settings dialog with markdown expectations', async ({ page }, testInfo) => {
-		await page.setViewportSize({ width: 1280, height: 900 });
-		await setupMockAPI(page, MOCK_REPO_WITH_EXPECTATIONS);
+	test('dashboard - trash empty', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
+		await page.route('**/api/v1/capabilities', (route) =>
+			route.fulfill({ json: { data: MOCK_CAPABILITIES_TRASH } })
+		);
+		await page.route('**/api/v1/repos/*/tasks/trash', (route) =>
+			route.fulfill({ json: { data: [] } })
+		);
 		await page.goto('/');
 
 		await page.waitForTimeout(1500);
 
-		// Click the "Repo Settings" sidebar link
-		const repoSettingsBtn = page.getByRole('button', { name: /repo settings/i });
-		await repoSettingsBtn.click();
+		await page.getByRole('button', { name: /trash/i }).click();
+		await page.waitForTimeout(1000);
+
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.screenshot({
+			path: `screenshots/dashboard-trash-empty-${testInfo.project.name}.png`
+		});
+	});
+
+	// --- Archive Screenshots ---
 
+	test('dashboard - archived tasks', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
+		await page.route('**/api/v1/capabilities', (route) =>
+			route.fulfill({ json: { data: MOCK_CAPABILITIES_ARCHIVE } })
+		);
+		await page.goto('/');
+
+		await page.waitForTimeout(1500);
+
+		await page.getByRole('button', { name: /archived/i }).click();
 		await page.waitForTimeout(1000);
 
 		const dialog = page.locator('[role="dialog"]');
 		await dialog.screenshot({
-			path: `screenshots/repo-settings-markdown-${testInfo.project.name}.png`
+			path: `screenshots/dashboard-archived-${testInfo.project.name}.png`
 		});
 	});
 
-	test('task detail - review', async ({ page }, testInfo) => {
+	test('task detail - archived', async ({ page }, testInfo) => {
 		await setupMockAPI(page);
-		await page.goto(`/acme/webapp/tasks/4`);
+		await page.route(
+			(url) => /\/api\/v1\/repos\/[^/]+\/tasks\/13(\/|\?|$)/.test(url.pathname),
+			(route) => route.fulfill({ json: { data: MOCK_ARCHIVED_TASKS[0] } })
+		);
+		await page.goto(`/acme/webapp/tasks/13`);
 
 		await page.waitForTimeout(2000);
 
-		await page.screenshot({
-			path: `screenshots/task-detail-${testInfo.project.name}.png`,
-			fullPage: true
+		await page.getByText('Archived', { exact: true }).locator('xpath=../../..').screenshot({
+			path: `screenshots/task-archived-${testInfo.project.name}.png`
 		});
 	});
 
-	test('task detail - pr view', async ({ page }, testInfo) => {
+	// --- Auth Screenshots ---
+
+	test('settings dialog - single sign-on', async ({ page }, testInfo) => {
+		await page.setViewportSize({ width: 1280, height: 1600 });
 		await setupMockAPI(page);
-		await page.goto(`/acme/webapp/tasks/4/pr`);
+		await page.goto('/');
 
-		// Wait for the diff to auto-expand and render (file headers appear once loaded).
-		await page.waitForSelector('table', { timeout: 15000 });
-		await page.waitForTimeout(500);
+		await page.waitForTimeout(1500);
 
-		await page.screenshot({
-			path: `screenshots/task-pr-view-${testInfo.project.name}.png`,
-			fullPage: true
+		await page.getByTitle('Settings').click();
+		await page.waitForTimeout(1000);
+
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.getByText('Single Sign-On', { exact: true }).locator('xpath=../../..').screenshot({
+			path: `screenshots/settings-sso-${testInfo.project.name}.png`
 		});
 	});
 
-	test('task detail - running', async ({ page }, testInfo) => {
+	test('settings dialog - single sign-on editing', async ({ page }, testInfo) => {
+		await page.setViewportSize({ width: 1280, height: 1600 });
 		await setupMockAPI(page);
-		await page.goto(`/acme/webapp/tasks/3`);
+		await page.goto('/');
 
-		await page.waitForTimeout(2000);
+		await page.waitForTimeout(1500);
 
-		await page.screenshot({
-			path: `screenshots/task-running-${testInfo.project.name}.png`,
-			fullPage: true
+		await page.getByTitle('Settings').click();
+		await page.waitForTimeout(1000);
+
+		const dialog = page.locator('[role="dialog"]');
+		const section = dialog.getByText('Single Sign-On', { exact: true }).locator('xpath=../../..');
+		await section.getByRole('button', { name: /edit/i }).click();
+		await page.waitForSelector('#oidc-issuer', { timeout: 5000 });
+		await page.waitForTimeout(500);
+
+		await section.screenshot({
+			path: `screenshots/settings-sso-editing-${testInfo.project.name}.png`
 		});
 	});
 
-	test('task detail - retry running', async ({ page }, testInfo) => {
+	test('sign in dialog - single sign-on', async ({ page }, testInfo) => {
 		await setupMockAPI(page);
-		await page.goto(`/acme/webapp/tasks/7`);
 
-		await page.waitForTimeout(2000);
+		// Require sign-in so the token dialog opens on load.
+		await page.route('**/api/v1/capabilities', (route) =>
+			route.fulfill({ json: { data: MOCK_CAPABILITIES_AUTH } })
+		);
 
-		await page.screenshot({
-			path: `screenshots/task-retry-running-${testInfo.project.name}.png`,
-			fullPage: true
+		await page.goto('/');
+		await page.waitForSelector('text=Sign in with SSO', { timeout: 5000 });
+		await page.waitForTimeout(1000);
+
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.screenshot({
+			path: `screenshots/sign-in-sso-${testInfo.project.name}.png`
 		});
 	});
 
-	test('task detail - retry pending', async ({ page }, testInfo) => {
+	test('sign in dialog - api token', async ({ page }, testInfo) => {
 		await setupMockAPI(page);
-		await page.goto(`/acme/webapp/tasks/8`);
 
-		await page.waitForTimeout(2000);
+		// Require sign-in without single sign-on so only the token form shows.
+		await page.route('**/api/v1/capabilities', (route) =>
+			route.fulfill({ json: { data: MOCK_CAPABILITIES_AUTH } })
+		);
+		await page.route('**/api/v1/auth/oidc', (route) =>
+			route.fulfill({ json: { data: { enabled: false } } })
+		);
 
-		await page.screenshot({
-			path: `screenshots/task-retry-pending-${testInfo.project.name}.png`,
-			fullPage: true
+		await page.goto('/');
+		await page.waitForSelector('input[placeholder="vu_..."]', { timeout: 5000 });
+		await page.waitForTimeout(1000);
+
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.screenshot({
+			path: `screenshots/sign-in-token-${testInfo.project.name}.png`
 		});
 	});
 
-	test('task detail - not ready', async ({ page }, testInfo) => {
+	test('sign in dialog - invalid api token', async ({ page }, testInfo) => {
 		await setupMockAPI(page);
-		await page.goto(`/acme/webapp/tasks/2`);
 
-		await page.waitForTimeout(2000);
+		await page.route('**/api/v1/capabilities', (route) =>
+			route.fulfill({ json: { data: MOCK_CAPABILITIES_AUTH } })
+		);
+		await page.route('**/api/v1/auth/oidc', (route) =>
+			route.fulfill({ json: { data: { enabled: false } } })
+		);
+		await page.route('**/api/v1/users/me', (route) =>
+			route.fulfill({ status: 401, json: { error: { message: 'invalid API token' } } })
+		);
 
-		await page.screenshot({
-			path: `screenshots/task-not-ready-${testInfo.project.name}.png`,
-			fullPage: true
+		await page.goto('/');
+		await page.waitForSelector('input[placeholder="vu_..."]', { timeout: 5000 });
+
+		const dialog = page.locator('[role="dialog"]');
+		await dialog.locator('input[placeholder="vu_..."]').fill('vu_revoked');
+		await dialog.getByRole('button', { name: 'Sign In' }).click();
+		await page.waitForSelector('text=invalid API token', { timeout: 5000 });
+		await page.waitForTimeout(500);
+
+		await dialog.screenshot({
+			path: `screenshots/sign-in-token-invalid-${testInfo.project.name}.png`
 		});
 	});
 
-	test('edit task dialog', async ({ page }, testInfo) => {
-		// Use a tall viewport so the dialog's max-h-[90vh] doesn't clip content.
+	test('settings dialog - users', async ({ page }, testInfo) => {
 		await page.setViewportSize({ width: 1280, height: 1600 });
 		await setupMockAPI(page);
-		await page.goto('/acme/webapp/tasks/1');
+		await page.route('**/api/v1/capabilities', (route) =>
+			route.fulfill({ json: { data: MOCK_CAPABILITIES_AUTH } })
+		);
+		// Sign in as the admin so the settings load.
+		await page.addInitScript((token) => localStorage.setItem('verve.apiToken', token), MOCK_USER_TOKEN);
+		await page.goto('/');
 
-		// Wait for the Edit button to be visible, confirming the task has loaded.
-		const editButton = page.getByRole('button', { name: /edit/i });
-		await editButton.waitFor({ timeout: 15000 });
-		await editButton.click();
+		await page.waitForTimeout(1500);
 
-		// Wait for dialog to appear and settle.
+		await page.getByTitle('Settings').click();
 		await page.waitForTimeout(1000);
 
-		// Screenshot the dialog element directly to capture its full content.
 		const dialog = page.locator('[role="dialog"]');
-		await dialog.screenshot({
-			path: `screenshots/edit-task-dialog-${testInfo.project.name}.png`
+		await dialog.getByText('Users', { exact: true }).locator('xpath=../../..').screenshot({
+			path: `screenshots/settings-users-${testInfo.project.name}.png`
 		});
 	});
 
-	test('create task dialog', async ({ page }, testInfo) => {
-		// Use a tall viewport so the dialog's max-h-[90vh] doesn't clip content.
+	test('settings dialog - new user token shown once', async ({ page }, testInfo) => {
 		await page.setViewportSize({ width: 1280, height: 1600 });
 		await setupMockAPI(page);
+		await page.route('**/api/v1/capabilities', (route) =>
+			route.fulfill({ json: { data: MOCK_CAPABILITIES_AUTH } })
+		);
+		await page.addInitScript((token) => localStorage.setItem('verve.apiToken', token), MOCK_USER_TOKEN);
 		await page.goto('/');
 
-		// Wait for dashboard to load.
-		await page.waitForSelector('[data-testid="task-card"], .task-card, [class*="Card"]', {
-			timeout: 5000
-		}).catch(() => {});
-		await page.waitForTimeout(1000);
-
-		// Click the "New Task" button to open the dialog.
-		const createButton = page.getByRole('button', { name: /new task/i });
-		await createButton.click();
+		await page.waitForTimeout(1500);
 
-		// Wait for dialog to appear and settle.
+		await page.getByTitle('Settings').click();
 		await page.waitForTimeout(1000);
 
-		// Screenshot the dialog element directly to capture its full content.
 		const dialog = page.locator('[role="dialog"]');
-		await dialog.screenshot({
-			path: `screenshots/create-task-dialog-${testInfo.project.name}.png`
+		const section = dialog.getByText('Users', { exact: true }).locator('xpath=../../..');
+		await section.getByRole('button', { name: /add user/i }).click();
+		await page.fill('#user-name', 'ci-bot');
+		await page.selectOption('#user-role', 'operator');
+		await section.getByRole('button', { name: /create/i }).click();
+		await page.waitForSelector(`text=${MOCK_USER_TOKEN}`, { timeout: 5000 });
+		await page.waitForTimeout(500);
+
+		await section.screenshot({
+			path: `screenshots/settings-users-token-created-${testInfo.project.name}.png`
 		});
 	});
 
-	// --- Metrics Screenshots ---
-
-	test('metrics dashboard', async ({ page }, testInfo) => {
+	test('single sign-on callback - error', async ({ page }, testInfo) => {
 		await setupMockAPI(page);
-		await page.goto('/agents');
+		// The server form-encodes the reason the sign-in was refused.
+		await page.goto('/auth/callback#error=none+of+your+groups+are+allowed+to+use+Verve');
 
-		// Wait for metrics to load - ensure we see actual data, not loading state.
-		// Wait for the Tasks heading to appear
-		await page.waitForSelector('h2:has-text("Tasks")', { timeout: 5000 });
-		// Wait for specific numeric values to be rendered in the metric cards
-		await page.waitForSelector('.text-2xl.font-bold', { timeout: 5000 });
-		// Wait for Connected Workers section to ensure full page load
-		await page.waitForSelector('h2:has-text("Connected Workers")', { timeout: 5000 });
-		// Additional wait to ensure all components have rendered
-		await page.waitForTimeout(1500);
+		await page.waitForSelector('text=none of your groups are allowed to use Verve', { timeout: 5000 });
+		await page.waitForTimeout(500);
 
 		await page.screenshot({
-			path: `screenshots/metrics-dashboard-${testInfo.project.name}.png`,
+			path: `screenshots/sso-callback-error-${testInfo.project.name}.png`,
 			fullPage: true
 		});
 	});
@@ -1686,6 +3579,42 @@ test.describe('UI Screenshots', () => {
 		});
 	});
 
+	test('epic detail - shared integration branch', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
+		await page.goto('/acme/webapp/epics/6');
+
+		await page.waitForTimeout(2000);
+
+		await page.screenshot({
+			path: `screenshots/epic-shared-branch-${testInfo.project.name}.png`,
+			fullPage: true
+		});
+	});
+
+	test('epic detail - burndown with no completed tasks', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
+		await page.goto('/acme/webapp/epics/4');
+
+		await page.waitForTimeout(2000);
+
+		await page
+			.getByText('Burndown', { exact: true })
+			.locator('xpath=../..')
+			.screenshot({ path: `screenshots/epic-burndown-empty-${testInfo.project.name}.png` });
+	});
+
+	test('epic detail - burndown', async ({ page }, testInfo) => {
+		await setupMockAPI(page);
+		await page.goto('/acme/webapp/epics/7');
+
+		await page.waitForTimeout(2000);
+
+		await page
+			.getByText('Burndown', { exact: true })
+			.locator('xpath=../..')
+			.screenshot({ path: `screenshots/epic-burndown-${testInfo.project.name}.png` });
+	});
+
 	test('epic detail - redirect from old ID route', async ({ page }, testInfo) => {
 		await setupMockAPI(page);
 		await page.goto('/epics/epc_draft01');
@@ -1,9 +1,48 @@
 import { API_BASE_URL } from './config/api';
-import type { Task } from './models/task';
-import type { Repo, GitHubRepo } from './models/repo';
-import type { Epic, ProposedTask } from './models/epic';
+import type {
+	AgentEvent,
+	BulkTaskActionRequest,
+	BulkTaskActionResult,
+	ProvenanceReview,
+	SelfReview,
+	ShadowDiff,
+	Proposal,
+	Task,
+	TaskArtifact,
+	TaskAttachment,
+	TaskAttempt,
+	TaskComment,
+	TaskNote,
+	TaskNudge,
+	TaskProgress
+} from './models/task';
+import type {
+	Repo,
+	GitHubRepo,
+	CIWorkflow,
+	CheckGating,
+	CompletionValidations,
+	RetryPolicy,
+	QueuePaused,
+	RuntimeSetting
+} from './models/repo';
+import type { Epic, EpicProgress, ProposedTask, DependencySuggestion } from './models/epic';
 import type { Conversation } from './models/conversation';
-import type { Metrics } from './models/metrics';
+import type { Metrics, FailureCategoryCount, ThroughputReport } from './models/metrics';
+import type { Capabilities } from './models/capabilities';
+import type {
+	NotificationSink,
+	NotificationSinkKind,
+	NotificationEventType,
+	TestNotificationSinkResult
+} from './models/notification';
+import type { Team, TeamCosts } from './models/team';
+import type { OIDCConfig, OIDCSettings, User, UserRole, UserToken } from './models/user';
+import type { GitHubCredential, GitHubCredentialInput, GitHubRateLimit } from './models/github';
+
+// Key of the user's API token in localStorage. Servers that require auth
+// reject requests without one.
+const API_TOKEN_KEY = 'verve.apiToken';
 
 export class VerveClient {
 	private baseUrl: string;
@@ -12,6 +51,62 @@ export class VerveClient {
 		this.baseUrl = API_BASE_URL + '/api/v1';
 	}
 
+	// --- Authentication ---
+
+	getToken(): string | null {
+		return typeof localStorage === 'undefined' ? null : localStorage.getItem(API_TOKEN_KEY);
+	}
+
+	setToken(token: string | null): void {
+		if (token) {
+			localStorage.setItem(API_TOKEN_KEY, token);
+		} else {
+			localStorage.removeItem(API_TOKEN_KEY);
+		}
+	}
+
+	// fetch sends the user's API token, when set, as a bearer token.
+	private fetch(url: string, init: RequestInit = {}): Promise<Response> {
+		const token = this.getToken();
+		if (!token) {
+			return fetch(url, init);
+		}
+		const headers = new Headers(init.headers);
+		headers.set('Authorization', `Bearer ${token}`);
+		return fetch(url, { ...init, headers });
+	}
+
+	// withToken adds the user's API token to a stream URL: EventSource and
+	// WebSocket connections can't set headers.
+	private withToken(url: string): string {
+		const token = this.getToken();
+		if (!token) {
+			return url;
+		}
+		return `${url}${url.includes('?') ? '&' : '?'}token=${encodeURIComponent(token)}`;
+	}
+
+	async getCurrentUser(): Promise<User> {
+		const res = await this.fetch(`${this.baseUrl}/users/me`);
+		return this.request<User>(res, 'Failed to verify API token');
+	}
+
+	async getOIDCStatus(): Promise<{ enabled: boolean }> {
+		const res = await fetch(`${this.baseUrl}/auth/oidc`);
+		return this.request(res, 'Failed to check single sign-on');
+	}
+
+	// oidcLoginURL starts a single sign-on that returns the user to redirect.
+	oidcLoginURL(redirect: string): string {
+		return `${this.baseUrl}/auth/oidc/login?redirect=${encodeURIComponent(redirect)}`;
+	}
+
+	async logout(): Promise<void> {
+		const res = await this.fetch(`${this.baseUrl}/auth/logout`, { method: 'POST' });
+		this.setToken(null);
+		return this.requestVoid(res, 'Failed to sign out');
+	}
+
 	private async request<T>(res: Response, fallbackError: string): Promise<T> {
 		if (!res.ok) {
 			const body = await res.json().catch(() => null);
@@ -31,12 +126,12 @@ export class VerveClient {
 	// --- Repo APIs ---
 
 	async listRepos(): Promise<Repo[]> {
-		const res = await fetch(`${this.baseUrl}/repos`);
+		const res = await this.fetch(`${this.baseUrl}/repos`);
 		return this.request<Repo[]>(res, 'Failed to fetch repos');
 	}
 
 	async addRepo(fullName: string): Promise<Repo> {
-		const res = await fetch(`${this.baseUrl}/repos`, {
+		const res = await this.fetch(`${this.baseUrl}/repos`, {
 			method: 'POST',
 			headers: { 'Content-Type': 'application/json' },
 			body: JSON.stringify({ full_name: fullName })
@@ -45,21 +140,21 @@ export class VerveClient {
 	}
 
 	async removeRepo(repoId: string): Promise<void> {
-		const res = await fetch(`${this.baseUrl}/repos/${repoId}`, {
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}`, {
 			method: 'DELETE'
 		});
 		return this.requestVoid(res, 'Failed to remove repo');
 	}
 
 	async listAvailableRepos(): Promise<GitHubRepo[]> {
-		const res = await fetch(`${this.baseUrl}/repos/available`);
+		const res = await this.fetch(`${this.baseUrl}/repos/available`);
 		return this.request<GitHubRepo[]>(res, 'Failed to list available repos');
 	}
 
 	// --- Repo Setup APIs ---
 
 	async getRepoSetup(repoId: string): Promise<Repo> {
-		const res = await fetch(`${this.baseUrl}/repos/${repoId}/setup`);
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}/setup`);
 		return this.request<Repo>(res, 'Failed to fetch repo setup');
 	}
 
@@ -69,10 +164,24 @@ export class VerveClient {
 			summary?: string;
 			expectations?: string;
 			tech_stack?: string[];
+			ci_workflow?: CIWorkflow;
+			protected_paths?: string[];
+			shadow_mode?: boolean;
+			completion_validations?: CompletionValidations;
+			retry_policy?: RetryPolicy;
+			max_runtime_seconds?: number;
+			required_labels?: string[];
+			ignored_checks?: string[];
+			check_gating?: CheckGating;
+			fallback_models?: string[];
+			sync_disabled?: boolean;
+			sync_interval_seconds?: number;
+			review_sla_seconds?: number;
+			team_id?: string;
 			mark_ready?: boolean;
 		}
 	): Promise<Repo> {
-		const res = await fetch(`${this.baseUrl}/repos/${repoId}/setup`, {
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}/setup`, {
 			method: 'PATCH',
 			headers: { 'Content-Type': 'application/json' },
 			body: JSON.stringify(updates)
@@ -81,14 +190,14 @@ export class VerveClient {
 	}
 
 	async rescanRepo(repoId: string): Promise<Repo> {
-		const res = await fetch(`${this.baseUrl}/repos/${repoId}/setup/rescan`, {
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}/setup/rescan`, {
 			method: 'POST'
 		});
 		return this.request<Repo>(res, 'Failed to trigger rescan');
 	}
 
 	async skipRepoSetup(repoId: string): Promise<Repo> {
-		const res = await fetch(`${this.baseUrl}/repos/${repoId}/setup/skip`, {
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}/setup/skip`, {
 			method: 'POST'
 		});
 		return this.request<Repo>(res, 'Failed to skip setup');
@@ -102,7 +211,7 @@ export class VerveClient {
 			tech_stack?: string[];
 		}
 	): Promise<Repo> {
-		const res = await fetch(`${this.baseUrl}/repos/${repoId}/setup/submit`, {
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}/setup/submit`, {
 			method: 'POST',
 			headers: { 'Content-Type': 'application/json' },
 			body: JSON.stringify(updates)
@@ -111,19 +220,55 @@ export class VerveClient {
 	}
 
 	async confirmRepoSetup(repoId: string): Promise<Repo> {
-		const res = await fetch(`${this.baseUrl}/repos/${repoId}/setup/confirm`, {
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}/setup/confirm`, {
 			method: 'POST'
 		});
 		return this.request<Repo>(res, 'Failed to confirm repo setup');
 	}
 
+	async invalidateWorkspaceCache(repoId: string): Promise<Repo> {
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}/workspace-cache`, {
+			method: 'DELETE'
+		});
+		return this.request<Repo>(res, 'Failed to clear workspace cache');
+	}
+
+	async syncRepo(repoId: string): Promise<void> {
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}/sync`, { method: 'POST' });
+		return this.requestVoid(res, 'Failed to sync repo');
+	}
+
+	async setRepoPaused(repoId: string, paused: boolean): Promise<Repo> {
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}/paused`, {
+			method: 'PUT',
+			headers: { 'Content-Type': 'application/json' },
+			body: JSON.stringify({ paused })
+		});
+		return this.request<Repo>(res, paused ? 'Failed to pause repo' : 'Failed to resume repo');
+	}
+
 	// --- Repo-scoped Task APIs ---
 
-	async listTasksByRepo(repoId: string): Promise<Task[]> {
-		const res = await fetch(`${this.baseUrl}/repos/${repoId}/tasks`);
+	async listTasksByRepo(repoId: string, includeArchived = false): Promise<Task[]> {
+		const query = includeArchived ? '?include_archived=true' : '';
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}/tasks${query}`);
 		return this.request<Task[]>(res, 'Failed to fetch tasks');
 	}
 
+	async listFailedTasks(
+		repoId: string,
+		category?: string
+	): Promise<{ tasks: Task[]; categories: FailureCategoryCount[] }> {
+		const query = category ? `?category=${encodeURIComponent(category)}` : '';
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}/tasks/failed${query}`);
+		return this.request<{ tasks: Task[]; categories: FailureCategoryCount[] }>(res, 'Failed to fetch failed tasks');
+	}
+
+	async listTrashedTasks(repoId: string): Promise<Task[]> {
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}/tasks/trash`);
+		return this.request<Task[]>(res, 'Failed to fetch trash');
+	}
+
 	async createTaskInRepo(
 		repoId: string,
 		title: string,
@@ -134,7 +279,12 @@ export class VerveClient {
 		skipPr?: boolean,
 		draftPr?: boolean,
 		model?: string,
-		notReady?: boolean
+		notReady?: boolean,
+		additionalRepoIds?: string[],
+		maxRuntimeSeconds?: number,
+		requiredLabels?: string[],
+		stackOn?: string,
+		planOnly?: boolean
 	): Promise<Task> {
 		const body: Record<string, unknown> = { title, description, depends_on: dependsOn };
 		if (acceptanceCriteria && acceptanceCriteria.length > 0)
@@ -144,7 +294,13 @@ export class VerveClient {
 		if (draftPr) body.draft_pr = true;
 		if (model) body.model = model;
 		if (notReady) body.not_ready = true;
-		const res = await fetch(`${this.baseUrl}/repos/${repoId}/tasks`, {
+		if (additionalRepoIds && additionalRepoIds.length > 0)
+			body.additional_repo_ids = additionalRepoIds;
+		if (maxRuntimeSeconds && maxRuntimeSeconds > 0) body.max_runtime_seconds = maxRuntimeSeconds;
+		if (requiredLabels && requiredLabels.length > 0) body.required_labels = requiredLabels;
+		if (stackOn) body.stack_on = stackOn;
+		if (planOnly) body.plan_only = true;
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}/tasks`, {
 			method: 'POST',
 			headers: { 'Content-Type': 'application/json' },
 			body: JSON.stringify(body)
@@ -153,7 +309,7 @@ export class VerveClient {
 	}
 
 	async syncRepoTasks(repoId: string): Promise<{ synced: number; merged: number }> {
-		const res = await fetch(`${this.baseUrl}/repos/${repoId}/tasks/sync`, {
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}/tasks/sync`, {
 			method: 'POST'
 		});
 		return this.request<{ synced: number; merged: number }>(res, 'Failed to sync tasks');
@@ -173,9 +329,12 @@ export class VerveClient {
 			draft_pr?: boolean;
 			model?: string;
 			not_ready?: boolean;
+			max_runtime_seconds?: number;
+			required_labels?: string[];
+			expected_version?: number;
 		}
 	): Promise<Task> {
-		const res = await fetch(`${this.baseUrl}/tasks/${id}`, {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}`, {
 			method: 'PATCH',
 			headers: { 'Content-Type': 'application/json' },
 			body: JSON.stringify(updates)
@@ -184,24 +343,24 @@ export class VerveClient {
 	}
 
 	async getTask(id: string): Promise<Task> {
-		const res = await fetch(`${this.baseUrl}/tasks/${id}`);
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}`);
 		return this.request<Task>(res, 'Task not found');
 	}
 
 	async getTaskByNumber(repoId: string, number: number): Promise<Task> {
-		const res = await fetch(`${this.baseUrl}/repos/${repoId}/tasks/${number}`);
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}/tasks/${number}`);
 		return this.request<Task>(res, 'Task not found');
 	}
 
 	async syncTask(id: string): Promise<Task> {
-		const res = await fetch(`${this.baseUrl}/tasks/${id}/sync`, {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/sync`, {
 			method: 'POST'
 		});
 		return this.request<Task>(res, 'Failed to sync task');
 	}
 
 	async stopTask(id: string): Promise<Task> {
-		const res = await fetch(`${this.baseUrl}/tasks/${id}/stop`, {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/stop`, {
 			method: 'POST',
 			headers: { 'Content-Type': 'application/json' }
 		});
@@ -209,7 +368,7 @@ export class VerveClient {
 	}
 
 	async closeTask(id: string, reason?: string): Promise<Task> {
-		const res = await fetch(`${this.baseUrl}/tasks/${id}/close`, {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/close`, {
 			method: 'POST',
 			headers: { 'Content-Type': 'application/json' },
 			body: JSON.stringify({ reason })
@@ -217,24 +376,205 @@ export class VerveClient {
 		return this.request<Task>(res, 'Failed to close task');
 	}
 
+	async approveTask(id: string, body?: string): Promise<Task> {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/approve`, {
+			method: 'POST',
+			headers: { 'Content-Type': 'application/json' },
+			body: JSON.stringify({ body })
+		});
+		return this.request<Task>(res, 'Failed to approve pull request');
+	}
+
+	async mergeTask(id: string, mergeMethod?: 'merge' | 'squash' | 'rebase'): Promise<Task> {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/merge`, {
+			method: 'POST',
+			headers: { 'Content-Type': 'application/json' },
+			body: JSON.stringify({ merge_method: mergeMethod })
+		});
+		return this.request<Task>(res, 'Failed to merge pull request');
+	}
+
 	async getTaskChecks(id: string): Promise<{
 		status: 'pending' | 'success' | 'failure' | 'error';
 		summary?: string;
 		failed_names?: string[];
 		check_runs_skipped?: boolean;
-		checks?: { name: string; status: string; conclusion: string; url: string }[];
+		checks?: { name: string; status: string; conclusion: string; url: string; ignored?: boolean; informational?: boolean }[];
 	}> {
-		const res = await fetch(`${this.baseUrl}/tasks/${id}/checks`);
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/checks`);
 		return this.request(res, 'Failed to fetch check status');
 	}
 
+	async rerunTaskChecks(id: string): Promise<{
+		status: 'pending' | 'success' | 'failure' | 'error';
+		summary?: string;
+		checks?: { name: string; status: string; conclusion: string; url: string; ignored?: boolean; informational?: boolean }[];
+	}> {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/checks/rerun`, {
+			method: 'POST',
+			headers: { 'Content-Type': 'application/json' }
+		});
+		return this.request(res, 'Failed to re-run checks');
+	}
+
+	async listTaskAttempts(id: string): Promise<TaskAttempt[]> {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/attempts`);
+		return this.request<TaskAttempt[]>(res, 'Failed to fetch task attempts');
+	}
+
+	async listTaskEvents(id: string, attempt?: number): Promise<AgentEvent[]> {
+		const query = attempt ? `?attempt=${attempt}` : '';
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/events${query}`);
+		return this.request<AgentEvent[]>(res, 'Failed to fetch task events');
+	}
+
+	async getTaskProgress(id: string): Promise<TaskProgress> {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/progress`);
+		return this.request<TaskProgress>(res, 'Failed to fetch task progress');
+	}
+
+	async getTaskProvenance(id: string): Promise<ProvenanceReview | null> {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/provenance`);
+		return this.request<ProvenanceReview | null>(res, 'Failed to fetch provenance review');
+	}
+
+	async acknowledgeTaskProvenance(id: string): Promise<ProvenanceReview> {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/provenance/acknowledge`, {
+			method: 'POST'
+		});
+		return this.request<ProvenanceReview>(res, 'Failed to acknowledge provenance findings');
+	}
+
+	async getTaskSelfReview(id: string): Promise<SelfReview | null> {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/self-review`);
+		return this.request<SelfReview | null>(res, 'Failed to fetch self-review');
+	}
+
+	async nudgeTask(id: string, message: string): Promise<TaskNudge> {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/nudge`, {
+			method: 'POST',
+			headers: { 'Content-Type': 'application/json' },
+			body: JSON.stringify({ message })
+		});
+		return this.request<TaskNudge>(res, 'Failed to send message');
+	}
+
+	async listTaskNudges(id: string): Promise<TaskNudge[]> {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/nudges`);
+		return this.request<TaskNudge[]>(res, 'Failed to fetch task messages');
+	}
+
+	async listTaskComments(id: string): Promise<TaskComment[]> {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/comments`);
+		return this.request<TaskComment[]>(res, 'Failed to fetch comments');
+	}
+
+	async createTaskComment(id: string, body: string, attempt?: number): Promise<TaskComment> {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/comments`, {
+			method: 'POST',
+			headers: { 'Content-Type': 'application/json' },
+			body: JSON.stringify({ body, attempt })
+		});
+		return this.request<TaskComment>(res, 'Failed to add comment');
+	}
+
+	async updateTaskComment(id: string, commentId: number, body: string): Promise<TaskComment> {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/comments/${commentId}`, {
+			method: 'PATCH',
+			headers: { 'Content-Type': 'application/json' },
+			body: JSON.stringify({ body })
+		});
+		return this.request<TaskComment>(res, 'Failed to update comment');
+	}
+
+	async deleteTaskComment(id: string, commentId: number): Promise<void> {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/comments/${commentId}`, {
+			method: 'DELETE'
+		});
+		return this.requestVoid(res, 'Failed to delete comment');
+	}
+
+	async listTaskAttachments(id: string): Promise<TaskAttachment[]> {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/attachments`);
+		return this.request<TaskAttachment[]>(res, 'Failed to fetch attachments');
+	}
+
+	async uploadTaskAttachment(id: string, file: File): Promise<TaskAttachment> {
+		const form = new FormData();
+		form.append('file', file);
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/attachments`, {
+			method: 'POST',
+			body: form
+		});
+		return this.request<TaskAttachment>(res, 'Failed to upload attachment');
+	}
+
+	// downloadTaskAttachment fetches an attachment's contents. It is fetched
+	// rather than linked to so the API token is sent.
+	async downloadTaskAttachment(id: string, attachmentId: number): Promise<Blob> {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/attachments/${attachmentId}`);
+		if (!res.ok) {
+			const body = await res.json().catch(() => null);
+			throw new Error(body?.error?.message || 'Failed to download attachment');
+		}
+		return res.blob();
+	}
+
+	async deleteTaskAttachment(id: string, attachmentId: number): Promise<void> {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/attachments/${attachmentId}`, {
+			method: 'DELETE'
+		});
+		return this.requestVoid(res, 'Failed to delete attachment');
+	}
+
+	async listTaskArtifacts(id: string): Promise<TaskArtifact[]> {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/artifacts`);
+		return this.request<TaskArtifact[]>(res, 'Failed to fetch artifacts');
+	}
+
+	async downloadTaskArtifact(id: string, artifactId: number): Promise<Blob> {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/artifacts/${artifactId}`);
+		if (!res.ok) {
+			const body = await res.json().catch(() => null);
+			throw new Error(body?.error?.message || 'Failed to download artifact');
+		}
+		return res.blob();
+	}
+
+	async listRepoNotes(repoId: string): Promise<TaskNote[]> {
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}/notes`);
+		return this.request<TaskNote[]>(res, 'Failed to fetch notes');
+	}
+
+	async convertNote(id: number): Promise<Task> {
+		const res = await this.fetch(`${this.baseUrl}/notes/${id}/convert`, { method: 'POST' });
+		return this.request<Task>(res, 'Failed to create task from note');
+	}
+
+	async getTaskShadowDiff(id: string): Promise<ShadowDiff | null> {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/shadow-diff`);
+		return this.request<ShadowDiff | null>(res, 'Failed to fetch shadow diff');
+	}
+
+	async getTaskProposal(id: string): Promise<Proposal | null> {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/proposal`);
+		return this.request<Proposal | null>(res, 'Failed to fetch proposal');
+	}
+
+	async approveTaskProposal(id: string): Promise<Task> {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/proposal/approve`, {
+			method: 'POST'
+		});
+		return this.request<Task>(res, 'Failed to approve proposal');
+	}
+
 	async getTaskDiff(id: string): Promise<{ diff: string }> {
-		const res = await fetch(`${this.baseUrl}/tasks/${id}/diff`);
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/diff`);
 		return this.request<{ diff: string }>(res, 'Failed to fetch task diff');
 	}
 
 	async retryTask(id: string, instructions?: string): Promise<Task> {
-		const res = await fetch(`${this.baseUrl}/tasks/${id}/retry`, {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/retry`, {
 			method: 'POST',
 			headers: { 'Content-Type': 'application/json' },
 			body: JSON.stringify({ instructions })
@@ -243,7 +583,7 @@ export class VerveClient {
 	}
 
 	async feedbackTask(id: string, feedback: string): Promise<Task> {
-		const res = await fetch(`${this.baseUrl}/tasks/${id}/feedback`, {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/feedback`, {
 			method: 'POST',
 			headers: { 'Content-Type': 'application/json' },
 			body: JSON.stringify({ feedback })
@@ -252,7 +592,7 @@ export class VerveClient {
 	}
 
 	async moveToReview(id: string): Promise<Task> {
-		const res = await fetch(`${this.baseUrl}/tasks/${id}/move-to-review`, {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/move-to-review`, {
 			method: 'POST',
 			headers: { 'Content-Type': 'application/json' }
 		});
@@ -261,9 +601,14 @@ export class VerveClient {
 
 	async startOverTask(
 		id: string,
-		updates?: { title?: string; description?: string; acceptance_criteria?: string[] }
+		updates?: {
+			title?: string;
+			description?: string;
+			acceptance_criteria?: string[];
+			expected_version?: number;
+		}
 	): Promise<Task> {
-		const res = await fetch(`${this.baseUrl}/tasks/${id}/start-over`, {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/start-over`, {
 			method: 'POST',
 			headers: { 'Content-Type': 'application/json' },
 			body: JSON.stringify(updates ?? {})
@@ -271,8 +616,20 @@ export class VerveClient {
 		return this.request<Task>(res, 'Failed to start over');
 	}
 
+	async moveTask(
+		id: string,
+		req: { repo_id: string; drop_dependencies?: boolean; expected_version?: number }
+	): Promise<Task> {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/move`, {
+			method: 'POST',
+			headers: { 'Content-Type': 'application/json' },
+			body: JSON.stringify(req)
+		});
+		return this.request<Task>(res, 'Failed to move task');
+	}
+
 	async setReady(id: string, ready: boolean): Promise<Task> {
-		const res = await fetch(`${this.baseUrl}/tasks/${id}/ready`, {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/ready`, {
 			method: 'PUT',
 			headers: { 'Content-Type': 'application/json' },
 			body: JSON.stringify({ ready })
@@ -281,7 +638,7 @@ export class VerveClient {
 	}
 
 	async removeDependency(id: string, dependsOn: string): Promise<Task> {
-		const res = await fetch(`${this.baseUrl}/tasks/${id}/dependency`, {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/dependency`, {
 			method: 'DELETE',
 			headers: { 'Content-Type': 'application/json' },
 			body: JSON.stringify({ depends_on: dependsOn })
@@ -290,37 +647,73 @@ export class VerveClient {
 	}
 
 	async deleteTask(id: string): Promise<void> {
-		const res = await fetch(`${this.baseUrl}/tasks/${id}`, {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}`, {
 			method: 'DELETE'
 		});
 		return this.requestVoid(res, 'Failed to delete task');
 	}
 
-	async bulkDeleteTasks(taskIds: string[]): Promise<void> {
-		const res = await fetch(`${this.baseUrl}/tasks/bulk-delete`, {
+	async restoreTask(id: string): Promise<Task> {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/restore`, {
+			method: 'POST'
+		});
+		return this.request<Task>(res, 'Failed to restore task');
+	}
+
+	async unarchiveTask(id: string): Promise<Task> {
+		const res = await this.fetch(`${this.baseUrl}/tasks/${id}/unarchive`, {
+			method: 'POST'
+		});
+		return this.request<Task>(res, 'Failed to unarchive task');
+	}
+
+	// bulkTaskAction applies one action to several of a repo's tasks in one
+	// transaction, returning whether it was applied or skipped for each.
+	async bulkTaskAction(repoId: string, req: BulkTaskActionRequest): Promise<BulkTaskActionResult[]> {
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}/tasks/bulk-action`, {
 			method: 'POST',
 			headers: { 'Content-Type': 'application/json' },
-			body: JSON.stringify({ task_ids: taskIds })
+			body: JSON.stringify(req)
 		});
-		return this.requestVoid(res, 'Failed to bulk delete tasks');
+		const data = await this.request<{ results: BulkTaskActionResult[] }>(res, 'Failed to update tasks');
+		return data.results;
 	}
 
 	// --- Agent Observability APIs ---
 
 	async getMetrics(): Promise<Metrics> {
-		const res = await fetch(`${this.baseUrl}/metrics`);
+		const res = await this.fetch(`${this.baseUrl}/metrics`);
 		return this.request<Metrics>(res, 'Failed to fetch metrics');
 	}
 
+	async getThroughputReport(days?: number, repoId?: string): Promise<ThroughputReport> {
+		const params = new URLSearchParams();
+		if (days) params.set('days', String(days));
+		if (repoId) params.set('repo_id', repoId);
+		const query = params.size > 0 ? `?${params}` : '';
+		const res = await this.fetch(`${this.baseUrl}/reports/throughput${query}`);
+		return this.request<ThroughputReport>(res, 'Failed to fetch throughput report');
+	}
+
 	// --- Settings APIs ---
 
-	async getGitHubTokenStatus(): Promise<{ configured: boolean; fine_grained?: boolean }> {
-		const res = await fetch(`${this.baseUrl}/settings/github-token`);
+	async getCapabilities(): Promise<Capabilities> {
+		const res = await this.fetch(`${this.baseUrl}/capabilities`);
+		return this.request(res, 'Failed to get server capabilities');
+	}
+
+	async getGitHubTokenStatus(): Promise<{
+		configured: boolean;
+		fine_grained?: boolean;
+		managed?: boolean;
+		error?: string;
+	}> {
+		const res = await this.fetch(`${this.baseUrl}/settings/github-token`);
 		return this.request(res, 'Failed to check GitHub token status');
 	}
 
 	async saveGitHubToken(token: string): Promise<void> {
-		const res = await fetch(`${this.baseUrl}/settings/github-token`, {
+		const res = await this.fetch(`${this.baseUrl}/settings/github-token`, {
 			method: 'PUT',
 			headers: { 'Content-Type': 'application/json' },
 			body: JSON.stringify({ token })
@@ -329,24 +722,104 @@ export class VerveClient {
 	}
 
 	async deleteGitHubToken(): Promise<void> {
-		const res = await fetch(`${this.baseUrl}/settings/github-token`, {
+		const res = await this.fetch(`${this.baseUrl}/settings/github-token`, {
 			method: 'DELETE'
 		});
 		return this.requestVoid(res, 'Failed to delete GitHub token');
 	}
 
+	async getGitHubRateLimit(): Promise<GitHubRateLimit[]> {
+		const res = await this.fetch(`${this.baseUrl}/settings/github-token/rate-limit`);
+		return this.request<GitHubRateLimit[]>(res, 'Failed to get GitHub rate limit');
+	}
+
+	async listGitHubCredentials(): Promise<GitHubCredential[]> {
+		const res = await this.fetch(`${this.baseUrl}/settings/github-credentials`);
+		return this.request<GitHubCredential[]>(res, 'Failed to fetch GitHub credentials');
+	}
+
+	async createGitHubCredential(input: GitHubCredentialInput): Promise<GitHubCredential> {
+		const res = await this.fetch(`${this.baseUrl}/settings/github-credentials`, {
+			method: 'POST',
+			headers: { 'Content-Type': 'application/json' },
+			body: JSON.stringify(input)
+		});
+		return this.request(res, 'Failed to create GitHub credential');
+	}
+
+	async updateGitHubCredential(
+		credentialId: string,
+		input: GitHubCredentialInput
+	): Promise<GitHubCredential> {
+		const res = await this.fetch(`${this.baseUrl}/settings/github-credentials/${credentialId}`, {
+			method: 'PUT',
+			headers: { 'Content-Type': 'application/json' },
+			body: JSON.stringify(input)
+		});
+		return this.request(res, 'Failed to update GitHub credential');
+	}
+
+	async deleteGitHubCredential(credentialId: string): Promise<void> {
+		const res = await this.fetch(`${this.baseUrl}/settings/github-credentials/${credentialId}`, {
+			method: 'DELETE'
+		});
+		return this.requestVoid(res, 'Failed to delete GitHub credential');
+	}
+
+	async getOIDCSettings(): Promise<OIDCSettings> {
+		const res = await this.fetch(`${this.baseUrl}/settings/oidc`);
+		return this.request(res, 'Failed to get single sign-on settings');
+	}
+
+	async saveOIDCSettings(config: OIDCConfig): Promise<OIDCSettings> {
+		const res = await this.fetch(`${this.baseUrl}/settings/oidc`, {
+			method: 'PUT',
+			headers: { 'Content-Type': 'application/json' },
+			body: JSON.stringify(config)
+		});
+		return this.request(res, 'Failed to save single sign-on settings');
+	}
+
+	async deleteOIDCSettings(): Promise<void> {
+		const res = await this.fetch(`${this.baseUrl}/settings/oidc`, {
+			method: 'DELETE'
+		});
+		return this.requestVoid(res, 'Failed to delete single sign-on settings');
+	}
+
+	async listUsers(): Promise<User[]> {
+		const res = await this.fetch(`${this.baseUrl}/users`);
+		return this.request<User[]>(res, 'Failed to fetch users');
+	}
+
+	async createUser(name: string, role: UserRole): Promise<UserToken> {
+		const res = await this.fetch(`${this.baseUrl}/users`, {
+			method: 'POST',
+			headers: { 'Content-Type': 'application/json' },
+			body: JSON.stringify({ name, role })
+		});
+		return this.request(res, 'Failed to create user');
+	}
+
+	async rotateUserToken(userId: string): Promise<UserToken> {
+		const res = await this.fetch(`${this.baseUrl}/users/${userId}/rotate-token`, {
+			method: 'POST'
+		});
+		return this.request(res, 'Failed to rotate API token');
+	}
+
 	async getDefaultModel(): Promise<{ model: string; configured: boolean }> {
-		const res = await fetch(`${this.baseUrl}/settings/default-model`);
+		const res = await this.fetch(`${this.baseUrl}/settings/default-model`);
 		return this.request(res, 'Failed to get default model');
 	}
 
 	async listModels(): Promise<{ value: string; label: string }[]> {
-		const res = await fetch(`${this.baseUrl}/settings/models`);
+		const res = await this.fetch(`${this.baseUrl}/settings/models`);
 		return this.request(res, 'Failed to list models');
 	}
 
 	async saveDefaultModel(model: string): Promise<void> {
-		const res = await fetch(`${this.baseUrl}/settings/default-model`, {
+		const res = await this.fetch(`${this.baseUrl}/settings/default-model`, {
 			method: 'PUT',
 			headers: { 'Content-Type': 'application/json' },
 			body: JSON.stringify({ model })
@@ -355,16 +828,74 @@ export class VerveClient {
 	}
 
 	async deleteDefaultModel(): Promise<void> {
-		const res = await fetch(`${this.baseUrl}/settings/default-model`, {
+		const res = await this.fetch(`${this.baseUrl}/settings/default-model`, {
 			method: 'DELETE'
 		});
 		return this.requestVoid(res, 'Failed to delete default model');
 	}
 
+	async getRetryPolicy(): Promise<{ policy: RetryPolicy; defaults: RetryPolicy; configured: boolean }> {
+		const res = await this.fetch(`${this.baseUrl}/settings/retry-policy`);
+		return this.request(res, 'Failed to get retry policy');
+	}
+
+	async saveRetryPolicy(
+		policy: RetryPolicy
+	): Promise<{ policy: RetryPolicy; defaults: RetryPolicy; configured: boolean }> {
+		const res = await this.fetch(`${this.baseUrl}/settings/retry-policy`, {
+			method: 'PUT',
+			headers: { 'Content-Type': 'application/json' },
+			body: JSON.stringify(policy)
+		});
+		return this.request(res, 'Failed to save retry policy');
+	}
+
+	async deleteRetryPolicy(): Promise<void> {
+		const res = await this.fetch(`${this.baseUrl}/settings/retry-policy`, {
+			method: 'DELETE'
+		});
+		return this.requestVoid(res, 'Failed to delete retry policy');
+	}
+
+	async getQueuePaused(): Promise<QueuePaused> {
+		const res = await this.fetch(`${this.baseUrl}/settings/queue-paused`);
+		return this.request(res, 'Failed to get queue pause');
+	}
+
+	async setQueuePaused(paused: boolean, reason?: string): Promise<QueuePaused> {
+		const res = await this.fetch(`${this.baseUrl}/settings/queue-paused`, {
+			method: 'PUT',
+			headers: { 'Content-Type': 'application/json' },
+			body: JSON.stringify({ paused, reason })
+		});
+		return this.request(res, paused ? 'Failed to pause queue' : 'Failed to resume queue');
+	}
+
+	async listRuntimeSettings(): Promise<RuntimeSetting[]> {
+		const res = await this.fetch(`${this.baseUrl}/settings/runtime`);
+		return this.request<RuntimeSetting[]>(res, 'Failed to fetch runtime settings');
+	}
+
+	async setRuntimeSetting(key: string, value: string): Promise<RuntimeSetting> {
+		const res = await this.fetch(`${this.baseUrl}/settings/runtime/${key}`, {
+			method: 'PUT',
+			headers: { 'Content-Type': 'application/json' },
+			body: JSON.stringify({ value })
+		});
+		return this.request<RuntimeSetting>(res, 'Failed to save setting');
+	}
+
+	async deleteRuntimeSetting(key: string): Promise<void> {
+		const res = await this.fetch(`${this.baseUrl}/settings/runtime/${key}`, {
+			method: 'DELETE'
+		});
+		return this.requestVoid(res, 'Failed to reset setting');
+	}
+
 	// --- Epic APIs ---
 
 	async listEpicsByRepo(repoId: string): Promise<Epic[]> {
-		const res = await fetch(`${this.baseUrl}/repos/${repoId}/epics`);
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}/epics`);
 		return this.request<Epic[]>(res, 'Failed to fetch epics');
 	}
 
@@ -373,12 +904,14 @@ export class VerveClient {
 		title: string,
 		description: string,
 		planningPrompt?: string,
-		model?: string
+		model?: string,
+		maxCostUsd?: number
 	): Promise<Epic> {
 		const body: Record<string, unknown> = { title, description };
 		if (planningPrompt) body.planning_prompt = planningPrompt;
 		if (model) body.model = model;
-		const res = await fetch(`${this.baseUrl}/repos/${repoId}/epics`, {
+		if (maxCostUsd) body.max_cost_usd = maxCostUsd;
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}/epics`, {
 			method: 'POST',
 			headers: { 'Content-Type': 'application/json' },
 			body: JSON.stringify(body)
@@ -387,29 +920,34 @@ export class VerveClient {
 	}
 
 	async getEpic(id: string): Promise<Epic> {
-		const res = await fetch(`${this.baseUrl}/epics/${id}`);
+		const res = await this.fetch(`${this.baseUrl}/epics/${id}`);
 		return this.request<Epic>(res, 'Epic not found');
 	}
 
 	async getEpicByNumber(repoId: string, number: number): Promise<Epic> {
-		const res = await fetch(`${this.baseUrl}/repos/${repoId}/epics/${number}`);
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}/epics/${number}`);
 		return this.request<Epic>(res, 'Epic not found');
 	}
 
 	async getEpicTasks(id: string): Promise<{ id: string; number: number; title: string; status: string }[]> {
-		const res = await fetch(`${this.baseUrl}/epics/${id}/tasks`);
+		const res = await this.fetch(`${this.baseUrl}/epics/${id}/tasks`);
 		return this.request(res, 'Failed to fetch epic tasks');
 	}
 
+	async getEpicProgress(id: string): Promise<EpicProgress> {
+		const res = await this.fetch(`${this.baseUrl}/epics/${id}/progress`);
+		return this.request<EpicProgress>(res, 'Failed to fetch epic progress');
+	}
+
 	async deleteEpic(id: string): Promise<void> {
-		const res = await fetch(`${this.baseUrl}/epics/${id}`, {
+		const res = await this.fetch(`${this.baseUrl}/epics/${id}`, {
 			method: 'DELETE'
 		});
 		return this.requestVoid(res, 'Failed to delete epic');
 	}
 
 	async startPlanning(id: string, prompt: string): Promise<Epic> {
-		const res = await fetch(`${this.baseUrl}/epics/${id}/plan`, {
+		const res = await this.fetch(`${this.baseUrl}/epics/${id}/plan`, {
 			method: 'POST',
 			headers: { 'Content-Type': 'application/json' },
 			body: JSON.stringify({ prompt })
@@ -418,7 +956,7 @@ export class VerveClient {
 	}
 
 	async updateProposedTasks(id: string, tasks: ProposedTask[]): Promise<Epic> {
-		const res = await fetch(`${this.baseUrl}/epics/${id}/proposed-tasks`, {
+		const res = await this.fetch(`${this.baseUrl}/epics/${id}/proposed-tasks`, {
 			method: 'PUT',
 			headers: { 'Content-Type': 'application/json' },
 			body: JSON.stringify({ tasks })
@@ -427,7 +965,7 @@ export class VerveClient {
 	}
 
 	async sendSessionMessage(id: string, message: string): Promise<Epic> {
-		const res = await fetch(`${this.baseUrl}/epics/${id}/session-message`, {
+		const res = await this.fetch(`${this.baseUrl}/epics/${id}/session-message`, {
 			method: 'POST',
 			headers: { 'Content-Type': 'application/json' },
 			body: JSON.stringify({ message })
@@ -435,24 +973,62 @@ export class VerveClient {
 		return this.request<Epic>(res, 'Failed to send message');
 	}
 
-	async confirmEpic(id: string, notReady?: boolean): Promise<Epic> {
-		const res = await fetch(`${this.baseUrl}/epics/${id}/confirm`, {
+	async resumePlanning(id: string, message: string): Promise<Epic> {
+		const res = await this.fetch(`${this.baseUrl}/epics/${id}/resume`, {
 			method: 'POST',
 			headers: { 'Content-Type': 'application/json' },
-			body: JSON.stringify({ not_ready: notReady ?? false })
+			body: JSON.stringify({ message })
+		});
+		return this.request<Epic>(res, 'Failed to resume planning');
+	}
+
+	epicTranscriptUrl(id: string, format: 'markdown' | 'json'): string {
+		return `${this.baseUrl}/epics/${id}/transcript?format=${format}`;
+	}
+
+	async getSuggestedDependencies(id: string): Promise<DependencySuggestion[]> {
+		const res = await this.fetch(`${this.baseUrl}/epics/${id}/suggested-dependencies`);
+		return this.request<DependencySuggestion[]>(res, 'Failed to fetch suggested dependencies');
+	}
+
+	async applySuggestedDependencies(id: string): Promise<Epic> {
+		const res = await this.fetch(`${this.baseUrl}/epics/${id}/suggested-dependencies/apply`, {
+			method: 'POST'
+		});
+		return this.request<Epic>(res, 'Failed to apply suggested dependencies');
+	}
+
+	async confirmEpic(id: string, notReady?: boolean, sharedBranch?: boolean, tempIds?: string[]): Promise<Epic> {
+		const res = await this.fetch(`${this.baseUrl}/epics/${id}/confirm`, {
+			method: 'POST',
+			headers: { 'Content-Type': 'application/json' },
+			body: JSON.stringify({
+				not_ready: notReady ?? false,
+				shared_branch: sharedBranch ?? false,
+				temp_ids: tempIds ?? []
+			})
 		});
 		return this.request<Epic>(res, 'Failed to confirm epic');
 	}
 
+	async replanEpic(id: string, prompt: string): Promise<Epic> {
+		const res = await this.fetch(`${this.baseUrl}/epics/${id}/replan`, {
+			method: 'POST',
+			headers: { 'Content-Type': 'application/json' },
+			body: JSON.stringify({ prompt })
+		});
+		return this.request<Epic>(res, 'Failed to re-plan epic');
+	}
+
 	async closeEpic(id: string): Promise<Epic> {
-		const res = await fetch(`${this.baseUrl}/epics/${id}/close`, {
+		const res = await this.fetch(`${this.baseUrl}/epics/${id}/close`, {
 			method: 'POST'
 		});
 		return this.request<Epic>(res, 'Failed to close epic');
 	}
 
 	async stopEpic(id: string): Promise<Epic> {
-		const res = await fetch(`${this.baseUrl}/epics/${id}/stop`, {
+		const res = await this.fetch(`${this.baseUrl}/epics/${id}/stop`, {
 			method: 'POST',
 			headers: { 'Content-Type': 'application/json' }
 		});
@@ -463,7 +1039,7 @@ export class VerveClient {
 
 	async listConversationsByRepo(repoId: string, status?: string): Promise<Conversation[]> {
 		const params = status ? `?status=${encodeURIComponent(status)}` : '';
-		const res = await fetch(`${this.baseUrl}/repos/${repoId}/conversations${params}`);
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}/conversations${params}`);
 		return this.request<Conversation[]>(res, 'Failed to fetch conversations');
 	}
 
@@ -476,7 +1052,7 @@ export class VerveClient {
 		const body: Record<string, unknown> = { title };
 		if (initialMessage) body.initial_message = initialMessage;
 		if (model) body.model = model;
-		const res = await fetch(`${this.baseUrl}/repos/${repoId}/conversations`, {
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}/conversations`, {
 			method: 'POST',
 			headers: { 'Content-Type': 'application/json' },
 			body: JSON.stringify(body)
@@ -485,19 +1061,19 @@ export class VerveClient {
 	}
 
 	async getConversation(id: string): Promise<Conversation> {
-		const res = await fetch(`${this.baseUrl}/conversations/${id}`);
+		const res = await this.fetch(`${this.baseUrl}/conversations/${id}`);
 		return this.request<Conversation>(res, 'Conversation not found');
 	}
 
 	async deleteConversation(id: string): Promise<void> {
-		const res = await fetch(`${this.baseUrl}/conversations/${id}`, {
+		const res = await this.fetch(`${this.baseUrl}/conversations/${id}`, {
 			method: 'DELETE'
 		});
 		return this.requestVoid(res, 'Failed to delete conversation');
 	}
 
 	async sendConversationMessage(id: string, message: string): Promise<Conversation> {
-		const res = await fetch(`${this.baseUrl}/conversations/${id}/messages`, {
+		const res = await this.fetch(`${this.baseUrl}/conversations/${id}/messages`, {
 			method: 'POST',
 			headers: { 'Content-Type': 'application/json' },
 			body: JSON.stringify({ message })
@@ -506,7 +1082,7 @@ export class VerveClient {
 	}
 
 	async archiveConversation(id: string): Promise<Conversation> {
-		const res = await fetch(`${this.baseUrl}/conversations/${id}/archive`, {
+		const res = await this.fetch(`${this.baseUrl}/conversations/${id}/archive`, {
 			method: 'POST'
 		});
 		return this.request<Conversation>(res, 'Failed to archive conversation');
@@ -519,7 +1095,7 @@ export class VerveClient {
 	): Promise<Epic> {
 		const body: Record<string, unknown> = { title };
 		if (planningPrompt) body.planning_prompt = planningPrompt;
-		const res = await fetch(`${this.baseUrl}/conversations/${id}/generate-tasks`, {
+		const res = await this.fetch(`${this.baseUrl}/conversations/${id}/generate-tasks`, {
 			method: 'POST',
 			headers: { 'Content-Type': 'application/json' },
 			body: JSON.stringify(body)
@@ -527,17 +1103,160 @@ export class VerveClient {
 		return this.request<Epic>(res, 'Failed to generate tasks');
 	}
 
+	// --- Notification APIs ---
+
+	async listNotificationSinks(repoId: string): Promise<NotificationSink[]> {
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}/notification-sinks`);
+		return this.request<NotificationSink[]>(res, 'Failed to fetch notification sinks');
+	}
+
+	async createNotificationSink(
+		repoId: string,
+		kind: NotificationSinkKind,
+		url: string,
+		events?: NotificationEventType[]
+	): Promise<NotificationSink> {
+		const body: Record<string, unknown> = { kind, url };
+		if (events && events.length > 0) body.events = events;
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}/notification-sinks`, {
+			method: 'POST',
+			headers: { 'Content-Type': 'application/json' },
+			body: JSON.stringify(body)
+		});
+		return this.request<NotificationSink>(res, 'Failed to create notification sink');
+	}
+
+	async deleteNotificationSink(id: string): Promise<void> {
+		const res = await this.fetch(`${this.baseUrl}/notification-sinks/${id}`, {
+			method: 'DELETE'
+		});
+		return this.requestVoid(res, 'Failed to delete notification sink');
+	}
+
+	async testNotificationSink(id: string): Promise<TestNotificationSinkResult> {
+		const res = await this.fetch(`${this.baseUrl}/notification-sinks/${id}/test`, {
+			method: 'POST'
+		});
+		return this.request<TestNotificationSinkResult>(res, 'Failed to send test notification');
+	}
+
+	async testNotificationSinkUrl(
+		repoId: string,
+		kind: NotificationSinkKind,
+		url: string
+	): Promise<TestNotificationSinkResult> {
+		const res = await this.fetch(`${this.baseUrl}/repos/${repoId}/notification-sinks/test`, {
+			method: 'POST',
+			headers: { 'Content-Type': 'application/json' },
+			body: JSON.stringify({ kind, url })
+		});
+		return this.request<TestNotificationSinkResult>(res, 'Failed to send test notification');
+	}
+
+	async listTeamNotificationSinks(teamId: string): Promise<NotificationSink[]> {
+		const res = await this.fetch(`${this.baseUrl}/teams/${teamId}/notification-sinks`);
+		return this.request<NotificationSink[]>(res, 'Failed to fetch notification sinks');
+	}
+
+	async createTeamNotificationSink(
+		teamId: string,
+		kind: NotificationSinkKind,
+		url: string,
+		events?: NotificationEventType[]
+	): Promise<NotificationSink> {
+		const body: Record<string, unknown> = { kind, url };
+		if (events && events.length > 0) body.events = events;
+		const res = await this.fetch(`${this.baseUrl}/teams/${teamId}/notification-sinks`, {
+			method: 'POST',
+			headers: { 'Content-Type': 'application/json' },
+			body: JSON.stringify(body)
+		});
+		return this.request<NotificationSink>(res, 'Failed to create notification sink');
+	}
+
+	// --- Team APIs ---
+
+	async listTeams(): Promise<Team[]> {
+		const res = await this.fetch(`${this.baseUrl}/teams`);
+		return this.request<Team[]>(res, 'Failed to fetch teams');
+	}
+
+	async createTeam(
+		name: string,
+		defaultModel?: string,
+		defaultMaxCostUsd?: number
+	): Promise<Team> {
+		const body: Record<string, unknown> = { name };
+		if (defaultModel) body.default_model = defaultModel;
+		if (defaultMaxCostUsd) body.default_max_cost_usd = defaultMaxCostUsd;
+		const res = await this.fetch(`${this.baseUrl}/teams`, {
+			method: 'POST',
+			headers: { 'Content-Type': 'application/json' },
+			body: JSON.stringify(body)
+		});
+		return this.request<Team>(res, 'Failed to create team');
+	}
+
+	async updateTeam(
+		teamId: string,
+		updates: { name?: string; default_model?: string; default_max_cost_usd?: number }
+	): Promise<Team> {
+		const res = await this.fetch(`${this.baseUrl}/teams/${teamId}`, {
+			method: 'PATCH',
+			headers: { 'Content-Type': 'application/json' },
+			body: JSON.stringify(updates)
+		});
+		return this.request<Team>(res, 'Failed to update team');
+	}
+
+	async deleteTeam(teamId: string): Promise<void> {
+		const res = await this.fetch(`${this.baseUrl}/teams/${teamId}`, {
+			method: 'DELETE'
+		});
+		return this.requestVoid(res, 'Failed to delete team');
+	}
+
+	async listTeamRepos(teamId: string): Promise<Repo[]> {
+		const res = await this.fetch(`${this.baseUrl}/teams/${teamId}/repos`);
+		return this.request<Repo[]>(res, 'Failed to fetch team repos');
+	}
+
+	async getTeamCosts(days?: number): Promise<TeamCosts> {
+		const query = days ? `?days=${days}` : '';
+		const res = await this.fetch(`${this.baseUrl}/teams/costs${query}`);
+		return this.request<TeamCosts>(res, 'Failed to fetch team costs');
+	}
+
 	// --- SSE URLs ---
 
 	eventsURL(repoId?: string): string {
 		if (repoId) {
-			return `${this.baseUrl}/events?repo_id=${repoId}`;
+			return this.withToken(`${this.baseUrl}/events?repo_id=${repoId}`);
 		}
-		return `${this.baseUrl}/events`;
+		return this.withToken(`${this.baseUrl}/events`);
 	}
 
 	taskLogsURL(id: string): string {
-		return `${this.baseUrl}/tasks/${id}/logs`;
+		return this.withToken(`${this.baseUrl}/tasks/${id}/logs`);
+	}
+
+	taskAttemptLogsURL(id: string, attempt: number): string {
+		return this.withToken(`${this.baseUrl}/tasks/${id}/attempts/${attempt}/logs`);
+	}
+
+	// --- WebSocket URLs ---
+
+	eventsWebSocketURL(repoId?: string): string {
+		const url = new URL(`${this.baseUrl}/ws`, window.location.href);
+		url.protocol = url.protocol === 'https:' ? 'wss:' : 'ws:';
+		if (repoId) {
+			url.searchParams.set('repo_id', repoId);
+		}
+		const token = this.getToken();
+		if (token) {
+			url.searchParams.set('token', token);
+		}
+		return url.toString();
 	}
 }
 
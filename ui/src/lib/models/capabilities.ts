@@ -0,0 +1,26 @@
+export interface CapabilityFeatures {
+	admin: boolean;
+	auth: boolean;
+	mcp: boolean;
+	github_token_storage: boolean;
+	provenance_scan: boolean;
+	self_review: boolean;
+	cost_anomaly_detection: boolean;
+	cost_anomaly_auto_pause: boolean;
+}
+
+export interface CapabilityLimits {
+	max_labels: number;
+	task_timeout_seconds: number;
+	log_retention_seconds: number;
+	// Zero when deleted tasks are removed right away instead of trashed.
+	trash_retention_seconds: number;
+	// Zero when merged and closed tasks are never archived.
+	archive_after_seconds: number;
+}
+
+export interface Capabilities {
+	version: string;
+	features: CapabilityFeatures;
+	limits: CapabilityLimits;
+}
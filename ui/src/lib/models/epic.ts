@@ -6,6 +6,26 @@ export interface ProposedTask {
 	description: string;
 	depends_on_temp_ids?: string[];
 	acceptance_criteria?: string[];
+	estimate?: TaskEstimate;
+}
+
+export type Complexity = 'low' | 'medium' | 'high';
+
+// TaskEstimate is the planning agent's estimate for a proposed task. The
+// upper end of the cost range becomes the task's budget when it is created.
+export interface TaskEstimate {
+	model?: string;
+	min_cost_usd?: number;
+	max_cost_usd?: number;
+	complexity?: Complexity;
+}
+
+export interface DependencySuggestion {
+	temp_id: string;
+	title: string;
+	depends_on_temp_id: string;
+	depends_on_title: string;
+	shared: string[];
 }
 
 export interface Epic {
@@ -18,10 +38,44 @@ export interface Epic {
 	proposed_tasks: ProposedTask[];
 	task_ids: string[];
 	planning_prompt?: string;
+	planning_summary?: string;
 	session_log: string[];
 	not_ready: boolean;
 	model?: string;
 	claimed_at?: string;
+	cost_usd: number;
+	max_cost_usd?: number;
+	// Tasks branch from and merge into epic/<id>; once all are done a single
+	// PR merges that branch into the default branch.
+	shared_branch: boolean;
+	pull_request_url?: string;
+	pr_number?: number;
 	created_at: string;
 	updated_at: string;
 }
+
+export interface BurndownPoint {
+	time: string;
+	completed: number;
+	remaining: number;
+}
+
+export interface EpicProgress {
+	epic_id: string;
+	status: EpicStatus;
+	// Every task status is present, with zero for statuses no task is in.
+	status_counts: Record<string, number>;
+	total_tasks: number;
+	completed_tasks: number; // Merged or closed
+	// Before the epic is confirmed this is the number of proposed tasks.
+	estimated_remaining_tasks: number;
+	cost: {
+		planning_usd: number;
+		tasks_usd: number;
+		spent_usd: number;
+		estimated_total_usd: number;
+	};
+	estimated_completion_at?: string;
+	// A point when the first task was created and one per completion since.
+	burndown: BurndownPoint[];
+}
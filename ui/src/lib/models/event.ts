@@ -0,0 +1,36 @@
+import type {
+	AgentEvent,
+	ProvenanceReview,
+	Task,
+	TaskComment,
+	TaskNudge,
+	TaskProgress
+} from './task';
+
+// Messages sent by the client over the /ws event stream.
+export type EventStreamClientMessage =
+	| { type: 'subscribe' | 'unsubscribe'; repo_ids?: string[]; task_ids?: string[] }
+	| { type: 'ping' };
+
+// Messages sent by the server over the /ws event stream. Broker events use
+// the same payloads as the SSE /events stream.
+export type EventStreamServerMessage =
+	| { type: 'init'; tasks: Task[] }
+	| { type: 'subscribed'; repo_ids: string[]; task_ids: string[] }
+	| { type: 'pong' }
+	| { type: 'error'; error: string }
+	| { type: 'task_created' | 'task_updated'; repo_id?: string; task: Task }
+	| { type: 'task_deleted'; repo_id?: string; task_id: string }
+	| { type: 'logs_appended'; task_id: string; logs: string[]; attempt: number }
+	| { type: 'agent_events_appended'; task_id: string; agent_events: AgentEvent[]; attempt: number }
+	| { type: 'task_progress'; repo_id?: string; task_id: string; progress: TaskProgress; attempt: number }
+	| { type: 'task_nudges'; repo_id?: string; task_id: string; nudges: TaskNudge[]; attempt: number }
+	| {
+			type: 'comment_created' | 'comment_updated' | 'comment_deleted';
+			repo_id?: string;
+			task_id: string;
+			comment: TaskComment;
+	  }
+	| { type: 'task_provenance'; repo_id?: string; task_id: string; provenance: ProvenanceReview }
+	| { type: 'review_overdue'; repo_id?: string; task_id: string; task: Task }
+	| { type: 'repo_updated'; repo_id: string; repo: unknown };
@@ -0,0 +1,38 @@
+export type GitHubCredentialKind = 'token' | 'app';
+
+// A GitHub credential used instead of the global token for the repos it
+// covers: "owner/name" entries for single repos and "owner/*" entries for
+// every repo of an owner. The secret is write-only.
+export interface GitHubCredential {
+	id: string;
+	name: string;
+	kind: GitHubCredentialKind;
+	repos: string[];
+	app_id?: number;
+	installation_id?: number;
+	created_at: string;
+	updated_at: string;
+}
+
+// The body for creating or updating a credential. secret is the personal
+// access token or the GitHub App's PEM private key; an empty secret keeps the
+// current one on update.
+export interface GitHubCredentialInput {
+	name: string;
+	kind: GitHubCredentialKind;
+	repos: string[];
+	secret?: string;
+	app_id?: number;
+	installation_id?: number;
+}
+
+// One of GitHub's rate limit buckets, such as "core" for the REST API, as
+// last reported to the server.
+export interface GitHubRateLimit {
+	resource: string;
+	limit: number;
+	remaining: number;
+	used: number;
+	reset: string;
+	updated_at: string;
+}
@@ -34,6 +34,12 @@ export interface WorkerInfo {
 	last_poll_at: string;
 	uptime_ms: number;
 	polling: boolean;
+	labels?: string[];
+}
+
+export interface FailureCategoryCount {
+	category: string;
+	count: number;
 }
 
 export interface Metrics {
@@ -43,8 +49,46 @@ export interface Metrics {
 	total_tasks: number;
 	completed_tasks: number;
 	failed_tasks: number;
+	failure_categories: FailureCategoryCount[];
 	total_cost_usd: number;
+	planning_cost_usd: number;
 	active_agents: ActiveAgent[];
 	recent_completions: CompletedAgent[];
 	workers: WorkerInfo[];
 }
+
+export interface Percentiles {
+	count: number;
+	p50_seconds: number;
+	p90_seconds: number;
+	p95_seconds: number;
+}
+
+export interface CycleTimes {
+	pending_to_running: Percentiles;
+	running_to_review: Percentiles;
+	review_to_merged: Percentiles;
+}
+
+export interface ThroughputDay {
+	date: string;
+	created: number;
+	merged: number;
+	failed: number;
+}
+
+export interface ThroughputRepo {
+	repo_id: string;
+	created: number;
+	merged: number;
+	failed: number;
+	per_day: ThroughputDay[];
+	cycle_times: CycleTimes;
+}
+
+export interface ThroughputReport {
+	since: string;
+	days: number;
+	repos: ThroughputRepo[];
+	cycle_times: CycleTimes;
+}
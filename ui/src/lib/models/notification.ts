@@ -0,0 +1,35 @@
+export type NotificationSinkKind = 'slack' | 'discord' | 'webhook';
+
+export type NotificationEventType =
+	| 'task_failed'
+	| 'task_needs_review'
+	| 'pr_merged'
+	| 'budget_exceeded'
+	| 'epic_completed'
+	| 'cost_anomaly'
+	| 'review_overdue';
+
+export const notificationEventTypes: { value: NotificationEventType; label: string }[] = [
+	{ value: 'task_failed', label: 'Task failed' },
+	{ value: 'task_needs_review', label: 'Task needs review' },
+	{ value: 'pr_merged', label: 'PR merged' },
+	{ value: 'budget_exceeded', label: 'Budget exceeded' },
+	{ value: 'epic_completed', label: 'Epic completed' },
+	{ value: 'cost_anomaly', label: 'Cost anomaly' },
+	{ value: 'review_overdue', label: 'Review overdue' }
+];
+
+export interface NotificationSink {
+	id: string;
+	repo_id?: string;
+	team_id?: string;
+	kind: NotificationSinkKind;
+	url: string; // masked, e.g. https://hooks.slack.com/•••
+	events: NotificationEventType[];
+	created_at: string;
+}
+
+export interface TestNotificationSinkResult {
+	success: boolean;
+	error?: string;
+}
@@ -13,9 +13,84 @@ export interface Repo {
 	has_readme: boolean;
 	expectations: string;
 	setup_completed_at?: string;
+	ci_workflow?: CIWorkflow;
+	protected_paths: string[];
+	shadow_mode: boolean;
+	completion_validations?: CompletionValidations;
+	retry_policy?: RetryPolicy;
+	max_runtime_seconds: number;
+	required_labels: string[];
+	ignored_checks: string[];
+	check_gating?: CheckGating;
+	fallback_models: string[];
+	// Workers are handed no pending tasks from the repo while it is paused.
+	paused: boolean;
+	// PR sync skips the repo while disabled. A non-zero interval overrides
+	// the server's sync interval.
+	sync_disabled: boolean;
+	sync_interval_seconds: number;
+	// Tasks in review longer than this are reported overdue. 0 means no SLA.
+	review_sla_seconds: number;
+	team_id?: string;
+	workspace_cache_generation: number;
 	created_at: string;
 }
 
+// Workflow dispatched on the PR branch whenever an agent opens or updates a
+// pull request. Checks wait for its run before the PR counts as passing.
+export interface CIWorkflow {
+	file: string;
+	inputs?: Record<string, string>;
+}
+
+// Limits the CI checks that gate agent pull requests to the required ones.
+// Without checks, the base branch's required status checks are used.
+export interface CheckGating {
+	required_only: boolean;
+	checks?: string[];
+}
+
+// Conventions agent pull requests must follow. Violations send the task back
+// to the agent when it reports success.
+export interface CompletionValidations {
+	title_prefix?: string;
+	title_pattern?: string;
+	require_task_id?: boolean;
+	branch_pattern?: string;
+	max_commits?: number;
+	conventional_commits?: boolean;
+}
+
+// How failing tasks are retried. Unset fields fall back to the instance-wide
+// policy, then to the built-in defaults. Category limit keys are a failure
+// category (e.g. "ci_failure:tests") or its kind (e.g. "ci_failure").
+export interface RetryPolicy {
+	max_attempts?: number;
+	circuit_breaker_threshold?: number;
+	category_limits?: Record<string, number>;
+	rate_limit_backoff_seconds?: number[];
+	// Requeue tasks whose worker stopped sending heartbeats instead of
+	// failing them. Unset falls back like the other fields.
+	requeue_stale?: boolean;
+}
+
+// The instance-wide queue pause. While paused, workers are handed no new work
+// but tasks can still be created.
+export interface QueuePaused {
+	paused: boolean;
+	reason?: string;
+	paused_at?: string;
+}
+
+// A setting that can be changed while the server runs. value is empty while
+// the server's configured default applies.
+export interface RuntimeSetting {
+	key: string;
+	type: 'string' | 'float' | 'duration';
+	description: string;
+	value: string;
+}
+
 export interface GitHubRepo {
 	full_name: string;
 	owner_login: string;
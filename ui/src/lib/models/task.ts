@@ -5,6 +5,7 @@ export interface Task {
 	id: string;
 	number: number;
 	repo_id: string;
+	additional_repo_ids?: string[];
 	type: TaskType;
 	title: string;
 	description: string;
@@ -12,8 +13,13 @@ export interface Task {
 	logs: string[];
 	pull_request_url?: string;
 	pr_number?: number;
+	pull_requests?: PullRequest[];
 	depends_on?: string[];
+	// Dependency whose PR branch this task builds on and targets.
+	stack_parent_id?: string;
 	close_reason?: string;
+	// Why a failed task failed, e.g. max_attempts, ci_failure or worker_timeout.
+	failure_category?: string;
 	attempt: number;
 	max_attempts: number;
 	retry_reason?: string;
@@ -23,14 +29,229 @@ export interface Task {
 	consecutive_failures: number;
 	cost_usd: number;
 	max_cost_usd?: number;
+	max_runtime_seconds?: number;
+	required_labels?: string[];
 	skip_pr: boolean;
 	draft_pr: boolean;
+	plan_only: boolean;
 	ready: boolean;
 	epic_id?: string;
 	model?: string;
+	// Models the task falls back to, in order, when rate-limited.
+	fallback_models?: string[];
+	// Fallback model the task runs with instead of model after a rate limit.
+	active_model?: string;
 	branch_name?: string;
 	started_at?: string;
+	// Earliest time a delayed retry can be claimed.
+	not_before?: string;
+	// When the task was moved to the trash. Only set on trashed tasks.
+	deleted_at?: string;
+	// When the merged or closed task was archived. Archived tasks are only
+	// listed when asked for and their logs are back once unarchived.
+	archived_at?: string;
 	duration_ms?: number;
+	// Number of comments left on the task. Only set in listings and single
+	// task reads, not in live task events.
+	comment_count?: number;
+	// Bumped on every change; edits send the version they were made from.
+	version: number;
 	created_at: string;
 	updated_at: string;
 }
+
+// A pull request opened in one of a task's additional repos.
+export interface PullRequest {
+	repo_id: string;
+	url: string;
+	number: number;
+	merged: boolean;
+}
+
+export interface TaskAttempt {
+	number: number;
+	retry_reason?: string;
+	result?: string;
+	cost_usd: number;
+	started_at: string;
+	ended_at?: string;
+	// Model the attempt's agent ran with.
+	model?: string;
+}
+
+export type AgentEventType =
+	| 'pr_created'
+	| 'pr_updated'
+	| 'branch_pushed'
+	| 'status'
+	| 'no_changes'
+	| 'cost'
+	| 'tool_call'
+	| 'plan'
+	| 'step'
+	| 'note'
+	| 'budget_exceeded';
+
+// A structured event reported by the agent. The payload shape depends on type:
+// pr_created/pr_updated {url, number}, branch_pushed {branch}, cost {cost_usd},
+// tool_call {name, detail?}, plan {steps}, step {index, status}, note {text},
+// budget_exceeded {cost_usd, budget_usd}, status is the agent's status object.
+export interface AgentEvent {
+	id: number;
+	attempt: number;
+	type: AgentEventType;
+	payload: Record<string, unknown>;
+	created_at: string;
+}
+
+export type StepStatus = 'pending' | 'in_progress' | 'completed';
+
+export interface ProgressStep {
+	title: string;
+	status: StepStatus;
+	started_at?: string;
+	completed_at?: string;
+}
+
+// The agent's latest reported plan for a task and the status of each step.
+export interface TaskProgress {
+	attempt: number;
+	steps: ProgressStep[];
+	updated_at: string;
+}
+
+// An addition in a task's PR that may have been copied from elsewhere.
+export interface ProvenanceFinding {
+	kind: 'license_header' | 'large_block' | 'external' | string;
+	path: string;
+	line?: number;
+	lines?: number;
+	detail: string;
+}
+
+// The latest license and provenance scan of a task's PR. Findings must be
+// acknowledged before the PR is cleared for merging.
+export interface ProvenanceReview {
+	findings: ProvenanceFinding[];
+	head_sha: string;
+	scanned_at: string;
+	acknowledged_at?: string;
+}
+
+// One check of a PR self-review.
+export interface SelfReviewCheck {
+	name: 'acceptance_criteria' | 'leftovers' | 'file_count' | 'diff_size' | string;
+	passed: boolean;
+	detail: string;
+	findings?: string[];
+}
+
+// The latest automated self-review of a task's PR, scored out of 100. The
+// report is also posted as a comment on the PR.
+export interface SelfReview {
+	score: number;
+	checks: SelfReviewCheck[];
+	head_sha: string;
+	reviewed_at: string;
+}
+
+// The diff from a task's latest shadow-mode run. Shadow-mode agents record
+// their changes for review instead of pushing or opening a pull request.
+export interface ShadowDiff {
+	attempt: number;
+	diff: string;
+	created_at: string;
+}
+
+// The change plan from a plan-only run. approved_at is set once a human
+// approved it for implementation.
+export interface ProposalFile {
+	path: string;
+	change: string;
+}
+
+export interface Proposal {
+	attempt: number;
+	summary: string;
+	approach: string;
+	files: ProposalFile[];
+	risks: string[];
+	created_at: string;
+	approved_at?: string;
+}
+
+// A message the user sent to the agent of a running task. delivered_at is set
+// once the worker has handed it to the agent container.
+export interface TaskNudge {
+	id: number;
+	attempt: number;
+	message: string;
+	created_at: string;
+	delivered_at?: string;
+}
+
+// A comment or review note a user left on a task. attempt is set when the
+// comment is about a specific attempt rather than the task as a whole.
+export interface TaskComment {
+	id: number;
+	task_id: string;
+	attempt?: number;
+	author: string;
+	author_id?: string;
+	body: string;
+	created_at: string;
+	updated_at: string;
+}
+
+// A file attached to a task for its agent to read, such as a design
+// screenshot or an error log.
+export interface TaskAttachment {
+	id: number;
+	task_id: string;
+	filename: string;
+	content_type: string;
+	size_bytes: number;
+	uploaded_by: string;
+	created_at: string;
+}
+
+// A file an agent produced during an attempt, such as a test report,
+// coverage summary or screenshot. Name is its path within the agent's
+// artifacts directory.
+export interface TaskArtifact {
+	id: number;
+	task_id: string;
+	attempt: number;
+	name: string;
+	content_type: string;
+	size_bytes: number;
+	created_at: string;
+}
+
+export interface TaskNote {
+	id: number;
+	task_id: string;
+	repo_id: string;
+	attempt: number;
+	text: string;
+	created_at: string;
+	converted_task_id?: string;
+}
+
+// An action applied to several of a repo's tasks at once.
+export type BulkTaskAction = 'close' | 'delete' | 'set-ready' | 'retry';
+
+export interface BulkTaskActionRequest {
+	action: BulkTaskAction;
+	task_ids: string[];
+	reason?: string;
+	ready?: boolean;
+	instructions?: string;
+}
+
+// The outcome of a bulk action for one task. reason is why it was skipped.
+export interface BulkTaskActionResult {
+	task_id: string;
+	result: 'applied' | 'skipped';
+	reason?: string;
+}
@@ -0,0 +1,30 @@
+// A group of repos whose settings the repos inherit: new work runs with the
+// team's default model and budget unless it sets its own, and the team's
+// notification sinks receive the events of every repo in it.
+export interface Team {
+	id: string;
+	name: string;
+	default_model: string;
+	default_max_cost_usd: number;
+	created_at: string;
+}
+
+export interface RepoCost {
+	repo_id: string;
+	full_name: string;
+	cost_usd: number;
+}
+
+// A team's spend over the reporting window, rolled up from its repos. Repos
+// without a team are grouped under an entry with no team_id.
+export interface TeamCost {
+	team_id?: string;
+	team_name: string;
+	cost_usd: number;
+	repos: RepoCost[];
+}
+
+export interface TeamCosts {
+	days: number;
+	teams: TeamCost[];
+}
@@ -0,0 +1,35 @@
+// Roles grant access to the API; each role can do everything the roles
+// before it can.
+export type UserRole = 'viewer' | 'operator' | 'admin';
+
+export interface User {
+	id: string;
+	name: string;
+	role: UserRole;
+	created_at: string;
+}
+
+// A user with their API token, returned only when the token is created or
+// rotated.
+export interface UserToken {
+	user: User;
+	token: string;
+}
+
+// Single sign-on configuration. The client secret is write-only: responses
+// only say whether one is set.
+export interface OIDCConfig {
+	issuer_url: string;
+	client_id: string;
+	client_secret?: string;
+	redirect_url: string;
+	scopes?: string[];
+	groups_claim?: string;
+	group_roles?: Record<string, UserRole>;
+	default_role?: UserRole | '';
+}
+
+export interface OIDCSettings extends OIDCConfig {
+	has_client_secret: boolean;
+	configured: boolean;
+}
@@ -0,0 +1,12 @@
+import type { Capabilities } from '$lib/models/capabilities';
+
+class CapabilityStore {
+	// Null until loaded; components should render their default UI until then.
+	capabilities = $state<Capabilities | null>(null);
+
+	hasFeature(feature: keyof Capabilities['features']): boolean {
+		return this.capabilities?.features[feature] ?? false;
+	}
+}
+
+export const capabilityStore = new CapabilityStore();
@@ -20,3 +20,20 @@ export function taskUrl(owner: string, name: string, number: number): string {
 export function epicUrl(owner: string, name: string, number: number): string {
 	return `/${owner}/${name}/epics/${number}`;
 }
+
+// formatCostRange formats a proposed task's estimated cost, e.g. "$1.50–$4.00".
+export function formatCostRange(minUSD?: number, maxUSD?: number): string {
+	if (!maxUSD) return minUSD ? `$${minUSD.toFixed(2)}+` : '';
+	if (!minUSD) return `≤ $${maxUSD.toFixed(2)}`;
+	return `$${minUSD.toFixed(2)}–$${maxUSD.toFixed(2)}`;
+}
+
+// parseLabels splits comma-separated worker labels, dropping blanks and
+// duplicates.
+export function parseLabels(text: string): string[] {
+	const labels = text
+		.split(',')
+		.map((s) => s.trim().toLowerCase())
+		.filter((s) => s !== '');
+	return [...new Set(labels)];
+}